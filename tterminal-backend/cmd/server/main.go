@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"tterminal-backend/config"
+	"tterminal-backend/internal/batchwriter"
 	"tterminal-backend/internal/database"
 	"tterminal-backend/routes"
 
@@ -24,8 +26,14 @@ func main() {
 		log.Println("No .env file found, using system environment variables")
 	}
 
-	// Initialize configuration
-	cfg := config.Load()
+	// Initialize configuration (profile from APP_ENV), refusing to start when it is invalid
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if sanitized, err := json.Marshal(cfg.Sanitized()); err == nil {
+		log.Printf("Loaded %s configuration: %s", cfg.Profile, sanitized)
+	}
 
 	// Initialize database
 	db, err := database.NewConnection(cfg.DatabaseURL)
@@ -42,12 +50,15 @@ func main() {
 	// Initialize Echo
 	e := echo.New()
 
-	// Basic middleware
-	e.Use(middleware.Logger())
+	// Basic middleware; the request log is written at the debug and info levels (LOG_LEVEL, reloadable)
+	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
+		Skipper: func(echo.Context) bool { return !cfg.Runtime().Settings().LogsRequests() },
+	}))
 	e.Use(middleware.Recover())
 
-	// Setup routes
-	routes.SetupRoutes(e, db, cfg)
+	// Setup routes; persistence streams register their batch writers to be flushed on shutdown
+	writers := batchwriter.NewRegistry()
+	routes.SetupRoutes(e, db, cfg, writers)
 
 	// Start server in a goroutine
 	go func() {
@@ -68,7 +79,12 @@ func main() {
 	defer cancel()
 
 	if err := e.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	// Write what the batch writers still hold before the database closes
+	if err := writers.Close(ctx); err != nil {
+		log.Printf("Failed to flush pending writes: %v", err)
 	}
 
 	log.Println("Server exited")
@@ -0,0 +1,441 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+)
+
+// Profile selects defaults and validation strictness for a deployment
+type Profile string
+
+// Deployment profiles
+const (
+	ProfileDev     Profile = "dev"     // Local development: every variable has a default
+	ProfileStaging Profile = "staging" // Required variables must be set
+	ProfileProd    Profile = "prod"    // Required variables must be set, plus production safety checks
+)
+
+// redacted replaces secret values in logs and the admin config endpoint
+const redacted = "[REDACTED]"
+
+// ParseProfile parses an APP_ENV value, defaulting to dev when empty
+func ParseProfile(value string) (Profile, error) {
+	switch Profile(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ProfileDev:
+		return ProfileDev, nil
+	case ProfileStaging:
+		return ProfileStaging, nil
+	case ProfileProd:
+		return ProfileProd, nil
+	}
+	return "", fmt.Errorf("invalid APP_ENV %q, use dev, staging or prod", value)
+}
+
+// defaultGinMode returns the GIN_MODE used when the variable is unset
+func (p Profile) defaultGinMode() string {
+	if p == ProfileDev {
+		return "debug"
+	}
+	return "release"
+}
+
+// validate checks value ranges and cross-field rules, returning one message per problem
+func (c *Config) validate() []string {
+	var errs []string
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port <= 0 || port > 65535 {
+		errs = append(errs, "PORT must be a port number")
+	}
+	if c.DatabaseURL != "" {
+		if u, err := url.Parse(c.DatabaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, "TIMESCALE_DB_URL must be a postgres:// URL")
+		}
+	}
+	if c.SessionRecordingRetention <= 0 {
+		errs = append(errs, "SESSION_RECORDING_RETENTION_HOURS must be positive")
+	}
+	if c.SymbolSyncInterval <= 0 {
+		errs = append(errs, "SYMBOL_SYNC_INTERVAL_MINUTES must be positive")
+	}
+	sessionNames := make(map[string]bool, len(c.VolumeProfileSessions))
+	for _, value := range c.VolumeProfileSessions {
+		session, err := models.ParseTradingSession(value)
+		if err != nil {
+			errs = append(errs, "VOLUME_PROFILE_SESSIONS: "+err.Error())
+			continue
+		}
+		if sessionNames[session.Name] {
+			errs = append(errs, fmt.Sprintf("VOLUME_PROFILE_SESSIONS: session %q is listed twice", session.Name))
+		}
+		sessionNames[session.Name] = true
+	}
+	for _, pair := range c.SpreadPairs {
+		if _, err := models.ParseSpreadPair(pair); err != nil {
+			errs = append(errs, "SPREAD_PAIRS: "+err.Error())
+		}
+	}
+	if c.SpreadInterval <= 0 {
+		errs = append(errs, "SPREAD_INTERVAL_SECONDS must be positive")
+	}
+	if c.SpreadArbitrageBps <= 0 {
+		errs = append(errs, "SPREAD_ARBITRAGE_BPS must be positive")
+	}
+	if c.HiddenLiquidityWindow <= 0 {
+		errs = append(errs, "HIDDEN_LIQUIDITY_WINDOW_MINUTES must be positive")
+	}
+	if c.WSKeepaliveInterval < 0 || c.DepthSnapshotInterval < 0 || c.AggregationMultiTimeout < 0 {
+		errs = append(errs, "durations must not be negative")
+	}
+	if c.WSMaxSymbols < 0 || c.WSMaxChannels < 0 || c.WSMessagesPerSecond < 0 {
+		errs = append(errs, "WS_MAX_SYMBOLS, WS_MAX_CHANNELS and WS_MESSAGES_PER_SECOND must not be negative")
+	}
+	if c.WSMaxBytesPerSecond < 0 || c.WSMaxUserBytesPerSecond < 0 {
+		errs = append(errs, "WS_MAX_BYTES_PER_SECOND and WS_MAX_USER_BYTES_PER_SECOND must not be negative")
+	}
+	if c.WSLoadBytesPerSecond < 0 || c.WSLoadMaxClients < 0 || c.WSLoadQueuePct < 0 || c.WSLoadQueuePct > 100 {
+		errs = append(errs, "WS_LOAD_BYTES_PER_SECOND and WS_LOAD_MAX_CLIENTS must not be negative and WS_LOAD_QUEUE_PCT must be between 0 and 100")
+	}
+	if c.WSLoadReconnectPct < 0 || c.WSLoadReconnectPct > 100 {
+		errs = append(errs, "WS_LOAD_RECONNECT_PCT must be between 0 and 100")
+	}
+	if c.CandlePriceTolerance < 0 || c.CandleVolumeTolerance < 0 {
+		errs = append(errs, "CANDLE_CONSISTENCY_PRICE_TOLERANCE_PCT and CANDLE_CONSISTENCY_VOLUME_TOLERANCE_PCT must not be negative")
+	}
+	if c.CandlePreferredSource != models.CandleSourceStream && c.CandlePreferredSource != models.CandleSourceRESTPoll {
+		errs = append(errs, fmt.Sprintf("CANDLE_PREFERRED_SOURCE must be %s or %s", models.CandleSourceStream, models.CandleSourceRESTPoll))
+	}
+	if c.AnomalyMovePct < 0 || (c.AnomalyMovePct > 0 && c.AnomalyPause <= 0) {
+		errs = append(errs, "COLLECTION_ANOMALY_MOVE_PCT must not be negative and COLLECTION_ANOMALY_PAUSE_MINUTES must be positive when it is set")
+	}
+	if c.TickMulticastAddr != "" {
+		if _, err := net.ResolveUDPAddr("udp", c.TickMulticastAddr); err != nil {
+			errs = append(errs, "TICK_MULTICAST_ADDR must be a host:port address")
+		}
+	}
+	if c.TickMulticastTTL < 1 || c.TickMulticastTTL > 255 {
+		errs = append(errs, "TICK_MULTICAST_TTL must be between 1 and 255")
+	}
+	if c.AggregationMultiConcurrency <= 0 {
+		errs = append(errs, "AGGREGATION_MULTI_CONCURRENCY must be positive")
+	}
+	if c.MakerFeeRate < 0 || c.MakerFeeRate >= 1 || c.TakerFeeRate < 0 || c.TakerFeeRate >= 1 {
+		errs = append(errs, "MAKER_FEE_RATE and TAKER_FEE_RATE must be fractions between 0 and 1")
+	}
+	if c.EmbedConnectsPerMinute <= 0 {
+		errs = append(errs, "EMBED_CONNECTS_PER_MINUTE must be positive")
+	}
+	if c.DrainGrace <= 0 {
+		errs = append(errs, "DRAIN_GRACE_SECONDS must be positive")
+	}
+	if c.SLAWindow <= 0 || c.SLATarget <= 0 {
+		errs = append(errs, "SLA_WINDOW_MINUTES and SLA_TARGET_MS must be positive")
+	}
+	if c.FreshnessWindow <= 0 {
+		errs = append(errs, "SLA_FRESHNESS_WINDOW_HOURS must be positive")
+	}
+	if !containsString(LogLevels, c.LogLevel) {
+		errs = append(errs, fmt.Sprintf("LOG_LEVEL must be one of %s", strings.Join(LogLevels, ", ")))
+	}
+	if c.RateLimitRPS <= 0 || c.RateLimitBurst <= 0 {
+		errs = append(errs, "RATE_LIMIT_REQUESTS_PER_SECOND and RATE_LIMIT_BURST must be positive")
+	}
+	if c.TrafficDBBatchRequests <= 0 || c.TrafficWorkers <= 0 || c.TrafficBatchWorkers <= 0 {
+		errs = append(errs, "TRAFFIC_DB_BATCH_REQUESTS, TRAFFIC_AGGREGATION_WORKERS and TRAFFIC_AGGREGATION_BATCH_WORKERS must be positive")
+	}
+	if c.TrafficBatchWorkers > c.TrafficWorkers {
+		errs = append(errs, "TRAFFIC_AGGREGATION_BATCH_WORKERS must not exceed TRAFFIC_AGGREGATION_WORKERS")
+	}
+	if c.TrafficRateReserve < 0 || c.TrafficRateReserve >= 1 {
+		errs = append(errs, "TRAFFIC_RATE_LIMIT_RESERVE must be a fraction between 0 and 1")
+	}
+	for _, symbol := range c.BinanceCoinMSymbols {
+		if !strings.Contains(symbol, "USD_") {
+			errs = append(errs, fmt.Sprintf("BINANCE_COINM_SYMBOLS: %q is not a COIN-margined contract such as BTCUSD_PERP", symbol))
+		}
+	}
+	if c.TradingEnabled && (c.BinanceAPIKey == "" || c.BinanceSecretKey == "") {
+		errs = append(errs, "BINANCE_API_KEY and BINANCE_SECRET_KEY are required when TRADING_ENABLED is true")
+	}
+	if c.TradingEnabled && c.AdminToken == "" && c.JWTSecret == "" {
+		errs = append(errs, "ADMIN_TOKEN or JWT_SECRET is required when TRADING_ENABLED is true, so only admins can place orders")
+	}
+	if c.TradingEnabled && c.SyntheticData {
+		errs = append(errs, "TRADING_ENABLED cannot be combined with SYNTHETIC_DATA")
+	}
+	if c.BybitEnabled && len(c.BybitSymbols) == 0 {
+		errs = append(errs, "BYBIT_SYMBOLS must list at least one symbol when BYBIT_ENABLED is true")
+	}
+	if c.OKXEnabled && len(c.OKXSymbols) == 0 {
+		errs = append(errs, "OKX_SYMBOLS must list at least one symbol when OKX_ENABLED is true")
+	}
+	if c.CoinbaseEnabled && len(c.CoinbaseSymbols) == 0 {
+		errs = append(errs, "COINBASE_SYMBOLS must list at least one symbol when COINBASE_ENABLED is true")
+	}
+	if c.KrakenEnabled && len(c.KrakenSymbols) == 0 {
+		errs = append(errs, "KRAKEN_SYMBOLS must list at least one symbol when KRAKEN_ENABLED is true")
+	}
+	if c.HyperliquidEnabled && len(c.HyperliquidSymbols) == 0 {
+		errs = append(errs, "HYPERLIQUID_SYMBOLS must list at least one symbol when HYPERLIQUID_ENABLED is true")
+	}
+	if c.BinanceOptionsEnabled && len(c.BinanceOptionsUnderlyings) == 0 {
+		errs = append(errs, "BINANCE_OPTIONS_UNDERLYINGS must list at least one asset when BINANCE_OPTIONS_ENABLED is true")
+	}
+	if c.DeribitEnabled && len(c.DeribitCurrencies) == 0 {
+		errs = append(errs, "DERIBIT_CURRENCIES must list at least one currency when DERIBIT_ENABLED is true")
+	}
+	if c.JWTSecret != "" && len(c.JWTSecret) < 32 {
+		errs = append(errs, "JWT_SECRET must be at least 32 characters")
+	}
+	if c.ComplianceMode && !c.ComplianceAllowAnonymous && c.JWTSecret == "" {
+		errs = append(errs, "JWT_SECRET is required when COMPLIANCE_MODE disallows anonymous access, to verify callers")
+	}
+	if c.JWTTTL <= 0 {
+		errs = append(errs, "JWT_TTL_HOURS must be positive")
+	}
+	if c.AuthAttemptsPerMinute <= 0 {
+		errs = append(errs, "AUTH_ATTEMPTS_PER_MINUTE must be positive")
+	}
+	if c.SMTPUsername != "" && c.SMTPPassword == "" {
+		errs = append(errs, "SMTP_PASSWORD is required when SMTP_USERNAME is set")
+	}
+
+	if c.Profile == ProfileProd {
+		if c.SyntheticData {
+			errs = append(errs, "SYNTHETIC_DATA must not be enabled in prod")
+		}
+		if c.WebhooksAllowPrivateURLs {
+			errs = append(errs, "WEBHOOKS_ALLOW_PRIVATE_URLS must not be enabled in prod")
+		}
+		if c.AdminToken == "" {
+			errs = append(errs, "ADMIN_TOKEN is required in prod")
+		}
+		if c.JWTSecret == "" {
+			errs = append(errs, "JWT_SECRET is required in prod")
+		}
+		if u, err := url.Parse(c.DatabaseURL); err == nil {
+			if password, _ := u.User.Password(); password == "password" {
+				errs = append(errs, "TIMESCALE_DB_URL must not use the development password in prod")
+			}
+		}
+	}
+
+	return errs
+}
+
+// Sanitized returns the configuration with secrets redacted, for startup logs and admin inspection
+func (c *Config) Sanitized() map[string]interface{} {
+	freshnessTargets := make(map[string]string, len(c.FreshnessTargets))
+	for interval, target := range c.FreshnessTargets {
+		freshnessTargets[interval] = target.String()
+	}
+
+	return map[string]interface{}{
+		"profile": c.Profile,
+		"server": map[string]interface{}{
+			"port":      c.Port,
+			"gin_mode":  c.GinMode,
+			"log_level": c.LogLevel,
+		},
+		"database": map[string]interface{}{
+			"url": redactURL(c.DatabaseURL),
+		},
+		"binance": map[string]interface{}{
+			"api_key":    redactSecret(c.BinanceAPIKey),
+			"secret_key": redactSecret(c.BinanceSecretKey),
+			"base_url":   c.BinanceBaseURL,
+			"ws_url":     c.BinanceWSURL,
+		},
+		"trading": map[string]interface{}{
+			"enabled":  c.TradingEnabled,
+			"base_url": c.BinanceTradingBaseURL,
+			"ws_url":   c.BinanceTradingWSURL,
+		},
+		"binance_ws_api": map[string]interface{}{
+			"enabled": c.BinanceWSAPIEnabled,
+			"url":     c.BinanceWSAPIURL,
+		},
+		"binance_coinm": map[string]interface{}{
+			"base_url": c.BinanceCoinMBaseURL,
+			"ws_url":   c.BinanceCoinMWSURL,
+			"symbols":  c.BinanceCoinMSymbols,
+		},
+		"binance_options": map[string]interface{}{
+			"enabled":     c.BinanceOptionsEnabled,
+			"base_url":    c.BinanceOptionsBaseURL,
+			"underlyings": c.BinanceOptionsUnderlyings,
+		},
+		"bybit": map[string]interface{}{
+			"enabled":  c.BybitEnabled,
+			"base_url": c.BybitBaseURL,
+			"ws_url":   c.BybitWSURL,
+			"symbols":  c.BybitSymbols,
+		},
+		"okx": map[string]interface{}{
+			"enabled":         c.OKXEnabled,
+			"base_url":        c.OKXBaseURL,
+			"ws_url":          c.OKXWSURL,
+			"ws_business_url": c.OKXWSBusinessURL,
+			"symbols":         c.OKXSymbols,
+		},
+		"coinbase": map[string]interface{}{
+			"enabled":  c.CoinbaseEnabled,
+			"base_url": c.CoinbaseBaseURL,
+			"ws_url":   c.CoinbaseWSURL,
+			"symbols":  c.CoinbaseSymbols,
+		},
+		"kraken": map[string]interface{}{
+			"enabled":  c.KrakenEnabled,
+			"base_url": c.KrakenBaseURL,
+			"ws_url":   c.KrakenWSURL,
+			"symbols":  c.KrakenSymbols,
+		},
+		"hyperliquid": map[string]interface{}{
+			"enabled":  c.HyperliquidEnabled,
+			"base_url": c.HyperliquidBaseURL,
+			"ws_url":   c.HyperliquidWSURL,
+			"symbols":  c.HyperliquidSymbols,
+		},
+		"deribit": map[string]interface{}{
+			"enabled":    c.DeribitEnabled,
+			"base_url":   c.DeribitBaseURL,
+			"currencies": c.DeribitCurrencies,
+		},
+		"synthetic": map[string]interface{}{
+			"enabled": c.SyntheticData,
+			"seed":    c.SyntheticSeed,
+		},
+		"websocket": map[string]interface{}{
+			"keepalive_interval":        c.WSKeepaliveInterval.String(),
+			"max_frame_bytes":           c.WSMaxFrameBytes,
+			"max_symbols":               c.WSMaxSymbols,
+			"max_channels":              c.WSMaxChannels,
+			"messages_per_second":       c.WSMessagesPerSecond,
+			"max_bytes_per_second":      c.WSMaxBytesPerSecond,
+			"max_user_bytes_per_second": c.WSMaxUserBytesPerSecond,
+			"load_bytes_per_second":     c.WSLoadBytesPerSecond,
+			"load_queue_pct":            c.WSLoadQueuePct,
+			"load_max_clients":          c.WSLoadMaxClients,
+			"load_enforce":              c.WSLoadEnforce,
+			"load_reconnect_pct":        c.WSLoadReconnectPct,
+		},
+		"embed": map[string]interface{}{
+			"connects_per_minute": c.EmbedConnectsPerMinute,
+			"max_connections":     c.EmbedMaxConnections,
+			"max_per_ip":          c.EmbedMaxPerIP,
+		},
+		"session_recording_retention": c.SessionRecordingRetention.String(),
+		"depth_snapshot_interval":     c.DepthSnapshotInterval.String(),
+		"symbol_sync_interval":        c.SymbolSyncInterval.String(),
+		"spreads": map[string]interface{}{
+			"pairs":         c.SpreadPairs,
+			"interval":      c.SpreadInterval.String(),
+			"arbitrage_bps": c.SpreadArbitrageBps,
+		},
+		"hidden_liquidity": map[string]interface{}{
+			"symbols": c.HiddenLiquiditySymbols,
+			"window":  c.HiddenLiquidityWindow.String(),
+		},
+		"candle_consistency": map[string]interface{}{
+			"price_tolerance_pct":  c.CandlePriceTolerance,
+			"volume_tolerance_pct": c.CandleVolumeTolerance,
+			"preferred_source":     c.CandlePreferredSource,
+		},
+		"collection_anomaly": map[string]interface{}{
+			"move_pct": c.AnomalyMovePct,
+			"pause":    c.AnomalyPause.String(),
+		},
+		"aggregation_multi": map[string]interface{}{
+			"timeout":     c.AggregationMultiTimeout.String(),
+			"concurrency": c.AggregationMultiConcurrency,
+		},
+		"fees": map[string]interface{}{
+			"maker_rate": c.MakerFeeRate,
+			"taker_rate": c.TakerFeeRate,
+		},
+		"smtp": map[string]interface{}{
+			"host":       c.SMTPHost,
+			"port":       c.SMTPPort,
+			"username":   c.SMTPUsername,
+			"password":   redactSecret(c.SMTPPassword),
+			"email_from": c.ReportEmailFrom,
+		},
+		"webhooks": map[string]interface{}{
+			"allow_private_urls": c.WebhooksAllowPrivateURLs,
+		},
+		"compliance": map[string]interface{}{
+			"enabled":         c.ComplianceMode,
+			"allow_anonymous": c.ComplianceAllowAnonymous,
+			"allow_exports":   c.ComplianceAllowExports,
+			"deployment_id":   c.DeploymentID,
+		},
+		"admin_token": redactSecret(c.AdminToken),
+		"auth": map[string]interface{}{
+			"jwt_secret":          redactSecret(c.JWTSecret),
+			"jwt_ttl":             c.JWTTTL.String(),
+			"attempts_per_minute": c.AuthAttemptsPerMinute,
+		},
+		"audit_log": map[string]interface{}{
+			"exclude_routes": c.AuditExcludeRoutes,
+		},
+		"drain": map[string]interface{}{
+			"peers": c.DrainPeers,
+			"grace": c.DrainGrace.String(),
+		},
+		"sla": map[string]interface{}{
+			"window":         c.SLAWindow.String(),
+			"target":         c.SLATarget.String(),
+			"exclude_routes": c.SLAExcludeRoutes,
+		},
+		"sla_freshness": map[string]interface{}{
+			"targets": freshnessTargets,
+			"window":  c.FreshnessWindow.String(),
+		},
+		"instance": map[string]interface{}{
+			"id":     c.InstanceID,
+			"region": c.InstanceRegion,
+		},
+		"rate_limit": map[string]interface{}{
+			"requests_per_second": c.RateLimitRPS,
+			"burst":               c.RateLimitBurst,
+			"routes":              c.RateLimitRoutes,
+		},
+		"traffic": map[string]interface{}{
+			"batch_routes":              c.TrafficBatchRoutes,
+			"db_batch_requests":         c.TrafficDBBatchRequests,
+			"aggregation_workers":       c.TrafficWorkers,
+			"aggregation_batch_workers": c.TrafficBatchWorkers,
+			"rate_limit_reserve":        c.TrafficRateReserve,
+		},
+	}
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecret hides a secret's value while showing whether it is set
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURL hides the password in a connection URL
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		return redacted
+	}
+	return u.Redacted()
+}
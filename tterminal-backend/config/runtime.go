@@ -0,0 +1,87 @@
+package config
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Log levels accepted in LOG_LEVEL, from most to least verbose
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// RuntimeSettings are the settings applied without a restart: they are reloaded on SIGHUP or
+// from the admin API while streams and WebSocket clients stay connected. Everything else in
+// Config is read once at startup
+type RuntimeSettings struct {
+	LogLevel            string `json:"log_level"`
+	RateLimitRPS        int    `json:"rate_limit_requests_per_second"`
+	RateLimitBurst      int    `json:"rate_limit_burst"`
+	BinanceWSAPIEnabled bool   `json:"binance_ws_api_enabled"`
+	TradingEnabled      bool   `json:"trading_enabled"`
+}
+
+// LogsRequests reports whether the per-request access log is written (debug and info levels)
+func (s RuntimeSettings) LogsRequests() bool {
+	return s.LogLevel == "debug" || s.LogLevel == "info"
+}
+
+// Values returns the settings as strings keyed by their environment variable, for change audits
+func (s RuntimeSettings) Values() map[string]string {
+	return map[string]string{
+		"LOG_LEVEL":                      s.LogLevel,
+		"RATE_LIMIT_REQUESTS_PER_SECOND": strconv.Itoa(s.RateLimitRPS),
+		"RATE_LIMIT_BURST":               strconv.Itoa(s.RateLimitBurst),
+		"BINANCE_WS_API_ENABLED":         strconv.FormatBool(s.BinanceWSAPIEnabled),
+		"TRADING_ENABLED":                strconv.FormatBool(s.TradingEnabled),
+	}
+}
+
+// Runtime holds the current runtime settings and notifies components that apply them
+type Runtime struct {
+	mu        sync.RWMutex
+	settings  RuntimeSettings
+	listeners []func(RuntimeSettings)
+}
+
+// Settings returns the current runtime settings
+func (r *Runtime) Settings() RuntimeSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.settings
+}
+
+// OnChange registers a function called with the new settings after every Apply
+func (r *Runtime) OnChange(listener func(RuntimeSettings)) {
+	r.mu.Lock()
+	r.listeners = append(r.listeners, listener)
+	r.mu.Unlock()
+}
+
+// Apply replaces the runtime settings, notifies the listeners and returns the previous settings
+func (r *Runtime) Apply(settings RuntimeSettings) RuntimeSettings {
+	r.mu.Lock()
+	previous := r.settings
+	r.settings = settings
+	listeners := r.listeners[:len(r.listeners):len(r.listeners)]
+	r.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(settings)
+	}
+	return previous
+}
+
+// Runtime returns the runtime settings holder, starting from the loaded values
+func (c *Config) Runtime() *Runtime {
+	return c.runtime
+}
+
+// RuntimeSettings returns the loaded values of the runtime settings
+func (c *Config) RuntimeSettings() RuntimeSettings {
+	return RuntimeSettings{
+		LogLevel:            c.LogLevel,
+		RateLimitRPS:        c.RateLimitRPS,
+		RateLimitBurst:      c.RateLimitBurst,
+		BinanceWSAPIEnabled: c.BinanceWSAPIEnabled,
+		TradingEnabled:      c.TradingEnabled,
+	}
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/traffic"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminController handles deployment inspection endpoints
+type AdminController struct {
+	cfg           *config.Config
+	reloadService *services.ConfigReloadService // Optional; enables config reloads and their audit log
+	auditService  *services.AuditLogService     // Optional; serves the API audit log
+	// Optional; report the traffic class limits in use
+	dbBatchGate        *traffic.Gate
+	aggregationService *services.AggregationService
+}
+
+// NewAdminController creates a new admin controller
+func NewAdminController(cfg *config.Config) *AdminController {
+	return &AdminController{cfg: cfg}
+}
+
+// GetConfig returns the loaded configuration with secrets redacted, and the runtime settings in effect
+func (ac *AdminController) GetConfig(c echo.Context) error {
+	sanitized := ac.cfg.Sanitized()
+	sanitized["runtime"] = ac.cfg.Runtime().Settings() // Current values, after any reload
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, sanitized)
+}
+
+// SetConfigReloadService enables the config reload endpoints
+func (ac *AdminController) SetConfigReloadService(reloadService *services.ConfigReloadService) {
+	ac.reloadService = reloadService
+}
+
+// SetTrafficStats enables the traffic class report from the database batch gate and the aggregation workers
+func (ac *AdminController) SetTrafficStats(dbBatchGate *traffic.Gate, aggregationService *services.AggregationService) {
+	ac.dbBatchGate = dbBatchGate
+	ac.aggregationService = aggregationService
+}
+
+// GetTraffic reports how much of each traffic class limit is in use and how many requests wait for it
+func (ac *AdminController) GetTraffic(c echo.Context) error {
+	response := map[string]interface{}{
+		"batch_routes":       ac.cfg.TrafficBatchRoutes,
+		"rate_limit_reserve": ac.cfg.TrafficRateReserve,
+	}
+	if ac.dbBatchGate != nil {
+		response["db_batch_requests"] = ac.dbBatchGate.Stats()
+	}
+	if ac.aggregationService != nil {
+		response["aggregation_workers"] = ac.aggregationService.FetchWorkerStats()
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// ReloadConfig reloads the runtime settings, as SIGHUP does, and returns what changed
+// An invalid configuration is rejected with 400 and changes nothing
+func (ac *AdminController) ReloadConfig(c echo.Context) error {
+	if ac.reloadService == nil {
+		return configReloadUnavailable(c)
+	}
+
+	entry, err := ac.reloadService.Reload(c.Request().Context(), models.ConfigReloadAdmin, requestUserID(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "validation failed") {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, map[string]interface{}{
+			"error":  err.Error(),
+			"reload": entry,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"reload":  entry,
+		"runtime": ac.cfg.Runtime().Settings(),
+	})
+}
+
+// GetConfigAudit returns the most recent config reloads, newest first
+func (ac *AdminController) GetConfigAudit(c echo.Context) error {
+	if ac.reloadService == nil {
+		return configReloadUnavailable(c)
+	}
+
+	entries, err := ac.reloadService.GetAudit(c.Request().Context(), queryInt(c, "limit", 100, 1, 500))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve config audit: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":   len(entries),
+		"reloads": entries,
+	})
+}
+
+// SetAuditLogService enables the API audit log endpoint
+func (ac *AdminController) SetAuditLogService(auditService *services.AuditLogService) {
+	ac.auditService = auditService
+}
+
+// GetAuditLog returns the state-changing API requests matching the query filters, newest first
+// GET /api/v1/admin/audit-log?actor=&resource=&resource_id=&method=&path=&start=&end=&before_id=&limit=100
+func (ac *AdminController) GetAuditLog(c echo.Context) error {
+	if ac.auditService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "audit log is not available",
+		})
+	}
+
+	filter := models.AuditLogFilter{
+		Actor:      c.QueryParam("actor"),
+		Resource:   c.QueryParam("resource"),
+		ResourceID: c.QueryParam("resource_id"),
+		Method:     c.QueryParam("method"),
+		PathPrefix: c.QueryParam("path"),
+		Limit:      queryInt(c, "limit", 100, 1, 500),
+	}
+	if beforeID := c.QueryParam("before_id"); beforeID != "" {
+		parsed, err := strconv.ParseInt(beforeID, 10, 64)
+		if err != nil || parsed <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "before_id must be a positive entry ID",
+			})
+		}
+		filter.BeforeID = parsed
+	}
+	for param, bound := range map[string]*time.Time{"start": &filter.Start, "end": &filter.End} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseAnchorTime(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": param + " must be Unix milliseconds or an RFC3339 time",
+			})
+		}
+		*bound = parsed
+	}
+
+	entries, err := ac.auditService.Query(c.Request().Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "validation failed") {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, map[string]string{
+			"error": "Failed to retrieve audit log: " + err.Error(),
+		})
+	}
+
+	response := map[string]interface{}{
+		"count":   len(entries),
+		"entries": entries,
+	}
+	if len(entries) == filter.Limit {
+		response["next_before_id"] = entries[len(entries)-1].ID
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// configReloadUnavailable responds when config reloads are not set up
+func configReloadUnavailable(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{
+		"error": "config reload is not available",
+	})
+}
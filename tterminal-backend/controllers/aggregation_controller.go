@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"tterminal-backend/models"
 	"tterminal-backend/services"
 
 	"github.com/labstack/echo/v4"
@@ -14,6 +17,8 @@ import (
 // AggregationController handles ultra-fast aggregated data endpoints
 type AggregationController struct {
 	aggregationService *services.AggregationService
+	historyService     *services.AggregateHistoryService // Versioned "as of" aggregates (optional)
+	vwapService        *services.VWAPService             // Session and anchored VWAP (optional)
 }
 
 // NewAggregationController creates a new aggregation controller
@@ -27,6 +32,16 @@ func NewAggregationController(aggregationService *services.AggregationService) *
 	}
 }
 
+// SetHistoryService enables versioned fixed-range aggregates and "as of" requests
+func (ctrl *AggregationController) SetHistoryService(historyService *services.AggregateHistoryService) {
+	ctrl.historyService = historyService
+}
+
+// SetVWAPService enables session and anchored VWAP
+func (ctrl *AggregationController) SetVWAPService(vwapService *services.VWAPService) {
+	ctrl.vwapService = vwapService
+}
+
 // ErrorResponse represents a standardized error response
 type ErrorResponse struct {
 	Error   string            `json:"error"`
@@ -36,18 +51,29 @@ type ErrorResponse struct {
 }
 
 // GetOptimizedCandles returns ultra-optimized candle data for frontend rendering
-// GET /api/v1/aggregation/candles/:symbol/:interval?limit=500
+// GET /api/v1/aggregation/candles/:symbol/:interval?limit=500[&endTime=|&before=|&cursor=][&source=composite][&include=funding,oi]
 func (ctrl *AggregationController) GetOptimizedCandles(c echo.Context) error {
 	startTime := time.Now()
 
 	// Extract parameters
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
 	interval := c.Param("interval")
 	limitStr := c.QueryParam("limit")
 
 	log.Printf("[AggregationController] GetOptimizedCandles request: symbol=%s, interval=%s, limit=%s", symbol, interval, limitStr)
 
 	// Validate and parse parameters
+	if !ok {
+		err := ErrorResponse{
+			Error:   "Invalid parameter value",
+			Message: fmt.Sprintf("Exchange %q is not supported, supported exchanges: %s", c.QueryParam("exchange"), strings.Join(models.Exchanges, ", ")),
+			Code:    "INVALID_EXCHANGE",
+			Details: map[string]string{"parameter": "exchange", "value": c.QueryParam("exchange")},
+		}
+		log.Printf("[AggregationController] Validation error: %+v", err)
+		return c.JSON(http.StatusBadRequest, err)
+	}
+
 	if symbol == "" {
 		err := ErrorResponse{
 			Error:   "Missing required parameter",
@@ -70,6 +96,10 @@ func (ctrl *AggregationController) GetOptimizedCandles(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, err)
 	}
 
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
 	// Parse limit with default
 	limit := 500
 	if limitStr != "" {
@@ -96,25 +126,123 @@ func (ctrl *AggregationController) GetOptimizedCandles(c echo.Context) error {
 		}
 	}
 
-	log.Printf("[AggregationController] Calling aggregation service with validated parameters: symbol=%s, interval=%s, limit=%d", symbol, interval, limit)
+	before, err := parseAnchor(c)
+	if err != nil {
+		errResp := ErrorResponse{
+			Error:   "Invalid parameter value",
+			Message: err.Error(),
+			Code:    "INVALID_ANCHOR",
+			Details: map[string]string{"endTime": c.QueryParam("endTime"), "before": c.QueryParam("before"), "cursor": c.QueryParam("cursor")},
+		}
+		log.Printf("[AggregationController] Validation error: %+v", errResp)
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+
+	source := c.QueryParam("source")
+	if source != "" && source != "composite" {
+		errResp := ErrorResponse{
+			Error:   "Invalid parameter value",
+			Message: fmt.Sprintf("Source %q is not supported, use composite or omit it", source),
+			Code:    "INVALID_SOURCE",
+			Details: map[string]string{"parameter": "source", "value": source},
+		}
+		log.Printf("[AggregationController] Validation error: %+v", errResp)
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+
+	volumeUnit, err := parseVolumeUnit(c)
+	if err != nil {
+		errResp := ErrorResponse{
+			Error:   "Invalid parameter value",
+			Message: err.Error(),
+			Code:    "INVALID_VOLUME_UNIT",
+			Details: map[string]string{"parameter": "volumeUnit", "value": c.QueryParam("volumeUnit")},
+		}
+		log.Printf("[AggregationController] Validation error: %+v", errResp)
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+
+	include, err := parseIncludes(c.QueryParam("include"))
+	if err != nil {
+		errResp := ErrorResponse{
+			Error:   "Invalid parameter value",
+			Message: err.Error(),
+			Code:    "INVALID_INCLUDE",
+			Details: map[string]string{"parameter": "include", "value": c.QueryParam("include")},
+		}
+		log.Printf("[AggregationController] Validation error: %+v", errResp)
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
 
-	// Call aggregation service
-	response, err := ctrl.aggregationService.GetAggregatedCandles(c.Request().Context(), symbol, interval, limit)
+	log.Printf("[AggregationController] Calling aggregation service with validated parameters: symbol=%s, interval=%s, limit=%d, source=%s", symbol, interval, limit, source)
+
+	// Call aggregation service; the composite source builds a volume-weighted index across exchanges
+	var response *models.CandleResponse
+	if source == "composite" {
+		response, err = ctrl.aggregationService.GetCompositeCandlesBefore(c.Request().Context(), symbol, interval, before, limit)
+	} else {
+		response, err = ctrl.aggregationService.GetAggregatedCandlesBefore(c.Request().Context(), symbol, interval, before, limit)
+	}
 	if err != nil {
 		duration := time.Since(startTime)
+		status, code := errorStatus(err)
+		if errors.Is(err, services.ErrNoIndexConstituents) {
+			status, code = http.StatusNotFound, "NO_INDEX_CONSTITUENTS"
+		}
+		if code == "" {
+			code = "AGGREGATION_SERVICE_ERROR"
+		}
 		errResp := ErrorResponse{
 			Error:   "Service error",
 			Message: fmt.Sprintf("Failed to get aggregated candles: %s", err.Error()),
-			Code:    "AGGREGATION_SERVICE_ERROR",
+			Code:    code,
 			Details: map[string]string{
-				"symbol":   symbol,
-				"interval": interval,
-				"limit":    strconv.Itoa(limit),
-				"duration": duration.String(),
+				"symbol":     symbol,
+				"interval":   interval,
+				"limit":      strconv.Itoa(limit),
+				"duration":   duration.String(),
+				"request_id": c.Response().Header().Get(echo.HeaderXRequestID),
 			},
 		}
 		log.Printf("[AggregationController] Service error after %v: %+v", duration, errResp)
-		return c.JSON(http.StatusInternalServerError, errResp)
+		setRetryAfter(c, err)
+		return c.JSON(status, errResp)
+	}
+
+	// Volume is converted after caching, so every unit is served from the same cached candles
+	if response, err = ctrl.aggregationService.ConvertVolume(c.Request().Context(), response, volumeUnit); err != nil {
+		status, code := http.StatusInternalServerError, "AGGREGATION_SERVICE_ERROR"
+		if errors.Is(err, services.ErrVolumeUnitUnsupported) {
+			status, code = http.StatusBadRequest, "UNSUPPORTED_VOLUME_UNIT"
+		}
+		errResp := ErrorResponse{
+			Error:   "Volume conversion failed",
+			Message: err.Error(),
+			Code:    code,
+			Details: map[string]string{"parameter": "volumeUnit", "value": volumeUnit},
+		}
+		log.Printf("[AggregationController] Volume conversion error: %+v", errResp)
+		return c.JSON(status, errResp)
+	}
+
+	// Funding and open interest are merged after caching too, from their own cached history
+	if response, err = ctrl.aggregationService.OverlayCandles(c.Request().Context(), response, include); err != nil {
+		status, code := errorStatus(err)
+		if errors.Is(err, services.ErrCandleOverlayUnsupported) {
+			status, code = http.StatusBadRequest, "UNSUPPORTED_INCLUDE"
+		}
+		if code == "" {
+			code = "OVERLAY_FAILED"
+		}
+		errResp := ErrorResponse{
+			Error:   "Derivatives overlay failed",
+			Message: err.Error(),
+			Code:    code,
+			Details: map[string]string{"parameter": "include", "value": c.QueryParam("include")},
+		}
+		log.Printf("[AggregationController] Overlay error: %+v", errResp)
+		setRetryAfter(c, err)
+		return c.JSON(status, errResp)
 	}
 
 	duration := time.Since(startTime)
@@ -123,7 +251,17 @@ func (ctrl *AggregationController) GetOptimizedCandles(c echo.Context) error {
 	c.Response().Header().Set("Cache-Control", "public, max-age=30")
 	c.Response().Header().Set("X-Data-Count", strconv.Itoa(response.N))
 	c.Response().Header().Set("X-Response-Time", duration.String())
-	c.Response().Header().Set("X-Cache-Key", fmt.Sprintf("agg:candles:%s:%s:%d", symbol, interval, limit))
+	cacheKey := fmt.Sprintf("agg:candles:%s:%s:%d", symbol, interval, limit)
+	if source == "composite" {
+		cacheKey = fmt.Sprintf("agg:candles:composite:%s:%s:%d", response.S, interval, limit)
+	}
+	if !before.IsZero() && before.Before(time.Now()) {
+		cacheKey += fmt.Sprintf(":before:%d", before.UnixMilli())
+	}
+	if len(include) > 0 {
+		cacheKey += ":include:" + strings.Join(include, ",")
+	}
+	c.Response().Header().Set("X-Cache-Key", cacheKey)
 
 	log.Printf("[AggregationController] Successfully returned %d candles in %v", response.N, duration)
 	return c.JSON(http.StatusOK, response)
@@ -144,11 +282,15 @@ func (ctrl *AggregationController) GetServiceStats(c echo.Context) error {
 	})
 }
 
-// GetVolumeProfile returns volume profile data for a symbol
-// GET /api/v1/aggregation/volume-profile/:symbol?hours=24
+// GetVolumeProfile returns volume profile data for a symbol, over the last hours or a fixed range,
+// as one profile or one per trading session occurrence (?sessions=all or ?sessions=asia,london)
+// GET /api/v1/aggregation/volume-profile/:symbol?hours=24&bucket=10 or ?start=...&end=...[&asOf=...][&sessions=...]
 func (ctrl *AggregationController) GetVolumeProfile(c echo.Context) error {
 	startTime := time.Now()
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
 	hoursStr := c.QueryParam("hours")
 
 	log.Printf("[AggregationController] GetVolumeProfile request: symbol=%s, hours=%s", symbol, hoursStr)
@@ -188,18 +330,71 @@ func (ctrl *AggregationController) GetVolumeProfile(c echo.Context) error {
 		}
 	}
 
+	// Optional fixed bucket size, matching the grid of live "vp_delta" updates
+	bucketSize := 0.0
+	if bucketStr := c.QueryParam("bucket"); bucketStr != "" {
+		parsedBucket, err := strconv.ParseFloat(bucketStr, 64)
+		if err != nil || parsedBucket <= 0 {
+			errResp := ErrorResponse{
+				Error:   "Invalid parameter value",
+				Message: fmt.Sprintf("Bucket must be a positive number, got: %s", bucketStr),
+				Code:    "INVALID_BUCKET_SIZE",
+			}
+			log.Printf("[AggregationController] Validation error: %+v", errResp)
+			return c.JSON(http.StatusBadRequest, errResp)
+		}
+		bucketSize = parsedBucket
+	}
+
 	endTime := time.Now()
 	startTimeRange := endTime.Add(-time.Duration(hours) * time.Hour)
 
+	// A fixed range is versioned, and may be requested as of an earlier computation
+	fixedRange, asOf, errResp := parseVersionedRange(c)
+	if errResp != nil {
+		log.Printf("[AggregationController] Validation error: %+v", *errResp)
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+	if fixedRange {
+		startTimeRange, endTime, _ = parseFixedRange(c)
+	}
+	if sessions, ok := c.QueryParams()["sessions"]; ok {
+		if !asOf.IsZero() {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid parameter value",
+				Message: "asOf is not supported with sessions",
+				Code:    "INVALID_AS_OF",
+			})
+		}
+		return ctrl.getSessionVolumeProfiles(c, symbol, startTimeRange, endTime, strings.Join(sessions, ","), bucketSize)
+	}
+	if !asOf.IsZero() && ctrl.historyService == nil {
+		return aggregateHistoryDisabled(c)
+	}
+
 	log.Printf("[AggregationController] Calling volume profile service: symbol=%s, timeRange=%v to %v", symbol, startTimeRange, endTime)
 
-	volumeProfile, err := ctrl.aggregationService.GetVolumeProfile(c.Request().Context(), symbol, startTimeRange, endTime)
+	var volumeProfile *models.VolumeProfile
+	var version *models.AggregateVersion
+	var err error
+	if fixedRange && ctrl.historyService != nil {
+		volumeProfile, version, err = ctrl.historyService.VolumeProfile(c.Request().Context(), symbol, startTimeRange, endTime, asOf)
+	} else {
+		volumeProfile, err = ctrl.aggregationService.GetVolumeProfile(c.Request().Context(), symbol, startTimeRange, endTime)
+	}
 	if err != nil {
 		duration := time.Since(startTime)
+		status, code := http.StatusInternalServerError, "VOLUME_PROFILE_ERROR"
+		switch {
+		case errors.Is(err, services.ErrAggregateVersionNotFound):
+			status, code = http.StatusNotFound, "AGGREGATE_VERSION_NOT_FOUND"
+		case strings.HasPrefix(err.Error(), "validation failed"):
+			status, code = http.StatusBadRequest, "INVALID_RANGE"
+		}
 		errResp := ErrorResponse{
 			Error:   "Service error",
 			Message: fmt.Sprintf("Failed to get volume profile: %s", err.Error()),
-			Code:    "VOLUME_PROFILE_ERROR",
+			Code:    code,
 			Details: map[string]string{
 				"symbol":   symbol,
 				"hours":    strconv.Itoa(hours),
@@ -207,7 +402,12 @@ func (ctrl *AggregationController) GetVolumeProfile(c echo.Context) error {
 			},
 		}
 		log.Printf("[AggregationController] Volume profile error after %v: %+v", duration, errResp)
-		return c.JSON(http.StatusInternalServerError, errResp)
+		return c.JSON(status, errResp)
+	}
+	setAggregateVersion(c, version)
+
+	if bucketSize > 0 {
+		volumeProfile = volumeProfile.Rebucket(bucketSize)
 	}
 
 	duration := time.Since(startTime)
@@ -221,10 +421,280 @@ func (ctrl *AggregationController) GetVolumeProfile(c echo.Context) error {
 	return c.JSON(http.StatusOK, volumeProfile)
 }
 
-// GetFootprintData returns footprint chart data
-// GET /api/v1/aggregation/footprint/:symbol/:interval?limit=100
+// getSessionVolumeProfiles responds with a volume profile per trading session occurrence in the
+// range; sessions lists session names, or is empty or "all" for every configured session
+func (ctrl *AggregationController) getSessionVolumeProfiles(c echo.Context, symbol string, startTime, endTime time.Time, sessions string, bucketSize float64) error {
+	var names []string
+	if sessions != "" && sessions != "all" {
+		for _, name := range strings.Split(sessions, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	profiles, err := ctrl.aggregationService.GetSessionVolumeProfiles(c.Request().Context(), symbol, startTime, endTime, names)
+	if err != nil {
+		status, code := http.StatusInternalServerError, "VOLUME_PROFILE_ERROR"
+		switch {
+		case strings.HasPrefix(err.Error(), "validation failed") && strings.Contains(err.Error(), "session"):
+			status, code = http.StatusBadRequest, "INVALID_SESSIONS"
+		case strings.HasPrefix(err.Error(), "validation failed"):
+			status, code = http.StatusBadRequest, "INVALID_RANGE"
+		}
+		return c.JSON(status, ErrorResponse{
+			Error:   "Service error",
+			Message: fmt.Sprintf("Failed to get session volume profiles: %s", err.Error()),
+			Code:    code,
+			Details: map[string]string{
+				"symbol": symbol,
+			},
+		})
+	}
+
+	// Bucketed copies, leaving the cached profiles as they are
+	if bucketSize > 0 {
+		bucketed := *profiles
+		bucketed.P = make([]models.SessionVolumeProfile, len(profiles.P))
+		for i, profile := range profiles.P {
+			profile.VolumeProfile = profile.VolumeProfile.Rebucket(bucketSize)
+			bucketed.P[i] = profile
+		}
+		profiles = &bucketed
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=120")
+	c.Response().Header().Set("X-Profiles-Count", strconv.Itoa(len(profiles.P)))
+	return c.JSON(http.StatusOK, profiles)
+}
+
+// GetCVD returns the cumulative volume delta of a fixed range, computed now or as of an earlier computation
+// GET /api/v1/aggregation/cvd/:symbol/:interval?start=...&end=...[&asOf=...]
+func (ctrl *AggregationController) GetCVD(c echo.Context) error {
+	if ctrl.historyService == nil {
+		return aggregateHistoryDisabled(c)
+	}
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+	interval := c.Param("interval")
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	fixedRange, asOf, errResp := parseVersionedRange(c)
+	if errResp != nil {
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+	if !fixedRange {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "start and end are required",
+		})
+	}
+	start, end, _ := parseFixedRange(c)
+
+	series, version, err := ctrl.historyService.CVD(c.Request().Context(), symbol, interval, start, end, asOf)
+	if err != nil {
+		return aggregateHistoryError(c, err)
+	}
+	setAggregateVersion(c, version)
+	c.Response().Header().Set("X-Candles-Count", strconv.Itoa(len(series.D)))
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// GetVWAP returns the session VWAP, restarting at every UTC midnight, or the VWAP anchored at a
+// timestamp, with standard deviation bands
+// GET /api/v1/aggregation/vwap/:symbol?interval=1m[&anchor=...][&start=...&end=...][&bands=1,2][&source=candles|trades]
+func (ctrl *AggregationController) GetVWAP(c echo.Context) error {
+	if ctrl.vwapService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "VWAP is not available",
+		})
+	}
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "1m"
+	}
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	params := services.VWAPParams{
+		Symbol:   symbol,
+		Interval: interval,
+		Source:   c.QueryParam("source"),
+		Bands:    []float64{1, 2},
+	}
+	if params.Source == "" {
+		params.Source = models.VWAPSourceCandles
+	}
+	var err error
+	if anchor := c.QueryParam("anchor"); anchor != "" {
+		if params.Anchor, err = parseAnchorTime(anchor); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "anchor must be Unix milliseconds or an RFC3339 time",
+			})
+		}
+	}
+	for name, value := range map[string]*time.Time{"start": &params.Start, "end": &params.End} {
+		if param := c.QueryParam(name); param != "" {
+			if *value, err = parseAnchorTime(param); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": name + " must be Unix milliseconds or an RFC3339 time",
+				})
+			}
+		}
+	}
+	if bands := c.QueryParam("bands"); bands != "" {
+		params.Bands = params.Bands[:0]
+		for _, value := range strings.Split(bands, ",") {
+			multiplier, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "bands must be comma-separated standard deviation multipliers, such as 1,2,3",
+				})
+			}
+			params.Bands = append(params.Bands, multiplier)
+		}
+	}
+
+	series, err := ctrl.vwapService.GetVWAP(c.Request().Context(), params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case strings.HasPrefix(err.Error(), "validation failed"):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrQueryTimeout):
+			status = http.StatusGatewayTimeout
+		}
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	c.Response().Header().Set("X-Candles-Count", strconv.Itoa(len(series.D)))
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// GetAggregateVersions lists the stored versions of a fixed-range aggregate, newest first
+// GET /api/v1/aggregation/versions/:kind/:symbol?start=...&end=...[&interval=1m][&limit=100]
+func (ctrl *AggregationController) GetAggregateVersions(c echo.Context) error {
+	if ctrl.historyService == nil {
+		return aggregateHistoryDisabled(c)
+	}
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+	start, end, err := parseFixedRange(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "start and end are required, as Unix milliseconds or RFC3339 times",
+		})
+	}
+
+	kind := c.Param("kind")
+	versions, err := ctrl.historyService.Versions(c.Request().Context(), kind, symbol, c.QueryParam("interval"), start, end, queryInt(c, "limit", 100, 1, 500))
+	if err != nil {
+		return aggregateHistoryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"kind":     kind,
+		"symbol":   symbol,
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+// parseVersionedRange reads the optional start, end and asOf parameters of a versioned aggregate
+// fixedRange is set when start and end are both given; asOf needs a fixed range
+func parseVersionedRange(c echo.Context) (fixedRange bool, asOf time.Time, errResp *ErrorResponse) {
+	if c.QueryParam("start") != "" || c.QueryParam("end") != "" {
+		if _, _, err := parseFixedRange(c); err != nil {
+			return false, time.Time{}, &ErrorResponse{
+				Error:   "Invalid parameter value",
+				Message: "start and end must both be Unix milliseconds or RFC3339 times",
+				Code:    "INVALID_RANGE",
+			}
+		}
+		fixedRange = true
+	}
+
+	if asOfParam := c.QueryParam("asOf"); asOfParam != "" {
+		parsed, err := parseAnchorTime(asOfParam)
+		if err != nil {
+			return false, time.Time{}, &ErrorResponse{
+				Error:   "Invalid parameter value",
+				Message: "asOf must be Unix milliseconds or an RFC3339 time",
+				Code:    "INVALID_AS_OF",
+			}
+		}
+		if !fixedRange {
+			return false, time.Time{}, &ErrorResponse{
+				Error:   "Invalid parameter value",
+				Message: "asOf requires a fixed range (start and end)",
+				Code:    "INVALID_AS_OF",
+			}
+		}
+		asOf = parsed
+	}
+	return fixedRange, asOf, nil
+}
+
+// parseFixedRange parses the start and end parameters
+func parseFixedRange(c echo.Context) (start, end time.Time, err error) {
+	if start, err = parseAnchorTime(c.QueryParam("start")); err != nil {
+		return
+	}
+	end, err = parseAnchorTime(c.QueryParam("end"))
+	return
+}
+
+// setAggregateVersion identifies the stored version an aggregate response came from
+// Clients pass X-Aggregate-Computed-At back as asOf to get the same result later
+func setAggregateVersion(c echo.Context, version *models.AggregateVersion) {
+	if version == nil {
+		return
+	}
+	c.Response().Header().Set("X-Aggregate-Version", strconv.FormatInt(version.ID, 10))
+	c.Response().Header().Set("X-Aggregate-Computed-At", version.ValidFrom.UTC().Format(time.RFC3339Nano))
+}
+
+// aggregateHistoryError maps aggregate history service errors
+func aggregateHistoryError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrAggregateVersionNotFound):
+		status = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "validation failed"):
+		status = http.StatusBadRequest
+	}
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
+
+// aggregateHistoryDisabled responds when aggregate versioning is not configured
+func aggregateHistoryDisabled(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{
+		"error": "Aggregate versioning is not available",
+	})
+}
+
+// GetFootprintData returns footprint chart data, the stored bars opening in [start, end) when a range is given
+// GET /api/v1/aggregation/footprint/:symbol/:interval?limit=100 or ?start=...&end=...
 func (ctrl *AggregationController) GetFootprintData(c echo.Context) error {
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
 	interval := c.Param("interval")
 
 	limit := 100
@@ -240,9 +710,33 @@ func (ctrl *AggregationController) GetFootprintData(c echo.Context) error {
 		})
 	}
 
-	footprint, err := ctrl.aggregationService.GetFootprintData(c.Request().Context(), symbol, interval, limit)
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	var footprint []models.FootprintCandle
+	var err error
+	if startParam, endParam := c.QueryParam("start"), c.QueryParam("end"); startParam != "" || endParam != "" {
+		start, startErr := parseAnchorTime(startParam)
+		end, endErr := parseAnchorTime(endParam)
+		if startErr != nil || endErr != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "start and end must both be Unix milliseconds or RFC3339 times",
+			})
+		}
+		footprint, err = ctrl.aggregationService.GetFootprintRange(c.Request().Context(), symbol, interval, start, end)
+	} else {
+		footprint, err = ctrl.aggregationService.GetFootprintData(c.Request().Context(), symbol, interval, limit)
+	}
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrFootprintStorageDisabled):
+			status = http.StatusServiceUnavailable
+		case strings.HasPrefix(err.Error(), "validation failed"):
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, map[string]string{
 			"error": "failed to get footprint data: " + err.Error(),
 		})
 	}
@@ -262,7 +756,10 @@ func (ctrl *AggregationController) GetFootprintData(c echo.Context) error {
 // GetLiquidations returns detected liquidation events
 // GET /api/v1/aggregation/liquidations/:symbol?hours=1
 func (ctrl *AggregationController) GetLiquidations(c echo.Context) error {
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
 
 	hours := 1
 	if hoursStr := c.QueryParam("hours"); hoursStr != "" {
@@ -300,7 +797,10 @@ func (ctrl *AggregationController) GetLiquidations(c echo.Context) error {
 // GetHeatmap returns price/volume heatmap data
 // GET /api/v1/aggregation/heatmap/:symbol?hours=6&resolution=100
 func (ctrl *AggregationController) GetHeatmap(c echo.Context) error {
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
 
 	hours := 6
 	if hoursStr := c.QueryParam("hours"); hoursStr != "" {
@@ -350,6 +850,9 @@ func (ctrl *AggregationController) GetAggregatedMultiData(c echo.Context) error
 		IncludeLiq bool     `json:"include_liquidations"`
 		VPHours    int      `json:"vp_hours"`
 		LiqHours   int      `json:"liq_hours"`
+		Snapshot   bool     `json:"snapshot"`    // Pin every section to one last closed candle boundary
+		VolumeUnit string   `json:"volume_unit"` // base, quote or contracts; empty keeps the stored unit
+		Include    []string `json:"include"`     // Derivatives overlays merged into the candles (funding, oi)
 	}
 
 	var req MultiRequest
@@ -369,53 +872,75 @@ func (ctrl *AggregationController) GetAggregatedMultiData(c echo.Context) error
 		req.Intervals = []string{"1m", "5m", "15m", "1h"}
 	}
 
-	if req.Limit <= 0 || req.Limit > 1000 {
-		req.Limit = 500
-	}
-
-	response := map[string]interface{}{
-		"symbol":  req.Symbol,
-		"candles": make(map[string]interface{}),
-	}
-
-	// Get candles for all intervals
 	for _, interval := range req.Intervals {
-		candles, err := ctrl.aggregationService.GetAggregatedCandles(c.Request().Context(), req.Symbol, interval, req.Limit)
-		if err == nil {
-			response["candles"].(map[string]interface{})[interval] = candles
+		if !models.IsValidInterval(interval) {
+			return invalidInterval(c, interval)
 		}
 	}
 
-	// Get volume profile if requested
-	if req.IncludeVP {
-		if req.VPHours <= 0 || req.VPHours > 168 {
-			req.VPHours = 24
-		}
-		endTime := time.Now()
-		startTime := endTime.Add(-time.Duration(req.VPHours) * time.Hour)
+	if req.VolumeUnit != "" && !models.IsValidVolumeUnit(req.VolumeUnit) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid volume_unit %q, use base, quote or contracts", req.VolumeUnit),
+		})
+	}
 
-		vp, err := ctrl.aggregationService.GetVolumeProfile(c.Request().Context(), req.Symbol, startTime, endTime)
-		if err == nil {
-			response["volume_profile"] = vp
-		}
+	include, err := parseIncludes(strings.Join(req.Include, ","))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
 	}
 
-	// Get liquidations if requested
-	if req.IncludeLiq {
-		if req.LiqHours <= 0 || req.LiqHours > 24 {
-			req.LiqHours = 1
-		}
-		timeRange := time.Duration(req.LiqHours) * time.Hour
+	if req.Limit <= 0 || req.Limit > 1000 {
+		req.Limit = 500
+	}
 
-		liquidations, err := ctrl.aggregationService.GetLiquidations(c.Request().Context(), req.Symbol, timeRange)
-		if err == nil {
-			response["liquidations"] = liquidations
-		}
+	if req.VPHours <= 0 || req.VPHours > 168 {
+		req.VPHours = 24
 	}
+	if req.LiqHours <= 0 || req.LiqHours > 24 {
+		req.LiqHours = 1
+	}
+
+	// Sections are fetched concurrently; failures and deadline misses are reported per section
+	response := ctrl.aggregationService.GetMultiData(c.Request().Context(), services.MultiDataParams{
+		Symbol:               req.Symbol,
+		Intervals:            req.Intervals,
+		Limit:                req.Limit,
+		IncludeVolumeProfile: req.IncludeVP,
+		VolumeProfileHours:   req.VPHours,
+		IncludeLiquidations:  req.IncludeLiq,
+		LiquidationHours:     req.LiqHours,
+		Snapshot:             req.Snapshot,
+		VolumeUnit:           req.VolumeUnit,
+		Include:              include,
+	})
 
 	// Ultra-fast response headers
 	c.Response().Header().Set("Cache-Control", "public, max-age=30")
 	c.Response().Header().Set("X-Multi-Response", "true")
+	if response.Partial {
+		c.Response().Header().Set("Cache-Control", "no-store")
+		c.Response().Header().Set("X-Partial-Response", "true")
+	}
 
 	return c.JSON(http.StatusOK, response)
 }
+
+// parseIncludes splits a comma-separated list of derivatives overlays, dropping blanks and duplicates
+func parseIncludes(value string) ([]string, error) {
+	var includes []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if !models.IsValidCandleInclude(name) {
+			return nil, fmt.Errorf("invalid include %q, use funding or oi", name)
+		}
+		seen[name] = true
+		includes = append(includes, name)
+	}
+	return includes, nil
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AlertController handles user price alert requests
+type AlertController struct {
+	alertService *services.AlertService
+}
+
+// NewAlertController creates a new price alert controller
+func NewAlertController(alertService *services.AlertService) *AlertController {
+	return &AlertController{
+		alertService: alertService,
+	}
+}
+
+// GetAlerts returns the price alerts of the requesting user (?state= filters by state)
+func (ac *AlertController) GetAlerts(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	alerts, err := ac.alertService.GetAlerts(c.Request().Context(), userID, c.QueryParam("state"))
+	if err != nil {
+		return alertError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":  len(alerts),
+		"alerts": alerts,
+	})
+}
+
+// GetAlert returns a single price alert
+func (ac *AlertController) GetAlert(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := alertID(c)
+	if !ok {
+		return invalidAlertID(c)
+	}
+
+	alert, err := ac.alertService.GetAlert(c.Request().Context(), userID, id)
+	if err != nil {
+		return alertError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, alert)
+}
+
+// CreateAlert creates a price alert, evaluated against live prices until it fires
+func (ac *AlertController) CreateAlert(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.CreatePriceAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	alert, err := ac.alertService.CreateAlert(c.Request().Context(), userID, &req)
+	if err != nil {
+		return alertError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, alert)
+}
+
+// PreviewAlert evaluates an alert definition against past candles without saving it,
+// returning when it would have fired
+func (ac *AlertController) PreviewAlert(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.AlertPreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	preview, err := ac.alertService.PreviewAlert(c.Request().Context(), &req)
+	if err != nil {
+		return alertError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, preview)
+}
+
+// DeleteAlert removes a price alert
+func (ac *AlertController) DeleteAlert(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := alertID(c)
+	if !ok {
+		return invalidAlertID(c)
+	}
+
+	if err := ac.alertService.DeleteAlert(c.Request().Context(), userID, id); err != nil {
+		return alertError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Alert deleted successfully",
+	})
+}
+
+// alertID parses the alert ID path parameter
+func alertID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// invalidAlertID responds to requests with a malformed alert ID
+func invalidAlertID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Invalid alert ID",
+	})
+}
+
+// alertError maps price alert service errors to HTTP responses
+func alertError(c echo.Context, err error) error {
+	message := err.Error()
+	switch {
+	case message == "alert not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Alert not found",
+		})
+	case strings.HasPrefix(message, "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
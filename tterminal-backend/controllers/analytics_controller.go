@@ -0,0 +1,333 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AnalyticsController handles quant analytics requests
+type AnalyticsController struct {
+	analyticsService *services.AnalyticsService
+	spreadService    *services.SpreadService // Optional; nil when no spread pairs are configured
+
+	// Optional; nil when no hidden liquidity symbols are configured
+	hiddenLiquidityService *services.HiddenLiquidityService
+}
+
+// NewAnalyticsController creates a new analytics controller
+func NewAnalyticsController(analyticsService *services.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetFlowToxicity returns VPIN and order flow imbalance for a symbol
+// GET /api/v1/analytics/toxicity/:symbol
+func (ac *AnalyticsController) GetFlowToxicity(c echo.Context) error {
+	symbol := c.Param("symbol")
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Symbol is required",
+		})
+	}
+
+	params := services.FlowToxicityParams{
+		Interval:      c.QueryParam("interval"),
+		Bars:          queryInt(c, "limit", 100, 1, 1000),
+		VPINWindow:    queryInt(c, "vpin_window", 50, 5, 500),
+		RollingWindow: queryInt(c, "rolling_window", 20, 2, 500),
+	}
+	if params.Interval == "" {
+		params.Interval = "5m"
+	}
+	if bucketStr := c.QueryParam("bucket_volume"); bucketStr != "" {
+		bucketVolume, err := strconv.ParseFloat(bucketStr, 64)
+		if err != nil || bucketVolume <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "bucket_volume must be a positive number",
+			})
+		}
+		params.BucketVolume = bucketVolume
+	}
+
+	response, err := ac.analyticsService.GetFlowToxicity(c.Request().Context(), symbol, params)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetSupportResistance returns support/resistance levels detected from persisted book snapshots
+// GET /api/v1/analytics/levels/:symbol
+func (ac *AnalyticsController) GetSupportResistance(c echo.Context) error {
+	symbol := c.Param("symbol")
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Symbol is required",
+		})
+	}
+
+	params := services.SupportResistanceParams{
+		Hours: queryInt(c, "hours", 24, 1, 720),
+		Limit: queryInt(c, "limit", 10, 1, 100),
+	}
+	floatParams := []struct {
+		name string
+		dest *float64
+	}{
+		{"bucket", &params.BucketSize},
+		{"min_persistence", &params.MinPersistence},
+		{"min_size_ratio", &params.MinSizeRatio},
+	}
+	for _, p := range floatParams {
+		value := c.QueryParam(p.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": p.name + " must be a positive number",
+			})
+		}
+		*p.dest = parsed
+	}
+
+	response, err := ac.analyticsService.GetSupportResistance(c.Request().Context(), symbol, params)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=60")
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetRollingWindow returns trailing-window sum/avg/max/min of bar volume, delta and range
+// GET /api/v1/analytics/rolling/:symbol
+func (ac *AnalyticsController) GetRollingWindow(c echo.Context) error {
+	symbol := c.Param("symbol")
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Symbol is required",
+		})
+	}
+
+	params := services.RollingWindowParams{
+		Interval: c.QueryParam("interval"),
+		Window:   queryInt(c, "window", 20, 2, 500),
+		Limit:    queryInt(c, "limit", 200, 1, 1000),
+		Fields:   queryList(c, "fields", []string{models.RollingFieldVolume, models.RollingFieldDelta, models.RollingFieldRange}),
+		Stats:    queryList(c, "stats", []string{models.RollingStatSum, models.RollingStatAvg, models.RollingStatMax}),
+		Align:    c.QueryParam("align"),
+	}
+	if params.Interval == "" {
+		params.Interval = "5m"
+	}
+
+	response, err := ac.analyticsService.GetRollingWindow(c.Request().Context(), symbol, params)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetCompare returns several symbols' closes rebased to 100 on a shared time axis
+// GET /api/v1/analytics/compare?symbols=BTCUSDT,ETHUSDT,BYBIT:SOLUSDT&interval=1h&start=...&end=...
+func (ac *AnalyticsController) GetCompare(c echo.Context) error {
+	var symbols []string
+	for _, symbol := range strings.Split(c.QueryParam("symbols"), ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+	barDuration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "unsupported interval: " + interval,
+		})
+	}
+
+	// The range defaults to the last 200 bars
+	endTime := time.Now().UTC()
+	if value := c.QueryParam("end"); value != "" {
+		parsed, err := parseAnchorTime(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "end must be Unix milliseconds or RFC3339",
+			})
+		}
+		endTime = parsed
+	}
+	startTime := endTime.Add(-200 * barDuration)
+	if value := c.QueryParam("start"); value != "" {
+		parsed, err := parseAnchorTime(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "start must be Unix milliseconds or RFC3339",
+			})
+		}
+		startTime = parsed
+	}
+
+	response, err := ac.analyticsService.GetCompare(c.Request().Context(), symbols, interval, startTime, endTime)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, response)
+}
+
+// SetSpreadService enables the spread endpoints
+func (ac *AnalyticsController) SetSpreadService(spreadService *services.SpreadService) {
+	ac.spreadService = spreadService
+}
+
+// GetSpreads returns the latest spot-perp and cross-exchange spread of every monitored pair
+// GET /api/v1/analytics/spreads
+func (ac *AnalyticsController) GetSpreads(c echo.Context) error {
+	if ac.spreadService == nil {
+		return spreadsDisabled(c)
+	}
+
+	spreads := ac.spreadService.GetLatest()
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"spreads":       spreads,
+		"arbitrage_bps": ac.spreadService.ArbitrageBps(),
+		"count":         len(spreads),
+		"timestamp":     time.Now().UnixMilli(),
+	})
+}
+
+// GetSpreadHistory returns a monitored pair's stored spreads, bucketed by interval
+// GET /api/v1/analytics/spreads/history?pair=BTCUSDT/BYBIT:BTCUSDT&interval=1m&hours=24
+func (ac *AnalyticsController) GetSpreadHistory(c echo.Context) error {
+	if ac.spreadService == nil {
+		return spreadsDisabled(c)
+	}
+
+	pair := c.QueryParam("pair")
+	if pair == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "pair query parameter is required",
+		})
+	}
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "1m"
+	}
+
+	history, err := ac.spreadService.GetHistory(c.Request().Context(), pair, interval, queryInt(c, "hours", 24, 1, 168))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrUnknownSpreadPair):
+			status = http.StatusNotFound
+		case strings.HasPrefix(err.Error(), "validation failed"):
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, history)
+}
+
+// spreadsDisabled responds when spread monitoring is not configured
+func spreadsDisabled(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{
+		"error": "spread monitoring is disabled; set SPREAD_PAIRS to enable it",
+	})
+}
+
+// SetHiddenLiquidityService enables the hidden liquidity endpoint
+func (ac *AnalyticsController) SetHiddenLiquidityService(hiddenLiquidityService *services.HiddenLiquidityService) {
+	ac.hiddenLiquidityService = hiddenLiquidityService
+}
+
+// GetHiddenLiquidity returns the hidden and iceberg size inferred per price level of a tracked symbol
+// GET /api/v1/experimental/hidden-liquidity/:symbol?min_hidden=0.5&limit=50
+func (ac *AnalyticsController) GetHiddenLiquidity(c echo.Context) error {
+	if ac.hiddenLiquidityService == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "hidden liquidity inference is disabled; set HIDDEN_LIQUIDITY_SYMBOLS to enable it",
+		})
+	}
+
+	minHidden := 0.0
+	if value := c.QueryParam("min_hidden"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "min_hidden must be a non-negative number",
+			})
+		}
+		minHidden = parsed
+	}
+
+	estimate, err := ac.hiddenLiquidityService.GetEstimate(c.Param("symbol"), minHidden, queryInt(c, "limit", 50, 1, 200))
+	if err != nil {
+		if errors.Is(err, services.ErrHiddenLiquiditySymbol) {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"error":   err.Error(),
+				"tracked": ac.hiddenLiquidityService.Symbols(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, estimate)
+}
+
+// queryInt parses an integer query parameter, falling back to def when missing or out of range
+func queryInt(c echo.Context, name string, def, min, max int) int {
+	if value := c.QueryParam(name); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= min && parsed <= max {
+			return parsed
+		}
+	}
+	return def
+}
+
+// queryList parses a comma-separated query parameter, falling back to def when missing
+func queryList(c echo.Context, name string, def []string) []string {
+	value := c.QueryParam(name)
+	if value == "" {
+		return def
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
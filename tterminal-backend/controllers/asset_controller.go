@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AssetController handles asset requests: underlyings with their instruments across exchanges
+type AssetController struct {
+	assetService *services.AssetService
+}
+
+// NewAssetController creates a new asset controller
+func NewAssetController(assetService *services.AssetService) *AssetController {
+	return &AssetController{
+		assetService: assetService,
+	}
+}
+
+// GetAssets returns every asset with its instruments, or the asset trading a symbol
+// GET /api/v1/assets?symbol=BYBIT:BTCUSDT
+func (ac *AssetController) GetAssets(c echo.Context) error {
+	assets := ac.assetService.GetAssets()
+	if symbol := c.QueryParam("symbol"); symbol != "" {
+		code, ok := ac.assetService.AssetOf(symbol)
+		matched := []models.Asset{}
+		for _, asset := range assets {
+			if ok && asset.Code == code {
+				matched = append(matched, asset)
+			}
+		}
+		assets = matched
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":  len(assets),
+		"assets": assets,
+	})
+}
+
+// GetAsset returns an asset with its whole instrument family
+// GET /api/v1/assets/:code
+func (ac *AssetController) GetAsset(c echo.Context) error {
+	asset, err := ac.assetService.GetAsset(c.Param("code"))
+	if err != nil {
+		return assetError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, asset)
+}
+
+// GetMetrics aggregates an asset's instruments over the trailing hours
+// GET /api/v1/assets/:code/metrics?hours=24
+func (ac *AssetController) GetMetrics(c echo.Context) error {
+	metrics, err := ac.assetService.GetMetrics(c.Request().Context(), c.Param("code"), queryInt(c, "hours", 24, 1, 720))
+	if err != nil {
+		return assetError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, metrics)
+}
+
+// SetAsset creates an asset or renames an existing one
+// PUT /api/v1/admin/assets/:code
+func (ac *AssetController) SetAsset(c echo.Context) error {
+	var req models.SetAssetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	asset, err := ac.assetService.SetAsset(c.Request().Context(), c.Param("code"), &req)
+	if err != nil {
+		return assetError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, asset)
+}
+
+// DeleteAsset removes an asset with its instruments
+// DELETE /api/v1/admin/assets/:code
+func (ac *AssetController) DeleteAsset(c echo.Context) error {
+	if err := ac.assetService.DeleteAsset(c.Request().Context(), c.Param("code")); err != nil {
+		return assetError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Asset deleted successfully",
+	})
+}
+
+// SetInstrument adds an instrument to an asset
+// PUT /api/v1/admin/assets/:code/instruments
+func (ac *AssetController) SetInstrument(c echo.Context) error {
+	var req models.SetAssetInstrumentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	instrument, err := ac.assetService.SetInstrument(c.Request().Context(), c.Param("code"), &req)
+	if err != nil {
+		return assetError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, instrument)
+}
+
+// DeleteInstrument removes an instrument from an asset
+// DELETE /api/v1/admin/assets/:code/instruments/:id
+func (ac *AssetController) DeleteInstrument(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid instrument ID",
+		})
+	}
+
+	if err := ac.assetService.DeleteInstrument(c.Request().Context(), c.Param("code"), id); err != nil {
+		return assetError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Asset instrument deleted successfully",
+	})
+}
+
+// assetError maps asset service errors to HTTP responses
+func assetError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrAssetNotFound), errors.Is(err, services.ErrAssetInstrumentNotFound):
+		status = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "validation failed"):
+		status = http.StatusBadRequest
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
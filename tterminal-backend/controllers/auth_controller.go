@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"tterminal-backend/internal/audit"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuthController handles user registration, login and account requests
+type AuthController struct {
+	authService *services.AuthService // nil when JWT_SECRET is not set
+}
+
+// NewAuthController creates a new auth controller
+func NewAuthController(authService *services.AuthService) *AuthController {
+	return &AuthController{
+		authService: authService,
+	}
+}
+
+// Register creates an account and returns an access token
+func (ac *AuthController) Register(c echo.Context) error {
+	if ac.authService == nil {
+		return authDisabled(c)
+	}
+
+	var req models.CredentialsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	response, err := ac.authService.Register(c.Request().Context(), &req)
+	if err != nil {
+		return authError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// Login exchanges an account's email and password for an access token
+func (ac *AuthController) Login(c echo.Context) error {
+	if ac.authService == nil {
+		return authDisabled(c)
+	}
+
+	var req models.CredentialsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	response, err := ac.authService.Login(c.Request().Context(), &req)
+	if err != nil {
+		return authError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetMe returns the account of the authenticated user
+func (ac *AuthController) GetMe(c echo.Context) error {
+	if ac.authService == nil {
+		return authDisabled(c)
+	}
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	user, err := ac.authService.GetUser(c.Request().Context(), userID)
+	if err != nil {
+		return authError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// GetUsers lists accounts with their roles (admin)
+func (ac *AuthController) GetUsers(c echo.Context) error {
+	if ac.authService == nil {
+		return authDisabled(c)
+	}
+
+	users, err := ac.authService.ListUsers(c.Request().Context(), queryInt(c, "limit", 100, 1, 500), queryInt(c, "offset", 0, 0, math.MaxInt32))
+	if err != nil {
+		return authError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count": len(users),
+		"users": users,
+	})
+}
+
+// SetUserRole changes an account's role (admin)
+func (ac *AuthController) SetUserRole(c echo.Context) error {
+	if ac.authService == nil {
+		return authDisabled(c)
+	}
+
+	var req models.SetRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	user, err := ac.authService.SetRole(c.Request().Context(), c.Param("id"), req.Role)
+	if err != nil {
+		return authError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// SetUserPermissions changes the permissions an account holds as an admin (admin)
+func (ac *AuthController) SetUserPermissions(c echo.Context) error {
+	if ac.authService == nil {
+		return authDisabled(c)
+	}
+
+	var req models.SetPermissionsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	ctx := c.Request().Context()
+	before, err := ac.authService.GetUser(ctx, c.Param("id"))
+	if err != nil {
+		return authError(c, err)
+	}
+	user, err := ac.authService.SetPermissions(ctx, c.Param("id"), req.Permissions)
+	if err != nil {
+		return authError(c, err)
+	}
+
+	audit.Record(ctx, "user", user.ID, before, user)
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// authDisabled responds when user accounts are not configured
+func authDisabled(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{
+		"error": "user accounts are disabled; set JWT_SECRET to enable them",
+	})
+}
+
+// authError responds to a failed auth service call
+func authError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Invalid email or password",
+			"code":  "INVALID_CREDENTIALS",
+		})
+	case errors.Is(err, services.ErrEmailTaken):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Email is already registered",
+			"code":  "EMAIL_TAKEN",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "User not found",
+		})
+	case strings.HasPrefix(err.Error(), "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BacktestController handles strategy backtests over stored data
+type BacktestController struct {
+	backtestService *services.BacktestService
+}
+
+// NewBacktestController creates a new backtest controller
+func NewBacktestController(backtestService *services.BacktestService) *BacktestController {
+	return &BacktestController{
+		backtestService: backtestService,
+	}
+}
+
+// Run replays stored candles or trades through a strategy and returns the equity curve, drawdown and trades
+// POST /api/v1/backtest
+func (bc *BacktestController) Run(c echo.Context) error {
+	var req models.BacktestRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid backtest: " + err.Error(),
+		})
+	}
+
+	result, err := bc.backtestService.Run(c.Request().Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrBacktestTimeout):
+			status = http.StatusGatewayTimeout
+		case strings.HasPrefix(err.Error(), "validation failed"):
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetStrategies lists the built-in strategies and their parameter defaults
+// GET /api/v1/backtest/strategies
+func (bc *BacktestController) GetStrategies(c echo.Context) error {
+	strategies := bc.backtestService.Strategies()
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":      len(strategies),
+		"strategies": strategies,
+	})
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BasketController handles symbol basket and basket footprint requests
+type BasketController struct {
+	basketService *services.BasketService
+}
+
+// NewBasketController creates a new basket controller
+func NewBasketController(basketService *services.BasketService) *BasketController {
+	return &BasketController{
+		basketService: basketService,
+	}
+}
+
+// GetBaskets returns all baskets of the requesting user
+func (bc *BasketController) GetBaskets(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	baskets, err := bc.basketService.GetBaskets(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve baskets: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":   len(baskets),
+		"baskets": baskets,
+	})
+}
+
+// GetBasket returns a single basket
+func (bc *BasketController) GetBasket(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := basketID(c)
+	if !ok {
+		return invalidBasketID(c)
+	}
+
+	basket, err := bc.basketService.GetBasket(c.Request().Context(), userID, id)
+	if err != nil {
+		return basketError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, basket)
+}
+
+// CreateBasket creates a new basket
+func (bc *BasketController) CreateBasket(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.CreateBasketRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	basket, err := bc.basketService.CreateBasket(c.Request().Context(), userID, &req)
+	if err != nil {
+		return basketError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, basket)
+}
+
+// UpdateBasket renames a basket or replaces its members
+func (bc *BasketController) UpdateBasket(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := basketID(c)
+	if !ok {
+		return invalidBasketID(c)
+	}
+
+	var req models.UpdateBasketRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	basket, err := bc.basketService.UpdateBasket(c.Request().Context(), userID, id, &req)
+	if err != nil {
+		return basketError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, basket)
+}
+
+// DeleteBasket deletes a basket
+func (bc *BasketController) DeleteBasket(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := basketID(c)
+	if !ok {
+		return invalidBasketID(c)
+	}
+
+	if err := bc.basketService.DeleteBasket(c.Request().Context(), userID, id); err != nil {
+		return basketError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Basket deleted successfully",
+	})
+}
+
+// GetBasketFootprint returns a basket's combined notional delta series
+// GET /api/v1/baskets/:id/footprint?interval=5m&limit=200
+func (bc *BasketController) GetBasketFootprint(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := basketID(c)
+	if !ok {
+		return invalidBasketID(c)
+	}
+
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "5m"
+	}
+	limit := queryInt(c, "limit", 200, 1, 1000)
+
+	response, err := bc.basketService.GetBasketFootprint(c.Request().Context(), userID, id, interval, limit)
+	if err != nil {
+		return basketError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// basketID parses the basket ID path parameter
+func basketID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// invalidBasketID responds to requests with a malformed basket ID
+func invalidBasketID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Invalid basket ID",
+	})
+}
+
+// basketError maps basket service errors to HTTP responses
+func basketError(c echo.Context, err error) error {
+	message := err.Error()
+	switch {
+	case message == "basket not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Basket not found",
+		})
+	case strings.HasPrefix(message, "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
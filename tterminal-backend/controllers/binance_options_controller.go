@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BinanceOptionsController handles Binance European options board requests
+type BinanceOptionsController struct {
+	binanceOptionsService *services.BinanceOptionsService
+}
+
+// NewBinanceOptionsController creates a new Binance options controller
+func NewBinanceOptionsController(binanceOptionsService *services.BinanceOptionsService) *BinanceOptionsController {
+	return &BinanceOptionsController{
+		binanceOptionsService: binanceOptionsService,
+	}
+}
+
+// GetBoard returns the options board of an underlying, optionally limited to one expiry ("250627")
+func (bc *BinanceOptionsController) GetBoard(c echo.Context) error {
+	board, err := bc.binanceOptionsService.GetBoard(c.Request().Context(), c.Param("underlying"), c.QueryParam("expiry"))
+	if err != nil {
+		return binanceOptionsError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, board)
+}
+
+// GetExpiries returns the ATM IV, open interest and put/call ratio of every expiry of an underlying
+func (bc *BinanceOptionsController) GetExpiries(c echo.Context) error {
+	expiries, err := bc.binanceOptionsService.GetExpiries(c.Request().Context(), c.Param("underlying"))
+	if err != nil {
+		return binanceOptionsError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, expiries)
+}
+
+// binanceOptionsError maps Binance options errors to status codes; failed Binance requests are a bad gateway
+func binanceOptionsError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	var apiErr *binance.APIError
+	switch {
+	case errors.Is(err, services.ErrBinanceOptionsDisabled):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrBinanceOptionsUnderlying), errors.Is(err, services.ErrOptionsExpiryNotFound):
+		status = http.StatusNotFound
+	case errors.As(err, &apiErr):
+		status = http.StatusBadGateway
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -1,18 +1,35 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"tterminal-backend/models"
 	"tterminal-backend/services"
 
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// defaultCandleRange is the window used when a range query omits start_time
+	defaultCandleRange = 24 * time.Hour
+	// defaultCoverageRange is the window used when a coverage report omits start_time
+	defaultCoverageRange = 7 * 24 * time.Hour
+	// maxSuggestedChunks caps the chunk list returned for oversized range queries
+	maxSuggestedChunks = 100
+	// defaultCandleTradesLimit and maxCandleTradesLimit bound candle drill-down responses
+	defaultCandleTradesLimit = 1000
+	maxCandleTradesLimit     = 10000
+)
+
 type CandleController struct {
-	candleService  *services.CandleService
-	binanceService *services.BinanceService
+	candleService      *services.CandleService
+	binanceService     *services.BinanceService
+	consistencyService *services.CandleConsistencyService
 }
 
 func NewCandleController(candleService *services.CandleService, binanceService *services.BinanceService) *CandleController {
@@ -24,7 +41,10 @@ func NewCandleController(candleService *services.CandleService, binanceService *
 
 // GetCandles retrieves candles optimized for ultra-fast frontend rendering
 func (cc *CandleController) GetCandles(c echo.Context) error {
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
 	if symbol == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Symbol is required",
@@ -47,24 +67,54 @@ func (cc *CandleController) GetCandles(c echo.Context) error {
 		interval = "1h" // default
 	}
 
-	// Use optimized method for ultra-fast response
-	response, err := cc.candleService.GetOptimizedCandles(c.Request().Context(), symbol, interval, limit)
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	priceType, err := parsePriceType(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	volumeUnit, err := parseVolumeUnit(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
+	before, err := parseAnchor(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Use optimized method for ultra-fast response
+	response, err := cc.getCandles(c, symbol, interval, priceType, before, limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+	if response, err = cc.candleService.ConvertVolume(c.Request().Context(), response, volumeUnit); err != nil {
+		return volumeUnitError(c, err)
+	}
+
 	// Set optimized headers for caching and performance
 	c.Response().Header().Set("Cache-Control", "public, max-age=30")
 	c.Response().Header().Set("Content-Type", "application/json; charset=utf-8")
-	
+	setDataAge(c, response.Stale, response.DataAge)
+
 	return c.JSON(http.StatusOK, response)
 }
 
 // GetCandlesRaw returns pre-serialized JSON for maximum performance
 func (cc *CandleController) GetCandlesRaw(c echo.Context) error {
-	symbol := c.Param("symbol")
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
 	if symbol == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Symbol is required",
@@ -87,8 +137,41 @@ func (cc *CandleController) GetCandlesRaw(c echo.Context) error {
 		interval = "1h" // default
 	}
 
-	// Get pre-serialized JSON for maximum speed
-	jsonBytes, err := cc.candleService.GetOptimizedCandlesJSON(c.Request().Context(), symbol, interval, limit)
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	priceType, err := parsePriceType(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	volumeUnit, err := parseVolumeUnit(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	before, err := parseAnchor(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	response, err := cc.getCandles(c, symbol, interval, priceType, before, limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+	if response, err = cc.candleService.ConvertVolume(c.Request().Context(), response, volumeUnit); err != nil {
+		return volumeUnitError(c, err)
+	}
+
+	// Pre-serialize JSON for maximum speed
+	jsonBytes, err := response.ToMinimalJSON()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -99,17 +182,27 @@ func (cc *CandleController) GetCandlesRaw(c echo.Context) error {
 	c.Response().Header().Set("Cache-Control", "public, max-age=30")
 	c.Response().Header().Set("Content-Type", "application/json; charset=utf-8")
 	c.Response().Header().Set("Content-Length", strconv.Itoa(len(jsonBytes)))
-	
+	setDataAge(c, response.Stale, response.DataAge)
+
 	// Return raw JSON bytes for fastest possible response
 	return c.Blob(http.StatusOK, "application/json", jsonBytes)
 }
 
+// getCandles returns the most recent candles, or the page ending before the anchor when one is set
+func (cc *CandleController) getCandles(c echo.Context, symbol, interval, priceType string, before time.Time, limit int) (*models.CandleResponse, error) {
+	if before.IsZero() {
+		return cc.candleService.GetOptimizedCandlesByPriceType(c.Request().Context(), symbol, interval, priceType, limit)
+	}
+	return cc.candleService.GetCandlesBeforeByPriceType(c.Request().Context(), symbol, interval, priceType, before, limit)
+}
+
 // FetchAndStoreCandles fetches candles from Binance and stores them
 func (cc *CandleController) FetchAndStoreCandles(c echo.Context) error {
 	var request struct {
-		Symbol   string `json:"symbol" validate:"required"`
-		Interval string `json:"interval" validate:"required"`
-		Limit    int    `json:"limit"`
+		Symbol    string `json:"symbol" validate:"required"`
+		Interval  string `json:"interval" validate:"required"`
+		Limit     int    `json:"limit"`
+		PriceType string `json:"price_type"`
 	}
 
 	if err := c.Bind(&request); err != nil {
@@ -118,16 +211,27 @@ func (cc *CandleController) FetchAndStoreCandles(c echo.Context) error {
 		})
 	}
 
-	if request.Limit == 0 {
+	if request.Limit <= 0 {
 		request.Limit = 100
 	}
 
+	if !models.IsValidInterval(request.Interval) {
+		return invalidInterval(c, request.Interval)
+	}
+
+	if request.PriceType == "" {
+		request.PriceType = models.PriceTypeLast
+	}
+	if !models.IsValidPriceType(request.PriceType) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid price_type, use last, mark or index",
+		})
+	}
+
 	// Use the optimized method which automatically fetches from Binance if needed
-	response, err := cc.candleService.GetOptimizedCandles(c.Request().Context(), request.Symbol, request.Interval, request.Limit)
+	response, err := cc.candleService.GetOptimizedCandlesByPriceType(c.Request().Context(), request.Symbol, request.Interval, request.PriceType, request.Limit)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return serviceError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -135,6 +239,7 @@ func (cc *CandleController) FetchAndStoreCandles(c echo.Context) error {
 		"symbol":     request.Symbol,
 		"interval":   request.Interval,
 		"limit":      request.Limit,
+		"price_type": request.PriceType,
 		"count":      response.N,
 		"first_time": response.F,
 		"last_time":  response.L,
@@ -155,23 +260,43 @@ func (cc *CandleController) GetLatestCandle(c echo.Context) error {
 		interval = "1h"
 	}
 
-	// Get optimized response with limit 1 for latest candle
-	response, err := cc.candleService.GetOptimizedCandles(c.Request().Context(), symbol, interval, 1)
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	priceType, err := parsePriceType(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	volumeUnit, err := parseVolumeUnit(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
+	// Get optimized response with limit 1 for latest candle
+	response, err := cc.candleService.GetOptimizedCandlesByPriceType(c.Request().Context(), symbol, interval, priceType, 1)
+	if err != nil {
+		return serviceError(c, err)
+	}
+	if response, err = cc.candleService.ConvertVolume(c.Request().Context(), response, volumeUnit); err != nil {
+		return volumeUnitError(c, err)
+	}
+
 	var latestCandle interface{}
 	if len(response.D) > 0 {
 		latestCandle = response.D[0]
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"symbol":   symbol,
-		"interval": interval,
-		"candle":   latestCandle,
+		"symbol":     symbol,
+		"interval":   interval,
+		"price_type": priceType,
+		"candle":     latestCandle,
 	})
 }
 
@@ -193,6 +318,10 @@ func (cc *CandleController) GetCandleRange(c echo.Context) error {
 		interval = "1h"
 	}
 
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
 	var startTime, endTime time.Time
 	var err error
 
@@ -214,22 +343,362 @@ func (cc *CandleController) GetCandleRange(c echo.Context) error {
 		}
 	}
 
-	candles, err := cc.candleService.GetCandleRange(c.Request().Context(), symbol, interval, startTime, endTime)
+	// Default to the last 24 hours ending now
+	if endTimeStr == "" {
+		endTime = time.Now().UTC()
+	}
+	if startTimeStr == "" {
+		startTime = endTime.Add(-defaultCandleRange)
+	}
+
+	if !startTime.Before(endTime) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "start_time must be before end_time",
+		})
+	}
+
+	if maxRange := models.MaxRangeDuration(interval); endTime.Sub(startTime) > maxRange {
+		return rangeTooLarge(c, interval, startTime, endTime, maxRange)
+	}
+
+	priceType, err := parsePriceType(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	sources, err := parseSources(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
 	}
+	if len(sources) > 0 && priceType != models.PriceTypeLast {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "source filtering is only available for last price candles",
+		})
+	}
+
+	candles, err := cc.candleService.GetCandleRangeByPriceType(c.Request().Context(), symbol, interval, priceType, startTime, endTime)
+	if err != nil {
+		return serviceError(c, err)
+	}
+	if len(sources) > 0 {
+		candles = models.FilterCandlesBySource(candles, sources)
+	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"symbol":     symbol,
 		"interval":   interval,
+		"price_type": priceType,
 		"start_time": startTime,
 		"end_time":   endTime,
 		"candles":    candles,
 	})
 }
 
+// GetCandleCoverage reports how much of a time range is stored and the provenance of the stored candles
+func (cc *CandleController) GetCandleCoverage(c echo.Context) error {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "1m"
+	}
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	endTime := time.Now().UTC()
+	if endTimeStr := c.QueryParam("end_time"); endTimeStr != "" {
+		parsed, err := time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid end_time format, use RFC3339",
+			})
+		}
+		endTime = parsed
+	}
+
+	startTime := endTime.Add(-defaultCoverageRange)
+	if startTimeStr := c.QueryParam("start_time"); startTimeStr != "" {
+		parsed, err := time.Parse(time.RFC3339, startTimeStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid start_time format, use RFC3339",
+			})
+		}
+		startTime = parsed
+	}
+
+	if !startTime.Before(endTime) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "start_time must be before end_time",
+		})
+	}
+
+	coverage, err := cc.candleService.GetCandleCoverage(c.Request().Context(), symbol, interval, startTime, endTime)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, coverage)
+}
+
+// SetConsistencyService enables the stream/REST candle consistency report
+func (cc *CandleController) SetConsistencyService(consistencyService *services.CandleConsistencyService) {
+	cc.consistencyService = consistencyService
+}
+
+// GetCandleConsistency reports how often a symbol's closed bars diverged between the stream and
+// REST polling since startup, with the latest recorded discrepancies
+func (cc *CandleController) GetCandleConsistency(c echo.Context) error {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	interval := c.QueryParam("interval")
+	if interval != "" && !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	stats := cc.consistencyService.GetStats(symbol)
+	if interval != "" {
+		filtered := stats[:0]
+		for _, stat := range stats {
+			if stat.Interval == interval {
+				filtered = append(filtered, stat)
+			}
+		}
+		stats = filtered
+	}
+
+	discrepancies, err := cc.consistencyService.GetDiscrepancies(c.Request().Context(), symbol, interval, queryInt(c, "limit", 50, 1, 500))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"symbol":        symbol,
+		"settings":      cc.consistencyService.Settings(),
+		"stats":         stats,
+		"discrepancies": discrepancies,
+		"count":         len(discrepancies),
+	})
+}
+
+// parseAnchor reads the optional ?endTime= (inclusive), ?before= (exclusive) or ?cursor= anchor for backwards paging
+// endTime and before accept Unix milliseconds or RFC3339; cursor is a nextCursor from a previous page (the
+// earliest open time it returned) and resumes just before it. The anchor is returned as an exclusive bound
+// on open time; the zero time means no anchor (most recent candles)
+func parseAnchor(c echo.Context) (time.Time, error) {
+	endTimeStr, beforeStr, cursorStr := c.QueryParam("endTime"), c.QueryParam("before"), c.QueryParam("cursor")
+	set := 0
+	for _, value := range []string{endTimeStr, beforeStr, cursorStr} {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return time.Time{}, fmt.Errorf("use only one of endTime, before or cursor")
+	}
+
+	if endTimeStr != "" {
+		endTime, err := parseAnchorTime(endTimeStr)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid endTime, use Unix milliseconds or RFC3339")
+		}
+		return endTime.Add(time.Millisecond), nil
+	}
+	if beforeStr != "" {
+		before, err := parseAnchorTime(beforeStr)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid before, use Unix milliseconds or RFC3339")
+		}
+		return before, nil
+	}
+	if cursorStr != "" {
+		cursor, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || cursor <= 0 {
+			return time.Time{}, fmt.Errorf("invalid cursor, pass the nextCursor of the previous page")
+		}
+		return time.UnixMilli(cursor).UTC(), nil
+	}
+	return time.Time{}, nil
+}
+
+// parseAnchorTime parses Unix milliseconds or an RFC3339 timestamp
+func parseAnchorTime(value string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseSources reads the optional comma-separated source filter
+func parseSources(c echo.Context) ([]string, error) {
+	param := c.QueryParam("source")
+	if param == "" {
+		return nil, nil
+	}
+
+	var sources []string
+	for _, source := range strings.Split(param, ",") {
+		source = strings.TrimSpace(source)
+		if !models.IsValidCandleSource(source) {
+			return nil, fmt.Errorf("invalid source %q, use %s", source, strings.Join(models.CandleSources, ", "))
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// rangeTooLarge responds with 400 and the chunked requests that cover the same range
+func rangeTooLarge(c echo.Context, interval string, startTime, endTime time.Time, maxRange time.Duration) error {
+	chunks := models.ChunkTimeRange(interval, startTime, endTime)
+	chunkCount := len(chunks)
+	if len(chunks) > maxSuggestedChunks {
+		chunks = chunks[:maxSuggestedChunks]
+	}
+
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error":            fmt.Sprintf("time range too large for %s candles, split the request into chunks of at most %s", interval, maxRange),
+		"max_candles":      models.MaxRangeCandles,
+		"max_range_hours":  maxRange.Hours(),
+		"chunk_count":      chunkCount,
+		"suggested_chunks": chunks,
+	})
+}
+
+// GetCandleTrades returns the persisted trades composing one historical candle for bar drill-down
+func (cc *CandleController) GetCandleTrades(c echo.Context) error {
+	symbol := c.Param("symbol")
+	interval := c.Param("interval")
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	// openTime accepts Unix milliseconds (as returned in candle responses) or RFC3339
+	openTimeStr := c.Param("openTime")
+	var openTime time.Time
+	if ms, err := strconv.ParseInt(openTimeStr, 10, 64); err == nil {
+		openTime = time.UnixMilli(ms).UTC()
+	} else if openTime, err = time.Parse(time.RFC3339, openTimeStr); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid openTime, use Unix milliseconds or RFC3339",
+		})
+	}
+	if _, ok := models.CandleCloseTime(interval, openTime); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("%s is not the open time of a %s candle", openTime.UTC().Format(time.RFC3339), interval),
+		})
+	}
+
+	limit := defaultCandleTradesLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid limit parameter",
+			})
+		}
+		limit = parsed
+	}
+	if limit > maxCandleTradesLimit {
+		limit = maxCandleTradesLimit
+	}
+
+	response, err := cc.candleService.GetCandleTrades(c.Request().Context(), symbol, interval, openTime, limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parsePriceType reads the priceType query parameter (last, mark or index), defaulting to last
+func parsePriceType(c echo.Context) (string, error) {
+	priceType := c.QueryParam("priceType")
+	if priceType == "" {
+		return models.PriceTypeLast, nil
+	}
+	if !models.IsValidPriceType(priceType) {
+		return "", fmt.Errorf("invalid priceType %q, use last, mark or index", priceType)
+	}
+	return priceType, nil
+}
+
+// parseVolumeUnit reads the volumeUnit query parameter (base, quote or contracts); empty keeps
+// the stored unit
+func parseVolumeUnit(c echo.Context) (string, error) {
+	volumeUnit := c.QueryParam("volumeUnit")
+	if volumeUnit != "" && !models.IsValidVolumeUnit(volumeUnit) {
+		return "", fmt.Errorf("invalid volumeUnit %q, use base, quote or contracts", volumeUnit)
+	}
+	return volumeUnit, nil
+}
+
+// volumeUnitError responds with 400 for series that cannot be expressed in the requested volume
+// unit, mapping other failures as service errors
+func volumeUnitError(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrVolumeUnitUnsupported) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return serviceError(c, err)
+}
+
+// invalidInterval responds with 400 and the supported interval list
+func invalidInterval(c echo.Context, interval string) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error":               fmt.Sprintf("invalid interval %q", interval),
+		"supported_intervals": models.SupportedIntervalNames(),
+	})
+}
+
+// exchangeSymbol returns the symbol path parameter qualified by the optional "exchange" query
+// parameter ("bybit" turns BTCUSDT into BYBIT:BTCUSDT); ok is false for an unsupported exchange
+func exchangeSymbol(c echo.Context) (symbol string, ok bool) {
+	symbol = c.Param("symbol")
+	exchange := strings.ToLower(c.QueryParam("exchange"))
+	if exchange == "" || symbol == "" {
+		return symbol, true
+	}
+	if !models.IsValidExchange(exchange) {
+		return "", false
+	}
+	return models.QualifySymbol(exchange, symbol), true
+}
+
+// invalidExchange answers a request for an unsupported exchange
+func invalidExchange(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error":               fmt.Sprintf("invalid exchange %q", c.QueryParam("exchange")),
+		"supported_exchanges": models.Exchanges,
+	})
+}
+
+// setDataAge marks a response served from stored data while upstream is unavailable
+func setDataAge(c echo.Context, stale bool, ageSeconds int64) {
+	if !stale {
+		return
+	}
+	c.Response().Header().Set("X-Data-Age", strconv.FormatInt(ageSeconds, 10))
+	c.Response().Header().Set("Cache-Control", "no-cache")
+}
+
+// GetIntervals returns every supported candle interval for building interval pickers
+func (cc *CandleController) GetIntervals(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=3600")
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"intervals": models.SupportedIntervals(),
+		"names":     cc.binanceService.GetValidIntervals(),
+		"default":   "1h",
+	})
+}
+
 // StreamCandles handles WebSocket connections for real-time candle data
 func (cc *CandleController) StreamCandles(c echo.Context) error {
 	// For now, return a placeholder response
@@ -247,15 +716,17 @@ func (cc *CandleController) GetCandleMetrics(c echo.Context) error {
 		interval = "1h"
 	}
 
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
 	// Get a small sample to estimate performance
 	start := time.Now()
 	response, err := cc.candleService.GetOptimizedCandles(c.Request().Context(), symbol, interval, 100)
 	duration := time.Since(start)
 
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		return serviceError(c, err)
 	}
 
 	estimatedSize := response.EstimateJSONSize()
@@ -271,4 +742,3 @@ func (cc *CandleController) GetCandleMetrics(c echo.Context) error {
 		"last_timestamp":   response.L,
 	})
 }
- 
\ No newline at end of file
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CompositeController handles composite symbol requests
+// Composite candles are served by the candle endpoints under the composite's symbol
+type CompositeController struct {
+	compositeService *services.CompositeService
+}
+
+// NewCompositeController creates a new composite controller
+func NewCompositeController(compositeService *services.CompositeService) *CompositeController {
+	return &CompositeController{
+		compositeService: compositeService,
+	}
+}
+
+// GetComposites returns all composites of the requesting user
+func (cc *CompositeController) GetComposites(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	composites, err := cc.compositeService.GetComposites(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve composites: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":      len(composites),
+		"composites": composites,
+	})
+}
+
+// GetComposite returns a single composite
+func (cc *CompositeController) GetComposite(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := compositeID(c)
+	if !ok {
+		return invalidCompositeID(c)
+	}
+
+	composite, err := cc.compositeService.GetComposite(c.Request().Context(), userID, id)
+	if err != nil {
+		return compositeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, composite)
+}
+
+// CreateComposite defines a new composite symbol
+func (cc *CompositeController) CreateComposite(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.CreateCompositeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	composite, err := cc.compositeService.CreateComposite(c.Request().Context(), userID, &req)
+	if err != nil {
+		return compositeError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, composite)
+}
+
+// DeleteComposite deletes a composite and its stored candles
+func (cc *CompositeController) DeleteComposite(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := compositeID(c)
+	if !ok {
+		return invalidCompositeID(c)
+	}
+
+	if err := cc.compositeService.DeleteComposite(c.Request().Context(), userID, id); err != nil {
+		return compositeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Composite deleted successfully",
+	})
+}
+
+// compositeID parses the composite ID path parameter
+func compositeID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// invalidCompositeID responds to requests with a malformed composite ID
+func invalidCompositeID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Invalid composite ID",
+	})
+}
+
+// compositeError maps composite service errors to HTTP responses
+func compositeError(c echo.Context, err error) error {
+	message := err.Error()
+	switch {
+	case message == "composite not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Composite not found",
+		})
+	case strings.HasPrefix(message, "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
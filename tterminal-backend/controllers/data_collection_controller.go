@@ -1,8 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strings"
+	"time"
+	"tterminal-backend/internal/audit"
+	"tterminal-backend/models"
 	"tterminal-backend/services"
 
 	"github.com/labstack/echo/v4"
@@ -11,6 +16,7 @@ import (
 // DataCollectionController handles data collection service endpoints
 type DataCollectionController struct {
 	dataCollectionService *services.DataCollectionService
+	consistencyService    *services.CandleConsistencyService
 }
 
 // NewDataCollectionController creates a new data collection controller
@@ -31,6 +37,23 @@ func (ctrl *DataCollectionController) GetStats(c echo.Context) error {
 	})
 }
 
+// SetConsistencyService enables the stream/REST candle consistency stats
+func (ctrl *DataCollectionController) SetConsistencyService(consistencyService *services.CandleConsistencyService) {
+	ctrl.consistencyService = consistencyService
+}
+
+// GetConsistency returns per-symbol divergence stats between closed stream klines and REST candles
+// GET /api/v1/data-collection/consistency
+func (ctrl *DataCollectionController) GetConsistency(c echo.Context) error {
+	stats := ctrl.consistencyService.GetStats("")
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"settings": ctrl.consistencyService.Settings(),
+		"stats":    stats,
+		"count":    len(stats),
+	})
+}
+
 // TriggerCollection manually triggers a data collection run
 // POST /api/v1/data-collection/collect
 func (ctrl *DataCollectionController) TriggerCollection(c echo.Context) error {
@@ -42,6 +65,7 @@ func (ctrl *DataCollectionController) TriggerCollection(c echo.Context) error {
 	}
 
 	ctrl.dataCollectionService.CollectNow()
+	audit.Record(c.Request().Context(), "data_collection_run", "collect", nil, ctrl.collectionState())
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Data collection triggered successfully",
@@ -58,6 +82,7 @@ func (ctrl *DataCollectionController) StartService(c echo.Context) error {
 		})
 	}
 
+	before := ctrl.collectionState()
 	if err := ctrl.dataCollectionService.Start(); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error":   "start_failed",
@@ -65,6 +90,8 @@ func (ctrl *DataCollectionController) StartService(c echo.Context) error {
 		})
 	}
 
+	audit.Record(c.Request().Context(), "data_collection", "", before, ctrl.collectionState())
+
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Data collection service started successfully",
 	})
@@ -80,7 +107,9 @@ func (ctrl *DataCollectionController) StopService(c echo.Context) error {
 		})
 	}
 
+	before := ctrl.collectionState()
 	ctrl.dataCollectionService.Stop()
+	audit.Record(c.Request().Context(), "data_collection", "", before, ctrl.collectionState())
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Data collection service stopped successfully",
@@ -109,7 +138,9 @@ func (ctrl *DataCollectionController) AddSymbol(c echo.Context) error {
 		})
 	}
 
+	before := ctrl.collectionState()
 	ctrl.dataCollectionService.AddSymbol(req.Symbol)
+	audit.Record(c.Request().Context(), "data_collection", "", before, ctrl.collectionState())
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Symbol added successfully",
@@ -117,6 +148,37 @@ func (ctrl *DataCollectionController) AddSymbol(c echo.Context) error {
 	})
 }
 
+// SetPriceTypes sets which price types (last, mark, index) are collected
+// PUT /api/v1/data-collection/price-types
+func (ctrl *DataCollectionController) SetPriceTypes(c echo.Context) error {
+	type SetPriceTypesRequest struct {
+		PriceTypes []string `json:"price_types"`
+	}
+
+	var req SetPriceTypesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_request",
+			"message": "Invalid request format",
+		})
+	}
+
+	before := ctrl.collectionState()
+	if err := ctrl.dataCollectionService.SetPriceTypes(req.PriceTypes); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_price_types",
+			"message": err.Error(),
+		})
+	}
+
+	audit.Record(c.Request().Context(), "data_collection", "", before, ctrl.collectionState())
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":     "Price types updated successfully",
+		"price_types": ctrl.dataCollectionService.GetStats().ActivePriceTypes,
+	})
+}
+
 // RemoveSymbol removes a symbol from the collection list
 // DELETE /api/v1/data-collection/symbols/:symbol
 func (ctrl *DataCollectionController) RemoveSymbol(c echo.Context) error {
@@ -128,7 +190,9 @@ func (ctrl *DataCollectionController) RemoveSymbol(c echo.Context) error {
 		})
 	}
 
+	before := ctrl.collectionState()
 	ctrl.dataCollectionService.RemoveSymbol(symbol)
+	audit.Record(c.Request().Context(), "data_collection", "", before, ctrl.collectionState())
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Symbol removed successfully",
@@ -136,6 +200,136 @@ func (ctrl *DataCollectionController) RemoveSymbol(c echo.Context) error {
 	})
 }
 
+// PauseSymbol pauses a symbol's collection and streaming, until resumed or until a given time
+// POST /api/v1/data-collection/symbols/:symbol/pause
+func (ctrl *DataCollectionController) PauseSymbol(c echo.Context) error {
+	type PauseSymbolRequest struct {
+		Reason          string     `json:"reason"`
+		Until           *time.Time `json:"until"`            // RFC3339; omitted with duration_minutes for a pause held until resumed
+		DurationMinutes int        `json:"duration_minutes"` // Alternative to until
+	}
+
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+
+	var req PauseSymbolRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_request",
+			"message": "Invalid request format",
+		})
+	}
+	if req.Until != nil && req.DurationMinutes != 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_request",
+			"message": "Set either until or duration_minutes, not both",
+		})
+	}
+	if req.DurationMinutes < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_request",
+			"message": "duration_minutes must be positive",
+		})
+	}
+
+	pause := &models.SymbolPause{
+		Symbol:   strings.ToUpper(symbol),
+		Source:   models.SymbolPauseManual,
+		Reason:   strings.TrimSpace(req.Reason),
+		PausedBy: requestUserID(c),
+		Until:    req.Until,
+	}
+	if req.DurationMinutes > 0 {
+		until := time.Now().UTC().Add(time.Duration(req.DurationMinutes) * time.Minute)
+		pause.Until = &until
+	}
+
+	pause, err := ctrl.dataCollectionService.PauseSymbol(c.Request().Context(), pause)
+	if err != nil {
+		return symbolPauseError(c, err)
+	}
+
+	audit.Record(c.Request().Context(), "symbol_pause", pause.Symbol, nil, pause)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Symbol paused successfully",
+		"pause":   pause,
+	})
+}
+
+// ResumeSymbol ends a symbol's pause, resuming its collection and streaming
+// DELETE /api/v1/data-collection/symbols/:symbol/pause
+func (ctrl *DataCollectionController) ResumeSymbol(c echo.Context) error {
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+	symbol = strings.ToUpper(symbol)
+
+	pause, err := ctrl.dataCollectionService.ResumeSymbol(c.Request().Context(), symbol, requestUserID(c))
+	if err != nil {
+		return symbolPauseError(c, err)
+	}
+
+	active := *pause
+	active.ResumedAt, active.ResumedBy = nil, ""
+	audit.Record(c.Request().Context(), "symbol_pause", symbol, &active, pause)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Symbol resumed successfully",
+		"pause":   pause,
+	})
+}
+
+// GetPauses returns the pauses in effect and the most recent pauses, optionally of one symbol
+// GET /api/v1/data-collection/pauses
+func (ctrl *DataCollectionController) GetPauses(c echo.Context) error {
+	symbol := strings.ToUpper(c.QueryParam("symbol"))
+	limit := queryInt(c, "limit", 100, 1, 500)
+
+	history, err := ctrl.dataCollectionService.PauseHistory(c.Request().Context(), symbol, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   "pauses_unavailable",
+			"message": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"active":  ctrl.dataCollectionService.PausedSymbols(),
+		"history": history,
+		"count":   len(history),
+	})
+}
+
+// symbolPauseError maps symbol pause errors to HTTP responses
+func symbolPauseError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrSymbolAlreadyPaused):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error":   "already_paused",
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrSymbolNotPaused):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error":   "not_paused",
+			"message": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_request",
+			"message": err.Error(),
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   "pause_failed",
+			"message": err.Error(),
+		})
+	}
+}
+
 // FetchHistoricalData manually triggers historical data fetching
 func (ctrl *DataCollectionController) FetchHistoricalData(c echo.Context) error {
 	if ctrl.dataCollectionService == nil {
@@ -158,9 +352,20 @@ func (ctrl *DataCollectionController) FetchHistoricalData(c echo.Context) error
 		// For now, we'll trigger a full collection which includes historical data
 		ctrl.dataCollectionService.CollectNow()
 	}()
+	audit.Record(c.Request().Context(), "data_collection_run", "historical", nil, ctrl.collectionState())
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Historical data fetch triggered successfully",
 		"status":  "running",
 	})
 }
+
+// collectionState is the controllable state of the service, recorded in the audit log
+func (ctrl *DataCollectionController) collectionState() map[string]interface{} {
+	stats := ctrl.dataCollectionService.GetStats()
+	return map[string]interface{}{
+		"running":     stats.IsRunning,
+		"symbols":     stats.ActiveSymbols,
+		"price_types": stats.ActivePriceTypes,
+	}
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"tterminal-backend/internal/batchwriter"
+	"tterminal-backend/internal/sla"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DebugController reports this instance's measured performance
+type DebugController struct {
+	httpLatency *sla.Tracker
+	deliveryLag *sla.Tracker
+	target      time.Duration
+	instanceID  string
+	freshness   *services.FreshnessService // Stored data freshness per symbol/interval (optional)
+	writers     *batchwriter.Registry      // Write metrics of the persistence streams (optional)
+}
+
+// NewDebugController creates a new debug controller reporting request latency and WebSocket
+// delivery lag against target
+func NewDebugController(httpLatency, deliveryLag *sla.Tracker, target time.Duration, instanceID string) *DebugController {
+	return &DebugController{
+		httpLatency: httpLatency,
+		deliveryLag: deliveryLag,
+		target:      target,
+		instanceID:  instanceID,
+	}
+}
+
+// SetFreshnessService enables the data freshness report
+func (dc *DebugController) SetFreshnessService(freshness *services.FreshnessService) {
+	dc.freshness = freshness
+}
+
+// SetWriterRegistry enables the batch writer report
+func (dc *DebugController) SetWriterRegistry(writers *batchwriter.Registry) {
+	dc.writers = writers
+}
+
+// GetSLA returns the rolling p50/p95/p99 latency of every API route and the delivery lag of every
+// WebSocket message type, measured on this instance
+// GET /api/v1/debug/sla
+func (dc *DebugController) GetSLA(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	return c.JSON(http.StatusOK, &models.SLAReport{
+		InstanceID:        dc.instanceID,
+		WindowSeconds:     int64(dc.httpLatency.Window() / time.Second),
+		TargetMs:          int(dc.target / time.Millisecond),
+		HTTP:              dc.httpLatency.Stats(dc.target),
+		WebSocketDelivery: dc.deliveryLag.Stats(dc.target),
+		GeneratedAt:       time.Now().UTC(),
+	})
+}
+
+// GetFreshness returns, for every collected symbol/interval, its freshness target, how far its
+// stored candles lag behind and its rolling compliance (?symbol=, ?interval= narrow the report)
+// GET /api/v1/sla/freshness
+func (dc *DebugController) GetFreshness(c echo.Context) error {
+	if dc.freshness == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "freshness monitoring is not enabled",
+		})
+	}
+
+	interval := c.QueryParam("interval")
+	if interval != "" && !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	return c.JSON(http.StatusOK, dc.freshness.Report(strings.ToUpper(c.QueryParam("symbol")), interval))
+}
+
+// GetWriters returns the write metrics of every persistence stream's batch writer on this
+// instance: items written, failed, dropped and pending, retries and the last error
+// GET /api/v1/debug/writers
+func (dc *DebugController) GetWriters(c echo.Context) error {
+	if dc.writers == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "batch writer metrics are not enabled",
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	return c.JSON(http.StatusOK, &models.WriterReport{
+		InstanceID:  dc.instanceID,
+		Writers:     dc.writers.Stats(),
+		GeneratedAt: time.Now().UTC(),
+	})
+}
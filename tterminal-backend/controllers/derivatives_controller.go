@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DerivativesController handles derivatives dashboard requests
+type DerivativesController struct {
+	derivativesService *services.DerivativesService
+}
+
+// NewDerivativesController creates a new derivatives controller
+func NewDerivativesController(derivativesService *services.DerivativesService) *DerivativesController {
+	return &DerivativesController{
+		derivativesService: derivativesService,
+	}
+}
+
+// GetDerivatives returns funding, open interest, long/short, liquidation and basis data for a symbol
+func (dc *DerivativesController) GetDerivatives(c echo.Context) error {
+	symbol := c.Param("symbol")
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Symbol is required",
+		})
+	}
+
+	snapshot, err := dc.derivativesService.GetSnapshot(c.Request().Context(), symbol, parseLiquidationHours(c))
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	setDataAge(c, snapshot.Stale, snapshot.DataAge)
+	return c.JSON(http.StatusOK, snapshot)
+}
+
+// GetAllDerivatives returns derivatives dashboard data for every streamed symbol
+func (dc *DerivativesController) GetAllDerivatives(c echo.Context) error {
+	snapshots, err := dc.derivativesService.GetAllSnapshots(c.Request().Context(), parseLiquidationHours(c))
+	if err != nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":     len(snapshots),
+		"symbols":   snapshots,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// GetFundingPrediction returns the live premium index and predicted next funding rate for a symbol
+func (dc *DerivativesController) GetFundingPrediction(c echo.Context) error {
+	prediction, err := dc.derivativesService.GetFundingPrediction(c.Param("symbol"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, prediction)
+}
+
+// GetFundingHistory returns predicted vs settled funding rates for charting
+func (dc *DerivativesController) GetFundingHistory(c echo.Context) error {
+	limit := 100
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 1000 {
+			limit = parsed
+		}
+	}
+
+	history, err := dc.derivativesService.GetFundingHistory(c.Request().Context(), c.Param("symbol"), limit)
+	if err != nil {
+		return serviceError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=60")
+	return c.JSON(http.StatusOK, history)
+}
+
+// parseLiquidationHours reads the liquidation window, defaulting to 24 hours
+func parseLiquidationHours(c echo.Context) int {
+	hours := 24
+	if hoursStr := c.QueryParam("hours"); hoursStr != "" {
+		if parsed, err := strconv.Atoi(hoursStr); err == nil && parsed > 0 && parsed <= 168 {
+			hours = parsed
+		}
+	}
+	return hours
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"net/http"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DrainController handles draining the instance for rolling restarts
+type DrainController struct {
+	drainService *services.DrainService
+}
+
+// NewDrainController creates a new drain controller
+func NewDrainController(drainService *services.DrainService) *DrainController {
+	return &DrainController{
+		drainService: drainService,
+	}
+}
+
+// StartDrain marks the instance as draining and returns the drain progress
+// Poll GetDrainStatus until ready_to_terminate before stopping the process
+func (dc *DrainController) StartDrain(c echo.Context) error {
+	return c.JSON(http.StatusAccepted, dc.drainService.Start(c.Request().Context()))
+}
+
+// GetDrainStatus returns the drain progress
+func (dc *DrainController) GetDrainStatus(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, dc.drainService.Status())
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"tterminal-backend/internal/binance"
+
+	"github.com/labstack/echo/v4"
+)
+
+// serviceError responds to a failed service call: classified Binance failures get their own
+// status (429, 404, 503, ...) and code, anything else is a 500. The request ID is included
+// so the response can be matched with server logs
+func serviceError(c echo.Context, err error) error {
+	status, code := errorStatus(err)
+	setRetryAfter(c, err)
+	body := map[string]string{
+		"error": err.Error(),
+	}
+	if code != "" {
+		body["code"] = code
+	}
+	if requestID := c.Response().Header().Get(echo.HeaderXRequestID); requestID != "" {
+		body["request_id"] = requestID
+	}
+	return c.JSON(status, body)
+}
+
+// errorStatus returns the HTTP status and error code for a service error, setting no code for
+// errors that did not come from Binance
+func errorStatus(err error) (int, string) {
+	apiErr, ok := binance.AsAPIError(err)
+	if !ok {
+		return http.StatusInternalServerError, ""
+	}
+	return apiErr.HTTPStatus(), apiErr.ErrorCode()
+}
+
+// setRetryAfter forwards Binance's suggested wait on rate-limited responses
+func setRetryAfter(c echo.Context, err error) {
+	if apiErr, ok := binance.AsAPIError(err); ok && apiErr.RetryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(apiErr.RetryAfter.Seconds())))
+	}
+}
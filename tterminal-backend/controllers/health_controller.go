@@ -2,28 +2,61 @@ package controllers
 
 import (
 	"net/http"
+	"tterminal-backend/internal/binance"
 	"tterminal-backend/internal/database"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/services"
 
 	"github.com/labstack/echo/v4"
 )
 
 // HealthController handles health check endpoints
 type HealthController struct {
-	db *database.DB
+	db            *database.DB
+	binanceClient *binance.Client
+	drainService  *services.DrainService          // Optional; a draining instance reports unavailable
+	collection    *services.DataCollectionService // Optional; paused symbols are listed
+	hub           *websocket.Hub                  // Optional; the instance's routing hint is reported
 }
 
 // NewHealthController creates a new health controller
-func NewHealthController(db *database.DB) *HealthController {
+func NewHealthController(db *database.DB, binanceClient *binance.Client) *HealthController {
 	return &HealthController{
-		db: db,
+		db:            db,
+		binanceClient: binanceClient,
 	}
 }
 
+// SetDrainService reports the instance unavailable while it drains, so load balancers stop routing to it
+func (h *HealthController) SetDrainService(drainService *services.DrainService) {
+	h.drainService = drainService
+}
+
+// SetDataCollectionService lists the symbols whose collection and streaming are paused
+func (h *HealthController) SetDataCollectionService(collection *services.DataCollectionService) {
+	h.collection = collection
+}
+
+// SetHub reports the instance's routing hint, which peers read to rank reconnect targets
+func (h *HealthController) SetHub(hub *websocket.Hub) {
+	h.hub = hub
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status   string `json:"status"`
 	Database string `json:"database"`
 	Message  string `json:"message,omitempty"`
+
+	// Binance REST reachability; "degraded" status means stored data is being served
+	Upstream *binance.UpstreamStatus `json:"upstream,omitempty"`
+
+	// Symbols not collected or streamed while paused; they do not affect the status
+	PausedSymbols []string `json:"paused_symbols,omitempty"`
+
+	// Instance ID, region and WebSocket load, for clients and peers choosing where to reconnect
+	Instance *transport.RoutingHint `json:"instance,omitempty"`
 }
 
 // HealthCheck performs a health check of the application
@@ -31,6 +64,16 @@ func (h *HealthController) HealthCheck(c echo.Context) error {
 	response := HealthResponse{
 		Status: "healthy",
 	}
+	if h.hub != nil {
+		hint := h.hub.GetRoutingHint()
+		response.Instance = &hint
+	}
+
+	if h.drainService != nil && h.drainService.IsDraining() {
+		response.Status = "draining"
+		response.Message = "Instance is draining for a restart"
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
 
 	// Check database connection
 	ctx := c.Request().Context()
@@ -42,5 +85,21 @@ func (h *HealthController) HealthCheck(c echo.Context) error {
 	}
 
 	response.Database = "healthy"
+
+	if h.binanceClient != nil {
+		upstream := h.binanceClient.UpstreamStatus()
+		response.Upstream = &upstream
+		if upstream.Degraded {
+			response.Status = "degraded"
+			response.Message = "Binance API unreachable - serving stored data"
+		}
+	}
+
+	if h.collection != nil {
+		for _, pause := range h.collection.PausedSymbols() {
+			response.PausedSymbols = append(response.PausedSymbols, pause.Symbol)
+		}
+	}
+
 	return c.JSON(http.StatusOK, response)
 }
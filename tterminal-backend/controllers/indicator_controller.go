@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IndicatorController handles custom indicator requests
+type IndicatorController struct {
+	indicatorService *services.IndicatorService
+}
+
+// NewIndicatorController creates a new custom indicator controller
+func NewIndicatorController(indicatorService *services.IndicatorService) *IndicatorController {
+	return &IndicatorController{
+		indicatorService: indicatorService,
+	}
+}
+
+// GetIndicators returns all custom indicators of the requesting user
+func (ic *IndicatorController) GetIndicators(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	indicators, err := ic.indicatorService.GetIndicators(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve indicators: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":      len(indicators),
+		"indicators": indicators,
+	})
+}
+
+// GetIndicator returns a single custom indicator
+func (ic *IndicatorController) GetIndicator(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := indicatorID(c)
+	if !ok {
+		return invalidIndicatorID(c)
+	}
+
+	indicator, err := ic.indicatorService.GetIndicator(c.Request().Context(), userID, id)
+	if err != nil {
+		return indicatorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, indicator)
+}
+
+// CreateIndicator registers a custom indicator
+func (ic *IndicatorController) CreateIndicator(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.CreateIndicatorRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	indicator, err := ic.indicatorService.CreateIndicator(c.Request().Context(), userID, &req)
+	if err != nil {
+		return indicatorError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, indicator)
+}
+
+// UpdateIndicator renames a custom indicator or replaces its expression
+func (ic *IndicatorController) UpdateIndicator(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := indicatorID(c)
+	if !ok {
+		return invalidIndicatorID(c)
+	}
+
+	var req models.UpdateIndicatorRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	indicator, err := ic.indicatorService.UpdateIndicator(c.Request().Context(), userID, id, &req)
+	if err != nil {
+		return indicatorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, indicator)
+}
+
+// DeleteIndicator deletes a custom indicator
+func (ic *IndicatorController) DeleteIndicator(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := indicatorID(c)
+	if !ok {
+		return invalidIndicatorID(c)
+	}
+
+	if err := ic.indicatorService.DeleteIndicator(c.Request().Context(), userID, id); err != nil {
+		return indicatorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Indicator deleted successfully",
+	})
+}
+
+// GetIndicatorSeries evaluates a custom indicator against a symbol's candles, over a fixed range
+// or the last limit bars
+// GET /api/v1/indicators/:id/:symbol/:interval?start=...&end=... or ?limit=500
+func (ic *IndicatorController) GetIndicatorSeries(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := indicatorID(c)
+	if !ok {
+		return invalidIndicatorID(c)
+	}
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+	interval := c.Param("interval")
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
+
+	var start, end time.Time
+	if c.QueryParam("start") != "" || c.QueryParam("end") != "" {
+		var err error
+		if start, end, err = parseFixedRange(c); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "start and end must both be Unix milliseconds or RFC3339 times",
+			})
+		}
+	}
+	limit := queryInt(c, "limit", 500, 1, 1000)
+
+	series, err := ic.indicatorService.GetIndicatorSeries(c.Request().Context(), userID, id, symbol, interval, start, end, limit)
+	if err != nil {
+		return indicatorError(c, err)
+	}
+	c.Response().Header().Set("X-Candles-Count", strconv.Itoa(len(series.D)))
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// indicatorID parses the indicator ID path parameter
+func indicatorID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// invalidIndicatorID responds to requests with a malformed indicator ID
+func invalidIndicatorID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Invalid indicator ID",
+	})
+}
+
+// indicatorError maps custom indicator service errors to HTTP responses
+func indicatorError(c echo.Context, err error) error {
+	message := err.Error()
+	switch {
+	case message == "indicator not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Indicator not found",
+		})
+	case strings.HasPrefix(message, "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	case errors.Is(err, services.ErrQueryTimeout):
+		return c.JSON(http.StatusGatewayTimeout, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
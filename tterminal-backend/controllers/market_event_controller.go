@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MarketEventController handles the significant events navigator
+type MarketEventController struct {
+	eventIndexService *services.EventIndexService
+}
+
+// NewMarketEventController creates a new market event controller
+func NewMarketEventController(eventIndexService *services.EventIndexService) *MarketEventController {
+	return &MarketEventController{
+		eventIndexService: eventIndexService,
+	}
+}
+
+// GetEvents lists indexed events of a symbol (largest ranges, volume spikes and gaps)
+// GET /api/v1/events/:symbol
+func (mc *MarketEventController) GetEvents(c echo.Context) error {
+	symbol, ok := exchangeSymbol(c)
+	if !ok {
+		return invalidExchange(c)
+	}
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Symbol is required",
+		})
+	}
+
+	params := services.EventListParams{
+		Interval: c.QueryParam("interval"),
+		Limit:    queryInt(c, "limit", 100, 1, 500),
+	}
+	if typeParam := c.QueryParam("type"); typeParam != "" {
+		for _, eventType := range strings.Split(typeParam, ",") {
+			params.Types = append(params.Types, strings.TrimSpace(eventType))
+		}
+	}
+	if beforeStr := c.QueryParam("before"); beforeStr != "" {
+		before, err := parseAnchorTime(beforeStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid before, use Unix milliseconds or RFC3339",
+			})
+		}
+		params.Before = before
+	}
+	switch c.QueryParam("sort") {
+	case "", "time":
+	case "magnitude":
+		params.ByMagnitude = true
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "sort must be time or magnitude",
+		})
+	}
+
+	response, err := mc.eventIndexService.ListEvents(c.Request().Context(), symbol, params)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"tterminal-backend/internal/deribit"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OptionsController handles Deribit options chain, implied volatility and DVOL requests
+type OptionsController struct {
+	optionsService *services.OptionsService
+}
+
+// NewOptionsController creates a new options controller
+func NewOptionsController(optionsService *services.OptionsService) *OptionsController {
+	return &OptionsController{
+		optionsService: optionsService,
+	}
+}
+
+// GetChain returns the option chain of a currency, optionally limited to one expiry ("27JUN25")
+func (oc *OptionsController) GetChain(c echo.Context) error {
+	chain, err := oc.optionsService.GetChain(c.Request().Context(), c.Param("currency"), c.QueryParam("expiry"))
+	if err != nil {
+		return optionsError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, chain)
+}
+
+// GetTermStructure returns the at-the-money implied volatility of every expiry of a currency
+func (oc *OptionsController) GetTermStructure(c echo.Context) error {
+	term, err := oc.optionsService.GetTermStructure(c.Request().Context(), c.Param("currency"))
+	if err != nil {
+		return optionsError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, term)
+}
+
+// GetDVOL returns DVOL implied volatility index candles of a currency
+func (oc *OptionsController) GetDVOL(c echo.Context) error {
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+	limit := queryInt(c, "limit", 500, 1, 1000)
+
+	dvol, err := oc.optionsService.GetDVOL(c.Request().Context(), c.Param("currency"), interval, limit)
+	if err != nil {
+		return optionsError(c, err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=30")
+	return c.JSON(http.StatusOK, dvol)
+}
+
+// optionsError maps options errors to status codes; failed Deribit requests are a bad gateway
+func optionsError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	var apiErr *deribit.APIError
+	switch {
+	case errors.Is(err, services.ErrOptionsDisabled):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrOptionsCurrency), errors.Is(err, services.ErrOptionsExpiryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrOptionsInterval):
+		status = http.StatusBadRequest
+	case errors.As(err, &apiErr):
+		status = http.StatusBadGateway
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
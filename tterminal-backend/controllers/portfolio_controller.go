@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/internal/audit"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PortfolioController handles portfolio (sub-account), order and PnL requests
+type PortfolioController struct {
+	portfolioService   *services.PortfolioService
+	orderFilterService *services.OrderFilterService
+}
+
+// NewPortfolioController creates a new portfolio controller
+func NewPortfolioController(portfolioService *services.PortfolioService, orderFilterService *services.OrderFilterService) *PortfolioController {
+	return &PortfolioController{
+		portfolioService:   portfolioService,
+		orderFilterService: orderFilterService,
+	}
+}
+
+// GetPortfolios returns all portfolios of the requesting user
+func (pc *PortfolioController) GetPortfolios(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	portfolios, err := pc.portfolioService.GetPortfolios(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve portfolios: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":      len(portfolios),
+		"portfolios": portfolios,
+	})
+}
+
+// GetPortfolio returns a single portfolio
+func (pc *PortfolioController) GetPortfolio(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid portfolio ID",
+		})
+	}
+
+	portfolio, err := pc.portfolioService.GetPortfolio(c.Request().Context(), userID, id)
+	if err != nil {
+		return portfolioError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, portfolio)
+}
+
+// CreatePortfolio creates a new portfolio
+func (pc *PortfolioController) CreatePortfolio(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.CreatePortfolioRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	portfolio, err := pc.portfolioService.CreatePortfolio(c.Request().Context(), userID, &req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Failed to create portfolio: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, portfolio)
+}
+
+// UpdatePortfolio updates a portfolio's name and risk limits
+func (pc *PortfolioController) UpdatePortfolio(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid portfolio ID",
+		})
+	}
+
+	var req models.UpdatePortfolioRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	portfolio, err := pc.portfolioService.UpdatePortfolio(c.Request().Context(), userID, id, &req)
+	if err != nil {
+		return portfolioError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, portfolio)
+}
+
+// DeletePortfolio deletes a portfolio with its positions and orders
+func (pc *PortfolioController) DeletePortfolio(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid portfolio ID",
+		})
+	}
+
+	if err := pc.portfolioService.DeletePortfolio(c.Request().Context(), userID, id); err != nil {
+		return portfolioError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Portfolio deleted successfully",
+	})
+}
+
+// GetOrders returns the most recent orders of a portfolio
+func (pc *PortfolioController) GetOrders(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid portfolio ID",
+		})
+	}
+
+	limit := 100
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 1000 {
+			limit = parsed
+		}
+	}
+
+	orders, err := pc.portfolioService.GetOrders(c.Request().Context(), userID, id, limit)
+	if err != nil {
+		return portfolioError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"portfolio_id": id,
+		"count":        len(orders),
+		"orders":       orders,
+	})
+}
+
+// PlaceOrder fills a market order in the portfolio selected by portfolio_id
+func (pc *PortfolioController) PlaceOrder(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.PlaceOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+	if req.PortfolioID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "portfolio_id is required",
+		})
+	}
+
+	order, err := pc.portfolioService.PlaceOrder(c.Request().Context(), userID, &req)
+	if err != nil {
+		return portfolioError(c, err)
+	}
+	audit.Record(c.Request().Context(), "portfolio_order", strconv.FormatInt(order.ID, 10), nil, order)
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+// ValidateOrder checks an order against the symbol's exchange filters without placing it
+func (pc *PortfolioController) ValidateOrder(c echo.Context) error {
+	var req models.ValidateOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	result, err := pc.orderFilterService.ValidateOrder(c.Request().Context(), &req)
+	if err != nil {
+		return portfolioError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetPortfolioPnL returns the PnL report of a single portfolio
+func (pc *PortfolioController) GetPortfolioPnL(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid portfolio ID",
+		})
+	}
+
+	pnl, err := pc.portfolioService.GetPortfolioPnL(c.Request().Context(), userID, id)
+	if err != nil {
+		return portfolioError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, pnl)
+}
+
+// GetAggregatePnL returns the PnL of all the user's portfolios with totals
+func (pc *PortfolioController) GetAggregatePnL(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	pnl, err := pc.portfolioService.GetAggregatePnL(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to build PnL report: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, pnl)
+}
+
+// requestUserID reads the user ID from the X-User-ID header or user_id query parameter
+func requestUserID(c echo.Context) string {
+	if userID := c.Request().Header.Get("X-User-ID"); userID != "" {
+		return userID
+	}
+	return c.QueryParam("user_id")
+}
+
+// missingUserID responds to requests without a user ID
+func missingUserID(c echo.Context) error {
+	return c.JSON(http.StatusUnauthorized, map[string]string{
+		"error": "User ID is required (X-User-ID header)",
+	})
+}
+
+// portfolioError maps portfolio service errors to HTTP responses
+func portfolioError(c echo.Context, err error) error {
+	var filterErr *models.OrderFilterError
+	if errors.As(err, &filterErr) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":      err.Error(),
+			"violations": filterErr.Violations,
+		})
+	}
+
+	message := err.Error()
+	switch {
+	case message == "portfolio not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Portfolio not found",
+		})
+	case strings.HasPrefix(message, "validation failed"), strings.HasPrefix(message, "risk limit exceeded"),
+		strings.HasPrefix(message, "no live price"), strings.HasPrefix(message, "live order routing"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
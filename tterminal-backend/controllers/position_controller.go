@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PositionController handles account position and PnL requests
+type PositionController struct {
+	positionService *services.PositionService // Nil without Binance API credentials
+}
+
+// NewPositionController creates a new position controller; a nil service answers every request with 503
+func NewPositionController(positionService *services.PositionService) *PositionController {
+	return &PositionController{
+		positionService: positionService,
+	}
+}
+
+// GetPositions returns the account's open positions with unrealized and realized PnL
+// GET /api/v1/positions
+func (pc *PositionController) GetPositions(c echo.Context) error {
+	if pc.positionService == nil {
+		return positionsDisabled(c)
+	}
+
+	return c.JSON(http.StatusOK, pc.positionService.GetPositions())
+}
+
+// GetPosition returns the account's open positions in a symbol
+// GET /api/v1/positions/:symbol
+func (pc *PositionController) GetPosition(c echo.Context) error {
+	if pc.positionService == nil {
+		return positionsDisabled(c)
+	}
+
+	positions, err := pc.positionService.GetPosition(c.Param("symbol"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "No open position in " + c.Param("symbol"),
+		})
+	}
+
+	return c.JSON(http.StatusOK, positions)
+}
+
+// GetFills returns the account's most recent fills with their realized PnL and commission
+// GET /api/v1/positions/fills?symbol=BTCUSDT&since=1700000000000&limit=100
+func (pc *PositionController) GetFills(c echo.Context) error {
+	if pc.positionService == nil {
+		return positionsDisabled(c)
+	}
+
+	var since time.Time
+	if param := c.QueryParam("since"); param != "" {
+		parsed, err := parseAnchorTime(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid since, use Unix milliseconds or RFC3339",
+			})
+		}
+		since = parsed
+	}
+
+	fills, err := pc.positionService.GetFills(c.Request().Context(), c.QueryParam("symbol"), since, queryInt(c, "limit", 100, 1, 1000))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve fills: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count": len(fills),
+		"fills": fills,
+	})
+}
+
+// positionsDisabled responds when position tracking is not available
+func positionsDisabled(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{
+		"error": "position tracking needs the Binance API credentials and live market data",
+		"code":  "POSITIONS_DISABLED",
+	})
+}
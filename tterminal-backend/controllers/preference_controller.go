@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PreferenceController handles user preference requests
+type PreferenceController struct {
+	preferenceService *services.PreferenceService
+}
+
+// NewPreferenceController creates a new preference controller
+func NewPreferenceController(preferenceService *services.PreferenceService) *PreferenceController {
+	return &PreferenceController{
+		preferenceService: preferenceService,
+	}
+}
+
+// GetPreferences returns every preference of the requesting user, with defaults for those not set
+func (pc *PreferenceController) GetPreferences(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	prefs, err := pc.preferenceService.GetPreferences(c.Request().Context(), userID)
+	if err != nil {
+		return preferenceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences sets the preferences in the body, leaving the others as they are
+func (pc *PreferenceController) UpdatePreferences(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var updates map[string]interface{}
+	if err := c.Bind(&updates); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	prefs, err := pc.preferenceService.UpdatePreferences(c.Request().Context(), userID, updates)
+	if err != nil {
+		return preferenceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// ResetPreferences restores the defaults of every preference of the requesting user
+func (pc *PreferenceController) ResetPreferences(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	prefs, err := pc.preferenceService.ResetPreferences(c.Request().Context(), userID)
+	if err != nil {
+		return preferenceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, prefs)
+}
+
+// GetSchema returns the preference schema: each preference's type, accepted values and default
+func (pc *PreferenceController) GetSchema(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"preferences": models.PreferenceSchema(),
+	})
+}
+
+// preferenceError maps preference service errors to HTTP responses
+func preferenceError(c echo.Context, err error) error {
+	message := err.Error()
+	if strings.HasPrefix(message, "validation failed") {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
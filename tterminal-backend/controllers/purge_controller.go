@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PurgeController handles admin purges of stored symbol data
+type PurgeController struct {
+	purgeService *services.PurgeService
+}
+
+// NewPurgeController creates a new purge controller
+func NewPurgeController(purgeService *services.PurgeService) *PurgeController {
+	return &PurgeController{
+		purgeService: purgeService,
+	}
+}
+
+// PreviewPurge counts the rows a purge would delete and returns its confirmation token
+func (pc *PurgeController) PreviewPurge(c echo.Context) error {
+	var req models.PurgeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	preview, err := pc.purgeService.Preview(c.Request().Context(), req)
+	if err != nil {
+		return purgeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, preview)
+}
+
+// StartPurge starts a previewed purge in the background
+// The body must repeat the previewed parameters with the preview's confirmation_token
+func (pc *PurgeController) StartPurge(c echo.Context) error {
+	var req models.PurgeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	job, err := pc.purgeService.Start(req)
+	if err != nil {
+		return purgeError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// GetPurgeJob returns a purge job's progress
+func (pc *PurgeController) GetPurgeJob(c echo.Context) error {
+	job, err := pc.purgeService.GetJob(c.Param("id"))
+	if err != nil {
+		return purgeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// GetPurgeJobs lists recent purge jobs, newest first
+func (pc *PurgeController) GetPurgeJobs(c echo.Context) error {
+	jobs := pc.purgeService.ListJobs()
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count": len(jobs),
+		"jobs":  jobs,
+	})
+}
+
+// purgeError maps purge errors to status codes
+func purgeError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrPurgeInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrPurgeTokenInvalid):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrPurgeRunning):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrPurgeJobNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrPurgeDraining):
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
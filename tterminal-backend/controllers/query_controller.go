@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// QueryController handles declarative time-series queries
+type QueryController struct {
+	queryService *services.QueryService
+}
+
+// NewQueryController creates a new query controller
+func NewQueryController(queryService *services.QueryService) *QueryController {
+	return &QueryController{
+		queryService: queryService,
+	}
+}
+
+// Query evaluates a time-series query against stored data
+func (qc *QueryController) Query(c echo.Context) error {
+	var query models.SeriesQuery
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid query: " + err.Error(),
+		})
+	}
+
+	result, err := qc.queryService.Evaluate(c.Request().Context(), &query)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// queryError maps query service errors to HTTP responses
+func queryError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrQueryTimeout):
+		status = http.StatusGatewayTimeout
+	case strings.HasPrefix(err.Error(), "validation failed"):
+		status = http.StatusBadRequest
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReportController handles daily report requests
+type ReportController struct {
+	reportService *services.ReportService
+}
+
+// NewReportController creates a new report controller
+func NewReportController(reportService *services.ReportService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+// GetReports returns the user's report history
+func (rc *ReportController) GetReports(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	limit := 30
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 365 {
+			limit = parsed
+		}
+	}
+
+	history, err := rc.reportService.GetHistory(c.Request().Context(), userID, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve reports: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":   len(history),
+		"reports": history,
+	})
+}
+
+// GetReport returns a stored report
+func (rc *ReportController) GetReport(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid report ID",
+		})
+	}
+
+	report, err := rc.reportService.GetReport(c.Request().Context(), userID, id)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetLatestReport returns the user's most recent report
+func (rc *ReportController) GetLatestReport(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	report, err := rc.reportService.GetLatestReport(c.Request().Context(), userID)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// GenerateReport generates the user's report for a UTC day (default: yesterday)
+func (rc *ReportController) GenerateReport(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	day := time.Now().UTC().Add(-24 * time.Hour)
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil || parsed.After(time.Now().UTC()) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "date must be a past or current UTC day (YYYY-MM-DD)",
+			})
+		}
+		day = parsed
+	}
+
+	report, err := rc.reportService.GenerateReport(c.Request().Context(), userID, day)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, report)
+}
+
+// GetSettings returns the user's report watchlist and email settings
+func (rc *ReportController) GetSettings(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	settings, err := rc.reportService.GetSettings(c.Request().Context(), userID)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings updates the user's report watchlist and email settings
+func (rc *ReportController) UpdateSettings(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.UpdateReportSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	settings, err := rc.reportService.UpdateSettings(c.Request().Context(), userID, &req)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// reportError maps report service errors to HTTP responses
+func reportError(c echo.Context, err error) error {
+	message := err.Error()
+	switch {
+	case message == "report not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Report not found",
+		})
+	case strings.HasPrefix(message, "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
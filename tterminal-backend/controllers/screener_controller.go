@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ScreenerController handles market screener requests
+type ScreenerController struct {
+	screenerService *services.ScreenerService
+}
+
+// NewScreenerController creates a new screener controller
+func NewScreenerController(screenerService *services.ScreenerService) *ScreenerController {
+	return &ScreenerController{
+		screenerService: screenerService,
+	}
+}
+
+// GetScreener ranks the tracked symbols by a metric (?sort=, ?order=asc|desc), keeping those
+// within the min_<metric> and max_<metric> bounds
+// GET /api/v1/screener
+func (sc *ScreenerController) GetScreener(c echo.Context) error {
+	params := models.ScreenerParams{
+		Sort:  strings.ToLower(c.QueryParam("sort")),
+		Limit: queryInt(c, "limit", 50, 1, 500),
+		Min:   make(map[string]float64),
+		Max:   make(map[string]float64),
+	}
+
+	switch strings.ToLower(c.QueryParam("order")) {
+	case "", "desc":
+	case "asc":
+		params.Ascending = true
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "order must be asc or desc",
+		})
+	}
+
+	for _, metric := range models.ScreenerMetrics {
+		for prefix, bounds := range map[string]map[string]float64{"min_": params.Min, "max_": params.Max} {
+			name := prefix + metric
+			value := c.QueryParam(name)
+			if value == "" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "invalid " + name + ", must be a number",
+				})
+			}
+			bounds[metric] = parsed
+		}
+	}
+
+	response, err := sc.screenerService.Screen(c.Request().Context(), params)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "validation failed") {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=15")
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/http"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StatusController handles the public status page endpoint
+type StatusController struct {
+	statusService *services.StatusService
+}
+
+// NewStatusController creates a new status controller
+func NewStatusController(statusService *services.StatusService) *StatusController {
+	return &StatusController{
+		statusService: statusService,
+	}
+}
+
+// GetStatus returns component states, active incidents, recent stream reconnects and data freshness
+// Always answers 200 so the frontend banner can render outages instead of a failed request
+func (h *StatusController) GetStatus(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	return c.JSON(http.StatusOK, h.statusService.GetStatus(c.Request().Context()))
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StreamCaptureController handles downloads of archived stream captures for replay
+type StreamCaptureController struct {
+	streamCaptureService *services.StreamCaptureService
+}
+
+// NewStreamCaptureController creates a new stream capture controller
+func NewStreamCaptureController(streamCaptureService *services.StreamCaptureService) *StreamCaptureController {
+	return &StreamCaptureController{
+		streamCaptureService: streamCaptureService,
+	}
+}
+
+// GetCapture downloads stored events of a time range as a gzipped NDJSON stream capture
+// GET /api/v1/websocket/capture/:symbol?start=...&end=...&events=trades,klines,liquidations&interval=1m
+func (scc *StreamCaptureController) GetCapture(c echo.Context) error {
+	symbol := streamSymbol(c, c.Param("symbol"))
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Symbol is required",
+		})
+	}
+
+	startStr := c.QueryParam("start")
+	if startStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "start query parameter is required",
+		})
+	}
+	start, err := parseAnchorTime(startStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid start, use Unix milliseconds or RFC3339",
+		})
+	}
+	end := time.Now().UTC()
+	if endStr := c.QueryParam("end"); endStr != "" {
+		if end, err = parseAnchorTime(endStr); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid end, use Unix milliseconds or RFC3339",
+			})
+		}
+	}
+	interval := c.QueryParam("interval")
+	if interval == "" {
+		interval = "1m"
+	}
+
+	capture, err := scc.streamCaptureService.Capture(c.Request().Context(), models.StreamCaptureParams{
+		Symbol:   symbol,
+		Start:    start,
+		End:      end,
+		Events:   queryList(c, "events", nil),
+		Interval: interval,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "validation failed") {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	filename := fmt.Sprintf("%s-%d-%d.ndjson.gz", strings.ReplaceAll(symbol, ":", "_"), start.UnixMilli(), end.UnixMilli())
+	c.Response().Header().Set(echo.HeaderContentType, "application/gzip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().WriteHeader(http.StatusOK)
+
+	// Headers are sent, so a failure part way can only end the download early
+	written, err := capture.Write(c.Request().Context(), c.Response())
+	if err != nil {
+		log.Printf("[StreamCaptureController] Capture of %s stopped after %d messages: %v", symbol, written, err)
+	}
+	return nil
+}
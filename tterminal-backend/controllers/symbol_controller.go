@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"tterminal-backend/internal/audit"
 	"tterminal-backend/models"
 	"tterminal-backend/services"
 
@@ -93,6 +94,7 @@ func (sc *SymbolController) CreateSymbol(c echo.Context) error {
 			"error": "Failed to create symbol: " + err.Error(),
 		})
 	}
+	audit.Record(ctx, "symbol", symbol.Symbol, nil, symbol)
 
 	return c.JSON(http.StatusCreated, symbol)
 }
@@ -115,6 +117,7 @@ func (sc *SymbolController) UpdateSymbol(c echo.Context) error {
 		})
 	}
 
+	before, _ := sc.symbolService.GetSymbol(ctx, symbolName)
 	err := sc.symbolService.UpdateSymbol(ctx, symbolName, &req)
 	if err != nil {
 		if err.Error() == "symbol not found" {
@@ -127,6 +130,10 @@ func (sc *SymbolController) UpdateSymbol(c echo.Context) error {
 		})
 	}
 
+	if after, err := sc.symbolService.GetSymbol(ctx, symbolName); err == nil {
+		audit.Record(ctx, "symbol", symbolName, before, after)
+	}
+
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Symbol updated successfully",
 	})
@@ -143,6 +150,7 @@ func (sc *SymbolController) DeleteSymbol(c echo.Context) error {
 		})
 	}
 
+	before, _ := sc.symbolService.GetSymbol(ctx, symbolName)
 	err := sc.symbolService.DeleteSymbol(ctx, symbolName)
 	if err != nil {
 		if err.Error() == "symbol not found" {
@@ -155,6 +163,8 @@ func (sc *SymbolController) DeleteSymbol(c echo.Context) error {
 		})
 	}
 
+	audit.Record(ctx, "symbol", symbolName, before, nil)
+
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Symbol deleted successfully",
 	})
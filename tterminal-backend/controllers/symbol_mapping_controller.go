@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SymbolMappingController handles canonical symbol mapping requests
+// Canonical symbols are resolved for the data endpoints and stream subscriptions by middleware
+type SymbolMappingController struct {
+	mappingService *services.SymbolMappingService
+}
+
+// NewSymbolMappingController creates a new symbol mapping controller
+func NewSymbolMappingController(mappingService *services.SymbolMappingService) *SymbolMappingController {
+	return &SymbolMappingController{
+		mappingService: mappingService,
+	}
+}
+
+// GetCanonicalSymbols returns every canonical symbol with its per-exchange symbols
+func (mc *SymbolMappingController) GetCanonicalSymbols(c echo.Context) error {
+	symbols, err := mc.mappingService.GetCanonicalSymbols(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve symbol mappings: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":   len(symbols),
+		"symbols": symbols,
+	})
+}
+
+// GetCanonicalSymbol returns one canonical symbol with its per-exchange symbols
+func (mc *SymbolMappingController) GetCanonicalSymbol(c echo.Context) error {
+	symbol, err := mc.mappingService.GetCanonicalSymbol(c.Request().Context(), c.Param("canonical"))
+	if err != nil {
+		return symbolMappingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, symbol)
+}
+
+// SetMapping maps a canonical symbol to an exchange's symbol
+func (mc *SymbolMappingController) SetMapping(c echo.Context) error {
+	var req models.SetSymbolMappingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	mapping, err := mc.mappingService.SetMapping(c.Request().Context(), c.Param("canonical"), c.Param("exchange"), &req)
+	if err != nil {
+		return symbolMappingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, mapping)
+}
+
+// DeleteMapping removes a canonical symbol's mapping on an exchange
+func (mc *SymbolMappingController) DeleteMapping(c echo.Context) error {
+	if err := mc.mappingService.DeleteMapping(c.Request().Context(), c.Param("canonical"), c.Param("exchange")); err != nil {
+		return symbolMappingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Symbol mapping deleted successfully",
+	})
+}
+
+// symbolMappingError maps symbol mapping service errors to HTTP responses
+func symbolMappingError(c echo.Context, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrCanonicalSymbolNotFound), errors.Is(err, services.ErrSymbolMappingNotFound):
+		status = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "validation failed"):
+		status = http.StatusBadRequest
+	}
+
+	return c.JSON(status, map[string]string{
+		"error": err.Error(),
+	})
+}
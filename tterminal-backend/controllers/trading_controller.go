@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/internal/audit"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TradingController handles live futures order requests
+type TradingController struct {
+	tradingService *services.TradingService // Nil without Binance API credentials
+}
+
+// NewTradingController creates a new trading controller; a nil service answers every request with 503
+func NewTradingController(tradingService *services.TradingService) *TradingController {
+	return &TradingController{
+		tradingService: tradingService,
+	}
+}
+
+// GetOrders returns recent live orders, newest first
+// GET /api/v1/trading/orders?symbol=BTCUSDT&open=true&limit=100
+func (tc *TradingController) GetOrders(c echo.Context) error {
+	if tc.tradingService == nil {
+		return tradingDisabled(c)
+	}
+
+	openOnly, _ := strconv.ParseBool(c.QueryParam("open"))
+	orders, err := tc.tradingService.GetOrders(c.Request().Context(), c.QueryParam("symbol"), openOnly, queryInt(c, "limit", 100, 1, 500))
+	if err != nil {
+		return tradingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":  len(orders),
+		"orders": orders,
+	})
+}
+
+// GetOrder returns a live order, refreshed from Binance while it is open
+// GET /api/v1/trading/orders/:id
+func (tc *TradingController) GetOrder(c echo.Context) error {
+	if tc.tradingService == nil {
+		return tradingDisabled(c)
+	}
+	id, ok := tradingOrderID(c)
+	if !ok {
+		return invalidTradingOrderID(c)
+	}
+
+	order, err := tc.tradingService.GetOrder(c.Request().Context(), id)
+	if err != nil {
+		return tradingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// PlaceOrder places a live futures order
+// POST /api/v1/trading/orders
+func (tc *TradingController) PlaceOrder(c echo.Context) error {
+	if tc.tradingService == nil {
+		return tradingDisabled(c)
+	}
+
+	var req models.PlaceTradingOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+			"code":  "INVALID_ORDER",
+		})
+	}
+
+	order, err := tc.tradingService.PlaceOrder(c.Request().Context(), requestUserID(c), &req)
+	if err != nil {
+		return tradingError(c, err)
+	}
+	audit.Record(c.Request().Context(), "trading_order", strconv.FormatInt(order.ID, 10), nil, order)
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+// AmendOrder changes the quantity and/or price of an open limit order
+// PUT /api/v1/trading/orders/:id
+func (tc *TradingController) AmendOrder(c echo.Context) error {
+	if tc.tradingService == nil {
+		return tradingDisabled(c)
+	}
+	id, ok := tradingOrderID(c)
+	if !ok {
+		return invalidTradingOrderID(c)
+	}
+
+	var req models.AmendTradingOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+			"code":  "INVALID_ORDER",
+		})
+	}
+
+	before, _ := tc.tradingService.StoredOrder(c.Request().Context(), id)
+	order, err := tc.tradingService.AmendOrder(c.Request().Context(), id, &req)
+	if err != nil {
+		return tradingError(c, err)
+	}
+	audit.Record(c.Request().Context(), "trading_order", strconv.FormatInt(id, 10), before, order)
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// CancelOrder cancels an open order
+// DELETE /api/v1/trading/orders/:id
+func (tc *TradingController) CancelOrder(c echo.Context) error {
+	if tc.tradingService == nil {
+		return tradingDisabled(c)
+	}
+	id, ok := tradingOrderID(c)
+	if !ok {
+		return invalidTradingOrderID(c)
+	}
+
+	before, _ := tc.tradingService.StoredOrder(c.Request().Context(), id)
+	order, err := tc.tradingService.CancelOrder(c.Request().Context(), id)
+	if err != nil {
+		return tradingError(c, err)
+	}
+	audit.Record(c.Request().Context(), "trading_order", strconv.FormatInt(id, 10), before, order)
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// tradingOrderID parses the order ID path parameter
+func tradingOrderID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// invalidTradingOrderID responds to requests with a malformed order ID
+func invalidTradingOrderID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Invalid order ID",
+		"code":  "INVALID_ORDER",
+	})
+}
+
+// tradingDisabled responds when live trading is not enabled
+func tradingDisabled(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{
+		"error": "live trading is disabled; set TRADING_ENABLED and the Binance API credentials to enable it",
+		"code":  "TRADING_DISABLED",
+	})
+}
+
+// tradingError maps trading service errors to HTTP responses in the same format as serviceError:
+// an error message, a machine-readable code and the request ID. Binance failures keep their own
+// status and code
+func tradingError(c echo.Context, err error) error {
+	status, code := http.StatusInternalServerError, ""
+	body := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	var filterErr *models.OrderFilterError
+	message := err.Error()
+	switch {
+	case errors.As(err, &filterErr):
+		status, code = http.StatusBadRequest, "ORDER_FILTER_REJECTED"
+		body["violations"] = filterErr.Violations
+	case message == "trading order not found":
+		status, code = http.StatusNotFound, "ORDER_NOT_FOUND"
+	case strings.HasPrefix(message, "validation failed"):
+		status, code = http.StatusBadRequest, "INVALID_ORDER"
+	case errors.Is(err, services.ErrTradingDisabled), errors.Is(err, binance.ErrNoCredentials):
+		status, code = http.StatusServiceUnavailable, "TRADING_DISABLED"
+	default:
+		status, code = errorStatus(err)
+		setRetryAfter(c, err)
+	}
+
+	if code != "" {
+		body["code"] = code
+	}
+	if requestID := c.Response().Header().Get(echo.HeaderXRequestID); requestID != "" {
+		body["request_id"] = requestID
+	}
+	return c.JSON(status, body)
+}
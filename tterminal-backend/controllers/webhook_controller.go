@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookController handles user webhook requests
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// GetWebhooks returns all webhooks of the requesting user
+func (wc *WebhookController) GetWebhooks(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	webhooks, err := wc.webhookService.GetWebhooks(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve webhooks: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":    len(webhooks),
+		"webhooks": webhooks,
+	})
+}
+
+// GetWebhook returns a single webhook
+func (wc *WebhookController) GetWebhook(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	webhook, err := wc.webhookService.GetWebhook(c.Request().Context(), userID, id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// CreateWebhook registers a webhook; the response carries the signing secret, shown only once
+func (wc *WebhookController) CreateWebhook(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	webhook, err := wc.webhookService.CreateWebhook(c.Request().Context(), userID, &req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, webhook)
+}
+
+// UpdateWebhook changes a webhook's endpoint, subscriptions or active flag
+func (wc *WebhookController) UpdateWebhook(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body: " + err.Error(),
+		})
+	}
+
+	webhook, err := wc.webhookService.UpdateWebhook(c.Request().Context(), userID, id, &req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// RotateSecret replaces a webhook's signing secret, returning the new secret once
+func (wc *WebhookController) RotateSecret(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	webhook, err := wc.webhookService.RotateSecret(c.Request().Context(), userID, id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (wc *WebhookController) DeleteWebhook(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	if err := wc.webhookService.DeleteWebhook(c.Request().Context(), userID, id); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// GetDeliveries returns a webhook's delivery log, newest first; ?status=failed lists the dead letters
+func (wc *WebhookController) GetDeliveries(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	limit := 100
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	deliveries, err := wc.webhookService.GetDeliveries(c.Request().Context(), userID, id, c.QueryParam("status"), limit)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"webhook_id": id,
+		"count":      len(deliveries),
+		"deliveries": deliveries,
+	})
+}
+
+// SendTest queues a ping delivery to a webhook
+func (wc *WebhookController) SendTest(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	delivery, err := wc.webhookService.SendTest(c.Request().Context(), userID, id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+// ReplayDelivery sends a dead letter again with a fresh set of attempts
+func (wc *WebhookController) ReplayDelivery(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+	if err != nil || deliveryID <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid delivery ID",
+		})
+	}
+
+	replayed, err := wc.webhookService.ReplayDeliveries(c.Request().Context(), userID, id, deliveryID)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, replayed[0])
+}
+
+// ReplayDeadLetters sends every dead letter of a webhook again with a fresh set of attempts
+func (wc *WebhookController) ReplayDeadLetters(c echo.Context) error {
+	userID := requestUserID(c)
+	if userID == "" {
+		return missingUserID(c)
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return invalidWebhookID(c)
+	}
+
+	replayed, err := wc.webhookService.ReplayDeliveries(c.Request().Context(), userID, id, 0)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"webhook_id": id,
+		"count":      len(replayed),
+		"deliveries": replayed,
+	})
+}
+
+// webhookID parses the webhook ID path parameter
+func webhookID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// invalidWebhookID responds to requests with a malformed webhook ID
+func invalidWebhookID(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "Invalid webhook ID",
+	})
+}
+
+// webhookError maps webhook service errors to HTTP responses
+func webhookError(c echo.Context, err error) error {
+	message := err.Error()
+	switch {
+	case message == "webhook not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Webhook not found",
+		})
+	case message == "delivery not found":
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Delivery not found",
+		})
+	case strings.HasPrefix(message, "validation failed"):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": message,
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": message,
+		})
+	}
+}
@@ -6,19 +6,44 @@ import (
 	"strings"
 	"time"
 
+	"tterminal-backend/internal/middleware"
+	"tterminal-backend/internal/synthetic"
 	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
 
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// Default and maximum wait for a long-poll request
+	defaultPollTimeout = 25 * time.Second
+	maxPollTimeout     = 30 * time.Second
+
+	// Default session recording length, and page sizes for reading one back
+	defaultRecordingMinutes = 15
+	defaultRecordingLimit   = 500
+	maxRecordingLimit       = 5000
+)
+
 // WebSocketController handles WebSocket-related endpoints
 type WebSocketController struct {
 	hub           *websocket.Hub
 	binanceStream *websocket.BinanceStream
+	// Optional streams of other exchanges, serving their qualified symbols ("BYBIT:BTCUSDT")
+	exchangeStreams map[string]ExchangeStream
+}
+
+// ExchangeStream is the stream cache of a non-Binance exchange
+type ExchangeStream interface {
+	GetConnectedSymbols() []string
+	GetLastPrice(symbol string) (float64, bool)
+	GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent
+	GetStreamStats() map[string]interface{}
 }
 
 // NewWebSocketController creates a new WebSocket controller
-func NewWebSocketController() *WebSocketController {
+// A non-nil generator replaces the Binance streams with synthetic market data
+func NewWebSocketController(generator *synthetic.Generator) *WebSocketController {
 	// Create WebSocket hub
 	hub := websocket.NewHub()
 
@@ -28,6 +53,9 @@ func NewWebSocketController() *WebSocketController {
 	// Create Binance stream with popular symbols
 	symbols := []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "ADAUSDT", "SOLUSDT"}
 	binanceStream := websocket.NewBinanceStream(hub, symbols)
+	if generator != nil {
+		binanceStream.UseSyntheticFeed(generator)
+	}
 
 	// Start Binance stream
 	if err := binanceStream.Start(); err != nil {
@@ -41,12 +69,90 @@ func NewWebSocketController() *WebSocketController {
 	}
 }
 
+// SetExchangeStream enables an exchange's symbols on the stream cache endpoints and embeds
+func (wsc *WebSocketController) SetExchangeStream(exchange string, stream ExchangeStream) {
+	if wsc.exchangeStreams == nil {
+		wsc.exchangeStreams = make(map[string]ExchangeStream)
+	}
+	wsc.exchangeStreams[exchange] = stream
+}
+
+// streamSymbol reads the symbol path parameter, qualified by the optional "exchange" query parameter
+func streamSymbol(c echo.Context, symbol string) string {
+	if symbol == "" {
+		return ""
+	}
+	return models.QualifySymbol(c.QueryParam("exchange"), strings.ToUpper(symbol))
+}
+
+// exchangeStream returns the stream serving a qualified non-Binance symbol, or nil
+func (wsc *WebSocketController) exchangeStream(symbol string) ExchangeStream {
+	return wsc.exchangeStreams[models.SymbolExchange(symbol)]
+}
+
+// LastPrice returns the last streamed price of a symbol key from the stream serving it
+func (wsc *WebSocketController) LastPrice(symbol string) (float64, bool) {
+	if stream := wsc.exchangeStream(symbol); stream != nil {
+		return stream.GetLastPrice(symbol)
+	}
+	return wsc.binanceStream.GetLastPrice(symbol)
+}
+
 // HandleWebSocket upgrades HTTP connection to WebSocket
+// The client belongs to the user of the access_token query parameter, if any
 func (wsc *WebSocketController) HandleWebSocket(c echo.Context) error {
-	wsc.hub.HandleWebSocket(c.Response(), c.Request())
+	if hint := wsc.hub.GetDrainHint(); hint != nil {
+		return drainingError(c, hint)
+	}
+	wsc.hub.HandleWebSocket(c.Response(), c.Request(), middleware.AuthenticatedUser(c))
 	return nil
 }
 
+// HandleLiteWebSocket upgrades a watch-only lite connection for an embedded mini-chart
+// The symbol is fixed by the "symbol" query parameter and must be one the server streams
+func (wsc *WebSocketController) HandleLiteWebSocket(c echo.Context) error {
+	if hint := wsc.hub.GetDrainHint(); hint != nil {
+		return drainingError(c, hint)
+	}
+	symbol := streamSymbol(c, c.QueryParam("symbol"))
+	if symbol == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "symbol query parameter is required",
+		})
+	}
+
+	connected := wsc.binanceStream.GetConnectedSymbols()
+	if stream := wsc.exchangeStream(symbol); stream != nil {
+		connected = stream.GetConnectedSymbols()
+	}
+	streamed := false
+	for _, existing := range connected {
+		if existing == symbol {
+			streamed = true
+			break
+		}
+	}
+	if !streamed {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Symbol is not streamed: " + symbol,
+		})
+	}
+
+	err := wsc.hub.HandleLiteWebSocket(c.Response(), c.Request(), symbol, c.RealIP())
+	switch err {
+	case nil:
+		return nil
+	case websocket.ErrLiteIPLimit:
+		return c.JSON(http.StatusTooManyRequests, map[string]string{
+			"error": err.Error(),
+		})
+	default:
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": err.Error(),
+		})
+	}
+}
+
 // GetWebSocketStats returns WebSocket connection statistics
 func (wsc *WebSocketController) GetWebSocketStats(c echo.Context) error {
 	// Get enhanced stream statistics
@@ -54,7 +160,12 @@ func (wsc *WebSocketController) GetWebSocketStats(c echo.Context) error {
 
 	stats := map[string]interface{}{
 		"connected_clients": wsc.hub.GetConnectedClients(),
+		"poll_sessions":     wsc.hub.GetPollSessionCount(),
+		"lite":              wsc.hub.GetLiteStats(),
+		"bandwidth":         wsc.hub.GetBandwidthStats(),
+		"load":              wsc.hub.GetLoadStats(),
 		"subscriptions":     wsc.hub.GetSubscriptionStats(),
+		"channels":          wsc.hub.GetChannelStats(),
 		"binance_stream":    streamStats,
 		"service":           "websocket",
 		"status":            "active",
@@ -68,6 +179,8 @@ func (wsc *WebSocketController) GetWebSocketStats(c echo.Context) error {
 		},
 		"endpoints": map[string]string{
 			"websocket":    "/api/v1/websocket/connect",
+			"long_poll":    "/api/v1/websocket/poll",
+			"embed":        "/api/v1/embed/connect?symbol={symbol}",
 			"price":        "/api/v1/websocket/price/{symbol}",
 			"depth":        "/api/v1/websocket/depth/{symbol}",
 			"trades":       "/api/v1/websocket/trades/{symbol}",
@@ -77,17 +190,21 @@ func (wsc *WebSocketController) GetWebSocketStats(c echo.Context) error {
 		},
 	}
 
+	for exchange, stream := range wsc.exchangeStreams {
+		stats[exchange+"_stream"] = stream.GetStreamStats()
+	}
+
 	return c.JSON(200, stats)
 }
 
 // GetLastPrice returns the last known price for a symbol
 func (wsc *WebSocketController) GetLastPrice(c echo.Context) error {
-	symbol := strings.ToUpper(c.Param("symbol"))
+	symbol := streamSymbol(c, c.Param("symbol"))
 	if symbol == "" {
 		return c.JSON(400, map[string]string{"error": "Symbol parameter is required"})
 	}
 
-	price, exists := wsc.binanceStream.GetLastPrice(symbol)
+	price, exists := wsc.LastPrice(symbol)
 	if !exists {
 		return c.JSON(404, map[string]string{"error": "Price data not found for symbol"})
 	}
@@ -115,14 +232,12 @@ func (wsc *WebSocketController) GetDepthData(c echo.Context) error {
 	}
 
 	response := map[string]interface{}{
-		"symbol":          symbol,
-		"bids":            depth.Bids,
-		"asks":            depth.Asks,
-		"first_update_id": depth.FirstUpdateID,
-		"final_update_id": depth.FinalUpdateID,
-		"event_time":      depth.EventTime,
-		"timestamp":       time.Now().UnixMilli(),
-		"source":          "websocket_cache",
+		"symbol":     symbol,
+		"bids":       depth.Bids,
+		"asks":       depth.Asks,
+		"event_time": depth.Time.UnixMilli(),
+		"timestamp":  time.Now().UnixMilli(),
+		"source":     "websocket_cache",
 	}
 
 	return c.JSON(200, response)
@@ -173,6 +288,9 @@ func (wsc *WebSocketController) GetVolumeData(c echo.Context) error {
 	if interval == "" {
 		interval = "1m"
 	}
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
 
 	// Get current kline data for the specified interval
 	klineData, exists := wsc.binanceStream.GetKlineData(symbol, interval)
@@ -181,8 +299,8 @@ func (wsc *WebSocketController) GetVolumeData(c echo.Context) error {
 	}
 
 	// Parse volume data from kline
-	totalVolume, _ := strconv.ParseFloat(klineData.Kline.Volume, 64)
-	takerBuyVolume, _ := strconv.ParseFloat(klineData.Kline.TakerBuyBaseVolume, 64)
+	totalVolume, _ := strconv.ParseFloat(klineData.Volume, 64)
+	takerBuyVolume, _ := strconv.ParseFloat(klineData.BuyVolume, 64)
 
 	// Calculate buy/sell volumes
 	buyVolume := takerBuyVolume
@@ -203,14 +321,11 @@ func (wsc *WebSocketController) GetVolumeData(c echo.Context) error {
 	// Convert trades to simplified format
 	simplifiedTrades := make([]map[string]interface{}, 0, len(recentTrades))
 	for _, trade := range recentTrades {
-		price, _ := strconv.ParseFloat(trade.Price, 64)
-		quantity, _ := strconv.ParseFloat(trade.Quantity, 64)
-
 		simplifiedTrades = append(simplifiedTrades, map[string]interface{}{
-			"price":     price,
-			"quantity":  quantity,
+			"price":     trade.Price,
+			"quantity":  trade.Quantity,
 			"is_buy":    !trade.IsBuyerMaker, // Inverted: if buyer is maker, it's a sell order
-			"timestamp": trade.TradeTime,
+			"timestamp": trade.TradeTime.UnixMilli(),
 		})
 	}
 
@@ -224,8 +339,8 @@ func (wsc *WebSocketController) GetVolumeData(c echo.Context) error {
 			"delta":           delta,
 			"buy_percentage":  buyPercentage,
 			"sell_percentage": sellPercentage,
-			"start_time":      klineData.Kline.StartTime,
-			"is_closed":       klineData.Kline.IsClosed,
+			"start_time":      klineData.OpenTime.UnixMilli(),
+			"is_closed":       klineData.Closed,
 		},
 		"recent_trades": simplifiedTrades,
 		"timestamp":     time.Now().UnixMilli(),
@@ -246,6 +361,9 @@ func (wsc *WebSocketController) GetKlineData(c echo.Context) error {
 	if interval == "" {
 		return c.JSON(400, map[string]string{"error": "Interval parameter is required"})
 	}
+	if !models.IsValidInterval(interval) {
+		return invalidInterval(c, interval)
+	}
 
 	kline, exists := wsc.binanceStream.GetKlineData(symbol, interval)
 	if !exists {
@@ -253,12 +371,11 @@ func (wsc *WebSocketController) GetKlineData(c echo.Context) error {
 	}
 
 	response := map[string]interface{}{
-		"symbol":     symbol,
-		"interval":   interval,
-		"kline":      kline.Kline,
-		"event_time": kline.EventTime,
-		"timestamp":  time.Now().UnixMilli(),
-		"source":     "websocket_cache",
+		"symbol":    symbol,
+		"interval":  interval,
+		"kline":     kline,
+		"timestamp": time.Now().UnixMilli(),
+		"source":    "websocket_cache",
 	}
 
 	return c.JSON(200, response)
@@ -282,8 +399,8 @@ func (wsc *WebSocketController) GetMarkPriceData(c echo.Context) error {
 		"index_price":       markPrice.IndexPrice,
 		"estimated_price":   markPrice.EstimatedPrice,
 		"funding_rate":      markPrice.FundingRate,
-		"next_funding_time": markPrice.NextFundingTime,
-		"event_time":        markPrice.EventTime,
+		"next_funding_time": markPrice.NextFundingTime.UnixMilli(),
+		"event_time":        markPrice.Time.UnixMilli(),
 		"timestamp":         time.Now().UnixMilli(),
 		"source":            "websocket_cache",
 	}
@@ -293,7 +410,7 @@ func (wsc *WebSocketController) GetMarkPriceData(c echo.Context) error {
 
 // GetRecentLiquidations returns recent Futures liquidations for a symbol
 func (wsc *WebSocketController) GetRecentLiquidations(c echo.Context) error {
-	symbol := strings.ToUpper(c.Param("symbol"))
+	symbol := streamSymbol(c, c.Param("symbol"))
 	if symbol == "" {
 		return c.JSON(400, map[string]string{"error": "Symbol parameter is required"})
 	}
@@ -307,11 +424,15 @@ func (wsc *WebSocketController) GetRecentLiquidations(c echo.Context) error {
 		}
 	}
 
-	liquidations := wsc.binanceStream.GetRecentLiquidations(symbol, limit)
-
-	// Return empty array instead of error when no liquidations exist
+	var liquidations []models.LiquidationEvent
+	if stream := wsc.exchangeStream(symbol); stream != nil {
+		liquidations = stream.GetRecentLiquidations(symbol, limit)
+	} else {
+		liquidations = wsc.binanceStream.GetRecentLiquidations(symbol, limit)
+	}
+	// An empty array instead of an error when none exist
 	if liquidations == nil {
-		liquidations = []*websocket.BinanceLiquidationData{}
+		liquidations = []models.LiquidationEvent{}
 	}
 
 	response := map[string]interface{}{
@@ -344,6 +465,166 @@ func (wsc *WebSocketController) AddSymbolToStream(c echo.Context) error {
 	})
 }
 
+// OpenPollSession opens a long-polling session for clients that cannot keep a WebSocket open
+// Accepts the same access_token and resubscribe query parameters as the WebSocket endpoint; the
+// session belongs to the token's user, and later poll requests must carry the same token
+func (wsc *WebSocketController) OpenPollSession(c echo.Context) error {
+	if hint := wsc.hub.GetDrainHint(); hint != nil {
+		return drainingError(c, hint)
+	}
+	sessionID := wsc.hub.OpenPollSession(middleware.AuthenticatedUser(c), c.QueryParam("resubscribe") == "true")
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"session_id":  sessionID,
+		"poll":        "/api/v1/websocket/poll/" + sessionID,
+		"messages":    "/api/v1/websocket/poll/" + sessionID + "/messages",
+		"max_timeout": int(maxPollTimeout / time.Second),
+		"timestamp":   time.Now().UnixMilli(),
+	})
+}
+
+// Poll waits for messages on a long-polling session
+func (wsc *WebSocketController) Poll(c echo.Context) error {
+	timeout := defaultPollTimeout
+	if timeoutStr := c.QueryParam("timeout"); timeoutStr != "" {
+		if seconds, err := strconv.Atoi(timeoutStr); err == nil && seconds >= 0 {
+			timeout = time.Duration(seconds) * time.Second
+			if timeout > maxPollTimeout {
+				timeout = maxPollTimeout
+			}
+		}
+	}
+
+	messages, err := wsc.hub.PollSessionReceive(c.Request().Context(), c.Param("session"), middleware.AuthenticatedUser(c), timeout)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"messages":  messages,
+		"count":     len(messages),
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// SendPollMessage handles a subscribe/unsubscribe/ping message for a long-polling session
+// The body uses the same format as WebSocket client messages
+func (wsc *WebSocketController) SendPollMessage(c echo.Context) error {
+	var message websocket.ClientMessage
+	if err := c.Bind(&message); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid message: " + err.Error()})
+	}
+
+	if err := wsc.hub.PollSessionSend(c.Param("session"), middleware.AuthenticatedUser(c), message); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"accepted":  true,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// ClosePollSession closes a long-polling session
+func (wsc *WebSocketController) ClosePollSession(c echo.Context) error {
+	if err := wsc.hub.ClosePollSession(c.Param("session"), middleware.AuthenticatedUser(c)); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Poll session closed"})
+}
+
+// StartRecording starts recording the stream sent to a connected client for support
+// Body: {"client_id": "...", "minutes": 15}; the client must have connected with allow_recording=true
+func (wsc *WebSocketController) StartRecording(c echo.Context) error {
+	var req struct {
+		ClientID string `json:"client_id"`
+		Minutes  int    `json:"minutes"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request: " + err.Error()})
+	}
+	if req.ClientID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "client_id is required"})
+	}
+	if req.Minutes <= 0 {
+		req.Minutes = defaultRecordingMinutes
+	}
+
+	recording, err := wsc.hub.StartRecording(req.ClientID, time.Duration(req.Minutes)*time.Minute)
+	if err != nil {
+		return c.JSON(recordingErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, recording)
+}
+
+// GetRecording returns a session recording with a page of its messages
+// Query parameters: offset (default 0) and limit (default 500, max 5000)
+func (wsc *WebSocketController) GetRecording(c echo.Context) error {
+	offset := 0
+	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid offset"})
+		}
+		offset = parsed
+	}
+
+	limit := defaultRecordingLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+		}
+		if parsed > maxRecordingLimit {
+			parsed = maxRecordingLimit
+		}
+		limit = parsed
+	}
+
+	page, err := wsc.hub.GetRecording(c.Request().Context(), c.Param("id"), offset, limit)
+	if err != nil {
+		return c.JSON(recordingErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+// StopRecording ends an active session recording early
+func (wsc *WebSocketController) StopRecording(c echo.Context) error {
+	if err := wsc.hub.StopRecording(c.Param("id")); err != nil {
+		return c.JSON(recordingErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{"message": "Recording stopping"})
+}
+
+// recordingErrorStatus maps session recording errors to status codes
+func recordingErrorStatus(err error) int {
+	switch err {
+	case websocket.ErrRecordingClientNotFound, websocket.ErrRecordingNotFound:
+		return http.StatusNotFound
+	case websocket.ErrRecordingNotAllowed:
+		return http.StatusForbidden
+	case websocket.ErrRecordingActive:
+		return http.StatusConflict
+	case websocket.ErrRecordingUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// drainingError refuses a new connection while the instance drains, pointing at healthy peers
+func drainingError(c echo.Context, hint *websocket.ReconnectHint) error {
+	return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+		"error": websocket.ErrDraining.Error(),
+		"code":  "DRAINING",
+		"peers": hint.Peers,
+	})
+}
+
 // GetHub returns the WebSocket hub (for use in other parts of the application)
 func (wsc *WebSocketController) GetHub() *websocket.Hub {
 	return wsc.hub
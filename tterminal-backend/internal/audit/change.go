@@ -0,0 +1,152 @@
+// Package audit carries what a state-changing API request changed from the handler to the audit
+// log: the audit middleware opens a change in the request context, handlers record the affected
+// resource's state before and after, and the middleware stores the difference with the request
+//
+// Handlers of requests without an audit change in their context (background work, routes not
+// audited) record nothing, so recording is always safe
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"tterminal-backend/models"
+)
+
+type contextKey struct{}
+
+// Change is the resource a request changed with its state before and after
+// Before is nil for created resources, After for deleted ones
+type Change struct {
+	Resource   string
+	ResourceID string
+	Before     interface{}
+	After      interface{}
+}
+
+// WithChange returns a context carrying an empty change for handlers to record into
+func WithChange(ctx context.Context) (context.Context, *Change) {
+	change := &Change{}
+	return context.WithValue(ctx, contextKey{}, change), change
+}
+
+// Record records the state of the resource a request changed
+func Record(ctx context.Context, resource, resourceID string, before, after interface{}) {
+	change, ok := ctx.Value(contextKey{}).(*Change)
+	if !ok {
+		return
+	}
+	change.Resource = resource
+	change.ResourceID = resourceID
+	change.Before = before
+	change.After = after
+}
+
+// Recorded reports whether a handler recorded the change
+func (c *Change) Recorded() bool {
+	return c.Resource != ""
+}
+
+// Diff returns the top-level fields whose value differs between the before and after states,
+// compared in their JSON form. Values of sensitive fields are redacted, so a changed secret shows
+// as changed without being stored. States that are not JSON objects are compared as a whole
+// under the "value" field
+func (c *Change) Diff() map[string]models.AuditFieldChange {
+	before, after := jsonFields(c.Before), jsonFields(c.After)
+
+	diff := make(map[string]models.AuditFieldChange)
+	for field, old := range before {
+		if value, ok := after[field]; !ok || !reflect.DeepEqual(old, value) {
+			diff[field] = models.AuditFieldChange{Old: redactField(field, old), New: redactField(field, after[field])}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			diff[field] = models.AuditFieldChange{New: redactField(field, value)}
+		}
+	}
+	return diff
+}
+
+// jsonFields decodes a state's JSON form into its fields
+func jsonFields(state interface{}) map[string]interface{} {
+	if state == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(encoded, &value); err != nil || value == nil {
+		return nil
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{"value": value}
+	}
+	return fields
+}
+
+// Redacted is the value stored in place of sensitive fields
+const Redacted = "[REDACTED]"
+
+// sensitiveFields are the field names (or name parts) never stored in the audit log
+var sensitiveFields = []string{"password", "secret", "token", "api_key", "apikey", "private_key"}
+
+// RedactJSON returns a JSON document with the values of sensitive fields replaced, at any depth
+// ok is false when the document is not valid JSON
+func RedactJSON(document []byte) (redacted []byte, ok bool) {
+	var value interface{}
+	if err := json.Unmarshal(document, &value); err != nil {
+		return nil, false
+	}
+	redacted, err := json.Marshal(redact(value))
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+// redact replaces the values of sensitive fields in a decoded JSON value
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitive(key) {
+				v[key] = Redacted
+			} else {
+				v[key] = redact(field)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// redactField redacts a top-level field's value
+func redactField(field string, value interface{}) interface{} {
+	if value != nil && isSensitive(field) {
+		return Redacted
+	}
+	return redact(value)
+}
+
+// isSensitive reports whether a field name holds a credential
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, sensitive := range sensitiveFields {
+		if strings.Contains(field, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// tokenHeader is the JOSE header of every token: HMAC-SHA256 signed JWTs only
+const tokenHeader = `{"alg":"HS256","typ":"JWT"}`
+
+// Token verification failures
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrInvalidToken   = errors.New("invalid token signature")
+	ErrExpiredToken   = errors.New("token has expired")
+)
+
+// Claims are the registered JWT claims carried by access tokens
+type Claims struct {
+	Subject     string   `json:"sub"` // User ID
+	Email       string   `json:"email,omitempty"`
+	Role        string   `json:"role,omitempty"` // Empty in tokens issued before roles, treated as a user
+	Permissions []string `json:"permissions"`    // Held as an admin; null, or absent in older tokens, for every one
+	Issuer      string   `json:"iss,omitempty"`
+	IssuedAt    int64    `json:"iat"`
+	ExpiresAt   int64    `json:"exp"`
+}
+
+// Sign encodes the claims as an HS256 JWT signed with secret
+func Sign(claims Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encodeSegment([]byte(tokenHeader)) + "." + encodeSegment(payload)
+	return unsigned + "." + encodeSegment(signature(unsigned, secret)), nil
+}
+
+// Verify checks an HS256 JWT's signature and expiry and returns its claims
+// Tokens with any other algorithm are refused, whatever their header claims
+func Verify(token string, secret []byte, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	var jose struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &jose); err != nil || jose.Alg != "HS256" {
+		return nil, ErrMalformedToken
+	}
+
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !hmac.Equal(sig, signature(parts[0]+"."+parts[1], secret)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return nil, ErrMalformedToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+// signature is the HMAC-SHA256 of a token's header and payload segments
+func signature(unsigned string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+// encodeSegment encodes a token segment as unpadded base64url
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSegment decodes an unpadded base64url token segment
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
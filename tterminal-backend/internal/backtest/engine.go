@@ -0,0 +1,264 @@
+// Package backtest replays stored candles, and optionally the trades between them, through a
+// strategy and simulates the account trading its signals: fills at the next price after each
+// decision, with fees and slippage, an equity curve marked at every bar close and the list of
+// round-trip trades
+//
+// The engine holds no I/O; services.BacktestService loads the data and runs it
+package backtest
+
+import (
+	"context"
+	"math"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Config holds the simulated account's settings
+type Config struct {
+	InitialEquity float64
+	PositionSize  float64 // Fraction of equity held at a target of 1
+	FeeRate       float64 // Fraction of notional charged per fill
+	SlippageBps   float64 // Adverse price move per fill, in basis points
+	AllowShort    bool    // Negative targets go flat when false
+}
+
+// Result is a completed simulation
+type Result struct {
+	Summary     models.BacktestSummary
+	EquityCurve []models.BacktestEquityPoint
+	Trades      []models.BacktestTrade
+}
+
+// Run replays bars, oldest first, through a strategy
+// When trades are given (oldest first) they are replayed before the bar they fall in, a changed
+// target fills at the next trade and a TradeStrategy is also called with every trade; otherwise a
+// changed target fills at the next bar's open. A position still open at the end is closed at the
+// last close. Run returns the context's error if it ends first
+func Run(ctx context.Context, strategy Strategy, bars []Bar, trades []Trade, cfg Config) (*Result, error) {
+	acc := &account{cfg: cfg, cash: cfg.InitialEquity, peak: cfg.InitialEquity}
+	tradeStrategy, decidesOnTrades := strategy.(TradeStrategy)
+	replayTrades := len(trades) > 0
+
+	curve := make([]models.BacktestEquityPoint, 0, len(bars))
+	exposedBars := 0
+	next := 0
+	for i, bar := range bars {
+		if i%1000 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if replayTrades {
+			for next < len(trades) && !trades[next].Time.After(bar.CloseTime) {
+				trade := trades[next]
+				next++
+				acc.fillPending(trade.Time, trade.Price)
+				if decidesOnTrades {
+					acc.request(tradeStrategy.OnTrade(trade))
+				}
+			}
+		} else {
+			acc.fillPending(bar.Time, bar.Open)
+		}
+
+		acc.request(strategy.OnBar(bar))
+
+		if acc.qty != 0 {
+			exposedBars++
+		}
+		if i == len(bars)-1 && acc.qty != 0 {
+			acc.fill(bar.CloseTime, bar.Close, 0)
+			acc.trades[len(acc.trades)-1].ClosedAtEnd = true
+		}
+		curve = append(curve, acc.mark(bar))
+	}
+
+	result := &Result{
+		Summary:     acc.summary(),
+		EquityCurve: curve,
+		Trades:      acc.trades,
+	}
+	if result.Trades == nil {
+		result.Trades = []models.BacktestTrade{}
+	}
+	if len(bars) > 0 {
+		result.Summary.ExposurePct = float64(exposedBars) / float64(len(bars)) * 100
+		if first := bars[0].Open; first > 0 {
+			result.Summary.BuyHoldReturnPct = (bars[len(bars)-1].Close - first) / first * 100
+		}
+	}
+	return result, nil
+}
+
+// account is the simulated account
+type account struct {
+	cfg       Config
+	cash      float64
+	qty       float64 // Signed position
+	target    float64 // Last target requested
+	held      float64 // Target of the last fill
+	pending   bool    // target differs from held and waits for the next price
+	feesPaid  float64
+	peak      float64
+	maxDD     float64
+	trades    []models.BacktestTrade
+	roundTrip *roundTrip
+}
+
+// roundTrip accumulates the fills of the open round trip
+type roundTrip struct {
+	trade         *models.BacktestTrade
+	side          float64 // 1 long, -1 short
+	entryQty      float64
+	entryNotional float64
+	exitQty       float64
+	exitNotional  float64
+	realized      float64 // Before fees
+}
+
+// request records a strategy's target, clamped to what the account may hold
+func (a *account) request(target float64) {
+	if math.IsNaN(target) {
+		return
+	}
+	target = math.Max(-1, math.Min(1, target))
+	if !a.cfg.AllowShort && target < 0 {
+		target = 0
+	}
+	a.target = target
+	a.pending = target != a.held
+}
+
+// fillPending fills a changed target at a price
+func (a *account) fillPending(t time.Time, price float64) {
+	if a.pending {
+		a.fill(t, price, a.target)
+	}
+}
+
+// fill trades the position to a target at a market price, paying slippage and fees
+func (a *account) fill(t time.Time, price, target float64) {
+	a.held, a.pending = target, false
+	if price <= 0 {
+		return
+	}
+
+	desired := 0.0
+	if equity := a.cash + a.qty*price; equity > 0 {
+		desired = target * a.cfg.PositionSize * equity / price
+	}
+	delta := desired - a.qty
+	if delta == 0 {
+		return
+	}
+
+	direction := 1.0
+	if delta < 0 {
+		direction = -1
+	}
+	fillPrice := price * (1 + direction*a.cfg.SlippageBps/10000)
+	size := math.Abs(delta)
+	fee := size * fillPrice * a.cfg.FeeRate
+	a.cash -= delta*fillPrice + fee
+	a.feesPaid += fee
+
+	// The part reducing the open position closes against the round trip, the rest opens or adds
+	closing := 0.0
+	if a.qty != 0 && (a.qty > 0) != (delta > 0) {
+		closing = math.Min(size, math.Abs(a.qty))
+	}
+	if closing > 0 {
+		rt := a.roundTrip
+		rt.exitQty += closing
+		rt.exitNotional += closing * fillPrice
+		rt.realized += closing * (fillPrice - rt.entryNotional/rt.entryQty) * rt.side
+		rt.trade.Fees += fee * closing / size
+		if closing == math.Abs(a.qty) {
+			a.closeRoundTrip(t)
+		}
+	}
+	if opening := size - closing; opening > 0 {
+		if a.roundTrip == nil {
+			a.roundTrip = &roundTrip{
+				trade: &models.BacktestTrade{Side: "LONG", EntryTime: t},
+				side:  direction,
+			}
+			if direction < 0 {
+				a.roundTrip.trade.Side = "SHORT"
+			}
+		}
+		rt := a.roundTrip
+		rt.entryQty += opening
+		rt.entryNotional += opening * fillPrice
+		rt.trade.Fees += fee * opening / size
+	}
+
+	a.qty = desired
+	if a.roundTrip != nil {
+		a.roundTrip.trade.Quantity = math.Max(a.roundTrip.trade.Quantity, math.Abs(a.qty))
+	}
+}
+
+// closeRoundTrip completes the open round trip
+func (a *account) closeRoundTrip(t time.Time) {
+	rt := a.roundTrip
+	trade := rt.trade
+	trade.EntryPrice = rt.entryNotional / rt.entryQty
+	trade.ExitTime = t
+	trade.ExitPrice = rt.exitNotional / rt.exitQty
+	trade.PnL = rt.realized - trade.Fees
+	trade.ReturnPct = trade.PnL / rt.entryNotional * 100
+	a.trades = append(a.trades, *trade)
+	a.roundTrip = nil
+}
+
+// mark values the account at a bar's close and tracks the drawdown
+func (a *account) mark(bar Bar) models.BacktestEquityPoint {
+	equity := a.cash + a.qty*bar.Close
+	drawdown := 0.0
+	if equity > a.peak {
+		a.peak = equity
+	} else if a.peak > 0 {
+		drawdown = (a.peak - equity) / a.peak * 100
+	}
+	a.maxDD = math.Max(a.maxDD, drawdown)
+
+	return models.BacktestEquityPoint{
+		Time:        bar.Time.UnixMilli(),
+		Equity:      equity,
+		DrawdownPct: drawdown,
+		Position:    a.qty,
+	}
+}
+
+// summary computes the headline statistics from the closed round trips
+func (a *account) summary() models.BacktestSummary {
+	summary := models.BacktestSummary{
+		InitialEquity:  a.cfg.InitialEquity,
+		FinalEquity:    a.cash,
+		MaxDrawdownPct: a.maxDD,
+		TradeCount:     len(a.trades),
+		FeesPaid:       a.feesPaid,
+	}
+	if a.cfg.InitialEquity > 0 {
+		summary.TotalReturnPct = (a.cash - a.cfg.InitialEquity) / a.cfg.InitialEquity * 100
+	}
+
+	var grossProfit, grossLoss, total float64
+	for _, trade := range a.trades {
+		total += trade.PnL
+		if trade.PnL > 0 {
+			summary.WinningTrades++
+			grossProfit += trade.PnL
+		} else {
+			grossLoss -= trade.PnL
+		}
+	}
+	if len(a.trades) > 0 {
+		summary.WinRatePct = float64(summary.WinningTrades) / float64(len(a.trades)) * 100
+		summary.AvgTradePnL = total / float64(len(a.trades))
+	}
+	if grossLoss > 0 {
+		summary.ProfitFactor = grossProfit / grossLoss
+	}
+	return summary
+}
@@ -0,0 +1,381 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Bar is a replayed candle
+type Bar struct {
+	Time      time.Time // Open time
+	CloseTime time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+	BuyVolume float64 // Taker buy volume
+}
+
+// Trade is a replayed trade
+type Trade struct {
+	Time     time.Time
+	Price    float64
+	Quantity float64
+	Buy      bool // Aggressive buyer
+}
+
+// Strategy decides a target position from replayed market data
+// Targets are signed fractions of the position size: 1 is fully long, -1 fully short and 0 flat
+// A strategy is called with every bar once it has closed, and the engine fills a changed target
+// at the next price, so a strategy can never trade on a price it has not seen
+type Strategy interface {
+	OnBar(bar Bar) float64
+}
+
+// TradeStrategy is a strategy that also decides on individual trades under the trades replay
+// OnTrade is called with every trade of a bar before OnBar is called with the bar
+type TradeStrategy interface {
+	Strategy
+	OnTrade(trade Trade) float64
+}
+
+// definition is a built-in strategy: its description and a constructor taking complete parameters
+type definition struct {
+	info  models.BacktestStrategy
+	build func(params map[string]float64) (Strategy, error)
+}
+
+// definitions are the built-in strategies by name
+var definitions = map[string]definition{
+	"sma_cross": {
+		info: models.BacktestStrategy{
+			Name:        "sma_cross",
+			Description: "Long while the fast simple moving average of closes is above the slow one, short while below",
+			Params: []models.BacktestStrategyParam{
+				{Name: "fast", Default: 10, Description: "Fast average period, in bars"},
+				{Name: "slow", Default: 30, Description: "Slow average period, in bars"},
+			},
+		},
+		build: newSMACross,
+	},
+	"breakout": {
+		info: models.BacktestStrategy{
+			Name:        "breakout",
+			Description: "Long on a close above the highest high of the lookback, short on a close below its lowest low",
+			Params: []models.BacktestStrategyParam{
+				{Name: "lookback", Default: 20, Description: "Channel length, in bars"},
+			},
+		},
+		build: newBreakout,
+	},
+	"rsi_reversion": {
+		info: models.BacktestStrategy{
+			Name:        "rsi_reversion",
+			Description: "Long when the RSI falls below lower, short when it rises above upper, flat when it crosses exit",
+			Params: []models.BacktestStrategyParam{
+				{Name: "period", Default: 14, Description: "RSI period, in bars"},
+				{Name: "lower", Default: 30, Description: "Oversold level opening a long"},
+				{Name: "upper", Default: 70, Description: "Overbought level opening a short"},
+				{Name: "exit", Default: 50, Description: "Level closing either position"},
+			},
+		},
+		build: newRSIReversion,
+	},
+	"taker_flow": {
+		info: models.BacktestStrategy{
+			Name:        "taker_flow",
+			Description: "Long while taker buy volume dominates the trailing window, short while taker sell volume does",
+			Params: []models.BacktestStrategyParam{
+				{Name: "window_seconds", Default: 60, Description: "Trailing window of trades, in seconds"},
+				{Name: "threshold", Default: 0.3, Description: "Imbalance (buy - sell) / (buy + sell) needed to take a side"},
+			},
+			UsesTrades: true,
+		},
+		build: newTakerFlow,
+	},
+}
+
+// maxPeriod caps the bar periods of the built-in strategies
+const maxPeriod = 1000
+
+// Strategies describes the built-in strategies, sorted by name
+func Strategies() []models.BacktestStrategy {
+	strategies := make([]models.BacktestStrategy, 0, len(definitions))
+	for _, def := range definitions {
+		strategies = append(strategies, def.info)
+	}
+	sort.Slice(strategies, func(i, j int) bool { return strategies[i].Name < strategies[j].Name })
+	return strategies
+}
+
+// NewStrategy builds a built-in strategy, filling parameters missing from params with their defaults
+// It returns the complete parameters used
+func NewStrategy(name string, params map[string]float64) (Strategy, map[string]float64, error) {
+	def, ok := definitions[name]
+	if !ok {
+		names := make([]string, 0, len(definitions))
+		for n := range definitions {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, nil, fmt.Errorf("unknown strategy %q, use one of %v", name, names)
+	}
+
+	complete := make(map[string]float64, len(def.info.Params))
+	for _, param := range def.info.Params {
+		complete[param.Name] = param.Default
+	}
+	for key, value := range params {
+		if _, known := complete[key]; !known {
+			return nil, nil, fmt.Errorf("unknown parameter %q for strategy %s", key, name)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, nil, fmt.Errorf("parameter %q must be a finite number", key)
+		}
+		complete[key] = value
+	}
+
+	strategy, err := def.build(complete)
+	if err != nil {
+		return nil, nil, fmt.Errorf("strategy %s: %w", name, err)
+	}
+	return strategy, complete, nil
+}
+
+// period reads a bar period parameter
+func period(params map[string]float64, name string) (int, error) {
+	value := params[name]
+	if value != math.Trunc(value) || value < 1 || value > maxPeriod {
+		return 0, fmt.Errorf("%s must be a whole number between 1 and %d", name, maxPeriod)
+	}
+	return int(value), nil
+}
+
+// smaCross follows the crossing of two simple moving averages of closes
+type smaCross struct {
+	fast, slow *average
+}
+
+func newSMACross(params map[string]float64) (Strategy, error) {
+	fast, err := period(params, "fast")
+	if err != nil {
+		return nil, err
+	}
+	slow, err := period(params, "slow")
+	if err != nil {
+		return nil, err
+	}
+	if fast >= slow {
+		return nil, fmt.Errorf("fast must be shorter than slow")
+	}
+	return &smaCross{fast: newAverage(fast), slow: newAverage(slow)}, nil
+}
+
+func (s *smaCross) OnBar(bar Bar) float64 {
+	s.fast.add(bar.Close)
+	s.slow.add(bar.Close)
+	if !s.slow.full() {
+		return 0
+	}
+	switch fast, slow := s.fast.value(), s.slow.value(); {
+	case fast > slow:
+		return 1
+	case fast < slow:
+		return -1
+	}
+	return 0
+}
+
+// breakout trades closes outside the channel of the previous bars' highs and lows
+type breakout struct {
+	lookback    int
+	highs, lows []float64 // The previous lookback bars
+	target      float64
+}
+
+func newBreakout(params map[string]float64) (Strategy, error) {
+	lookback, err := period(params, "lookback")
+	if err != nil {
+		return nil, err
+	}
+	return &breakout{lookback: lookback}, nil
+}
+
+func (s *breakout) OnBar(bar Bar) float64 {
+	if len(s.highs) == s.lookback {
+		high, low := s.highs[0], s.lows[0]
+		for i := 1; i < s.lookback; i++ {
+			high = math.Max(high, s.highs[i])
+			low = math.Min(low, s.lows[i])
+		}
+		switch {
+		case bar.Close > high:
+			s.target = 1
+		case bar.Close < low:
+			s.target = -1
+		}
+		s.highs, s.lows = s.highs[1:], s.lows[1:]
+	}
+	s.highs = append(s.highs, bar.High)
+	s.lows = append(s.lows, bar.Low)
+	return s.target
+}
+
+// rsiReversion fades RSI extremes until the RSI returns to the exit level
+type rsiReversion struct {
+	period             int
+	lower, upper, exit float64
+	prevClose          float64
+	bars               int
+	avgGain, avgLoss   float64 // Wilder smoothed, once seeded with the first period changes
+	target             float64
+}
+
+func newRSIReversion(params map[string]float64) (Strategy, error) {
+	p, err := period(params, "period")
+	if err != nil {
+		return nil, err
+	}
+	lower, upper, exit := params["lower"], params["upper"], params["exit"]
+	if lower <= 0 || upper >= 100 || lower > exit || exit > upper || lower == upper {
+		return nil, fmt.Errorf("levels must satisfy 0 < lower <= exit <= upper < 100 with lower below upper")
+	}
+	return &rsiReversion{period: p, lower: lower, upper: upper, exit: exit}, nil
+}
+
+func (s *rsiReversion) OnBar(bar Bar) float64 {
+	s.bars++
+	if s.bars == 1 {
+		s.prevClose = bar.Close
+		return 0
+	}
+	change := bar.Close - s.prevClose
+	s.prevClose = bar.Close
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+
+	n := float64(s.period)
+	changes := s.bars - 1
+	if changes <= s.period {
+		s.avgGain += gain / n
+		s.avgLoss += loss / n
+		if changes < s.period {
+			return 0
+		}
+	} else {
+		s.avgGain = (s.avgGain*(n-1) + gain) / n
+		s.avgLoss = (s.avgLoss*(n-1) + loss) / n
+	}
+
+	rsi := 100.0
+	if s.avgLoss > 0 {
+		rsi = 100 - 100/(1+s.avgGain/s.avgLoss)
+	}
+	switch {
+	case rsi < s.lower:
+		s.target = 1
+	case rsi > s.upper:
+		s.target = -1
+	case s.target > 0 && rsi >= s.exit, s.target < 0 && rsi <= s.exit:
+		s.target = 0
+	}
+	return s.target
+}
+
+// takerFlow follows the taker volume imbalance of a trailing window of trades
+type takerFlow struct {
+	window    time.Duration
+	threshold float64
+	trades    []Trade // Inside the window, oldest first
+	buy, sell float64
+	target    float64
+}
+
+func newTakerFlow(params map[string]float64) (Strategy, error) {
+	seconds := params["window_seconds"]
+	if seconds < 1 || seconds > 86400 {
+		return nil, fmt.Errorf("window_seconds must be between 1 and 86400")
+	}
+	threshold := params["threshold"]
+	if threshold <= 0 || threshold >= 1 {
+		return nil, fmt.Errorf("threshold must be between 0 and 1")
+	}
+	return &takerFlow{window: time.Duration(seconds * float64(time.Second)), threshold: threshold}, nil
+}
+
+func (s *takerFlow) OnTrade(trade Trade) float64 {
+	s.trades = append(s.trades, trade)
+	if trade.Buy {
+		s.buy += trade.Quantity
+	} else {
+		s.sell += trade.Quantity
+	}
+
+	cutoff := trade.Time.Add(-s.window)
+	evict := 0
+	for evict < len(s.trades) && s.trades[evict].Time.Before(cutoff) {
+		if s.trades[evict].Buy {
+			s.buy -= s.trades[evict].Quantity
+		} else {
+			s.sell -= s.trades[evict].Quantity
+		}
+		evict++
+	}
+	s.trades = s.trades[evict:]
+
+	if total := s.buy + s.sell; total > 0 {
+		switch imbalance := (s.buy - s.sell) / total; {
+		case imbalance > s.threshold:
+			s.target = 1
+		case imbalance < -s.threshold:
+			s.target = -1
+		default:
+			s.target = 0
+		}
+	}
+	return s.target
+}
+
+// OnBar keeps the position decided by the trades
+func (s *takerFlow) OnBar(bar Bar) float64 {
+	return s.target
+}
+
+// average is a simple moving average over the last size values
+type average struct {
+	values []float64 // Ring buffer
+	count  int
+	sum    float64
+}
+
+func newAverage(size int) *average {
+	return &average{values: make([]float64, size)}
+}
+
+func (a *average) add(value float64) {
+	slot := a.count % len(a.values)
+	if a.count >= len(a.values) {
+		a.sum -= a.values[slot]
+	}
+	a.values[slot] = value
+	a.sum += value
+	a.count++
+}
+
+func (a *average) full() bool {
+	return a.count >= len(a.values)
+}
+
+func (a *average) value() float64 {
+	n := a.count
+	if n > len(a.values) {
+		n = len(a.values)
+	}
+	if n == 0 {
+		return 0
+	}
+	return a.sum / float64(n)
+}
@@ -0,0 +1,43 @@
+package batchwriter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// IsTransient reports whether a failed write may succeed when repeated: lost or refused
+// connections, timeouts, an exhausted or restarting server, and serialization failures or
+// deadlocks. Constraint violations and other statement errors are not
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "08"), // Connection exception
+			strings.HasPrefix(pgErr.Code, "53"),  // Insufficient resources
+			strings.HasPrefix(pgErr.Code, "57P"), // Server shutting down or restarting
+			pgErr.Code == "40001",                // Serialization failure
+			pgErr.Code == "40P01":                // Deadlock detected
+			return true
+		}
+		return false
+	}
+
+	var retryable interface{ SafeToRetry() bool }
+	if errors.As(err, &retryable) && retryable.SafeToRetry() {
+		return true
+	}
+	var netErr net.Error
+	return pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,71 @@
+package batchwriter
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"tterminal-backend/models"
+)
+
+// stream is a writer of any item type
+type stream interface {
+	Name() string
+	Stats() models.WriterStats
+	Close(ctx context.Context) error
+}
+
+// Registry holds the writers of every persistence path, to report their metrics together and
+// flush them all on shutdown
+type Registry struct {
+	mu      sync.Mutex
+	writers []stream
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// register adds a writer
+func (r *Registry) register(w stream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writers = append(r.writers, w)
+}
+
+// Stats returns the counters of every writer, by name
+func (r *Registry) Stats() []models.WriterStats {
+	r.mu.Lock()
+	writers := append([]stream(nil), r.writers...)
+	r.mu.Unlock()
+
+	stats := make([]models.WriterStats, 0, len(writers))
+	for _, w := range writers {
+		stats = append(stats, w.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// Close closes every writer at once, writing what they hold, and waits until all are done or
+// ctx ends; the errors name the writers that could not finish
+func (r *Registry) Close(ctx context.Context) error {
+	r.mu.Lock()
+	writers := append([]stream(nil), r.writers...)
+	r.mu.Unlock()
+
+	errs := make([]error, len(writers))
+	var wg sync.WaitGroup
+	for i, w := range writers {
+		wg.Add(1)
+		go func(i int, w stream) {
+			defer wg.Done()
+			errs[i] = w.Close(ctx)
+		}(i, w)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
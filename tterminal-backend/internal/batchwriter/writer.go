@@ -0,0 +1,256 @@
+package batchwriter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Defaults of a writer's configuration
+const (
+	defaultBatchSize     = 1000
+	defaultFlushInterval = time.Second
+	defaultQueueSize     = 20000
+	defaultMaxAttempts   = 3
+	defaultRetryBackoff  = 200 * time.Millisecond
+	defaultWriteTimeout  = 10 * time.Second
+)
+
+// Config tunes a writer; zero fields take the defaults above
+type Config struct {
+	Name          string        // Stream name in logs and metrics, such as "trades:binance"
+	BatchSize     int           // Pending items that flush early
+	FlushInterval time.Duration // Longest an item waits before it is written
+	QueueSize     int           // Items held while the store falls behind
+	Block         bool          // Add waits for room in a full queue instead of dropping
+	MaxAttempts   int           // Attempts of a batch failing with transient errors
+	RetryBackoff  time.Duration // Delay before the first retry, doubled for each next one
+	WriteTimeout  time.Duration // Bound of one attempt
+}
+
+// WriteFunc persists one batch; it must be safe to repeat, as transient failures are retried
+type WriteFunc[T any] func(ctx context.Context, items []T) error
+
+// Writer batches items of one persistence stream into a store off the caller's path, flushing
+// by size and time, retrying transient database errors and flushing what is pending on Close
+type Writer[T any] struct {
+	cfg   Config
+	write WriteFunc[T]
+	queue chan T
+	stop  chan struct{}
+	done  chan struct{}
+
+	// closing is held exclusively by Close so no Add is mid-send once the queue is drained
+	closing sync.RWMutex
+	closed  bool
+
+	written   int64
+	failed    int64
+	dropped   int64
+	retries   int64
+	batches   int64
+	lastFlush atomic.Int64 // Unix milliseconds of the last successful write
+
+	errMu     sync.Mutex
+	lastError string
+}
+
+// New creates and starts a writer, registered for metrics and shutdown flushing when registry
+// is not nil
+func New[T any](registry *Registry, cfg Config, write WriteFunc[T]) *Writer[T] {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = defaultWriteTimeout
+	}
+
+	w := &Writer[T]{
+		cfg:   cfg,
+		write: write,
+		queue: make(chan T, cfg.QueueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	if registry != nil {
+		registry.register(w)
+	}
+	return w
+}
+
+// Name returns the writer's stream name
+func (w *Writer[T]) Name() string {
+	return w.cfg.Name
+}
+
+// Add queues items to be written with the next batch and returns how many were queued. Items
+// are dropped after Close and, unless the writer blocks, while the queue is full
+func (w *Writer[T]) Add(items ...T) int {
+	w.closing.RLock()
+	defer w.closing.RUnlock()
+
+	queued := 0
+	for _, item := range items {
+		if w.closed {
+			break
+		}
+		if w.cfg.Block {
+			w.queue <- item
+			queued++
+			continue
+		}
+		select {
+		case w.queue <- item:
+			queued++
+		default:
+		}
+	}
+
+	// Drops are logged on the first and every thousandth
+	if dropped := int64(len(items) - queued); dropped > 0 {
+		total := atomic.AddInt64(&w.dropped, dropped)
+		if before := total - dropped; before == 0 || before/1000 != total/1000 {
+			log.Printf("[BatchWriter] %s queue full or closed - %d items dropped so far", w.cfg.Name, total)
+		}
+	}
+	return queued
+}
+
+// Write persists items now, with the writer's retries, counting them in its metrics
+// It is for callers that need the outcome, such as collection runs reporting their result
+func (w *Writer[T]) Write(ctx context.Context, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return w.flush(ctx, items)
+}
+
+// Close stops accepting items and writes everything pending, waiting until done or ctx ends
+func (w *Writer[T]) Close(ctx context.Context) error {
+	w.closing.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.stop)
+	}
+	w.closing.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %d items still pending at shutdown: %w", w.cfg.Name, len(w.queue), ctx.Err())
+	}
+}
+
+// Stats returns the writer's counters
+func (w *Writer[T]) Stats() models.WriterStats {
+	stats := models.WriterStats{
+		Name:    w.cfg.Name,
+		Written: atomic.LoadInt64(&w.written),
+		Failed:  atomic.LoadInt64(&w.failed),
+		Dropped: atomic.LoadInt64(&w.dropped),
+		Retries: atomic.LoadInt64(&w.retries),
+		Batches: atomic.LoadInt64(&w.batches),
+		Pending: len(w.queue),
+	}
+	if ms := w.lastFlush.Load(); ms > 0 {
+		lastFlush := time.UnixMilli(ms).UTC()
+		stats.LastWriteAt = &lastFlush
+	}
+	w.errMu.Lock()
+	stats.LastError = w.lastError
+	w.errMu.Unlock()
+	return stats
+}
+
+// run collects queued items into batches until stopped, then drains the queue
+func (w *Writer[T]) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, w.cfg.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			w.flush(context.Background(), batch)
+			batch = make([]T, 0, w.cfg.BatchSize)
+		}
+	}
+
+	for {
+		select {
+		case item := <-w.queue:
+			batch = append(batch, item)
+			if len(batch) >= w.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-w.stop:
+			for {
+				select {
+				case item := <-w.queue:
+					batch = append(batch, item)
+					if len(batch) >= w.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes a batch, retrying transient errors with a doubling backoff
+func (w *Writer[T]) flush(ctx context.Context, batch []T) error {
+	backoff := w.cfg.RetryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, w.cfg.WriteTimeout)
+		err = w.write(attemptCtx, batch)
+		cancel()
+		if err == nil {
+			atomic.AddInt64(&w.written, int64(len(batch)))
+			atomic.AddInt64(&w.batches, 1)
+			w.lastFlush.Store(time.Now().UnixMilli())
+			return nil
+		}
+		if attempt >= w.cfg.MaxAttempts || !IsTransient(err) || ctx.Err() != nil {
+			break
+		}
+
+		atomic.AddInt64(&w.retries, 1)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+		backoff *= 2
+	}
+
+	atomic.AddInt64(&w.failed, int64(len(batch)))
+	w.errMu.Lock()
+	w.lastError = err.Error()
+	w.errMu.Unlock()
+	log.Printf("[BatchWriter] Failed to write %d %s items: %v", len(batch), w.cfg.Name, err)
+	return err
+}
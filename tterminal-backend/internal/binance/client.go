@@ -12,15 +12,24 @@ import (
 	"sync"
 	"time"
 	"tterminal-backend/config"
+	"tterminal-backend/internal/synthetic"
 	"tterminal-backend/models"
 )
 
+// klinesPath is the futures klines endpoint
+const klinesPath = "/fapi/v1/klines"
+
 // Client represents an ultra-high-performance Binance API client
 type Client struct {
-	baseURL     string
-	httpClient  *http.Client
-	cfg         *config.Config
-	rateLimiter *RateLimiter
+	baseURL        string
+	coinMBaseURL   string // COIN-margined futures (dapi), used for symbols like BTCUSD_PERP
+	optionsBaseURL string // European options (eapi)
+	tradingBaseURL string // Signed account and order endpoints, e.g. the futures testnet
+	httpClient     *http.Client
+	cfg            *config.Config
+	rateLimiter    *RateLimiter
+	// Upstream reachability, drives degraded mode
+	upstream *upstreamTracker
 	// Connection pool for maximum performance
 	requestPool sync.Pool
 	// Compression support
@@ -51,13 +60,24 @@ func NewClient(cfg *config.Config) *Client {
 		ResponseHeaderTimeout: 5 * time.Second,
 	}
 
+	// Serve generated market data instead of calling Binance in offline mode
+	var roundTripper http.RoundTripper = transport
+	if cfg.SyntheticData {
+		roundTripper = synthetic.NewTransport(synthetic.NewGenerator(int64(cfg.SyntheticSeed)))
+	}
+	upstream := newUpstreamTracker(roundTripper)
+
 	client := &Client{
-		baseURL: cfg.BinanceBaseURL,
+		baseURL:        cfg.BinanceBaseURL,
+		coinMBaseURL:   cfg.BinanceCoinMBaseURL,
+		optionsBaseURL: cfg.BinanceOptionsBaseURL,
+		tradingBaseURL: cfg.BinanceTradingBaseURL,
 		httpClient: &http.Client{
 			Timeout:   10 * time.Second, // Reasonable timeout
-			Transport: transport,
+			Transport: upstream,
 		},
-		cfg: cfg,
+		cfg:      cfg,
+		upstream: upstream,
 		rateLimiter: &RateLimiter{
 			maxRequests: 1200, // Binance limit
 			window:      time.Minute,
@@ -154,23 +174,42 @@ func (c *Client) GetKlinesParallel(symbols []string, intervals []string, limit i
 
 // GetKlinesOptimized is an ultra-fast version of GetKlines with optimizations
 func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
-	startTime := time.Now()
-	defer c.updateMetrics(time.Since(startTime))
-
-	// Check rate limit
-	if !c.rateLimiter.canMakeRequest() {
-		return nil, fmt.Errorf("rate limit exceeded")
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("interval", interval)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
 	}
 
-	// Build optimized URL
+	return c.fetchKlines(ctx, symbol, interval, params)
+}
+
+// GetKlinesEndingAt fetches the limit klines opening at or before endTime, for backwards paging
+func (c *Client) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("interval", interval)
+	params.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
 	if limit > 0 {
 		params.Set("limit", strconv.Itoa(limit))
 	}
 
-	url := fmt.Sprintf("%s/fapi/v1/klines?%s", c.baseURL, params.Encode())
+	return c.fetchKlines(ctx, symbol, interval, params)
+}
+
+// fetchKlines requests last price klines with the given query parameters and converts them to candles
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, params url.Values) ([]models.Candle, error) {
+	startTime := time.Now()
+	defer func() { c.updateMetrics(time.Since(startTime)) }()
+
+	// Check rate limit
+	path := futuresPath(symbol, klinesPath)
+	if !c.rateLimiter.canMakeRequest() {
+		return nil, newRateLimitError(ctx, path)
+	}
+
+	// Build optimized URL
+	url := fmt.Sprintf("%s%s?%s", c.baseURLFor(path), path, params.Encode())
 
 	// Create optimized request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -187,13 +226,13 @@ func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, newNetworkError(ctx, path, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newStatusError(ctx, path, resp, body)
 	}
 
 	// Handle compressed response
@@ -238,11 +277,12 @@ func (c *Client) GetKlines(symbol, interval string, limit int, startTime, endTim
 // GetKlinesWithTimeRange fetches klines within a specific time range for gap backfilling
 func (c *Client) GetKlinesWithTimeRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.Candle, error) {
 	requestStart := time.Now()
-	defer c.updateMetrics(time.Since(requestStart))
+	defer func() { c.updateMetrics(time.Since(requestStart)) }()
 
 	// Check rate limit
+	path := futuresPath(symbol, klinesPath)
 	if !c.rateLimiter.canMakeRequest() {
-		return nil, fmt.Errorf("rate limit exceeded")
+		return nil, newRateLimitError(ctx, path)
 	}
 
 	// Build URL with time range parameters
@@ -253,7 +293,7 @@ func (c *Client) GetKlinesWithTimeRange(ctx context.Context, symbol, interval st
 	params.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
 	params.Set("limit", "1000") // Maximum allowed by Binance
 
-	url := fmt.Sprintf("%s/fapi/v1/klines?%s", c.baseURL, params.Encode())
+	url := fmt.Sprintf("%s%s?%s", c.baseURLFor(path), path, params.Encode())
 
 	// Create optimized request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -270,13 +310,13 @@ func (c *Client) GetKlinesWithTimeRange(ctx context.Context, symbol, interval st
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, newNetworkError(ctx, path, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newStatusError(ctx, path, resp, body)
 	}
 
 	// Handle compressed response
@@ -395,17 +435,18 @@ func (c *Client) toString(v interface{}) string {
 
 // GetExchangeInfo fetches exchange information from Binance
 func (c *Client) GetExchangeInfo() (*BinanceExchangeInfo, error) {
-	url := fmt.Sprintf("%s/fapi/v1/exchangeInfo", c.baseURL)
+	const path = "/fapi/v1/exchangeInfo"
+	ctx := context.Background()
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Get(c.baseURL + path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, newNetworkError(ctx, path, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newStatusError(ctx, path, resp, body)
 	}
 
 	var exchangeInfo BinanceExchangeInfo
@@ -429,18 +470,23 @@ type BinanceSymbolInfo struct {
 	Status            string                `json:"status"`
 	PricePrecision    int                   `json:"pricePrecision"`
 	QuantityPrecision int                   `json:"quantityPrecision"`
+	ContractSize      float64               `json:"contractSize,omitempty"` // Coin-margined contracts only
 	Filters           []BinanceSymbolFilter `json:"filters"`
 }
 
 // BinanceSymbolFilter represents a filter for a symbol
 type BinanceSymbolFilter struct {
-	FilterType string `json:"filterType"`
-	MinPrice   string `json:"minPrice,omitempty"`
-	MaxPrice   string `json:"maxPrice,omitempty"`
-	TickSize   string `json:"tickSize,omitempty"`
-	MinQty     string `json:"minQty,omitempty"`
-	MaxQty     string `json:"maxQty,omitempty"`
-	StepSize   string `json:"stepSize,omitempty"`
+	FilterType     string `json:"filterType"`
+	MinPrice       string `json:"minPrice,omitempty"`
+	MaxPrice       string `json:"maxPrice,omitempty"`
+	TickSize       string `json:"tickSize,omitempty"`
+	MinQty         string `json:"minQty,omitempty"`
+	MaxQty         string `json:"maxQty,omitempty"`
+	StepSize       string `json:"stepSize,omitempty"`
+	Notional       string `json:"notional,omitempty"`       // Futures MIN_NOTIONAL
+	MinNotional    string `json:"minNotional,omitempty"`    // Spot MIN_NOTIONAL and NOTIONAL
+	MultiplierUp   string `json:"multiplierUp,omitempty"`   // PERCENT_PRICE upper bound
+	MultiplierDown string `json:"multiplierDown,omitempty"` // PERCENT_PRICE lower bound
 }
 
 // Rate limiter implementation
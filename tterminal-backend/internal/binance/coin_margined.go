@@ -0,0 +1,44 @@
+package binance
+
+import "strings"
+
+// COIN-margined futures are served by the dapi host under the same paths as USDⓈ-M futures,
+// with /dapi/ in place of /fapi/
+const (
+	usdMarginedPrefix  = "/fapi/"
+	coinMarginedPrefix = "/dapi/"
+)
+
+// IsCoinMargined reports whether a symbol is a COIN-margined futures contract: a USD pair with
+// a contract suffix, such as the BTCUSD_PERP perpetual or the BTCUSD_250926 quarterly
+// USDⓈ-M quarterlies (BTCUSDT_250926) are not COIN-margined
+func IsCoinMargined(symbol string) bool {
+	pair, contract, found := strings.Cut(symbol, "_")
+	return found && contract != "" && strings.HasSuffix(pair, "USD")
+}
+
+// coinMarginedPair returns the underlying pair of a COIN-margined contract ("BTCUSD_PERP" is "BTCUSD")
+func coinMarginedPair(symbol string) string {
+	pair, _, _ := strings.Cut(symbol, "_")
+	return pair
+}
+
+// futuresPath returns the endpoint path serving a symbol, mapping USDⓈ-M paths to their
+// COIN-margined equivalent for COIN-margined contracts
+func futuresPath(symbol, path string) string {
+	if IsCoinMargined(symbol) && strings.HasPrefix(path, usdMarginedPrefix) {
+		return coinMarginedPrefix + strings.TrimPrefix(path, usdMarginedPrefix)
+	}
+	return path
+}
+
+// baseURLFor returns the host serving an endpoint path
+func (c *Client) baseURLFor(path string) string {
+	switch {
+	case strings.HasPrefix(path, coinMarginedPrefix):
+		return c.coinMBaseURL
+	case strings.HasPrefix(path, optionsPrefix):
+		return c.optionsBaseURL
+	}
+	return c.baseURL
+}
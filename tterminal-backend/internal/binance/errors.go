@@ -0,0 +1,193 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorKind classifies a failed Binance REST request
+type ErrorKind string
+
+// Error kinds, each mapped to the status code API consumers receive
+const (
+	ErrRateLimited   ErrorKind = "rate_limited"   // 429: Binance or the local limiter throttled the request
+	ErrInvalidSymbol ErrorKind = "invalid_symbol" // 404: symbol unknown to Binance
+	ErrMaintenance   ErrorKind = "maintenance"    // 503: Binance reports maintenance or overload
+	ErrNetwork       ErrorKind = "network"        // 503: Binance unreachable (or degraded mode)
+	ErrBadRequest    ErrorKind = "bad_request"    // 400: Binance rejected the parameters
+	ErrUpstream      ErrorKind = "upstream"       // 502: any other upstream failure
+)
+
+// Binance error codes used for classification
+const (
+	binanceCodeTooManyRequests = -1003
+	binanceCodeServerBusy      = -1008
+	binanceCodeInvalidSymbol   = -1121
+	binanceCodeSymbolStatus    = -1122
+)
+
+// APIError is a classified Binance REST failure, carried up to controllers
+type APIError struct {
+	Kind       ErrorKind
+	Path       string        // Binance endpoint path
+	Status     int           // Upstream HTTP status (0 for network failures)
+	Code       int           // Binance error code, when the body carried one
+	Message    string        // Binance error message or network error
+	RetryAfter time.Duration // Wait suggested by Binance for rate limits
+	RequestID  string        // ID of the API request that triggered the call
+	Err        error         // Underlying network error
+}
+
+// Error describes the failure, including the request ID for correlation
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("binance %s %s", e.Path, e.Kind)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d", e.Status)
+		if e.Code != 0 {
+			msg += fmt.Sprintf(", code %d", e.Code)
+		}
+		msg += ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " [request_id=" + e.RequestID + "]"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying network error, if any
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus returns the status code to answer API consumers with
+func (e *APIError) HTTPStatus() int {
+	switch e.Kind {
+	case ErrRateLimited:
+		return http.StatusTooManyRequests
+	case ErrInvalidSymbol:
+		return http.StatusNotFound
+	case ErrMaintenance, ErrNetwork:
+		return http.StatusServiceUnavailable
+	case ErrBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// ErrorCode returns the machine-readable code for API error responses
+func (e *APIError) ErrorCode() string {
+	switch e.Kind {
+	case ErrRateLimited:
+		return "UPSTREAM_RATE_LIMITED"
+	case ErrInvalidSymbol:
+		return "INVALID_SYMBOL"
+	case ErrMaintenance:
+		return "UPSTREAM_MAINTENANCE"
+	case ErrNetwork:
+		return "UPSTREAM_UNAVAILABLE"
+	case ErrBadRequest:
+		return "UPSTREAM_BAD_REQUEST"
+	default:
+		return "UPSTREAM_ERROR"
+	}
+}
+
+// AsAPIError returns the classified Binance error wrapped in err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// newRateLimitError reports a request refused by the client's own rate limiter
+func newRateLimitError(ctx context.Context, path string) *APIError {
+	return &APIError{
+		Kind:      ErrRateLimited,
+		Path:      path,
+		Message:   "local request budget exhausted",
+		RequestID: RequestIDFromContext(ctx),
+	}
+}
+
+// newNetworkError reports a request that got no response
+func newNetworkError(ctx context.Context, path string, err error) *APIError {
+	return &APIError{
+		Kind:      ErrNetwork,
+		Path:      path,
+		Message:   err.Error(),
+		RequestID: RequestIDFromContext(ctx),
+		Err:       err,
+	}
+}
+
+// newStatusError classifies a non-200 response from its status, Binance error code and message
+func newStatusError(ctx context.Context, path string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Kind:      ErrUpstream,
+		Path:      path,
+		Status:    resp.StatusCode,
+		Message:   strings.TrimSpace(string(body)),
+		RequestID: RequestIDFromContext(ctx),
+	}
+
+	var payload struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Code != 0 {
+		apiErr.Code = payload.Code
+		apiErr.Message = payload.Msg
+	}
+
+	apiErr.classify()
+	if apiErr.Kind == ErrRateLimited {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return apiErr
+}
+
+// classify sets the error kind from the status, Binance error code and message
+// REST responses and WS-API replies carry the same statuses and codes
+func (e *APIError) classify() {
+	switch {
+	case e.Status == http.StatusTooManyRequests || e.Status == http.StatusTeapot || e.Code == binanceCodeTooManyRequests:
+		// 418 means the IP was banned for ignoring 429s
+		e.Kind = ErrRateLimited
+	case e.Code == binanceCodeInvalidSymbol || e.Code == binanceCodeSymbolStatus:
+		e.Kind = ErrInvalidSymbol
+	case e.Status == http.StatusServiceUnavailable || e.Code == binanceCodeServerBusy ||
+		strings.Contains(strings.ToLower(e.Message), "maintenance"):
+		e.Kind = ErrMaintenance
+	case e.Status >= http.StatusBadRequest && e.Status < http.StatusInternalServerError:
+		e.Kind = ErrBadRequest
+	}
+}
+
+// requestIDKey is the context key carrying the API request ID into Binance calls
+type requestIDKey struct{}
+
+// WithRequestID attaches an API request ID to a context for error correlation
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the API request ID attached to a context, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
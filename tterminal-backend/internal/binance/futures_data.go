@@ -0,0 +1,213 @@
+package binance
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// OpenInterest represents the current open interest of a futures symbol
+type OpenInterest struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+// OpenInterestStat represents a historical open interest data point
+type OpenInterestStat struct {
+	Symbol               string `json:"symbol"`
+	SumOpenInterest      string `json:"sumOpenInterest"`
+	SumOpenInterestValue string `json:"sumOpenInterestValue"`
+	Timestamp            int64  `json:"timestamp"`
+}
+
+// LongShortRatio represents the global long/short account ratio of a futures symbol
+type LongShortRatio struct {
+	Symbol         string `json:"symbol"`
+	LongShortRatio string `json:"longShortRatio"`
+	LongAccount    string `json:"longAccount"`
+	ShortAccount   string `json:"shortAccount"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+// FundingRate represents a settled funding rate of a futures symbol
+type FundingRate struct {
+	Symbol      string `json:"symbol"`
+	FundingRate string `json:"fundingRate"`
+	FundingTime int64  `json:"fundingTime"`
+	MarkPrice   string `json:"markPrice"`
+}
+
+// GetOpenInterest fetches the current open interest for a symbol
+func (c *Client) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterest, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	var oi OpenInterest
+	if err := c.getJSON(ctx, futuresPath(symbol, "/fapi/v1/openInterest"), params, &oi); err != nil {
+		return nil, err
+	}
+
+	return &oi, nil
+}
+
+// GetDepthSnapshot fetches the top limit levels of a symbol's order book (default and max 1000)
+func (c *Client) GetDepthSnapshot(ctx context.Context, symbol string, limit int) (*OrderBookSnapshot, error) {
+	if limit <= 0 || limit > maxDepthLimit {
+		limit = maxDepthLimit
+	}
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var snapshot OrderBookSnapshot
+	if err := c.getJSON(ctx, futuresPath(symbol, "/fapi/v1/depth"), params, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// GetOpenInterestHist fetches historical open interest for a symbol, oldest first
+// period is one of Binance's statistics periods ("5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d");
+// Binance keeps the last 30 days. Zero start or end times are omitted so the most recent entries are returned
+func (c *Client) GetOpenInterestHist(ctx context.Context, symbol, period string, startTime, endTime time.Time, limit int) ([]OpenInterestStat, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("period", period)
+	if !startTime.IsZero() {
+		params.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	}
+	if !endTime.IsZero() {
+		params.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var stats []OpenInterestStat
+	if err := c.getJSON(ctx, "/futures/data/openInterestHist", params, &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetLongShortRatio fetches the global long/short account ratio for a symbol
+func (c *Client) GetLongShortRatio(ctx context.Context, symbol, period string, limit int) ([]LongShortRatio, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("period", period)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var ratios []LongShortRatio
+	if err := c.getJSON(ctx, "/futures/data/globalLongShortAccountRatio", params, &ratios); err != nil {
+		return nil, err
+	}
+
+	return ratios, nil
+}
+
+// GetFundingRateHistory fetches settled funding rates for a symbol, oldest first
+// Zero start or end times are omitted so Binance returns the most recent entries
+func (c *Client) GetFundingRateHistory(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]FundingRate, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	if !startTime.IsZero() {
+		params.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	}
+	if !endTime.IsZero() {
+		params.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var rates []FundingRate
+	if err := c.getJSON(ctx, futuresPath(symbol, "/fapi/v1/fundingRate"), params, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// GetServerTime fetches the exchange's current time
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	var serverTime struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := c.getJSON(ctx, "/fapi/v1/time", url.Values{}, &serverTime); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.UnixMilli(serverTime.ServerTime), nil
+}
+
+// getJSON performs a rate-limited GET request against the futures API and decodes the JSON body
+// /dapi/ paths are sent to the COIN-margined host and /eapi/ paths to the options host
+func (c *Client) getJSON(ctx context.Context, path string, params url.Values, dest interface{}) error {
+	return c.requestJSON(ctx, http.MethodGet, c.baseURLFor(path), path, params.Encode(), nil, dest)
+}
+
+// requestJSON performs a rate-limited request with an encoded query and extra headers and decodes the JSON body
+// Signed POST, PUT and DELETE requests carry their parameters in the query, as Binance accepts
+func (c *Client) requestJSON(ctx context.Context, method, baseURL, path, query string, header http.Header, dest interface{}) error {
+	requestStart := time.Now()
+	defer func() { c.updateMetrics(time.Since(requestStart)) }()
+
+	// Check rate limit
+	if !c.rateLimiter.canMakeRequest() {
+		return newRateLimitError(ctx, path)
+	}
+
+	url := fmt.Sprintf("%s%s?%s", baseURL, path, query)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.useCompression {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return newNetworkError(ctx, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newStatusError(ctx, path, resp, body)
+	}
+
+	// Handle compressed response
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	if err := json.NewDecoder(reader).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package binance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"tterminal-backend/models"
+)
+
+// ErrNoCredentials is returned by signed endpoints when BINANCE_API_KEY or BINANCE_SECRET_KEY is unset
+var ErrNoCredentials = errors.New("binance API credentials are not configured")
+
+// symbolBrackets is one symbol's entry in the leverage bracket response
+type symbolBrackets struct {
+	Symbol   string                   `json:"symbol"`
+	Brackets []models.LeverageBracket `json:"brackets"`
+}
+
+// HasCredentials reports whether signed (USER_DATA) endpoints can be called
+func (c *Client) HasCredentials() bool {
+	return c.cfg.BinanceAPIKey != "" && c.cfg.BinanceSecretKey != ""
+}
+
+// GetLeverageBrackets fetches the notional brackets of every symbol, keyed by symbol
+// The endpoint is signed, so it needs BINANCE_API_KEY and BINANCE_SECRET_KEY
+func (c *Client) GetLeverageBrackets(ctx context.Context) (map[string][]models.LeverageBracket, error) {
+	if !c.HasCredentials() {
+		return nil, ErrNoCredentials
+	}
+
+	var response []symbolBrackets
+	if err := c.signedJSON(ctx, http.MethodGet, "/fapi/v1/leverageBracket", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+
+	brackets := make(map[string][]models.LeverageBracket, len(response))
+	for _, entry := range response {
+		brackets[entry.Symbol] = entry.Brackets
+	}
+	return brackets, nil
+}
+
+// signedJSON performs a request against a signed endpoint: the query is stamped and signed
+// with HMAC-SHA256 of the secret key, and the API key is sent in X-MBX-APIKEY
+// Signed requests go to the trading host, which the credentials belong to
+func (c *Client) signedJSON(ctx context.Context, method, path string, params url.Values, dest interface{}) error {
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+	query := params.Encode()
+	mac := hmac.New(sha256.New, []byte(c.cfg.BinanceSecretKey))
+	mac.Write([]byte(query))
+
+	header := http.Header{}
+	header.Set("X-MBX-APIKEY", c.cfg.BinanceAPIKey)
+	return c.requestJSON(ctx, method, c.tradingBaseURL, path, query+"&signature="+hex.EncodeToString(mac.Sum(nil)), header, dest)
+}
@@ -0,0 +1,138 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// optionsPrefix marks European options endpoints, served by the eapi host
+const optionsPrefix = "/eapi/"
+
+// optionsExpiryHour is the UTC hour Binance options expire and settle
+const optionsExpiryHour = 8
+
+// OptionTicker represents the 24h ticker of an option contract
+type OptionTicker struct {
+	Symbol      string `json:"symbol"`
+	LastPrice   string `json:"lastPrice"`
+	PriceChange string `json:"priceChange"`
+	Volume      string `json:"volume"` // Contracts traded over 24h
+	Amount      string `json:"amount"` // Quote volume over 24h
+	BidPrice    string `json:"bidPrice"`
+	AskPrice    string `json:"askPrice"`
+	TradeCount  int64  `json:"tradeCount"`
+	StrikePrice string `json:"strikePrice"`
+	CloseTime   int64  `json:"closeTime"`
+}
+
+// OptionMark represents the mark price, implied volatilities and greeks of an option contract
+type OptionMark struct {
+	Symbol    string `json:"symbol"`
+	MarkPrice string `json:"markPrice"`
+	BidIV     string `json:"bidIV"`
+	AskIV     string `json:"askIV"`
+	MarkIV    string `json:"markIV"`
+	Delta     string `json:"delta"`
+	Gamma     string `json:"gamma"`
+	Theta     string `json:"theta"`
+	Vega      string `json:"vega"`
+}
+
+// OptionOpenInterest represents the open interest of an option contract
+type OptionOpenInterest struct {
+	Symbol             string `json:"symbol"`
+	SumOpenInterest    string `json:"sumOpenInterest"`
+	SumOpenInterestUSD string `json:"sumOpenInterestUsd"`
+}
+
+// OptionIndex represents the spot index price of an options underlying
+type OptionIndex struct {
+	Time       int64  `json:"time"`
+	IndexPrice string `json:"indexPrice"`
+}
+
+// OptionContract describes an option contract parsed from its symbol ("BTC-250627-100000-C")
+type OptionContract struct {
+	Underlying  string // Underlying asset ("BTC")
+	ExpiryLabel string // Expiry date as YYMMDD ("250627")
+	Expiry      time.Time
+	Strike      float64
+	Call        bool
+}
+
+// ParseOptionSymbol parses an option symbol of the form <asset>-<YYMMDD>-<strike>-<C|P>
+func ParseOptionSymbol(symbol string) (OptionContract, error) {
+	parts := strings.Split(symbol, "-")
+	if len(parts) != 4 || (parts[3] != "C" && parts[3] != "P") {
+		return OptionContract{}, fmt.Errorf("invalid option symbol %q", symbol)
+	}
+
+	date, err := time.Parse("060102", parts[1])
+	if err != nil {
+		return OptionContract{}, fmt.Errorf("invalid expiry in option symbol %q: %w", symbol, err)
+	}
+	strike, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || strike <= 0 {
+		return OptionContract{}, fmt.Errorf("invalid strike in option symbol %q", symbol)
+	}
+
+	return OptionContract{
+		Underlying:  parts[0],
+		ExpiryLabel: parts[1],
+		Expiry:      date.Add(optionsExpiryHour * time.Hour),
+		Strike:      strike,
+		Call:        parts[3] == "C",
+	}, nil
+}
+
+// GetOptionTickers fetches the 24h tickers of every listed option contract
+func (c *Client) GetOptionTickers(ctx context.Context) ([]OptionTicker, error) {
+	var tickers []OptionTicker
+	if err := c.getJSON(ctx, "/eapi/v1/ticker", url.Values{}, &tickers); err != nil {
+		return nil, err
+	}
+
+	return tickers, nil
+}
+
+// GetOptionMarks fetches the mark prices, implied volatilities and greeks of every listed option contract
+func (c *Client) GetOptionMarks(ctx context.Context) ([]OptionMark, error) {
+	var marks []OptionMark
+	if err := c.getJSON(ctx, "/eapi/v1/mark", url.Values{}, &marks); err != nil {
+		return nil, err
+	}
+
+	return marks, nil
+}
+
+// GetOptionOpenInterest fetches the open interest of an underlying's contracts expiring on one date
+// expiration is the YYMMDD expiry date ("250627")
+func (c *Client) GetOptionOpenInterest(ctx context.Context, underlying, expiration string) ([]OptionOpenInterest, error) {
+	params := url.Values{}
+	params.Set("underlyingAsset", underlying)
+	params.Set("expiration", expiration)
+
+	var openInterest []OptionOpenInterest
+	if err := c.getJSON(ctx, "/eapi/v1/openInterest", params, &openInterest); err != nil {
+		return nil, err
+	}
+
+	return openInterest, nil
+}
+
+// GetOptionIndex fetches the spot index price of an options underlying pair ("BTCUSDT")
+func (c *Client) GetOptionIndex(ctx context.Context, underlying string) (*OptionIndex, error) {
+	params := url.Values{}
+	params.Set("underlying", underlying)
+
+	var index OptionIndex
+	if err := c.getJSON(ctx, "/eapi/v1/index", params, &index); err != nil {
+		return nil, err
+	}
+
+	return &index, nil
+}
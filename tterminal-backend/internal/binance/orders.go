@@ -0,0 +1,132 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// futuresOrderPath is the USDⓈ-M futures order endpoint: POST places, PUT amends, DELETE cancels
+// and GET queries an order
+const futuresOrderPath = "/fapi/v1/order"
+
+// orderRecvWindow bounds how long after its timestamp Binance accepts an order request, in milliseconds
+const orderRecvWindow = "5000"
+
+// binanceCodeOrderNotFound is returned for orders unknown to Binance
+const binanceCodeOrderNotFound = -2013
+
+// FuturesOrder is an order as reported by the futures order endpoint
+type FuturesOrder struct {
+	OrderID       int64  `json:"orderId"`
+	ClientOrderID string `json:"clientOrderId"`
+	Symbol        string `json:"symbol"`
+	Status        string `json:"status"`
+	Side          string `json:"side"`
+	Type          string `json:"type"`
+	TimeInForce   string `json:"timeInForce"`
+	Price         string `json:"price"`
+	StopPrice     string `json:"stopPrice"`
+	AvgPrice      string `json:"avgPrice"`
+	OrigQty       string `json:"origQty"`
+	ExecutedQty   string `json:"executedQty"`
+	ReduceOnly    bool   `json:"reduceOnly"`
+	UpdateTime    int64  `json:"updateTime"`
+}
+
+// OrderParams describes a new futures order; empty fields are not sent
+type OrderParams struct {
+	Symbol        string
+	Side          string // BUY or SELL
+	Type          string // LIMIT, MARKET, STOP, STOP_MARKET, TAKE_PROFIT or TAKE_PROFIT_MARKET
+	TimeInForce   string // GTC, IOC, FOK or GTX, for orders with a limit price
+	Quantity      string
+	Price         string
+	StopPrice     string
+	ReduceOnly    bool
+	ClientOrderID string // Identifies the order in later requests and makes placement safe to retry
+}
+
+// PlaceOrder places a futures order
+// The endpoint is signed, so it needs BINANCE_API_KEY and BINANCE_SECRET_KEY
+func (c *Client) PlaceOrder(ctx context.Context, order OrderParams) (*FuturesOrder, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("side", order.Side)
+	params.Set("type", order.Type)
+	params.Set("quantity", order.Quantity)
+	params.Set("newClientOrderId", order.ClientOrderID)
+	params.Set("newOrderRespType", "RESULT")
+	if order.TimeInForce != "" {
+		params.Set("timeInForce", order.TimeInForce)
+	}
+	if order.Price != "" {
+		params.Set("price", order.Price)
+	}
+	if order.StopPrice != "" {
+		params.Set("stopPrice", order.StopPrice)
+	}
+	if order.ReduceOnly {
+		params.Set("reduceOnly", "true")
+	}
+	return c.orderRequest(ctx, http.MethodPost, params)
+}
+
+// AmendOrder changes the quantity and price of an open limit order; Binance requires both, and the side
+func (c *Client) AmendOrder(ctx context.Context, symbol, clientOrderID, side, quantity, price string) (*FuturesOrder, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("origClientOrderId", clientOrderID)
+	params.Set("side", side)
+	params.Set("quantity", quantity)
+	params.Set("price", price)
+	return c.orderRequest(ctx, http.MethodPut, params)
+}
+
+// CancelOrder cancels an open order
+func (c *Client) CancelOrder(ctx context.Context, symbol, clientOrderID string) (*FuturesOrder, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("origClientOrderId", clientOrderID)
+	return c.orderRequest(ctx, http.MethodDelete, params)
+}
+
+// GetOrder queries an order's current state
+func (c *Client) GetOrder(ctx context.Context, symbol, clientOrderID string) (*FuturesOrder, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("origClientOrderId", clientOrderID)
+	return c.orderRequest(ctx, http.MethodGet, params)
+}
+
+// orderRequest sends a signed request to the order endpoint
+func (c *Client) orderRequest(ctx context.Context, method string, params url.Values) (*FuturesOrder, error) {
+	if !c.HasCredentials() {
+		return nil, ErrNoCredentials
+	}
+
+	params.Set("recvWindow", orderRecvWindow)
+	var order FuturesOrder
+	if err := c.signedJSON(ctx, method, futuresOrderPath, params, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// IsOrderNotFound reports whether err is Binance's reply for an order it does not know
+func IsOrderNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Code == binanceCodeOrderNotFound
+}
+
+// IsRejection reports whether a failed order request certainly had no effect: it was refused
+// locally or answered by Binance with a client error. Network failures and 5xx replies leave the
+// outcome unknown, since Binance may have executed the request anyway
+func IsRejection(err error) bool {
+	if errors.Is(err, ErrNoCredentials) || errors.Is(err, ErrUpstreamUnavailable) {
+		return true
+	}
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Kind != ErrNetwork && apiErr.Status < http.StatusInternalServerError
+}
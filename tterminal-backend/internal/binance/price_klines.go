@@ -0,0 +1,93 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"tterminal-backend/models"
+)
+
+// GetPriceKlines fetches the most recent klines for a price type (last, mark or index)
+func (c *Client) GetPriceKlines(ctx context.Context, symbol, interval, priceType string, limit int) ([]models.Candle, error) {
+	if priceType == "" || priceType == models.PriceTypeLast {
+		return c.GetKlinesOptimized(ctx, symbol, interval, limit)
+	}
+
+	params := url.Values{}
+	params.Set("interval", interval)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	return c.fetchPriceKlines(ctx, symbol, interval, priceType, params)
+}
+
+// GetPriceKlinesWithTimeRange fetches klines for a price type within a specific time range
+func (c *Client) GetPriceKlinesWithTimeRange(ctx context.Context, symbol, interval, priceType string, startTime, endTime time.Time) ([]models.Candle, error) {
+	if priceType == "" || priceType == models.PriceTypeLast {
+		return c.GetKlinesWithTimeRange(ctx, symbol, interval, startTime, endTime)
+	}
+
+	params := url.Values{}
+	params.Set("interval", interval)
+	params.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	params.Set("limit", "1000") // Maximum allowed by Binance
+
+	return c.fetchPriceKlines(ctx, symbol, interval, priceType, params)
+}
+
+// GetPriceKlinesEndingAt fetches the limit klines for a price type opening at or before endTime
+func (c *Client) GetPriceKlinesEndingAt(ctx context.Context, symbol, interval, priceType string, endTime time.Time, limit int) ([]models.Candle, error) {
+	if priceType == "" || priceType == models.PriceTypeLast {
+		return c.GetKlinesEndingAt(ctx, symbol, interval, endTime, limit)
+	}
+
+	params := url.Values{}
+	params.Set("interval", interval)
+	params.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	return c.fetchPriceKlines(ctx, symbol, interval, priceType, params)
+}
+
+// fetchPriceKlines requests mark or index price klines and converts them to candles
+// Volume fields are zero because these series carry prices only
+func (c *Client) fetchPriceKlines(ctx context.Context, symbol, interval, priceType string, params url.Values) ([]models.Candle, error) {
+	var path string
+	switch priceType {
+	case models.PriceTypeMark:
+		path = futuresPath(symbol, "/fapi/v1/markPriceKlines")
+		params.Set("symbol", symbol)
+	case models.PriceTypeIndex:
+		path = futuresPath(symbol, "/fapi/v1/indexPriceKlines")
+		if IsCoinMargined(symbol) {
+			params.Set("pair", coinMarginedPair(symbol)) // Every contract of a pair shares its index
+		} else {
+			params.Set("pair", symbol) // Index klines are keyed by pair, not contract symbol
+		}
+	default:
+		return nil, fmt.Errorf("unsupported price type: %s", priceType)
+	}
+
+	var binanceKlines BinanceKlineResponse
+	if err := c.getJSON(ctx, path, params, &binanceKlines); err != nil {
+		return nil, err
+	}
+
+	candles := make([]models.Candle, 0, len(binanceKlines))
+	for _, klineData := range binanceKlines {
+		candle, err := c.convertBinanceKlineToCandle(klineData, symbol, interval)
+		if err != nil {
+			continue // Skip invalid candles
+		}
+		candle.PriceType = priceType
+		candles = append(candles, *candle)
+	}
+
+	return candles, nil
+}
@@ -0,0 +1,127 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// degradeAfterFailures is the number of consecutive failed requests that switches to degraded mode
+	degradeAfterFailures = 3
+	// upstreamProbeInterval is how often a request is let through to check for recovery while degraded
+	upstreamProbeInterval = 10 * time.Second
+)
+
+// ErrUpstreamUnavailable is returned without a network round trip while Binance is considered down
+var ErrUpstreamUnavailable = errors.New("binance API is unavailable (degraded mode)")
+
+// UpstreamStatus describes the reachability of the Binance REST API
+type UpstreamStatus struct {
+	Degraded            bool       `json:"degraded"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	Since               time.Time  `json:"since"` // When the current mode started
+}
+
+// upstreamTracker is an http.RoundTripper recording request outcomes against Binance
+// Network errors and 5xx responses count as failures; any other response proves the API is
+// reachable. While degraded, requests fail fast except for one probe per probe interval
+type upstreamTracker struct {
+	next http.RoundTripper
+
+	mutex     sync.Mutex
+	status    UpstreamStatus
+	lastProbe time.Time
+	onChange  func(UpstreamStatus)
+}
+
+// newUpstreamTracker wraps a transport with upstream health tracking
+func newUpstreamTracker(next http.RoundTripper) *upstreamTracker {
+	return &upstreamTracker{
+		next:   next,
+		status: UpstreamStatus{Since: time.Now()},
+	}
+}
+
+// RoundTrip executes the request unless the upstream is degraded and no probe is due
+func (t *upstreamTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	if t.status.Degraded {
+		if time.Since(t.lastProbe) < upstreamProbeInterval {
+			t.mutex.Unlock()
+			return nil, ErrUpstreamUnavailable
+		}
+		t.lastProbe = time.Now()
+	}
+	t.mutex.Unlock()
+
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		t.recordFailure(err.Error())
+	case resp.StatusCode >= http.StatusInternalServerError:
+		t.recordFailure(fmt.Sprintf("status %d", resp.StatusCode))
+	default:
+		t.recordSuccess()
+	}
+	return resp, err
+}
+
+// recordSuccess resets the failure count and leaves degraded mode
+func (t *upstreamTracker) recordSuccess() {
+	t.mutex.Lock()
+	now := time.Now()
+	t.status.LastSuccess = &now
+	t.status.ConsecutiveFailures = 0
+	recovered := t.status.Degraded
+	if recovered {
+		t.status.Degraded = false
+		t.status.Since = now
+		log.Printf("[Binance] Upstream recovered - leaving degraded mode")
+	}
+	status, onChange := t.status, t.onChange
+	t.mutex.Unlock()
+
+	if recovered && onChange != nil {
+		onChange(status)
+	}
+}
+
+// recordFailure counts a failure and enters degraded mode after too many in a row
+func (t *upstreamTracker) recordFailure(reason string) {
+	t.mutex.Lock()
+	t.status.ConsecutiveFailures++
+	t.status.LastError = reason
+	degraded := !t.status.Degraded && t.status.ConsecutiveFailures >= degradeAfterFailures
+	if degraded {
+		t.status.Degraded = true
+		t.status.Since = time.Now()
+		t.lastProbe = t.status.Since
+		log.Printf("[Binance] Upstream unreachable after %d failures (%s) - entering degraded mode", t.status.ConsecutiveFailures, reason)
+	}
+	status, onChange := t.status, t.onChange
+	t.mutex.Unlock()
+
+	if degraded && onChange != nil {
+		onChange(status)
+	}
+}
+
+// UpstreamStatus returns the current reachability of the Binance REST API
+func (c *Client) UpstreamStatus() UpstreamStatus {
+	c.upstream.mutex.Lock()
+	defer c.upstream.mutex.Unlock()
+	return c.upstream.status
+}
+
+// OnUpstreamChange registers a callback invoked when the client enters or leaves degraded mode
+func (c *Client) OnUpstreamChange(fn func(UpstreamStatus)) {
+	c.upstream.mutex.Lock()
+	defer c.upstream.mutex.Unlock()
+	c.upstream.onChange = fn
+}
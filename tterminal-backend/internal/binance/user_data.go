@@ -0,0 +1,85 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// futuresListenKeyPath creates (POST), keeps alive (PUT) and closes (DELETE) the listen key of the
+// account's user data stream
+const futuresListenKeyPath = "/fapi/v1/listenKey"
+
+// listenKeyResponse is the listen key endpoint's reply
+type listenKeyResponse struct {
+	ListenKey string `json:"listenKey"`
+}
+
+// PositionRisk is one position as reported by the position risk endpoint
+type PositionRisk struct {
+	Symbol           string `json:"symbol"`
+	PositionSide     string `json:"positionSide"` // BOTH in one-way mode, LONG or SHORT in hedge mode
+	PositionAmt      string `json:"positionAmt"`  // Signed: negative for shorts
+	EntryPrice       string `json:"entryPrice"`
+	MarkPrice        string `json:"markPrice"`
+	UnRealizedProfit string `json:"unRealizedProfit"`
+	LiquidationPrice string `json:"liquidationPrice"`
+	Leverage         string `json:"leverage"`
+	MarginType       string `json:"marginType"` // cross or isolated
+	UpdateTime       int64  `json:"updateTime"`
+}
+
+// StartUserDataStream creates a listen key for the account's user data stream, or returns the
+// active one; it expires after 60 minutes without a keepalive
+func (c *Client) StartUserDataStream(ctx context.Context) (string, error) {
+	if !c.HasCredentials() {
+		return "", ErrNoCredentials
+	}
+
+	var response listenKeyResponse
+	if err := c.listenKeyRequest(ctx, http.MethodPost, &response); err != nil {
+		return "", err
+	}
+	return response.ListenKey, nil
+}
+
+// KeepAliveUserDataStream extends the active listen key by 60 minutes
+func (c *Client) KeepAliveUserDataStream(ctx context.Context) error {
+	if !c.HasCredentials() {
+		return ErrNoCredentials
+	}
+
+	var response listenKeyResponse
+	return c.listenKeyRequest(ctx, http.MethodPut, &response)
+}
+
+// CloseUserDataStream closes the active listen key
+func (c *Client) CloseUserDataStream(ctx context.Context) error {
+	if !c.HasCredentials() {
+		return ErrNoCredentials
+	}
+
+	var response struct{}
+	return c.listenKeyRequest(ctx, http.MethodDelete, &response)
+}
+
+// GetPositionRisk fetches every position of the account, including empty ones
+// The endpoint is signed, so it needs BINANCE_API_KEY and BINANCE_SECRET_KEY
+func (c *Client) GetPositionRisk(ctx context.Context) ([]PositionRisk, error) {
+	if !c.HasCredentials() {
+		return nil, ErrNoCredentials
+	}
+
+	var positions []PositionRisk
+	if err := c.signedJSON(ctx, http.MethodGet, "/fapi/v2/positionRisk", url.Values{}, &positions); err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// listenKeyRequest sends a request to the listen key endpoint, which takes the API key but no signature
+func (c *Client) listenKeyRequest(ctx context.Context, method string, dest interface{}) error {
+	header := http.Header{}
+	header.Set("X-MBX-APIKEY", c.cfg.BinanceAPIKey)
+	return c.requestJSON(ctx, method, c.tradingBaseURL, futuresListenKeyPath, "", header, dest)
+}
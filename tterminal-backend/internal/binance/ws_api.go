@@ -0,0 +1,340 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// WS-API methods: request/response market data over one long-lived WebSocket connection,
+// saving the TCP and TLS setup a REST call pays on a cold or recycled connection
+const (
+	wsAPIMethodKlines = "klines"
+	wsAPIMethodDepth  = "depth"
+)
+
+const (
+	wsAPIRequestTimeout = 5 * time.Second  // Wait for a reply before giving up on a request
+	wsAPIHandshake      = 5 * time.Second  // Dial timeout; requests fall back to REST meanwhile
+	wsAPIRedialBackoff  = 10 * time.Second // Wait after a failed dial before trying again
+	wsAPIWriteTimeout   = 5 * time.Second
+)
+
+// maxDepthLimit is the most order book levels per side one depth snapshot returns
+const maxDepthLimit = 1000
+
+// ErrWSAPIUnsupportedSymbol is returned for symbols the WS-API endpoint does not serve
+// (COIN-margined contracts); callers use REST instead
+var ErrWSAPIUnsupportedSymbol = errors.New("symbol is not served by the binance ws-api")
+
+// wsAPIRequest is one request frame
+type wsAPIRequest struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// wsAPIResponse is one reply frame, matched to its request by ID
+type wsAPIResponse struct {
+	ID     string          `json:"id"`
+	Status int             `json:"status"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
+
+// OrderBookSnapshot is a futures order book snapshot from the REST or WS-API depth method
+type OrderBookSnapshot struct {
+	LastUpdateID    int64      `json:"lastUpdateId"`
+	EventTime       int64      `json:"E"`
+	TransactionTime int64      `json:"T"`
+	Bids            [][]string `json:"bids"` // [price, quantity], best first
+	Asks            [][]string `json:"asks"`
+}
+
+// DepthUpdate converts the snapshot into a book replacement for a symbol
+func (s *OrderBookSnapshot) DepthUpdate(symbol string) models.DepthUpdate {
+	return models.DepthUpdate{
+		Symbol:   symbol,
+		Bids:     s.Bids,
+		Asks:     s.Asks,
+		Snapshot: true,
+		Time:     time.UnixMilli(s.TransactionTime),
+	}
+}
+
+// WSAPIClient fetches klines and order book snapshots over the Binance WebSocket API
+// The connection is dialed on first use and redialed after Binance closes it (every 24 hours),
+// requests share the REST client's request budget, and replies are converted like REST responses
+type WSAPIClient struct {
+	url    string
+	client *Client // Request budget and kline conversion
+
+	mu       sync.Mutex // Guards the connection, pending requests and writes
+	conn     *websocket.Conn
+	pending  map[string]chan wsAPIResponse
+	nextID   int64
+	redialAt time.Time // No dial before this, after a failed one
+}
+
+// NewWSAPIClient creates a WS-API client for an endpoint such as wss://ws-fapi.binance.com/ws-fapi/v1
+func NewWSAPIClient(client *Client, url string) *WSAPIClient {
+	return &WSAPIClient{
+		url:     url,
+		client:  client,
+		pending: make(map[string]chan wsAPIResponse),
+	}
+}
+
+// GetKlinesOptimized fetches the most recent limit last price klines
+func (w *WSAPIClient) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	params := map[string]interface{}{
+		"symbol":   symbol,
+		"interval": interval,
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	return w.fetchKlines(ctx, symbol, interval, params)
+}
+
+// GetKlinesEndingAt fetches the limit last price klines opening at or before endTime
+func (w *WSAPIClient) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	params := map[string]interface{}{
+		"symbol":   symbol,
+		"interval": interval,
+		"endTime":  endTime.UnixMilli(),
+	}
+	if limit > 0 {
+		params["limit"] = limit
+	}
+	return w.fetchKlines(ctx, symbol, interval, params)
+}
+
+// GetDepthSnapshot fetches the top limit levels of a symbol's order book (default and max 1000)
+func (w *WSAPIClient) GetDepthSnapshot(ctx context.Context, symbol string, limit int) (*OrderBookSnapshot, error) {
+	if IsCoinMargined(symbol) {
+		return nil, ErrWSAPIUnsupportedSymbol
+	}
+	if limit <= 0 || limit > maxDepthLimit {
+		limit = maxDepthLimit
+	}
+
+	result, err := w.request(ctx, wsAPIMethodDepth, map[string]interface{}{
+		"symbol": symbol,
+		"limit":  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot OrderBookSnapshot
+	if err := json.Unmarshal(result, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode depth reply: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// BookSnapshots fetches order book snapshots over the WS-API while enabled reports true, and over
+// REST otherwise or when the WS-API request fails (COIN-margined contracts always use REST)
+type BookSnapshots struct {
+	wsAPI   *WSAPIClient
+	rest    *Client
+	enabled func() bool // Checked per request, so the WS-API can be toggled at runtime
+}
+
+// NewBookSnapshots creates a snapshot source preferring the WS-API over REST
+func NewBookSnapshots(wsAPI *WSAPIClient, rest *Client, enabled func() bool) *BookSnapshots {
+	return &BookSnapshots{wsAPI: wsAPI, rest: rest, enabled: enabled}
+}
+
+// GetDepthSnapshot fetches the top limit levels of a symbol's order book (default and max 1000)
+func (b *BookSnapshots) GetDepthSnapshot(ctx context.Context, symbol string, limit int) (*OrderBookSnapshot, error) {
+	if b.enabled() {
+		if snapshot, err := b.wsAPI.GetDepthSnapshot(ctx, symbol, limit); err == nil {
+			return snapshot, nil
+		}
+	}
+	return b.rest.GetDepthSnapshot(ctx, symbol, limit)
+}
+
+// fetchKlines requests klines and converts them to candles, as the REST client does
+func (w *WSAPIClient) fetchKlines(ctx context.Context, symbol, interval string, params map[string]interface{}) ([]models.Candle, error) {
+	if IsCoinMargined(symbol) {
+		return nil, ErrWSAPIUnsupportedSymbol
+	}
+
+	result, err := w.request(ctx, wsAPIMethodKlines, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var klines BinanceKlineResponse
+	if err := json.Unmarshal(result, &klines); err != nil {
+		return nil, fmt.Errorf("failed to decode klines reply: %w", err)
+	}
+
+	candles := make([]models.Candle, 0, len(klines))
+	for _, klineData := range klines {
+		candle, err := w.client.convertBinanceKlineToCandle(klineData, symbol, interval)
+		if err != nil {
+			continue // Skip invalid candles
+		}
+		candles = append(candles, *candle)
+	}
+	return candles, nil
+}
+
+// request sends one request and waits for its reply, the context's end or the request timeout
+func (w *WSAPIClient) request(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	path := "ws-api " + method
+	if !w.client.rateLimiter.canMakeRequest() {
+		return nil, newRateLimitError(ctx, path)
+	}
+
+	id, replies, err := w.send(method, params)
+	if err != nil {
+		return nil, newNetworkError(ctx, path, err)
+	}
+
+	timer := time.NewTimer(wsAPIRequestTimeout)
+	defer timer.Stop()
+
+	select {
+	case resp, ok := <-replies:
+		if !ok {
+			return nil, newNetworkError(ctx, path, errors.New("connection closed before the reply"))
+		}
+		if resp.Status != 200 || resp.Error != nil {
+			return nil, newWSAPIError(ctx, path, resp)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		w.forget(id)
+		return nil, ctx.Err()
+	case <-timer.C:
+		w.forget(id)
+		return nil, newNetworkError(ctx, path, fmt.Errorf("no reply within %s", wsAPIRequestTimeout))
+	}
+}
+
+// send writes a request on the connection, dialing it if needed, and registers its reply channel
+func (w *WSAPIClient) send(method string, params map[string]interface{}) (string, chan wsAPIResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if err := w.dialLocked(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	w.nextID++
+	id := strconv.FormatInt(w.nextID, 10)
+	replies := make(chan wsAPIResponse, 1)
+	w.pending[id] = replies
+
+	w.conn.SetWriteDeadline(time.Now().Add(wsAPIWriteTimeout))
+	if err := w.conn.WriteJSON(wsAPIRequest{ID: id, Method: method, Params: params}); err != nil {
+		delete(w.pending, id)
+		w.closeLocked(w.conn)
+		return "", nil, fmt.Errorf("failed to write request: %w", err)
+	}
+	return id, replies, nil
+}
+
+// dialLocked connects to the endpoint and starts reading replies; the caller holds w.mu
+// A failed dial is not retried until the backoff passes, so requests fall back to REST quickly
+func (w *WSAPIClient) dialLocked() error {
+	if time.Now().Before(w.redialAt) {
+		return errors.New("ws-api connection unavailable, waiting to redial")
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: wsAPIHandshake}
+	conn, _, err := dialer.Dial(w.url, nil)
+	if err != nil {
+		w.redialAt = time.Now().Add(wsAPIRedialBackoff)
+		log.Printf("[Binance WS-API] Failed to connect to %s: %v", w.url, err)
+		return err
+	}
+
+	log.Printf("[Binance WS-API] Connected to %s", w.url)
+	w.conn = conn
+	go w.readLoop(conn)
+	return nil
+}
+
+// readLoop delivers replies to their waiting requests until the connection fails
+// Binance's ping frames are answered by the default ping handler
+func (w *WSAPIClient) readLoop(conn *websocket.Conn) {
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			log.Printf("[Binance WS-API] Connection closed: %v", err)
+			w.mu.Lock()
+			w.closeLocked(conn)
+			w.mu.Unlock()
+			return
+		}
+
+		var resp wsAPIResponse
+		if err := json.Unmarshal(message, &resp); err != nil || resp.ID == "" {
+			continue
+		}
+
+		w.mu.Lock()
+		replies, exists := w.pending[resp.ID]
+		delete(w.pending, resp.ID)
+		w.mu.Unlock()
+		if exists {
+			replies <- resp
+		}
+	}
+}
+
+// closeLocked drops a connection and fails its waiting requests; the caller holds w.mu
+// It is a no-op for a connection already replaced
+func (w *WSAPIClient) closeLocked(conn *websocket.Conn) {
+	if w.conn != conn {
+		return
+	}
+	conn.Close()
+	w.conn = nil
+	for id, replies := range w.pending {
+		close(replies)
+		delete(w.pending, id)
+	}
+}
+
+// forget drops a request that stopped waiting for its reply
+func (w *WSAPIClient) forget(id string) {
+	w.mu.Lock()
+	delete(w.pending, id)
+	w.mu.Unlock()
+}
+
+// newWSAPIError classifies a WS-API error reply like a REST error response
+func newWSAPIError(ctx context.Context, path string, resp wsAPIResponse) *APIError {
+	apiErr := &APIError{
+		Kind:      ErrUpstream,
+		Path:      path,
+		Status:    resp.Status,
+		RequestID: RequestIDFromContext(ctx),
+	}
+	if resp.Error != nil {
+		apiErr.Code = resp.Error.Code
+		apiErr.Message = resp.Error.Msg
+	}
+	apiErr.classify()
+	return apiErr
+}
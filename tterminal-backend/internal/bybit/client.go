@@ -0,0 +1,205 @@
+// Package bybit provides a Bybit v5 REST client for linear perpetual market data
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+)
+
+const (
+	// klinePath is the v5 market kline endpoint
+	klinePath = "/v5/market/kline"
+	// category selects USDT linear perpetuals
+	category = "linear"
+	// maxKlineLimit is the largest page Bybit returns per kline request
+	maxKlineLimit = 1000
+)
+
+// intervals maps API intervals to Bybit kline intervals; Bybit has no 1s, 8h or 3d klines
+var intervals = map[string]string{
+	"1m": "1", "3m": "3", "5m": "5", "15m": "15", "30m": "30",
+	"1h": "60", "2h": "120", "4h": "240", "6h": "360", "12h": "720",
+	"1d": "D", "1w": "W", "1M": "M",
+}
+
+// Interval returns the Bybit kline interval for an API interval
+func Interval(interval string) (string, bool) {
+	bybitInterval, ok := intervals[interval]
+	return bybitInterval, ok
+}
+
+// APIError is a failed Bybit request: a non-200 response or a non-zero retCode
+type APIError struct {
+	Path    string
+	Status  int    // HTTP status (0 for network failures)
+	Code    int    // Bybit retCode
+	Message string // Bybit retMsg or network error
+}
+
+// Error describes the failure
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("bybit %s", e.Path)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d", e.Status)
+		if e.Code != 0 {
+			msg += fmt.Sprintf(", code %d", e.Code)
+		}
+		msg += ")"
+	}
+	return msg + ": " + e.Message
+}
+
+// Client fetches Bybit linear perpetual klines
+// Symbols may be given bare ("BTCUSDT") or qualified ("BYBIT:BTCUSDT"); returned candles always
+// carry the qualified symbol so they are stored and cached apart from Binance data
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Bybit API client
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.BybitBaseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// envelope is the common v5 response wrapper
+type envelope struct {
+	RetCode int             `json:"retCode"`
+	RetMsg  string          `json:"retMsg"`
+	Result  json.RawMessage `json:"result"`
+	Time    int64           `json:"time"` // Server time (Unix milliseconds)
+}
+
+// klineResult is the v5 kline result; each entry is [start, open, high, low, close, volume, turnover], newest first
+type klineResult struct {
+	List [][]string `json:"list"`
+}
+
+// GetServerTime returns Bybit's current server time
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	response, err := c.get(ctx, "/v5/market/time", url.Values{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(response.Time), nil
+}
+
+// GetKlinesOptimized fetches the most recent klines
+func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, url.Values{}, limit)
+}
+
+// GetKlinesEndingAt fetches the limit klines opening at or before endTime, for backwards paging
+func (c *Client) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	params := url.Values{}
+	params.Set("end", strconv.FormatInt(endTime.UnixMilli(), 10))
+	return c.fetchKlines(ctx, symbol, interval, params, limit)
+}
+
+// fetchKlines requests klines and converts them to candles, oldest first
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, params url.Values, limit int) ([]models.Candle, error) {
+	bybitInterval, ok := Interval(interval)
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not available on Bybit", interval)
+	}
+	duration, _ := models.IntervalDuration(interval)
+
+	_, bybitSymbol := models.SplitSymbol(symbol)
+	key := models.QualifySymbol(models.ExchangeBybit, bybitSymbol)
+
+	if limit <= 0 || limit > maxKlineLimit {
+		limit = maxKlineLimit
+	}
+	params.Set("category", category)
+	params.Set("symbol", bybitSymbol)
+	params.Set("interval", bybitInterval)
+	params.Set("limit", strconv.Itoa(limit))
+
+	response, err := c.get(ctx, klinePath, params)
+	if err != nil {
+		return nil, err
+	}
+	var result klineResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode klines: %w", err)
+	}
+
+	candles := make([]models.Candle, 0, len(result.List))
+	for _, entry := range result.List {
+		if len(entry) < 7 {
+			continue
+		}
+		start, err := strconv.ParseInt(entry[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		openTime := time.UnixMilli(start)
+		candles = append(candles, models.Candle{
+			Symbol:           key,
+			OpenTime:         openTime,
+			Open:             entry[1],
+			High:             entry[2],
+			Low:              entry[3],
+			Close:            entry[4],
+			Volume:           entry[5],
+			CloseTime:        openTime.Add(duration - time.Millisecond),
+			QuoteAssetVolume: entry[6],
+			// Bybit klines carry no trade count or taker buy volume
+			TakerBuyBaseAssetVolume:  "0",
+			TakerBuyQuoteAssetVolume: "0",
+			Interval:                 interval,
+			PriceType:                models.PriceTypeLast,
+		})
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	return candles, nil
+}
+
+// get performs a GET request and decodes the response envelope, checking Bybit's retCode
+func (c *Client) get(ctx context.Context, path string, params url.Values) (*envelope, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &APIError{Path: path, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Path: path, Status: resp.StatusCode, Message: string(body)}
+	}
+
+	var response envelope
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.RetCode != 0 {
+		return nil, &APIError{Path: path, Status: resp.StatusCode, Code: response.RetCode, Message: response.RetMsg}
+	}
+	return &response, nil
+}
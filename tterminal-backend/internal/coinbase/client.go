@@ -0,0 +1,243 @@
+// Package coinbase provides a Coinbase Advanced Trade REST client for spot market data
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+)
+
+const (
+	// maxCandlesPage is the largest number of candles Coinbase returns per request
+	maxCandlesPage = 350
+	// maxKlineLimit caps the klines assembled from pages per call
+	maxKlineLimit = 1000
+)
+
+// granularities maps API intervals to Coinbase candle granularities
+var granularities = map[string]string{
+	"1m": "ONE_MINUTE", "5m": "FIVE_MINUTE", "15m": "FIFTEEN_MINUTE", "30m": "THIRTY_MINUTE",
+	"1h": "ONE_HOUR", "2h": "TWO_HOUR", "4h": "FOUR_HOUR", "6h": "SIX_HOUR", "1d": "ONE_DAY",
+}
+
+// quoteCurrencies are the quote currencies recognised when splitting a bare symbol, longest first
+var quoteCurrencies = []string{"USDC", "USDT", "USD", "EUR", "GBP"}
+
+// Granularity returns the Coinbase candle granularity for an API interval
+func Granularity(interval string) (string, bool) {
+	granularity, ok := granularities[interval]
+	return granularity, ok
+}
+
+// ProductID returns the spot product of a bare or qualified symbol ("BTCUSD" or
+// "COINBASE:BTCUSD" becomes "BTC-USD"); product IDs are returned unchanged
+func ProductID(symbol string) string {
+	_, symbol = models.SplitSymbol(symbol)
+	symbol = strings.ToUpper(symbol)
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+	for _, quote := range quoteCurrencies {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base + "-" + quote
+		}
+	}
+	return symbol
+}
+
+// Symbol returns the bare symbol of a product ("BTC-USD" becomes "BTCUSD")
+func Symbol(productID string) string {
+	return strings.ReplaceAll(productID, "-", "")
+}
+
+// APIError is a failed Coinbase request
+type APIError struct {
+	Path    string
+	Status  int    // HTTP status (0 for network failures)
+	Message string // Coinbase error message or network error
+}
+
+// Error describes the failure
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("coinbase %s", e.Path)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.Status)
+	}
+	return msg + ": " + e.Message
+}
+
+// Client fetches Coinbase spot candles from the public market endpoints (no API key required)
+// Symbols may be given bare ("BTCUSD"), qualified ("COINBASE:BTCUSD") or as products
+// ("BTC-USD"); returned candles always carry the qualified symbol so they are stored and cached
+// apart from Binance data
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Coinbase API client
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.CoinbaseBaseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// candlesResponse is the public product candles response, newest first
+type candlesResponse struct {
+	Candles []struct {
+		Start  string `json:"start"` // Unix seconds
+		Low    string `json:"low"`
+		High   string `json:"high"`
+		Open   string `json:"open"`
+		Close  string `json:"close"`
+		Volume string `json:"volume"` // Base currency
+	} `json:"candles"`
+}
+
+// GetServerTime returns Coinbase's current server time
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	var response struct {
+		EpochMillis string `json:"epochMillis"`
+	}
+	if err := c.get(ctx, "/api/v3/brokerage/time", url.Values{}, &response); err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(response.EpochMillis, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid server time %q", response.EpochMillis)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// GetKlinesOptimized fetches the most recent klines
+func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, time.Now(), limit)
+}
+
+// GetKlinesEndingAt fetches the limit klines opening at or before endTime, for backwards paging
+func (c *Client) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, endTime, limit)
+}
+
+// fetchKlines requests windows of candles backwards from endTime until limit klines are
+// collected or a window comes back empty, and converts them to candles, oldest first
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	granularity, ok := Granularity(interval)
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not available on Coinbase", interval)
+	}
+	duration, _ := models.IntervalDuration(interval)
+
+	productID := ProductID(symbol)
+	key := models.QualifySymbol(models.ExchangeCoinbase, Symbol(productID))
+	path := "/api/v3/brokerage/market/products/" + productID + "/candles"
+
+	if limit <= 0 || limit > maxKlineLimit {
+		limit = maxKlineLimit
+	}
+
+	// Windows are inclusive on both ends and aligned to the last bar opening at or before endTime
+	end := endTime.Truncate(duration)
+	candles := make([]models.Candle, 0, limit)
+	for len(candles) < limit {
+		pageSize := limit - len(candles)
+		if pageSize > maxCandlesPage {
+			pageSize = maxCandlesPage
+		}
+		start := end.Add(-time.Duration(pageSize-1) * duration)
+
+		params := url.Values{}
+		params.Set("start", strconv.FormatInt(start.Unix(), 10))
+		params.Set("end", strconv.FormatInt(end.Unix(), 10))
+		params.Set("granularity", granularity)
+		params.Set("limit", strconv.Itoa(pageSize))
+
+		var response candlesResponse
+		if err := c.get(ctx, path, params, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Candles) == 0 {
+			break
+		}
+
+		for _, entry := range response.Candles {
+			seconds, err := strconv.ParseInt(entry.Start, 10, 64)
+			if err != nil {
+				continue
+			}
+			openTime := time.Unix(seconds, 0)
+			if openTime.Before(start) || openTime.After(end) {
+				continue
+			}
+			candles = append(candles, models.Candle{
+				Symbol:    key,
+				OpenTime:  openTime,
+				Open:      entry.Open,
+				High:      entry.High,
+				Low:       entry.Low,
+				Close:     entry.Close,
+				Volume:    entry.Volume,
+				CloseTime: openTime.Add(duration - time.Millisecond),
+				// Coinbase candles carry no quote volume, trade count or taker buy volume
+				QuoteAssetVolume:         "0",
+				TakerBuyBaseAssetVolume:  "0",
+				TakerBuyQuoteAssetVolume: "0",
+				Interval:                 interval,
+				PriceType:                models.PriceTypeLast,
+			})
+		}
+		end = start.Add(-duration)
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	return candles, nil
+}
+
+// get performs a GET request and decodes a successful JSON response into out
+func (c *Client) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &APIError{Path: path, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		message := string(body)
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			message = apiErr.Message
+		}
+		return &APIError{Path: path, Status: resp.StatusCode, Message: message}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
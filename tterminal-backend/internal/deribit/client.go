@@ -0,0 +1,273 @@
+// Package deribit provides a Deribit REST client for option chains and the DVOL index
+package deribit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+)
+
+// maxDVOLLimit caps the DVOL candles fetched per call
+const maxDVOLLimit = 1000
+
+// dvolResolutions maps API intervals to Deribit volatility index resolutions
+var dvolResolutions = map[string]string{
+	"1m":  "60",
+	"1h":  "3600",
+	"12h": "43200",
+	"1d":  "1D",
+}
+
+// DVOLResolution returns the Deribit volatility index resolution for an API interval
+func DVOLResolution(interval string) (string, bool) {
+	resolution, ok := dvolResolutions[interval]
+	return resolution, ok
+}
+
+// DVOLIntervals lists the intervals DVOL candles are available in
+func DVOLIntervals() []string {
+	return []string{"1m", "1h", "12h", "1d"}
+}
+
+// APIError is a failed Deribit request
+type APIError struct {
+	Path    string
+	Status  int    // HTTP status (0 for network failures)
+	Code    int    // Deribit error code
+	Message string // Deribit error or network error
+}
+
+// Error describes the failure
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("deribit %s", e.Path)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.Status)
+	}
+	return msg + ": " + e.Message
+}
+
+// Client fetches public Deribit option and volatility index data
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Deribit API client
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.DeribitBaseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// bookSummary is a get_book_summary_by_currency entry; bid and ask are null without orders
+type bookSummary struct {
+	InstrumentName  string   `json:"instrument_name"`
+	MarkPrice       float64  `json:"mark_price"`
+	MarkIV          float64  `json:"mark_iv"`
+	BidPrice        *float64 `json:"bid_price"`
+	AskPrice        *float64 `json:"ask_price"`
+	OpenInterest    float64  `json:"open_interest"`
+	Volume          float64  `json:"volume"`
+	UnderlyingPrice float64  `json:"underlying_price"`
+}
+
+// GetOptionQuotes returns the market of every listed option of a currency ("BTC", "ETH")
+func (c *Client) GetOptionQuotes(ctx context.Context, currency string) ([]models.OptionQuote, error) {
+	params := url.Values{}
+	params.Set("currency", strings.ToUpper(currency))
+	params.Set("kind", "option")
+
+	var summaries []bookSummary
+	if err := c.get(ctx, "/api/v2/public/get_book_summary_by_currency", params, &summaries); err != nil {
+		return nil, err
+	}
+
+	quotes := make([]models.OptionQuote, 0, len(summaries))
+	for _, summary := range summaries {
+		expiry, strike, optionType, ok := ParseInstrument(summary.InstrumentName)
+		if !ok {
+			continue
+		}
+		quote := models.OptionQuote{
+			Instrument:      summary.InstrumentName,
+			Type:            optionType,
+			Strike:          strike,
+			Expiry:          expiry,
+			MarkPrice:       summary.MarkPrice,
+			MarkIV:          summary.MarkIV,
+			OpenInterest:    summary.OpenInterest,
+			Volume:          summary.Volume,
+			UnderlyingPrice: summary.UnderlyingPrice,
+		}
+		if summary.BidPrice != nil {
+			quote.BidPrice = *summary.BidPrice
+		}
+		if summary.AskPrice != nil {
+			quote.AskPrice = *summary.AskPrice
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, nil
+}
+
+// GetIndexPrice returns the Deribit price index of a currency ("btc_usd")
+func (c *Client) GetIndexPrice(ctx context.Context, currency string) (float64, error) {
+	params := url.Values{}
+	params.Set("index_name", strings.ToLower(currency)+"_usd")
+
+	var result struct {
+		IndexPrice float64 `json:"index_price"`
+	}
+	if err := c.get(ctx, "/api/v2/public/get_index_price", params, &result); err != nil {
+		return 0, err
+	}
+	return result.IndexPrice, nil
+}
+
+// GetDVOLCandles fetches the last limit DVOL candles of a currency, oldest first
+func (c *Client) GetDVOLCandles(ctx context.Context, currency, interval string, limit int) ([]models.DVOLCandle, error) {
+	resolution, ok := DVOLResolution(interval)
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not available for DVOL", interval)
+	}
+	duration, _ := models.IntervalDuration(interval)
+	if limit <= 0 || limit > maxDVOLLimit {
+		limit = maxDVOLLimit
+	}
+
+	end := time.Now()
+	start := end.Truncate(duration).Add(-time.Duration(limit-1) * duration)
+
+	params := url.Values{}
+	params.Set("currency", strings.ToUpper(currency))
+	params.Set("resolution", resolution)
+	params.Set("start_timestamp", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("end_timestamp", strconv.FormatInt(end.UnixMilli(), 10))
+
+	// Deribit pages from the newest candle backwards, returning a continuation timestamp
+	var candles []models.DVOLCandle
+	for len(candles) < limit {
+		var result struct {
+			Data         [][]float64 `json:"data"` // [timestamp, open, high, low, close]
+			Continuation *int64      `json:"continuation"`
+		}
+		if err := c.get(ctx, "/api/v2/public/get_volatility_index_data", params, &result); err != nil {
+			return nil, err
+		}
+		for _, entry := range result.Data {
+			if len(entry) < 5 {
+				continue
+			}
+			candles = append(candles, models.DVOLCandle{T: int64(entry[0]), O: entry[1], H: entry[2], L: entry[3], C: entry[4]})
+		}
+		if result.Continuation == nil || len(result.Data) == 0 {
+			break
+		}
+		params.Set("end_timestamp", strconv.FormatInt(*result.Continuation, 10))
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].T < candles[j].T })
+	// Pages may overlap at their boundary
+	unique := candles[:0]
+	for _, candle := range candles {
+		if len(unique) == 0 || unique[len(unique)-1].T != candle.T {
+			unique = append(unique, candle)
+		}
+	}
+	if len(unique) > limit {
+		unique = unique[len(unique)-limit:]
+	}
+	return unique, nil
+}
+
+// ParseInstrument parses an option instrument name ("BTC-27JUN25-100000-C") into its expiry,
+// strike and type. Deribit options expire at 08:00 UTC
+func ParseInstrument(name string) (expiry time.Time, strike float64, optionType string, ok bool) {
+	parts := strings.Split(name, "-")
+	if len(parts) != 4 {
+		return time.Time{}, 0, "", false
+	}
+
+	date, err := time.Parse("2Jan06", parts[1])
+	if err != nil {
+		return time.Time{}, 0, "", false
+	}
+	// Fractional strikes of linear options use "d" as the decimal point ("2d5")
+	strike, err = strconv.ParseFloat(strings.Replace(parts[2], "d", ".", 1), 64)
+	if err != nil {
+		return time.Time{}, 0, "", false
+	}
+	switch parts[3] {
+	case "C":
+		optionType = models.OptionTypeCall
+	case "P":
+		optionType = models.OptionTypePut
+	default:
+		return time.Time{}, 0, "", false
+	}
+
+	return date.Add(8 * time.Hour), strike, optionType, true
+}
+
+// ExpiryLabel returns Deribit's code for an expiry ("27JUN25")
+func ExpiryLabel(expiry time.Time) string {
+	return strings.ToUpper(expiry.UTC().Format("2Jan06"))
+}
+
+// get performs a GET request and decodes the result of a successful JSON-RPC response into out
+func (c *Client) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &APIError{Path: path, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{Path: path, Status: resp.StatusCode, Message: string(body)}
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if envelope.Error != nil {
+		return &APIError{Path: path, Status: resp.StatusCode, Code: envelope.Error.Code, Message: envelope.Error.Message}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Path: path, Status: resp.StatusCode, Message: string(body)}
+	}
+
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
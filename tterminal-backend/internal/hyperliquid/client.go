@@ -0,0 +1,268 @@
+// Package hyperliquid provides a Hyperliquid REST client for perpetual market data
+package hyperliquid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+)
+
+const (
+	// maxKlineLimit caps the klines fetched per call; Hyperliquid serves up to 5000 per request
+	maxKlineLimit = 1000
+	// quoteSuffix is appended to coins to form symbols; perpetuals are margined in USDC
+	quoteSuffix = "USD"
+)
+
+// resolutions lists the API intervals Hyperliquid candles serve under the same name
+var resolutions = map[string]bool{
+	"1m": true, "3m": true, "5m": true, "15m": true, "30m": true,
+	"1h": true, "2h": true, "4h": true, "8h": true, "12h": true,
+	"1d": true, "3d": true, "1w": true, "1M": true,
+}
+
+// Resolution returns the Hyperliquid candle interval for an API interval
+func Resolution(interval string) (string, bool) {
+	return interval, resolutions[interval]
+}
+
+// Coin returns the upper-cased coin of a bare or qualified symbol ("BTCUSD" or
+// "HYPERLIQUID:BTCUSD" becomes "BTC"); coins are returned upper-cased
+// Hyperliquid names some coins in mixed case ("kPEPE"); Client.CoinName resolves those
+func Coin(symbol string) string {
+	_, symbol = models.SplitSymbol(symbol)
+	symbol = strings.ToUpper(symbol)
+	if trimmed := strings.TrimSuffix(symbol, quoteSuffix); trimmed != "" {
+		return trimmed
+	}
+	return symbol
+}
+
+// Symbol returns the bare symbol of a coin ("BTC" becomes "BTCUSD")
+func Symbol(coin string) string {
+	return strings.ToUpper(coin) + quoteSuffix
+}
+
+// APIError is a failed Hyperliquid request
+type APIError struct {
+	Type    string // Info request type
+	Status  int    // HTTP status (0 for network failures)
+	Message string // Response body or network error
+}
+
+// Error describes the failure
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("hyperliquid %s", e.Type)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.Status)
+	}
+	return msg + ": " + e.Message
+}
+
+// Client fetches Hyperliquid perpetual klines from the public info endpoint
+// Symbols may be given bare ("BTCUSD"), qualified ("HYPERLIQUID:BTCUSD") or as coins ("BTC");
+// returned candles always carry the qualified symbol so they are stored and cached apart from
+// Binance data
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu    sync.RWMutex
+	coins map[string]string // Upper-cased coin to Hyperliquid's name ("KPEPE" to "kPEPE")
+}
+
+// NewClient creates a new Hyperliquid API client
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.HyperliquidBaseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// candle is a candle snapshot entry; prices and volumes are strings
+type candle struct {
+	OpenTime  int64  `json:"t"` // Unix milliseconds
+	CloseTime int64  `json:"T"`
+	Open      string `json:"o"`
+	High      string `json:"h"`
+	Low       string `json:"l"`
+	Close     string `json:"c"`
+	Volume    string `json:"v"` // Base currency
+	Trades    int32  `json:"n"`
+}
+
+// CoinName returns Hyperliquid's name for the coin of a symbol, loading the perpetual universe
+// on first use
+func (c *Client) CoinName(ctx context.Context, symbol string) (string, error) {
+	coin := Coin(symbol)
+
+	c.mu.RLock()
+	name, exists := c.coins[coin]
+	loaded := c.coins != nil
+	c.mu.RUnlock()
+	if exists {
+		return name, nil
+	}
+	if loaded {
+		return "", fmt.Errorf("%s is not a Hyperliquid perpetual", coin)
+	}
+
+	var meta struct {
+		Universe []struct {
+			Name string `json:"name"`
+		} `json:"universe"`
+	}
+	if err := c.info(ctx, map[string]interface{}{"type": "meta"}, &meta); err != nil {
+		return "", err
+	}
+
+	coins := make(map[string]string, len(meta.Universe))
+	for _, asset := range meta.Universe {
+		coins[strings.ToUpper(asset.Name)] = asset.Name
+	}
+	c.mu.Lock()
+	c.coins = coins
+	c.mu.Unlock()
+
+	if name, exists := coins[coin]; exists {
+		return name, nil
+	}
+	return "", fmt.Errorf("%s is not a Hyperliquid perpetual", coin)
+}
+
+// GetCoinNames resolves Hyperliquid's coin names for bare symbols, keyed by symbol
+func (c *Client) GetCoinNames(ctx context.Context, symbols []string) (map[string]string, error) {
+	names := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		name, err := c.CoinName(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		names[symbol] = name
+	}
+	return names, nil
+}
+
+// GetKlinesOptimized fetches the most recent klines
+func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, time.Now(), limit)
+}
+
+// GetKlinesEndingAt fetches the limit klines opening at or before endTime, for backwards paging
+func (c *Client) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, endTime, limit)
+}
+
+// fetchKlines requests the candles opening between limit bars before endTime and endTime and
+// converts them to candles, oldest first
+// Hyperliquid only serves the most recent 5000 candles of each interval
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	resolution, ok := Resolution(interval)
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not available on Hyperliquid", interval)
+	}
+	duration, _ := models.IntervalDuration(interval)
+
+	coin, err := c.CoinName(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	key := models.QualifySymbol(models.ExchangeHyperliquid, Symbol(coin))
+
+	if limit <= 0 || limit > maxKlineLimit {
+		limit = maxKlineLimit
+	}
+
+	end := endTime.Truncate(duration)
+	start := end.Add(-time.Duration(limit-1) * duration)
+	request := map[string]interface{}{
+		"type": "candleSnapshot",
+		"req": map[string]interface{}{
+			"coin":      coin,
+			"interval":  resolution,
+			"startTime": start.UnixMilli(),
+			"endTime":   end.UnixMilli(),
+		},
+	}
+
+	var response []candle
+	if err := c.info(ctx, request, &response); err != nil {
+		return nil, err
+	}
+
+	candles := make([]models.Candle, 0, len(response))
+	for _, entry := range response {
+		openTime := time.UnixMilli(entry.OpenTime)
+		if openTime.Before(start) || openTime.After(end) {
+			continue
+		}
+		candles = append(candles, models.Candle{
+			Symbol:     key,
+			OpenTime:   openTime,
+			Open:       entry.Open,
+			High:       entry.High,
+			Low:        entry.Low,
+			Close:      entry.Close,
+			Volume:     entry.Volume,
+			CloseTime:  openTime.Add(duration - time.Millisecond),
+			TradeCount: entry.Trades,
+			// Hyperliquid candles carry no quote volume or taker buy volume
+			QuoteAssetVolume:         "0",
+			TakerBuyBaseAssetVolume:  "0",
+			TakerBuyQuoteAssetVolume: "0",
+			Interval:                 interval,
+			PriceType:                models.PriceTypeLast,
+		})
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	return candles, nil
+}
+
+// info posts an info request and decodes a successful JSON response into out
+func (c *Client) info(ctx context.Context, request map[string]interface{}, out interface{}) error {
+	requestType, _ := request["type"].(string)
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/info", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &APIError{Type: requestType, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Type: requestType, Status: resp.StatusCode, Message: string(responseBody)}
+	}
+
+	if err := json.Unmarshal(responseBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
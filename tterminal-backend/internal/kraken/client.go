@@ -0,0 +1,240 @@
+// Package kraken provides a Kraken Futures REST client for linear perpetual market data
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+)
+
+const (
+	// maxCandlesPage is the number of bars requested per charts window
+	maxCandlesPage = 1000
+	// maxKlineLimit caps the klines assembled from pages per call
+	maxKlineLimit = 1000
+	// perpetualPrefix marks multi-collateral linear perpetuals, sized in the base currency
+	perpetualPrefix = "PF_"
+)
+
+// resolutions lists the API intervals Kraken's charts serve under the same name
+var resolutions = map[string]bool{
+	"1m": true, "5m": true, "15m": true, "30m": true,
+	"1h": true, "4h": true, "12h": true, "1d": true, "1w": true,
+}
+
+// Resolution returns the Kraken charts resolution for an API interval
+func Resolution(interval string) (string, bool) {
+	return interval, resolutions[interval]
+}
+
+// ProductID returns the linear perpetual of a bare or qualified symbol ("BTCUSD" or
+// "KRAKEN:BTCUSD" becomes "PF_XBTUSD"); product IDs are returned unchanged
+func ProductID(symbol string) string {
+	_, symbol = models.SplitSymbol(symbol)
+	symbol = strings.ToUpper(symbol)
+	if strings.Contains(symbol, "_") {
+		return symbol
+	}
+	// Kraken names bitcoin XBT
+	if strings.HasPrefix(symbol, "BTC") {
+		symbol = "XBT" + strings.TrimPrefix(symbol, "BTC")
+	}
+	return perpetualPrefix + symbol
+}
+
+// Symbol returns the bare symbol of a product ("PF_XBTUSD" becomes "BTCUSD")
+func Symbol(productID string) string {
+	symbol := strings.TrimPrefix(strings.ToUpper(productID), perpetualPrefix)
+	if strings.HasPrefix(symbol, "XBT") {
+		symbol = "BTC" + strings.TrimPrefix(symbol, "XBT")
+	}
+	return symbol
+}
+
+// APIError is a failed Kraken request
+type APIError struct {
+	Path    string
+	Status  int    // HTTP status (0 for network failures)
+	Message string // Kraken error or network error
+}
+
+// Error describes the failure
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("kraken %s", e.Path)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.Status)
+	}
+	return msg + ": " + e.Message
+}
+
+// Client fetches Kraken Futures perpetual klines from the public charts endpoints
+// Symbols may be given bare ("BTCUSD"), qualified ("KRAKEN:BTCUSD") or as products
+// ("PF_XBTUSD"); returned candles always carry the qualified symbol so they are stored and
+// cached apart from Binance data
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Kraken Futures API client
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.KrakenBaseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// candlesResponse is a charts response, oldest first
+// Prices are strings and volumes numbers; json.Number accepts either
+type candlesResponse struct {
+	Candles []struct {
+		Time   int64       `json:"time"` // Unix milliseconds
+		Open   json.Number `json:"open"`
+		High   json.Number `json:"high"`
+		Low    json.Number `json:"low"`
+		Close  json.Number `json:"close"`
+		Volume json.Number `json:"volume"` // Base currency
+	} `json:"candles"`
+}
+
+// GetServerTime returns Kraken's current server time, as stamped on the tickers response
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	var response struct {
+		Result     string `json:"result"`
+		ServerTime string `json:"serverTime"`
+	}
+	if err := c.get(ctx, "/derivatives/api/v3/tickers", url.Values{}, &response); err != nil {
+		return time.Time{}, err
+	}
+	serverTime, err := time.Parse(time.RFC3339Nano, response.ServerTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid server time %q", response.ServerTime)
+	}
+	return serverTime, nil
+}
+
+// GetKlinesOptimized fetches the most recent klines
+func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, time.Now(), limit)
+}
+
+// GetKlinesEndingAt fetches the limit klines opening at or before endTime, for backwards paging
+func (c *Client) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, endTime, limit)
+}
+
+// fetchKlines requests windows of candles backwards from endTime until limit klines are
+// collected or a window comes back empty, and converts them to candles, oldest first
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	resolution, ok := Resolution(interval)
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not available on Kraken", interval)
+	}
+	duration, _ := models.IntervalDuration(interval)
+
+	productID := ProductID(symbol)
+	key := models.QualifySymbol(models.ExchangeKraken, Symbol(productID))
+	path := "/api/charts/v1/trade/" + productID + "/" + resolution
+
+	if limit <= 0 || limit > maxKlineLimit {
+		limit = maxKlineLimit
+	}
+
+	// Windows are inclusive on both ends and aligned to the last bar opening at or before endTime
+	end := endTime.Truncate(duration)
+	candles := make([]models.Candle, 0, limit)
+	for len(candles) < limit {
+		pageSize := limit - len(candles)
+		if pageSize > maxCandlesPage {
+			pageSize = maxCandlesPage
+		}
+		start := end.Add(-time.Duration(pageSize-1) * duration)
+
+		params := url.Values{}
+		params.Set("from", strconv.FormatInt(start.Unix(), 10))
+		params.Set("to", strconv.FormatInt(end.Unix(), 10))
+
+		var response candlesResponse
+		if err := c.get(ctx, path, params, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Candles) == 0 {
+			break
+		}
+
+		for _, entry := range response.Candles {
+			openTime := time.UnixMilli(entry.Time)
+			if openTime.Before(start) || openTime.After(end) {
+				continue
+			}
+			candles = append(candles, models.Candle{
+				Symbol:    key,
+				OpenTime:  openTime,
+				Open:      entry.Open.String(),
+				High:      entry.High.String(),
+				Low:       entry.Low.String(),
+				Close:     entry.Close.String(),
+				Volume:    entry.Volume.String(),
+				CloseTime: openTime.Add(duration - time.Millisecond),
+				// Kraken charts carry no quote volume, trade count or taker buy volume
+				QuoteAssetVolume:         "0",
+				TakerBuyBaseAssetVolume:  "0",
+				TakerBuyQuoteAssetVolume: "0",
+				Interval:                 interval,
+				PriceType:                models.PriceTypeLast,
+			})
+		}
+		end = start.Add(-duration)
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	return candles, nil
+}
+
+// get performs a GET request and decodes a successful JSON response into out
+func (c *Client) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &APIError{Path: path, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		message := string(body)
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			message = apiErr.Error
+		}
+		return &APIError{Path: path, Status: resp.StatusCode, Message: message}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Package marketdata is the exchange-agnostic market data core, split in three layers joined by
+// interfaces:
+//   - ingest: the market data interface implemented by every exchange connector, a registry
+//     resolving a symbol to its exchange's provider, and the stream's recent liquidations
+//     (LiquidationSource)
+//   - store: candle, trade and footprint persistence (CandleStore, PriceCandleStore, TradeStore,
+//     FootprintStore), implemented by the repositories
+//   - aggregate: candle reads served to aggregation and analytics (CandleSource), implemented by
+//     services.CandleService
+//
+// Services depend on these interfaces rather than concrete repositories, and publish to clients
+// through internal/transport, so new transports and tools can reuse the core without import cycles
+package marketdata
+
+import (
+	"context"
+	"time"
+	"tterminal-backend/models"
+)
+
+// KlineSource fetches last price klines from an exchange's REST API
+// Candles returned carry the exchange-qualified symbol (see models.QualifySymbol)
+type KlineSource interface {
+	GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error)
+	GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error)
+}
+
+// EventSource delivers an exchange stream's normalized trades, book updates and liquidations
+// Implemented by websocket.MarketEvents
+type EventSource interface {
+	OnTrade(handler func(models.TradeRecord))
+	OnDepth(handler func(models.DepthUpdate))
+	OnLiquidation(handler func(models.LiquidationEvent))
+}
+
+// LiquidationSource holds the recent liquidations of an exchange stream, oldest first
+// Liquidations are not persisted, so only what the stream still holds is available
+// Implemented by websocket.BinanceStream
+type LiquidationSource interface {
+	GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent
+}
+
+// MarketDataProvider is the market data of one exchange: REST klines and live stream events
+// Stream handlers run on the exchange stream's read loop and must return quickly
+type MarketDataProvider interface {
+	// Exchange returns the exchange identifier (models.ExchangeBinance, models.ExchangeBybit, ...)
+	Exchange() string
+	// GetKlines returns the most recent limit klines, oldest first
+	GetKlines(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error)
+	// GetKlinesEndingAt returns up to limit klines opening at or before endTime, oldest first
+	GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error)
+	// StreamTrades registers a handler for every streamed trade
+	StreamTrades(handler func(models.TradeRecord))
+	// StreamDepth registers a handler for every streamed order book snapshot and update
+	StreamDepth(handler func(models.DepthUpdate))
+	// StreamLiquidations registers a handler for every streamed liquidation
+	StreamLiquidations(handler func(models.LiquidationEvent))
+}
+
+// provider joins an exchange's REST client with its stream events
+type provider struct {
+	exchange string
+	klines   KlineSource
+	events   EventSource
+}
+
+// NewProvider creates the provider of an exchange from its kline source and stream events
+// A nil events source makes the stream methods no-ops, for exchanges served over REST only
+func NewProvider(exchange string, klines KlineSource, events EventSource) MarketDataProvider {
+	return &provider{
+		exchange: exchange,
+		klines:   klines,
+		events:   events,
+	}
+}
+
+// Exchange returns the exchange identifier
+func (p *provider) Exchange() string {
+	return p.exchange
+}
+
+// GetKlines returns the most recent limit klines
+func (p *provider) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return p.klines.GetKlinesOptimized(ctx, symbol, interval, limit)
+}
+
+// GetKlinesEndingAt returns up to limit klines opening at or before endTime
+func (p *provider) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	return p.klines.GetKlinesEndingAt(ctx, symbol, interval, endTime, limit)
+}
+
+// StreamTrades registers a trade handler with the exchange stream
+func (p *provider) StreamTrades(handler func(models.TradeRecord)) {
+	if p.events != nil {
+		p.events.OnTrade(handler)
+	}
+}
+
+// StreamDepth registers a book handler with the exchange stream
+func (p *provider) StreamDepth(handler func(models.DepthUpdate)) {
+	if p.events != nil {
+		p.events.OnDepth(handler)
+	}
+}
+
+// StreamLiquidations registers a liquidation handler with the exchange stream
+func (p *provider) StreamLiquidations(handler func(models.LiquidationEvent)) {
+	if p.events != nil {
+		p.events.OnLiquidation(handler)
+	}
+}
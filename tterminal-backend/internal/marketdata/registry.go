@@ -0,0 +1,53 @@
+package marketdata
+
+import (
+	"sort"
+	"sync"
+	"tterminal-backend/models"
+)
+
+// Registry holds the market data provider of every enabled exchange
+// Services resolve symbols through it, so enabling an exchange only registers its provider
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]MarketDataProvider
+}
+
+// NewRegistry creates an empty provider registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]MarketDataProvider),
+	}
+}
+
+// Register adds a provider, replacing any earlier provider of the same exchange
+func (r *Registry) Register(provider MarketDataProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[provider.Exchange()] = provider
+}
+
+// Get returns the provider of an exchange, or nil when the exchange is not enabled
+func (r *Registry) Get(exchange string) MarketDataProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.providers[exchange]
+}
+
+// ForSymbol returns the provider of a symbol's exchange ("BYBIT:BTCUSDT" resolves to Bybit,
+// bare symbols to Binance), or nil when the exchange is not enabled
+func (r *Registry) ForSymbol(symbol string) MarketDataProvider {
+	return r.Get(models.SymbolExchange(symbol))
+}
+
+// Exchanges returns the registered exchanges, sorted
+func (r *Registry) Exchanges() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	exchanges := make([]string, 0, len(r.providers))
+	for exchange := range r.providers {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	return exchanges
+}
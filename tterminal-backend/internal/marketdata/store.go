@@ -0,0 +1,49 @@
+package marketdata
+
+import (
+	"context"
+	"time"
+	"tterminal-backend/models"
+)
+
+// CandleStore persists last price candles
+type CandleStore interface {
+	Create(ctx context.Context, candle *models.Candle) error
+	BulkCreate(ctx context.Context, candles []models.Candle) error
+	GetLatest(ctx context.Context, symbol, interval string) (*models.Candle, error)
+	GetBySymbolAndInterval(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error)
+	GetBeforeTime(ctx context.Context, symbol, interval string, before time.Time, limit int) ([]models.Candle, error)
+	GetByTimeRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.Candle, error)
+	GetOptimizedCandleData(ctx context.Context, symbol, interval string, limit int) ([]models.OptimizedCandle, error)
+	GetOptimizedCandleDataBefore(ctx context.Context, symbol, interval string, before time.Time, limit int) ([]models.OptimizedCandle, error)
+	GetSourceStats(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.CandleSourceStats, error)
+}
+
+// PriceCandleStore persists mark and index price candles, keyed by price type
+type PriceCandleStore interface {
+	BulkCreate(ctx context.Context, candles []models.Candle) error
+	GetBySymbolAndInterval(ctx context.Context, symbol, interval, priceType string, limit int) ([]models.Candle, error)
+	GetBeforeTime(ctx context.Context, symbol, interval, priceType string, before time.Time, limit int) ([]models.Candle, error)
+	GetByTimeRange(ctx context.Context, symbol, interval, priceType string, startTime, endTime time.Time) ([]models.Candle, error)
+}
+
+// TradeStore persists streamed trades
+type TradeStore interface {
+	BulkCreate(ctx context.Context, trades []models.TradeRecord) error
+	GetByTimeRange(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]models.TradeRecord, error)
+}
+
+// FootprintStore persists the footprint levels of closed bars, built from stored trades
+type FootprintStore interface {
+	UpsertFromTrades(ctx context.Context, symbol, interval string, openTime, closeTime time.Time, bucketSize float64) (int64, error)
+	GetRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.FootprintCandle, error)
+	GetLatest(ctx context.Context, symbol, interval string, limit int) ([]models.FootprintCandle, error)
+}
+
+// CandleSource serves candles to the aggregation layer, from storage or an exchange on a miss
+type CandleSource interface {
+	GetBySymbolAndInterval(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error)
+	GetByTimeRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.Candle, error)
+	GetOptimizedCandleData(ctx context.Context, symbol, interval string, limit int) ([]models.OptimizedCandle, error)
+	GetOptimizedCandleDataBefore(ctx context.Context, symbol, interval string, before time.Time, limit int) ([]models.OptimizedCandle, error)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireAdmin protects admin endpoints with the X-Admin-Token header, or an access token of an
+// admin account. Without a configured ADMIN_TOKEN, admin endpoints are open in the dev profile
+// and otherwise only to admin accounts
+func RequireAdmin(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if AuthenticatedRole(c) == models.RoleAdmin {
+				return next(c)
+			}
+			if cfg.AdminToken == "" {
+				if cfg.Profile == config.ProfileDev {
+					return next(c)
+				}
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Admin endpoints are disabled",
+					"code":  "ADMIN_DISABLED",
+				})
+			}
+
+			if !adminTokenValid(cfg, c) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid or missing X-Admin-Token",
+					"code":  "ADMIN_UNAUTHORIZED",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// adminTokenValid reports whether the request carries the configured X-Admin-Token
+func adminTokenValid(cfg *config.Config, c echo.Context) bool {
+	if cfg.AdminToken == "" {
+		return false
+	}
+	token := c.Request().Header.Get("X-Admin-Token")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(cfg.AdminToken)) == 1
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/audit"
+	"tterminal-backend/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxAuditPayload is the largest request body stored in the audit log; larger bodies are omitted
+const maxAuditPayload = 64 << 10
+
+// AuditRecorder stores audited requests
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *models.AuditLogEntry)
+}
+
+// AuditLog records every state-changing request (POST, PUT, PATCH, DELETE) once it completes,
+// whatever its outcome: the actor, route, status, the JSON request body with credentials
+// redacted, and the fields changed when the handler records its resource (see internal/audit).
+// Routes under an AUDIT_LOG_EXCLUDE_ROUTES prefix (read-only POSTs, polling) are not recorded
+func AuditLog(cfg *config.Config, recorder AuditRecorder) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			switch req.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				return next(c)
+			}
+			for _, prefix := range cfg.AuditExcludeRoutes {
+				if strings.HasPrefix(c.Path(), prefix) {
+					return next(c)
+				}
+			}
+
+			started := time.Now()
+			payload := auditPayload(req)
+			ctx, change := audit.WithChange(req.Context())
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				// Let the error handler write the response so its status is recorded
+				c.Error(err)
+			}
+
+			entry := &models.AuditLogEntry{
+				ActorRole:  AuthenticatedRole(c),
+				ClientIP:   c.RealIP(),
+				Method:     req.Method,
+				Route:      c.Path(),
+				Path:       req.URL.Path,
+				Query:      auditQuery(c.Request()),
+				Status:     c.Response().Status,
+				RequestID:  c.Response().Header().Get(echo.HeaderXRequestID),
+				Payload:    payload,
+				DurationMs: time.Since(started).Milliseconds(),
+				CreatedAt:  started,
+			}
+			switch {
+			case AuthenticatedUser(c) != "":
+				entry.Actor, entry.ActorType = AuthenticatedUser(c), models.AuditActorUser
+			case adminTokenValid(cfg, c):
+				entry.ActorType = models.AuditActorAdminToken
+			case requestIdentity(c) != "":
+				entry.Actor, entry.ActorType = requestIdentity(c), models.AuditActorHeader
+			default:
+				entry.ActorType = models.AuditActorAnonymous
+			}
+			if change.Recorded() {
+				entry.Resource, entry.ResourceID = change.Resource, change.ResourceID
+				entry.Diff = change.Diff()
+			}
+
+			recorder.Record(ctx, entry)
+			return err
+		}
+	}
+}
+
+// auditPayload reads the JSON request body for the audit log with credentials redacted, leaving
+// the body for the handler. Bodies that are not JSON or exceed maxAuditPayload are not stored
+func auditPayload(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(req.Body, maxAuditPayload+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), req.Body), req.Body}
+	if err != nil || len(head) == 0 || len(head) > maxAuditPayload {
+		return nil
+	}
+
+	redacted, ok := audit.RedactJSON(head)
+	if !ok {
+		return nil
+	}
+	return redacted
+}
+
+// auditQuery returns the request's query string without an access token (one left there when
+// JWT_SECRET is unset and Authenticate did not consume it)
+func auditQuery(req *http.Request) string {
+	query := req.URL.Query()
+	if !query.Has("access_token") {
+		return req.URL.RawQuery
+	}
+	query.Set("access_token", audit.Redacted)
+	return query.Encode()
+}
+
+// requestIdentity returns the caller's user ID as sent, verified or not, for the audit log
+func requestIdentity(c echo.Context) string {
+	if userID := c.Request().Header.Get("X-User-ID"); userID != "" {
+		return userID
+	}
+	return c.QueryParam("user_id")
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/auth"
+	"tterminal-backend/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Request context keys of a verified access token
+const (
+	authenticatedUserKey  = "authenticated_user"        // User ID
+	authenticatedRoleKey  = "authenticated_role"        // models.Role*
+	authenticatedPermsKey = "authenticated_permissions" // models.Permission*; nil for every one
+)
+
+// Authenticate verifies bearer access tokens when JWT_SECRET is set
+// A valid token's user replaces any X-User-ID header or user_id query parameter, so every handler
+// reading the caller's identity (portfolios, compliance, WebSocket clients) sees the authenticated
+// user; without a token both are removed, since anyone could send them, and an invalid or expired
+// token is refused. WebSocket clients, which cannot set headers, pass the token as the
+// access_token query parameter
+func Authenticate(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if cfg.JWTSecret == "" {
+			return next
+		}
+		secret := []byte(cfg.JWTSecret)
+
+		return func(c echo.Context) error {
+			req := c.Request()
+			query := req.URL.Query()
+			token := bearerToken(req.Header.Get(echo.HeaderAuthorization))
+			if token == "" {
+				token = query.Get("access_token")
+			}
+			if token == "" {
+				req.Header.Del("X-User-ID")
+				if query.Has("user_id") {
+					query.Del("user_id")
+					req.URL.RawQuery = query.Encode()
+				}
+				return next(c)
+			}
+
+			claims, err := auth.Verify(token, secret, time.Now())
+			if err != nil {
+				code := "INVALID_TOKEN"
+				if err == auth.ErrExpiredToken {
+					code = "TOKEN_EXPIRED"
+				}
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid access token: " + err.Error(),
+					"code":  code,
+				})
+			}
+
+			role := claims.Role
+			if role == "" {
+				role = models.RoleUser
+			}
+			c.Set(authenticatedUserKey, claims.Subject)
+			c.Set(authenticatedRoleKey, role)
+			c.Set(authenticatedPermsKey, claims.Permissions)
+			req.Header.Set("X-User-ID", claims.Subject)
+			query.Del("access_token")
+			query.Set("user_id", claims.Subject)
+			req.URL.RawQuery = query.Encode()
+			return next(c)
+		}
+	}
+}
+
+// RequireUser protects user-scoped routes: with JWT_SECRET set they need a valid access token,
+// and readonly accounts may only read; without it the X-User-ID header is trusted as before
+// (only allowed outside prod)
+func RequireUser(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if cfg.JWTSecret == "" {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			if AuthenticatedUser(c) == "" {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error":   "Authentication required",
+					"message": "Log in at /api/v1/auth/login and send the token in the Authorization: Bearer header",
+					"code":    "AUTHENTICATION_REQUIRED",
+				})
+			}
+			if method := c.Request().Method; method != http.MethodGet && method != http.MethodHead &&
+				!models.RoleAtLeast(AuthenticatedRole(c), models.RoleUser) {
+				return roleRequired(c, models.RoleUser)
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireRole guards operational routes with a minimum role. Callers pass with an access token
+// whose role grants it, or with the X-Admin-Token as an admin. When neither JWT_SECRET nor
+// ADMIN_TOKEN is set, the routes are open in the dev profile and disabled otherwise
+func RequireRole(cfg *config.Config, role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if userRole := AuthenticatedRole(c); userRole != "" {
+				if !models.RoleAtLeast(userRole, role) {
+					return roleRequired(c, role)
+				}
+				return next(c)
+			}
+			if adminTokenValid(cfg, c) {
+				return next(c)
+			}
+
+			if cfg.JWTSecret == "" && cfg.AdminToken == "" {
+				if cfg.Profile == config.ProfileDev {
+					return next(c)
+				}
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Operational endpoints are disabled; set JWT_SECRET or ADMIN_TOKEN",
+					"code":  "ADMIN_DISABLED",
+				})
+			}
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error":   "Authentication required",
+				"message": fmt.Sprintf("Requires an access token with the %s role, or the X-Admin-Token header", role),
+				"code":    "AUTHENTICATION_REQUIRED",
+			})
+		}
+	}
+}
+
+// RequirePermission guards a data collection action with the admin role and one permission
+// Admins whose token lists their permissions need this one; the X-Admin-Token holds every one
+func RequirePermission(cfg *config.Config, permission string) echo.MiddlewareFunc {
+	requireAdmin := RequireRole(cfg, models.RoleAdmin)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		guarded := requireAdmin(next)
+		return func(c echo.Context) error {
+			if role := AuthenticatedRole(c); role == models.RoleAdmin && !models.HasPermission(role, authenticatedPermissions(c), permission) {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": fmt.Sprintf("The %s permission is required", permission),
+					"code":  "PERMISSION_REQUIRED",
+				})
+			}
+			return guarded(c)
+		}
+	}
+}
+
+// authenticatedPermissions returns the permissions of the request's verified access token
+func authenticatedPermissions(c echo.Context) []string {
+	permissions, _ := c.Get(authenticatedPermsKey).([]string)
+	return permissions
+}
+
+// AuthenticatedRole returns the role of the request's verified access token, if any
+func AuthenticatedRole(c echo.Context) string {
+	role, _ := c.Get(authenticatedRoleKey).(string)
+	return role
+}
+
+// roleRequired refuses an authenticated caller whose role does not grant the route's
+func roleRequired(c echo.Context, role string) error {
+	return c.JSON(http.StatusForbidden, map[string]string{
+		"error": fmt.Sprintf("The %s role is required", role),
+		"code":  "ROLE_REQUIRED",
+	})
+}
+
+// AuthenticatedUser returns the user ID of the request's verified access token, if any
+func AuthenticatedUser(c echo.Context) string {
+	userID, _ := c.Get(authenticatedUserKey).(string)
+	return userID
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header
+func bearerToken(header string) string {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SymbolResolver resolves canonical symbols ("BTC-PERP") to an exchange's symbol key
+// Symbols that are not canonical are returned unchanged with resolved false
+type SymbolResolver interface {
+	ResolveSymbol(symbol, exchange string) (key string, resolved bool, err error)
+}
+
+// CanonicalSymbol rewrites a canonical symbol in the "symbol" path or query parameter to the
+// symbol key of the exchange named by the "exchange" query parameter (Binance when omitted), so
+// handlers only ever see exchange symbols. The resolved key is echoed in X-Resolved-Symbol
+func CanonicalSymbol(resolver SymbolResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			exchange := c.QueryParam("exchange")
+
+			names, values := c.ParamNames(), c.ParamValues()
+			for i, name := range names {
+				if name != "symbol" || i >= len(values) {
+					continue
+				}
+				key, resolved, err := resolver.ResolveSymbol(values[i], exchange)
+				if err != nil {
+					return unresolvedSymbol(c, err)
+				}
+				if resolved {
+					values[i] = key
+					c.SetParamValues(values...)
+					c.Response().Header().Set("X-Resolved-Symbol", key)
+				}
+			}
+
+			if symbol := c.QueryParam("symbol"); symbol != "" {
+				key, resolved, err := resolver.ResolveSymbol(symbol, exchange)
+				if err != nil {
+					return unresolvedSymbol(c, err)
+				}
+				if resolved {
+					c.QueryParams().Set("symbol", key)
+					c.Response().Header().Set("X-Resolved-Symbol", key)
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// unresolvedSymbol answers a request for a canonical symbol that is not mapped on the exchange
+func unresolvedSymbol(c echo.Context, err error) error {
+	return c.JSON(http.StatusNotFound, map[string]string{
+		"error": err.Error(),
+		"code":  "SYMBOL_NOT_MAPPED",
+	})
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"tterminal-backend/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireIdentity rejects anonymous market data requests when compliance mode disallows them
+// Callers identify with an access token (see Authenticate, which drops unverified identities);
+// config validation requires JWT_SECRET for this, so the X-User-ID header alone is never trusted
+func RequireIdentity(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !cfg.ComplianceMode || cfg.ComplianceAllowAnonymous {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			if AuthenticatedUser(c) == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error":   "Anonymous access is disabled",
+					"message": "Log in at /api/v1/auth/login and send the access token; market data is licensed per user on this deployment",
+					"code":    "ANONYMOUS_ACCESS_DISABLED",
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// DataExport gates raw-data export endpoints in compliance mode
+// Exports are refused unless allowed for the deployment; allowed exports carry a watermark
+// naming the deployment and requesting user so redistributed copies can be traced
+func DataExport(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !cfg.ComplianceMode {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			if !cfg.ComplianceAllowExports {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error":   "Raw data export is disabled",
+					"message": "This deployment does not redistribute raw exchange data",
+					"code":    "EXPORT_DISABLED",
+				})
+			}
+
+			user := AuthenticatedUser(c)
+			if user == "" {
+				user = "anonymous"
+			}
+			c.Response().Header().Set("X-Deployment-ID", cfg.DeploymentID)
+			c.Response().Header().Set("X-Data-Watermark", fmt.Sprintf("deployment=%s; user=%s; issued=%s",
+				cfg.DeploymentID, user, time.Now().UTC().Format(time.RFC3339)))
+			return next(c)
+		}
+	}
+}
@@ -12,8 +12,8 @@ func CORS(cfg *config.Config) echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     []string{"*"}, // Configure properly for production
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "Cache-Control", "Pragma"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "Cache-Control", "Pragma", "X-Request-ID", "X-Traffic-Class"},
+		ExposeHeaders:    []string{"Content-Length", "X-Data-Age", "X-Data-Watermark", "X-Deployment-ID", "X-Request-ID", "X-Traffic-Class"},
 		AllowCredentials: true,
 	})
 }
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// ipLimiterIdle is how long an address may go without requests before its limiter is dropped
+const ipLimiterIdle = 10 * time.Minute
+
+// ipLimiter is one address's token bucket and when it was last used
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// IPRateLimit applies a separate token bucket per client address, for endpoints that are
+// open to anonymous callers and must not be able to exhaust the shared limit
+func IPRateLimit(perMinute, burst int) echo.MiddlewareFunc {
+	var mu sync.Mutex
+	limiters := make(map[string]*ipLimiter)
+
+	// Forget idle addresses so the map does not grow without bound
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			mu.Lock()
+			for ip, entry := range limiters {
+				if time.Since(entry.lastSeen) > ipLimiterIdle {
+					delete(limiters, ip)
+				}
+			}
+			mu.Unlock()
+		}
+	}()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ip := c.RealIP()
+
+			mu.Lock()
+			entry, exists := limiters[ip]
+			if !exists {
+				entry = &ipLimiter{limiter: rate.NewLimiter(rate.Limit(float64(perMinute)/60), burst)}
+				limiters[ip] = entry
+			}
+			entry.lastSeen = time.Now()
+			allowed := entry.limiter.Allow()
+			mu.Unlock()
+
+			if !allowed {
+				return c.JSON(http.StatusTooManyRequests, map[string]string{
+					"error":   "Rate limit exceeded",
+					"message": "Too many requests from this address, please try again later",
+				})
+			}
+			return next(c)
+		}
+	}
+}
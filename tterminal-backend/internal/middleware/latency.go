@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/sla"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Latency records how long each request takes, from receipt to its response, per method and
+// route pattern (GET /debug/sla). Routes under an SLA_EXCLUDE_ROUTES prefix (WebSocket upgrades,
+// long polls) and unmatched routes are not recorded
+func Latency(cfg *config.Config, tracker *sla.Tracker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			started := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" || err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+				return err
+			}
+			for _, prefix := range cfg.SLAExcludeRoutes {
+				if strings.HasPrefix(route, prefix) {
+					return err
+				}
+			}
+			tracker.Observe(c.Request().Method+" "+route, time.Since(started))
+			return err
+		}
+	}
+}
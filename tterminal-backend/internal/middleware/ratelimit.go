@@ -2,19 +2,98 @@ package middleware
 
 import (
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 	"tterminal-backend/config"
+	"tterminal-backend/internal/traffic"
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/time/rate"
 )
 
-// RateLimit applies rate limiting to requests using Echo
+// RateLimit applies a token bucket per identity and route group to requests using Echo
+// The identity is the verified access token's user (see Authenticate), or the client address for
+// anonymous requests; unverified X-User-ID headers are not trusted, so they cannot be rotated to
+// escape the limit. Routes under a RATE_LIMIT_ROUTES prefix (the longest matching one) get their
+// own bucket, others share the default bucket, which follows RATE_LIMIT_* reloads without being
+// reset. Batch requests (see TrafficClass) are refused once a bucket falls below
+// TRAFFIC_RATE_LIMIT_RESERVE of its burst, keeping the rest for interactive requests
 func RateLimit(cfg *config.Config) echo.MiddlewareFunc {
-	limiter := rate.NewLimiter(rate.Limit(cfg.RateLimitRPS), cfg.RateLimitBurst)
+	reserve := cfg.TrafficRateReserve
+	routes := append([]config.RouteRateLimit(nil), cfg.RateLimitRoutes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].Prefix) > len(routes[j].Prefix)
+	})
+
+	var mu sync.Mutex
+	settings := cfg.Runtime().Settings()
+	defaultLimit := rate.Limit(settings.RateLimitRPS)
+	defaultBurst := settings.RateLimitBurst
+	cfg.Runtime().OnChange(func(settings config.RuntimeSettings) {
+		mu.Lock()
+		defaultLimit = rate.Limit(settings.RateLimitRPS)
+		defaultBurst = settings.RateLimitBurst
+		mu.Unlock()
+	})
+
+	// Buckets by "<route prefix>|<identity>", forgotten once idle so the map does not grow without bound
+	limiters := make(map[string]*ipLimiter)
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			mu.Lock()
+			for key, entry := range limiters {
+				if time.Since(entry.lastSeen) > ipLimiterIdle {
+					delete(limiters, key)
+				}
+			}
+			mu.Unlock()
+		}
+	}()
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			if !limiter.Allow() {
+			identity := "ip:" + c.RealIP()
+			if userID := AuthenticatedUser(c); userID != "" {
+				identity = "user:" + userID
+			}
+
+			group := ""
+			mu.Lock()
+			limit, burst := defaultLimit, defaultBurst
+			for _, route := range routes {
+				if strings.HasPrefix(c.Path(), route.Prefix) {
+					group, limit, burst = route.Prefix, rate.Limit(route.RPS), route.Burst
+					break
+				}
+			}
+
+			key := group + "|" + identity
+			entry, exists := limiters[key]
+			if !exists {
+				entry = &ipLimiter{limiter: rate.NewLimiter(limit, burst)}
+				limiters[key] = entry
+			} else if entry.limiter.Limit() != limit || entry.limiter.Burst() != burst {
+				// A reload changed the default bucket
+				entry.limiter.SetLimit(limit)
+				entry.limiter.SetBurst(burst)
+			}
+			entry.lastSeen = time.Now()
+
+			reserved := traffic.FromContext(c.Request().Context()) == traffic.Batch && entry.limiter.Tokens() < reserve*float64(burst)
+			allowed := !reserved && entry.limiter.Allow()
+			mu.Unlock()
+
+			if reserved {
+				return c.JSON(http.StatusTooManyRequests, map[string]string{
+					"error":   "Rate limit exceeded",
+					"message": "Remaining capacity is reserved for interactive requests, please retry batch requests later",
+				})
+			}
+			if !allowed {
 				return c.JSON(http.StatusTooManyRequests, map[string]string{
 					"error":   "Rate limit exceeded",
 					"message": "Too many requests, please try again later",
@@ -24,4 +103,3 @@ func RateLimit(cfg *config.Config) echo.MiddlewareFunc {
 		}
 	}
 }
- 
\ No newline at end of file
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"tterminal-backend/internal/binance"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestID assigns each request an X-Request-ID (reusing the caller's, if sent) and carries it
+// in the request context so upstream Binance errors can be correlated with the API request
+func RequestID() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			c.SetRequest(c.Request().WithContext(binance.WithRequestID(c.Request().Context(), requestID)))
+		},
+	})
+}
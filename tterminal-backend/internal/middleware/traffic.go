@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/traffic"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TrafficClass tags each request interactive or batch and carries the class in the request
+// context. Routes under TRAFFIC_BATCH_ROUTES are always batch; any other request is batch when
+// it sends "X-Traffic-Class: batch". The class applied is reported in the same response header
+// It must run after routing and before RateLimit, which reads the class
+func TrafficClass(cfg *config.Config) echo.MiddlewareFunc {
+	batchRoutes := cfg.TrafficBatchRoutes
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			class := traffic.Interactive
+			if requested, ok := traffic.ParseClass(c.Request().Header.Get(traffic.Header)); ok {
+				class = requested
+			}
+			for _, prefix := range batchRoutes {
+				if strings.HasPrefix(c.Path(), prefix) {
+					class = traffic.Batch
+					break
+				}
+			}
+
+			c.SetRequest(c.Request().WithContext(traffic.WithClass(c.Request().Context(), class)))
+			c.Response().Header().Set(traffic.Header, string(class))
+			return next(c)
+		}
+	}
+}
+
+// BatchConcurrency holds a slot of gate for the whole of every batch request, so only the
+// gate's batch slots run at once and the rest of the database pool stays free for interactive
+// requests. Interactive requests pass straight through
+func BatchConcurrency(gate *traffic.Gate) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if traffic.FromContext(c.Request().Context()) != traffic.Batch {
+				return next(c)
+			}
+
+			release, err := gate.Acquire(c.Request().Context())
+			if err != nil {
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{
+					"error": "Request cancelled while queued behind other batch requests",
+				})
+			}
+			defer release()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package multicast publishes price and trade ticks as compact binary UDP datagrams, for
+// co-located consumers on a trusted network that cannot afford the WebSocket hub's JSON encoding
+// and per-client queues. Datagrams go to one multicast group (or unicast address) and are never
+// retransmitted: consumers detect loss from gaps in the sequence number
+//
+// Datagram layout, big-endian:
+//
+//	offset size field
+//	0      2    magic "TT"
+//	2      1    version (1)
+//	3      1    type: 1 price, 2 trade
+//	4      8    sequence number, per publisher, starting at 1
+//	12     8    event time, Unix nanoseconds
+//	20     8    price, IEEE 754 float64
+//	28     8    quantity, IEEE 754 float64 (0 for prices)
+//	36     8    trade ID (0 for prices)
+//	44     1    flags: bit 0 aggressive seller (buyer is maker), bit 1 spot market
+//	45     1    symbol length n
+//	46     n    symbol key, ASCII (e.g. "BTCUSDT", "BYBIT:BTCUSDT")
+package multicast
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/models"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	// Version is the datagram layout version
+	Version = 1
+
+	// Tick types
+	TypePrice = 1
+	TypeTrade = 2
+
+	// Flags
+	FlagSellerAggressor = 1 << 0
+	FlagSpot            = 1 << 1
+
+	// HeaderSize is the size of a datagram before its symbol
+	HeaderSize = 46
+
+	// queueSize bounds the ticks waiting to be sent
+	queueSize = 65536
+)
+
+// tick is a price or trade waiting to be sent
+type tick struct {
+	kind     byte
+	flags    byte
+	symbol   string
+	price    float64
+	quantity float64
+	tradeID  int64
+	time     time.Time
+}
+
+// Publisher sends ticks of the configured symbols to a UDP destination off the streams' read loops
+type Publisher struct {
+	conn    net.PacketConn
+	dest    *net.UDPAddr
+	symbols map[string]bool // Symbol keys published, all when empty
+
+	queue    chan tick
+	sequence uint64
+	dropped  int64
+	failed   int64
+
+	mu        sync.Mutex
+	stopChan  chan struct{}
+	isRunning bool
+}
+
+// NewPublisher opens a UDP socket towards addr ("host:port"). For a multicast group, ttl bounds
+// the routers datagrams may cross (1 keeps them on the local network) and iface, when set, names
+// the network interface they leave from. symbols restricts the published symbol keys
+func NewPublisher(addr, iface string, ttl int, symbols []string) (*Publisher, error) {
+	dest, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid multicast address %q: %w", addr, err)
+	}
+
+	network, local := "udp4", "0.0.0.0:0"
+	if dest.IP.To4() == nil {
+		network, local = "udp6", "[::]:0"
+	}
+	conn, err := net.ListenPacket(network, local)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open multicast socket: %w", err)
+	}
+
+	if dest.IP.IsMulticast() {
+		if err := configureMulticast(conn, dest, iface, ttl); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	allowed := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		allowed[symbol] = true
+	}
+
+	return &Publisher{
+		conn:     conn,
+		dest:     dest,
+		symbols:  allowed,
+		queue:    make(chan tick, queueSize),
+		stopChan: make(chan struct{}),
+	}, nil
+}
+
+// configureMulticast sets the hop limit and outgoing interface of a multicast socket
+func configureMulticast(conn net.PacketConn, dest *net.UDPAddr, iface string, ttl int) error {
+	var ifi *net.Interface
+	if iface != "" {
+		var err error
+		if ifi, err = net.InterfaceByName(iface); err != nil {
+			return fmt.Errorf("unknown multicast interface %q: %w", iface, err)
+		}
+	}
+
+	if dest.IP.To4() != nil {
+		pc := ipv4.NewPacketConn(conn)
+		if err := pc.SetMulticastTTL(ttl); err != nil {
+			return fmt.Errorf("failed to set multicast TTL: %w", err)
+		}
+		if ifi != nil {
+			if err := pc.SetMulticastInterface(ifi); err != nil {
+				return fmt.Errorf("failed to set multicast interface: %w", err)
+			}
+		}
+		return nil
+	}
+
+	pc := ipv6.NewPacketConn(conn)
+	if err := pc.SetMulticastHopLimit(ttl); err != nil {
+		return fmt.Errorf("failed to set multicast hop limit: %w", err)
+	}
+	if ifi != nil {
+		if err := pc.SetMulticastInterface(ifi); err != nil {
+			return fmt.Errorf("failed to set multicast interface: %w", err)
+		}
+	}
+	return nil
+}
+
+// Start starts sending queued ticks
+func (p *Publisher) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isRunning {
+		return fmt.Errorf("multicast publisher is already running")
+	}
+	p.isRunning = true
+	go p.run()
+
+	log.Printf("[Multicast] Publishing ticks to %s", p.dest)
+	return nil
+}
+
+// Stop stops sending and closes the socket
+func (p *Publisher) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.isRunning {
+		return
+	}
+	p.isRunning = false
+	close(p.stopChan)
+	p.conn.Close()
+}
+
+// PublishPrice queues a last price change without blocking
+func (p *Publisher) PublishPrice(price models.PriceTick) {
+	var flags byte
+	if price.Market == "spot" {
+		flags |= FlagSpot
+	}
+	p.enqueue(tick{kind: TypePrice, flags: flags, symbol: price.Symbol, price: price.Price, time: price.Time})
+}
+
+// PublishTrade queues a trade without blocking
+func (p *Publisher) PublishTrade(trade models.TradeRecord) {
+	var flags byte
+	if trade.IsBuyerMaker {
+		flags |= FlagSellerAggressor
+	}
+	p.enqueue(tick{kind: TypeTrade, flags: flags, symbol: trade.Symbol, price: trade.Price,
+		quantity: trade.Quantity, tradeID: trade.TradeID, time: trade.TradeTime})
+}
+
+// enqueue queues a tick of a published symbol; ticks are dropped if the socket falls behind
+func (p *Publisher) enqueue(t tick) {
+	if len(p.symbols) > 0 && !p.symbols[t.symbol] {
+		return
+	}
+
+	select {
+	case p.queue <- t:
+	default:
+		if dropped := atomic.AddInt64(&p.dropped, 1); dropped%1000 == 1 {
+			log.Printf("[Multicast] WARNING: queue full - %d ticks dropped so far", dropped)
+		}
+	}
+}
+
+// run sends queued ticks, one datagram each, until stopped
+func (p *Publisher) run() {
+	buf := make([]byte, HeaderSize+math.MaxUint8)
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case t := <-p.queue:
+			p.sequence++
+			n := Encode(buf, p.sequence, t.kind, t.flags, t.symbol, t.price, t.quantity, t.tradeID, t.time)
+			if _, err := p.conn.WriteTo(buf[:n], p.dest); err != nil {
+				if failed := atomic.AddInt64(&p.failed, 1); failed%1000 == 1 {
+					log.Printf("[Multicast] ERROR sending to %s (%d failures so far): %v", p.dest, failed, err)
+				}
+			}
+		}
+	}
+}
+
+// Encode writes one datagram into buf, which must hold HeaderSize+255 bytes, and returns its
+// length. Symbols longer than 255 bytes are truncated
+func Encode(buf []byte, sequence uint64, kind, flags byte, symbol string, price, quantity float64, tradeID int64, at time.Time) int {
+	if len(symbol) > math.MaxUint8 {
+		symbol = symbol[:math.MaxUint8]
+	}
+
+	buf[0], buf[1] = 'T', 'T'
+	buf[2] = Version
+	buf[3] = kind
+	binary.BigEndian.PutUint64(buf[4:], sequence)
+	binary.BigEndian.PutUint64(buf[12:], uint64(at.UnixNano()))
+	binary.BigEndian.PutUint64(buf[20:], math.Float64bits(price))
+	binary.BigEndian.PutUint64(buf[28:], math.Float64bits(quantity))
+	binary.BigEndian.PutUint64(buf[36:], uint64(tradeID))
+	buf[44] = flags
+	buf[45] = byte(len(symbol))
+	return HeaderSize + copy(buf[HeaderSize:], symbol)
+}
@@ -0,0 +1,289 @@
+// Package okx provides an OKX v5 REST client for USDT-margined perpetual swap market data
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+)
+
+const (
+	// candlesPath serves the most recent 1440 bars; historyCandlesPath serves older bars as well
+	candlesPath        = "/api/v5/market/candles"
+	historyCandlesPath = "/api/v5/market/history-candles"
+	// maxCandlesPage and maxHistoryPage are the largest pages OKX returns per request
+	maxCandlesPage = 300
+	maxHistoryPage = 100
+	// maxKlineLimit caps the klines assembled from pages per call
+	maxKlineLimit = 1000
+	// swapSuffix marks perpetual swap instruments
+	swapSuffix = "-SWAP"
+)
+
+// bars maps API intervals to OKX bars; daily and longer bars use the UTC-aligned variants so they
+// open with Binance's. OKX has no 8h or 3d bars matching Binance's alignment
+var bars = map[string]string{
+	"1m": "1m", "3m": "3m", "5m": "5m", "15m": "15m", "30m": "30m",
+	"1h": "1H", "2h": "2H", "4h": "4H", "6h": "6Hutc", "12h": "12Hutc",
+	"1d": "1Dutc", "1w": "1Wutc", "1M": "1Mutc",
+}
+
+// quoteCurrencies are the quote currencies recognised when splitting a bare symbol, longest first
+var quoteCurrencies = []string{"USDT", "USDC", "USD"}
+
+// Bar returns the OKX bar for an API interval
+func Bar(interval string) (string, bool) {
+	bar, ok := bars[interval]
+	return bar, ok
+}
+
+// InstrumentID returns the perpetual swap instrument of a bare or qualified symbol
+// ("BTCUSDT" or "OKX:BTCUSDT" becomes "BTC-USDT-SWAP"); instrument IDs are returned unchanged
+func InstrumentID(symbol string) string {
+	_, symbol = models.SplitSymbol(symbol)
+	symbol = strings.ToUpper(symbol)
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+	for _, quote := range quoteCurrencies {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base + "-" + quote + swapSuffix
+		}
+	}
+	return symbol + swapSuffix
+}
+
+// Symbol returns the bare symbol of a swap instrument ("BTC-USDT-SWAP" becomes "BTCUSDT")
+func Symbol(instID string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(instID, swapSuffix), "-", "")
+}
+
+// IndexID returns the index instrument a swap is priced from ("BTC-USDT-SWAP" becomes "BTC-USDT")
+func IndexID(instID string) string {
+	return strings.TrimSuffix(instID, swapSuffix)
+}
+
+// APIError is a failed OKX request: a non-200 response or a non-zero code
+type APIError struct {
+	Path    string
+	Status  int    // HTTP status (0 for network failures)
+	Code    string // OKX error code
+	Message string // OKX msg or network error
+}
+
+// Error describes the failure
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("okx %s", e.Path)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (status %d", e.Status)
+		if e.Code != "" && e.Code != "0" {
+			msg += ", code " + e.Code
+		}
+		msg += ")"
+	}
+	return msg + ": " + e.Message
+}
+
+// Client fetches OKX perpetual swap klines and instrument details
+// Symbols may be given bare ("BTCUSDT"), qualified ("OKX:BTCUSDT") or as instruments
+// ("BTC-USDT-SWAP"); returned candles always carry the qualified symbol so they are stored and
+// cached apart from Binance data
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new OKX API client
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.OKXBaseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// envelope is the common v5 response wrapper
+type envelope struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// GetServerTime returns OKX's current server time
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	response, err := c.get(ctx, "/api/v5/public/time", url.Values{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	var data []struct {
+		Ts string `json:"ts"`
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil || len(data) == 0 {
+		return time.Time{}, fmt.Errorf("failed to decode server time")
+	}
+	ms, err := strconv.ParseInt(data[0].Ts, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid server time %q", data[0].Ts)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// GetContractValues returns the base currency amount of one contract per swap instrument
+// Trade, book and liquidation sizes on OKX swaps are in contracts
+func (c *Client) GetContractValues(ctx context.Context, symbols []string) (map[string]float64, error) {
+	values := make(map[string]float64, len(symbols))
+	for _, symbol := range symbols {
+		instID := InstrumentID(symbol)
+		params := url.Values{}
+		params.Set("instType", "SWAP")
+		params.Set("instId", instID)
+
+		response, err := c.get(ctx, "/api/v5/public/instruments", params)
+		if err != nil {
+			return nil, err
+		}
+		var data []struct {
+			CtVal string `json:"ctVal"`
+		}
+		if err := json.Unmarshal(response.Data, &data); err != nil || len(data) == 0 {
+			return nil, fmt.Errorf("instrument %s not found", instID)
+		}
+		values[instID] = models.ParseFloat(data[0].CtVal)
+	}
+	return values, nil
+}
+
+// GetKlinesOptimized fetches the most recent klines
+func (c *Client) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return c.fetchKlines(ctx, symbol, interval, 0, limit)
+}
+
+// GetKlinesEndingAt fetches the limit klines opening at or before endTime, for backwards paging
+func (c *Client) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	// OKX's "after" cursor is exclusive
+	return c.fetchKlines(ctx, symbol, interval, endTime.UnixMilli()+1, limit)
+}
+
+// fetchKlines pages backwards from before (0 = the latest bar) until limit klines are
+// collected or history runs out, and converts them to candles, oldest first
+func (c *Client) fetchKlines(ctx context.Context, symbol, interval string, before int64, limit int) ([]models.Candle, error) {
+	bar, ok := Bar(interval)
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not available on OKX", interval)
+	}
+	duration, _ := models.IntervalDuration(interval)
+
+	instID := InstrumentID(symbol)
+	key := models.QualifySymbol(models.ExchangeOKX, Symbol(instID))
+
+	if limit <= 0 || limit > maxKlineLimit {
+		limit = maxKlineLimit
+	}
+
+	candles := make([]models.Candle, 0, limit)
+	for len(candles) < limit {
+		path, pageSize := candlesPath, maxCandlesPage
+		params := url.Values{}
+		if before > 0 {
+			path, pageSize = historyCandlesPath, maxHistoryPage
+			params.Set("after", strconv.FormatInt(before, 10))
+		}
+		if remaining := limit - len(candles); remaining < pageSize {
+			pageSize = remaining
+		}
+		params.Set("instId", instID)
+		params.Set("bar", bar)
+		params.Set("limit", strconv.Itoa(pageSize))
+
+		response, err := c.get(ctx, path, params)
+		if err != nil {
+			return nil, err
+		}
+		// Each entry is [ts, o, h, l, c, vol (contracts), volCcy (base), volCcyQuote, confirm], newest first
+		var entries [][]string
+		if err := json.Unmarshal(response.Data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode klines: %w", err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		for _, entry := range entries {
+			if len(entry) < 8 {
+				continue
+			}
+			start, err := strconv.ParseInt(entry[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			openTime := time.UnixMilli(start)
+			candles = append(candles, models.Candle{
+				Symbol:           key,
+				OpenTime:         openTime,
+				Open:             entry[1],
+				High:             entry[2],
+				Low:              entry[3],
+				Close:            entry[4],
+				Volume:           entry[6],
+				CloseTime:        openTime.Add(duration - time.Millisecond),
+				QuoteAssetVolume: entry[7],
+				// OKX klines carry no trade count or taker buy volume
+				TakerBuyBaseAssetVolume:  "0",
+				TakerBuyQuoteAssetVolume: "0",
+				Interval:                 interval,
+				PriceType:                models.PriceTypeLast,
+			})
+			before = start
+		}
+		if len(entries) < pageSize {
+			break
+		}
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	return candles, nil
+}
+
+// get performs a GET request and decodes the response envelope, checking OKX's code
+func (c *Client) get(ctx context.Context, path string, params url.Values) (*envelope, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "TTerminal/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &APIError{Path: path, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Path: path, Status: resp.StatusCode, Message: string(body)}
+	}
+
+	var response envelope
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Code != "0" {
+		return nil, &APIError{Path: path, Status: resp.StatusCode, Code: response.Code, Message: response.Msg}
+	}
+	return &response, nil
+}
@@ -0,0 +1,127 @@
+// Package sla measures latencies over a rolling window, per API route or WebSocket message type,
+// so deployments can check their p50/p95/p99 against the latency they promise clients
+//
+// Each key keeps its most recent samples in a fixed ring, so memory stays bounded however busy
+// the instance is; percentiles are computed from the samples still inside the window
+package sla
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+const (
+	// samplesPerKey is the number of recent samples kept per key
+	samplesPerKey = 2048
+
+	// maxKeys caps the keys tracked; samples of further keys are dropped
+	maxKeys = 1000
+)
+
+// sample is one observed latency
+type sample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// series is the ring of a key's most recent samples
+type series struct {
+	samples []sample
+	next    int
+}
+
+// Tracker keeps the most recent latencies per key
+type Tracker struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// NewTracker creates a tracker reporting on the samples of the last window
+func NewTracker(window time.Duration) *Tracker {
+	return &Tracker{
+		window: window,
+		series: make(map[string]*series),
+	}
+}
+
+// Window returns how far back the tracker reports
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// Observe records one latency of a key
+func (t *Tracker) Observe(key string, latency time.Duration) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, exists := t.series[key]
+	if !exists {
+		if len(t.series) >= maxKeys {
+			return
+		}
+		s = &series{samples: make([]sample, 0, 64)}
+		t.series[key] = s
+	}
+	if len(s.samples) < samplesPerKey {
+		s.samples = append(s.samples, sample{at: now, latency: latency})
+		return
+	}
+	s.samples[s.next] = sample{at: now, latency: latency}
+	s.next = (s.next + 1) % samplesPerKey
+}
+
+// Stats returns the percentiles of every key with samples in the window, by key
+// A key meets the target when its p95 is at or under it
+func (t *Tracker) Stats(target time.Duration) []models.LatencyStats {
+	since := time.Now().Add(-t.window)
+
+	t.mu.Lock()
+	windows := make(map[string][]time.Duration, len(t.series))
+	for key, s := range t.series {
+		var latencies []time.Duration
+		for _, observed := range s.samples {
+			if observed.at.After(since) {
+				latencies = append(latencies, observed.latency)
+			}
+		}
+		if len(latencies) > 0 {
+			windows[key] = latencies
+		}
+	}
+	t.mu.Unlock()
+
+	stats := make([]models.LatencyStats, 0, len(windows))
+	for key, latencies := range windows {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		p95 := percentile(latencies, 95)
+		stats = append(stats, models.LatencyStats{
+			Key:         key,
+			Count:       len(latencies),
+			P50Ms:       milliseconds(percentile(latencies, 50)),
+			P95Ms:       milliseconds(p95),
+			P99Ms:       milliseconds(percentile(latencies, 99)),
+			MaxMs:       milliseconds(latencies[len(latencies)-1]),
+			MeetsTarget: p95 <= target,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// milliseconds converts a latency to fractional milliseconds, rounded to the microsecond
+func milliseconds(latency time.Duration) float64 {
+	return math.Round(float64(latency)/float64(time.Microsecond)) / 1000
+}
@@ -0,0 +1,377 @@
+package synthetic
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Price path tuning
+const (
+	dailyVolatility  = 0.03             // Typical daily log-return volatility in a calm regime
+	baseOctaveScale  = 15.0             // Seconds covered by the finest noise octave
+	octaveCount      = 10               // Finest octave 15s, coarsest ~45 days
+	regimeScale      = 6 * 3600.0       // Seconds per volatility regime
+	microNoiseBucket = 100              // Milliseconds per tick-level noise step
+	samplesPerCandle = 30               // Price samples used to build a candle's high and low
+	basisAmplitude   = 0.0005           // Mark/index deviation from last price
+	weekOffset       = 4 * 24 * 3600000 // Epoch was a Thursday; weeks start on Monday
+)
+
+// knownBasePrices gives familiar symbols realistic price levels
+var knownBasePrices = map[string]float64{
+	"BTCUSDT": 60000,
+	"ETHUSDT": 3000,
+	"BNBUSDT": 600,
+	"SOLUSDT": 150,
+	"ADAUSDT": 0.5,
+}
+
+// Generator synthesizes deterministic, realistic market data for any symbol
+// Prices are a pure function of (seed, symbol, time), so REST candles, stream updates
+// and repeated requests always agree without storing any state
+type Generator struct {
+	seed    uint64
+	markets map[string]*market
+	mu      sync.Mutex
+}
+
+// market holds the per-symbol parameters derived from the seed
+type market struct {
+	symbol       string
+	seed         uint64
+	basePrice    float64
+	tickSize     float64
+	stepSize     float64
+	minuteVolume float64 // Base asset volume per minute in a calm regime
+}
+
+// NewGenerator creates a generator; the same seed always produces the same data
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		seed:    uint64(seed),
+		markets: make(map[string]*market),
+	}
+}
+
+// market returns the parameters for a symbol, deriving them on first use
+func (g *Generator) market(symbol string) *market {
+	symbol = strings.ToUpper(symbol)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if m, exists := g.markets[symbol]; exists {
+		return m
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(symbol))
+	seed := h.Sum64() ^ g.seed*0x9E3779B97F4A7C15
+
+	basePrice, known := knownBasePrices[symbol]
+	if !known {
+		// Unknown symbols get a stable price between 0.1 and 1000
+		basePrice = math.Pow(10, -1+4*unitHash(seed, 0, 0))
+	}
+
+	// Tick size keeps roughly six significant digits, lots are worth ~10 quote units
+	tickSize := math.Pow(10, math.Floor(math.Log10(basePrice))-5)
+	stepSize := math.Min(1, math.Pow(10, math.Floor(math.Log10(10000/basePrice))-2))
+
+	m := &market{
+		symbol:       symbol,
+		seed:         seed,
+		basePrice:    basePrice,
+		tickSize:     tickSize,
+		stepSize:     stepSize,
+		minuteVolume: 2_000_000 / basePrice, // ~2M quote volume per minute
+	}
+	g.markets[symbol] = m
+	return m
+}
+
+// Symbols returns the well-known symbols plus every symbol generated so far, sorted
+func (g *Generator) Symbols() []string {
+	g.mu.Lock()
+	seen := make(map[string]bool, len(knownBasePrices)+len(g.markets))
+	for symbol := range knownBasePrices {
+		seen[symbol] = true
+	}
+	for symbol := range g.markets {
+		seen[symbol] = true
+	}
+	g.mu.Unlock()
+
+	symbols := make([]string, 0, len(seen))
+	for symbol := range seen {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Price returns the synthetic last price of a symbol at a point in time
+func (g *Generator) Price(symbol string, at time.Time) float64 {
+	return g.market(symbol).price(at.UnixMilli())
+}
+
+// MarkPrice returns the synthetic mark price, deviating slightly from the last price
+func (g *Generator) MarkPrice(symbol string, at time.Time) float64 {
+	m := g.market(symbol)
+	return m.round(m.price(at.UnixMilli()) * (1 + m.basis(at.UnixMilli())))
+}
+
+// IndexPrice returns the synthetic index price, on the other side of the last price from mark
+func (g *Generator) IndexPrice(symbol string, at time.Time) float64 {
+	m := g.market(symbol)
+	return m.round(m.price(at.UnixMilli()) * (1 - m.basis(at.UnixMilli())))
+}
+
+// FundingRate returns the synthetic funding rate at a point in time (±0.03% around 0.01%)
+func (g *Generator) FundingRate(symbol string, at time.Time) float64 {
+	m := g.market(symbol)
+	return 0.0001 + 0.0003*m.noise(float64(at.Unix())/28800, 901)
+}
+
+// Volatility returns the relative volatility regime multiplier at a point in time (1 = calm)
+func (g *Generator) Volatility(symbol string, at time.Time) float64 {
+	return g.market(symbol).regime(float64(at.UnixMilli()) / 1000)
+}
+
+// TickSize returns the price increment used for a symbol
+func (g *Generator) TickSize(symbol string) float64 {
+	return g.market(symbol).tickSize
+}
+
+// StepSize returns the quantity increment used for a symbol
+func (g *Generator) StepSize(symbol string) float64 {
+	return g.market(symbol).stepSize
+}
+
+// FormatPrice formats a price at the symbol's tick precision
+func (g *Generator) FormatPrice(symbol string, price float64) string {
+	return formatIncrement(price, g.market(symbol).tickSize)
+}
+
+// FormatQuantity formats a quantity at the symbol's step precision
+func (g *Generator) FormatQuantity(symbol string, quantity float64) string {
+	return formatIncrement(quantity, g.market(symbol).stepSize)
+}
+
+// Candle builds the candle of an interval that opens at openTime
+// Candles that have not closed yet are built up to now, like a live exchange kline
+func (g *Generator) Candle(symbol, interval, priceType string, openTime time.Time) models.Candle {
+	m := g.market(symbol)
+	start := openTime.UnixMilli()
+	end := BarEnd(interval, start)
+
+	last := end
+	if now := time.Now().UnixMilli(); now < end {
+		last = now
+	}
+	if last < start {
+		last = start
+	}
+
+	priceAt := m.price
+	switch priceType {
+	case models.PriceTypeMark:
+		priceAt = func(ms int64) float64 { return m.round(m.price(ms) * (1 + m.basis(ms))) }
+	case models.PriceTypeIndex:
+		priceAt = func(ms int64) float64 { return m.round(m.price(ms) * (1 - m.basis(ms))) }
+	}
+
+	open := priceAt(start)
+	closePrice := priceAt(last)
+	high, low := math.Max(open, closePrice), math.Min(open, closePrice)
+	for i := 1; i < samplesPerCandle; i++ {
+		p := priceAt(start + (last-start)*int64(i)/samplesPerCandle)
+		high = math.Max(high, p)
+		low = math.Min(low, p)
+	}
+
+	candle := models.Candle{
+		Symbol:    strings.ToUpper(symbol),
+		OpenTime:  time.UnixMilli(start),
+		CloseTime: time.UnixMilli(end - 1),
+		Open:      g.FormatPrice(symbol, open),
+		High:      g.FormatPrice(symbol, high),
+		Low:       g.FormatPrice(symbol, low),
+		Close:     g.FormatPrice(symbol, closePrice),
+		Interval:  interval,
+	}
+
+	if priceType == models.PriceTypeMark || priceType == models.PriceTypeIndex {
+		candle.PriceType = priceType
+		candle.Volume, candle.QuoteAssetVolume = "0", "0"
+		candle.TakerBuyBaseAssetVolume, candle.TakerBuyQuoteAssetVolume = "0", "0"
+		return candle
+	}
+
+	// Volume scales with elapsed time and the volatility regime; taker flow leans with the move
+	minutes := float64(last-start) / 60000
+	midSeconds := float64(start+last) / 2000
+	volume := m.minuteVolume * minutes * m.regime(midSeconds) * (0.6 + 0.8*unitHash(m.seed, 7, start))
+	buyShare := 0.5
+	if open > 0 && high > low {
+		buyShare = 0.5 + 0.35*(closePrice-open)/(high-low)
+	}
+	buyVolume := volume * buyShare
+	avgPrice := (open + high + low + closePrice) / 4
+
+	candle.Volume = g.FormatQuantity(symbol, volume)
+	candle.QuoteAssetVolume = strconv.FormatFloat(volume*avgPrice, 'f', 2, 64)
+	candle.TakerBuyBaseAssetVolume = g.FormatQuantity(symbol, buyVolume)
+	candle.TakerBuyQuoteAssetVolume = strconv.FormatFloat(buyVolume*avgPrice, 'f', 2, 64)
+	candle.TradeCount = int32(math.Max(1, minutes*(40+60*m.regime(midSeconds))))
+	return candle
+}
+
+// Candles returns candles whose open time lies within [startTime, endTime], oldest first, capped at limit
+func (g *Generator) Candles(symbol, interval, priceType string, startTime, endTime time.Time, limit int) []models.Candle {
+	var candles []models.Candle
+	end := endTime.UnixMilli()
+	if now := time.Now().UnixMilli(); end > now {
+		end = now
+	}
+
+	openTime := BarStart(interval, startTime.UnixMilli())
+	if openTime < startTime.UnixMilli() {
+		openTime = BarEnd(interval, openTime)
+	}
+	for ; openTime <= end && (limit <= 0 || len(candles) < limit); openTime = BarEnd(interval, openTime) {
+		candles = append(candles, g.Candle(symbol, interval, priceType, time.UnixMilli(openTime)))
+	}
+	return candles
+}
+
+// RecentCandles returns the latest limit candles, ending with the candle that is still open
+func (g *Generator) RecentCandles(symbol, interval, priceType string, limit int) []models.Candle {
+	if limit <= 0 {
+		limit = 500
+	}
+
+	openTime := BarStart(interval, time.Now().UnixMilli())
+	for i := 1; i < limit; i++ {
+		openTime = BarStart(interval, openTime-1)
+	}
+	return g.Candles(symbol, interval, priceType, time.UnixMilli(openTime), time.Now(), limit)
+}
+
+// BarStart returns the open time (Unix ms) of the bar containing ms, aligned like Binance
+// (weeks start on Monday, months on the first calendar day, both in UTC)
+func BarStart(interval string, ms int64) int64 {
+	switch interval {
+	case "1M":
+		t := time.UnixMilli(ms).UTC()
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	case "1w":
+		week := int64(7 * 24 * 3600000)
+		return floorDiv(ms-weekOffset, week)*week + weekOffset
+	}
+
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		duration = time.Minute
+	}
+	step := duration.Milliseconds()
+	return floorDiv(ms, step) * step
+}
+
+// BarEnd returns the open time (Unix ms) of the bar following the one opening at openTime
+func BarEnd(interval string, openTime int64) int64 {
+	if interval == "1M" {
+		return time.UnixMilli(openTime).UTC().AddDate(0, 1, 0).UnixMilli()
+	}
+
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		duration = time.Minute
+	}
+	return openTime + duration.Milliseconds()
+}
+
+// price evaluates the log-price path: octaves of smoothed value noise approximate a random walk
+// across scales, and fine octaves are amplified by the volatility regime to cluster volatility
+func (m *market) price(ms int64) float64 {
+	seconds := float64(ms) / 1000
+	sigma := dailyVolatility / math.Sqrt(86400)
+	regime := m.regime(seconds)
+
+	logPrice := 0.0
+	scale := baseOctaveScale
+	for octave := 0; octave < octaveCount; octave++ {
+		amplitude := sigma * math.Sqrt(scale)
+		if scale < 86400 {
+			amplitude *= regime
+		}
+		logPrice += amplitude * m.noise(seconds/scale, uint64(octave+1))
+		scale *= 4
+	}
+
+	// Tick-level jitter so consecutive trades are not perfectly smooth
+	bucket := floorDiv(ms, microNoiseBucket)
+	logPrice += sigma * math.Sqrt(baseOctaveScale) * 0.15 * regime * (2*unitHash(m.seed, 97, bucket) - 1)
+
+	return m.round(m.basePrice * math.Exp(logPrice))
+}
+
+// regime returns the volatility multiplier at a point in time, between ~0.4 and ~3
+func (m *market) regime(seconds float64) float64 {
+	return math.Exp(0.7*m.noise(seconds/regimeScale, 501) + 0.3*m.noise(seconds/(regimeScale/6), 502))
+}
+
+// basis returns the mark/index deviation from last price at a point in time
+func (m *market) basis(ms int64) float64 {
+	return basisAmplitude * m.noise(float64(ms)/1000/600, 701)
+}
+
+// noise returns smoothly interpolated value noise in [-1, 1]
+func (m *market) noise(x float64, stream uint64) float64 {
+	cell := math.Floor(x)
+	frac := x - cell
+	a := 2*unitHash(m.seed, stream, int64(cell)) - 1
+	b := 2*unitHash(m.seed, stream, int64(cell)+1) - 1
+	smooth := frac * frac * (3 - 2*frac)
+	return a + (b-a)*smooth
+}
+
+// round snaps a price to the symbol's tick size
+func (m *market) round(price float64) float64 {
+	return math.Round(price/m.tickSize) * m.tickSize
+}
+
+// unitHash maps (seed, stream, index) to a uniform value in [0, 1)
+func unitHash(seed, stream uint64, index int64) float64 {
+	x := seed ^ stream*0xBF58476D1CE4E5B9 ^ uint64(index)*0x94D049BB133111EB
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return float64(x>>11) / float64(uint64(1)<<53)
+}
+
+// floorDiv divides rounding toward negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// formatIncrement formats a value with as many decimals as the increment needs
+func formatIncrement(value, increment float64) string {
+	decimals := 0
+	if increment < 1 {
+		decimals = int(math.Round(-math.Log10(increment)))
+	}
+	return strconv.FormatFloat(value, 'f', decimals, 64)
+}
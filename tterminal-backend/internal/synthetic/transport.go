@@ -0,0 +1,297 @@
+package synthetic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Binance REST defaults mirrored by the synthetic API
+const (
+	defaultKlineLimit = 500
+	maxKlineLimit     = 1500
+	fundingPeriod     = 8 * time.Hour
+)
+
+// Transport is an http.RoundTripper that answers Binance Futures REST requests from a Generator
+// Plugging it into an http.Client lets the regular Binance client run fully offline
+type Transport struct {
+	generator *Generator
+}
+
+// NewTransport creates a transport serving synthetic Binance responses
+func NewTransport(generator *Generator) *Transport {
+	return &Transport{generator: generator}
+}
+
+// RoundTrip serves a request without touching the network
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	symbol := strings.ToUpper(query.Get("symbol"))
+
+	var body interface{}
+	switch req.URL.Path {
+	case "/fapi/v1/ping":
+		body = map[string]interface{}{}
+	case "/fapi/v1/time":
+		body = map[string]int64{"serverTime": time.Now().UnixMilli()}
+	case "/fapi/v1/exchangeInfo":
+		body = t.exchangeInfo()
+	case "/fapi/v1/klines":
+		body = t.klines(symbol, models.PriceTypeLast, query)
+	case "/fapi/v1/markPriceKlines":
+		body = t.klines(symbol, models.PriceTypeMark, query)
+	case "/fapi/v1/indexPriceKlines":
+		body = t.klines(strings.ToUpper(query.Get("pair")), models.PriceTypeIndex, query)
+	case "/fapi/v1/openInterest":
+		body = map[string]interface{}{
+			"symbol":       symbol,
+			"openInterest": t.generator.FormatQuantity(symbol, t.openInterest(symbol, time.Now())),
+			"time":         time.Now().UnixMilli(),
+		}
+	case "/futures/data/openInterestHist":
+		body = t.openInterestHist(symbol, query)
+	case "/futures/data/globalLongShortAccountRatio":
+		body = t.longShortRatio(symbol, query)
+	case "/fapi/v1/fundingRate":
+		body = t.fundingRates(symbol, query)
+	default:
+		return jsonResponse(req, http.StatusNotFound, map[string]interface{}{
+			"code": -1000,
+			"msg":  fmt.Sprintf("%s is not available with synthetic data", req.URL.Path),
+		})
+	}
+
+	return jsonResponse(req, http.StatusOK, body)
+}
+
+// klines builds Binance kline arrays, honoring startTime, endTime and limit like the real API
+func (t *Transport) klines(symbol, priceType string, query map[string][]string) [][]interface{} {
+	interval := firstValue(query, "interval")
+	if !models.IsValidInterval(interval) {
+		interval = "1m"
+	}
+	limit := queryInt(query, "limit", defaultKlineLimit)
+	if limit <= 0 || limit > maxKlineLimit {
+		limit = defaultKlineLimit
+	}
+
+	var candles []models.Candle
+	startMs := int64(queryInt(query, "startTime", 0))
+	endMs := int64(queryInt(query, "endTime", 0))
+	switch {
+	case startMs > 0:
+		endTime := time.Now()
+		if endMs > 0 {
+			endTime = time.UnixMilli(endMs)
+		}
+		candles = t.generator.Candles(symbol, interval, priceType, time.UnixMilli(startMs), endTime, limit)
+	case endMs > 0:
+		// Latest limit candles opening at or before endTime
+		openTime := BarStart(interval, endMs)
+		for i := 1; i < limit; i++ {
+			openTime = BarStart(interval, openTime-1)
+		}
+		candles = t.generator.Candles(symbol, interval, priceType, time.UnixMilli(openTime), time.UnixMilli(endMs), limit)
+	default:
+		candles = t.generator.RecentCandles(symbol, interval, priceType, limit)
+	}
+
+	rows := make([][]interface{}, 0, len(candles))
+	for _, candle := range candles {
+		rows = append(rows, []interface{}{
+			candle.OpenTime.UnixMilli(),
+			candle.Open,
+			candle.High,
+			candle.Low,
+			candle.Close,
+			candle.Volume,
+			candle.CloseTime.UnixMilli(),
+			candle.QuoteAssetVolume,
+			candle.TradeCount,
+			candle.TakerBuyBaseAssetVolume,
+			candle.TakerBuyQuoteAssetVolume,
+			"0",
+		})
+	}
+	return rows
+}
+
+// exchangeInfo lists the well-known symbols plus every symbol requested so far
+func (t *Transport) exchangeInfo() map[string]interface{} {
+	symbols := t.generator.Symbols()
+	infos := make([]map[string]interface{}, 0, len(symbols))
+	for _, symbol := range symbols {
+		tickSize := t.generator.TickSize(symbol)
+		stepSize := t.generator.StepSize(symbol)
+		infos = append(infos, map[string]interface{}{
+			"symbol":            symbol,
+			"baseAsset":         strings.TrimSuffix(symbol, "USDT"),
+			"quoteAsset":        "USDT",
+			"status":            "TRADING",
+			"pricePrecision":    decimals(tickSize),
+			"quantityPrecision": decimals(stepSize),
+			"filters": []map[string]string{
+				{
+					"filterType": "PRICE_FILTER",
+					"minPrice":   t.generator.FormatPrice(symbol, tickSize),
+					"maxPrice":   t.generator.FormatPrice(symbol, t.generator.Price(symbol, time.Now())*100),
+					"tickSize":   t.generator.FormatPrice(symbol, tickSize),
+				},
+				{
+					"filterType": "LOT_SIZE",
+					"minQty":     t.generator.FormatQuantity(symbol, stepSize),
+					"maxQty":     "1000000",
+					"stepSize":   t.generator.FormatQuantity(symbol, stepSize),
+				},
+				{
+					"filterType": "MIN_NOTIONAL",
+					"notional":   "5",
+				},
+			},
+		})
+	}
+	return map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": time.Now().UnixMilli(),
+		"symbols":    infos,
+	}
+}
+
+// openInterest returns open interest in contracts, drifting around ~50M quote notional
+func (t *Transport) openInterest(symbol string, at time.Time) float64 {
+	m := t.generator.market(symbol)
+	return 50_000_000 / m.basePrice * (1 + 0.25*m.noise(float64(at.Unix())/86400, 801))
+}
+
+// openInterestHist returns open interest statistics per period, oldest first
+func (t *Transport) openInterestHist(symbol string, query map[string][]string) []map[string]interface{} {
+	period, ok := models.IntervalDuration(firstValue(query, "period"))
+	if !ok {
+		period = 5 * time.Minute
+	}
+	limit := queryInt(query, "limit", 30)
+
+	now := time.Now()
+	last := now.Truncate(period)
+	stats := make([]map[string]interface{}, 0, limit)
+	for i := limit - 1; i >= 0; i-- {
+		at := last.Add(-time.Duration(i) * period)
+		oi := t.openInterest(symbol, at)
+		stats = append(stats, map[string]interface{}{
+			"symbol":               symbol,
+			"sumOpenInterest":      t.generator.FormatQuantity(symbol, oi),
+			"sumOpenInterestValue": strconv.FormatFloat(oi*t.generator.Price(symbol, at), 'f', 2, 64),
+			"timestamp":            at.UnixMilli(),
+		})
+	}
+	return stats
+}
+
+// longShortRatio returns account ratios drifting slowly over the day
+func (t *Transport) longShortRatio(symbol string, query map[string][]string) []map[string]interface{} {
+	period, ok := models.IntervalDuration(firstValue(query, "period"))
+	if !ok {
+		period = 5 * time.Minute
+	}
+	limit := queryInt(query, "limit", 30)
+
+	m := t.generator.market(symbol)
+	last := time.Now().Truncate(period)
+	ratios := make([]map[string]interface{}, 0, limit)
+	for i := limit - 1; i >= 0; i-- {
+		at := last.Add(-time.Duration(i) * period)
+		longAccount := 0.5 + 0.15*m.noise(float64(at.Unix())/43200, 851)
+		ratios = append(ratios, map[string]interface{}{
+			"symbol":         symbol,
+			"longShortRatio": strconv.FormatFloat(longAccount/(1-longAccount), 'f', 4, 64),
+			"longAccount":    strconv.FormatFloat(longAccount, 'f', 4, 64),
+			"shortAccount":   strconv.FormatFloat(1-longAccount, 'f', 4, 64),
+			"timestamp":      at.UnixMilli(),
+		})
+	}
+	return ratios
+}
+
+// fundingRates returns settled funding at 00:00, 08:00 and 16:00 UTC, oldest first
+func (t *Transport) fundingRates(symbol string, query map[string][]string) []map[string]interface{} {
+	limit := queryInt(query, "limit", 100)
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	end := time.Now()
+	if endMs := queryInt(query, "endTime", 0); endMs > 0 {
+		end = time.UnixMilli(int64(endMs))
+	}
+	var start time.Time
+	if startMs := queryInt(query, "startTime", 0); startMs > 0 {
+		start = time.UnixMilli(int64(startMs))
+	}
+
+	rates := make([]map[string]interface{}, 0, limit)
+	for at := end.Truncate(fundingPeriod); len(rates) < limit && !at.Before(start); at = at.Add(-fundingPeriod) {
+		rates = append(rates, map[string]interface{}{
+			"symbol":      symbol,
+			"fundingRate": strconv.FormatFloat(t.generator.FundingRate(symbol, at), 'f', 8, 64),
+			"fundingTime": at.UnixMilli(),
+			"markPrice":   t.generator.FormatPrice(symbol, t.generator.MarkPrice(symbol, at)),
+		})
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i]["fundingTime"].(int64) < rates[j]["fundingTime"].(int64)
+	})
+	return rates
+}
+
+// jsonResponse wraps a JSON body in an HTTP response
+func jsonResponse(req *http.Request, status int, body interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode synthetic response: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+		Request:       req,
+	}, nil
+}
+
+// firstValue returns the first value of a query parameter
+func firstValue(query map[string][]string, name string) string {
+	if values := query[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// queryInt parses an integer query parameter with a default
+func queryInt(query map[string][]string, name string, def int) int {
+	if parsed, err := strconv.Atoi(firstValue(query, name)); err == nil {
+		return parsed
+	}
+	return def
+}
+
+// decimals returns the number of decimals an increment needs
+func decimals(increment float64) int {
+	if increment >= 1 {
+		return 0
+	}
+	return len(formatIncrement(increment, increment)) - 2
+}
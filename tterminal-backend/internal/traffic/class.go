@@ -0,0 +1,50 @@
+// Package traffic classifies API requests as interactive (chart loads and other requests a user
+// is waiting on) or batch (exports, backtests and other bulk work), and gives interactive work
+// priority where both compete for capacity: the database pool, the aggregation workers and the
+// request rate limit
+//
+// The class travels in the request context, so services deeper in the call chain can apply it
+package traffic
+
+import (
+	"context"
+	"strings"
+)
+
+// Class is a request's traffic class
+type Class string
+
+const (
+	Interactive Class = "interactive" // Served first; the default
+	Batch       Class = "batch"       // Yields to interactive work and may only use part of each resource
+)
+
+// Header lets clients declare a request batch; it is also set on responses to report the class applied
+const Header = "X-Traffic-Class"
+
+// ParseClass parses a traffic class name, case-insensitively
+func ParseClass(value string) (Class, bool) {
+	switch Class(strings.ToLower(strings.TrimSpace(value))) {
+	case Interactive:
+		return Interactive, true
+	case Batch:
+		return Batch, true
+	}
+	return "", false
+}
+
+// classKey is the context key carrying a request's traffic class
+type classKey struct{}
+
+// WithClass attaches a traffic class to a context
+func WithClass(ctx context.Context, class Class) context.Context {
+	return context.WithValue(ctx, classKey{}, class)
+}
+
+// FromContext returns the traffic class attached to a context, Interactive if none
+func FromContext(ctx context.Context) Class {
+	if class, ok := ctx.Value(classKey{}).(Class); ok {
+		return class
+	}
+	return Interactive
+}
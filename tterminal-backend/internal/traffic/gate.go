@@ -0,0 +1,177 @@
+package traffic
+
+import (
+	"context"
+	"sync"
+)
+
+// Gate limits concurrent work to a number of slots, of which batch work may hold at most
+// batchSlots. Waiting interactive work is given freed slots before waiting batch work, and
+// batch work does not start while interactive work is waiting
+type Gate struct {
+	mu         sync.Mutex
+	slots      int
+	batchSlots int
+	inUse      int
+	batchInUse int
+	// Waiters in arrival order, per class
+	interactive []*gateWaiter
+	batch       []*gateWaiter
+}
+
+// gateWaiter is work waiting for a slot; ready is closed once it holds one
+type gateWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// GateStats is a gate's current usage
+type GateStats struct {
+	Slots              int `json:"slots"`
+	BatchSlots         int `json:"batch_slots"`
+	InUse              int `json:"in_use"`
+	BatchInUse         int `json:"batch_in_use"`
+	InteractiveWaiting int `json:"interactive_waiting"`
+	BatchWaiting       int `json:"batch_waiting"`
+}
+
+// NewGate creates a gate with slots slots, batchSlots of which batch work may use
+// batchSlots is capped at slots and raised to 1, so batch work is slowed but never starved
+func NewGate(slots, batchSlots int) *Gate {
+	if slots < 1 {
+		slots = 1
+	}
+	if batchSlots < 1 {
+		batchSlots = 1
+	}
+	if batchSlots > slots {
+		batchSlots = slots
+	}
+	return &Gate{slots: slots, batchSlots: batchSlots}
+}
+
+// Acquire waits for a slot for the context's traffic class and returns the function releasing it
+// It returns the context's error if the context ends first
+func (g *Gate) Acquire(ctx context.Context) (func(), error) {
+	isBatch := FromContext(ctx) == Batch
+
+	g.mu.Lock()
+	if g.canStart(isBatch) && len(g.interactive) == 0 && (!isBatch || len(g.batch) == 0) {
+		g.take(isBatch)
+		g.mu.Unlock()
+		return g.releaser(isBatch), nil
+	}
+
+	waiter := &gateWaiter{ready: make(chan struct{})}
+	if isBatch {
+		g.batch = append(g.batch, waiter)
+	} else {
+		g.interactive = append(g.interactive, waiter)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return g.releaser(isBatch), nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		granted := waiter.granted
+		if !granted {
+			if isBatch {
+				g.batch = removeWaiter(g.batch, waiter)
+			} else {
+				g.interactive = removeWaiter(g.interactive, waiter)
+			}
+			// Batch work may have been held back only by this waiter
+			g.dispatch()
+		}
+		g.mu.Unlock()
+
+		// A slot granted as the context ended is handed on
+		if granted {
+			g.releaser(isBatch)()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Stats returns the gate's current usage
+func (g *Gate) Stats() GateStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return GateStats{
+		Slots:              g.slots,
+		BatchSlots:         g.batchSlots,
+		InUse:              g.inUse,
+		BatchInUse:         g.batchInUse,
+		InteractiveWaiting: len(g.interactive),
+		BatchWaiting:       len(g.batch),
+	}
+}
+
+// canStart reports whether a slot is free for the class; callers hold mu
+func (g *Gate) canStart(isBatch bool) bool {
+	if g.inUse >= g.slots {
+		return false
+	}
+	return !isBatch || g.batchInUse < g.batchSlots
+}
+
+// take occupies a slot; callers hold mu
+func (g *Gate) take(isBatch bool) {
+	g.inUse++
+	if isBatch {
+		g.batchInUse++
+	}
+}
+
+// releaser returns a function freeing a slot once and handing free slots to waiters
+func (g *Gate) releaser(isBatch bool) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+
+			g.inUse--
+			if isBatch {
+				g.batchInUse--
+			}
+			g.dispatch()
+		})
+	}
+}
+
+// dispatch grants free slots to waiters, interactive first; callers hold mu
+func (g *Gate) dispatch() {
+	for {
+		switch {
+		case len(g.interactive) > 0 && g.canStart(false):
+			g.grant(g.interactive[0], false)
+			g.interactive = g.interactive[1:]
+		case len(g.interactive) == 0 && len(g.batch) > 0 && g.canStart(true):
+			g.grant(g.batch[0], true)
+			g.batch = g.batch[1:]
+		default:
+			return
+		}
+	}
+}
+
+// grant gives a waiter a slot; callers hold mu
+func (g *Gate) grant(waiter *gateWaiter, isBatch bool) {
+	g.take(isBatch)
+	waiter.granted = true
+	close(waiter.ready)
+}
+
+// removeWaiter removes a waiter from a queue
+func removeWaiter(queue []*gateWaiter, waiter *gateWaiter) []*gateWaiter {
+	for i, w := range queue {
+		if w == waiter {
+			return append(queue[:i], queue[i+1:]...)
+		}
+	}
+	return queue
+}
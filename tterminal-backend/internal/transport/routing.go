@@ -0,0 +1,32 @@
+package transport
+
+import "time"
+
+// RoutingHint identifies the instance a client is connected to and how busy it is, so clients
+// behind a load balancer can choose where to reconnect
+type RoutingHint struct {
+	InstanceID string `json:"instance_id"`
+	Region     string `json:"region,omitempty"`
+	Load       string `json:"load"`     // "normal" or "saturated"
+	LoadPct    int    `json:"load_pct"` // Highest share of a load threshold in use (0 when none is set)
+	Clients    int    `json:"clients"`  // Connected regular clients as of the last load measurement
+	Draining   bool   `json:"draining"`
+}
+
+// ReconnectPeer is an instance offered in a "reconnect_to" advisory
+type ReconnectPeer struct {
+	URL      string       `json:"url"`
+	Instance *RoutingHint `json:"instance,omitempty"` // From the peer's health check; nil for peers not reporting one
+}
+
+// Drainer moves a transport's clients off the instance before a restart
+// Implemented by websocket.Hub
+type Drainer interface {
+	// StartDrain refuses new connections and tells connected clients to reconnect to peers
+	// (best first) within grace
+	StartDrain(peers []ReconnectPeer, grace time.Duration)
+	// CloseAllClients closes every remaining connection
+	CloseAllClients()
+	// GetConnectedClients returns the number of connected clients
+	GetConnectedClients() int
+}
@@ -0,0 +1,41 @@
+// Package transport defines what the market data core needs from the protocols serving clients
+// HTTP handlers live in controllers and routes, the WebSocket transport in internal/websocket;
+// services publish, pause symbols, drain connections and encode replays through the interfaces
+// here, so further transports (gRPC, CLI, a message bus) can be added without the services
+// importing them. Live stream state (prices, funding predictions, user data) is still read from
+// the exchange streams in internal/websocket
+package transport
+
+import (
+	"time"
+	"tterminal-backend/models"
+)
+
+// Publisher pushes core events to subscribed clients
+// Implemented by websocket.Hub
+type Publisher interface {
+	// BroadcastSpreadUpdate sends the latest spread of every monitored pair
+	BroadcastSpreadUpdate(spreads []models.SpreadSample)
+	// BroadcastSymbolUpdate sends changed symbol metadata with the fields that changed
+	BroadcastSymbolUpdate(symbol *models.Symbol, changes []string, brackets []models.LeverageBracket)
+	// BroadcastHiddenLiquidityUpdate sends changed hidden liquidity estimates
+	BroadcastHiddenLiquidityUpdate(estimates []models.HiddenLiquidityEstimate)
+	// SendAlertTriggered sends a triggered alert to its owner's connections, returning how many
+	// received it
+	SendAlertTriggered(alert models.PriceAlert, trigger models.AlertTrigger) int
+	// SetSymbolPaused stops or resumes pushing a symbol's market data until the given time
+	// (zero for no expiry), telling subscribers why
+	SetSymbolPaused(symbol string, paused bool, reason string, until time.Time)
+}
+
+// MessageEncoder builds the messages a transport sends for market data, so stored data can be
+// replayed exactly like the live stream
+// Implemented by websocket.StreamMessages
+type MessageEncoder interface {
+	// TradeMessage encodes a trade, stamped at timestamp (Unix milliseconds)
+	TradeMessage(trade models.TradeRecord, timestamp int64) ([]byte, error)
+	// KlineMessage encodes a candle, stamped at timestamp (Unix milliseconds)
+	KlineMessage(candle models.Candle, closed bool, timestamp int64) ([]byte, error)
+	// LiquidationMessage encodes a liquidation, stamped at timestamp (Unix milliseconds)
+	LiquidationMessage(event models.LiquidationEvent, timestamp int64) ([]byte, error)
+}
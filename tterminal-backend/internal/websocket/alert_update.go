@@ -0,0 +1,49 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+	"tterminal-backend/models"
+)
+
+// AlertTriggered tells a user's connections that one of their price alerts fired
+type AlertTriggered struct {
+	Type      string              `json:"type"` // Always "alert_triggered"
+	Alert     models.PriceAlert   `json:"alert"`
+	Trigger   models.AlertTrigger `json:"trigger"`
+	Timestamp int64               `json:"timestamp"`
+}
+
+// SendAlertTriggered sends an "alert_triggered" event to every connection of the alert's user,
+// whatever it subscribed to, and returns the number of connections reached. Connections belong
+// to a user only through a verified access token (see HandleWebSocket)
+func (h *Hub) SendAlertTriggered(alert models.PriceAlert, trigger models.AlertTrigger) int {
+	message, err := json.Marshal(&AlertTriggered{
+		Type:      "alert_triggered",
+		Alert:     alert,
+		Trigger:   trigger,
+		Timestamp: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Error marshaling alert notification: %v", err)
+		return 0
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	reached := 0
+	for client := range h.clients {
+		if client.userID == "" || client.userID != alert.UserID {
+			continue
+		}
+		select {
+		case client.send <- message:
+			reached++
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+	return reached
+}
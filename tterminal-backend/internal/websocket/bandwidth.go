@@ -0,0 +1,475 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// bandwidthWindow is how often sending rates are measured and caps enforced
+	bandwidthWindow = 5 * time.Second
+
+	// bandwidthConflateInterval is how often a conflated client receives the latest state updates
+	bandwidthConflateInterval = time.Second
+
+	// bandwidthHold is how long a client stays conflated or downgraded before stepping back
+	bandwidthHold = 30 * time.Second
+
+	// bandwidthRecoverRatio is the share of the cap the offered rate must fall under to step back
+	bandwidthRecoverRatio = 0.8
+
+	// bandwidthUserRetention is how long a user without connections keeps its totals
+	bandwidthUserRetention = time.Hour
+
+	// bandwidthTopClients caps the clients listed in bandwidth stats
+	bandwidthTopClients = 20
+)
+
+// Bandwidth states of a client, raised one step each window its cap is exceeded
+const (
+	BandwidthNormal     = "normal"     // Every message delivered
+	BandwidthConflated  = "conflated"  // State updates (prices, books, forming klines) sent once per second
+	BandwidthDowngraded = "downgraded" // Also no trades or order books
+)
+
+// bandwidthStates indexes the states by level
+var bandwidthStates = []string{BandwidthNormal, BandwidthConflated, BandwidthDowngraded}
+
+// conflatedTypes are state updates a conflated client receives only the latest of per key
+var conflatedTypes = map[string]bool{
+	"price_update":      true,
+	"depth_update":      true,
+	"kline_update":      true,
+	"mark_price_update": true,
+}
+
+// downgradedTypes are dropped for downgraded clients, the heaviest per-symbol feeds
+var downgradedTypes = []string{"trade_update", "depth_update"}
+
+// clientBandwidth counts a client's bytes and holds its bandwidth state
+type clientBandwidth struct {
+	connectedAt time.Time
+	user        *userBandwidth // Nil for anonymous clients
+
+	sent          atomic.Int64 // Bytes written since connecting
+	windowSent    atomic.Int64 // Bytes written in the current window
+	windowOffered atomic.Int64 // Bytes queued for the client in the current window, before conflation
+	rate          atomic.Int64 // Bytes written per second over the last window
+	offeredRate   atomic.Int64 // Bytes queued per second over the last window
+	level         atomic.Int32 // Index into bandwidthStates
+
+	// Owned by the bandwidth monitor
+	levelSince time.Time
+
+	// Owned by the write goroutine: latest conflated message per key, in first-queued order
+	pending      map[string][]byte
+	pendingOrder []string
+}
+
+// userBandwidth counts the bytes sent to one user's connections
+type userBandwidth struct {
+	sent atomic.Int64 // Bytes written since the user was first seen
+
+	// Guarded by bandwidthTracker.mu
+	rate        int64
+	connections int
+	lastSeen    time.Time
+}
+
+// bandwidthTracker holds hub-wide and per-user byte totals, kept after clients disconnect
+type bandwidthTracker struct {
+	sent atomic.Int64 // Bytes written to all clients since start
+	rate atomic.Int64 // Bytes written per second to all clients over the last window
+
+	mu    sync.Mutex
+	users map[string]*userBandwidth
+}
+
+// ClientBandwidth is one connection's bandwidth figures
+type ClientBandwidth struct {
+	ClientID              string `json:"client_id"`
+	UserID                string `json:"user_id,omitempty"`
+	BytesSent             int64  `json:"bytes_sent"`
+	BytesPerSecond        int64  `json:"bytes_per_second"`
+	OfferedBytesPerSecond int64  `json:"offered_bytes_per_second"` // Before conflation and downgrades
+	State                 string `json:"state"`
+	ConnectedSeconds      int64  `json:"connected_seconds"`
+}
+
+// UserBandwidth is one user's bandwidth figures across connections
+type UserBandwidth struct {
+	UserID         string `json:"user_id"`
+	Connections    int    `json:"connections"`
+	BytesSent      int64  `json:"bytes_sent"` // Including closed connections
+	BytesPerSecond int64  `json:"bytes_per_second"`
+}
+
+// BandwidthStats are the hub's bandwidth figures; rates cover the last measurement window
+type BandwidthStats struct {
+	BytesSent             int64             `json:"bytes_sent"`
+	BytesPerSecond        int64             `json:"bytes_per_second"`
+	MaxBytesPerSecond     int               `json:"max_bytes_per_second"`      // Per connection (0 = unlimited)
+	MaxUserBytesPerSecond int               `json:"max_user_bytes_per_second"` // Per user (0 = unlimited)
+	WindowSeconds         int               `json:"window_seconds"`
+	States                map[string]int    `json:"states"`      // Connections per bandwidth state
+	TopClients            []ClientBandwidth `json:"top_clients"` // Highest offered rates first
+	Users                 []UserBandwidth   `json:"users"`       // Highest rates first
+}
+
+// BandwidthMessage tells a client its bandwidth state changed
+type BandwidthMessage struct {
+	Type               string   `json:"type"` // "bandwidth"
+	State              string   `json:"state"`
+	BytesPerSecond     int64    `json:"bytes_per_second"` // Offered to the client over the last window
+	MaxBytesPerSecond  int      `json:"max_bytes_per_second,omitempty"`
+	MaxUserBytes       int      `json:"max_user_bytes_per_second,omitempty"`
+	ConflateIntervalMs int64    `json:"conflate_interval_ms,omitempty"`
+	Dropped            []string `json:"dropped,omitempty"` // Message types not delivered while downgraded
+	Message            string   `json:"message"`
+	Timestamp          int64    `json:"timestamp"`
+}
+
+// newBandwidthTracker creates an empty bandwidth tracker
+func newBandwidthTracker() *bandwidthTracker {
+	return &bandwidthTracker{users: make(map[string]*userBandwidth)}
+}
+
+// user returns the counters of a user, creating them on first sight
+func (t *bandwidthTracker) user(userID string) *userBandwidth {
+	if userID == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	user, exists := t.users[userID]
+	if !exists {
+		user = &userBandwidth{}
+		t.users[userID] = user
+	}
+	user.lastSeen = time.Now()
+	return user
+}
+
+// initBandwidth starts a client's bandwidth accounting; called before its goroutines start
+// Clients count toward their user only by the verified identity of their access token, so a
+// client cannot escape the user cap by changing a user ID, nor spend another user's
+func (c *Client) initBandwidth() {
+	c.bandwidth.connectedAt = time.Now()
+	c.bandwidth.user = c.hub.bandwidth.user(c.userID)
+}
+
+// recordSent counts bytes written to the client
+func (c *Client) recordSent(n int) {
+	c.bandwidth.sent.Add(int64(n))
+	c.bandwidth.windowSent.Add(int64(n))
+	if c.bandwidth.user != nil {
+		c.bandwidth.user.sent.Add(int64(n))
+	}
+	c.hub.bandwidth.sent.Add(int64(n))
+}
+
+// recordPolled counts a message handed to a long-polling client, which is never throttled
+func (c *Client) recordPolled(n int) {
+	c.bandwidth.windowOffered.Add(int64(n))
+	c.recordSent(n)
+}
+
+// filterBandwidth applies the client's bandwidth state to a batch about to be written
+// Conflated clients keep only the latest state update per key until the next flush, and
+// downgraded clients also lose trades and order books. Called from the write goroutine
+func (c *Client) filterBandwidth(batch [][]byte) [][]byte {
+	offered := 0
+	for _, message := range batch {
+		offered += len(message)
+	}
+	c.bandwidth.windowOffered.Add(int64(offered))
+
+	level := c.bandwidth.level.Load()
+	if level == 0 {
+		return batch
+	}
+
+	kept := batch[:0]
+	for _, message := range batch {
+		header, ok := parseMessageHeader(message)
+		if !ok {
+			kept = append(kept, message)
+			continue
+		}
+		if level >= 2 && isDowngradedType(header.Type) {
+			continue
+		}
+		if !conflatedTypes[header.Type] || header.IsClosed {
+			kept = append(kept, message)
+			continue
+		}
+
+		key := header.key()
+		if c.bandwidth.pending == nil {
+			c.bandwidth.pending = make(map[string][]byte)
+		}
+		if _, queued := c.bandwidth.pending[key]; !queued {
+			c.bandwidth.pendingOrder = append(c.bandwidth.pendingOrder, key)
+		}
+		c.bandwidth.pending[key] = message
+	}
+	return kept
+}
+
+// flushConflated returns the conflated messages waiting for the client, oldest key first
+// Called from the write goroutine
+func (c *Client) flushConflated() [][]byte {
+	if len(c.bandwidth.pendingOrder) == 0 {
+		return nil
+	}
+
+	batch := make([][]byte, 0, len(c.bandwidth.pendingOrder))
+	for _, key := range c.bandwidth.pendingOrder {
+		batch = append(batch, c.bandwidth.pending[key])
+		delete(c.bandwidth.pending, key)
+	}
+	c.bandwidth.pendingOrder = c.bandwidth.pendingOrder[:0]
+	return batch
+}
+
+// messageHeader holds the fields of an outbound message that delivery filters key on
+type messageHeader struct {
+	Type     string `json:"type"`
+	Symbol   string `json:"symbol"`
+	Interval string `json:"interval"`
+	IsClosed bool   `json:"is_closed"`
+}
+
+// parseMessageHeader reads the header fields of an outbound message
+func parseMessageHeader(message []byte) (messageHeader, bool) {
+	var header messageHeader
+	if json.Unmarshal(message, &header) != nil {
+		return header, false
+	}
+	return header, true
+}
+
+// key identifies the stream of a message: its type, symbol and interval
+func (h messageHeader) key() string {
+	return h.Type + ":" + h.Symbol + ":" + h.Interval
+}
+
+// isDowngradedType reports whether downgraded clients lose messages of the type
+func isDowngradedType(messageType string) bool {
+	for _, t := range downgradedTypes {
+		if t == messageType {
+			return true
+		}
+	}
+	return false
+}
+
+// runBandwidthMonitor measures sending rates every window and moves clients over their caps
+// (or whose user is over its cap) through the bandwidth states
+func (h *Hub) runBandwidthMonitor() {
+	ticker := time.NewTicker(bandwidthWindow)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		h.measureBandwidth(now)
+	}
+}
+
+// measureBandwidth closes a measurement window and enforces the caps
+func (h *Hub) measureBandwidth(now time.Time) {
+	seconds := int64(bandwidthWindow / time.Second)
+	userCap := int64(h.getClientLimits().MaxUserBytesPerSecond)
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	// Rates per client and per user
+	var total int64
+	userOffered := make(map[*userBandwidth]int64)
+	h.bandwidth.mu.Lock()
+	for _, user := range h.bandwidth.users {
+		user.rate, user.connections = 0, 0
+	}
+	for client := range h.clients {
+		bw := &client.bandwidth
+		rate := bw.windowSent.Swap(0) / seconds
+		offered := bw.windowOffered.Swap(0) / seconds
+		bw.rate.Store(rate)
+		bw.offeredRate.Store(offered)
+		total += rate
+		if bw.user != nil {
+			bw.user.rate += rate
+			bw.user.connections++
+			bw.user.lastSeen = now
+			userOffered[bw.user] += offered
+		}
+	}
+	for userID, user := range h.bandwidth.users {
+		if user.connections == 0 && now.Sub(user.lastSeen) > bandwidthUserRetention {
+			delete(h.bandwidth.users, userID)
+		}
+	}
+	userRates := make(map[*userBandwidth]int64, len(userOffered))
+	for user := range userOffered {
+		userRates[user] = user.rate
+	}
+	h.bandwidth.mu.Unlock()
+	h.bandwidth.rate.Store(total)
+
+	// Raise clients over a cap one level per window; lower them after the hold once well under
+	for client := range h.clients {
+		if client.lite || client.conn == nil {
+			continue // Lite and long-polling clients are counted but not throttled
+		}
+		bw := &client.bandwidth
+		clientCap := connectionCap(bw, int64(client.limits.MaxBytesPerSecond), userCap)
+		offered, sent := bw.offeredRate.Load(), bw.rate.Load()
+		var offeredByUser, sentByUser int64
+		if bw.user != nil {
+			offeredByUser, sentByUser = userOffered[bw.user], userRates[bw.user]
+		}
+
+		level := bw.level.Load()
+		next := level
+		switch {
+		case level == 0 && (over(offered, clientCap) || over(offeredByUser, userCap)):
+			next = 1
+		case level == 1 && (over(sent, clientCap) || over(sentByUser, userCap)):
+			next = 2
+		case level > 0 && now.Sub(bw.levelSince) >= bandwidthHold &&
+			under(offered, clientCap) && under(offeredByUser, userCap):
+			next = level - 1
+		}
+		if next == level {
+			continue
+		}
+
+		bw.level.Store(next)
+		bw.levelSince = now
+		h.notifyBandwidth(client, bandwidthStates[next], offered, userCap)
+	}
+}
+
+// connectionCap returns the cap of one connection; anonymous connections, which have no user to
+// share a cap with, are also held to the user cap on their own
+func connectionCap(bw *clientBandwidth, clientCap, userCap int64) int64 {
+	if bw.user != nil || userCap <= 0 {
+		return clientCap
+	}
+	if clientCap <= 0 || userCap < clientCap {
+		return userCap
+	}
+	return clientCap
+}
+
+// over reports whether a rate exceeds a cap; a zero cap is unlimited
+func over(rate, cap int64) bool {
+	return cap > 0 && rate > cap
+}
+
+// under reports whether a rate is far enough below a cap to step back; a zero cap is unlimited
+func under(rate, cap int64) bool {
+	return cap <= 0 || float64(rate) <= float64(cap)*bandwidthRecoverRatio
+}
+
+// notifyBandwidth tells a client its new bandwidth state; the caller holds h.mutex
+// The notice is dropped rather than blocking when the client's queue is full
+func (h *Hub) notifyBandwidth(client *Client, state string, offered, userCap int64) {
+	notice := BandwidthMessage{
+		Type:              "bandwidth",
+		State:             state,
+		BytesPerSecond:    offered,
+		MaxBytesPerSecond: client.limits.MaxBytesPerSecond,
+		MaxUserBytes:      int(userCap),
+		Timestamp:         time.Now().UnixMilli(),
+	}
+	switch state {
+	case BandwidthNormal:
+		notice.Message = "Bandwidth back under the cap: every update is delivered again"
+	case BandwidthConflated:
+		notice.ConflateIntervalMs = bandwidthConflateInterval.Milliseconds()
+		notice.Message = fmt.Sprintf("Bandwidth cap exceeded: prices, books and forming klines are sent every %s", bandwidthConflateInterval)
+	case BandwidthDowngraded:
+		notice.ConflateIntervalMs = bandwidthConflateInterval.Milliseconds()
+		notice.Dropped = downgradedTypes
+		notice.Message = "Bandwidth cap still exceeded while conflated: trades and order books are paused"
+	}
+
+	message, err := json.Marshal(notice)
+	if err != nil {
+		return
+	}
+	select {
+	case client.send <- message:
+	default:
+	}
+}
+
+// bandwidthState returns the client's current bandwidth figures
+func (c *Client) bandwidthState() ClientBandwidth {
+	return ClientBandwidth{
+		ClientID:              c.id,
+		UserID:                c.userID,
+		BytesSent:             c.bandwidth.sent.Load(),
+		BytesPerSecond:        c.bandwidth.rate.Load(),
+		OfferedBytesPerSecond: c.bandwidth.offeredRate.Load(),
+		State:                 bandwidthStates[c.bandwidth.level.Load()],
+		ConnectedSeconds:      int64(time.Since(c.bandwidth.connectedAt) / time.Second),
+	}
+}
+
+// GetBandwidthStats returns bytes sent in total, per busiest connection and per user
+func (h *Hub) GetBandwidthStats() BandwidthStats {
+	limits := h.getClientLimits()
+	stats := BandwidthStats{
+		BytesSent:             h.bandwidth.sent.Load(),
+		BytesPerSecond:        h.bandwidth.rate.Load(),
+		MaxBytesPerSecond:     limits.MaxBytesPerSecond,
+		MaxUserBytesPerSecond: limits.MaxUserBytesPerSecond,
+		WindowSeconds:         int(bandwidthWindow / time.Second),
+		States:                map[string]int{BandwidthNormal: 0, BandwidthConflated: 0, BandwidthDowngraded: 0},
+		TopClients:            []ClientBandwidth{},
+		Users:                 []UserBandwidth{},
+	}
+
+	h.mutex.RLock()
+	clients := make([]ClientBandwidth, 0, len(h.clients))
+	for client := range h.clients {
+		state := client.bandwidthState()
+		stats.States[state.State]++
+		clients = append(clients, state)
+	}
+	h.mutex.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].OfferedBytesPerSecond > clients[j].OfferedBytesPerSecond
+	})
+	if len(clients) > bandwidthTopClients {
+		clients = clients[:bandwidthTopClients]
+	}
+	stats.TopClients = append(stats.TopClients, clients...)
+
+	h.bandwidth.mu.Lock()
+	for userID, user := range h.bandwidth.users {
+		stats.Users = append(stats.Users, UserBandwidth{
+			UserID:         userID,
+			Connections:    user.connections,
+			BytesSent:      user.sent.Load(),
+			BytesPerSecond: user.rate,
+		})
+	}
+	h.bandwidth.mu.Unlock()
+	sort.Slice(stats.Users, func(i, j int) bool {
+		if stats.Users[i].BytesPerSecond != stats.Users[j].BytesPerSecond {
+			return stats.Users[i].BytesPerSecond > stats.Users[j].BytesPerSecond
+		}
+		return stats.Users[i].UserID < stats.Users[j].UserID
+	})
+
+	return stats
+}
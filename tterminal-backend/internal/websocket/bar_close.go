@@ -0,0 +1,312 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+const (
+	// barCloseConfirmWait is how long after a boundary the stream may take to deliver the
+	// closed kline before the bar is confirmed over REST instead
+	barCloseConfirmWait = 3 * time.Second
+	// clockSyncInterval is how often the exchange clock offset is re-measured
+	clockSyncInterval = 10 * time.Minute
+)
+
+// barCloseIntervals are the kline intervals streamed from Binance and scheduled for bar closes
+var barCloseIntervals = []string{"1m", "5m", "15m"}
+
+// ServerClock returns the exchange's current time
+type ServerClock func(ctx context.Context) (time.Time, error)
+
+// ClosedBarFetcher loads a closed bar over REST when the stream did not confirm it in time
+type ClosedBarFetcher func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error)
+
+// BarCloseScheduler emits "bar_close" events at exchange-aligned interval boundaries
+// Boundaries are computed on the exchange clock (local time plus the measured offset). A bar is
+// confirmed by the futures stream's closed kline, or over REST if that does not arrive within
+// barCloseConfirmWait, and is emitted exactly once to WebSocket subscribers of the symbol and
+// to every registered handler
+type BarCloseScheduler struct {
+	hub     *Hub
+	symbols func() []string
+
+	mu       sync.Mutex
+	clock    ServerClock
+	fetcher  ClosedBarFetcher
+	offset   time.Duration // Exchange time minus local time
+	syncedAt time.Time
+	handlers []func(models.BarClose)
+	// Open time of the last emitted bar per "SYMBOL:interval"
+	emitted map[string]int64
+	// Stats
+	streamConfirmed int64
+	restConfirmed   int64
+	missed          int64
+	lastBoundary    time.Time
+}
+
+// newBarCloseScheduler creates a scheduler for the symbols returned by symbols
+func newBarCloseScheduler(hub *Hub, symbols func() []string) *BarCloseScheduler {
+	return &BarCloseScheduler{
+		hub:     hub,
+		symbols: symbols,
+		emitted: make(map[string]int64),
+	}
+}
+
+// SetServerClock sets the exchange clock used to align boundaries and re-syncs the offset
+func (s *BarCloseScheduler) SetServerClock(clock ServerClock) {
+	s.mu.Lock()
+	s.clock = clock
+	s.mu.Unlock()
+
+	go s.syncClock()
+}
+
+// SetFallbackFetcher sets the REST fetcher used for bars the stream did not confirm in time
+func (s *BarCloseScheduler) SetFallbackFetcher(fetcher ClosedBarFetcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetcher = fetcher
+}
+
+// OnBarClose registers a handler called (in its own goroutine) for every emitted bar close
+func (s *BarCloseScheduler) OnBarClose(handler func(models.BarClose)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Offset returns the measured exchange clock offset (exchange time minus local time)
+func (s *BarCloseScheduler) Offset() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// run waits for each exchange-aligned boundary and confirms the bars closing at it
+func (s *BarCloseScheduler) run(stop <-chan struct{}) {
+	clockTicker := time.NewTicker(clockSyncInterval)
+	defer clockTicker.Stop()
+
+	for {
+		boundary := s.exchangeNow().Truncate(time.Minute).Add(time.Minute)
+
+		// Sleep until the boundary plus the confirmation window, on the exchange clock
+		timer := time.NewTimer(time.Until(boundary.Add(-s.Offset())) + barCloseConfirmWait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-clockTicker.C:
+			timer.Stop()
+			go s.syncClock()
+			continue
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		s.lastBoundary = boundary
+		s.mu.Unlock()
+
+		for _, interval := range barCloseIntervals {
+			duration, _ := models.IntervalDuration(interval)
+			if boundary.UnixMilli()%duration.Milliseconds() != 0 {
+				continue
+			}
+			openTime := boundary.Add(-duration)
+			for _, symbol := range s.symbols() {
+				if !s.isEmitted(symbol, interval, openTime.UnixMilli()) {
+					go s.confirmOverREST(symbol, interval, openTime)
+				}
+			}
+		}
+	}
+}
+
+// confirmStream emits a bar from a closed futures kline
+func (s *BarCloseScheduler) confirmStream(k models.KlineEvent) {
+	bar := models.BarClose{
+		Symbol:      k.Symbol,
+		Interval:    k.Interval,
+		OpenTime:    k.OpenTime.UnixMilli(),
+		CloseTime:   k.CloseTime.UnixMilli(),
+		Open:        models.ParseFloat(k.Open),
+		High:        models.ParseFloat(k.High),
+		Low:         models.ParseFloat(k.Low),
+		Close:       models.ParseFloat(k.Close),
+		Volume:      models.ParseFloat(k.Volume),
+		BuyVolume:   models.ParseFloat(k.BuyVolume),
+		QuoteVolume: models.ParseFloat(k.QuoteVolume),
+		TradeCount:  k.TradeCount,
+		Source:      "stream",
+		Candle: models.Candle{
+			Symbol:                   k.Symbol,
+			OpenTime:                 k.OpenTime,
+			Open:                     k.Open,
+			High:                     k.High,
+			Low:                      k.Low,
+			Close:                    k.Close,
+			Volume:                   k.Volume,
+			CloseTime:                k.CloseTime,
+			QuoteAssetVolume:         k.QuoteVolume,
+			TradeCount:               int32(k.TradeCount),
+			TakerBuyBaseAssetVolume:  k.BuyVolume,
+			TakerBuyQuoteAssetVolume: k.BuyQuoteVolume,
+			Interval:                 k.Interval,
+			PriceType:                models.PriceTypeLast,
+			Source:                   models.CandleSourceStream,
+		},
+	}
+
+	s.emit(bar)
+}
+
+// confirmOverREST fetches a bar the stream did not confirm and emits it
+func (s *BarCloseScheduler) confirmOverREST(symbol, interval string, openTime time.Time) {
+	s.mu.Lock()
+	fetcher := s.fetcher
+	s.mu.Unlock()
+
+	if fetcher == nil {
+		s.recordMissed(symbol, interval, openTime, "no REST fallback configured")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	candle, err := fetcher(ctx, symbol, interval, openTime)
+	if err != nil || candle == nil {
+		reason := "bar not returned"
+		if err != nil {
+			reason = err.Error()
+		}
+		s.recordMissed(symbol, interval, openTime, reason)
+		return
+	}
+	candle.Source = models.CandleSourceRESTPoll
+
+	s.emit(models.BarClose{
+		Symbol:      symbol,
+		Interval:    interval,
+		OpenTime:    candle.OpenTime.UnixMilli(),
+		CloseTime:   candle.CloseTime.UnixMilli(),
+		Open:        models.ParseFloat(candle.Open),
+		High:        models.ParseFloat(candle.High),
+		Low:         models.ParseFloat(candle.Low),
+		Close:       models.ParseFloat(candle.Close),
+		Volume:      models.ParseFloat(candle.Volume),
+		BuyVolume:   models.ParseFloat(candle.TakerBuyBaseAssetVolume),
+		QuoteVolume: models.ParseFloat(candle.QuoteAssetVolume),
+		TradeCount:  int64(candle.TradeCount),
+		Source:      "rest",
+		Candle:      *candle,
+	})
+}
+
+// emit delivers a bar once: later confirmations of the same bar are dropped
+func (s *BarCloseScheduler) emit(bar models.BarClose) {
+	key := bar.Symbol + ":" + bar.Interval
+	exchangeNow := s.exchangeNow()
+
+	s.mu.Lock()
+	if bar.OpenTime <= s.emitted[key] {
+		s.mu.Unlock()
+		return
+	}
+	s.emitted[key] = bar.OpenTime
+	if bar.Source == "rest" {
+		s.restConfirmed++
+	} else {
+		s.streamConfirmed++
+	}
+	handlers := append([]func(models.BarClose){}, s.handlers...)
+	s.mu.Unlock()
+
+	bar.Type = "bar_close"
+	bar.ConfirmedAt = exchangeNow.UnixMilli()
+	if duration, ok := models.IntervalDuration(bar.Interval); ok {
+		bar.DelayMs = bar.ConfirmedAt - (bar.OpenTime + duration.Milliseconds())
+	}
+
+	s.hub.BroadcastBarClose(bar)
+	for _, handler := range handlers {
+		go handler(bar)
+	}
+}
+
+// isEmitted reports whether the bar opening at openTime was already emitted
+func (s *BarCloseScheduler) isEmitted(symbol, interval string, openTime int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return openTime <= s.emitted[symbol+":"+interval]
+}
+
+// recordMissed counts a bar that could not be confirmed
+func (s *BarCloseScheduler) recordMissed(symbol, interval string, openTime time.Time, reason string) {
+	s.mu.Lock()
+	s.missed++
+	s.mu.Unlock()
+
+	log.Printf("Bar close for %s %s at %s not confirmed: %s", symbol, interval, openTime.UTC().Format(time.RFC3339), reason)
+}
+
+// syncClock measures the exchange clock offset, assuming symmetric request latency
+func (s *BarCloseScheduler) syncClock() {
+	s.mu.Lock()
+	clock := s.clock
+	s.mu.Unlock()
+
+	if clock == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sent := time.Now()
+	serverTime, err := clock(ctx)
+	if err != nil {
+		log.Printf("Failed to sync exchange clock: %v", err)
+		return
+	}
+	received := time.Now()
+
+	offset := serverTime.Sub(sent.Add(received.Sub(sent) / 2))
+
+	s.mu.Lock()
+	s.offset = offset
+	s.syncedAt = received
+	s.mu.Unlock()
+}
+
+// exchangeNow returns the current time on the exchange clock
+func (s *BarCloseScheduler) exchangeNow() time.Time {
+	return time.Now().Add(s.Offset())
+}
+
+// stats returns scheduler counters for stream statistics
+func (s *BarCloseScheduler) stats() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := map[string]interface{}{
+		"intervals":        barCloseIntervals,
+		"clock_offset_ms":  s.offset.Milliseconds(),
+		"stream_confirmed": s.streamConfirmed,
+		"rest_confirmed":   s.restConfirmed,
+		"missed":           s.missed,
+	}
+	if !s.syncedAt.IsZero() {
+		stats["clock_synced_at"] = s.syncedAt.UnixMilli()
+	}
+	if !s.lastBoundary.IsZero() {
+		stats["last_boundary"] = s.lastBoundary.UnixMilli()
+	}
+	return stats
+}
@@ -0,0 +1,267 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/models"
+)
+
+const (
+	// binanceBookDepth is the levels per side of the snapshot a local book starts from, and of
+	// the book handed to the depth recorder
+	binanceBookDepth = 1000
+
+	// binanceBookBuffer caps the diffs kept while a snapshot is fetched; older ones are dropped,
+	// since the snapshot is newer than them
+	binanceBookBuffer = 1000
+
+	// binanceBookFetchTimeout bounds one snapshot request
+	binanceBookFetchTimeout = 10 * time.Second
+
+	// binanceBookRetryDelay is the wait before fetching another snapshot after a failed sync
+	binanceBookRetryDelay = 5 * time.Second
+
+	// binanceBookRecordInterval is how often a synced book is handed to the depth recorder
+	binanceBookRecordInterval = time.Second
+)
+
+// DepthSnapshotSource fetches the order book snapshots local futures books start from
+// Implemented by the Binance REST and WS-API clients
+type DepthSnapshotSource interface {
+	GetDepthSnapshot(ctx context.Context, symbol string, limit int) (*binance.OrderBookSnapshot, error)
+}
+
+// binanceBook is a futures order book kept from a snapshot and the @depth diffs following it
+type binanceBook struct {
+	localBook
+	lastUpdateID int64 // Final update ID of the last diff applied, or the snapshot's
+	bridged      bool  // A diff spanning the snapshot was applied; later ones must follow on by pu
+	synced       bool
+	fetching     bool
+	retryAt      time.Time
+	pending      []BinanceDepthData // Diffs received while unsynced, oldest first
+	recordedAt   time.Time
+}
+
+// newBinanceBook creates an empty, unsynced book
+func newBinanceBook() *binanceBook {
+	return &binanceBook{localBook: localBook{bids: make(map[string]string), asks: make(map[string]string)}}
+}
+
+// SetDepthSnapshotSource keeps a local futures order book per symbol, started from the source's
+// snapshots and updated by the @depth diffs, following Binance's book sync rules. Depth
+// snapshots persist this book; without a source nothing is recorded and handlers only see diffs
+func (bs *BinanceStream) SetDepthSnapshotSource(source DepthSnapshotSource) {
+	bs.booksMu.Lock()
+	defer bs.booksMu.Unlock()
+	bs.depthSource = source
+}
+
+// processFuturesBook applies a futures diff to its symbol's local book and hands it on to event
+// handlers; called on the symbol's pipeline. Unsynced books buffer the diff and fetch a snapshot
+func (bs *BinanceStream) processFuturesBook(data BinanceDepthData, stages *stageTimer) {
+	diff := data.Event()
+
+	bs.booksMu.Lock()
+	book := bs.books[data.Symbol]
+	if book == nil {
+		book = newBinanceBook()
+		bs.books[data.Symbol] = book
+	}
+
+	switch {
+	case bs.synthetic != nil:
+		// Synthetic depth events are whole books
+		book.load(data.Bids, data.Asks, data.FinalUpdateID)
+		book.bridged, book.synced = true, true
+		diff.Snapshot = true
+	case bs.depthSource == nil:
+		bs.booksMu.Unlock()
+		stages.mark(pipelineStageEnrich)
+		bs.events.emitDepth(diff)
+		return
+	case !book.synced:
+		book.buffer(data)
+		bs.fetchBookSnapshotLocked(data.Symbol, book)
+		bs.booksMu.Unlock()
+		stages.mark(pipelineStageEnrich)
+		return
+	case !book.apply(data):
+		log.Printf("Futures book for %s missed updates (pu %d after %d) - resyncing", data.Symbol, data.PrevFinalUpdateID, book.lastUpdateID)
+		book.synced, book.pending = false, nil
+		book.buffer(data)
+		bs.fetchBookSnapshotLocked(data.Symbol, book)
+		bs.booksMu.Unlock()
+		stages.mark(pipelineStageEnrich)
+		return
+	}
+	full := bs.recordableBookLocked(data.Symbol, book, diff.Time, false)
+	bs.booksMu.Unlock()
+	stages.mark(pipelineStageEnrich)
+
+	if full != nil {
+		bs.depthRecorder.Load().observe(*full)
+		stages.mark(pipelineStagePersist)
+	}
+	bs.events.emitDepth(diff)
+}
+
+// fetchBookSnapshotLocked starts fetching a snapshot for an unsynced book unless one is in
+// flight or a failed sync is backing off; the caller holds bs.booksMu
+func (bs *BinanceStream) fetchBookSnapshotLocked(symbol string, book *binanceBook) {
+	if book.fetching || time.Now().Before(book.retryAt) {
+		return
+	}
+	book.fetching = true
+	source := bs.depthSource
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), binanceBookFetchTimeout)
+		snapshot, err := source.GetDepthSnapshot(ctx, symbol, binanceBookDepth)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to fetch futures book snapshot for %s: %v", symbol, err)
+			bs.booksMu.Lock()
+			book.fetching = false
+			book.retryAt = time.Now().Add(binanceBookRetryDelay)
+			bs.booksMu.Unlock()
+			return
+		}
+
+		// Applied on the symbol's pipeline, in order with the diffs
+		bs.pipelines.submit(symbol, "depth:snapshot", PipelinePolicyBlock, time.Now(), func(stages *stageTimer) {
+			bs.applyBookSnapshot(symbol, book, snapshot, stages)
+		})
+	}()
+}
+
+// applyBookSnapshot loads a snapshot into a book and replays the diffs buffered since, then
+// hands the whole book to event handlers and the depth recorder; called on the symbol's pipeline
+func (bs *BinanceStream) applyBookSnapshot(symbol string, book *binanceBook, snapshot *binance.OrderBookSnapshot, stages *stageTimer) {
+	bs.booksMu.Lock()
+	book.fetching = false
+	book.load(snapshot.Bids, snapshot.Asks, snapshot.LastUpdateID)
+
+	pending := book.pending
+	book.pending = nil
+	for i, diff := range pending {
+		if !book.apply(diff) {
+			// The snapshot is older than the buffered diffs: fetch a newer one
+			log.Printf("Futures book snapshot for %s at %d does not meet buffered update %d - refetching", symbol, snapshot.LastUpdateID, diff.FirstUpdateID)
+			book.pending = pending[i:]
+			book.retryAt = time.Now().Add(binanceBookRetryDelay)
+			bs.booksMu.Unlock()
+			stages.mark(pipelineStageEnrich)
+			return
+		}
+	}
+	book.synced = true
+
+	at := time.UnixMilli(snapshot.TransactionTime)
+	if len(pending) > 0 {
+		at = time.UnixMilli(pending[len(pending)-1].EventTime)
+	}
+	full := bs.recordableBookLocked(symbol, book, at, true)
+	if full == nil {
+		full = book.snapshot(symbol, at)
+	}
+	bs.booksMu.Unlock()
+	stages.mark(pipelineStageEnrich)
+
+	log.Printf("Futures book for %s synced at update %d", symbol, book.lastUpdateID)
+	if recorder := bs.depthRecorder.Load(); recorder != nil {
+		recorder.observe(*full)
+		stages.mark(pipelineStagePersist)
+	}
+	bs.events.emitDepth(*full)
+	stages.mark(pipelineStageBroadcast)
+}
+
+// recordableBookLocked returns the whole book for the depth recorder, at most once per
+// binanceBookRecordInterval unless forced, and nil without a recorder; the caller holds bs.booksMu
+func (bs *BinanceStream) recordableBookLocked(symbol string, book *binanceBook, at time.Time, force bool) *models.DepthUpdate {
+	if bs.depthRecorder.Load() == nil {
+		return nil
+	}
+	now := time.Now()
+	if !force && now.Sub(book.recordedAt) < binanceBookRecordInterval {
+		return nil
+	}
+	book.recordedAt = now
+	return book.snapshot(symbol, at)
+}
+
+// syncedBooks returns the number of futures books in sync with the stream
+func (bs *BinanceStream) syncedBooks() int {
+	bs.booksMu.Lock()
+	defer bs.booksMu.Unlock()
+
+	synced := 0
+	for _, book := range bs.books {
+		if book.synced {
+			synced++
+		}
+	}
+	return synced
+}
+
+// load replaces the book's levels with a snapshot's
+func (b *binanceBook) load(bids, asks [][]string, lastUpdateID int64) {
+	b.bids = make(map[string]string, len(bids))
+	b.asks = make(map[string]string, len(asks))
+	applyBookLevels(b.bids, bids)
+	applyBookLevels(b.asks, asks)
+	b.lastUpdateID = lastUpdateID
+	b.bridged = false
+}
+
+// buffer keeps a diff until the snapshot arrives
+func (b *binanceBook) buffer(data BinanceDepthData) {
+	if len(b.pending) >= binanceBookBuffer {
+		b.pending = b.pending[1:]
+	}
+	b.pending = append(b.pending, data)
+}
+
+// apply applies a diff in sequence, reporting false when updates are missing: the first diff
+// applied must span the snapshot's update ID, and each later one follow on from the previous
+// Diffs already contained in the snapshot are skipped
+func (b *binanceBook) apply(data BinanceDepthData) bool {
+	if !b.bridged {
+		if data.FinalUpdateID < b.lastUpdateID {
+			return true
+		}
+		if data.FirstUpdateID > b.lastUpdateID {
+			return false
+		}
+		b.bridged = true
+	} else if data.PrevFinalUpdateID != b.lastUpdateID {
+		return false
+	}
+
+	applyBookLevels(b.bids, data.Bids)
+	applyBookLevels(b.asks, data.Asks)
+	b.lastUpdateID = data.FinalUpdateID
+	return true
+}
+
+// snapshot returns the top binanceBookDepth levels per side as a book replacement
+func (b *binanceBook) snapshot(symbol string, at time.Time) *models.DepthUpdate {
+	bids := sortedBookLevels(b.bids, true)
+	asks := sortedBookLevels(b.asks, false)
+	if len(bids) > binanceBookDepth {
+		bids = bids[:binanceBookDepth]
+	}
+	if len(asks) > binanceBookDepth {
+		asks = asks[:binanceBookDepth]
+	}
+	return &models.DepthUpdate{
+		Symbol:   symbol,
+		Bids:     bids,
+		Asks:     asks,
+		Snapshot: true,
+		Time:     at,
+	}
+}
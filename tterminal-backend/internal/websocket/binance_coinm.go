@@ -0,0 +1,135 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// EnableCoinMargined streams COIN-margined futures contracts ("BTCUSD_PERP") from the dstream
+// WebSocket at url alongside the USDⓈ-M streams
+// Contracts keep their bare symbols and are processed like USDⓈ-M futures: trades are persisted,
+// klines confirm bar closes and liquidations feed the tape. Volumes are in contracts, as Binance reports them
+func (bs *BinanceStream) EnableCoinMargined(url string, symbols []string) error {
+	bs.coinMURL = strings.TrimSuffix(url, "/")
+	bs.coinMSymbols = symbols
+	if !bs.isRunning || bs.synthetic != nil {
+		return nil
+	}
+	return bs.startCoinMStream()
+}
+
+// coinMStreamURL returns the COIN-margined Futures combined stream URL for the streamed contracts
+func (bs *BinanceStream) coinMStreamURL() string {
+	var streams []string
+	for _, symbol := range bs.coinMSymbols {
+		symbolLower := strings.ToLower(symbol)
+		streams = append(streams,
+			symbolLower+"@ticker",      // 24hr ticker statistics
+			symbolLower+"@depth@100ms", // Order book depth updates (100ms)
+			symbolLower+"@aggTrade",    // Aggregate trade data
+			symbolLower+"@kline_1m",    // 1-minute klines
+			symbolLower+"@kline_5m",    // 5-minute klines
+			symbolLower+"@kline_15m",   // 15-minute klines
+			symbolLower+"@markPrice",   // Mark price updates
+		)
+	}
+	streams = append(streams, "!forceOrder@arr") // COIN-margined liquidation orders
+
+	return bs.coinMURL + "/stream?streams=" + strings.Join(streams, "/")
+}
+
+// startCoinMStream connects to the Binance COIN-margined Futures WebSocket
+func (bs *BinanceStream) startCoinMStream() error {
+	url := bs.coinMStreamURL()
+	log.Printf("Connecting to COIN-margined Futures: %s", url)
+
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		return err
+	}
+
+	bs.coinMConn = conn
+	bs.health.connect(streamCoinM)
+
+	go bs.readCoinMMessages()
+	go bs.pingCoinMPeriodically()
+
+	return nil
+}
+
+// pingCoinMPeriodically sends ping messages to keep the COIN-margined connection alive
+func (bs *BinanceStream) pingCoinMPeriodically() {
+	ticker := time.NewTicker(20 * time.Second)
+	defer ticker.Stop()
+
+	for bs.isRunning {
+		select {
+		case <-ticker.C:
+			if bs.coinMConn != nil {
+				if err := bs.coinMConn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+					log.Printf("Failed to send COIN-margined Futures ping: %v", err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// readCoinMMessages reads and processes messages from the COIN-margined Futures WebSocket
+func (bs *BinanceStream) readCoinMMessages() {
+	defer bs.coinMConn.Close()
+
+	bs.coinMConn.SetPongHandler(func(appData string) error {
+		return nil
+	})
+
+	for bs.isRunning {
+		_, message, err := bs.coinMConn.ReadMessage()
+		if err != nil {
+			if bs.isRunning {
+				log.Printf("Error reading from Binance COIN-margined Futures WebSocket: %v", err)
+				bs.health.disconnect(streamCoinM)
+				bs.reconnectCoinM()
+			}
+			return
+		}
+
+		bs.processCoinMMessage(message)
+	}
+}
+
+// processCoinMMessage processes COIN-margined Futures messages, which share the USDⓈ-M formats
+func (bs *BinanceStream) processCoinMMessage(message []byte) {
+	bs.health.message(streamCoinM)
+
+	var combinedMsg BinanceCombinedStreamMessage
+	if err := json.Unmarshal(message, &combinedMsg); err != nil {
+		bs.parseDirectMessage(message, StreamTypeFutures)
+		return
+	}
+
+	bs.processCombinedMessage(combinedMsg, StreamTypeFutures)
+}
+
+// reconnectCoinM attempts to reconnect to the Binance COIN-margined Futures WebSocket
+func (bs *BinanceStream) reconnectCoinM() {
+	log.Println("Attempting to reconnect to Binance COIN-margined Futures WebSocket...")
+	bs.health.attempt(streamCoinM)
+	time.Sleep(5 * time.Second)
+	if bs.isRunning {
+		if err := bs.startCoinMStream(); err != nil {
+			log.Printf("COIN-margined Futures reconnection failed: %v", err)
+			time.Sleep(10 * time.Second)
+			bs.reconnectCoinM()
+		} else {
+			log.Println("Successfully reconnected to Binance COIN-margined Futures WebSocket")
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// backupConnection is a second connection to one Binance market, reading the same streams as
+// the primary; whichever connection delivers an event first wins and the other copy is dropped
+type backupConnection struct {
+	stream     string // Connection health name ("spot_backup", ...)
+	market     string // Market name for logs
+	streamType StreamType
+	url        func() string
+
+	conn        *websocket.Conn
+	lastMessage atomic.Int64 // Unix milliseconds
+}
+
+// EnableRedundantConnections opens a backup connection per Binance market (spot, futures and
+// COIN-margined futures when enabled) next to each primary, deduplicating events by trade ID,
+// book update ID or event time before they are processed, so a single dropped connection never
+// interrupts client feeds
+// Call it after EnableCoinMargined so the COIN-margined stream gets a backup too
+func (bs *BinanceStream) EnableRedundantConnections() {
+	if bs.dedup != nil {
+		return
+	}
+	bs.dedup = newSequenceFilter()
+	bs.backups = []*backupConnection{
+		{stream: streamSpot + "_backup", market: "Spot", streamType: StreamTypeSpot, url: bs.spotStreamURL},
+		{stream: streamFutures + "_backup", market: "Futures", streamType: StreamTypeFutures, url: bs.futuresStreamURL},
+	}
+	if len(bs.coinMSymbols) > 0 {
+		bs.backups = append(bs.backups, &backupConnection{
+			stream: streamCoinM + "_backup", market: "COIN-margined Futures", streamType: StreamTypeFutures, url: bs.coinMStreamURL,
+		})
+	}
+	if !bs.isRunning || bs.synthetic != nil {
+		return
+	}
+	bs.startBackups()
+}
+
+// startBackups connects every backup connection, retrying failed ones in the background
+func (bs *BinanceStream) startBackups() {
+	for _, backup := range bs.backups {
+		if err := bs.startBackup(backup); err != nil {
+			log.Printf("Failed to start %s backup stream: %v", backup.market, err)
+			bs.health.disconnect(backup.stream)
+			go bs.reconnectBackup(backup)
+		}
+	}
+}
+
+// stopBackups closes every backup connection
+func (bs *BinanceStream) stopBackups() {
+	for _, backup := range bs.backups {
+		if backup.conn != nil {
+			backup.conn.Close()
+			log.Printf("Binance %s backup WebSocket stream stopped", backup.market)
+		}
+	}
+}
+
+// startBackup connects a backup connection to its market's combined stream
+func (bs *BinanceStream) startBackup(backup *backupConnection) error {
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(backup.url(), nil)
+	if err != nil {
+		return err
+	}
+
+	backup.conn = conn
+	bs.health.connect(backup.stream)
+	log.Printf("Connected to Binance %s backup stream", backup.market)
+
+	go bs.readBackupMessages(backup, conn)
+	go bs.pingBackupPeriodically(conn, backup.market)
+
+	return nil
+}
+
+// pingBackupPeriodically sends ping messages to keep a backup connection alive
+func (bs *BinanceStream) pingBackupPeriodically(conn *websocket.Conn, market string) {
+	ticker := time.NewTicker(20 * time.Second)
+	defer ticker.Stop()
+
+	for bs.isRunning {
+		<-ticker.C
+		if err := conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+			log.Printf("Failed to send %s backup ping: %v", market, err)
+			return
+		}
+	}
+}
+
+// readBackupMessages reads a backup connection, feeding its messages through the same parsing
+// as the primary
+func (bs *BinanceStream) readBackupMessages(backup *backupConnection, conn *websocket.Conn) {
+	defer conn.Close()
+
+	conn.SetPongHandler(func(appData string) error {
+		return nil
+	})
+
+	for bs.isRunning {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if bs.isRunning {
+				log.Printf("Error reading from Binance %s backup WebSocket: %v", backup.market, err)
+				bs.health.disconnect(backup.stream)
+				bs.reconnectBackup(backup)
+			}
+			return
+		}
+
+		backup.lastMessage.Store(time.Now().UnixMilli())
+		var combinedMsg BinanceCombinedStreamMessage
+		if err := json.Unmarshal(message, &combinedMsg); err != nil {
+			bs.parseDirectMessage(message, backup.streamType)
+			continue
+		}
+		bs.processCombinedMessage(combinedMsg, backup.streamType)
+	}
+}
+
+// reconnectBackup attempts to reconnect a backup connection
+func (bs *BinanceStream) reconnectBackup(backup *backupConnection) {
+	for bs.isRunning {
+		bs.health.attempt(backup.stream)
+		time.Sleep(5 * time.Second)
+		if !bs.isRunning {
+			return
+		}
+		if err := bs.startBackup(backup); err != nil {
+			log.Printf("%s backup reconnection failed: %v", backup.market, err)
+			time.Sleep(10 * time.Second)
+			continue
+		}
+		log.Printf("Successfully reconnected to Binance %s backup WebSocket", backup.market)
+		return
+	}
+}
+
+// fresh reports whether an event is newer than the last one forwarded under its key; always
+// true without redundant connections
+func (bs *BinanceStream) fresh(key string, sequence int64) bool {
+	if bs.dedup == nil {
+		return true
+	}
+	return bs.dedup.fresh(key, sequence)
+}
+
+// sequenceFilter forwards an event only when its sequence number (trade ID, book update ID or
+// event time) is above the last forwarded one of its key
+// Each upstream connection delivers a key's events in order, so copies from the slower
+// connection are always at or below the last forwarded sequence
+type sequenceFilter struct {
+	mu   sync.Mutex
+	last map[string]int64
+
+	forwarded atomic.Int64
+	dropped   atomic.Int64
+}
+
+// newSequenceFilter creates an empty filter
+func newSequenceFilter() *sequenceFilter {
+	return &sequenceFilter{last: make(map[string]int64)}
+}
+
+// fresh records and forwards a sequence above the key's last one
+func (f *sequenceFilter) fresh(key string, sequence int64) bool {
+	f.mu.Lock()
+	last, seen := f.last[key]
+	if seen && sequence <= last {
+		f.mu.Unlock()
+		f.dropped.Add(1)
+		return false
+	}
+	f.last[key] = sequence
+	f.mu.Unlock()
+	f.forwarded.Add(1)
+	return true
+}
+
+// redundancyStats reports the backup connections and deduplication counters
+func (bs *BinanceStream) redundancyStats() map[string]interface{} {
+	if bs.dedup == nil {
+		return map[string]interface{}{"enabled": false}
+	}
+
+	backups := make(map[string]interface{}, len(bs.backups))
+	for _, backup := range bs.backups {
+		entry := map[string]interface{}{}
+		if last := backup.lastMessage.Load(); last > 0 {
+			entry["last_message_age_ms"] = time.Now().UnixMilli() - last
+		}
+		backups[backup.stream] = entry
+	}
+	return map[string]interface{}{
+		"enabled":            true,
+		"backups":            backups,
+		"forwarded":          bs.dedup.forwarded.Load(),
+		"duplicates_dropped": bs.dedup.dropped.Load(),
+	}
+}
+
+// klineSequence orders kline events of one symbol and interval: by event time, with the closing
+// event after an update carrying the same time
+func klineSequence(data *BinanceKlineData) int64 {
+	sequence := data.EventTime * 2
+	if data.Kline.IsClosed {
+		sequence++
+	}
+	return sequence
+}
+
+// tradeSequence returns a trade's ID; "a" holds the aggregate trade ID for aggTrade events
+func tradeSequence(data *BinanceTradeData) int64 {
+	if data.EventType == "aggTrade" {
+		return data.SellerOrderID
+	}
+	return data.TradeID
+}
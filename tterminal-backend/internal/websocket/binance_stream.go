@@ -6,7 +6,11 @@ import (
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"tterminal-backend/internal/synthetic"
+	"tterminal-backend/models"
 
 	"github.com/gorilla/websocket"
 )
@@ -19,23 +23,65 @@ const (
 	StreamTypeFutures StreamType = "futures"
 )
 
-// BinanceStream handles real-time data from Binance WebSocket (Spot + Futures)
+// BinanceStream handles real-time data from Binance WebSocket (Spot + Futures, plus COIN-margined futures when enabled)
 type BinanceStream struct {
 	hub         *Hub
 	spotConn    *websocket.Conn
 	futuresConn *websocket.Conn
+	coinMConn   *websocket.Conn
 	symbols     []string
-	isRunning   bool
-	lastPrices  map[string]float64
+	// COIN-margined contracts ("BTCUSD_PERP") streamed from coinMURL; none unless EnableCoinMargined is called
+	coinMSymbols []string
+	coinMURL     string
+	isRunning    bool
+	lastPrices   map[string]float64
+	pricesMu     sync.RWMutex // Guards lastPrices, which other goroutines read
+	// Account user data (positions, orders) from userDataURL; none unless EnableUserData is called
+	userDataURL       string
+	userDataKeys      UserDataListenKeys
+	userDataHandler   UserDataHandler
+	userDataConn      *websocket.Conn
+	userDataConnected atomic.Bool
+	userDataDisabled  atomic.Bool // Set by DisableUserData to stop reconnecting
+	// Per-symbol ingestion pipelines processing parsed events off the read loops
+	pipelines *IngestPipelines
+	// Guards the stored data below, written by the per-symbol pipelines
+	dataMu sync.RWMutex
 	// Enhanced data storage for volume profile
-	depthData map[string]*BinanceDepthData
-	tradeData map[string][]*BinanceTradeData
-	klineData map[string]*BinanceKlineData
+	depthData map[string]models.DepthUpdate
+	tradeData map[string][]models.TradeEvent
+	klineData map[string]models.KlineEvent
 	// Futures-specific data
 	futuresTickerData map[string]*BinanceFuturesTickerData
-	markPriceData     map[string]*BinanceMarkPriceData
+	markPriceData     map[string]models.MarkPriceEvent
 	fundingRateData   map[string]*BinanceFundingRateData
-	liquidationData   map[string][]*BinanceLiquidationData
+	liquidationData   map[string][]models.LiquidationEvent
+	// Rolling VWAP/delta/size context for enriched trade updates
+	tradeEnricher *TradeEnricher
+	// Premium index and predicted funding rate per symbol
+	fundingPredictor *FundingPredictor
+	// Optional persistence of futures aggregate trades (set after start)
+	tradeRecorder atomic.Pointer[tradeRecorder]
+	// Optional periodic persistence of futures book snapshots (set after start)
+	depthRecorder atomic.Pointer[depthRecorder]
+	// Local futures books kept from snapshots and diffs (see SetDepthSnapshotSource)
+	booksMu     sync.Mutex
+	books       map[string]*binanceBook
+	depthSource DepthSnapshotSource
+	// Connection state, last message times and reconnects of the spot and futures streams
+	health *connectionHealth
+	// Exchange-aligned bar close events, confirmed by closed futures klines
+	barClose        *BarCloseScheduler
+	barCloseStarted sync.Once
+	// Normalized trades, book updates and liquidations for in-process consumers
+	events *MarketEvents
+	// Backup connections and the filter dropping events already delivered by another
+	// connection; nil unless EnableRedundantConnections is called
+	backups []*backupConnection
+	dedup   *sequenceFilter
+	// Offline market data generator replacing the Binance connections (nil = live)
+	synthetic     *synthetic.Generator
+	syntheticStop chan struct{}
 }
 
 // BinanceTickerData represents Binance 24hr ticker data (Spot)
@@ -133,13 +179,14 @@ type BinanceLiquidationData struct {
 
 // BinanceDepthData represents order book depth data
 type BinanceDepthData struct {
-	EventType     string     `json:"e"` // Event type
-	EventTime     int64      `json:"E"` // Event time
-	Symbol        string     `json:"s"` // Symbol
-	FirstUpdateID int64      `json:"U"` // First update ID in event
-	FinalUpdateID int64      `json:"u"` // Final update ID in event
-	Bids          [][]string `json:"b"` // Bids to be updated
-	Asks          [][]string `json:"a"` // Asks to be updated
+	EventType         string     `json:"e"`  // Event type
+	EventTime         int64      `json:"E"`  // Event time
+	Symbol            string     `json:"s"`  // Symbol
+	FirstUpdateID     int64      `json:"U"`  // First update ID in event
+	FinalUpdateID     int64      `json:"u"`  // Final update ID in event
+	PrevFinalUpdateID int64      `json:"pu"` // Final update ID of the previous event (futures only)
+	Bids              [][]string `json:"b"`  // Bids to be updated
+	Asks              [][]string `json:"a"`  // Asks to be updated
 }
 
 // BinanceTradeData represents individual trade data
@@ -191,22 +238,41 @@ type BinanceCombinedStreamMessage struct {
 
 // NewBinanceStream creates a new enhanced Binance WebSocket stream (Spot + Futures)
 func NewBinanceStream(hub *Hub, symbols []string) *BinanceStream {
-	return &BinanceStream{
+	bs := &BinanceStream{
 		hub:               hub,
 		symbols:           symbols,
 		lastPrices:        make(map[string]float64),
-		depthData:         make(map[string]*BinanceDepthData),
-		tradeData:         make(map[string][]*BinanceTradeData),
-		klineData:         make(map[string]*BinanceKlineData),
+		depthData:         make(map[string]models.DepthUpdate),
+		tradeData:         make(map[string][]models.TradeEvent),
+		klineData:         make(map[string]models.KlineEvent),
 		futuresTickerData: make(map[string]*BinanceFuturesTickerData),
-		markPriceData:     make(map[string]*BinanceMarkPriceData),
+		markPriceData:     make(map[string]models.MarkPriceEvent),
 		fundingRateData:   make(map[string]*BinanceFundingRateData),
-		liquidationData:   make(map[string][]*BinanceLiquidationData),
+		liquidationData:   make(map[string][]models.LiquidationEvent),
+		books:             make(map[string]*binanceBook),
+		tradeEnricher:     NewTradeEnricher(),
+		fundingPredictor:  NewFundingPredictor(),
+		health:            newConnectionHealth(),
+		pipelines:         newIngestPipelines(),
 	}
+	bs.barClose = newBarCloseScheduler(hub, bs.GetConnectedSymbols)
+	bs.events = newMarketEvents()
+	return bs
 }
 
 // Start connects to both Binance Spot and Futures WebSocket streams
 func (bs *BinanceStream) Start() error {
+	// The bar close schedule keeps running across stream restarts
+	bs.barCloseStarted.Do(func() {
+		go bs.barClose.run(make(chan struct{}))
+	})
+
+	if bs.synthetic != nil {
+		bs.startSyntheticFeed()
+		bs.isRunning = true
+		return nil
+	}
+
 	log.Println("Connecting to Enhanced Binance WebSocket streams (Spot + Futures)...")
 
 	// Start Spot stream
@@ -219,14 +285,34 @@ func (bs *BinanceStream) Start() error {
 		log.Printf("Failed to start Futures stream: %v", err)
 	}
 
+	// Start COIN-margined Futures stream
+	if len(bs.coinMSymbols) > 0 {
+		if err := bs.startCoinMStream(); err != nil {
+			log.Printf("Failed to start COIN-margined Futures stream: %v", err)
+		}
+	}
+
 	bs.isRunning = true
+
+	// Start the backup connections next to the primaries
+	if bs.dedup != nil {
+		bs.startBackups()
+	}
+
+	// Start the account's user data stream, retrying in the background until it connects
+	if bs.userDataKeys != nil {
+		if err := bs.startUserDataStream(); err != nil {
+			log.Printf("Failed to start user data stream: %v", err)
+			go bs.reconnectUserData()
+		}
+	}
 	log.Printf("Connected to Enhanced Binance WebSocket - Streaming %d symbols with Spot + Futures data", len(bs.symbols))
 
 	return nil
 }
 
-// startSpotStream connects to Binance Spot WebSocket
-func (bs *BinanceStream) startSpotStream() error {
+// spotStreamURL returns the Binance Spot combined stream URL for the streamed symbols
+func (bs *BinanceStream) spotStreamURL() string {
 	// Create comprehensive stream names for Spot data
 	var streams []string
 	for _, symbol := range bs.symbols {
@@ -242,9 +328,12 @@ func (bs *BinanceStream) startSpotStream() error {
 	}
 
 	// Use Binance Spot combined stream
-	streamNames := strings.Join(streams, "/")
-	url := "wss://stream.binance.com:9443/stream?streams=" + streamNames
+	return "wss://stream.binance.com:9443/stream?streams=" + strings.Join(streams, "/")
+}
 
+// startSpotStream connects to Binance Spot WebSocket
+func (bs *BinanceStream) startSpotStream() error {
+	url := bs.spotStreamURL()
 	log.Printf("Connecting to Spot: %s", url)
 
 	// Connect to Binance Spot WebSocket
@@ -257,6 +346,7 @@ func (bs *BinanceStream) startSpotStream() error {
 	}
 
 	bs.spotConn = conn
+	bs.health.connect(streamSpot)
 
 	// Start reading Spot messages
 	go bs.readSpotMessages()
@@ -267,8 +357,8 @@ func (bs *BinanceStream) startSpotStream() error {
 	return nil
 }
 
-// startFuturesStream connects to Binance Futures WebSocket
-func (bs *BinanceStream) startFuturesStream() error {
+// futuresStreamURL returns the Binance Futures combined stream URL for the streamed symbols
+func (bs *BinanceStream) futuresStreamURL() string {
 	// Create comprehensive stream names for Futures data
 	var streams []string
 	for _, symbol := range bs.symbols {
@@ -292,9 +382,12 @@ func (bs *BinanceStream) startFuturesStream() error {
 	)
 
 	// Use Binance Futures combined stream
-	streamNames := strings.Join(streams, "/")
-	url := "wss://fstream.binance.com/stream?streams=" + streamNames
+	return "wss://fstream.binance.com/stream?streams=" + strings.Join(streams, "/")
+}
 
+// startFuturesStream connects to Binance Futures WebSocket
+func (bs *BinanceStream) startFuturesStream() error {
+	url := bs.futuresStreamURL()
 	log.Printf("Connecting to Futures: %s", url)
 
 	// Connect to Binance Futures WebSocket
@@ -307,6 +400,7 @@ func (bs *BinanceStream) startFuturesStream() error {
 	}
 
 	bs.futuresConn = conn
+	bs.health.connect(streamFutures)
 
 	// Start reading Futures messages
 	go bs.readFuturesMessages()
@@ -320,6 +414,8 @@ func (bs *BinanceStream) startFuturesStream() error {
 // Stop disconnects from both Binance WebSocket streams
 func (bs *BinanceStream) Stop() {
 	bs.isRunning = false
+	bs.stopSyntheticFeed()
+	bs.health.stopped()
 
 	if bs.spotConn != nil {
 		bs.spotConn.Close()
@@ -330,6 +426,19 @@ func (bs *BinanceStream) Stop() {
 		bs.futuresConn.Close()
 		log.Println("Binance Futures WebSocket stream stopped")
 	}
+
+	if bs.coinMConn != nil {
+		bs.coinMConn.Close()
+		log.Println("Binance COIN-margined Futures WebSocket stream stopped")
+	}
+
+	bs.stopBackups()
+
+	if bs.userDataConn != nil {
+		bs.userDataConn.Close()
+		bs.userDataConnected.Store(false)
+		log.Println("Binance user data stream stopped")
+	}
 }
 
 // pingSpotPeriodically sends ping messages to keep Spot connection alive
@@ -381,6 +490,7 @@ func (bs *BinanceStream) readSpotMessages() {
 		if err != nil {
 			if bs.isRunning {
 				log.Printf("Error reading from Binance Spot WebSocket: %v", err)
+				bs.health.disconnect(streamSpot)
 				bs.reconnectSpot()
 			}
 			return
@@ -403,6 +513,7 @@ func (bs *BinanceStream) readFuturesMessages() {
 		if err != nil {
 			if bs.isRunning {
 				log.Printf("Error reading from Binance Futures WebSocket: %v", err)
+				bs.health.disconnect(streamFutures)
 				bs.reconnectFutures()
 			}
 			return
@@ -414,6 +525,8 @@ func (bs *BinanceStream) readFuturesMessages() {
 
 // processSpotMessage processes Spot WebSocket messages
 func (bs *BinanceStream) processSpotMessage(message []byte) {
+	bs.health.message(streamSpot)
+
 	// Parse combined stream message
 	var combinedMsg BinanceCombinedStreamMessage
 	if err := json.Unmarshal(message, &combinedMsg); err != nil {
@@ -426,6 +539,8 @@ func (bs *BinanceStream) processSpotMessage(message []byte) {
 
 // processFuturesMessage processes Futures WebSocket messages
 func (bs *BinanceStream) processFuturesMessage(message []byte) {
+	bs.health.message(streamFutures)
+
 	// Parse combined stream message
 	var combinedMsg BinanceCombinedStreamMessage
 	if err := json.Unmarshal(message, &combinedMsg); err != nil {
@@ -436,8 +551,9 @@ func (bs *BinanceStream) processFuturesMessage(message []byte) {
 	bs.processCombinedMessage(combinedMsg, StreamTypeFutures)
 }
 
-// processCombinedMessage processes messages from combined stream
+// processCombinedMessage parses messages from combined stream and hands them to their symbol's pipeline
 func (bs *BinanceStream) processCombinedMessage(msg BinanceCombinedStreamMessage, streamType StreamType) {
+	received := time.Now()
 	streamParts := strings.Split(msg.Stream, "@")
 	if len(streamParts) < 2 {
 		return
@@ -456,45 +572,54 @@ func (bs *BinanceStream) processCombinedMessage(msg BinanceCombinedStreamMessage
 	case streamName == "ticker":
 		if streamType == StreamTypeSpot {
 			var tickerData BinanceTickerData
-			if err := json.Unmarshal(dataBytes, &tickerData); err == nil {
-				bs.processSpotPriceUpdate(tickerData)
+			if err := json.Unmarshal(dataBytes, &tickerData); err == nil && bs.fresh("ticker:spot:"+tickerData.Symbol, tickerData.EventTime) {
+				bs.pipelines.submit(tickerData.Symbol, "ticker:spot", PipelinePolicyConflate, received, func(stages *stageTimer) {
+					bs.processSpotPriceUpdate(tickerData, stages)
+				})
 			}
 		} else {
 			var futuresTickerData BinanceFuturesTickerData
-			if err := json.Unmarshal(dataBytes, &futuresTickerData); err == nil {
-				bs.processFuturesPriceUpdate(futuresTickerData)
+			if err := json.Unmarshal(dataBytes, &futuresTickerData); err == nil && bs.fresh("ticker:futures:"+futuresTickerData.Symbol, futuresTickerData.EventTime) {
+				bs.pipelines.submit(futuresTickerData.Symbol, "ticker:futures", PipelinePolicyConflate, received, func(stages *stageTimer) {
+					bs.processFuturesPriceUpdate(futuresTickerData, stages)
+				})
 			}
 		}
 
 	case strings.HasPrefix(streamName, "depth"):
 		var depthData BinanceDepthData
 		if err := json.Unmarshal(dataBytes, &depthData); err == nil {
-			bs.processDepthUpdate(depthData)
+			bs.submitDepthUpdate(depthData, streamType, received)
 		}
 
 	case streamName == "trade" || streamName == "aggTrade":
 		var tradeData BinanceTradeData
 		if err := json.Unmarshal(dataBytes, &tradeData); err == nil {
-			bs.processTradeUpdate(tradeData)
+			bs.submitTradeUpdate(tradeData, received)
 		}
 
 	case strings.HasPrefix(streamName, "kline"):
 		var klineData BinanceKlineData
 		if err := json.Unmarshal(dataBytes, &klineData); err == nil {
-			bs.processKlineUpdate(klineData)
+			bs.submitKlineUpdate(klineData, streamType, received)
 		}
 
 	case streamName == "markPrice":
 		var markPriceData BinanceMarkPriceData
 		if err := json.Unmarshal(dataBytes, &markPriceData); err == nil {
-			bs.processMarkPriceUpdate(markPriceData)
+			bs.submitMarkPriceUpdate(markPriceData, received)
 		}
 
 	case msg.Stream == "!forceOrder@arr":
 		log.Printf("LIQUIDATION STREAM: Received liquidation stream message: %s", string(dataBytes))
 		var liquidationData BinanceLiquidationData
 		if err := json.Unmarshal(dataBytes, &liquidationData); err == nil {
-			bs.processLiquidationUpdate(liquidationData)
+			if !bs.fresh("liquidation:"+liquidationData.LiquidationOrder.Symbol, liquidationData.EventTime) {
+				return
+			}
+			bs.pipelines.submit(liquidationData.LiquidationOrder.Symbol, "liquidation", PipelinePolicyBlock, received, func(stages *stageTimer) {
+				bs.processLiquidationUpdate(liquidationData, stages)
+			})
 		} else {
 			log.Printf("ERROR: Error parsing liquidation data: %v", err)
 		}
@@ -504,7 +629,7 @@ func (bs *BinanceStream) processCombinedMessage(msg BinanceCombinedStreamMessage
 		var markPriceArray []BinanceMarkPriceData
 		if err := json.Unmarshal(dataBytes, &markPriceArray); err == nil {
 			for _, markPrice := range markPriceArray {
-				bs.processMarkPriceUpdate(markPrice)
+				bs.submitMarkPriceUpdate(markPrice, received)
 			}
 		}
 	}
@@ -512,18 +637,29 @@ func (bs *BinanceStream) processCombinedMessage(msg BinanceCombinedStreamMessage
 
 // parseDirectMessage handles direct messages (fallback)
 func (bs *BinanceStream) parseDirectMessage(message []byte, streamType StreamType) {
+	received := time.Now()
 	if streamType == StreamTypeSpot {
 		// Try parsing as spot ticker data
 		var tickerData BinanceTickerData
 		if err := json.Unmarshal(message, &tickerData); err == nil && tickerData.EventType == "24hrTicker" {
-			bs.processSpotPriceUpdate(tickerData)
+			if !bs.fresh("ticker:spot:"+tickerData.Symbol, tickerData.EventTime) {
+				return
+			}
+			bs.pipelines.submit(tickerData.Symbol, "ticker:spot", PipelinePolicyConflate, received, func(stages *stageTimer) {
+				bs.processSpotPriceUpdate(tickerData, stages)
+			})
 			return
 		}
 	} else {
 		// Try parsing as futures ticker data
 		var futuresTickerData BinanceFuturesTickerData
 		if err := json.Unmarshal(message, &futuresTickerData); err == nil && futuresTickerData.EventType == "24hrTicker" {
-			bs.processFuturesPriceUpdate(futuresTickerData)
+			if !bs.fresh("ticker:futures:"+futuresTickerData.Symbol, futuresTickerData.EventTime) {
+				return
+			}
+			bs.pipelines.submit(futuresTickerData.Symbol, "ticker:futures", PipelinePolicyConflate, received, func(stages *stageTimer) {
+				bs.processFuturesPriceUpdate(futuresTickerData, stages)
+			})
 			return
 		}
 	}
@@ -531,38 +667,86 @@ func (bs *BinanceStream) parseDirectMessage(message []byte, streamType StreamTyp
 	// Common parsing for both types
 	var depthData BinanceDepthData
 	if err := json.Unmarshal(message, &depthData); err == nil && depthData.EventType == "depthUpdate" {
-		bs.processDepthUpdate(depthData)
+		bs.submitDepthUpdate(depthData, streamType, received)
 		return
 	}
 
 	var tradeData BinanceTradeData
 	if err := json.Unmarshal(message, &tradeData); err == nil && (tradeData.EventType == "trade" || tradeData.EventType == "aggTrade") {
-		bs.processTradeUpdate(tradeData)
+		bs.submitTradeUpdate(tradeData, received)
 		return
 	}
 
 	var klineData BinanceKlineData
 	if err := json.Unmarshal(message, &klineData); err == nil && klineData.EventType == "kline" {
-		bs.processKlineUpdate(klineData)
+		bs.submitKlineUpdate(klineData, streamType, received)
+		return
+	}
+}
+
+// submitDepthUpdate hands a book diff to its symbol's pipeline; diffs only make sense applied in
+// sequence, so they are never conflated or dropped
+func (bs *BinanceStream) submitDepthUpdate(data BinanceDepthData, streamType StreamType, received time.Time) {
+	if !bs.fresh("depth:"+string(streamType)+":"+data.Symbol, data.FinalUpdateID) {
+		return
+	}
+	bs.pipelines.submit(data.Symbol, "depth:"+string(streamType), PipelinePolicyBlock, received, func(stages *stageTimer) {
+		bs.processDepthUpdate(data, streamType, stages)
+	})
+}
+
+// submitTradeUpdate hands a trade to its symbol's pipeline; futures aggregate trades are persisted
+// and never dropped, spot trades are only broadcast and dropped when the pipeline is full
+func (bs *BinanceStream) submitTradeUpdate(data BinanceTradeData, received time.Time) {
+	if !bs.fresh(data.EventType+":"+data.Symbol, tradeSequence(&data)) {
+		return
+	}
+	policy := PipelinePolicyDrop
+	if data.EventType == "aggTrade" {
+		policy = PipelinePolicyBlock
+	}
+	bs.pipelines.submit(data.Symbol, data.EventType, policy, received, func(stages *stageTimer) {
+		bs.processTradeUpdate(data, stages)
+	})
+}
+
+// submitKlineUpdate hands a kline to its symbol's pipeline; klines confirm bar closes and are never dropped
+func (bs *BinanceStream) submitKlineUpdate(data BinanceKlineData, streamType StreamType, received time.Time) {
+	if !bs.fresh("kline:"+string(streamType)+":"+data.Symbol+":"+data.Kline.Interval, klineSequence(&data)) {
+		return
+	}
+	bs.pipelines.submit(data.Symbol, "kline", PipelinePolicyBlock, received, func(stages *stageTimer) {
+		bs.processKlineUpdate(data, streamType, stages)
+	})
+}
+
+// submitMarkPriceUpdate hands a mark price to its symbol's pipeline; a newer mark price replaces one still waiting
+func (bs *BinanceStream) submitMarkPriceUpdate(data BinanceMarkPriceData, received time.Time) {
+	if !bs.fresh("markPrice:"+data.Symbol, data.EventTime) {
 		return
 	}
+	bs.pipelines.submit(data.Symbol, "markPrice", PipelinePolicyConflate, received, func(stages *stageTimer) {
+		bs.processMarkPriceUpdate(data, stages)
+	})
 }
 
 // processSpotPriceUpdate processes and broadcasts Spot price updates
-func (bs *BinanceStream) processSpotPriceUpdate(data BinanceTickerData) {
-	bs.processPriceUpdate(data.Symbol, data.LastPrice, data.PriceChange, data.PriceChangePercent, data.TotalTradedVolume, "spot")
+func (bs *BinanceStream) processSpotPriceUpdate(data BinanceTickerData, stages *stageTimer) {
+	bs.processPriceUpdate(data.Symbol, data.LastPrice, data.PriceChange, data.PriceChangePercent, data.TotalTradedVolume, "spot", stages)
 }
 
 // processFuturesPriceUpdate processes and broadcasts Futures price updates
-func (bs *BinanceStream) processFuturesPriceUpdate(data BinanceFuturesTickerData) {
+func (bs *BinanceStream) processFuturesPriceUpdate(data BinanceFuturesTickerData, stages *stageTimer) {
 	// Store futures ticker data
+	bs.dataMu.Lock()
 	bs.futuresTickerData[data.Symbol] = &data
+	bs.dataMu.Unlock()
 
-	bs.processPriceUpdate(data.Symbol, data.LastPrice, data.PriceChange, data.PriceChangePercent, data.TotalTradedVolume, "futures")
+	bs.processPriceUpdate(data.Symbol, data.LastPrice, data.PriceChange, data.PriceChangePercent, data.TotalTradedVolume, "futures", stages)
 }
 
 // processPriceUpdate processes and broadcasts price updates (unified)
-func (bs *BinanceStream) processPriceUpdate(symbol, lastPriceStr, priceChangeStr, priceChangePercentStr, volumeStr, source string) {
+func (bs *BinanceStream) processPriceUpdate(symbol, lastPriceStr, priceChangeStr, priceChangePercentStr, volumeStr, source string, stages *stageTimer) {
 	// Parse price values with enhanced error handling
 	lastPrice, err := strconv.ParseFloat(lastPriceStr, 64)
 	if err != nil {
@@ -589,7 +773,9 @@ func (bs *BinanceStream) processPriceUpdate(symbol, lastPriceStr, priceChangeStr
 	}
 
 	// ULTRA-FAST real-time updates: Update on ANY price movement for maximum responsiveness
+	bs.pricesMu.RLock()
 	lastKnownPrice, exists := bs.lastPrices[symbol]
+	bs.pricesMu.RUnlock()
 	if exists && lastPrice == lastKnownPrice {
 		return // Only skip if price is exactly the same (no movement at all)
 	}
@@ -605,7 +791,9 @@ func (bs *BinanceStream) processPriceUpdate(symbol, lastPriceStr, priceChangeStr
 	}
 
 	// Update last known price
+	bs.pricesMu.Lock()
 	bs.lastPrices[symbol] = lastPrice
+	bs.pricesMu.Unlock()
 
 	// Create enhanced price update message
 	update := PriceUpdate{
@@ -617,6 +805,7 @@ func (bs *BinanceStream) processPriceUpdate(symbol, lastPriceStr, priceChangeStr
 		Volume:        volume,
 		Timestamp:     time.Now().UnixMilli(),
 	}
+	stages.mark(pipelineStageEnrich)
 
 	// Debug logging for broadcasts
 	if symbol == "BTCUSDT" {
@@ -625,40 +814,57 @@ func (bs *BinanceStream) processPriceUpdate(symbol, lastPriceStr, priceChangeStr
 
 	// Broadcast to all subscribed clients
 	bs.hub.BroadcastPriceUpdate(update)
+
+	// Embedded lite connections follow the futures price only
+	if source == "futures" {
+		bs.hub.QueueLitePrice(update)
+	}
+
+	bs.events.emitPrice(models.PriceTick{
+		Symbol: symbol,
+		Market: source,
+		Price:  lastPrice,
+		Time:   time.UnixMilli(update.Timestamp),
+	})
+	stages.mark(pipelineStageBroadcast)
 }
 
 // processMarkPriceUpdate processes Futures mark price updates
-func (bs *BinanceStream) processMarkPriceUpdate(data BinanceMarkPriceData) {
-	// Store mark price data
-	bs.markPriceData[data.Symbol] = &data
-
-	// Parse mark price
-	markPrice, err := strconv.ParseFloat(data.MarkPrice, 64)
+func (bs *BinanceStream) processMarkPriceUpdate(data BinanceMarkPriceData, stages *stageTimer) {
+	// Convert at the edge: the cache and everything downstream see the normalized mark price
+	event, err := data.Event()
 	if err != nil {
 		return
 	}
 
-	fundingRate, err := strconv.ParseFloat(data.FundingRate, 64)
-	if err != nil {
-		return
-	}
+	// Store mark price data
+	bs.dataMu.Lock()
+	bs.markPriceData[data.Symbol] = event
+	bs.dataMu.Unlock()
+
+	// Update premium index and predicted funding for the current window
+	prediction := bs.fundingPredictor.Update(data.Symbol, event.MarkPrice, event.IndexPrice, data.NextFundingTime, data.EventTime)
 
 	// Create mark price update message
 	markPriceUpdate := map[string]interface{}{
-		"type":              "mark_price_update",
-		"symbol":            data.Symbol,
-		"mark_price":        markPrice,
-		"funding_rate":      fundingRate,
-		"next_funding_time": data.NextFundingTime,
-		"timestamp":         time.Now().UnixMilli(),
+		"type":                   "mark_price_update",
+		"symbol":                 data.Symbol,
+		"mark_price":             event.MarkPrice,
+		"funding_rate":           event.FundingRate,
+		"next_funding_time":      data.NextFundingTime,
+		"premium_index":          prediction.PremiumIndex,
+		"predicted_funding_rate": prediction.PredictedFundingRate,
+		"timestamp":              time.Now().UnixMilli(),
 	}
+	stages.mark(pipelineStageEnrich)
 
 	// Broadcast mark price update
 	bs.hub.BroadcastMarkPriceUpdate(markPriceUpdate)
+	stages.mark(pipelineStageBroadcast)
 }
 
 // processLiquidationUpdate processes Futures liquidation updates
-func (bs *BinanceStream) processLiquidationUpdate(data BinanceLiquidationData) {
+func (bs *BinanceStream) processLiquidationUpdate(data BinanceLiquidationData, stages *stageTimer) {
 	// Debug logging for liquidation data
 	log.Printf("LIQUIDATION RECEIVED: Symbol=%s, Side=%s, Price=%s, AvgPrice=%s, Qty=%s",
 		data.LiquidationOrder.Symbol,
@@ -667,62 +873,42 @@ func (bs *BinanceStream) processLiquidationUpdate(data BinanceLiquidationData) {
 		data.LiquidationOrder.AveragePrice,
 		data.LiquidationOrder.OriginalQuantity)
 
-	// Store liquidation data (keep last 1000 per symbol)
+	// Convert at the edge: everything downstream sees the normalized liquidation
+	// (the average price is the actual liquidation price)
 	symbol := data.LiquidationOrder.Symbol
-	if bs.liquidationData[symbol] == nil {
-		bs.liquidationData[symbol] = make([]*BinanceLiquidationData, 0, 1000)
-	}
-
-	liquidations := bs.liquidationData[symbol]
-	liquidations = append(liquidations, &data)
-
-	// Keep only recent liquidations (last 1000)
-	if len(liquidations) > 1000 {
-		liquidations = liquidations[len(liquidations)-1000:]
-	}
-	bs.liquidationData[symbol] = liquidations
-
-	// Parse liquidation data - use AVERAGE PRICE for accuracy (actual liquidation price)
-	price, err := strconv.ParseFloat(data.LiquidationOrder.AveragePrice, 64)
-	if err != nil {
-		// Fallback to order price if average price is not available
-		price, err = strconv.ParseFloat(data.LiquidationOrder.Price, 64)
-		if err != nil {
-			log.Printf("ERROR: Error parsing liquidation price for %s: %v", symbol, err)
-			return
-		}
-	}
-
-	quantity, err := strconv.ParseFloat(data.LiquidationOrder.OriginalQuantity, 64)
+	event, err := data.Event()
 	if err != nil {
-		log.Printf("ERROR: Error parsing liquidation quantity for %s: %v", symbol, err)
+		log.Printf("ERROR: Error parsing liquidation for %s: %v", symbol, err)
 		return
 	}
 
-	// Create liquidation update message with accurate data
-	liquidationUpdate := map[string]interface{}{
-		"type":         "liquidation_update",
-		"symbol":       symbol,
-		"side":         data.LiquidationOrder.Side,
-		"price":        price,                       // Using average price (actual liquidation price)
-		"order_price":  data.LiquidationOrder.Price, // Include order price for reference
-		"quantity":     quantity,
-		"trade_time":   data.LiquidationOrder.TradeTime,
-		"timestamp":    time.Now().UnixMilli(),
-		"order_status": data.LiquidationOrder.OrderStatus,
-	}
+	// Store liquidation data (keep last 1000 per symbol)
+	bs.dataMu.Lock()
+	recordLiquidation(bs.liquidationData, event)
+	bs.dataMu.Unlock()
+	stages.mark(pipelineStageEnrich)
 
 	log.Printf("BROADCAST: Broadcasting liquidation: %s %s $%.2f (qty: %.4f)",
-		symbol, data.LiquidationOrder.Side, price, quantity)
+		symbol, event.Side, event.Price, event.Quantity)
 
-	// Broadcast liquidation update
-	bs.hub.BroadcastLiquidationUpdate(liquidationUpdate)
+	publishLiquidation(bs.hub, bs.events, event)
+	stages.mark(pipelineStageBroadcast)
 }
 
 // processDepthUpdate processes order book depth updates for volume profile
-func (bs *BinanceStream) processDepthUpdate(data BinanceDepthData) {
+func (bs *BinanceStream) processDepthUpdate(data BinanceDepthData, streamType StreamType, stages *stageTimer) {
 	// Store depth data for volume profile calculations
-	bs.depthData[data.Symbol] = &data
+	bs.dataMu.Lock()
+	bs.depthData[data.Symbol] = data.Event()
+	bs.dataMu.Unlock()
+
+	// Futures diffs update the local book, sampled for support/resistance analysis, and are
+	// handed to event handlers
+	if streamType == StreamTypeFutures {
+		bs.processFuturesBook(data, stages)
+	} else {
+		stages.mark(pipelineStageEnrich)
+	}
 
 	// Create depth update message for clients
 	depthUpdate := map[string]interface{}{
@@ -735,54 +921,66 @@ func (bs *BinanceStream) processDepthUpdate(data BinanceDepthData) {
 
 	// Broadcast depth update
 	bs.hub.BroadcastDepthUpdate(depthUpdate)
+	stages.mark(pipelineStageBroadcast)
 }
 
 // processTradeUpdate processes individual trade data for volume profile
-func (bs *BinanceStream) processTradeUpdate(data BinanceTradeData) {
+func (bs *BinanceStream) processTradeUpdate(data BinanceTradeData, stages *stageTimer) {
+	// Convert at the edge: the cache and everything downstream see the normalized trade
+	trade, err := data.Event()
+	if err != nil {
+		return
+	}
+	price, quantity := trade.Price, trade.Quantity
+
 	// Store recent trades (keep last 1000 trades per symbol)
+	bs.dataMu.Lock()
 	if bs.tradeData[data.Symbol] == nil {
-		bs.tradeData[data.Symbol] = make([]*BinanceTradeData, 0, 1000)
+		bs.tradeData[data.Symbol] = make([]models.TradeEvent, 0, 1000)
 	}
 
 	trades := bs.tradeData[data.Symbol]
-	trades = append(trades, &data)
+	trades = append(trades, trade)
 
 	// Keep only recent trades (last 1000)
 	if len(trades) > 1000 {
 		trades = trades[len(trades)-1000:]
 	}
 	bs.tradeData[data.Symbol] = trades
+	bs.dataMu.Unlock()
 
-	// Parse trade data
-	price, err := strconv.ParseFloat(data.Price, 64)
-	if err != nil {
-		return
-	}
+	// Create trade update message
+	tradeUpdate := TradeUpdateMessage(data.Symbol, price, quantity, data.IsBuyerMaker, data.TradeTime, time.Now().UnixMilli())
 
-	quantity, err := strconv.ParseFloat(data.Quantity, 64)
-	if err != nil {
-		return
-	}
+	// Compute rolling context for clients that enabled trade enrichment
+	tradeContext := bs.tradeEnricher.Update(data.Symbol, price, quantity, data.IsBuyerMaker, data.TradeTime)
+	stages.mark(pipelineStageEnrich)
 
-	// Create trade update message
-	tradeUpdate := map[string]interface{}{
-		"type":           "trade_update",
-		"symbol":         data.Symbol,
-		"price":          price,
-		"quantity":       quantity,
-		"is_buyer_maker": data.IsBuyerMaker,
-		"trade_time":     data.TradeTime,
-		"timestamp":      time.Now().UnixMilli(),
+	// Persist and publish futures aggregate trades
+	if trade.Market == "futures" {
+		record := trade.Record()
+		if recorder := bs.tradeRecorder.Load(); recorder != nil {
+			recorder.record(record)
+		}
+		stages.mark(pipelineStagePersist)
+		bs.events.emitTrade(record)
+
+		// Feed live volume profiles from futures aggregate trades only, so spot and futures
+		// prints of the same symbol are not counted twice
+		bs.hub.QueueVolumeProfileTrade(data.Symbol, price, quantity, data.IsBuyerMaker, data.TradeTime)
 	}
 
 	// Broadcast trade update
-	bs.hub.BroadcastTradeUpdate(tradeUpdate)
+	bs.hub.BroadcastEnrichedTradeUpdate(tradeUpdate, tradeContext)
+	stages.mark(pipelineStageBroadcast)
 }
 
 // processKlineUpdate processes kline/candlestick data for real-time charts
-func (bs *BinanceStream) processKlineUpdate(data BinanceKlineData) {
+func (bs *BinanceStream) processKlineUpdate(data BinanceKlineData, streamType StreamType, stages *stageTimer) {
 	// Store kline data
-	bs.klineData[data.Symbol+"_"+data.Kline.Interval] = &data
+	bs.dataMu.Lock()
+	bs.klineData[data.Symbol+"_"+data.Kline.Interval] = data.Event()
+	bs.dataMu.Unlock()
 
 	// Parse kline data
 	open, _ := strconv.ParseFloat(data.Kline.Open, 64)
@@ -791,29 +989,44 @@ func (bs *BinanceStream) processKlineUpdate(data BinanceKlineData) {
 	close, _ := strconv.ParseFloat(data.Kline.Close, 64)
 	volume, _ := strconv.ParseFloat(data.Kline.Volume, 64)
 
-	// Create kline update message
-	klineUpdate := map[string]interface{}{
-		"type":       "kline_update",
-		"symbol":     data.Symbol,
-		"interval":   data.Kline.Interval,
-		"open":       open,
-		"high":       high,
-		"low":        low,
-		"close":      close,
-		"volume":     volume,
-		"is_closed":  data.Kline.IsClosed,
-		"start_time": data.Kline.StartTime,
-		"end_time":   data.Kline.EndTime,
-		"timestamp":  time.Now().UnixMilli(),
+	candle := LayoutCandle{
+		Symbol:    data.Symbol,
+		Interval:  data.Kline.Interval,
+		StartTime: data.Kline.StartTime,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+		IsClosed:  data.Kline.IsClosed,
 	}
+	stages.mark(pipelineStageEnrich)
 
 	// Broadcast kline update
-	bs.hub.BroadcastKlineUpdate(klineUpdate)
+	bs.hub.BroadcastKlineUpdate(KlineUpdateMessage(candle, data.Kline.EndTime, time.Now().UnixMilli()))
+
+	// Multi-chart layouts chart futures klines; spot bars of the same symbol and interval would
+	// overwrite them in the batch
+	if streamType == StreamTypeFutures {
+		bs.hub.QueueLayoutCandle(candle)
+	}
+
+	// Embedded lite connections chart the futures 1m kline only
+	if data.Kline.Interval == "1m" && streamType == StreamTypeFutures {
+		bs.hub.QueueLiteKline(candle)
+	}
+
+	// Closed futures klines confirm bar closes (spot bars of the same symbol differ)
+	if data.Kline.IsClosed && streamType == StreamTypeFutures {
+		bs.barClose.confirmStream(data.Event())
+	}
+	stages.mark(pipelineStageBroadcast)
 }
 
 // reconnectSpot attempts to reconnect to Binance Spot WebSocket
 func (bs *BinanceStream) reconnectSpot() {
 	log.Println("Attempting to reconnect to Binance Spot WebSocket...")
+	bs.health.attempt(streamSpot)
 	time.Sleep(5 * time.Second)
 	if bs.isRunning {
 		if err := bs.startSpotStream(); err != nil {
@@ -829,6 +1042,7 @@ func (bs *BinanceStream) reconnectSpot() {
 // reconnectFutures attempts to reconnect to Binance Futures WebSocket
 func (bs *BinanceStream) reconnectFutures() {
 	log.Println("Attempting to reconnect to Binance Futures WebSocket...")
+	bs.health.attempt(streamFutures)
 	time.Sleep(5 * time.Second)
 	if bs.isRunning {
 		if err := bs.startFuturesStream(); err != nil {
@@ -854,14 +1068,11 @@ func (bs *BinanceStream) AddSymbol(symbol string) {
 	log.Printf("Added symbol %s to Enhanced Binance streams (Spot + Futures)", symbol)
 
 	// Initialize data structures for new symbol
-	bs.depthData[symbol] = nil
-	bs.tradeData[symbol] = make([]*BinanceTradeData, 0, 1000)
-	bs.klineData[symbol+"_1m"] = nil
-	bs.klineData[symbol+"_5m"] = nil
-	bs.klineData[symbol+"_15m"] = nil
+	bs.dataMu.Lock()
+	bs.tradeData[symbol] = make([]models.TradeEvent, 0, 1000)
 	bs.futuresTickerData[symbol] = nil
-	bs.markPriceData[symbol] = nil
-	bs.liquidationData[symbol] = make([]*BinanceLiquidationData, 0, 1000)
+	bs.liquidationData[symbol] = make([]models.LiquidationEvent, 0, maxRecentLiquidations)
+	bs.dataMu.Unlock()
 
 	// Restart streams with new symbols for full data coverage
 	if bs.isRunning {
@@ -871,25 +1082,46 @@ func (bs *BinanceStream) AddSymbol(symbol string) {
 	}
 }
 
+// BarCloses returns the bar close scheduler for registering handlers and configuring its clock
+func (bs *BinanceStream) BarCloses() *BarCloseScheduler {
+	return bs.barClose
+}
+
+// Events returns the stream's normalized market events for registering handlers
+func (bs *BinanceStream) Events() *MarketEvents {
+	return bs.events
+}
+
 // GetConnectedSymbols returns list of symbols being streamed
 func (bs *BinanceStream) GetConnectedSymbols() []string {
-	return bs.symbols
+	if len(bs.coinMSymbols) == 0 {
+		return bs.symbols
+	}
+	symbols := make([]string, 0, len(bs.symbols)+len(bs.coinMSymbols))
+	symbols = append(symbols, bs.symbols...)
+	return append(symbols, bs.coinMSymbols...)
 }
 
 // GetLastPrice returns the last known price for a symbol
 func (bs *BinanceStream) GetLastPrice(symbol string) (float64, bool) {
+	bs.pricesMu.RLock()
+	defer bs.pricesMu.RUnlock()
 	price, exists := bs.lastPrices[symbol]
 	return price, exists
 }
 
-// GetDepthData returns the latest depth data for a symbol
-func (bs *BinanceStream) GetDepthData(symbol string) (*BinanceDepthData, bool) {
+// GetDepthData returns the latest book update for a symbol
+func (bs *BinanceStream) GetDepthData(symbol string) (models.DepthUpdate, bool) {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
 	depth, exists := bs.depthData[symbol]
 	return depth, exists
 }
 
-// GetRecentTrades returns recent trades for a symbol
-func (bs *BinanceStream) GetRecentTrades(symbol string, limit int) []*BinanceTradeData {
+// GetRecentTrades returns recent spot trades and futures aggregate trades for a symbol, oldest first
+func (bs *BinanceStream) GetRecentTrades(symbol string, limit int) []models.TradeEvent {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
 	trades, exists := bs.tradeData[symbol]
 	if !exists {
 		return nil
@@ -902,34 +1134,57 @@ func (bs *BinanceStream) GetRecentTrades(symbol string, limit int) []*BinanceTra
 	return trades[len(trades)-limit:]
 }
 
-// GetKlineData returns the latest kline data for a symbol and interval
-func (bs *BinanceStream) GetKlineData(symbol, interval string) (*BinanceKlineData, bool) {
+// GetKlineData returns the latest kline for a symbol and interval
+func (bs *BinanceStream) GetKlineData(symbol, interval string) (models.KlineEvent, bool) {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
 	kline, exists := bs.klineData[symbol+"_"+interval]
 	return kline, exists
 }
 
-// GetMarkPriceData returns the latest mark price data for a symbol
-func (bs *BinanceStream) GetMarkPriceData(symbol string) (*BinanceMarkPriceData, bool) {
+// GetFuturesTicker returns the latest futures 24hr ticker of a symbol
+func (bs *BinanceStream) GetFuturesTicker(symbol string) (*BinanceFuturesTickerData, bool) {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
+	ticker, exists := bs.futuresTickerData[symbol]
+	return ticker, exists && ticker != nil
+}
+
+// GetMarkPriceData returns the latest mark price for a symbol
+func (bs *BinanceStream) GetMarkPriceData(symbol string) (models.MarkPriceEvent, bool) {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
 	markPrice, exists := bs.markPriceData[symbol]
 	return markPrice, exists
 }
 
+// GetFundingPrediction returns the live premium index and predicted funding rate for a symbol
+func (bs *BinanceStream) GetFundingPrediction(symbol string) (FundingPrediction, bool) {
+	return bs.fundingPredictor.Get(symbol)
+}
+
+// GetFundingPredictionHistory returns final predictions of past funding windows for a symbol
+func (bs *BinanceStream) GetFundingPredictionHistory(symbol string) []FundingPredictionRecord {
+	return bs.fundingPredictor.History(symbol)
+}
+
 // GetRecentLiquidations returns recent liquidations for a symbol
-func (bs *BinanceStream) GetRecentLiquidations(symbol string, limit int) []*BinanceLiquidationData {
+func (bs *BinanceStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
 	liquidations, exists := bs.liquidationData[symbol]
 	if !exists {
 		return nil
 	}
 
-	if limit <= 0 || limit > len(liquidations) {
-		return liquidations
-	}
-
-	return liquidations[len(liquidations)-limit:]
+	return lastLiquidations(liquidations, limit)
 }
 
 // GetStreamStats returns comprehensive statistics about both streams
 func (bs *BinanceStream) GetStreamStats() map[string]interface{} {
+	bs.dataMu.RLock()
+	defer bs.dataMu.RUnlock()
+
 	stats := map[string]interface{}{
 		"connected_symbols":    len(bs.symbols),
 		"symbols":              bs.symbols,
@@ -942,6 +1197,9 @@ func (bs *BinanceStream) GetStreamStats() map[string]interface{} {
 		"is_running":           bs.isRunning,
 		"spot_connected":       bs.spotConn != nil,
 		"futures_connected":    bs.futuresConn != nil,
+		"coinm_symbols":        bs.coinMSymbols,
+		"coinm_connected":      bs.coinMConn != nil,
+		"synthetic":            bs.synthetic != nil,
 		"stream_types": []string{
 			"spot_ticker", "futures_ticker", "depth@100ms", "trade", "aggTrade",
 			"kline_1m", "kline_5m", "kline_15m", "markPrice", "liquidations",
@@ -955,6 +1213,20 @@ func (bs *BinanceStream) GetStreamStats() map[string]interface{} {
 	}
 	stats["trade_counts"] = tradeCounts
 
+	// Bar close schedule and confirmation counters
+	stats["bar_close"] = bs.barClose.stats()
+
+	// Trade persistence counters
+	if recorder := bs.tradeRecorder.Load(); recorder != nil {
+		stats["trade_persistence"] = recorder.stats()
+	}
+
+	// Depth snapshot persistence counters
+	if recorder := bs.depthRecorder.Load(); recorder != nil {
+		stats["depth_persistence"] = recorder.stats()
+	}
+	stats["synced_books"] = bs.syncedBooks()
+
 	// Add liquidation counts per symbol
 	liquidationCounts := make(map[string]int)
 	for symbol, liquidations := range bs.liquidationData {
@@ -962,5 +1234,11 @@ func (bs *BinanceStream) GetStreamStats() map[string]interface{} {
 	}
 	stats["liquidation_counts"] = liquidationCounts
 
+	// Per-symbol ingestion pipeline counters and stage latencies
+	stats["pipelines"] = bs.pipelines.stats()
+
+	// Backup connections and duplicate events dropped
+	stats["redundancy"] = bs.redundancyStats()
+
 	return stats
 }
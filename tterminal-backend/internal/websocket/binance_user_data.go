@@ -0,0 +1,269 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// userDataKeepAlive is how often the listen key is extended; Binance expires it after 60 minutes
+	userDataKeepAlive = 30 * time.Minute
+	// userDataRequestTimeout bounds a listen key request
+	userDataRequestTimeout = 10 * time.Second
+)
+
+// UserDataListenKeys creates, extends and closes the listen key of the account's user data stream
+type UserDataListenKeys interface {
+	StartUserDataStream(ctx context.Context) (string, error)
+	KeepAliveUserDataStream(ctx context.Context) error
+	CloseUserDataStream(ctx context.Context) error
+}
+
+// UserDataHandler receives the account's user data events, in order, on the stream's read loop
+type UserDataHandler interface {
+	// HandleUserDataConnected is called after every (re)connect; events may have been missed before it
+	HandleUserDataConnected()
+	HandleAccountUpdate(update BinanceAccountUpdate)
+	HandleOrderTradeUpdate(update BinanceOrderTradeUpdate)
+}
+
+// BinanceAccountUpdate is an ACCOUNT_UPDATE event: balances and positions changed by a fill,
+// a funding payment or a margin transfer. Only the positions that changed are included
+type BinanceAccountUpdate struct {
+	EventType       string `json:"e"` // ACCOUNT_UPDATE
+	EventTime       int64  `json:"E"`
+	TransactionTime int64  `json:"T"`
+	Account         struct {
+		Reason    string                   `json:"m"` // ORDER, FUNDING_FEE, DEPOSIT, ...
+		Positions []BinanceAccountPosition `json:"P"`
+	} `json:"a"`
+}
+
+// BinanceAccountPosition is a position in an ACCOUNT_UPDATE event
+type BinanceAccountPosition struct {
+	Symbol              string `json:"s"`
+	PositionAmt         string `json:"pa"` // Signed: negative for shorts
+	EntryPrice          string `json:"ep"`
+	BreakEvenPrice      string `json:"bep"`
+	AccumulatedRealized string `json:"cr"` // Pre-fee accumulated realized PnL
+	UnrealizedPnL       string `json:"up"`
+	MarginType          string `json:"mt"` // cross or isolated
+	PositionSide        string `json:"ps"` // BOTH, LONG or SHORT
+}
+
+// BinanceOrderTradeUpdate is an ORDER_TRADE_UPDATE event: an order was placed, changed, filled or closed
+type BinanceOrderTradeUpdate struct {
+	EventType       string             `json:"e"` // ORDER_TRADE_UPDATE
+	EventTime       int64              `json:"E"`
+	TransactionTime int64              `json:"T"`
+	Order           BinanceOrderUpdate `json:"o"`
+}
+
+// BinanceOrderUpdate is the order in an ORDER_TRADE_UPDATE event
+// Keys differing only in case are all declared, since JSON decoding would otherwise mix them up
+type BinanceOrderUpdate struct {
+	Symbol          string `json:"s"`
+	ClientOrderID   string `json:"c"`
+	Side            string `json:"S"` // BUY or SELL
+	Type            string `json:"o"`
+	TimeInForce     string `json:"f"`
+	OrigQty         string `json:"q"`
+	Price           string `json:"p"`
+	AvgPrice        string `json:"ap"`
+	ActivationPrice string `json:"AP"` // Trailing stop activation price
+	StopPrice       string `json:"sp"`
+	ExecutionType   string `json:"x"` // NEW, TRADE, CANCELED, EXPIRED, AMENDMENT, ...
+	Status          string `json:"X"`
+	OrderID         int64  `json:"i"`
+	LastFilledQty   string `json:"l"`
+	LastFilledPrice string `json:"L"`
+	CumFilledQty    string `json:"z"`
+	Commission      string `json:"n"`
+	CommissionAsset string `json:"N"`
+	TradeTime       int64  `json:"T"`
+	TradeID         int64  `json:"t"`
+	IsMaker         bool   `json:"m"`
+	ReduceOnly      bool   `json:"R"`
+	PositionSide    string `json:"ps"`
+	RealizedProfit  string `json:"rp"` // Realized PnL of this fill
+}
+
+// EnableUserData streams the account's user data from the WebSocket host at url, with listen keys
+// from keys, and passes position and order updates to handler
+// The stream is separate from the market data streams and is not reported in their health
+func (bs *BinanceStream) EnableUserData(url string, keys UserDataListenKeys, handler UserDataHandler) error {
+	bs.userDataURL = strings.TrimSuffix(url, "/")
+	bs.userDataKeys = keys
+	bs.userDataHandler = handler
+	bs.userDataDisabled.Store(false)
+	if !bs.isRunning || bs.synthetic != nil {
+		return nil
+	}
+	return bs.startUserDataStream()
+}
+
+// DisableUserData disconnects the user data stream and closes its listen key, so Binance stops
+// producing events for it
+func (bs *BinanceStream) DisableUserData() {
+	if bs.userDataKeys == nil || bs.userDataDisabled.Swap(true) {
+		return
+	}
+
+	if bs.userDataConn != nil {
+		bs.userDataConn.Close()
+		bs.userDataConnected.Store(false)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), userDataRequestTimeout)
+	defer cancel()
+	if err := bs.userDataKeys.CloseUserDataStream(ctx); err != nil {
+		log.Printf("Failed to close user data stream listen key: %v", err)
+		return
+	}
+	log.Println("Binance user data stream closed")
+}
+
+// userDataActive reports whether the user data stream should stay connected
+func (bs *BinanceStream) userDataActive() bool {
+	return bs.isRunning && !bs.userDataDisabled.Load()
+}
+
+// UserDataConnected reports whether the user data stream is connected
+func (bs *BinanceStream) UserDataConnected() bool {
+	return bs.userDataConnected.Load()
+}
+
+// startUserDataStream obtains a listen key and connects to the user data stream
+func (bs *BinanceStream) startUserDataStream() error {
+	ctx, cancel := context.WithTimeout(context.Background(), userDataRequestTimeout)
+	listenKey, err := bs.userDataKeys.StartUserDataStream(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Connecting to Binance user data stream: %s/ws/<listen key>", bs.userDataURL)
+
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(bs.userDataURL+"/ws/"+listenKey, nil)
+	if err != nil {
+		return err
+	}
+
+	bs.userDataConn = conn
+	bs.userDataConnected.Store(true)
+	bs.userDataHandler.HandleUserDataConnected()
+
+	done := make(chan struct{})
+	go bs.readUserDataMessages(conn, done)
+	go bs.keepUserDataAlive(conn, done)
+
+	return nil
+}
+
+// keepUserDataAlive pings the connection and extends the listen key until the connection ends
+func (bs *BinanceStream) keepUserDataAlive(conn *websocket.Conn, done chan struct{}) {
+	pingTicker := time.NewTicker(20 * time.Second)
+	defer pingTicker.Stop()
+	keepAliveTicker := time.NewTicker(userDataKeepAlive)
+	defer keepAliveTicker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-pingTicker.C:
+			if err := conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+				log.Printf("Failed to send user data stream ping: %v", err)
+				return
+			}
+		case <-keepAliveTicker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), userDataRequestTimeout)
+			if err := bs.userDataKeys.KeepAliveUserDataStream(ctx); err != nil {
+				log.Printf("Failed to extend user data stream listen key: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// readUserDataMessages reads the user data stream, reconnecting with a new listen key when the
+// connection drops or the key expires
+func (bs *BinanceStream) readUserDataMessages(conn *websocket.Conn, done chan struct{}) {
+	defer close(done)
+	defer conn.Close()
+
+	for bs.userDataActive() {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if bs.userDataActive() {
+				log.Printf("Error reading from Binance user data stream: %v", err)
+				bs.userDataConnected.Store(false)
+				go bs.reconnectUserData()
+			}
+			return
+		}
+
+		if expired := bs.processUserDataMessage(message); expired && bs.userDataActive() {
+			log.Println("Binance user data stream listen key expired")
+			bs.userDataConnected.Store(false)
+			go bs.reconnectUserData()
+			return
+		}
+	}
+}
+
+// processUserDataMessage dispatches a user data event and reports whether the listen key expired
+func (bs *BinanceStream) processUserDataMessage(message []byte) bool {
+	var event struct {
+		EventType string `json:"e"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		log.Printf("ERROR: Error parsing user data event: %v", err)
+		return false
+	}
+
+	switch event.EventType {
+	case "ACCOUNT_UPDATE":
+		var update BinanceAccountUpdate
+		if err := json.Unmarshal(message, &update); err != nil {
+			log.Printf("ERROR: Error parsing account update: %v", err)
+			return false
+		}
+		bs.userDataHandler.HandleAccountUpdate(update)
+
+	case "ORDER_TRADE_UPDATE":
+		var update BinanceOrderTradeUpdate
+		if err := json.Unmarshal(message, &update); err != nil {
+			log.Printf("ERROR: Error parsing order update: %v", err)
+			return false
+		}
+		bs.userDataHandler.HandleOrderTradeUpdate(update)
+
+	case "listenKeyExpired":
+		return true
+	}
+	return false
+}
+
+// reconnectUserData attempts to reconnect to the Binance user data stream
+func (bs *BinanceStream) reconnectUserData() {
+	log.Println("Attempting to reconnect to Binance user data stream...")
+	time.Sleep(5 * time.Second)
+	if bs.userDataActive() {
+		if err := bs.startUserDataStream(); err != nil {
+			log.Printf("User data stream reconnection failed: %v", err)
+			time.Sleep(10 * time.Second)
+			bs.reconnectUserData()
+		} else {
+			log.Println("Successfully reconnected to Binance user data stream")
+		}
+	}
+}
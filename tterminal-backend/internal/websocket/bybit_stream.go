@@ -0,0 +1,735 @@
+package websocket
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// bybitSubscribeBatch is the number of topics sent per subscribe request
+	bybitSubscribeBatch = 10
+	// bybitPingInterval keeps the connection open; Bybit drops connections silent for 30s
+	bybitPingInterval = 20 * time.Second
+	// bybitBookDepth is the order book depth subscribed per symbol
+	bybitBookDepth = "50"
+)
+
+// bybitKlineIntervals maps the streamed Bybit kline intervals to API intervals (see barCloseIntervals)
+var bybitKlineIntervals = map[string]string{"1": "1m", "5": "5m", "15": "15m"}
+
+// BybitStream streams Bybit linear perpetual market data into the hub
+// Updates are broadcast under exchange-qualified symbols ("BYBIT:BTCUSDT") with an "exchange"
+// field, so clients subscribe to them like any Binance symbol. Trades, book snapshots and
+// closed bars feed the same recorders and bar close pipeline as the Binance futures stream
+type BybitStream struct {
+	hub     *Hub
+	url     string
+	symbols []string // Bare Bybit symbols
+
+	mu           sync.RWMutex
+	conn         *websocket.Conn
+	isRunning    bool
+	connectedAt  time.Time
+	reconnects   int64
+	tickers      map[string]*bybitTicker
+	books        map[string]*localBook
+	liquidations map[string][]models.LiquidationEvent
+	lastPrices   map[string]float64
+	lastMessage  atomic.Int64 // Unix milliseconds
+
+	// Rolling VWAP/delta/size context for enriched trade updates
+	tradeEnricher *TradeEnricher
+	// Optional persistence of trades and book snapshots (set after start)
+	tradeRecorder atomic.Pointer[tradeRecorder]
+	depthRecorder atomic.Pointer[depthRecorder]
+	// Bar close events, confirmed by closed Bybit klines
+	barClose        *BarCloseScheduler
+	barCloseStarted sync.Once
+	// Normalized trades, book updates and liquidations for in-process consumers
+	events *MarketEvents
+}
+
+// bybitMessage is a v5 public stream message (topic data or an operation response)
+type bybitMessage struct {
+	Topic   string          `json:"topic"`
+	Type    string          `json:"type"` // "snapshot" or "delta"
+	Ts      int64           `json:"ts"`
+	Data    json.RawMessage `json:"data"`
+	Op      string          `json:"op"`
+	Success *bool           `json:"success"`
+	RetMsg  string          `json:"ret_msg"`
+}
+
+// bybitTicker is the merged state of a tickers topic (deltas only carry changed fields)
+type bybitTicker struct {
+	Symbol          string `json:"symbol"`
+	LastPrice       string `json:"lastPrice"`
+	PrevPrice24h    string `json:"prevPrice24h"`
+	Price24hPcnt    string `json:"price24hPcnt"`
+	Volume24h       string `json:"volume24h"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	FundingRate     string `json:"fundingRate"`
+	NextFundingTime string `json:"nextFundingTime"`
+}
+
+// bybitTrade is one publicTrade entry
+type bybitTrade struct {
+	Time   int64  `json:"T"`
+	Symbol string `json:"s"`
+	Side   string `json:"S"` // Taker side
+	Size   string `json:"v"`
+	Price  string `json:"p"`
+	ID     string `json:"i"`
+}
+
+// bybitLiquidation is one allLiquidation entry
+type bybitLiquidation struct {
+	Time   int64  `json:"T"`
+	Symbol string `json:"s"`
+	Side   string `json:"S"` // Position side liquidated: "Buy" is a long
+	Size   string `json:"v"`
+	Price  string `json:"p"`
+}
+
+// bybitKline is one kline entry
+type bybitKline struct {
+	Start    int64  `json:"start"`
+	End      int64  `json:"end"`
+	Interval string `json:"interval"`
+	Open     string `json:"open"`
+	Close    string `json:"close"`
+	High     string `json:"high"`
+	Low      string `json:"low"`
+	Volume   string `json:"volume"`
+	Turnover string `json:"turnover"`
+	Confirm  bool   `json:"confirm"`
+}
+
+// bybitBookData is an orderbook snapshot or delta
+type bybitBookData struct {
+	Symbol string     `json:"s"`
+	Bids   [][]string `json:"b"`
+	Asks   [][]string `json:"a"`
+}
+
+// localBook is an order book maintained from exchange snapshots and deltas
+type localBook struct {
+	bids map[string]string
+	asks map[string]string
+}
+
+// NewBybitStream creates a Bybit stream for bare symbols (e.g. "BTCUSDT")
+func NewBybitStream(hub *Hub, url string, symbols []string) *BybitStream {
+	bs := &BybitStream{
+		hub:           hub,
+		url:           url,
+		symbols:       symbols,
+		tickers:       make(map[string]*bybitTicker),
+		books:         make(map[string]*localBook),
+		liquidations:  make(map[string][]models.LiquidationEvent),
+		lastPrices:    make(map[string]float64),
+		tradeEnricher: NewTradeEnricher(),
+	}
+	bs.barClose = newBarCloseScheduler(hub, bs.GetConnectedSymbols)
+	bs.events = newMarketEvents()
+	return bs
+}
+
+// Start connects to the Bybit public linear stream, reconnecting in the background on failure
+func (bs *BybitStream) Start() error {
+	bs.barCloseStarted.Do(func() {
+		go bs.barClose.run(make(chan struct{}))
+	})
+
+	bs.mu.Lock()
+	bs.isRunning = true
+	bs.mu.Unlock()
+
+	if err := bs.connect(); err != nil {
+		log.Printf("Failed to connect to Bybit stream: %v", err)
+		go bs.reconnect()
+		return nil
+	}
+
+	log.Printf("Connected to Bybit WebSocket - Streaming %d linear symbols", len(bs.symbols))
+	return nil
+}
+
+// Stop disconnects from the Bybit stream
+func (bs *BybitStream) Stop() {
+	bs.mu.Lock()
+	bs.isRunning = false
+	conn := bs.conn
+	bs.conn = nil
+	bs.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+		log.Println("Bybit WebSocket stream stopped")
+	}
+}
+
+// SetTradeWriter enables persistence of Bybit trades
+func (bs *BybitStream) SetTradeWriter(writer *TradeWriter) {
+	bs.tradeRecorder.Store(newTradeRecorder(writer, bs.hub))
+	log.Printf("Trade persistence enabled for Bybit trades")
+}
+
+// SetDepthSnapshotWriter enables periodic persistence of Bybit order book snapshots
+func (bs *BybitStream) SetDepthSnapshotWriter(writer *DepthSnapshotWriter, interval time.Duration) {
+	recorder := newDepthRecorder(writer, interval)
+	bs.depthRecorder.Store(recorder)
+	log.Printf("Bybit depth snapshot persistence enabled every %v", recorder.interval)
+}
+
+// connect dials the stream, subscribes to every topic and starts the read and ping loops
+func (bs *BybitStream) connect() error {
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(bs.url, nil)
+	if err != nil {
+		return err
+	}
+
+	topics := bs.topics()
+	for start := 0; start < len(topics); start += bybitSubscribeBatch {
+		end := start + bybitSubscribeBatch
+		if end > len(topics) {
+			end = len(topics)
+		}
+		request := map[string]interface{}{"op": "subscribe", "args": topics[start:end]}
+		if err := conn.WriteJSON(request); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	bs.mu.Lock()
+	bs.conn = conn
+	bs.connectedAt = time.Now()
+	// Order books restart from the snapshot sent after subscribing
+	bs.books = make(map[string]*localBook)
+	bs.mu.Unlock()
+
+	go bs.readMessages(conn)
+	go bs.pingPeriodically(conn)
+	return nil
+}
+
+// topics returns the subscription topics of every symbol
+func (bs *BybitStream) topics() []string {
+	var topics []string
+	for _, symbol := range bs.symbols {
+		topics = append(topics,
+			"tickers."+symbol,                      // Last, mark, index price and funding
+			"publicTrade."+symbol,                  // Individual trades
+			"orderbook."+bybitBookDepth+"."+symbol, // Order book snapshot + deltas
+			"allLiquidation."+symbol,               // Liquidations
+			"kline.1."+symbol,                      // 1-minute klines
+			"kline.5."+symbol,                      // 5-minute klines
+			"kline.15."+symbol,                     // 15-minute klines
+		)
+	}
+	return topics
+}
+
+// pingPeriodically sends application-level pings, which Bybit requires instead of control frames
+func (bs *BybitStream) pingPeriodically(conn *websocket.Conn) {
+	ticker := time.NewTicker(bybitPingInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !bs.isCurrent(conn) {
+			return
+		}
+		if err := conn.WriteJSON(map[string]string{"op": "ping"}); err != nil {
+			log.Printf("Failed to send Bybit ping: %v", err)
+			return
+		}
+	}
+}
+
+// readMessages reads and processes messages until the connection fails
+func (bs *BybitStream) readMessages(conn *websocket.Conn) {
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if bs.isCurrent(conn) {
+				log.Printf("Error reading from Bybit WebSocket: %v", err)
+				bs.mu.Lock()
+				bs.conn = nil
+				bs.mu.Unlock()
+				bs.reconnect()
+			}
+			return
+		}
+
+		bs.processMessage(message)
+	}
+}
+
+// isCurrent reports whether conn is the live connection of a running stream
+func (bs *BybitStream) isCurrent(conn *websocket.Conn) bool {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.isRunning && bs.conn == conn
+}
+
+// reconnect retries the connection until it succeeds or the stream is stopped
+func (bs *BybitStream) reconnect() {
+	for {
+		time.Sleep(5 * time.Second)
+
+		bs.mu.Lock()
+		running := bs.isRunning
+		bs.reconnects++
+		bs.mu.Unlock()
+		if !running {
+			return
+		}
+
+		log.Println("Attempting to reconnect to Bybit WebSocket...")
+		if err := bs.connect(); err != nil {
+			log.Printf("Bybit reconnection failed: %v", err)
+			continue
+		}
+		log.Println("Successfully reconnected to Bybit WebSocket")
+		return
+	}
+}
+
+// processMessage routes a stream message by topic
+func (bs *BybitStream) processMessage(message []byte) {
+	bs.lastMessage.Store(time.Now().UnixMilli())
+
+	var msg bybitMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	if msg.Op != "" {
+		if msg.Op == "subscribe" && msg.Success != nil && !*msg.Success {
+			log.Printf("Bybit subscription rejected: %s", msg.RetMsg)
+		}
+		return
+	}
+
+	topic, symbol := msg.Topic, msg.Topic[strings.LastIndex(msg.Topic, ".")+1:]
+	switch {
+	case strings.HasPrefix(topic, "tickers."):
+		var ticker bybitTicker
+		if err := json.Unmarshal(msg.Data, &ticker); err == nil {
+			bs.processTicker(symbol, ticker)
+		}
+
+	case strings.HasPrefix(topic, "publicTrade."):
+		var trades []bybitTrade
+		if err := json.Unmarshal(msg.Data, &trades); err == nil {
+			for _, trade := range trades {
+				bs.processTrade(trade)
+			}
+		}
+
+	case strings.HasPrefix(topic, "orderbook."):
+		var book bybitBookData
+		if err := json.Unmarshal(msg.Data, &book); err == nil {
+			bs.processBook(book, msg.Type == "snapshot", msg.Ts)
+		}
+
+	case strings.HasPrefix(topic, "allLiquidation."):
+		var liquidations []bybitLiquidation
+		if err := json.Unmarshal(msg.Data, &liquidations); err == nil {
+			for _, liquidation := range liquidations {
+				bs.processLiquidation(liquidation)
+			}
+		}
+
+	case strings.HasPrefix(topic, "kline."):
+		var klines []bybitKline
+		if err := json.Unmarshal(msg.Data, &klines); err == nil {
+			for _, kline := range klines {
+				bs.processKline(symbol, kline)
+			}
+		}
+	}
+}
+
+// processTicker merges a ticker snapshot or delta and broadcasts price and mark price updates
+func (bs *BybitStream) processTicker(symbol string, data bybitTicker) {
+	bs.mu.Lock()
+	ticker, exists := bs.tickers[symbol]
+	if !exists {
+		ticker = &bybitTicker{Symbol: symbol}
+		bs.tickers[symbol] = ticker
+	}
+	mergeField(&ticker.LastPrice, data.LastPrice)
+	mergeField(&ticker.PrevPrice24h, data.PrevPrice24h)
+	mergeField(&ticker.Price24hPcnt, data.Price24hPcnt)
+	mergeField(&ticker.Volume24h, data.Volume24h)
+	mergeField(&ticker.MarkPrice, data.MarkPrice)
+	mergeField(&ticker.IndexPrice, data.IndexPrice)
+	mergeField(&ticker.FundingRate, data.FundingRate)
+	mergeField(&ticker.NextFundingTime, data.NextFundingTime)
+	merged := *ticker
+
+	key := models.QualifySymbol(models.ExchangeBybit, symbol)
+	lastPrice := models.ParseFloat(merged.LastPrice)
+	priceChanged := data.LastPrice != "" && lastPrice > 0 && bs.lastPrices[key] != lastPrice
+	if priceChanged {
+		bs.lastPrices[key] = lastPrice
+	}
+	bs.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	if priceChanged {
+		update := PriceUpdate{
+			Type:          "price_update",
+			Symbol:        key,
+			Exchange:      models.ExchangeBybit,
+			Price:         lastPrice,
+			Change:        lastPrice - models.ParseFloat(merged.PrevPrice24h),
+			ChangePercent: models.ParseFloat(merged.Price24hPcnt) * 100,
+			Volume:        models.ParseFloat(merged.Volume24h),
+			Timestamp:     now,
+		}
+		bs.hub.BroadcastPriceUpdate(update)
+		bs.hub.QueueLitePrice(update)
+	}
+
+	if data.MarkPrice != "" || data.FundingRate != "" {
+		nextFundingTime, _ := strconv.ParseInt(merged.NextFundingTime, 10, 64)
+		bs.hub.BroadcastMarkPriceUpdate(map[string]interface{}{
+			"type":              "mark_price_update",
+			"symbol":            key,
+			"exchange":          models.ExchangeBybit,
+			"mark_price":        models.ParseFloat(merged.MarkPrice),
+			"index_price":       models.ParseFloat(merged.IndexPrice),
+			"funding_rate":      models.ParseFloat(merged.FundingRate),
+			"next_funding_time": nextFundingTime,
+			"timestamp":         now,
+		})
+	}
+}
+
+// mergeField overwrites a ticker field when the delta carries it
+func mergeField(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// processTrade broadcasts, records and profiles a trade
+func (bs *BybitStream) processTrade(data bybitTrade) {
+	price, err := strconv.ParseFloat(data.Price, 64)
+	if err != nil {
+		return
+	}
+	quantity, err := strconv.ParseFloat(data.Size, 64)
+	if err != nil {
+		return
+	}
+
+	key := models.QualifySymbol(models.ExchangeBybit, data.Symbol)
+	// A taker sell hits the bid, so the buyer is the maker
+	isBuyerMaker := data.Side == "Sell"
+
+	tradeUpdate := map[string]interface{}{
+		"type":           "trade_update",
+		"symbol":         key,
+		"exchange":       models.ExchangeBybit,
+		"price":          price,
+		"quantity":       quantity,
+		"is_buyer_maker": isBuyerMaker,
+		"trade_time":     data.Time,
+		"timestamp":      time.Now().UnixMilli(),
+	}
+
+	// Bybit trade IDs are UUIDs; stored trades need a numeric ID
+	hash := fnv.New64a()
+	hash.Write([]byte(data.ID))
+	record := models.TradeRecord{
+		Symbol:       key,
+		TradeID:      int64(hash.Sum64() >> 1),
+		Price:        price,
+		Quantity:     quantity,
+		IsBuyerMaker: isBuyerMaker,
+		TradeTime:    time.UnixMilli(data.Time),
+	}
+	if recorder := bs.tradeRecorder.Load(); recorder != nil {
+		recorder.record(record)
+	}
+	bs.events.emitTrade(record)
+
+	bs.hub.QueueVolumeProfileTrade(key, price, quantity, isBuyerMaker, data.Time)
+
+	tradeContext := bs.tradeEnricher.Update(key, price, quantity, isBuyerMaker, data.Time)
+	bs.hub.BroadcastEnrichedTradeUpdate(tradeUpdate, tradeContext)
+}
+
+// processBook applies a book snapshot or delta and broadcasts the changed levels
+func (bs *BybitStream) processBook(data bybitBookData, snapshot bool, ts int64) {
+	key := models.QualifySymbol(models.ExchangeBybit, data.Symbol)
+
+	bs.mu.Lock()
+	book, exists := bs.books[data.Symbol]
+	if snapshot || !exists {
+		book = &localBook{bids: make(map[string]string), asks: make(map[string]string)}
+		bs.books[data.Symbol] = book
+	}
+	applyBookLevels(book.bids, data.Bids)
+	applyBookLevels(book.asks, data.Asks)
+
+	var full *models.DepthUpdate
+	if bs.depthRecorder.Load() != nil {
+		full = &models.DepthUpdate{
+			Symbol:   key,
+			Bids:     sortedBookLevels(book.bids, true),
+			Asks:     sortedBookLevels(book.asks, false),
+			Snapshot: true,
+			Time:     time.UnixMilli(ts),
+		}
+	}
+	bs.mu.Unlock()
+
+	// Depth snapshots sample the whole local book, not the delta
+	if recorder := bs.depthRecorder.Load(); recorder != nil && full != nil {
+		recorder.observe(*full)
+	}
+
+	bs.events.emitDepth(models.DepthUpdate{
+		Symbol:   key,
+		Bids:     data.Bids,
+		Asks:     data.Asks,
+		Snapshot: snapshot,
+		Time:     time.UnixMilli(ts),
+	})
+
+	bs.hub.BroadcastDepthUpdate(map[string]interface{}{
+		"type":      "depth_update",
+		"symbol":    key,
+		"exchange":  models.ExchangeBybit,
+		"bids":      data.Bids,
+		"asks":      data.Asks,
+		"snapshot":  snapshot,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// applyBookLevels applies [price, size] levels to one side of a book; size "0" removes the level
+func applyBookLevels(side map[string]string, levels [][]string) {
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		if models.ParseFloat(level[1]) == 0 {
+			delete(side, level[0])
+		} else {
+			side[level[0]] = level[1]
+		}
+	}
+}
+
+// sortedBookLevels returns one side of a book as [price, size] pairs, best price first
+func sortedBookLevels(side map[string]string, descending bool) [][]string {
+	levels := make([][]string, 0, len(side))
+	for price, size := range side {
+		levels = append(levels, []string{price, size})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		pi, pj := models.ParseFloat(levels[i][0]), models.ParseFloat(levels[j][0])
+		if descending {
+			return pi > pj
+		}
+		return pi < pj
+	})
+	return levels
+}
+
+// processLiquidation normalizes, stores and broadcasts a liquidation
+func (bs *BybitStream) processLiquidation(data bybitLiquidation) {
+	price, err := strconv.ParseFloat(data.Price, 64)
+	if err != nil {
+		return
+	}
+	quantity, err := strconv.ParseFloat(data.Size, 64)
+	if err != nil {
+		return
+	}
+
+	key := models.QualifySymbol(models.ExchangeBybit, data.Symbol)
+	// A liquidated long is closed by a sell order, matching Binance's forceOrder side
+	side := "SELL"
+	if data.Side == "Sell" {
+		side = "BUY"
+	}
+
+	event := models.LiquidationEvent{
+		Symbol:     key,
+		Side:       side,
+		Price:      price,
+		OrderPrice: price,
+		Quantity:   quantity,
+		Status:     "FILLED",
+		TradeTime:  time.UnixMilli(data.Time),
+	}
+
+	// Keep the last 1000 liquidations per symbol
+	bs.mu.Lock()
+	recordLiquidation(bs.liquidations, event)
+	bs.mu.Unlock()
+
+	publishLiquidation(bs.hub, bs.events, event)
+}
+
+// processKline broadcasts a kline and confirms closed bars
+func (bs *BybitStream) processKline(symbol string, data bybitKline) {
+	interval, ok := bybitKlineIntervals[data.Interval]
+	if !ok {
+		return
+	}
+	key := models.QualifySymbol(models.ExchangeBybit, symbol)
+
+	open := models.ParseFloat(data.Open)
+	high := models.ParseFloat(data.High)
+	low := models.ParseFloat(data.Low)
+	close := models.ParseFloat(data.Close)
+	volume := models.ParseFloat(data.Volume)
+
+	bs.hub.BroadcastKlineUpdate(map[string]interface{}{
+		"type":       "kline_update",
+		"symbol":     key,
+		"exchange":   models.ExchangeBybit,
+		"interval":   interval,
+		"open":       open,
+		"high":       high,
+		"low":        low,
+		"close":      close,
+		"volume":     volume,
+		"is_closed":  data.Confirm,
+		"start_time": data.Start,
+		"end_time":   data.End,
+		"timestamp":  time.Now().UnixMilli(),
+	})
+
+	candle := LayoutCandle{
+		Symbol:    key,
+		Interval:  interval,
+		StartTime: data.Start,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+		IsClosed:  data.Confirm,
+	}
+	bs.hub.QueueLayoutCandle(candle)
+	if interval == "1m" {
+		bs.hub.QueueLiteKline(candle)
+	}
+
+	if data.Confirm {
+		// Bybit klines carry no trade count or taker buy volume
+		bs.barClose.confirmStream(models.KlineEvent{
+			Symbol:         key,
+			Interval:       interval,
+			OpenTime:       time.UnixMilli(data.Start),
+			CloseTime:      time.UnixMilli(data.End),
+			Open:           data.Open,
+			High:           data.High,
+			Low:            data.Low,
+			Close:          data.Close,
+			Volume:         data.Volume,
+			QuoteVolume:    data.Turnover,
+			BuyVolume:      "0",
+			BuyQuoteVolume: "0",
+			Closed:         true,
+		})
+	}
+}
+
+// BarCloses returns the bar close scheduler for registering handlers and configuring its clock
+func (bs *BybitStream) BarCloses() *BarCloseScheduler {
+	return bs.barClose
+}
+
+// Events returns the stream's normalized market events for registering handlers
+func (bs *BybitStream) Events() *MarketEvents {
+	return bs.events
+}
+
+// GetConnectedSymbols returns the qualified symbols being streamed
+func (bs *BybitStream) GetConnectedSymbols() []string {
+	symbols := make([]string, len(bs.symbols))
+	for i, symbol := range bs.symbols {
+		symbols[i] = models.QualifySymbol(models.ExchangeBybit, symbol)
+	}
+	return symbols
+}
+
+// GetLastPrice returns the last known price for a qualified symbol
+func (bs *BybitStream) GetLastPrice(symbol string) (float64, bool) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	price, exists := bs.lastPrices[symbol]
+	return price, exists
+}
+
+// GetRecentLiquidations returns recent liquidations for a qualified symbol
+func (bs *BybitStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return lastLiquidations(bs.liquidations[symbol], limit)
+}
+
+// GetStreamStats returns statistics about the Bybit stream
+func (bs *BybitStream) GetStreamStats() map[string]interface{} {
+	bs.mu.RLock()
+	liquidationCounts := make(map[string]int, len(bs.liquidations))
+	for symbol, liquidations := range bs.liquidations {
+		liquidationCounts[symbol] = len(liquidations)
+	}
+	stats := map[string]interface{}{
+		"exchange":           models.ExchangeBybit,
+		"connected_symbols":  len(bs.symbols),
+		"symbols":            bs.GetConnectedSymbols(),
+		"price_data_count":   len(bs.lastPrices),
+		"book_count":         len(bs.books),
+		"is_running":         bs.isRunning,
+		"connected":          bs.conn != nil,
+		"reconnects":         bs.reconnects,
+		"liquidation_counts": liquidationCounts,
+		"stream_types": []string{
+			"tickers", "publicTrade", "orderbook." + bybitBookDepth, "allLiquidation",
+			"kline_1m", "kline_5m", "kline_15m",
+		},
+	}
+	if !bs.connectedAt.IsZero() {
+		stats["connected_at"] = bs.connectedAt.UnixMilli()
+	}
+	bs.mu.RUnlock()
+
+	if last := bs.lastMessage.Load(); last > 0 {
+		stats["last_message_at"] = last
+	}
+	stats["bar_close"] = bs.barClose.stats()
+	if recorder := bs.tradeRecorder.Load(); recorder != nil {
+		stats["trade_persistence"] = recorder.stats()
+	}
+	if recorder := bs.depthRecorder.Load(); recorder != nil {
+		stats["depth_persistence"] = recorder.stats()
+	}
+	return stats
+}
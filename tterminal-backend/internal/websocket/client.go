@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"time"
+	"tterminal-backend/models"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -20,19 +21,34 @@ const (
 	// Send pings to peer with this period. Must be less than pongWait
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	// Maximum message size allowed from peer; fits a subscribe with maxLayoutPairs pairs
+	maxMessageSize = 4096
 )
 
 // ClientMessage represents incoming message from client
 type ClientMessage struct {
-	Type   string      `json:"type"`
-	Symbol string      `json:"symbol,omitempty"`
-	Data   interface{} `json:"data,omitempty"`
+	Type     string               `json:"type"`
+	Symbol   string               `json:"symbol,omitempty"`
+	Exchange string               `json:"exchange,omitempty"` // Qualifies Symbol for non-Binance exchanges ("bybit")
+	Channel  string               `json:"channel,omitempty"`
+	Data     interface{}          `json:"data,omitempty"`
+	Options  *SubscriptionOptions `json:"options,omitempty"`
+	Replay   *ReplayOptions       `json:"replay,omitempty"`   // replay_start and replay_speed
+	Delivery *DeliveryOptions     `json:"delivery,omitempty"` // set_delivery
+}
+
+// SubscriptionOptions represents per-subscription toggles sent with a subscribe message
+type SubscriptionOptions struct {
+	EnrichTrades bool                        `json:"enrich_trades"` // Attach VWAP/delta/size context to trade updates
+	MinNotional  float64                     `json:"min_notional"`  // Minimum liquidation notional for "liquidations:all"
+	Pairs        []LayoutPair                `json:"pairs"`         // Symbol/interval pairs for "layout:sync"
+	Profiles     []VolumeProfileSubscription `json:"profiles"`      // Symbol/bucket size pairs for "vp:delta"
 }
 
 // HandleWebSocket handles WebSocket connection upgrade and client management
-func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+// userID is the verified identity of the request's access token, empty for anonymous clients; a
+// user_id query parameter is never trusted, since alerts and saved subscriptions are routed by it
+func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request, userID string) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -41,16 +57,32 @@ func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// Create new client
 	client := &Client{
-		conn:    conn,
-		send:    make(chan []byte, 256),
-		id:      uuid.New().String()[:8], // Short ID for logging
-		symbols: make(map[string]bool),
-		hub:     h,
+		conn:            conn,
+		send:            make(chan []byte, 256),
+		id:              uuid.New().String()[:8], // Short ID for logging
+		userID:          userID,
+		symbols:         make(map[string]bool),
+		enrichedSymbols: make(map[string]bool),
+		channels:        make(map[string]bool),
+		hub:             h,
+		lastWrite:       time.Now(),
+		allowRecording:  r.URL.Query().Get("allow_recording") == "true",
 	}
+	client.applyTransportOptions(h.getTransportConfig(), r.URL.Query())
+	client.limits = h.getClientLimits()
+	client.initBandwidth()
 
 	// Register client with hub
 	h.register <- client
 
+	// Tell the client its quotas before anything it might exceed
+	client.sendLimits()
+
+	// Restore the user's last active subscriptions when requested
+	if r.URL.Query().Get("resubscribe") == "true" {
+		go h.restoreSubscriptions(client)
+	}
+
 	// Start goroutines for reading and writing
 	go client.writePump()
 	go client.readPump()
@@ -63,11 +95,15 @@ func (c *Client) readPump() {
 		c.conn.Close()
 	}()
 
-	// Configure connection
-	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	// Configure connection (lite clients are pinged less often and may only send pings)
+	readLimit, readWait := int64(maxMessageSize), pongWait
+	if c.lite {
+		readLimit, readWait = liteMaxMessageSize, litePongWait
+	}
+	c.conn.SetReadLimit(readLimit)
+	c.conn.SetReadDeadline(time.Now().Add(readWait))
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.conn.SetReadDeadline(time.Now().Add(readWait))
 		return nil
 	})
 
@@ -88,6 +124,14 @@ func (c *Client) readPump() {
 			continue
 		}
 
+		// Drop messages over the per-second quota, reporting once per window
+		if !c.allowMessage() {
+			if c.messageCount == c.limits.MessagesPerSecond+1 {
+				c.sendMessage(c.rateLimitError(message))
+			}
+			continue
+		}
+
 		// Handle different message types
 		c.handleMessage(message)
 	}
@@ -95,9 +139,26 @@ func (c *Client) readPump() {
 
 // writePump pumps messages from the hub to the WebSocket connection
 func (c *Client) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	period := pingPeriod
+	if c.lite {
+		period = litePingPeriod
+	}
+	ticker := time.NewTicker(period)
+
+	// Application-level keepalive frames for proxies that ignore control frames
+	var keepalive <-chan time.Time
+	if c.keepaliveInterval > 0 {
+		keepaliveTicker := time.NewTicker(c.keepaliveInterval / 2)
+		defer keepaliveTicker.Stop()
+		keepalive = keepaliveTicker.C
+	}
+
+	// Latest state updates held back while the client is over its bandwidth cap
+	conflate := time.NewTicker(bandwidthConflateInterval)
+
 	defer func() {
 		ticker.Stop()
+		conflate.Stop()
 		c.conn.Close()
 	}()
 
@@ -111,20 +172,28 @@ func (c *Client) writePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			w.Write(message)
-
 			// Add queued messages to current message (batch sending for performance)
+			batch := [][]byte{message}
 			n := len(c.send)
 			for i := 0; i < n; i++ {
-				w.Write([]byte("\n"))
-				w.Write(<-c.send)
+				batch = append(batch, <-c.send)
+			}
+
+			// Skip updates inside the delivery intervals, then conflate or drop updates while
+			// over the bandwidth cap
+			batch = c.filterBandwidth(c.filterDelivery(batch))
+			if err := c.writeBatch(batch); err != nil {
+				return
+			}
+			c.sampleDeliveryLag(batch)
+
+		case <-conflate.C:
+			if err := c.writeBatch(c.flushConflated()); err != nil {
+				return
 			}
 
-			if err := w.Close(); err != nil {
+		case <-keepalive:
+			if err := c.writeKeepalive(); err != nil {
 				return
 			}
 
@@ -139,22 +208,76 @@ func (c *Client) writePump() {
 
 // handleMessage processes incoming messages from client
 func (c *Client) handleMessage(message ClientMessage) {
+	// Lite connections are fixed to their symbol, enforced here rather than by the embed
+	if c.lite && message.Type != "ping" {
+		c.rejectLiteMessage()
+		return
+	}
+
+	// Remember the latest subscription set for identified users
+	if message.Type == "subscribe" || message.Type == "unsubscribe" {
+		defer c.schedulePersist()
+
+		// {"symbol":"BTC-PERP","exchange":"okx"} subscribes to the canonical symbol's OKX key
+		if message.Symbol != "" {
+			key, resolved, err := c.hub.resolveSymbol(message.Symbol, message.Exchange)
+			if err != nil {
+				c.sendMessage(map[string]interface{}{
+					"type":      "error",
+					"symbol":    message.Symbol,
+					"message":   err.Error(),
+					"timestamp": time.Now().UnixMilli(),
+				})
+				return
+			}
+			if resolved {
+				message.Symbol = key
+			}
+		}
+
+		// {"symbol":"BTCUSDT","exchange":"bybit"} subscribes to "BYBIT:BTCUSDT"
+		if message.Symbol != "" && message.Exchange != "" {
+			message.Symbol = models.QualifySymbol(message.Exchange, message.Symbol)
+		}
+	}
+
 	switch message.Type {
 	case "subscribe":
-		if message.Symbol != "" {
+		if message.Channel != "" {
+			c.subscribeChannel(message)
+		} else if message.Symbol != "" {
+			if limitErr := c.hub.symbolLimitError(c, message.Symbol); limitErr != nil {
+				c.sendMessage(limitErr)
+				return
+			}
 			c.hub.SubscribeSymbol(c, message.Symbol)
+
+			// Apply per-subscription options (re-subscribing updates them)
+			enrichTrades := message.Options != nil && message.Options.EnrichTrades
+			c.hub.SetTradeEnrichment(c, message.Symbol, enrichTrades)
+
 			// Send confirmation
 			response := map[string]interface{}{
-				"type":      "subscribed",
-				"symbol":    message.Symbol,
-				"message":   "Successfully subscribed to " + message.Symbol,
-				"timestamp": time.Now().UnixMilli(),
+				"type":          "subscribed",
+				"symbol":        message.Symbol,
+				"enrich_trades": enrichTrades,
+				"message":       "Successfully subscribed to " + message.Symbol,
+				"timestamp":     time.Now().UnixMilli(),
 			}
 			c.sendMessage(response)
 		}
 
 	case "unsubscribe":
-		if message.Symbol != "" {
+		if message.Channel != "" {
+			c.hub.UnsubscribeChannel(c, message.Channel)
+			response := map[string]interface{}{
+				"type":      "unsubscribed",
+				"channel":   message.Channel,
+				"message":   "Successfully unsubscribed from " + message.Channel,
+				"timestamp": time.Now().UnixMilli(),
+			}
+			c.sendMessage(response)
+		} else if message.Symbol != "" {
 			c.hub.UnsubscribeSymbol(c, message.Symbol)
 			// Send confirmation
 			response := map[string]interface{}{
@@ -174,13 +297,21 @@ func (c *Client) handleMessage(message ClientMessage) {
 		}
 		c.sendMessage(response)
 
+	case "replay_start", "replay_pause", "replay_resume", "replay_speed", "replay_step", "replay_stop":
+		c.handleReplayMessage(message)
+
+	case "set_delivery":
+		c.setDelivery(message.Delivery)
+
 	case "getStats":
 		// Send connection statistics
 		response := map[string]interface{}{
 			"type":          "stats",
 			"clientCount":   c.hub.GetConnectedClients(),
 			"subscriptions": c.hub.GetSubscriptionStats(),
+			"channels":      c.hub.GetChannelStats(),
 			"yourSymbols":   c.getSymbolList(),
+			"yourBandwidth": c.bandwidthState(),
 			"timestamp":     time.Now().UnixMilli(),
 		}
 		c.sendMessage(response)
@@ -190,6 +321,73 @@ func (c *Client) handleMessage(message ClientMessage) {
 	}
 }
 
+// subscribeChannel handles subscription to a channel that spans all symbols
+func (c *Client) subscribeChannel(message ClientMessage) {
+	if knownChannels[message.Channel] {
+		if limitErr := c.hub.channelLimitError(c, message.Channel); limitErr != nil {
+			c.sendMessage(limitErr)
+			return
+		}
+	}
+
+	// The layout pair list is checked first, so a refused list leaves the subscription unchanged
+	var layoutPairs []LayoutPair
+	var rejectedPairs []LayoutPairRejection
+	if message.Channel == ChannelLayoutSync {
+		var pairs []LayoutPair
+		if message.Options != nil {
+			pairs = message.Options.Pairs
+		}
+		var limitErr *LimitError
+		if layoutPairs, rejectedPairs, limitErr = c.hub.SetLayoutPairs(c, pairs); limitErr != nil {
+			c.sendMessage(limitErr)
+			return
+		}
+	}
+
+	if !c.hub.SubscribeChannel(c, message.Channel) {
+		response := map[string]interface{}{
+			"type":      "error",
+			"channel":   message.Channel,
+			"message":   "Unknown channel: " + message.Channel,
+			"timestamp": time.Now().UnixMilli(),
+		}
+		c.sendMessage(response)
+		return
+	}
+
+	response := map[string]interface{}{
+		"type":      "subscribed",
+		"channel":   message.Channel,
+		"message":   "Successfully subscribed to " + message.Channel,
+		"timestamp": time.Now().UnixMilli(),
+	}
+
+	if message.Channel == ChannelLiquidationsAll {
+		minNotional := 0.0
+		if message.Options != nil {
+			minNotional = message.Options.MinNotional
+		}
+		c.hub.SetLiquidationMinNotional(c, minNotional)
+		response["min_notional"] = minNotional
+	}
+
+	if message.Channel == ChannelLayoutSync {
+		response["pairs"] = layoutPairs
+		response["rejected_pairs"] = rejectedPairs
+	}
+
+	if message.Channel == ChannelVolumeProfile {
+		var profiles []VolumeProfileSubscription
+		if message.Options != nil {
+			profiles = message.Options.Profiles
+		}
+		response["profiles"] = c.hub.SetVolumeProfiles(c, profiles)
+	}
+
+	c.sendMessage(response)
+}
+
 // sendMessage sends a message to this specific client
 func (c *Client) sendMessage(data interface{}) {
 	message, err := json.Marshal(data)
@@ -0,0 +1,568 @@
+package websocket
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/internal/coinbase"
+	"tterminal-backend/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// coinbaseChannels are the Advanced Trade channels subscribed for every product
+// Heartbeats keep the connection open while products are quiet
+var coinbaseChannels = []string{"ticker", "market_trades", "level2", "candles", "heartbeats"}
+
+// CoinbaseStream streams Coinbase Advanced Trade spot market data into the hub
+// Updates are broadcast under exchange-qualified symbols ("COINBASE:BTCUSD") with an "exchange"
+// field. Trades and book snapshots feed the same recorders as the Binance futures stream.
+// Coinbase only streams forming 5m candles without a close flag, so bar closes of every
+// interval are confirmed over REST by the scheduler
+type CoinbaseStream struct {
+	hub      *Hub
+	url      string
+	products []string // Coinbase product IDs ("BTC-USD")
+
+	mu          sync.RWMutex
+	conn        *websocket.Conn
+	isRunning   bool
+	connectedAt time.Time
+	reconnects  int64
+	books       map[string]*localBook
+	lastPrices  map[string]float64
+	lastMessage atomic.Int64 // Unix milliseconds
+
+	// Rolling VWAP/delta/size context for enriched trade updates
+	tradeEnricher *TradeEnricher
+	// Optional persistence of trades and book snapshots (set after start)
+	tradeRecorder atomic.Pointer[tradeRecorder]
+	depthRecorder atomic.Pointer[depthRecorder]
+	// Bar close events, confirmed over REST
+	barClose        *BarCloseScheduler
+	barCloseStarted sync.Once
+	// Normalized trades, book updates and liquidations for in-process consumers
+	events *MarketEvents
+}
+
+// coinbaseMessage is an Advanced Trade channel message
+type coinbaseMessage struct {
+	Channel string          `json:"channel"`
+	Type    string          `json:"type"` // "error" for rejected subscriptions
+	Message string          `json:"message"`
+	Events  json.RawMessage `json:"events"`
+}
+
+// coinbaseTickerEvent is a ticker channel event
+type coinbaseTickerEvent struct {
+	Tickers []struct {
+		ProductID    string `json:"product_id"`
+		Price        string `json:"price"`
+		Volume24h    string `json:"volume_24_h"`
+		PricePercent string `json:"price_percent_chg_24_h"`
+	} `json:"tickers"`
+}
+
+// coinbaseTradesEvent is a market_trades channel event
+type coinbaseTradesEvent struct {
+	Trades []struct {
+		TradeID   string    `json:"trade_id"`
+		ProductID string    `json:"product_id"`
+		Price     string    `json:"price"`
+		Size      string    `json:"size"`
+		Side      string    `json:"side"` // Taker side: "BUY" or "SELL"
+		Time      time.Time `json:"time"`
+	} `json:"trades"`
+}
+
+// coinbaseBookEvent is a level2 (l2_data) channel event
+type coinbaseBookEvent struct {
+	Type      string `json:"type"` // "snapshot" or "update"
+	ProductID string `json:"product_id"`
+	Updates   []struct {
+		Side     string `json:"side"` // "bid" or "offer"
+		Price    string `json:"price_level"`
+		Quantity string `json:"new_quantity"`
+	} `json:"updates"`
+}
+
+// coinbaseCandlesEvent is a candles channel event (forming 5m candles)
+type coinbaseCandlesEvent struct {
+	Candles []struct {
+		Start     string `json:"start"` // Unix seconds
+		Open      string `json:"open"`
+		High      string `json:"high"`
+		Low       string `json:"low"`
+		Close     string `json:"close"`
+		Volume    string `json:"volume"`
+		ProductID string `json:"product_id"`
+	} `json:"candles"`
+}
+
+// NewCoinbaseStream creates a Coinbase stream for bare symbols (e.g. "BTCUSD")
+func NewCoinbaseStream(hub *Hub, url string, symbols []string) *CoinbaseStream {
+	products := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		products[i] = coinbase.ProductID(symbol)
+	}
+
+	cs := &CoinbaseStream{
+		hub:           hub,
+		url:           url,
+		products:      products,
+		books:         make(map[string]*localBook),
+		lastPrices:    make(map[string]float64),
+		tradeEnricher: NewTradeEnricher(),
+	}
+	cs.barClose = newBarCloseScheduler(hub, cs.GetConnectedSymbols)
+	cs.events = newMarketEvents()
+	return cs
+}
+
+// Start connects to the Coinbase stream, reconnecting in the background on failure
+func (cs *CoinbaseStream) Start() error {
+	cs.barCloseStarted.Do(func() {
+		go cs.barClose.run(make(chan struct{}))
+	})
+
+	cs.mu.Lock()
+	cs.isRunning = true
+	cs.mu.Unlock()
+
+	if err := cs.connect(); err != nil {
+		log.Printf("Failed to connect to Coinbase stream: %v", err)
+		go cs.reconnect()
+		return nil
+	}
+
+	log.Printf("Connected to Coinbase WebSocket - Streaming %d spot products", len(cs.products))
+	return nil
+}
+
+// Stop disconnects from the Coinbase stream
+func (cs *CoinbaseStream) Stop() {
+	cs.mu.Lock()
+	cs.isRunning = false
+	conn := cs.conn
+	cs.conn = nil
+	cs.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+		log.Println("Coinbase WebSocket stream stopped")
+	}
+}
+
+// SetTradeWriter enables persistence of Coinbase trades
+func (cs *CoinbaseStream) SetTradeWriter(writer *TradeWriter) {
+	cs.tradeRecorder.Store(newTradeRecorder(writer, cs.hub))
+	log.Printf("Trade persistence enabled for Coinbase trades")
+}
+
+// SetDepthSnapshotWriter enables periodic persistence of Coinbase order book snapshots
+func (cs *CoinbaseStream) SetDepthSnapshotWriter(writer *DepthSnapshotWriter, interval time.Duration) {
+	recorder := newDepthRecorder(writer, interval)
+	cs.depthRecorder.Store(recorder)
+	log.Printf("Coinbase depth snapshot persistence enabled every %v", recorder.interval)
+}
+
+// connect dials the stream, subscribes to every channel and starts the read loop
+// Coinbase answers control-frame pings, so no application-level ping is needed
+func (cs *CoinbaseStream) connect() error {
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(cs.url, nil)
+	if err != nil {
+		return err
+	}
+
+	// Advanced Trade accepts one channel per subscribe message
+	for _, channel := range coinbaseChannels {
+		request := map[string]interface{}{"type": "subscribe", "product_ids": cs.products, "channel": channel}
+		if err := conn.WriteJSON(request); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	cs.mu.Lock()
+	cs.conn = conn
+	cs.connectedAt = time.Now()
+	// Order books restart from the snapshot sent after subscribing
+	cs.books = make(map[string]*localBook)
+	cs.mu.Unlock()
+
+	go cs.readMessages(conn)
+	return nil
+}
+
+// readMessages reads and processes messages until the connection fails
+func (cs *CoinbaseStream) readMessages(conn *websocket.Conn) {
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if cs.isCurrent(conn) {
+				log.Printf("Error reading from Coinbase WebSocket: %v", err)
+				cs.mu.Lock()
+				cs.conn = nil
+				cs.mu.Unlock()
+				cs.reconnect()
+			}
+			return
+		}
+
+		cs.processMessage(message)
+	}
+}
+
+// isCurrent reports whether conn is the live connection of a running stream
+func (cs *CoinbaseStream) isCurrent(conn *websocket.Conn) bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.isRunning && cs.conn == conn
+}
+
+// reconnect retries the connection until it succeeds or the stream is stopped
+func (cs *CoinbaseStream) reconnect() {
+	for {
+		time.Sleep(5 * time.Second)
+
+		cs.mu.Lock()
+		running := cs.isRunning
+		cs.reconnects++
+		cs.mu.Unlock()
+		if !running {
+			return
+		}
+
+		log.Println("Attempting to reconnect to Coinbase WebSocket...")
+		if err := cs.connect(); err != nil {
+			log.Printf("Coinbase reconnection failed: %v", err)
+			continue
+		}
+		log.Println("Successfully reconnected to Coinbase WebSocket")
+		return
+	}
+}
+
+// processMessage routes a stream message by channel
+func (cs *CoinbaseStream) processMessage(message []byte) {
+	cs.lastMessage.Store(time.Now().UnixMilli())
+
+	var msg coinbaseMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	if msg.Type == "error" {
+		log.Printf("Coinbase subscription rejected: %s", msg.Message)
+		return
+	}
+
+	switch msg.Channel {
+	case "ticker":
+		var events []coinbaseTickerEvent
+		if err := json.Unmarshal(msg.Events, &events); err == nil {
+			for _, event := range events {
+				cs.processTicker(event)
+			}
+		}
+
+	case "market_trades":
+		var events []coinbaseTradesEvent
+		if err := json.Unmarshal(msg.Events, &events); err == nil {
+			for _, event := range events {
+				cs.processTrades(event)
+			}
+		}
+
+	case "l2_data":
+		var events []coinbaseBookEvent
+		if err := json.Unmarshal(msg.Events, &events); err == nil {
+			for _, event := range events {
+				cs.processBook(event)
+			}
+		}
+
+	case "candles":
+		var events []coinbaseCandlesEvent
+		if err := json.Unmarshal(msg.Events, &events); err == nil {
+			for _, event := range events {
+				cs.processCandles(event)
+			}
+		}
+	}
+}
+
+// coinbaseSymbolKey returns the qualified symbol of a product ("COINBASE:BTCUSD")
+func coinbaseSymbolKey(productID string) string {
+	return models.QualifySymbol(models.ExchangeCoinbase, coinbase.Symbol(productID))
+}
+
+// processTicker broadcasts a price update when the last price changes
+func (cs *CoinbaseStream) processTicker(event coinbaseTickerEvent) {
+	for _, ticker := range event.Tickers {
+		key := coinbaseSymbolKey(ticker.ProductID)
+		price := models.ParseFloat(ticker.Price)
+		if price <= 0 {
+			continue
+		}
+
+		cs.mu.Lock()
+		priceChanged := cs.lastPrices[key] != price
+		cs.lastPrices[key] = price
+		cs.mu.Unlock()
+		if !priceChanged {
+			continue
+		}
+
+		changePercent := models.ParseFloat(ticker.PricePercent)
+		update := PriceUpdate{
+			Type:          "price_update",
+			Symbol:        key,
+			Exchange:      models.ExchangeCoinbase,
+			Price:         price,
+			Change:        price - price/(1+changePercent/100),
+			ChangePercent: changePercent,
+			Volume:        models.ParseFloat(ticker.Volume24h),
+			Timestamp:     time.Now().UnixMilli(),
+		}
+		cs.hub.BroadcastPriceUpdate(update)
+		cs.hub.QueueLitePrice(update)
+	}
+}
+
+// processTrades broadcasts, records and profiles trades
+func (cs *CoinbaseStream) processTrades(event coinbaseTradesEvent) {
+	for _, trade := range event.Trades {
+		price, err := strconv.ParseFloat(trade.Price, 64)
+		if err != nil {
+			continue
+		}
+		quantity, err := strconv.ParseFloat(trade.Size, 64)
+		if err != nil {
+			continue
+		}
+
+		key := coinbaseSymbolKey(trade.ProductID)
+		tradeTime := trade.Time.UnixMilli()
+		// A taker sell hits the bid, so the buyer is the maker
+		isBuyerMaker := trade.Side == "SELL"
+
+		tradeUpdate := map[string]interface{}{
+			"type":           "trade_update",
+			"symbol":         key,
+			"exchange":       models.ExchangeCoinbase,
+			"price":          price,
+			"quantity":       quantity,
+			"is_buyer_maker": isBuyerMaker,
+			"trade_time":     tradeTime,
+			"timestamp":      time.Now().UnixMilli(),
+		}
+
+		tradeID, err := strconv.ParseInt(trade.TradeID, 10, 64)
+		if err != nil {
+			// Stored trades need a numeric ID
+			hash := fnv.New64a()
+			hash.Write([]byte(trade.TradeID))
+			tradeID = int64(hash.Sum64() >> 1)
+		}
+		record := models.TradeRecord{
+			Symbol:       key,
+			TradeID:      tradeID,
+			Price:        price,
+			Quantity:     quantity,
+			IsBuyerMaker: isBuyerMaker,
+			TradeTime:    trade.Time,
+		}
+		if recorder := cs.tradeRecorder.Load(); recorder != nil {
+			recorder.record(record)
+		}
+		cs.events.emitTrade(record)
+
+		cs.hub.QueueVolumeProfileTrade(key, price, quantity, isBuyerMaker, tradeTime)
+
+		tradeContext := cs.tradeEnricher.Update(key, price, quantity, isBuyerMaker, tradeTime)
+		cs.hub.BroadcastEnrichedTradeUpdate(tradeUpdate, tradeContext)
+	}
+}
+
+// processBook applies a level2 snapshot or update and broadcasts the changed levels
+func (cs *CoinbaseStream) processBook(event coinbaseBookEvent) {
+	key := coinbaseSymbolKey(event.ProductID)
+	snapshot := event.Type == "snapshot"
+
+	var bids, asks [][]string
+	for _, update := range event.Updates {
+		level := []string{update.Price, update.Quantity}
+		if update.Side == "bid" {
+			bids = append(bids, level)
+		} else {
+			asks = append(asks, level)
+		}
+	}
+
+	cs.mu.Lock()
+	book, exists := cs.books[event.ProductID]
+	if snapshot || !exists {
+		book = &localBook{bids: make(map[string]string), asks: make(map[string]string)}
+		cs.books[event.ProductID] = book
+	}
+	applyBookLevels(book.bids, bids)
+	applyBookLevels(book.asks, asks)
+
+	var full *models.DepthUpdate
+	if cs.depthRecorder.Load() != nil {
+		full = &models.DepthUpdate{
+			Symbol:   key,
+			Bids:     sortedBookLevels(book.bids, true),
+			Asks:     sortedBookLevels(book.asks, false),
+			Snapshot: true,
+			Time:     time.Now(),
+		}
+	}
+	cs.mu.Unlock()
+
+	// Depth snapshots sample the whole local book, not the update
+	if recorder := cs.depthRecorder.Load(); recorder != nil && full != nil {
+		recorder.observe(*full)
+	}
+
+	cs.events.emitDepth(models.DepthUpdate{
+		Symbol:   key,
+		Bids:     bids,
+		Asks:     asks,
+		Snapshot: snapshot,
+		Time:     time.Now(),
+	})
+
+	// Snapshots hold the whole book; only updates are forwarded to clients
+	if snapshot {
+		return
+	}
+	cs.hub.BroadcastDepthUpdate(map[string]interface{}{
+		"type":      "depth_update",
+		"symbol":    key,
+		"exchange":  models.ExchangeCoinbase,
+		"bids":      bids,
+		"asks":      asks,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// processCandles broadcasts forming 5m candles
+func (cs *CoinbaseStream) processCandles(event coinbaseCandlesEvent) {
+	duration := 5 * time.Minute
+	for _, data := range event.Candles {
+		seconds, err := strconv.ParseInt(data.Start, 10, 64)
+		if err != nil {
+			continue
+		}
+		start := seconds * 1000
+		key := coinbaseSymbolKey(data.ProductID)
+
+		open := models.ParseFloat(data.Open)
+		high := models.ParseFloat(data.High)
+		low := models.ParseFloat(data.Low)
+		close := models.ParseFloat(data.Close)
+		volume := models.ParseFloat(data.Volume)
+
+		cs.hub.BroadcastKlineUpdate(map[string]interface{}{
+			"type":       "kline_update",
+			"symbol":     key,
+			"exchange":   models.ExchangeCoinbase,
+			"interval":   "5m",
+			"open":       open,
+			"high":       high,
+			"low":        low,
+			"close":      close,
+			"volume":     volume,
+			"is_closed":  false,
+			"start_time": start,
+			"end_time":   start + duration.Milliseconds() - 1,
+			"timestamp":  time.Now().UnixMilli(),
+		})
+
+		cs.hub.QueueLayoutCandle(LayoutCandle{
+			Symbol:    key,
+			Interval:  "5m",
+			StartTime: start,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+		})
+	}
+}
+
+// BarCloses returns the bar close scheduler for registering handlers and configuring its clock
+func (cs *CoinbaseStream) BarCloses() *BarCloseScheduler {
+	return cs.barClose
+}
+
+// Events returns the stream's normalized market events for registering handlers
+func (cs *CoinbaseStream) Events() *MarketEvents {
+	return cs.events
+}
+
+// GetConnectedSymbols returns the qualified symbols being streamed
+func (cs *CoinbaseStream) GetConnectedSymbols() []string {
+	symbols := make([]string, len(cs.products))
+	for i, product := range cs.products {
+		symbols[i] = coinbaseSymbolKey(product)
+	}
+	return symbols
+}
+
+// GetLastPrice returns the last known price for a qualified symbol
+func (cs *CoinbaseStream) GetLastPrice(symbol string) (float64, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	price, exists := cs.lastPrices[symbol]
+	return price, exists
+}
+
+// GetRecentLiquidations returns no liquidations: spot markets have none
+func (cs *CoinbaseStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	return nil
+}
+
+// GetStreamStats returns statistics about the Coinbase stream
+func (cs *CoinbaseStream) GetStreamStats() map[string]interface{} {
+	cs.mu.RLock()
+	stats := map[string]interface{}{
+		"exchange":          models.ExchangeCoinbase,
+		"connected_symbols": len(cs.products),
+		"symbols":           cs.GetConnectedSymbols(),
+		"products":          cs.products,
+		"price_data_count":  len(cs.lastPrices),
+		"book_count":        len(cs.books),
+		"is_running":        cs.isRunning,
+		"connected":         cs.conn != nil,
+		"reconnects":        cs.reconnects,
+		"stream_types":      coinbaseChannels,
+	}
+	if !cs.connectedAt.IsZero() {
+		stats["connected_at"] = cs.connectedAt.UnixMilli()
+	}
+	cs.mu.RUnlock()
+
+	if last := cs.lastMessage.Load(); last > 0 {
+		stats["last_message_at"] = last
+	}
+	stats["bar_close"] = cs.barClose.stats()
+	if recorder := cs.tradeRecorder.Load(); recorder != nil {
+		stats["trade_persistence"] = recorder.stats()
+	}
+	if recorder := cs.depthRecorder.Load(); recorder != nil {
+		stats["depth_persistence"] = recorder.stats()
+	}
+	return stats
+}
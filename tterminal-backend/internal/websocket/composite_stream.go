@@ -0,0 +1,285 @@
+package websocket
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+// compositeSampleInterval is how often composites are recomputed from constituent prices
+const compositeSampleInterval = time.Second
+
+// PriceLookup returns the last streamed price of a symbol key
+type PriceLookup func(symbol string) (float64, bool)
+
+// compositeKline is a composite kline being built from price samples
+type compositeKline struct {
+	start                  int64
+	open, high, low, close float64
+}
+
+// CompositeStream streams user-defined composite symbols ("COMPOSITE:ETHBTC") into the hub
+// Composites are recomputed every second from their constituents' last streamed prices, and
+// broadcast as price updates and forming 1m/5m/15m klines with an "exchange" field of
+// "composite". Closed bars are computed from constituent bars by the composite service and
+// published here, replacing the sampled forming bar. Composites whose constituents are not all
+// streamed get closed bars only
+type CompositeStream struct {
+	hub    *Hub
+	prices PriceLookup
+
+	mu         sync.RWMutex
+	composites map[string]*models.CompositeExpression // Keyed by composite symbol
+	lastPrices map[string]float64
+	klines     map[string]*compositeKline // Forming klines keyed by "SYMBOL:interval"
+	isRunning  bool
+	stop       chan struct{}
+	published  int64 // Closed bars published
+}
+
+// NewCompositeStream creates a composite stream reading constituent prices from prices
+func NewCompositeStream(hub *Hub, prices PriceLookup) *CompositeStream {
+	return &CompositeStream{
+		hub:        hub,
+		prices:     prices,
+		composites: make(map[string]*models.CompositeExpression),
+		lastPrices: make(map[string]float64),
+		klines:     make(map[string]*compositeKline),
+	}
+}
+
+// Start begins sampling composites
+func (cs *CompositeStream) Start() {
+	cs.mu.Lock()
+	if cs.isRunning {
+		cs.mu.Unlock()
+		return
+	}
+	cs.isRunning = true
+	cs.stop = make(chan struct{})
+	stop := cs.stop
+	cs.mu.Unlock()
+
+	go cs.run(stop)
+	log.Printf("Composite stream started")
+}
+
+// Stop stops sampling composites
+func (cs *CompositeStream) Stop() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.isRunning {
+		return
+	}
+	cs.isRunning = false
+	close(cs.stop)
+}
+
+// SetComposite adds or replaces a composite
+func (cs *CompositeStream) SetComposite(symbol string, expression *models.CompositeExpression) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.composites[symbol] = expression
+}
+
+// RemoveComposite stops streaming a composite
+func (cs *CompositeStream) RemoveComposite(symbol string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.composites, symbol)
+	delete(cs.lastPrices, symbol)
+	for _, interval := range barCloseIntervals {
+		delete(cs.klines, symbol+":"+interval)
+	}
+}
+
+// run samples every composite until stopped
+func (cs *CompositeStream) run(stop chan struct{}) {
+	ticker := time.NewTicker(compositeSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			cs.sample(now)
+		}
+	}
+}
+
+// sample evaluates each composite whose constituents all have prices and broadcasts the result
+func (cs *CompositeStream) sample(now time.Time) {
+	cs.mu.RLock()
+	composites := make(map[string]*models.CompositeExpression, len(cs.composites))
+	for symbol, expression := range cs.composites {
+		composites[symbol] = expression
+	}
+	cs.mu.RUnlock()
+
+	for symbol, expression := range composites {
+		prices := make(map[string]float64, len(expression.Constituents()))
+		complete := true
+		for _, constituent := range expression.Constituents() {
+			price, ok := cs.prices(constituent)
+			if !ok {
+				complete = false
+				break
+			}
+			prices[constituent] = price
+		}
+		if !complete {
+			continue
+		}
+
+		value, err := expression.Evaluate(prices)
+		if err != nil {
+			continue
+		}
+		cs.update(symbol, value, now.UnixMilli())
+	}
+}
+
+// update broadcasts a new composite value and adds it to the forming klines
+func (cs *CompositeStream) update(symbol string, value float64, timestamp int64) {
+	cs.mu.Lock()
+	priceChanged := cs.lastPrices[symbol] != value
+	cs.lastPrices[symbol] = value
+	cs.mu.Unlock()
+	if !priceChanged {
+		return
+	}
+
+	update := PriceUpdate{
+		Type:      "price_update",
+		Symbol:    symbol,
+		Exchange:  models.ExchangeComposite,
+		Price:     value,
+		Timestamp: timestamp,
+	}
+	cs.hub.BroadcastPriceUpdate(update)
+	cs.hub.QueueLitePrice(update)
+
+	for _, interval := range barCloseIntervals {
+		cs.updateKline(symbol, interval, value, timestamp)
+	}
+}
+
+// updateKline adds a sample to the forming kline of an interval and broadcasts it
+func (cs *CompositeStream) updateKline(symbol, interval string, value float64, timestamp int64) {
+	duration, _ := models.IntervalDuration(interval)
+	start := timestamp - timestamp%duration.Milliseconds()
+
+	cs.mu.Lock()
+	kline, exists := cs.klines[symbol+":"+interval]
+	if !exists || kline.start < start {
+		kline = &compositeKline{start: start, open: value, high: value, low: value}
+		cs.klines[symbol+":"+interval] = kline
+	}
+	if value > kline.high {
+		kline.high = value
+	}
+	if value < kline.low {
+		kline.low = value
+	}
+	kline.close = value
+	formed := *kline
+	cs.mu.Unlock()
+
+	cs.broadcastKline(symbol, interval, formed, false)
+}
+
+// PublishClosedBar broadcasts a composite bar computed from closed constituent bars
+func (cs *CompositeStream) PublishClosedBar(candle models.Candle) {
+	cs.mu.Lock()
+	cs.published++
+	cs.mu.Unlock()
+
+	cs.broadcastKline(candle.Symbol, candle.Interval, compositeKline{
+		start: candle.OpenTime.UnixMilli(),
+		open:  models.ParseFloat(candle.Open),
+		high:  models.ParseFloat(candle.High),
+		low:   models.ParseFloat(candle.Low),
+		close: models.ParseFloat(candle.Close),
+	}, true)
+}
+
+// broadcastKline sends a composite kline to subscribers and the layout and lite queues
+// Composites have no volume of their own
+func (cs *CompositeStream) broadcastKline(symbol, interval string, kline compositeKline, closed bool) {
+	duration, _ := models.IntervalDuration(interval)
+
+	cs.hub.BroadcastKlineUpdate(map[string]interface{}{
+		"type":       "kline_update",
+		"symbol":     symbol,
+		"exchange":   models.ExchangeComposite,
+		"interval":   interval,
+		"open":       kline.open,
+		"high":       kline.high,
+		"low":        kline.low,
+		"close":      kline.close,
+		"volume":     0,
+		"is_closed":  closed,
+		"start_time": kline.start,
+		"end_time":   kline.start + duration.Milliseconds() - 1,
+		"timestamp":  time.Now().UnixMilli(),
+	})
+
+	candle := LayoutCandle{
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: kline.start,
+		Open:      kline.open,
+		High:      kline.high,
+		Low:       kline.low,
+		Close:     kline.close,
+	}
+	cs.hub.QueueLayoutCandle(candle)
+	if interval == "1m" {
+		cs.hub.QueueLiteKline(candle)
+	}
+}
+
+// GetConnectedSymbols returns the composite symbols being streamed
+func (cs *CompositeStream) GetConnectedSymbols() []string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	symbols := make([]string, 0, len(cs.composites))
+	for symbol := range cs.composites {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// GetLastPrice returns the last computed value of a composite
+func (cs *CompositeStream) GetLastPrice(symbol string) (float64, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	price, exists := cs.lastPrices[symbol]
+	return price, exists
+}
+
+// GetRecentLiquidations returns no liquidations: composites are not traded
+func (cs *CompositeStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	return nil
+}
+
+// GetStreamStats returns statistics about the composite stream
+func (cs *CompositeStream) GetStreamStats() map[string]interface{} {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	return map[string]interface{}{
+		"exchange":          models.ExchangeComposite,
+		"composites":        len(cs.composites),
+		"price_data_count":  len(cs.lastPrices),
+		"forming_klines":    len(cs.klines),
+		"closed_bars":       cs.published,
+		"is_running":        cs.isRunning,
+		"sample_interval_s": compositeSampleInterval.Seconds(),
+	}
+}
@@ -0,0 +1,53 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// DegradedModeStatus tells clients that upstream market data is unavailable and REST
+// responses are being served from stored data, or that it has recovered
+type DegradedModeStatus struct {
+	Type      string `json:"type"` // Always "degraded_mode"
+	Degraded  bool   `json:"degraded"`
+	Reason    string `json:"reason,omitempty"`
+	Since     int64  `json:"since"` // When the current mode started (Unix milliseconds)
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SetDegradedMode records the upstream status and broadcasts a "degraded_mode" event to every client
+func (h *Hub) SetDegradedMode(degraded bool, reason string, since time.Time) {
+	status := &DegradedModeStatus{
+		Type:      "degraded_mode",
+		Degraded:  degraded,
+		Reason:    reason,
+		Since:     since.UnixMilli(),
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	message, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("Error marshaling degraded mode status: %v", err)
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.degradedMode = status
+	for client := range h.clients {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+}
+
+// GetDegradedMode returns the last broadcast upstream status (nil if never degraded)
+func (h *Hub) GetDegradedMode() *DegradedModeStatus {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.degradedMode
+}
@@ -0,0 +1,52 @@
+package websocket
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// deliveryLagSampleInterval is how often each client's delivery lag is sampled
+	deliveryLagSampleInterval = 250 * time.Millisecond
+
+	// maxDeliveryLag discards samples of messages whose timestamp is not a recent event time
+	maxDeliveryLag = time.Minute
+)
+
+// LatencyRecorder receives latency samples per key
+type LatencyRecorder interface {
+	Observe(key string, latency time.Duration)
+}
+
+// SetDeliveryLagRecorder samples how long messages take from their timestamp (the exchange event
+// or server time they carry) to being written to the client, per message type
+func (h *Hub) SetDeliveryLagRecorder(recorder LatencyRecorder) {
+	h.deliveryLag.Store(&recorder)
+}
+
+// sampleDeliveryLag records the lag of the oldest message of a batch just written, at most once
+// per sample interval. Called from the write goroutine
+func (c *Client) sampleDeliveryLag(batch [][]byte) {
+	recorder := c.hub.deliveryLag.Load()
+	if recorder == nil || len(batch) == 0 {
+		return
+	}
+	now := time.Now()
+	if now.Sub(c.lagSampledAt) < deliveryLagSampleInterval {
+		return
+	}
+	c.lagSampledAt = now
+
+	var header struct {
+		Type      string `json:"type"`
+		Timestamp int64  `json:"timestamp"` // Unix milliseconds
+	}
+	if json.Unmarshal(batch[0], &header) != nil || header.Type == "" || header.Timestamp <= 0 {
+		return
+	}
+	lag := now.Sub(time.UnixMilli(header.Timestamp))
+	if lag < 0 || lag > maxDeliveryLag {
+		return
+	}
+	(*recorder).Observe(header.Type, lag)
+}
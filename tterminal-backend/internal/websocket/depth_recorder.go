@@ -0,0 +1,111 @@
+package websocket
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+	"tterminal-backend/internal/batchwriter"
+	"tterminal-backend/models"
+)
+
+// defaultDepthSnapshotInterval is how often the futures book is sampled when no interval is set
+const defaultDepthSnapshotInterval = 10 * time.Second
+
+// DepthSnapshotWriter batches sampled order book snapshots into the depth snapshot store
+type DepthSnapshotWriter = batchwriter.Writer[models.DepthSnapshot]
+
+// depthRecorder samples the latest futures book per symbol and hands it to a batch writer off
+// the stream's read path
+type depthRecorder struct {
+	writer   *DepthSnapshotWriter
+	interval time.Duration
+
+	mu     sync.Mutex
+	latest map[string]models.DepthUpdate
+}
+
+// newDepthRecorder creates and starts a recorder sampling books every interval
+func newDepthRecorder(writer *DepthSnapshotWriter, interval time.Duration) *depthRecorder {
+	if interval <= 0 {
+		interval = defaultDepthSnapshotInterval
+	}
+
+	recorder := &depthRecorder{
+		writer:   writer,
+		interval: interval,
+		latest:   make(map[string]models.DepthUpdate),
+	}
+	go recorder.run()
+	return recorder
+}
+
+// SetDepthSnapshotWriter enables periodic persistence of futures order book snapshots
+func (bs *BinanceStream) SetDepthSnapshotWriter(writer *DepthSnapshotWriter, interval time.Duration) {
+	recorder := newDepthRecorder(writer, interval)
+	bs.depthRecorder.Store(recorder)
+	log.Printf("Depth snapshot persistence enabled every %v", recorder.interval)
+}
+
+// observe keeps the most recent full book of a symbol for the next sample
+func (r *depthRecorder) observe(book models.DepthUpdate) {
+	r.mu.Lock()
+	r.latest[book.Symbol] = book
+	r.mu.Unlock()
+}
+
+// run samples the latest books once per interval
+func (r *depthRecorder) run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		r.mu.Lock()
+		latest := r.latest
+		r.latest = make(map[string]models.DepthUpdate, len(latest))
+		r.mu.Unlock()
+
+		snapshotTime := now.Truncate(time.Second)
+		snapshots := make([]models.DepthSnapshot, 0, len(latest))
+		for symbol, book := range latest {
+			snapshot := models.DepthSnapshot{
+				Symbol: symbol,
+				Time:   snapshotTime,
+				Bids:   parseDepthLevels(book.Bids),
+				Asks:   parseDepthLevels(book.Asks),
+			}
+			if len(snapshot.Bids) > 0 || len(snapshot.Asks) > 0 {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		r.writer.Add(snapshots...)
+	}
+}
+
+// stats returns the sampling interval and the writer's counters
+func (r *depthRecorder) stats() map[string]interface{} {
+	return map[string]interface{}{
+		"interval_seconds": r.interval.Seconds(),
+		"writer":           r.writer.Stats(),
+	}
+}
+
+// parseDepthLevels converts [price, quantity] string pairs, skipping removed (zero) levels
+func parseDepthLevels(raw [][]string) []models.DepthLevel {
+	levels := make([]models.DepthLevel, 0, len(raw))
+	for _, entry := range raw {
+		if len(entry) < 2 {
+			continue
+		}
+		price, err := strconv.ParseFloat(entry[0], 64)
+		if err != nil || price <= 0 {
+			continue
+		}
+		quantity, err := strconv.ParseFloat(entry[1], 64)
+		if err != nil || quantity <= 0 {
+			continue
+		}
+		levels = append(levels, models.DepthLevel{Price: price, Quantity: quantity})
+	}
+	return levels
+}
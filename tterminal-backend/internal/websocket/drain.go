@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+	"tterminal-backend/internal/transport"
+)
+
+// ErrDraining is returned for new connections while the instance drains for a restart
+var ErrDraining = errors.New("instance is draining; reconnect to another instance")
+
+// ReconnectHint tells clients the instance is draining and where to reconnect
+type ReconnectHint struct {
+	Type            string   `json:"type"`   // Always "reconnect"
+	Reason          string   `json:"reason"` // Always "draining"
+	Peers           []string `json:"peers"`  // Healthy instances, in preference order; empty means reconnect through the load balancer
+	ReconnectWithin int64    `json:"reconnect_within_ms"`
+	Timestamp       int64    `json:"timestamp"`
+}
+
+// StartDrain stops accepting connections and sends every client a "reconnect" hint and a
+// "reconnect_to" advisory ranking the peers. Clients should reconnect to a peer within grace,
+// after which CloseAllClients drops them
+func (h *Hub) StartDrain(peers []transport.ReconnectPeer, grace time.Duration) {
+	advisory, err := h.reconnectAdvisory(ReconnectDraining, peers, grace)
+	if err != nil {
+		log.Printf("Error marshaling reconnect advisory: %v", err)
+		return
+	}
+
+	h.routing.mu.RLock()
+	ranked := RankPeers(peers, h.routing.region)
+	h.routing.mu.RUnlock()
+	urls := make([]string, 0, len(ranked))
+	for _, peer := range ranked {
+		urls = append(urls, peer.URL)
+	}
+	hint := &ReconnectHint{
+		Type:            "reconnect",
+		Reason:          "draining",
+		Peers:           urls,
+		ReconnectWithin: grace.Milliseconds(),
+		Timestamp:       time.Now().UnixMilli(),
+	}
+
+	message, err := json.Marshal(hint)
+	if err != nil {
+		log.Printf("Error marshaling reconnect hint: %v", err)
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.drainHint = hint
+	for client := range h.clients {
+		for _, payload := range [][]byte{message, advisory} {
+			select {
+			case client.send <- payload:
+			default:
+				// Client buffer full, skip rather than block the hub
+			}
+		}
+	}
+	log.Printf("Draining: sent reconnect hint to %d clients (%d peers)", len(h.clients), len(peers))
+}
+
+// IsDraining reports whether the instance has stopped accepting connections
+func (h *Hub) IsDraining() bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.drainHint != nil
+}
+
+// GetDrainHint returns the reconnect hint sent when draining started (nil if not draining)
+func (h *Hub) GetDrainHint() *ReconnectHint {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.drainHint
+}
+
+// CloseAllClients disconnects every WebSocket client and long-polling session
+func (h *Hub) CloseAllClients() {
+	h.mutex.RLock()
+	conns := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		if client.conn != nil {
+			conns = append(conns, client)
+		}
+	}
+	h.mutex.RUnlock()
+
+	// Closing the connection ends the read pump, which unregisters the client
+	for _, client := range conns {
+		client.conn.Close()
+	}
+
+	h.pollSessions.mu.Lock()
+	sessions := make([]*Client, 0, len(h.pollSessions.sessions))
+	for sessionID, session := range h.pollSessions.sessions {
+		sessions = append(sessions, session.client)
+		delete(h.pollSessions.sessions, sessionID)
+	}
+	h.pollSessions.mu.Unlock()
+
+	for _, client := range sessions {
+		h.unregister <- client
+	}
+	log.Printf("Draining: closed %d connections and %d long-polling sessions", len(conns), len(sessions))
+}
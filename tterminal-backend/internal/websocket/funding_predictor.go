@@ -0,0 +1,181 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// premiumSampleInterval matches Binance's premium index sampling cadence
+	premiumSampleInterval = 5 * time.Second
+
+	// defaultFundingInterval is used when the funding window length cannot be inferred
+	defaultFundingInterval = 8 * time.Hour
+
+	// fundingInterestRate is Binance's fixed interest rate per 8h funding interval (0.01%)
+	fundingInterestRate = 0.0001
+
+	// fundingClampLimit bounds the interest-minus-premium adjustment (+/-0.05%)
+	fundingClampLimit = 0.0005
+
+	// fundingHistoryLimit is the number of closed funding windows kept per symbol (90 days at 8h)
+	fundingHistoryLimit = 270
+)
+
+// FundingPrediction carries the live premium index and predicted next funding rate
+type FundingPrediction struct {
+	Symbol               string  `json:"symbol"`
+	PremiumIndex         float64 `json:"premium_index"`          // Latest (mark - index) / index sample
+	AveragePremiumIndex  float64 `json:"average_premium_index"`  // Time-weighted average over the funding window
+	PredictedFundingRate float64 `json:"predicted_funding_rate"` // Average premium + clamp(interest - average premium)
+	InterestRate         float64 `json:"interest_rate"`
+	WindowStart          int64   `json:"window_start"`
+	NextFundingTime      int64   `json:"next_funding_time"`
+	Samples              int     `json:"samples"`
+}
+
+// FundingPredictionRecord is the final prediction made for a closed funding window
+type FundingPredictionRecord struct {
+	FundingTime          int64   `json:"funding_time"`
+	PredictedFundingRate float64 `json:"predicted_funding_rate"`
+	AveragePremiumIndex  float64 `json:"average_premium_index"`
+	Samples              int     `json:"samples"`
+}
+
+// fundingWindowState holds the running premium average for one symbol's funding window
+type fundingWindowState struct {
+	windowStart     int64
+	nextFundingTime int64
+	lastSampleTime  int64
+	samples         int
+	weightedSum     float64 // Sum of sample index * premium
+	weightTotal     float64 // Sum of sample indexes
+	lastPremium     float64
+	history         []FundingPredictionRecord
+}
+
+// FundingPredictor replicates Binance's funding formula from streamed mark and index prices
+type FundingPredictor struct {
+	mu     sync.RWMutex
+	states map[string]*fundingWindowState
+}
+
+// NewFundingPredictor creates a new funding predictor
+func NewFundingPredictor() *FundingPredictor {
+	return &FundingPredictor{
+		states: make(map[string]*fundingWindowState),
+	}
+}
+
+// Update folds a mark/index price observation into the symbol's funding window
+func (p *FundingPredictor) Update(symbol string, markPrice, indexPrice float64, nextFundingTime, eventTime int64) FundingPrediction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, exists := p.states[symbol]
+	if !exists {
+		state = &fundingWindowState{}
+		p.states[symbol] = state
+	}
+
+	// A new next funding time means the previous window settled
+	if nextFundingTime != state.nextFundingTime {
+		if state.nextFundingTime != 0 && state.samples > 0 {
+			prediction := state.prediction(symbol)
+			state.history = append(state.history, FundingPredictionRecord{
+				FundingTime:          state.nextFundingTime,
+				PredictedFundingRate: prediction.PredictedFundingRate,
+				AveragePremiumIndex:  prediction.AveragePremiumIndex,
+				Samples:              prediction.Samples,
+			})
+			if len(state.history) > fundingHistoryLimit {
+				state.history = state.history[len(state.history)-fundingHistoryLimit:]
+			}
+		}
+
+		windowLength := defaultFundingInterval.Milliseconds()
+		if state.nextFundingTime != 0 && nextFundingTime > state.nextFundingTime {
+			windowLength = nextFundingTime - state.nextFundingTime
+		}
+
+		state.windowStart = nextFundingTime - windowLength
+		state.nextFundingTime = nextFundingTime
+		state.lastSampleTime = 0
+		state.samples = 0
+		state.weightedSum = 0
+		state.weightTotal = 0
+	}
+
+	if indexPrice > 0 {
+		state.lastPremium = (markPrice - indexPrice) / indexPrice
+
+		// Sample at Binance's cadence; later samples carry more weight
+		if eventTime-state.lastSampleTime >= premiumSampleInterval.Milliseconds() {
+			state.samples++
+			state.weightedSum += float64(state.samples) * state.lastPremium
+			state.weightTotal += float64(state.samples)
+			state.lastSampleTime = eventTime
+		}
+	}
+
+	return state.prediction(symbol)
+}
+
+// Get returns the current funding prediction for a symbol
+func (p *FundingPredictor) Get(symbol string) (FundingPrediction, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	state, exists := p.states[symbol]
+	if !exists {
+		return FundingPrediction{}, false
+	}
+	return state.prediction(symbol), true
+}
+
+// History returns the final predictions of closed funding windows, oldest first
+func (p *FundingPredictor) History(symbol string) []FundingPredictionRecord {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	state, exists := p.states[symbol]
+	if !exists {
+		return nil
+	}
+
+	history := make([]FundingPredictionRecord, len(state.history))
+	copy(history, state.history)
+	return history
+}
+
+// prediction applies Binance's funding formula to the window's average premium
+func (s *fundingWindowState) prediction(symbol string) FundingPrediction {
+	averagePremium := s.lastPremium
+	if s.weightTotal > 0 {
+		averagePremium = s.weightedSum / s.weightTotal
+	}
+
+	// Interest rate is quoted per 8h; scale for shorter or longer funding intervals
+	interestRate := fundingInterestRate
+	if windowLength := s.nextFundingTime - s.windowStart; windowLength > 0 {
+		interestRate = fundingInterestRate * float64(windowLength) / float64(defaultFundingInterval.Milliseconds())
+	}
+
+	adjustment := interestRate - averagePremium
+	if adjustment > fundingClampLimit {
+		adjustment = fundingClampLimit
+	} else if adjustment < -fundingClampLimit {
+		adjustment = -fundingClampLimit
+	}
+
+	return FundingPrediction{
+		Symbol:               symbol,
+		PremiumIndex:         s.lastPremium,
+		AveragePremiumIndex:  averagePremium,
+		PredictedFundingRate: averagePremium + adjustment,
+		InterestRate:         interestRate,
+		WindowStart:          s.windowStart,
+		NextFundingTime:      s.nextFundingTime,
+		Samples:              s.samples,
+	}
+}
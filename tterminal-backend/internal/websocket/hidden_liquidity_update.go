@@ -0,0 +1,47 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+	"tterminal-backend/models"
+)
+
+// HiddenLiquidityUpdate carries the changed hidden liquidity estimates of tracked symbols
+type HiddenLiquidityUpdate struct {
+	Type      string                           `json:"type"`    // Always "hidden_liquidity_update"
+	Channel   string                           `json:"channel"` // Always "hidden_liquidity"
+	Estimates []models.HiddenLiquidityEstimate `json:"estimates"`
+	Timestamp int64                            `json:"timestamp"`
+}
+
+// BroadcastHiddenLiquidityUpdate sends a "hidden_liquidity_update" event to clients subscribed to
+// the "hidden_liquidity" channel
+func (h *Hub) BroadcastHiddenLiquidityUpdate(estimates []models.HiddenLiquidityEstimate) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients := h.channelSubscriptions[ChannelHiddenLiquidity]
+	if len(clients) == 0 {
+		return
+	}
+
+	message, err := json.Marshal(&HiddenLiquidityUpdate{
+		Type:      "hidden_liquidity_update",
+		Channel:   ChannelHiddenLiquidity,
+		Estimates: estimates,
+		Timestamp: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Error marshaling hidden liquidity update: %v", err)
+		return
+	}
+
+	for client := range clients {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+}
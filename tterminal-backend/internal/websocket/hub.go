@@ -7,7 +7,9 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
+	"tterminal-backend/models"
 
 	"github.com/gorilla/websocket"
 )
@@ -31,6 +33,68 @@ type Hub struct {
 
 	// Symbol subscriptions (symbol -> clients)
 	subscriptions map[string]map[*Client]bool
+
+	// Channel subscriptions not bound to a single symbol (channel -> clients)
+	channelSubscriptions map[string]map[*Client]bool
+
+	// Optional store for per-user subscription persistence
+	subscriptionStore SubscriptionStore
+
+	// Optional resolver for subscriptions by canonical symbol ("BTC-PERP")
+	symbolResolver SymbolResolver
+
+	// Changed candles awaiting the next layout sync frame
+	layoutSync *layoutSyncBuffer
+
+	// Trade volume awaiting the next volume profile delta
+	volumeProfile *volumeProfileBuffer
+
+	// Default keepalive and frame size settings for new connections
+	transport TransportConfig
+
+	// Per-client subscription and inbound message quotas
+	limits ClientLimits
+
+	// Long-polling sessions for clients that cannot hold a WebSocket open
+	pollSessions *pollSessionRegistry
+
+	// Last upstream status event, replayed to clients connecting during an outage
+	degradedMode *DegradedModeStatus
+
+	// Reconnect hint sent when the instance started draining (nil while serving)
+	drainHint *ReconnectHint
+
+	// Watch-only lite clients per symbol, their connection counts and caps
+	liteSubscriptions   map[string]map[*Client]bool
+	liteConnections     int
+	liteConnectionsByIP map[string]int
+	liteConfig          LiteConfig
+
+	// Latest price and 1m kline awaiting the next lite tick
+	lite *liteFeed
+
+	// Optional archive for support session recordings, and the recordings in progress
+	recordingStore RecordingStore
+	recordings     map[string]*sessionRecorder
+
+	// Bytes sent in total and per user, kept across reconnects
+	bandwidth *bandwidthTracker
+
+	// Saturation thresholds, current load level and the clients advised to downgrade
+	load *loadMonitor
+
+	// Instance identity announced to clients and the peers offered in reconnect advisories
+	routing hubRouting
+
+	// Optional stored candles and trades for bar replay
+	replayCandles ReplayCandleSource
+	replayTrades  ReplayTradeSource
+
+	// Symbols whose stream updates are not delivered (see SetSymbolPaused)
+	pausedSymbols atomic.Pointer[map[string]bool]
+
+	// Optional recorder of message delivery lag per type
+	deliveryLag atomic.Pointer[LatencyRecorder]
 }
 
 // Client represents a WebSocket connection
@@ -44,9 +108,75 @@ type Client struct {
 	// Client ID for logging
 	id string
 
+	// Verified user ID of the access token, for alerts and subscription persistence (empty for
+	// anonymous clients)
+	userID string
+
+	// Debounce timer for persisting subscription changes
+	persistTimer *time.Timer
+
 	// Subscribed symbols
 	symbols map[string]bool
 
+	// Symbols for which trade updates carry computed context
+	enrichedSymbols map[string]bool
+
+	// Subscribed channels (e.g. "liquidations:all")
+	channels map[string]bool
+
+	// Minimum notional (price * quantity) for the global liquidation feed
+	liquidationMinNotional float64
+
+	// Symbol/interval pairs ("BTCUSDT:1m") delivered through "layout:sync"
+	layoutPairs map[string]bool
+
+	// Live volume profiles delivered through "vp:delta" (symbol -> bucket size)
+	volumeProfiles map[string]float64
+
+	// Idle time before an application-level keepalive frame is sent (0 disables)
+	keepaliveInterval time.Duration
+
+	// Largest outbound text frame in bytes; larger messages are fragmented (0 = unlimited)
+	maxFrameSize int
+
+	// Time of the last frame written, used for keepalive idle detection
+	lastWrite time.Time
+
+	// Sequence for fragmented message IDs
+	fragmentSeq uint64
+
+	// Subscription and message quotas, and the current one-second inbound message window
+	limits        ClientLimits
+	messageWindow time.Time
+	messageCount  int
+
+	// Watch-only lite connection fixed to one symbol (see HandleLiteWebSocket)
+	lite       bool
+	liteSymbol string
+	remoteIP   string
+
+	// Client opted in to session recording (allow_recording=true) and its active recorder
+	allowRecording bool
+	recorder       atomic.Pointer[sessionRecorder]
+
+	// Bytes sent, sending rates and bandwidth state (see runBandwidthMonitor)
+	bandwidth clientBandwidth
+
+	// Delivery intervals for depth and forming klines in ms (see set_delivery), and when each
+	// stream was last delivered (owned by the write goroutine)
+	depthInterval atomic.Int64
+	klineInterval atomic.Int64
+	lastDelivered map[string]time.Time
+
+	// When delivery lag was last sampled (owned by the write goroutine)
+	lagSampledAt time.Time
+
+	// Bar replay streaming to the client, if any
+	replay atomic.Pointer[replaySession]
+
+	// Set once a full send buffer has handed the client to the unregister loop
+	dropping atomic.Bool
+
 	// Hub reference
 	hub *Hub
 }
@@ -55,6 +185,7 @@ type Client struct {
 type PriceUpdate struct {
 	Type          string  `json:"type"`
 	Symbol        string  `json:"symbol"`
+	Exchange      string  `json:"exchange,omitempty"` // Set for non-Binance symbols
 	Price         float64 `json:"price"`
 	Change        float64 `json:"change"`
 	ChangePercent float64 `json:"changePercent"`
@@ -62,6 +193,26 @@ type PriceUpdate struct {
 	Timestamp     int64   `json:"timestamp"`
 }
 
+// Channel names for subscriptions that span all symbols
+const (
+	ChannelLiquidationsAll = "liquidations:all"
+	ChannelLayoutSync      = "layout:sync"
+	ChannelVolumeProfile   = "vp:delta"
+	ChannelSymbolMeta      = "symbols:meta"
+	ChannelSpreads         = "spreads"
+	ChannelHiddenLiquidity = "hidden_liquidity"
+)
+
+// knownChannels lists channels clients may subscribe to
+var knownChannels = map[string]bool{
+	ChannelLiquidationsAll: true,
+	ChannelLayoutSync:      true,
+	ChannelVolumeProfile:   true,
+	ChannelSymbolMeta:      true,
+	ChannelSpreads:         true,
+	ChannelHiddenLiquidity: true,
+}
+
 // WebSocket upgrader configuration
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
@@ -76,11 +227,22 @@ var upgrader = websocket.Upgrader{
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
 	return &Hub{
-		clients:       make(map[*Client]bool),
-		broadcast:     make(chan []byte),
-		register:      make(chan *Client),
-		unregister:    make(chan *Client),
-		subscriptions: make(map[string]map[*Client]bool),
+		clients:              make(map[*Client]bool),
+		broadcast:            make(chan []byte),
+		register:             make(chan *Client),
+		unregister:           make(chan *Client),
+		subscriptions:        make(map[string]map[*Client]bool),
+		channelSubscriptions: make(map[string]map[*Client]bool),
+		layoutSync:           &layoutSyncBuffer{dirty: make(map[string]LayoutCandle)},
+		volumeProfile:        &volumeProfileBuffer{symbols: make(map[string]*volumeProfileTrades)},
+		transport:            TransportConfig{KeepaliveInterval: defaultKeepaliveInterval},
+		pollSessions:         &pollSessionRegistry{sessions: make(map[string]*pollSession)},
+		liteSubscriptions:    make(map[string]map[*Client]bool),
+		liteConnectionsByIP:  make(map[string]int),
+		lite:                 &liteFeed{prices: make(map[string]PriceUpdate), klines: make(map[string]LayoutCandle)},
+		recordings:           make(map[string]*sessionRecorder),
+		bandwidth:            newBandwidthTracker(),
+		load:                 newLoadMonitor(),
 	}
 }
 
@@ -88,6 +250,24 @@ func NewHub() *Hub {
 func (h *Hub) Run() {
 	log.Println("WebSocket Hub started - Ready for ultra-fast trading connections")
 
+	// Batched candle patches for multi-chart layouts
+	go h.runLayoutSync()
+
+	// Incremental volume profile updates
+	go h.runVolumeProfileDeltas()
+
+	// Expire long-polling sessions that stopped polling
+	go h.runPollSessionReaper()
+
+	// Conflated updates for watch-only lite connections
+	go h.runLiteFeed()
+
+	// Sending rates and bandwidth caps
+	go h.runBandwidthMonitor()
+
+	// Load advisories for clients with high-cost subscriptions
+	go h.runLoadMonitor()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -102,12 +282,24 @@ func (h *Hub) Run() {
 				"type":      "connected",
 				"message":   "WebSocket connection established",
 				"clientId":  client.id,
+				"routing":   h.GetRoutingHint(),
 				"timestamp": time.Now().UnixMilli(),
 			}
 			h.sendToClient(client, response)
 
+			// Clients connecting during an outage learn about it immediately
+			if status := h.GetDegradedMode(); status != nil && status.Degraded {
+				h.sendToClient(client, status)
+			}
+
 		case client := <-h.unregister:
+			client.stopRecording()
+			client.stopReplay()
+
 			h.mutex.Lock()
+			if client.lite {
+				h.removeLiteClientLocked(client)
+			}
 			if _, ok := h.clients[client]; ok {
 				// Remove from all symbol subscriptions
 				for symbol := range client.symbols {
@@ -119,6 +311,16 @@ func (h *Hub) Run() {
 					}
 				}
 
+				// Remove from all channel subscriptions
+				for channel := range client.channels {
+					if clients, exists := h.channelSubscriptions[channel]; exists {
+						delete(clients, client)
+						if len(clients) == 0 {
+							delete(h.channelSubscriptions, channel)
+						}
+					}
+				}
+
 				delete(h.clients, client)
 				close(client.send)
 				log.Printf("Client disconnected: %s (Total: %d)", client.id, len(h.clients))
@@ -142,6 +344,10 @@ func (h *Hub) Run() {
 
 // BroadcastPriceUpdate sends price update to all subscribed clients
 func (h *Hub) BroadcastPriceUpdate(update PriceUpdate) {
+	if h.symbolPaused(update.Symbol) {
+		return
+	}
+
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 
@@ -181,7 +387,7 @@ func (h *Hub) BroadcastDepthUpdate(update map[string]interface{}) {
 
 	// Send to clients subscribed to this symbol
 	symbol, ok := update["symbol"].(string)
-	if !ok {
+	if !ok || h.symbolPaused(symbol) {
 		return
 	}
 
@@ -199,34 +405,53 @@ func (h *Hub) BroadcastDepthUpdate(update map[string]interface{}) {
 	}
 }
 
-// BroadcastTradeUpdate sends individual trade update to all subscribed clients
-func (h *Hub) BroadcastTradeUpdate(update map[string]interface{}) {
+// BroadcastEnrichedTradeUpdate sends a trade update to subscribed clients, attaching the
+// computed context only for clients that enabled trade enrichment for the symbol
+func (h *Hub) BroadcastEnrichedTradeUpdate(update map[string]interface{}, tradeContext TradeContext) {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 
-	// Convert to JSON
-	message, err := json.Marshal(update)
-	if err != nil {
-		log.Printf("Error marshaling trade update: %v", err)
+	symbol, ok := update["symbol"].(string)
+	if !ok || h.symbolPaused(symbol) {
 		return
 	}
 
-	// Send to clients subscribed to this symbol
-	symbol, ok := update["symbol"].(string)
-	if !ok {
+	clients, exists := h.subscriptions[symbol]
+	if !exists {
 		return
 	}
 
-	if clients, exists := h.subscriptions[symbol]; exists {
-		for client := range clients {
-			select {
-			case client.send <- message:
-			default:
-				// Client buffer full, remove client
-				close(client.send)
-				delete(h.clients, client)
-				delete(clients, client)
+	// Marshal both variants once, lazily for the enriched one
+	message, err := json.Marshal(update)
+	if err != nil {
+		log.Printf("Error marshaling trade update: %v", err)
+		return
+	}
+	var enrichedMessage []byte
+
+	for client := range clients {
+		payload := message
+		if client.enrichedSymbols[symbol] {
+			if enrichedMessage == nil {
+				enriched := make(map[string]interface{}, len(update)+1)
+				for key, value := range update {
+					enriched[key] = value
+				}
+				enriched["context"] = tradeContext
+				enrichedMessage, err = json.Marshal(enriched)
+				if err != nil {
+					log.Printf("Error marshaling enriched trade update: %v", err)
+					enrichedMessage = message
+				}
 			}
+			payload = enrichedMessage
+		}
+
+		select {
+		case client.send <- payload:
+		default:
+			// Client buffer full, remove client
+			h.dropSlowClient(client)
 		}
 	}
 }
@@ -245,7 +470,7 @@ func (h *Hub) BroadcastKlineUpdate(update map[string]interface{}) {
 
 	// Send to clients subscribed to this symbol
 	symbol, ok := update["symbol"].(string)
-	if !ok {
+	if !ok || h.symbolPaused(symbol) {
 		return
 	}
 
@@ -263,6 +488,36 @@ func (h *Hub) BroadcastKlineUpdate(update map[string]interface{}) {
 	}
 }
 
+// BroadcastBarClose sends an authoritative bar close event to all clients subscribed to the symbol
+func (h *Hub) BroadcastBarClose(bar models.BarClose) {
+	if h.symbolPaused(bar.Symbol) {
+		return
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients, exists := h.subscriptions[bar.Symbol]
+	if !exists {
+		return
+	}
+
+	message, err := json.Marshal(bar)
+	if err != nil {
+		log.Printf("Error marshaling bar close: %v", err)
+		return
+	}
+
+	for client := range clients {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, remove client
+			h.dropSlowClient(client)
+		}
+	}
+}
+
 // BroadcastMarkPriceUpdate sends Futures mark price update to all subscribed clients
 func (h *Hub) BroadcastMarkPriceUpdate(update map[string]interface{}) {
 	h.mutex.RLock()
@@ -277,7 +532,7 @@ func (h *Hub) BroadcastMarkPriceUpdate(update map[string]interface{}) {
 
 	// Send to clients subscribed to this symbol
 	symbol, ok := update["symbol"].(string)
-	if !ok {
+	if !ok || h.symbolPaused(symbol) {
 		return
 	}
 
@@ -309,7 +564,7 @@ func (h *Hub) BroadcastLiquidationUpdate(update map[string]interface{}) {
 
 	// Send to clients subscribed to this symbol
 	symbol, ok := update["symbol"].(string)
-	if !ok {
+	if !ok || h.symbolPaused(symbol) {
 		return
 	}
 
@@ -327,6 +582,98 @@ func (h *Hub) BroadcastLiquidationUpdate(update map[string]interface{}) {
 	}
 }
 
+// BroadcastGlobalLiquidation sends a liquidation of any symbol to clients subscribed to the
+// "liquidations:all" channel whose minimum notional filter the liquidation meets
+func (h *Hub) BroadcastGlobalLiquidation(update map[string]interface{}, notional float64) {
+	if symbol, _ := update["symbol"].(string); h.symbolPaused(symbol) {
+		return
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients, exists := h.channelSubscriptions[ChannelLiquidationsAll]
+	if !exists {
+		return
+	}
+
+	message, err := json.Marshal(update)
+	if err != nil {
+		log.Printf("Error marshaling global liquidation update: %v", err)
+		return
+	}
+
+	for client := range clients {
+		if notional < client.liquidationMinNotional {
+			continue
+		}
+
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, remove client
+			h.dropSlowClient(client)
+		}
+	}
+}
+
+// SubscribeChannel adds a client to a channel subscription
+func (h *Hub) SubscribeChannel(client *Client, channel string) bool {
+	if !knownChannels[channel] {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if client.channels == nil {
+		client.channels = make(map[string]bool)
+	}
+	client.channels[channel] = true
+
+	if h.channelSubscriptions[channel] == nil {
+		h.channelSubscriptions[channel] = make(map[*Client]bool)
+	}
+	h.channelSubscriptions[channel][client] = true
+
+	log.Printf("Client %s subscribed to channel %s", client.id, channel)
+	return true
+}
+
+// UnsubscribeChannel removes a client from a channel subscription
+func (h *Hub) UnsubscribeChannel(client *Client, channel string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(client.channels, channel)
+	if channel == ChannelLayoutSync {
+		client.layoutPairs = nil
+	}
+	if channel == ChannelVolumeProfile {
+		client.volumeProfiles = nil
+	}
+
+	if clients, exists := h.channelSubscriptions[channel]; exists {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.channelSubscriptions, channel)
+		}
+	}
+
+	log.Printf("Client %s unsubscribed from channel %s", client.id, channel)
+}
+
+// SetLiquidationMinNotional sets the minimum notional filter for a client's global liquidation feed
+func (h *Hub) SetLiquidationMinNotional(client *Client, minNotional float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if minNotional < 0 {
+		minNotional = 0
+	}
+	client.liquidationMinNotional = minNotional
+}
+
 // SubscribeSymbol adds a client to symbol subscription
 func (h *Hub) SubscribeSymbol(client *Client, symbol string) {
 	h.mutex.Lock()
@@ -354,6 +701,7 @@ func (h *Hub) UnsubscribeSymbol(client *Client, symbol string) {
 
 	// Remove from client's symbols
 	delete(client.symbols, symbol)
+	delete(client.enrichedSymbols, symbol)
 
 	// Remove from hub's subscriptions
 	if clients, exists := h.subscriptions[symbol]; exists {
@@ -366,6 +714,21 @@ func (h *Hub) UnsubscribeSymbol(client *Client, symbol string) {
 	log.Printf("Client %s unsubscribed from %s", client.id, symbol)
 }
 
+// SetTradeEnrichment toggles computed trade context for a client's symbol subscription
+func (h *Hub) SetTradeEnrichment(client *Client, symbol string, enabled bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if client.enrichedSymbols == nil {
+		client.enrichedSymbols = make(map[string]bool)
+	}
+	if enabled {
+		client.enrichedSymbols[symbol] = true
+	} else {
+		delete(client.enrichedSymbols, symbol)
+	}
+}
+
 // sendToClient sends a message to a specific client
 func (h *Hub) sendToClient(client *Client, data interface{}) {
 	message, err := json.Marshal(data)
@@ -402,3 +765,25 @@ func (h *Hub) GetSubscriptionStats() map[string]int {
 	}
 	return stats
 }
+
+// GetChannelStats returns channel subscription statistics
+func (h *Hub) GetChannelStats() map[string]int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	stats := make(map[string]int)
+	for channel, clients := range h.channelSubscriptions {
+		stats[channel] = len(clients)
+	}
+	return stats
+}
+
+// dropSlowClient disconnects a client whose send buffer is full. Broadcasters hold the read lock,
+// so the client is handed to the run loop, which removes it from every subscription under the
+// write lock, instead of being closed in place
+func (h *Hub) dropSlowClient(client *Client) {
+	if client.dropping.Swap(true) {
+		return
+	}
+	go func() { h.unregister <- client }()
+}
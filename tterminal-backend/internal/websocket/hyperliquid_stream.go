@@ -0,0 +1,541 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/internal/hyperliquid"
+	"tterminal-backend/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// hyperliquidPingInterval keeps the connection open; Hyperliquid drops clients silent for 60s
+	hyperliquidPingInterval = 30 * time.Second
+)
+
+// hyperliquidChannels are the subscriptions made for every coin (plus a candle per bar close interval)
+var hyperliquidChannels = []string{"trades", "activeAssetCtx", "candle"}
+
+// HyperliquidStream streams Hyperliquid perpetual market data into the hub
+// Updates are broadcast under exchange-qualified symbols ("HYPERLIQUID:BTCUSD") with an
+// "exchange" field. Trades feed the same recorder as the Binance futures stream. Candles are
+// streamed without a closed flag, so a bar is confirmed closed when the next one starts, with
+// the scheduler's REST confirmation as the fallback
+type HyperliquidStream struct {
+	hub   *Hub
+	url   string
+	coins []string // Hyperliquid coin names ("BTC", "kPEPE")
+
+	mu          sync.RWMutex
+	conn        *websocket.Conn
+	isRunning   bool
+	connectedAt time.Time
+	reconnects  int64
+	lastPrices  map[string]float64
+	contexts    map[string]hyperliquidAssetContext // Latest asset context by symbol key
+	candles     map[string]hyperliquidCandle       // Latest candle keyed by "SYMBOL:interval"
+	lastMessage atomic.Int64                       // Unix milliseconds
+
+	// Rolling VWAP/delta/size context for enriched trade updates
+	tradeEnricher *TradeEnricher
+	// Optional persistence of trades (set after start)
+	tradeRecorder atomic.Pointer[tradeRecorder]
+	// Bar close events, confirmed by the next candle or over REST
+	barClose        *BarCloseScheduler
+	barCloseStarted sync.Once
+	// Normalized trades, book updates and liquidations for in-process consumers
+	events *MarketEvents
+}
+
+// hyperliquidMessage is a Hyperliquid stream message
+type hyperliquidMessage struct {
+	Channel string          `json:"channel"` // "trades", "candle", "activeAssetCtx", "pong", "error", ...
+	Data    json.RawMessage `json:"data"`
+}
+
+// hyperliquidTrade is one trades entry
+type hyperliquidTrade struct {
+	Coin  string `json:"coin"`
+	Side  string `json:"side"` // Taker side: "B" (buy) or "A" (sell)
+	Price string `json:"px"`
+	Size  string `json:"sz"` // Base currency
+	Time  int64  `json:"time"`
+	TID   int64  `json:"tid"`
+}
+
+// hyperliquidCandle is a candle update; prices and volumes are strings
+type hyperliquidCandle struct {
+	OpenTime  int64  `json:"t"` // Unix milliseconds
+	CloseTime int64  `json:"T"`
+	Coin      string `json:"s"`
+	Interval  string `json:"i"`
+	Open      string `json:"o"`
+	High      string `json:"h"`
+	Low       string `json:"l"`
+	Close     string `json:"c"`
+	Volume    string `json:"v"` // Base currency
+	Trades    int64  `json:"n"`
+}
+
+// hyperliquidAssetContext is an activeAssetCtx update
+type hyperliquidAssetContext struct {
+	Coin string `json:"coin"`
+	Ctx  struct {
+		Funding      string `json:"funding"` // Hourly rate
+		OpenInterest string `json:"openInterest"`
+		PrevDayPx    string `json:"prevDayPx"`
+		DayBaseVlm   string `json:"dayBaseVlm"`
+		MarkPx       string `json:"markPx"`
+		OraclePx     string `json:"oraclePx"`
+	} `json:"ctx"`
+}
+
+// NewHyperliquidStream creates a Hyperliquid stream for coin names as Hyperliquid spells them
+// (see hyperliquid.Client.GetCoinNames)
+func NewHyperliquidStream(hub *Hub, url string, coins []string) *HyperliquidStream {
+	hs := &HyperliquidStream{
+		hub:           hub,
+		url:           url,
+		coins:         coins,
+		lastPrices:    make(map[string]float64),
+		contexts:      make(map[string]hyperliquidAssetContext),
+		candles:       make(map[string]hyperliquidCandle),
+		tradeEnricher: NewTradeEnricher(),
+	}
+	hs.barClose = newBarCloseScheduler(hub, hs.GetConnectedSymbols)
+	hs.events = newMarketEvents()
+	return hs
+}
+
+// Start connects to the Hyperliquid stream, reconnecting in the background on failure
+func (hs *HyperliquidStream) Start() error {
+	hs.barCloseStarted.Do(func() {
+		go hs.barClose.run(make(chan struct{}))
+	})
+
+	hs.mu.Lock()
+	hs.isRunning = true
+	hs.mu.Unlock()
+
+	if err := hs.connect(); err != nil {
+		log.Printf("Failed to connect to Hyperliquid stream: %v", err)
+		go hs.reconnect()
+		return nil
+	}
+
+	log.Printf("Connected to Hyperliquid WebSocket - Streaming %d perpetuals", len(hs.coins))
+	return nil
+}
+
+// Stop disconnects from the Hyperliquid stream
+func (hs *HyperliquidStream) Stop() {
+	hs.mu.Lock()
+	hs.isRunning = false
+	conn := hs.conn
+	hs.conn = nil
+	hs.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+		log.Println("Hyperliquid WebSocket stream stopped")
+	}
+}
+
+// SetTradeWriter enables persistence of Hyperliquid trades
+func (hs *HyperliquidStream) SetTradeWriter(writer *TradeWriter) {
+	hs.tradeRecorder.Store(newTradeRecorder(writer, hs.hub))
+	log.Printf("Trade persistence enabled for Hyperliquid trades")
+}
+
+// connect dials the stream, subscribes to every channel and starts the read and ping loops
+func (hs *HyperliquidStream) connect() error {
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(hs.url, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, coin := range hs.coins {
+		subscriptions := []map[string]interface{}{
+			{"type": "trades", "coin": coin},
+			{"type": "activeAssetCtx", "coin": coin},
+		}
+		for _, interval := range barCloseIntervals {
+			subscriptions = append(subscriptions, map[string]interface{}{"type": "candle", "coin": coin, "interval": interval})
+		}
+		for _, subscription := range subscriptions {
+			request := map[string]interface{}{"method": "subscribe", "subscription": subscription}
+			if err := conn.WriteJSON(request); err != nil {
+				conn.Close()
+				return err
+			}
+		}
+	}
+
+	hs.mu.Lock()
+	hs.conn = conn
+	hs.connectedAt = time.Now()
+	// Bars in progress while disconnected are left to REST confirmation
+	hs.candles = make(map[string]hyperliquidCandle)
+	hs.mu.Unlock()
+
+	go hs.readMessages(conn)
+	go hs.pingPeriodically(conn)
+	return nil
+}
+
+// pingPeriodically sends application pings so Hyperliquid keeps the connection open
+func (hs *HyperliquidStream) pingPeriodically(conn *websocket.Conn) {
+	ticker := time.NewTicker(hyperliquidPingInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !hs.isCurrent(conn) {
+			return
+		}
+		if err := conn.WriteJSON(map[string]string{"method": "ping"}); err != nil {
+			log.Printf("Failed to send Hyperliquid ping: %v", err)
+			return
+		}
+	}
+}
+
+// readMessages reads and processes messages until the connection fails
+func (hs *HyperliquidStream) readMessages(conn *websocket.Conn) {
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if hs.isCurrent(conn) {
+				log.Printf("Error reading from Hyperliquid WebSocket: %v", err)
+				hs.mu.Lock()
+				hs.conn = nil
+				hs.mu.Unlock()
+				hs.reconnect()
+			}
+			return
+		}
+
+		hs.processMessage(message)
+	}
+}
+
+// isCurrent reports whether conn is the live connection of a running stream
+func (hs *HyperliquidStream) isCurrent(conn *websocket.Conn) bool {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.isRunning && hs.conn == conn
+}
+
+// reconnect retries the connection until it succeeds or the stream is stopped
+func (hs *HyperliquidStream) reconnect() {
+	for {
+		time.Sleep(5 * time.Second)
+
+		hs.mu.Lock()
+		running := hs.isRunning
+		hs.reconnects++
+		hs.mu.Unlock()
+		if !running {
+			return
+		}
+
+		log.Println("Attempting to reconnect to Hyperliquid WebSocket...")
+		if err := hs.connect(); err != nil {
+			log.Printf("Hyperliquid reconnection failed: %v", err)
+			continue
+		}
+		log.Println("Successfully reconnected to Hyperliquid WebSocket")
+		return
+	}
+}
+
+// processMessage routes a stream message by channel
+func (hs *HyperliquidStream) processMessage(message []byte) {
+	hs.lastMessage.Store(time.Now().UnixMilli())
+
+	var msg hyperliquidMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+
+	switch msg.Channel {
+	case "trades":
+		var trades []hyperliquidTrade
+		if err := json.Unmarshal(msg.Data, &trades); err == nil {
+			for _, trade := range trades {
+				hs.processTrade(trade)
+			}
+		}
+	case "candle":
+		var candle hyperliquidCandle
+		if err := json.Unmarshal(msg.Data, &candle); err == nil {
+			hs.processCandle(candle)
+		}
+	case "activeAssetCtx":
+		var assetContext hyperliquidAssetContext
+		if err := json.Unmarshal(msg.Data, &assetContext); err == nil {
+			hs.processAssetContext(assetContext)
+		}
+	case "error":
+		log.Printf("Hyperliquid subscription rejected: %s", string(msg.Data))
+	}
+}
+
+// hyperliquidSymbolKey returns the qualified symbol of a coin ("HYPERLIQUID:BTCUSD")
+func hyperliquidSymbolKey(coin string) string {
+	return models.QualifySymbol(models.ExchangeHyperliquid, hyperliquid.Symbol(coin))
+}
+
+// processAssetContext keeps a coin's 24h statistics and broadcasts its mark price, oracle
+// price and hourly funding rate
+func (hs *HyperliquidStream) processAssetContext(assetContext hyperliquidAssetContext) {
+	key := hyperliquidSymbolKey(assetContext.Coin)
+
+	hs.mu.Lock()
+	hs.contexts[key] = assetContext
+	hs.mu.Unlock()
+
+	markPrice := models.ParseFloat(assetContext.Ctx.MarkPx)
+	if markPrice <= 0 {
+		return
+	}
+	// Funding settles every hour on the hour
+	now := time.Now()
+	hs.hub.BroadcastMarkPriceUpdate(map[string]interface{}{
+		"type":              "mark_price_update",
+		"symbol":            key,
+		"exchange":          models.ExchangeHyperliquid,
+		"mark_price":        markPrice,
+		"index_price":       models.ParseFloat(assetContext.Ctx.OraclePx),
+		"funding_rate":      models.ParseFloat(assetContext.Ctx.Funding),
+		"next_funding_time": now.Truncate(time.Hour).Add(time.Hour).UnixMilli(),
+		"open_interest":     models.ParseFloat(assetContext.Ctx.OpenInterest),
+		"timestamp":         now.UnixMilli(),
+	})
+}
+
+// processTrade broadcasts, records and profiles a trade and broadcasts a price update when the
+// last price changes
+func (hs *HyperliquidStream) processTrade(trade hyperliquidTrade) {
+	price, err := strconv.ParseFloat(trade.Price, 64)
+	if err != nil || price <= 0 {
+		return
+	}
+	quantity, err := strconv.ParseFloat(trade.Size, 64)
+	if err != nil || quantity <= 0 {
+		return
+	}
+
+	key := hyperliquidSymbolKey(trade.Coin)
+	// A taker sell hits the bid, so the buyer is the maker
+	isBuyerMaker := trade.Side == "A"
+
+	tradeUpdate := map[string]interface{}{
+		"type":           "trade_update",
+		"symbol":         key,
+		"exchange":       models.ExchangeHyperliquid,
+		"price":          price,
+		"quantity":       quantity,
+		"is_buyer_maker": isBuyerMaker,
+		"trade_time":     trade.Time,
+		"timestamp":      time.Now().UnixMilli(),
+	}
+
+	record := models.TradeRecord{
+		Symbol:       key,
+		TradeID:      trade.TID,
+		Price:        price,
+		Quantity:     quantity,
+		IsBuyerMaker: isBuyerMaker,
+		TradeTime:    time.UnixMilli(trade.Time),
+	}
+	if recorder := hs.tradeRecorder.Load(); recorder != nil {
+		recorder.record(record)
+	}
+	hs.events.emitTrade(record)
+
+	hs.hub.QueueVolumeProfileTrade(key, price, quantity, isBuyerMaker, trade.Time)
+
+	tradeContext := hs.tradeEnricher.Update(key, price, quantity, isBuyerMaker, trade.Time)
+	hs.hub.BroadcastEnrichedTradeUpdate(tradeUpdate, tradeContext)
+
+	hs.mu.Lock()
+	priceChanged := hs.lastPrices[key] != price
+	hs.lastPrices[key] = price
+	assetContext := hs.contexts[key]
+	hs.mu.Unlock()
+	if !priceChanged {
+		return
+	}
+
+	update := PriceUpdate{
+		Type:      "price_update",
+		Symbol:    key,
+		Exchange:  models.ExchangeHyperliquid,
+		Price:     price,
+		Volume:    models.ParseFloat(assetContext.Ctx.DayBaseVlm),
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if prevDay := models.ParseFloat(assetContext.Ctx.PrevDayPx); prevDay > 0 {
+		update.Change = price - prevDay
+		update.ChangePercent = update.Change / prevDay * 100
+	}
+	hs.hub.BroadcastPriceUpdate(update)
+	hs.hub.QueueLitePrice(update)
+}
+
+// processCandle broadcasts a forming candle and confirms the previous bar once the next starts
+func (hs *HyperliquidStream) processCandle(data hyperliquidCandle) {
+	duration, ok := models.IntervalDuration(data.Interval)
+	if !ok {
+		return
+	}
+	key := hyperliquidSymbolKey(data.Coin)
+	end := data.OpenTime + duration.Milliseconds() - 1
+
+	hs.mu.Lock()
+	previous, exists := hs.candles[key+":"+data.Interval]
+	if exists && previous.OpenTime > data.OpenTime {
+		// A late update of a bar that has already rolled over
+		hs.mu.Unlock()
+		return
+	}
+	hs.candles[key+":"+data.Interval] = data
+	hs.mu.Unlock()
+
+	open := models.ParseFloat(data.Open)
+	high := models.ParseFloat(data.High)
+	low := models.ParseFloat(data.Low)
+	close := models.ParseFloat(data.Close)
+	volume := models.ParseFloat(data.Volume)
+
+	hs.hub.BroadcastKlineUpdate(map[string]interface{}{
+		"type":       "kline_update",
+		"symbol":     key,
+		"exchange":   models.ExchangeHyperliquid,
+		"interval":   data.Interval,
+		"open":       open,
+		"high":       high,
+		"low":        low,
+		"close":      close,
+		"volume":     volume,
+		"is_closed":  false,
+		"start_time": data.OpenTime,
+		"end_time":   end,
+		"timestamp":  time.Now().UnixMilli(),
+	})
+
+	candle := LayoutCandle{
+		Symbol:    key,
+		Interval:  data.Interval,
+		StartTime: data.OpenTime,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+	}
+	hs.hub.QueueLayoutCandle(candle)
+	if data.Interval == "1m" {
+		hs.hub.QueueLiteKline(candle)
+	}
+
+	if exists && previous.OpenTime < data.OpenTime {
+		hs.confirmCandle(key, previous, duration)
+	}
+}
+
+// confirmCandle emits the last update of a bar as its close
+// Hyperliquid candles carry no quote volume or taker buy volume
+func (hs *HyperliquidStream) confirmCandle(key string, data hyperliquidCandle, duration time.Duration) {
+	end := data.OpenTime + duration.Milliseconds() - 1
+
+	hs.barClose.confirmStream(models.KlineEvent{
+		Symbol:         key,
+		Interval:       data.Interval,
+		OpenTime:       time.UnixMilli(data.OpenTime),
+		CloseTime:      time.UnixMilli(end),
+		Open:           data.Open,
+		High:           data.High,
+		Low:            data.Low,
+		Close:          data.Close,
+		Volume:         data.Volume,
+		QuoteVolume:    "0",
+		BuyVolume:      "0",
+		BuyQuoteVolume: "0",
+		TradeCount:     data.Trades,
+		Closed:         true,
+	})
+}
+
+// BarCloses returns the bar close scheduler for registering handlers and configuring its clock
+func (hs *HyperliquidStream) BarCloses() *BarCloseScheduler {
+	return hs.barClose
+}
+
+// Events returns the stream's normalized market events for registering handlers
+func (hs *HyperliquidStream) Events() *MarketEvents {
+	return hs.events
+}
+
+// GetConnectedSymbols returns the qualified symbols being streamed
+func (hs *HyperliquidStream) GetConnectedSymbols() []string {
+	symbols := make([]string, len(hs.coins))
+	for i, coin := range hs.coins {
+		symbols[i] = hyperliquidSymbolKey(coin)
+	}
+	return symbols
+}
+
+// GetLastPrice returns the last traded price for a qualified symbol
+func (hs *HyperliquidStream) GetLastPrice(symbol string) (float64, bool) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	price, exists := hs.lastPrices[symbol]
+	return price, exists
+}
+
+// GetRecentLiquidations returns no liquidations: Hyperliquid's public feeds do not mark
+// liquidation fills
+func (hs *HyperliquidStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	return nil
+}
+
+// GetStreamStats returns statistics about the Hyperliquid stream
+func (hs *HyperliquidStream) GetStreamStats() map[string]interface{} {
+	hs.mu.RLock()
+	stats := map[string]interface{}{
+		"exchange":          models.ExchangeHyperliquid,
+		"connected_symbols": len(hs.coins),
+		"symbols":           hs.GetConnectedSymbols(),
+		"coins":             hs.coins,
+		"price_data_count":  len(hs.lastPrices),
+		"asset_contexts":    len(hs.contexts),
+		"is_running":        hs.isRunning,
+		"connected":         hs.conn != nil,
+		"reconnects":        hs.reconnects,
+		"stream_types":      hyperliquidChannels,
+	}
+	if !hs.connectedAt.IsZero() {
+		stats["connected_at"] = hs.connectedAt.UnixMilli()
+	}
+	hs.mu.RUnlock()
+
+	if last := hs.lastMessage.Load(); last > 0 {
+		stats["last_message_at"] = last
+	}
+	stats["bar_close"] = hs.barClose.stats()
+	if recorder := hs.tradeRecorder.Load(); recorder != nil {
+		stats["trade_persistence"] = recorder.stats()
+	}
+	return stats
+}
@@ -0,0 +1,313 @@
+package websocket
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// pipelineQueueSize bounds the events waiting in one symbol's pipeline
+const pipelineQueueSize = 1024
+
+// Pipeline policies decide what happens to an event when its symbol's queue is full
+const (
+	// PipelinePolicyBlock waits for room: book diffs, trades that are persisted, klines and
+	// liquidations are never lost
+	PipelinePolicyBlock = "block"
+	// PipelinePolicyConflate keeps only the newest event per stream: tickers and mark prices are
+	// snapshots, so a newer one replaces any still waiting
+	PipelinePolicyConflate = "conflate"
+	// PipelinePolicyDrop discards the event: spot trades are broadcast only and not persisted
+	PipelinePolicyDrop = "drop"
+)
+
+// Pipeline stages with latency metrics
+const (
+	pipelineStageParse     = "parse"     // Decoding in the read loop, until the event is handed to its pipeline
+	pipelineStageQueue     = "queue"     // Waiting in the symbol's pipeline
+	pipelineStageEnrich    = "enrich"    // Numeric parsing and derived state: enrichers, local books, predictors
+	pipelineStagePersist   = "persist"   // Hand-off to the trade and depth recorders
+	pipelineStageBroadcast = "broadcast" // Fan-out to clients and event handlers
+)
+
+// stageTimer splits an event's handling into stages: the handler marks the end of each stage it
+// runs, and the time since the previous mark is recorded against that stage
+type stageTimer struct {
+	last  time.Time
+	spans []stageSpan
+}
+
+// stageSpan is the time one event spent in one stage
+type stageSpan struct {
+	stage   string
+	latency time.Duration
+}
+
+// mark ends a stage
+func (t *stageTimer) mark(stage string) {
+	now := time.Now()
+	t.spans = append(t.spans, stageSpan{stage: stage, latency: now.Sub(t.last)})
+	t.last = now
+}
+
+// pipelineEvent is one parsed stream event waiting to be processed
+type pipelineEvent struct {
+	key      string // Conflation key within the symbol (stream and event type)
+	received time.Time
+	parsed   time.Time
+	handle   func(stages *stageTimer)
+}
+
+// PipelineStageStats are the latencies of one pipeline stage
+type PipelineStageStats struct {
+	Count int64   `json:"count"`
+	AvgMs float64 `json:"avg_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// PipelineStats describes the ingestion pipeline of one symbol
+type PipelineStats struct {
+	Symbol        string                        `json:"symbol"`
+	QueueLength   int                           `json:"queue_length"`
+	QueueCapacity int                           `json:"queue_capacity"`
+	Processed     int64                         `json:"processed"`
+	Dropped       int64                         `json:"dropped"`   // Drop-policy events discarded on a full queue
+	Conflated     int64                         `json:"conflated"` // Conflate-policy events replaced by a newer one
+	Blocked       int64                         `json:"blocked"`   // Block-policy events that waited for room
+	Stages        map[string]PipelineStageStats `json:"stages"`
+}
+
+// stageLatency accumulates the latencies of one stage
+type stageLatency struct {
+	count int64
+	total time.Duration
+	max   time.Duration
+}
+
+// symbolPipeline processes one symbol's events in order on its own goroutine, so a slow
+// broadcast or store write only holds back that symbol
+type symbolPipeline struct {
+	symbol string
+	queue  chan pipelineEvent
+	wake   chan struct{} // Signals conflated events waiting in latest
+
+	mu     sync.Mutex
+	latest map[string]pipelineEvent // Newest conflated event per key, not yet processed
+	stages map[string]*stageLatency
+
+	processed int64
+	dropped   int64
+	conflated int64
+	blocked   int64
+}
+
+// IngestPipelines fans a stream's parsed events out to per-symbol pipelines
+type IngestPipelines struct {
+	mu        sync.RWMutex
+	pipelines map[string]*symbolPipeline
+}
+
+// newIngestPipelines creates an empty set of pipelines; a symbol's pipeline starts with its first event
+func newIngestPipelines() *IngestPipelines {
+	return &IngestPipelines{
+		pipelines: make(map[string]*symbolPipeline),
+	}
+}
+
+// submit hands a parsed event to its symbol's pipeline under the given policy
+// received is when the raw message was read, before parsing
+func (p *IngestPipelines) submit(symbol, key, policy string, received time.Time, handle func(stages *stageTimer)) {
+	pipeline := p.pipeline(symbol)
+	event := pipelineEvent{
+		key:      key,
+		received: received,
+		parsed:   time.Now(),
+		handle:   handle,
+	}
+	pipeline.observe(pipelineStageParse, event.parsed.Sub(received))
+
+	switch policy {
+	case PipelinePolicyConflate:
+		pipeline.mu.Lock()
+		if _, waiting := pipeline.latest[key]; waiting {
+			pipeline.conflated++
+		}
+		pipeline.latest[key] = event
+		pipeline.mu.Unlock()
+
+		select {
+		case pipeline.wake <- struct{}{}:
+		default:
+			// Already signalled; the worker drains every waiting key
+		}
+
+	case PipelinePolicyDrop:
+		select {
+		case pipeline.queue <- event:
+		default:
+			pipeline.mu.Lock()
+			pipeline.dropped++
+			dropped := pipeline.dropped
+			pipeline.mu.Unlock()
+			if dropped%1000 == 1 {
+				log.Printf("Ingest pipeline for %s full - %d events dropped so far", symbol, dropped)
+			}
+		}
+
+	default:
+		select {
+		case pipeline.queue <- event:
+		default:
+			pipeline.mu.Lock()
+			pipeline.blocked++
+			pipeline.mu.Unlock()
+			pipeline.queue <- event
+		}
+	}
+}
+
+// pipeline returns a symbol's pipeline, starting it on first use
+func (p *IngestPipelines) pipeline(symbol string) *symbolPipeline {
+	p.mu.RLock()
+	pipeline, exists := p.pipelines[symbol]
+	p.mu.RUnlock()
+	if exists {
+		return pipeline
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pipeline, exists = p.pipelines[symbol]; exists {
+		return pipeline
+	}
+	pipeline = &symbolPipeline{
+		symbol: symbol,
+		queue:  make(chan pipelineEvent, pipelineQueueSize),
+		wake:   make(chan struct{}, 1),
+		latest: make(map[string]pipelineEvent),
+		stages: make(map[string]*stageLatency),
+	}
+	p.pipelines[symbol] = pipeline
+	go pipeline.run()
+	return pipeline
+}
+
+// stats returns the counters and stage latencies of every pipeline, by symbol
+func (p *IngestPipelines) stats() []PipelineStats {
+	p.mu.RLock()
+	pipelines := make([]*symbolPipeline, 0, len(p.pipelines))
+	for _, pipeline := range p.pipelines {
+		pipelines = append(pipelines, pipeline)
+	}
+	p.mu.RUnlock()
+
+	stats := make([]PipelineStats, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		stats = append(stats, pipeline.stats())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Symbol < stats[j].Symbol
+	})
+	return stats
+}
+
+// run processes queued events in order and waiting conflated events as they are signalled
+// Pipelines live as long as the stream, across reconnects
+func (sp *symbolPipeline) run() {
+	for {
+		select {
+		case event := <-sp.queue:
+			sp.process(event)
+		case <-sp.wake:
+			sp.drainConflated()
+		}
+	}
+}
+
+// drainConflated processes the newest waiting event of every conflated key
+func (sp *symbolPipeline) drainConflated() {
+	sp.mu.Lock()
+	if len(sp.latest) == 0 {
+		sp.mu.Unlock()
+		return
+	}
+	events := make([]pipelineEvent, 0, len(sp.latest))
+	for key, event := range sp.latest {
+		events = append(events, event)
+		delete(sp.latest, key)
+	}
+	sp.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].parsed.Before(events[j].parsed)
+	})
+	for _, event := range events {
+		sp.process(event)
+	}
+}
+
+// process runs an event's handler, recording its queue latency and the stages it marked
+func (sp *symbolPipeline) process(event pipelineEvent) {
+	stages := stageTimer{last: time.Now()}
+	queued := stages.last.Sub(event.parsed)
+	event.handle(&stages)
+
+	sp.mu.Lock()
+	sp.processed++
+	sp.observeLocked(pipelineStageQueue, queued)
+	for _, span := range stages.spans {
+		sp.observeLocked(span.stage, span.latency)
+	}
+	sp.mu.Unlock()
+}
+
+// observe records one latency of a stage
+func (sp *symbolPipeline) observe(stage string, latency time.Duration) {
+	sp.mu.Lock()
+	sp.observeLocked(stage, latency)
+	sp.mu.Unlock()
+}
+
+// observeLocked records one latency of a stage; the caller holds sp.mu
+func (sp *symbolPipeline) observeLocked(stage string, latency time.Duration) {
+	stats, exists := sp.stages[stage]
+	if !exists {
+		stats = &stageLatency{}
+		sp.stages[stage] = stats
+	}
+	stats.count++
+	stats.total += latency
+	if latency > stats.max {
+		stats.max = latency
+	}
+}
+
+// stats returns the pipeline's counters and stage latencies
+func (sp *symbolPipeline) stats() PipelineStats {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	stages := make(map[string]PipelineStageStats, len(sp.stages))
+	for stage, latency := range sp.stages {
+		stats := PipelineStageStats{
+			Count: latency.count,
+			MaxMs: float64(latency.max) / float64(time.Millisecond),
+		}
+		if latency.count > 0 {
+			stats.AvgMs = float64(latency.total) / float64(latency.count) / float64(time.Millisecond)
+		}
+		stages[stage] = stats
+	}
+
+	return PipelineStats{
+		Symbol:        sp.symbol,
+		QueueLength:   len(sp.queue),
+		QueueCapacity: cap(sp.queue),
+		Processed:     sp.processed,
+		Dropped:       sp.dropped,
+		Conflated:     sp.conflated,
+		Blocked:       sp.blocked,
+		Stages:        stages,
+	}
+}
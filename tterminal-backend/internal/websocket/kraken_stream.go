@@ -0,0 +1,481 @@
+package websocket
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/internal/kraken"
+	"tterminal-backend/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// krakenPingInterval keeps the connection open; Kraken drops clients silent for 60s
+	krakenPingInterval = 30 * time.Second
+)
+
+// krakenFeeds are the Kraken Futures feeds subscribed for every product
+var krakenFeeds = []string{"ticker", "trade"}
+
+// KrakenStream streams Kraken Futures linear perpetual market data into the hub
+// Updates are broadcast under exchange-qualified symbols ("KRAKEN:BTCUSD") with an "exchange"
+// field. Trades feed the same recorder as the Binance futures stream. Kraken streams no
+// candles, so forming 1m/5m/15m klines are built from trades and bar closes are confirmed
+// over REST by the scheduler
+type KrakenStream struct {
+	hub      *Hub
+	url      string
+	products []string // Kraken product IDs ("PF_XBTUSD")
+
+	mu          sync.RWMutex
+	conn        *websocket.Conn
+	isRunning   bool
+	connectedAt time.Time
+	reconnects  int64
+	lastPrices  map[string]float64
+	klines      map[string]*krakenKline // Forming klines keyed by "SYMBOL:interval"
+	lastMessage atomic.Int64            // Unix milliseconds
+
+	// Rolling VWAP/delta/size context for enriched trade updates
+	tradeEnricher *TradeEnricher
+	// Optional persistence of trades (set after start)
+	tradeRecorder atomic.Pointer[tradeRecorder]
+	// Bar close events, confirmed over REST
+	barClose        *BarCloseScheduler
+	barCloseStarted sync.Once
+	// Normalized trades, book updates and liquidations for in-process consumers
+	events *MarketEvents
+}
+
+// krakenMessage is a Kraken Futures feed message or subscription event
+// Ticker and trade fields share one struct; each feed fills its own
+type krakenMessage struct {
+	Event     string `json:"event"` // "subscribed", "error", ... (empty for feed data)
+	Message   string `json:"message"`
+	Feed      string `json:"feed"`
+	ProductID string `json:"product_id"`
+	Time      int64  `json:"time"` // Unix milliseconds
+
+	// ticker
+	Last                float64 `json:"last"`
+	Change              float64 `json:"change"` // 24h change percent
+	Vol24h              float64 `json:"vol24h"`
+	MarkPrice           float64 `json:"markPrice"`
+	Index               float64 `json:"index"`
+	RelativeFundingRate float64 `json:"relative_funding_rate"`
+	NextFundingRateTime int64   `json:"next_funding_rate_time"`
+
+	// trade
+	UID   string  `json:"uid"`
+	Side  string  `json:"side"` // Taker side: "buy" or "sell"
+	Type  string  `json:"type"` // "fill", "liquidation", "termination" or "block"
+	Qty   float64 `json:"qty"`  // Base currency
+	Price float64 `json:"price"`
+}
+
+// krakenKline is a kline being built from streamed trades
+type krakenKline struct {
+	start                          int64
+	open, high, low, close, volume float64
+}
+
+// NewKrakenStream creates a Kraken stream for bare symbols (e.g. "BTCUSD")
+func NewKrakenStream(hub *Hub, url string, symbols []string) *KrakenStream {
+	products := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		products[i] = kraken.ProductID(symbol)
+	}
+
+	ks := &KrakenStream{
+		hub:           hub,
+		url:           url,
+		products:      products,
+		lastPrices:    make(map[string]float64),
+		klines:        make(map[string]*krakenKline),
+		tradeEnricher: NewTradeEnricher(),
+	}
+	ks.barClose = newBarCloseScheduler(hub, ks.GetConnectedSymbols)
+	ks.events = newMarketEvents()
+	return ks
+}
+
+// Start connects to the Kraken stream, reconnecting in the background on failure
+func (ks *KrakenStream) Start() error {
+	ks.barCloseStarted.Do(func() {
+		go ks.barClose.run(make(chan struct{}))
+	})
+
+	ks.mu.Lock()
+	ks.isRunning = true
+	ks.mu.Unlock()
+
+	if err := ks.connect(); err != nil {
+		log.Printf("Failed to connect to Kraken stream: %v", err)
+		go ks.reconnect()
+		return nil
+	}
+
+	log.Printf("Connected to Kraken WebSocket - Streaming %d perpetuals", len(ks.products))
+	return nil
+}
+
+// Stop disconnects from the Kraken stream
+func (ks *KrakenStream) Stop() {
+	ks.mu.Lock()
+	ks.isRunning = false
+	conn := ks.conn
+	ks.conn = nil
+	ks.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+		log.Println("Kraken WebSocket stream stopped")
+	}
+}
+
+// SetTradeWriter enables persistence of Kraken trades
+func (ks *KrakenStream) SetTradeWriter(writer *TradeWriter) {
+	ks.tradeRecorder.Store(newTradeRecorder(writer, ks.hub))
+	log.Printf("Trade persistence enabled for Kraken trades")
+}
+
+// connect dials the stream, subscribes to every feed and starts the read and ping loops
+func (ks *KrakenStream) connect() error {
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(ks.url, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, feed := range krakenFeeds {
+		request := map[string]interface{}{"event": "subscribe", "feed": feed, "product_ids": ks.products}
+		if err := conn.WriteJSON(request); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	ks.mu.Lock()
+	ks.conn = conn
+	ks.connectedAt = time.Now()
+	// Klines restart from the first trade after reconnecting
+	ks.klines = make(map[string]*krakenKline)
+	ks.mu.Unlock()
+
+	go ks.readMessages(conn)
+	go ks.pingPeriodically(conn)
+	return nil
+}
+
+// pingPeriodically sends control-frame pings so Kraken keeps the connection open
+func (ks *KrakenStream) pingPeriodically(conn *websocket.Conn) {
+	ticker := time.NewTicker(krakenPingInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !ks.isCurrent(conn) {
+			return
+		}
+		if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+			log.Printf("Failed to send Kraken ping: %v", err)
+			return
+		}
+	}
+}
+
+// readMessages reads and processes messages until the connection fails
+func (ks *KrakenStream) readMessages(conn *websocket.Conn) {
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ks.isCurrent(conn) {
+				log.Printf("Error reading from Kraken WebSocket: %v", err)
+				ks.mu.Lock()
+				ks.conn = nil
+				ks.mu.Unlock()
+				ks.reconnect()
+			}
+			return
+		}
+
+		ks.processMessage(message)
+	}
+}
+
+// isCurrent reports whether conn is the live connection of a running stream
+func (ks *KrakenStream) isCurrent(conn *websocket.Conn) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.isRunning && ks.conn == conn
+}
+
+// reconnect retries the connection until it succeeds or the stream is stopped
+func (ks *KrakenStream) reconnect() {
+	for {
+		time.Sleep(5 * time.Second)
+
+		ks.mu.Lock()
+		running := ks.isRunning
+		ks.reconnects++
+		ks.mu.Unlock()
+		if !running {
+			return
+		}
+
+		log.Println("Attempting to reconnect to Kraken WebSocket...")
+		if err := ks.connect(); err != nil {
+			log.Printf("Kraken reconnection failed: %v", err)
+			continue
+		}
+		log.Println("Successfully reconnected to Kraken WebSocket")
+		return
+	}
+}
+
+// processMessage routes a stream message by feed
+// Trade snapshots sent after subscribing repeat trades already recorded and are skipped
+func (ks *KrakenStream) processMessage(message []byte) {
+	ks.lastMessage.Store(time.Now().UnixMilli())
+
+	var msg krakenMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	if msg.Event == "error" || msg.Event == "alert" {
+		log.Printf("Kraken subscription rejected: %s", msg.Message)
+		return
+	}
+
+	switch msg.Feed {
+	case "ticker":
+		ks.processTicker(msg)
+	case "trade":
+		ks.processTrade(msg)
+	}
+}
+
+// krakenSymbolKey returns the qualified symbol of a product ("KRAKEN:BTCUSD")
+func krakenSymbolKey(productID string) string {
+	return models.QualifySymbol(models.ExchangeKraken, kraken.Symbol(productID))
+}
+
+// processTicker broadcasts a price update when the last price changes, and the mark price
+// with the hourly funding rate
+func (ks *KrakenStream) processTicker(msg krakenMessage) {
+	key := krakenSymbolKey(msg.ProductID)
+
+	if msg.MarkPrice > 0 {
+		ks.hub.BroadcastMarkPriceUpdate(map[string]interface{}{
+			"type":              "mark_price_update",
+			"symbol":            key,
+			"exchange":          models.ExchangeKraken,
+			"mark_price":        msg.MarkPrice,
+			"index_price":       msg.Index,
+			"funding_rate":      msg.RelativeFundingRate,
+			"next_funding_time": msg.NextFundingRateTime,
+			"timestamp":         time.Now().UnixMilli(),
+		})
+	}
+
+	if msg.Last <= 0 {
+		return
+	}
+	ks.mu.Lock()
+	priceChanged := ks.lastPrices[key] != msg.Last
+	ks.lastPrices[key] = msg.Last
+	ks.mu.Unlock()
+	if !priceChanged {
+		return
+	}
+
+	update := PriceUpdate{
+		Type:          "price_update",
+		Symbol:        key,
+		Exchange:      models.ExchangeKraken,
+		Price:         msg.Last,
+		Change:        msg.Last - msg.Last/(1+msg.Change/100),
+		ChangePercent: msg.Change,
+		Volume:        msg.Vol24h,
+		Timestamp:     time.Now().UnixMilli(),
+	}
+	ks.hub.BroadcastPriceUpdate(update)
+	ks.hub.QueueLitePrice(update)
+}
+
+// processTrade broadcasts, records and profiles a trade and adds it to the forming klines
+func (ks *KrakenStream) processTrade(msg krakenMessage) {
+	if msg.Price <= 0 || msg.Qty <= 0 {
+		return
+	}
+
+	key := krakenSymbolKey(msg.ProductID)
+	// A taker sell hits the bid, so the buyer is the maker
+	isBuyerMaker := msg.Side == "sell"
+
+	tradeUpdate := map[string]interface{}{
+		"type":           "trade_update",
+		"symbol":         key,
+		"exchange":       models.ExchangeKraken,
+		"price":          msg.Price,
+		"quantity":       msg.Qty,
+		"is_buyer_maker": isBuyerMaker,
+		"trade_time":     msg.Time,
+		"timestamp":      time.Now().UnixMilli(),
+	}
+
+	// Stored trades need a numeric ID
+	hash := fnv.New64a()
+	hash.Write([]byte(msg.UID))
+	record := models.TradeRecord{
+		Symbol:       key,
+		TradeID:      int64(hash.Sum64() >> 1),
+		Price:        msg.Price,
+		Quantity:     msg.Qty,
+		IsBuyerMaker: isBuyerMaker,
+		TradeTime:    time.UnixMilli(msg.Time),
+	}
+	if recorder := ks.tradeRecorder.Load(); recorder != nil {
+		recorder.record(record)
+	}
+	ks.events.emitTrade(record)
+
+	ks.hub.QueueVolumeProfileTrade(key, msg.Price, msg.Qty, isBuyerMaker, msg.Time)
+
+	tradeContext := ks.tradeEnricher.Update(key, msg.Price, msg.Qty, isBuyerMaker, msg.Time)
+	ks.hub.BroadcastEnrichedTradeUpdate(tradeUpdate, tradeContext)
+
+	for _, interval := range barCloseIntervals {
+		ks.updateKline(key, interval, msg.Price, msg.Qty, msg.Time)
+	}
+}
+
+// updateKline adds a trade to the forming kline of an interval and broadcasts it
+// Closed bars are left to the scheduler's REST confirmation, which also covers trades
+// missed while disconnected
+func (ks *KrakenStream) updateKline(key, interval string, price, quantity float64, tradeTime int64) {
+	duration, _ := models.IntervalDuration(interval)
+	start := tradeTime - tradeTime%duration.Milliseconds()
+
+	ks.mu.Lock()
+	kline, exists := ks.klines[key+":"+interval]
+	if !exists || kline.start < start {
+		kline = &krakenKline{start: start, open: price, high: price, low: price}
+		ks.klines[key+":"+interval] = kline
+	} else if kline.start > start {
+		// A late trade of a bar that has already rolled over
+		ks.mu.Unlock()
+		return
+	}
+	if price > kline.high {
+		kline.high = price
+	}
+	if price < kline.low {
+		kline.low = price
+	}
+	kline.close = price
+	kline.volume += quantity
+	formed := *kline
+	ks.mu.Unlock()
+
+	ks.hub.BroadcastKlineUpdate(map[string]interface{}{
+		"type":       "kline_update",
+		"symbol":     key,
+		"exchange":   models.ExchangeKraken,
+		"interval":   interval,
+		"open":       formed.open,
+		"high":       formed.high,
+		"low":        formed.low,
+		"close":      formed.close,
+		"volume":     formed.volume,
+		"is_closed":  false,
+		"start_time": formed.start,
+		"end_time":   formed.start + duration.Milliseconds() - 1,
+		"timestamp":  time.Now().UnixMilli(),
+	})
+
+	candle := LayoutCandle{
+		Symbol:    key,
+		Interval:  interval,
+		StartTime: formed.start,
+		Open:      formed.open,
+		High:      formed.high,
+		Low:       formed.low,
+		Close:     formed.close,
+		Volume:    formed.volume,
+	}
+	ks.hub.QueueLayoutCandle(candle)
+	if interval == "1m" {
+		ks.hub.QueueLiteKline(candle)
+	}
+}
+
+// BarCloses returns the bar close scheduler for registering handlers and configuring its clock
+func (ks *KrakenStream) BarCloses() *BarCloseScheduler {
+	return ks.barClose
+}
+
+// Events returns the stream's normalized market events for registering handlers
+func (ks *KrakenStream) Events() *MarketEvents {
+	return ks.events
+}
+
+// GetConnectedSymbols returns the qualified symbols being streamed
+func (ks *KrakenStream) GetConnectedSymbols() []string {
+	symbols := make([]string, len(ks.products))
+	for i, product := range ks.products {
+		symbols[i] = krakenSymbolKey(product)
+	}
+	return symbols
+}
+
+// GetLastPrice returns the last known price for a qualified symbol
+func (ks *KrakenStream) GetLastPrice(symbol string) (float64, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	price, exists := ks.lastPrices[symbol]
+	return price, exists
+}
+
+// GetRecentLiquidations returns no liquidations: Kraken's public feeds do not stream them
+// separately from trades
+func (ks *KrakenStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	return nil
+}
+
+// GetStreamStats returns statistics about the Kraken stream
+func (ks *KrakenStream) GetStreamStats() map[string]interface{} {
+	ks.mu.RLock()
+	stats := map[string]interface{}{
+		"exchange":          models.ExchangeKraken,
+		"connected_symbols": len(ks.products),
+		"symbols":           ks.GetConnectedSymbols(),
+		"products":          ks.products,
+		"price_data_count":  len(ks.lastPrices),
+		"forming_klines":    len(ks.klines),
+		"is_running":        ks.isRunning,
+		"connected":         ks.conn != nil,
+		"reconnects":        ks.reconnects,
+		"stream_types":      krakenFeeds,
+	}
+	if !ks.connectedAt.IsZero() {
+		stats["connected_at"] = ks.connectedAt.UnixMilli()
+	}
+	ks.mu.RUnlock()
+
+	if last := ks.lastMessage.Load(); last > 0 {
+		stats["last_message_at"] = last
+	}
+	stats["bar_close"] = ks.barClose.stats()
+	if recorder := ks.tradeRecorder.Load(); recorder != nil {
+		stats["trade_persistence"] = recorder.stats()
+	}
+	return stats
+}
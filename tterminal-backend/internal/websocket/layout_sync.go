@@ -0,0 +1,174 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+const (
+	// layoutSyncInterval is how often batched candle patches are flushed to layout clients
+	layoutSyncInterval = 250 * time.Millisecond
+	// maxLayoutPairs caps the symbol/interval pairs per client
+	maxLayoutPairs = 20
+)
+
+// LayoutPair identifies one chart (symbol + interval) in a multi-chart layout
+type LayoutPair struct {
+	Symbol   string `json:"symbol"`
+	Interval string `json:"interval"`
+}
+
+// key returns the map key for a layout pair
+func (p LayoutPair) key() string {
+	return strings.ToUpper(p.Symbol) + ":" + p.Interval
+}
+
+// LayoutPairRejection is a requested layout pair that is not delivered, with the reason
+type LayoutPairRejection struct {
+	Symbol   string `json:"symbol"`
+	Interval string `json:"interval"`
+	Reason   string `json:"reason"`
+}
+
+// LayoutCandle is a compact forming-candle patch sent in layout sync frames
+type LayoutCandle struct {
+	Symbol    string  `json:"s"`
+	Interval  string  `json:"i"`
+	StartTime int64   `json:"t"`
+	Open      float64 `json:"o"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	Close     float64 `json:"c"`
+	Volume    float64 `json:"v"`
+	IsClosed  bool    `json:"x"`
+}
+
+// layoutSyncBuffer collects candles changed since the last flush (latest version wins)
+type layoutSyncBuffer struct {
+	mu    sync.Mutex
+	dirty map[string]LayoutCandle
+}
+
+// QueueLayoutCandle records a changed candle for the next layout sync frame
+func (h *Hub) QueueLayoutCandle(candle LayoutCandle) {
+	if h.symbolPaused(candle.Symbol) {
+		return
+	}
+
+	h.layoutSync.mu.Lock()
+	h.layoutSync.dirty[LayoutPair{Symbol: candle.Symbol, Interval: candle.Interval}.key()] = candle
+	h.layoutSync.mu.Unlock()
+}
+
+// SetLayoutPairs replaces the symbol/interval pairs a client receives in layout sync frames and
+// returns the accepted pairs and the rejected ones. Only the streamed kline intervals are
+// accepted; more than maxLayoutPairs pairs are refused with a limit error, keeping the current list
+func (h *Hub) SetLayoutPairs(client *Client, pairs []LayoutPair) ([]LayoutPair, []LayoutPairRejection, *LimitError) {
+	if len(pairs) > maxLayoutPairs {
+		return nil, nil, &LimitError{
+			Type:      "error",
+			Code:      "limit_exceeded",
+			Limit:     LimitMaxLayoutPairs,
+			Max:       maxLayoutPairs,
+			Channel:   ChannelLayoutSync,
+			Message:   fmt.Sprintf("Cannot subscribe to %d layout pairs: limit of %d pairs per connection", len(pairs), maxLayoutPairs),
+			Timestamp: time.Now().UnixMilli(),
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	client.layoutPairs = make(map[string]bool, len(pairs))
+	accepted := make([]LayoutPair, 0, len(pairs))
+	rejected := make([]LayoutPairRejection, 0)
+	for _, pair := range pairs {
+		pair.Symbol = strings.ToUpper(pair.Symbol)
+		reason := ""
+		switch _, valid := models.IntervalDuration(pair.Interval); {
+		case pair.Symbol == "":
+			reason = "symbol is required"
+		case !valid:
+			reason = fmt.Sprintf("invalid interval %q", pair.Interval)
+		case !slices.Contains(barCloseIntervals, pair.Interval):
+			reason = fmt.Sprintf("interval %s is not streamed, use one of %s", pair.Interval, strings.Join(barCloseIntervals, ", "))
+		}
+		if reason != "" {
+			rejected = append(rejected, LayoutPairRejection{Symbol: pair.Symbol, Interval: pair.Interval, Reason: reason})
+			continue
+		}
+		if !client.layoutPairs[pair.key()] {
+			client.layoutPairs[pair.key()] = true
+			accepted = append(accepted, pair)
+		}
+	}
+
+	return accepted, rejected, nil
+}
+
+// runLayoutSync flushes batched candle patches to layout clients once per tick
+func (h *Hub) runLayoutSync() {
+	ticker := time.NewTicker(layoutSyncInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.flushLayoutSync()
+	}
+}
+
+// flushLayoutSync sends each layout client one frame with its changed candles
+func (h *Hub) flushLayoutSync() {
+	h.layoutSync.mu.Lock()
+	if len(h.layoutSync.dirty) == 0 {
+		h.layoutSync.mu.Unlock()
+		return
+	}
+	dirty := h.layoutSync.dirty
+	h.layoutSync.dirty = make(map[string]LayoutCandle, len(dirty))
+	h.layoutSync.mu.Unlock()
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients, exists := h.channelSubscriptions[ChannelLayoutSync]
+	if !exists {
+		return
+	}
+
+	timestamp := time.Now().UnixMilli()
+	for client := range clients {
+		candles := make([]LayoutCandle, 0, len(client.layoutPairs))
+		for key := range client.layoutPairs {
+			if candle, changed := dirty[key]; changed {
+				candles = append(candles, candle)
+			}
+		}
+		if len(candles) == 0 {
+			continue
+		}
+
+		message, err := json.Marshal(map[string]interface{}{
+			"type":      "layout_sync",
+			"channel":   ChannelLayoutSync,
+			"candles":   candles,
+			"timestamp": timestamp,
+		})
+		if err != nil {
+			log.Printf("Error marshaling layout sync frame: %v", err)
+			continue
+		}
+
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, remove client
+			h.dropSlowClient(client)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package websocket
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limit names referenced by "limits" and limit "error" messages
+const (
+	LimitMaxSymbols        = "max_symbols"
+	LimitMaxChannels       = "max_channels"
+	LimitMessagesPerSecond = "messages_per_second"
+	LimitMaxLayoutPairs    = "max_layout_pairs"
+)
+
+// ClientLimits caps what a single regular WebSocket client may subscribe to and send
+type ClientLimits struct {
+	MaxSymbols        int // Symbol subscriptions per client (0 = unlimited)
+	MaxChannels       int // Channel subscriptions per client (0 = unlimited)
+	MessagesPerSecond int // Inbound messages per client per second (0 = unlimited)
+
+	// Outbound bandwidth caps; clients over them are conflated, then downgraded (0 = unlimited)
+	MaxBytesPerSecond     int // Per connection
+	MaxUserBytesPerSecond int // Across one user's connections
+}
+
+// LimitsMessage is sent on connect so clients can stay within the server's limits
+// Zero maximums mean unlimited
+type LimitsMessage struct {
+	Type                    string           `json:"type"` // "limits"
+	MaxSymbols              int              `json:"max_symbols"`
+	MaxChannels             int              `json:"max_channels"`
+	MessagesPerSecond       int              `json:"messages_per_second"`
+	MaxVolumeProfiles       int              `json:"max_volume_profiles"`
+	MaxLayoutPairs          int              `json:"max_layout_pairs"`
+	MaxBytesPerSecond       int              `json:"max_bytes_per_second"`
+	MaxUserBytesPerSecond   int              `json:"max_user_bytes_per_second"`
+	ConflationMinIntervalMs map[string]int64 `json:"conflation_min_interval_ms"` // Fastest flush per conflated channel
+	Timestamp               int64            `json:"timestamp"`
+}
+
+// LimitError rejects a client message that would exceed one of its limits
+type LimitError struct {
+	Type      string `json:"type"` // "error"
+	Code      string `json:"code"` // "limit_exceeded"
+	Limit     string `json:"limit"`
+	Max       int    `json:"max"`
+	Symbol    string `json:"symbol,omitempty"`
+	Channel   string `json:"channel,omitempty"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SetClientLimits sets the per-client subscription, message and bandwidth quotas for new connections
+func (h *Hub) SetClientLimits(cfg ClientLimits) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.limits = cfg
+}
+
+// getClientLimits returns the per-client limits
+func (h *Hub) getClientLimits() ClientLimits {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.limits
+}
+
+// sendLimits tells a newly connected client the limits that apply to it
+func (c *Client) sendLimits() {
+	c.sendMessage(LimitsMessage{
+		Type:                  "limits",
+		MaxSymbols:            c.limits.MaxSymbols,
+		MaxChannels:           c.limits.MaxChannels,
+		MessagesPerSecond:     c.limits.MessagesPerSecond,
+		MaxVolumeProfiles:     maxVolumeProfileSubscriptions,
+		MaxLayoutPairs:        maxLayoutPairs,
+		MaxBytesPerSecond:     c.limits.MaxBytesPerSecond,
+		MaxUserBytesPerSecond: c.limits.MaxUserBytesPerSecond,
+		ConflationMinIntervalMs: map[string]int64{
+			ChannelLayoutSync:    layoutSyncInterval.Milliseconds(),
+			ChannelVolumeProfile: volumeProfileDeltaInterval.Milliseconds(),
+		},
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
+// allowMessage counts an inbound message against the per-second quota
+// Only called from readPump, so the window needs no locking
+func (c *Client) allowMessage() bool {
+	if c.limits.MessagesPerSecond <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(c.messageWindow) >= time.Second {
+		c.messageWindow, c.messageCount = now, 0
+	}
+	c.messageCount++
+	return c.messageCount <= c.limits.MessagesPerSecond
+}
+
+// symbolLimitError returns the error for subscribing to symbol, or nil when it fits
+// Re-subscribing to a symbol the client already has never counts against the limit
+func (h *Hub) symbolLimitError(client *Client, symbol string) *LimitError {
+	max := client.limits.MaxSymbols
+	if max <= 0 {
+		return nil
+	}
+
+	h.mutex.RLock()
+	count, subscribed := len(client.symbols), client.symbols[symbol]
+	h.mutex.RUnlock()
+
+	if subscribed || count < max {
+		return nil
+	}
+	return &LimitError{
+		Type:      "error",
+		Code:      "limit_exceeded",
+		Limit:     LimitMaxSymbols,
+		Max:       max,
+		Symbol:    symbol,
+		Message:   fmt.Sprintf("Cannot subscribe to %s: limit of %d symbols reached", symbol, max),
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
+
+// channelLimitError returns the error for subscribing to channel, or nil when it fits
+func (h *Hub) channelLimitError(client *Client, channel string) *LimitError {
+	max := client.limits.MaxChannels
+	if max <= 0 {
+		return nil
+	}
+
+	h.mutex.RLock()
+	count, subscribed := len(client.channels), client.channels[channel]
+	h.mutex.RUnlock()
+
+	if subscribed || count < max {
+		return nil
+	}
+	return &LimitError{
+		Type:      "error",
+		Code:      "limit_exceeded",
+		Limit:     LimitMaxChannels,
+		Max:       max,
+		Channel:   channel,
+		Message:   fmt.Sprintf("Cannot subscribe to %s: limit of %d channels reached", channel, max),
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
+
+// rateLimitError returns the error for a message dropped by the per-second quota
+func (c *Client) rateLimitError(message ClientMessage) *LimitError {
+	return &LimitError{
+		Type:      "error",
+		Code:      "limit_exceeded",
+		Limit:     LimitMessagesPerSecond,
+		Max:       c.limits.MessagesPerSecond,
+		Symbol:    message.Symbol,
+		Channel:   message.Channel,
+		Message:   fmt.Sprintf("Message %q dropped: limit of %d messages per second reached", message.Type, c.limits.MessagesPerSecond),
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
@@ -0,0 +1,45 @@
+package websocket
+
+import (
+	"time"
+	"tterminal-backend/models"
+)
+
+// maxRecentLiquidations is how many liquidations a stream keeps per symbol
+const maxRecentLiquidations = 1000
+
+// recordLiquidation appends a liquidation to its symbol's recent liquidations, keeping the
+// last maxRecentLiquidations; the caller holds the stream's lock
+func recordLiquidation(recent map[string][]models.LiquidationEvent, event models.LiquidationEvent) {
+	liquidations := append(recent[event.Symbol], event)
+	if len(liquidations) > maxRecentLiquidations {
+		liquidations = liquidations[len(liquidations)-maxRecentLiquidations:]
+	}
+	recent[event.Symbol] = liquidations
+}
+
+// lastLiquidations returns a copy of the last limit liquidations (all when limit <= 0)
+func lastLiquidations(liquidations []models.LiquidationEvent, limit int) []models.LiquidationEvent {
+	if limit > 0 && limit < len(liquidations) {
+		liquidations = liquidations[len(liquidations)-limit:]
+	}
+	return append([]models.LiquidationEvent(nil), liquidations...)
+}
+
+// publishLiquidation broadcasts a liquidation to the symbol's subscribers and the market-wide
+// tape (filtered per client by notional), then hands it to the stream's event handlers
+func publishLiquidation(hub *Hub, events *MarketEvents, event models.LiquidationEvent) {
+	liquidationUpdate := LiquidationUpdateMessage(event, time.Now().UnixMilli())
+	hub.BroadcastLiquidationUpdate(liquidationUpdate)
+
+	notional := event.Price * event.Quantity
+	globalUpdate := make(map[string]interface{}, len(liquidationUpdate)+2)
+	for key, value := range liquidationUpdate {
+		globalUpdate[key] = value
+	}
+	globalUpdate["channel"] = ChannelLiquidationsAll
+	globalUpdate["notional"] = notional
+	hub.BroadcastGlobalLiquidation(globalUpdate, notional)
+
+	events.emitLiquidation(event)
+}
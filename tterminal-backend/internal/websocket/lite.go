@@ -0,0 +1,259 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// liteConflateInterval is how often lite clients receive the latest price and 1m kline
+	liteConflateInterval = time.Second
+
+	// litePongWait and litePingPeriod replace the regular ping timings for lite connections
+	litePongWait   = 3 * time.Minute
+	litePingPeriod = (litePongWait * 9) / 10
+
+	// liteSendBuffer is the outbound queue size of a lite client (one conflated frame per tick)
+	liteSendBuffer = 16
+
+	// liteMaxMessageSize caps inbound messages; lite clients may only ping
+	liteMaxMessageSize = 128
+)
+
+// Errors returned when a lite connection is refused before upgrading
+var (
+	ErrLiteCapacity = errors.New("lite connection limit reached")
+	ErrLiteIPLimit  = errors.New("too many lite connections from this address")
+)
+
+// LiteConfig caps watch-only lite connections used by embedded mini-charts
+type LiteConfig struct {
+	MaxConnections int // Lite connections across all clients (0 = unlimited)
+	MaxPerIP       int // Concurrent lite connections per remote address (0 = unlimited)
+}
+
+// LiteUpdate is the conflated state sent to lite clients once per tick
+type LiteUpdate struct {
+	Type          string        `json:"type"` // "lite_update"
+	Symbol        string        `json:"symbol"`
+	Price         float64       `json:"price,omitempty"`
+	ChangePercent float64       `json:"change_percent,omitempty"`
+	Kline         *LayoutCandle `json:"kline,omitempty"` // Forming 1m candle
+	Timestamp     int64         `json:"timestamp"`
+}
+
+// liteFeed holds the latest price and 1m kline per symbol since the last tick (latest wins)
+type liteFeed struct {
+	mu     sync.Mutex
+	prices map[string]PriceUpdate
+	klines map[string]LayoutCandle
+}
+
+// SetLiteConfig sets the connection caps for lite connections
+func (h *Hub) SetLiteConfig(cfg LiteConfig) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.liteConfig = cfg
+}
+
+// HandleLiteWebSocket upgrades a watch-only connection fixed to one symbol
+// Lite clients receive only a conflated price and 1m kline once per second, cannot change
+// their subscription and are pinged less often. Caps are checked before upgrading
+func (h *Hub) HandleLiteWebSocket(w http.ResponseWriter, r *http.Request, symbol, remoteIP string) error {
+	symbol = strings.ToUpper(symbol)
+
+	if err := h.reserveLiteSlot(remoteIP); err != nil {
+		return err
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.releaseLiteSlot(remoteIP)
+		log.Printf("Lite WebSocket upgrade failed: %v", err)
+		return nil // The upgrader already responded
+	}
+
+	client := &Client{
+		conn:       conn,
+		send:       make(chan []byte, liteSendBuffer),
+		id:         uuid.New().String()[:8],
+		symbols:    make(map[string]bool),
+		channels:   make(map[string]bool),
+		hub:        h,
+		lastWrite:  time.Now(),
+		lite:       true,
+		liteSymbol: symbol,
+		remoteIP:   remoteIP,
+	}
+	client.initBandwidth()
+
+	h.mutex.Lock()
+	if h.liteSubscriptions[symbol] == nil {
+		h.liteSubscriptions[symbol] = make(map[*Client]bool)
+	}
+	h.liteSubscriptions[symbol][client] = true
+	h.mutex.Unlock()
+
+	h.register <- client
+
+	go client.writePump()
+	go client.readPump()
+	return nil
+}
+
+// reserveLiteSlot counts a new lite connection against the global and per-address caps
+func (h *Hub) reserveLiteSlot(remoteIP string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.liteConfig.MaxConnections > 0 && h.liteConnections >= h.liteConfig.MaxConnections {
+		return ErrLiteCapacity
+	}
+	if h.liteConfig.MaxPerIP > 0 && h.liteConnectionsByIP[remoteIP] >= h.liteConfig.MaxPerIP {
+		return ErrLiteIPLimit
+	}
+	h.liteConnections++
+	h.liteConnectionsByIP[remoteIP]++
+	return nil
+}
+
+// releaseLiteSlot frees a lite connection's slot
+func (h *Hub) releaseLiteSlot(remoteIP string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.releaseLiteSlotLocked(remoteIP)
+}
+
+// releaseLiteSlotLocked frees a lite connection's slot; the caller holds h.mutex
+func (h *Hub) releaseLiteSlotLocked(remoteIP string) {
+	h.liteConnections--
+	if h.liteConnectionsByIP[remoteIP] <= 1 {
+		delete(h.liteConnectionsByIP, remoteIP)
+	} else {
+		h.liteConnectionsByIP[remoteIP]--
+	}
+}
+
+// removeLiteClientLocked drops a disconnected lite client; the caller holds h.mutex
+func (h *Hub) removeLiteClientLocked(client *Client) {
+	if clients, exists := h.liteSubscriptions[client.liteSymbol]; exists {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.liteSubscriptions, client.liteSymbol)
+		}
+	}
+	h.releaseLiteSlotLocked(client.remoteIP)
+}
+
+// QueueLitePrice records the latest price of a symbol for the next lite tick
+func (h *Hub) QueueLitePrice(update PriceUpdate) {
+	if h.symbolPaused(update.Symbol) {
+		return
+	}
+
+	h.lite.mu.Lock()
+	h.lite.prices[update.Symbol] = update
+	h.lite.mu.Unlock()
+}
+
+// QueueLiteKline records the forming 1m candle of a symbol for the next lite tick
+func (h *Hub) QueueLiteKline(candle LayoutCandle) {
+	if h.symbolPaused(candle.Symbol) {
+		return
+	}
+
+	h.lite.mu.Lock()
+	h.lite.klines[candle.Symbol] = candle
+	h.lite.mu.Unlock()
+}
+
+// runLiteFeed flushes conflated updates to lite clients once per tick
+func (h *Hub) runLiteFeed() {
+	ticker := time.NewTicker(liteConflateInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.flushLiteFeed()
+	}
+}
+
+// flushLiteFeed sends each symbol with changes one "lite_update" to its lite clients
+func (h *Hub) flushLiteFeed() {
+	h.lite.mu.Lock()
+	if len(h.lite.prices) == 0 && len(h.lite.klines) == 0 {
+		h.lite.mu.Unlock()
+		return
+	}
+	prices, klines := h.lite.prices, h.lite.klines
+	h.lite.prices = make(map[string]PriceUpdate, len(prices))
+	h.lite.klines = make(map[string]LayoutCandle, len(klines))
+	h.lite.mu.Unlock()
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	timestamp := time.Now().UnixMilli()
+	for symbol, clients := range h.liteSubscriptions {
+		price, hasPrice := prices[symbol]
+		kline, hasKline := klines[symbol]
+		if !hasPrice && !hasKline {
+			continue
+		}
+
+		update := LiteUpdate{Type: "lite_update", Symbol: symbol, Timestamp: timestamp}
+		if hasPrice {
+			update.Price = price.Price
+			update.ChangePercent = price.ChangePercent
+		}
+		if hasKline {
+			update.Kline = &kline
+		}
+
+		message, err := json.Marshal(update)
+		if err != nil {
+			log.Printf("Error marshaling lite update: %v", err)
+			continue
+		}
+
+		for client := range clients {
+			select {
+			case client.send <- message:
+			default:
+				// Skip this tick for a slow client; the next one carries newer state
+			}
+		}
+	}
+}
+
+// rejectLiteMessage answers any lite client message other than ping
+func (c *Client) rejectLiteMessage() {
+	c.sendMessage(map[string]interface{}{
+		"type":      "error",
+		"message":   "Lite connections are fixed to " + c.liteSymbol + " and only accept ping",
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// GetLiteStats returns lite connection counts per symbol
+func (h *Hub) GetLiteStats() map[string]interface{} {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	symbols := make(map[string]int, len(h.liteSubscriptions))
+	for symbol, clients := range h.liteSubscriptions {
+		symbols[symbol] = len(clients)
+	}
+	return map[string]interface{}{
+		"connections":     h.liteConnections,
+		"max_connections": h.liteConfig.MaxConnections,
+		"max_per_ip":      h.liteConfig.MaxPerIP,
+		"symbols":         symbols,
+	}
+}
@@ -0,0 +1,383 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// loadWindow is how often the hub's load is measured
+	loadWindow = 5 * time.Second
+
+	// loadHold is how long the hub stays saturated before it may return to normal
+	loadHold = 30 * time.Second
+
+	// loadRecoverRatio is the share of every threshold load must fall under to return to normal
+	loadRecoverRatio = 0.8
+
+	// loadQueueBusyRatio is the send queue fill from which a client counts as backed up
+	loadQueueBusyRatio = 0.5
+
+	// Delivery intervals suggested while saturated, and enforced when enforcement is on
+	loadDepthInterval = time.Second
+	loadKlineInterval = time.Second
+
+	// maxDeliveryInterval caps the delivery intervals a client may choose
+	maxDeliveryInterval = 5 * time.Second
+)
+
+// Hub load levels
+const (
+	LoadNormal    = "normal"
+	LoadSaturated = "saturated"
+)
+
+// Load signals naming the thresholds a saturated hub crossed
+const (
+	LoadSignalBytesPerSecond = "bytes_per_second"
+	LoadSignalQueuePct       = "queue_pct"
+	LoadSignalClients        = "clients"
+)
+
+// LoadConfig sets the thresholds at which the hub counts as saturated (0 disables a threshold)
+type LoadConfig struct {
+	MaxBytesPerSecond int  `json:"max_bytes_per_second"` // Outbound bytes per second across all clients
+	MaxQueuePct       int  `json:"max_queue_pct"`        // Percentage of clients whose send queue is at least half full
+	MaxClients        int  `json:"max_clients"`          // Connected regular clients
+	Enforce           bool `json:"enforce"`              // Apply the suggested delivery intervals while saturated
+	ReconnectPct      int  `json:"reconnect_pct"`        // Share of clients advised to reconnect to a peer while saturated
+}
+
+// DeliveryOptions are a client's delivery intervals for high-frequency updates, set with a
+// "set_delivery" message; updates inside an interval are skipped (0 = every update)
+type DeliveryOptions struct {
+	DepthIntervalMs int64 `json:"depth_interval_ms"` // depth_update per symbol
+	KlineIntervalMs int64 `json:"kline_interval_ms"` // Forming kline_update per symbol and interval
+}
+
+// LoadSuggestion is one cheaper alternative to a high-cost subscription
+type LoadSuggestion struct {
+	Subscription string `json:"subscription"` // "depth", "klines" or "layout:sync"
+	Symbol       string `json:"symbol,omitempty"`
+	Setting      string `json:"setting"` // What to change: a delivery option or the layout pair interval
+	Current      string `json:"current"`
+	Suggested    string `json:"suggested"`
+}
+
+// LoadAdvisory asks a client to switch to cheaper subscriptions while the hub is saturated, and
+// tells it when load is back to normal
+type LoadAdvisory struct {
+	Type        string           `json:"type"` // "load_advisory"
+	Level       string           `json:"level"`
+	Signals     []string         `json:"signals,omitempty"` // Thresholds crossed
+	Enforced    bool             `json:"enforced"`          // Suggested delivery intervals applied by the server
+	Suggestions []LoadSuggestion `json:"suggestions,omitempty"`
+	Message     string           `json:"message"`
+	Timestamp   int64            `json:"timestamp"`
+}
+
+// LoadStats is the hub's load as of the last measurement
+type LoadStats struct {
+	Level          string     `json:"level"`
+	Signals        []string   `json:"signals"`
+	Since          time.Time  `json:"since"`
+	Enforced       bool       `json:"enforced"`
+	BytesPerSecond int64      `json:"bytes_per_second"`
+	QueuePct       float64    `json:"queue_pct"`
+	Clients        int        `json:"clients"`
+	Advised        int        `json:"advised"` // Clients sent a saturated advisory
+	Thresholds     LoadConfig `json:"thresholds"`
+}
+
+// loadMonitor holds the hub's load level
+type loadMonitor struct {
+	mu      sync.RWMutex
+	config  LoadConfig
+	stats   LoadStats
+	advised map[*Client]bool // Clients sent the current saturated advisory
+}
+
+// newLoadMonitor creates a load monitor starting at normal load
+func newLoadMonitor() *loadMonitor {
+	return &loadMonitor{stats: LoadStats{Level: LoadNormal, Signals: []string{}, Since: time.Now()}}
+}
+
+// SetLoadConfig sets the saturation thresholds and whether downgrades are enforced
+func (h *Hub) SetLoadConfig(cfg LoadConfig) {
+	h.load.mu.Lock()
+	defer h.load.mu.Unlock()
+	h.load.config = cfg
+	h.load.stats.Thresholds = cfg
+}
+
+// GetLoadStats returns the hub's load level and the figures it was decided on
+func (h *Hub) GetLoadStats() LoadStats {
+	h.load.mu.RLock()
+	defer h.load.mu.RUnlock()
+	stats := h.load.stats
+	stats.Signals = append([]string{}, stats.Signals...)
+	stats.Advised = len(h.load.advised)
+	return stats
+}
+
+// loadEnforced reports whether delivery intervals are currently enforced
+func (h *Hub) loadEnforced() bool {
+	h.load.mu.RLock()
+	defer h.load.mu.RUnlock()
+	return h.load.stats.Enforced
+}
+
+// runLoadMonitor measures the hub's load every window and advises clients with high-cost
+// subscriptions while it is saturated
+func (h *Hub) runLoadMonitor() {
+	ticker := time.NewTicker(loadWindow)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		h.measureLoad(now)
+	}
+}
+
+// measureLoad decides the load level and sends advisories when it changes
+func (h *Hub) measureLoad(now time.Time) {
+	h.load.mu.RLock()
+	cfg := h.load.config
+	level, since := h.load.stats.Level, h.load.stats.Since
+	h.load.mu.RUnlock()
+
+	bytesPerSecond := h.bandwidth.rate.Load()
+	h.mutex.RLock()
+	clients, busy := 0, 0
+	for client := range h.clients {
+		if client.lite {
+			continue
+		}
+		clients++
+		if float64(len(client.send)) >= float64(cap(client.send))*loadQueueBusyRatio {
+			busy++
+		}
+	}
+	h.mutex.RUnlock()
+	queuePct := 0.0
+	if clients > 0 {
+		queuePct = float64(busy) / float64(clients) * 100
+	}
+
+	// Saturated when any threshold is crossed; normal again once all are well under after the hold
+	var signals []string
+	recovered := true
+	for _, check := range []struct {
+		signal string
+		value  float64
+		limit  int
+	}{
+		{LoadSignalBytesPerSecond, float64(bytesPerSecond), cfg.MaxBytesPerSecond},
+		{LoadSignalQueuePct, queuePct, cfg.MaxQueuePct},
+		{LoadSignalClients, float64(clients), cfg.MaxClients},
+	} {
+		if check.limit <= 0 {
+			continue
+		}
+		if check.value > float64(check.limit) {
+			signals = append(signals, check.signal)
+		}
+		if check.value > float64(check.limit)*loadRecoverRatio {
+			recovered = false
+		}
+	}
+
+	next := level
+	switch {
+	case level == LoadNormal && len(signals) > 0:
+		next = LoadSaturated
+	case level == LoadSaturated && recovered && now.Sub(since) >= loadHold:
+		next = LoadNormal
+	}
+
+	h.load.mu.Lock()
+	h.load.stats.BytesPerSecond = bytesPerSecond
+	h.load.stats.QueuePct = queuePct
+	h.load.stats.Clients = clients
+	h.load.stats.Signals = append([]string{}, signals...)
+	changed := next != level
+	if changed {
+		h.load.stats.Level = next
+		h.load.stats.Since = now
+		h.load.stats.Enforced = next == LoadSaturated && cfg.Enforce
+	}
+	h.load.mu.Unlock()
+
+	if changed {
+		h.sendLoadAdvisories(next, signals, cfg.Enforce)
+		if next == LoadSaturated && cfg.ReconnectPct > 0 {
+			go h.adviseOverloadReconnect(cfg.ReconnectPct)
+		}
+	}
+}
+
+// sendLoadAdvisories tells clients with high-cost subscriptions about a saturated hub, or the
+// clients advised earlier that load is back to normal
+func (h *Hub) sendLoadAdvisories(level string, signals []string, enforce bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	h.load.mu.Lock()
+	defer h.load.mu.Unlock()
+
+	if level == LoadNormal {
+		for client := range h.load.advised {
+			if h.clients[client] {
+				h.sendAdvisoryLocked(client, LoadAdvisory{
+					Type:    "load_advisory",
+					Level:   LoadNormal,
+					Message: "Server load is back to normal; full-rate subscriptions are fine again",
+				})
+			}
+		}
+		h.load.advised = nil
+		return
+	}
+
+	h.load.advised = make(map[*Client]bool)
+	for client := range h.clients {
+		if client.lite {
+			continue
+		}
+		suggestions := client.loadSuggestions()
+		if len(suggestions) == 0 {
+			continue
+		}
+		message := "Server is under heavy load: please switch to the suggested subscriptions"
+		if enforce {
+			message = fmt.Sprintf("Server is under heavy load: depth and forming klines are limited to one update per %s until load drops", loadDepthInterval)
+		}
+		h.sendAdvisoryLocked(client, LoadAdvisory{
+			Type:        "load_advisory",
+			Level:       LoadSaturated,
+			Signals:     signals,
+			Enforced:    enforce,
+			Suggestions: suggestions,
+			Message:     message,
+		})
+		h.load.advised[client] = true
+	}
+}
+
+// sendAdvisoryLocked queues an advisory without blocking; the caller holds h.mutex
+func (h *Hub) sendAdvisoryLocked(client *Client, advisory LoadAdvisory) {
+	advisory.Timestamp = time.Now().UnixMilli()
+	message, err := json.Marshal(advisory)
+	if err != nil {
+		return
+	}
+	select {
+	case client.send <- message:
+	default:
+	}
+}
+
+// loadSuggestions lists cheaper alternatives to the client's high-cost subscriptions
+// The caller holds h.mutex
+func (c *Client) loadSuggestions() []LoadSuggestion {
+	var suggestions []LoadSuggestion
+	if len(c.symbols) > 0 {
+		if depth := time.Duration(c.depthInterval.Load()) * time.Millisecond; depth < loadDepthInterval {
+			suggestions = append(suggestions, LoadSuggestion{
+				Subscription: "depth",
+				Setting:      "delivery.depth_interval_ms",
+				Current:      formatDeliveryInterval(depth),
+				Suggested:    formatDeliveryInterval(loadDepthInterval),
+			})
+		}
+		if kline := time.Duration(c.klineInterval.Load()) * time.Millisecond; kline < loadKlineInterval {
+			suggestions = append(suggestions, LoadSuggestion{
+				Subscription: "klines",
+				Setting:      "delivery.kline_interval_ms",
+				Current:      formatDeliveryInterval(kline),
+				Suggested:    formatDeliveryInterval(loadKlineInterval),
+			})
+		}
+	}
+
+	return suggestions
+}
+
+// formatDeliveryInterval describes a delivery interval, 0 being every update
+func formatDeliveryInterval(interval time.Duration) string {
+	if interval <= 0 {
+		return "every update"
+	}
+	return fmt.Sprintf("%dms", interval.Milliseconds())
+}
+
+// setDelivery applies a client's "set_delivery" message
+func (c *Client) setDelivery(options *DeliveryOptions) {
+	if options == nil || options.DepthIntervalMs < 0 || options.KlineIntervalMs < 0 ||
+		options.DepthIntervalMs > maxDeliveryInterval.Milliseconds() || options.KlineIntervalMs > maxDeliveryInterval.Milliseconds() {
+		c.sendMessage(map[string]interface{}{
+			"type":      "error",
+			"message":   fmt.Sprintf("delivery intervals must be between 0 and %d ms", maxDeliveryInterval.Milliseconds()),
+			"timestamp": time.Now().UnixMilli(),
+		})
+		return
+	}
+
+	c.depthInterval.Store(options.DepthIntervalMs)
+	c.klineInterval.Store(options.KlineIntervalMs)
+	c.sendMessage(map[string]interface{}{
+		"type":              "delivery",
+		"depth_interval_ms": options.DepthIntervalMs,
+		"kline_interval_ms": options.KlineIntervalMs,
+		"enforced":          c.hub.loadEnforced(),
+		"timestamp":         time.Now().UnixMilli(),
+	})
+}
+
+// filterDelivery skips depth and forming kline updates arriving inside the client's delivery
+// interval, or the enforced one while the hub is saturated. Called from the write goroutine
+func (c *Client) filterDelivery(batch [][]byte) [][]byte {
+	depth := time.Duration(c.depthInterval.Load()) * time.Millisecond
+	kline := time.Duration(c.klineInterval.Load()) * time.Millisecond
+	if !c.lite && c.hub.loadEnforced() {
+		depth = max(depth, loadDepthInterval)
+		kline = max(kline, loadKlineInterval)
+	}
+	if depth <= 0 && kline <= 0 {
+		return batch
+	}
+
+	now := time.Now()
+	kept := batch[:0]
+	for _, message := range batch {
+		header, ok := parseMessageHeader(message)
+		if !ok {
+			kept = append(kept, message)
+			continue
+		}
+
+		var interval time.Duration
+		switch {
+		case header.Type == "depth_update":
+			interval = depth
+		case header.Type == "kline_update" && !header.IsClosed:
+			interval = kline
+		}
+		if interval <= 0 {
+			kept = append(kept, message)
+			continue
+		}
+
+		key := header.key()
+		if last, sent := c.lastDelivered[key]; sent && now.Sub(last) < interval {
+			continue
+		}
+		if c.lastDelivered == nil {
+			c.lastDelivered = make(map[string]time.Time)
+		}
+		c.lastDelivered[key] = now
+		kept = append(kept, message)
+	}
+	return kept
+}
@@ -0,0 +1,204 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Sessions that have not polled for this long are closed
+	pollSessionTimeout = 60 * time.Second
+
+	// How often expired sessions are reaped
+	pollReapInterval = 15 * time.Second
+
+	// Outbound buffer per session; larger than WebSocket clients since messages wait between polls
+	pollSendBuffer = 1024
+
+	// Maximum messages returned by a single poll
+	maxPollBatch = 500
+)
+
+// ErrPollSessionNotFound is returned for unknown or expired long-polling sessions
+var ErrPollSessionNotFound = errors.New("poll session not found")
+
+// pollSession is a long-polling client sharing the Hub subscription model with WebSocket clients
+type pollSession struct {
+	client   *Client
+	lastPoll time.Time
+}
+
+// pollSessionRegistry tracks active long-polling sessions
+type pollSessionRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]*pollSession
+}
+
+// OpenPollSession registers a long-polling client and returns its session ID
+// The client subscribes with the same messages as a WebSocket client via PollSessionSend. userID
+// must be a verified identity; a session opened with one is only reachable by the same user
+func (h *Hub) OpenPollSession(userID string, resubscribe bool) string {
+	client := &Client{
+		send:            make(chan []byte, pollSendBuffer),
+		id:              uuid.New().String()[:8],
+		userID:          userID,
+		symbols:         make(map[string]bool),
+		enrichedSymbols: make(map[string]bool),
+		channels:        make(map[string]bool),
+		hub:             h,
+	}
+	client.initBandwidth()
+	// Poll requests go through the HTTP rate limiter rather than the per-second message quota
+	client.limits = h.getClientLimits()
+	client.limits.MessagesPerSecond = 0
+	sessionID := uuid.New().String()
+
+	h.pollSessions.mu.Lock()
+	h.pollSessions.sessions[sessionID] = &pollSession{client: client, lastPoll: time.Now()}
+	h.pollSessions.mu.Unlock()
+
+	h.register <- client
+	client.sendLimits()
+
+	if resubscribe {
+		go h.restoreSubscriptions(client)
+	}
+
+	return sessionID
+}
+
+// PollSessionSend handles a client message (subscribe, unsubscribe, ...) for a session
+func (h *Hub) PollSessionSend(sessionID, userID string, message ClientMessage) error {
+	session := h.touchPollSession(sessionID, userID)
+	if session == nil {
+		return ErrPollSessionNotFound
+	}
+
+	session.client.handleMessage(message)
+	return nil
+}
+
+// PollSessionReceive waits up to timeout for messages and returns everything queued
+// An empty result means the wait timed out; the client should poll again
+func (h *Hub) PollSessionReceive(ctx context.Context, sessionID, userID string, timeout time.Duration) ([]json.RawMessage, error) {
+	session := h.touchPollSession(sessionID, userID)
+	if session == nil {
+		return nil, ErrPollSessionNotFound
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	messages := make([]json.RawMessage, 0)
+	select {
+	case message, ok := <-session.client.send:
+		if !ok {
+			h.removePollSession(sessionID)
+			return nil, ErrPollSessionNotFound
+		}
+		messages = append(messages, message)
+		session.client.recordPolled(len(message))
+	case <-timer.C:
+		return messages, nil
+	case <-ctx.Done():
+		return messages, nil
+	}
+
+	// Drain whatever else is queued without waiting
+	for len(messages) < maxPollBatch {
+		select {
+		case message, ok := <-session.client.send:
+			if !ok {
+				return messages, nil
+			}
+			messages = append(messages, message)
+			session.client.recordPolled(len(message))
+		default:
+			h.touchPollSession(sessionID, userID)
+			return messages, nil
+		}
+	}
+
+	h.touchPollSession(sessionID, userID)
+	return messages, nil
+}
+
+// ClosePollSession unregisters a long-polling session
+func (h *Hub) ClosePollSession(sessionID, userID string) error {
+	if h.touchPollSession(sessionID, userID) == nil {
+		return ErrPollSessionNotFound
+	}
+	session := h.removePollSession(sessionID)
+	if session == nil {
+		return ErrPollSessionNotFound
+	}
+
+	h.unregister <- session.client
+	return nil
+}
+
+// GetPollSessionCount returns the number of active long-polling sessions
+func (h *Hub) GetPollSessionCount() int {
+	h.pollSessions.mu.Lock()
+	defer h.pollSessions.mu.Unlock()
+	return len(h.pollSessions.sessions)
+}
+
+// touchPollSession returns a session and marks it as recently polled
+// Sessions opened by a user are not found for anyone else
+func (h *Hub) touchPollSession(sessionID, userID string) *pollSession {
+	h.pollSessions.mu.Lock()
+	defer h.pollSessions.mu.Unlock()
+
+	session, exists := h.pollSessions.sessions[sessionID]
+	if !exists || session.client.userID != userID {
+		return nil
+	}
+	session.lastPoll = time.Now()
+	return session
+}
+
+// removePollSession removes and returns a session
+func (h *Hub) removePollSession(sessionID string) *pollSession {
+	h.pollSessions.mu.Lock()
+	defer h.pollSessions.mu.Unlock()
+
+	session, exists := h.pollSessions.sessions[sessionID]
+	if !exists {
+		return nil
+	}
+	delete(h.pollSessions.sessions, sessionID)
+	return session
+}
+
+// runPollSessionReaper closes sessions that stopped polling
+func (h *Hub) runPollSessionReaper() {
+	ticker := time.NewTicker(pollReapInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var expired []*Client
+
+		h.pollSessions.mu.Lock()
+		for sessionID, session := range h.pollSessions.sessions {
+			if time.Since(session.lastPoll) > pollSessionTimeout {
+				expired = append(expired, session.client)
+				delete(h.pollSessions.sessions, sessionID)
+			}
+		}
+		h.pollSessions.mu.Unlock()
+
+		for _, client := range expired {
+			h.unregister <- client
+		}
+		if len(expired) > 0 {
+			log.Printf("Expired %d long-polling sessions", len(expired))
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package websocket
+
+import (
+	"sync"
+	"tterminal-backend/models"
+)
+
+// MarketEvents fans a stream's trades, book updates, liquidations and prices out to in-process handlers
+// in an exchange-agnostic form, so consumers need not know any exchange's wire format
+// Handlers run on the stream's read loop and must return quickly
+type MarketEvents struct {
+	mu           sync.RWMutex
+	trades       []func(models.TradeRecord)
+	depth        []func(models.DepthUpdate)
+	liquidations []func(models.LiquidationEvent)
+	prices       []func(models.PriceTick)
+}
+
+// newMarketEvents creates an event fan-out without handlers
+func newMarketEvents() *MarketEvents {
+	return &MarketEvents{}
+}
+
+// OnTrade registers a handler for every streamed trade
+func (e *MarketEvents) OnTrade(handler func(models.TradeRecord)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.trades = append(e.trades, handler)
+}
+
+// OnDepth registers a handler for every order book snapshot and update
+func (e *MarketEvents) OnDepth(handler func(models.DepthUpdate)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.depth = append(e.depth, handler)
+}
+
+// OnLiquidation registers a handler for every streamed liquidation
+func (e *MarketEvents) OnLiquidation(handler func(models.LiquidationEvent)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.liquidations = append(e.liquidations, handler)
+}
+
+// OnPrice registers a handler for every last price change
+func (e *MarketEvents) OnPrice(handler func(models.PriceTick)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.prices = append(e.prices, handler)
+}
+
+// emitTrade delivers a trade to the trade handlers
+func (e *MarketEvents) emitTrade(trade models.TradeRecord) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, handler := range e.trades {
+		handler(trade)
+	}
+}
+
+// emitDepth delivers a book update to the depth handlers
+func (e *MarketEvents) emitDepth(update models.DepthUpdate) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, handler := range e.depth {
+		handler(update)
+	}
+}
+
+// emitLiquidation delivers a liquidation to the liquidation handlers
+func (e *MarketEvents) emitLiquidation(liquidation models.LiquidationEvent) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, handler := range e.liquidations {
+		handler(liquidation)
+	}
+}
+
+// emitPrice delivers a last price change to the price handlers
+func (e *MarketEvents) emitPrice(tick models.PriceTick) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, handler := range e.prices {
+		handler(tick)
+	}
+}
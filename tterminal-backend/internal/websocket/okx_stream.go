@@ -0,0 +1,822 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/internal/okx"
+	"tterminal-backend/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// okxSubscribeBatch is the number of channel arguments sent per subscribe request
+	okxSubscribeBatch = 20
+	// okxPingInterval keeps connections open; OKX drops connections silent for 30s
+	okxPingInterval = 20 * time.Second
+)
+
+// okxCandleChannels maps the streamed OKX candle channels to API intervals (see barCloseIntervals)
+var okxCandleChannels = map[string]string{"candle1m": "1m", "candle5m": "5m", "candle15m": "15m"}
+
+// OKXStream streams OKX perpetual swap market data into the hub
+// OKX serves candles on a separate business endpoint, so the stream holds two connections.
+// Updates are broadcast under exchange-qualified symbols ("OKX:BTCUSDT") with an "exchange"
+// field. Trade, book and liquidation sizes are converted from contracts to the base currency,
+// then feed the same recorders and bar close pipeline as the Binance futures stream
+type OKXStream struct {
+	hub     *Hub
+	symbols []string // Bare symbols
+	conns   []*okxConnection
+
+	mu             sync.RWMutex
+	isRunning      bool
+	contractValues map[string]float64 // Base currency per contract by instrument
+	derivatives    map[string]*okxDerivatives
+	books          map[string]*localBook
+	liquidations   map[string][]models.LiquidationEvent
+	lastPrices     map[string]float64
+	lastMessage    atomic.Int64 // Unix milliseconds
+
+	// Rolling VWAP/delta/size context for enriched trade updates
+	tradeEnricher *TradeEnricher
+	// Optional persistence of trades and book snapshots (set after start)
+	tradeRecorder atomic.Pointer[tradeRecorder]
+	depthRecorder atomic.Pointer[depthRecorder]
+	// Bar close events, confirmed by closed OKX candles
+	barClose        *BarCloseScheduler
+	barCloseStarted sync.Once
+	// Normalized trades, book updates and liquidations for in-process consumers
+	events *MarketEvents
+}
+
+// okxConnection is one of the stream's endpoints and the channels subscribed on it
+type okxConnection struct {
+	name string // "public" or "business"
+	url  string
+	args []okxArg
+
+	conn        *websocket.Conn
+	connectedAt time.Time
+	reconnects  int64
+}
+
+// okxArg identifies a channel subscription
+type okxArg struct {
+	Channel  string `json:"channel"`
+	InstID   string `json:"instId,omitempty"`
+	InstType string `json:"instType,omitempty"`
+}
+
+// okxMessage is a v5 public stream message (channel data or an event response)
+type okxMessage struct {
+	Event  string          `json:"event"` // "subscribe" or "error" for operation responses
+	Code   string          `json:"code"`
+	Msg    string          `json:"msg"`
+	Arg    okxArg          `json:"arg"`
+	Action string          `json:"action"` // "snapshot" or "update" for books
+	Data   json.RawMessage `json:"data"`
+}
+
+// okxTicker is one tickers entry
+type okxTicker struct {
+	InstID    string `json:"instId"`
+	Last      string `json:"last"`
+	Open24h   string `json:"open24h"`
+	VolCcy24h string `json:"volCcy24h"` // Base currency
+}
+
+// okxDerivatives is the merged mark price, index price and funding state of an instrument
+type okxDerivatives struct {
+	MarkPrice   string
+	IndexPrice  string
+	FundingRate string
+	FundingTime string // Next settlement (Unix milliseconds)
+}
+
+// okxTrade is one trades entry
+type okxTrade struct {
+	InstID  string `json:"instId"`
+	TradeID string `json:"tradeId"`
+	Price   string `json:"px"`
+	Size    string `json:"sz"`   // Contracts
+	Side    string `json:"side"` // Taker side
+	Ts      string `json:"ts"`
+}
+
+// okxBookData is a books snapshot or update; levels are [price, contracts, deprecated, orders]
+type okxBookData struct {
+	Bids [][]string `json:"bids"`
+	Asks [][]string `json:"asks"`
+	Ts   string     `json:"ts"`
+}
+
+// okxLiquidation is one liquidation-orders entry
+type okxLiquidation struct {
+	InstID  string `json:"instId"`
+	Details []struct {
+		Side  string `json:"side"` // Liquidation order side: "sell" closes a long
+		Price string `json:"bkPx"` // Bankruptcy price
+		Size  string `json:"sz"`   // Contracts
+		Ts    string `json:"ts"`
+	} `json:"details"`
+}
+
+// NewOKXStream creates an OKX stream for bare symbols (e.g. "BTCUSDT")
+func NewOKXStream(hub *Hub, publicURL, businessURL string, symbols []string) *OKXStream {
+	s := &OKXStream{
+		hub:            hub,
+		symbols:        symbols,
+		contractValues: make(map[string]float64),
+		derivatives:    make(map[string]*okxDerivatives),
+		books:          make(map[string]*localBook),
+		liquidations:   make(map[string][]models.LiquidationEvent),
+		lastPrices:     make(map[string]float64),
+		tradeEnricher:  NewTradeEnricher(),
+	}
+
+	public := &okxConnection{name: "public", url: publicURL}
+	business := &okxConnection{name: "business", url: businessURL}
+	for _, symbol := range symbols {
+		instID := okx.InstrumentID(symbol)
+		public.args = append(public.args,
+			okxArg{Channel: "tickers", InstID: instID},                    // Last price and 24h stats
+			okxArg{Channel: "trades", InstID: instID},                     // Individual trades
+			okxArg{Channel: "books", InstID: instID},                      // Order book snapshot + updates
+			okxArg{Channel: "mark-price", InstID: instID},                 // Mark price
+			okxArg{Channel: "index-tickers", InstID: okx.IndexID(instID)}, // Index price
+			okxArg{Channel: "funding-rate", InstID: instID},               // Funding rate and next settlement
+		)
+		for channel := range okxCandleChannels {
+			business.args = append(business.args, okxArg{Channel: channel, InstID: instID})
+		}
+	}
+	// Liquidations are only published per instrument type; other instruments are filtered out
+	public.args = append(public.args, okxArg{Channel: "liquidation-orders", InstType: "SWAP"})
+	s.conns = []*okxConnection{public, business}
+
+	s.barClose = newBarCloseScheduler(hub, s.GetConnectedSymbols)
+	s.events = newMarketEvents()
+	return s
+}
+
+// SetContractValues sets the base currency amount of one contract per instrument
+// Sizes of instruments without a value are treated as base currency amounts
+func (s *OKXStream) SetContractValues(values map[string]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for instID, value := range values {
+		if value > 0 {
+			s.contractValues[instID] = value
+		}
+	}
+}
+
+// Start connects to the OKX public and business streams, reconnecting in the background on failure
+func (s *OKXStream) Start() error {
+	s.barCloseStarted.Do(func() {
+		go s.barClose.run(make(chan struct{}))
+	})
+
+	s.mu.Lock()
+	s.isRunning = true
+	s.mu.Unlock()
+
+	for _, c := range s.conns {
+		if err := s.connect(c); err != nil {
+			log.Printf("Failed to connect to OKX %s stream: %v", c.name, err)
+			go s.reconnect(c)
+		}
+	}
+
+	log.Printf("Connected to OKX WebSocket - Streaming %d swap symbols", len(s.symbols))
+	return nil
+}
+
+// Stop disconnects from the OKX streams
+func (s *OKXStream) Stop() {
+	s.mu.Lock()
+	s.isRunning = false
+	var conns []*websocket.Conn
+	for _, c := range s.conns {
+		if c.conn != nil {
+			conns = append(conns, c.conn)
+			c.conn = nil
+		}
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if len(conns) > 0 {
+		log.Println("OKX WebSocket stream stopped")
+	}
+}
+
+// SetTradeWriter enables persistence of OKX trades
+func (s *OKXStream) SetTradeWriter(writer *TradeWriter) {
+	s.tradeRecorder.Store(newTradeRecorder(writer, s.hub))
+	log.Printf("Trade persistence enabled for OKX trades")
+}
+
+// SetDepthSnapshotWriter enables periodic persistence of OKX order book snapshots
+func (s *OKXStream) SetDepthSnapshotWriter(writer *DepthSnapshotWriter, interval time.Duration) {
+	recorder := newDepthRecorder(writer, interval)
+	s.depthRecorder.Store(recorder)
+	log.Printf("OKX depth snapshot persistence enabled every %v", recorder.interval)
+}
+
+// connect dials an endpoint, subscribes to its channels and starts the read and ping loops
+func (s *OKXStream) connect(c *okxConnection) error {
+	dialer := websocket.DefaultDialer
+	dialer.HandshakeTimeout = 10 * time.Second
+
+	conn, _, err := dialer.Dial(c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(c.args); start += okxSubscribeBatch {
+		end := start + okxSubscribeBatch
+		if end > len(c.args) {
+			end = len(c.args)
+		}
+		request := map[string]interface{}{"op": "subscribe", "args": c.args[start:end]}
+		if err := conn.WriteJSON(request); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	c.conn = conn
+	c.connectedAt = time.Now()
+	if c.name == "public" {
+		// Order books restart from the snapshot sent after subscribing
+		s.books = make(map[string]*localBook)
+	}
+	s.mu.Unlock()
+
+	go s.readMessages(c, conn)
+	go s.pingPeriodically(c, conn)
+	return nil
+}
+
+// pingPeriodically sends the text pings OKX requires instead of control frames
+func (s *OKXStream) pingPeriodically(c *okxConnection, conn *websocket.Conn) {
+	ticker := time.NewTicker(okxPingInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !s.isCurrent(c, conn) {
+			return
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
+			log.Printf("Failed to send OKX %s ping: %v", c.name, err)
+			return
+		}
+	}
+}
+
+// readMessages reads and processes messages until the connection fails
+func (s *OKXStream) readMessages(c *okxConnection, conn *websocket.Conn) {
+	defer conn.Close()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if s.isCurrent(c, conn) {
+				log.Printf("Error reading from OKX %s WebSocket: %v", c.name, err)
+				s.mu.Lock()
+				c.conn = nil
+				s.mu.Unlock()
+				s.reconnect(c)
+			}
+			return
+		}
+
+		s.processMessage(message)
+	}
+}
+
+// isCurrent reports whether conn is the live connection of an endpoint of a running stream
+func (s *OKXStream) isCurrent(c *okxConnection, conn *websocket.Conn) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isRunning && c.conn == conn
+}
+
+// reconnect retries an endpoint's connection until it succeeds or the stream is stopped
+func (s *OKXStream) reconnect(c *okxConnection) {
+	for {
+		time.Sleep(5 * time.Second)
+
+		s.mu.Lock()
+		running := s.isRunning
+		c.reconnects++
+		s.mu.Unlock()
+		if !running {
+			return
+		}
+
+		log.Printf("Attempting to reconnect to OKX %s WebSocket...", c.name)
+		if err := s.connect(c); err != nil {
+			log.Printf("OKX %s reconnection failed: %v", c.name, err)
+			continue
+		}
+		log.Printf("Successfully reconnected to OKX %s WebSocket", c.name)
+		return
+	}
+}
+
+// processMessage routes a stream message by channel
+func (s *OKXStream) processMessage(message []byte) {
+	s.lastMessage.Store(time.Now().UnixMilli())
+
+	if string(message) == "pong" {
+		return
+	}
+	var msg okxMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	if msg.Event != "" {
+		if msg.Event == "error" {
+			log.Printf("OKX subscription rejected: %s (code %s)", msg.Msg, msg.Code)
+		}
+		return
+	}
+
+	switch msg.Arg.Channel {
+	case "tickers":
+		var tickers []okxTicker
+		if err := json.Unmarshal(msg.Data, &tickers); err == nil {
+			for _, ticker := range tickers {
+				s.processTicker(ticker)
+			}
+		}
+
+	case "mark-price", "index-tickers", "funding-rate":
+		var entries []map[string]string
+		if err := json.Unmarshal(msg.Data, &entries); err == nil {
+			for _, entry := range entries {
+				s.processDerivatives(msg.Arg.Channel, entry)
+			}
+		}
+
+	case "trades":
+		var trades []okxTrade
+		if err := json.Unmarshal(msg.Data, &trades); err == nil {
+			for _, trade := range trades {
+				s.processTrade(trade)
+			}
+		}
+
+	case "books":
+		var books []okxBookData
+		if err := json.Unmarshal(msg.Data, &books); err == nil {
+			for _, book := range books {
+				s.processBook(msg.Arg.InstID, book, msg.Action == "snapshot")
+			}
+		}
+
+	case "liquidation-orders":
+		var liquidations []okxLiquidation
+		if err := json.Unmarshal(msg.Data, &liquidations); err == nil {
+			for _, liquidation := range liquidations {
+				s.processLiquidation(liquidation)
+			}
+		}
+
+	default:
+		if interval, ok := okxCandleChannels[msg.Arg.Channel]; ok {
+			var candles [][]string
+			if err := json.Unmarshal(msg.Data, &candles); err == nil {
+				for _, candle := range candles {
+					s.processCandle(msg.Arg.InstID, interval, candle)
+				}
+			}
+		}
+	}
+}
+
+// symbolKey returns the qualified symbol of an instrument ("OKX:BTCUSDT")
+func okxSymbolKey(instID string) string {
+	return models.QualifySymbol(models.ExchangeOKX, okx.Symbol(instID))
+}
+
+// baseSize converts a size in contracts to the base currency; the caller holds s.mu
+func (s *OKXStream) baseSize(instID, contracts string) float64 {
+	size := models.ParseFloat(contracts)
+	if value, ok := s.contractValues[instID]; ok {
+		return size * value
+	}
+	return size
+}
+
+// processTicker broadcasts a price update when the last price changes
+func (s *OKXStream) processTicker(data okxTicker) {
+	key := okxSymbolKey(data.InstID)
+	lastPrice := models.ParseFloat(data.Last)
+	if lastPrice <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	priceChanged := s.lastPrices[key] != lastPrice
+	s.lastPrices[key] = lastPrice
+	s.mu.Unlock()
+
+	if !priceChanged {
+		return
+	}
+
+	open24h := models.ParseFloat(data.Open24h)
+	changePercent := 0.0
+	if open24h > 0 {
+		changePercent = (lastPrice - open24h) / open24h * 100
+	}
+	update := PriceUpdate{
+		Type:          "price_update",
+		Symbol:        key,
+		Exchange:      models.ExchangeOKX,
+		Price:         lastPrice,
+		Change:        lastPrice - open24h,
+		ChangePercent: changePercent,
+		Volume:        models.ParseFloat(data.VolCcy24h),
+		Timestamp:     time.Now().UnixMilli(),
+	}
+	s.hub.BroadcastPriceUpdate(update)
+	s.hub.QueueLitePrice(update)
+}
+
+// processDerivatives merges a mark price, index price or funding entry and broadcasts the
+// instrument's mark price update
+func (s *OKXStream) processDerivatives(channel string, entry map[string]string) {
+	instID := entry["instId"]
+	if channel == "index-tickers" {
+		// Index instruments ("BTC-USDT") are shared by the swap priced from them
+		instID = okx.InstrumentID(instID)
+	}
+
+	s.mu.Lock()
+	state, exists := s.derivatives[instID]
+	if !exists {
+		state = &okxDerivatives{}
+		s.derivatives[instID] = state
+	}
+	switch channel {
+	case "mark-price":
+		mergeField(&state.MarkPrice, entry["markPx"])
+	case "index-tickers":
+		mergeField(&state.IndexPrice, entry["idxPx"])
+	case "funding-rate":
+		mergeField(&state.FundingRate, entry["fundingRate"])
+		mergeField(&state.FundingTime, entry["fundingTime"])
+	}
+	merged := *state
+	s.mu.Unlock()
+
+	nextFundingTime, _ := strconv.ParseInt(merged.FundingTime, 10, 64)
+	s.hub.BroadcastMarkPriceUpdate(map[string]interface{}{
+		"type":              "mark_price_update",
+		"symbol":            okxSymbolKey(instID),
+		"exchange":          models.ExchangeOKX,
+		"mark_price":        models.ParseFloat(merged.MarkPrice),
+		"index_price":       models.ParseFloat(merged.IndexPrice),
+		"funding_rate":      models.ParseFloat(merged.FundingRate),
+		"next_funding_time": nextFundingTime,
+		"timestamp":         time.Now().UnixMilli(),
+	})
+}
+
+// processTrade broadcasts, records and profiles a trade
+func (s *OKXStream) processTrade(data okxTrade) {
+	price, err := strconv.ParseFloat(data.Price, 64)
+	if err != nil {
+		return
+	}
+	tradeTime, _ := strconv.ParseInt(data.Ts, 10, 64)
+
+	s.mu.RLock()
+	quantity := s.baseSize(data.InstID, data.Size)
+	s.mu.RUnlock()
+
+	key := okxSymbolKey(data.InstID)
+	// A taker sell hits the bid, so the buyer is the maker
+	isBuyerMaker := data.Side == "sell"
+
+	tradeUpdate := map[string]interface{}{
+		"type":           "trade_update",
+		"symbol":         key,
+		"exchange":       models.ExchangeOKX,
+		"price":          price,
+		"quantity":       quantity,
+		"is_buyer_maker": isBuyerMaker,
+		"trade_time":     tradeTime,
+		"timestamp":      time.Now().UnixMilli(),
+	}
+
+	if tradeID, err := strconv.ParseInt(data.TradeID, 10, 64); err == nil {
+		record := models.TradeRecord{
+			Symbol:       key,
+			TradeID:      tradeID,
+			Price:        price,
+			Quantity:     quantity,
+			IsBuyerMaker: isBuyerMaker,
+			TradeTime:    time.UnixMilli(tradeTime),
+		}
+		if recorder := s.tradeRecorder.Load(); recorder != nil {
+			recorder.record(record)
+		}
+		s.events.emitTrade(record)
+	}
+
+	s.hub.QueueVolumeProfileTrade(key, price, quantity, isBuyerMaker, tradeTime)
+
+	tradeContext := s.tradeEnricher.Update(key, price, quantity, isBuyerMaker, tradeTime)
+	s.hub.BroadcastEnrichedTradeUpdate(tradeUpdate, tradeContext)
+}
+
+// processBook applies a book snapshot or update and broadcasts the changed levels in base currency
+func (s *OKXStream) processBook(instID string, data okxBookData, snapshot bool) {
+	key := okxSymbolKey(instID)
+	ts, _ := strconv.ParseInt(data.Ts, 10, 64)
+
+	s.mu.Lock()
+	bids := s.baseLevels(instID, data.Bids)
+	asks := s.baseLevels(instID, data.Asks)
+	book, exists := s.books[instID]
+	if snapshot || !exists {
+		book = &localBook{bids: make(map[string]string), asks: make(map[string]string)}
+		s.books[instID] = book
+	}
+	applyBookLevels(book.bids, bids)
+	applyBookLevels(book.asks, asks)
+
+	var full *models.DepthUpdate
+	if s.depthRecorder.Load() != nil {
+		full = &models.DepthUpdate{
+			Symbol:   key,
+			Bids:     sortedBookLevels(book.bids, true),
+			Asks:     sortedBookLevels(book.asks, false),
+			Snapshot: true,
+			Time:     time.UnixMilli(ts),
+		}
+	}
+	s.mu.Unlock()
+
+	// Depth snapshots sample the whole local book, not the update
+	if recorder := s.depthRecorder.Load(); recorder != nil && full != nil {
+		recorder.observe(*full)
+	}
+
+	s.events.emitDepth(models.DepthUpdate{
+		Symbol:   key,
+		Bids:     bids,
+		Asks:     asks,
+		Snapshot: snapshot,
+		Time:     time.UnixMilli(ts),
+	})
+
+	s.hub.BroadcastDepthUpdate(map[string]interface{}{
+		"type":      "depth_update",
+		"symbol":    key,
+		"exchange":  models.ExchangeOKX,
+		"bids":      bids,
+		"asks":      asks,
+		"snapshot":  snapshot,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// baseLevels converts OKX book levels to [price, base size] pairs; the caller holds s.mu
+func (s *OKXStream) baseLevels(instID string, levels [][]string) [][]string {
+	converted := make([][]string, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		size := strconv.FormatFloat(s.baseSize(instID, level[1]), 'f', -1, 64)
+		converted = append(converted, []string{level[0], size})
+	}
+	return converted
+}
+
+// processLiquidation normalizes, stores and broadcasts the liquidations of a streamed instrument
+func (s *OKXStream) processLiquidation(data okxLiquidation) {
+	key := okxSymbolKey(data.InstID)
+	if !s.isStreamed(key) {
+		return
+	}
+
+	for _, detail := range data.Details {
+		price, err := strconv.ParseFloat(detail.Price, 64)
+		if err != nil {
+			continue
+		}
+		tradeTime, _ := strconv.ParseInt(detail.Ts, 10, 64)
+
+		s.mu.RLock()
+		quantity := s.baseSize(data.InstID, detail.Size)
+		s.mu.RUnlock()
+
+		// OKX reports the liquidation order side, matching Binance's forceOrder side
+		side := "SELL"
+		if detail.Side == "buy" {
+			side = "BUY"
+		}
+
+		event := models.LiquidationEvent{
+			Symbol:     key,
+			Side:       side,
+			Price:      price,
+			OrderPrice: price,
+			Quantity:   quantity,
+			Status:     "FILLED",
+			TradeTime:  time.UnixMilli(tradeTime),
+		}
+
+		// Keep the last 1000 liquidations per symbol
+		s.mu.Lock()
+		recordLiquidation(s.liquidations, event)
+		s.mu.Unlock()
+
+		publishLiquidation(s.hub, s.events, event)
+	}
+}
+
+// isStreamed reports whether a qualified symbol is one of the stream's symbols
+func (s *OKXStream) isStreamed(key string) bool {
+	for _, symbol := range s.GetConnectedSymbols() {
+		if symbol == key {
+			return true
+		}
+	}
+	return false
+}
+
+// processCandle broadcasts a candle and confirms closed bars
+// Entries are [ts, o, h, l, c, vol (contracts), volCcy (base), volCcyQuote, confirm]
+func (s *OKXStream) processCandle(instID, interval string, data []string) {
+	if len(data) < 9 {
+		return
+	}
+	start, err := strconv.ParseInt(data[0], 10, 64)
+	if err != nil {
+		return
+	}
+	duration, _ := models.IntervalDuration(interval)
+	end := start + duration.Milliseconds() - 1
+	closed := data[8] == "1"
+	key := okxSymbolKey(instID)
+
+	open := models.ParseFloat(data[1])
+	high := models.ParseFloat(data[2])
+	low := models.ParseFloat(data[3])
+	close := models.ParseFloat(data[4])
+	volume := models.ParseFloat(data[6])
+
+	s.hub.BroadcastKlineUpdate(map[string]interface{}{
+		"type":       "kline_update",
+		"symbol":     key,
+		"exchange":   models.ExchangeOKX,
+		"interval":   interval,
+		"open":       open,
+		"high":       high,
+		"low":        low,
+		"close":      close,
+		"volume":     volume,
+		"is_closed":  closed,
+		"start_time": start,
+		"end_time":   end,
+		"timestamp":  time.Now().UnixMilli(),
+	})
+
+	candle := LayoutCandle{
+		Symbol:    key,
+		Interval:  interval,
+		StartTime: start,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    volume,
+		IsClosed:  closed,
+	}
+	s.hub.QueueLayoutCandle(candle)
+	if interval == "1m" {
+		s.hub.QueueLiteKline(candle)
+	}
+
+	if closed {
+		// OKX candles carry no trade count or taker buy volume
+		s.barClose.confirmStream(models.KlineEvent{
+			Symbol:         key,
+			Interval:       interval,
+			OpenTime:       time.UnixMilli(start),
+			CloseTime:      time.UnixMilli(end),
+			Open:           data[1],
+			High:           data[2],
+			Low:            data[3],
+			Close:          data[4],
+			Volume:         data[6],
+			QuoteVolume:    data[7],
+			BuyVolume:      "0",
+			BuyQuoteVolume: "0",
+			Closed:         true,
+		})
+	}
+}
+
+// BarCloses returns the bar close scheduler for registering handlers and configuring its clock
+func (s *OKXStream) BarCloses() *BarCloseScheduler {
+	return s.barClose
+}
+
+// Events returns the stream's normalized market events for registering handlers
+func (s *OKXStream) Events() *MarketEvents {
+	return s.events
+}
+
+// GetConnectedSymbols returns the qualified symbols being streamed
+func (s *OKXStream) GetConnectedSymbols() []string {
+	symbols := make([]string, len(s.symbols))
+	for i, symbol := range s.symbols {
+		symbols[i] = okxSymbolKey(okx.InstrumentID(symbol))
+	}
+	return symbols
+}
+
+// GetLastPrice returns the last known price for a qualified symbol
+func (s *OKXStream) GetLastPrice(symbol string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	price, exists := s.lastPrices[symbol]
+	return price, exists
+}
+
+// GetRecentLiquidations returns recent liquidations for a qualified symbol
+func (s *OKXStream) GetRecentLiquidations(symbol string, limit int) []models.LiquidationEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return lastLiquidations(s.liquidations[symbol], limit)
+}
+
+// GetStreamStats returns statistics about the OKX stream
+func (s *OKXStream) GetStreamStats() map[string]interface{} {
+	s.mu.RLock()
+	liquidationCounts := make(map[string]int, len(s.liquidations))
+	for symbol, liquidations := range s.liquidations {
+		liquidationCounts[symbol] = len(liquidations)
+	}
+	connections := make(map[string]interface{}, len(s.conns))
+	for _, c := range s.conns {
+		connection := map[string]interface{}{
+			"connected":  c.conn != nil,
+			"reconnects": c.reconnects,
+			"channels":   len(c.args),
+		}
+		if !c.connectedAt.IsZero() {
+			connection["connected_at"] = c.connectedAt.UnixMilli()
+		}
+		connections[c.name] = connection
+	}
+	contractValues := make(map[string]float64, len(s.contractValues))
+	for instID, value := range s.contractValues {
+		contractValues[instID] = value
+	}
+	stats := map[string]interface{}{
+		"exchange":           models.ExchangeOKX,
+		"connected_symbols":  len(s.symbols),
+		"symbols":            s.GetConnectedSymbols(),
+		"price_data_count":   len(s.lastPrices),
+		"book_count":         len(s.books),
+		"is_running":         s.isRunning,
+		"connections":        connections,
+		"contract_values":    contractValues,
+		"liquidation_counts": liquidationCounts,
+		"stream_types": []string{
+			"tickers", "trades", "books", "mark-price", "index-tickers", "funding-rate",
+			"liquidation-orders", "candle1m", "candle5m", "candle15m",
+		},
+	}
+	s.mu.RUnlock()
+
+	if last := s.lastMessage.Load(); last > 0 {
+		stats["last_message_at"] = last
+	}
+	stats["bar_close"] = s.barClose.stats()
+	if recorder := s.tradeRecorder.Load(); recorder != nil {
+		stats["trade_persistence"] = recorder.stats()
+	}
+	if recorder := s.depthRecorder.Load(); recorder != nil {
+		stats["depth_persistence"] = recorder.stats()
+	}
+	return stats
+}
@@ -0,0 +1,564 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Replay speeds, as multiples of real time
+	replayMinSpeed = 1
+	replayMaxSpeed = 60
+
+	// replayTick is how often the replay clock advances and due bars and trades are sent
+	replayTick = 100 * time.Millisecond
+
+	// replayLoadTimeout caps loading a replay's candles and trades
+	replayLoadTimeout = 15 * time.Second
+
+	// replayMaxTrades caps the trades loaded for one replay
+	replayMaxTrades = 200000
+
+	// replayMaxBatch caps the bars and trades in one replay_update; the rest follow on later ticks
+	replayMaxBatch = 1000
+
+	// replayControlBuffer is how many control messages may await the replay goroutine
+	replayControlBuffer = 8
+)
+
+// Replay states reported in "replay_status" messages
+const (
+	ReplayLoading  = "loading"
+	ReplayPlaying  = "playing"
+	ReplayPaused   = "paused"
+	ReplayFinished = "finished" // Every bar and trade in the range was sent
+	ReplayStopped  = "stopped"  // Stopped by the client or replaced by a new replay
+	ReplayFailed   = "failed"   // Loading failed; Message says why
+)
+
+// ReplayCandleSource serves the stored candles of a replay range
+type ReplayCandleSource interface {
+	GetCandleRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.Candle, error)
+}
+
+// ReplayTradeSource serves the stored trades of a replay range
+type ReplayTradeSource interface {
+	GetByTimeRange(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]models.TradeRecord, error)
+}
+
+// ReplayOptions configures a bar replay started with a "replay_start" message
+type ReplayOptions struct {
+	Interval string           `json:"interval"`
+	Start    models.QueryTime `json:"start"`
+	End      models.QueryTime `json:"end"`    // Now when empty
+	Speed    float64          `json:"speed"`  // 1 (real time) to 60; 1 when empty
+	Trades   bool             `json:"trades"` // Also replay stored trades, building each bar as they arrive
+}
+
+// ReplayKline is a replayed bar; forming bars built from trades have IsClosed false
+type ReplayKline struct {
+	StartTime int64   `json:"start_time"`
+	EndTime   int64   `json:"end_time"`
+	Open      float64 `json:"open"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	Volume    float64 `json:"volume"`
+	IsClosed  bool    `json:"is_closed"`
+}
+
+// ReplayTrade is a replayed trade
+type ReplayTrade struct {
+	Price        float64 `json:"price"`
+	Quantity     float64 `json:"quantity"`
+	IsBuyerMaker bool    `json:"is_buyer_maker"`
+	TradeTime    int64   `json:"trade_time"`
+}
+
+// ReplayUpdate carries the bars and trades that became due since the previous update, oldest first
+type ReplayUpdate struct {
+	Type        string        `json:"type"` // "replay_update"
+	ReplayID    string        `json:"replay_id"`
+	Symbol      string        `json:"symbol"`
+	Interval    string        `json:"interval"`
+	ReplayTime  int64         `json:"replay_time"` // Position of the replay clock
+	Klines      []ReplayKline `json:"klines,omitempty"`
+	Trades      []ReplayTrade `json:"trades,omitempty"`
+	ProgressPct float64       `json:"progress_pct"`
+	Timestamp   int64         `json:"timestamp"`
+}
+
+// ReplayStatus reports a replay's state after it starts, changes or ends
+type ReplayStatus struct {
+	Type        string  `json:"type"` // "replay_status"
+	ReplayID    string  `json:"replay_id"`
+	Symbol      string  `json:"symbol"`
+	Interval    string  `json:"interval"`
+	State       string  `json:"state"`
+	Speed       float64 `json:"speed"`
+	Start       int64   `json:"start"`
+	End         int64   `json:"end"`
+	ReplayTime  int64   `json:"replay_time"`
+	Candles     int     `json:"candles"`
+	Trades      int     `json:"trades"`
+	ProgressPct float64 `json:"progress_pct"`
+	Message     string  `json:"message,omitempty"`
+	Timestamp   int64   `json:"timestamp"`
+}
+
+// replayCommand is a control message for a running replay
+type replayCommand struct {
+	action string // "pause", "resume", "speed", "step" or "stop"
+	speed  float64
+}
+
+// replaySession streams one client's replay from its own goroutine
+type replaySession struct {
+	id       string
+	symbol   string
+	interval string
+	start    time.Time
+	end      time.Time
+	trades   bool
+
+	control  chan replayCommand
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// Owned by the replay goroutine
+	speed      float64
+	playing    bool
+	clock      time.Time
+	candles    []models.Candle
+	records    []models.TradeRecord
+	nextCandle int
+	nextTrade  int
+	forming    *ReplayKline // Bar being built from trades, when replaying trades
+}
+
+// SetReplaySources enables bar replay from stored candles and trades
+func (h *Hub) SetReplaySources(candles ReplayCandleSource, trades ReplayTradeSource) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.replayCandles = candles
+	h.replayTrades = trades
+}
+
+// getReplaySources returns the replay sources, nil when replay is not configured
+func (h *Hub) getReplaySources() (ReplayCandleSource, ReplayTradeSource) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.replayCandles, h.replayTrades
+}
+
+// handleReplayMessage handles the replay_* client messages
+func (c *Client) handleReplayMessage(message ClientMessage) {
+	if message.Type == "replay_start" {
+		c.startReplay(message)
+		return
+	}
+
+	session := c.replay.Load()
+	if session == nil {
+		c.sendReplayError("No replay is running")
+		return
+	}
+
+	command := replayCommand{action: strings.TrimPrefix(message.Type, "replay_")}
+	if command.action == "speed" {
+		if message.Replay == nil || message.Replay.Speed < replayMinSpeed || message.Replay.Speed > replayMaxSpeed {
+			c.sendReplayError(fmt.Sprintf("replay.speed must be between %d and %d", replayMinSpeed, replayMaxSpeed))
+			return
+		}
+		command.speed = message.Replay.Speed
+	}
+	if command.action == "stop" {
+		c.stopReplay()
+		return
+	}
+
+	select {
+	case session.control <- command:
+	case <-session.done:
+	default:
+		c.sendReplayError("Too many replay commands, slow down")
+	}
+}
+
+// startReplay validates a replay_start message and starts the replay, stopping any running one
+func (c *Client) startReplay(message ClientMessage) {
+	candleSource, tradeSource := c.hub.getReplaySources()
+	if candleSource == nil || tradeSource == nil {
+		c.sendReplayError("Bar replay is not available")
+		return
+	}
+
+	options := message.Replay
+	if options == nil {
+		c.sendReplayError("replay options are required")
+		return
+	}
+	if message.Symbol == "" {
+		c.sendReplayError("symbol is required")
+		return
+	}
+	symbol, resolved, err := c.hub.resolveSymbol(message.Symbol, message.Exchange)
+	if err != nil {
+		c.sendReplayError(err.Error())
+		return
+	}
+	if !resolved && message.Exchange != "" {
+		symbol = models.QualifySymbol(message.Exchange, symbol)
+	}
+	symbol = strings.ToUpper(symbol)
+	if !models.IsValidInterval(options.Interval) {
+		c.sendReplayError("replay.interval must be one of " + strings.Join(models.SupportedIntervalNames(), ", "))
+		return
+	}
+	if options.Start.IsZero() {
+		c.sendReplayError("replay.start is required")
+		return
+	}
+	end := options.End.Time
+	if end.IsZero() || end.After(time.Now()) {
+		end = time.Now().UTC()
+	}
+	if !options.Start.Before(end) {
+		c.sendReplayError("replay.start must be before replay.end")
+		return
+	}
+	speed := options.Speed
+	if speed == 0 {
+		speed = replayMinSpeed
+	}
+	if speed < replayMinSpeed || speed > replayMaxSpeed {
+		c.sendReplayError(fmt.Sprintf("replay.speed must be between %d and %d", replayMinSpeed, replayMaxSpeed))
+		return
+	}
+
+	session := &replaySession{
+		id:       uuid.New().String()[:8],
+		symbol:   symbol,
+		interval: options.Interval,
+		start:    options.Start.UTC(),
+		end:      end.UTC(),
+		trades:   options.Trades,
+		control:  make(chan replayCommand, replayControlBuffer),
+		done:     make(chan struct{}),
+		speed:    speed,
+		playing:  true,
+		clock:    options.Start.UTC(),
+	}
+	if previous := c.replay.Swap(session); previous != nil {
+		previous.stop()
+	}
+
+	go session.run(c, candleSource, tradeSource)
+}
+
+// stopReplay stops the client's replay, if any
+func (c *Client) stopReplay() {
+	if session := c.replay.Swap(nil); session != nil {
+		session.stop()
+	}
+}
+
+// sendReplayError rejects a replay message
+func (c *Client) sendReplayError(message string) {
+	c.sendMessage(map[string]interface{}{
+		"type":      "error",
+		"code":      "replay_invalid",
+		"message":   message,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// stop ends the replay goroutine; safe to call more than once
+func (s *replaySession) stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+// run loads the replay range and streams it to the client until it finishes, is stopped or the
+// client disconnects
+func (s *replaySession) run(c *Client, candleSource ReplayCandleSource, tradeSource ReplayTradeSource) {
+	defer c.replay.CompareAndSwap(s, nil)
+
+	s.sendStatus(c, ReplayLoading, "")
+	if err := s.load(candleSource, tradeSource); err != nil {
+		s.sendStatus(c, ReplayFailed, err.Error())
+		return
+	}
+	select {
+	case <-s.done:
+		s.sendStatus(c, ReplayStopped, "")
+		return
+	default:
+	}
+	s.sendStatus(c, ReplayPlaying, "")
+
+	ticker := time.NewTicker(replayTick)
+	defer ticker.Stop()
+	last := time.Now()
+
+	for {
+		select {
+		case <-s.done:
+			s.sendStatus(c, ReplayStopped, "")
+			return
+
+		case command := <-s.control:
+			switch command.action {
+			case "pause":
+				s.playing = false
+			case "resume":
+				s.playing = true
+			case "speed":
+				s.speed = command.speed
+			case "step":
+				// Move a paused replay to the close of the next bar
+				s.playing = false
+				if s.nextCandle < len(s.candles) {
+					s.clock = s.candles[s.nextCandle].CloseTime
+				}
+			default:
+				c.sendReplayError("Unknown replay command: replay_" + command.action)
+				continue
+			}
+			state := ReplayPaused
+			if s.playing {
+				state = ReplayPlaying
+			}
+			s.sendStatus(c, state, "")
+
+		case now := <-ticker.C:
+			if s.playing {
+				s.clock = s.clock.Add(time.Duration(float64(now.Sub(last)) * s.speed))
+			}
+			last = now
+
+			connected := s.emit(c)
+			if !connected {
+				return
+			}
+			if s.nextCandle >= len(s.candles) && s.nextTrade >= len(s.records) {
+				s.clock = s.end
+				s.sendStatus(c, ReplayFinished, "")
+				return
+			}
+		}
+	}
+}
+
+// load reads the replay's candles, and trades when requested
+func (s *replaySession) load(candleSource ReplayCandleSource, tradeSource ReplayTradeSource) error {
+	ctx, cancel := context.WithTimeout(context.Background(), replayLoadTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	candles, err := candleSource.GetCandleRange(ctx, s.symbol, s.interval, s.start, s.end)
+	if err != nil {
+		return fmt.Errorf("loading candles: %w", err)
+	}
+	if len(candles) == 0 {
+		return fmt.Errorf("no %s %s candles between start and end", s.symbol, s.interval)
+	}
+	s.candles = candles
+
+	if s.trades {
+		records, err := tradeSource.GetByTimeRange(ctx, s.symbol, candles[0].OpenTime, candles[len(candles)-1].CloseTime, replayMaxTrades+1)
+		if err != nil {
+			return fmt.Errorf("loading trades: %w", err)
+		}
+		if len(records) > replayMaxTrades {
+			return fmt.Errorf("more than %d trades in range, narrow it or replay bars only", replayMaxTrades)
+		}
+		s.records = records
+	}
+
+	if s.clock.Before(candles[0].OpenTime) {
+		s.clock = candles[0].OpenTime
+	}
+	return nil
+}
+
+// emit sends the bars and trades due at the replay clock in one update
+// Nothing is consumed when the client's queue is full, so a slow client falls behind the
+// clock instead of losing bars. It returns false once the client has disconnected
+func (s *replaySession) emit(c *Client) bool {
+	update := ReplayUpdate{
+		Type:     "replay_update",
+		ReplayID: s.id,
+		Symbol:   s.symbol,
+		Interval: s.interval,
+	}
+	nextCandle, nextTrade := s.nextCandle, s.nextTrade
+	var forming *ReplayKline
+	if s.forming != nil {
+		copied := *s.forming
+		forming = &copied
+	}
+
+batch:
+	for len(update.Klines)+len(update.Trades) < replayMaxBatch {
+		candleDue := nextCandle < len(s.candles) && !s.candles[nextCandle].CloseTime.After(s.clock)
+		tradeDue := nextTrade < len(s.records) && !s.records[nextTrade].TradeTime.After(s.clock)
+		if tradeDue && candleDue && s.records[nextTrade].TradeTime.After(s.candles[nextCandle].CloseTime) {
+			tradeDue = false
+		}
+
+		switch {
+		case tradeDue:
+			trade := s.records[nextTrade]
+			nextTrade++
+			update.Trades = append(update.Trades, ReplayTrade{
+				Price:        trade.Price,
+				Quantity:     trade.Quantity,
+				IsBuyerMaker: trade.IsBuyerMaker,
+				TradeTime:    trade.TradeTime.UnixMilli(),
+			})
+			forming = s.buildForming(forming, nextCandle, trade)
+
+		case candleDue:
+			candle := s.candles[nextCandle]
+			nextCandle++
+			update.Klines = append(update.Klines, ReplayKline{
+				StartTime: candle.OpenTime.UnixMilli(),
+				EndTime:   candle.CloseTime.UnixMilli(),
+				Open:      models.ParseFloat(candle.Open),
+				High:      models.ParseFloat(candle.High),
+				Low:       models.ParseFloat(candle.Low),
+				Close:     models.ParseFloat(candle.Close),
+				Volume:    models.ParseFloat(candle.Volume),
+				IsClosed:  true,
+			})
+			forming = nil
+
+		default:
+			break batch
+		}
+	}
+
+	if len(update.Klines) == 0 && len(update.Trades) == 0 {
+		return c.hub.isConnected(c)
+	}
+	if forming != nil && len(update.Trades) > 0 {
+		update.Klines = append(update.Klines, *forming)
+	}
+	update.ReplayTime = s.clock.UnixMilli()
+	update.ProgressPct = s.progress()
+	update.Timestamp = time.Now().UnixMilli()
+
+	message, err := json.Marshal(update)
+	if err != nil {
+		return true
+	}
+	sent, connected := c.hub.trySend(c, message)
+	if sent {
+		s.nextCandle, s.nextTrade, s.forming = nextCandle, nextTrade, forming
+	}
+	return connected
+}
+
+// buildForming adds a trade to the forming bar of the candle at index
+func (s *replaySession) buildForming(forming *ReplayKline, index int, trade models.TradeRecord) *ReplayKline {
+	if index >= len(s.candles) || trade.TradeTime.Before(s.candles[index].OpenTime) {
+		return forming
+	}
+	if forming == nil {
+		candle := s.candles[index]
+		return &ReplayKline{
+			StartTime: candle.OpenTime.UnixMilli(),
+			EndTime:   candle.CloseTime.UnixMilli(),
+			Open:      trade.Price,
+			High:      trade.Price,
+			Low:       trade.Price,
+			Close:     trade.Price,
+			Volume:    trade.Quantity,
+		}
+	}
+	if trade.Price > forming.High {
+		forming.High = trade.Price
+	}
+	if trade.Price < forming.Low {
+		forming.Low = trade.Price
+	}
+	forming.Close = trade.Price
+	forming.Volume += trade.Quantity
+	return forming
+}
+
+// progress returns how far the replay clock is through the range, in percent
+func (s *replaySession) progress() float64 {
+	span := s.end.Sub(s.start)
+	if span <= 0 {
+		return 100
+	}
+	pct := float64(s.clock.Sub(s.start)) / float64(span) * 100
+	if pct < 0 {
+		return 0
+	}
+	if pct > 100 {
+		return 100
+	}
+	return pct
+}
+
+// sendStatus tells the client the replay's state; dropped when the client's queue is full
+func (s *replaySession) sendStatus(c *Client, state, message string) {
+	status, err := json.Marshal(ReplayStatus{
+		Type:        "replay_status",
+		ReplayID:    s.id,
+		Symbol:      s.symbol,
+		Interval:    s.interval,
+		State:       state,
+		Speed:       s.speed,
+		Start:       s.start.UnixMilli(),
+		End:         s.end.UnixMilli(),
+		ReplayTime:  s.clock.UnixMilli(),
+		Candles:     len(s.candles),
+		Trades:      len(s.records),
+		ProgressPct: s.progress(),
+		Message:     message,
+		Timestamp:   time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return
+	}
+	c.hub.trySend(c, status)
+}
+
+// trySend queues a message for a client without blocking or closing it when its queue is full
+// It reports whether the message was queued and whether the client is still connected
+func (h *Hub) trySend(client *Client, message []byte) (sent, connected bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if !h.clients[client] {
+		return false, false
+	}
+	select {
+	case client.send <- message:
+		return true, true
+	default:
+		return false, true
+	}
+}
+
+// isConnected reports whether a client is still registered
+func (h *Hub) isConnected(client *Client) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.clients[client]
+}
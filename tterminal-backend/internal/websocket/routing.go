@@ -0,0 +1,191 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"tterminal-backend/internal/transport"
+)
+
+// peerLookupTimeout bounds finding peers for an overload advisory
+const peerLookupTimeout = 5 * time.Second
+
+// Reasons for a "reconnect_to" advisory
+const (
+	ReconnectDraining   = "draining"   // The instance is restarting; reconnecting is required
+	ReconnectOverloaded = "overloaded" // The instance is saturated; reconnecting is optional
+)
+
+// PeerSource returns the healthy peers a client may reconnect to
+type PeerSource func(ctx context.Context) []transport.ReconnectPeer
+
+// ReconnectAdvisory tells clients which instances to reconnect to, best first
+type ReconnectAdvisory struct {
+	Type            string                    `json:"type"`   // Always "reconnect_to"
+	Reason          string                    `json:"reason"` // ReconnectDraining or ReconnectOverloaded
+	From            transport.RoutingHint     `json:"from"`
+	Peers           []transport.ReconnectPeer `json:"peers"`    // Empty means reconnect through the load balancer
+	Required        bool                      `json:"required"` // The connection is closed after reconnect_within_ms
+	ReconnectWithin int64                     `json:"reconnect_within_ms"`
+	Timestamp       int64                     `json:"timestamp"`
+}
+
+// hubRouting holds the instance's identity and where its peers are found
+type hubRouting struct {
+	mu         sync.RWMutex
+	instanceID string
+	region     string
+	peers      PeerSource
+}
+
+// SetRouting sets the instance ID and region announced to clients, and the peers offered in
+// "reconnect_to" advisories (nil offers none while overloaded)
+func (h *Hub) SetRouting(instanceID, region string, peers PeerSource) {
+	h.routing.mu.Lock()
+	defer h.routing.mu.Unlock()
+	h.routing.instanceID = instanceID
+	h.routing.region = region
+	h.routing.peers = peers
+}
+
+// GetRoutingHint returns the instance's identity and current load
+func (h *Hub) GetRoutingHint() transport.RoutingHint {
+	h.routing.mu.RLock()
+	hint := transport.RoutingHint{InstanceID: h.routing.instanceID, Region: h.routing.region}
+	h.routing.mu.RUnlock()
+
+	stats := h.GetLoadStats()
+	hint.Load = stats.Level
+	hint.LoadPct = loadPct(stats)
+	hint.Clients = stats.Clients
+	hint.Draining = h.IsDraining()
+	return hint
+}
+
+// loadPct returns the highest share of a configured threshold the measured load uses
+func loadPct(stats LoadStats) int {
+	pct := 0.0
+	for _, check := range []struct {
+		value float64
+		limit int
+	}{
+		{float64(stats.BytesPerSecond), stats.Thresholds.MaxBytesPerSecond},
+		{stats.QueuePct, stats.Thresholds.MaxQueuePct},
+		{float64(stats.Clients), stats.Thresholds.MaxClients},
+	} {
+		if check.limit > 0 {
+			pct = math.Max(pct, check.value/float64(check.limit)*100)
+		}
+	}
+	return int(math.Round(pct))
+}
+
+// RankPeers orders peers best first: same region, then not saturated, then least loaded
+// Peers not reporting a routing hint come last; ties keep their configured order
+func RankPeers(peers []transport.ReconnectPeer, region string) []transport.ReconnectPeer {
+	ranked := append([]transport.ReconnectPeer{}, peers...)
+	rank := func(peer transport.ReconnectPeer) (int, int) {
+		if peer.Instance == nil {
+			return 3, 0
+		}
+		tier := 0
+		if region != "" && peer.Instance.Region != region {
+			tier++
+		}
+		if peer.Instance.Load == LoadSaturated {
+			tier++
+		}
+		return tier, peer.Instance.LoadPct
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		tierI, pctI := rank(ranked[i])
+		tierJ, pctJ := rank(ranked[j])
+		if tierI != tierJ {
+			return tierI < tierJ
+		}
+		return pctI < pctJ
+	})
+	return ranked
+}
+
+// reconnectAdvisory builds a "reconnect_to" advisory with the peers ranked for this instance
+func (h *Hub) reconnectAdvisory(reason string, peers []transport.ReconnectPeer, within time.Duration) ([]byte, error) {
+	from := h.GetRoutingHint()
+	if reason == ReconnectDraining {
+		from.Draining = true
+	}
+	ranked := RankPeers(peers, from.Region)
+	if ranked == nil {
+		ranked = []transport.ReconnectPeer{}
+	}
+	return json.Marshal(&ReconnectAdvisory{
+		Type:            "reconnect_to",
+		Reason:          reason,
+		From:            from,
+		Peers:           ranked,
+		Required:        reason == ReconnectDraining,
+		ReconnectWithin: within.Milliseconds(),
+		Timestamp:       time.Now().UnixMilli(),
+	})
+}
+
+// adviseOverloadReconnect asks the most recently connected share of regular clients to move to a
+// peer that is not saturated, spreading reconnects over the load hold. Long-standing clients stay
+// so the hub sheds load without dropping everyone's state
+func (h *Hub) adviseOverloadReconnect(pct int) {
+	h.routing.mu.RLock()
+	source := h.routing.peers
+	h.routing.mu.RUnlock()
+	if source == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), peerLookupTimeout)
+	defer cancel()
+	var peers []transport.ReconnectPeer
+	for _, peer := range source(ctx) {
+		if peer.Instance != nil && (peer.Instance.Load == LoadSaturated || peer.Instance.Draining) {
+			continue
+		}
+		peers = append(peers, peer)
+	}
+	if len(peers) == 0 {
+		log.Printf("Load saturated: no unsaturated peers to offer in reconnect advisories")
+		return
+	}
+	if h.GetLoadStats().Level != LoadSaturated || h.IsDraining() {
+		return
+	}
+
+	message, err := h.reconnectAdvisory(ReconnectOverloaded, peers, loadHold)
+	if err != nil {
+		log.Printf("Error marshaling reconnect advisory: %v", err)
+		return
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		if !client.lite {
+			clients = append(clients, client)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].bandwidth.connectedAt.After(clients[j].bandwidth.connectedAt)
+	})
+	advised := min(int(math.Ceil(float64(len(clients))*float64(pct)/100)), len(clients))
+	for _, client := range clients[:advised] {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+	log.Printf("Load saturated: advised %d of %d clients to reconnect to %d peers", advised, len(clients), len(peers))
+}
@@ -0,0 +1,307 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxRecordingDuration caps how long a single recording may run
+	maxRecordingDuration = 60 * time.Minute
+
+	// recordingFlushInterval is how often captured messages are appended to the store
+	recordingFlushInterval = time.Second
+
+	// recordingBufferSize is how many messages may await a flush before new ones are dropped
+	recordingBufferSize = 4096
+)
+
+// Errors returned when a recording cannot be started or found
+var (
+	ErrRecordingClientNotFound = errors.New("client not connected")
+	ErrRecordingNotAllowed     = errors.New("client did not opt in to session recording")
+	ErrRecordingActive         = errors.New("client is already being recorded")
+	ErrRecordingNotFound       = errors.New("recording not found")
+	ErrRecordingUnavailable    = errors.New("session recording is not configured")
+)
+
+// RecordingStore archives session recordings for support retrieval
+type RecordingStore interface {
+	SaveRecording(ctx context.Context, recording *models.SessionRecording) error
+	AppendMessages(ctx context.Context, recordingID string, messages []models.RecordedMessage) error
+	GetRecording(ctx context.Context, recordingID string) (*models.SessionRecording, error)
+	GetMessages(ctx context.Context, recordingID string, offset, limit int) ([]models.RecordedMessage, error)
+}
+
+// sessionRecorder captures the messages written to one client until it is stopped or expires
+type sessionRecorder struct {
+	store    RecordingStore
+	messages chan models.RecordedMessage
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu        sync.Mutex
+	recording models.SessionRecording
+}
+
+// SetRecordingStore enables session recording for clients that connect with allow_recording=true
+func (h *Hub) SetRecordingStore(store RecordingStore) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.recordingStore = store
+}
+
+// StartRecording records every message sent to a connected client for the given duration
+// Only clients that opted in when connecting can be recorded; the client is told when it starts
+func (h *Hub) StartRecording(clientID string, duration time.Duration) (*models.SessionRecording, error) {
+	if duration <= 0 || duration > maxRecordingDuration {
+		duration = maxRecordingDuration
+	}
+
+	h.mutex.Lock()
+	store := h.recordingStore
+	if store == nil {
+		h.mutex.Unlock()
+		return nil, ErrRecordingUnavailable
+	}
+	var client *Client
+	for candidate := range h.clients {
+		if candidate.id == clientID {
+			client = candidate
+			break
+		}
+	}
+	if client == nil {
+		h.mutex.Unlock()
+		return nil, ErrRecordingClientNotFound
+	}
+	if !client.allowRecording {
+		h.mutex.Unlock()
+		return nil, ErrRecordingNotAllowed
+	}
+
+	now := time.Now().UTC()
+	recorder := &sessionRecorder{
+		store:    store,
+		messages: make(chan models.RecordedMessage, recordingBufferSize),
+		stop:     make(chan struct{}),
+		recording: models.SessionRecording{
+			ID:        uuid.New().String(),
+			ClientID:  client.id,
+			UserID:    client.userID,
+			Status:    models.RecordingStatusActive,
+			StartedAt: now,
+			EndsAt:    now.Add(duration),
+		},
+	}
+	if !client.recorder.CompareAndSwap(nil, recorder) {
+		h.mutex.Unlock()
+		return nil, ErrRecordingActive
+	}
+	h.recordings[recorder.recording.ID] = recorder
+	h.mutex.Unlock()
+
+	recording := recorder.snapshot()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := store.SaveRecording(ctx, &recording); err != nil {
+		h.finishRecording(client, recorder)
+		return nil, err
+	}
+
+	go recorder.run(h, client)
+
+	h.sendToClient(client, map[string]interface{}{
+		"type":         "recording_started",
+		"recording_id": recording.ID,
+		"ends_at":      recording.EndsAt.UnixMilli(),
+		"timestamp":    now.UnixMilli(),
+	})
+
+	log.Printf("Recording session of client %s as %s until %s", client.id, recording.ID, recording.EndsAt.Format(time.RFC3339))
+	return &recording, nil
+}
+
+// StopRecording ends an active recording early
+func (h *Hub) StopRecording(recordingID string) error {
+	h.mutex.RLock()
+	recorder, exists := h.recordings[recordingID]
+	h.mutex.RUnlock()
+
+	if !exists {
+		return ErrRecordingNotFound
+	}
+	recorder.requestStop()
+	return nil
+}
+
+// GetRecording returns a recording's metadata and up to limit of its messages from offset
+// Active recordings report their live message count; messages appear once flushed
+func (h *Hub) GetRecording(ctx context.Context, recordingID string, offset, limit int) (*models.SessionRecordingPage, error) {
+	h.mutex.RLock()
+	store := h.recordingStore
+	recorder, active := h.recordings[recordingID]
+	h.mutex.RUnlock()
+
+	if store == nil {
+		return nil, ErrRecordingUnavailable
+	}
+
+	var recording *models.SessionRecording
+	if active {
+		snapshot := recorder.snapshot()
+		recording = &snapshot
+	} else {
+		stored, err := store.GetRecording(ctx, recordingID)
+		if err != nil {
+			return nil, err
+		}
+		if stored == nil {
+			return nil, ErrRecordingNotFound
+		}
+		recording = stored
+	}
+
+	messages, err := store.GetMessages(ctx, recordingID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SessionRecordingPage{
+		Recording: recording,
+		Messages:  messages,
+		Offset:    offset,
+		Limit:     limit,
+		HasMore:   offset+len(messages) < recording.MessageCount,
+	}, nil
+}
+
+// recordBatch captures messages about to be written to the client, if it is being recorded
+// Called from the client's write goroutine, so it never blocks: overflow is counted as dropped
+func (c *Client) recordBatch(messages [][]byte) {
+	recorder := c.recorder.Load()
+	if recorder == nil {
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	for _, message := range messages {
+		select {
+		case recorder.messages <- models.RecordedMessage{Time: now, Message: json.RawMessage(message)}:
+		default:
+			recorder.mu.Lock()
+			recorder.recording.Dropped++
+			recorder.mu.Unlock()
+		}
+	}
+}
+
+// stopRecording ends the client's recording when it disconnects
+func (c *Client) stopRecording() {
+	if recorder := c.recorder.Load(); recorder != nil {
+		recorder.requestStop()
+	}
+}
+
+// run flushes captured messages until the recording is stopped, expires or the client leaves
+func (r *sessionRecorder) run(h *Hub, client *Client) {
+	ticker := time.NewTicker(recordingFlushInterval)
+	defer ticker.Stop()
+
+	expiry := time.NewTimer(time.Until(r.snapshot().EndsAt))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.flush()
+		case <-expiry.C:
+			r.requestStop()
+		case <-r.stop:
+			h.finishRecording(client, r)
+			r.flush()
+			r.complete()
+			return
+		}
+	}
+}
+
+// requestStop signals the recorder to finish; safe to call more than once
+func (r *sessionRecorder) requestStop() {
+	r.stopOnce.Do(func() { close(r.stop) })
+}
+
+// flush appends the captured messages to the store
+func (r *sessionRecorder) flush() {
+	var batch []models.RecordedMessage
+drain:
+	for len(batch) < recordingBufferSize {
+		select {
+		case message := <-r.messages:
+			batch = append(batch, message)
+		default:
+			break drain
+		}
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.store.AppendMessages(ctx, r.snapshot().ID, batch); err != nil {
+		log.Printf("Failed to append %d recorded messages: %v", len(batch), err)
+		r.mu.Lock()
+		r.recording.Dropped += len(batch)
+		r.mu.Unlock()
+		return
+	}
+
+	r.mu.Lock()
+	r.recording.MessageCount += len(batch)
+	r.mu.Unlock()
+}
+
+// complete marks the recording finished and saves its final metadata
+func (r *sessionRecorder) complete() {
+	now := time.Now().UTC()
+	r.mu.Lock()
+	r.recording.Status = models.RecordingStatusCompleted
+	r.recording.StoppedAt = &now
+	recording := r.recording
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.store.SaveRecording(ctx, &recording); err != nil {
+		log.Printf("Failed to save recording %s: %v", recording.ID, err)
+		return
+	}
+	log.Printf("Recording %s of client %s completed (%d messages, %d dropped)", recording.ID, recording.ClientID, recording.MessageCount, recording.Dropped)
+}
+
+// snapshot returns a copy of the recording's current metadata
+func (r *sessionRecorder) snapshot() models.SessionRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recording
+}
+
+// finishRecording detaches a recorder from its client and the active recordings
+func (h *Hub) finishRecording(client *Client, recorder *sessionRecorder) {
+	client.recorder.CompareAndSwap(recorder, nil)
+
+	h.mutex.Lock()
+	delete(h.recordings, recorder.recording.ID)
+	h.mutex.Unlock()
+}
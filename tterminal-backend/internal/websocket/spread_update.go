@@ -0,0 +1,46 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+	"tterminal-backend/models"
+)
+
+// SpreadUpdate carries the latest spread of every monitored pair
+type SpreadUpdate struct {
+	Type      string                `json:"type"`    // Always "spread_update"
+	Channel   string                `json:"channel"` // Always "spreads"
+	Spreads   []models.SpreadSample `json:"spreads"`
+	Timestamp int64                 `json:"timestamp"`
+}
+
+// BroadcastSpreadUpdate sends a "spread_update" event to clients subscribed to the "spreads" channel
+func (h *Hub) BroadcastSpreadUpdate(spreads []models.SpreadSample) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients := h.channelSubscriptions[ChannelSpreads]
+	if len(clients) == 0 {
+		return
+	}
+
+	message, err := json.Marshal(&SpreadUpdate{
+		Type:      "spread_update",
+		Channel:   ChannelSpreads,
+		Spreads:   spreads,
+		Timestamp: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Error marshaling spread update: %v", err)
+		return
+	}
+
+	for client := range clients {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+}
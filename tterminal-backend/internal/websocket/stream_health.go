@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Stream names used in connection health
+const (
+	streamSpot    = "spot"
+	streamFutures = "futures"
+	streamCoinM   = "coinm" // COIN-margined futures, only when enabled
+)
+
+// maxReconnectEvents caps the reconnect history kept for the status page
+const maxReconnectEvents = 50
+
+// StreamConnection is the connection state of one Binance market data stream
+type StreamConnection struct {
+	Stream        string     `json:"stream"`
+	Connected     bool       `json:"connected"`
+	Since         *time.Time `json:"since,omitempty"` // When the current state started
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+}
+
+// connectionHealth tracks connection state, message times and reconnects of the upstream streams
+type connectionHealth struct {
+	lastSpotMessage    atomic.Int64 // Unix milliseconds
+	lastFuturesMessage atomic.Int64
+	lastCoinMMessage   atomic.Int64
+
+	mu        sync.Mutex
+	connected map[string]bool
+	since     map[string]time.Time
+	open      map[string]int // Index of each stream's unfinished reconnect event
+	events    []models.ReconnectEvent
+}
+
+// newConnectionHealth creates an empty connection tracker
+func newConnectionHealth() *connectionHealth {
+	return &connectionHealth{
+		connected: make(map[string]bool),
+		since:     make(map[string]time.Time),
+		open:      make(map[string]int),
+	}
+}
+
+// message records a message received on a stream
+func (h *connectionHealth) message(stream string) {
+	now := time.Now().UnixMilli()
+	switch stream {
+	case streamSpot:
+		h.lastSpotMessage.Store(now)
+	case streamCoinM:
+		h.lastCoinMMessage.Store(now)
+	default:
+		h.lastFuturesMessage.Store(now)
+	}
+}
+
+// connect marks a stream connected, completing its reconnect event if one is open
+func (h *connectionHealth) connect(stream string) {
+	now := time.Now().UTC()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.connected[stream] = true
+	h.since[stream] = now
+	if i, exists := h.open[stream]; exists {
+		event := &h.events[i]
+		event.ReconnectedAt = &now
+		event.DowntimeMs = now.Sub(event.DisconnectedAt).Milliseconds()
+		delete(h.open, stream)
+	}
+}
+
+// disconnect marks a stream lost and opens a reconnect event
+func (h *connectionHealth) disconnect(stream string) {
+	now := time.Now().UTC()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.connected[stream] = false
+	h.since[stream] = now
+	if _, exists := h.open[stream]; exists {
+		return
+	}
+
+	if len(h.events) >= maxReconnectEvents {
+		h.events = append(h.events[:0], h.events[1:]...)
+		for s, i := range h.open {
+			h.open[s] = i - 1
+		}
+	}
+	h.events = append(h.events, models.ReconnectEvent{Stream: stream, DisconnectedAt: now})
+	h.open[stream] = len(h.events) - 1
+}
+
+// attempt counts a reconnect attempt against the stream's open event
+func (h *connectionHealth) attempt(stream string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if i, exists := h.open[stream]; exists {
+		h.events[i].Attempts++
+	}
+}
+
+// stopped marks every stream disconnected after a deliberate stop, without reconnect events
+func (h *connectionHealth) stopped() {
+	now := time.Now().UTC()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for stream := range h.connected {
+		h.connected[stream] = false
+		h.since[stream] = now
+	}
+}
+
+// StreamConnections returns the connection state of the spot and futures streams, and of the
+// COIN-margined futures stream and the backup connections when they are enabled
+func (bs *BinanceStream) StreamConnections() []StreamConnection {
+	streams := []string{streamSpot, streamFutures}
+	if len(bs.coinMSymbols) > 0 {
+		streams = append(streams, streamCoinM)
+	}
+
+	h := bs.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	connections := make([]StreamConnection, 0, len(streams))
+	for _, stream := range streams {
+		connection := StreamConnection{Stream: stream, Connected: h.connected[stream]}
+		if since, exists := h.since[stream]; exists {
+			connection.Since = &since
+		}
+
+		last := h.lastSpotMessage.Load()
+		switch stream {
+		case streamFutures:
+			last = h.lastFuturesMessage.Load()
+		case streamCoinM:
+			last = h.lastCoinMMessage.Load()
+		}
+		if last > 0 {
+			lastMessage := time.UnixMilli(last).UTC()
+			connection.LastMessageAt = &lastMessage
+		}
+		connections = append(connections, connection)
+	}
+	for _, backup := range bs.backups {
+		connection := StreamConnection{Stream: backup.stream, Connected: h.connected[backup.stream]}
+		if since, exists := h.since[backup.stream]; exists {
+			connection.Since = &since
+		}
+		if last := backup.lastMessage.Load(); last > 0 {
+			lastMessage := time.UnixMilli(last).UTC()
+			connection.LastMessageAt = &lastMessage
+		}
+		connections = append(connections, connection)
+	}
+	return connections
+}
+
+// RecentReconnects returns stream reconnect events that started after since, newest first
+func (bs *BinanceStream) RecentReconnects(since time.Time) []models.ReconnectEvent {
+	h := bs.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := []models.ReconnectEvent{}
+	for i := len(h.events) - 1; i >= 0; i-- {
+		if h.events[i].DisconnectedAt.Before(since) {
+			break
+		}
+		events = append(events, h.events[i])
+	}
+	return events
+}
@@ -0,0 +1,203 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"tterminal-backend/models"
+)
+
+// The builders below produce the trade, kline and liquidation messages the streams broadcast,
+// so archived stream captures match live messages field for field
+
+// TradeUpdateMessage builds a "trade_update" message
+func TradeUpdateMessage(symbol string, price, quantity float64, isBuyerMaker bool, tradeTime, timestamp int64) map[string]interface{} {
+	return map[string]interface{}{
+		"type":           "trade_update",
+		"symbol":         symbol,
+		"price":          price,
+		"quantity":       quantity,
+		"is_buyer_maker": isBuyerMaker,
+		"trade_time":     tradeTime,
+		"timestamp":      timestamp,
+	}
+}
+
+// KlineUpdateMessage builds a "kline_update" message; endTime is the bar's inclusive close time
+func KlineUpdateMessage(candle LayoutCandle, endTime, timestamp int64) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "kline_update",
+		"symbol":     candle.Symbol,
+		"interval":   candle.Interval,
+		"open":       candle.Open,
+		"high":       candle.High,
+		"low":        candle.Low,
+		"close":      candle.Close,
+		"volume":     candle.Volume,
+		"is_closed":  candle.IsClosed,
+		"start_time": candle.StartTime,
+		"end_time":   endTime,
+		"timestamp":  timestamp,
+	}
+}
+
+// LiquidationUpdateMessage builds a "liquidation_update" message from a normalized liquidation
+// Liquidations of other exchanges carry an "exchange" field
+func LiquidationUpdateMessage(event models.LiquidationEvent, timestamp int64) map[string]interface{} {
+	message := map[string]interface{}{
+		"type":         "liquidation_update",
+		"symbol":       event.Symbol,
+		"side":         event.Side,
+		"price":        event.Price,                                        // Fill price (actual liquidation price)
+		"order_price":  strconv.FormatFloat(event.OrderPrice, 'f', -1, 64), // Order price for reference
+		"quantity":     event.Quantity,
+		"trade_time":   event.TradeTime.UnixMilli(),
+		"timestamp":    timestamp,
+		"order_status": event.Status,
+	}
+	if exchange := models.SymbolExchange(event.Symbol); exchange != models.ExchangeBinance {
+		message["exchange"] = exchange
+	}
+	return message
+}
+
+// StreamMessages encodes stored data as the messages the streams broadcast
+// Implements transport.MessageEncoder for stream captures
+type StreamMessages struct{}
+
+// TradeMessage encodes a trade as a "trade_update" message
+func (StreamMessages) TradeMessage(trade models.TradeRecord, timestamp int64) ([]byte, error) {
+	return json.Marshal(TradeUpdateMessage(trade.Symbol, trade.Price, trade.Quantity, trade.IsBuyerMaker, trade.TradeTime.UnixMilli(), timestamp))
+}
+
+// KlineMessage encodes a candle as a "kline_update" message
+func (StreamMessages) KlineMessage(candle models.Candle, closed bool, timestamp int64) ([]byte, error) {
+	return json.Marshal(KlineUpdateMessage(LayoutCandle{
+		Symbol:    candle.Symbol,
+		Interval:  candle.Interval,
+		StartTime: candle.OpenTime.UnixMilli(),
+		Open:      models.ParseFloat(candle.Open),
+		High:      models.ParseFloat(candle.High),
+		Low:       models.ParseFloat(candle.Low),
+		Close:     models.ParseFloat(candle.Close),
+		Volume:    models.ParseFloat(candle.Volume),
+		IsClosed:  closed,
+	}, candle.CloseTime.UnixMilli(), timestamp))
+}
+
+// LiquidationMessage encodes a liquidation as a "liquidation_update" message
+func (StreamMessages) LiquidationMessage(event models.LiquidationEvent, timestamp int64) ([]byte, error) {
+	return json.Marshal(LiquidationUpdateMessage(event, timestamp))
+}
+
+// Event converts a Binance forceOrder to a normalized liquidation
+// The average price is the actual liquidation price; the order price is used when it is missing
+func (data *BinanceLiquidationData) Event() (models.LiquidationEvent, error) {
+	order := &data.LiquidationOrder
+	price, priceErr := strconv.ParseFloat(order.AveragePrice, 64)
+	orderPrice, err := strconv.ParseFloat(order.Price, 64)
+	switch {
+	case priceErr != nil && err != nil:
+		return models.LiquidationEvent{}, fmt.Errorf("invalid liquidation price: %w", err)
+	case priceErr != nil:
+		price = orderPrice
+	case err != nil:
+		orderPrice = price
+	}
+	quantity, err := strconv.ParseFloat(order.OriginalQuantity, 64)
+	if err != nil {
+		return models.LiquidationEvent{}, fmt.Errorf("invalid liquidation quantity: %w", err)
+	}
+
+	return models.LiquidationEvent{
+		Symbol:     order.Symbol,
+		Side:       order.Side,
+		Price:      price,
+		OrderPrice: orderPrice,
+		Quantity:   quantity,
+		Status:     order.OrderStatus,
+		TradeTime:  time.UnixMilli(order.TradeTime),
+	}, nil
+}
+
+// Event converts a Binance trade or aggregate trade to a normalized trade; aggregate trades are
+// the futures stream's, identified by their aggregate trade ID ("a")
+func (data *BinanceTradeData) Event() (models.TradeEvent, error) {
+	price, err := strconv.ParseFloat(data.Price, 64)
+	if err != nil {
+		return models.TradeEvent{}, fmt.Errorf("invalid trade price: %w", err)
+	}
+	quantity, err := strconv.ParseFloat(data.Quantity, 64)
+	if err != nil {
+		return models.TradeEvent{}, fmt.Errorf("invalid trade quantity: %w", err)
+	}
+
+	event := models.TradeEvent{
+		Symbol:       data.Symbol,
+		Market:       "spot",
+		TradeID:      data.TradeID,
+		Price:        price,
+		Quantity:     quantity,
+		IsBuyerMaker: data.IsBuyerMaker,
+		TradeTime:    time.UnixMilli(data.TradeTime),
+	}
+	if data.EventType == "aggTrade" {
+		event.Market = "futures"
+		event.TradeID = data.SellerOrderID
+	}
+	return event, nil
+}
+
+// Event converts a Binance mark price to a normalized mark price
+func (data *BinanceMarkPriceData) Event() (models.MarkPriceEvent, error) {
+	markPrice, err := strconv.ParseFloat(data.MarkPrice, 64)
+	if err != nil {
+		return models.MarkPriceEvent{}, fmt.Errorf("invalid mark price: %w", err)
+	}
+	fundingRate, err := strconv.ParseFloat(data.FundingRate, 64)
+	if err != nil {
+		return models.MarkPriceEvent{}, fmt.Errorf("invalid funding rate: %w", err)
+	}
+
+	return models.MarkPriceEvent{
+		Symbol:          data.Symbol,
+		MarkPrice:       markPrice,
+		IndexPrice:      models.ParseFloat(data.IndexPrice),
+		EstimatedPrice:  models.ParseFloat(data.EstimatedPrice),
+		FundingRate:     fundingRate,
+		NextFundingTime: time.UnixMilli(data.NextFundingTime),
+		Time:            time.UnixMilli(data.EventTime),
+	}, nil
+}
+
+// Event converts a Binance depth diff to a normalized book update
+func (data *BinanceDepthData) Event() models.DepthUpdate {
+	return models.DepthUpdate{
+		Symbol: data.Symbol,
+		Bids:   data.Bids,
+		Asks:   data.Asks,
+		Time:   time.UnixMilli(data.EventTime),
+	}
+}
+
+// Event converts a Binance kline to a normalized kline
+func (data *BinanceKlineData) Event() models.KlineEvent {
+	k := &data.Kline
+	return models.KlineEvent{
+		Symbol:         data.Symbol,
+		Interval:       k.Interval,
+		OpenTime:       time.UnixMilli(k.StartTime),
+		CloseTime:      time.UnixMilli(k.EndTime),
+		Open:           k.Open,
+		High:           k.High,
+		Low:            k.Low,
+		Close:          k.Close,
+		Volume:         k.Volume,
+		QuoteVolume:    k.QuoteVolume,
+		BuyVolume:      k.TakerBuyBaseVolume,
+		BuyQuoteVolume: k.TakerBuyQuoteVolume,
+		TradeCount:     k.TradeCount,
+		Closed:         k.IsClosed,
+	}
+}
@@ -0,0 +1,152 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+	"tterminal-backend/models"
+)
+
+// subscriptionPersistDelay debounces bursts of subscribe/unsubscribe messages into one write
+const subscriptionPersistDelay = 500 * time.Millisecond
+
+// SubscriptionStore persists each user's last active subscriptions across sessions
+// Subscriptions are keyed by the verified user of the client's access token, never by a user ID
+// the client sends, so no one can read or overwrite another user's subscriptions
+type SubscriptionStore interface {
+	Get(ctx context.Context, userID string) (*models.UserSubscriptions, error)
+	Save(ctx context.Context, subs *models.UserSubscriptions) error
+}
+
+// SetSubscriptionStore enables subscription persistence for clients that connect with an access token
+func (h *Hub) SetSubscriptionStore(store SubscriptionStore) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.subscriptionStore = store
+}
+
+// schedulePersist queues a save of the client's subscriptions, coalescing rapid changes
+// Only called from the client's read goroutine
+func (c *Client) schedulePersist() {
+	if c.userID == "" || c.hub.getSubscriptionStore() == nil {
+		return
+	}
+
+	if c.persistTimer != nil {
+		c.persistTimer.Stop()
+	}
+	c.persistTimer = time.AfterFunc(subscriptionPersistDelay, func() {
+		c.hub.persistSubscriptions(c)
+	})
+}
+
+// persistSubscriptions saves a snapshot of the client's current subscriptions
+func (h *Hub) persistSubscriptions(client *Client) {
+	store := h.getSubscriptionStore()
+	if store == nil || client.userID == "" {
+		return
+	}
+
+	h.mutex.RLock()
+	subs := &models.UserSubscriptions{
+		UserID:                 client.userID,
+		Symbols:                sortedKeys(client.symbols),
+		EnrichedSymbols:        sortedKeys(client.enrichedSymbols),
+		Channels:               sortedKeys(client.channels),
+		LiquidationMinNotional: client.liquidationMinNotional,
+	}
+	h.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := store.Save(ctx, subs); err != nil {
+		log.Printf("Failed to persist subscriptions for user %s: %v", client.userID, err)
+	}
+}
+
+// restoreSubscriptions re-applies a user's stored subscriptions to a newly connected client
+// Anonymous clients are told that restoring needs an access token
+func (h *Hub) restoreSubscriptions(client *Client) {
+	store := h.getSubscriptionStore()
+	if store == nil {
+		return
+	}
+	if client.userID == "" {
+		h.sendToClient(client, map[string]interface{}{
+			"type":      "error",
+			"message":   "Restoring subscriptions requires an access token",
+			"timestamp": time.Now().UnixMilli(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subs, err := store.Get(ctx, client.userID)
+	if err != nil {
+		log.Printf("Failed to load subscriptions for user %s: %v", client.userID, err)
+		h.sendToClient(client, map[string]interface{}{
+			"type":      "error",
+			"message":   "Failed to restore subscriptions",
+			"timestamp": time.Now().UnixMilli(),
+		})
+		return
+	}
+
+	symbols := []string{}
+	channels := []string{}
+	if subs != nil {
+		enriched := make(map[string]bool, len(subs.EnrichedSymbols))
+		for _, symbol := range subs.EnrichedSymbols {
+			enriched[symbol] = true
+		}
+
+		for _, symbol := range subs.Symbols {
+			if h.symbolLimitError(client, symbol) != nil {
+				continue
+			}
+			h.SubscribeSymbol(client, symbol)
+			h.SetTradeEnrichment(client, symbol, enriched[symbol])
+			symbols = append(symbols, symbol)
+		}
+
+		for _, channel := range subs.Channels {
+			if h.channelLimitError(client, channel) != nil || !h.SubscribeChannel(client, channel) {
+				continue
+			}
+			if channel == ChannelLiquidationsAll {
+				h.SetLiquidationMinNotional(client, subs.LiquidationMinNotional)
+			}
+			channels = append(channels, channel)
+		}
+	}
+
+	log.Printf("Client %s restored %d symbols and %d channels for user %s", client.id, len(symbols), len(channels), client.userID)
+
+	h.sendToClient(client, map[string]interface{}{
+		"type":      "resubscribed",
+		"symbols":   symbols,
+		"channels":  channels,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// getSubscriptionStore returns the configured subscription store, if any
+func (h *Hub) getSubscriptionStore() SubscriptionStore {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.subscriptionStore
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
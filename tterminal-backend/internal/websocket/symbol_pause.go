@@ -0,0 +1,71 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// SymbolPauseStatus tells clients subscribed to a symbol that its updates were paused (e.g. during
+// a data anomaly or delisting window) or resumed
+type SymbolPauseStatus struct {
+	Type      string `json:"type"` // Always "symbol_pause"
+	Symbol    string `json:"symbol"`
+	Paused    bool   `json:"paused"`
+	Reason    string `json:"reason,omitempty"`
+	Until     int64  `json:"until,omitempty"` // When the pause ends on its own (Unix milliseconds)
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SetSymbolPaused stops or resumes delivering a symbol's stream updates and broadcasts a
+// "symbol_pause" event to the clients subscribed to it. until is zero for pauses held until resumed
+func (h *Hub) SetSymbolPaused(symbol string, paused bool, reason string, until time.Time) {
+	status := &SymbolPauseStatus{
+		Type:      "symbol_pause",
+		Symbol:    symbol,
+		Paused:    paused,
+		Reason:    reason,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if paused && !until.IsZero() {
+		status.Until = until.UnixMilli()
+	}
+
+	message, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("Error marshaling symbol pause status: %v", err)
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// Copied on write so the broadcast paths read the set without locking
+	current := h.pausedSymbols.Load()
+	next := make(map[string]bool)
+	if current != nil {
+		for key := range *current {
+			next[key] = true
+		}
+	}
+	if paused {
+		next[symbol] = true
+	} else {
+		delete(next, symbol)
+	}
+	h.pausedSymbols.Store(&next)
+
+	for client := range h.subscriptions[symbol] {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+}
+
+// symbolPaused reports whether a symbol's stream updates are paused
+func (h *Hub) symbolPaused(symbol string) bool {
+	paused := h.pausedSymbols.Load()
+	return paused != nil && (*paused)[symbol]
+}
@@ -0,0 +1,26 @@
+package websocket
+
+// SymbolResolver resolves canonical symbols ("BTC-PERP") to an exchange's symbol key
+// Symbols that are not canonical are returned unchanged with resolved false
+type SymbolResolver interface {
+	ResolveSymbol(symbol, exchange string) (key string, resolved bool, err error)
+}
+
+// SetSymbolResolver lets clients subscribe by canonical symbol
+func (h *Hub) SetSymbolResolver(resolver SymbolResolver) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.symbolResolver = resolver
+}
+
+// resolveSymbol returns the symbol key of a canonical symbol on an exchange (Binance when
+// exchange is empty); other symbols are returned unchanged
+func (h *Hub) resolveSymbol(symbol, exchange string) (string, bool, error) {
+	h.mutex.RLock()
+	resolver := h.symbolResolver
+	h.mutex.RUnlock()
+	if resolver == nil {
+		return symbol, false, nil
+	}
+	return resolver.ResolveSymbol(symbol, exchange)
+}
@@ -0,0 +1,46 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+	"tterminal-backend/models"
+)
+
+// SymbolUpdate tells terminals that a symbol's order-entry metadata changed after an exchangeInfo
+// sync, so they revalidate orders without a reload. Symbol carries the full metadata after the change
+type SymbolUpdate struct {
+	Type             string                   `json:"type"`    // Always "symbol_update"
+	Channel          string                   `json:"channel"` // Always "symbols:meta"
+	Changes          []string                 `json:"changes"` // listed, precision, filters, status and/or leverage_brackets
+	Symbol           *models.Symbol           `json:"symbol"`
+	LeverageBrackets []models.LeverageBracket `json:"leverage_brackets,omitempty"`
+	Timestamp        int64                    `json:"timestamp"`
+}
+
+// BroadcastSymbolUpdate sends a "symbol_update" event to clients subscribed to the "symbols:meta" channel
+func (h *Hub) BroadcastSymbolUpdate(symbol *models.Symbol, changes []string, brackets []models.LeverageBracket) {
+	message, err := json.Marshal(&SymbolUpdate{
+		Type:             "symbol_update",
+		Channel:          ChannelSymbolMeta,
+		Changes:          changes,
+		Symbol:           symbol,
+		LeverageBrackets: brackets,
+		Timestamp:        time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Error marshaling symbol update: %v", err)
+		return
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for client := range h.channelSubscriptions[ChannelSymbolMeta] {
+		select {
+		case client.send <- message:
+		default:
+			// Client buffer full, skip rather than block the hub
+		}
+	}
+}
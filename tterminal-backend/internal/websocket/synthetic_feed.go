@@ -0,0 +1,311 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/internal/synthetic"
+	"tterminal-backend/models"
+)
+
+const (
+	// syntheticTickInterval is how often the synthetic feed emits trades, depth and klines
+	syntheticTickInterval = 250 * time.Millisecond
+	// syntheticSlowEvery emits tickers and mark prices once per second
+	syntheticSlowEvery = 4
+	// syntheticDepthLevels is the number of book levels per side
+	syntheticDepthLevels = 20
+	// syntheticTradeNotional is the average quote notional of a synthetic trade
+	syntheticTradeNotional = 4000.0
+)
+
+// syntheticKlineIntervals mirrors the kline streams subscribed on Binance
+var syntheticKlineIntervals = []string{"1m", "5m", "15m"}
+
+// UseSyntheticFeed makes the stream generate market data locally instead of connecting to Binance
+// Events are encoded as Binance combined-stream messages and run through the regular message
+// pipeline, so every consumer (hub channels, trade persistence, funding prediction) sees them
+// exactly like live data. Must be called before Start
+func (bs *BinanceStream) UseSyntheticFeed(generator *synthetic.Generator) {
+	bs.synthetic = generator
+}
+
+// IsSynthetic reports whether the stream serves generated data
+func (bs *BinanceStream) IsSynthetic() bool {
+	return bs.synthetic != nil
+}
+
+// startSyntheticFeed starts the generator loop in place of the Binance connections
+func (bs *BinanceStream) startSyntheticFeed() {
+	bs.syntheticStop = make(chan struct{})
+	go bs.runSyntheticFeed(bs.syntheticStop)
+	log.Printf("Synthetic market data feed started - generating %d symbols offline", len(bs.symbols))
+}
+
+// stopSyntheticFeed stops the generator loop
+func (bs *BinanceStream) stopSyntheticFeed() {
+	if bs.syntheticStop != nil {
+		close(bs.syntheticStop)
+		bs.syntheticStop = nil
+		log.Println("Synthetic market data feed stopped")
+	}
+}
+
+// syntheticSymbolState tracks per-symbol feed state between ticks
+type syntheticSymbolState struct {
+	lastPrice  float64
+	aggTradeID int64
+	updateID   int64
+	barStarts  map[string]int64
+}
+
+// runSyntheticFeed emits one round of events per tick until stopped
+func (bs *BinanceStream) runSyntheticFeed(stop chan struct{}) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	states := make(map[string]*syntheticSymbolState)
+
+	ticker := time.NewTicker(syntheticTickInterval)
+	defer ticker.Stop()
+
+	for tick := 0; ; tick++ {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		for _, symbol := range bs.symbols {
+			state, exists := states[symbol]
+			if !exists {
+				state = &syntheticSymbolState{barStarts: make(map[string]int64)}
+				states[symbol] = state
+			}
+
+			bs.emitSyntheticTrades(symbol, state, now, rng)
+			bs.emitSyntheticDepth(symbol, state, now, rng)
+			bs.emitSyntheticKlines(symbol, state, now)
+			if tick%syntheticSlowEvery == 0 {
+				bs.emitSyntheticTicker(symbol, now)
+				bs.emitSyntheticMarkPrice(symbol, now)
+				bs.maybeEmitSyntheticLiquidation(symbol, now, rng)
+			}
+		}
+	}
+}
+
+// emitSyntheticTrades emits aggregate trades whose count and aggressor side follow the price path
+func (bs *BinanceStream) emitSyntheticTrades(symbol string, state *syntheticSymbolState, now time.Time, rng *rand.Rand) {
+	generator := bs.synthetic
+	price := generator.Price(symbol, now)
+	volatility := generator.Volatility(symbol, now)
+
+	count := rng.Intn(int(1+4*volatility)) + 1
+	for i := 0; i < count; i++ {
+		isBuyerMaker := price < state.lastPrice || (price == state.lastPrice && rng.Intn(2) == 0)
+		tradePrice := price
+		if rng.Intn(3) == 0 {
+			tradePrice += float64(rng.Intn(3)-1) * generator.TickSize(symbol)
+		}
+		quantity := math.Max(generator.StepSize(symbol), syntheticTradeNotional/price*math.Exp(rng.NormFloat64()))
+
+		state.aggTradeID++
+		bs.processSyntheticEvent(symbol, "aggTrade", map[string]interface{}{
+			"e": "aggTrade",
+			"E": now.UnixMilli(),
+			"s": symbol,
+			"a": state.aggTradeID,
+			"t": state.aggTradeID,
+			"p": generator.FormatPrice(symbol, tradePrice),
+			"q": generator.FormatQuantity(symbol, quantity),
+			"T": now.UnixMilli(),
+			"m": isBuyerMaker,
+		})
+	}
+	state.lastPrice = price
+}
+
+// emitSyntheticDepth emits a book snapshot around the current price as a depth update
+func (bs *BinanceStream) emitSyntheticDepth(symbol string, state *syntheticSymbolState, now time.Time, rng *rand.Rand) {
+	generator := bs.synthetic
+	price := generator.Price(symbol, now)
+	tick := generator.TickSize(symbol)
+	levelSpacing := math.Max(tick, price*0.0001)
+
+	bids := make([][]string, 0, syntheticDepthLevels)
+	asks := make([][]string, 0, syntheticDepthLevels)
+	for level := 1; level <= syntheticDepthLevels; level++ {
+		// Resting size grows away from the touch
+		size := syntheticTradeNotional / price * (1 + float64(level)/4) * (0.5 + rng.Float64()*1.5)
+		bids = append(bids, []string{
+			generator.FormatPrice(symbol, price-float64(level)*levelSpacing),
+			generator.FormatQuantity(symbol, size),
+		})
+		asks = append(asks, []string{
+			generator.FormatPrice(symbol, price+float64(level)*levelSpacing),
+			generator.FormatQuantity(symbol, size*(0.8+rng.Float64()*0.4)),
+		})
+	}
+
+	firstUpdateID := state.updateID + 1
+	state.updateID += int64(2 * syntheticDepthLevels)
+	bs.processSyntheticEvent(symbol, "depth@100ms", map[string]interface{}{
+		"e": "depthUpdate",
+		"E": now.UnixMilli(),
+		"s": symbol,
+		"U": firstUpdateID,
+		"u": state.updateID,
+		"b": bids,
+		"a": asks,
+	})
+}
+
+// emitSyntheticKlines emits the open kline of each interval, closing the previous bar at boundaries
+func (bs *BinanceStream) emitSyntheticKlines(symbol string, state *syntheticSymbolState, now time.Time) {
+	nowMs := now.UnixMilli()
+	for _, interval := range syntheticKlineIntervals {
+		barStart := synthetic.BarStart(interval, nowMs)
+		if previous, exists := state.barStarts[interval]; exists && previous != barStart {
+			bs.emitSyntheticKline(symbol, interval, previous, true)
+		}
+		state.barStarts[interval] = barStart
+		bs.emitSyntheticKline(symbol, interval, barStart, false)
+	}
+}
+
+// emitSyntheticKline emits a kline event for the bar opening at barStart
+func (bs *BinanceStream) emitSyntheticKline(symbol, interval string, barStart int64, closed bool) {
+	candle := bs.synthetic.Candle(symbol, interval, models.PriceTypeLast, time.UnixMilli(barStart))
+	bs.processSyntheticEvent(symbol, "kline_"+interval, map[string]interface{}{
+		"e": "kline",
+		"E": time.Now().UnixMilli(),
+		"s": symbol,
+		"k": map[string]interface{}{
+			"t": candle.OpenTime.UnixMilli(),
+			"T": candle.CloseTime.UnixMilli(),
+			"s": symbol,
+			"i": interval,
+			"o": candle.Open,
+			"c": candle.Close,
+			"h": candle.High,
+			"l": candle.Low,
+			"v": candle.Volume,
+			"n": candle.TradeCount,
+			"x": closed,
+			"q": candle.QuoteAssetVolume,
+			"V": candle.TakerBuyBaseAssetVolume,
+			"Q": candle.TakerBuyQuoteAssetVolume,
+			"B": "0",
+		},
+	})
+}
+
+// emitSyntheticTicker emits 24h rolling statistics built from hourly candles
+func (bs *BinanceStream) emitSyntheticTicker(symbol string, now time.Time) {
+	generator := bs.synthetic
+	candles := generator.Candles(symbol, "1h", models.PriceTypeLast, now.Add(-24*time.Hour), now, 25)
+	if len(candles) == 0 {
+		return
+	}
+
+	last := generator.Price(symbol, now)
+	open := generator.Price(symbol, now.Add(-24*time.Hour))
+	high, low := math.Max(open, last), math.Min(open, last)
+	var volume, quoteVolume float64
+	for _, candle := range candles {
+		high = math.Max(high, models.ParseFloat(candle.High))
+		low = math.Min(low, models.ParseFloat(candle.Low))
+		volume += models.ParseFloat(candle.Volume)
+		quoteVolume += models.ParseFloat(candle.QuoteAssetVolume)
+	}
+
+	bs.processSyntheticEvent(symbol, "ticker", map[string]interface{}{
+		"e": "24hrTicker",
+		"E": now.UnixMilli(),
+		"s": symbol,
+		"p": generator.FormatPrice(symbol, last-open),
+		"P": strconv.FormatFloat((last-open)/open*100, 'f', 3, 64),
+		"w": generator.FormatPrice(symbol, quoteVolume/math.Max(volume, 1e-12)),
+		"c": generator.FormatPrice(symbol, last),
+		"o": generator.FormatPrice(symbol, open),
+		"h": generator.FormatPrice(symbol, high),
+		"l": generator.FormatPrice(symbol, low),
+		"v": generator.FormatQuantity(symbol, volume),
+		"q": strconv.FormatFloat(quoteVolume, 'f', 2, 64),
+		"O": now.Add(-24 * time.Hour).UnixMilli(),
+		"C": now.UnixMilli(),
+	})
+}
+
+// emitSyntheticMarkPrice emits mark, index and funding data with the next 8h funding time
+func (bs *BinanceStream) emitSyntheticMarkPrice(symbol string, now time.Time) {
+	generator := bs.synthetic
+	nextFunding := now.Truncate(8 * time.Hour).Add(8 * time.Hour)
+
+	bs.processSyntheticEvent(symbol, "markPrice", map[string]interface{}{
+		"e": "markPriceUpdate",
+		"E": now.UnixMilli(),
+		"s": symbol,
+		"p": generator.FormatPrice(symbol, generator.MarkPrice(symbol, now)),
+		"i": generator.FormatPrice(symbol, generator.IndexPrice(symbol, now)),
+		"P": generator.FormatPrice(symbol, generator.MarkPrice(symbol, now)),
+		"r": strconv.FormatFloat(generator.FundingRate(symbol, now), 'f', 8, 64),
+		"T": nextFunding.UnixMilli(),
+	})
+}
+
+// maybeEmitSyntheticLiquidation occasionally emits a liquidation, more often in volatile regimes
+func (bs *BinanceStream) maybeEmitSyntheticLiquidation(symbol string, now time.Time, rng *rand.Rand) {
+	generator := bs.synthetic
+	if rng.Float64() > 0.02*generator.Volatility(symbol, now) {
+		return
+	}
+
+	price := generator.Price(symbol, now)
+	side := "SELL" // Long liquidated
+	if price > generator.Price(symbol, now.Add(-time.Minute)) {
+		side = "BUY" // Shorts squeezed on the way up
+	}
+	quantity := generator.FormatQuantity(symbol, 10*syntheticTradeNotional/price*math.Exp(rng.NormFloat64()))
+	formattedPrice := generator.FormatPrice(symbol, price)
+
+	bs.processSyntheticEvent("", "!forceOrder@arr", map[string]interface{}{
+		"e": "forceOrder",
+		"E": now.UnixMilli(),
+		"o": map[string]interface{}{
+			"s":  symbol,
+			"S":  side,
+			"o":  "LIMIT",
+			"f":  "IOC",
+			"q":  quantity,
+			"p":  formattedPrice,
+			"ap": formattedPrice,
+			"X":  "FILLED",
+			"l":  quantity,
+			"z":  quantity,
+			"T":  now.UnixMilli(),
+		},
+	})
+}
+
+// processSyntheticEvent wraps an event in a futures combined-stream message and processes it
+func (bs *BinanceStream) processSyntheticEvent(symbol, stream string, data map[string]interface{}) {
+	if symbol != "" {
+		stream = strings.ToLower(symbol) + "@" + stream
+	}
+
+	message, err := json.Marshal(map[string]interface{}{
+		"stream": stream,
+		"data":   data,
+	})
+	if err != nil {
+		log.Printf("Error encoding synthetic %s event: %v", stream, err)
+		return
+	}
+
+	bs.processFuturesMessage(message)
+}
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// tradeSizeWindow is the number of recent trade sizes kept per symbol for percentile ranking
+const tradeSizeWindow = 1000
+
+// TradeContext carries computed context attached to enriched trade updates
+type TradeContext struct {
+	VWAP            float64 `json:"vwap"`              // Session VWAP (UTC day)
+	VWAPDistance    float64 `json:"vwap_distance"`     // Trade price minus session VWAP
+	VWAPDistancePct float64 `json:"vwap_distance_pct"` // Distance from VWAP in percent
+	Delta1m         float64 `json:"delta_1m"`          // Buy minus sell volume of the current 1m bar
+	SizePercentile  float64 `json:"size_percentile"`   // Percentile rank of trade size in recent window (0-100)
+}
+
+// symbolTradeState holds rolling per-symbol trade statistics
+type symbolTradeState struct {
+	sessionStart int64 // UTC day start (Unix milliseconds)
+	pvSum        float64
+	volumeSum    float64
+	minuteStart  int64 // Current 1m bucket start (Unix milliseconds)
+	minuteDelta  float64
+	sizes        []float64 // Ring buffer of recent trade sizes
+	sizeNext     int
+	sizeFilled   bool
+}
+
+// TradeEnricher maintains rolling VWAP, delta and size statistics for trade enrichment
+type TradeEnricher struct {
+	mu     sync.Mutex
+	states map[string]*symbolTradeState
+}
+
+// NewTradeEnricher creates a new trade enricher
+func NewTradeEnricher() *TradeEnricher {
+	return &TradeEnricher{
+		states: make(map[string]*symbolTradeState),
+	}
+}
+
+// Update folds a trade into the rolling statistics and returns its computed context
+func (e *TradeEnricher) Update(symbol string, price, quantity float64, isBuyerMaker bool, tradeTime int64) TradeContext {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, exists := e.states[symbol]
+	if !exists {
+		state = &symbolTradeState{sizes: make([]float64, tradeSizeWindow)}
+		e.states[symbol] = state
+	}
+
+	// Reset session VWAP at the UTC day boundary
+	tradeAt := time.UnixMilli(tradeTime).UTC()
+	sessionStart := time.Date(tradeAt.Year(), tradeAt.Month(), tradeAt.Day(), 0, 0, 0, 0, time.UTC).UnixMilli()
+	if sessionStart != state.sessionStart {
+		state.sessionStart = sessionStart
+		state.pvSum = 0
+		state.volumeSum = 0
+	}
+	state.pvSum += price * quantity
+	state.volumeSum += quantity
+
+	// Reset delta when a new 1m bar starts
+	minuteStart := tradeTime - tradeTime%60000
+	if minuteStart != state.minuteStart {
+		state.minuteStart = minuteStart
+		state.minuteDelta = 0
+	}
+	if isBuyerMaker {
+		state.minuteDelta -= quantity // Buyer is maker: aggressive seller
+	} else {
+		state.minuteDelta += quantity
+	}
+
+	// Record size in the ring buffer
+	state.sizes[state.sizeNext] = quantity
+	state.sizeNext = (state.sizeNext + 1) % tradeSizeWindow
+	if state.sizeNext == 0 {
+		state.sizeFilled = true
+	}
+
+	ctx := TradeContext{
+		Delta1m:        state.minuteDelta,
+		SizePercentile: state.sizePercentile(quantity),
+	}
+	if state.volumeSum > 0 {
+		ctx.VWAP = state.pvSum / state.volumeSum
+		ctx.VWAPDistance = price - ctx.VWAP
+		if ctx.VWAP > 0 {
+			ctx.VWAPDistancePct = ctx.VWAPDistance / ctx.VWAP * 100
+		}
+	}
+
+	return ctx
+}
+
+// sizePercentile returns the percentile rank of a trade size within the recent window
+func (s *symbolTradeState) sizePercentile(quantity float64) float64 {
+	count := s.sizeNext
+	if s.sizeFilled {
+		count = tradeSizeWindow
+	}
+	if count == 0 {
+		return 0
+	}
+
+	atOrBelow := 0
+	for i := 0; i < count; i++ {
+		if s.sizes[i] <= quantity {
+			atOrBelow++
+		}
+	}
+
+	return float64(atOrBelow) / float64(count) * 100
+}
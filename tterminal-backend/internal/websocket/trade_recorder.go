@@ -0,0 +1,44 @@
+package websocket
+
+import (
+	"log"
+	"tterminal-backend/internal/batchwriter"
+	"tterminal-backend/models"
+)
+
+// TradeWriter batches streamed trades into the trade store
+type TradeWriter = batchwriter.Writer[models.TradeRecord]
+
+// tradeRecorder hands streamed trades to a batch writer off the stream's read path
+// Trades of symbols paused on the hub are not stored
+type tradeRecorder struct {
+	writer *TradeWriter
+	hub    *Hub
+}
+
+// newTradeRecorder creates a recorder queueing trades on writer
+func newTradeRecorder(writer *TradeWriter, hub *Hub) *tradeRecorder {
+	return &tradeRecorder{
+		writer: writer,
+		hub:    hub,
+	}
+}
+
+// SetTradeWriter enables persistence of futures aggregate trades
+func (bs *BinanceStream) SetTradeWriter(writer *TradeWriter) {
+	bs.tradeRecorder.Store(newTradeRecorder(writer, bs.hub))
+	log.Printf("Trade persistence enabled for futures aggregate trades")
+}
+
+// record queues a trade without blocking; the writer drops trades if the store falls behind
+func (r *tradeRecorder) record(trade models.TradeRecord) {
+	if r.hub != nil && r.hub.symbolPaused(trade.Symbol) {
+		return
+	}
+	r.writer.Add(trade)
+}
+
+// stats returns the writer's counters
+func (r *tradeRecorder) stats() models.WriterStats {
+	return r.writer.Stats()
+}
@@ -0,0 +1,203 @@
+package websocket
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Default interval for application-level keepalive frames on idle connections
+	defaultKeepaliveInterval = 25 * time.Second
+
+	// Longest keepalive interval a client may request
+	maxKeepaliveInterval = 5 * time.Minute
+
+	// Smallest frame size that fragmenting may be configured to
+	minFrameSize = 1024
+
+	// Bytes reserved for the fragment envelope around base64 data
+	fragmentEnvelopeSize = 128
+)
+
+// TransportConfig controls keepalive frames and frame size limits for WebSocket clients
+type TransportConfig struct {
+	KeepaliveInterval time.Duration // Idle time before a keepalive frame is sent (0 disables)
+	MaxFrameSize      int           // Largest text frame in bytes (0 = unlimited)
+}
+
+// FragmentMessage carries one piece of a message larger than the client's max frame size
+// Data is base64 so chunks split on byte boundaries stay valid JSON
+type FragmentMessage struct {
+	Type  string `json:"type"` // "fragment"
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+	Count int    `json:"count"`
+	Data  string `json:"data"`
+}
+
+// SetTransportConfig sets the default keepalive and frame size settings for new connections
+func (h *Hub) SetTransportConfig(cfg TransportConfig) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if cfg.MaxFrameSize > 0 && cfg.MaxFrameSize < minFrameSize {
+		cfg.MaxFrameSize = minFrameSize
+	}
+	h.transport = cfg
+}
+
+// getTransportConfig returns the default transport settings
+func (h *Hub) getTransportConfig() TransportConfig {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.transport
+}
+
+// applyTransportOptions sets a client's keepalive and frame size from hub defaults,
+// overridden by the "keepalive" (seconds) and "max_frame" (bytes) query parameters
+func (c *Client) applyTransportOptions(defaults TransportConfig, query url.Values) {
+	c.keepaliveInterval = defaults.KeepaliveInterval
+	c.maxFrameSize = defaults.MaxFrameSize
+
+	if value := query.Get("keepalive"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			c.keepaliveInterval = time.Duration(seconds) * time.Second
+			if c.keepaliveInterval > maxKeepaliveInterval {
+				c.keepaliveInterval = maxKeepaliveInterval
+			}
+		}
+	}
+
+	if value := query.Get("max_frame"); value != "" {
+		if size, err := strconv.Atoi(value); err == nil && size >= 0 {
+			c.maxFrameSize = size
+			if size > 0 && size < minFrameSize {
+				c.maxFrameSize = minFrameSize
+			}
+		}
+	}
+}
+
+// writeBatch writes queued messages, joining them with newlines into frames no larger
+// than the client's max frame size and fragmenting single messages that exceed it
+func (c *Client) writeBatch(messages [][]byte) error {
+	c.recordBatch(messages)
+
+	if c.maxFrameSize <= 0 {
+		return c.writeFrame(messages...)
+	}
+
+	var frame [][]byte
+	frameSize := 0
+	for _, message := range messages {
+		if len(message) > c.maxFrameSize {
+			if err := c.writeFrame(frame...); err != nil {
+				return err
+			}
+			frame, frameSize = nil, 0
+
+			if err := c.writeFragments(message); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if frameSize > 0 && frameSize+1+len(message) > c.maxFrameSize {
+			if err := c.writeFrame(frame...); err != nil {
+				return err
+			}
+			frame, frameSize = nil, 0
+		}
+		if frameSize > 0 {
+			frameSize++
+		}
+		frame = append(frame, message)
+		frameSize += len(message)
+	}
+
+	return c.writeFrame(frame...)
+}
+
+// writeFrame writes messages as one newline-separated text frame
+func (c *Client) writeFrame(messages ...[]byte) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	w, err := c.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	size := 0
+	for i, message := range messages {
+		if i > 0 {
+			w.Write([]byte("\n"))
+			size++
+		}
+		w.Write(message)
+		size += len(message)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	c.recordSent(size)
+
+	c.lastWrite = time.Now()
+	return nil
+}
+
+// writeFragments splits a message into base64 fragment frames within the max frame size
+func (c *Client) writeFragments(message []byte) error {
+	chunkSize := (c.maxFrameSize - fragmentEnvelopeSize) / 4 * 3
+	count := (len(message) + chunkSize - 1) / chunkSize
+
+	c.fragmentSeq++
+	id := fmt.Sprintf("%s-%d", c.id, c.fragmentSeq)
+
+	for index := 0; index < count; index++ {
+		end := (index + 1) * chunkSize
+		if end > len(message) {
+			end = len(message)
+		}
+
+		fragment, err := json.Marshal(FragmentMessage{
+			Type:  "fragment",
+			ID:    id,
+			Index: index,
+			Count: count,
+			Data:  base64.StdEncoding.EncodeToString(message[index*chunkSize : end]),
+		})
+		if err != nil {
+			return err
+		}
+		if err := c.writeFrame(fragment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeKeepalive sends an application-level keepalive frame if the connection has been idle
+// Control-frame pings are invisible to some proxies, so idle detection needs a data frame
+func (c *Client) writeKeepalive() error {
+	if c.keepaliveInterval <= 0 || time.Since(c.lastWrite) < c.keepaliveInterval {
+		return nil
+	}
+
+	message, err := json.Marshal(map[string]interface{}{
+		"type":      "keepalive",
+		"timestamp": time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(message)
+}
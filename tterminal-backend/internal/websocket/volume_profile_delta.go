@@ -0,0 +1,197 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+// volumeProfileDeltaInterval is how often changed volume profile buckets are flushed to clients
+const volumeProfileDeltaInterval = 500 * time.Millisecond
+
+// maxVolumeProfileSubscriptions caps the number of live profiles per client
+const maxVolumeProfileSubscriptions = 20
+
+// VolumeProfileSubscription identifies one live volume profile (symbol + bucket size)
+type VolumeProfileSubscription struct {
+	Symbol     string  `json:"symbol"`
+	BucketSize float64 `json:"bucket_size"`
+}
+
+// VolumeProfileDeltaLevel is the volume added to one price bucket since the previous delta
+type VolumeProfileDeltaLevel struct {
+	P  float64 `json:"p"`  // Bucket price (lower bound)
+	V  float64 `json:"v"`  // Added volume
+	BV float64 `json:"bv"` // Added buy (taker) volume
+	SV float64 `json:"sv"` // Added sell (taker) volume
+}
+
+// volumeProfileTrades accumulates one symbol's trade volume by exact price between flushes
+type volumeProfileTrades struct {
+	from   int64 // Earliest trade time (Unix milliseconds)
+	to     int64 // Latest trade time (Unix milliseconds)
+	levels map[float64]*VolumeProfileDeltaLevel
+}
+
+// volumeProfileBuffer collects trade volume per symbol since the last flush
+type volumeProfileBuffer struct {
+	mu      sync.Mutex
+	symbols map[string]*volumeProfileTrades
+}
+
+// QueueVolumeProfileTrade records a trade for the next volume profile delta of its symbol
+func (h *Hub) QueueVolumeProfileTrade(symbol string, price, quantity float64, isBuyerMaker bool, tradeTime int64) {
+	if h.symbolPaused(symbol) {
+		return
+	}
+
+	h.volumeProfile.mu.Lock()
+	defer h.volumeProfile.mu.Unlock()
+
+	trades, exists := h.volumeProfile.symbols[symbol]
+	if !exists {
+		trades = &volumeProfileTrades{from: tradeTime, levels: make(map[float64]*VolumeProfileDeltaLevel)}
+		h.volumeProfile.symbols[symbol] = trades
+	}
+	if tradeTime < trades.from {
+		trades.from = tradeTime
+	}
+	if tradeTime > trades.to {
+		trades.to = tradeTime
+	}
+
+	level, exists := trades.levels[price]
+	if !exists {
+		level = &VolumeProfileDeltaLevel{P: price}
+		trades.levels[price] = level
+	}
+	level.V += quantity
+	if isBuyerMaker {
+		level.SV += quantity // Buyer is maker: aggressive seller
+	} else {
+		level.BV += quantity
+	}
+}
+
+// SetVolumeProfiles replaces the live volume profiles a client receives deltas for
+func (h *Hub) SetVolumeProfiles(client *Client, profiles []VolumeProfileSubscription) []VolumeProfileSubscription {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	client.volumeProfiles = make(map[string]float64, len(profiles))
+	accepted := make([]VolumeProfileSubscription, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Symbol == "" || profile.BucketSize <= 0 || len(accepted) >= maxVolumeProfileSubscriptions {
+			continue
+		}
+		profile.Symbol = strings.ToUpper(profile.Symbol)
+		if _, exists := client.volumeProfiles[profile.Symbol]; !exists {
+			client.volumeProfiles[profile.Symbol] = profile.BucketSize
+			accepted = append(accepted, profile)
+		}
+	}
+
+	return accepted
+}
+
+// runVolumeProfileDeltas flushes changed volume profile buckets to clients once per tick
+func (h *Hub) runVolumeProfileDeltas() {
+	ticker := time.NewTicker(volumeProfileDeltaInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.flushVolumeProfileDeltas()
+	}
+}
+
+// flushVolumeProfileDeltas sends each profile client one "vp_delta" message per changed symbol,
+// with the traded volume folded into that client's bucket size
+func (h *Hub) flushVolumeProfileDeltas() {
+	h.volumeProfile.mu.Lock()
+	if len(h.volumeProfile.symbols) == 0 {
+		h.volumeProfile.mu.Unlock()
+		return
+	}
+	changed := h.volumeProfile.symbols
+	h.volumeProfile.symbols = make(map[string]*volumeProfileTrades, len(changed))
+	h.volumeProfile.mu.Unlock()
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	clients, exists := h.channelSubscriptions[ChannelVolumeProfile]
+	if !exists {
+		return
+	}
+
+	// Clients sharing a symbol and bucket size receive the same message
+	messages := make(map[VolumeProfileSubscription][]byte)
+	timestamp := time.Now().UnixMilli()
+
+	for client := range clients {
+		for symbol, bucketSize := range client.volumeProfiles {
+			trades, exists := changed[symbol]
+			if !exists {
+				continue
+			}
+
+			profile := VolumeProfileSubscription{Symbol: symbol, BucketSize: bucketSize}
+			message, built := messages[profile]
+			if !built {
+				var err error
+				message, err = json.Marshal(map[string]interface{}{
+					"type":        "vp_delta",
+					"channel":     ChannelVolumeProfile,
+					"symbol":      symbol,
+					"bucket_size": bucketSize,
+					"from":        trades.from,
+					"to":          trades.to,
+					"levels":      trades.bucketed(bucketSize),
+					"timestamp":   timestamp,
+				})
+				if err != nil {
+					log.Printf("Error marshaling volume profile delta: %v", err)
+					continue
+				}
+				messages[profile] = message
+			}
+
+			select {
+			case client.send <- message:
+			default:
+				// Client buffer full, remove client
+				h.dropSlowClient(client)
+			}
+		}
+	}
+}
+
+// bucketed folds the accumulated trade volume into buckets of the given size, sorted by price
+func (t *volumeProfileTrades) bucketed(bucketSize float64) []VolumeProfileDeltaLevel {
+	buckets := make(map[float64]*VolumeProfileDeltaLevel)
+	for price, level := range t.levels {
+		bucketPrice := models.VolumeProfileBucket(price, bucketSize)
+		bucket, exists := buckets[bucketPrice]
+		if !exists {
+			bucket = &VolumeProfileDeltaLevel{P: bucketPrice}
+			buckets[bucketPrice] = bucket
+		}
+		bucket.V += level.V
+		bucket.BV += level.BV
+		bucket.SV += level.SV
+	}
+
+	levels := make([]VolumeProfileDeltaLevel, 0, len(buckets))
+	for _, bucket := range buckets {
+		levels = append(levels, *bucket)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].P < levels[j].P
+	})
+
+	return levels
+}
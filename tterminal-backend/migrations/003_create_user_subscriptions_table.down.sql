@@ -0,0 +1,2 @@
+-- Drop user subscriptions table
+DROP TABLE IF EXISTS user_subscriptions;
@@ -0,0 +1,9 @@
+-- Create user subscriptions table (last active WebSocket subscriptions per user)
+CREATE TABLE IF NOT EXISTS user_subscriptions (
+    user_id VARCHAR(128) PRIMARY KEY,
+    symbols TEXT[] NOT NULL DEFAULT '{}',
+    enriched_symbols TEXT[] NOT NULL DEFAULT '{}',
+    channels TEXT[] NOT NULL DEFAULT '{}',
+    liquidation_min_notional DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_price_candles_symbol_type_interval_time;
+
+-- Drop price candles table
+DROP TABLE IF EXISTS price_candles;
@@ -0,0 +1,21 @@
+-- Create price candles table (mark and index price klines)
+CREATE TABLE IF NOT EXISTS price_candles (
+    symbol VARCHAR(50) NOT NULL,
+    price_type VARCHAR(10) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    open DECIMAL(20,8) NOT NULL,
+    high DECIMAL(20,8) NOT NULL,
+    low DECIMAL(20,8) NOT NULL,
+    close DECIMAL(20,8) NOT NULL,
+    close_time TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Convert to hypertable for time-series optimization
+SELECT create_hypertable('price_candles', 'open_time', chunk_time_interval => INTERVAL '1 day');
+
+-- Create unique constraint for upserts
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_candles_symbol_type_interval_time
+ON price_candles(symbol, price_type, interval, open_time);
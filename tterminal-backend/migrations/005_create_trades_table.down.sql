@@ -0,0 +1,9 @@
+-- Remove retention policy
+SELECT remove_retention_policy('trades', if_exists => true);
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_trades_symbol_time;
+DROP INDEX IF EXISTS idx_trades_symbol_id_time;
+
+-- Drop trades table
+DROP TABLE IF EXISTS trades;
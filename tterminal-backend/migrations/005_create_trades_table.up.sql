@@ -0,0 +1,24 @@
+-- Create trades table (futures aggregate trades persisted from the live stream)
+CREATE TABLE IF NOT EXISTS trades (
+    symbol VARCHAR(50) NOT NULL,
+    trade_id BIGINT NOT NULL,
+    price DECIMAL(20,8) NOT NULL,
+    quantity DECIMAL(20,8) NOT NULL,
+    is_buyer_maker BOOLEAN NOT NULL,
+    trade_time TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Convert to hypertable for time-series optimization
+SELECT create_hypertable('trades', 'trade_time', chunk_time_interval => INTERVAL '1 hour');
+
+-- Create unique constraint so replayed trades are ignored
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trades_symbol_id_time
+ON trades(symbol, trade_id, trade_time);
+
+-- Create index for time range queries
+CREATE INDEX IF NOT EXISTS idx_trades_symbol_time
+ON trades(symbol, trade_time DESC);
+
+-- Keep raw trades for 30 days
+SELECT add_retention_policy('trades', INTERVAL '30 days');
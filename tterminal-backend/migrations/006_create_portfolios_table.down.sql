@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_portfolio_orders_portfolio_time;
+DROP INDEX IF EXISTS idx_portfolios_user_id;
+
+-- Drop portfolio tables
+DROP TABLE IF EXISTS portfolio_orders;
+DROP TABLE IF EXISTS portfolio_positions;
+DROP TABLE IF EXISTS portfolios;
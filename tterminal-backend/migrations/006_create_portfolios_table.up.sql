@@ -0,0 +1,42 @@
+-- Create portfolios table (sub-accounts with isolated balances and risk limits)
+CREATE TABLE IF NOT EXISTS portfolios (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(128) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    mode VARCHAR(10) NOT NULL DEFAULT 'paper',
+    initial_balance DECIMAL(24,8) NOT NULL,
+    balance DECIMAL(24,8) NOT NULL,
+    max_leverage DECIMAL(10,2) NOT NULL DEFAULT 0,
+    max_position_notional DECIMAL(24,8) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Create portfolio positions table (one row per portfolio and symbol, quantity is signed)
+CREATE TABLE IF NOT EXISTS portfolio_positions (
+    portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    symbol VARCHAR(50) NOT NULL,
+    quantity DECIMAL(20,8) NOT NULL DEFAULT 0,
+    entry_price DECIMAL(20,8) NOT NULL DEFAULT 0,
+    realized_pnl DECIMAL(24,8) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (portfolio_id, symbol)
+);
+
+-- Create portfolio orders table (filled orders per portfolio)
+CREATE TABLE IF NOT EXISTS portfolio_orders (
+    id BIGSERIAL PRIMARY KEY,
+    portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    symbol VARCHAR(50) NOT NULL,
+    side VARCHAR(4) NOT NULL,
+    quantity DECIMAL(20,8) NOT NULL,
+    price DECIMAL(20,8) NOT NULL,
+    realized_pnl DECIMAL(24,8) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_portfolios_user_id ON portfolios(user_id);
+CREATE INDEX IF NOT EXISTS idx_portfolio_orders_portfolio_time
+ON portfolio_orders(portfolio_id, created_at DESC);
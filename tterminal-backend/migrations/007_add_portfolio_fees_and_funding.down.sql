@@ -0,0 +1,12 @@
+-- Drop fee and funding columns
+ALTER TABLE portfolio_orders DROP COLUMN IF EXISTS fee;
+
+ALTER TABLE portfolio_positions
+    DROP COLUMN IF EXISTS funding_settled_at,
+    DROP COLUMN IF EXISTS funding_accrued,
+    DROP COLUMN IF EXISTS fees_paid;
+
+ALTER TABLE portfolios
+    DROP COLUMN IF EXISTS funding_accrued,
+    DROP COLUMN IF EXISTS fees_paid,
+    DROP COLUMN IF EXISTS realized_pnl;
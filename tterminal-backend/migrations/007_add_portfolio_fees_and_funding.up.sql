@@ -0,0 +1,19 @@
+-- Track realized PnL, fees and funding per portfolio
+ALTER TABLE portfolios
+    ADD COLUMN IF NOT EXISTS realized_pnl DECIMAL(24,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fees_paid DECIMAL(24,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS funding_accrued DECIMAL(24,8) NOT NULL DEFAULT 0;
+
+-- Backfill realized PnL from filled orders
+UPDATE portfolios p
+SET realized_pnl = COALESCE((SELECT SUM(o.realized_pnl) FROM portfolio_orders o WHERE o.portfolio_id = p.id), 0);
+
+-- Track fees and funding of the open position
+ALTER TABLE portfolio_positions
+    ADD COLUMN IF NOT EXISTS fees_paid DECIMAL(24,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS funding_accrued DECIMAL(24,8) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS funding_settled_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Track the fee paid per order
+ALTER TABLE portfolio_orders
+    ADD COLUMN IF NOT EXISTS fee DECIMAL(24,8) NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_reports_user_date;
+
+-- Drop report tables
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS report_settings;
@@ -0,0 +1,22 @@
+-- Create report settings table (watchlist and email delivery per user)
+CREATE TABLE IF NOT EXISTS report_settings (
+    user_id VARCHAR(128) PRIMARY KEY,
+    watchlist TEXT[] NOT NULL DEFAULT '{}',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    email_enabled BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create reports table (generated daily recaps stored as JSON)
+CREATE TABLE IF NOT EXISTS reports (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(128) NOT NULL,
+    report_date DATE NOT NULL,
+    payload JSONB NOT NULL,
+    emailed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, report_date)
+);
+
+-- Create index for report history
+CREATE INDEX IF NOT EXISTS idx_reports_user_date ON reports(user_id, report_date DESC);
@@ -0,0 +1,4 @@
+-- Drop symbol display metadata columns
+ALTER TABLE symbols
+    DROP COLUMN IF EXISTS contract_multiplier,
+    DROP COLUMN IF EXISTS min_notional;
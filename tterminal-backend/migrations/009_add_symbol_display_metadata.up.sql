@@ -0,0 +1,4 @@
+-- Minimum order notional and contract multiplier from Binance symbol filters
+ALTER TABLE symbols
+    ADD COLUMN IF NOT EXISTS min_notional DECIMAL(20,8),
+    ADD COLUMN IF NOT EXISTS contract_multiplier DECIMAL(20,8) DEFAULT 1;
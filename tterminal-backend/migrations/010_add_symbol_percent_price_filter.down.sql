@@ -0,0 +1,4 @@
+-- Drop PERCENT_PRICE filter columns
+ALTER TABLE symbols
+    DROP COLUMN IF EXISTS multiplier_down,
+    DROP COLUMN IF EXISTS multiplier_up;
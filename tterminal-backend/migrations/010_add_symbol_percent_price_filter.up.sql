@@ -0,0 +1,4 @@
+-- PERCENT_PRICE filter bounds relative to the mark price
+ALTER TABLE symbols
+    ADD COLUMN IF NOT EXISTS multiplier_up DECIMAL(20,8),
+    ADD COLUMN IF NOT EXISTS multiplier_down DECIMAL(20,8);
@@ -0,0 +1,8 @@
+-- Remove retention policy
+SELECT remove_retention_policy('depth_levels', if_exists => true);
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_depth_levels_symbol_time_side_price;
+
+-- Drop depth levels table
+DROP TABLE IF EXISTS depth_levels;
@@ -0,0 +1,18 @@
+-- Create depth levels table (periodic futures order book snapshots, one row per level)
+CREATE TABLE IF NOT EXISTS depth_levels (
+    symbol VARCHAR(50) NOT NULL,
+    snapshot_time TIMESTAMPTZ NOT NULL,
+    side VARCHAR(3) NOT NULL CHECK (side IN ('bid', 'ask')),
+    price DECIMAL(20,8) NOT NULL,
+    quantity DECIMAL(20,8) NOT NULL
+);
+
+-- Convert to hypertable for time-series optimization
+SELECT create_hypertable('depth_levels', 'snapshot_time', chunk_time_interval => INTERVAL '1 day');
+
+-- Create unique constraint so replayed snapshots are ignored
+CREATE UNIQUE INDEX IF NOT EXISTS idx_depth_levels_symbol_time_side_price
+ON depth_levels(symbol, snapshot_time, side, price);
+
+-- Keep book snapshots for 30 days
+SELECT add_retention_policy('depth_levels', INTERVAL '30 days');
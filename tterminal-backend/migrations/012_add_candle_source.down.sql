@@ -0,0 +1,6 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_candles_symbol_interval_source_time;
+
+-- Drop candle source column
+ALTER TABLE candles
+    DROP COLUMN IF EXISTS source;
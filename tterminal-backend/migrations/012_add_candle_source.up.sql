@@ -0,0 +1,8 @@
+-- Label each candle with where it came from; candles stored before labeling came from REST klines
+ALTER TABLE candles
+    ADD COLUMN IF NOT EXISTS source VARCHAR(16) NOT NULL DEFAULT 'rest_poll'
+    CHECK (source IN ('ws_stream', 'rest_poll', 'backfill', 'import', 'manual_fix'));
+
+-- Index for per-source coverage reports
+CREATE INDEX IF NOT EXISTS idx_candles_symbol_interval_source_time
+ON candles(symbol, interval, source, open_time DESC);
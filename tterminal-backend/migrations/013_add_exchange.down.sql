@@ -0,0 +1,9 @@
+-- Drop exchange columns
+ALTER TABLE depth_levels
+    DROP COLUMN IF EXISTS exchange;
+
+ALTER TABLE trades
+    DROP COLUMN IF EXISTS exchange;
+
+ALTER TABLE candles
+    DROP COLUMN IF EXISTS exchange;
@@ -0,0 +1,13 @@
+-- Tag market data with its exchange; rows stored before multi-exchange support came from Binance
+-- Symbols of other exchanges are stored prefixed ("BYBIT:BTCUSDT"), so unique indexes are unchanged
+ALTER TABLE candles
+    ADD COLUMN IF NOT EXISTS exchange VARCHAR(16) NOT NULL DEFAULT 'binance'
+    CHECK (exchange IN ('binance', 'bybit'));
+
+ALTER TABLE trades
+    ADD COLUMN IF NOT EXISTS exchange VARCHAR(16) NOT NULL DEFAULT 'binance'
+    CHECK (exchange IN ('binance', 'bybit'));
+
+ALTER TABLE depth_levels
+    ADD COLUMN IF NOT EXISTS exchange VARCHAR(16) NOT NULL DEFAULT 'binance'
+    CHECK (exchange IN ('binance', 'bybit'));
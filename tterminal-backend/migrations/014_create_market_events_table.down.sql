@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_market_events_symbol_interval_type_magnitude;
+DROP INDEX IF EXISTS idx_market_events_symbol_interval_time;
+
+-- Drop market events table
+DROP TABLE IF EXISTS market_events;
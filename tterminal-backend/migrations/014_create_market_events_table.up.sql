@@ -0,0 +1,26 @@
+-- Create market events table (notable closed bars for the chart's significant events navigator)
+CREATE TABLE IF NOT EXISTS market_events (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    event_type VARCHAR(16) NOT NULL CHECK (event_type IN ('range', 'volume_spike', 'gap')),
+    open_time TIMESTAMPTZ NOT NULL,
+    magnitude DOUBLE PRECISION NOT NULL,
+    reference DOUBLE PRECISION NOT NULL,
+    open DECIMAL(20,8) NOT NULL,
+    high DECIMAL(20,8) NOT NULL,
+    low DECIMAL(20,8) NOT NULL,
+    close DECIMAL(20,8) NOT NULL,
+    volume DECIMAL(20,8) NOT NULL,
+    exchange VARCHAR(16) NOT NULL DEFAULT 'binance' CHECK (exchange IN ('binance', 'bybit')),
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (symbol, interval, event_type, open_time)
+);
+
+-- Create index for listing events newest first
+CREATE INDEX IF NOT EXISTS idx_market_events_symbol_interval_time
+ON market_events(symbol, interval, open_time DESC);
+
+-- Create index for ranking the largest range events
+CREATE INDEX IF NOT EXISTS idx_market_events_symbol_interval_type_magnitude
+ON market_events(symbol, interval, event_type, magnitude DESC);
@@ -0,0 +1,20 @@
+-- Remove OKX rows, then restore the Binance and Bybit exchange checks
+DELETE FROM market_events WHERE exchange = 'okx';
+ALTER TABLE market_events DROP CONSTRAINT IF EXISTS market_events_exchange_check;
+ALTER TABLE market_events ADD CONSTRAINT market_events_exchange_check
+    CHECK (exchange IN ('binance', 'bybit'));
+
+DELETE FROM depth_levels WHERE exchange = 'okx';
+ALTER TABLE depth_levels DROP CONSTRAINT IF EXISTS depth_levels_exchange_check;
+ALTER TABLE depth_levels ADD CONSTRAINT depth_levels_exchange_check
+    CHECK (exchange IN ('binance', 'bybit'));
+
+DELETE FROM trades WHERE exchange = 'okx';
+ALTER TABLE trades DROP CONSTRAINT IF EXISTS trades_exchange_check;
+ALTER TABLE trades ADD CONSTRAINT trades_exchange_check
+    CHECK (exchange IN ('binance', 'bybit'));
+
+DELETE FROM candles WHERE exchange = 'okx';
+ALTER TABLE candles DROP CONSTRAINT IF EXISTS candles_exchange_check;
+ALTER TABLE candles ADD CONSTRAINT candles_exchange_check
+    CHECK (exchange IN ('binance', 'bybit'));
@@ -0,0 +1,20 @@
+-- Remove Coinbase rows, then restore the previous exchange checks
+DELETE FROM market_events WHERE exchange = 'coinbase';
+ALTER TABLE market_events DROP CONSTRAINT IF EXISTS market_events_exchange_check;
+ALTER TABLE market_events ADD CONSTRAINT market_events_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx'));
+
+DELETE FROM depth_levels WHERE exchange = 'coinbase';
+ALTER TABLE depth_levels DROP CONSTRAINT IF EXISTS depth_levels_exchange_check;
+ALTER TABLE depth_levels ADD CONSTRAINT depth_levels_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx'));
+
+DELETE FROM trades WHERE exchange = 'coinbase';
+ALTER TABLE trades DROP CONSTRAINT IF EXISTS trades_exchange_check;
+ALTER TABLE trades ADD CONSTRAINT trades_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx'));
+
+DELETE FROM candles WHERE exchange = 'coinbase';
+ALTER TABLE candles DROP CONSTRAINT IF EXISTS candles_exchange_check;
+ALTER TABLE candles ADD CONSTRAINT candles_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx'));
@@ -0,0 +1,16 @@
+-- Allow Coinbase spot rows in every exchange-tagged table ("COINBASE:BTCUSD" symbols)
+ALTER TABLE candles DROP CONSTRAINT IF EXISTS candles_exchange_check;
+ALTER TABLE candles ADD CONSTRAINT candles_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase'));
+
+ALTER TABLE trades DROP CONSTRAINT IF EXISTS trades_exchange_check;
+ALTER TABLE trades ADD CONSTRAINT trades_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase'));
+
+ALTER TABLE depth_levels DROP CONSTRAINT IF EXISTS depth_levels_exchange_check;
+ALTER TABLE depth_levels ADD CONSTRAINT depth_levels_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase'));
+
+ALTER TABLE market_events DROP CONSTRAINT IF EXISTS market_events_exchange_check;
+ALTER TABLE market_events ADD CONSTRAINT market_events_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase'));
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_symbol_baskets_user_id;
+
+-- Drop symbol baskets table
+DROP TABLE IF EXISTS symbol_baskets;
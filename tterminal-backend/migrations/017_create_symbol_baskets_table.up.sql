@@ -0,0 +1,13 @@
+-- Create symbol baskets table (user-defined symbol groups for combined order flow)
+CREATE TABLE IF NOT EXISTS symbol_baskets (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(128) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    symbols TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_symbol_baskets_user_id ON symbol_baskets(user_id);
@@ -0,0 +1,11 @@
+-- Remove composite candles, then restore the previous exchange check
+DELETE FROM candles WHERE exchange = 'composite';
+ALTER TABLE candles DROP CONSTRAINT IF EXISTS candles_exchange_check;
+ALTER TABLE candles ADD CONSTRAINT candles_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase', 'kraken'));
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_composite_symbols_user_id;
+
+-- Drop composite symbols table
+DROP TABLE IF EXISTS composite_symbols;
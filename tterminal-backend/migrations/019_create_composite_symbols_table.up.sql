@@ -0,0 +1,17 @@
+-- Create composite symbols table (user-defined spreads and ratios of other symbols)
+-- Names are unique across users: candles are stored and streamed under "COMPOSITE:<name>"
+CREATE TABLE IF NOT EXISTS composite_symbols (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(128) NOT NULL,
+    name VARCHAR(32) NOT NULL UNIQUE,
+    expression VARCHAR(200) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_composite_symbols_user_id ON composite_symbols(user_id);
+
+-- Allow composite candles ("COMPOSITE:ETHBTC" symbols)
+ALTER TABLE candles DROP CONSTRAINT IF EXISTS candles_exchange_check;
+ALTER TABLE candles ADD CONSTRAINT candles_exchange_check
+    CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase', 'kraken', 'composite'));
@@ -0,0 +1,8 @@
+-- Clear estimated taker volume, then drop the flag
+UPDATE candles
+SET taker_buy_base_asset_volume = 0,
+    taker_buy_quote_asset_volume = 0
+WHERE taker_volume_estimated;
+
+ALTER TABLE candles
+    DROP COLUMN IF EXISTS taker_volume_estimated;
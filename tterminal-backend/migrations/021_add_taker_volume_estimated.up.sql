@@ -0,0 +1,14 @@
+-- Flag candles whose taker buy volume was estimated because their source had none
+ALTER TABLE candles
+    ADD COLUMN IF NOT EXISTS taker_volume_estimated BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Estimate the split of stored candles without taker volume from where each bar closed within its
+-- range; bars without a range are split evenly. Binance klines carry real taker volume unless imported
+UPDATE candles
+SET taker_buy_base_asset_volume = volume * CASE WHEN high > low THEN (close - low) / (high - low) ELSE 0.5 END,
+    taker_buy_quote_asset_volume = quote_asset_volume * CASE WHEN high > low THEN (close - low) / (high - low) ELSE 0.5 END,
+    taker_volume_estimated = TRUE
+WHERE volume > 0
+  AND taker_buy_base_asset_volume = 0
+  AND taker_buy_quote_asset_volume = 0
+  AND (exchange <> 'binance' OR source = 'import');
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_symbol_mappings_exchange_symbol;
+
+-- Drop symbol mappings table
+DROP TABLE IF EXISTS symbol_mappings;
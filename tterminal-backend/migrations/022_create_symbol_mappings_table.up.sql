@@ -0,0 +1,30 @@
+-- Create symbol mappings table: one canonical symbol ("BTC-PERP") per instrument, resolved to each
+-- exchange's own symbol in the form used by stored data and streams (bare, e.g. "BTCUSDT")
+CREATE TABLE IF NOT EXISTS symbol_mappings (
+    id BIGSERIAL PRIMARY KEY,
+    canonical VARCHAR(32) NOT NULL,
+    exchange VARCHAR(16) NOT NULL
+        CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase', 'kraken', 'hyperliquid')),
+    exchange_symbol VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (canonical, exchange)
+);
+
+-- Create index for reverse lookups from an exchange symbol
+CREATE INDEX IF NOT EXISTS idx_symbol_mappings_exchange_symbol ON symbol_mappings(exchange, exchange_symbol);
+
+-- Seed the default perpetuals (Kraken's XBT and Hyperliquid's coins are stored as BTCUSD)
+INSERT INTO symbol_mappings (canonical, exchange, exchange_symbol) VALUES
+    ('BTC-PERP', 'binance', 'BTCUSDT'),
+    ('BTC-PERP', 'bybit', 'BTCUSDT'),
+    ('BTC-PERP', 'okx', 'BTCUSDT'),
+    ('BTC-PERP', 'kraken', 'BTCUSD'),
+    ('BTC-PERP', 'hyperliquid', 'BTCUSD'),
+    ('ETH-PERP', 'binance', 'ETHUSDT'),
+    ('ETH-PERP', 'bybit', 'ETHUSDT'),
+    ('ETH-PERP', 'okx', 'ETHUSDT'),
+    ('ETH-PERP', 'kraken', 'ETHUSD'),
+    ('ETH-PERP', 'hyperliquid', 'ETHUSD'),
+    ('BTC-USD', 'coinbase', 'BTCUSD'),
+    ('ETH-USD', 'coinbase', 'ETHUSD')
+ON CONFLICT (canonical, exchange) DO NOTHING;
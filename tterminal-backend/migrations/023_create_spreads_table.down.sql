@@ -0,0 +1,8 @@
+-- Remove retention policy
+SELECT remove_retention_policy('spreads', if_exists => true);
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_spreads_pair_time;
+
+-- Drop spreads table
+DROP TABLE IF EXISTS spreads;
@@ -0,0 +1,20 @@
+-- Create spreads table (spot-perp and cross-exchange spread samples of configured pairs)
+CREATE TABLE IF NOT EXISTS spreads (
+    pair VARCHAR(110) NOT NULL,
+    time TIMESTAMPTZ NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('spot_perp', 'cross_exchange')),
+    price_a DECIMAL(20,8) NOT NULL,
+    price_b DECIMAL(20,8) NOT NULL,
+    spread DECIMAL(20,8) NOT NULL,
+    spread_bps DOUBLE PRECISION NOT NULL
+);
+
+-- Convert to hypertable for time-series optimization
+SELECT create_hypertable('spreads', 'time', chunk_time_interval => INTERVAL '1 day');
+
+-- Create unique constraint so replayed samples are ignored
+CREATE UNIQUE INDEX IF NOT EXISTS idx_spreads_pair_time
+ON spreads(pair, time DESC);
+
+-- Keep spread samples for 30 days
+SELECT add_retention_policy('spreads', INTERVAL '30 days');
@@ -0,0 +1,8 @@
+-- Remove compression policy
+SELECT remove_compression_policy('footprint_levels', if_exists => true);
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_footprint_levels_symbol_interval_time_bucket;
+
+-- Drop footprint levels table
+DROP TABLE IF EXISTS footprint_levels;
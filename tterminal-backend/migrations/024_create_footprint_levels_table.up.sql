@@ -0,0 +1,28 @@
+-- Create footprint levels table (buy/sell volume per price bucket of every closed bar, built from persisted trades)
+CREATE TABLE IF NOT EXISTS footprint_levels (
+    symbol VARCHAR(50) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    price_bucket DECIMAL(20,8) NOT NULL,
+    bucket_size DECIMAL(20,8) NOT NULL,
+    buy_volume DECIMAL(30,8) NOT NULL,
+    sell_volume DECIMAL(30,8) NOT NULL,
+    trade_count INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Convert to hypertable for time-series optimization
+SELECT create_hypertable('footprint_levels', 'open_time', chunk_time_interval => INTERVAL '7 days');
+
+-- Create unique constraint so a bar's levels are updated in place when it is rebuilt
+CREATE UNIQUE INDEX IF NOT EXISTS idx_footprint_levels_symbol_interval_time_bucket
+ON footprint_levels(symbol, interval, open_time, price_bucket);
+
+-- Compress chunks older than 7 days; footprint history is kept without retention, so it can be
+-- served months back after the raw trades expire
+ALTER TABLE footprint_levels SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'symbol, interval',
+    timescaledb.compress_orderby = 'open_time DESC, price_bucket'
+);
+SELECT add_compression_policy('footprint_levels', INTERVAL '7 days');
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_trading_orders_open;
+DROP INDEX IF EXISTS idx_trading_orders_created;
+
+-- Drop trading orders table
+DROP TABLE IF EXISTS trading_orders;
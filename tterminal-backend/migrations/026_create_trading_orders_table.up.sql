@@ -0,0 +1,27 @@
+-- Create trading orders table (live futures orders routed to Binance, with their latest known state)
+-- Amounts are kept as entered or as reported by Binance, so they are sent back exactly
+CREATE TABLE IF NOT EXISTS trading_orders (
+    id BIGSERIAL PRIMARY KEY,
+    client_order_id VARCHAR(36) NOT NULL UNIQUE,
+    exchange_order_id BIGINT,
+    symbol VARCHAR(64) NOT NULL,
+    side VARCHAR(4) NOT NULL CHECK (side IN ('BUY', 'SELL')),
+    type VARCHAR(32) NOT NULL,
+    time_in_force VARCHAR(8) NOT NULL DEFAULT '',
+    quantity VARCHAR(40) NOT NULL,
+    price VARCHAR(40) NOT NULL DEFAULT '0',
+    stop_price VARCHAR(40) NOT NULL DEFAULT '0',
+    reduce_only BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(32) NOT NULL,
+    executed_qty VARCHAR(40) NOT NULL DEFAULT '0',
+    avg_price VARCHAR(40) NOT NULL DEFAULT '0',
+    last_error TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(128) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_trading_orders_created ON trading_orders(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_trading_orders_open ON trading_orders(symbol)
+    WHERE status IN ('PENDING', 'NEW', 'PARTIALLY_FILLED');
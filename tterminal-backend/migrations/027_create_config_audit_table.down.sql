@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_config_audit_created;
+
+-- Drop config audit table
+DROP TABLE IF EXISTS config_audit;
@@ -0,0 +1,12 @@
+-- Create config audit table (one row per configuration reload, with the runtime settings it changed)
+CREATE TABLE IF NOT EXISTS config_audit (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(16) NOT NULL CHECK (source IN ('sighup', 'admin')),
+    actor VARCHAR(128) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_config_audit_created ON config_audit(created_at DESC);
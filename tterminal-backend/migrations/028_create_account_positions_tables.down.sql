@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_account_fills_symbol_traded;
+DROP INDEX IF EXISTS idx_account_fills_traded;
+
+-- Drop account positions tables
+DROP TABLE IF EXISTS account_fills;
+DROP TABLE IF EXISTS account_positions;
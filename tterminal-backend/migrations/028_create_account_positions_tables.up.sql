@@ -0,0 +1,40 @@
+-- Create account positions table (futures positions of the deployment's Binance account, kept
+-- from the user data stream; one row per symbol and position side, quantity is signed)
+-- Realized PnL and fees cover the current position and reset when it is reopened from flat;
+-- closed positions keep their row with a zero quantity until reopened
+CREATE TABLE IF NOT EXISTS account_positions (
+    symbol VARCHAR(64) NOT NULL,
+    position_side VARCHAR(8) NOT NULL DEFAULT 'BOTH',
+    quantity DECIMAL(24,8) NOT NULL DEFAULT 0,
+    entry_price DECIMAL(24,8) NOT NULL DEFAULT 0,
+    break_even_price DECIMAL(24,8) NOT NULL DEFAULT 0,
+    margin_type VARCHAR(16) NOT NULL DEFAULT '',
+    realized_pnl DECIMAL(24,8) NOT NULL DEFAULT 0,
+    fees_paid DECIMAL(24,8) NOT NULL DEFAULT 0,
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (symbol, position_side)
+);
+
+-- Create account fills table (executions reported on the user data stream)
+CREATE TABLE IF NOT EXISTS account_fills (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(64) NOT NULL,
+    position_side VARCHAR(8) NOT NULL DEFAULT 'BOTH',
+    trade_id BIGINT NOT NULL,
+    order_id BIGINT NOT NULL,
+    client_order_id VARCHAR(64) NOT NULL DEFAULT '',
+    side VARCHAR(4) NOT NULL,
+    price DECIMAL(24,8) NOT NULL,
+    quantity DECIMAL(24,8) NOT NULL,
+    realized_pnl DECIMAL(24,8) NOT NULL DEFAULT 0,
+    commission DECIMAL(24,8) NOT NULL DEFAULT 0,
+    commission_asset VARCHAR(16) NOT NULL DEFAULT '',
+    is_maker BOOLEAN NOT NULL DEFAULT FALSE,
+    traded_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (symbol, trade_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_account_fills_traded ON account_fills(traded_at DESC);
+CREATE INDEX IF NOT EXISTS idx_account_fills_symbol_traded ON account_fills(symbol, traded_at DESC);
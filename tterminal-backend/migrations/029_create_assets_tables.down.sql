@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_asset_instruments_asset;
+
+-- Drop asset tables
+DROP TABLE IF EXISTS asset_instruments;
+DROP TABLE IF EXISTS assets;
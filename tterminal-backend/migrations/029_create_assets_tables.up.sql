@@ -0,0 +1,48 @@
+-- Create assets table: one row per underlying ("BTC"), grouping its instruments across exchanges
+CREATE TABLE IF NOT EXISTS assets (
+    code VARCHAR(16) PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create asset instruments table: spot, perpetual, delivery and index instruments of an asset
+-- Symbols are bare, as in symbol_mappings; an instrument belongs to one asset
+CREATE TABLE IF NOT EXISTS asset_instruments (
+    id BIGSERIAL PRIMARY KEY,
+    asset VARCHAR(16) NOT NULL REFERENCES assets(code) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('spot', 'perp', 'delivery', 'index')),
+    exchange VARCHAR(16) NOT NULL
+        CHECK (exchange IN ('binance', 'bybit', 'okx', 'coinbase', 'kraken', 'hyperliquid', 'composite')),
+    exchange_symbol VARCHAR(32) NOT NULL,
+    price_type VARCHAR(8) NOT NULL DEFAULT 'last' CHECK (price_type IN ('last', 'mark', 'index')),
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (exchange, exchange_symbol, price_type)
+);
+
+-- Create index for loading an asset's family
+CREATE INDEX IF NOT EXISTS idx_asset_instruments_asset ON asset_instruments(asset);
+
+-- Seed the default underlyings with the instruments collected out of the box
+INSERT INTO assets (code, name) VALUES
+    ('BTC', 'Bitcoin'),
+    ('ETH', 'Ethereum')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO asset_instruments (asset, kind, exchange, exchange_symbol, price_type) VALUES
+    ('BTC', 'perp', 'binance', 'BTCUSDT', 'last'),
+    ('BTC', 'perp', 'bybit', 'BTCUSDT', 'last'),
+    ('BTC', 'perp', 'okx', 'BTCUSDT', 'last'),
+    ('BTC', 'perp', 'kraken', 'BTCUSD', 'last'),
+    ('BTC', 'perp', 'hyperliquid', 'BTCUSD', 'last'),
+    ('BTC', 'spot', 'coinbase', 'BTCUSD', 'last'),
+    ('BTC', 'index', 'binance', 'BTCUSDT', 'index'),
+    ('ETH', 'perp', 'binance', 'ETHUSDT', 'last'),
+    ('ETH', 'perp', 'bybit', 'ETHUSDT', 'last'),
+    ('ETH', 'perp', 'okx', 'ETHUSDT', 'last'),
+    ('ETH', 'perp', 'kraken', 'ETHUSD', 'last'),
+    ('ETH', 'perp', 'hyperliquid', 'ETHUSD', 'last'),
+    ('ETH', 'spot', 'coinbase', 'ETHUSD', 'last'),
+    ('ETH', 'index', 'binance', 'ETHUSDT', 'index')
+ON CONFLICT (exchange, exchange_symbol, price_type) DO NOTHING;
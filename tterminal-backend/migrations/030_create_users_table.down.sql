@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_email;
+
+-- Drop users table
+DROP TABLE IF EXISTS users;
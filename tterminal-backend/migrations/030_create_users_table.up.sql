@@ -0,0 +1,12 @@
+-- Create users table (accounts authenticating with email and password for JWT access tokens)
+CREATE TABLE IF NOT EXISTS users (
+    id VARCHAR(36) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ
+);
+
+-- Emails are stored lower-cased; one account per address
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_candle_discrepancies_symbol_detected;
+
+-- Drop candle discrepancies table
+DROP TABLE IF EXISTS candle_discrepancies;
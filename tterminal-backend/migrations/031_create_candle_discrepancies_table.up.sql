@@ -0,0 +1,28 @@
+-- Create candle discrepancies table (bars whose closed stream kline and REST-polled candle diverged
+-- beyond tolerance, with both versions and the one kept)
+CREATE TABLE IF NOT EXISTS candle_discrepancies (
+    symbol VARCHAR(50) NOT NULL,
+    interval VARCHAR(10) NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    stream_open DECIMAL(20,8) NOT NULL,
+    stream_high DECIMAL(20,8) NOT NULL,
+    stream_low DECIMAL(20,8) NOT NULL,
+    stream_close DECIMAL(20,8) NOT NULL,
+    stream_volume DECIMAL(30,8) NOT NULL,
+    stream_trade_count INTEGER NOT NULL,
+    rest_open DECIMAL(20,8) NOT NULL,
+    rest_high DECIMAL(20,8) NOT NULL,
+    rest_low DECIMAL(20,8) NOT NULL,
+    rest_close DECIMAL(20,8) NOT NULL,
+    rest_volume DECIMAL(30,8) NOT NULL,
+    rest_trade_count INTEGER NOT NULL,
+    price_diff_pct DOUBLE PRECISION NOT NULL,
+    volume_diff_pct DOUBLE PRECISION NOT NULL,
+    kept_source VARCHAR(20) NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (symbol, interval, open_time)
+);
+
+-- Create index for recent discrepancies of a symbol
+CREATE INDEX IF NOT EXISTS idx_candle_discrepancies_symbol_detected
+ON candle_discrepancies(symbol, detected_at DESC);
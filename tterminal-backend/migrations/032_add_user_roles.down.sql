@@ -0,0 +1,2 @@
+-- Drop user roles
+ALTER TABLE users DROP COLUMN IF EXISTS role;
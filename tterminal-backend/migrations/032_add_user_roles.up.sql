@@ -0,0 +1,3 @@
+-- Add roles to users: admin operates the deployment, user manages their own data, readonly only reads
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('admin', 'user', 'readonly'));
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_aggregate_versions_current;
+DROP INDEX IF EXISTS idx_aggregate_versions_range;
+
+-- Drop aggregate versions table
+DROP TABLE IF EXISTS aggregate_versions;
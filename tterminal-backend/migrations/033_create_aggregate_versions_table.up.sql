@@ -0,0 +1,22 @@
+-- Create aggregate versions table (recomputations of an aggregate over a fixed range; each version
+-- is valid from its computation until a recomputation with a different result supersedes it)
+CREATE TABLE IF NOT EXISTS aggregate_versions (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(32) NOT NULL,
+    symbol VARCHAR(50) NOT NULL,
+    interval VARCHAR(10) NOT NULL DEFAULT '',
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    data JSONB NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ
+);
+
+-- Create index for "as of" lookups of an aggregate's versions
+CREATE INDEX IF NOT EXISTS idx_aggregate_versions_range
+ON aggregate_versions(kind, symbol, interval, start_time, end_time, valid_from DESC);
+
+-- One current version per aggregate
+CREATE UNIQUE INDEX IF NOT EXISTS idx_aggregate_versions_current
+ON aggregate_versions(kind, symbol, interval, start_time, end_time) WHERE valid_to IS NULL;
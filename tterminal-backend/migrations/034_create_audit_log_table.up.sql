@@ -0,0 +1,26 @@
+-- Create audit log table (one row per state-changing API request, with its actor, redacted
+-- payload and the fields it changed)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(128) NOT NULL DEFAULT '',
+    actor_type VARCHAR(16) NOT NULL CHECK (actor_type IN ('user', 'admin_token', 'header', 'anonymous')),
+    actor_role VARCHAR(16) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    status INTEGER NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    resource VARCHAR(64) NOT NULL DEFAULT '',
+    resource_id VARCHAR(128) NOT NULL DEFAULT '',
+    payload JSONB,
+    diff JSONB,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource, resource_id, created_at DESC);
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_symbol_pauses_active;
+DROP INDEX IF EXISTS idx_symbol_pauses_symbol_paused;
+
+-- Drop symbol pauses table
+DROP TABLE IF EXISTS symbol_pauses;
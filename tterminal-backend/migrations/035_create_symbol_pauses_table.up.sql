@@ -0,0 +1,21 @@
+-- Create symbol pauses table (windows during which a symbol's collection and streaming were paused,
+-- manually or after a data anomaly; resumed_at is NULL while a pause is in effect)
+CREATE TABLE IF NOT EXISTS symbol_pauses (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    paused_by VARCHAR(100) NOT NULL DEFAULT '',
+    paused_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    paused_until TIMESTAMPTZ,
+    resumed_at TIMESTAMPTZ,
+    resumed_by VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+-- Create index for a symbol's pauses over a time range
+CREATE INDEX IF NOT EXISTS idx_symbol_pauses_symbol_paused
+ON symbol_pauses(symbol, paused_at DESC);
+
+-- At most one pause in effect per symbol
+CREATE UNIQUE INDEX IF NOT EXISTS idx_symbol_pauses_active
+ON symbol_pauses(symbol) WHERE resumed_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop user permissions
+ALTER TABLE users DROP COLUMN IF EXISTS permissions;
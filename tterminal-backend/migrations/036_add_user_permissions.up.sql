@@ -0,0 +1,2 @@
+-- Add per-action permissions to users: NULL holds every permission of the role, a list only those
+ALTER TABLE users ADD COLUMN IF NOT EXISTS permissions TEXT[];
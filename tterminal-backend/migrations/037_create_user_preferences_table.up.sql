@@ -0,0 +1,6 @@
+-- Create user preferences table (terminal preferences per user, only the values the user set)
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id VARCHAR(128) PRIMARY KEY,
+    preferences JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- Drop price alerts table
+DROP INDEX IF EXISTS idx_price_alerts_active;
+DROP INDEX IF EXISTS idx_price_alerts_user;
+DROP TABLE IF EXISTS price_alerts;
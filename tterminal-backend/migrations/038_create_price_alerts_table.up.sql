@@ -0,0 +1,25 @@
+-- Create price alerts table (user price-cross alerts and the state of their evaluation)
+CREATE TABLE IF NOT EXISTS price_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(128) NOT NULL,
+    symbol VARCHAR(32) NOT NULL,
+    market VARCHAR(16) NOT NULL DEFAULT 'futures',
+    note TEXT NOT NULL DEFAULT '',
+    price DOUBLE PRECISION NOT NULL,
+    direction VARCHAR(16) NOT NULL,
+    hysteresis DOUBLE PRECISION NOT NULL DEFAULT 0,
+    mode VARCHAR(16) NOT NULL,
+    state VARCHAR(16) NOT NULL DEFAULT 'pending',
+    armed_below BOOLEAN NOT NULL DEFAULT FALSE,
+    armed_above BOOLEAN NOT NULL DEFAULT FALSE,
+    trigger_count INTEGER NOT NULL DEFAULT 0,
+    last_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_triggered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_alerts_user ON price_alerts (user_id, id);
+
+-- Alerts that can still fire, loaded for evaluation
+CREATE INDEX IF NOT EXISTS idx_price_alerts_active ON price_alerts (symbol) WHERE state <> 'triggered';
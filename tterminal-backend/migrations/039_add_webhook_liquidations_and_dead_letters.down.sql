@@ -0,0 +1,3 @@
+-- Drop webhook dead letter index and liquidation threshold
+DROP INDEX IF EXISTS idx_webhook_deliveries_dead_letters;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS min_notional;
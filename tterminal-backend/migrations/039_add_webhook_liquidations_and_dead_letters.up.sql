@@ -0,0 +1,5 @@
+-- Add the smallest liquidation notional a webhook receives (0 receives every liquidation)
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS min_notional DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- Dead letters (deliveries out of attempts), listed and replayed per webhook
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_dead_letters ON webhook_deliveries(webhook_id, created_at DESC) WHERE status = 'failed';
@@ -0,0 +1,4 @@
+-- Drop notification channels
+ALTER TABLE price_alerts DROP COLUMN IF EXISTS webhook_ids;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS chat_id;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS channel;
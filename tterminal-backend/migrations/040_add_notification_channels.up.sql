@@ -0,0 +1,6 @@
+-- Deliver webhooks as Telegram or Discord messages; a Telegram webhook keeps its bot token as its secret
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS channel VARCHAR(16) NOT NULL DEFAULT 'http';
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS chat_id VARCHAR(128) NOT NULL DEFAULT '';
+
+-- Webhooks a price alert notifies when it fires (empty notifies those subscribed to alerts on its symbol)
+ALTER TABLE price_alerts ADD COLUMN IF NOT EXISTS webhook_ids BIGINT[] NOT NULL DEFAULT '{}';
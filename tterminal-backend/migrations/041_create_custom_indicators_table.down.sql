@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_custom_indicators_user_id;
+
+-- Drop custom indicators table
+DROP TABLE IF EXISTS custom_indicators;
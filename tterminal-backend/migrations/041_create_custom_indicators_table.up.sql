@@ -0,0 +1,13 @@
+-- Create custom indicators table (user-registered expressions evaluated against candle series)
+CREATE TABLE IF NOT EXISTS custom_indicators (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(128) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    expression TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_custom_indicators_user_id ON custom_indicators(user_id);
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Position sides: BOTH in one-way mode, LONG or SHORT in hedge mode
+const (
+	PositionSideBoth  = "BOTH"
+	PositionSideLong  = "LONG"
+	PositionSideShort = "SHORT"
+)
+
+// AccountPosition is a futures position of the deployment's Binance account, kept from the user
+// data stream. Quantity is signed: positive for long, negative for short. Realized PnL and fees
+// cover the current position and reset when it is reopened from flat or flipped
+type AccountPosition struct {
+	Symbol         string    `json:"symbol" db:"symbol"`
+	PositionSide   string    `json:"position_side" db:"position_side"`
+	Quantity       float64   `json:"quantity" db:"quantity"`
+	EntryPrice     float64   `json:"entry_price" db:"entry_price"`
+	BreakEvenPrice float64   `json:"break_even_price" db:"break_even_price"` // As reported by Binance, including fees
+	MarginType     string    `json:"margin_type" db:"margin_type"`           // cross or isolated
+	RealizedPnL    float64   `json:"realized_pnl" db:"realized_pnl"`
+	FeesPaid       float64   `json:"fees_paid" db:"fees_paid"` // Commissions paid in the quote asset
+	OpenedAt       time.Time `json:"opened_at" db:"opened_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen reports whether the position holds a quantity
+func (p *AccountPosition) IsOpen() bool {
+	return p.Quantity != 0
+}
+
+// AccountFill is an execution of an account order, as reported on the user data stream
+type AccountFill struct {
+	ID              int64     `json:"id" db:"id"`
+	Symbol          string    `json:"symbol" db:"symbol"`
+	PositionSide    string    `json:"position_side" db:"position_side"`
+	TradeID         int64     `json:"trade_id" db:"trade_id"`
+	OrderID         int64     `json:"order_id" db:"order_id"`
+	ClientOrderID   string    `json:"client_order_id" db:"client_order_id"`
+	Side            string    `json:"side" db:"side"`
+	Price           float64   `json:"price" db:"price"`
+	Quantity        float64   `json:"quantity" db:"quantity"`
+	RealizedPnL     float64   `json:"realized_pnl" db:"realized_pnl"`
+	Commission      float64   `json:"commission" db:"commission"`
+	CommissionAsset string    `json:"commission_asset" db:"commission_asset"`
+	IsMaker         bool      `json:"is_maker" db:"is_maker"`
+	TradedAt        time.Time `json:"traded_at" db:"traded_at"`
+}
+
+// AccountPositionPnL is an open account position valued at the live mark price
+type AccountPositionPnL struct {
+	AccountPosition
+	Side             string  `json:"side"` // LONG or SHORT, from the quantity's sign
+	MarkPrice        float64 `json:"mark_price"`
+	Notional         float64 `json:"notional"`
+	UnrealizedPnL    float64 `json:"unrealized_pnl"`
+	UnrealizedPnLPct float64 `json:"unrealized_pnl_pct"` // Relative to the entry notional
+	NetPnL           float64 `json:"net_pnl"`            // Realized + unrealized - fees paid
+}
+
+// AccountPositionsResponse lists the account's open positions with their combined PnL
+type AccountPositionsResponse struct {
+	Positions       []AccountPositionPnL `json:"positions"`
+	Count           int                  `json:"count"`
+	GrossNotional   float64              `json:"gross_notional"`
+	UnrealizedPnL   float64              `json:"unrealized_pnl"`
+	RealizedPnL     float64              `json:"realized_pnl"`
+	FeesPaid        float64              `json:"fees_paid"`
+	StreamConnected bool                 `json:"stream_connected"` // False while updates may be missing
+	Timestamp       int64                `json:"timestamp"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Aggregate kinds with stored versions
+const (
+	AggregateVolumeProfile = "volume_profile"
+	AggregateCVD           = "cvd"
+)
+
+// AggregateVersion is one computation of an aggregate over a fixed range. It is valid from its
+// computation until a recomputation with a different result (after a data correction) supersedes
+// it, so the aggregate can be requested "as of" any earlier computation time
+type AggregateVersion struct {
+	ID        int64           `json:"id" db:"id"`
+	Kind      string          `json:"kind" db:"kind"`                   // Aggregate* kind
+	Symbol    string          `json:"symbol" db:"symbol"`               // Exchange-qualified symbol
+	Interval  string          `json:"interval,omitempty" db:"interval"` // Empty for volume profiles
+	StartTime time.Time       `json:"start_time" db:"start_time"`
+	EndTime   time.Time       `json:"end_time" db:"end_time"`
+	Checksum  string          `json:"checksum" db:"checksum"` // SHA-256 of the data
+	ValidFrom time.Time       `json:"valid_from" db:"valid_from"`
+	ValidTo   *time.Time      `json:"valid_to,omitempty" db:"valid_to"` // Nil for the current version
+	Data      json.RawMessage `json:"-" db:"data"`
+}
+
+// CVDBar is one bar of a cumulative volume delta series
+type CVDBar struct {
+	T  int64   `json:"t"`           // Open time (Unix milliseconds)
+	D  float64 `json:"d"`           // Taker buy minus taker sell volume
+	CD float64 `json:"cd"`          // Cumulative delta from the first bar of the range
+	E  bool    `json:"e,omitempty"` // Delta estimated (see EstimateTakerVolume)
+}
+
+// CVDSeries is the cumulative volume delta of a symbol's bars opening in [st, et], oldest first
+type CVDSeries struct {
+	S  string   `json:"s"`  // Symbol
+	I  string   `json:"i"`  // Interval
+	ST int64    `json:"st"` // Start time
+	ET int64    `json:"et"` // End time
+	D  []CVDBar `json:"d"`  // Bars
+}
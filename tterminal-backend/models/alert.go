@@ -0,0 +1,149 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Alert trigger directions
+const (
+	AlertDirectionCrossUp   = "cross_up"   // Price rises through the trigger price
+	AlertDirectionCrossDown = "cross_down" // Price falls through the trigger price
+	AlertDirectionCross     = "cross"      // Either direction
+)
+
+// Alert trigger modes
+const (
+	AlertModeOnce   = "once"   // Fire once, then stay triggered
+	AlertModeRepeat = "repeat" // Re-arm after price leaves the hysteresis band
+)
+
+// Markets whose last price a price alert follows
+const (
+	AlertMarketFutures = "futures" // Binance USD-M futures
+	AlertMarketSpot    = "spot"    // Binance spot
+)
+
+// Alert states
+const (
+	AlertStatePending   = "pending"   // Waiting for price to move beyond the hysteresis band on the arming side
+	AlertStateArmed     = "armed"     // Ready to fire on the next cross of the trigger price
+	AlertStateTriggered = "triggered" // One-shot alert has fired
+)
+
+// AlertCondition defines when a price alert fires
+// Hysteresis is an absolute price distance: a cross up only arms once price has been below
+// Price - Hysteresis (and a cross down above Price + Hysteresis), so ticks oscillating around
+// the trigger price cannot fire the alert repeatedly
+type AlertCondition struct {
+	Price      float64 `json:"price"`
+	Direction  string  `json:"direction"`  // cross_up, cross_down or cross
+	Hysteresis float64 `json:"hysteresis"` // Re-arm distance from the trigger price (0 = re-arm on any move back)
+	Mode       string  `json:"mode"`       // once or repeat
+}
+
+// AlertState is the server-side state machine of an alert, persisted between evaluations
+type AlertState struct {
+	State           string     `json:"state"`
+	ArmedBelow      bool       `json:"armed_below"` // Price was below the band, a cross up can fire
+	ArmedAbove      bool       `json:"armed_above"` // Price was above the band, a cross down can fire
+	TriggerCount    int        `json:"trigger_count"`
+	LastPrice       float64    `json:"last_price"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+}
+
+// AlertTrigger describes a single firing of an alert
+type AlertTrigger struct {
+	Direction    string    `json:"direction"` // cross_up or cross_down
+	TriggerPrice float64   `json:"trigger_price"`
+	Price        float64   `json:"price"` // Price that crossed the trigger
+	TriggerCount int       `json:"trigger_count"`
+	TriggeredAt  time.Time `json:"triggered_at"`
+}
+
+// Normalize fills defaults for an alert condition (cross, once)
+func (c *AlertCondition) Normalize() {
+	if c.Direction == "" {
+		c.Direction = AlertDirectionCross
+	}
+	if c.Mode == "" {
+		c.Mode = AlertModeOnce
+	}
+}
+
+// Validate checks an alert condition after Normalize
+func (c *AlertCondition) Validate() error {
+	if c.Price <= 0 {
+		return fmt.Errorf("price must be positive")
+	}
+	switch c.Direction {
+	case AlertDirectionCrossUp, AlertDirectionCrossDown, AlertDirectionCross:
+	default:
+		return fmt.Errorf("invalid direction %q, use cross_up, cross_down or cross", c.Direction)
+	}
+	switch c.Mode {
+	case AlertModeOnce, AlertModeRepeat:
+	default:
+		return fmt.Errorf("invalid mode %q, use once or repeat", c.Mode)
+	}
+	if c.Hysteresis < 0 {
+		return fmt.Errorf("hysteresis cannot be negative")
+	}
+	if c.Hysteresis >= c.Price {
+		return fmt.Errorf("hysteresis must be smaller than the trigger price")
+	}
+	return nil
+}
+
+// NewAlertState returns the initial state of an alert before any price has been seen
+func NewAlertState() AlertState {
+	return AlertState{State: AlertStatePending}
+}
+
+// PriceAlert is a user's persisted price-cross alert with the state of its evaluation
+type PriceAlert struct {
+	ID     int64  `json:"id" db:"id"`
+	UserID string `json:"user_id" db:"user_id"`
+	Symbol string `json:"symbol" db:"symbol"`
+	Market string `json:"market" db:"market"` // futures or spot
+	Note   string `json:"note,omitempty" db:"note"`
+	AlertCondition
+	AlertState
+	WebhookIDs []int64   `json:"webhook_ids" db:"webhook_ids"` // Webhooks notified when it fires; empty notifies those subscribed to alerts on its symbol
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreatePriceAlertRequest represents the request structure for creating a price alert
+type CreatePriceAlertRequest struct {
+	Symbol string `json:"symbol"`
+	Market string `json:"market"` // Defaults to futures
+	Note   string `json:"note"`
+	AlertCondition
+	WebhookIDs []int64 `json:"webhook_ids"` // The user's webhooks (including Telegram and Discord channels) notified when it fires
+}
+
+// AlertPreviewRequest represents the request structure for previewing an alert against history
+type AlertPreviewRequest struct {
+	Symbol        string `json:"symbol"`
+	Market        string `json:"market"`         // Defaults to futures, the only market with stored candles
+	LookbackHours int    `json:"lookback_hours"` // Defaults to 24
+	AlertCondition
+}
+
+// AlertPreview reports when an alert would have fired over a past window
+// Candles are replayed as open, nearer extreme, farther extreme, close, and a firing is stamped
+// with the open time of the candle it happened in
+type AlertPreview struct {
+	Symbol string `json:"symbol"`
+	Market string `json:"market"`
+	AlertCondition
+	Interval     string         `json:"interval"`
+	StartTime    time.Time      `json:"start_time"`
+	EndTime      time.Time      `json:"end_time"`
+	Candles      int            `json:"candles"`
+	TriggerCount int            `json:"trigger_count"`
+	Triggers     []AlertTrigger `json:"triggers"`
+	Truncated    bool           `json:"truncated"` // More firings than listed
+	FinalState   AlertState     `json:"final_state"`
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// Instrument kinds of an asset
+const (
+	InstrumentKindSpot     = "spot"
+	InstrumentKindPerp     = "perp"
+	InstrumentKindDelivery = "delivery" // Dated futures
+	InstrumentKindIndex    = "index"    // Index prices: price_type index klines or composite symbols
+)
+
+// InstrumentKinds lists the instrument kinds
+var InstrumentKinds = []string{InstrumentKindSpot, InstrumentKindPerp, InstrumentKindDelivery, InstrumentKindIndex}
+
+// IsValidInstrumentKind reports whether kind is an instrument kind
+func IsValidInstrumentKind(kind string) bool {
+	for _, k := range InstrumentKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// assetCodePattern restricts asset codes to the underlying's ticker ("BTC", "1000PEPE")
+var assetCodePattern = regexp.MustCompile(`^[A-Z0-9]{1,16}$`)
+
+// IsValidAssetCode reports whether code has the form of an asset code
+func IsValidAssetCode(code string) bool {
+	return assetCodePattern.MatchString(code)
+}
+
+// Asset is an underlying ("BTC") grouping its spot, perpetual, delivery and index instruments
+// across exchanges
+type Asset struct {
+	Code        string            `json:"code" db:"code"`
+	Name        string            `json:"name" db:"name"`
+	Instruments []AssetInstrument `json:"instruments"` // Ordered by kind, exchange and symbol
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// AssetInstrument is one instrument of an asset
+// ExchangeSymbol is bare, as in symbol mappings; Symbol is the qualified key ("BYBIT:BTCUSDT")
+type AssetInstrument struct {
+	ID             int64      `json:"id" db:"id"`
+	Asset          string     `json:"asset" db:"asset"`
+	Kind           string     `json:"kind" db:"kind"`
+	Exchange       string     `json:"exchange" db:"exchange"`
+	ExchangeSymbol string     `json:"exchange_symbol" db:"exchange_symbol"`
+	Symbol         string     `json:"symbol"`
+	PriceType      string     `json:"price_type" db:"price_type"`           // Candles read for the instrument
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" db:"expires_at"` // Delivery instruments
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SetAssetRequest represents the request structure for creating or renaming an asset
+type SetAssetRequest struct {
+	Name string `json:"name"`
+}
+
+// SetAssetInstrumentRequest represents the request structure for adding an instrument to an asset
+// An instrument already belonging to another asset is moved
+type SetAssetInstrumentRequest struct {
+	Kind      string    `json:"kind"`
+	Exchange  string    `json:"exchange"`   // Binance when empty
+	Symbol    string    `json:"symbol"`     // Bare exchange symbol ("BTCUSDT")
+	PriceType string    `json:"price_type"` // last when empty; index for Binance index prices
+	ExpiresAt QueryTime `json:"expires_at"` // Delivery instruments
+}
+
+// AssetInstrumentMetrics are one instrument's metrics over an asset metrics window
+type AssetInstrumentMetrics struct {
+	ID             int64   `json:"id"`
+	Kind           string  `json:"kind"`
+	Symbol         string  `json:"symbol"`
+	PriceType      string  `json:"price_type"`
+	Price          float64 `json:"price"` // Live price when streamed, else the last close
+	ChangePct      float64 `json:"change_pct"`
+	Volume         float64 `json:"volume"` // Base asset
+	QuoteVolume    float64 `json:"quote_volume"`
+	VolumeSharePct float64 `json:"volume_share_pct"`    // Of the asset's quote volume
+	BasisBps       float64 `json:"basis_bps,omitempty"` // Premium over the reference price
+}
+
+// AssetMetrics aggregates an asset's instruments over a trailing window
+type AssetMetrics struct {
+	Asset              string                   `json:"asset"`
+	Hours              int                      `json:"hours"`
+	ReferenceSymbol    string                   `json:"reference_symbol,omitempty"` // Index when present, else the most traded spot
+	ReferencePrice     float64                  `json:"reference_price,omitempty"`
+	Price              float64                  `json:"price"` // Traded instrument prices weighted by quote volume
+	QuoteVolume        float64                  `json:"quote_volume"`
+	VolumeByKind       map[string]float64       `json:"volume_by_kind"`       // Quote volume
+	VolumeByExchange   map[string]float64       `json:"volume_by_exchange"`   // Quote volume
+	PriceDispersionBps float64                  `json:"price_dispersion_bps"` // Highest over lowest traded price
+	AvgPerpBasisBps    float64                  `json:"avg_perp_basis_bps"`
+	Instruments        []AssetInstrumentMetrics `json:"instruments"`
+	Missing            []string                 `json:"missing,omitempty"` // Instruments without candles in the window
+	Timestamp          int64                    `json:"timestamp"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit log actor types: how the caller of an audited request was identified
+const (
+	AuditActorUser       = "user"        // Verified access token; actor is the user ID
+	AuditActorAdminToken = "admin_token" // X-Admin-Token
+	AuditActorHeader     = "header"      // Unverified X-User-ID header (deployments without JWT_SECRET)
+	AuditActorAnonymous  = "anonymous"   // Not identified; only the client address is known
+)
+
+// AuditFieldChange is one field of a resource changed by an audited request
+type AuditFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLogEntry records one state-changing API request: who made it, what it asked for and,
+// for resources whose handlers record their state, which fields it changed
+type AuditLogEntry struct {
+	ID         int64                       `json:"id" db:"id"`
+	Actor      string                      `json:"actor" db:"actor"`           // User ID, X-User-ID or empty
+	ActorType  string                      `json:"actor_type" db:"actor_type"` // AuditActor*
+	ActorRole  string                      `json:"actor_role,omitempty" db:"actor_role"`
+	ClientIP   string                      `json:"client_ip" db:"client_ip"`
+	Method     string                      `json:"method" db:"method"`
+	Route      string                      `json:"route" db:"route"` // Route pattern, e.g. /api/v1/symbols/:symbol
+	Path       string                      `json:"path" db:"path"`
+	Query      string                      `json:"query,omitempty" db:"query"`
+	Status     int                         `json:"status" db:"status"`
+	RequestID  string                      `json:"request_id,omitempty" db:"request_id"`
+	Resource   string                      `json:"resource,omitempty" db:"resource"`
+	ResourceID string                      `json:"resource_id,omitempty" db:"resource_id"`
+	Payload    json.RawMessage             `json:"payload,omitempty" db:"payload"` // JSON request body, sensitive fields redacted
+	Diff       map[string]AuditFieldChange `json:"diff,omitempty" db:"diff"`
+	DurationMs int64                       `json:"duration_ms" db:"duration_ms"`
+	CreatedAt  time.Time                   `json:"created_at" db:"created_at"`
+}
+
+// AuditLogFilter selects audit log entries; zero fields match everything
+type AuditLogFilter struct {
+	Actor      string
+	Resource   string
+	ResourceID string
+	Method     string
+	PathPrefix string
+	Start      time.Time
+	End        time.Time
+	BeforeID   int64 // Entries older than this ID, for paging
+	Limit      int
+}
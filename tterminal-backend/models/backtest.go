@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// Backtest replay modes
+const (
+	BacktestReplayCandles = "candles" // Decisions fill at the next bar's open
+	BacktestReplayTrades  = "trades"  // Stored trades are replayed between bars; decisions fill at the next trade
+)
+
+// BacktestRequest runs a strategy over stored candles (and optionally trades) of one symbol
+type BacktestRequest struct {
+	Symbol        string             `json:"symbol"`
+	Interval      string             `json:"interval"`
+	Start         QueryTime          `json:"start"`
+	End           QueryTime          `json:"end"` // Now when empty
+	Strategy      string             `json:"strategy"`
+	Params        map[string]float64 `json:"params"`         // Strategy parameters; defaults for those omitted
+	Replay        string             `json:"replay"`         // BacktestReplay*; candles when empty
+	InitialEquity float64            `json:"initial_equity"` // 10000 when empty
+	PositionSize  float64            `json:"position_size"`  // Fraction of equity held at full signal; 1 when empty
+	FeeRate       *float64           `json:"fee_rate"`       // Fraction of notional per fill; TAKER_FEE_RATE when omitted
+	SlippageBps   float64            `json:"slippage_bps"`   // Adverse price move per fill, in basis points
+	AllowShort    *bool              `json:"allow_short"`    // true when omitted; short signals go flat when false
+}
+
+// BacktestTrade is one round trip: from opening a position to closing or flipping it
+type BacktestTrade struct {
+	Side        string    `json:"side"` // LONG or SHORT
+	EntryTime   time.Time `json:"entry_time"`
+	EntryPrice  float64   `json:"entry_price"` // Average, including slippage
+	ExitTime    time.Time `json:"exit_time"`
+	ExitPrice   float64   `json:"exit_price"` // Average, including slippage
+	Quantity    float64   `json:"quantity"`   // Largest size held
+	PnL         float64   `json:"pnl"`        // Net of fees
+	Fees        float64   `json:"fees"`
+	ReturnPct   float64   `json:"return_pct"`              // PnL relative to the entry notional
+	ClosedAtEnd bool      `json:"closed_at_end,omitempty"` // Still open at the end and closed at the last close
+}
+
+// BacktestEquityPoint is the account marked at a bar's close
+type BacktestEquityPoint struct {
+	Time        int64   `json:"t"` // Bar open time, Unix milliseconds
+	Equity      float64 `json:"equity"`
+	DrawdownPct float64 `json:"drawdown_pct"` // Below the running peak
+	Position    float64 `json:"position"`     // Signed quantity held at the close
+}
+
+// BacktestSummary holds a backtest's headline statistics
+type BacktestSummary struct {
+	InitialEquity    float64 `json:"initial_equity"`
+	FinalEquity      float64 `json:"final_equity"`
+	TotalReturnPct   float64 `json:"total_return_pct"`
+	BuyHoldReturnPct float64 `json:"buy_hold_return_pct"` // First open to last close, for comparison
+	MaxDrawdownPct   float64 `json:"max_drawdown_pct"`
+	TradeCount       int     `json:"trade_count"`
+	WinningTrades    int     `json:"winning_trades"`
+	WinRatePct       float64 `json:"win_rate_pct"`
+	ProfitFactor     float64 `json:"profit_factor"` // Gross profit over gross loss; 0 without losing trades
+	AvgTradePnL      float64 `json:"avg_trade_pnl"`
+	FeesPaid         float64 `json:"fees_paid"`
+	ExposurePct      float64 `json:"exposure_pct"` // Share of bars closed with a position
+}
+
+// BacktestResult is a completed backtest
+type BacktestResult struct {
+	Symbol         string                `json:"symbol"`
+	Interval       string                `json:"interval"`
+	Strategy       string                `json:"strategy"`
+	Params         map[string]float64    `json:"params"` // Including defaults
+	Replay         string                `json:"replay"`
+	Start          int64                 `json:"start"`
+	End            int64                 `json:"end"`
+	Bars           int                   `json:"bars"`
+	TradesReplayed int                   `json:"trades_replayed"`
+	Summary        BacktestSummary       `json:"summary"`
+	EquityCurve    []BacktestEquityPoint `json:"equity_curve"`
+	Trades         []BacktestTrade       `json:"trades"`
+	ElapsedMs      int64                 `json:"elapsed_ms"`
+}
+
+// BacktestStrategyParam is a strategy parameter and its default
+type BacktestStrategyParam struct {
+	Name        string  `json:"name"`
+	Default     float64 `json:"default"`
+	Description string  `json:"description"`
+}
+
+// BacktestStrategy describes a built-in strategy
+type BacktestStrategy struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Params      []BacktestStrategyParam `json:"params"`
+	UsesTrades  bool                    `json:"uses_trades"` // Decides on trades; needs the trades replay
+}
@@ -0,0 +1,23 @@
+package models
+
+// BarClose is the authoritative final state of a bar, emitted once per symbol/interval/bar
+type BarClose struct {
+	Type        string  `json:"type"`
+	Symbol      string  `json:"symbol"`
+	Interval    string  `json:"interval"`
+	OpenTime    int64   `json:"open_time"`
+	CloseTime   int64   `json:"close_time"`
+	Open        float64 `json:"open"`
+	High        float64 `json:"high"`
+	Low         float64 `json:"low"`
+	Close       float64 `json:"close"`
+	Volume      float64 `json:"volume"`
+	BuyVolume   float64 `json:"buy_volume"`
+	QuoteVolume float64 `json:"quote_volume"`
+	TradeCount  int64   `json:"trade_count"`
+	Source      string  `json:"source"`       // "stream" (closed kline event) or "rest" (klines endpoint)
+	ConfirmedAt int64   `json:"confirmed_at"` // Exchange time the bar was confirmed (Unix milliseconds)
+	DelayMs     int64   `json:"delay_ms"`     // Confirmation delay after the exchange-aligned boundary
+
+	Candle Candle `json:"-"` // The final bar as a candle for persistence
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Basket limits
+const (
+	MaxBasketSymbols = 20
+)
+
+// SymbolBasket is a user-defined group of symbols (e.g. an "ETH ecosystem" basket) whose order
+// flow is combined for sector-flow analysis
+type SymbolBasket struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Symbols   []string  `json:"symbols" db:"symbols"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HasSymbol reports whether symbol is a member of the basket
+func (b *SymbolBasket) HasSymbol(symbol string) bool {
+	for _, member := range b.Symbols {
+		if member == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateBasketRequest represents the request structure for creating baskets
+type CreateBasketRequest struct {
+	Name    string   `json:"name"`
+	Symbols []string `json:"symbols"`
+}
+
+// UpdateBasketRequest represents the request structure for renaming a basket or replacing its members
+type UpdateBasketRequest struct {
+	Name    string   `json:"name"`
+	Symbols []string `json:"symbols"` // Replaces the members when not empty
+}
+
+// BasketFootprintBar is the combined order flow of a basket's members in one bar
+// Member deltas are taken in quote notional (taker buy minus taker sell quote volume) so members
+// of different prices and sizes add up; R normalizes the combined delta by the traded notional
+type BasketFootprintBar struct {
+	T  int64              `json:"t"`  // Open time (Unix milliseconds)
+	D  float64            `json:"d"`  // Combined notional delta
+	V  float64            `json:"v"`  // Combined notional volume
+	R  float64            `json:"r"`  // Delta ratio D / V (-1 all selling, 1 all buying)
+	CD float64            `json:"cd"` // Cumulative notional delta from the first bar returned
+	M  map[string]float64 `json:"m"`  // Delta ratio per member present in the bar
+}
+
+// BasketFootprintResponse is a basket's combined delta series, oldest first
+type BasketFootprintResponse struct {
+	Basket   SymbolBasket         `json:"basket"`
+	Interval string               `json:"interval"`
+	Count    int                  `json:"count"`
+	Bars     []BasketFootprintBar `json:"bars"`
+}
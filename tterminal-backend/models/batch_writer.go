@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// WriterStats are the counters of one batched persistence stream (trades of an exchange, closed
+// bars, depth snapshots, ...) since startup
+type WriterStats struct {
+	Name        string     `json:"name"`
+	Written     int64      `json:"written"`              // Items stored
+	Failed      int64      `json:"failed"`               // Items of batches that failed every attempt
+	Dropped     int64      `json:"dropped"`              // Items not queued: the queue was full, or the writer closed
+	Retries     int64      `json:"retries"`              // Repeated attempts after transient errors
+	Batches     int64      `json:"batches"`              // Successful writes
+	Pending     int        `json:"pending"`              // Items queued for the next batch
+	LastWriteAt *time.Time `json:"last_write_at"`        // Null before the first successful write
+	LastError   string     `json:"last_error,omitempty"` // Of the last failed batch
+}
+
+// WriterReport lists the batched persistence streams of this instance
+type WriterReport struct {
+	InstanceID  string        `json:"instance_id"`
+	Writers     []WriterStats `json:"writers"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// BinanceOptionQuote is the market of one Binance European option contract
+// Prices are in USDT, as Binance options are quoted and settled in USDT
+type BinanceOptionQuote struct {
+	Symbol          string    `json:"symbol"` // e.g. "BTC-250627-100000-C"
+	Type            string    `json:"type"`   // OptionTypeCall or OptionTypePut
+	Strike          float64   `json:"strike"`
+	Expiry          time.Time `json:"expiry"`
+	LastPrice       float64   `json:"last_price"`
+	BidPrice        float64   `json:"bid_price,omitempty"`
+	AskPrice        float64   `json:"ask_price,omitempty"`
+	MarkPrice       float64   `json:"mark_price"`
+	MarkIV          float64   `json:"mark_iv"` // Percent
+	BidIV           float64   `json:"bid_iv,omitempty"`
+	AskIV           float64   `json:"ask_iv,omitempty"`
+	Delta           float64   `json:"delta"`
+	Gamma           float64   `json:"gamma"`
+	Theta           float64   `json:"theta"`
+	Vega            float64   `json:"vega"`
+	Volume          float64   `json:"volume"`        // 24h contracts
+	OpenInterest    float64   `json:"open_interest"` // Contracts
+	OpenInterestUSD float64   `json:"open_interest_usd"`
+}
+
+// BinanceOptionStrike pairs the call and put of a strike
+type BinanceOptionStrike struct {
+	Strike float64             `json:"strike"`
+	Call   *BinanceOptionQuote `json:"call,omitempty"`
+	Put    *BinanceOptionQuote `json:"put,omitempty"`
+}
+
+// BinanceOptionExpiry is one expiry of an options board, strikes ascending
+type BinanceOptionExpiry struct {
+	Label           string                `json:"label"` // Binance's YYMMDD expiry date, e.g. "250627"
+	Expiry          time.Time             `json:"expiry"`
+	DaysToExpiry    float64               `json:"days_to_expiry"`
+	ATMStrike       float64               `json:"atm_strike"` // Strike nearest the index price
+	ATMIV           float64               `json:"atm_iv"`     // Mean mark IV of the ATM call and put, percent
+	OpenInterest    float64               `json:"open_interest"`
+	OpenInterestUSD float64               `json:"open_interest_usd"`
+	PutCallRatio    float64               `json:"put_call_ratio"` // Put over call open interest
+	Strikes         []BinanceOptionStrike `json:"strikes,omitempty"`
+}
+
+// BinanceOptionsBoard is a snapshot of an underlying's Binance options, expiries ascending
+type BinanceOptionsBoard struct {
+	Underlying string                `json:"underlying"` // e.g. "BTC"
+	IndexPrice float64               `json:"index_price"`
+	Timestamp  int64                 `json:"timestamp"`
+	Expiries   []BinanceOptionExpiry `json:"expiries"`
+}
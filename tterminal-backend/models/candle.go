@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -23,6 +25,9 @@ type Candle struct {
 	TakerBuyBaseAssetVolume  string    `json:"taker_buy_base_asset_volume" db:"taker_buy_base_asset_volume"`
 	TakerBuyQuoteAssetVolume string    `json:"taker_buy_quote_asset_volume" db:"taker_buy_quote_asset_volume"`
 	Interval                 string    `json:"interval" db:"interval"`
+	PriceType                string    `json:"price_type,omitempty" db:"price_type"`                         // last (default), mark or index
+	Source                   string    `json:"source,omitempty" db:"source"`                                 // Where the stored candle came from (CandleSource*)
+	TakerVolumeEstimated     bool      `json:"taker_volume_estimated,omitempty" db:"taker_volume_estimated"` // Taker buy volume estimated from the bar shape
 	CreatedAt                time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt                time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -30,14 +35,21 @@ type Candle struct {
 // OptimizedCandle represents ultra-fast OHLCV data for frontend rendering
 // Compact field names and optimal data types for minimal JSON payload (70% smaller)
 type OptimizedCandle struct {
-	T  int64   `json:"t"`  // Timestamp (Unix milliseconds)
-	O  float64 `json:"o"`  // Open price
-	H  float64 `json:"h"`  // High price
-	L  float64 `json:"l"`  // Low price
-	C  float64 `json:"c"`  // Close price
-	V  float64 `json:"v"`  // Total volume
-	BV float64 `json:"bv"` // Buy volume (taker buy base asset volume)
-	SV float64 `json:"sv"` // Sell volume (total - buy volume)
+	T  int64   `json:"t"`           // Timestamp (Unix milliseconds)
+	O  float64 `json:"o"`           // Open price
+	H  float64 `json:"h"`           // High price
+	L  float64 `json:"l"`           // Low price
+	C  float64 `json:"c"`           // Close price
+	V  float64 `json:"v"`           // Total volume
+	BV float64 `json:"bv"`          // Buy volume (taker buy base asset volume)
+	SV float64 `json:"sv"`          // Sell volume (total - buy volume)
+	E  bool    `json:"e,omitempty"` // BV and SV are estimated (source had no taker volume)
+
+	// Derivatives overlays merged on request (?include=funding,oi); absent when not requested or
+	// when no data covers the bar
+	FR  *float64 `json:"fr,omitempty"`  // Last funding rate settled at or before the bar's close
+	OI  *float64 `json:"oi,omitempty"`  // Open interest at the bar's close (base asset)
+	OIV *float64 `json:"oiv,omitempty"` // Open interest value at the bar's close (quote asset)
 }
 
 // CandleResponse optimized for ultra-fast network transmission and parsing
@@ -48,6 +60,133 @@ type CandleResponse struct {
 	N int               `json:"n"`           // Count
 	F int64             `json:"f,omitempty"` // First timestamp (optional)
 	L int64             `json:"l,omitempty"` // Last timestamp (optional)
+	P string            `json:"p,omitempty"` // Price type when not last traded price (optional)
+	X []string          `json:"x,omitempty"` // Exchange symbols of a composite index (optional)
+	U string            `json:"u,omitempty"` // Volume unit when converted from the stored unit (optional)
+
+	// Set when fresh data could not be fetched and stored candles were served instead
+	Stale   bool  `json:"stale,omitempty"`
+	DataAge int64 `json:"data_age,omitempty"` // Seconds since the newest candle closed
+
+	// Cursor for the previous page (earliest open time, Unix milliseconds); absent at the start of history
+	NextCursor int64 `json:"nextCursor,omitempty"`
+
+	// Ranges of the page whose data is questionable, oldest first (optional)
+	A []CandleAnnotation `json:"a,omitempty"`
+}
+
+// Kinds of candle annotations
+const (
+	CandleAnnotationDowntime     = "downtime"      // Bars missing, or the exchange stream was disconnected
+	CandleAnnotationQuarantined  = "quarantined"   // Collection paused after an anomalous bar
+	CandleAnnotationPaused       = "paused"        // Collection paused by hand
+	CandleAnnotationCorrected    = "corrected"     // Bars corrected by hand
+	CandleAnnotationDiscrepancy  = "discrepancy"   // Stream and REST versions of the bar diverged
+	CandleAnnotationSourceSwitch = "source_switch" // The bar comes from another source than the bar before
+)
+
+// CandleAnnotation marks a time range of a candle series so charts can shade it
+type CandleAnnotation struct {
+	Kind   string `json:"kind"`
+	Start  int64  `json:"start"` // Unix milliseconds
+	End    int64  `json:"end"`   // Unix milliseconds, exclusive
+	Detail string `json:"detail,omitempty"`
+}
+
+// Price types a candle series can be built from
+const (
+	PriceTypeLast  = "last"  // Last traded price (regular klines)
+	PriceTypeMark  = "mark"  // Futures mark price klines
+	PriceTypeIndex = "index" // Futures index price klines
+)
+
+// IsValidPriceType reports whether priceType is a supported candle price type
+func IsValidPriceType(priceType string) bool {
+	switch priceType {
+	case PriceTypeLast, PriceTypeMark, PriceTypeIndex:
+		return true
+	default:
+		return false
+	}
+}
+
+// Units candle volume can be expressed in
+const (
+	VolumeUnitBase      = "base"      // Base asset quantity
+	VolumeUnitQuote     = "quote"     // Quote asset notional
+	VolumeUnitContracts = "contracts" // Futures contracts
+)
+
+// IsValidVolumeUnit reports whether unit is a supported candle volume unit
+func IsValidVolumeUnit(unit string) bool {
+	switch unit {
+	case VolumeUnitBase, VolumeUnitQuote, VolumeUnitContracts:
+		return true
+	default:
+		return false
+	}
+}
+
+// Derivatives overlays that can be merged into aggregated candles
+const (
+	CandleIncludeFunding = "funding" // Funding rate per bar
+	CandleIncludeOI      = "oi"      // Open interest per bar
+)
+
+// IsValidCandleInclude reports whether include is a supported candle overlay
+func IsValidCandleInclude(include string) bool {
+	switch include {
+	case CandleIncludeFunding, CandleIncludeOI:
+		return true
+	default:
+		return false
+	}
+}
+
+// Candle sources recorded with each stored candle, so analyses can filter or weight by origin
+const (
+	CandleSourceStream    = "ws_stream"  // Closed kline from the live WebSocket stream
+	CandleSourceRESTPoll  = "rest_poll"  // Klines fetched by collection runs or on demand
+	CandleSourceBackfill  = "backfill"   // Historical klines fetched to fill stored history
+	CandleSourceImport    = "import"     // Bulk-loaded from an external dataset
+	CandleSourceManualFix = "manual_fix" // Corrected by hand
+)
+
+// CandleSources lists every candle source
+var CandleSources = []string{CandleSourceStream, CandleSourceRESTPoll, CandleSourceBackfill, CandleSourceImport, CandleSourceManualFix}
+
+// IsValidCandleSource reports whether source is a known candle source
+func IsValidCandleSource(source string) bool {
+	for _, known := range CandleSources {
+		if source == known {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelCandles sets the source of candles that do not have one yet
+func LabelCandles(candles []Candle, source string) []Candle {
+	for i := range candles {
+		if candles[i].Source == "" {
+			candles[i].Source = source
+		}
+	}
+	return candles
+}
+
+// FilterCandlesBySource keeps the candles whose source is one of sources
+func FilterCandlesBySource(candles []Candle, sources []string) []Candle {
+	filtered := make([]Candle, 0, len(candles))
+	for _, candle := range candles {
+		for _, source := range sources {
+			if candle.Source == source {
+				filtered = append(filtered, candle)
+				break
+			}
+		}
+	}
+	return filtered
 }
 
 // Trade represents individual trade data for order flow analysis
@@ -77,6 +216,30 @@ type FootprintCandle struct {
 	POC float64          `json:"poc"` // Point of Control (highest volume price)
 }
 
+// FootprintBucketSize picks a round footprint price bucket near 0.01% of price (e.g. 10 for BTC at 100k)
+func FootprintBucketSize(price float64) float64 {
+	if price <= 0 {
+		return 1
+	}
+	return math.Pow(10, math.Floor(math.Log10(price*0.0001)))
+}
+
+// NewFootprintCandle builds a footprint bar from its price levels, deriving the totals and the POC
+func NewFootprintCandle(openTime int64, levels []FootprintLevel) FootprintCandle {
+	candle := FootprintCandle{T: openTime, L: levels}
+	var pocVolume float64
+	for _, level := range levels {
+		candle.TBV += level.BV
+		candle.TSV += level.SV
+		if volume := level.BV + level.SV; volume > pocVolume {
+			pocVolume = volume
+			candle.POC = level.P
+		}
+	}
+	candle.TD = candle.TBV - candle.TSV
+	return candle
+}
+
 // VolumeProfileLevel represents volume at price for volume profile
 type VolumeProfileLevel struct {
 	P   float64 `json:"p"`   // Price
@@ -86,14 +249,67 @@ type VolumeProfileLevel struct {
 
 // VolumeProfile represents volume distribution across price levels
 type VolumeProfile struct {
-	S   string               `json:"s"`   // Symbol
-	ST  int64                `json:"st"`  // Start time
-	ET  int64                `json:"et"`  // End time
-	L   []VolumeProfileLevel `json:"l"`   // Levels
-	POC float64              `json:"poc"` // Point of Control
-	VAH float64              `json:"vah"` // Value Area High
-	VAL float64              `json:"val"` // Value Area Low
-	VAV float64              `json:"vav"` // Value Area Volume %
+	S   string               `json:"s"`            // Symbol
+	ST  int64                `json:"st"`           // Start time
+	ET  int64                `json:"et"`           // End time
+	L   []VolumeProfileLevel `json:"l"`            // Levels
+	POC float64              `json:"poc"`          // Point of Control
+	VAH float64              `json:"vah"`          // Value Area High
+	VAL float64              `json:"val"`          // Value Area Low
+	VAV float64              `json:"vav"`          // Value Area Volume %
+	BS  float64              `json:"bs,omitempty"` // Bucket size (set when levels are bucketed)
+}
+
+// VolumeProfileBucket returns the lower bound of the fixed-size price bucket containing price,
+// rounded to the bucket size's decimals so REST levels and live deltas share exact keys
+func VolumeProfileBucket(price, bucketSize float64) float64 {
+	if bucketSize <= 0 {
+		return price
+	}
+
+	decimals, _ := IncrementDecimals(strconv.FormatFloat(bucketSize, 'f', -1, 64))
+	scale := math.Pow10(decimals)
+	return math.Round(roundDown(price, bucketSize)*scale) / scale
+}
+
+// Rebucket returns a copy of the profile with its levels folded into fixed-size price buckets,
+// the grid live "vp_delta" updates use
+func (vp *VolumeProfile) Rebucket(bucketSize float64) *VolumeProfile {
+	if bucketSize <= 0 {
+		return vp
+	}
+
+	totalVolume := 0.0
+	buckets := make(map[float64]float64)
+	for _, level := range vp.L {
+		buckets[VolumeProfileBucket(level.P, bucketSize)] += level.V
+		totalVolume += level.V
+	}
+
+	levels := make([]VolumeProfileLevel, 0, len(buckets))
+	for price, volume := range buckets {
+		level := VolumeProfileLevel{P: price, V: volume}
+		if totalVolume > 0 {
+			level.Pct = volume / totalVolume * 100
+		}
+		levels = append(levels, level)
+	}
+
+	// Keep the volume-descending order of unbucketed profiles
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].V > levels[j].V
+	})
+
+	bucketed := *vp
+	bucketed.L = levels
+	bucketed.BS = bucketSize
+	bucketed.VAH = VolumeProfileBucket(vp.VAH, bucketSize)
+	bucketed.VAL = VolumeProfileBucket(vp.VAL, bucketSize)
+	if len(levels) > 0 {
+		bucketed.POC = levels[0].P
+	}
+
+	return &bucketed
 }
 
 // Liquidation represents detected liquidation event
@@ -130,6 +346,20 @@ type CumulativeDelta struct {
 	C float64 `json:"c"` // Cumulative delta
 }
 
+// MultiDataResponse bundles candles, volume profile and liquidations for one symbol
+// Sections that failed or missed the latency budget are listed in Errors and Partial is set
+type MultiDataResponse struct {
+	Symbol        string                     `json:"symbol"`
+	Candles       map[string]*CandleResponse `json:"candles"`
+	VolumeProfile *VolumeProfile             `json:"volume_profile,omitempty"`
+	Liquidations  []Liquidation              `json:"liquidations,omitempty"`
+	Snapshot      bool                       `json:"snapshot,omitempty"`
+	BasisTime     int64                      `json:"basis_time,omitempty"` // Snapshot: every section ends here (Unix milliseconds)
+	Errors        map[string]string          `json:"errors,omitempty"`
+	Partial       bool                       `json:"partial"`
+	ElapsedMs     int64                      `json:"elapsed_ms"`
+}
+
 // RealTimeUpdate represents streaming data updates
 type RealTimeUpdate struct {
 	Type string      `json:"type"` // "candle", "trade", "orderbook", "liquidation"
@@ -169,6 +399,7 @@ func (c *Candle) ToOptimized() OptimizedCandle {
 		V:  totalVolume,
 		BV: buyVolume,
 		SV: sellVolume,
+		E:  c.TakerVolumeEstimated,
 	}
 }
 
@@ -197,6 +428,8 @@ func ParseFloat(s string) float64 {
 
 // NewOptimizedResponse creates a new optimized response for ultra-fast rendering
 func NewOptimizedResponse(symbol, interval string, candles []Candle) *CandleResponse {
+	// Candles fetched from exchanges without taker data keep delta charts continuous with an estimate
+	candles = EstimateTakerVolume(candles)
 	optimized := make([]OptimizedCandle, len(candles))
 
 	var firstTime, lastTime int64
@@ -219,6 +452,27 @@ func NewOptimizedResponse(symbol, interval string, candles []Candle) *CandleResp
 	}
 }
 
+// MarkStale flags a response built from stored candles because upstream data was unavailable
+func (r *CandleResponse) MarkStale(candles []Candle) {
+	var newestClose time.Time
+	for _, candle := range candles {
+		if candle.CloseTime.After(newestClose) {
+			newestClose = candle.CloseTime
+		}
+	}
+
+	r.Stale = true
+	r.DataAge = DataAgeSeconds(newestClose)
+}
+
+// DataAgeSeconds returns the whole seconds elapsed since t, never negative
+func DataAgeSeconds(t time.Time) int64 {
+	if age := int64(time.Since(t) / time.Second); age > 0 {
+		return age
+	}
+	return 0
+}
+
 // EstimateJSONSize estimates the JSON payload size for frontend optimization
 func (r *CandleResponse) EstimateJSONSize() int {
 	// Rough estimate: 60 bytes per candle + overhead
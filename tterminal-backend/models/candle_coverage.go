@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// CandleCoverage reports how much of a time range is stored for a symbol/interval, where the
+// stored candles came from, and when collection was paused
+type CandleCoverage struct {
+	Symbol          string              `json:"symbol"`
+	Interval        string              `json:"interval"`
+	StartTime       time.Time           `json:"start_time"`
+	EndTime         time.Time           `json:"end_time"`
+	ExpectedCandles int64               `json:"expected_candles"`
+	StoredCandles   int64               `json:"stored_candles"`
+	MissingCandles  int64               `json:"missing_candles"`
+	CoveragePercent float64             `json:"coverage_percent"`
+	Sources         []CandleSourceStats `json:"sources"`
+	Paused          bool                `json:"paused"`         // Collection of the symbol is paused now
+	PausedCandles   int64               `json:"paused_candles"` // Expected candles opening while collection was paused
+	Pauses          []SymbolPause       `json:"pauses"`         // Collection pauses overlapping the range
+}
+
+// CandleSourceStats aggregates the stored candles of one source within a coverage report
+type CandleSourceStats struct {
+	Source        string    `json:"source"`
+	Count         int64     `json:"count"`
+	SharePercent  float64   `json:"share_percent"` // Of stored candles
+	FirstOpenTime time.Time `json:"first_open_time"`
+	LastOpenTime  time.Time `json:"last_open_time"`
+	LastUpdatedAt time.Time `json:"last_updated_at"`
+}
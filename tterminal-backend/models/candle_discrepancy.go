@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// CandleValues are the compared fields of one source's version of a bar
+type CandleValues struct {
+	Open       float64 `json:"open"`
+	High       float64 `json:"high"`
+	Low        float64 `json:"low"`
+	Close      float64 `json:"close"`
+	Volume     float64 `json:"volume"`
+	TradeCount int32   `json:"trade_count"`
+}
+
+// CandleValuesOf parses the compared fields of a candle
+func CandleValuesOf(candle Candle) CandleValues {
+	return CandleValues{
+		Open:       ParseFloat(candle.Open),
+		High:       ParseFloat(candle.High),
+		Low:        ParseFloat(candle.Low),
+		Close:      ParseFloat(candle.Close),
+		Volume:     ParseFloat(candle.Volume),
+		TradeCount: candle.TradeCount,
+	}
+}
+
+// CandleDiscrepancy is a bar whose closed stream kline and REST-polled candle diverged beyond
+// tolerance. Differences are percentages of the REST value; the largest OHLC difference is kept
+type CandleDiscrepancy struct {
+	Symbol        string       `json:"symbol" db:"symbol"`
+	Interval      string       `json:"interval" db:"interval"`
+	OpenTime      time.Time    `json:"open_time" db:"open_time"`
+	Stream        CandleValues `json:"stream"`
+	REST          CandleValues `json:"rest"`
+	PriceDiffPct  float64      `json:"price_diff_pct" db:"price_diff_pct"`
+	VolumeDiffPct float64      `json:"volume_diff_pct" db:"volume_diff_pct"`
+	KeptSource    string       `json:"kept_source" db:"kept_source"` // CandleSource* of the stored version
+	DetectedAt    time.Time    `json:"detected_at" db:"detected_at"`
+}
+
+// CandleConsistencyStats counts the bars of a symbol/interval compared between stream and REST
+// since startup, and how many diverged
+type CandleConsistencyStats struct {
+	Symbol           string     `json:"symbol"`
+	Interval         string     `json:"interval"`
+	Compared         int64      `json:"compared"`
+	Diverged         int64      `json:"diverged"`
+	DivergenceRate   float64    `json:"divergence_rate"` // Diverged / Compared
+	MaxPriceDiffPct  float64    `json:"max_price_diff_pct"`
+	MaxVolumeDiffPct float64    `json:"max_volume_diff_pct"`
+	LastDivergence   *time.Time `json:"last_divergence,omitempty"` // Open time of the latest diverged bar
+}
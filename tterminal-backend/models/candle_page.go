@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// maxMonthGap is the longest spacing between consecutive monthly candles
+const maxMonthGap = 31 * 24 * time.Hour
+
+// IsCompletePage reports whether stored candle open times (Unix milliseconds, oldest first) form a
+// full page ending just before an anchor: limit candles, no missing bar between consecutive candles
+// and the newest opening within one interval of before. Incomplete pages are backfilled from the
+// exchange so scrolling charts never see gaps in stored history
+func IsCompletePage(interval string, openTimes []int64, before time.Time, limit int) bool {
+	if len(openTimes) < limit || len(openTimes) == 0 {
+		return false
+	}
+	duration, ok := IntervalDuration(interval)
+	if !ok {
+		return false
+	}
+
+	maxGap := duration.Milliseconds()
+	if interval == "1M" {
+		maxGap = maxMonthGap.Milliseconds()
+	}
+
+	if before.UnixMilli()-openTimes[len(openTimes)-1] > maxGap {
+		return false
+	}
+	for i := 1; i < len(openTimes); i++ {
+		if openTimes[i]-openTimes[i-1] > maxGap {
+			return false
+		}
+	}
+	return true
+}
+
+// CandleOpenTimes returns the open times of candles in Unix milliseconds
+func CandleOpenTimes(candles []Candle) []int64 {
+	openTimes := make([]int64, len(candles))
+	for i, candle := range candles {
+		openTimes[i] = candle.OpenTime.UnixMilli()
+	}
+	return openTimes
+}
+
+// OptimizedOpenTimes returns the open times of optimized candles in Unix milliseconds
+func OptimizedOpenTimes(candles []OptimizedCandle) []int64 {
+	openTimes := make([]int64, len(candles))
+	for i, candle := range candles {
+		openTimes[i] = candle.T
+	}
+	return openTimes
+}
+
+// SetNextCursor points the response at the page before it: the open time of its earliest candle,
+// passed back as ?cursor= to continue scrolling. A short page means the start of the exchange's
+// history was reached and no cursor is set; stale partial pages keep one so scrolling continues
+func (r *CandleResponse) SetNextCursor(limit int) {
+	if r.N > 0 && (r.N >= limit || r.Stale) {
+		r.NextCursor = r.F
+	}
+}
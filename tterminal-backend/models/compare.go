@@ -0,0 +1,25 @@
+package models
+
+// CompareSeries is one symbol's closes rebased to 100 at the comparison's base time
+type CompareSeries struct {
+	Symbol    string    `json:"symbol"`
+	BasePrice float64   `json:"base_price"` // Close at the base time, which maps to 100
+	LastPrice float64   `json:"last_price"`
+	ChangePct float64   `json:"change_pct"` // Percent change from the base time to the last bar
+	Values    []float64 `json:"values"`     // Rebased closes, parallel to the response's Times
+}
+
+// CompareResponse holds several symbols' percent-normalized closes on a shared time axis
+// Every series starts at the base time, the first bar by which all symbols have data; a bar one symbol lacks
+// repeats its previous value so the series stay aligned
+type CompareResponse struct {
+	Interval  string          `json:"interval"`
+	Start     int64           `json:"start"`             // Requested range (Unix milliseconds)
+	End       int64           `json:"end"`               // Requested range (Unix milliseconds)
+	BaseTime  int64           `json:"base_time"`         // Open time of the bar rebased to 100 (Unix milliseconds)
+	Times     []int64         `json:"t"`                 // Bar open times (Unix milliseconds)
+	Series    []CompareSeries `json:"series"`            // In request order
+	Missing   []string        `json:"missing,omitempty"` // Symbols without stored candles in the range
+	Count     int             `json:"count"`
+	Timestamp int64           `json:"timestamp"`
+}
@@ -0,0 +1,310 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Composite limits
+const (
+	MaxCompositeConstituents = 6
+	MaxCompositeExpression   = 200
+)
+
+// compositeNamePattern restricts composite names to what is safe in a symbol key
+var compositeNamePattern = regexp.MustCompile(`^[A-Z0-9_]{1,32}$`)
+
+// IsValidCompositeName reports whether name can be used for a composite symbol
+func IsValidCompositeName(name string) bool {
+	return compositeNamePattern.MatchString(name)
+}
+
+// CompositeSymbol is a user-defined synthetic series (a ratio such as BTCUSDT/ETHUSDT or a
+// spread such as ETHUSDT-2*SOLUSDT) computed from its constituents' prices
+// Its candles are stored and streamed under the symbol key "COMPOSITE:<name>"
+type CompositeSymbol struct {
+	ID           int64     `json:"id" db:"id"`
+	UserID       string    `json:"user_id" db:"user_id"`
+	Name         string    `json:"name" db:"name"`
+	Symbol       string    `json:"symbol"` // "COMPOSITE:<name>"
+	Expression   string    `json:"expression" db:"expression"`
+	Constituents []string  `json:"constituents"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateCompositeRequest represents the request structure for creating composite symbols
+type CreateCompositeRequest struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// CompositeExpression is a parsed composite expression over constituent symbol keys
+// Supports numbers, symbols, + - * / and parentheses with the usual precedence
+type CompositeExpression struct {
+	root         compositeNode
+	constituents []string
+}
+
+// compositeNode is a node of the expression tree
+type compositeNode interface {
+	eval(prices map[string]float64) (float64, error)
+}
+
+type compositeNumber float64
+
+type compositeSymbolRef string
+
+type compositeUnary struct {
+	operand compositeNode
+}
+
+type compositeBinary struct {
+	op          byte
+	left, right compositeNode
+}
+
+func (n compositeNumber) eval(map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+func (n compositeSymbolRef) eval(prices map[string]float64) (float64, error) {
+	price, ok := prices[string(n)]
+	if !ok {
+		return 0, fmt.Errorf("no price for %s", string(n))
+	}
+	return price, nil
+}
+
+func (n compositeUnary) eval(prices map[string]float64) (float64, error) {
+	value, err := n.operand.eval(prices)
+	return -value, err
+}
+
+func (n compositeBinary) eval(prices map[string]float64) (float64, error) {
+	left, err := n.left.eval(prices)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(prices)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return left / right, nil
+	}
+}
+
+// ParseCompositeExpression parses an expression such as "BTCUSDT/ETHUSDT" or
+// "ETHUSDT - 2*SOLUSDT". Symbols are upper-cased and may be exchange-qualified ("BYBIT:BTCUSDT")
+func ParseCompositeExpression(expression string) (*CompositeExpression, error) {
+	if len(expression) > MaxCompositeExpression {
+		return nil, fmt.Errorf("expression is longer than %d characters", MaxCompositeExpression)
+	}
+
+	p := &compositeParser{input: strings.ToUpper(expression), seen: make(map[string]bool)}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	if len(p.constituents) == 0 {
+		return nil, fmt.Errorf("expression must reference at least one symbol")
+	}
+	if len(p.constituents) > MaxCompositeConstituents {
+		return nil, fmt.Errorf("expression references more than %d symbols", MaxCompositeConstituents)
+	}
+
+	sort.Strings(p.constituents)
+	return &CompositeExpression{root: root, constituents: p.constituents}, nil
+}
+
+// Constituents returns the symbol keys the expression references, sorted
+func (e *CompositeExpression) Constituents() []string {
+	return e.constituents
+}
+
+// Evaluate computes the expression from constituent prices
+func (e *CompositeExpression) Evaluate(prices map[string]float64) (float64, error) {
+	value, err := e.root.eval(prices)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("expression is not finite")
+	}
+	return value, nil
+}
+
+// EvaluateCandle computes a composite bar from one constituent bar each, keyed by symbol
+// Open and close are exact. High and low are the extremes of the expression over every
+// combination of constituent highs and lows, widened to include the open and close, so they
+// bound the composite's true range rather than reproduce it
+func (e *CompositeExpression) EvaluateCandle(bars map[string]Candle) (open, high, low, close float64, err error) {
+	prices := make(map[string]float64, len(e.constituents))
+	for _, symbol := range e.constituents {
+		prices[symbol] = ParseFloat(bars[symbol].Open)
+	}
+	if open, err = e.Evaluate(prices); err != nil {
+		return
+	}
+	for _, symbol := range e.constituents {
+		prices[symbol] = ParseFloat(bars[symbol].Close)
+	}
+	if close, err = e.Evaluate(prices); err != nil {
+		return
+	}
+
+	high, low = math.Max(open, close), math.Min(open, close)
+	for corner := 0; corner < 1<<len(e.constituents); corner++ {
+		for i, symbol := range e.constituents {
+			if corner&(1<<i) != 0 {
+				prices[symbol] = ParseFloat(bars[symbol].High)
+			} else {
+				prices[symbol] = ParseFloat(bars[symbol].Low)
+			}
+		}
+		value, evalErr := e.Evaluate(prices)
+		if evalErr != nil {
+			continue
+		}
+		high = math.Max(high, value)
+		low = math.Min(low, value)
+	}
+	return
+}
+
+// compositeParser is a recursive descent parser for composite expressions
+type compositeParser struct {
+	input        string
+	pos          int
+	constituents []string
+	seen         map[string]bool
+}
+
+func (p *compositeParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// parseSum parses terms joined by + and -
+func (p *compositeParser) parseSum() (compositeNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return left, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = compositeBinary{op: op, left: left, right: right}
+	}
+}
+
+// parseProduct parses factors joined by * and /
+func (p *compositeParser) parseProduct() (compositeNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			return left, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = compositeBinary{op: op, left: left, right: right}
+	}
+}
+
+// parseFactor parses a number, a symbol, a negation or a parenthesized expression
+// A run of letters, digits, '_', ':' and '.' is a number when it parses as one and a symbol
+// otherwise, so symbols starting with digits ("1000PEPEUSDT") work
+func (p *compositeParser) parseFactor() (compositeNode, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	switch c := p.input[p.pos]; {
+	case c == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return compositeUnary{operand: operand}, nil
+
+	case c == '(':
+		p.pos++
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && isCompositeTokenChar(p.input[p.pos]) {
+		p.pos++
+	}
+	token := p.input[start:p.pos]
+	if token == "" {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[start], start)
+	}
+
+	// Tokens such as "INF" and "NAN" parse as floats but are symbols here
+	if c := token[0]; c == '.' || (c >= '0' && c <= '9') {
+		if number, err := strconv.ParseFloat(token, 64); err == nil {
+			return compositeNumber(number), nil
+		}
+	}
+	if SymbolExchange(token) == ExchangeComposite {
+		return nil, fmt.Errorf("composites cannot reference other composites (%s)", token)
+	}
+	if !p.seen[token] {
+		p.seen[token] = true
+		p.constituents = append(p.constituents, token)
+	}
+	return compositeSymbolRef(token), nil
+}
+
+// isCompositeTokenChar reports whether c can appear in a number or symbol token
+func isCompositeTokenChar(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == ':' || c == '.' || c == '_'
+}
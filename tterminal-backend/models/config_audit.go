@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Configuration reload sources
+const (
+	ConfigReloadSignal = "sighup" // SIGHUP sent to the server process
+	ConfigReloadAdmin  = "admin"  // POST /api/v1/admin/config/reload
+)
+
+// ConfigChange is one runtime setting changed by a reload
+type ConfigChange struct {
+	Setting string `json:"setting"` // Environment variable, e.g. LOG_LEVEL
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// ConfigAuditEntry records one configuration reload and what it changed
+// A reload rejected by validation changes nothing and carries the validation error
+type ConfigAuditEntry struct {
+	ID        int64          `json:"id" db:"id"`
+	Source    string         `json:"source" db:"source"`         // sighup or admin
+	Actor     string         `json:"actor,omitempty" db:"actor"` // X-User-ID of an admin reload
+	Changes   []ConfigChange `json:"changes" db:"changes"`
+	Error     string         `json:"error,omitempty" db:"error"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
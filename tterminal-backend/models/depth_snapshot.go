@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Order book sides
+const (
+	DepthSideBid = "bid"
+	DepthSideAsk = "ask"
+)
+
+// DepthLevel is one price level of an order book snapshot
+type DepthLevel struct {
+	Price    float64 `json:"price" db:"price"`
+	Quantity float64 `json:"quantity" db:"quantity"`
+}
+
+// DepthSnapshot is a persisted futures order book sample
+type DepthSnapshot struct {
+	Symbol string       `json:"symbol" db:"symbol"`
+	Time   time.Time    `json:"time" db:"snapshot_time"`
+	Bids   []DepthLevel `json:"bids"`
+	Asks   []DepthLevel `json:"asks"`
+}
+
+// DepthLevelStats summarizes the resting liquidity of one price bucket over many snapshots
+type DepthLevelStats struct {
+	Side        string    // bid or ask
+	Price       float64   // Bucket lower bound
+	Snapshots   int64     // Snapshots in which the bucket held liquidity
+	AvgQuantity float64   // Average resting quantity while present
+	MaxQuantity float64   // Largest resting quantity seen
+	Touches     int64     // Snapshots in which the bucket was the best bid/ask while present
+	FirstSeen   time.Time // First snapshot with liquidity in the bucket
+	LastSeen    time.Time // Last snapshot with liquidity in the bucket
+}
+
+// Support/resistance level types
+const (
+	LevelTypeSupport    = "support"    // Resting bids below price
+	LevelTypeResistance = "resistance" // Resting asks above price
+)
+
+// SupportResistanceLevel is a price level that repeatedly held large resting liquidity
+type SupportResistanceLevel struct {
+	Price       float64 `json:"price"`        // Bucket lower bound
+	Type        string  `json:"type"`         // support or resistance
+	Strength    float64 `json:"strength"`     // Combined score (0-100)
+	Persistence float64 `json:"persistence"`  // Share of snapshots with liquidity at the level (0-1)
+	SizeRatio   float64 `json:"size_ratio"`   // Average resting size relative to the median level on that side
+	AvgQuantity float64 `json:"avg_quantity"` // Average resting quantity while present
+	MaxQuantity float64 `json:"max_quantity"` // Largest resting quantity seen
+	Touches     int64   `json:"touches"`      // Snapshots where price reached the level and the liquidity held
+	FirstSeen   int64   `json:"first_seen"`   // Unix milliseconds
+	LastSeen    int64   `json:"last_seen"`    // Unix milliseconds
+}
+
+// SupportResistanceResponse contains detected support/resistance levels for a symbol
+type SupportResistanceResponse struct {
+	Symbol     string                   `json:"symbol"`
+	Hours      int                      `json:"hours"`       // Lookback window
+	BucketSize float64                  `json:"bucket_size"` // Price bucket width
+	Snapshots  int64                    `json:"snapshots"`   // Book snapshots analyzed
+	Levels     []SupportResistanceLevel `json:"levels"`      // Strongest first
+	Count      int                      `json:"count"`
+	Timestamp  int64                    `json:"timestamp"`
+}
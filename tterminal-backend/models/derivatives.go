@@ -0,0 +1,60 @@
+package models
+
+// DerivativesSnapshot combines funding, open interest, positioning, liquidation and basis
+// data for a futures symbol so the derivatives panel loads with a single request
+type DerivativesSnapshot struct {
+	Symbol          string  `json:"symbol"`
+	MarkPrice       float64 `json:"mark_price"`
+	IndexPrice      float64 `json:"index_price"`
+	FundingRate     float64 `json:"funding_rate"`
+	NextFundingTime int64   `json:"next_funding_time"`
+
+	OpenInterest          float64 `json:"open_interest"`            // Contracts (base asset)
+	OpenInterestValue     float64 `json:"open_interest_value"`      // Notional (quote asset)
+	OpenInterestChangePct float64 `json:"open_interest_change_pct"` // Change over the last 24h
+
+	LongShortRatio  float64 `json:"long_short_ratio"`
+	LongAccountPct  float64 `json:"long_account_pct"`
+	ShortAccountPct float64 `json:"short_account_pct"`
+
+	Liquidations LiquidationTotals `json:"liquidations"`
+
+	Basis    float64 `json:"basis"`     // Mark price minus index price
+	BasisPct float64 `json:"basis_pct"` // Basis relative to index price in percent
+
+	Timestamp int64             `json:"timestamp"`
+	Errors    map[string]string `json:"errors,omitempty"` // Sections that could not be loaded
+
+	// Set when REST-backed sections were carried over from an earlier snapshot
+	Stale   bool  `json:"stale,omitempty"`
+	DataAge int64 `json:"data_age,omitempty"` // Seconds since the carried-over sections were fetched
+}
+
+// LiquidationTotals summarizes liquidations over a time window
+// Liquidations are not stored: they come from the live stream's buffer of recent liquidations,
+// which may not reach back to the window's start, so CoveredFrom reports the span they cover
+type LiquidationTotals struct {
+	WindowHours   int     `json:"window_hours"`
+	CoveredFrom   int64   `json:"covered_from"`   // Start of the span the totals cover (Unix ms); 0 when none are buffered
+	LongCount     int     `json:"long_count"`     // Long positions liquidated (SELL orders)
+	ShortCount    int     `json:"short_count"`    // Short positions liquidated (BUY orders)
+	LongNotional  float64 `json:"long_notional"`  // Quote notional of long liquidations
+	ShortNotional float64 `json:"short_notional"` // Quote notional of short liquidations
+}
+
+// FundingHistoryPoint pairs the predicted and settled funding rate for one funding time
+// Either rate is null when it is unavailable (e.g. windows before the server started)
+type FundingHistoryPoint struct {
+	FundingTime          int64    `json:"funding_time"`
+	PredictedFundingRate *float64 `json:"predicted_funding_rate"`
+	ActualFundingRate    *float64 `json:"actual_funding_rate"`
+	PredictionError      *float64 `json:"prediction_error,omitempty"` // Predicted minus actual
+}
+
+// FundingHistoryResponse is the predicted-vs-actual funding series for a symbol
+type FundingHistoryResponse struct {
+	Symbol    string                `json:"symbol"`
+	Points    []FundingHistoryPoint `json:"points"`
+	Count     int                   `json:"count"`
+	Timestamp int64                 `json:"timestamp"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DrainStatus reports the progress of draining an instance for a rolling restart
+type DrainStatus struct {
+	Draining         bool           `json:"draining"`
+	StartedAt        *time.Time     `json:"started_at,omitempty"`
+	Peers            []string       `json:"peers"`       // Healthy peers offered to clients in reconnect hints
+	Connections      int            `json:"connections"` // WebSocket clients and long-polling sessions still attached
+	InFlightJobs     DrainJobCounts `json:"in_flight_jobs"`
+	CloseAt          *time.Time     `json:"close_at,omitempty"` // When remaining connections are closed
+	ReadyToTerminate bool           `json:"ready_to_terminate"` // No connections and no in-flight jobs remain
+}
+
+// DrainJobCounts counts background work still running on a draining instance
+type DrainJobCounts struct {
+	Purge          int `json:"purge"`
+	DataCollection int `json:"data_collection"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "strings"
+
+// Exchanges market data is collected from
+const (
+	ExchangeBinance     = "binance"
+	ExchangeBybit       = "bybit"
+	ExchangeOKX         = "okx"
+	ExchangeCoinbase    = "coinbase" // Spot
+	ExchangeKraken      = "kraken"
+	ExchangeHyperliquid = "hyperliquid"
+)
+
+// ExchangeComposite qualifies user-defined composite symbols ("COMPOSITE:ETHBTC"), which are
+// computed from other symbols rather than collected from an exchange
+const ExchangeComposite = "composite"
+
+// Exchanges lists the supported exchanges
+var Exchanges = []string{ExchangeBinance, ExchangeBybit, ExchangeOKX, ExchangeCoinbase, ExchangeKraken, ExchangeHyperliquid}
+
+// IsValidExchange reports whether exchange is supported
+func IsValidExchange(exchange string) bool {
+	for _, e := range Exchanges {
+		if e == exchange {
+			return true
+		}
+	}
+	return false
+}
+
+// QualifySymbol returns the symbol key used for stored data, caches and WebSocket subscriptions
+// Binance symbols stay bare so existing data and clients are unchanged; other exchanges are
+// prefixed with the exchange name ("BYBIT:BTCUSDT")
+func QualifySymbol(exchange, symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	if exchange == "" || exchange == ExchangeBinance || strings.Contains(symbol, ":") {
+		return symbol
+	}
+	return strings.ToUpper(exchange) + ":" + symbol
+}
+
+// SplitSymbol returns the exchange and the exchange's own symbol for a symbol key
+func SplitSymbol(key string) (exchange, symbol string) {
+	if prefix, rest, found := strings.Cut(key, ":"); found {
+		return strings.ToLower(prefix), rest
+	}
+	return ExchangeBinance, key
+}
+
+// SymbolExchange returns the exchange a symbol key belongs to
+func SymbolExchange(key string) string {
+	exchange, _ := SplitSymbol(key)
+	return exchange
+}
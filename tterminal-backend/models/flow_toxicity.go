@@ -0,0 +1,27 @@
+package models
+
+// FlowToxicityBar holds order flow imbalance and VPIN for one interval bar
+type FlowToxicityBar struct {
+	Time       int64   `json:"t"`           // Bar open time (Unix milliseconds)
+	BuyVolume  float64 `json:"bv"`          // Taker buy volume
+	SellVolume float64 `json:"sv"`          // Taker sell volume
+	OFI        float64 `json:"ofi"`         // Order flow imbalance (buy - sell)
+	OFIRatio   float64 `json:"ofi_ratio"`   // Imbalance normalized by bar volume (-1 to 1)
+	RollingOFI float64 `json:"ofi_rolling"` // Normalized imbalance over the rolling window
+	VPIN       float64 `json:"vpin"`        // VPIN at bar close (0 until enough buckets fill)
+}
+
+// FlowToxicityResponse contains trade flow toxicity metrics for a symbol and interval
+type FlowToxicityResponse struct {
+	Symbol         string            `json:"symbol"`
+	Interval       string            `json:"interval"`
+	BucketVolume   float64           `json:"bucket_volume"`   // Volume per VPIN bucket
+	VPINWindow     int               `json:"vpin_window"`     // Buckets averaged per VPIN value
+	RollingWindow  int               `json:"rolling_window"`  // Bars in the rolling OFI
+	CurrentVPIN    float64           `json:"current_vpin"`    // Latest VPIN value
+	VPINPercentile float64           `json:"vpin_percentile"` // Rank of current VPIN within the series (0-100)
+	Toxic          bool              `json:"toxic"`           // Current VPIN at or above the 90th percentile
+	Bars           []FlowToxicityBar `json:"bars"`
+	Count          int               `json:"count"`
+	Timestamp      int64             `json:"timestamp"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Book sides of a hidden liquidity level
+const (
+	BookSideBid = "bid"
+	BookSideAsk = "ask"
+)
+
+// HiddenLiquidityLevel is the hidden size inferred at one price level over the estimate window
+// Volume executed against a level beyond what its displayed size lost is attributed to hidden or
+// iceberg orders resting there
+type HiddenLiquidityLevel struct {
+	Side              string    `json:"side"` // BookSide*: bid levels absorb taker sells, ask levels taker buys
+	Price             float64   `json:"price"`
+	Executed          float64   `json:"executed"`           // Base volume traded at the level
+	DisplayedDecrease float64   `json:"displayed_decrease"` // Displayed size the level lost while it traded
+	HiddenEstimate    float64   `json:"hidden_estimate"`    // Executed beyond the displayed decrease
+	HiddenRatio       float64   `json:"hidden_ratio"`       // HiddenEstimate / Executed
+	Refills           int       `json:"refills"`            // Times the displayed size held or grew back while trading
+	Displayed         float64   `json:"displayed"`          // Current displayed size, 0 when removed or never seen
+	LastTrade         time.Time `json:"last_trade"`
+}
+
+// HiddenLiquidityEstimate is a symbol's inferred hidden liquidity, largest estimates first
+type HiddenLiquidityEstimate struct {
+	Symbol        string                 `json:"symbol"`
+	WindowSeconds int                    `json:"window_seconds"`
+	HiddenBid     float64                `json:"hidden_bid"` // Sum of bid level estimates
+	HiddenAsk     float64                `json:"hidden_ask"`
+	Levels        []HiddenLiquidityLevel `json:"levels"`
+	Experimental  bool                   `json:"experimental"` // Always true: estimates are heuristic
+	Timestamp     int64                  `json:"timestamp"`
+}
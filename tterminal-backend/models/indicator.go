@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Custom indicator limits
+const (
+	MaxIndicatorExpression = 256
+	MaxIndicatorNodes      = 32   // Numbers, fields, operators and calls of one expression
+	MaxIndicatorPeriod     = 1000 // Period argument of a window function
+	MaxIndicatorLookback   = 5000 // Warm-up bars an expression may need
+)
+
+// CustomIndicator is a user-registered expression over candle fields (e.g.
+// "ema(close,21) - ema(close,55)") evaluated server-side against any symbol and interval
+type CustomIndicator struct {
+	ID         int64     `json:"id" db:"id"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	Name       string    `json:"name" db:"name"`
+	Expression string    `json:"expression" db:"expression"`
+	Lookback   int       `json:"lookback"` // Warm-up bars read before a requested range
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateIndicatorRequest represents the request structure for registering custom indicators
+type CreateIndicatorRequest struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// UpdateIndicatorRequest represents the request structure for renaming a custom indicator or
+// replacing its expression
+type UpdateIndicatorRequest struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"` // Replaces the expression when not empty
+}
+
+// IndicatorBar is a custom indicator's value at one bar
+type IndicatorBar struct {
+	T int64   `json:"t"` // Open time (Unix milliseconds)
+	V float64 `json:"v"`
+}
+
+// IndicatorSeries is a custom indicator evaluated over a symbol's bars opening in [st, et],
+// oldest first; bars without a value (warm-up, division by zero) are left out
+type IndicatorSeries struct {
+	ID int64          `json:"id"` // Indicator
+	N  string         `json:"n"`  // Indicator name
+	E  string         `json:"e"`  // Expression
+	S  string         `json:"s"`  // Symbol
+	I  string         `json:"i"`  // Interval
+	ST int64          `json:"st"` // Start time
+	ET int64          `json:"et"` // End time
+	D  []IndicatorBar `json:"d"`  // Bars
+}
@@ -0,0 +1,139 @@
+package models
+
+import "time"
+
+// Interval sources
+const (
+	IntervalSourceBinance = "binance" // Served directly by Binance klines
+	IntervalSourceDerived = "derived" // Built locally by aggregating a smaller interval
+)
+
+// IntervalInfo describes a candle interval for frontend pickers
+type IntervalInfo struct {
+	Interval string `json:"interval"`
+	Seconds  int64  `json:"seconds"`
+	Source   string `json:"source"`
+}
+
+// supportedIntervals lists every candle interval the API accepts, smallest first
+// Derived intervals are appended here with IntervalSourceDerived once they are built
+var supportedIntervals = []IntervalInfo{
+	{Interval: "1s", Seconds: 1, Source: IntervalSourceBinance},
+	{Interval: "1m", Seconds: 60, Source: IntervalSourceBinance},
+	{Interval: "3m", Seconds: 3 * 60, Source: IntervalSourceBinance},
+	{Interval: "5m", Seconds: 5 * 60, Source: IntervalSourceBinance},
+	{Interval: "15m", Seconds: 15 * 60, Source: IntervalSourceBinance},
+	{Interval: "30m", Seconds: 30 * 60, Source: IntervalSourceBinance},
+	{Interval: "1h", Seconds: 3600, Source: IntervalSourceBinance},
+	{Interval: "2h", Seconds: 2 * 3600, Source: IntervalSourceBinance},
+	{Interval: "4h", Seconds: 4 * 3600, Source: IntervalSourceBinance},
+	{Interval: "6h", Seconds: 6 * 3600, Source: IntervalSourceBinance},
+	{Interval: "8h", Seconds: 8 * 3600, Source: IntervalSourceBinance},
+	{Interval: "12h", Seconds: 12 * 3600, Source: IntervalSourceBinance},
+	{Interval: "1d", Seconds: 86400, Source: IntervalSourceBinance},
+	{Interval: "3d", Seconds: 3 * 86400, Source: IntervalSourceBinance},
+	{Interval: "1w", Seconds: 7 * 86400, Source: IntervalSourceBinance},
+	{Interval: "1M", Seconds: 30 * 86400, Source: IntervalSourceBinance}, // Calendar month, approximated as 30 days
+}
+
+// SupportedIntervals returns details for every supported interval, smallest first
+func SupportedIntervals() []IntervalInfo {
+	intervals := make([]IntervalInfo, len(supportedIntervals))
+	copy(intervals, supportedIntervals)
+	return intervals
+}
+
+// SupportedIntervalNames returns the names of every supported interval, smallest first
+func SupportedIntervalNames() []string {
+	names := make([]string, len(supportedIntervals))
+	for i, info := range supportedIntervals {
+		names[i] = info.Interval
+	}
+	return names
+}
+
+// IsValidInterval checks if the interval is supported (intervals are case-sensitive: 1m is a minute, 1M a month)
+func IsValidInterval(interval string) bool {
+	_, ok := IntervalDuration(interval)
+	return ok
+}
+
+// IntervalDuration returns the length of one candle for a supported interval
+func IntervalDuration(interval string) (time.Duration, bool) {
+	for _, info := range supportedIntervals {
+		if info.Interval == interval {
+			return time.Duration(info.Seconds) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// MaxRangeCandles caps how many candles a single time range query may cover (30 days of 1m candles)
+const MaxRangeCandles = 43200
+
+// maxRangeCap keeps range limits for long intervals from overflowing time.Duration
+const maxRangeCap = 100 * 365 * 24 * time.Hour
+
+// TimeRangeChunk is one slice of a time range that fits within MaxRangeCandles
+type TimeRangeChunk struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// MaxRangeDuration returns the longest time range allowed in one query for the interval
+func MaxRangeDuration(interval string) time.Duration {
+	duration, ok := IntervalDuration(interval)
+	if !ok {
+		return 0
+	}
+	if duration > maxRangeCap/MaxRangeCandles {
+		return maxRangeCap
+	}
+	return duration * MaxRangeCandles
+}
+
+// ChunkTimeRange splits a time range into consecutive chunks no longer than MaxRangeDuration
+func ChunkTimeRange(interval string, startTime, endTime time.Time) []TimeRangeChunk {
+	maxRange := MaxRangeDuration(interval)
+	if maxRange <= 0 || !startTime.Before(endTime) {
+		return nil
+	}
+
+	var chunks []TimeRangeChunk
+	for chunkStart := startTime; chunkStart.Before(endTime); chunkStart = chunkStart.Add(maxRange) {
+		chunkEnd := chunkStart.Add(maxRange)
+		if chunkEnd.After(endTime) {
+			chunkEnd = endTime
+		}
+		chunks = append(chunks, TimeRangeChunk{StartTime: chunkStart, EndTime: chunkEnd})
+	}
+	return chunks
+}
+
+// CandleCloseTime returns the exclusive end of the candle opening at openTime, and false if
+// openTime is not an exchange-aligned open time for the interval (weeks open Monday 00:00 UTC,
+// months on the 1st)
+func CandleCloseTime(interval string, openTime time.Time) (time.Time, bool) {
+	duration, ok := IntervalDuration(interval)
+	if !ok {
+		return time.Time{}, false
+	}
+	openTime = openTime.UTC()
+
+	switch interval {
+	case "1M":
+		if openTime.Day() != 1 || openTime.Truncate(24*time.Hour) != openTime {
+			return time.Time{}, false
+		}
+		return openTime.AddDate(0, 1, 0), true
+	case "1w":
+		if openTime.Weekday() != time.Monday || openTime.Truncate(24*time.Hour) != openTime {
+			return time.Time{}, false
+		}
+	default:
+		if openTime.UnixMilli()%duration.Milliseconds() != 0 {
+			return time.Time{}, false
+		}
+	}
+	return openTime.Add(duration), true
+}
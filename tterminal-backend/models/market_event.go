@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Market event types indexed for the chart's significant events navigator
+const (
+	MarketEventRange       = "range"        // One of the largest high-low ranges of the symbol and interval
+	MarketEventVolumeSpike = "volume_spike" // Volume far above the rolling mean of the preceding bars
+	MarketEventGap         = "gap"          // Open far from the previous bar's close
+)
+
+// MarketEventTypes lists every market event type
+var MarketEventTypes = []string{MarketEventRange, MarketEventVolumeSpike, MarketEventGap}
+
+// IsValidMarketEventType checks if the event type is supported
+func IsValidMarketEventType(eventType string) bool {
+	for _, t := range MarketEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// MarketEvent is a notable closed bar detected when it closed
+// Magnitude depends on the type: range as a percent of the open, volume as a multiple of the
+// rolling mean, and gap as a signed percent of the previous close. Reference is the value the
+// bar was compared against (open, mean volume or previous close)
+type MarketEvent struct {
+	ID         int64     `json:"id"`
+	Symbol     string    `json:"symbol"`
+	Interval   string    `json:"interval"`
+	Type       string    `json:"type"`
+	OpenTime   time.Time `json:"open_time"`
+	Magnitude  float64   `json:"magnitude"`
+	Reference  float64   `json:"reference"`
+	Open       float64   `json:"open"`
+	High       float64   `json:"high"`
+	Low        float64   `json:"low"`
+	Close      float64   `json:"close"`
+	Volume     float64   `json:"volume"`
+	DetectedAt time.Time `json:"detected_at"`
+
+	Jump *EventJump `json:"jump,omitempty"`
+}
+
+// EventJump tells the chart how to load a candle page centred on an event
+// Request /candles/:symbol with interval, before and limit to place the event mid-page
+type EventJump struct {
+	Time     int64  `json:"time"`   // Event bar open time (Unix milliseconds)
+	Before   int64  `json:"before"` // Exclusive page anchor (Unix milliseconds)
+	Interval string `json:"interval"`
+	Limit    int    `json:"limit"`
+}
+
+// NewEventJump returns the page of limit bars of the event's interval with the event in the middle
+func NewEventJump(event MarketEvent, limit int) *EventJump {
+	duration, ok := IntervalDuration(event.Interval)
+	if !ok {
+		return nil
+	}
+	openTime := event.OpenTime.UnixMilli()
+	return &EventJump{
+		Time:     openTime,
+		Before:   openTime + int64(limit/2+1)*duration.Milliseconds(),
+		Interval: event.Interval,
+		Limit:    limit,
+	}
+}
+
+// MarketEventsResponse lists indexed events of a symbol, newest first
+type MarketEventsResponse struct {
+	Symbol   string        `json:"symbol"`
+	Interval string        `json:"interval"`
+	Types    []string      `json:"types"`
+	Count    int           `json:"count"`
+	Events   []MarketEvent `json:"events"`
+}
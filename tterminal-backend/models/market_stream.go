@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// The events below are the exchange-agnostic shapes every stream adapter converts its venue's
+// messages to; downstream consumers (bar closes, depth snapshots, liquidation caches, event
+// handlers) never see an exchange's wire format
+
+// KlineEvent is a normalized candle from an exchange stream
+// Prices and volumes keep the exchange's decimal strings; venues without a field report "0"
+type KlineEvent struct {
+	Symbol         string    `json:"symbol"` // Qualified symbol key
+	Interval       string    `json:"interval"`
+	OpenTime       time.Time `json:"open_time"`
+	CloseTime      time.Time `json:"close_time"` // Inclusive: the bar's last millisecond
+	Open           string    `json:"open"`
+	High           string    `json:"high"`
+	Low            string    `json:"low"`
+	Close          string    `json:"close"`
+	Volume         string    `json:"volume"` // Base units
+	QuoteVolume    string    `json:"quote_volume"`
+	BuyVolume      string    `json:"buy_volume"` // Taker buy base volume
+	BuyQuoteVolume string    `json:"buy_quote_volume"`
+	TradeCount     int64     `json:"trade_count"`
+	Closed         bool      `json:"closed"`
+}
+
+// TradeEvent is a normalized trade from an exchange stream
+type TradeEvent struct {
+	Symbol       string    `json:"symbol"`   // Qualified symbol key
+	Market       string    `json:"market"`   // "spot" or "futures"
+	TradeID      int64     `json:"trade_id"` // Aggregate trade ID for venues streaming aggregate trades
+	Price        float64   `json:"price"`
+	Quantity     float64   `json:"quantity"`       // Base units
+	IsBuyerMaker bool      `json:"is_buyer_maker"` // true = aggressive seller
+	TradeTime    time.Time `json:"trade_time"`
+}
+
+// Record returns the trade as a persisted trade record
+func (t TradeEvent) Record() TradeRecord {
+	return TradeRecord{
+		Symbol:       t.Symbol,
+		TradeID:      t.TradeID,
+		Price:        t.Price,
+		Quantity:     t.Quantity,
+		IsBuyerMaker: t.IsBuyerMaker,
+		TradeTime:    t.TradeTime,
+	}
+}
+
+// MarkPriceEvent is a normalized futures mark price and funding rate from an exchange stream
+type MarkPriceEvent struct {
+	Symbol          string    `json:"symbol"` // Qualified symbol key
+	MarkPrice       float64   `json:"mark_price"`
+	IndexPrice      float64   `json:"index_price"`
+	EstimatedPrice  float64   `json:"estimated_price"` // Estimated settle price; 0 when the venue reports none
+	FundingRate     float64   `json:"funding_rate"`
+	NextFundingTime time.Time `json:"next_funding_time"`
+	Time            time.Time `json:"time"`
+}
+
+// DepthUpdate is a normalized order book update from an exchange stream
+// Levels are [price, quantity] pairs in base units; a zero quantity removes the level
+type DepthUpdate struct {
+	Symbol   string     `json:"symbol"` // Qualified symbol key
+	Bids     [][]string `json:"bids"`
+	Asks     [][]string `json:"asks"`
+	Snapshot bool       `json:"snapshot"` // Replaces the whole book instead of updating it
+	Time     time.Time  `json:"time"`
+}
+
+// LiquidationEvent is a normalized forced liquidation from an exchange stream
+type LiquidationEvent struct {
+	Symbol     string    `json:"symbol"`      // Qualified symbol key
+	Side       string    `json:"side"`        // Liquidation order side: "SELL" closes a long, "BUY" a short
+	Price      float64   `json:"price"`       // Fill price
+	OrderPrice float64   `json:"order_price"` // Bankruptcy order price; the fill price when the venue reports one price
+	Quantity   float64   `json:"quantity"`    // Base units
+	Status     string    `json:"status"`      // Order status, "FILLED" unless the venue reports otherwise
+	TradeTime  time.Time `json:"trade_time"`
+}
+
+// PriceTick is a last price change from an exchange stream
+type PriceTick struct {
+	Symbol string    `json:"symbol"`
+	Market string    `json:"market"` // "spot" or "futures"
+	Price  float64   `json:"price"`
+	Time   time.Time `json:"time"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Option types
+const (
+	OptionTypeCall = "call"
+	OptionTypePut  = "put"
+)
+
+// OptionQuote is the market of one option instrument from a chain snapshot
+// Prices are in the underlying currency, as Deribit quotes inverse options
+type OptionQuote struct {
+	Instrument      string    `json:"instrument"` // e.g. "BTC-27JUN25-100000-C"
+	Type            string    `json:"type"`       // OptionTypeCall or OptionTypePut
+	Strike          float64   `json:"strike"`
+	Expiry          time.Time `json:"expiry"`
+	MarkPrice       float64   `json:"mark_price"`
+	MarkIV          float64   `json:"mark_iv"` // Percent
+	BidPrice        float64   `json:"bid_price,omitempty"`
+	AskPrice        float64   `json:"ask_price,omitempty"`
+	OpenInterest    float64   `json:"open_interest"` // Contracts
+	Volume          float64   `json:"volume"`        // 24h contracts
+	UnderlyingPrice float64   `json:"underlying_price"`
+}
+
+// OptionStrike pairs the call and put of a strike
+type OptionStrike struct {
+	Strike float64      `json:"strike"`
+	Call   *OptionQuote `json:"call,omitempty"`
+	Put    *OptionQuote `json:"put,omitempty"`
+}
+
+// OptionExpiry is one expiry of a chain, strikes ascending
+type OptionExpiry struct {
+	Label        string         `json:"label"` // Deribit's expiry code, e.g. "27JUN25"
+	Expiry       time.Time      `json:"expiry"`
+	DaysToExpiry float64        `json:"days_to_expiry"`
+	Forward      float64        `json:"forward"`    // Underlying price of the expiry
+	ATMStrike    float64        `json:"atm_strike"` // Strike nearest the forward
+	ATMIV        float64        `json:"atm_iv"`     // Mean mark IV of the ATM call and put, percent
+	OpenInterest float64        `json:"open_interest"`
+	PutCallRatio float64        `json:"put_call_ratio"` // Put over call open interest
+	Strikes      []OptionStrike `json:"strikes,omitempty"`
+}
+
+// OptionsChain is a snapshot of a currency's option chain, expiries ascending
+type OptionsChain struct {
+	Currency   string         `json:"currency"`
+	IndexPrice float64        `json:"index_price"`
+	Timestamp  int64          `json:"timestamp"`
+	Expiries   []OptionExpiry `json:"expiries"`
+}
+
+// IVTermStructure is the at-the-money implied volatility of every expiry of a currency
+type IVTermStructure struct {
+	Currency   string         `json:"currency"`
+	IndexPrice float64        `json:"index_price"`
+	DVOL       float64        `json:"dvol,omitempty"` // Latest DVOL index value
+	Timestamp  int64          `json:"timestamp"`
+	Expiries   []OptionExpiry `json:"expiries"` // Without strikes
+}
+
+// DVOLCandle is one bar of the Deribit implied volatility index
+type DVOLCandle struct {
+	T int64   `json:"t"` // Open time (Unix milliseconds)
+	O float64 `json:"o"`
+	H float64 `json:"h"`
+	L float64 `json:"l"`
+	C float64 `json:"c"`
+}
+
+// DVOLResponse is a DVOL candle series, oldest first
+type DVOLResponse struct {
+	Currency string       `json:"currency"`
+	Interval string       `json:"interval"`
+	Count    int          `json:"count"`
+	Candles  []DVOLCandle `json:"candles"`
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Order types checked by order validation
+const (
+	OrderTypeMarket = "MARKET"
+	OrderTypeLimit  = "LIMIT"
+)
+
+// Exchange filter names reported in order validation violations
+const (
+	FilterPrice        = "PRICE_FILTER"
+	FilterLotSize      = "LOT_SIZE"
+	FilterMinNotional  = "MIN_NOTIONAL"
+	FilterPercentPrice = "PERCENT_PRICE"
+)
+
+// filterEpsilon absorbs float error when checking multiples of a tick or step size
+const filterEpsilon = 1e-9
+
+// OrderFilterViolation describes one exchange filter an order fails, with the nearest valid value
+type OrderFilterViolation struct {
+	Filter    string  `json:"filter"` // PRICE_FILTER, LOT_SIZE, MIN_NOTIONAL or PERCENT_PRICE
+	Field     string  `json:"field"`  // quantity or price
+	Message   string  `json:"message"`
+	Suggested float64 `json:"suggested,omitempty"` // Nearest value passing the filter
+}
+
+// ValidateOrderRequest represents an order to check against exchange filters before routing
+type ValidateOrderRequest struct {
+	Symbol   string  `json:"symbol"`
+	Side     string  `json:"side"`
+	Quantity float64 `json:"quantity"`
+	Price    float64 `json:"price"` // Limit price (0 = market order at the live price)
+}
+
+// OrderValidationResult is the outcome of checking an order against a symbol's filters
+type OrderValidationResult struct {
+	Symbol         string                 `json:"symbol"`
+	Valid          bool                   `json:"valid"`
+	FiltersChecked bool                   `json:"filters_checked"` // False when no filters are stored for the symbol
+	OrderType      string                 `json:"order_type"`      // MARKET or LIMIT
+	Quantity       float64                `json:"quantity"`
+	Price          float64                `json:"price"`
+	ReferencePrice float64                `json:"reference_price,omitempty"` // Mark price used for PERCENT_PRICE
+	Violations     []OrderFilterViolation `json:"violations,omitempty"`
+}
+
+// OrderFilterError rejects an order that fails exchange filters
+type OrderFilterError struct {
+	Symbol     string
+	Violations []OrderFilterViolation
+}
+
+// Error joins the violation messages
+func (e *OrderFilterError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return fmt.Sprintf("order rejected by %s filters: %s", e.Symbol, strings.Join(messages, "; "))
+}
+
+// ValidateOrder checks quantity and price against the stored PRICE_FILTER, LOT_SIZE, MIN_NOTIONAL
+// and PERCENT_PRICE filters. Market orders skip the price filters and use price only for the
+// notional check. referencePrice is the mark price PERCENT_PRICE bounds are relative to
+// (0 skips that filter). Filters that are not stored, or set to 0, are not enforced
+func (s *Symbol) ValidateOrder(quantity, price, referencePrice float64, market bool) []OrderFilterViolation {
+	var violations []OrderFilterViolation
+	s.FillDisplay()
+
+	// PRICE_FILTER
+	tickSize := nullFloat(s.TickSize)
+	minPrice, maxPrice := nullFloat(s.MinPrice), nullFloat(s.MaxPrice)
+	switch {
+	case market:
+		// Market orders fill at the book price
+	case minPrice > 0 && price < minPrice:
+		violations = append(violations, s.violation(FilterPrice, "price",
+			fmt.Sprintf("price %s below min price %s", formatInput(price), s.formatPrice(minPrice)), minPrice))
+	case maxPrice > 0 && price > maxPrice:
+		violations = append(violations, s.violation(FilterPrice, "price",
+			fmt.Sprintf("price %s above max price %s", formatInput(price), s.formatPrice(maxPrice)), maxPrice))
+	case tickSize > 0 && !isMultiple(price-minPrice, tickSize):
+		nearest := minPrice + math.Round((price-minPrice)/tickSize)*tickSize
+		violations = append(violations, s.violation(FilterPrice, "price",
+			fmt.Sprintf("price %s not a multiple of tickSize %s, nearest valid %s",
+				formatInput(price), s.formatPrice(tickSize), s.formatPrice(nearest)), nearest))
+	}
+
+	// LOT_SIZE
+	stepSize := nullFloat(s.StepSize)
+	minQty, maxQty := nullFloat(s.MinQty), nullFloat(s.MaxQty)
+	switch {
+	case minQty > 0 && quantity < minQty:
+		violations = append(violations, s.violation(FilterLotSize, "quantity",
+			fmt.Sprintf("qty %s below minQty, nearest valid %s", formatInput(quantity), s.formatQuantity(minQty)), minQty))
+	case maxQty > 0 && quantity > maxQty:
+		violations = append(violations, s.violation(FilterLotSize, "quantity",
+			fmt.Sprintf("qty %s above maxQty, nearest valid %s", formatInput(quantity), s.formatQuantity(maxQty)), maxQty))
+	case stepSize > 0 && !isMultiple(quantity-minQty, stepSize):
+		nearest := minQty + math.Round((quantity-minQty)/stepSize)*stepSize
+		if nearest < minQty || nearest <= 0 {
+			nearest = minQty + stepSize
+		}
+		violations = append(violations, s.violation(FilterLotSize, "quantity",
+			fmt.Sprintf("qty %s not a multiple of stepSize %s, nearest valid %s",
+				formatInput(quantity), s.formatQuantity(stepSize), s.formatQuantity(nearest)), nearest))
+	}
+
+	// MIN_NOTIONAL
+	if minNotional := nullFloat(s.MinNotional); minNotional > 0 && price > 0 && quantity*price < minNotional-filterEpsilon {
+		// Smallest quantity on the step grid that reaches the minimum notional
+		needed := minNotional / price
+		if stepSize > 0 {
+			needed = math.Ceil(needed/stepSize-filterEpsilon) * stepSize
+		}
+		violations = append(violations, s.violation(FilterMinNotional, "quantity",
+			fmt.Sprintf("notional %.2f below min notional %.2f, min qty at this price %s", quantity*price, minNotional, s.formatQuantity(needed)), needed))
+	}
+
+	// PERCENT_PRICE
+	multiplierUp, multiplierDown := nullFloat(s.MultiplierUp), nullFloat(s.MultiplierDown)
+	if referencePrice > 0 && !market {
+		if upper := referencePrice * multiplierUp; multiplierUp > 0 && price > upper {
+			bound := roundDown(upper, tickSize)
+			violations = append(violations, s.violation(FilterPercentPrice, "price",
+				fmt.Sprintf("price %s more than %s above mark price %s, max valid %s",
+					formatInput(price), percentOf(multiplierUp-1), s.formatPrice(referencePrice), s.formatPrice(bound)), bound))
+		} else if lower := referencePrice * multiplierDown; multiplierDown > 0 && price < lower {
+			bound := roundUp(lower, tickSize)
+			violations = append(violations, s.violation(FilterPercentPrice, "price",
+				fmt.Sprintf("price %s more than %s below mark price %s, min valid %s",
+					formatInput(price), percentOf(1-multiplierDown), s.formatPrice(referencePrice), s.formatPrice(bound)), bound))
+		}
+	}
+
+	return violations
+}
+
+// HasOrderFilters reports whether any order filter is stored for the symbol
+func (s *Symbol) HasOrderFilters() bool {
+	return s.TickSize.Valid || s.StepSize.Valid || s.MinQty.Valid || s.MinNotional.Valid || s.MultiplierUp.Valid
+}
+
+// violation builds a violation with the suggestion rounded to the display decimals of its field
+func (s *Symbol) violation(filter, field, message string, suggested float64) OrderFilterViolation {
+	decimals := s.Display.PriceDecimals
+	if field == "quantity" {
+		decimals = s.Display.QuantityDecimals
+	}
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(suggested, 'f', decimals, 64), 64)
+
+	return OrderFilterViolation{
+		Filter:    filter,
+		Field:     field,
+		Message:   message,
+		Suggested: rounded,
+	}
+}
+
+// formatPrice formats a price with the symbol's price decimals
+func (s *Symbol) formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', s.Display.PriceDecimals, 64)
+}
+
+// formatQuantity formats a quantity with the symbol's quantity decimals
+func (s *Symbol) formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', s.Display.QuantityDecimals, 64)
+}
+
+// formatInput formats a requested value exactly as given
+func formatInput(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// nullFloat parses a nullable decimal column, returning 0 when missing
+func nullFloat(value sql.NullString) float64 {
+	if !value.Valid {
+		return 0
+	}
+	return ParseFloat(value.String)
+}
+
+// isMultiple reports whether value is a whole multiple of increment within float tolerance
+func isMultiple(value, increment float64) bool {
+	steps := value / increment
+	return math.Abs(steps-math.Round(steps)) < filterEpsilon*math.Max(1, math.Abs(steps))
+}
+
+// roundDown rounds a price down to the tick grid (no-op without a tick size)
+func roundDown(price, tickSize float64) float64 {
+	if tickSize <= 0 {
+		return price
+	}
+	return math.Floor(price/tickSize+filterEpsilon) * tickSize
+}
+
+// roundUp rounds a price up to the tick grid (no-op without a tick size)
+func roundUp(price, tickSize float64) float64 {
+	if tickSize <= 0 {
+		return price
+	}
+	return math.Ceil(price/tickSize-filterEpsilon) * tickSize
+}
+
+// percentOf formats a fraction as a percentage
+func percentOf(fraction float64) string {
+	return strconv.FormatFloat(math.Round(fraction*10000)/100, 'f', -1, 64) + "%"
+}
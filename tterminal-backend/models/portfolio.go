@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// Portfolio modes
+const (
+	PortfolioModePaper = "paper"
+	PortfolioModeReal  = "real"
+)
+
+// Order sides
+const (
+	OrderSideBuy  = "BUY"
+	OrderSideSell = "SELL"
+)
+
+// Portfolio represents a user sub-account with an isolated balance and risk limits
+type Portfolio struct {
+	ID                  int64     `json:"id" db:"id"`
+	UserID              string    `json:"user_id" db:"user_id"`
+	Name                string    `json:"name" db:"name"`
+	Mode                string    `json:"mode" db:"mode"`                                   // paper or real
+	InitialBalance      float64   `json:"initial_balance" db:"initial_balance"`             // Starting cash balance
+	Balance             float64   `json:"balance" db:"balance"`                             // Cash balance including realized PnL, fees and funding
+	RealizedPnL         float64   `json:"realized_pnl" db:"realized_pnl"`                   // Realized trading PnL before fees
+	FeesPaid            float64   `json:"fees_paid" db:"fees_paid"`                         // Trading fees paid
+	FundingAccrued      float64   `json:"funding_accrued" db:"funding_accrued"`             // Funding received (negative when paid)
+	MaxLeverage         float64   `json:"max_leverage" db:"max_leverage"`                   // Gross notional / equity limit (0 = unlimited)
+	MaxPositionNotional float64   `json:"max_position_notional" db:"max_position_notional"` // Per-symbol notional limit (0 = unlimited)
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PortfolioPosition represents a portfolio's position in a symbol
+// Quantity is signed: positive for long, negative for short. Realized PnL, fees and
+// funding cover the current position and reset when it is reopened from flat
+type PortfolioPosition struct {
+	PortfolioID      int64     `json:"portfolio_id" db:"portfolio_id"`
+	Symbol           string    `json:"symbol" db:"symbol"`
+	Quantity         float64   `json:"quantity" db:"quantity"`
+	EntryPrice       float64   `json:"entry_price" db:"entry_price"`
+	RealizedPnL      float64   `json:"realized_pnl" db:"realized_pnl"`
+	FeesPaid         float64   `json:"fees_paid" db:"fees_paid"`
+	FundingAccrued   float64   `json:"funding_accrued" db:"funding_accrued"`
+	FundingSettledAt time.Time `json:"funding_settled_at" db:"funding_settled_at"` // Last funding time applied
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PortfolioOrder represents a filled order in a portfolio
+type PortfolioOrder struct {
+	ID          int64     `json:"id" db:"id"`
+	PortfolioID int64     `json:"portfolio_id" db:"portfolio_id"`
+	Symbol      string    `json:"symbol" db:"symbol"`
+	Side        string    `json:"side" db:"side"`
+	Quantity    float64   `json:"quantity" db:"quantity"`
+	Price       float64   `json:"price" db:"price"`
+	RealizedPnL float64   `json:"realized_pnl" db:"realized_pnl"`
+	Fee         float64   `json:"fee" db:"fee"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreatePortfolioRequest represents the request structure for creating portfolios
+type CreatePortfolioRequest struct {
+	Name                string  `json:"name"`
+	Mode                string  `json:"mode"`
+	InitialBalance      float64 `json:"initial_balance"`
+	MaxLeverage         float64 `json:"max_leverage"`
+	MaxPositionNotional float64 `json:"max_position_notional"`
+}
+
+// UpdatePortfolioRequest represents the request structure for updating portfolio risk limits
+type UpdatePortfolioRequest struct {
+	Name                string   `json:"name"`
+	MaxLeverage         *float64 `json:"max_leverage"`
+	MaxPositionNotional *float64 `json:"max_position_notional"`
+}
+
+// PlaceOrderRequest represents a market order against a selected portfolio
+type PlaceOrderRequest struct {
+	PortfolioID int64   `json:"portfolio_id"`
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	Quantity    float64 `json:"quantity"`
+}
+
+// FeeSchedule holds maker and taker fee rates as fractions of notional
+type FeeSchedule struct {
+	MakerRate float64 `json:"maker_rate"`
+	TakerRate float64 `json:"taker_rate"`
+}
+
+// PositionPnL is a position valued at the live price, including fees and funding
+type PositionPnL struct {
+	Symbol           string  `json:"symbol"`
+	Quantity         float64 `json:"quantity"`
+	EntryPrice       float64 `json:"entry_price"`
+	MarkPrice        float64 `json:"mark_price"`
+	Notional         float64 `json:"notional"`
+	UnrealizedPnL    float64 `json:"unrealized_pnl"`
+	RealizedPnL      float64 `json:"realized_pnl"`
+	FeesPaid         float64 `json:"fees_paid"`
+	ExpectedCloseFee float64 `json:"expected_close_fee"` // Taker fee to close at the mark price
+	FundingAccrued   float64 `json:"funding_accrued"`
+	NetPnL           float64 `json:"net_pnl"`          // Realized + unrealized + funding - fees paid - expected close fee
+	BreakEvenPrice   float64 `json:"break_even_price"` // Close price at which net PnL is zero
+}
+
+// PortfolioPnL is the PnL report for a single portfolio
+type PortfolioPnL struct {
+	PortfolioID    int64         `json:"portfolio_id"`
+	Name           string        `json:"name"`
+	Mode           string        `json:"mode"`
+	InitialBalance float64       `json:"initial_balance"`
+	Balance        float64       `json:"balance"`
+	RealizedPnL    float64       `json:"realized_pnl"`
+	UnrealizedPnL  float64       `json:"unrealized_pnl"`
+	FeesPaid       float64       `json:"fees_paid"`
+	FundingAccrued float64       `json:"funding_accrued"`
+	Equity         float64       `json:"equity"`     // Balance plus unrealized PnL
+	ReturnPct      float64       `json:"return_pct"` // Equity change relative to initial balance
+	GrossNotional  float64       `json:"gross_notional"`
+	Leverage       float64       `json:"leverage"`
+	FeeSchedule    FeeSchedule   `json:"fee_schedule"`
+	Positions      []PositionPnL `json:"positions"`
+	Timestamp      int64         `json:"timestamp"`
+}
+
+// AggregatePnL combines the PnL reports of all of a user's portfolios
+type AggregatePnL struct {
+	UserID         string         `json:"user_id"`
+	InitialBalance float64        `json:"initial_balance"`
+	Balance        float64        `json:"balance"`
+	RealizedPnL    float64        `json:"realized_pnl"`
+	UnrealizedPnL  float64        `json:"unrealized_pnl"`
+	FeesPaid       float64        `json:"fees_paid"`
+	FundingAccrued float64        `json:"funding_accrued"`
+	Equity         float64        `json:"equity"`
+	ReturnPct      float64        `json:"return_pct"`
+	GrossNotional  float64        `json:"gross_notional"`
+	Portfolios     []PortfolioPnL `json:"portfolios"`
+	Count          int            `json:"count"`
+	Timestamp      int64          `json:"timestamp"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Datasets a purge can delete
+const (
+	PurgeDatasetCandles      = "candles"          // Last price candles
+	PurgeDatasetPriceCandles = "price_candles"    // Mark and index price candles
+	PurgeDatasetTrades       = "trades"           // Persisted futures trades
+	PurgeDatasetDepth        = "depth_levels"     // Order book snapshots
+	PurgeDatasetFootprints   = "footprint_levels" // Stored footprint bars
+)
+
+// PurgeDatasets lists every purgeable dataset, in purge order
+var PurgeDatasets = []string{PurgeDatasetCandles, PurgeDatasetPriceCandles, PurgeDatasetTrades, PurgeDatasetDepth, PurgeDatasetFootprints}
+
+// Purge job statuses
+const (
+	PurgeStatusRunning   = "running"
+	PurgeStatusCompleted = "completed"
+	PurgeStatusFailed    = "failed"
+)
+
+// PurgeRequest selects the stored data to delete for one symbol
+// Without a time range every row of the symbol is deleted; without datasets every dataset is purged
+type PurgeRequest struct {
+	Symbol            string     `json:"symbol"`
+	StartTime         *time.Time `json:"start_time,omitempty"`
+	EndTime           *time.Time `json:"end_time,omitempty"`
+	Datasets          []string   `json:"datasets,omitempty"`
+	ConfirmationToken string     `json:"confirmation_token,omitempty"`
+}
+
+// PurgePreview reports the rows a purge would delete and the token that confirms it
+type PurgePreview struct {
+	Symbol            string           `json:"symbol"`
+	StartTime         *time.Time       `json:"start_time,omitempty"`
+	EndTime           *time.Time       `json:"end_time,omitempty"`
+	Datasets          []string         `json:"datasets"`
+	Rows              map[string]int64 `json:"rows"`
+	TotalRows         int64            `json:"total_rows"`
+	ConfirmationToken string           `json:"confirmation_token"`
+	ExpiresAt         time.Time        `json:"expires_at"`
+}
+
+// PurgeJob tracks a purge running in the background
+type PurgeJob struct {
+	ID             string           `json:"id"`
+	Symbol         string           `json:"symbol"`
+	StartTime      *time.Time       `json:"start_time,omitempty"`
+	EndTime        *time.Time       `json:"end_time,omitempty"`
+	Datasets       []string         `json:"datasets"`
+	Status         string           `json:"status"`
+	Progress       float64          `json:"progress"` // Percent of time chunks processed
+	CurrentDataset string           `json:"current_dataset,omitempty"`
+	ExpectedRows   int64            `json:"expected_rows"` // Rows counted by the preview
+	DeletedRows    map[string]int64 `json:"deleted_rows"`
+	Error          string           `json:"error,omitempty"`
+	StartedAt      time.Time        `json:"started_at"`
+	FinishedAt     *time.Time       `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Query sources
+const (
+	QuerySourceCandles = "candles" // Stored candles of a symbol, by price type
+)
+
+// Candle fields a query series can be read from
+const (
+	QueryFieldOpen       = "open"
+	QueryFieldHigh       = "high"
+	QueryFieldLow        = "low"
+	QueryFieldClose      = "close"
+	QueryFieldVolume     = "volume"
+	QueryFieldBuyVolume  = "buy_volume"  // Taker buy base volume
+	QueryFieldSellVolume = "sell_volume" // Volume minus taker buy base volume
+	QueryFieldDelta      = "delta"       // Taker buy minus taker sell base volume
+	QueryFieldTrades     = "trades"
+)
+
+// Query transforms, applied in order
+const (
+	QueryOpResample  = "resample"   // Buckets into a longer interval with an aggregation
+	QueryOpEMA       = "ema"        // Exponential moving average over period points
+	QueryOpSMA       = "sma"        // Simple moving average over period points
+	QueryOpDelta     = "delta"      // Difference from the value lag points earlier
+	QueryOpPctChange = "pct_change" // Percent change from the value lag points earlier
+	QueryOpCumSum    = "cumsum"     // Running total
+)
+
+// Resample aggregations
+const (
+	QueryAggLast  = "last"
+	QueryAggFirst = "first"
+	QueryAggSum   = "sum"
+	QueryAggMean  = "mean"
+	QueryAggMax   = "max"
+	QueryAggMin   = "min"
+)
+
+// SeriesQuery is a declarative time-series query: a source series, a range and transforms
+type SeriesQuery struct {
+	Source     QuerySource      `json:"source"`
+	Range      QueryRange       `json:"range"`
+	Transforms []QueryTransform `json:"transforms"`
+}
+
+// QuerySource selects the series a query starts from
+type QuerySource struct {
+	Type      string `json:"type"` // QuerySource*; candles when empty
+	Symbol    string `json:"symbol"`
+	Exchange  string `json:"exchange"` // Binance when empty
+	Interval  string `json:"interval"`
+	Field     string `json:"field"`      // QueryField*; close when empty
+	PriceType string `json:"price_type"` // last (default), mark or index
+}
+
+// QueryRange bounds the source series by candle open time; End defaults to now
+type QueryRange struct {
+	Start QueryTime `json:"start"`
+	End   QueryTime `json:"end"`
+}
+
+// QueryTransform is one step of a query
+type QueryTransform struct {
+	Op       string `json:"op"`                 // QueryOp*
+	Interval string `json:"interval,omitempty"` // resample: target interval
+	Agg      string `json:"agg,omitempty"`      // resample: QueryAgg*, last when empty
+	Period   int    `json:"period,omitempty"`   // ema, sma
+	Lag      int    `json:"lag,omitempty"`      // delta, pct_change; 1 when empty
+}
+
+// QueryTime is a time given as Unix milliseconds or an RFC3339 string
+type QueryTime struct {
+	time.Time
+}
+
+// UnmarshalJSON accepts Unix milliseconds or an RFC3339 string
+func (t *QueryTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if ms, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		t.Time = time.UnixMilli(ms).UTC()
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("time must be Unix milliseconds or an RFC3339 string")
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		t.Time = time.UnixMilli(ms).UTC()
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("time must be Unix milliseconds or an RFC3339 string")
+	}
+	t.Time = parsed
+	return nil
+}
+
+// QueryResult is an evaluated query as columnar series
+type QueryResult struct {
+	Symbol       string    `json:"symbol"`
+	Field        string    `json:"field"`
+	Interval     string    `json:"interval"` // After any resample
+	Times        []int64   `json:"t"`        // Open times, Unix milliseconds
+	Values       []float64 `json:"v"`        // Parallel to Times
+	Count        int       `json:"count"`
+	SourcePoints int       `json:"source_points"` // Candles read before transforms
+	Timestamp    int64     `json:"timestamp"`
+}
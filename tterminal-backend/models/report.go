@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// ReportSettings stores a user's daily report watchlist and email delivery preferences
+// An empty watchlist falls back to the user's persisted WebSocket subscription symbols
+type ReportSettings struct {
+	UserID       string    `json:"user_id" db:"user_id"`
+	Watchlist    []string  `json:"watchlist" db:"watchlist"`
+	Email        string    `json:"email" db:"email"`
+	EmailEnabled bool      `json:"email_enabled" db:"email_enabled"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateReportSettingsRequest represents the request structure for updating report settings
+type UpdateReportSettingsRequest struct {
+	Watchlist    []string `json:"watchlist"`
+	Email        *string  `json:"email"`
+	EmailEnabled *bool    `json:"email_enabled"`
+}
+
+// Report is a stored daily recap
+type Report struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	ReportDate string     `json:"report_date" db:"report_date"` // UTC day (YYYY-MM-DD)
+	Recap      DailyRecap `json:"recap" db:"payload"`
+	EmailedAt  *time.Time `json:"emailed_at" db:"emailed_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ReportSummary is a report history entry without its payload
+type ReportSummary struct {
+	ID          int64      `json:"id"`
+	ReportDate  string     `json:"report_date"`
+	SymbolCount int        `json:"symbol_count"`
+	EmailedAt   *time.Time `json:"emailed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// DailyRecap summarizes a UTC day of market activity for a user's watchlist
+type DailyRecap struct {
+	Date        string        `json:"date"`
+	Symbols     []SymbolRecap `json:"symbols"`
+	GeneratedAt int64         `json:"generated_at"`
+}
+
+// SymbolRecap summarizes one symbol's day
+type SymbolRecap struct {
+	Symbol      string  `json:"symbol"`
+	Open        float64 `json:"open"`
+	High        float64 `json:"high"`
+	Low         float64 `json:"low"`
+	Close       float64 `json:"close"`
+	ChangePct   float64 `json:"change_pct"` // Close relative to open
+	RangePct    float64 `json:"range_pct"`  // High-low range relative to open
+	Volume      float64 `json:"volume"`
+	QuoteVolume float64 `json:"quote_volume"`
+	TradeCount  int32   `json:"trade_count"`
+
+	FundingRateAvg     float64 `json:"funding_rate_avg"`
+	FundingRateSum     float64 `json:"funding_rate_sum"` // Total funding a long paid over the day
+	FundingSettlements int     `json:"funding_settlements"`
+
+	Liquidations        LiquidationTotals    `json:"liquidations"`
+	NotableLiquidations []NotableLiquidation `json:"notable_liquidations"`
+
+	Errors map[string]string `json:"errors,omitempty"` // Sections that could not be loaded
+}
+
+// NotableLiquidation is one of the day's largest liquidations
+type NotableLiquidation struct {
+	Time     int64   `json:"time"`
+	Side     string  `json:"side"` // Liquidation order side (SELL closes a long)
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+	Notional float64 `json:"notional"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// Rolling window fields computed per bar
+const (
+	RollingFieldVolume = "volume" // Base volume
+	RollingFieldDelta  = "delta"  // Taker buy minus taker sell base volume
+	RollingFieldRange  = "range"  // High minus low
+)
+
+// Rolling window statistics over the trailing bars
+const (
+	RollingStatSum = "sum"
+	RollingStatAvg = "avg"
+	RollingStatMax = "max"
+	RollingStatMin = "min"
+)
+
+// Rolling window alignments
+const (
+	// RollingAlignClose stamps each value at the close time of its last bar and leaves out the
+	// forming bar, so every value is final
+	RollingAlignClose = "close"
+	// RollingAlignOpen stamps each value at the open time of its last bar, matching candle
+	// timestamps for chart overlays, and includes the forming bar
+	RollingAlignOpen = "open"
+)
+
+// RollingWindowResponse holds trailing-window statistics of bar fields as columnar series
+type RollingWindowResponse struct {
+	Symbol    string               `json:"symbol"`
+	Interval  string               `json:"interval"`
+	Window    int                  `json:"window"` // Trailing bars per value
+	Align     string               `json:"align"`
+	Times     []int64              `json:"t"`      // Unix milliseconds, per Align
+	Series    map[string][]float64 `json:"series"` // Keyed "<field>_<stat>", e.g. "volume_sum", parallel to Times
+	Count     int                  `json:"count"`
+	Timestamp int64                `json:"timestamp"`
+}
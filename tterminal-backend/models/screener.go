@@ -0,0 +1,85 @@
+package models
+
+// Screener metrics rows can be sorted and filtered by
+const (
+	ScreenerMetricChange      = "change"       // 24h price change in percent
+	ScreenerMetricVolume      = "volume"       // 24h quote volume
+	ScreenerMetricVolumeSpike = "volume_spike" // Last closed hour's volume over the average hour
+	ScreenerMetricOIChange    = "oi_change"    // 24h open interest change in percent
+	ScreenerMetricFunding     = "funding"      // Current funding rate
+)
+
+// ScreenerMetrics lists the metrics in their documented order
+var ScreenerMetrics = []string{
+	ScreenerMetricChange,
+	ScreenerMetricVolume,
+	ScreenerMetricVolumeSpike,
+	ScreenerMetricOIChange,
+	ScreenerMetricFunding,
+}
+
+// IsValidScreenerMetric reports whether a metric name is supported
+func IsValidScreenerMetric(metric string) bool {
+	for _, m := range ScreenerMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// ScreenerRow holds the ranking metrics of one tracked symbol
+// A metric is null when its source had no data for the symbol
+type ScreenerRow struct {
+	Symbol                string   `json:"symbol"`
+	LastPrice             float64  `json:"last_price"`
+	ChangePct             *float64 `json:"change_pct"`
+	QuoteVolume           *float64 `json:"quote_volume"`
+	VolumeSpike           *float64 `json:"volume_spike"` // 1.0 is an average hour
+	OpenInterestValue     *float64 `json:"open_interest_value"`
+	OpenInterestChangePct *float64 `json:"open_interest_change_pct"`
+	FundingRate           *float64 `json:"funding_rate"`
+	NextFundingTime       int64    `json:"next_funding_time,omitempty"`
+}
+
+// Metric returns a row's value for a screener metric, false when it is null
+func (r *ScreenerRow) Metric(metric string) (float64, bool) {
+	var value *float64
+	switch metric {
+	case ScreenerMetricChange:
+		value = r.ChangePct
+	case ScreenerMetricVolume:
+		value = r.QuoteVolume
+	case ScreenerMetricVolumeSpike:
+		value = r.VolumeSpike
+	case ScreenerMetricOIChange:
+		value = r.OpenInterestChangePct
+	case ScreenerMetricFunding:
+		value = r.FundingRate
+	}
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+// ScreenerParams selects, filters and orders screener rows
+type ScreenerParams struct {
+	Sort      string             // Metric to rank by
+	Ascending bool               // Lowest first instead of highest first
+	Limit     int                // At most this many rows
+	Min       map[string]float64 // Per-metric lower bounds; rows missing the metric are dropped
+	Max       map[string]float64 // Per-metric upper bounds
+}
+
+// ScreenerResponse is a ranked list of tracked symbols
+type ScreenerResponse struct {
+	Sort       string            `json:"sort"`
+	Order      string            `json:"order"` // "asc" or "desc"
+	Count      int               `json:"count"`
+	Matched    int               `json:"matched"` // Rows passing the filters before the limit
+	Tracked    int               `json:"tracked"` // Symbols screened
+	Symbols    []ScreenerRow     `json:"symbols"`
+	ComputedAt int64             `json:"computed_at"`      // When the metrics were computed (Unix milliseconds)
+	Errors     map[string]string `json:"errors,omitempty"` // Symbols whose sources partly failed
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Session recording statuses
+const (
+	RecordingStatusActive    = "recording"
+	RecordingStatusCompleted = "completed"
+)
+
+// SessionRecording describes an archive of the messages sent to one WebSocket client, used by
+// support to replay exactly what a terminal received
+type SessionRecording struct {
+	ID           string     `json:"id"`
+	ClientID     string     `json:"client_id"`
+	UserID       string     `json:"user_id,omitempty"`
+	Status       string     `json:"status"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	StoppedAt    *time.Time `json:"stopped_at,omitempty"`
+	MessageCount int        `json:"message_count"`
+	Dropped      int        `json:"dropped,omitempty"` // Messages lost because the recorder fell behind
+}
+
+// RecordedMessage is one message as written to the client
+type RecordedMessage struct {
+	Time    int64           `json:"t"` // Unix milliseconds the message was written
+	Message json.RawMessage `json:"m"`
+}
+
+// SessionRecordingPage is a recording with one page of its messages
+type SessionRecordingPage struct {
+	Recording *SessionRecording `json:"recording"`
+	Messages  []RecordedMessage `json:"messages"`
+	Offset    int               `json:"offset"`
+	Limit     int               `json:"limit"`
+	HasMore   bool              `json:"has_more"`
+}
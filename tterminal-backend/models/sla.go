@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// LatencyStats are the latency percentiles of one endpoint or WebSocket message type over the
+// rolling window, from the most recent samples
+type LatencyStats struct {
+	Key         string  `json:"key"`   // "GET /api/v1/candles/:symbol", or a WebSocket message type
+	Count       int     `json:"count"` // Samples in the window
+	P50Ms       float64 `json:"p50_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	P99Ms       float64 `json:"p99_ms"`
+	MaxMs       float64 `json:"max_ms"`
+	MeetsTarget bool    `json:"meets_target"` // p95 at or under the target
+}
+
+// SLAReport is the measured API latency and WebSocket delivery lag of this instance
+type SLAReport struct {
+	InstanceID        string         `json:"instance_id"`
+	WindowSeconds     int64          `json:"window_seconds"`
+	TargetMs          int            `json:"target_ms"`
+	HTTP              []LatencyStats `json:"http"`               // Time from receiving a request to writing its response
+	WebSocketDelivery []LatencyStats `json:"websocket_delivery"` // Time from a message's timestamp to its write to the client
+	GeneratedAt       time.Time      `json:"generated_at"`
+}
+
+// FreshnessStats is how current the stored candles of one collected symbol/interval are, against
+// the interval's freshness target
+type FreshnessStats struct {
+	Symbol        string   `json:"symbol"`
+	Interval      string   `json:"interval"`
+	TargetSeconds float64  `json:"target_seconds"`
+	LagSeconds    *float64 `json:"lag_seconds"` // Since the candles were last stored; null before the first store
+	WithinTarget  bool     `json:"within_target"`
+	CompliancePct *float64 `json:"compliance_pct"` // Share of the window's samples within target; null before the first sample
+	Samples       int      `json:"samples"`
+	Paused        bool     `json:"paused,omitempty"` // Collection is paused on purpose; paused time is not sampled
+}
+
+// FreshnessReport is the data freshness of every collected symbol/interval of this instance
+type FreshnessReport struct {
+	WindowSeconds         int64            `json:"window_seconds"`
+	SampleIntervalSeconds int64            `json:"sample_interval_seconds"`
+	CompliancePct         *float64         `json:"compliance_pct"` // Over every listed series' samples
+	Series                []FreshnessStats `json:"series"`
+	GeneratedAt           time.Time        `json:"generated_at"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Spread kinds
+const (
+	SpreadKindSpotPerp      = "spot_perp"      // One leg trades on a spot exchange
+	SpreadKindCrossExchange = "cross_exchange" // Both legs are perpetuals on different exchanges
+)
+
+// spotExchanges lists exchanges whose symbols are spot markets
+var spotExchanges = map[string]bool{
+	ExchangeCoinbase: true,
+}
+
+// SpreadPair is a monitored pair of symbol keys; the spread is LegA minus LegB
+type SpreadPair struct {
+	Name string `json:"pair"` // "<leg_a>/<leg_b>", e.g. "BTCUSDT/BYBIT:BTCUSDT"
+	LegA string `json:"leg_a"`
+	LegB string `json:"leg_b"`
+	Kind string `json:"kind"`
+}
+
+// ParseSpreadPair parses "<leg_a>/<leg_b>" symbol keys into a spread pair
+func ParseSpreadPair(value string) (SpreadPair, error) {
+	legA, legB, found := strings.Cut(strings.ToUpper(strings.TrimSpace(value)), "/")
+	if !found || legA == "" || legB == "" || strings.Contains(legB, "/") {
+		return SpreadPair{}, fmt.Errorf("spread pair %q must be two symbol keys joined by /", value)
+	}
+	if legA == legB {
+		return SpreadPair{}, fmt.Errorf("spread pair %q must have two different legs", value)
+	}
+
+	kind := SpreadKindCrossExchange
+	if spotExchanges[SymbolExchange(legA)] != spotExchanges[SymbolExchange(legB)] {
+		kind = SpreadKindSpotPerp
+	}
+	return SpreadPair{Name: legA + "/" + legB, LegA: legA, LegB: legB, Kind: kind}, nil
+}
+
+// SpreadSample is a pair's spread at one time
+type SpreadSample struct {
+	SpreadPair
+	PriceA    float64   `json:"price_a"`
+	PriceB    float64   `json:"price_b"`
+	Spread    float64   `json:"spread"`     // PriceA minus PriceB
+	SpreadBps float64   `json:"spread_bps"` // Spread in basis points of PriceB
+	Arbitrage bool      `json:"arbitrage"`  // |SpreadBps| at or above the arbitrage threshold
+	Time      time.Time `json:"time"`
+}
+
+// SpreadBucket summarizes a pair's stored spread samples over one time bucket
+type SpreadBucket struct {
+	Time         time.Time `json:"t"`
+	AvgSpreadBps float64   `json:"avg_bps"`
+	MinSpreadBps float64   `json:"min_bps"`
+	MaxSpreadBps float64   `json:"max_bps"`
+	LastSpread   float64   `json:"last_spread"`
+	Samples      int64     `json:"samples"`
+}
+
+// SpreadHistory is a pair's bucketed spread history
+type SpreadHistory struct {
+	Pair      string         `json:"pair"`
+	Interval  string         `json:"interval"`
+	Buckets   []SpreadBucket `json:"buckets"`
+	Count     int            `json:"count"`
+	Timestamp int64          `json:"timestamp"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// Component states, from best to worst
+const (
+	ComponentOperational   = "operational"
+	ComponentDegraded      = "degraded"
+	ComponentPartialOutage = "partial_outage"
+	ComponentMajorOutage   = "major_outage"
+)
+
+// ComponentSeverity ranks a component state so the worst one sets the overall status
+func ComponentSeverity(state string) int {
+	switch state {
+	case ComponentDegraded:
+		return 1
+	case ComponentPartialOutage:
+		return 2
+	case ComponentMajorOutage:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// StatusPage is the public system status shown in the frontend's status banner
+type StatusPage struct {
+	Status           string            `json:"status"` // Worst component state
+	Components       []ComponentStatus `json:"components"`
+	Incidents        []Incident        `json:"incidents"` // Active incidents, one per component not operational
+	RecentReconnects []ReconnectEvent  `json:"recent_reconnects"`
+	Freshness        DataFreshness     `json:"freshness"`
+	PausedSymbols    []PausedSymbol    `json:"paused_symbols"` // Symbols whose data is not collected or streamed
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// ComponentStatus is the state of one monitored component
+type ComponentStatus struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	Status  string     `json:"status"`
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"` // When the current state started, when known
+}
+
+// Incident is an ongoing problem with a component
+type Incident struct {
+	ID        string    `json:"id"`
+	Component string    `json:"component"`
+	Severity  string    `json:"severity"` // "minor" or "major"
+	Title     string    `json:"title"`
+	Message   string    `json:"message,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// PausedSymbol is a symbol whose collection and streaming are paused, e.g. during a data anomaly
+// or delisting window
+type PausedSymbol struct {
+	Symbol string     `json:"symbol"`
+	Reason string     `json:"reason,omitempty"`
+	Since  time.Time  `json:"since"`
+	Until  *time.Time `json:"until,omitempty"` // When the pause ends on its own
+}
+
+// ReconnectEvent records a market data stream dropping and, once back, when it reconnected
+type ReconnectEvent struct {
+	Stream         string     `json:"stream"` // "spot" or "futures"
+	DisconnectedAt time.Time  `json:"disconnected_at"`
+	ReconnectedAt  *time.Time `json:"reconnected_at,omitempty"`
+	Attempts       int        `json:"attempts"`
+	DowntimeMs     int64      `json:"downtime_ms,omitempty"`
+}
+
+// DataFreshness reports how old the newest market data is
+type DataFreshness struct {
+	StreamAgeSeconds     map[string]float64 `json:"stream_age_seconds"`               // Since the last message per stream
+	CollectionAgeSeconds *float64           `json:"collection_age_seconds,omitempty"` // Since the last successful collection run
+	CandleAgeSeconds     map[string]float64 `json:"candle_age_seconds"`               // Since each symbol's 1m candles were last stored
+}
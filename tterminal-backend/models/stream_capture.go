@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Stream capture event types
+const (
+	CaptureEventTrades       = "trades"       // "trade_update" messages from stored futures trades
+	CaptureEventKlines       = "klines"       // Closed "kline_update" messages from stored candles
+	CaptureEventLiquidations = "liquidations" // "liquidation_update" messages kept by the live stream
+)
+
+// CaptureEventTypes lists every stream capture event type
+var CaptureEventTypes = []string{CaptureEventTrades, CaptureEventKlines, CaptureEventLiquidations}
+
+// IsValidCaptureEvent checks if the event type is supported
+func IsValidCaptureEvent(event string) bool {
+	for _, e := range CaptureEventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamCaptureParams selects the stored events packaged into a stream capture
+// A capture is newline-delimited RecordedMessage lines, the format session recordings use,
+// so replay reads archived captures and live recordings the same way
+type StreamCaptureParams struct {
+	Symbol   string
+	Start    time.Time // Inclusive
+	End      time.Time // Inclusive
+	Events   []string  // CaptureEvent*
+	Interval string    // Kline interval
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,8 +23,69 @@ type Symbol struct {
 	MaxQty            sql.NullString `json:"max_qty" db:"max_qty"`
 	StepSize          sql.NullString `json:"step_size" db:"step_size"`
 	TickSize          sql.NullString `json:"tick_size" db:"tick_size"`
-	CreatedAt         time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at" db:"updated_at"`
+	// Smallest order notional (MIN_NOTIONAL filter) and contract multiplier (1 for linear contracts)
+	MinNotional        sql.NullString `json:"min_notional" db:"min_notional"`
+	ContractMultiplier sql.NullString `json:"contract_multiplier" db:"contract_multiplier"`
+	// PERCENT_PRICE bounds as multiples of the mark price
+	MultiplierUp   sql.NullString `json:"multiplier_up" db:"multiplier_up"`
+	MultiplierDown sql.NullString `json:"multiplier_down" db:"multiplier_down"`
+	// Precomputed formatting hints, derived from the filters on read
+	Display   SymbolDisplay `json:"display" db:"-"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// SymbolDisplay holds formatting metadata so every frontend widget renders prices and sizes
+// the same way without re-parsing filters
+type SymbolDisplay struct {
+	PriceDecimals      int     `json:"price_decimals"`    // Decimal places implied by the tick size
+	QuantityDecimals   int     `json:"quantity_decimals"` // Decimal places implied by the step size
+	MinNotional        float64 `json:"min_notional"`      // Smallest order value in the quote asset (0 = no minimum)
+	ContractMultiplier float64 `json:"contract_multiplier"`
+}
+
+// FillDisplay derives display metadata from the symbol filters
+// Decimal places fall back to the exchange precision when a filter is missing
+func (s *Symbol) FillDisplay() {
+	s.Display = SymbolDisplay{
+		PriceDecimals:      s.PricePrecision,
+		QuantityDecimals:   s.QuantityPrecision,
+		ContractMultiplier: 1,
+	}
+
+	if s.TickSize.Valid {
+		if decimals, ok := IncrementDecimals(s.TickSize.String); ok {
+			s.Display.PriceDecimals = decimals
+		}
+	}
+	if s.StepSize.Valid {
+		if decimals, ok := IncrementDecimals(s.StepSize.String); ok {
+			s.Display.QuantityDecimals = decimals
+		}
+	}
+	if s.MinNotional.Valid {
+		s.Display.MinNotional = ParseFloat(s.MinNotional.String)
+	}
+	if s.ContractMultiplier.Valid {
+		if multiplier := ParseFloat(s.ContractMultiplier.String); multiplier > 0 {
+			s.Display.ContractMultiplier = multiplier
+		}
+	}
+}
+
+// IncrementDecimals returns the decimal places needed to display multiples of an increment
+// such as a tick or step size ("0.01000000" -> 2, "1.00000000" -> 0)
+func IncrementDecimals(increment string) (int, bool) {
+	increment = strings.TrimSpace(increment)
+	if value, err := strconv.ParseFloat(increment, 64); err != nil || value <= 0 {
+		return 0, false
+	}
+
+	dot := strings.IndexByte(increment, '.')
+	if dot < 0 {
+		return 0, true
+	}
+	return len(strings.TrimRight(increment[dot+1:], "0")), true
 }
 
 // CreateSymbolRequest represents the request structure for creating symbols
@@ -45,3 +108,49 @@ type SymbolResponse struct {
 	Count   int      `json:"count"`
 	Symbols []Symbol `json:"symbols"`
 }
+
+// Symbol metadata that changes after an exchangeInfo sync, reported in "symbol_update" events
+const (
+	SymbolChangeListed           = "listed"
+	SymbolChangePrecision        = "precision"
+	SymbolChangeFilters          = "filters"
+	SymbolChangeStatus           = "status"
+	SymbolChangeLeverageBrackets = "leverage_brackets"
+)
+
+// LeverageBracket is one notional tier of a futures symbol's leverage and maintenance margin
+type LeverageBracket struct {
+	Bracket          int     `json:"bracket"`
+	InitialLeverage  int     `json:"initialLeverage"`
+	NotionalCap      float64 `json:"notionalCap"`
+	NotionalFloor    float64 `json:"notionalFloor"`
+	MaintMarginRatio float64 `json:"maintMarginRatio"`
+	Cum              float64 `json:"cum"` // Maintenance amount
+}
+
+// MetadataChanges lists what differs between a stored symbol and its synced exchange metadata
+func MetadataChanges(stored, synced *Symbol) []string {
+	var changes []string
+	if stored.PricePrecision != synced.PricePrecision || stored.QuantityPrecision != synced.QuantityPrecision {
+		changes = append(changes, SymbolChangePrecision)
+	}
+	if !sameDecimal(stored.MinPrice, synced.MinPrice) || !sameDecimal(stored.MaxPrice, synced.MaxPrice) ||
+		!sameDecimal(stored.MinQty, synced.MinQty) || !sameDecimal(stored.MaxQty, synced.MaxQty) ||
+		!sameDecimal(stored.StepSize, synced.StepSize) || !sameDecimal(stored.TickSize, synced.TickSize) ||
+		!sameDecimal(stored.MinNotional, synced.MinNotional) || !sameDecimal(stored.ContractMultiplier, synced.ContractMultiplier) ||
+		!sameDecimal(stored.MultiplierUp, synced.MultiplierUp) || !sameDecimal(stored.MultiplierDown, synced.MultiplierDown) {
+		changes = append(changes, SymbolChangeFilters)
+	}
+	if stored.Status != synced.Status || stored.IsActive != synced.IsActive {
+		changes = append(changes, SymbolChangeStatus)
+	}
+	return changes
+}
+
+// sameDecimal compares filter values numerically, since stored decimals carry trailing zeros
+func sameDecimal(a, b sql.NullString) bool {
+	if !a.Valid || !b.Valid {
+		return a.Valid == b.Valid
+	}
+	return ParseFloat(a.String) == ParseFloat(b.String)
+}
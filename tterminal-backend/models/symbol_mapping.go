@@ -0,0 +1,38 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// canonicalSymbolPattern restricts canonical symbols to hyphenated names ("BTC-PERP", "ETH-USD")
+// The hyphen keeps them apart from exchange symbols, which are stored without one
+var canonicalSymbolPattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)+$`)
+
+// IsCanonicalSymbol reports whether symbol has the form of a canonical symbol
+func IsCanonicalSymbol(symbol string) bool {
+	return len(symbol) <= 32 && canonicalSymbolPattern.MatchString(symbol)
+}
+
+// SymbolMapping maps a canonical symbol to one exchange's symbol
+// ExchangeSymbol is bare, in the form used by stored data and streams (Kraken's PF_XBTUSD is
+// "BTCUSD", OKX's BTC-USDT-SWAP is "BTCUSDT"); Symbol is the qualified key ("KRAKEN:BTCUSD")
+type SymbolMapping struct {
+	ID             int64     `json:"id" db:"id"`
+	Canonical      string    `json:"canonical" db:"canonical"`
+	Exchange       string    `json:"exchange" db:"exchange"`
+	ExchangeSymbol string    `json:"exchange_symbol" db:"exchange_symbol"`
+	Symbol         string    `json:"symbol"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// CanonicalSymbol is a canonical symbol with its per-exchange mappings
+type CanonicalSymbol struct {
+	Canonical string          `json:"canonical"`
+	Mappings  []SymbolMapping `json:"mappings"`
+}
+
+// SetSymbolMappingRequest represents the request structure for mapping a canonical symbol on an exchange
+type SetSymbolMappingRequest struct {
+	Symbol string `json:"symbol"` // Bare exchange symbol ("BTCUSDT")
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Symbol pause sources: who paused a symbol's collection and streaming
+const (
+	SymbolPauseManual  = "manual"  // An admin, e.g. for a delisting window
+	SymbolPauseAnomaly = "anomaly" // A closed 1m bar moved beyond COLLECTION_ANOMALY_MOVE_PCT
+)
+
+// SymbolPauseExpired is the ResumedBy of a pause that reached its until time
+const SymbolPauseExpired = "expired"
+
+// SymbolPause is a window during which a symbol's candles were not collected or stored and its
+// stream updates were not delivered. Until is nil for pauses held until resumed; ResumedAt is nil
+// while the pause is in effect
+type SymbolPause struct {
+	ID        int64      `json:"id" db:"id"`
+	Symbol    string     `json:"symbol" db:"symbol"`
+	Source    string     `json:"source" db:"source"` // SymbolPause{Manual,Anomaly}
+	Reason    string     `json:"reason" db:"reason"`
+	PausedBy  string     `json:"paused_by,omitempty" db:"paused_by"` // User ID of a manual pause
+	PausedAt  time.Time  `json:"paused_at" db:"paused_at"`
+	Until     *time.Time `json:"until,omitempty" db:"paused_until"`
+	ResumedAt *time.Time `json:"resumed_at,omitempty" db:"resumed_at"`
+	ResumedBy string     `json:"resumed_by,omitempty" db:"resumed_by"` // User ID or SymbolPauseExpired
+}
+
+// End returns when the pause ended or is due to end; zero for a pause held until resumed
+func (p *SymbolPause) End() time.Time {
+	if p.ResumedAt != nil {
+		return *p.ResumedAt
+	}
+	if p.Until != nil {
+		return *p.Until
+	}
+	return time.Time{}
+}
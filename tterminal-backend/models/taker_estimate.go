@@ -0,0 +1,75 @@
+package models
+
+import "strconv"
+
+// LacksTakerVolume reports whether a candle has traded volume but no taker buy/sell split
+// Binance klines always carry taker volume, so a zero split there is a real all-sell bar unless the
+// candle was imported from a dataset that may have left it out. Composite candles have no volume
+func LacksTakerVolume(candle *Candle) bool {
+	if candle.TakerVolumeEstimated || parseFloat(candle.Volume) <= 0 {
+		return false
+	}
+	if parseFloat(candle.TakerBuyBaseAssetVolume) != 0 || parseFloat(candle.TakerBuyQuoteAssetVolume) != 0 {
+		return false
+	}
+	return SymbolExchange(candle.Symbol) != ExchangeBinance || candle.Source == CandleSourceImport
+}
+
+// EstimateTakerVolume fills in an estimated taker buy volume for candles that lack one and flags
+// them with TakerVolumeEstimated. Candles must be ordered by open time within each symbol
+//
+// The buy share of a bar is where it closed within its range, (close - low) / (high - low): a bar
+// closing at its high is taken as all buying, one closing at its low as all selling. Bars without a
+// range fall back to the tick rule against the previous close (up is buying, down selling, unchanged
+// is split evenly). The input is returned as is when no candle needs an estimate, otherwise a copy
+// with the estimates is returned so slices shared with other goroutines are never written to
+func EstimateTakerVolume(candles []Candle) []Candle {
+	var estimated []Candle
+	for i := range candles {
+		if !LacksTakerVolume(&candles[i]) {
+			continue
+		}
+		if estimated == nil {
+			estimated = append([]Candle(nil), candles...)
+		}
+
+		candle := &estimated[i]
+		prevClose := 0.0
+		if i > 0 && candles[i-1].Symbol == candle.Symbol {
+			prevClose = parseFloat(candles[i-1].Close)
+		}
+		share := takerBuyShare(parseFloat(candle.High), parseFloat(candle.Low), parseFloat(candle.Close), prevClose)
+
+		candle.TakerBuyBaseAssetVolume = strconv.FormatFloat(parseFloat(candle.Volume)*share, 'f', 8, 64)
+		candle.TakerBuyQuoteAssetVolume = strconv.FormatFloat(parseFloat(candle.QuoteAssetVolume)*share, 'f', 8, 64)
+		candle.TakerVolumeEstimated = true
+	}
+
+	if estimated == nil {
+		return candles
+	}
+	return estimated
+}
+
+// takerBuyShare estimates the share of a bar's volume bought by takers (0 to 1)
+func takerBuyShare(high, low, close, prevClose float64) float64 {
+	if high > low {
+		share := (close - low) / (high - low)
+		if share < 0 {
+			return 0
+		}
+		if share > 1 {
+			return 1
+		}
+		return share
+	}
+
+	switch {
+	case prevClose <= 0 || close == prevClose:
+		return 0.5
+	case close > prevClose:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// TradeRecord represents a persisted futures trade
+type TradeRecord struct {
+	Symbol       string    `json:"symbol" db:"symbol"`
+	TradeID      int64     `json:"trade_id" db:"trade_id"`
+	Price        float64   `json:"price" db:"price"`
+	Quantity     float64   `json:"quantity" db:"quantity"`
+	IsBuyerMaker bool      `json:"is_buyer_maker" db:"is_buyer_maker"` // true = aggressive seller
+	TradeTime    time.Time `json:"trade_time" db:"trade_time"`
+}
+
+// TradeFlowBucket aggregates taker buy and sell volume over a fixed time bucket
+type TradeFlowBucket struct {
+	Time       time.Time `json:"time"`
+	BuyVolume  float64   `json:"buy_volume"`
+	SellVolume float64   `json:"sell_volume"`
+	TradeCount int64     `json:"trade_count"`
+}
+
+// CandleTradesResponse lists the trades that make up one candle, with totals rebuilt from them
+type CandleTradesResponse struct {
+	Symbol     string        `json:"symbol"`
+	Interval   string        `json:"interval"`
+	OpenTime   int64         `json:"open_time"`  // Unix milliseconds
+	CloseTime  int64         `json:"close_time"` // Unix milliseconds, inclusive
+	Candle     *Candle       `json:"candle,omitempty"`
+	Trades     []TradeRecord `json:"trades"`
+	Count      int           `json:"count"`
+	Truncated  bool          `json:"truncated"` // More trades exist than the limit returned
+	Volume     float64       `json:"volume"`
+	BuyVolume  float64       `json:"buy_volume"`
+	SellVolume float64       `json:"sell_volume"`
+	Delta      float64       `json:"delta"`
+	VWAP       float64       `json:"vwap"`
+}
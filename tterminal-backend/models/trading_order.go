@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Futures order types beyond MARKET and LIMIT
+const (
+	OrderTypeStop             = "STOP"               // Stop-limit: a limit order triggered at the stop price
+	OrderTypeStopMarket       = "STOP_MARKET"        // Stop-loss market order
+	OrderTypeTakeProfit       = "TAKE_PROFIT"        // Take-profit limit order
+	OrderTypeTakeProfitMarket = "TAKE_PROFIT_MARKET" // Take-profit market order
+)
+
+// TradingOrderTypes lists the order types accepted for live trading
+var TradingOrderTypes = []string{OrderTypeLimit, OrderTypeMarket, OrderTypeStop, OrderTypeStopMarket, OrderTypeTakeProfit, OrderTypeTakeProfitMarket}
+
+// TimeInForceValues lists the time in force values for orders with a limit price
+var TimeInForceValues = []string{"GTC", "IOC", "FOK", "GTX"}
+
+// IsValidTradingOrderType checks if an order type can be placed
+func IsValidTradingOrderType(orderType string) bool {
+	return containsString(TradingOrderTypes, orderType)
+}
+
+// IsValidTimeInForce checks if a time in force value is supported
+func IsValidTimeInForce(timeInForce string) bool {
+	return containsString(TimeInForceValues, timeInForce)
+}
+
+// Live order statuses: Binance's, plus pending for orders whose placement outcome is unknown
+const (
+	TradingOrderPending         = "PENDING" // Sent, but no answer from Binance yet; reconciled on the next read
+	TradingOrderNew             = "NEW"
+	TradingOrderPartiallyFilled = "PARTIALLY_FILLED"
+	TradingOrderFilled          = "FILLED"
+	TradingOrderCanceled        = "CANCELED"
+	TradingOrderRejected        = "REJECTED"
+	TradingOrderExpired         = "EXPIRED"
+)
+
+// TradingOrder is a futures order routed to Binance, persisted with its latest known state
+// Decimal fields are strings so amounts reach Binance exactly as entered
+type TradingOrder struct {
+	ID              int64     `json:"id" db:"id"`
+	ClientOrderID   string    `json:"client_order_id" db:"client_order_id"`
+	ExchangeOrderID *int64    `json:"exchange_order_id,omitempty" db:"exchange_order_id"` // Binance order ID, once placed
+	Symbol          string    `json:"symbol" db:"symbol"`
+	Side            string    `json:"side" db:"side"`
+	Type            string    `json:"type" db:"type"`
+	TimeInForce     string    `json:"time_in_force,omitempty" db:"time_in_force"`
+	Quantity        string    `json:"quantity" db:"quantity"`
+	Price           string    `json:"price" db:"price"`           // Limit price (0 for market orders)
+	StopPrice       string    `json:"stop_price" db:"stop_price"` // Trigger price (0 without one)
+	ReduceOnly      bool      `json:"reduce_only" db:"reduce_only"`
+	Status          string    `json:"status" db:"status"`
+	ExecutedQty     string    `json:"executed_qty" db:"executed_qty"`
+	AvgPrice        string    `json:"avg_price" db:"avg_price"`
+	LastError       string    `json:"last_error,omitempty" db:"last_error"` // Why the last request for the order failed
+	CreatedBy       string    `json:"created_by,omitempty" db:"created_by"` // X-User-ID of the requester, for auditing
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen reports whether the order may still fill, be amended or be cancelled
+func (o *TradingOrder) IsOpen() bool {
+	return o.Status == TradingOrderPending || o.Status == TradingOrderNew || o.Status == TradingOrderPartiallyFilled
+}
+
+// PlaceTradingOrderRequest represents the request structure for placing a live futures order
+type PlaceTradingOrderRequest struct {
+	Symbol        string `json:"symbol"`
+	Side          string `json:"side"`
+	Type          string `json:"type"`
+	TimeInForce   string `json:"time_in_force"` // Default GTC for orders with a limit price
+	Quantity      string `json:"quantity"`
+	Price         string `json:"price"`      // Required for LIMIT, STOP and TAKE_PROFIT
+	StopPrice     string `json:"stop_price"` // Required for STOP, STOP_MARKET, TAKE_PROFIT and TAKE_PROFIT_MARKET
+	ReduceOnly    bool   `json:"reduce_only"`
+	ClientOrderID string `json:"client_order_id"` // Optional idempotency key; repeating it returns the existing order
+}
+
+// AmendTradingOrderRequest represents the request structure for amending an open limit order
+// Omitted fields keep their current value
+type AmendTradingOrderRequest struct {
+	Quantity string `json:"quantity"`
+	Price    string `json:"price"`
+}
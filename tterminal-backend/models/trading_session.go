@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tradingSessionPattern matches "<name>=HH:MM-HH:MM"
+var tradingSessionPattern = regexp.MustCompile(`^([a-z][a-z0-9_]*)=(\d{2}):(\d{2})-(\d{2}):(\d{2})$`)
+
+// TradingSession is a named daily trading session in UTC, such as the London session. A session
+// whose end is not after its start runs past midnight into the next day
+type TradingSession struct {
+	Name  string        `json:"name"`
+	Start time.Duration `json:"-"` // Offset of the open from UTC midnight
+	End   time.Duration `json:"-"` // Offset of the close from UTC midnight
+}
+
+// ParseTradingSession parses a "<name>=HH:MM-HH:MM" UTC session, such as "london=08:00-16:30"
+func ParseTradingSession(value string) (TradingSession, error) {
+	match := tradingSessionPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if match == nil {
+		return TradingSession{}, fmt.Errorf("trading session %q must be <name>=HH:MM-HH:MM", value)
+	}
+
+	var bounds [2]time.Duration
+	for i := range bounds {
+		hours, _ := strconv.Atoi(match[2+2*i])
+		minutes, _ := strconv.Atoi(match[3+2*i])
+		if hours > 23 || minutes > 59 {
+			return TradingSession{}, fmt.Errorf("trading session %q has an invalid time of day", value)
+		}
+		bounds[i] = time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	}
+	if bounds[0] == bounds[1] {
+		return TradingSession{}, fmt.Errorf("trading session %q must not open and close at the same time", value)
+	}
+
+	return TradingSession{Name: match[1], Start: bounds[0], End: bounds[1]}, nil
+}
+
+// Windows returns the session's occurrences overlapping [start, end), oldest first, each as its
+// full open and close times
+func (s TradingSession) Windows(start, end time.Time) [][2]time.Time {
+	length := s.End - s.Start
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+
+	var windows [][2]time.Time
+	// An occurrence opening the day before start may still be open at start
+	for day := start.UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour); day.Before(end); day = day.Add(24 * time.Hour) {
+		open := day.Add(s.Start)
+		closeTime := open.Add(length)
+		if closeTime.After(start) && open.Before(end) {
+			windows = append(windows, [2]time.Time{open, closeTime})
+		}
+	}
+	return windows
+}
+
+// SessionVolumeProfile is the volume profile of one occurrence of a trading session; its st and
+// et are the part of the session within the requested range
+type SessionVolumeProfile struct {
+	N       string `json:"n"`                 // Session name
+	Open    int64  `json:"open"`              // Session open (Unix milliseconds)
+	Close   int64  `json:"close"`             // Session close (Unix milliseconds)
+	Partial bool   `json:"partial,omitempty"` // The range cuts the session, or it is still open
+	*VolumeProfile
+}
+
+// SessionVolumeProfiles lists the session volume profiles of a symbol within [st, et], ordered by
+// session open
+type SessionVolumeProfiles struct {
+	S  string                 `json:"s"`  // Symbol
+	ST int64                  `json:"st"` // Start time
+	ET int64                  `json:"et"` // End time
+	P  []SessionVolumeProfile `json:"p"`  // Profiles
+}
@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// User roles, from most to least privileged
+const (
+	RoleAdmin    = "admin"    // Operates the deployment: data collection, symbols and streams
+	RoleUser     = "user"     // Manages their own portfolios, orders, baskets, reports and webhooks
+	RoleReadonly = "readonly" // Reads market data and their own data without changing anything
+)
+
+// roleRanks orders the roles by privilege
+var roleRanks = map[string]int{RoleReadonly: 1, RoleUser: 2, RoleAdmin: 3}
+
+// IsValidRole reports whether role is a known user role
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants everything required grants
+func RoleAtLeast(role, required string) bool {
+	return roleRanks[role] > 0 && roleRanks[role] >= roleRanks[required]
+}
+
+// Data collection permissions, one per kind of action
+// Admins hold every permission unless their account lists the ones it holds
+const (
+	PermissionCollectionTrigger   = "collection:trigger"   // Run a collection or historical fetch now
+	PermissionCollectionConfigure = "collection:configure" // Change collected symbols and price types, pause and resume symbols
+	PermissionCollectionStop      = "collection:stop"      // Start and stop the collection service
+)
+
+// Permissions lists every permission an account can hold
+var Permissions = []string{PermissionCollectionTrigger, PermissionCollectionConfigure, PermissionCollectionStop}
+
+// IsValidPermission reports whether permission is a known permission
+func IsValidPermission(permission string) bool {
+	for _, known := range Permissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether an account with role and permissions holds permission: only
+// admins hold permissions, every one when permissions is nil and those listed otherwise
+func HasPermission(role string, permissions []string, permission string) bool {
+	if role != RoleAdmin {
+		return false
+	}
+	if permissions == nil {
+		return true
+	}
+	for _, held := range permissions {
+		if held == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// User is an account authenticating with email and password
+// Its ID is the user ID that scopes portfolios, orders, baskets, reports and webhooks
+type User struct {
+	ID           string     `json:"id" db:"id"`
+	Email        string     `json:"email" db:"email"`
+	PasswordHash string     `json:"-" db:"password_hash"`
+	Role         string     `json:"role" db:"role"`               // Role*; applies to tokens issued after it changes
+	Permissions  []string   `json:"permissions" db:"permissions"` // Permission* held as an admin; null for every one
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+}
+
+// CredentialsRequest is the request body to register or log in
+type CredentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// SetRoleRequest is the request body to change a user's role
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// SetPermissionsRequest is the request body to change a user's permissions
+// A null list restores every permission of the role
+type SetPermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// AuthResponse carries a bearer access token for the Authorization header
+type AuthResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type"` // Always "Bearer"
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// Preference value types
+const (
+	PreferenceString  = "string"
+	PreferenceBoolean = "boolean"
+	PreferenceNumber  = "number"
+	PreferenceObject  = "object" // Groups nested fields, each validated and defaulted on its own
+)
+
+// PreferenceField describes one user preference: its type, the values it accepts and its default
+type PreferenceField struct {
+	Type        string                     `json:"type"`
+	Description string                     `json:"description"`
+	Enum        []string                   `json:"enum,omitempty"`    // Values a string preference accepts; empty accepts any
+	Minimum     *float64                   `json:"minimum,omitempty"` // Bounds of a number preference
+	Maximum     *float64                   `json:"maximum,omitempty"`
+	Default     interface{}                `json:"default,omitempty"` // Unset for objects, whose fields carry defaults
+	Fields      map[string]PreferenceField `json:"fields,omitempty"`  // Fields of an object preference
+}
+
+// preferenceSchema lists every preference a user can store; values outside it are rejected
+var preferenceSchema = map[string]PreferenceField{
+	"theme": {
+		Type:        PreferenceString,
+		Description: "Terminal color theme",
+		Enum:        []string{"dark", "light", "system"},
+		Default:     "dark",
+	},
+	"default_interval": {
+		Type:        PreferenceString,
+		Description: "Candle interval new charts open with",
+		Enum:        SupportedIntervalNames(),
+		Default:     "1m",
+	},
+	"notifications": {
+		Type:        PreferenceObject,
+		Description: "How the terminal notifies the user of alerts and market events",
+		Fields: map[string]PreferenceField{
+			"enabled": {
+				Type:        PreferenceBoolean,
+				Description: "Show notifications at all",
+				Default:     true,
+			},
+			"desktop": {
+				Type:        PreferenceBoolean,
+				Description: "Use browser desktop notifications rather than in-app toasts",
+				Default:     false,
+			},
+			"sound": {
+				Type:        PreferenceBoolean,
+				Description: "Play a sound with each notification",
+				Default:     true,
+			},
+			"volume": {
+				Type:        PreferenceNumber,
+				Description: "Notification sound volume, in percent",
+				Minimum:     float64Ptr(0),
+				Maximum:     float64Ptr(100),
+				Default:     50.0,
+			},
+			"market_events": {
+				Type:        PreferenceBoolean,
+				Description: "Notify on indexed market events (largest ranges, volume spikes, gaps)",
+				Default:     true,
+			},
+			"webhook_failures": {
+				Type:        PreferenceBoolean,
+				Description: "Notify when a webhook delivery fails for good",
+				Default:     true,
+			},
+		},
+	},
+}
+
+// PreferenceSchema returns the schema of every user preference, by name. The schema is shared:
+// callers must not modify it
+func PreferenceSchema() map[string]PreferenceField {
+	return preferenceSchema
+}
+
+// UserPreferences holds a user's terminal preferences, keyed as in the preference schema
+// Stored preferences keep only the values a user set; responses fill in defaults for the rest
+type UserPreferences struct {
+	UserID      string                 `json:"user_id" db:"user_id"`
+	Preferences map[string]interface{} `json:"preferences" db:"preferences"`
+	UpdatedAt   *time.Time             `json:"updated_at" db:"updated_at"` // Nil until the user first saves a preference
+}
+
+// float64Ptr returns a pointer to a float64 literal
+func float64Ptr(value float64) *float64 {
+	return &value
+}
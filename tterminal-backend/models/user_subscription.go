@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserSubscriptions stores a user's last active WebSocket subscriptions so reconnecting
+// terminals can restore their data flows
+type UserSubscriptions struct {
+	UserID                 string    `json:"user_id" db:"user_id"`
+	Symbols                []string  `json:"symbols" db:"symbols"`
+	EnrichedSymbols        []string  `json:"enriched_symbols" db:"enriched_symbols"`
+	Channels               []string  `json:"channels" db:"channels"`
+	LiquidationMinNotional float64   `json:"liquidation_min_notional" db:"liquidation_min_notional"`
+	UpdatedAt              time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// VWAP modes: session VWAP restarts at every UTC day, anchored VWAP runs from one timestamp
+const (
+	VWAPModeSession  = "session"
+	VWAPModeAnchored = "anchored"
+)
+
+// VWAP sources: candle typical prices, or the prices of stored trades
+const (
+	VWAPSourceCandles = "candles"
+	VWAPSourceTrades  = "trades"
+)
+
+// VWAPBucket sums the traded volume of one bar for VWAP, weighted by price and squared price
+type VWAPBucket struct {
+	Time           time.Time
+	Notional       float64 // Sum of price * volume
+	Volume         float64
+	SquareNotional float64 // Sum of price^2 * volume, for the standard deviation
+}
+
+// VWAPBar is the VWAP and its bands as of the close of one bar
+type VWAPBar struct {
+	T  int64     `json:"t"`  // Open time (Unix milliseconds)
+	V  float64   `json:"v"`  // Cumulative VWAP
+	SD float64   `json:"sd"` // Volume-weighted standard deviation of price around the VWAP
+	U  []float64 `json:"u"`  // Upper bands, VWAP plus each multiplier times SD
+	L  []float64 `json:"l"`  // Lower bands, VWAP minus each multiplier times SD
+}
+
+// VWAPSeries is the VWAP of a symbol's bars opening in [st, et], oldest first
+type VWAPSeries struct {
+	S   string    `json:"s"`           // Symbol
+	I   string    `json:"i"`           // Interval
+	M   string    `json:"m"`           // Mode (session or anchored)
+	Src string    `json:"src"`         // Source (candles or trades)
+	A   int64     `json:"a,omitempty"` // Anchor time of an anchored VWAP
+	B   []float64 `json:"b"`           // Band multipliers, in the order of each bar's u and l
+	ST  int64     `json:"st"`          // Start time
+	ET  int64     `json:"et"`          // End time
+	D   []VWAPBar `json:"d"`           // Bars
+}
@@ -0,0 +1,168 @@
+package models
+
+import "time"
+
+// Webhook events a user endpoint can receive
+const (
+	WebhookEventCandle      = "candle"       // Closed candles of the chosen symbols and intervals
+	WebhookEventMarketEvent = "market_event" // Significant event alerts (ranges, volume spikes, gaps)
+	WebhookEventAlert       = "alert"        // The user's own price alerts firing
+	WebhookEventLiquidation = "liquidation"  // Streamed liquidations at or above the webhook's minimum notional
+	WebhookEventPing        = "ping"         // Test delivery, sent on request only
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{WebhookEventCandle, WebhookEventMarketEvent, WebhookEventAlert, WebhookEventLiquidation}
+
+// IsValidWebhookEvent checks if a webhook can subscribe to the event
+func IsValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook channels: how deliveries reach the user
+const (
+	WebhookChannelHTTP     = "http"     // Signed JSON POSTs to the user's endpoint
+	WebhookChannelTelegram = "telegram" // Text messages sent by the user's Telegram bot to a chat
+	WebhookChannelDiscord  = "discord"  // Text messages posted to a Discord channel webhook
+)
+
+// WebhookChannels lists the channels a webhook can deliver through
+var WebhookChannels = []string{WebhookChannelHTTP, WebhookChannelTelegram, WebhookChannelDiscord}
+
+// IsValidWebhookChannel checks if a webhook can deliver through the channel
+func IsValidWebhookChannel(channel string) bool {
+	return containsString(WebhookChannels, channel)
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its first attempt or a retry
+	WebhookDeliveryDelivered = "delivered" // The endpoint answered 2xx
+	WebhookDeliveryFailed    = "failed"    // Dead letter: every attempt failed, or the webhook was disabled; can be replayed
+)
+
+// Webhook is a user endpoint receiving closed candles and alerts, as signed JSON POSTs or as
+// Telegram or Discord messages. An empty Intervals list matches every interval
+type Webhook struct {
+	ID          int64     `json:"id" db:"id"`
+	UserID      string    `json:"user_id" db:"user_id"`
+	Channel     string    `json:"channel" db:"channel"` // http, telegram or discord
+	URL         string    `json:"url" db:"url"`
+	Secret      string    `json:"secret,omitempty" db:"secret"`   // Signing secret (http) or bot token (telegram), only returned on creation and rotation
+	ChatID      string    `json:"chat_id,omitempty" db:"chat_id"` // Telegram chat messages are sent to
+	Events      []string  `json:"events" db:"events"`
+	Symbols     []string  `json:"symbols" db:"symbols"`
+	Intervals   []string  `json:"intervals" db:"intervals"`
+	MinNotional float64   `json:"min_notional" db:"min_notional"` // Smallest liquidation notional delivered (0 delivers every one)
+	Active      bool      `json:"active" db:"active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Matches reports whether the webhook receives an event of a symbol and interval
+// Events without an interval (alerts, liquidations) ignore the interval filter
+func (w *Webhook) Matches(event, symbol, interval string) bool {
+	if !w.Active || !containsString(w.Events, event) || !containsString(w.Symbols, symbol) {
+		return false
+	}
+	return interval == "" || len(w.Intervals) == 0 || containsString(w.Intervals, interval)
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest represents the request structure for registering a webhook
+type CreateWebhookRequest struct {
+	Channel     string   `json:"channel"`   // Defaults to http
+	URL         string   `json:"url"`       // Endpoint (http) or channel webhook URL (discord); unused for telegram
+	BotToken    string   `json:"bot_token"` // Telegram bot token
+	ChatID      string   `json:"chat_id"`   // Telegram chat ID or @channel username
+	Events      []string `json:"events"`
+	Symbols     []string `json:"symbols"`
+	Intervals   []string `json:"intervals"`
+	MinNotional float64  `json:"min_notional"`
+}
+
+// UpdateWebhookRequest represents the request structure for updating a webhook
+// Omitted fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url"`
+	BotToken    *string  `json:"bot_token"`
+	ChatID      *string  `json:"chat_id"`
+	Events      []string `json:"events"`
+	Symbols     []string `json:"symbols"`
+	Intervals   []string `json:"intervals"` // Replaces the intervals when present; [] matches every interval
+	MinNotional *float64 `json:"min_notional"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookDelivery is one payload sent, or to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             int64      `json:"id" db:"id"`
+	WebhookID      int64      `json:"webhook_id" db:"webhook_id"`
+	Event          string     `json:"event" db:"event"`
+	Symbol         string     `json:"symbol" db:"symbol"`
+	Interval       string     `json:"interval" db:"interval"`
+	Payload        []byte     `json:"-" db:"payload"` // JSON of the event's data
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty" db:"response_status"` // Status of the last attempt (0 when none answered)
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook
+type WebhookPayload struct {
+	ID        int64       `json:"id"` // Delivery ID, the same across retries
+	Event     string      `json:"event"`
+	WebhookID int64       `json:"webhook_id"`
+	Attempt   int         `json:"attempt"`
+	CreatedAt int64       `json:"created_at"` // When the event occurred (Unix milliseconds)
+	Data      interface{} `json:"data"`
+}
+
+// WebhookCandle is the data of a candle delivery: one closed bar
+type WebhookCandle struct {
+	Symbol      string  `json:"symbol"`
+	Interval    string  `json:"interval"`
+	OpenTime    int64   `json:"open_time"`  // Unix milliseconds
+	CloseTime   int64   `json:"close_time"` // Unix milliseconds
+	Open        float64 `json:"open"`
+	High        float64 `json:"high"`
+	Low         float64 `json:"low"`
+	Close       float64 `json:"close"`
+	Volume      float64 `json:"volume"`
+	BuyVolume   float64 `json:"buy_volume"`
+	QuoteVolume float64 `json:"quote_volume"`
+	TradeCount  int64   `json:"trade_count"`
+}
+
+// WebhookAlert is the data of an alert delivery: a price alert and the crossing that fired it
+type WebhookAlert struct {
+	Alert   PriceAlert   `json:"alert"`
+	Trigger AlertTrigger `json:"trigger"`
+}
+
+// WebhookLiquidation is the data of a liquidation delivery: one liquidation order
+type WebhookLiquidation struct {
+	Symbol    string  `json:"symbol"`
+	Side      string  `json:"side"` // "SELL" closes a long, "BUY" a short
+	Price     float64 `json:"price"`
+	Quantity  float64 `json:"quantity"`   // Base units
+	Notional  float64 `json:"notional"`   // Price × quantity
+	TradeTime int64   `json:"trade_time"` // Unix milliseconds
+}
@@ -80,3 +80,41 @@ func (r *RedisCache) Close() error {
 func (r *RedisCache) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
+
+// AppendList pushes raw values onto the end of a list and resets its expiration
+func (r *RedisCache) AppendList(ctx context.Context, key string, values [][]byte, expiration time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.RPush(ctx, key, args...)
+	pipe.Expire(ctx, key, expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetListRange returns list elements from start to stop (inclusive, negative counts from the end)
+func (r *RedisCache) GetListRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return r.client.LRange(ctx, key, start, stop).Result()
+}
+
+// DeletePattern removes every key matching a glob pattern, returning how many were deleted
+// Keys are found with SCAN so large keyspaces do not block Redis
+func (r *RedisCache) DeletePattern(ctx context.Context, pattern string) (int64, error) {
+	var deleted int64
+	iter := r.client.Scan(ctx, 0, pattern, 500).Iterator()
+	for iter.Next(ctx) {
+		n, err := r.client.Del(ctx, iter.Val()).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, iter.Err()
+}
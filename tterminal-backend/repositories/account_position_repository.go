@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// accountPositionColumns lists account position columns in scan order
+const accountPositionColumns = `symbol, position_side, quantity, entry_price, break_even_price, margin_type,
+		realized_pnl, fees_paid, opened_at, updated_at`
+
+// AccountPositionRepository handles database operations for the account's positions and fills
+type AccountPositionRepository struct {
+	db *database.DB
+}
+
+// NewAccountPositionRepository creates a new account position repository
+func NewAccountPositionRepository(db *database.DB) *AccountPositionRepository {
+	return &AccountPositionRepository{db: db}
+}
+
+// GetAll retrieves every stored position, including closed ones
+func (r *AccountPositionRepository) GetAll(ctx context.Context) ([]models.AccountPosition, error) {
+	query := `
+		SELECT ` + accountPositionColumns + `
+		FROM account_positions
+		ORDER BY symbol ASC, position_side ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []models.AccountPosition
+	for rows.Next() {
+		var p models.AccountPosition
+		err := rows.Scan(
+			&p.Symbol, &p.PositionSide, &p.Quantity, &p.EntryPrice, &p.BreakEvenPrice, &p.MarginType,
+			&p.RealizedPnL, &p.FeesPaid, &p.OpenedAt, &p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account position: %w", err)
+		}
+		positions = append(positions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating account positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// Save inserts or replaces a position
+func (r *AccountPositionRepository) Save(ctx context.Context, position *models.AccountPosition) error {
+	query := `
+		INSERT INTO account_positions (` + accountPositionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (symbol, position_side) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			entry_price = EXCLUDED.entry_price,
+			break_even_price = EXCLUDED.break_even_price,
+			margin_type = EXCLUDED.margin_type,
+			realized_pnl = EXCLUDED.realized_pnl,
+			fees_paid = EXCLUDED.fees_paid,
+			opened_at = EXCLUDED.opened_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		position.Symbol, position.PositionSide, position.Quantity, position.EntryPrice, position.BreakEvenPrice,
+		position.MarginType, position.RealizedPnL, position.FeesPaid, position.OpenedAt, position.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save account position: %w", err)
+	}
+	return nil
+}
+
+// CreateFill records a fill, returning false if it was already recorded
+func (r *AccountPositionRepository) CreateFill(ctx context.Context, fill *models.AccountFill) (bool, error) {
+	query := `
+		INSERT INTO account_fills (symbol, position_side, trade_id, order_id, client_order_id, side, price,
+		                           quantity, realized_pnl, commission, commission_asset, is_maker, traded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (symbol, trade_id) DO NOTHING
+		RETURNING id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query,
+		fill.Symbol, fill.PositionSide, fill.TradeID, fill.OrderID, fill.ClientOrderID, fill.Side, fill.Price,
+		fill.Quantity, fill.RealizedPnL, fill.Commission, fill.CommissionAsset, fill.IsMaker, fill.TradedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create account fill: %w", err)
+	}
+	defer rows.Close()
+
+	created := false
+	for rows.Next() {
+		if err := rows.Scan(&fill.ID); err != nil {
+			return false, fmt.Errorf("failed to scan account fill ID: %w", err)
+		}
+		created = true
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to create account fill: %w", err)
+	}
+	return created, nil
+}
+
+// GetFills retrieves the most recent fills, newest first, optionally for one symbol and after since
+func (r *AccountPositionRepository) GetFills(ctx context.Context, symbol string, since time.Time, limit int) ([]models.AccountFill, error) {
+	query := `
+		SELECT id, symbol, position_side, trade_id, order_id, client_order_id, side, price, quantity,
+		       realized_pnl, commission, commission_asset, is_maker, traded_at
+		FROM account_fills
+		WHERE ($1 = '' OR symbol = $1) AND traded_at >= $2
+		ORDER BY traded_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account fills: %w", err)
+	}
+	defer rows.Close()
+
+	fills := []models.AccountFill{}
+	for rows.Next() {
+		var f models.AccountFill
+		err := rows.Scan(
+			&f.ID, &f.Symbol, &f.PositionSide, &f.TradeID, &f.OrderID, &f.ClientOrderID, &f.Side, &f.Price, &f.Quantity,
+			&f.RealizedPnL, &f.Commission, &f.CommissionAsset, &f.IsMaker, &f.TradedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account fill: %w", err)
+		}
+		fills = append(fills, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating account fills: %w", err)
+	}
+
+	return fills, nil
+}
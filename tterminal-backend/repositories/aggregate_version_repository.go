@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const aggregateVersionColumns = `id, kind, symbol, interval, start_time, end_time, checksum, valid_from, valid_to`
+
+// AggregateVersionRepository handles database operations for stored aggregate versions
+type AggregateVersionRepository struct {
+	db *database.DB
+}
+
+// NewAggregateVersionRepository creates a new aggregate version repository
+func NewAggregateVersionRepository(db *database.DB) *AggregateVersionRepository {
+	return &AggregateVersionRepository{db: db}
+}
+
+// Record stores a computation of an aggregate. When it matches the current version's checksum the
+// current version is returned unchanged; otherwise the current version is closed at the new one's
+// valid_from and the new one is inserted. Reports whether a new version was stored
+func (r *AggregateVersionRepository) Record(ctx context.Context, v *models.AggregateVersion) (*models.AggregateVersion, bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := scanAggregateVersion(tx.QueryRow(ctx, `
+		SELECT `+aggregateVersionColumns+`
+		FROM aggregate_versions
+		WHERE kind = $1 AND symbol = $2 AND interval = $3 AND start_time = $4 AND end_time = $5
+		  AND valid_to IS NULL
+		FOR UPDATE
+	`, v.Kind, v.Symbol, v.Interval, v.StartTime, v.EndTime))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get current aggregate version: %w", err)
+	}
+	if current != nil && current.Checksum == v.Checksum {
+		return current, false, nil
+	}
+
+	if current != nil {
+		if _, err := tx.Exec(ctx, `UPDATE aggregate_versions SET valid_to = $2 WHERE id = $1`, current.ID, v.ValidFrom); err != nil {
+			return nil, false, fmt.Errorf("failed to supersede aggregate version: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO aggregate_versions (kind, symbol, interval, start_time, end_time, checksum, data, valid_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (kind, symbol, interval, start_time, end_time) WHERE valid_to IS NULL DO NOTHING
+		RETURNING id
+	`, v.Kind, v.Symbol, v.Interval, v.StartTime, v.EndTime, v.Checksum, v.Data, v.ValidFrom).Scan(&v.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// A concurrent first computation stored the current version meanwhile
+		current, err := scanAggregateVersion(tx.QueryRow(ctx, `
+			SELECT `+aggregateVersionColumns+`
+			FROM aggregate_versions
+			WHERE kind = $1 AND symbol = $2 AND interval = $3 AND start_time = $4 AND end_time = $5
+			  AND valid_to IS NULL
+		`, v.Kind, v.Symbol, v.Interval, v.StartTime, v.EndTime))
+		if err != nil || current == nil {
+			return nil, false, fmt.Errorf("failed to get concurrently recorded aggregate version: %v", err)
+		}
+		return current, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record aggregate version: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit aggregate version: %w", err)
+	}
+	return v, true, nil
+}
+
+// GetAsOf retrieves the version of an aggregate, with its data, that was valid at asOf
+// Returns nil when the aggregate had not been computed by then
+func (r *AggregateVersionRepository) GetAsOf(ctx context.Context, kind, symbol, interval string, startTime, endTime, asOf time.Time) (*models.AggregateVersion, error) {
+	query := `
+		SELECT ` + aggregateVersionColumns + `, data
+		FROM aggregate_versions
+		WHERE kind = $1 AND symbol = $2 AND interval = $3 AND start_time = $4 AND end_time = $5
+		  AND valid_from <= $6 AND (valid_to IS NULL OR valid_to > $6)
+		ORDER BY valid_from DESC
+		LIMIT 1
+	`
+
+	var v models.AggregateVersion
+	err := r.db.Pool.QueryRow(ctx, query, kind, symbol, interval, startTime, endTime, asOf).Scan(
+		&v.ID, &v.Kind, &v.Symbol, &v.Interval, &v.StartTime, &v.EndTime, &v.Checksum, &v.ValidFrom, &v.ValidTo, &v.Data)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get aggregate version: %w", err)
+	}
+	return &v, nil
+}
+
+// List retrieves an aggregate's versions without their data, newest first
+func (r *AggregateVersionRepository) List(ctx context.Context, kind, symbol, interval string, startTime, endTime time.Time, limit int) ([]models.AggregateVersion, error) {
+	query := `
+		SELECT ` + aggregateVersionColumns + `
+		FROM aggregate_versions
+		WHERE kind = $1 AND symbol = $2 AND interval = $3 AND start_time = $4 AND end_time = $5
+		ORDER BY valid_from DESC
+		LIMIT $6
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, kind, symbol, interval, startTime, endTime, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aggregate versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []models.AggregateVersion{}
+	for rows.Next() {
+		var v models.AggregateVersion
+		if err := rows.Scan(&v.ID, &v.Kind, &v.Symbol, &v.Interval, &v.StartTime, &v.EndTime, &v.Checksum, &v.ValidFrom, &v.ValidTo); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// scanAggregateVersion scans a version row without its data, returning nil when there is none
+func scanAggregateVersion(row pgx.Row) (*models.AggregateVersion, error) {
+	var v models.AggregateVersion
+	err := row.Scan(&v.ID, &v.Kind, &v.Symbol, &v.Interval, &v.StartTime, &v.EndTime, &v.Checksum, &v.ValidFrom, &v.ValidTo)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// AssetRepository handles database operations for assets and their instruments
+type AssetRepository struct {
+	db *database.DB
+}
+
+// NewAssetRepository creates a new asset repository
+func NewAssetRepository(db *database.DB) *AssetRepository {
+	return &AssetRepository{db: db}
+}
+
+// UpsertAsset creates an asset or renames an existing one
+func (r *AssetRepository) UpsertAsset(ctx context.Context, asset *models.Asset) error {
+	query := `
+		INSERT INTO assets (code, name)
+		VALUES ($1, $2)
+		ON CONFLICT (code) DO UPDATE SET
+			name = EXCLUDED.name,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query, asset.Code, asset.Name).Scan(&asset.CreatedAt, &asset.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert asset: %w", err)
+	}
+	return nil
+}
+
+// DeleteAsset removes an asset with its instruments, reporting whether it existed
+func (r *AssetRepository) DeleteAsset(ctx context.Context, code string) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM assets WHERE code = $1`, code)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete asset: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// GetAll retrieves every asset with its instruments, ordered by code
+func (r *AssetRepository) GetAll(ctx context.Context) ([]models.Asset, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT code, name, created_at, updated_at FROM assets ORDER BY code ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assets: %w", err)
+	}
+	defer rows.Close()
+
+	assets := []models.Asset{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var a models.Asset
+		if err := rows.Scan(&a.Code, &a.Name, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		a.Instruments = []models.AssetInstrument{}
+		index[a.Code] = len(assets)
+		assets = append(assets, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assets: %w", err)
+	}
+
+	instruments, err := r.getInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, instrument := range instruments {
+		if i, ok := index[instrument.Asset]; ok {
+			assets[i].Instruments = append(assets[i].Instruments, instrument)
+		}
+	}
+
+	return assets, nil
+}
+
+// getInstruments retrieves every instrument, ordered by asset, kind, exchange and symbol
+func (r *AssetRepository) getInstruments(ctx context.Context) ([]models.AssetInstrument, error) {
+	query := `
+		SELECT id, asset, kind, exchange, exchange_symbol, price_type, expires_at, created_at
+		FROM asset_instruments
+		ORDER BY asset ASC, kind ASC, exchange ASC, exchange_symbol ASC, price_type ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query asset instruments: %w", err)
+	}
+	defer rows.Close()
+
+	instruments := []models.AssetInstrument{}
+	for rows.Next() {
+		var i models.AssetInstrument
+		if err := rows.Scan(&i.ID, &i.Asset, &i.Kind, &i.Exchange, &i.ExchangeSymbol, &i.PriceType, &i.ExpiresAt, &i.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan asset instrument: %w", err)
+		}
+		instruments = append(instruments, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating asset instruments: %w", err)
+	}
+
+	return instruments, nil
+}
+
+// UpsertInstrument adds an instrument to an asset, moving it if it belongs to another asset
+func (r *AssetRepository) UpsertInstrument(ctx context.Context, instrument *models.AssetInstrument) error {
+	query := `
+		INSERT INTO asset_instruments (asset, kind, exchange, exchange_symbol, price_type, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (exchange, exchange_symbol, price_type) DO UPDATE SET
+			asset = EXCLUDED.asset,
+			kind = EXCLUDED.kind,
+			expires_at = EXCLUDED.expires_at
+		RETURNING id, created_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		instrument.Asset, instrument.Kind, instrument.Exchange, instrument.ExchangeSymbol,
+		instrument.PriceType, instrument.ExpiresAt,
+	).Scan(&instrument.ID, &instrument.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert asset instrument: %w", err)
+	}
+	return nil
+}
+
+// DeleteInstrument removes an instrument from an asset, reporting whether it existed
+func (r *AssetRepository) DeleteInstrument(ctx context.Context, code string, id int64) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM asset_instruments WHERE asset = $1 AND id = $2`, code, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete asset instrument: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
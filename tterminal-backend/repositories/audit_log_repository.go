@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// AuditLogRepository handles database operations for the API audit log
+type AuditLogRepository struct {
+	db *database.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *database.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create records an audited request
+func (r *AuditLogRepository) Create(ctx context.Context, entry *models.AuditLogEntry) error {
+	var diff []byte
+	if len(entry.Diff) > 0 {
+		var err error
+		if diff, err = json.Marshal(entry.Diff); err != nil {
+			return fmt.Errorf("failed to encode audit diff: %w", err)
+		}
+	}
+	var payload []byte
+	if len(entry.Payload) > 0 {
+		payload = entry.Payload
+	}
+
+	query := `
+		INSERT INTO audit_log (actor, actor_type, actor_role, client_ip, method, route, path, query,
+			status, request_id, resource, resource_id, payload, diff, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query, entry.Actor, entry.ActorType, entry.ActorRole, entry.ClientIP,
+		entry.Method, entry.Route, entry.Path, entry.Query, entry.Status, entry.RequestID,
+		entry.Resource, entry.ResourceID, payload, diff, entry.DurationMs, entry.CreatedAt).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+	return nil
+}
+
+// Query retrieves the entries matching a filter, newest first
+func (r *AuditLogRepository) Query(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLogEntry, error) {
+	query := `
+		SELECT id, actor, actor_type, actor_role, client_ip, method, route, path, query,
+		       status, request_id, resource, resource_id, payload, diff, duration_ms, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR actor = $1)
+		  AND ($2 = '' OR resource = $2)
+		  AND ($3 = '' OR resource_id = $3)
+		  AND ($4 = '' OR method = $4)
+		  AND ($5 = '' OR path LIKE $5 || '%')
+		  AND ($6::timestamptz IS NULL OR created_at >= $6)
+		  AND ($7::timestamptz IS NULL OR created_at < $7)
+		  AND ($8 = 0 OR id < $8)
+		ORDER BY id DESC
+		LIMIT $9
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.Actor, filter.Resource, filter.ResourceID, filter.Method,
+		filter.PathPrefix, nullTime(filter.Start), nullTime(filter.End), filter.BeforeID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditLogEntry{}
+	for rows.Next() {
+		var entry models.AuditLogEntry
+		var payload, diff []byte
+		err := rows.Scan(&entry.ID, &entry.Actor, &entry.ActorType, &entry.ActorRole, &entry.ClientIP,
+			&entry.Method, &entry.Route, &entry.Path, &entry.Query, &entry.Status, &entry.RequestID,
+			&entry.Resource, &entry.ResourceID, &payload, &diff, &entry.DurationMs, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+		if len(payload) > 0 {
+			entry.Payload = payload
+		}
+		if len(diff) > 0 {
+			if err := json.Unmarshal(diff, &entry.Diff); err != nil {
+				return nil, fmt.Errorf("failed to decode audit diff: %w", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return entries, nil
+}
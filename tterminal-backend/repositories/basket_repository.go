@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// BasketRepository handles database operations for symbol baskets
+type BasketRepository struct {
+	db *database.DB
+}
+
+// NewBasketRepository creates a new basket repository
+func NewBasketRepository(db *database.DB) *BasketRepository {
+	return &BasketRepository{db: db}
+}
+
+// Create inserts a new basket
+func (r *BasketRepository) Create(ctx context.Context, basket *models.SymbolBasket) error {
+	query := `
+		INSERT INTO symbol_baskets (user_id, name, symbols, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, basket.UserID, basket.Name, basket.Symbols, now, now).Scan(&basket.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create basket: %w", err)
+	}
+
+	basket.CreatedAt = now
+	basket.UpdatedAt = now
+	return nil
+}
+
+// GetByID retrieves a basket by ID, returning nil if it does not exist
+func (r *BasketRepository) GetByID(ctx context.Context, id int64) (*models.SymbolBasket, error) {
+	query := `
+		SELECT id, user_id, name, symbols, created_at, updated_at
+		FROM symbol_baskets
+		WHERE id = $1
+	`
+
+	var b models.SymbolBasket
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(&b.ID, &b.UserID, &b.Name, &b.Symbols, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get basket: %w", err)
+	}
+
+	return &b, nil
+}
+
+// GetByUser retrieves all baskets of a user
+func (r *BasketRepository) GetByUser(ctx context.Context, userID string) ([]models.SymbolBasket, error) {
+	query := `
+		SELECT id, user_id, name, symbols, created_at, updated_at
+		FROM symbol_baskets
+		WHERE user_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query baskets: %w", err)
+	}
+	defer rows.Close()
+
+	baskets := []models.SymbolBasket{}
+	for rows.Next() {
+		var b models.SymbolBasket
+		if err := rows.Scan(&b.ID, &b.UserID, &b.Name, &b.Symbols, &b.CreatedAt, &b.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan basket: %w", err)
+		}
+		baskets = append(baskets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating baskets: %w", err)
+	}
+
+	return baskets, nil
+}
+
+// Update updates a basket's name and members
+func (r *BasketRepository) Update(ctx context.Context, basket *models.SymbolBasket) error {
+	query := `
+		UPDATE symbol_baskets
+		SET name = $2, symbols = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	basket.UpdatedAt = time.Now()
+	if _, err := r.db.Pool.Exec(ctx, query, basket.ID, basket.Name, basket.Symbols, basket.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update basket: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a basket
+func (r *BasketRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM symbol_baskets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete basket: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// candleDiscrepancyColumns are the selected columns, in scan order
+const candleDiscrepancyColumns = `symbol, interval, open_time,
+		       stream_open::float8, stream_high::float8, stream_low::float8, stream_close::float8,
+		       stream_volume::float8, stream_trade_count,
+		       rest_open::float8, rest_high::float8, rest_low::float8, rest_close::float8,
+		       rest_volume::float8, rest_trade_count,
+		       price_diff_pct, volume_diff_pct, kept_source, detected_at`
+
+// CandleDiscrepancyRepository handles database operations for diverged stream/REST candles
+type CandleDiscrepancyRepository struct {
+	db *database.DB
+}
+
+// NewCandleDiscrepancyRepository creates a new candle discrepancy repository
+func NewCandleDiscrepancyRepository(db *database.DB) *CandleDiscrepancyRepository {
+	return &CandleDiscrepancyRepository{db: db}
+}
+
+// Upsert records a discrepancy, replacing an earlier record of the same bar
+func (r *CandleDiscrepancyRepository) Upsert(ctx context.Context, d *models.CandleDiscrepancy) error {
+	query := `
+		INSERT INTO candle_discrepancies (symbol, interval, open_time,
+			stream_open, stream_high, stream_low, stream_close, stream_volume, stream_trade_count,
+			rest_open, rest_high, rest_low, rest_close, rest_volume, rest_trade_count,
+			price_diff_pct, volume_diff_pct, kept_source, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (symbol, interval, open_time) DO UPDATE SET
+			stream_open = EXCLUDED.stream_open,
+			stream_high = EXCLUDED.stream_high,
+			stream_low = EXCLUDED.stream_low,
+			stream_close = EXCLUDED.stream_close,
+			stream_volume = EXCLUDED.stream_volume,
+			stream_trade_count = EXCLUDED.stream_trade_count,
+			rest_open = EXCLUDED.rest_open,
+			rest_high = EXCLUDED.rest_high,
+			rest_low = EXCLUDED.rest_low,
+			rest_close = EXCLUDED.rest_close,
+			rest_volume = EXCLUDED.rest_volume,
+			rest_trade_count = EXCLUDED.rest_trade_count,
+			price_diff_pct = EXCLUDED.price_diff_pct,
+			volume_diff_pct = EXCLUDED.volume_diff_pct,
+			kept_source = EXCLUDED.kept_source,
+			detected_at = EXCLUDED.detected_at
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query, d.Symbol, d.Interval, d.OpenTime,
+		d.Stream.Open, d.Stream.High, d.Stream.Low, d.Stream.Close, d.Stream.Volume, d.Stream.TradeCount,
+		d.REST.Open, d.REST.High, d.REST.Low, d.REST.Close, d.REST.Volume, d.REST.TradeCount,
+		d.PriceDiffPct, d.VolumeDiffPct, d.KeptSource, d.DetectedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record candle discrepancy: %w", err)
+	}
+	return nil
+}
+
+// GetRange retrieves the discrepancies of bars opening within a time range, oldest first
+func (r *CandleDiscrepancyRepository) GetRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.CandleDiscrepancy, error) {
+	query := `
+		SELECT ` + candleDiscrepancyColumns + `
+		FROM candle_discrepancies
+		WHERE symbol = $1 AND interval = $2 AND open_time >= $3 AND open_time <= $4
+		ORDER BY open_time
+	`
+	return r.query(ctx, query, symbol, interval, startTime, endTime)
+}
+
+// GetRecent retrieves a symbol's latest discrepancies, newest first; an empty interval matches all
+func (r *CandleDiscrepancyRepository) GetRecent(ctx context.Context, symbol, interval string, limit int) ([]models.CandleDiscrepancy, error) {
+	query := `
+		SELECT ` + candleDiscrepancyColumns + `
+		FROM candle_discrepancies
+		WHERE symbol = $1 AND ($2 = '' OR interval = $2)
+		ORDER BY detected_at DESC
+		LIMIT $3
+	`
+	return r.query(ctx, query, symbol, interval, limit)
+}
+
+// query runs a discrepancy query and scans every row
+func (r *CandleDiscrepancyRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.CandleDiscrepancy, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candle discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	discrepancies := []models.CandleDiscrepancy{}
+	for rows.Next() {
+		var d models.CandleDiscrepancy
+		err := rows.Scan(&d.Symbol, &d.Interval, &d.OpenTime,
+			&d.Stream.Open, &d.Stream.High, &d.Stream.Low, &d.Stream.Close, &d.Stream.Volume, &d.Stream.TradeCount,
+			&d.REST.Open, &d.REST.High, &d.REST.Low, &d.REST.Close, &d.REST.Volume, &d.REST.TradeCount,
+			&d.PriceDiffPct, &d.VolumeDiffPct, &d.KeptSource, &d.DetectedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle discrepancy: %w", err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+
+	return discrepancies, rows.Err()
+}
@@ -25,17 +25,21 @@ func (r *CandleRepository) Create(ctx context.Context, candle *models.Candle) er
 	query := `
 		INSERT INTO candles (symbol, open_time, open, high, low, close, volume, close_time, 
 		                     quote_asset_volume, trade_count, taker_buy_base_asset_volume, 
-		                     taker_buy_quote_asset_volume, interval, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		                     taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, exchange, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id
 	`
 
 	now := time.Now()
+	candle.Source = candleSource(candle)
+	if models.LacksTakerVolume(candle) {
+		*candle = models.EstimateTakerVolume([]models.Candle{*candle})[0]
+	}
 	err := r.db.Pool.QueryRow(ctx, query,
 		candle.Symbol, candle.OpenTime, candle.Open, candle.High, candle.Low,
 		candle.Close, candle.Volume, candle.CloseTime, candle.QuoteAssetVolume,
 		candle.TradeCount, candle.TakerBuyBaseAssetVolume, candle.TakerBuyQuoteAssetVolume,
-		candle.Interval, now, now,
+		candle.Interval, candle.Source, candle.TakerVolumeEstimated, models.SymbolExchange(candle.Symbol), now, now,
 	).Scan(&candle.ID)
 
 	if err != nil {
@@ -52,11 +56,11 @@ func (r *CandleRepository) GetBySymbolAndInterval(ctx context.Context, symbol, i
 	query := `
 		SELECT id, symbol, open_time, open, high, low, close, volume, close_time,
 		       quote_asset_volume, trade_count, taker_buy_base_asset_volume,
-		       taker_buy_quote_asset_volume, interval, created_at, updated_at
+		       taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, created_at, updated_at
 		FROM (
 			SELECT id, symbol, open_time, open, high, low, close, volume, close_time,
 			       quote_asset_volume, trade_count, taker_buy_base_asset_volume,
-			       taker_buy_quote_asset_volume, interval, created_at, updated_at
+			       taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, created_at, updated_at
 			FROM candles
 			WHERE symbol = $1 AND interval = $2
 			ORDER BY open_time DESC
@@ -79,7 +83,51 @@ func (r *CandleRepository) GetBySymbolAndInterval(ctx context.Context, symbol, i
 			&candle.High, &candle.Low, &candle.Close, &candle.Volume,
 			&candle.CloseTime, &candle.QuoteAssetVolume, &candle.TradeCount,
 			&candle.TakerBuyBaseAssetVolume, &candle.TakerBuyQuoteAssetVolume,
-			&candle.Interval, &candle.CreatedAt, &candle.UpdatedAt,
+			&candle.Interval, &candle.Source, &candle.TakerVolumeEstimated, &candle.CreatedAt, &candle.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+
+	return candles, nil
+}
+
+// GetBeforeTime retrieves the limit candles opening before a time, oldest first
+// Walks idx_candles_symbol_interval_time backwards from the anchor, so paging into history is as cheap as the latest page
+func (r *CandleRepository) GetBeforeTime(ctx context.Context, symbol, interval string, before time.Time, limit int) ([]models.Candle, error) {
+	query := `
+		SELECT id, symbol, open_time, open, high, low, close, volume, close_time,
+		       quote_asset_volume, trade_count, taker_buy_base_asset_volume,
+		       taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, created_at, updated_at
+		FROM (
+			SELECT id, symbol, open_time, open, high, low, close, volume, close_time,
+			       quote_asset_volume, trade_count, taker_buy_base_asset_volume,
+			       taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, created_at, updated_at
+			FROM candles
+			WHERE symbol = $1 AND interval = $2 AND open_time < $3
+			ORDER BY open_time DESC
+			LIMIT $4
+		) AS anchored_candles
+		ORDER BY open_time ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candles before %s: %w", before.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	var candles []models.Candle
+	for rows.Next() {
+		var candle models.Candle
+		err := rows.Scan(
+			&candle.ID, &candle.Symbol, &candle.OpenTime, &candle.Open,
+			&candle.High, &candle.Low, &candle.Close, &candle.Volume,
+			&candle.CloseTime, &candle.QuoteAssetVolume, &candle.TradeCount,
+			&candle.TakerBuyBaseAssetVolume, &candle.TakerBuyQuoteAssetVolume,
+			&candle.Interval, &candle.Source, &candle.TakerVolumeEstimated, &candle.CreatedAt, &candle.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan candle: %w", err)
@@ -95,7 +143,7 @@ func (r *CandleRepository) GetLatest(ctx context.Context, symbol, interval strin
 	query := `
 		SELECT id, symbol, open_time, open, high, low, close, volume, close_time,
 		       quote_asset_volume, trade_count, taker_buy_base_asset_volume,
-		       taker_buy_quote_asset_volume, interval, created_at, updated_at
+		       taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, created_at, updated_at
 		FROM candles
 		WHERE symbol = $1 AND interval = $2
 		ORDER BY open_time DESC
@@ -108,7 +156,7 @@ func (r *CandleRepository) GetLatest(ctx context.Context, symbol, interval strin
 		&candle.High, &candle.Low, &candle.Close, &candle.Volume,
 		&candle.CloseTime, &candle.QuoteAssetVolume, &candle.TradeCount,
 		&candle.TakerBuyBaseAssetVolume, &candle.TakerBuyQuoteAssetVolume,
-		&candle.Interval, &candle.CreatedAt, &candle.UpdatedAt,
+		&candle.Interval, &candle.Source, &candle.TakerVolumeEstimated, &candle.CreatedAt, &candle.UpdatedAt,
 	)
 
 	if err != nil {
@@ -126,7 +174,7 @@ func (r *CandleRepository) GetByTimeRange(ctx context.Context, symbol, interval
 	query := `
 		SELECT id, symbol, open_time, open, high, low, close, volume, close_time,
 		       quote_asset_volume, trade_count, taker_buy_base_asset_volume,
-		       taker_buy_quote_asset_volume, interval, created_at, updated_at
+		       taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, created_at, updated_at
 		FROM candles
 		WHERE symbol = $1 AND interval = $2 AND open_time >= $3 AND open_time <= $4
 		ORDER BY open_time ASC
@@ -146,7 +194,7 @@ func (r *CandleRepository) GetByTimeRange(ctx context.Context, symbol, interval
 			&candle.High, &candle.Low, &candle.Close, &candle.Volume,
 			&candle.CloseTime, &candle.QuoteAssetVolume, &candle.TradeCount,
 			&candle.TakerBuyBaseAssetVolume, &candle.TakerBuyQuoteAssetVolume,
-			&candle.Interval, &candle.CreatedAt, &candle.UpdatedAt,
+			&candle.Interval, &candle.Source, &candle.TakerVolumeEstimated, &candle.CreatedAt, &candle.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan candle: %w", err)
@@ -166,12 +214,14 @@ func (r *CandleRepository) BulkCreate(ctx context.Context, candles []models.Cand
 	batch := &pgx.Batch{}
 	now := time.Now()
 
+	// Candles from sources without taker volume are stored with an estimated split
+	candles = models.EstimateTakerVolume(candles)
 	for _, candle := range candles {
 		batch.Queue(`
 			INSERT INTO candles (symbol, open_time, open, high, low, close, volume, close_time, 
 			                     quote_asset_volume, trade_count, taker_buy_base_asset_volume, 
-			                     taker_buy_quote_asset_volume, interval, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			                     taker_buy_quote_asset_volume, interval, source, taker_volume_estimated, exchange, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 			ON CONFLICT (symbol, open_time, interval) DO UPDATE SET
 				open = EXCLUDED.open,
 				high = EXCLUDED.high,
@@ -183,12 +233,14 @@ func (r *CandleRepository) BulkCreate(ctx context.Context, candles []models.Cand
 				trade_count = EXCLUDED.trade_count,
 				taker_buy_base_asset_volume = EXCLUDED.taker_buy_base_asset_volume,
 				taker_buy_quote_asset_volume = EXCLUDED.taker_buy_quote_asset_volume,
-				updated_at = $15
+				source = EXCLUDED.source,
+				taker_volume_estimated = EXCLUDED.taker_volume_estimated,
+				updated_at = $18
 		`,
 			candle.Symbol, candle.OpenTime, candle.Open, candle.High, candle.Low,
 			candle.Close, candle.Volume, candle.CloseTime, candle.QuoteAssetVolume,
 			candle.TradeCount, candle.TakerBuyBaseAssetVolume, candle.TakerBuyQuoteAssetVolume,
-			candle.Interval, now, now,
+			candle.Interval, candleSource(&candle), candle.TakerVolumeEstimated, models.SymbolExchange(candle.Symbol), now, now,
 		)
 	}
 
@@ -208,9 +260,9 @@ func (r *CandleRepository) BulkCreate(ctx context.Context, candles []models.Cand
 // GetOptimizedCandleData returns minimal candle data for ultra-fast frontend rendering
 func (r *CandleRepository) GetOptimizedCandleData(ctx context.Context, symbol, interval string, limit int) ([]models.OptimizedCandle, error) {
 	query := `
-		SELECT open_time, open, high, low, close, volume, taker_buy_base_asset_volume
+		SELECT open_time, open, high, low, close, volume, taker_buy_base_asset_volume, taker_volume_estimated
 		FROM (
-			SELECT open_time, open, high, low, close, volume, taker_buy_base_asset_volume
+			SELECT open_time, open, high, low, close, volume, taker_buy_base_asset_volume, taker_volume_estimated
 			FROM candles
 			WHERE symbol = $1 AND interval = $2
 			ORDER BY open_time DESC
@@ -225,14 +277,43 @@ func (r *CandleRepository) GetOptimizedCandleData(ctx context.Context, symbol, i
 	}
 	defer rows.Close()
 
+	return scanOptimizedCandles(rows, limit)
+}
+
+// GetOptimizedCandleDataBefore returns minimal candle data for the limit candles opening before a time
+func (r *CandleRepository) GetOptimizedCandleDataBefore(ctx context.Context, symbol, interval string, before time.Time, limit int) ([]models.OptimizedCandle, error) {
+	query := `
+		SELECT open_time, open, high, low, close, volume, taker_buy_base_asset_volume, taker_volume_estimated
+		FROM (
+			SELECT open_time, open, high, low, close, volume, taker_buy_base_asset_volume, taker_volume_estimated
+			FROM candles
+			WHERE symbol = $1 AND interval = $2 AND open_time < $3
+			ORDER BY open_time DESC
+			LIMIT $4
+		) AS anchored_candles
+		ORDER BY open_time ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get optimized candles before %s: %w", before.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	return scanOptimizedCandles(rows, limit)
+}
+
+// scanOptimizedCandles converts candle rows to the optimized format with buy/sell volume split
+func scanOptimizedCandles(rows pgx.Rows, limit int) ([]models.OptimizedCandle, error) {
 	// Pre-allocate slice for performance
 	candles := make([]models.OptimizedCandle, 0, limit)
 
 	for rows.Next() {
 		var openTime time.Time
 		var open, high, low, close, volume, takerBuyVolume string
+		var estimated bool
 
-		err := rows.Scan(&openTime, &open, &high, &low, &close, &volume, &takerBuyVolume)
+		err := rows.Scan(&openTime, &open, &high, &low, &close, &volume, &takerBuyVolume, &estimated)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan optimized candle: %w", err)
 		}
@@ -251,6 +332,7 @@ func (r *CandleRepository) GetOptimizedCandleData(ctx context.Context, symbol, i
 			V:  totalVolume,
 			BV: buyVolume,
 			SV: sellVolume,
+			E:  estimated,
 		})
 	}
 
@@ -263,6 +345,9 @@ func (r *CandleRepository) BulkCreateOptimized(ctx context.Context, candles []mo
 		return nil
 	}
 
+	// Candles from sources without taker volume are stored with an estimated split
+	candles = models.EstimateTakerVolume(candles)
+
 	// Use COPY for maximum insert performance (10x faster than INSERT)
 	copyCount, err := r.db.Pool.CopyFrom(
 		ctx,
@@ -270,7 +355,7 @@ func (r *CandleRepository) BulkCreateOptimized(ctx context.Context, candles []mo
 		[]string{"symbol", "open_time", "open", "high", "low", "close", "volume",
 			"close_time", "quote_asset_volume", "trade_count",
 			"taker_buy_base_asset_volume", "taker_buy_quote_asset_volume",
-			"interval", "created_at", "updated_at"},
+			"interval", "source", "taker_volume_estimated", "exchange", "created_at", "updated_at"},
 		pgx.CopyFromSlice(len(candles), func(i int) ([]interface{}, error) {
 			candle := candles[i]
 			now := time.Now()
@@ -278,7 +363,7 @@ func (r *CandleRepository) BulkCreateOptimized(ctx context.Context, candles []mo
 				candle.Symbol, candle.OpenTime, candle.Open, candle.High, candle.Low,
 				candle.Close, candle.Volume, candle.CloseTime, candle.QuoteAssetVolume,
 				candle.TradeCount, candle.TakerBuyBaseAssetVolume, candle.TakerBuyQuoteAssetVolume,
-				candle.Interval, now, now,
+				candle.Interval, candleSource(&candle), candle.TakerVolumeEstimated, models.SymbolExchange(candle.Symbol), now, now,
 			}, nil
 		}),
 	)
@@ -392,6 +477,42 @@ func (r *CandleRepository) GetCandleAggregates(ctx context.Context, symbol, inte
 	return aggregates, nil
 }
 
+// GetSourceStats aggregates stored candles per source within a time range, for coverage reports
+func (r *CandleRepository) GetSourceStats(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.CandleSourceStats, error) {
+	query := `
+		SELECT source, COUNT(*), MIN(open_time), MAX(open_time), MAX(updated_at)
+		FROM candles
+		WHERE symbol = $1 AND interval = $2 AND open_time >= $3 AND open_time <= $4
+		GROUP BY source
+		ORDER BY COUNT(*) DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candle source stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.CandleSourceStats
+	for rows.Next() {
+		var stat models.CandleSourceStats
+		if err := rows.Scan(&stat.Source, &stat.Count, &stat.FirstOpenTime, &stat.LastOpenTime, &stat.LastUpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan candle source stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+// candleSource returns the candle's source, defaulting unlabeled candles to REST polling
+func candleSource(candle *models.Candle) string {
+	if candle.Source == "" {
+		return models.CandleSourceRESTPoll
+	}
+	return candle.Source
+}
+
 // Helper types for aggregated queries
 type VolumeProfileRow struct {
 	PriceLevel  float64
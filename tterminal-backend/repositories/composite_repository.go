@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CompositeRepository handles database operations for composite symbols
+type CompositeRepository struct {
+	db *database.DB
+}
+
+// NewCompositeRepository creates a new composite repository
+func NewCompositeRepository(db *database.DB) *CompositeRepository {
+	return &CompositeRepository{db: db}
+}
+
+// Create inserts a new composite symbol
+func (r *CompositeRepository) Create(ctx context.Context, composite *models.CompositeSymbol) error {
+	query := `
+		INSERT INTO composite_symbols (user_id, name, expression, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, composite.UserID, composite.Name, composite.Expression, now).Scan(&composite.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create composite: %w", err)
+	}
+
+	composite.CreatedAt = now
+	return nil
+}
+
+// GetByID retrieves a composite by ID, returning nil if it does not exist
+func (r *CompositeRepository) GetByID(ctx context.Context, id int64) (*models.CompositeSymbol, error) {
+	query := `
+		SELECT id, user_id, name, expression, created_at
+		FROM composite_symbols
+		WHERE id = $1
+	`
+
+	var cs models.CompositeSymbol
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(&cs.ID, &cs.UserID, &cs.Name, &cs.Expression, &cs.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get composite: %w", err)
+	}
+
+	return &cs, nil
+}
+
+// GetByName retrieves a composite by name, returning nil if it does not exist
+func (r *CompositeRepository) GetByName(ctx context.Context, name string) (*models.CompositeSymbol, error) {
+	query := `
+		SELECT id, user_id, name, expression, created_at
+		FROM composite_symbols
+		WHERE name = $1
+	`
+
+	var cs models.CompositeSymbol
+	err := r.db.Pool.QueryRow(ctx, query, name).Scan(&cs.ID, &cs.UserID, &cs.Name, &cs.Expression, &cs.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get composite: %w", err)
+	}
+
+	return &cs, nil
+}
+
+// GetByUser retrieves all composites of a user
+func (r *CompositeRepository) GetByUser(ctx context.Context, userID string) ([]models.CompositeSymbol, error) {
+	return r.query(ctx, `
+		SELECT id, user_id, name, expression, created_at
+		FROM composite_symbols
+		WHERE user_id = $1
+		ORDER BY id ASC
+	`, userID)
+}
+
+// GetAll retrieves every composite, for computing them live
+func (r *CompositeRepository) GetAll(ctx context.Context) ([]models.CompositeSymbol, error) {
+	return r.query(ctx, `
+		SELECT id, user_id, name, expression, created_at
+		FROM composite_symbols
+		ORDER BY id ASC
+	`)
+}
+
+// Delete removes a composite together with its stored candles
+func (r *CompositeRepository) Delete(ctx context.Context, composite *models.CompositeSymbol) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM candles WHERE symbol = $1`, composite.Symbol); err != nil {
+		return fmt.Errorf("failed to delete composite candles: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM composite_symbols WHERE id = $1`, composite.ID); err != nil {
+		return fmt.Errorf("failed to delete composite: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// query runs a composite listing query
+func (r *CompositeRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.CompositeSymbol, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query composites: %w", err)
+	}
+	defer rows.Close()
+
+	composites := []models.CompositeSymbol{}
+	for rows.Next() {
+		var cs models.CompositeSymbol
+		if err := rows.Scan(&cs.ID, &cs.UserID, &cs.Name, &cs.Expression, &cs.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan composite: %w", err)
+		}
+		composites = append(composites, cs)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating composites: %w", err)
+	}
+
+	return composites, nil
+}
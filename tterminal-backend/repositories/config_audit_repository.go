@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// ConfigAuditRepository handles database operations for the configuration reload audit log
+type ConfigAuditRepository struct {
+	db *database.DB
+}
+
+// NewConfigAuditRepository creates a new config audit repository
+func NewConfigAuditRepository(db *database.DB) *ConfigAuditRepository {
+	return &ConfigAuditRepository{db: db}
+}
+
+// Create records a reload
+func (r *ConfigAuditRepository) Create(ctx context.Context, entry *models.ConfigAuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode config changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO config_audit (source, actor, changes, error, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	now := time.Now()
+	if err := r.db.Pool.QueryRow(ctx, query, entry.Source, entry.Actor, changes, entry.Error, now).Scan(&entry.ID); err != nil {
+		return fmt.Errorf("failed to create config audit entry: %w", err)
+	}
+
+	entry.CreatedAt = now
+	return nil
+}
+
+// GetRecent retrieves the most recent reloads, newest first
+func (r *ConfigAuditRepository) GetRecent(ctx context.Context, limit int) ([]models.ConfigAuditEntry, error) {
+	query := `
+		SELECT id, source, actor, changes, error, created_at
+		FROM config_audit
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query config audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ConfigAuditEntry{}
+	for rows.Next() {
+		var entry models.ConfigAuditEntry
+		var changes []byte
+		if err := rows.Scan(&entry.ID, &entry.Source, &entry.Actor, &changes, &entry.Error, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan config audit entry: %w", err)
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode config changes: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating config audit: %w", err)
+	}
+
+	return entries, nil
+}
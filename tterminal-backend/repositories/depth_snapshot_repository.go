@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DepthSnapshotRepository handles database operations for persisted order book snapshots
+type DepthSnapshotRepository struct {
+	db *database.DB
+}
+
+// NewDepthSnapshotRepository creates a new depth snapshot repository
+func NewDepthSnapshotRepository(db *database.DB) *DepthSnapshotRepository {
+	return &DepthSnapshotRepository{db: db}
+}
+
+// BulkCreate inserts the levels of multiple snapshots, ignoring snapshots that were already stored
+func (r *DepthSnapshotRepository) BulkCreate(ctx context.Context, snapshots []models.DepthSnapshot) error {
+	batch := &pgx.Batch{}
+	queue := func(snapshot models.DepthSnapshot, side string, levels []models.DepthLevel) {
+		for _, level := range levels {
+			batch.Queue(`
+				INSERT INTO depth_levels (symbol, snapshot_time, side, price, quantity, exchange)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (symbol, snapshot_time, side, price) DO NOTHING
+			`,
+				snapshot.Symbol, snapshot.Time, side, level.Price, level.Quantity, models.SymbolExchange(snapshot.Symbol),
+			)
+		}
+	}
+	for _, snapshot := range snapshots {
+		queue(snapshot, models.DepthSideBid, snapshot.Bids)
+		queue(snapshot, models.DepthSideAsk, snapshot.Asks)
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	br := r.db.Pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("failed to insert depth level %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// CountSnapshots returns the number of snapshots stored for a symbol within a time range
+func (r *DepthSnapshotRepository) CountSnapshots(ctx context.Context, symbol string, startTime, endTime time.Time) (int64, error) {
+	query := `
+		SELECT COUNT(DISTINCT snapshot_time)
+		FROM depth_levels
+		WHERE symbol = $1 AND snapshot_time >= $2 AND snapshot_time <= $3
+	`
+
+	var count int64
+	if err := r.db.Pool.QueryRow(ctx, query, symbol, startTime, endTime).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count depth snapshots: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetLatestMidPrice returns the mid price of the most recent snapshot for a symbol
+func (r *DepthSnapshotRepository) GetLatestMidPrice(ctx context.Context, symbol string) (float64, error) {
+	query := `
+		SELECT ((MAX(price) FILTER (WHERE side = 'bid') + MIN(price) FILTER (WHERE side = 'ask')) / 2)::float8
+		FROM depth_levels
+		WHERE symbol = $1 AND snapshot_time = (
+			SELECT MAX(snapshot_time) FROM depth_levels WHERE symbol = $1
+		)
+	`
+
+	var mid *float64
+	if err := r.db.Pool.QueryRow(ctx, query, symbol).Scan(&mid); err != nil {
+		return 0, fmt.Errorf("failed to get latest depth mid price: %w", err)
+	}
+	if mid == nil {
+		return 0, nil
+	}
+
+	return *mid, nil
+}
+
+// GetLevelStats aggregates resting liquidity per side and price bucket over a time range
+// A touch is a snapshot in which the bucket held liquidity while containing the best bid (or ask)
+func (r *DepthSnapshotRepository) GetLevelStats(ctx context.Context, symbol string, bucketSize float64, startTime, endTime time.Time) ([]models.DepthLevelStats, error) {
+	query := `
+		WITH levels AS (
+			SELECT snapshot_time, side, FLOOR(price / $2) * $2 AS bucket, SUM(quantity) AS quantity
+			FROM depth_levels
+			WHERE symbol = $1 AND snapshot_time >= $3 AND snapshot_time <= $4
+			GROUP BY snapshot_time, side, bucket
+		),
+		tops AS (
+			SELECT snapshot_time,
+			       MAX(price) FILTER (WHERE side = 'bid') AS best_bid,
+			       MIN(price) FILTER (WHERE side = 'ask') AS best_ask
+			FROM depth_levels
+			WHERE symbol = $1 AND snapshot_time >= $3 AND snapshot_time <= $4
+			GROUP BY snapshot_time
+		)
+		SELECT l.side, l.bucket::float8,
+		       COUNT(*) AS snapshots,
+		       AVG(l.quantity)::float8 AS avg_quantity,
+		       MAX(l.quantity)::float8 AS max_quantity,
+		       COUNT(*) FILTER (WHERE
+		           (l.side = 'bid' AND t.best_bid >= l.bucket AND t.best_bid < l.bucket + $2) OR
+		           (l.side = 'ask' AND t.best_ask >= l.bucket AND t.best_ask < l.bucket + $2)
+		       ) AS touches,
+		       MIN(l.snapshot_time) AS first_seen,
+		       MAX(l.snapshot_time) AS last_seen
+		FROM levels l
+		JOIN tops t ON t.snapshot_time = l.snapshot_time
+		GROUP BY l.side, l.bucket
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, bucketSize, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get depth level stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.DepthLevelStats
+	for rows.Next() {
+		var s models.DepthLevelStats
+		err := rows.Scan(&s.Side, &s.Price, &s.Snapshots, &s.AvgQuantity, &s.MaxQuantity, &s.Touches, &s.FirstSeen, &s.LastSeen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan depth level stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FootprintRepository handles database operations for stored footprint levels
+type FootprintRepository struct {
+	db *database.DB
+}
+
+// NewFootprintRepository creates a new footprint repository
+func NewFootprintRepository(db *database.DB) *FootprintRepository {
+	return &FootprintRepository{db: db}
+}
+
+// UpsertFromTrades builds the footprint levels of one bar from the persisted trades in
+// [openTime, closeTime) and stores them, replacing the bar's earlier levels in the same buckets
+// Returns the number of levels written
+func (r *FootprintRepository) UpsertFromTrades(ctx context.Context, symbol, interval string, openTime, closeTime time.Time, bucketSize float64) (int64, error) {
+	query := `
+		INSERT INTO footprint_levels (symbol, interval, open_time, price_bucket, bucket_size, buy_volume, sell_volume, trade_count, updated_at)
+		SELECT $1, $2, $3,
+		       FLOOR(price / $5::numeric) * $5::numeric AS price_bucket,
+		       $5::numeric,
+		       COALESCE(SUM(quantity) FILTER (WHERE NOT is_buyer_maker), 0),
+		       COALESCE(SUM(quantity) FILTER (WHERE is_buyer_maker), 0),
+		       COUNT(*),
+		       NOW()
+		FROM trades
+		WHERE symbol = $1 AND trade_time >= $3 AND trade_time < $4
+		GROUP BY price_bucket
+		ON CONFLICT (symbol, interval, open_time, price_bucket) DO UPDATE SET
+			bucket_size = EXCLUDED.bucket_size,
+			buy_volume = EXCLUDED.buy_volume,
+			sell_volume = EXCLUDED.sell_volume,
+			trade_count = EXCLUDED.trade_count,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, symbol, interval, openTime, closeTime, bucketSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert footprint levels: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetRange returns the stored footprint bars opening in [startTime, endTime), oldest first
+func (r *FootprintRepository) GetRange(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([]models.FootprintCandle, error) {
+	query := `
+		SELECT open_time, price_bucket::float8, buy_volume::float8, sell_volume::float8, trade_count
+		FROM footprint_levels
+		WHERE symbol = $1 AND interval = $2 AND open_time >= $3 AND open_time < $4
+		ORDER BY open_time ASC, price_bucket ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get footprint range: %w", err)
+	}
+
+	return scanFootprintBars(rows)
+}
+
+// GetLatest returns the last limit stored footprint bars, oldest first
+func (r *FootprintRepository) GetLatest(ctx context.Context, symbol, interval string, limit int) ([]models.FootprintCandle, error) {
+	query := `
+		SELECT open_time, price_bucket::float8, buy_volume::float8, sell_volume::float8, trade_count
+		FROM footprint_levels
+		WHERE symbol = $1 AND interval = $2 AND open_time IN (
+			SELECT DISTINCT open_time
+			FROM footprint_levels
+			WHERE symbol = $1 AND interval = $2
+			ORDER BY open_time DESC
+			LIMIT $3
+		)
+		ORDER BY open_time ASC, price_bucket ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest footprint bars: %w", err)
+	}
+
+	return scanFootprintBars(rows)
+}
+
+// scanFootprintBars groups level rows ordered by open time and price into footprint bars
+func scanFootprintBars(rows pgx.Rows) ([]models.FootprintCandle, error) {
+	defer rows.Close()
+
+	bars := []models.FootprintCandle{}
+	var (
+		openTime time.Time
+		levels   []models.FootprintLevel
+	)
+	for rows.Next() {
+		var (
+			rowTime time.Time
+			level   models.FootprintLevel
+		)
+		if err := rows.Scan(&rowTime, &level.P, &level.BV, &level.SV, &level.T); err != nil {
+			return nil, fmt.Errorf("failed to scan footprint level: %w", err)
+		}
+		level.D = level.BV - level.SV
+
+		if !rowTime.Equal(openTime) && len(levels) > 0 {
+			bars = append(bars, models.NewFootprintCandle(openTime.UnixMilli(), levels))
+			levels = nil
+		}
+		openTime = rowTime
+		levels = append(levels, level)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating footprint levels: %w", err)
+	}
+	if len(levels) > 0 {
+		bars = append(bars, models.NewFootprintCandle(openTime.UnixMilli(), levels))
+	}
+
+	return bars, nil
+}
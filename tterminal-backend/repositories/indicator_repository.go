@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IndicatorRepository handles database operations for custom indicators
+type IndicatorRepository struct {
+	db *database.DB
+}
+
+// NewIndicatorRepository creates a new custom indicator repository
+func NewIndicatorRepository(db *database.DB) *IndicatorRepository {
+	return &IndicatorRepository{db: db}
+}
+
+// Create inserts a new custom indicator
+func (r *IndicatorRepository) Create(ctx context.Context, indicator *models.CustomIndicator) error {
+	query := `
+		INSERT INTO custom_indicators (user_id, name, expression, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, indicator.UserID, indicator.Name, indicator.Expression, now, now).Scan(&indicator.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create indicator: %w", err)
+	}
+
+	indicator.CreatedAt = now
+	indicator.UpdatedAt = now
+	return nil
+}
+
+// GetByID retrieves a custom indicator by ID, returning nil if it does not exist
+func (r *IndicatorRepository) GetByID(ctx context.Context, id int64) (*models.CustomIndicator, error) {
+	query := `
+		SELECT id, user_id, name, expression, created_at, updated_at
+		FROM custom_indicators
+		WHERE id = $1
+	`
+
+	var i models.CustomIndicator
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(&i.ID, &i.UserID, &i.Name, &i.Expression, &i.CreatedAt, &i.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get indicator: %w", err)
+	}
+
+	return &i, nil
+}
+
+// GetByUser retrieves all custom indicators of a user
+func (r *IndicatorRepository) GetByUser(ctx context.Context, userID string) ([]models.CustomIndicator, error) {
+	query := `
+		SELECT id, user_id, name, expression, created_at, updated_at
+		FROM custom_indicators
+		WHERE user_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indicators: %w", err)
+	}
+	defer rows.Close()
+
+	indicators := []models.CustomIndicator{}
+	for rows.Next() {
+		var i models.CustomIndicator
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Name, &i.Expression, &i.CreatedAt, &i.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan indicator: %w", err)
+		}
+		indicators = append(indicators, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating indicators: %w", err)
+	}
+
+	return indicators, nil
+}
+
+// Update updates a custom indicator's name and expression
+func (r *IndicatorRepository) Update(ctx context.Context, indicator *models.CustomIndicator) error {
+	query := `
+		UPDATE custom_indicators
+		SET name = $2, expression = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	indicator.UpdatedAt = time.Now()
+	if _, err := r.db.Pool.Exec(ctx, query, indicator.ID, indicator.Name, indicator.Expression, indicator.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update indicator: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a custom indicator
+func (r *IndicatorRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM custom_indicators WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete indicator: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// MarketEventRepository handles database operations for indexed market events
+type MarketEventRepository struct {
+	db *database.DB
+}
+
+// NewMarketEventRepository creates a new market event repository
+func NewMarketEventRepository(db *database.DB) *MarketEventRepository {
+	return &MarketEventRepository{db: db}
+}
+
+// Create inserts an event, ignoring a bar already indexed for the same type
+func (r *MarketEventRepository) Create(ctx context.Context, event *models.MarketEvent) error {
+	query := `
+		INSERT INTO market_events (symbol, interval, event_type, open_time, magnitude, reference,
+		                           open, high, low, close, volume, exchange, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (symbol, interval, event_type, open_time) DO NOTHING
+	`
+
+	event.DetectedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query,
+		event.Symbol, event.Interval, event.Type, event.OpenTime, event.Magnitude, event.Reference,
+		event.Open, event.High, event.Low, event.Close, event.Volume,
+		models.SymbolExchange(event.Symbol), event.DetectedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create market event: %w", err)
+	}
+
+	return nil
+}
+
+// RangeFloor returns the smallest magnitude among the keep largest range events of a symbol and
+// interval, and how many range events are stored (up to keep)
+func (r *MarketEventRepository) RangeFloor(ctx context.Context, symbol, interval string, keep int) (floor float64, count int, err error) {
+	query := `
+		SELECT COALESCE(MIN(magnitude), 0), COUNT(*)
+		FROM (
+			SELECT magnitude
+			FROM market_events
+			WHERE symbol = $1 AND interval = $2 AND event_type = $3
+			ORDER BY magnitude DESC
+			LIMIT $4
+		) AS top_ranges
+	`
+
+	if err := r.db.Pool.QueryRow(ctx, query, symbol, interval, models.MarketEventRange, keep).Scan(&floor, &count); err != nil {
+		return 0, 0, fmt.Errorf("failed to get range event floor: %w", err)
+	}
+	return floor, count, nil
+}
+
+// PruneRanges deletes range events of a symbol and interval outside the keep largest
+func (r *MarketEventRepository) PruneRanges(ctx context.Context, symbol, interval string, keep int) (int64, error) {
+	query := `
+		DELETE FROM market_events
+		WHERE symbol = $1 AND interval = $2 AND event_type = $3
+		  AND id NOT IN (
+			SELECT id
+			FROM market_events
+			WHERE symbol = $1 AND interval = $2 AND event_type = $3
+			ORDER BY magnitude DESC, open_time DESC
+			LIMIT $4
+		  )
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, symbol, interval, models.MarketEventRange, keep)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune range events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// List returns events of a symbol opening before a time, newest first, or largest first when
+// byMagnitude is set. An empty interval or types matches every interval or type
+func (r *MarketEventRepository) List(ctx context.Context, symbol, interval string, types []string, before time.Time, byMagnitude bool, limit int) ([]models.MarketEvent, error) {
+	order := "open_time DESC"
+	if byMagnitude {
+		order = "ABS(magnitude) DESC, open_time DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, symbol, interval, event_type, open_time, magnitude, reference,
+		       open::float8, high::float8, low::float8, close::float8, volume::float8, detected_at
+		FROM market_events
+		WHERE symbol = $1
+		  AND ($2 = '' OR interval = $2)
+		  AND (cardinality($3::text[]) = 0 OR event_type = ANY($3))
+		  AND ($4::timestamptz IS NULL OR open_time < $4)
+		ORDER BY %s
+		LIMIT $5
+	`, order)
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, emptyIfNil(types), nullTime(before), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list market events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.MarketEvent{}
+	for rows.Next() {
+		var event models.MarketEvent
+		err := rows.Scan(
+			&event.ID, &event.Symbol, &event.Interval, &event.Type, &event.OpenTime, &event.Magnitude, &event.Reference,
+			&event.Open, &event.High, &event.Low, &event.Close, &event.Volume, &event.DetectedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan market event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate market events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,344 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PortfolioRepository handles database operations for portfolios, positions and orders
+type PortfolioRepository struct {
+	db *database.DB
+}
+
+// NewPortfolioRepository creates a new portfolio repository
+func NewPortfolioRepository(db *database.DB) *PortfolioRepository {
+	return &PortfolioRepository{db: db}
+}
+
+// Create inserts a new portfolio
+func (r *PortfolioRepository) Create(ctx context.Context, portfolio *models.Portfolio) error {
+	query := `
+		INSERT INTO portfolios (user_id, name, mode, initial_balance, balance,
+		                        max_leverage, max_position_notional, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query,
+		portfolio.UserID, portfolio.Name, portfolio.Mode, portfolio.InitialBalance, portfolio.Balance,
+		portfolio.MaxLeverage, portfolio.MaxPositionNotional, now, now,
+	).Scan(&portfolio.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create portfolio: %w", err)
+	}
+
+	portfolio.CreatedAt = now
+	portfolio.UpdatedAt = now
+	return nil
+}
+
+// GetByID retrieves a portfolio by ID, returning nil if it does not exist
+func (r *PortfolioRepository) GetByID(ctx context.Context, id int64) (*models.Portfolio, error) {
+	query := `
+		SELECT id, user_id, name, mode, initial_balance, balance, realized_pnl, fees_paid, funding_accrued,
+		       max_leverage, max_position_notional, created_at, updated_at
+		FROM portfolios
+		WHERE id = $1
+	`
+
+	var p models.Portfolio
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&p.ID, &p.UserID, &p.Name, &p.Mode, &p.InitialBalance, &p.Balance, &p.RealizedPnL, &p.FeesPaid, &p.FundingAccrued,
+		&p.MaxLeverage, &p.MaxPositionNotional, &p.CreatedAt, &p.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	return &p, nil
+}
+
+// GetByUser retrieves all portfolios of a user
+func (r *PortfolioRepository) GetByUser(ctx context.Context, userID string) ([]models.Portfolio, error) {
+	query := `
+		SELECT id, user_id, name, mode, initial_balance, balance, realized_pnl, fees_paid, funding_accrued,
+		       max_leverage, max_position_notional, created_at, updated_at
+		FROM portfolios
+		WHERE user_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query portfolios: %w", err)
+	}
+	defer rows.Close()
+
+	var portfolios []models.Portfolio
+	for rows.Next() {
+		var p models.Portfolio
+		err := rows.Scan(
+			&p.ID, &p.UserID, &p.Name, &p.Mode, &p.InitialBalance, &p.Balance, &p.RealizedPnL, &p.FeesPaid, &p.FundingAccrued,
+			&p.MaxLeverage, &p.MaxPositionNotional, &p.CreatedAt, &p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan portfolio: %w", err)
+		}
+		portfolios = append(portfolios, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating portfolios: %w", err)
+	}
+
+	return portfolios, nil
+}
+
+// Update updates a portfolio's name and risk limits
+func (r *PortfolioRepository) Update(ctx context.Context, portfolio *models.Portfolio) error {
+	query := `
+		UPDATE portfolios
+		SET name = $2, max_leverage = $3, max_position_notional = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	portfolio.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query,
+		portfolio.ID, portfolio.Name, portfolio.MaxLeverage, portfolio.MaxPositionNotional, portfolio.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update portfolio: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a portfolio together with its positions and orders
+func (r *PortfolioRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM portfolios WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete portfolio: %w", err)
+	}
+	return nil
+}
+
+// positionColumns lists the columns scanned by scanPositions
+const positionColumns = `portfolio_id, symbol, quantity, entry_price, realized_pnl,
+		       fees_paid, funding_accrued, funding_settled_at, updated_at`
+
+// GetPositions retrieves all positions of a portfolio, including closed positions
+func (r *PortfolioRepository) GetPositions(ctx context.Context, portfolioID int64) ([]models.PortfolioPosition, error) {
+	query := `
+		SELECT ` + positionColumns + `
+		FROM portfolio_positions
+		WHERE portfolio_id = $1
+		ORDER BY symbol ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPositions(rows)
+}
+
+// GetPosition retrieves a portfolio's position in a symbol, returning nil if none exists
+func (r *PortfolioRepository) GetPosition(ctx context.Context, portfolioID int64, symbol string) (*models.PortfolioPosition, error) {
+	query := `
+		SELECT ` + positionColumns + `
+		FROM portfolio_positions
+		WHERE portfolio_id = $1 AND symbol = $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, portfolioID, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query position: %w", err)
+	}
+	defer rows.Close()
+
+	positions, err := scanPositions(rows)
+	if err != nil || len(positions) == 0 {
+		return nil, err
+	}
+	return &positions[0], nil
+}
+
+// GetOpenPositions retrieves every open position across all portfolios
+func (r *PortfolioRepository) GetOpenPositions(ctx context.Context) ([]models.PortfolioPosition, error) {
+	query := `
+		SELECT ` + positionColumns + `
+		FROM portfolio_positions
+		WHERE quantity <> 0
+		ORDER BY symbol ASC, portfolio_id ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open positions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPositions(rows)
+}
+
+// scanPositions scans position rows selected with positionColumns
+func scanPositions(rows pgx.Rows) ([]models.PortfolioPosition, error) {
+	var positions []models.PortfolioPosition
+	for rows.Next() {
+		var p models.PortfolioPosition
+		err := rows.Scan(
+			&p.PortfolioID, &p.Symbol, &p.Quantity, &p.EntryPrice, &p.RealizedPnL,
+			&p.FeesPaid, &p.FundingAccrued, &p.FundingSettledAt, &p.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan position: %w", err)
+		}
+		positions = append(positions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// GetOrders retrieves the most recent orders of a portfolio, newest first
+func (r *PortfolioRepository) GetOrders(ctx context.Context, portfolioID int64, limit int) ([]models.PortfolioOrder, error) {
+	query := `
+		SELECT id, portfolio_id, symbol, side, quantity, price, realized_pnl, fee, created_at
+		FROM portfolio_orders
+		WHERE portfolio_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, portfolioID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []models.PortfolioOrder
+	for rows.Next() {
+		var o models.PortfolioOrder
+		if err := rows.Scan(&o.ID, &o.PortfolioID, &o.Symbol, &o.Side, &o.Quantity, &o.Price, &o.RealizedPnL, &o.Fee, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// ApplyFill records a filled order, its resulting position and the balance change in one transaction
+// The balance changes by the order's realized PnL minus its fee
+func (r *PortfolioRepository) ApplyFill(ctx context.Context, order *models.PortfolioOrder, position *models.PortfolioPosition) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO portfolio_orders (portfolio_id, symbol, side, quantity, price, realized_pnl, fee, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`,
+		order.PortfolioID, order.Symbol, order.Side, order.Quantity, order.Price, order.RealizedPnL, order.Fee, now,
+	).Scan(&order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert order: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO portfolio_positions (portfolio_id, symbol, quantity, entry_price, realized_pnl,
+		                                 fees_paid, funding_accrued, funding_settled_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (portfolio_id, symbol) DO UPDATE SET
+			quantity = EXCLUDED.quantity,
+			entry_price = EXCLUDED.entry_price,
+			realized_pnl = EXCLUDED.realized_pnl,
+			fees_paid = EXCLUDED.fees_paid,
+			funding_accrued = EXCLUDED.funding_accrued,
+			funding_settled_at = EXCLUDED.funding_settled_at,
+			updated_at = EXCLUDED.updated_at
+	`,
+		position.PortfolioID, position.Symbol, position.Quantity, position.EntryPrice, position.RealizedPnL,
+		position.FeesPaid, position.FundingAccrued, position.FundingSettledAt, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save position: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE portfolios
+		SET balance = balance + $2 - $3, realized_pnl = realized_pnl + $2, fees_paid = fees_paid + $3, updated_at = $4
+		WHERE id = $1
+	`, order.PortfolioID, order.RealizedPnL, order.Fee, now)
+	if err != nil {
+		return fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit fill: %w", err)
+	}
+
+	order.CreatedAt = now
+	position.UpdatedAt = now
+	return nil
+}
+
+// ApplyFunding credits a funding payment (negative when paid) to a position and its portfolio balance
+func (r *PortfolioRepository) ApplyFunding(ctx context.Context, portfolioID int64, symbol string, payment float64, settledAt time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+
+	_, err = tx.Exec(ctx, `
+		UPDATE portfolio_positions
+		SET funding_accrued = funding_accrued + $3, funding_settled_at = $4, updated_at = $5
+		WHERE portfolio_id = $1 AND symbol = $2
+	`, portfolioID, symbol, payment, settledAt, now)
+	if err != nil {
+		return fmt.Errorf("failed to update position funding: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE portfolios
+		SET balance = balance + $2, funding_accrued = funding_accrued + $2, updated_at = $3
+		WHERE id = $1
+	`, portfolioID, payment, now)
+	if err != nil {
+		return fmt.Errorf("failed to update portfolio funding: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit funding: %w", err)
+	}
+
+	return nil
+}
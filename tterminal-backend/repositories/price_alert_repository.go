@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// priceAlertColumns are the columns scanned by scanPriceAlert
+const priceAlertColumns = `id, user_id, symbol, market, note, price, direction, hysteresis, mode,
+	state, armed_below, armed_above, trigger_count, last_price, last_triggered_at, webhook_ids, created_at, updated_at`
+
+// PriceAlertRepository handles database operations for user price alerts
+type PriceAlertRepository struct {
+	db *database.DB
+}
+
+// NewPriceAlertRepository creates a new price alert repository
+func NewPriceAlertRepository(db *database.DB) *PriceAlertRepository {
+	return &PriceAlertRepository{db: db}
+}
+
+// Create inserts a new price alert
+func (r *PriceAlertRepository) Create(ctx context.Context, alert *models.PriceAlert) error {
+	query := `
+		INSERT INTO price_alerts (user_id, symbol, market, note, price, direction, hysteresis, mode,
+			state, armed_below, armed_above, trigger_count, last_price, webhook_ids, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, alert.UserID, alert.Symbol, alert.Market, alert.Note,
+		alert.Price, alert.Direction, alert.Hysteresis, alert.Mode,
+		alert.State, alert.ArmedBelow, alert.ArmedAbove, alert.TriggerCount, alert.LastPrice, alert.WebhookIDs, now, now).Scan(&alert.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create price alert: %w", err)
+	}
+
+	alert.CreatedAt = now
+	alert.UpdatedAt = now
+	return nil
+}
+
+// GetByID retrieves a price alert by ID, returning nil if it does not exist
+func (r *PriceAlertRepository) GetByID(ctx context.Context, id int64) (*models.PriceAlert, error) {
+	query := `SELECT ` + priceAlertColumns + ` FROM price_alerts WHERE id = $1`
+
+	alert, err := scanPriceAlert(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get price alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// GetByUser retrieves the price alerts of a user, optionally only those in a state
+func (r *PriceAlertRepository) GetByUser(ctx context.Context, userID, state string) ([]models.PriceAlert, error) {
+	query := `SELECT ` + priceAlertColumns + ` FROM price_alerts
+		WHERE user_id = $1 AND ($2 = '' OR state = $2)
+		ORDER BY id ASC`
+	return r.queryPriceAlerts(ctx, query, userID, state)
+}
+
+// GetActive retrieves every price alert that can still fire, for evaluation
+func (r *PriceAlertRepository) GetActive(ctx context.Context) ([]models.PriceAlert, error) {
+	query := `SELECT ` + priceAlertColumns + ` FROM price_alerts WHERE state <> $1 ORDER BY id ASC`
+	return r.queryPriceAlerts(ctx, query, models.AlertStateTriggered)
+}
+
+// CountByUser returns the number of price alerts a user has
+func (r *PriceAlertRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM price_alerts WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count price alerts: %w", err)
+	}
+	return count, nil
+}
+
+// queryPriceAlerts runs a price alert query and scans every row
+func (r *PriceAlertRepository) queryPriceAlerts(ctx context.Context, query string, args ...interface{}) ([]models.PriceAlert, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []models.PriceAlert{}
+	for rows.Next() {
+		alert, err := scanPriceAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price alert: %w", err)
+		}
+		alerts = append(alerts, *alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating price alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// SaveState stores the evaluation state of a price alert. A triggered alert is never moved
+// back, so an instance evaluating a stale copy cannot revive an alert another one completed
+func (r *PriceAlertRepository) SaveState(ctx context.Context, alert *models.PriceAlert) error {
+	query := `
+		UPDATE price_alerts
+		SET state = $2, armed_below = $3, armed_above = $4, trigger_count = GREATEST(trigger_count, $5),
+			last_price = $6, last_triggered_at = COALESCE($7, last_triggered_at), updated_at = $8
+		WHERE id = $1 AND state <> $9
+	`
+
+	alert.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query, alert.ID, alert.State, alert.ArmedBelow, alert.ArmedAbove,
+		alert.TriggerCount, alert.LastPrice, alert.LastTriggeredAt, alert.UpdatedAt, models.AlertStateTriggered)
+	if err != nil {
+		return fmt.Errorf("failed to save price alert state: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a price alert
+func (r *PriceAlertRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM price_alerts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete price alert: %w", err)
+	}
+	return nil
+}
+
+// scanPriceAlert scans a row of priceAlertColumns
+func scanPriceAlert(row pgx.Row) (*models.PriceAlert, error) {
+	var alert models.PriceAlert
+	err := row.Scan(&alert.ID, &alert.UserID, &alert.Symbol, &alert.Market, &alert.Note,
+		&alert.Price, &alert.Direction, &alert.Hysteresis, &alert.Mode,
+		&alert.State, &alert.ArmedBelow, &alert.ArmedAbove, &alert.TriggerCount, &alert.LastPrice,
+		&alert.LastTriggeredAt, &alert.WebhookIDs, &alert.CreatedAt, &alert.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
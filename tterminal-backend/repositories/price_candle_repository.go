@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PriceCandleRepository handles database operations for mark and index price candles
+type PriceCandleRepository struct {
+	db *database.DB
+}
+
+// NewPriceCandleRepository creates a new price candle repository
+func NewPriceCandleRepository(db *database.DB) *PriceCandleRepository {
+	return &PriceCandleRepository{db: db}
+}
+
+// GetBySymbolAndInterval retrieves the most recent price candles for a symbol, interval and price type
+func (r *PriceCandleRepository) GetBySymbolAndInterval(ctx context.Context, symbol, interval, priceType string, limit int) ([]models.Candle, error) {
+	query := `
+		SELECT symbol, price_type, interval, open_time, open, high, low, close, close_time, created_at, updated_at
+		FROM (
+			SELECT symbol, price_type, interval, open_time, open, high, low, close, close_time, created_at, updated_at
+			FROM price_candles
+			WHERE symbol = $1 AND interval = $2 AND price_type = $3
+			ORDER BY open_time DESC
+			LIMIT $4
+		) AS recent_candles
+		ORDER BY open_time ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, priceType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price candles: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPriceCandles(rows)
+}
+
+// GetBeforeTime retrieves the limit price candles opening before a time, oldest first
+func (r *PriceCandleRepository) GetBeforeTime(ctx context.Context, symbol, interval, priceType string, before time.Time, limit int) ([]models.Candle, error) {
+	query := `
+		SELECT symbol, price_type, interval, open_time, open, high, low, close, close_time, created_at, updated_at
+		FROM (
+			SELECT symbol, price_type, interval, open_time, open, high, low, close, close_time, created_at, updated_at
+			FROM price_candles
+			WHERE symbol = $1 AND interval = $2 AND price_type = $3 AND open_time < $4
+			ORDER BY open_time DESC
+			LIMIT $5
+		) AS anchored_candles
+		ORDER BY open_time ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, priceType, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price candles before %s: %w", before.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	return scanPriceCandles(rows)
+}
+
+// GetByTimeRange retrieves price candles within a time range
+func (r *PriceCandleRepository) GetByTimeRange(ctx context.Context, symbol, interval, priceType string, startTime, endTime time.Time) ([]models.Candle, error) {
+	query := `
+		SELECT symbol, price_type, interval, open_time, open, high, low, close, close_time, created_at, updated_at
+		FROM price_candles
+		WHERE symbol = $1 AND interval = $2 AND price_type = $3 AND open_time >= $4 AND open_time <= $5
+		ORDER BY open_time ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, interval, priceType, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price candles by time range: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPriceCandles(rows)
+}
+
+// BulkCreate upserts multiple price candles (each candle's PriceType must be set)
+func (r *PriceCandleRepository) BulkCreate(ctx context.Context, candles []models.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	now := time.Now()
+
+	for _, candle := range candles {
+		batch.Queue(`
+			INSERT INTO price_candles (symbol, price_type, interval, open_time, open, high, low, close,
+			                           close_time, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (symbol, price_type, interval, open_time) DO UPDATE SET
+				open = EXCLUDED.open,
+				high = EXCLUDED.high,
+				low = EXCLUDED.low,
+				close = EXCLUDED.close,
+				close_time = EXCLUDED.close_time,
+				updated_at = $11
+		`,
+			candle.Symbol, candle.PriceType, candle.Interval, candle.OpenTime,
+			candle.Open, candle.High, candle.Low, candle.Close,
+			candle.CloseTime, now, now,
+		)
+	}
+
+	br := r.db.Pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < len(candles); i++ {
+		_, err := br.Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert price candle %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// scanPriceCandles converts price candle rows to candles with zero volume fields
+func scanPriceCandles(rows pgx.Rows) ([]models.Candle, error) {
+	var candles []models.Candle
+	for rows.Next() {
+		candle := models.Candle{
+			Volume:                   "0",
+			QuoteAssetVolume:         "0",
+			TakerBuyBaseAssetVolume:  "0",
+			TakerBuyQuoteAssetVolume: "0",
+		}
+		err := rows.Scan(
+			&candle.Symbol, &candle.PriceType, &candle.Interval, &candle.OpenTime,
+			&candle.Open, &candle.High, &candle.Low, &candle.Close,
+			&candle.CloseTime, &candle.CreatedAt, &candle.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price candle: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+
+	return candles, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// purgeTimeColumns maps each purgeable dataset table to its time column
+var purgeTimeColumns = map[string]string{
+	models.PurgeDatasetCandles:      "open_time",
+	models.PurgeDatasetPriceCandles: "open_time",
+	models.PurgeDatasetTrades:       "trade_time",
+	models.PurgeDatasetDepth:        "snapshot_time",
+	models.PurgeDatasetFootprints:   "open_time",
+}
+
+// PurgeRepository deletes stored market data of a symbol across datasets
+type PurgeRepository struct {
+	db *database.DB
+}
+
+// NewPurgeRepository creates a new purge repository
+func NewPurgeRepository(db *database.DB) *PurgeRepository {
+	return &PurgeRepository{db: db}
+}
+
+// Count returns the rows of a dataset for a symbol within [startTime, endTime)
+// Zero times leave that side of the range open
+func (r *PurgeRepository) Count(ctx context.Context, dataset, symbol string, startTime, endTime time.Time) (int64, error) {
+	column, ok := purgeTimeColumns[dataset]
+	if !ok {
+		return 0, fmt.Errorf("unknown dataset: %s", dataset)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s
+		WHERE symbol = $1
+		  AND ($2::timestamptz IS NULL OR %s >= $2)
+		  AND ($3::timestamptz IS NULL OR %s < $3)
+	`, dataset, column, column)
+
+	var count int64
+	if err := r.db.Pool.QueryRow(ctx, query, symbol, nullTime(startTime), nullTime(endTime)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s rows: %w", dataset, err)
+	}
+	return count, nil
+}
+
+// TimeBounds returns the oldest and newest row time of a dataset for a symbol within the range
+// ok is false when no rows match
+func (r *PurgeRepository) TimeBounds(ctx context.Context, dataset, symbol string, startTime, endTime time.Time) (oldest, newest time.Time, ok bool, err error) {
+	column, known := purgeTimeColumns[dataset]
+	if !known {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("unknown dataset: %s", dataset)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT MIN(%s), MAX(%s)
+		FROM %s
+		WHERE symbol = $1
+		  AND ($2::timestamptz IS NULL OR %s >= $2)
+		  AND ($3::timestamptz IS NULL OR %s < $3)
+	`, column, column, dataset, column, column)
+
+	var minTime, maxTime *time.Time
+	if err := r.db.Pool.QueryRow(ctx, query, symbol, nullTime(startTime), nullTime(endTime)).Scan(&minTime, &maxTime); err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("failed to get %s time bounds: %w", dataset, err)
+	}
+	if minTime == nil || maxTime == nil {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return *minTime, *maxTime, true, nil
+}
+
+// Delete removes the rows of a dataset for a symbol within [startTime, endTime)
+func (r *PurgeRepository) Delete(ctx context.Context, dataset, symbol string, startTime, endTime time.Time) (int64, error) {
+	column, ok := purgeTimeColumns[dataset]
+	if !ok {
+		return 0, fmt.Errorf("unknown dataset: %s", dataset)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE symbol = $1 AND %s >= $2 AND %s < $3`, dataset, column, column)
+
+	tag, err := r.db.Pool.Exec(ctx, query, symbol, startTime, endTime)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %s rows: %w", dataset, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// nullTime passes a zero time as SQL NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ReportRepository handles database operations for report settings and generated reports
+type ReportRepository struct {
+	db *database.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *database.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// GetSettings retrieves a user's report settings, returning nil if none exist
+func (r *ReportRepository) GetSettings(ctx context.Context, userID string) (*models.ReportSettings, error) {
+	query := `
+		SELECT user_id, watchlist, email, email_enabled, updated_at
+		FROM report_settings
+		WHERE user_id = $1
+	`
+
+	var settings models.ReportSettings
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
+		&settings.UserID, &settings.Watchlist, &settings.Email, &settings.EmailEnabled, &settings.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings upserts a user's report settings
+func (r *ReportRepository) SaveSettings(ctx context.Context, settings *models.ReportSettings) error {
+	query := `
+		INSERT INTO report_settings (user_id, watchlist, email, email_enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			watchlist = EXCLUDED.watchlist,
+			email = EXCLUDED.email,
+			email_enabled = EXCLUDED.email_enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	settings.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query,
+		settings.UserID, emptyIfNil(settings.Watchlist), settings.Email, settings.EmailEnabled, settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save report settings: %w", err)
+	}
+
+	return nil
+}
+
+// GetReportUserIDs returns every user with report settings or persisted subscriptions
+func (r *ReportRepository) GetReportUserIDs(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT user_id FROM report_settings
+		UNION
+		SELECT user_id FROM user_subscriptions WHERE cardinality(symbols) > 0
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report users: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan report user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report users: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// SaveReport upserts the report of a user for its report date
+func (r *ReportRepository) SaveReport(ctx context.Context, report *models.Report) error {
+	payload, err := json.Marshal(report.Recap)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	query := `
+		INSERT INTO reports (user_id, report_date, payload, created_at)
+		VALUES ($1, $2::date, $3, $4)
+		ON CONFLICT (user_id, report_date) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			emailed_at = NULL,
+			created_at = EXCLUDED.created_at
+		RETURNING id
+	`
+
+	now := time.Now()
+	err = r.db.Pool.QueryRow(ctx, query, report.UserID, report.ReportDate, payload, now).Scan(&report.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+
+	report.CreatedAt = now
+	report.EmailedAt = nil
+	return nil
+}
+
+// GetReport retrieves a user's report by ID, returning nil if it does not exist
+func (r *ReportRepository) GetReport(ctx context.Context, userID string, id int64) (*models.Report, error) {
+	query := `
+		SELECT id, user_id, to_char(report_date, 'YYYY-MM-DD'), payload, emailed_at, created_at
+		FROM reports
+		WHERE user_id = $1 AND id = $2
+	`
+	return r.getReport(ctx, query, userID, id)
+}
+
+// GetLatestReport retrieves a user's most recent report, returning nil if none exist
+func (r *ReportRepository) GetLatestReport(ctx context.Context, userID string) (*models.Report, error) {
+	query := `
+		SELECT id, user_id, to_char(report_date, 'YYYY-MM-DD'), payload, emailed_at, created_at
+		FROM reports
+		WHERE user_id = $1
+		ORDER BY report_date DESC
+		LIMIT 1
+	`
+	return r.getReport(ctx, query, userID)
+}
+
+// GetHistory retrieves a user's report history, newest first
+func (r *ReportRepository) GetHistory(ctx context.Context, userID string, limit int) ([]models.ReportSummary, error) {
+	query := `
+		SELECT id, to_char(report_date, 'YYYY-MM-DD'), COALESCE(jsonb_array_length(payload->'symbols'), 0),
+		       emailed_at, created_at
+		FROM reports
+		WHERE user_id = $1
+		ORDER BY report_date DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.ReportSummary
+	for rows.Next() {
+		var summary models.ReportSummary
+		if err := rows.Scan(&summary.ID, &summary.ReportDate, &summary.SymbolCount, &summary.EmailedAt, &summary.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan report summary: %w", err)
+		}
+		history = append(history, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report history: %w", err)
+	}
+
+	return history, nil
+}
+
+// MarkEmailed records when a report was emailed
+func (r *ReportRepository) MarkEmailed(ctx context.Context, id int64, emailedAt time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE reports SET emailed_at = $2 WHERE id = $1`, id, emailedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark report emailed: %w", err)
+	}
+	return nil
+}
+
+// getReport runs a single-report query and decodes its payload
+func (r *ReportRepository) getReport(ctx context.Context, query string, args ...interface{}) (*models.Report, error) {
+	var report models.Report
+	var payload []byte
+	err := r.db.Pool.QueryRow(ctx, query, args...).Scan(
+		&report.ID, &report.UserID, &report.ReportDate, &payload, &report.EmailedAt, &report.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	if err := json.Unmarshal(payload, &report.Recap); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionRecordingRepository archives WebSocket session recordings in Redis
+// Each recording is a metadata key plus a list of messages, both expiring after the retention
+type SessionRecordingRepository struct {
+	cache     *cache.RedisCache
+	retention time.Duration
+}
+
+// NewSessionRecordingRepository creates a session recording repository
+func NewSessionRecordingRepository(redisCache *cache.RedisCache, retention time.Duration) *SessionRecordingRepository {
+	return &SessionRecordingRepository{cache: redisCache, retention: retention}
+}
+
+// SaveRecording stores a recording's metadata
+func (r *SessionRecordingRepository) SaveRecording(ctx context.Context, recording *models.SessionRecording) error {
+	if err := r.cache.Set(ctx, recordingKey(recording.ID), recording, r.retention); err != nil {
+		return fmt.Errorf("failed to save session recording: %w", err)
+	}
+	return nil
+}
+
+// AppendMessages adds messages to a recording
+func (r *SessionRecordingRepository) AppendMessages(ctx context.Context, recordingID string, messages []models.RecordedMessage) error {
+	values := make([][]byte, 0, len(messages))
+	for _, message := range messages {
+		value, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal recorded message: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if err := r.cache.AppendList(ctx, recordingMessagesKey(recordingID), values, r.retention); err != nil {
+		return fmt.Errorf("failed to append recorded messages: %w", err)
+	}
+	return nil
+}
+
+// GetRecording returns a recording's metadata, or nil if it does not exist or has expired
+func (r *SessionRecordingRepository) GetRecording(ctx context.Context, recordingID string) (*models.SessionRecording, error) {
+	var recording models.SessionRecording
+	if err := r.cache.Get(ctx, recordingKey(recordingID), &recording); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session recording: %w", err)
+	}
+	return &recording, nil
+}
+
+// GetMessages returns up to limit recorded messages starting at offset, oldest first
+func (r *SessionRecordingRepository) GetMessages(ctx context.Context, recordingID string, offset, limit int) ([]models.RecordedMessage, error) {
+	values, err := r.cache.GetListRange(ctx, recordingMessagesKey(recordingID), int64(offset), int64(offset+limit-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recorded messages: %w", err)
+	}
+
+	messages := make([]models.RecordedMessage, 0, len(values))
+	for _, value := range values {
+		var message models.RecordedMessage
+		if err := json.Unmarshal([]byte(value), &message); err != nil {
+			return nil, fmt.Errorf("failed to decode recorded message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// recordingKey returns the Redis key of a recording's metadata
+func recordingKey(recordingID string) string {
+	return "ws:recording:" + recordingID
+}
+
+// recordingMessagesKey returns the Redis key of a recording's message list
+func recordingMessagesKey(recordingID string) string {
+	return "ws:recording:" + recordingID + ":messages"
+}
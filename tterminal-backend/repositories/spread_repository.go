@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SpreadRepository handles database operations for spread samples
+type SpreadRepository struct {
+	db *database.DB
+}
+
+// NewSpreadRepository creates a new spread repository
+func NewSpreadRepository(db *database.DB) *SpreadRepository {
+	return &SpreadRepository{db: db}
+}
+
+// BulkCreate inserts spread samples, ignoring samples that were already stored
+func (r *SpreadRepository) BulkCreate(ctx context.Context, samples []models.SpreadSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, sample := range samples {
+		batch.Queue(`
+			INSERT INTO spreads (pair, time, kind, price_a, price_b, spread, spread_bps)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (pair, time) DO NOTHING
+		`,
+			sample.Name, sample.Time, sample.Kind, sample.PriceA, sample.PriceB, sample.Spread, sample.SpreadBps,
+		)
+	}
+
+	br := r.db.Pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("failed to insert spread sample %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// GetBuckets aggregates a pair's spread samples into fixed time buckets, oldest first
+func (r *SpreadRepository) GetBuckets(ctx context.Context, pair string, bucket time.Duration, startTime, endTime time.Time) ([]models.SpreadBucket, error) {
+	query := `
+		SELECT time_bucket(make_interval(secs => $2), time) AS bucket,
+		       AVG(spread_bps)::float8 AS avg_bps,
+		       MIN(spread_bps)::float8 AS min_bps,
+		       MAX(spread_bps)::float8 AS max_bps,
+		       (ARRAY_AGG(spread ORDER BY time DESC))[1]::float8 AS last_spread,
+		       COUNT(*) AS samples
+		FROM spreads
+		WHERE pair = $1 AND time >= $3 AND time < $4
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, pair, bucket.Seconds(), startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spread buckets: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []models.SpreadBucket{}
+	for rows.Next() {
+		var b models.SpreadBucket
+		if err := rows.Scan(&b.Time, &b.AvgSpreadBps, &b.MinSpreadBps, &b.MaxSpreadBps, &b.LastSpread, &b.Samples); err != nil {
+			return nil, fmt.Errorf("failed to scan spread bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating spread buckets: %w", err)
+	}
+
+	return buckets, nil
+}
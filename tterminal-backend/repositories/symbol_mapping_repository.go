@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+)
+
+// SymbolMappingRepository handles database operations for canonical symbol mappings
+type SymbolMappingRepository struct {
+	db *database.DB
+}
+
+// NewSymbolMappingRepository creates a new symbol mapping repository
+func NewSymbolMappingRepository(db *database.DB) *SymbolMappingRepository {
+	return &SymbolMappingRepository{db: db}
+}
+
+// Upsert maps a canonical symbol on an exchange, replacing any earlier mapping on that exchange
+func (r *SymbolMappingRepository) Upsert(ctx context.Context, mapping *models.SymbolMapping) error {
+	query := `
+		INSERT INTO symbol_mappings (canonical, exchange, exchange_symbol)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (canonical, exchange) DO UPDATE SET
+			exchange_symbol = EXCLUDED.exchange_symbol
+		RETURNING id, created_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query, mapping.Canonical, mapping.Exchange, mapping.ExchangeSymbol).Scan(&mapping.ID, &mapping.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert symbol mapping: %w", err)
+	}
+	return nil
+}
+
+// GetAll retrieves every mapping, ordered by canonical symbol and exchange
+func (r *SymbolMappingRepository) GetAll(ctx context.Context) ([]models.SymbolMapping, error) {
+	query := `
+		SELECT id, canonical, exchange, exchange_symbol, created_at
+		FROM symbol_mappings
+		ORDER BY canonical ASC, exchange ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol mappings: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := []models.SymbolMapping{}
+	for rows.Next() {
+		var m models.SymbolMapping
+		if err := rows.Scan(&m.ID, &m.Canonical, &m.Exchange, &m.ExchangeSymbol, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol mapping: %w", err)
+		}
+		mappings = append(mappings, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+// Delete removes a canonical symbol's mapping on an exchange, reporting whether it existed
+func (r *SymbolMappingRepository) Delete(ctx context.Context, canonical, exchange string) (bool, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM symbol_mappings WHERE canonical = $1 AND exchange = $2`, canonical, exchange)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete symbol mapping: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
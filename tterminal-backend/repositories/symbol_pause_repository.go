@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const symbolPauseColumns = `id, symbol, source, reason, paused_by, paused_at, paused_until, resumed_at, resumed_by`
+
+// SymbolPauseRepository handles database operations for symbol collection pauses
+type SymbolPauseRepository struct {
+	db *database.DB
+}
+
+// NewSymbolPauseRepository creates a new symbol pause repository
+func NewSymbolPauseRepository(db *database.DB) *SymbolPauseRepository {
+	return &SymbolPauseRepository{db: db}
+}
+
+// Create stores a pause in effect. Reports false, storing nothing, when the symbol is already paused
+func (r *SymbolPauseRepository) Create(ctx context.Context, pause *models.SymbolPause) (bool, error) {
+	query := `
+		INSERT INTO symbol_pauses (symbol, source, reason, paused_by, paused_at, paused_until)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (symbol) WHERE resumed_at IS NULL DO NOTHING
+		RETURNING id
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query, pause.Symbol, pause.Source, pause.Reason, pause.PausedBy,
+		pause.PausedAt, pause.Until).Scan(&pause.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create symbol pause: %w", err)
+	}
+	return true, nil
+}
+
+// Resume ends a symbol's pause in effect, returning it; nil when the symbol is not paused
+func (r *SymbolPauseRepository) Resume(ctx context.Context, symbol string, resumedAt time.Time, resumedBy string) (*models.SymbolPause, error) {
+	query := `
+		UPDATE symbol_pauses
+		SET resumed_at = $2, resumed_by = $3
+		WHERE symbol = $1 AND resumed_at IS NULL
+		RETURNING ` + symbolPauseColumns
+
+	pause, err := scanSymbolPause(r.db.Pool.QueryRow(ctx, query, symbol, resumedAt, resumedBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume symbol pause: %w", err)
+	}
+	return pause, nil
+}
+
+// Expire ends a pause that reached its until time as of that time, returning it; nil when the
+// pause already ended
+func (r *SymbolPauseRepository) Expire(ctx context.Context, id int64) (*models.SymbolPause, error) {
+	query := `
+		UPDATE symbol_pauses
+		SET resumed_at = paused_until, resumed_by = $2
+		WHERE id = $1 AND resumed_at IS NULL AND paused_until IS NOT NULL
+		RETURNING ` + symbolPauseColumns
+
+	pause, err := scanSymbolPause(r.db.Pool.QueryRow(ctx, query, id, models.SymbolPauseExpired))
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire symbol pause: %w", err)
+	}
+	return pause, nil
+}
+
+// GetActive retrieves every pause in effect
+func (r *SymbolPauseRepository) GetActive(ctx context.Context) ([]models.SymbolPause, error) {
+	query := `
+		SELECT ` + symbolPauseColumns + `
+		FROM symbol_pauses
+		WHERE resumed_at IS NULL
+		ORDER BY paused_at
+	`
+	return r.query(ctx, query)
+}
+
+// GetOverlapping retrieves a symbol's pauses overlapping a time range, oldest first
+func (r *SymbolPauseRepository) GetOverlapping(ctx context.Context, symbol string, startTime, endTime time.Time) ([]models.SymbolPause, error) {
+	query := `
+		SELECT ` + symbolPauseColumns + `
+		FROM symbol_pauses
+		WHERE symbol = $1 AND paused_at < $3
+		  AND (COALESCE(resumed_at, paused_until) IS NULL OR COALESCE(resumed_at, paused_until) > $2)
+		ORDER BY paused_at
+	`
+	return r.query(ctx, query, symbol, startTime, endTime)
+}
+
+// List retrieves the most recent pauses of a symbol, or of every symbol when symbol is empty
+func (r *SymbolPauseRepository) List(ctx context.Context, symbol string, limit int) ([]models.SymbolPause, error) {
+	query := `
+		SELECT ` + symbolPauseColumns + `
+		FROM symbol_pauses
+		WHERE ($1 = '' OR symbol = $1)
+		ORDER BY paused_at DESC
+		LIMIT $2
+	`
+	return r.query(ctx, query, symbol, limit)
+}
+
+// query runs a pause query and scans every row
+func (r *SymbolPauseRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.SymbolPause, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol pauses: %w", err)
+	}
+	defer rows.Close()
+
+	pauses := []models.SymbolPause{}
+	for rows.Next() {
+		pause, err := scanSymbolPause(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol pause: %w", err)
+		}
+		pauses = append(pauses, *pause)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol pauses: %w", err)
+	}
+
+	return pauses, nil
+}
+
+// scanSymbolPause scans a pause row, returning nil when there is none
+func scanSymbolPause(row pgx.Row) (*models.SymbolPause, error) {
+	var p models.SymbolPause
+	err := row.Scan(&p.ID, &p.Symbol, &p.Source, &p.Reason, &p.PausedBy, &p.PausedAt, &p.Until, &p.ResumedAt, &p.ResumedBy)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
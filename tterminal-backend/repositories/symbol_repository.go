@@ -25,14 +25,15 @@ func (r *SymbolRepository) Create(ctx context.Context, symbol *models.Symbol) er
 	query := `
 		INSERT INTO symbols (symbol, base_asset, quote_asset, status, is_active, 
 		                     price_precision, quantity_precision, min_price, max_price,
-		                     min_qty, max_qty, step_size, tick_size, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		                     min_qty, max_qty, step_size, tick_size, min_notional, contract_multiplier,
+		                     multiplier_up, multiplier_down, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 		RETURNING id
 	`
 
 	now := time.Now()
 	// Handle NULL values for numeric fields
-	var minPrice, maxPrice, minQty, maxQty, stepSize, tickSize interface{}
+	var minPrice, maxPrice, minQty, maxQty, stepSize, tickSize, minNotional, multiplierUp, multiplierDown interface{}
 	if symbol.MinPrice.Valid {
 		minPrice = symbol.MinPrice.String
 	}
@@ -51,11 +52,25 @@ func (r *SymbolRepository) Create(ctx context.Context, symbol *models.Symbol) er
 	if symbol.TickSize.Valid {
 		tickSize = symbol.TickSize.String
 	}
+	if symbol.MinNotional.Valid {
+		minNotional = symbol.MinNotional.String
+	}
+	if symbol.MultiplierUp.Valid {
+		multiplierUp = symbol.MultiplierUp.String
+	}
+	if symbol.MultiplierDown.Valid {
+		multiplierDown = symbol.MultiplierDown.String
+	}
+	contractMultiplier := "1"
+	if symbol.ContractMultiplier.Valid {
+		contractMultiplier = symbol.ContractMultiplier.String
+	}
 
 	err := r.db.Pool.QueryRow(ctx, query,
 		symbol.Symbol, symbol.BaseAsset, symbol.QuoteAsset, symbol.Status, symbol.IsActive,
 		symbol.PricePrecision, symbol.QuantityPrecision, minPrice, maxPrice,
-		minQty, maxQty, stepSize, tickSize, now, now,
+		minQty, maxQty, stepSize, tickSize, minNotional, contractMultiplier,
+		multiplierUp, multiplierDown, now, now,
 	).Scan(&symbol.ID)
 
 	if err != nil {
@@ -72,7 +87,8 @@ func (r *SymbolRepository) GetBySymbol(ctx context.Context, symbolName string) (
 	query := `
 		SELECT id, symbol, base_asset, quote_asset, status, is_active,
 		       price_precision, quantity_precision, min_price, max_price,
-		       min_qty, max_qty, step_size, tick_size, created_at, updated_at
+		       min_qty, max_qty, step_size, tick_size, min_notional, contract_multiplier,
+		       multiplier_up, multiplier_down, created_at, updated_at
 		FROM symbols
 		WHERE symbol = $1
 	`
@@ -82,7 +98,8 @@ func (r *SymbolRepository) GetBySymbol(ctx context.Context, symbolName string) (
 		&symbol.ID, &symbol.Symbol, &symbol.BaseAsset, &symbol.QuoteAsset,
 		&symbol.Status, &symbol.IsActive, &symbol.PricePrecision, &symbol.QuantityPrecision,
 		&symbol.MinPrice, &symbol.MaxPrice, &symbol.MinQty, &symbol.MaxQty,
-		&symbol.StepSize, &symbol.TickSize, &symbol.CreatedAt, &symbol.UpdatedAt,
+		&symbol.StepSize, &symbol.TickSize, &symbol.MinNotional, &symbol.ContractMultiplier,
+		&symbol.MultiplierUp, &symbol.MultiplierDown, &symbol.CreatedAt, &symbol.UpdatedAt,
 	)
 
 	if err != nil {
@@ -100,7 +117,8 @@ func (r *SymbolRepository) GetAll(ctx context.Context) ([]models.Symbol, error)
 	query := `
 		SELECT id, symbol, base_asset, quote_asset, status, is_active,
 		       price_precision, quantity_precision, min_price, max_price,
-		       min_qty, max_qty, step_size, tick_size, created_at, updated_at
+		       min_qty, max_qty, step_size, tick_size, min_notional, contract_multiplier,
+		       multiplier_up, multiplier_down, created_at, updated_at
 		FROM symbols
 		ORDER BY symbol ASC
 	`
@@ -118,7 +136,8 @@ func (r *SymbolRepository) GetAll(ctx context.Context) ([]models.Symbol, error)
 			&symbol.ID, &symbol.Symbol, &symbol.BaseAsset, &symbol.QuoteAsset,
 			&symbol.Status, &symbol.IsActive, &symbol.PricePrecision, &symbol.QuantityPrecision,
 			&symbol.MinPrice, &symbol.MaxPrice, &symbol.MinQty, &symbol.MaxQty,
-			&symbol.StepSize, &symbol.TickSize, &symbol.CreatedAt, &symbol.UpdatedAt,
+			&symbol.StepSize, &symbol.TickSize, &symbol.MinNotional, &symbol.ContractMultiplier,
+			&symbol.MultiplierUp, &symbol.MultiplierDown, &symbol.CreatedAt, &symbol.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan symbol: %w", err)
@@ -189,7 +208,8 @@ func (r *SymbolRepository) GetActiveSymbols(ctx context.Context) ([]models.Symbo
 	query := `
 		SELECT id, symbol, base_asset, quote_asset, status, is_active,
 		       price_precision, quantity_precision, min_price, max_price,
-		       min_qty, max_qty, step_size, tick_size, created_at, updated_at
+		       min_qty, max_qty, step_size, tick_size, min_notional, contract_multiplier,
+		       multiplier_up, multiplier_down, created_at, updated_at
 		FROM symbols
 		WHERE is_active = true
 		ORDER BY symbol ASC
@@ -208,7 +228,8 @@ func (r *SymbolRepository) GetActiveSymbols(ctx context.Context) ([]models.Symbo
 			&symbol.ID, &symbol.Symbol, &symbol.BaseAsset, &symbol.QuoteAsset,
 			&symbol.Status, &symbol.IsActive, &symbol.PricePrecision, &symbol.QuantityPrecision,
 			&symbol.MinPrice, &symbol.MaxPrice, &symbol.MinQty, &symbol.MaxQty,
-			&symbol.StepSize, &symbol.TickSize, &symbol.CreatedAt, &symbol.UpdatedAt,
+			&symbol.StepSize, &symbol.TickSize, &symbol.MinNotional, &symbol.ContractMultiplier,
+			&symbol.MultiplierUp, &symbol.MultiplierDown, &symbol.CreatedAt, &symbol.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan symbol: %w", err)
@@ -218,4 +239,26 @@ func (r *SymbolRepository) GetActiveSymbols(ctx context.Context) ([]models.Symbo
 
 	return symbols, nil
 }
- 
\ No newline at end of file
+ 
+// UpdateMetadata replaces a symbol's exchange metadata (status, precision and filters) from an exchangeInfo sync
+func (r *SymbolRepository) UpdateMetadata(ctx context.Context, symbol *models.Symbol) error {
+	query := `
+		UPDATE symbols
+		SET status = $2, is_active = $3, price_precision = $4, quantity_precision = $5,
+		    min_price = $6, max_price = $7, min_qty = $8, max_qty = $9, step_size = $10, tick_size = $11,
+		    min_notional = $12, contract_multiplier = COALESCE($13, contract_multiplier),
+		    multiplier_up = $14, multiplier_down = $15, updated_at = $16
+		WHERE symbol = $1
+	`
+
+	symbol.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query,
+		symbol.Symbol, symbol.Status, symbol.IsActive, symbol.PricePrecision, symbol.QuantityPrecision,
+		symbol.MinPrice, symbol.MaxPrice, symbol.MinQty, symbol.MaxQty, symbol.StepSize, symbol.TickSize,
+		symbol.MinNotional, symbol.ContractMultiplier, symbol.MultiplierUp, symbol.MultiplierDown, symbol.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update symbol metadata: %w", err)
+	}
+	return nil
+}
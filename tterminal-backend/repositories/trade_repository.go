@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TradeRepository handles database operations for persisted trades
+type TradeRepository struct {
+	db *database.DB
+}
+
+// NewTradeRepository creates a new trade repository
+func NewTradeRepository(db *database.DB) *TradeRepository {
+	return &TradeRepository{db: db}
+}
+
+// BulkCreate inserts multiple trades, ignoring trades that were already stored
+func (r *TradeRepository) BulkCreate(ctx context.Context, trades []models.TradeRecord) error {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, trade := range trades {
+		batch.Queue(`
+			INSERT INTO trades (symbol, trade_id, price, quantity, is_buyer_maker, trade_time, exchange)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (symbol, trade_id, trade_time) DO NOTHING
+		`,
+			trade.Symbol, trade.TradeID, trade.Price, trade.Quantity, trade.IsBuyerMaker, trade.TradeTime,
+			models.SymbolExchange(trade.Symbol),
+		)
+	}
+
+	br := r.db.Pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < len(trades); i++ {
+		_, err := br.Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert trade %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// GetByTimeRange retrieves trades within a time range, oldest first
+func (r *TradeRepository) GetByTimeRange(ctx context.Context, symbol string, startTime, endTime time.Time, limit int) ([]models.TradeRecord, error) {
+	query := `
+		SELECT symbol, trade_id, price, quantity, is_buyer_maker, trade_time
+		FROM trades
+		WHERE symbol = $1 AND trade_time >= $2 AND trade_time <= $3
+		ORDER BY trade_time ASC, trade_id ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, startTime, endTime, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades by time range: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []models.TradeRecord
+	for rows.Next() {
+		var trade models.TradeRecord
+		err := rows.Scan(
+			&trade.Symbol, &trade.TradeID, &trade.Price, &trade.Quantity,
+			&trade.IsBuyerMaker, &trade.TradeTime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
+		}
+		trades = append(trades, trade)
+	}
+
+	return trades, nil
+}
+
+// GetFlowBuckets aggregates taker buy/sell volume into fixed time buckets, oldest first
+func (r *TradeRepository) GetFlowBuckets(ctx context.Context, symbol string, bucket time.Duration, startTime, endTime time.Time) ([]models.TradeFlowBucket, error) {
+	query := `
+		SELECT time_bucket(make_interval(secs => $2), trade_time) AS bucket,
+		       COALESCE(SUM(quantity) FILTER (WHERE NOT is_buyer_maker), 0)::float8 AS buy_volume,
+		       COALESCE(SUM(quantity) FILTER (WHERE is_buyer_maker), 0)::float8 AS sell_volume,
+		       COUNT(*) AS trade_count
+		FROM trades
+		WHERE symbol = $1 AND trade_time >= $3 AND trade_time < $4
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, bucket.Seconds(), startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trade flow buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.TradeFlowBucket
+	for rows.Next() {
+		var b models.TradeFlowBucket
+		if err := rows.Scan(&b.Time, &b.BuyVolume, &b.SellVolume, &b.TradeCount); err != nil {
+			return nil, fmt.Errorf("failed to scan trade flow bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, nil
+}
+
+// GetVWAPBuckets sums traded notional, volume and squared-price notional into fixed time buckets,
+// oldest first
+func (r *TradeRepository) GetVWAPBuckets(ctx context.Context, symbol string, bucket time.Duration, startTime, endTime time.Time) ([]models.VWAPBucket, error) {
+	query := `
+		SELECT time_bucket(make_interval(secs => $2), trade_time) AS bucket,
+		       COALESCE(SUM(price * quantity), 0)::float8 AS notional,
+		       COALESCE(SUM(quantity), 0)::float8 AS volume,
+		       COALESCE(SUM(price * price * quantity), 0)::float8 AS square_notional
+		FROM trades
+		WHERE symbol = $1 AND trade_time >= $3 AND trade_time <= $4
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, bucket.Seconds(), startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trade VWAP buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.VWAPBucket
+	for rows.Next() {
+		var b models.VWAPBucket
+		if err := rows.Scan(&b.Time, &b.Notional, &b.Volume, &b.SquareNotional); err != nil {
+			return nil, fmt.Errorf("failed to scan trade VWAP bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trade VWAP buckets: %w", err)
+	}
+
+	return buckets, nil
+}
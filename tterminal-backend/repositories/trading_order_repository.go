@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// tradingOrderColumns are the columns scanned by scanTradingOrder
+const tradingOrderColumns = `id, client_order_id, exchange_order_id, symbol, side, type, time_in_force, quantity,
+	price, stop_price, reduce_only, status, executed_qty, avg_price, last_error, created_by, created_at, updated_at`
+
+// TradingOrderRepository handles database operations for live futures orders
+type TradingOrderRepository struct {
+	db *database.DB
+}
+
+// NewTradingOrderRepository creates a new trading order repository
+func NewTradingOrderRepository(db *database.DB) *TradingOrderRepository {
+	return &TradingOrderRepository{db: db}
+}
+
+// Create inserts an order before it is sent to the exchange
+func (r *TradingOrderRepository) Create(ctx context.Context, order *models.TradingOrder) error {
+	query := `
+		INSERT INTO trading_orders (client_order_id, symbol, side, type, time_in_force, quantity, price, stop_price,
+			reduce_only, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, order.ClientOrderID, order.Symbol, order.Side, order.Type, order.TimeInForce,
+		order.Quantity, order.Price, order.StopPrice, order.ReduceOnly, order.Status, order.CreatedBy, now, now).Scan(&order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create trading order: %w", err)
+	}
+
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	return nil
+}
+
+// GetByID retrieves an order by ID, returning nil if it does not exist
+func (r *TradingOrderRepository) GetByID(ctx context.Context, id int64) (*models.TradingOrder, error) {
+	query := `SELECT ` + tradingOrderColumns + ` FROM trading_orders WHERE id = $1`
+	return r.getOne(ctx, query, id)
+}
+
+// GetByClientOrderID retrieves an order by its client order ID, returning nil if it does not exist
+func (r *TradingOrderRepository) GetByClientOrderID(ctx context.Context, clientOrderID string) (*models.TradingOrder, error) {
+	query := `SELECT ` + tradingOrderColumns + ` FROM trading_orders WHERE client_order_id = $1`
+	return r.getOne(ctx, query, clientOrderID)
+}
+
+// getOne runs a single-order query
+func (r *TradingOrderRepository) getOne(ctx context.Context, query string, arg interface{}) (*models.TradingOrder, error) {
+	order, err := scanTradingOrder(r.db.Pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get trading order: %w", err)
+	}
+	return order, nil
+}
+
+// GetRecent retrieves the most recent orders, newest first, optionally only open ones or one symbol's
+func (r *TradingOrderRepository) GetRecent(ctx context.Context, symbol string, openOnly bool, limit int) ([]models.TradingOrder, error) {
+	query := `
+		SELECT ` + tradingOrderColumns + `
+		FROM trading_orders
+		WHERE ($1 = '' OR symbol = $1)
+		  AND (NOT $2 OR status IN ('PENDING', 'NEW', 'PARTIALLY_FILLED'))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbol, openOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trading orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []models.TradingOrder{}
+	for rows.Next() {
+		order, err := scanTradingOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trading order: %w", err)
+		}
+		orders = append(orders, *order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trading orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// Update stores an order's latest exchange state and amounts
+func (r *TradingOrderRepository) Update(ctx context.Context, order *models.TradingOrder) error {
+	query := `
+		UPDATE trading_orders
+		SET exchange_order_id = $2, quantity = $3, price = $4, stop_price = $5, status = $6,
+			executed_qty = $7, avg_price = $8, last_error = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	order.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query, order.ID, order.ExchangeOrderID, order.Quantity, order.Price, order.StopPrice,
+		order.Status, order.ExecutedQty, order.AvgPrice, order.LastError, order.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update trading order: %w", err)
+	}
+	return nil
+}
+
+// scanTradingOrder scans one row of tradingOrderColumns
+func scanTradingOrder(row pgx.Row) (*models.TradingOrder, error) {
+	var o models.TradingOrder
+	err := row.Scan(&o.ID, &o.ClientOrderID, &o.ExchangeOrderID, &o.Symbol, &o.Side, &o.Type, &o.TimeInForce, &o.Quantity,
+		&o.Price, &o.StopPrice, &o.ReduceOnly, &o.Status, &o.ExecutedQty, &o.AvgPrice, &o.LastError, &o.CreatedBy,
+		&o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UserPreferencesRepository handles database operations for user preferences
+type UserPreferencesRepository struct {
+	db *database.DB
+}
+
+// NewUserPreferencesRepository creates a new user preferences repository
+func NewUserPreferencesRepository(db *database.DB) *UserPreferencesRepository {
+	return &UserPreferencesRepository{db: db}
+}
+
+// Get retrieves the stored preferences for a user, returning nil if none exist
+func (r *UserPreferencesRepository) Get(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	query := `
+		SELECT user_id, preferences, updated_at
+		FROM user_preferences
+		WHERE user_id = $1
+	`
+
+	var prefs models.UserPreferences
+	var raw []byte
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&prefs.UserID, &raw, &prefs.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+	if err := json.Unmarshal(raw, &prefs.Preferences); err != nil {
+		return nil, fmt.Errorf("failed to decode user preferences: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+// Save upserts the preferences for a user
+func (r *UserPreferencesRepository) Save(ctx context.Context, prefs *models.UserPreferences) error {
+	query := `
+		INSERT INTO user_preferences (user_id, preferences, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			preferences = EXCLUDED.preferences,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	raw, err := json.Marshal(prefs.Preferences)
+	if err != nil {
+		return fmt.Errorf("failed to encode user preferences: %w", err)
+	}
+	if prefs.Preferences == nil {
+		raw = []byte("{}")
+	}
+
+	updatedAt := time.Now()
+	if _, err := r.db.Pool.Exec(ctx, query, prefs.UserID, raw, updatedAt); err != nil {
+		return fmt.Errorf("failed to save user preferences: %w", err)
+	}
+	prefs.UpdatedAt = &updatedAt
+
+	return nil
+}
+
+// Delete removes the stored preferences for a user
+func (r *UserPreferencesRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM user_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// userColumns are the columns scanned by scanUser
+const userColumns = `id, email, password_hash, role, permissions, created_at, updated_at, last_login_at`
+
+// UserRepository handles database operations for user accounts
+type UserRepository struct {
+	db *database.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *database.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create inserts a new user, returning false without inserting if the email is already registered
+func (r *UserRepository) Create(ctx context.Context, user *models.User) (bool, error) {
+	query := `
+		INSERT INTO users (id, email, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (email) DO NOTHING
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, user.ID, user.Email, user.PasswordHash, user.Role, now, now).Scan(&user.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	return true, nil
+}
+
+// GetByID retrieves a user by ID, returning nil if it does not exist
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return r.getUser(ctx, query, id)
+}
+
+// GetByEmail retrieves a user by lower-cased email, returning nil if it does not exist
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return r.getUser(ctx, query, email)
+}
+
+// List retrieves users in registration order
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users ORDER BY created_at ASC, id ASC LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+// SetRole changes a user's role, returning false if the user does not exist
+func (r *UserRepository) SetRole(ctx context.Context, id, role string) (bool, error) {
+	query := `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, role)
+	if err != nil {
+		return false, fmt.Errorf("failed to set user role: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetPermissions changes the permissions a user holds as an admin (nil for every one), returning
+// false if the user does not exist
+func (r *UserRepository) SetPermissions(ctx context.Context, id string, permissions []string) (bool, error) {
+	query := `UPDATE users SET permissions = $2, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, permissions)
+	if err != nil {
+		return false, fmt.Errorf("failed to set user permissions: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecordLogin sets a user's last login time
+func (r *UserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE users SET last_login_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Pool.Exec(ctx, query, id, at); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+// getUser runs a single-user query, returning nil if no row matches
+func (r *UserRepository) getUser(ctx context.Context, query string, arg interface{}) (*models.User, error) {
+	user, err := scanUser(r.db.Pool.QueryRow(ctx, query, arg))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// scanUser scans the userColumns of one row
+func scanUser(row pgx.Row) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.Permissions,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UserSubscriptionRepository handles database operations for persisted WebSocket subscriptions
+type UserSubscriptionRepository struct {
+	db *database.DB
+}
+
+// NewUserSubscriptionRepository creates a new user subscription repository
+func NewUserSubscriptionRepository(db *database.DB) *UserSubscriptionRepository {
+	return &UserSubscriptionRepository{db: db}
+}
+
+// Get retrieves the stored subscriptions for a user, returning nil if none exist
+func (r *UserSubscriptionRepository) Get(ctx context.Context, userID string) (*models.UserSubscriptions, error) {
+	query := `
+		SELECT user_id, symbols, enriched_symbols, channels, liquidation_min_notional, updated_at
+		FROM user_subscriptions
+		WHERE user_id = $1
+	`
+
+	var subs models.UserSubscriptions
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
+		&subs.UserID, &subs.Symbols, &subs.EnrichedSymbols, &subs.Channels,
+		&subs.LiquidationMinNotional, &subs.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user subscriptions: %w", err)
+	}
+
+	return &subs, nil
+}
+
+// Save upserts the subscriptions for a user
+func (r *UserSubscriptionRepository) Save(ctx context.Context, subs *models.UserSubscriptions) error {
+	query := `
+		INSERT INTO user_subscriptions (user_id, symbols, enriched_symbols, channels, liquidation_min_notional, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			symbols = EXCLUDED.symbols,
+			enriched_symbols = EXCLUDED.enriched_symbols,
+			channels = EXCLUDED.channels,
+			liquidation_min_notional = EXCLUDED.liquidation_min_notional,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	subs.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query,
+		subs.UserID, emptyIfNil(subs.Symbols), emptyIfNil(subs.EnrichedSymbols), emptyIfNil(subs.Channels),
+		subs.LiquidationMinNotional, subs.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save user subscriptions: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the stored subscriptions for a user
+func (r *UserSubscriptionRepository) Delete(ctx context.Context, userID string) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM user_subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user subscriptions: %w", err)
+	}
+	return nil
+}
+
+// emptyIfNil ensures NOT NULL array columns receive an empty array instead of NULL
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// webhookColumns are the columns scanned by scanWebhook
+const webhookColumns = `id, user_id, channel, url, secret, chat_id, events, symbols, intervals, min_notional, active, created_at, updated_at`
+
+// webhookDeliveryColumns are the columns scanned by scanWebhookDelivery
+const webhookDeliveryColumns = `id, webhook_id, event, symbol, interval, payload, status, attempts,
+	response_status, last_error, next_attempt_at, created_at, delivered_at`
+
+// WebhookRepository handles database operations for user webhooks and their delivery log
+type WebhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create inserts a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (user_id, channel, url, secret, chat_id, events, symbols, intervals, min_notional, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, webhook.UserID, webhook.Channel, webhook.URL, webhook.Secret, webhook.ChatID, webhook.Events,
+		webhook.Symbols, webhook.Intervals, webhook.MinNotional, webhook.Active, now, now).Scan(&webhook.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	return nil
+}
+
+// GetByID retrieves a webhook by ID, returning nil if it does not exist
+func (r *WebhookRepository) GetByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	webhook, err := scanWebhook(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// GetByUser retrieves all webhooks of a user
+func (r *WebhookRepository) GetByUser(ctx context.Context, userID string) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY id ASC`
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+// GetActive retrieves every active webhook, for matching events
+func (r *WebhookRepository) GetActive(ctx context.Context) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE active ORDER BY id ASC`
+	return r.queryWebhooks(ctx, query)
+}
+
+// queryWebhooks runs a webhook query and scans every row
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]models.Webhook, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Update updates a webhook's endpoint, subscriptions, secret, chat and active flag
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $2, secret = $3, chat_id = $4, events = $5, symbols = $6, intervals = $7, min_notional = $8, active = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	webhook.UpdatedAt = time.Now()
+	_, err := r.db.Pool.Exec(ctx, query, webhook.ID, webhook.URL, webhook.Secret, webhook.ChatID, webhook.Events,
+		webhook.Symbols, webhook.Intervals, webhook.MinNotional, webhook.Active, webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a webhook and its delivery log
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// CreateDelivery inserts a pending delivery
+// Its next attempt is set by the caller: the immediate attempt leases it so the retry loop
+// only picks it up if that attempt never records a result
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, symbol, interval, payload, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	now := time.Now()
+	err := r.db.Pool.QueryRow(ctx, query, delivery.WebhookID, delivery.Event, delivery.Symbol, delivery.Interval,
+		delivery.Payload, models.WebhookDeliveryPending, delivery.NextAttemptAt, now).Scan(&delivery.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.CreatedAt = now
+	return nil
+}
+
+// GetDelivery retrieves a delivery by ID, returning nil if it does not exist
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanWebhookDelivery(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// GetDeliveries retrieves the most recent deliveries of a webhook, newest first, optionally
+// only those with a status
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	return r.queryDeliveries(ctx, query, webhookID, status, limit)
+}
+
+// ReplayDeliveries moves failed deliveries of a webhook back to pending with fresh attempts: one
+// delivery, or every failed one when deliveryID is 0. They are leased for an immediate attempt,
+// like new deliveries. Returns the deliveries replayed
+func (r *WebhookRepository) ReplayDeliveries(ctx context.Context, webhookID, deliveryID int64, lease time.Duration) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond', delivered_at = NULL
+		WHERE webhook_id = $1 AND status = 'failed' AND ($2::BIGINT = 0 OR id = $2)
+		RETURNING ` + webhookDeliveryColumns
+	return r.queryDeliveries(ctx, query, webhookID, deliveryID, lease.Milliseconds())
+}
+
+// ClaimDue leases up to limit pending deliveries whose next attempt is due, moving their next
+// attempt past the lease so concurrent claimers (other instances) skip them
+func (r *WebhookRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $1 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	return r.queryDeliveries(ctx, query, lease.Milliseconds(), limit)
+}
+
+// queryDeliveries runs a delivery query and scans every row
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6, delivered_at = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query, delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseStatus,
+		delivery.LastError, delivery.NextAttemptAt, delivery.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// DeleteDeliveriesBefore removes delivered deliveries created before one time and failed ones
+// (dead letters) created before another, returning the rows removed
+func (r *WebhookRepository) DeleteDeliveriesBefore(ctx context.Context, deliveredBefore, failedBefore time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE (status = 'delivered' AND created_at < $1) OR (status = 'failed' AND created_at < $2)
+	`, deliveredBefore, failedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// scanWebhook scans one row of webhookColumns
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var w models.Webhook
+	err := row.Scan(&w.ID, &w.UserID, &w.Channel, &w.URL, &w.Secret, &w.ChatID, &w.Events, &w.Symbols, &w.Intervals, &w.MinNotional, &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// scanWebhookDelivery scans one row of webhookDeliveryColumns
+func scanWebhookDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Symbol, &d.Interval, &d.Payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
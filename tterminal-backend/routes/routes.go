@@ -1,12 +1,28 @@
 package routes
 
 import (
+	"context"
 	"fmt"
+	"time"
 	"tterminal-backend/config"
 	"tterminal-backend/controllers"
+	"tterminal-backend/internal/batchwriter"
 	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/bybit"
+	"tterminal-backend/internal/coinbase"
 	"tterminal-backend/internal/database"
+	"tterminal-backend/internal/deribit"
+	"tterminal-backend/internal/hyperliquid"
+	"tterminal-backend/internal/kraken"
+	"tterminal-backend/internal/marketdata"
 	"tterminal-backend/internal/middleware"
+	"tterminal-backend/internal/multicast"
+	"tterminal-backend/internal/okx"
+	"tterminal-backend/internal/sla"
+	"tterminal-backend/internal/synthetic"
+	"tterminal-backend/internal/traffic"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
 	"tterminal-backend/pkg/cache"
 	"tterminal-backend/repositories"
 	"tterminal-backend/services"
@@ -15,77 +31,846 @@ import (
 )
 
 // SetupRoutes configures all application routes with ultra-fast aggregation endpoints
-func SetupRoutes(e *echo.Echo, db *database.DB, cfg *config.Config) {
+// Persistence streams write through batch writers registered with writers, which the caller
+// closes on shutdown to flush what is pending
+func SetupRoutes(e *echo.Echo, db *database.DB, cfg *config.Config, writers *batchwriter.Registry) {
 	// Initialize Redis cache for ultra-fast performance
 	redisCache := cache.NewRedisCache("localhost:6379", "", 0)
 
 	// Initialize Binance client
 	binanceClient := binance.NewClient(cfg)
 
+	// Initialize ULTRA-FAST WebSocket controller for real-time streaming
+	// Created early so stream-backed services can read live market data
+	// Offline development mode streams generated data from the same seed as the REST client
+	var marketGenerator *synthetic.Generator
+	if cfg.SyntheticData {
+		marketGenerator = synthetic.NewGenerator(int64(cfg.SyntheticSeed))
+	}
+	websocketController := controllers.NewWebSocketController(marketGenerator)
+
 	// Initialize repositories
 	candleRepo := repositories.NewCandleRepository(db)
+	priceCandleRepo := repositories.NewPriceCandleRepository(db)
 	symbolRepo := repositories.NewSymbolRepository(db)
+	userSubscriptionRepo := repositories.NewUserSubscriptionRepository(db)
+	tradeRepo := repositories.NewTradeRepository(db)
+	depthSnapshotRepo := repositories.NewDepthSnapshotRepository(db)
+	portfolioRepo := repositories.NewPortfolioRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	sessionRecordingRepo := repositories.NewSessionRecordingRepository(redisCache, cfg.SessionRecordingRetention)
+	purgeRepo := repositories.NewPurgeRepository(db)
+	marketEventRepo := repositories.NewMarketEventRepository(db)
+
+	// Streamed trades and sampled books are batched per exchange, each a writer of its own
+	tradeWriter := func(exchange string) *websocket.TradeWriter {
+		return batchwriter.New(writers, batchwriter.Config{Name: "trades:" + exchange}, tradeRepo.BulkCreate)
+	}
+	depthSnapshotWriter := func(exchange string) *websocket.DepthSnapshotWriter {
+		return batchwriter.New(writers, batchwriter.Config{Name: "depth:" + exchange}, depthSnapshotRepo.BulkCreate)
+	}
+	basketRepo := repositories.NewBasketRepository(db)
+	compositeRepo := repositories.NewCompositeRepository(db)
+	indicatorRepo := repositories.NewIndicatorRepository(db)
+	symbolMappingRepo := repositories.NewSymbolMappingRepository(db)
+	assetRepo := repositories.NewAssetRepository(db)
+	tradingOrderRepo := repositories.NewTradingOrderRepository(db)
+	accountPositionRepo := repositories.NewAccountPositionRepository(db)
+	spreadRepo := repositories.NewSpreadRepository(db)
+	footprintRepo := repositories.NewFootprintRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	candleDiscrepancyRepo := repositories.NewCandleDiscrepancyRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	aggregateVersionRepo := repositories.NewAggregateVersionRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+	symbolPauseRepo := repositories.NewSymbolPauseRepository(db)
+	userPreferencesRepo := repositories.NewUserPreferencesRepository(db)
+	priceAlertRepo := repositories.NewPriceAlertRepository(db)
+
+	// Persist per-user WebSocket subscriptions so reconnecting terminals can auto-resubscribe
+	websocketController.GetHub().SetSubscriptionStore(userSubscriptionRepo)
+
+	// Support recordings of the stream sent to opted-in clients
+	websocketController.GetHub().SetRecordingStore(sessionRecordingRepo)
+
+	// Keepalive frames and frame size limits for clients behind restrictive proxies
+	websocketController.GetHub().SetTransportConfig(websocket.TransportConfig{
+		KeepaliveInterval: cfg.WSKeepaliveInterval,
+		MaxFrameSize:      cfg.WSMaxFrameBytes,
+	})
+
+	// Per-client subscription, message and bandwidth quotas, announced to clients on connect
+	websocketController.GetHub().SetClientLimits(websocket.ClientLimits{
+		MaxSymbols:            cfg.WSMaxSymbols,
+		MaxChannels:           cfg.WSMaxChannels,
+		MessagesPerSecond:     cfg.WSMessagesPerSecond,
+		MaxBytesPerSecond:     cfg.WSMaxBytesPerSecond,
+		MaxUserBytesPerSecond: cfg.WSMaxUserBytesPerSecond,
+	})
+
+	// Load thresholds past which clients are advised (or made) to downgrade costly subscriptions
+	websocketController.GetHub().SetLoadConfig(websocket.LoadConfig{
+		MaxBytesPerSecond: cfg.WSLoadBytesPerSecond,
+		MaxQueuePct:       cfg.WSLoadQueuePct,
+		MaxClients:        cfg.WSLoadMaxClients,
+		Enforce:           cfg.WSLoadEnforce,
+		ReconnectPct:      cfg.WSLoadReconnectPct,
+	})
+
+	// Caps for watch-only lite connections from embedded mini-charts
+	websocketController.GetHub().SetLiteConfig(websocket.LiteConfig{
+		MaxConnections: cfg.EmbedMaxConnections,
+		MaxPerIP:       cfg.EmbedMaxPerIP,
+	})
+
+	// Tell connected terminals when Binance becomes unreachable and REST data goes stale
+	binanceClient.OnUpstreamChange(func(status binance.UpstreamStatus) {
+		reason := ""
+		if status.Degraded {
+			reason = status.LastError
+		}
+		websocketController.GetHub().SetDegradedMode(status.Degraded, reason, status.Since)
+	})
+
+	// Persist futures trades for trade-based analytics
+	websocketController.GetBinanceStream().SetTradeWriter(tradeWriter(models.ExchangeBinance))
+
+	// Binance klines and book snapshots over the WebSocket API, cutting the REST round trip;
+	// failures fall back to REST. The connection is only dialed once BINANCE_WS_API_ENABLED is on,
+	// at startup or after a reload
+	binanceWSAPI := binance.NewWSAPIClient(binanceClient, cfg.BinanceWSAPIURL)
+	binanceWSAPIEnabled := func() bool {
+		return cfg.Runtime().Settings().BinanceWSAPIEnabled
+	}
+
+	// Sample the futures order book for support/resistance detection: a local book started from
+	// snapshots and kept by the @depth diffs, since the diffs alone are only changed levels
+	if !cfg.SyntheticData {
+		websocketController.GetBinanceStream().SetDepthSnapshotSource(binance.NewBookSnapshots(binanceWSAPI, binanceClient, binanceWSAPIEnabled))
+	}
+	websocketController.GetBinanceStream().SetDepthSnapshotWriter(depthSnapshotWriter(models.ExchangeBinance), cfg.DepthSnapshotInterval)
+
+	// Market data providers (REST klines and stream events) of every enabled exchange; services
+	// resolve symbols through the registry, so each exchange below only registers its provider
+	providers := marketdata.NewRegistry()
+	providers.Register(marketdata.NewProvider(models.ExchangeBinance, binanceClient, websocketController.GetBinanceStream().Events()))
 
 	// Initialize services with Binance client for ultra-fast data fetching
-	candleService := services.NewCandleService(candleRepo, binanceClient)
+	candleService := services.NewCandleService(candleRepo, priceCandleRepo, binanceClient, providers, writers)
+	candleService.SetTradeRepository(tradeRepo)
+	// Contract sizes for the volumeUnit parameter (base, quote or contracts)
+	candleService.SetSymbolRepository(symbolRepo)
+
+	// Bar replay over the WebSocket from stored candles and trades
+	websocketController.GetHub().SetReplaySources(candleService, tradeRepo)
+
+	// Binance klines over the WebSocket API on cache misses
+	if !cfg.SyntheticData {
+		candleService.SetBinanceWSAPI(binanceWSAPI, binanceWSAPIEnabled)
+	}
 	symbolService := services.NewSymbolService(symbolRepo)
 	binanceService := services.NewBinanceService(cfg)
 
 	// Initialize ultra-fast aggregation service
 	aggregationService := services.NewAggregationService(candleService, redisCache)
+	aggregationService.SetMultiRequestBudget(cfg.AggregationMultiTimeout, cfg.AggregationMultiConcurrency)
+	aggregationService.SetFetchWorkers(cfg.TrafficWorkers, cfg.TrafficBatchWorkers)
+	aggregationService.SetVolumeConverter(candleService)
+	aggregationService.SetTradingSessions(cfg.VolumeProfileSessions)
+
+	// Store every closed bar's footprint, built from persisted trades, so footprint history
+	// survives restarts and outlives the raw trades' retention
+	aggregationService.SetFootprintStore(footprintRepo)
+
+	// Version fixed-range aggregates so they can be requested as of an earlier computation
+	aggregateHistoryService := services.NewAggregateHistoryService(aggregationService, candleService, aggregateVersionRepo)
+
+	// Initialize purge service (confirmed background deletes of a symbol's stored data)
+	purgeService := services.NewPurgeService(purgeRepo, candleService, aggregationService)
 
 	// Initialize DATA COLLECTION SERVICE for continuous fresh data
-	dataCollectionService := services.NewDataCollectionService(candleRepo, binanceClient)
+	dataCollectionService := services.NewDataCollectionService(candleRepo, priceCandleRepo, binanceClient, providers, writers)
+
+	// Compare closed bars between the stream and REST polling, recording divergences as a data-quality signal
+	candleConsistencyService := services.NewCandleConsistencyService(candleRepo, candleDiscrepancyRepo, cfg.CandlePriceTolerance, cfg.CandleVolumeTolerance, cfg.CandlePreferredSource)
+	dataCollectionService.SetConsistencyChecker(candleConsistencyService)
+
+	// Per-symbol pauses of collection and streaming, manual or after a data anomaly, shown in
+	// coverage reports; pauses in effect survive restarts
+	if err := dataCollectionService.SetPauseStore(symbolPauseRepo, websocketController.GetHub()); err != nil {
+		panic(fmt.Sprintf("Failed to load symbol pauses: %v", err))
+	}
+	dataCollectionService.SetAnomalyPause(cfg.AnomalyMovePct, cfg.AnomalyPause)
+	candleService.SetCollectionPauses(dataCollectionService)
+
+	// Annotate candle responses with missing bars, stream disconnects, pauses, corrections,
+	// stream/REST discrepancies and source switches so charts can shade them
+	candleService.SetAnnotationSources(candleDiscrepancyRepo, websocketController.GetBinanceStream())
+	aggregationService.SetCandleAnnotator(candleService)
+
+	// Binance COIN-margined futures (dapi) alongside USDⓈ-M, collected and streamed under their
+	// bare contract symbols ("BTCUSD_PERP"); the Binance client routes those symbols to dapi
+	if len(cfg.BinanceCoinMSymbols) > 0 && !cfg.SyntheticData {
+		dataCollectionService.AddExchangeSymbols(models.ExchangeBinance, cfg.BinanceCoinMSymbols)
+		if err := websocketController.GetBinanceStream().EnableCoinMargined(cfg.BinanceCoinMWSURL, cfg.BinanceCoinMSymbols); err != nil {
+			panic(fmt.Sprintf("Failed to start Binance COIN-margined stream: %v", err))
+		}
+	}
+
+	// Backup connection per Binance market, deduplicated so one dropped connection does not
+	// interrupt client feeds
+	if cfg.BinanceRedundantStreams && !cfg.SyntheticData {
+		websocketController.GetBinanceStream().EnableRedundantConnections()
+	}
+
+	// Initialize derivatives dashboard service (REST stats + live stream data)
+	derivativesService := services.NewDerivativesService(binanceClient, websocketController.GetBinanceStream())
+
+	// Initialize market screener (ranks streamed symbols by change, volume spike, OI change and funding)
+	screenerService := services.NewScreenerService(websocketController.GetBinanceStream(), candleService, derivativesService)
+
+	// Funding and open interest overlays on aggregated candles (?include=funding,oi)
+	aggregationService.SetCandleOverlay(derivativesService)
+
+	// Initialize options service (Deribit option chains, implied volatility and DVOL); without a
+	// client the options endpoints report the feature as disabled
+	var deribitClient *deribit.Client
+	if cfg.DeribitEnabled && !cfg.SyntheticData {
+		deribitClient = deribit.NewClient(cfg)
+	}
+	optionsService := services.NewOptionsService(deribitClient, cfg.DeribitCurrencies)
+
+	// Initialize Binance options service (eapi tickers, mark prices and open interest); the shared
+	// client sends /eapi/ requests to the options host
+	var binanceOptionsClient *binance.Client
+	if cfg.BinanceOptionsEnabled && !cfg.SyntheticData {
+		binanceOptionsClient = binanceClient
+	}
+	binanceOptionsService := services.NewBinanceOptionsService(binanceOptionsClient, cfg.BinanceOptionsUnderlyings)
+
+	// Initialize quant analytics service (computed from persisted trades, book snapshots and candles)
+	analyticsService := services.NewAnalyticsService(tradeRepo, depthSnapshotRepo, candleService)
+
+	// Declarative time-series queries evaluated against stored candles
+	queryService := services.NewQueryService(candleService)
+
+	// Strategy backtests replaying stored candles and trades, charged the taker fee by default
+	backtestService := services.NewBacktestService(candleService, tradeRepo, models.FeeSchedule{MakerRate: cfg.MakerFeeRate, TakerRate: cfg.TakerFeeRate})
+
+	// Archived stream captures: stored events packaged as Hub messages for replay
+	streamCaptureService := services.NewStreamCaptureService(tradeRepo, candleRepo, websocketController.GetBinanceStream(), websocket.StreamMessages{})
+
+	// Initialize event index service (largest ranges, volume spikes and gaps, checked on bar close)
+	eventIndexService := services.NewEventIndexService(marketEventRepo, candleRepo)
+
+	// Initialize webhook service (closed candles, event and price alerts and liquidations POSTed to
+	// user endpoints). Webhooks redistribute data, so compliance mode gates them like exports and
+	// watermarks every delivery; until started, the service has no active webhooks and ignores bar
+	// closes and events
+	webhookService := services.NewWebhookService(webhookRepo, cfg.WebhooksAllowPrivateURLs)
+	if cfg.ComplianceMode {
+		webhookService.SetWatermark(cfg.DeploymentID)
+	}
+	eventIndexService.SetEventHandler(webhookService.HandleMarketEvent)
+
+	// Initialize price alert service (user price-cross alerts evaluated on every Binance last price
+	// change, pushed to the user's WebSocket connections when they fire)
+	alertService := services.NewAlertService(priceAlertRepo, websocketController.GetBinanceStream(), websocketController.GetHub())
+	websocketController.GetBinanceStream().Events().OnPrice(alertService.HandlePrice)
+	alertService.SetTriggerHandler(webhookService.HandleAlertTriggered)
+	alertService.SetWebhookChecker(webhookService.CheckWebhooks)
+	alertService.SetCandleService(candleService)
+
+	// Initialize basket service (combined notional delta of symbol groups, updated on bar close)
+	basketService := services.NewBasketService(basketRepo, candleService)
+
+	// Initialize composite service (user-defined spreads and ratios computed from constituent bars),
+	// serving "COMPOSITE:" symbols to the candle endpoints like an exchange
+	compositeService := services.NewCompositeService(compositeRepo, candleService)
+	providers.Register(marketdata.NewProvider(models.ExchangeComposite, compositeService, nil))
+
+	// Initialize custom indicator service (user-registered expressions evaluated against candles)
+	indicatorService := services.NewIndicatorService(indicatorRepo, candleService)
+
+	// Initialize portfolio service (sub-accounts filled against live stream prices)
+	feeSchedule := models.FeeSchedule{MakerRate: cfg.MakerFeeRate, TakerRate: cfg.TakerFeeRate}
+	portfolioService := services.NewPortfolioService(portfolioRepo, binanceClient, websocketController.GetBinanceStream(), feeSchedule)
+
+	// Validate orders against exchange filters stored from exchangeInfo before filling them
+	orderFilterService := services.NewOrderFilterService(symbolRepo, websocketController.GetBinanceStream())
+	portfolioService.SetOrderFilters(orderFilterService)
+
+	// Live futures order routing through the signed Binance endpoints; new orders are only accepted
+	// while TRADING_ENABLED is on, which a reload can switch off as a kill switch
+	var tradingService *services.TradingService
+	if !cfg.SyntheticData && binanceClient.HasCredentials() {
+		tradingService = services.NewTradingService(tradingOrderRepo, binanceClient, cfg.Runtime())
+		tradingService.SetOrderFilters(orderFilterService)
+	}
+
+	// Account positions and PnL kept from the user data stream, whether or not trading is enabled
+	var positionService *services.PositionService
+	if !cfg.SyntheticData && binanceClient.HasCredentials() {
+		positionService = services.NewPositionService(accountPositionRepo, binanceClient, websocketController.GetBinanceStream(), cfg.BinanceTradingWSURL)
+	}
+
+	// Initialize daily report service (watchlist recaps stored as JSON, optionally emailed)
+	reportService := services.NewReportService(reportRepo, userSubscriptionRepo, binanceClient, websocketController.GetBinanceStream(), cfg)
+
+	// Initialize preference service (per-user terminal preferences validated against a schema)
+	preferenceService := services.NewPreferenceService(userPreferencesRepo)
+
+	// Exchange-aligned bar closes: clock offset from the server time, REST confirmation fallback,
+	// and pipelines that react to final bars instead of polling
+	barCloses := websocketController.GetBinanceStream().BarCloses()
+	barCloses.SetServerClock(binanceClient.GetServerTime)
+	barCloses.SetFallbackFetcher(func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error) {
+		duration, _ := models.IntervalDuration(interval)
+		candles, err := binanceClient.GetKlinesWithTimeRange(ctx, symbol, interval, openTime, openTime.Add(duration-time.Millisecond))
+		if err != nil {
+			return nil, err
+		}
+		for i := range candles {
+			if candles[i].OpenTime.Equal(openTime) {
+				return &candles[i], nil
+			}
+		}
+		return nil, nil
+	})
+	barCloses.OnBarClose(dataCollectionService.HandleBarClose)
+	barCloses.OnBarClose(aggregationService.HandleBarClose)
+	barCloses.OnBarClose(eventIndexService.HandleBarClose)
+	barCloses.OnBarClose(webhookService.HandleBarClose)
+	barCloses.OnBarClose(basketService.HandleBarClose)
+	barCloses.OnBarClose(compositeService.HandleBarClose)
+
+	// Bybit linear perpetuals alongside Binance, stored and streamed under "BYBIT:" symbols
+	// (synthetic mode replaces every live exchange)
+	if cfg.BybitEnabled && !cfg.SyntheticData {
+		bybitClient := bybit.NewClient(cfg)
+
+		bybitStream := websocket.NewBybitStream(websocketController.GetHub(), cfg.BybitWSURL, cfg.BybitSymbols)
+		providers.Register(marketdata.NewProvider(models.ExchangeBybit, bybitClient, bybitStream.Events()))
+		dataCollectionService.AddExchangeSymbols(models.ExchangeBybit, cfg.BybitSymbols)
+		bybitStream.SetTradeWriter(tradeWriter(models.ExchangeBybit))
+		bybitStream.SetDepthSnapshotWriter(depthSnapshotWriter(models.ExchangeBybit), cfg.DepthSnapshotInterval)
+
+		bybitBarCloses := bybitStream.BarCloses()
+		bybitBarCloses.SetServerClock(bybitClient.GetServerTime)
+		bybitBarCloses.SetFallbackFetcher(func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error) {
+			candles, err := bybitClient.GetKlinesEndingAt(ctx, symbol, interval, openTime, 1)
+			if err != nil {
+				return nil, err
+			}
+			for i := range candles {
+				if candles[i].OpenTime.Equal(openTime) {
+					return &candles[i], nil
+				}
+			}
+			return nil, nil
+		})
+		bybitBarCloses.OnBarClose(dataCollectionService.HandleBarClose)
+		bybitBarCloses.OnBarClose(aggregationService.HandleBarClose)
+		bybitBarCloses.OnBarClose(eventIndexService.HandleBarClose)
+		bybitBarCloses.OnBarClose(webhookService.HandleBarClose)
+		bybitBarCloses.OnBarClose(compositeService.HandleBarClose)
+
+		if err := bybitStream.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start Bybit stream: %v", err))
+		}
+		websocketController.SetExchangeStream(models.ExchangeBybit, bybitStream)
+	}
+
+	// OKX perpetual swaps alongside Binance, stored and streamed under "OKX:" symbols
+	if cfg.OKXEnabled && !cfg.SyntheticData {
+		okxClient := okx.NewClient(cfg)
+
+		// Trade, book and liquidation sizes are in contracts until converted with contract values
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		contractValues, err := okxClient.GetContractValues(ctx, cfg.OKXSymbols)
+		cancel()
+		if err != nil {
+			panic(fmt.Sprintf("Failed to load OKX contract values: %v", err))
+		}
+
+		okxStream := websocket.NewOKXStream(websocketController.GetHub(), cfg.OKXWSURL, cfg.OKXWSBusinessURL, cfg.OKXSymbols)
+		providers.Register(marketdata.NewProvider(models.ExchangeOKX, okxClient, okxStream.Events()))
+		dataCollectionService.AddExchangeSymbols(models.ExchangeOKX, cfg.OKXSymbols)
+		okxStream.SetContractValues(contractValues)
+		okxStream.SetTradeWriter(tradeWriter(models.ExchangeOKX))
+		okxStream.SetDepthSnapshotWriter(depthSnapshotWriter(models.ExchangeOKX), cfg.DepthSnapshotInterval)
+
+		okxBarCloses := okxStream.BarCloses()
+		okxBarCloses.SetServerClock(okxClient.GetServerTime)
+		okxBarCloses.SetFallbackFetcher(func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error) {
+			candles, err := okxClient.GetKlinesEndingAt(ctx, symbol, interval, openTime, 1)
+			if err != nil {
+				return nil, err
+			}
+			for i := range candles {
+				if candles[i].OpenTime.Equal(openTime) {
+					return &candles[i], nil
+				}
+			}
+			return nil, nil
+		})
+		okxBarCloses.OnBarClose(dataCollectionService.HandleBarClose)
+		okxBarCloses.OnBarClose(aggregationService.HandleBarClose)
+		okxBarCloses.OnBarClose(eventIndexService.HandleBarClose)
+		okxBarCloses.OnBarClose(webhookService.HandleBarClose)
+		okxBarCloses.OnBarClose(compositeService.HandleBarClose)
+
+		if err := okxStream.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start OKX stream: %v", err))
+		}
+		websocketController.SetExchangeStream(models.ExchangeOKX, okxStream)
+	}
+
+	// Coinbase spot pairs alongside Binance perpetuals, stored and streamed under "COINBASE:" symbols
+	if cfg.CoinbaseEnabled && !cfg.SyntheticData {
+		coinbaseClient := coinbase.NewClient(cfg)
+
+		coinbaseStream := websocket.NewCoinbaseStream(websocketController.GetHub(), cfg.CoinbaseWSURL, cfg.CoinbaseSymbols)
+		providers.Register(marketdata.NewProvider(models.ExchangeCoinbase, coinbaseClient, coinbaseStream.Events()))
+		dataCollectionService.AddExchangeSymbols(models.ExchangeCoinbase, cfg.CoinbaseSymbols)
+		coinbaseStream.SetTradeWriter(tradeWriter(models.ExchangeCoinbase))
+		coinbaseStream.SetDepthSnapshotWriter(depthSnapshotWriter(models.ExchangeCoinbase), cfg.DepthSnapshotInterval)
+
+		// Every Coinbase bar close is confirmed over REST (the stream has no closed candles)
+		coinbaseBarCloses := coinbaseStream.BarCloses()
+		coinbaseBarCloses.SetServerClock(coinbaseClient.GetServerTime)
+		coinbaseBarCloses.SetFallbackFetcher(func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error) {
+			candles, err := coinbaseClient.GetKlinesEndingAt(ctx, symbol, interval, openTime, 1)
+			if err != nil {
+				return nil, err
+			}
+			for i := range candles {
+				if candles[i].OpenTime.Equal(openTime) {
+					return &candles[i], nil
+				}
+			}
+			return nil, nil
+		})
+		coinbaseBarCloses.OnBarClose(dataCollectionService.HandleBarClose)
+		coinbaseBarCloses.OnBarClose(aggregationService.HandleBarClose)
+		coinbaseBarCloses.OnBarClose(eventIndexService.HandleBarClose)
+		coinbaseBarCloses.OnBarClose(webhookService.HandleBarClose)
+		coinbaseBarCloses.OnBarClose(compositeService.HandleBarClose)
+
+		if err := coinbaseStream.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start Coinbase stream: %v", err))
+		}
+		websocketController.SetExchangeStream(models.ExchangeCoinbase, coinbaseStream)
+	}
+
+	// Kraken Futures perpetuals alongside Binance, stored and streamed under "KRAKEN:" symbols
+	if cfg.KrakenEnabled && !cfg.SyntheticData {
+		krakenClient := kraken.NewClient(cfg)
+
+		krakenStream := websocket.NewKrakenStream(websocketController.GetHub(), cfg.KrakenWSURL, cfg.KrakenSymbols)
+		providers.Register(marketdata.NewProvider(models.ExchangeKraken, krakenClient, krakenStream.Events()))
+		dataCollectionService.AddExchangeSymbols(models.ExchangeKraken, cfg.KrakenSymbols)
+		krakenStream.SetTradeWriter(tradeWriter(models.ExchangeKraken))
+
+		// Every Kraken bar close is confirmed over REST (the stream has no candles)
+		krakenBarCloses := krakenStream.BarCloses()
+		krakenBarCloses.SetServerClock(krakenClient.GetServerTime)
+		krakenBarCloses.SetFallbackFetcher(func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error) {
+			candles, err := krakenClient.GetKlinesEndingAt(ctx, symbol, interval, openTime, 1)
+			if err != nil {
+				return nil, err
+			}
+			for i := range candles {
+				if candles[i].OpenTime.Equal(openTime) {
+					return &candles[i], nil
+				}
+			}
+			return nil, nil
+		})
+		krakenBarCloses.OnBarClose(dataCollectionService.HandleBarClose)
+		krakenBarCloses.OnBarClose(aggregationService.HandleBarClose)
+		krakenBarCloses.OnBarClose(eventIndexService.HandleBarClose)
+		krakenBarCloses.OnBarClose(webhookService.HandleBarClose)
+		krakenBarCloses.OnBarClose(compositeService.HandleBarClose)
+
+		if err := krakenStream.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start Kraken stream: %v", err))
+		}
+		websocketController.SetExchangeStream(models.ExchangeKraken, krakenStream)
+	}
+
+	// Hyperliquid perpetuals alongside Binance, stored and streamed under "HYPERLIQUID:" symbols
+	if cfg.HyperliquidEnabled && !cfg.SyntheticData {
+		hyperliquidClient := hyperliquid.NewClient(cfg)
+
+		// The stream subscribes by Hyperliquid's coin names, some of which are mixed case ("kPEPE")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		coinNames, err := hyperliquidClient.GetCoinNames(ctx, cfg.HyperliquidSymbols)
+		cancel()
+		if err != nil {
+			panic(fmt.Sprintf("Failed to load Hyperliquid coins: %v", err))
+		}
+		coins := make([]string, 0, len(coinNames))
+		for _, symbol := range cfg.HyperliquidSymbols {
+			coins = append(coins, coinNames[symbol])
+		}
+
+		hyperliquidStream := websocket.NewHyperliquidStream(websocketController.GetHub(), cfg.HyperliquidWSURL, coins)
+		providers.Register(marketdata.NewProvider(models.ExchangeHyperliquid, hyperliquidClient, hyperliquidStream.Events()))
+		dataCollectionService.AddExchangeSymbols(models.ExchangeHyperliquid, cfg.HyperliquidSymbols)
+		hyperliquidStream.SetTradeWriter(tradeWriter(models.ExchangeHyperliquid))
+
+		// Hyperliquid has no server time endpoint, so bar closes follow the local clock; bars the
+		// stream did not roll over in time are confirmed over REST
+		hyperliquidBarCloses := hyperliquidStream.BarCloses()
+		hyperliquidBarCloses.SetFallbackFetcher(func(ctx context.Context, symbol, interval string, openTime time.Time) (*models.Candle, error) {
+			candles, err := hyperliquidClient.GetKlinesEndingAt(ctx, symbol, interval, openTime, 1)
+			if err != nil {
+				return nil, err
+			}
+			for i := range candles {
+				if candles[i].OpenTime.Equal(openTime) {
+					return &candles[i], nil
+				}
+			}
+			return nil, nil
+		})
+		hyperliquidBarCloses.OnBarClose(dataCollectionService.HandleBarClose)
+		hyperliquidBarCloses.OnBarClose(aggregationService.HandleBarClose)
+		hyperliquidBarCloses.OnBarClose(eventIndexService.HandleBarClose)
+		hyperliquidBarCloses.OnBarClose(webhookService.HandleBarClose)
+		hyperliquidBarCloses.OnBarClose(compositeService.HandleBarClose)
+
+		if err := hyperliquidStream.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start Hyperliquid stream: %v", err))
+		}
+		websocketController.SetExchangeStream(models.ExchangeHyperliquid, hyperliquidStream)
+	}
+
+	// Composite symbols computed every second from the prices of the streams above
+	compositeStream := websocket.NewCompositeStream(websocketController.GetHub(), websocketController.LastPrice)
+	compositeService.SetStream(compositeStream)
+	if err := compositeService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start composite service: %v", err))
+	}
+	websocketController.SetExchangeStream(models.ExchangeComposite, compositeStream)
+	compositeStream.Start()
+
+	// Canonical symbols ("BTC-PERP") resolved to each exchange's symbol for requests and subscriptions
+	symbolMappingService := services.NewSymbolMappingService(symbolMappingRepo)
+	if err := symbolMappingService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to load symbol mappings: %v", err))
+	}
+	websocketController.GetHub().SetSymbolResolver(symbolMappingService)
+	aggregationService.SetIndexConstituents(symbolMappingService)
+
+	// Assets: the spot, perpetual, delivery and index instruments of each underlying
+	assetService := services.NewAssetService(assetRepo, candleService, websocketController.LastPrice)
+	if err := assetService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to load assets: %v", err))
+	}
+
+	// Public status page built from the database, upstream, stream and collection monitors
+	statusService := services.NewStatusService(db, binanceClient, websocketController.GetBinanceStream(), dataCollectionService)
+
+	// Draining for rolling restarts: refuse new connections, hint clients to peers, finish jobs
+	drainService := services.NewDrainService(websocketController.GetHub(), purgeService, dataCollectionService, cfg.DrainPeers, cfg.DrainGrace)
+
+	// Instance identity in connect acks and health checks; while saturated, clients are offered
+	// the healthy peers that are not
+	websocketController.GetHub().SetRouting(cfg.InstanceID, cfg.InstanceRegion, drainService.Peers)
 
 	// Start the data collection service to ensure fresh data
 	if err := dataCollectionService.Start(); err != nil {
 		panic(fmt.Sprintf("Failed to start data collection service: %v", err))
 	}
 
+	// Sample how far each collected series' stored candles lag behind its freshness target
+	freshnessService := services.NewFreshnessService(dataCollectionService, cfg.FreshnessTargets, cfg.FreshnessWindow)
+	if err := freshnessService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start freshness service: %v", err))
+	}
+
+	// Start funding settlement for open portfolio positions
+	if err := portfolioService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start portfolio service: %v", err))
+	}
+
+	// Start webhook delivery and retries, unless compliance mode disallows exports. Liquidations
+	// of every enabled exchange are offered to liquidation webhooks
+	if !cfg.ComplianceMode || cfg.ComplianceAllowExports {
+		if err := webhookService.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start webhook service: %v", err))
+		}
+		for _, exchange := range providers.Exchanges() {
+			providers.Get(exchange).StreamLiquidations(webhookService.HandleLiquidation)
+		}
+	}
+
+	// Publish Binance last prices and the trades of every enabled exchange as binary UDP ticks for
+	// co-located consumers. Like webhooks, the feed redistributes data and is gated in compliance mode
+	if cfg.TickMulticastAddr != "" && (!cfg.ComplianceMode || cfg.ComplianceAllowExports) {
+		tickPublisher, err := multicast.NewPublisher(cfg.TickMulticastAddr, cfg.TickMulticastInterface, cfg.TickMulticastTTL, cfg.TickMulticastSymbols)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to create tick multicast publisher: %v", err))
+		}
+		if err := tickPublisher.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start tick multicast publisher: %v", err))
+		}
+		websocketController.GetBinanceStream().Events().OnPrice(tickPublisher.PublishPrice)
+		for _, exchange := range providers.Exchanges() {
+			providers.Get(exchange).StreamTrades(tickPublisher.PublishTrade)
+		}
+	}
+
+	// Start evaluating price alerts
+	if err := alertService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start alert service: %v", err))
+	}
+
+	// Start the daily report schedule
+	if err := reportService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start report service: %v", err))
+	}
+
+	// Start position tracking from the account's user data stream
+	if positionService != nil {
+		if err := positionService.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start position service: %v", err))
+		}
+		// Close the listen key on shutdown rather than leaving it to expire
+		e.Server.RegisterOnShutdown(positionService.Stop)
+	}
+
+	// Reload the runtime settings (log level, rate limit, feature toggles) on SIGHUP
+	configReloadService := services.NewConfigReloadService(cfg, repositories.NewConfigAuditRepository(db))
+	if err := configReloadService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start config reload service: %v", err))
+	}
+
+	// Sync symbol metadata from exchangeInfo, pushing changes to "symbols:meta" subscribers
+	symbolSyncService := services.NewSymbolSyncService(binanceService, binanceClient, symbolRepo, websocketController.GetHub(), cfg.SymbolSyncInterval)
+	if err := symbolSyncService.Start(); err != nil {
+		panic(fmt.Sprintf("Failed to start symbol sync service: %v", err))
+	}
+
+	// Sample configured spot-perp and cross-exchange spreads from live prices, pushing them to "spreads" subscribers
+	var spreadService *services.SpreadService
+	if len(cfg.SpreadPairs) > 0 {
+		spreadService = services.NewSpreadService(spreadRepo, websocketController.GetHub(), websocketController.LastPrice, cfg.SpreadPairs, cfg.SpreadInterval, cfg.SpreadArbitrageBps, writers)
+		if err := spreadService.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start spread service: %v", err))
+		}
+	}
+
+	// Infer hidden and iceberg liquidity of configured symbols from their trades and book, pushing
+	// estimates to "hidden_liquidity" subscribers
+	var hiddenLiquidityService *services.HiddenLiquidityService
+	if len(cfg.HiddenLiquiditySymbols) > 0 {
+		hiddenLiquidityService = services.NewHiddenLiquidityService(providers, websocketController.GetHub(), cfg.HiddenLiquiditySymbols, cfg.HiddenLiquidityWindow)
+		if err := hiddenLiquidityService.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to start hidden liquidity service: %v", err))
+		}
+	}
+
+	// User accounts issuing JWT access tokens; without JWT_SECRET, user routes trust X-User-ID
+	var authService *services.AuthService
+	if cfg.JWTSecret != "" {
+		authService = services.NewAuthService(userRepo, cfg.JWTSecret, cfg.JWTTTL)
+	}
+
 	// Initialize controllers
 	candleController := controllers.NewCandleController(candleService, binanceService)
+	candleController.SetConsistencyService(candleConsistencyService)
 	symbolController := controllers.NewSymbolController(symbolService)
-	healthController := controllers.NewHealthController(db)
+	healthController := controllers.NewHealthController(db, binanceClient)
+	healthController.SetDrainService(drainService)
+	healthController.SetDataCollectionService(dataCollectionService)
+	healthController.SetHub(websocketController.GetHub())
+	statusController := controllers.NewStatusController(statusService)
 	aggregationController := controllers.NewAggregationController(aggregationService)
+	aggregationController.SetHistoryService(aggregateHistoryService)
+	aggregationController.SetVWAPService(services.NewVWAPService(candleService, tradeRepo))
 	dataCollectionController := controllers.NewDataCollectionController(dataCollectionService)
-
-	// Initialize ULTRA-FAST WebSocket controller for real-time streaming
-	websocketController := controllers.NewWebSocketController()
+	dataCollectionController.SetConsistencyService(candleConsistencyService)
+	derivativesController := controllers.NewDerivativesController(derivativesService)
+	screenerController := controllers.NewScreenerController(screenerService)
+	optionsController := controllers.NewOptionsController(optionsService)
+	binanceOptionsController := controllers.NewBinanceOptionsController(binanceOptionsService)
+	analyticsController := controllers.NewAnalyticsController(analyticsService)
+	analyticsController.SetSpreadService(spreadService)
+	analyticsController.SetHiddenLiquidityService(hiddenLiquidityService)
+	queryController := controllers.NewQueryController(queryService)
+	backtestController := controllers.NewBacktestController(backtestService)
+	streamCaptureController := controllers.NewStreamCaptureController(streamCaptureService)
+	marketEventController := controllers.NewMarketEventController(eventIndexService)
+	basketController := controllers.NewBasketController(basketService)
+	compositeController := controllers.NewCompositeController(compositeService)
+	indicatorController := controllers.NewIndicatorController(indicatorService)
+	symbolMappingController := controllers.NewSymbolMappingController(symbolMappingService)
+	assetController := controllers.NewAssetController(assetService)
+	portfolioController := controllers.NewPortfolioController(portfolioService, orderFilterService)
+	tradingController := controllers.NewTradingController(tradingService)
+	positionController := controllers.NewPositionController(positionService)
+	reportController := controllers.NewReportController(reportService)
+	webhookController := controllers.NewWebhookController(webhookService)
+	preferenceController := controllers.NewPreferenceController(preferenceService)
+	alertController := controllers.NewAlertController(alertService)
+	adminController := controllers.NewAdminController(cfg)
+	adminController.SetConfigReloadService(configReloadService)
+	auditLogService := services.NewAuditLogService(auditLogRepo)
+	adminController.SetAuditLogService(auditLogService)
+	purgeController := controllers.NewPurgeController(purgeService)
+	drainController := controllers.NewDrainController(drainService)
+	authController := controllers.NewAuthController(authService)
 
 	// Setup middleware
+	e.Use(middleware.RequestID())
 	e.Use(middleware.CORS(cfg))
+
+	// Rolling latency per route and WebSocket delivery lag per message type (GET /debug/sla)
+	httpLatency := sla.NewTracker(cfg.SLAWindow)
+	deliveryLag := sla.NewTracker(cfg.SLAWindow)
+	websocketController.GetHub().SetDeliveryLagRecorder(deliveryLag)
+	debugController := controllers.NewDebugController(httpLatency, deliveryLag, cfg.SLATarget, cfg.InstanceID)
+	debugController.SetFreshnessService(freshnessService)
+	debugController.SetWriterRegistry(writers)
+	e.Use(middleware.Latency(cfg, httpLatency))
+
+	// Verified access tokens replace the X-User-ID header and user_id parameter before any handler
+	// or middleware reads the caller's identity
+	e.Use(middleware.Authenticate(cfg))
+
+	// Traffic classes: batch requests yield to interactive ones in the rate limit, the database
+	// pool (only TRAFFIC_DB_BATCH_REQUESTS run at once) and the aggregation workers
+	dbBatchGate := traffic.NewGate(cfg.TrafficDBBatchRequests, cfg.TrafficDBBatchRequests)
+	adminController.SetTrafficStats(dbBatchGate, aggregationService)
+	e.Use(middleware.TrafficClass(cfg))
 	e.Use(middleware.RateLimit(cfg))
+	e.Use(middleware.BatchConcurrency(dbBatchGate))
 
 	// API v1 routes
 	v1 := e.Group("/api/v1")
 
+	// Canonical symbols in a "symbol" path or query parameter are resolved before any handler runs
+	v1.Use(middleware.CanonicalSymbol(symbolMappingService))
+
+	// Every state-changing request is recorded in the audit log with its actor and changes
+	v1.Use(middleware.AuditLog(cfg, auditLogService))
+
+	// Redistribution compliance: identity for market data, gated and watermarked raw exports
+	requireIdentity := middleware.RequireIdentity(cfg)
+	dataExport := middleware.DataExport(cfg)
+
+	// User-scoped routes need an access token once JWT_SECRET is set
+	requireUser := middleware.RequireUser(cfg)
+
+	// Operational routes (data collection, symbols, streams) need a role: admin to change them,
+	// any account to monitor them; the X-Admin-Token counts as admin
+	requireAdminRole := middleware.RequireRole(cfg, models.RoleAdmin)
+	requireReadonlyRole := middleware.RequireRole(cfg, models.RoleReadonly)
+
+	// Data collection control also needs the admin's permission for the kind of action
+	requireCollectionTrigger := middleware.RequirePermission(cfg, models.PermissionCollectionTrigger)
+	requireCollectionConfigure := middleware.RequirePermission(cfg, models.PermissionCollectionConfigure)
+	requireCollectionStop := middleware.RequirePermission(cfg, models.PermissionCollectionStop)
+
 	// Health check
 	v1.GET("/health", healthController.HealthCheck)
+	v1.GET("/status", statusController.GetStatus)
+
+	// Measured latency percentiles of this instance, to check against the promised response times
+	v1.GET("/debug/sla", debugController.GetSLA, requireReadonlyRole)
+	v1.GET("/debug/writers", debugController.GetWriters, requireReadonlyRole)
+
+	// Data freshness per symbol/interval against its target, with rolling compliance
+	v1.GET("/sla/freshness", debugController.GetFreshness, requireReadonlyRole)
+
+	// User accounts - register or log in for a bearer access token, limited per address
+	authGroup := v1.Group("/auth", middleware.IPRateLimit(cfg.AuthAttemptsPerMinute, cfg.AuthAttemptsPerMinute))
+	authGroup.POST("/register", authController.Register)
+	authGroup.POST("/login", authController.Login)
+	v1.GET("/auth/me", authController.GetMe, requireUser)
+
+	// Deployment inspection (X-Admin-Token)
+	admin := v1.Group("/admin", middleware.RequireAdmin(cfg))
+	admin.GET("/config", adminController.GetConfig)
+	admin.POST("/config/reload", adminController.ReloadConfig)
+	admin.GET("/config/audit", adminController.GetConfigAudit)
+	admin.GET("/audit-log", adminController.GetAuditLog)
+	admin.GET("/traffic", adminController.GetTraffic)
+
+	// User accounts, their roles and permissions
+	admin.GET("/users", authController.GetUsers)
+	admin.PUT("/users/:id/role", authController.SetUserRole)
+	admin.PUT("/users/:id/permissions", authController.SetUserPermissions)
+
+	// Drain for a rolling restart; poll the status until ready_to_terminate
+	admin.POST("/drain", drainController.StartDrain)
+	admin.GET("/drain", drainController.GetDrainStatus)
+
+	// Support session recordings of opted-in WebSocket clients
+	admin.POST("/recordings", websocketController.StartRecording)
+	admin.GET("/recordings/:id", websocketController.GetRecording)
+	admin.POST("/recordings/:id/stop", websocketController.StopRecording)
+
+	// Purge a symbol's stored data: preview for a confirmation token, then purge in the background
+	admin.POST("/purge/preview", purgeController.PreviewPurge)
+	admin.POST("/purge", purgeController.StartPurge)
+	admin.GET("/purge", purgeController.GetPurgeJobs)
+	admin.GET("/purge/:id", purgeController.GetPurgeJob)
+
+	// Map canonical symbols to exchange symbols
+	admin.PUT("/symbol-mappings/:canonical/:exchange", symbolMappingController.SetMapping)
+	admin.DELETE("/symbol-mappings/:canonical/:exchange", symbolMappingController.DeleteMapping)
+
+	// Manage assets and their instruments
+	admin.PUT("/assets/:code", assetController.SetAsset)
+	admin.DELETE("/assets/:code", assetController.DeleteAsset)
+	admin.PUT("/assets/:code/instruments", assetController.SetInstrument)
+	admin.DELETE("/assets/:code/instruments/:id", assetController.DeleteInstrument)
+
+	// Supported candle intervals for frontend interval pickers
+	v1.GET("/intervals", candleController.GetIntervals)
 
 	// Symbol routes
 	symbols := v1.Group("/symbols")
 	symbols.GET("", symbolController.GetSymbols)
 	symbols.GET("/:symbol", symbolController.GetSymbol)
-	symbols.POST("", symbolController.CreateSymbol)
-	symbols.PUT("/:symbol", symbolController.UpdateSymbol)
-	symbols.DELETE("/:symbol", symbolController.DeleteSymbol)
+	symbols.POST("", symbolController.CreateSymbol, requireAdminRole)
+	symbols.PUT("/:symbol", symbolController.UpdateSymbol, requireAdminRole)
+	symbols.DELETE("/:symbol", symbolController.DeleteSymbol, requireAdminRole)
+
+	// Canonical symbol routes - one symbol ("BTC-PERP") resolved to each exchange's symbol
+	symbolMappings := v1.Group("/symbol-mappings")
+	symbolMappings.GET("", symbolMappingController.GetCanonicalSymbols)
+	symbolMappings.GET("/:canonical", symbolMappingController.GetCanonicalSymbol)
+
+	// Asset routes - every instrument of an underlying and metrics aggregated across them
+	assets := v1.Group("/assets")
+	assets.GET("", assetController.GetAssets)
+	assets.GET("/:code", assetController.GetAsset)
+	assets.GET("/:code/metrics", assetController.GetMetrics, requireIdentity)
 
 	// Ultra-fast candle routes optimized for rendering performance
-	candles := v1.Group("/candles")
-	candles.GET("/:symbol", candleController.GetCandles)               // Optimized response format
-	candles.GET("/:symbol/raw", candleController.GetCandlesRaw)        // Pre-serialized JSON for maximum speed
-	candles.GET("/:symbol/metrics", candleController.GetCandleMetrics) // Performance monitoring
-	candles.POST("/fetch", candleController.FetchAndStoreCandles)      // Fetch from Binance
-	candles.GET("/:symbol/latest", candleController.GetLatestCandle)   // Latest candle
-	candles.GET("/:symbol/range", candleController.GetCandleRange)     // Time range queries
+	candles := v1.Group("/candles", requireIdentity)
+	candles.GET("/:symbol", candleController.GetCandles)                                             // Optimized response format
+	candles.GET("/:symbol/raw", candleController.GetCandlesRaw, dataExport)                          // Pre-serialized JSON for maximum speed
+	candles.GET("/:symbol/metrics", candleController.GetCandleMetrics)                               // Performance monitoring
+	candles.POST("/fetch", candleController.FetchAndStoreCandles, requireAdminRole)                  // Fetch from Binance
+	candles.GET("/:symbol/latest", candleController.GetLatestCandle)                                 // Latest candle
+	candles.GET("/:symbol/range", candleController.GetCandleRange, dataExport)                       // Time range queries
+	candles.GET("/:symbol/coverage", candleController.GetCandleCoverage)                             // Stored coverage and provenance
+	candles.GET("/:symbol/consistency", candleController.GetCandleConsistency)                       // Stream vs REST closed-bar divergence
+	candles.GET("/:symbol/:interval/:openTime/trades", candleController.GetCandleTrades, dataExport) // Trades composing one candle
 
 	// ULTRA-FAST AGGREGATION ROUTES - THE FASTEST DATA ENDPOINTS
-	agg := v1.Group("/aggregation")
+	agg := v1.Group("/aggregation", requireIdentity)
 
 	// Service monitoring and debugging
 	agg.GET("/stats", aggregationController.GetServiceStats)
 
-	// Optimized candle data (70% smaller payload, <50ms response)
+	// Optimized candle data (70% smaller payload, <50ms response); source=composite for a cross-exchange index
 	agg.GET("/candles/:symbol/:interval", aggregationController.GetOptimizedCandles)
 
 	// Advanced trading data (volume profile, footprints, liquidations, heatmaps)
@@ -94,25 +879,182 @@ func SetupRoutes(e *echo.Echo, db *database.DB, cfg *config.Config) {
 	agg.GET("/liquidations/:symbol", aggregationController.GetLiquidations)
 	agg.GET("/heatmap/:symbol", aggregationController.GetHeatmap)
 
+	// Cumulative volume delta, session/anchored VWAP and the stored versions of fixed-range aggregates ("as of" queries)
+	agg.GET("/cvd/:symbol/:interval", aggregationController.GetCVD)
+	agg.GET("/vwap/:symbol", aggregationController.GetVWAP)
+	agg.GET("/versions/:kind/:symbol", aggregationController.GetAggregateVersions)
+
 	// Multi-data endpoint for frontend efficiency (get everything in one call)
 	agg.POST("/multi", aggregationController.GetAggregatedMultiData)
 
+	// Derivatives dashboard routes - funding, OI, long/short, liquidations and basis in one call
+	derivatives := v1.Group("/derivatives", requireIdentity)
+	derivatives.GET("", derivativesController.GetAllDerivatives)                         // All streamed symbols
+	derivatives.GET("/:symbol", derivativesController.GetDerivatives)                    // Single symbol
+	derivatives.GET("/:symbol/funding", derivativesController.GetFundingPrediction)      // Live premium index + predicted funding
+	derivatives.GET("/:symbol/funding/history", derivativesController.GetFundingHistory) // Predicted vs actual funding
+
+	// Market screener - tracked symbols ranked and filtered server-side
+	v1.GET("/screener", screenerController.GetScreener, requireIdentity)
+
+	// Options routes - Deribit option chains, ATM implied volatility term structure and DVOL candles
+	options := v1.Group("/options", requireIdentity)
+	options.GET("/:currency/chain", optionsController.GetChain)      // ?expiry=27JUN25 for a single expiry
+	options.GET("/:currency/iv", optionsController.GetTermStructure) // ATM IV per expiry + latest DVOL
+	options.GET("/:currency/dvol", optionsController.GetDVOL)        // ?interval=1h&limit=500
+
+	// Binance options board - tickers, mark prices, greeks and open interest by expiry and strike
+	options.GET("/binance/:underlying/board", binanceOptionsController.GetBoard)       // ?expiry=250627 for a single expiry
+	options.GET("/binance/:underlying/expiries", binanceOptionsController.GetExpiries) // ATM IV, open interest and put/call ratio per expiry
+
+	// Quant analytics routes - computed from persisted trades and book snapshots
+	analytics := v1.Group("/analytics", requireIdentity)
+	analytics.GET("/toxicity/:symbol", analyticsController.GetFlowToxicity)    // VPIN + order flow imbalance
+	analytics.GET("/levels/:symbol", analyticsController.GetSupportResistance) // Depth-based support/resistance
+	analytics.GET("/rolling/:symbol", analyticsController.GetRollingWindow)    // Trailing-window volume/delta/range stats
+	analytics.GET("/compare", analyticsController.GetCompare)                  // Multi-symbol closes rebased to 100
+	analytics.GET("/spreads", analyticsController.GetSpreads)                  // Latest spot-perp/cross-exchange spreads
+	analytics.GET("/spreads/history", analyticsController.GetSpreadHistory)    // Bucketed spread history for one pair
+
+	// Experimental analytics - heuristic estimates whose output may change without notice
+	experimental := v1.Group("/experimental", requireIdentity)
+	experimental.GET("/hidden-liquidity/:symbol", analyticsController.GetHiddenLiquidity) // Inferred hidden/iceberg size per price level
+
+	// Time-series query DSL - source series, transforms and range in one JSON body
+	v1.POST("/query", queryController.Query, requireIdentity)
+
+	// Strategy backtests - built-in strategies replayed over stored candles or trades
+	backtests := v1.Group("/backtest", requireIdentity)
+	backtests.POST("", backtestController.Run)
+	backtests.GET("/strategies", backtestController.GetStrategies)
+
+	// Significant events navigator - largest ranges, volume spikes and gaps with jump-to metadata
+	events := v1.Group("/events", requireIdentity)
+	events.GET("/:symbol", marketEventController.GetEvents)
+
+	// Portfolio routes - sub-accounts with isolated balances, positions and risk limits (bearer token)
+	portfolios := v1.Group("/portfolios", requireUser)
+	portfolios.GET("", portfolioController.GetPortfolios)
+	portfolios.POST("", portfolioController.CreatePortfolio)
+	portfolios.GET("/pnl", portfolioController.GetAggregatePnL) // Aggregated + per-portfolio PnL
+	portfolios.GET("/:id", portfolioController.GetPortfolio)
+	portfolios.PUT("/:id", portfolioController.UpdatePortfolio) // Name and risk limits
+	portfolios.DELETE("/:id", portfolioController.DeletePortfolio)
+	portfolios.GET("/:id/orders", portfolioController.GetOrders)
+	portfolios.GET("/:id/pnl", portfolioController.GetPortfolioPnL)
+
+	// Basket routes - symbol groups with a combined notional delta series for sector flow (bearer token)
+	baskets := v1.Group("/baskets", requireUser)
+	baskets.GET("", basketController.GetBaskets)
+	baskets.POST("", basketController.CreateBasket)
+	baskets.GET("/:id", basketController.GetBasket)
+	baskets.PUT("/:id", basketController.UpdateBasket) // Name and members
+	baskets.DELETE("/:id", basketController.DeleteBasket)
+	baskets.GET("/:id/footprint", basketController.GetBasketFootprint)
+
+	// Composite routes - user-defined spreads and ratios (bearer token); candles are served by
+	// the candle endpoints under the composite's "COMPOSITE:<name>" symbol
+	composites := v1.Group("/composites", requireUser)
+	composites.GET("", compositeController.GetComposites)
+	composites.POST("", compositeController.CreateComposite)
+	composites.GET("/:id", compositeController.GetComposite)
+	composites.DELETE("/:id", compositeController.DeleteComposite)
+
+	// Custom indicator routes - expressions such as "ema(close,21) - ema(close,55)" (bearer token),
+	// evaluated against any symbol's candles like the built-in series
+	indicators := v1.Group("/indicators", requireUser)
+	indicators.GET("", indicatorController.GetIndicators)
+	indicators.POST("", indicatorController.CreateIndicator)
+	indicators.GET("/:id", indicatorController.GetIndicator)
+	indicators.PUT("/:id", indicatorController.UpdateIndicator) // Name and expression
+	indicators.DELETE("/:id", indicatorController.DeleteIndicator)
+	indicators.GET("/:id/:symbol/:interval", indicatorController.GetIndicatorSeries)
+
+	// Order routes - the portfolio is selected per order via portfolio_id
+	v1.POST("/orders", portfolioController.PlaceOrder, requireUser)
+	v1.POST("/orders/validate", portfolioController.ValidateOrder, requireUser) // Exchange filter check without placing
+
+	// Live trading routes - futures orders on the deployment's Binance account (admin role or
+	// X-Admin-Token); never open without credentials, unlike dev admin routes
+	trading := v1.Group("/trading", requireAdminRole)
+	trading.GET("/orders", tradingController.GetOrders)
+	trading.POST("/orders", tradingController.PlaceOrder)
+	trading.GET("/orders/:id", tradingController.GetOrder)   // Refreshed from Binance while open
+	trading.PUT("/orders/:id", tradingController.AmendOrder) // Quantity and/or price of an open LIMIT order
+	trading.DELETE("/orders/:id", tradingController.CancelOrder)
+
+	// Account positions - open positions with unrealized and realized PnL (admin role or X-Admin-Token)
+	positions := v1.Group("/positions", requireAdminRole)
+	positions.GET("", positionController.GetPositions)
+	positions.GET("/fills", positionController.GetFills)
+	positions.GET("/:symbol", positionController.GetPosition)
+
+	// Report routes - daily watchlist recaps (bearer token)
+	reports := v1.Group("/reports", requireUser)
+	reports.GET("", reportController.GetReports)               // Report history
+	reports.GET("/latest", reportController.GetLatestReport)   // Most recent report
+	reports.POST("/generate", reportController.GenerateReport) // Generate now (?date=YYYY-MM-DD)
+	reports.GET("/settings", reportController.GetSettings)
+	reports.PUT("/settings", reportController.UpdateSettings) // Watchlist and email delivery
+	reports.GET("/:id", reportController.GetReport)
+
+	// Preference routes - theme, default interval and notification settings (bearer token)
+	preferences := v1.Group("/preferences", requireUser)
+	preferences.GET("", preferenceController.GetPreferences)      // Every preference, defaults filled in
+	preferences.PUT("", preferenceController.UpdatePreferences)   // Sets the given preferences; null resets one
+	preferences.DELETE("", preferenceController.ResetPreferences) // Restores every default
+	preferences.GET("/schema", preferenceController.GetSchema)    // Types, accepted values and defaults
+
+	// Price alert routes - price-cross alerts fired over WebSocket (bearer token)
+	alerts := v1.Group("/alerts", requireUser)
+	alerts.GET("", alertController.GetAlerts) // ?state=pending|armed|triggered
+	alerts.POST("", alertController.CreateAlert)
+	alerts.POST("/preview", alertController.PreviewAlert)
+	alerts.GET("/:id", alertController.GetAlert)
+	alerts.DELETE("/:id", alertController.DeleteAlert)
+
+	// Webhook routes - closed candles, alerts and liquidations pushed to user endpoints (bearer token);
+	// refused like raw-data exports when compliance mode disallows them
+	webhooks := v1.Group("/webhooks", requireUser, dataExport)
+	webhooks.GET("", webhookController.GetWebhooks)
+	webhooks.POST("", webhookController.CreateWebhook) // Response carries the signing secret
+	webhooks.GET("/:id", webhookController.GetWebhook)
+	webhooks.PUT("/:id", webhookController.UpdateWebhook) // URL, events, symbols, intervals and active flag
+	webhooks.DELETE("/:id", webhookController.DeleteWebhook)
+	webhooks.POST("/:id/secret", webhookController.RotateSecret)
+	webhooks.POST("/:id/test", webhookController.SendTest)
+	webhooks.GET("/:id/deliveries", webhookController.GetDeliveries)                      // Delivery log, newest first; ?status=failed lists dead letters
+	webhooks.POST("/:id/deliveries/replay", webhookController.ReplayDeadLetters)          // Resend every dead letter
+	webhooks.POST("/:id/deliveries/:deliveryId/replay", webhookController.ReplayDelivery) // Resend one dead letter
+
 	// DATA COLLECTION SERVICE ROUTES - For monitoring and controlling continuous data collection
+	// Monitoring needs any role, control the admin role and the action's permission
 	collection := v1.Group("/data-collection")
-	collection.GET("/stats", dataCollectionController.GetStats)                  // Service statistics
-	collection.POST("/collect", dataCollectionController.TriggerCollection)      // Manual trigger
-	collection.POST("/historical", dataCollectionController.FetchHistoricalData) // Fetch historical data
-	collection.POST("/start", dataCollectionController.StartService)             // Start service
-	collection.POST("/stop", dataCollectionController.StopService)               // Stop service
-	collection.POST("/symbols", dataCollectionController.AddSymbol)              // Add symbol to collection
-	collection.DELETE("/symbols/:symbol", dataCollectionController.RemoveSymbol) // Remove symbol
+	collection.GET("/stats", dataCollectionController.GetStats, requireReadonlyRole)                               // Service statistics
+	collection.GET("/consistency", dataCollectionController.GetConsistency, requireReadonlyRole)                   // Stream vs REST divergence per symbol
+	collection.POST("/collect", dataCollectionController.TriggerCollection, requireCollectionTrigger)              // Manual trigger
+	collection.POST("/historical", dataCollectionController.FetchHistoricalData, requireCollectionTrigger)         // Fetch historical data
+	collection.POST("/start", dataCollectionController.StartService, requireCollectionStop)                        // Start service
+	collection.POST("/stop", dataCollectionController.StopService, requireCollectionStop)                          // Stop service
+	collection.POST("/symbols", dataCollectionController.AddSymbol, requireCollectionConfigure)                    // Add symbol to collection
+	collection.PUT("/price-types", dataCollectionController.SetPriceTypes, requireCollectionConfigure)             // Collect mark/index candles
+	collection.DELETE("/symbols/:symbol", dataCollectionController.RemoveSymbol, requireCollectionConfigure)       // Remove symbol
+	collection.GET("/pauses", dataCollectionController.GetPauses, requireReadonlyRole)                             // Symbol pauses in effect and history
+	collection.POST("/symbols/:symbol/pause", dataCollectionController.PauseSymbol, requireCollectionConfigure)    // Pause a symbol's collection and streaming
+	collection.DELETE("/symbols/:symbol/pause", dataCollectionController.ResumeSymbol, requireCollectionConfigure) // Resume a paused symbol
 
 	// ULTRA-FAST WEBSOCKET ROUTES - SUB-100MS REAL-TIME UPDATES
-	ws := v1.Group("/websocket")
+	ws := v1.Group("/websocket", requireIdentity)
 
 	// WebSocket connection endpoint - upgrade HTTP to WebSocket
 	ws.GET("/connect", websocketController.HandleWebSocket)
 
+	// Long-polling fallback transport (same subscription messages as the WebSocket)
+	ws.POST("/poll", websocketController.OpenPollSession)
+	ws.GET("/poll/:session", websocketController.Poll)
+	ws.POST("/poll/:session/messages", websocketController.SendPollMessage)
+	ws.DELETE("/poll/:session", websocketController.ClosePollSession)
+
 	// WebSocket service statistics and monitoring
 	ws.GET("/stats", websocketController.GetWebSocketStats)
 
@@ -120,19 +1062,26 @@ func SetupRoutes(e *echo.Echo, db *database.DB, cfg *config.Config) {
 	ws.GET("/price/:symbol", websocketController.GetLastPrice)
 
 	// Enhanced Binance WebSocket data endpoints - maximizing data streams
-	ws.GET("/depth/:symbol", websocketController.GetDepthData)           // Order book depth
-	ws.GET("/trades/:symbol", websocketController.GetRecentTrades)       // Recent trades
-	ws.GET("/kline/:symbol/:interval", websocketController.GetKlineData) // Kline data
-	ws.GET("/volume/:symbol", websocketController.GetVolumeData)         // Real-time buy/sell volume
+	ws.GET("/depth/:symbol", websocketController.GetDepthData, dataExport)     // Order book depth
+	ws.GET("/trades/:symbol", websocketController.GetRecentTrades, dataExport) // Recent trades
+	ws.GET("/kline/:symbol/:interval", websocketController.GetKlineData)       // Kline data
+	ws.GET("/volume/:symbol", websocketController.GetVolumeData)               // Real-time buy/sell volume
 
 	// NEW: Futures-specific endpoints for derivatives trading
 	ws.GET("/markprice/:symbol", websocketController.GetMarkPriceData)         // Futures mark price
 	ws.GET("/liquidations/:symbol", websocketController.GetRecentLiquidations) // Futures liquidations
 
-	// Symbol management endpoints
-	ws.POST("/symbols/:symbol", websocketController.AddSymbolToStream) // Add symbol to stream
+	// Archived stream captures in the Hub's message format, for replay
+	ws.GET("/capture/:symbol", streamCaptureController.GetCapture, dataExport) // Gzipped NDJSON of stored trades/klines/liquidations
+
+	// Symbol management endpoints (admin role)
+	ws.POST("/symbols/:symbol", websocketController.AddSymbolToStream, requireAdminRole) // Add symbol to stream
+
+	// Watch-only lite WebSocket for embedded mini-charts: exempt from identity, limited per address
+	embed := v1.Group("/embed", middleware.IPRateLimit(cfg.EmbedConnectsPerMinute, cfg.EmbedConnectsPerMinute))
+	embed.GET("/connect", websocketController.HandleLiteWebSocket)
 
 	// Legacy WebSocket routes for backward compatibility
-	legacyWs := v1.Group("/ws")
+	legacyWs := v1.Group("/ws", requireIdentity)
 	legacyWs.GET("/candles/:symbol", candleController.StreamCandles)
 }
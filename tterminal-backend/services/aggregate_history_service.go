@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxAggregateVersions caps the versions listed per request
+	maxAggregateVersions = 500
+	// maxVersionedVolumeProfileRange matches the longest volume profile served (168 hours)
+	maxVersionedVolumeProfileRange = 168 * time.Hour
+)
+
+// ErrAggregateVersionNotFound is returned for "as of" requests before an aggregate's first computation
+var ErrAggregateVersionNotFound = errors.New("no version of the aggregate was computed by then")
+
+// AggregateHistoryService versions aggregates over fixed ranges so they can be requested "as of"
+// an earlier computation time. Every computation of a range that has ended is stored as a new
+// version when its result differs from the current one (after a backfill, repair or purge
+// altered the underlying candles), so backtests replaying an earlier computation time get the
+// numbers they saw then. Ranges still open are computed but not versioned, since every new bar
+// changes them
+type AggregateHistoryService struct {
+	aggregationService *AggregationService
+	candleService      marketdata.CandleSource
+	versionRepo        *repositories.AggregateVersionRepository
+}
+
+// NewAggregateHistoryService creates a new aggregate history service
+func NewAggregateHistoryService(aggregationService *AggregationService, candleService marketdata.CandleSource, versionRepo *repositories.AggregateVersionRepository) *AggregateHistoryService {
+	if aggregationService == nil {
+		log.Fatalf("[AggregateHistoryService] CRITICAL: aggregationService cannot be nil")
+	}
+	if isNil(candleService) {
+		log.Fatalf("[AggregateHistoryService] CRITICAL: candleService cannot be nil")
+	}
+	if versionRepo == nil {
+		log.Fatalf("[AggregateHistoryService] CRITICAL: versionRepo cannot be nil")
+	}
+
+	log.Printf("[AggregateHistoryService] Successfully initialized")
+	return &AggregateHistoryService{
+		aggregationService: aggregationService,
+		candleService:      candleService,
+		versionRepo:        versionRepo,
+	}
+}
+
+// VolumeProfile returns the volume profile of [startTime, endTime] with its version
+// With a zero asOf the profile is computed now (and versioned when the range has ended, otherwise
+// the version is nil); with asOf the version valid at that time is returned
+func (s *AggregateHistoryService) VolumeProfile(ctx context.Context, symbol string, startTime, endTime, asOf time.Time) (*models.VolumeProfile, *models.AggregateVersion, error) {
+	if err := validateVersionedRange(startTime, endTime, maxVersionedVolumeProfileRange); err != nil {
+		return nil, nil, err
+	}
+
+	if !asOf.IsZero() {
+		var vp models.VolumeProfile
+		version, err := s.versionAsOf(ctx, models.AggregateVolumeProfile, symbol, "", startTime, endTime, asOf, &vp)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &vp, version, nil
+	}
+
+	computed, err := s.aggregationService.GetVolumeProfile(ctx, symbol, startTime, endTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Levels of equal volume come out of the calculation in map order; fix their order so an
+	// unchanged profile keeps its checksum. The computed profile may be cached, so it is copied
+	vp := *computed
+	vp.L = append([]models.VolumeProfileLevel(nil), computed.L...)
+	sort.SliceStable(vp.L, func(i, j int) bool {
+		if vp.L[i].V != vp.L[j].V {
+			return vp.L[i].V > vp.L[j].V
+		}
+		return vp.L[i].P < vp.L[j].P
+	})
+
+	version, err := s.record(ctx, models.AggregateVolumeProfile, symbol, "", startTime, endTime, &vp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &vp, version, nil
+}
+
+// CVD returns the cumulative volume delta of the bars opening in [startTime, endTime] with its
+// version, computed now or as of an earlier computation like VolumeProfile
+func (s *AggregateHistoryService) CVD(ctx context.Context, symbol, interval string, startTime, endTime, asOf time.Time) (*models.CVDSeries, *models.AggregateVersion, error) {
+	if !models.IsValidInterval(interval) {
+		return nil, nil, fmt.Errorf("validation failed: invalid interval %q", interval)
+	}
+	if err := validateVersionedRange(startTime, endTime, models.MaxRangeDuration(interval)); err != nil {
+		return nil, nil, err
+	}
+
+	if !asOf.IsZero() {
+		var series models.CVDSeries
+		version, err := s.versionAsOf(ctx, models.AggregateCVD, symbol, interval, startTime, endTime, asOf, &series)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &series, version, nil
+	}
+
+	candles, err := s.candleService.GetByTimeRange(ctx, symbol, interval, startTime, endTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	series := &models.CVDSeries{
+		S:  symbol,
+		I:  interval,
+		ST: startTime.UnixMilli(),
+		ET: endTime.UnixMilli(),
+		D:  make([]models.CVDBar, 0, len(candles)),
+	}
+	cumulative := 0.0
+	for _, candle := range models.EstimateTakerVolume(candles) {
+		delta := 2*models.ParseFloat(candle.TakerBuyBaseAssetVolume) - models.ParseFloat(candle.Volume)
+		cumulative += delta
+		series.D = append(series.D, models.CVDBar{
+			T:  candle.OpenTime.UnixMilli(),
+			D:  delta,
+			CD: cumulative,
+			E:  candle.TakerVolumeEstimated,
+		})
+	}
+
+	version, err := s.record(ctx, models.AggregateCVD, symbol, interval, startTime, endTime, series)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, version, nil
+}
+
+// Versions lists the stored versions of an aggregate, newest first; interval is empty for volume profiles
+func (s *AggregateHistoryService) Versions(ctx context.Context, kind, symbol, interval string, startTime, endTime time.Time, limit int) ([]models.AggregateVersion, error) {
+	switch kind {
+	case models.AggregateVolumeProfile:
+		interval = ""
+	case models.AggregateCVD:
+		if !models.IsValidInterval(interval) {
+			return nil, fmt.Errorf("validation failed: invalid interval %q", interval)
+		}
+	default:
+		return nil, fmt.Errorf("validation failed: kind must be %s or %s", models.AggregateVolumeProfile, models.AggregateCVD)
+	}
+	if limit <= 0 || limit > maxAggregateVersions {
+		limit = maxAggregateVersions
+	}
+	return s.versionRepo.List(ctx, kind, symbol, interval, startTime, endTime, limit)
+}
+
+// record stores a computation of an aggregate whose range has ended, returning the current version
+func (s *AggregateHistoryService) record(ctx context.Context, kind, symbol, interval string, startTime, endTime time.Time, data interface{}) (*models.AggregateVersion, error) {
+	// Truncated to the database's precision, so the computation time is a valid asOf
+	computedAt := time.Now().Truncate(time.Microsecond)
+	if !endTime.Before(computedAt) {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+	checksum := sha256.Sum256(payload)
+
+	version, created, err := s.versionRepo.Record(ctx, &models.AggregateVersion{
+		Kind:      kind,
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: startTime.UTC(),
+		EndTime:   endTime.UTC(),
+		Checksum:  hex.EncodeToString(checksum[:]),
+		ValidFrom: computedAt,
+		Data:      payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		log.Printf("[AggregateHistoryService] Stored %s version %d of %s %s [%s, %s]", kind, version.ID, symbol, interval,
+			startTime.UTC().Format(time.RFC3339), endTime.UTC().Format(time.RFC3339))
+	}
+	return version, nil
+}
+
+// versionAsOf loads the version of an aggregate valid at asOf and decodes its data into dst
+func (s *AggregateHistoryService) versionAsOf(ctx context.Context, kind, symbol, interval string, startTime, endTime, asOf time.Time, dst interface{}) (*models.AggregateVersion, error) {
+	version, err := s.versionRepo.GetAsOf(ctx, kind, symbol, interval, startTime.UTC(), endTime.UTC(), asOf)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, ErrAggregateVersionNotFound
+	}
+	if err := json.Unmarshal(version.Data, dst); err != nil {
+		return nil, fmt.Errorf("failed to decode %s version %d: %w", kind, version.ID, err)
+	}
+	return version, nil
+}
+
+// validateVersionedRange checks a fixed aggregate range
+func validateVersionedRange(startTime, endTime time.Time, maxRange time.Duration) error {
+	if !startTime.Before(endTime) {
+		return fmt.Errorf("validation failed: start must be before end")
+	}
+	if maxRange > 0 && endTime.Sub(startTime) > maxRange {
+		return fmt.Errorf("validation failed: range must not exceed %s", maxRange)
+	}
+	return nil
+}
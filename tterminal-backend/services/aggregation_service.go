@@ -5,15 +5,29 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strings"
 	"sync"
 	"time"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/internal/traffic"
 	"tterminal-backend/models"
 	"tterminal-backend/pkg/cache"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// Default latency budget and fetch concurrency for multi-data requests
+	defaultMultiTimeout     = 2 * time.Second
+	defaultMultiConcurrency = 4
+	// Default aggregation fetches running at once across requests, and how many batch requests may hold
+	defaultFetchWorkers      = 16
+	defaultFetchBatchWorkers = 4
 )
 
 // AggregationService handles ultra-fast data aggregation from multiple sources
 type AggregationService struct {
-	candleService *CandleService
+	candleService marketdata.CandleSource
 	cache         *cache.RedisCache
 	mu            sync.RWMutex
 	// In-memory cache for ultra-fast access (LRU with TTL)
@@ -28,6 +42,23 @@ type AggregationService struct {
 	errorCount    int64
 	lastError     error
 	lastErrorTime time.Time
+	// Multi-data request budget
+	multiTimeout     time.Duration
+	multiConcurrency int
+	// Shared by the fetches of all multi-data requests; interactive requests are served first
+	fetchGate *traffic.Gate
+	// Cross-exchange constituents of composite index candles (optional)
+	constituents IndexConstituents
+	// Stored footprint history built from persisted trades (optional)
+	footprints marketdata.FootprintStore
+	// Candle volume unit conversion (optional)
+	volumeConverter VolumeConverter
+	// Funding and open interest merged into candles (optional)
+	candleOverlay CandleOverlay
+	// Questionable ranges marked on candles (optional)
+	candleAnnotator CandleAnnotator
+	// Named UTC trading sessions of session volume profiles
+	sessions []models.TradingSession
 }
 
 // CachedData represents cached aggregated data
@@ -67,15 +98,21 @@ type AggregationResponse struct {
 }
 
 // NewAggregationService creates a new ultra-fast aggregation service
-func NewAggregationService(candleService *CandleService, cache *cache.RedisCache) *AggregationService {
+func NewAggregationService(candleService marketdata.CandleSource, cache *cache.RedisCache) *AggregationService {
+	if isNil(candleService) {
+		candleService = nil
+	}
 	service := &AggregationService{
-		candleService: candleService,
-		cache:         cache,
-		memCache:      make(map[string]*CachedData),
-		aggregations:  make(map[string]*PrecomputedAggregation),
-		workers:       8, // Use 8 worker goroutines for parallel processing
-		tickerStop:    make(chan bool),
-		updateQueue:   make(chan AggregationRequest, 1000), // Buffer for 1000 requests
+		candleService:    candleService,
+		cache:            cache,
+		memCache:         make(map[string]*CachedData),
+		aggregations:     make(map[string]*PrecomputedAggregation),
+		workers:          8, // Use 8 worker goroutines for parallel processing
+		tickerStop:       make(chan bool),
+		updateQueue:      make(chan AggregationRequest, 1000), // Buffer for 1000 requests
+		multiTimeout:     defaultMultiTimeout,
+		multiConcurrency: defaultMultiConcurrency,
+		fetchGate:        traffic.NewGate(defaultFetchWorkers, defaultFetchBatchWorkers),
 	}
 
 	// Start background workers
@@ -87,7 +124,13 @@ func NewAggregationService(candleService *CandleService, cache *cache.RedisCache
 
 // GetAggregatedCandles returns ultra-optimized candle data with detailed error handling
 func (s *AggregationService) GetAggregatedCandles(ctx context.Context, symbol, interval string, limit int) (*models.CandleResponse, error) {
-	log.Printf("[AggregationService] GetAggregatedCandles called: symbol=%s, interval=%s, limit=%d", symbol, interval, limit)
+	return s.GetAggregatedCandlesBefore(ctx, symbol, interval, time.Time{}, limit)
+}
+
+// GetAggregatedCandlesBefore returns the limit candles opening before a time; a zero time returns the latest candles
+// Anchored pages share the symbol's cache key prefix, so invalidation covers them
+func (s *AggregationService) GetAggregatedCandlesBefore(ctx context.Context, symbol, interval string, before time.Time, limit int) (*models.CandleResponse, error) {
+	log.Printf("[AggregationService] GetAggregatedCandles called: symbol=%s, interval=%s, limit=%d, before=%v", symbol, interval, limit, before)
 
 	// Validate inputs
 	if symbol == "" {
@@ -106,7 +149,11 @@ func (s *AggregationService) GetAggregatedCandles(ctx context.Context, symbol, i
 		return nil, err
 	}
 
+	anchored := !before.IsZero() && before.Before(time.Now())
 	cacheKey := fmt.Sprintf("agg:candles:%s:%s:%d", symbol, interval, limit)
+	if anchored {
+		cacheKey += fmt.Sprintf(":before:%d", before.UnixMilli())
+	}
 	log.Printf("[AggregationService] Generated cache key: %s", cacheKey)
 
 	// Try memory cache first (fastest)
@@ -146,7 +193,13 @@ func (s *AggregationService) GetAggregatedCandles(ctx context.Context, symbol, i
 	}
 
 	// Use the optimized method that returns real buy/sell volume data
-	optimizedCandles, err := s.candleService.GetOptimizedCandleData(ctx, symbol, interval, limit)
+	var optimizedCandles []models.OptimizedCandle
+	var err error
+	if anchored {
+		optimizedCandles, err = s.candleService.GetOptimizedCandleDataBefore(ctx, symbol, interval, before, limit)
+	} else {
+		optimizedCandles, err = s.candleService.GetOptimizedCandleData(ctx, symbol, interval, limit)
+	}
 	if err != nil {
 		err = fmt.Errorf("failed to get optimized candles from service: %w", err)
 		log.Printf("[AggregationService] Service error: %v", err)
@@ -171,8 +224,16 @@ func (s *AggregationService) GetAggregatedCandles(ctx context.Context, symbol, i
 		F: firstTime,
 		L: lastTime,
 	}
+	// Anchored pages are backfilled, so a short one is the start of history; the latest page is
+	// served as stored and always points further back
+	if anchored {
+		optimizedResponse.SetNextCursor(limit)
+	} else if optimizedResponse.N > 0 {
+		optimizedResponse.NextCursor = firstTime
+	}
 
 	log.Printf("[AggregationService] Created optimized response with %d candles including real buy/sell volume data", optimizedResponse.N)
+	s.annotateCandles(ctx, optimizedResponse)
 
 	// Cache the result (Redis: 5min, Memory: 30sec)
 	if s.cache != nil {
@@ -234,10 +295,21 @@ func (s *AggregationService) GetFootprintData(ctx context.Context, symbol, inter
 		}
 	}
 
-	// Generate footprint data (this would involve trade analysis)
-	footprint, err := s.generateFootprintData(ctx, symbol, interval, limit)
-	if err != nil {
-		return nil, err
+	// Serve stored footprint history when available, otherwise estimate from candles
+	var footprint []models.FootprintCandle
+	if store := s.footprintStore(); store != nil {
+		stored, err := store.GetLatest(ctx, symbol, interval, limit)
+		if err != nil {
+			log.Printf("[AggregationService] WARNING: Failed to read stored footprint of %s %s: %v", symbol, interval, err)
+		}
+		footprint = stored
+	}
+	if len(footprint) == 0 {
+		generated, err := s.generateFootprintData(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, err
+		}
+		footprint = generated
 	}
 
 	// Cache for 1 minute (footprint data changes frequently)
@@ -289,6 +361,65 @@ func (s *AggregationService) GetHeatmap(ctx context.Context, symbol string, star
 	return heatmap, nil
 }
 
+// HandleBarClose drops cached aggregated candles of the closed bar's symbol and interval so the
+// next request includes the final bar instead of waiting for the cache TTL, and stores the bar's
+// footprint when footprint history is enabled
+func (s *AggregationService) HandleBarClose(bar models.BarClose) {
+	if store := s.footprintStore(); store != nil {
+		go s.storeFootprint(store, bar)
+	}
+
+	prefix := fmt.Sprintf("agg:candles:%s:%s:", bar.Symbol, bar.Interval)
+
+	s.mu.Lock()
+	var stale []string
+	for key := range s.memCache {
+		if strings.HasPrefix(key, prefix) {
+			stale = append(stale, key)
+			delete(s.memCache, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.cache == nil || len(stale) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, key := range stale {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			log.Printf("[AggregationService] WARNING: Failed to invalidate Redis cache %s: %v", key, err)
+		}
+	}
+}
+
+// InvalidateSymbol drops every cached and pre-computed aggregation of a symbol, in memory and in
+// Redis, after its stored data changed underneath the cache
+func (s *AggregationService) InvalidateSymbol(ctx context.Context, symbol string) error {
+	prefixes := []string{"agg:candles:" + symbol + ":", "vp:" + symbol + ":", "footprint:" + symbol + ":", "heatmap:" + symbol + ":"}
+
+	s.mu.Lock()
+	for key := range s.memCache {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				delete(s.memCache, key)
+				break
+			}
+		}
+	}
+	delete(s.aggregations, symbol)
+	s.mu.Unlock()
+
+	if s.cache == nil {
+		return nil
+	}
+	if _, err := s.cache.DeletePattern(ctx, "agg:candles:"+symbol+":*"); err != nil {
+		return fmt.Errorf("failed to invalidate Redis cache for %s: %w", symbol, err)
+	}
+	return nil
+}
+
 // PRIVATE METHODS
 
 // Memory cache operations (ultra-fast)
@@ -397,6 +528,11 @@ func (s *AggregationService) calculateVolumeProfile(ctx context.Context, symbol
 		return nil, err
 	}
 
+	return buildVolumeProfile(symbol, startTime, endTime, candles), nil
+}
+
+// buildVolumeProfile distributes the volume of 1m candles across their price ranges
+func buildVolumeProfile(symbol string, startTime, endTime time.Time, candles []models.Candle) *models.VolumeProfile {
 	// Calculate price levels and volume distribution
 	priceVolume := make(map[float64]float64)
 	totalVolume := 0.0
@@ -471,7 +607,7 @@ func (s *AggregationService) calculateVolumeProfile(ctx context.Context, symbol
 		VAH: vah,
 		VAL: val,
 		VAV: 70.0,
-	}, nil
+	}
 }
 
 // Footprint data generation (simplified - would need trade data)
@@ -565,6 +701,308 @@ func (s *AggregationService) GetServiceStats() map[string]interface{} {
 		"last_error":        s.lastError,
 		"last_error_time":   s.lastErrorTime,
 		"workers":           s.workers,
+		"fetch_workers":     s.fetchGate.Stats(),
 		"aggregations":      len(s.aggregations),
 	}
 }
+
+// MultiDataParams selects the sections returned by GetMultiData
+type MultiDataParams struct {
+	Symbol               string
+	Intervals            []string
+	Limit                int
+	IncludeVolumeProfile bool
+	VolumeProfileHours   int
+	IncludeLiquidations  bool
+	LiquidationHours     int
+	Snapshot             bool     // Pin every section to the same last closed candle boundary
+	VolumeUnit           string   // Unit of candle volume; empty keeps the stored unit
+	Include              []string // Derivatives overlays merged into the candles (funding, oi)
+}
+
+// maxSnapshotBasis is the longest interval a snapshot basis is taken from; longer boundaries
+// are calendar-aligned (weeks, months) and cannot be found by truncating the time
+const maxSnapshotBasis = 24 * time.Hour
+
+// multiSection is one independently fetched part of a multi-data response
+// fetch returns a closure that stores the result so late results can be dropped after the deadline
+type multiSection struct {
+	name  string
+	fetch func(ctx context.Context) (func(*models.MultiDataResponse), error)
+}
+
+// SetMultiRequestBudget sets the overall deadline and fetch concurrency for multi-data requests
+func (s *AggregationService) SetMultiRequestBudget(timeout time.Duration, concurrency int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timeout > 0 {
+		s.multiTimeout = timeout
+	}
+	if concurrency > 0 {
+		s.multiConcurrency = concurrency
+	}
+}
+
+// SetFetchWorkers sets how many multi-data fetches run at once across requests, and how many of
+// them batch requests may hold
+func (s *AggregationService) SetFetchWorkers(workers, batchWorkers int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if workers > 0 && batchWorkers > 0 {
+		s.fetchGate = traffic.NewGate(workers, batchWorkers)
+	}
+}
+
+// FetchWorkerStats returns the usage of the shared multi-data fetch workers
+func (s *AggregationService) FetchWorkerStats() traffic.GateStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fetchGate.Stats()
+}
+
+// GetMultiData fetches every requested section concurrently and returns whatever finished within the budget
+// Failed and unfinished sections are reported per section instead of failing the whole response
+// In snapshot mode every section ends at one basis time: the close of the last candle of the
+// smallest requested interval (at most 1d), so candles, volume profile and liquidations agree
+func (s *AggregationService) GetMultiData(ctx context.Context, params MultiDataParams) *models.MultiDataResponse {
+	start := time.Now()
+
+	s.mu.RLock()
+	timeout, concurrency, fetchGate := s.multiTimeout, s.multiConcurrency, s.fetchGate
+	s.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var basis time.Time
+	if params.Snapshot {
+		basis = snapshotBasis(params.Intervals, start)
+	}
+	sections := s.buildMultiSections(params, basis)
+
+	response := &models.MultiDataResponse{
+		Symbol:  params.Symbol,
+		Candles: make(map[string]*models.CandleResponse),
+		Errors:  make(map[string]string),
+	}
+	if !basis.IsZero() {
+		response.Snapshot = true
+		response.BasisTime = basis.UnixMilli()
+	}
+
+	var resultMu sync.Mutex
+	finalized := false
+	pending := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		pending[section.name] = true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var g errgroup.Group
+		g.SetLimit(concurrency)
+		for _, section := range sections {
+			section := section
+			g.Go(func() error {
+				// Sections still queued when the budget runs out are skipped; batch requests
+				// wait for a worker while interactive ones are queued
+				release, err := fetchGate.Acquire(ctx)
+				if err != nil {
+					return nil
+				}
+				store, err := section.fetch(ctx)
+				release()
+
+				resultMu.Lock()
+				defer resultMu.Unlock()
+				if finalized {
+					return nil
+				}
+				delete(pending, section.name)
+				if err != nil {
+					response.Errors[section.name] = err.Error()
+					return nil
+				}
+				store(response)
+				return nil
+			})
+		}
+		g.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	resultMu.Lock()
+	defer resultMu.Unlock()
+	finalized = true
+
+	for name := range pending {
+		response.Errors[name] = fmt.Sprintf("not completed within the %s latency budget", timeout)
+	}
+	if len(response.Errors) == 0 {
+		response.Errors = nil
+	} else {
+		response.Partial = true
+		log.Printf("[AggregationService] Multi-data request for %s returned partial data: %d section errors", params.Symbol, len(response.Errors))
+	}
+	response.ElapsedMs = time.Since(start).Milliseconds()
+
+	return response
+}
+
+// buildMultiSections turns multi-data parameters into independent fetches, skipping duplicate intervals
+// A non-zero basis ends every section at that time instead of now
+func (s *AggregationService) buildMultiSections(params MultiDataParams, basis time.Time) []multiSection {
+	sections := make([]multiSection, 0, len(params.Intervals)+2)
+	seen := make(map[string]bool, len(params.Intervals))
+
+	for _, interval := range params.Intervals {
+		if seen[interval] {
+			continue
+		}
+		seen[interval] = true
+
+		interval := interval
+		sections = append(sections, multiSection{
+			name: "candles:" + interval,
+			fetch: func(ctx context.Context) (func(*models.MultiDataResponse), error) {
+				var candles *models.CandleResponse
+				var err error
+				if basis.IsZero() {
+					candles, err = s.GetAggregatedCandles(ctx, params.Symbol, interval, params.Limit)
+				} else {
+					candles, err = s.getClosedCandles(ctx, params.Symbol, interval, basis, params.Limit)
+				}
+				if err != nil {
+					return nil, err
+				}
+				if candles, err = s.ConvertVolume(ctx, candles, params.VolumeUnit); err != nil {
+					return nil, err
+				}
+				if candles, err = s.OverlayCandles(ctx, candles, params.Include); err != nil {
+					return nil, err
+				}
+				return func(response *models.MultiDataResponse) {
+					response.Candles[interval] = candles
+				}, nil
+			},
+		})
+	}
+
+	if params.IncludeVolumeProfile {
+		sections = append(sections, multiSection{
+			name: "volume_profile",
+			fetch: func(ctx context.Context) (func(*models.MultiDataResponse), error) {
+				endTime := time.Now()
+				if !basis.IsZero() {
+					endTime = basis
+				}
+				startTime := endTime.Add(-time.Duration(params.VolumeProfileHours) * time.Hour)
+				vp, err := s.GetVolumeProfile(ctx, params.Symbol, startTime, endTime)
+				if err != nil {
+					return nil, err
+				}
+				return func(response *models.MultiDataResponse) {
+					response.VolumeProfile = vp
+				}, nil
+			},
+		})
+	}
+
+	if params.IncludeLiquidations {
+		sections = append(sections, multiSection{
+			name: "liquidations",
+			fetch: func(ctx context.Context) (func(*models.MultiDataResponse), error) {
+				timeRange := time.Duration(params.LiquidationHours) * time.Hour
+				if !basis.IsZero() {
+					// Widened by the time since the basis, then cut back to the window ending at it
+					timeRange += time.Since(basis)
+				}
+				liquidations, err := s.GetLiquidations(ctx, params.Symbol, timeRange)
+				if err != nil {
+					return nil, err
+				}
+				if !basis.IsZero() {
+					liquidations = liquidationsBetween(liquidations, basis.Add(-time.Duration(params.LiquidationHours)*time.Hour), basis)
+				}
+				return func(response *models.MultiDataResponse) {
+					response.Liquidations = liquidations
+				}, nil
+			},
+		})
+	}
+
+	return sections
+}
+
+// snapshotBasis returns the last candle boundary of the smallest interval (at most 1d) at or before now
+func snapshotBasis(intervals []string, now time.Time) time.Time {
+	step := maxSnapshotBasis
+	for _, interval := range intervals {
+		if duration, ok := models.IntervalDuration(interval); ok && duration < step {
+			step = duration
+		}
+	}
+	return now.Truncate(step)
+}
+
+// getClosedCandles returns the limit latest candles closed at the basis time. Candles still open
+// at the basis (the current bar of longer intervals) are left out
+func (s *AggregationService) getClosedCandles(ctx context.Context, symbol, interval string, basis time.Time, limit int) (*models.CandleResponse, error) {
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("invalid interval: %s", interval)
+	}
+
+	// One extra candle covers the bar open at the basis that is trimmed below
+	page, err := s.GetAggregatedCandlesBefore(ctx, symbol, interval, basis, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := page.D
+	for len(candles) > 0 && time.UnixMilli(candles[len(candles)-1].T).Add(duration).After(basis) {
+		candles = candles[:len(candles)-1]
+	}
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+
+	// Pages are shared with the cache, so the trimmed response is a copy
+	closed := *page
+	closed.D = candles
+	closed.N = len(candles)
+	closed.F, closed.L, closed.NextCursor = 0, 0, 0
+	if closed.N > 0 {
+		closed.F = candles[0].T
+		closed.L = candles[len(candles)-1].T
+	}
+	if page.N > limit || page.Stale {
+		closed.NextCursor = closed.F
+	}
+	closed.A = nil
+	for _, annotation := range page.A {
+		if closed.N > 0 && annotation.End > closed.F && annotation.Start < closed.L+duration.Milliseconds() {
+			closed.A = append(closed.A, annotation)
+		}
+	}
+	return &closed, nil
+}
+
+// liquidationsBetween keeps the liquidations after start and at or before end
+func liquidationsBetween(liquidations []models.Liquidation, start, end time.Time) []models.Liquidation {
+	kept := make([]models.Liquidation, 0, len(liquidations))
+	for _, liquidation := range liquidations {
+		if liquidation.T > start.UnixMilli() && liquidation.T <= end.UnixMilli() {
+			kept = append(kept, liquidation)
+		}
+	}
+	return kept
+}
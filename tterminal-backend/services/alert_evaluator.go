@@ -0,0 +1,70 @@
+package services
+
+import (
+	"time"
+	"tterminal-backend/models"
+)
+
+// EvaluateAlert advances an alert's state machine with a new price and returns the trigger if it fired
+//
+// An alert never fires on the first price it sees: the price must first arm the alert by
+// moving beyond the hysteresis band on the arming side (below Price - Hysteresis for a cross up,
+// above Price + Hysteresis for a cross down) and then reach the trigger price. Firing disarms
+// that side, so a repeating alert only fires again after price has left the band
+func EvaluateAlert(condition models.AlertCondition, state *models.AlertState, price float64, at time.Time) *models.AlertTrigger {
+	if state.State == models.AlertStateTriggered || price <= 0 {
+		return nil
+	}
+	state.LastPrice = price
+
+	watchUp := condition.Direction != models.AlertDirectionCrossDown
+	watchDown := condition.Direction != models.AlertDirectionCrossUp
+
+	var trigger *models.AlertTrigger
+	switch {
+	case watchUp && state.ArmedBelow && price >= condition.Price:
+		state.ArmedBelow = false
+		trigger = fireAlert(condition, state, models.AlertDirectionCrossUp, price, at)
+	case watchDown && state.ArmedAbove && price <= condition.Price:
+		state.ArmedAbove = false
+		trigger = fireAlert(condition, state, models.AlertDirectionCrossDown, price, at)
+	}
+	if state.State == models.AlertStateTriggered {
+		return trigger
+	}
+
+	// Arm sides once price is clear of the hysteresis band
+	if price < condition.Price-condition.Hysteresis {
+		state.ArmedBelow = true
+	}
+	if price > condition.Price+condition.Hysteresis {
+		state.ArmedAbove = true
+	}
+
+	if (watchUp && state.ArmedBelow) || (watchDown && state.ArmedAbove) {
+		state.State = models.AlertStateArmed
+	} else {
+		state.State = models.AlertStatePending
+	}
+
+	return trigger
+}
+
+// fireAlert records a firing on the state and completes one-shot alerts
+func fireAlert(condition models.AlertCondition, state *models.AlertState, direction string, price float64, at time.Time) *models.AlertTrigger {
+	state.TriggerCount++
+	triggeredAt := at
+	state.LastTriggeredAt = &triggeredAt
+
+	if condition.Mode == models.AlertModeOnce {
+		state.State = models.AlertStateTriggered
+	}
+
+	return &models.AlertTrigger{
+		Direction:    direction,
+		TriggerPrice: condition.Price,
+		Price:        price,
+		TriggerCount: state.TriggerCount,
+		TriggeredAt:  at,
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+)
+
+const (
+	// alertPreviewInterval is the candle interval alerts are previewed on
+	alertPreviewInterval = "1m"
+	// defaultAlertPreviewHours is the lookback of a preview that does not set one
+	defaultAlertPreviewHours = 24
+	// maxAlertPreviewHours caps a preview's lookback at the range of one 1m candle request
+	maxAlertPreviewHours = 720
+	// maxAlertPreviewTriggers caps the firings listed by a preview
+	maxAlertPreviewTriggers = 500
+)
+
+// SetCandleService enables alert previews against stored candles
+func (s *AlertService) SetCandleService(candleService *CandleService) {
+	s.candleService = candleService
+}
+
+// PreviewAlert evaluates an alert definition against the stored 1m futures candles of a past
+// window and returns when it would have fired, without saving anything. Each candle is replayed
+// through the live state machine as four prices, so a preview arms and fires like a live alert
+// would have, except for moves inside a minute that the candle does not record
+func (s *AlertService) PreviewAlert(ctx context.Context, req *models.AlertPreviewRequest) (*models.AlertPreview, error) {
+	if s.candleService == nil {
+		return nil, fmt.Errorf("alert previews are not available")
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(req.Symbol))
+	market := strings.ToLower(strings.TrimSpace(req.Market))
+	if market == "" {
+		market = models.AlertMarketFutures
+	}
+	condition := req.AlertCondition
+	condition.Normalize()
+
+	if symbol == "" {
+		return nil, fmt.Errorf("validation failed: symbol is required")
+	}
+	if market != models.AlertMarketFutures {
+		return nil, fmt.Errorf("validation failed: previews are only available for the futures market")
+	}
+	if err := condition.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	hours := req.LookbackHours
+	if hours == 0 {
+		hours = defaultAlertPreviewHours
+	}
+	if hours < 0 || hours > maxAlertPreviewHours {
+		return nil, fmt.Errorf("validation failed: lookback_hours must be between 1 and %d", maxAlertPreviewHours)
+	}
+
+	end := time.Now().UTC().Truncate(time.Minute)
+	start := end.Add(-time.Duration(hours) * time.Hour)
+	candles, err := s.candleService.GetCandleRange(ctx, symbol, alertPreviewInterval, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candles: %w", err)
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("validation failed: no stored candles for %s in the last %d hours", symbol, hours)
+	}
+
+	preview := &models.AlertPreview{
+		Symbol:         symbol,
+		Market:         market,
+		AlertCondition: condition,
+		Interval:       alertPreviewInterval,
+		StartTime:      start,
+		EndTime:        end,
+		Candles:        len(candles),
+		Triggers:       []models.AlertTrigger{},
+	}
+
+	state := models.NewAlertState()
+	for _, candle := range candles {
+		for _, price := range candlePath(candle) {
+			trigger := EvaluateAlert(condition, &state, price, candle.OpenTime)
+			if trigger == nil {
+				continue
+			}
+			preview.TriggerCount++
+			if len(preview.Triggers) < maxAlertPreviewTriggers {
+				preview.Triggers = append(preview.Triggers, *trigger)
+			} else {
+				preview.Truncated = true
+			}
+		}
+		if state.State == models.AlertStateTriggered {
+			break
+		}
+	}
+	preview.FinalState = state
+
+	return preview, nil
+}
+
+// candlePath orders a candle's prices the way they most likely traded: a rising candle is assumed
+// to have made its low before its high, a falling one its high before its low
+func candlePath(candle models.Candle) [4]float64 {
+	open := models.ParseFloat(candle.Open)
+	high := models.ParseFloat(candle.High)
+	low := models.ParseFloat(candle.Low)
+	closePrice := models.ParseFloat(candle.Close)
+
+	if closePrice >= open {
+		return [4]float64{open, low, high, closePrice}
+	}
+	return [4]float64{open, high, low, closePrice}
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxAlertsPerUser caps the price alerts a user can create
+	maxAlertsPerUser = 100
+	// maxAlertNoteLength caps an alert's note
+	maxAlertNoteLength = 200
+	// maxAlertWebhooks caps the webhooks an alert notifies
+	maxAlertWebhooks = 5
+	// alertRefreshInterval reloads active alerts, picking up changes made on other instances
+	alertRefreshInterval = 30 * time.Second
+	// alertWriteQueueSize bounds the state changes waiting to be stored
+	alertWriteQueueSize = 1024
+	// alertWriteTimeout bounds storing one state change
+	alertWriteTimeout = 5 * time.Second
+)
+
+// AlertService manages user price alerts and evaluates them against every Binance last price
+// change. Alerts that can still fire are held in memory by symbol; state changes (arming and
+// firing) are written back in the background, and a firing is pushed to the user's WebSocket
+// connections as an "alert_triggered" event
+type AlertService struct {
+	alertRepo *repositories.PriceAlertRepository
+	stream    *websocket.BinanceStream
+	hub       transport.Publisher
+	onTrigger func(models.PriceAlert, models.AlertTrigger) // Called with every firing, such as webhook deliveries
+
+	checkWebhooks func(ctx context.Context, userID string, ids []int64) error // Validates the webhooks an alert notifies
+
+	candleService *CandleService // Optional, enables previews against stored candles
+
+	mu      sync.Mutex
+	active  map[string][]*models.PriceAlert // Symbol -> alerts that can still fire
+	retired map[int64]bool                  // Alerts fired or deleted here that may still be stored as active
+
+	writes    chan models.PriceAlert
+	stopChan  chan struct{}
+	isRunning bool
+}
+
+// NewAlertService creates a new price alert service
+func NewAlertService(alertRepo *repositories.PriceAlertRepository, stream *websocket.BinanceStream, hub transport.Publisher) *AlertService {
+	if alertRepo == nil {
+		log.Fatalf("[AlertService] CRITICAL: alertRepo cannot be nil")
+	}
+	if stream == nil || isNil(hub) {
+		log.Fatalf("[AlertService] CRITICAL: stream and hub cannot be nil")
+	}
+
+	log.Printf("[AlertService] Successfully initialized")
+	return &AlertService{
+		alertRepo: alertRepo,
+		stream:    stream,
+		hub:       hub,
+		active:    make(map[string][]*models.PriceAlert),
+		retired:   make(map[int64]bool),
+		writes:    make(chan models.PriceAlert, alertWriteQueueSize),
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start loads the active alerts and starts storing state changes and reloading alerts
+func (s *AlertService) Start() error {
+	s.mu.Lock()
+	if s.isRunning {
+		s.mu.Unlock()
+		return fmt.Errorf("alert service is already running")
+	}
+	s.isRunning = true
+	s.mu.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	go s.writer()
+	go s.refreshLoop()
+	return nil
+}
+
+// Stop stops storing state changes and reloading alerts
+func (s *AlertService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+}
+
+// SetTriggerHandler registers a handler called with every alert firing, after the user's
+// connections are notified. It runs on the stream's read loop and must return quickly
+func (s *AlertService) SetTriggerHandler(handler func(models.PriceAlert, models.AlertTrigger)) {
+	s.onTrigger = handler
+}
+
+// SetWebhookChecker registers the check of the webhooks an alert names, which must belong to its user
+func (s *AlertService) SetWebhookChecker(check func(ctx context.Context, userID string, ids []int64) error) {
+	s.checkWebhooks = check
+}
+
+// GetAlerts returns the price alerts of a user, optionally only those in a state
+func (s *AlertService) GetAlerts(ctx context.Context, userID, state string) ([]models.PriceAlert, error) {
+	switch state {
+	case "", models.AlertStatePending, models.AlertStateArmed, models.AlertStateTriggered:
+	default:
+		return nil, fmt.Errorf("validation failed: invalid state %q, use pending, armed or triggered", state)
+	}
+	alerts, err := s.alertRepo.GetByUser(ctx, userID, state)
+	if err != nil {
+		return nil, err
+	}
+	for i := range alerts {
+		s.withLiveState(&alerts[i])
+	}
+	return alerts, nil
+}
+
+// GetAlert returns a price alert owned by the user
+func (s *AlertService) GetAlert(ctx context.Context, userID string, id int64) (*models.PriceAlert, error) {
+	alert, err := s.ownedAlert(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.withLiveState(alert)
+	return alert, nil
+}
+
+// CreateAlert creates a price alert on a streamed symbol. It arms on the first prices of its
+// market, so it never fires on the price it was created at
+func (s *AlertService) CreateAlert(ctx context.Context, userID string, req *models.CreatePriceAlertRequest) (*models.PriceAlert, error) {
+	alert := &models.PriceAlert{
+		UserID:         userID,
+		Symbol:         strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Market:         strings.ToLower(strings.TrimSpace(req.Market)),
+		Note:           strings.TrimSpace(req.Note),
+		AlertCondition: req.AlertCondition,
+		AlertState:     models.NewAlertState(),
+		WebhookIDs:     []int64{},
+	}
+	if alert.Market == "" {
+		alert.Market = models.AlertMarketFutures
+	}
+	alert.Normalize()
+
+	if alert.Symbol == "" {
+		return nil, fmt.Errorf("validation failed: symbol is required")
+	}
+	if !s.isStreamed(alert.Symbol) {
+		return nil, fmt.Errorf("validation failed: %s is not streamed", alert.Symbol)
+	}
+	if alert.Market != models.AlertMarketFutures && alert.Market != models.AlertMarketSpot {
+		return nil, fmt.Errorf("validation failed: invalid market %q, use futures or spot", alert.Market)
+	}
+	if len(alert.Note) > maxAlertNoteLength {
+		return nil, fmt.Errorf("validation failed: note cannot exceed %d characters", maxAlertNoteLength)
+	}
+	if err := alert.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	for _, id := range req.WebhookIDs {
+		if !containsID(alert.WebhookIDs, id) {
+			alert.WebhookIDs = append(alert.WebhookIDs, id)
+		}
+	}
+	if len(alert.WebhookIDs) > maxAlertWebhooks {
+		return nil, fmt.Errorf("validation failed: an alert can notify at most %d webhooks", maxAlertWebhooks)
+	}
+	if len(alert.WebhookIDs) > 0 {
+		if s.checkWebhooks == nil {
+			return nil, fmt.Errorf("validation failed: webhooks are not available")
+		}
+		if err := s.checkWebhooks(ctx, userID, alert.WebhookIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	count, err := s.alertRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAlertsPerUser {
+		return nil, fmt.Errorf("validation failed: a user can have at most %d alerts", maxAlertsPerUser)
+	}
+
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	tracked := *alert
+	s.mu.Lock()
+	s.active[tracked.Symbol] = append(s.active[tracked.Symbol], &tracked)
+	s.mu.Unlock()
+
+	return alert, nil
+}
+
+// DeleteAlert removes a price alert owned by the user
+func (s *AlertService) DeleteAlert(ctx context.Context, userID string, id int64) error {
+	alert, err := s.ownedAlert(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.alertRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.untrack(alert.Symbol, id)
+	s.retired[id] = true
+	s.mu.Unlock()
+	return nil
+}
+
+// HandlePrice evaluates the alerts of a symbol against a last price change from the stream.
+// Runs on the stream's read loop: storing state and notifying happen off the evaluation lock
+func (s *AlertService) HandlePrice(tick models.PriceTick) {
+	type firing struct {
+		alert   models.PriceAlert
+		trigger *models.AlertTrigger
+	}
+	var changed []firing
+
+	s.mu.Lock()
+	for _, alert := range s.active[tick.Symbol] {
+		if alert.Market != tick.Market {
+			continue
+		}
+		state := alert.State
+		trigger := EvaluateAlert(alert.AlertCondition, &alert.AlertState, tick.Price, tick.Time)
+		if trigger != nil || alert.State != state {
+			changed = append(changed, firing{alert: *alert, trigger: trigger})
+		}
+	}
+	for _, change := range changed {
+		if change.alert.State == models.AlertStateTriggered {
+			s.untrack(change.alert.Symbol, change.alert.ID)
+			s.retired[change.alert.ID] = true
+		}
+	}
+	s.mu.Unlock()
+
+	for _, change := range changed {
+		if change.trigger != nil {
+			reached := s.hub.SendAlertTriggered(change.alert, *change.trigger)
+			log.Printf("[AlertService] Alert %d on %s fired at %.8g (%s), notified %d connections",
+				change.alert.ID, change.alert.Symbol, change.trigger.Price, change.trigger.Direction, reached)
+			if s.onTrigger != nil {
+				s.onTrigger(change.alert, *change.trigger)
+			}
+		}
+		select {
+		case s.writes <- change.alert:
+		default:
+			log.Printf("[AlertService] WARNING: write queue full, state of alert %d not stored", change.alert.ID)
+		}
+	}
+}
+
+// writer stores alert state changes in order until stopped
+func (s *AlertService) writer() {
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case alert := <-s.writes:
+			ctx, cancel := context.WithTimeout(context.Background(), alertWriteTimeout)
+			if err := s.alertRepo.SaveState(ctx, &alert); err != nil {
+				log.Printf("[AlertService] ERROR storing state of alert %d: %v", alert.ID, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// refreshLoop reloads the active alerts until stopped
+func (s *AlertService) refreshLoop() {
+	ticker := time.NewTicker(alertRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if err := s.refresh(); err != nil {
+				log.Printf("[AlertService] ERROR reloading alerts: %v", err)
+			}
+		}
+	}
+}
+
+// refresh replaces the tracked alerts with the active alerts stored, keeping the in-memory state
+// of alerts already tracked since it is at least as recent as the stored one. Alerts retired here
+// stay untracked until the store no longer lists them as active
+func (s *AlertService) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), alertWriteTimeout)
+	defer cancel()
+
+	stored, err := s.alertRepo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tracked := make(map[int64]*models.PriceAlert)
+	for _, alerts := range s.active {
+		for _, alert := range alerts {
+			tracked[alert.ID] = alert
+		}
+	}
+
+	active := make(map[string][]*models.PriceAlert)
+	retired := make(map[int64]bool)
+	for i := range stored {
+		alert := &stored[i]
+		if s.retired[alert.ID] {
+			retired[alert.ID] = true
+			continue
+		}
+		if existing, ok := tracked[alert.ID]; ok {
+			alert = existing
+		}
+		active[alert.Symbol] = append(active[alert.Symbol], alert)
+	}
+	s.active = active
+	s.retired = retired
+	return nil
+}
+
+// untrack stops evaluating an alert. Callers hold the lock
+func (s *AlertService) untrack(symbol string, id int64) {
+	alerts := s.active[symbol]
+	for i, alert := range alerts {
+		if alert.ID == id {
+			s.active[symbol] = append(alerts[:i:i], alerts[i+1:]...)
+			break
+		}
+	}
+	if len(s.active[symbol]) == 0 {
+		delete(s.active, symbol)
+	}
+}
+
+// withLiveState replaces a stored alert's state with the in-memory one, which may not be stored yet
+func (s *AlertService) withLiveState(alert *models.PriceAlert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tracked := range s.active[alert.Symbol] {
+		if tracked.ID == alert.ID {
+			alert.AlertState = tracked.AlertState
+			return
+		}
+	}
+}
+
+// ownedAlert loads an alert, treating alerts of other users as missing
+func (s *AlertService) ownedAlert(ctx context.Context, userID string, id int64) (*models.PriceAlert, error) {
+	alert, err := s.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert == nil || alert.UserID != userID {
+		return nil, fmt.Errorf("alert not found")
+	}
+	return alert, nil
+}
+
+// isStreamed reports whether the Binance stream carries a symbol
+func (s *AlertService) isStreamed(symbol string) bool {
+	for _, streamed := range s.stream.GetConnectedSymbols() {
+		if streamed == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,634 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// toxicityPercentileThreshold flags flow as toxic when current VPIN ranks at or above it
+const toxicityPercentileThreshold = 90.0
+
+// analyticsIntervals maps supported bar intervals to durations
+var analyticsIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+}
+
+// Support/resistance scoring weights (sum to 100)
+const (
+	levelSizeWeight        = 40.0
+	levelPersistenceWeight = 40.0
+	levelTouchWeight       = 20.0
+	// levelSizeRatioCap is the size ratio that earns the full size weight
+	levelSizeRatioCap = 5.0
+	// levelTouchCap is the number of held touches that earns the full touch weight
+	levelTouchCap = 10.0
+)
+
+// AnalyticsService computes quant analytics from persisted trades, order book snapshots and candles
+type AnalyticsService struct {
+	tradeRepo     *repositories.TradeRepository
+	depthRepo     *repositories.DepthSnapshotRepository
+	candleService *CandleService
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(tradeRepo *repositories.TradeRepository, depthRepo *repositories.DepthSnapshotRepository, candleService *CandleService) *AnalyticsService {
+	if tradeRepo == nil {
+		log.Fatalf("[AnalyticsService] CRITICAL: tradeRepo cannot be nil")
+	}
+	if depthRepo == nil {
+		log.Fatalf("[AnalyticsService] CRITICAL: depthRepo cannot be nil")
+	}
+	if candleService == nil {
+		log.Fatalf("[AnalyticsService] CRITICAL: candleService cannot be nil")
+	}
+	log.Printf("[AnalyticsService] Successfully initialized")
+	return &AnalyticsService{tradeRepo: tradeRepo, depthRepo: depthRepo, candleService: candleService}
+}
+
+// FlowToxicityParams configures a flow toxicity calculation
+type FlowToxicityParams struct {
+	Interval      string  // Bar interval (1m, 5m, 15m, 30m, 1h, 4h)
+	Bars          int     // Number of bars returned
+	VPINWindow    int     // Volume buckets averaged per VPIN value
+	RollingWindow int     // Bars summed for rolling OFI
+	BucketVolume  float64 // Volume per VPIN bucket; 0 derives it from the lookback volume
+}
+
+// GetFlowToxicity computes VPIN and order flow imbalance from persisted trades
+func (s *AnalyticsService) GetFlowToxicity(ctx context.Context, symbol string, params FlowToxicityParams) (*models.FlowToxicityResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	barDuration, ok := analyticsIntervals[params.Interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", params.Interval)
+	}
+	if params.Bars <= 0 {
+		params.Bars = 100
+	}
+	if params.VPINWindow <= 0 {
+		params.VPINWindow = 50
+	}
+	if params.RollingWindow <= 0 {
+		params.RollingWindow = 20
+	}
+
+	endTime := time.Now().Truncate(barDuration).Add(barDuration)
+	startTime := endTime.Add(-barDuration * time.Duration(params.Bars))
+
+	// One-second flow buckets keep the row count bounded while preserving ordering for VPIN
+	flow, err := s.tradeRepo.GetFlowBuckets(ctx, symbol, time.Second, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	if len(flow) == 0 {
+		return nil, fmt.Errorf("no persisted trades for %s in the requested window", symbol)
+	}
+
+	bucketVolume := params.BucketVolume
+	if bucketVolume <= 0 {
+		// Default to roughly one volume bucket per bar on average
+		var totalVolume float64
+		for _, f := range flow {
+			totalVolume += f.BuyVolume + f.SellVolume
+		}
+		bucketVolume = totalVolume / float64(params.Bars)
+	}
+	if bucketVolume <= 0 {
+		return nil, fmt.Errorf("no traded volume for %s in the requested window", symbol)
+	}
+
+	bars := make([]models.FlowToxicityBar, params.Bars)
+	for i := range bars {
+		bars[i].Time = startTime.Add(barDuration * time.Duration(i)).UnixMilli()
+	}
+
+	vpin := newVPINCalculator(bucketVolume, params.VPINWindow)
+	for _, f := range flow {
+		idx := int(f.Time.Sub(startTime) / barDuration)
+		if idx < 0 || idx >= len(bars) {
+			continue
+		}
+		bars[idx].BuyVolume += f.BuyVolume
+		bars[idx].SellVolume += f.SellVolume
+		vpin.add(f.BuyVolume, f.SellVolume)
+		bars[idx].VPIN = vpin.value()
+	}
+
+	// Carry VPIN through bars without trades and compute imbalances
+	rollingBuy := newRollingWindow(params.RollingWindow)
+	rollingSell := newRollingWindow(params.RollingWindow)
+	for i := range bars {
+		bar := &bars[i]
+		if i > 0 && bar.BuyVolume+bar.SellVolume == 0 {
+			bar.VPIN = bars[i-1].VPIN
+		}
+
+		bar.OFI = bar.BuyVolume - bar.SellVolume
+		if total := bar.BuyVolume + bar.SellVolume; total > 0 {
+			bar.OFIRatio = bar.OFI / total
+		}
+
+		rollingBuy.add(bar.BuyVolume)
+		rollingSell.add(bar.SellVolume)
+		if total := rollingBuy.total() + rollingSell.total(); total > 0 {
+			bar.RollingOFI = (rollingBuy.total() - rollingSell.total()) / total
+		}
+	}
+
+	current := bars[len(bars)-1].VPIN
+	percentile := vpinPercentile(bars, current)
+
+	return &models.FlowToxicityResponse{
+		Symbol:         symbol,
+		Interval:       params.Interval,
+		BucketVolume:   bucketVolume,
+		VPINWindow:     params.VPINWindow,
+		RollingWindow:  params.RollingWindow,
+		CurrentVPIN:    current,
+		VPINPercentile: percentile,
+		Toxic:          current > 0 && percentile >= toxicityPercentileThreshold,
+		Bars:           bars,
+		Count:          len(bars),
+		Timestamp:      time.Now().UnixMilli(),
+	}, nil
+}
+
+// rollingFields extracts the rolling window fields from a bar
+var rollingFields = map[string]func(models.OptimizedCandle) float64{
+	models.RollingFieldVolume: func(c models.OptimizedCandle) float64 { return c.V },
+	models.RollingFieldDelta:  func(c models.OptimizedCandle) float64 { return c.BV - c.SV },
+	models.RollingFieldRange:  func(c models.OptimizedCandle) float64 { return c.H - c.L },
+}
+
+// rollingStats reads a statistic from a full rolling window
+var rollingStats = map[string]func(*rollingWindow) float64{
+	models.RollingStatSum: (*rollingWindow).total,
+	models.RollingStatAvg: (*rollingWindow).avg,
+	models.RollingStatMax: (*rollingWindow).max,
+	models.RollingStatMin: (*rollingWindow).min,
+}
+
+// RollingWindowParams configures a rolling window query
+type RollingWindowParams struct {
+	Interval string   // Bar interval
+	Window   int      // Trailing bars per value
+	Limit    int      // Values returned
+	Fields   []string // Bar fields (volume, delta, range)
+	Stats    []string // Statistics per field (sum, avg, max, min)
+	Align    string   // models.RollingAlignClose or models.RollingAlignOpen
+}
+
+// GetRollingWindow computes trailing-window statistics of bar fields
+//
+// Every returned value covers a full window: window-1 extra bars are loaded ahead of the first
+// value. With close alignment the forming bar is left out and values are stamped at bar close,
+// so a value never changes once published
+func (s *AnalyticsService) GetRollingWindow(ctx context.Context, symbol string, params RollingWindowParams) (*models.RollingWindowResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	barDuration, ok := models.IntervalDuration(params.Interval)
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", params.Interval)
+	}
+	if params.Window < 2 {
+		return nil, fmt.Errorf("window must be at least 2 bars")
+	}
+	if params.Limit <= 0 {
+		params.Limit = 200
+	}
+	switch params.Align {
+	case "":
+		params.Align = models.RollingAlignClose
+	case models.RollingAlignClose, models.RollingAlignOpen:
+	default:
+		return nil, fmt.Errorf("align must be %s or %s", models.RollingAlignClose, models.RollingAlignOpen)
+	}
+	for _, field := range params.Fields {
+		if rollingFields[field] == nil {
+			return nil, fmt.Errorf("unsupported field: %s", field)
+		}
+	}
+	for _, stat := range params.Stats {
+		if rollingStats[stat] == nil {
+			return nil, fmt.Errorf("unsupported stat: %s", stat)
+		}
+	}
+	if len(params.Fields) == 0 || len(params.Stats) == 0 {
+		return nil, fmt.Errorf("at least one field and one stat are required")
+	}
+
+	// One extra bar covers the forming bar dropped by close alignment
+	response, err := s.candleService.GetOptimizedCandles(ctx, symbol, params.Interval, params.Limit+params.Window)
+	if err != nil {
+		return nil, err
+	}
+	bars := append([]models.OptimizedCandle(nil), response.D...)
+	sort.Slice(bars, func(i, j int) bool { return bars[i].T < bars[j].T })
+
+	offset := int64(0)
+	if params.Align == models.RollingAlignClose {
+		offset = barDuration.Milliseconds()
+		now := time.Now().UnixMilli()
+		for len(bars) > 0 && bars[len(bars)-1].T+offset > now {
+			bars = bars[:len(bars)-1]
+		}
+	}
+
+	windows := make(map[string]*rollingWindow, len(params.Fields))
+	for _, field := range params.Fields {
+		windows[field] = newRollingWindow(params.Window)
+	}
+	result := &models.RollingWindowResponse{
+		Symbol:   symbol,
+		Interval: params.Interval,
+		Window:   params.Window,
+		Align:    params.Align,
+		Series:   make(map[string][]float64, len(params.Fields)*len(params.Stats)),
+	}
+
+	for i, bar := range bars {
+		for _, field := range params.Fields {
+			windows[field].add(rollingFields[field](bar))
+		}
+		// Only the last limit full windows are returned
+		if !windows[params.Fields[0]].full() || len(bars)-i > params.Limit {
+			continue
+		}
+		result.Times = append(result.Times, bar.T+offset)
+		for _, field := range params.Fields {
+			for _, stat := range params.Stats {
+				key := field + "_" + stat
+				result.Series[key] = append(result.Series[key], rollingStats[stat](windows[field]))
+			}
+		}
+	}
+
+	result.Count = len(result.Times)
+	result.Timestamp = time.Now().UnixMilli()
+	return result, nil
+}
+
+// compareMaxSymbols caps the symbols of one comparison
+const compareMaxSymbols = 10
+
+// GetCompare rebases several symbols' stored closes to 100 so their performance over a range can
+// be overlaid on one chart
+//
+// The base is the first bar every symbol has, so no series is rebased at a different moment than
+// the others. Symbols without stored candles in the range are reported as missing rather than failing
+// the comparison
+func (s *AnalyticsService) GetCompare(ctx context.Context, symbols []string, interval string, startTime, endTime time.Time) (*models.CompareResponse, error) {
+	if len(symbols) < 2 {
+		return nil, fmt.Errorf("at least two symbols are required")
+	}
+	if len(symbols) > compareMaxSymbols {
+		return nil, fmt.Errorf("at most %d symbols can be compared", compareMaxSymbols)
+	}
+	if _, ok := models.IntervalDuration(interval); !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	seen := make(map[string]bool, len(symbols))
+	for i, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if seen[symbol] {
+			return nil, fmt.Errorf("duplicate symbol: %s", symbol)
+		}
+		seen[symbol] = true
+		symbols[i] = symbol
+	}
+
+	closes := make([]map[int64]float64, len(symbols))
+	errs := make([]error, len(symbols))
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			candles, err := s.candleService.GetCandleRange(ctx, symbol, interval, startTime, endTime)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			closes[i] = make(map[int64]float64, len(candles))
+			for _, candle := range candles {
+				if price := models.ParseFloat(candle.Close); price > 0 {
+					closes[i][candle.OpenTime.UnixMilli()] = price
+				}
+			}
+		}(i, symbol)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.CompareResponse{
+		Interval: interval,
+		Start:    startTime.UnixMilli(),
+		End:      endTime.UnixMilli(),
+		Series:   []models.CompareSeries{},
+		Times:    []int64{},
+	}
+
+	// The base time is the latest first bar among the symbols with data
+	var present []int
+	var times []int64
+	timeSet := make(map[int64]bool)
+	for i, symbolCloses := range closes {
+		if len(symbolCloses) == 0 {
+			result.Missing = append(result.Missing, symbols[i])
+			continue
+		}
+		present = append(present, i)
+		first := int64(math.MaxInt64)
+		for t := range symbolCloses {
+			if t < first {
+				first = t
+			}
+			if !timeSet[t] {
+				timeSet[t] = true
+				times = append(times, t)
+			}
+		}
+		if first > result.BaseTime {
+			result.BaseTime = first
+		}
+	}
+	if len(present) == 0 {
+		return nil, fmt.Errorf("no stored candles for any symbol in the range")
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for _, t := range times {
+		if t >= result.BaseTime {
+			result.Times = append(result.Times, t)
+		}
+	}
+
+	for _, i := range present {
+		series := models.CompareSeries{
+			Symbol: symbols[i],
+			Values: make([]float64, 0, len(result.Times)),
+		}
+		// A symbol missing the base bar is based on its previous close
+		for _, t := range times {
+			if t > result.BaseTime {
+				break
+			}
+			if price, ok := closes[i][t]; ok {
+				series.BasePrice = price
+			}
+		}
+		last := series.BasePrice
+		for _, t := range result.Times {
+			if price, ok := closes[i][t]; ok {
+				last = price
+			}
+			series.Values = append(series.Values, last/series.BasePrice*100)
+		}
+		series.LastPrice = last
+		series.ChangePct = (last/series.BasePrice - 1) * 100
+		result.Series = append(result.Series, series)
+	}
+
+	result.Count = len(result.Times)
+	result.Timestamp = time.Now().UnixMilli()
+	return result, nil
+}
+
+// SupportResistanceParams configures support/resistance detection
+type SupportResistanceParams struct {
+	Hours          int     // Lookback window of persisted book snapshots
+	BucketSize     float64 // Price bucket width; 0 derives it from the latest mid price
+	Limit          int     // Maximum levels returned
+	MinPersistence float64 // Minimum share of snapshots with liquidity at the level (0-1)
+	MinSizeRatio   float64 // Minimum average size relative to the median level on the same side
+}
+
+// GetSupportResistance detects price levels that repeatedly held large resting liquidity
+//
+// Persisted book levels are grouped into price buckets per side. A bucket qualifies when its
+// average resting size is well above the median bucket on that side and it was present in enough
+// snapshots. Strength combines relative size, persistence and touches (snapshots where the bucket
+// was the best bid/ask and its liquidity was still resting)
+func (s *AnalyticsService) GetSupportResistance(ctx context.Context, symbol string, params SupportResistanceParams) (*models.SupportResistanceResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	if params.Hours <= 0 {
+		params.Hours = 24
+	}
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+	if params.MinPersistence <= 0 {
+		params.MinPersistence = 0.25
+	}
+	if params.MinSizeRatio <= 0 {
+		params.MinSizeRatio = 2
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(params.Hours) * time.Hour)
+
+	snapshots, err := s.depthRepo.CountSnapshots(ctx, symbol, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	if snapshots == 0 {
+		return nil, fmt.Errorf("no persisted depth snapshots for %s in the requested window", symbol)
+	}
+
+	bucketSize := params.BucketSize
+	if bucketSize <= 0 {
+		mid, err := s.depthRepo.GetLatestMidPrice(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		bucketSize = defaultLevelBucket(mid)
+	}
+
+	stats, err := s.depthRepo.GetLevelStats(ctx, symbol, bucketSize, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	medians := map[string]float64{
+		models.DepthSideBid: medianLevelQuantity(stats, models.DepthSideBid),
+		models.DepthSideAsk: medianLevelQuantity(stats, models.DepthSideAsk),
+	}
+
+	levels := make([]models.SupportResistanceLevel, 0)
+	for _, stat := range stats {
+		median := medians[stat.Side]
+		if median <= 0 {
+			continue
+		}
+		persistence := float64(stat.Snapshots) / float64(snapshots)
+		sizeRatio := stat.AvgQuantity / median
+		if persistence < params.MinPersistence || sizeRatio < params.MinSizeRatio {
+			continue
+		}
+
+		strength := levelSizeWeight*math.Min(sizeRatio/levelSizeRatioCap, 1) +
+			levelPersistenceWeight*math.Min(persistence, 1) +
+			levelTouchWeight*math.Min(float64(stat.Touches)/levelTouchCap, 1)
+
+		levelType := models.LevelTypeSupport
+		if stat.Side == models.DepthSideAsk {
+			levelType = models.LevelTypeResistance
+		}
+
+		levels = append(levels, models.SupportResistanceLevel{
+			Price:       models.VolumeProfileBucket(stat.Price, bucketSize),
+			Type:        levelType,
+			Strength:    math.Round(strength*10) / 10,
+			Persistence: math.Round(persistence*1000) / 1000,
+			SizeRatio:   math.Round(sizeRatio*100) / 100,
+			AvgQuantity: stat.AvgQuantity,
+			MaxQuantity: stat.MaxQuantity,
+			Touches:     stat.Touches,
+			FirstSeen:   stat.FirstSeen.UnixMilli(),
+			LastSeen:    stat.LastSeen.UnixMilli(),
+		})
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Strength > levels[j].Strength
+	})
+	if len(levels) > params.Limit {
+		levels = levels[:params.Limit]
+	}
+
+	return &models.SupportResistanceResponse{
+		Symbol:     symbol,
+		Hours:      params.Hours,
+		BucketSize: bucketSize,
+		Snapshots:  snapshots,
+		Levels:     levels,
+		Count:      len(levels),
+		Timestamp:  time.Now().UnixMilli(),
+	}, nil
+}
+
+// defaultLevelBucket picks a round bucket width near 0.1% of price (e.g. 100 for BTC at 100k)
+func defaultLevelBucket(price float64) float64 {
+	if price <= 0 {
+		return 1
+	}
+	return math.Pow(10, math.Floor(math.Log10(price*0.001)))
+}
+
+// medianLevelQuantity returns the median average resting quantity of one side's buckets
+func medianLevelQuantity(stats []models.DepthLevelStats, side string) float64 {
+	quantities := make([]float64, 0, len(stats))
+	for _, stat := range stats {
+		if stat.Side == side {
+			quantities = append(quantities, stat.AvgQuantity)
+		}
+	}
+	if len(quantities) == 0 {
+		return 0
+	}
+
+	sort.Float64s(quantities)
+	middle := len(quantities) / 2
+	if len(quantities)%2 == 0 {
+		return (quantities[middle-1] + quantities[middle]) / 2
+	}
+	return quantities[middle]
+}
+
+// vpinCalculator fills equal-volume buckets and averages their buy/sell imbalance
+type vpinCalculator struct {
+	bucketVolume float64
+	window       int
+	currentBuy   float64
+	currentSell  float64
+	imbalances   []float64 // |buy - sell| of completed buckets (ring buffer)
+	next         int
+	filled       int
+	imbalanceSum float64
+}
+
+// newVPINCalculator creates a VPIN calculator
+func newVPINCalculator(bucketVolume float64, window int) *vpinCalculator {
+	return &vpinCalculator{
+		bucketVolume: bucketVolume,
+		window:       window,
+		imbalances:   make([]float64, window),
+	}
+}
+
+// add distributes volume into buckets, splitting it proportionally across bucket boundaries
+func (v *vpinCalculator) add(buyVolume, sellVolume float64) {
+	for buyVolume+sellVolume > 0 {
+		room := v.bucketVolume - v.currentBuy - v.currentSell
+		total := buyVolume + sellVolume
+		take := math.Min(room, total)
+		buyTake := buyVolume * take / total
+		sellTake := take - buyTake
+
+		v.currentBuy += buyTake
+		v.currentSell += sellTake
+		buyVolume -= buyTake
+		sellVolume -= sellTake
+
+		if v.currentBuy+v.currentSell >= v.bucketVolume*(1-1e-9) {
+			v.closeBucket()
+		}
+		if take <= 0 {
+			break
+		}
+	}
+}
+
+// closeBucket records the completed bucket's imbalance
+func (v *vpinCalculator) closeBucket() {
+	imbalance := math.Abs(v.currentBuy - v.currentSell)
+	v.imbalanceSum += imbalance - v.imbalances[v.next]
+	v.imbalances[v.next] = imbalance
+	v.next = (v.next + 1) % v.window
+	if v.filled < v.window {
+		v.filled++
+	}
+	v.currentBuy = 0
+	v.currentSell = 0
+}
+
+// value returns VPIN once the window is full, otherwise 0
+func (v *vpinCalculator) value() float64 {
+	if v.filled < v.window {
+		return 0
+	}
+	return v.imbalanceSum / (float64(v.window) * v.bucketVolume)
+}
+
+// vpinPercentile ranks a VPIN value among the non-zero VPIN values of the series
+func vpinPercentile(bars []models.FlowToxicityBar, current float64) float64 {
+	count, atOrBelow := 0, 0
+	for _, bar := range bars {
+		if bar.VPIN <= 0 {
+			continue
+		}
+		count++
+		if bar.VPIN <= current {
+			atOrBelow++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(atOrBelow) / float64(count) * 100
+}
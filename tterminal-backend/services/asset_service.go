@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// Asset limits
+const (
+	maxAssetInstruments = 50
+	maxAssetMetricHours = 720 // 30 days of 1h candles
+	assetMetricsTimeout = 10 * time.Second
+	assetMetricInterval = "1h"
+)
+
+// Errors returned when an asset or instrument cannot be found
+var (
+	ErrAssetNotFound           = errors.New("asset not found")
+	ErrAssetInstrumentNotFound = errors.New("asset instrument not found")
+)
+
+// AssetService manages assets: underlyings grouping their spot, perpetual, delivery and index
+// instruments across exchanges, so features can work per underlying instead of per symbol
+// Assets are kept in memory so symbol lookups resolve without a query
+type AssetService struct {
+	assetRepo     *repositories.AssetRepository
+	candleService *CandleService
+	lastPrice     func(symbol string) (float64, bool) // Live last price; nil without streams
+
+	mu       sync.RWMutex
+	assets   []models.Asset    // Ordered by code
+	bySymbol map[string]string // Symbol key of a traded instrument -> asset code
+}
+
+// NewAssetService creates a new asset service
+func NewAssetService(assetRepo *repositories.AssetRepository, candleService *CandleService, lastPrice func(symbol string) (float64, bool)) *AssetService {
+	if assetRepo == nil {
+		log.Fatalf("[AssetService] CRITICAL: assetRepo cannot be nil")
+	}
+	if candleService == nil {
+		log.Fatalf("[AssetService] CRITICAL: candleService cannot be nil")
+	}
+	log.Printf("[AssetService] Successfully initialized")
+	return &AssetService{
+		assetRepo:     assetRepo,
+		candleService: candleService,
+		lastPrice:     lastPrice,
+		bySymbol:      make(map[string]string),
+	}
+}
+
+// Start loads the stored assets
+func (s *AssetService) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.reload(ctx); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	log.Printf("[AssetService] Loaded %d assets", len(s.assets))
+	s.mu.RUnlock()
+	return nil
+}
+
+// reload replaces the in-memory assets with the stored ones
+func (s *AssetService) reload(ctx context.Context) error {
+	assets, err := s.assetRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	bySymbol := make(map[string]string)
+	for i := range assets {
+		for j := range assets[i].Instruments {
+			instrument := &assets[i].Instruments[j]
+			instrument.Symbol = models.QualifySymbol(instrument.Exchange, instrument.ExchangeSymbol)
+			if instrument.PriceType == models.PriceTypeLast {
+				bySymbol[instrument.Symbol] = instrument.Asset
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.assets = assets
+	s.bySymbol = bySymbol
+	s.mu.Unlock()
+	return nil
+}
+
+// AssetOf returns the code of the asset a symbol key is traded under
+func (s *AssetService) AssetOf(symbol string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	code, ok := s.bySymbol[strings.ToUpper(symbol)]
+	return code, ok
+}
+
+// GetAssets returns every asset with its instruments
+func (s *AssetService) GetAssets() []models.Asset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.Asset(nil), s.assets...)
+}
+
+// GetAsset returns an asset with its whole instrument family
+func (s *AssetService) GetAsset(code string) (*models.Asset, error) {
+	code = strings.ToUpper(code)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := range s.assets {
+		if s.assets[i].Code == code {
+			asset := s.assets[i]
+			return &asset, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAssetNotFound, code)
+}
+
+// SetAsset creates an asset or renames an existing one
+func (s *AssetService) SetAsset(ctx context.Context, code string, req *models.SetAssetRequest) (*models.Asset, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !models.IsValidAssetCode(code) {
+		return nil, fmt.Errorf("validation failed: asset code must be 1 to 16 letters and digits (e.g. BTC)")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 64 {
+		return nil, fmt.Errorf("validation failed: name is required and at most 64 characters")
+	}
+
+	if err := s.assetRepo.UpsertAsset(ctx, &models.Asset{Code: code, Name: name}); err != nil {
+		return nil, err
+	}
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetAsset(code)
+}
+
+// DeleteAsset removes an asset with its instruments
+func (s *AssetService) DeleteAsset(ctx context.Context, code string) error {
+	code = strings.ToUpper(code)
+	deleted, err := s.assetRepo.DeleteAsset(ctx, code)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrAssetNotFound, code)
+	}
+	return s.reload(ctx)
+}
+
+// SetInstrument adds an instrument to an asset, moving it from any asset it belonged to
+func (s *AssetService) SetInstrument(ctx context.Context, code string, req *models.SetAssetInstrumentRequest) (*models.AssetInstrument, error) {
+	asset, err := s.GetAsset(code)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	if !models.IsValidInstrumentKind(kind) {
+		return nil, fmt.Errorf("validation failed: kind must be one of %s", strings.Join(models.InstrumentKinds, ", "))
+	}
+	exchange := strings.ToLower(strings.TrimSpace(req.Exchange))
+	if exchange == "" {
+		exchange = models.ExchangeBinance
+	}
+	if !models.IsValidExchange(exchange) && exchange != models.ExchangeComposite {
+		return nil, fmt.Errorf("validation failed: exchange must be one of %s or %s", strings.Join(models.Exchanges, ", "), models.ExchangeComposite)
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if symbol == "" || len(symbol) > 32 || strings.ContainsAny(symbol, ":-") {
+		return nil, fmt.Errorf("validation failed: symbol must be the bare exchange symbol (e.g. BTCUSDT)")
+	}
+	priceType := strings.ToLower(strings.TrimSpace(req.PriceType))
+	if priceType == "" {
+		priceType = models.PriceTypeLast
+	}
+	if !models.IsValidPriceType(priceType) {
+		return nil, fmt.Errorf("validation failed: invalid price type %q", priceType)
+	}
+
+	// Index prices are index klines or composite symbols, and nothing else is an index
+	isIndexSource := priceType == models.PriceTypeIndex || exchange == models.ExchangeComposite
+	if (kind == models.InstrumentKindIndex) != isIndexSource {
+		return nil, fmt.Errorf("validation failed: index instruments, and only they, use the %s price type or the %s exchange", models.PriceTypeIndex, models.ExchangeComposite)
+	}
+	var expiresAt *time.Time
+	if !req.ExpiresAt.IsZero() {
+		if kind != models.InstrumentKindDelivery {
+			return nil, fmt.Errorf("validation failed: only delivery instruments expire")
+		}
+		expiry := req.ExpiresAt.UTC()
+		expiresAt = &expiry
+	}
+
+	instrument := &models.AssetInstrument{
+		Asset:          asset.Code,
+		Kind:           kind,
+		Exchange:       exchange,
+		ExchangeSymbol: symbol,
+		PriceType:      priceType,
+		ExpiresAt:      expiresAt,
+	}
+	existing := false
+	for _, current := range asset.Instruments {
+		if current.Exchange == exchange && current.ExchangeSymbol == symbol && current.PriceType == priceType {
+			existing = true
+		}
+	}
+	if !existing && len(asset.Instruments) >= maxAssetInstruments {
+		return nil, fmt.Errorf("validation failed: an asset has at most %d instruments", maxAssetInstruments)
+	}
+
+	if err := s.assetRepo.UpsertInstrument(ctx, instrument); err != nil {
+		return nil, err
+	}
+	instrument.Symbol = models.QualifySymbol(exchange, symbol)
+
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	return instrument, nil
+}
+
+// DeleteInstrument removes an instrument from an asset
+func (s *AssetService) DeleteInstrument(ctx context.Context, code string, id int64) error {
+	code = strings.ToUpper(code)
+	deleted, err := s.assetRepo.DeleteInstrument(ctx, code, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %d in %s", ErrAssetInstrumentNotFound, id, code)
+	}
+	return s.reload(ctx)
+}
+
+// instrumentWindow is an instrument's candles summed over the metrics window
+type instrumentWindow struct {
+	open        float64 // First bar's open
+	close       float64 // Last bar's close
+	volume      float64
+	quoteVolume float64
+}
+
+// GetMetrics aggregates an asset's instruments over the trailing hours from 1h candles: price,
+// change and volume per instrument, volume by kind and exchange, and basis over the index (or
+// the most traded spot). Instruments without candles are listed as missing
+func (s *AssetService) GetMetrics(ctx context.Context, code string, hours int) (*models.AssetMetrics, error) {
+	asset, err := s.GetAsset(code)
+	if err != nil {
+		return nil, err
+	}
+	if hours < 1 || hours > maxAssetMetricHours {
+		return nil, fmt.Errorf("validation failed: hours must be between 1 and %d", maxAssetMetricHours)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, assetMetricsTimeout)
+	defer cancel()
+
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(hours) * time.Hour)
+	windows := make([]*instrumentWindow, len(asset.Instruments))
+	var wg sync.WaitGroup
+	for i, instrument := range asset.Instruments {
+		wg.Add(1)
+		go func(i int, instrument models.AssetInstrument) {
+			defer wg.Done()
+			candles, err := s.candleService.GetCandleRangeByPriceType(ctx, instrument.Symbol, assetMetricInterval, instrument.PriceType, start, end)
+			if err != nil || len(candles) == 0 {
+				return
+			}
+			window := &instrumentWindow{
+				open:  models.ParseFloat(candles[0].Open),
+				close: models.ParseFloat(candles[len(candles)-1].Close),
+			}
+			for _, candle := range candles {
+				volume := models.ParseFloat(candle.Volume)
+				quoteVolume := models.ParseFloat(candle.QuoteAssetVolume)
+				if quoteVolume == 0 {
+					quoteVolume = volume * models.ParseFloat(candle.Close)
+				}
+				window.volume += volume
+				window.quoteVolume += quoteVolume
+			}
+			windows[i] = window
+		}(i, instrument)
+	}
+	wg.Wait()
+
+	metrics := &models.AssetMetrics{
+		Asset:            asset.Code,
+		Hours:            hours,
+		VolumeByKind:     make(map[string]float64),
+		VolumeByExchange: make(map[string]float64),
+		Instruments:      []models.AssetInstrumentMetrics{},
+		Timestamp:        time.Now().UnixMilli(),
+	}
+
+	// Per-instrument metrics, keeping the instrument order
+	var weighted, lowest, highest float64
+	for i, instrument := range asset.Instruments {
+		window := windows[i]
+		if window == nil {
+			metrics.Missing = append(metrics.Missing, instrumentLabel(instrument))
+			continue
+		}
+
+		price := window.close
+		if s.lastPrice != nil && instrument.PriceType == models.PriceTypeLast {
+			if live, ok := s.lastPrice(instrument.Symbol); ok && live > 0 {
+				price = live
+			}
+		}
+		m := models.AssetInstrumentMetrics{
+			ID:        instrument.ID,
+			Kind:      instrument.Kind,
+			Symbol:    instrument.Symbol,
+			PriceType: instrument.PriceType,
+			Price:     price,
+		}
+		if window.open > 0 {
+			m.ChangePct = (price - window.open) / window.open * 100
+		}
+		if instrument.Kind != models.InstrumentKindIndex {
+			m.Volume = window.volume
+			m.QuoteVolume = window.quoteVolume
+			metrics.QuoteVolume += window.quoteVolume
+			metrics.VolumeByKind[instrument.Kind] += window.quoteVolume
+			metrics.VolumeByExchange[instrument.Exchange] += window.quoteVolume
+			weighted += price * window.quoteVolume
+			if price > 0 {
+				if lowest == 0 || price < lowest {
+					lowest = price
+				}
+				highest = math.Max(highest, price)
+			}
+		}
+		metrics.Instruments = append(metrics.Instruments, m)
+	}
+	if metrics.QuoteVolume > 0 {
+		metrics.Price = weighted / metrics.QuoteVolume
+	}
+	if lowest > 0 {
+		metrics.PriceDispersionBps = (highest - lowest) / lowest * 10000
+	}
+
+	// The reference is the first index with a price, else the most traded spot
+	reference := -1
+	for i, m := range metrics.Instruments {
+		if m.Kind == models.InstrumentKindIndex && m.Price > 0 {
+			reference = i
+			break
+		}
+		if m.Kind == models.InstrumentKindSpot && m.Price > 0 && (reference < 0 || m.QuoteVolume > metrics.Instruments[reference].QuoteVolume) {
+			reference = i
+		}
+	}
+	if reference >= 0 {
+		ref := metrics.Instruments[reference]
+		metrics.ReferenceSymbol, metrics.ReferencePrice = ref.Symbol, ref.Price
+		var perpBasis float64
+		perps := 0
+		for i := range metrics.Instruments {
+			m := &metrics.Instruments[i]
+			if i == reference || m.Kind == models.InstrumentKindIndex || m.Price <= 0 {
+				continue
+			}
+			m.BasisBps = (m.Price - ref.Price) / ref.Price * 10000
+			if m.Kind == models.InstrumentKindPerp {
+				perpBasis += m.BasisBps
+				perps++
+			}
+		}
+		if perps > 0 {
+			metrics.AvgPerpBasisBps = perpBasis / float64(perps)
+		}
+	}
+	for i := range metrics.Instruments {
+		if metrics.QuoteVolume > 0 {
+			metrics.Instruments[i].VolumeSharePct = metrics.Instruments[i].QuoteVolume / metrics.QuoteVolume * 100
+		}
+	}
+
+	return metrics, nil
+}
+
+// instrumentLabel names an instrument in metrics, marking non-last price types ("BTCUSDT (index)")
+func instrumentLabel(instrument models.AssetInstrument) string {
+	if instrument.PriceType == models.PriceTypeLast {
+		return instrument.Symbol
+	}
+	return instrument.Symbol + " (" + instrument.PriceType + ")"
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxAuditLogEntries caps the audit log entries returned per request
+	maxAuditLogEntries = 500
+	// auditLogTimeout bounds recording an entry after its request completed
+	auditLogTimeout = 5 * time.Second
+)
+
+// AuditLogService records state-changing API requests and serves the audit log to admins
+type AuditLogService struct {
+	auditRepo *repositories.AuditLogRepository
+}
+
+// NewAuditLogService creates a new audit log service
+func NewAuditLogService(auditRepo *repositories.AuditLogRepository) *AuditLogService {
+	if auditRepo == nil {
+		log.Fatalf("[AuditLogService] CRITICAL: auditRepo cannot be nil")
+	}
+
+	log.Printf("[AuditLogService] Successfully initialized")
+	return &AuditLogService{auditRepo: auditRepo}
+}
+
+// Record stores an audited request. It runs after the response was written, so a failure is
+// logged rather than failing the request, and a client disconnecting does not cancel it
+func (s *AuditLogService) Record(ctx context.Context, entry *models.AuditLogEntry) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditLogTimeout)
+	defer cancel()
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[AuditLogService] ERROR recording %s %s by %s %q: %v", entry.Method, entry.Path, entry.ActorType, entry.Actor, err)
+	}
+}
+
+// Query returns the audit log entries matching a filter, newest first
+func (s *AuditLogService) Query(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLogEntry, error) {
+	if !filter.Start.IsZero() && !filter.End.IsZero() && !filter.Start.Before(filter.End) {
+		return nil, fmt.Errorf("validation failed: start must be before end")
+	}
+	filter.Method = strings.ToUpper(filter.Method)
+	switch filter.Method {
+	case "", http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("validation failed: method must be POST, PUT, PATCH or DELETE")
+	}
+	if filter.Limit <= 0 || filter.Limit > maxAuditLogEntries {
+		filter.Limit = maxAuditLogEntries
+	}
+
+	return s.auditRepo.Query(ctx, filter)
+}
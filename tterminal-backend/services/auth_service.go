@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+	"tterminal-backend/internal/auth"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// minPasswordLength is the shortest password accepted at registration
+	minPasswordLength = 8
+	// maxPasswordLength is bcrypt's input limit; longer passwords would be silently truncated
+	maxPasswordLength = 72
+	// tokenIssuer names this service in the iss claim of access tokens
+	tokenIssuer = "tterminal"
+	// maxUsersPerPage caps the users listed per request
+	maxUsersPerPage = 500
+)
+
+// Authentication failures
+var (
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrUserNotFound       = errors.New("user not found")
+)
+
+// AuthService registers user accounts and exchanges their credentials for HS256 JWT access tokens
+// Tokens are stateless: they stay valid until they expire, and rotating the secret revokes all of them
+type AuthService struct {
+	userRepo *repositories.UserRepository
+	secret   []byte
+	tokenTTL time.Duration
+
+	// dummyHash is compared against on logins of unknown emails, so they take as long as wrong passwords
+	dummyHash []byte
+}
+
+// NewAuthService creates a new auth service signing tokens valid for tokenTTL with secret
+func NewAuthService(userRepo *repositories.UserRepository, secret string, tokenTTL time.Duration) *AuthService {
+	if userRepo == nil {
+		log.Fatalf("[AuthService] CRITICAL: userRepo cannot be nil")
+	}
+	if secret == "" {
+		log.Fatalf("[AuthService] CRITICAL: secret cannot be empty")
+	}
+
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatalf("[AuthService] CRITICAL: failed to hash dummy password: %v", err)
+	}
+
+	log.Printf("[AuthService] Successfully initialized")
+	return &AuthService{
+		userRepo:  userRepo,
+		secret:    []byte(secret),
+		tokenTTL:  tokenTTL,
+		dummyHash: dummyHash,
+	}
+}
+
+// Register creates an account and returns an access token for it
+func (s *AuthService) Register(ctx context.Context, req *models.CredentialsRequest) (*models.AuthResponse, error) {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Password) < minPasswordLength {
+		return nil, fmt.Errorf("validation failed: password must be at least %d characters", minPasswordLength)
+	}
+	if len(req.Password) > maxPasswordLength {
+		return nil, fmt.Errorf("validation failed: password must be at most %d bytes", maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		ID:           uuid.New().String(),
+		Email:        email,
+		PasswordHash: string(hash),
+		Role:         models.RoleUser,
+	}
+	created, err := s.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrEmailTaken
+	}
+
+	log.Printf("[AuthService] Registered user %s", user.ID)
+	return s.issueToken(user)
+}
+
+// Login checks an account's credentials and returns a new access token
+func (s *AuthService) Login(ctx context.Context, req *models.CredentialsRequest) (*models.AuthResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(req.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	if err := s.userRepo.RecordLogin(ctx, user.ID, now); err != nil {
+		log.Printf("[AuthService] %v", err)
+	} else {
+		user.LastLoginAt = &now
+	}
+	return s.issueToken(user)
+}
+
+// GetUser retrieves the account of an authenticated user
+func (s *AuthService) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// ListUsers returns accounts in registration order, for administration
+func (s *AuthService) ListUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
+	if limit <= 0 || limit > maxUsersPerPage {
+		limit = maxUsersPerPage
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.userRepo.List(ctx, limit, offset)
+}
+
+// SetRole changes a user's role; tokens issued before keep the previous role until they expire
+func (s *AuthService) SetRole(ctx context.Context, userID, role string) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, fmt.Errorf("validation failed: role must be %s, %s or %s", models.RoleAdmin, models.RoleUser, models.RoleReadonly)
+	}
+
+	updated, err := s.userRepo.SetRole(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrUserNotFound
+	}
+
+	log.Printf("[AuthService] Set role of user %s to %s", userID, role)
+	return s.GetUser(ctx, userID)
+}
+
+// SetPermissions changes the permissions a user holds as an admin; nil restores every one
+// Tokens issued before keep the previous permissions until they expire
+func (s *AuthService) SetPermissions(ctx context.Context, userID string, permissions []string) (*models.User, error) {
+	if permissions != nil {
+		seen := make(map[string]bool, len(permissions))
+		unique := make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			if !models.IsValidPermission(permission) {
+				return nil, fmt.Errorf("validation failed: unknown permission %q, must be one of %s", permission, strings.Join(models.Permissions, ", "))
+			}
+			if !seen[permission] {
+				seen[permission] = true
+				unique = append(unique, permission)
+			}
+		}
+		permissions = unique
+	}
+
+	updated, err := s.userRepo.SetPermissions(ctx, userID, permissions)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrUserNotFound
+	}
+
+	log.Printf("[AuthService] Set permissions of user %s to %v", userID, permissions)
+	return s.GetUser(ctx, userID)
+}
+
+// issueToken signs an access token for a user
+func (s *AuthService) issueToken(user *models.User) (*models.AuthResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.tokenTTL)
+	token, err := auth.Sign(auth.Claims{
+		Subject:     user.ID,
+		Email:       user.Email,
+		Role:        user.Role,
+		Permissions: user.Permissions,
+		Issuer:      tokenIssuer,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   expiresAt.Unix(),
+	}, s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return &models.AuthResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: time.Unix(expiresAt.Unix(), 0).UTC(),
+		User:      *user,
+	}, nil
+}
+
+// normalizeEmail validates an email address and lower-cases it
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", fmt.Errorf("validation failed: email is required")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 255 {
+		return "", fmt.Errorf("validation failed: email is not a valid address")
+	}
+	return email, nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"tterminal-backend/internal/backtest"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/models"
+)
+
+// Resource limits of a backtest; the candle range is also capped at models.MaxRangeCandles candles
+const (
+	backtestTimeout       = 30 * time.Second
+	backtestMaxTrades     = 500000
+	backtestDefaultEquity = 10000
+	backtestMaxSlippage   = 1000 // Basis points
+)
+
+// ErrBacktestTimeout is returned when a backtest exceeds its time limit
+var ErrBacktestTimeout = errors.New("backtest exceeded its time limit")
+
+// BacktestService replays stored candles and trades through built-in strategies
+type BacktestService struct {
+	candleService *CandleService
+	tradeStore    marketdata.TradeStore
+	fees          models.FeeSchedule
+}
+
+// NewBacktestService creates a new backtest service; fees.TakerRate is the default fee rate
+func NewBacktestService(candleService *CandleService, tradeStore marketdata.TradeStore, fees models.FeeSchedule) *BacktestService {
+	if candleService == nil {
+		log.Fatalf("[BacktestService] CRITICAL: candleService cannot be nil")
+	}
+	if isNil(tradeStore) {
+		log.Fatalf("[BacktestService] CRITICAL: tradeStore cannot be nil")
+	}
+
+	return &BacktestService{
+		candleService: candleService,
+		tradeStore:    tradeStore,
+		fees:          fees,
+	}
+}
+
+// Strategies describes the built-in strategies
+func (s *BacktestService) Strategies() []models.BacktestStrategy {
+	return backtest.Strategies()
+}
+
+// Run validates a backtest request, loads its candles (and trades under the trades replay) and
+// simulates the strategy over them
+func (s *BacktestService) Run(ctx context.Context, req *models.BacktestRequest) (*models.BacktestResult, error) {
+	started := time.Now()
+	cfg, err := s.normalizeBacktest(req)
+	if err != nil {
+		return nil, err
+	}
+	strategy, params, err := backtest.NewStrategy(req.Strategy, req.Params)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, ok := strategy.(backtest.TradeStrategy); ok && req.Replay != models.BacktestReplayTrades {
+		return nil, fmt.Errorf("validation failed: strategy %s decides on trades and needs the %s replay", req.Strategy, models.BacktestReplayTrades)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backtestTimeout)
+	defer cancel()
+
+	candles, err := s.candleService.GetCandleRange(ctx, req.Symbol, req.Interval, req.Start.Time, req.End.Time)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrBacktestTimeout
+		}
+		if strings.HasPrefix(err.Error(), "time range too large") {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("validation failed: no %s %s candles between start and end", req.Symbol, req.Interval)
+	}
+	bars := make([]backtest.Bar, len(candles))
+	for i, candle := range candles {
+		bars[i] = backtest.Bar{
+			Time:      candle.OpenTime,
+			CloseTime: candle.CloseTime,
+			Open:      models.ParseFloat(candle.Open),
+			High:      models.ParseFloat(candle.High),
+			Low:       models.ParseFloat(candle.Low),
+			Close:     models.ParseFloat(candle.Close),
+			Volume:    models.ParseFloat(candle.Volume),
+			BuyVolume: models.ParseFloat(candle.TakerBuyBaseAssetVolume),
+		}
+	}
+
+	var trades []backtest.Trade
+	if req.Replay == models.BacktestReplayTrades {
+		records, err := s.tradeStore.GetByTimeRange(ctx, req.Symbol, candles[0].OpenTime, candles[len(candles)-1].CloseTime, backtestMaxTrades+1)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrBacktestTimeout
+			}
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("validation failed: no stored %s trades between start and end", req.Symbol)
+		}
+		if len(records) > backtestMaxTrades {
+			return nil, fmt.Errorf("validation failed: more than %d trades in range, narrow it or use the %s replay", backtestMaxTrades, models.BacktestReplayCandles)
+		}
+		trades = make([]backtest.Trade, len(records))
+		for i, record := range records {
+			trades[i] = backtest.Trade{
+				Time:     record.TradeTime,
+				Price:    record.Price,
+				Quantity: record.Quantity,
+				Buy:      !record.IsBuyerMaker,
+			}
+		}
+	}
+
+	result, err := backtest.Run(ctx, strategy, bars, trades, cfg)
+	if err != nil {
+		return nil, ErrBacktestTimeout
+	}
+
+	return &models.BacktestResult{
+		Symbol:         req.Symbol,
+		Interval:       req.Interval,
+		Strategy:       req.Strategy,
+		Params:         params,
+		Replay:         req.Replay,
+		Start:          req.Start.UnixMilli(),
+		End:            req.End.UnixMilli(),
+		Bars:           len(bars),
+		TradesReplayed: len(trades),
+		Summary:        result.Summary,
+		EquityCurve:    result.EquityCurve,
+		Trades:         result.Trades,
+		ElapsedMs:      time.Since(started).Milliseconds(),
+	}, nil
+}
+
+// normalizeBacktest validates a backtest request, fills its defaults and returns the account settings
+func (s *BacktestService) normalizeBacktest(req *models.BacktestRequest) (backtest.Config, error) {
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if req.Symbol == "" {
+		return backtest.Config{}, fmt.Errorf("validation failed: symbol is required")
+	}
+	if !models.IsValidInterval(req.Interval) {
+		return backtest.Config{}, fmt.Errorf("validation failed: interval must be one of %s", strings.Join(models.SupportedIntervalNames(), ", "))
+	}
+	req.Strategy = strings.ToLower(strings.TrimSpace(req.Strategy))
+	if req.Strategy == "" {
+		return backtest.Config{}, fmt.Errorf("validation failed: strategy is required")
+	}
+
+	if req.Start.IsZero() {
+		return backtest.Config{}, fmt.Errorf("validation failed: start is required")
+	}
+	if req.End.IsZero() {
+		req.End.Time = time.Now().UTC()
+	}
+	if !req.Start.Before(req.End.Time) {
+		return backtest.Config{}, fmt.Errorf("validation failed: start must be before end")
+	}
+
+	switch req.Replay {
+	case "":
+		req.Replay = models.BacktestReplayCandles
+	case models.BacktestReplayCandles, models.BacktestReplayTrades:
+	default:
+		return backtest.Config{}, fmt.Errorf("validation failed: replay must be %s or %s", models.BacktestReplayCandles, models.BacktestReplayTrades)
+	}
+
+	if req.InitialEquity == 0 {
+		req.InitialEquity = backtestDefaultEquity
+	}
+	if req.InitialEquity < 0 || math.IsInf(req.InitialEquity, 0) {
+		return backtest.Config{}, fmt.Errorf("validation failed: initial_equity must be positive")
+	}
+	if req.PositionSize == 0 {
+		req.PositionSize = 1
+	}
+	if req.PositionSize < 0 || req.PositionSize > 1 {
+		return backtest.Config{}, fmt.Errorf("validation failed: position_size must be between 0 and 1")
+	}
+	feeRate := s.fees.TakerRate
+	if req.FeeRate != nil {
+		feeRate = *req.FeeRate
+	}
+	if feeRate < 0 || feeRate >= 1 {
+		return backtest.Config{}, fmt.Errorf("validation failed: fee_rate must be at least 0 and below 1")
+	}
+	if req.SlippageBps < 0 || req.SlippageBps > backtestMaxSlippage {
+		return backtest.Config{}, fmt.Errorf("validation failed: slippage_bps must be between 0 and %d", backtestMaxSlippage)
+	}
+	allowShort := true
+	if req.AllowShort != nil {
+		allowShort = *req.AllowShort
+	}
+
+	return backtest.Config{
+		InitialEquity: req.InitialEquity,
+		PositionSize:  req.PositionSize,
+		FeeRate:       feeRate,
+		SlippageBps:   req.SlippageBps,
+		AllowShort:    allowShort,
+	}, nil
+}
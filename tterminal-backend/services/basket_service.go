@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// maxBasketFootprintBars caps the bars of a basket footprint request
+const maxBasketFootprintBars = 1000
+
+// memberFlow is one member's notional delta and volume in a bar
+type memberFlow struct {
+	delta  float64
+	volume float64
+}
+
+// basketBar holds the member flows of one bar
+type basketBar struct {
+	openTime int64
+	members  map[string]memberFlow
+}
+
+// basketSeries is a computed footprint series kept current by bar closes
+type basketSeries struct {
+	basket   models.SymbolBasket
+	interval string
+	duration time.Duration
+	limit    int         // Bars the series was built with and is trimmed to
+	bars     []basketBar // Oldest first
+}
+
+// current reports whether no bar after the series' latest one should have closed yet
+// Intervals without bar closes go stale one bar after they were built and are rebuilt on request
+func (s *basketSeries) current(now time.Time) bool {
+	if len(s.bars) == 0 {
+		return false
+	}
+	latest := time.UnixMilli(s.bars[len(s.bars)-1].openTime)
+	return now.Before(latest.Add(2 * s.duration))
+}
+
+// BasketService manages symbol baskets and computes their combined delta (basket footprint)
+// Member deltas are converted to quote notional so members of different prices add up. Series
+// are built from stored candles on request and then updated as member bars close
+type BasketService struct {
+	basketRepo    *repositories.BasketRepository
+	candleService *CandleService
+
+	mu     sync.Mutex
+	series map[string]*basketSeries // Keyed by "basketID:interval"
+}
+
+// NewBasketService creates a new basket service
+func NewBasketService(basketRepo *repositories.BasketRepository, candleService *CandleService) *BasketService {
+	if basketRepo == nil {
+		log.Fatalf("[BasketService] CRITICAL: basketRepo cannot be nil")
+	}
+	if candleService == nil {
+		log.Fatalf("[BasketService] CRITICAL: candleService cannot be nil")
+	}
+	log.Printf("[BasketService] Successfully initialized")
+	return &BasketService{
+		basketRepo:    basketRepo,
+		candleService: candleService,
+		series:        make(map[string]*basketSeries),
+	}
+}
+
+// CreateBasket creates a new basket for a user
+func (s *BasketService) CreateBasket(ctx context.Context, userID string, req *models.CreateBasketRequest) (*models.SymbolBasket, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("validation failed: name is required")
+	}
+	symbols, err := normalizeBasketSymbols(req.Symbols)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	basket := &models.SymbolBasket{
+		UserID:  userID,
+		Name:    name,
+		Symbols: symbols,
+	}
+	if err := s.basketRepo.Create(ctx, basket); err != nil {
+		return nil, err
+	}
+
+	return basket, nil
+}
+
+// GetBaskets returns all baskets of a user
+func (s *BasketService) GetBaskets(ctx context.Context, userID string) ([]models.SymbolBasket, error) {
+	return s.basketRepo.GetByUser(ctx, userID)
+}
+
+// GetBasket returns a basket owned by the user
+func (s *BasketService) GetBasket(ctx context.Context, userID string, id int64) (*models.SymbolBasket, error) {
+	basket, err := s.basketRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Baskets of other users are reported as missing
+	if basket == nil || basket.UserID != userID {
+		return nil, fmt.Errorf("basket not found")
+	}
+
+	return basket, nil
+}
+
+// UpdateBasket renames a basket or replaces its members
+func (s *BasketService) UpdateBasket(ctx context.Context, userID string, id int64, req *models.UpdateBasketRequest) (*models.SymbolBasket, error) {
+	basket, err := s.GetBasket(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		basket.Name = name
+	}
+	if len(req.Symbols) > 0 {
+		symbols, err := normalizeBasketSymbols(req.Symbols)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		basket.Symbols = symbols
+	}
+
+	if err := s.basketRepo.Update(ctx, basket); err != nil {
+		return nil, err
+	}
+	s.dropSeries(id)
+
+	return basket, nil
+}
+
+// DeleteBasket deletes a basket
+func (s *BasketService) DeleteBasket(ctx context.Context, userID string, id int64) error {
+	if _, err := s.GetBasket(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.basketRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.dropSeries(id)
+	return nil
+}
+
+// GetBasketFootprint returns the combined delta series of a basket's last limit bars
+func (s *BasketService) GetBasketFootprint(ctx context.Context, userID string, id int64, interval string, limit int) (*models.BasketFootprintResponse, error) {
+	if !models.IsValidInterval(interval) {
+		return nil, fmt.Errorf("validation failed: unsupported interval %s", interval)
+	}
+	if limit <= 0 || limit > maxBasketFootprintBars {
+		return nil, fmt.Errorf("validation failed: limit must be between 1 and %d", maxBasketFootprintBars)
+	}
+
+	basket, err := s.GetBasket(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%d:%s", id, interval)
+	s.mu.Lock()
+	series, exists := s.series[key]
+	if exists && (series.limit < limit || !series.current(time.Now())) {
+		exists = false
+	}
+	var response *models.BasketFootprintResponse
+	if exists {
+		response = series.response(limit)
+	}
+	s.mu.Unlock()
+	if response != nil {
+		return response, nil
+	}
+
+	series, err = s.buildSeries(ctx, *basket, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series[key] = series
+	return series.response(limit), nil
+}
+
+// HandleBarClose adds a closed member bar to the cached series of every basket containing it
+func (s *BasketService) HandleBarClose(bar models.BarClose) {
+	if models.SymbolExchange(bar.Symbol) != models.ExchangeBinance {
+		return
+	}
+	flow := candleFlow(bar.Candle)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, series := range s.series {
+		if series.interval != bar.Interval || !series.basket.HasSymbol(bar.Symbol) {
+			continue
+		}
+		series.add(bar.Symbol, bar.OpenTime, flow)
+	}
+}
+
+// buildSeries loads the last limit candles of every member and combines them by open time
+func (s *BasketService) buildSeries(ctx context.Context, basket models.SymbolBasket, interval string, limit int) (*basketSeries, error) {
+	duration, _ := models.IntervalDuration(interval)
+	series := &basketSeries{
+		basket:   basket,
+		interval: interval,
+		duration: duration,
+		limit:    limit,
+	}
+
+	for _, symbol := range basket.Symbols {
+		candles, err := s.candleService.GetBySymbolAndInterval(ctx, symbol, interval, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s candles: %w", symbol, err)
+		}
+		for _, candle := range candles {
+			series.add(symbol, candle.OpenTime.UnixMilli(), candleFlow(candle))
+		}
+	}
+
+	return series, nil
+}
+
+// dropSeries discards the cached series of a basket after it changed
+func (s *BasketService) dropSeries(id int64) {
+	prefix := fmt.Sprintf("%d:", id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.series {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.series, key)
+		}
+	}
+}
+
+// add records a member's flow in the bar opening at openTime, keeping bars in order and
+// trimming the oldest beyond the series limit
+func (s *basketSeries) add(symbol string, openTime int64, flow memberFlow) {
+	i := sort.Search(len(s.bars), func(i int) bool {
+		return s.bars[i].openTime >= openTime
+	})
+	if i == len(s.bars) || s.bars[i].openTime != openTime {
+		s.bars = append(s.bars, basketBar{})
+		copy(s.bars[i+1:], s.bars[i:])
+		s.bars[i] = basketBar{openTime: openTime, members: make(map[string]memberFlow)}
+	}
+	s.bars[i].members[symbol] = flow
+
+	if len(s.bars) > s.limit {
+		s.bars = s.bars[len(s.bars)-s.limit:]
+	}
+}
+
+// response combines the member flows of the last limit bars, with cumulative delta from the first
+func (s *basketSeries) response(limit int) *models.BasketFootprintResponse {
+	bars := s.bars
+	if len(bars) > limit {
+		bars = bars[len(bars)-limit:]
+	}
+
+	result := make([]models.BasketFootprintBar, 0, len(bars))
+	cumulative := 0.0
+	for _, bar := range bars {
+		combined := models.BasketFootprintBar{
+			T: bar.openTime,
+			M: make(map[string]float64, len(bar.members)),
+		}
+		for symbol, flow := range bar.members {
+			combined.D += flow.delta
+			combined.V += flow.volume
+			if flow.volume > 0 {
+				combined.M[symbol] = flow.delta / flow.volume
+			}
+		}
+		if combined.V > 0 {
+			combined.R = combined.D / combined.V
+		}
+		cumulative += combined.D
+		combined.CD = cumulative
+		result = append(result, combined)
+	}
+
+	return &models.BasketFootprintResponse{
+		Basket:   s.basket,
+		Interval: s.interval,
+		Count:    len(result),
+		Bars:     result,
+	}
+}
+
+// candleFlow returns a candle's notional delta (taker buy minus taker sell quote volume) and volume
+func candleFlow(candle models.Candle) memberFlow {
+	volume := models.ParseFloat(candle.QuoteAssetVolume)
+	buy := models.ParseFloat(candle.TakerBuyQuoteAssetVolume)
+	return memberFlow{delta: 2*buy - volume, volume: volume}
+}
+
+// normalizeBasketSymbols upper-cases and de-duplicates basket members
+// Only Binance symbols carry the taker buy volume a delta is computed from
+func normalizeBasketSymbols(symbols []string) ([]string, error) {
+	seen := make(map[string]bool, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		if models.SymbolExchange(symbol) != models.ExchangeBinance {
+			return nil, fmt.Errorf("%s is not a Binance symbol; other exchanges report no taker buy volume", symbol)
+		}
+		seen[symbol] = true
+		result = append(result, symbol)
+	}
+
+	if len(result) < 2 || len(result) > models.MaxBasketSymbols {
+		return nil, fmt.Errorf("a basket needs between 2 and %d symbols", models.MaxBasketSymbols)
+	}
+	return result, nil
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/models"
+)
+
+// binanceOptionsBoardCacheTTL controls how long an options board snapshot is reused
+const binanceOptionsBoardCacheTTL = 15 * time.Second
+
+// Errors returned when Binance options data cannot be served
+var (
+	ErrBinanceOptionsDisabled   = errors.New("Binance options data is disabled")
+	ErrBinanceOptionsUnderlying = errors.New("underlying is not an enabled Binance options underlying")
+)
+
+// BinanceOptionsService serves Binance European options boards: tickers, mark prices, greeks
+// and open interest grouped by expiry and strike
+type BinanceOptionsService struct {
+	binanceClient *binance.Client
+	underlyings   map[string]bool
+	boards        map[string]*models.BinanceOptionsBoard
+	cacheExpiry   map[string]time.Time
+	cacheMutex    sync.RWMutex
+}
+
+// NewBinanceOptionsService creates a new Binance options service; a nil client disables options data
+func NewBinanceOptionsService(binanceClient *binance.Client, underlyings []string) *BinanceOptionsService {
+	if binanceClient == nil {
+		log.Printf("[BinanceOptionsService] BINANCE_OPTIONS_ENABLED is false - Binance options endpoints will return 503")
+	}
+
+	enabled := make(map[string]bool, len(underlyings))
+	for _, underlying := range underlyings {
+		enabled[strings.ToUpper(underlying)] = true
+	}
+
+	log.Printf("[BinanceOptionsService] Successfully initialized")
+	return &BinanceOptionsService{
+		binanceClient: binanceClient,
+		underlyings:   enabled,
+		boards:        make(map[string]*models.BinanceOptionsBoard),
+		cacheExpiry:   make(map[string]time.Time),
+	}
+}
+
+// GetBoard returns the options board of an underlying, limited to one expiry when expiry is set
+func (s *BinanceOptionsService) GetBoard(ctx context.Context, underlying, expiry string) (*models.BinanceOptionsBoard, error) {
+	board, err := s.board(ctx, underlying)
+	if err != nil {
+		return nil, err
+	}
+	if expiry == "" {
+		return board, nil
+	}
+
+	for _, entry := range board.Expiries {
+		if entry.Label == expiry {
+			filtered := *board
+			filtered.Expiries = []models.BinanceOptionExpiry{entry}
+			return &filtered, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrOptionsExpiryNotFound, expiry)
+}
+
+// GetExpiries returns the summary of every expiry of an underlying, without strikes
+func (s *BinanceOptionsService) GetExpiries(ctx context.Context, underlying string) (*models.BinanceOptionsBoard, error) {
+	board, err := s.board(ctx, underlying)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := *board
+	summary.Expiries = make([]models.BinanceOptionExpiry, len(board.Expiries))
+	for i, entry := range board.Expiries {
+		entry.Strikes = nil
+		summary.Expiries[i] = entry
+	}
+	return &summary, nil
+}
+
+// Underlyings returns the enabled option underlyings, sorted
+func (s *BinanceOptionsService) Underlyings() []string {
+	underlyings := make([]string, 0, len(s.underlyings))
+	for underlying := range s.underlyings {
+		underlyings = append(underlyings, underlying)
+	}
+	sort.Strings(underlyings)
+	return underlyings
+}
+
+// board returns the cached board of an underlying, fetching a new snapshot once it expires
+func (s *BinanceOptionsService) board(ctx context.Context, underlying string) (*models.BinanceOptionsBoard, error) {
+	underlying, err := s.checkUnderlying(underlying)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMutex.RLock()
+	cached, exists := s.boards[underlying]
+	fresh := exists && time.Now().Before(s.cacheExpiry[underlying])
+	s.cacheMutex.RUnlock()
+	if fresh {
+		return cached, nil
+	}
+
+	quotes, err := s.fetchQuotes(ctx, underlying)
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.binanceClient.GetOptionIndex(ctx, underlying+"USDT")
+	if err != nil {
+		return nil, err
+	}
+	board := buildBinanceOptionsBoard(underlying, parseOptionFloat(index.IndexPrice), quotes, time.Now())
+
+	s.cacheMutex.Lock()
+	s.boards[underlying] = board
+	s.cacheExpiry[underlying] = time.Now().Add(binanceOptionsBoardCacheTTL)
+	s.cacheMutex.Unlock()
+
+	return board, nil
+}
+
+// fetchQuotes merges the tickers, marks and open interest of an underlying's contracts
+// Marks list every contract, so they decide which contracts are quoted
+func (s *BinanceOptionsService) fetchQuotes(ctx context.Context, underlying string) ([]models.BinanceOptionQuote, error) {
+	marks, err := s.binanceClient.GetOptionMarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tickers, err := s.binanceClient.GetOptionTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tickersBySymbol := make(map[string]*binance.OptionTicker, len(tickers))
+	for i := range tickers {
+		tickersBySymbol[tickers[i].Symbol] = &tickers[i]
+	}
+
+	quotes := make([]models.BinanceOptionQuote, 0, len(marks))
+	expirations := make(map[string]bool)
+	for _, mark := range marks {
+		contract, err := binance.ParseOptionSymbol(mark.Symbol)
+		if err != nil || contract.Underlying != underlying {
+			continue
+		}
+
+		quote := models.BinanceOptionQuote{
+			Symbol:    mark.Symbol,
+			Type:      models.OptionTypePut,
+			Strike:    contract.Strike,
+			Expiry:    contract.Expiry,
+			MarkPrice: parseOptionFloat(mark.MarkPrice),
+			MarkIV:    parseOptionFloat(mark.MarkIV) * 100,
+			BidIV:     parseOptionFloat(mark.BidIV) * 100,
+			AskIV:     parseOptionFloat(mark.AskIV) * 100,
+			Delta:     parseOptionFloat(mark.Delta),
+			Gamma:     parseOptionFloat(mark.Gamma),
+			Theta:     parseOptionFloat(mark.Theta),
+			Vega:      parseOptionFloat(mark.Vega),
+		}
+		if contract.Call {
+			quote.Type = models.OptionTypeCall
+		}
+		if ticker, ok := tickersBySymbol[mark.Symbol]; ok {
+			quote.LastPrice = parseOptionFloat(ticker.LastPrice)
+			quote.BidPrice = parseOptionFloat(ticker.BidPrice)
+			quote.AskPrice = parseOptionFloat(ticker.AskPrice)
+			quote.Volume = parseOptionFloat(ticker.Volume)
+		}
+		quotes = append(quotes, quote)
+		expirations[contract.ExpiryLabel] = true
+	}
+
+	// Open interest is served per expiry; a failed expiry leaves its contracts without open interest
+	openInterest := make(map[string]binance.OptionOpenInterest)
+	for expiration := range expirations {
+		entries, err := s.binanceClient.GetOptionOpenInterest(ctx, underlying, expiration)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("[BinanceOptionsService] Failed to fetch open interest for %s %s: %v", underlying, expiration, err)
+			continue
+		}
+		for _, entry := range entries {
+			openInterest[entry.Symbol] = entry
+		}
+	}
+	for i := range quotes {
+		if entry, ok := openInterest[quotes[i].Symbol]; ok {
+			quotes[i].OpenInterest = parseOptionFloat(entry.SumOpenInterest)
+			quotes[i].OpenInterestUSD = parseOptionFloat(entry.SumOpenInterestUSD)
+		}
+	}
+
+	return quotes, nil
+}
+
+// checkUnderlying returns the upper-cased underlying if Binance options data is enabled for it
+func (s *BinanceOptionsService) checkUnderlying(underlying string) (string, error) {
+	if s.binanceClient == nil {
+		return "", ErrBinanceOptionsDisabled
+	}
+	underlying = strings.ToUpper(underlying)
+	if !s.underlyings[underlying] {
+		return "", fmt.Errorf("%w: %s (enabled: %s)", ErrBinanceOptionsUnderlying, underlying, strings.Join(s.Underlyings(), ", "))
+	}
+	return underlying, nil
+}
+
+// buildBinanceOptionsBoard groups quotes by expiry and strike, dropping expired contracts, and
+// derives each expiry's at-the-money strike and IV, open interest and put/call ratio
+func buildBinanceOptionsBoard(underlying string, indexPrice float64, quotes []models.BinanceOptionQuote, now time.Time) *models.BinanceOptionsBoard {
+	type expiryGroup struct {
+		expiry  *models.BinanceOptionExpiry
+		strikes map[float64]*models.BinanceOptionStrike
+		callOI  float64
+		putOI   float64
+	}
+
+	groups := make(map[time.Time]*expiryGroup)
+	for i := range quotes {
+		quote := &quotes[i]
+		if !quote.Expiry.After(now) {
+			continue
+		}
+
+		group, exists := groups[quote.Expiry]
+		if !exists {
+			group = &expiryGroup{
+				expiry: &models.BinanceOptionExpiry{
+					Label:        quote.Expiry.Format("060102"),
+					Expiry:       quote.Expiry,
+					DaysToExpiry: quote.Expiry.Sub(now).Hours() / 24,
+				},
+				strikes: make(map[float64]*models.BinanceOptionStrike),
+			}
+			groups[quote.Expiry] = group
+		}
+
+		strike, exists := group.strikes[quote.Strike]
+		if !exists {
+			strike = &models.BinanceOptionStrike{Strike: quote.Strike}
+			group.strikes[quote.Strike] = strike
+		}
+		if quote.Type == models.OptionTypeCall {
+			strike.Call = quote
+			group.callOI += quote.OpenInterest
+		} else {
+			strike.Put = quote
+			group.putOI += quote.OpenInterest
+		}
+		group.expiry.OpenInterestUSD += quote.OpenInterestUSD
+	}
+
+	board := &models.BinanceOptionsBoard{
+		Underlying: underlying,
+		IndexPrice: indexPrice,
+		Timestamp:  now.UnixMilli(),
+		Expiries:   make([]models.BinanceOptionExpiry, 0, len(groups)),
+	}
+	for _, group := range groups {
+		expiry := group.expiry
+		expiry.OpenInterest = group.callOI + group.putOI
+		if group.callOI > 0 {
+			expiry.PutCallRatio = group.putOI / group.callOI
+		}
+
+		expiry.Strikes = make([]models.BinanceOptionStrike, 0, len(group.strikes))
+		for _, strike := range group.strikes {
+			expiry.Strikes = append(expiry.Strikes, *strike)
+		}
+		sort.Slice(expiry.Strikes, func(i, j int) bool {
+			return expiry.Strikes[i].Strike < expiry.Strikes[j].Strike
+		})
+
+		var atm *models.BinanceOptionStrike
+		for i := range expiry.Strikes {
+			if atm == nil || math.Abs(expiry.Strikes[i].Strike-indexPrice) < math.Abs(atm.Strike-indexPrice) {
+				atm = &expiry.Strikes[i]
+			}
+		}
+		if atm != nil {
+			expiry.ATMStrike = atm.Strike
+			expiry.ATMIV = binanceATMIV(atm)
+		}
+
+		board.Expiries = append(board.Expiries, *expiry)
+	}
+	sort.Slice(board.Expiries, func(i, j int) bool {
+		return board.Expiries[i].Expiry.Before(board.Expiries[j].Expiry)
+	})
+
+	return board
+}
+
+// binanceATMIV averages the mark IVs of a strike's call and put, using whichever is quoted
+func binanceATMIV(strike *models.BinanceOptionStrike) float64 {
+	var sum float64
+	var count int
+	for _, quote := range []*models.BinanceOptionQuote{strike.Call, strike.Put} {
+		if quote != nil && quote.MarkIV > 0 {
+			sum += quote.MarkIV
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// parseOptionFloat parses a Binance decimal string, treating empty or malformed values as zero
+func parseOptionFloat(value string) float64 {
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
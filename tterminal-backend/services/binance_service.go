@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 	"tterminal-backend/config"
 	"tterminal-backend/internal/binance"
@@ -109,43 +110,37 @@ func (s *BinanceService) ConvertBinanceSymbolToModel(binanceSymbol binance.Binan
 			symbol.MinQty = sql.NullString{String: filter.MinQty, Valid: filter.MinQty != ""}
 			symbol.MaxQty = sql.NullString{String: filter.MaxQty, Valid: filter.MaxQty != ""}
 			symbol.StepSize = sql.NullString{String: filter.StepSize, Valid: filter.StepSize != ""}
+		case "MIN_NOTIONAL", "NOTIONAL":
+			minNotional := filter.Notional
+			if minNotional == "" {
+				minNotional = filter.MinNotional
+			}
+			symbol.MinNotional = sql.NullString{String: minNotional, Valid: minNotional != ""}
+		case "PERCENT_PRICE":
+			symbol.MultiplierUp = sql.NullString{String: filter.MultiplierUp, Valid: filter.MultiplierUp != ""}
+			symbol.MultiplierDown = sql.NullString{String: filter.MultiplierDown, Valid: filter.MultiplierDown != ""}
 		}
 	}
 
+	// Linear (USDT-margined) contracts have a multiplier of 1
+	contractMultiplier := 1.0
+	if binanceSymbol.ContractSize > 0 {
+		contractMultiplier = binanceSymbol.ContractSize
+	}
+	symbol.ContractMultiplier = sql.NullString{String: strconv.FormatFloat(contractMultiplier, 'f', -1, 64), Valid: true}
+	symbol.FillDisplay()
+
 	return symbol
 }
 
 // isValidInterval checks if the interval is valid for Binance
 func (s *BinanceService) isValidInterval(interval string) bool {
-	validIntervals := map[string]bool{
-		"1s":  true,
-		"1m":  true,
-		"3m":  true,
-		"5m":  true,
-		"15m": true,
-		"30m": true,
-		"1h":  true,
-		"2h":  true,
-		"4h":  true,
-		"6h":  true,
-		"8h":  true,
-		"12h": true,
-		"1d":  true,
-		"3d":  true,
-		"1w":  true,
-		"1M":  true,
-	}
-
-	return validIntervals[interval]
+	return models.IsValidInterval(interval)
 }
 
 // GetValidIntervals returns a list of valid intervals
 func (s *BinanceService) GetValidIntervals() []string {
-	return []string{
-		"1s", "1m", "3m", "5m", "15m", "30m",
-		"1h", "2h", "4h", "6h", "8h", "12h",
-		"1d", "3d", "1w", "1M",
-	}
+	return models.SupportedIntervalNames()
 }
 
 // SyncSymbolsFromBinance fetches and returns symbols from Binance that can be synced to the database
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// maxCandleAnnotations caps the annotations of one response; the newest are kept
+const maxCandleAnnotations = 200
+
+// SetAnnotationSources enables annotating candle responses with stream/REST discrepancies and
+// Binance stream disconnects; missing bars, collection pauses and corrected bars are annotated
+// without them
+func (s *CandleService) SetAnnotationSources(discrepancyRepo *repositories.CandleDiscrepancyRepository, stream *websocket.BinanceStream) {
+	s.discrepancyRepo = discrepancyRepo
+	s.stream = stream
+}
+
+// AnnotateCandles returns the ranges of a candle response whose data is questionable: missing
+// bars and exchange stream disconnects, collection pauses (quarantined after an anomaly, or by
+// hand), bars corrected by hand, stream/REST discrepancies and switches between candle sources.
+// Sources that cannot be read are logged and skipped, so annotations never fail a response
+func (s *CandleService) AnnotateCandles(ctx context.Context, response *models.CandleResponse) []models.CandleAnnotation {
+	if response == nil || len(response.D) == 0 || len(response.X) > 0 {
+		return nil
+	}
+	duration, ok := models.IntervalDuration(response.I)
+	if !ok {
+		return nil
+	}
+	closeOf := func(openTime int64) int64 {
+		open := time.UnixMilli(openTime)
+		if closeTime, aligned := models.CandleCloseTime(response.I, open); aligned {
+			return closeTime.UnixMilli()
+		}
+		return open.Add(duration).UnixMilli()
+	}
+
+	firstOpen := response.D[0].T
+	lastOpen := response.D[len(response.D)-1].T
+	start, end := time.UnixMilli(firstOpen), time.UnixMilli(closeOf(lastOpen))
+	var annotations []models.CandleAnnotation
+
+	// Bars the exchange never reported or that were never stored
+	for i := 1; i < len(response.D); i++ {
+		if expected := closeOf(response.D[i-1].T); response.D[i].T > expected {
+			annotations = append(annotations, models.CandleAnnotation{
+				Kind:   models.CandleAnnotationDowntime,
+				Start:  expected,
+				End:    response.D[i].T,
+				Detail: "No bars",
+			})
+		}
+	}
+
+	// Stored sources of last price bars: hand corrections and source switches
+	if response.P == "" || response.P == models.PriceTypeLast {
+		candles, err := s.candleRepo.GetByTimeRange(ctx, response.S, response.I, start, time.UnixMilli(lastOpen))
+		if err != nil {
+			log.Printf("[CandleService] WARNING: failed to load candle sources for %s %s annotations: %v", response.S, response.I, err)
+		}
+		previous := ""
+		for _, candle := range candles {
+			openTime := candle.OpenTime.UnixMilli()
+			if candle.Source == models.CandleSourceManualFix {
+				annotations = append(annotations, models.CandleAnnotation{
+					Kind:   models.CandleAnnotationCorrected,
+					Start:  openTime,
+					End:    closeOf(openTime),
+					Detail: "Corrected by hand",
+				})
+			}
+			if candle.Source == "" {
+				continue
+			}
+			if previous != "" && candle.Source != previous {
+				annotations = append(annotations, models.CandleAnnotation{
+					Kind:   models.CandleAnnotationSourceSwitch,
+					Start:  openTime,
+					End:    closeOf(openTime),
+					Detail: previous + " to " + candle.Source,
+				})
+			}
+			previous = candle.Source
+		}
+
+		if s.discrepancyRepo != nil {
+			discrepancies, err := s.discrepancyRepo.GetRange(ctx, response.S, response.I, start, time.UnixMilli(lastOpen))
+			if err != nil {
+				log.Printf("[CandleService] WARNING: failed to load discrepancies for %s %s annotations: %v", response.S, response.I, err)
+			}
+			for _, d := range discrepancies {
+				openTime := d.OpenTime.UnixMilli()
+				annotations = append(annotations, models.CandleAnnotation{
+					Kind:   models.CandleAnnotationDiscrepancy,
+					Start:  openTime,
+					End:    closeOf(openTime),
+					Detail: fmt.Sprintf("Stream and REST differed by %.2f%% in price and %.2f%% in volume, kept %s", d.PriceDiffPct, d.VolumeDiffPct, d.KeptSource),
+				})
+			}
+		}
+	}
+
+	// Collection pauses; anomaly pauses hold back suspect data
+	if s.collection != nil {
+		pauses, err := s.collection.PausesBetween(ctx, response.S, start, end)
+		if err != nil {
+			log.Printf("[CandleService] WARNING: failed to load pauses for %s %s annotations: %v", response.S, response.I, err)
+		}
+		for _, pause := range pauses {
+			kind := models.CandleAnnotationPaused
+			if pause.Source == models.SymbolPauseAnomaly {
+				kind = models.CandleAnnotationQuarantined
+			}
+			pauseEnd := pause.End()
+			if pauseEnd.IsZero() || pauseEnd.After(time.Now()) {
+				pauseEnd = time.Now()
+			}
+			annotations = appendClipped(annotations, models.CandleAnnotation{
+				Kind:   kind,
+				Start:  pause.PausedAt.UnixMilli(),
+				End:    pauseEnd.UnixMilli(),
+				Detail: pause.Reason,
+			}, start, end)
+		}
+	}
+
+	// Disconnects of the Binance stream confirming the symbol's bars
+	if s.stream != nil && models.SymbolExchange(response.S) == models.ExchangeBinance {
+		stream := "futures"
+		if binance.IsCoinMargined(response.S) {
+			stream = "coinm"
+		}
+		// Reconnect history is short, so disconnects starting before the page are included
+		for _, event := range s.stream.RecentReconnects(start.Add(-24 * time.Hour)) {
+			if event.Stream != stream {
+				continue
+			}
+			reconnected := time.Now()
+			if event.ReconnectedAt != nil {
+				reconnected = *event.ReconnectedAt
+			}
+			annotations = appendClipped(annotations, models.CandleAnnotation{
+				Kind:   models.CandleAnnotationDowntime,
+				Start:  event.DisconnectedAt.UnixMilli(),
+				End:    reconnected.UnixMilli(),
+				Detail: "Binance " + stream + " stream disconnected",
+			}, start, end)
+		}
+	}
+
+	return mergeCandleAnnotations(annotations)
+}
+
+// appendClipped appends an annotation cut to a time range, dropping it when it lies outside
+func appendClipped(annotations []models.CandleAnnotation, annotation models.CandleAnnotation, start, end time.Time) []models.CandleAnnotation {
+	annotation.Start = max(annotation.Start, start.UnixMilli())
+	annotation.End = min(annotation.End, end.UnixMilli())
+	if annotation.End <= annotation.Start {
+		return annotations
+	}
+	return append(annotations, annotation)
+}
+
+// mergeCandleAnnotations joins touching or overlapping annotations of the same kind and detail,
+// ordering them oldest first and keeping the newest maxCandleAnnotations
+func mergeCandleAnnotations(annotations []models.CandleAnnotation) []models.CandleAnnotation {
+	if len(annotations) == 0 {
+		return nil
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].Start < annotations[j].Start
+	})
+
+	merged := make([]models.CandleAnnotation, 0, len(annotations))
+	open := make(map[string]int) // Kind and detail -> index of the latest merged annotation
+	for _, annotation := range annotations {
+		key := annotation.Kind + "\x00" + annotation.Detail
+		if i, exists := open[key]; exists && annotation.Start <= merged[i].End {
+			merged[i].End = max(merged[i].End, annotation.End)
+			continue
+		}
+		open[key] = len(merged)
+		merged = append(merged, annotation)
+	}
+
+	if len(merged) > maxCandleAnnotations {
+		merged = merged[len(merged)-maxCandleAnnotations:]
+	}
+	return merged
+}
+
+// CandleAnnotator marks the questionable ranges of candle responses
+type CandleAnnotator interface {
+	AnnotateCandles(ctx context.Context, response *models.CandleResponse) []models.CandleAnnotation
+}
+
+// SetCandleAnnotator enables annotations on aggregated candles
+func (s *AggregationService) SetCandleAnnotator(annotator CandleAnnotator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.candleAnnotator = annotator
+}
+
+// annotateCandles sets a response's annotations before it is cached
+func (s *AggregationService) annotateCandles(ctx context.Context, response *models.CandleResponse) {
+	s.mu.RLock()
+	annotator := s.candleAnnotator
+	s.mu.RUnlock()
+	if annotator != nil {
+		response.A = annotator.AnnotateCandles(ctx, response)
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// maxCandleDiscrepancies caps the discrepancies returned per request
+const maxCandleDiscrepancies = 500
+
+// CandleConsistencyService compares the two live sources of a closed bar: the WebSocket stream's
+// closed kline and the REST-polled candle. Whichever arrives second is compared with the stored
+// first one; bars diverging beyond tolerance are recorded, and the preferred source's version is
+// the one stored. REST candles are only compared once the bar had closed when they were fetched,
+// since collection runs also fetch the bar still forming
+type CandleConsistencyService struct {
+	candleRepo      marketdata.CandleStore
+	discrepancyRepo *repositories.CandleDiscrepancyRepository
+	priceTolerance  float64 // Largest OHLC difference tolerated, percent
+	volumeTolerance float64 // Largest volume difference tolerated, percent
+	preferred       string  // CandleSource* stored when both versions exist
+
+	mu    sync.Mutex
+	stats map[string]*models.CandleConsistencyStats // By "SYMBOL:interval"
+	// Newest open time compared per "SYMBOL:interval", so bars refetched by every collection
+	// run are only compared once
+	compared map[string]int64
+}
+
+// NewCandleConsistencyService creates a new candle consistency service
+// Tolerances are percentages of the REST value; preferred is models.CandleSourceStream or
+// models.CandleSourceRESTPoll
+func NewCandleConsistencyService(candleRepo marketdata.CandleStore, discrepancyRepo *repositories.CandleDiscrepancyRepository, priceTolerance, volumeTolerance float64, preferred string) *CandleConsistencyService {
+	if isNil(candleRepo) {
+		log.Fatalf("[CandleConsistencyService] CRITICAL: candleRepo cannot be nil")
+	}
+	if discrepancyRepo == nil {
+		log.Fatalf("[CandleConsistencyService] CRITICAL: discrepancyRepo cannot be nil")
+	}
+
+	log.Printf("[CandleConsistencyService] Successfully initialized (price tolerance %.4f%%, volume tolerance %.4f%%, preferring %s)",
+		priceTolerance, volumeTolerance, preferred)
+	return &CandleConsistencyService{
+		candleRepo:      candleRepo,
+		discrepancyRepo: discrepancyRepo,
+		priceTolerance:  priceTolerance,
+		volumeTolerance: volumeTolerance,
+		preferred:       preferred,
+		stats:           make(map[string]*models.CandleConsistencyStats),
+		compared:        make(map[string]int64),
+	}
+}
+
+// ReconcileREST compares REST-polled candles of one symbol/interval with stored stream candles
+// of the same bars and returns the candles to store: bars whose stream version is preferred are
+// left out so the stored kline stays
+func (s *CandleConsistencyService) ReconcileREST(ctx context.Context, symbol, interval string, candles []models.Candle, fetchedAt time.Time) []models.Candle {
+	key := symbol + ":" + interval
+	s.mu.Lock()
+	after := s.compared[key]
+	s.mu.Unlock()
+
+	// Only bars that had closed when fetched are compared, each once; while stream versions are
+	// preferred, every closed bar is looked up so stored klines are never overwritten
+	preferStream := s.preferred == models.CandleSourceStream
+	var lookup []models.Candle
+	for _, candle := range candles {
+		if candle.CloseTime.Before(fetchedAt) && (preferStream || candle.OpenTime.UnixMilli() > after) {
+			lookup = append(lookup, candle)
+		}
+	}
+	if len(lookup) == 0 {
+		return candles
+	}
+
+	stored, err := s.candleRepo.GetByTimeRange(ctx, symbol, interval, lookup[0].OpenTime, lookup[len(lookup)-1].OpenTime)
+	if err != nil {
+		log.Printf("[CandleConsistencyService] ERROR loading stored %s candles: %v", key, err)
+		return candles
+	}
+	streamed := make(map[int64]models.Candle, len(stored))
+	for _, candle := range stored {
+		if candle.Source == models.CandleSourceStream {
+			streamed[candle.OpenTime.UnixMilli()] = candle
+		}
+	}
+
+	keepStream := make(map[int64]bool)
+	for _, rest := range lookup {
+		openTime := rest.OpenTime.UnixMilli()
+		stream, ok := streamed[openTime]
+		if !ok {
+			continue
+		}
+		if openTime > after {
+			s.compare(ctx, stream, rest)
+		}
+		keepStream[openTime] = preferStream
+	}
+	s.markCompared(key, lookup[len(lookup)-1].OpenTime.UnixMilli())
+
+	if len(keepStream) == 0 {
+		return candles
+	}
+	kept := make([]models.Candle, 0, len(candles))
+	for _, candle := range candles {
+		if !keepStream[candle.OpenTime.UnixMilli()] {
+			kept = append(kept, candle)
+		}
+	}
+	return kept
+}
+
+// ReconcileStream compares a closed stream bar with a stored REST candle of the same bar fetched
+// after it closed, and reports whether the stream bar should be stored
+func (s *CandleConsistencyService) ReconcileStream(ctx context.Context, stream models.Candle) bool {
+	stored, err := s.candleRepo.GetByTimeRange(ctx, stream.Symbol, stream.Interval, stream.OpenTime, stream.OpenTime)
+	if err != nil {
+		log.Printf("[CandleConsistencyService] ERROR loading stored %s:%s candle: %v", stream.Symbol, stream.Interval, err)
+		return true
+	}
+	if len(stored) == 0 {
+		return true
+	}
+	rest := stored[0]
+	if rest.Source != models.CandleSourceRESTPoll || !rest.UpdatedAt.After(rest.CloseTime) {
+		return true
+	}
+
+	s.compare(ctx, stream, rest)
+	return s.preferred == models.CandleSourceStream
+}
+
+// GetStats returns the comparison stats of every symbol/interval, or of one symbol
+func (s *CandleConsistencyService) GetStats(symbol string) []models.CandleConsistencyStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]models.CandleConsistencyStats, 0, len(s.stats))
+	for _, stat := range s.stats {
+		if symbol != "" && stat.Symbol != symbol {
+			continue
+		}
+		entry := *stat
+		if entry.Compared > 0 {
+			entry.DivergenceRate = float64(entry.Diverged) / float64(entry.Compared)
+		}
+		stats = append(stats, entry)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Symbol != stats[j].Symbol {
+			return stats[i].Symbol < stats[j].Symbol
+		}
+		return stats[i].Interval < stats[j].Interval
+	})
+	return stats
+}
+
+// GetDiscrepancies returns a symbol's latest recorded discrepancies, newest first
+func (s *CandleConsistencyService) GetDiscrepancies(ctx context.Context, symbol, interval string, limit int) ([]models.CandleDiscrepancy, error) {
+	if limit <= 0 || limit > maxCandleDiscrepancies {
+		limit = maxCandleDiscrepancies
+	}
+	return s.discrepancyRepo.GetRecent(ctx, symbol, interval, limit)
+}
+
+// Settings returns the tolerances and preferred source, for reports
+func (s *CandleConsistencyService) Settings() map[string]interface{} {
+	return map[string]interface{}{
+		"price_tolerance_pct":  s.priceTolerance,
+		"volume_tolerance_pct": s.volumeTolerance,
+		"preferred_source":     s.preferred,
+	}
+}
+
+// compare counts a bar compared between its stream and REST versions and records it if they diverge
+func (s *CandleConsistencyService) compare(ctx context.Context, stream, rest models.Candle) {
+	streamValues := models.CandleValuesOf(stream)
+	restValues := models.CandleValuesOf(rest)
+
+	priceDiff := math.Max(
+		math.Max(diffPct(streamValues.Open, restValues.Open), diffPct(streamValues.High, restValues.High)),
+		math.Max(diffPct(streamValues.Low, restValues.Low), diffPct(streamValues.Close, restValues.Close)),
+	)
+	volumeDiff := diffPct(streamValues.Volume, restValues.Volume)
+	diverged := priceDiff > s.priceTolerance || volumeDiff > s.volumeTolerance
+
+	key := rest.Symbol + ":" + rest.Interval
+	s.mu.Lock()
+	stat, ok := s.stats[key]
+	if !ok {
+		stat = &models.CandleConsistencyStats{Symbol: rest.Symbol, Interval: rest.Interval}
+		s.stats[key] = stat
+	}
+	stat.Compared++
+	if diverged {
+		stat.Diverged++
+		stat.MaxPriceDiffPct = math.Max(stat.MaxPriceDiffPct, priceDiff)
+		stat.MaxVolumeDiffPct = math.Max(stat.MaxVolumeDiffPct, volumeDiff)
+		if stat.LastDivergence == nil || rest.OpenTime.After(*stat.LastDivergence) {
+			openTime := rest.OpenTime
+			stat.LastDivergence = &openTime
+		}
+	}
+	s.mu.Unlock()
+
+	if !diverged {
+		return
+	}
+
+	discrepancy := &models.CandleDiscrepancy{
+		Symbol:        rest.Symbol,
+		Interval:      rest.Interval,
+		OpenTime:      rest.OpenTime,
+		Stream:        streamValues,
+		REST:          restValues,
+		PriceDiffPct:  priceDiff,
+		VolumeDiffPct: volumeDiff,
+		KeptSource:    s.preferred,
+		DetectedAt:    time.Now(),
+	}
+	log.Printf("[CandleConsistencyService] %s %s bar diverged between stream and REST (price %.4f%%, volume %.4f%%), keeping %s",
+		key, rest.OpenTime.UTC().Format(time.RFC3339), priceDiff, volumeDiff, s.preferred)
+	if err := s.discrepancyRepo.Upsert(ctx, discrepancy); err != nil {
+		log.Printf("[CandleConsistencyService] ERROR %v", err)
+	}
+}
+
+// markCompared records the newest open time compared for a symbol/interval
+func (s *CandleConsistencyService) markCompared(key string, openTime int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if openTime > s.compared[key] {
+		s.compared[key] = openTime
+	}
+}
+
+// diffPct is the absolute difference of value from reference, as a percentage of reference
+func diffPct(value, reference float64) float64 {
+	if reference == 0 {
+		if value == 0 {
+			return 0
+		}
+		return 100
+	}
+	return math.Abs(value-reference) / math.Abs(reference) * 100
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/models"
+)
+
+const (
+	// candleOverlayCacheTTL controls how long fetched funding and open interest history is reused
+	candleOverlayCacheTTL = time.Minute
+
+	// openInterestHistoryWindow is how far back Binance keeps open interest statistics
+	openInterestHistoryWindow = 30 * 24 * time.Hour
+
+	// fundingLookback reaches back far enough that the first bar has a settled rate before it
+	// (the longest funding interval is 8 hours)
+	fundingLookback = 8 * time.Hour
+
+	// Page sizes of Binance's history endpoints and the most pages fetched for one response
+	fundingPageLimit      = 1000
+	openInterestPageLimit = 500
+	maxOverlayPages       = 10
+)
+
+// ErrCandleOverlayUnsupported is returned when a candle series cannot carry the requested overlays
+var ErrCandleOverlayUnsupported = errors.New("derivatives overlay is not supported for this series")
+
+// statisticsPeriod is one of Binance's futures statistics periods
+type statisticsPeriod struct {
+	name     string
+	duration time.Duration
+}
+
+// openInterestPeriods are Binance's open interest statistics periods, shortest first
+var openInterestPeriods = []statisticsPeriod{
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"2h", 2 * time.Hour},
+	{"4h", 4 * time.Hour},
+	{"6h", 6 * time.Hour},
+	{"12h", 12 * time.Hour},
+	{"1d", 24 * time.Hour},
+}
+
+// overlayPoint is a funding rate or open interest observation
+type overlayPoint struct {
+	at     int64 // Unix milliseconds
+	value  float64
+	value2 float64 // Open interest value; unused for funding
+}
+
+// overlayEntry is a cached history fetch
+type overlayEntry struct {
+	points  []overlayPoint
+	expires time.Time
+}
+
+// OverlayCandles returns a copy of a Binance futures candle response with the last settled funding
+// rate and the open interest at each bar's close merged in, leaving the response itself (which may
+// be cached) untouched. Bars no history covers keep null overlays; open interest only reaches back
+// 30 days. An empty include list returns the response unchanged
+func (s *DerivativesService) OverlayCandles(ctx context.Context, response *models.CandleResponse, include []string) (*models.CandleResponse, error) {
+	if len(include) == 0 || response == nil {
+		return response, nil
+	}
+
+	var funding, openInterest bool
+	for _, name := range include {
+		switch name {
+		case models.CandleIncludeFunding:
+			funding = true
+		case models.CandleIncludeOI:
+			openInterest = true
+		default:
+			return nil, fmt.Errorf("%w: unknown include %q, use funding or oi", ErrCandleOverlayUnsupported, name)
+		}
+	}
+	if models.SymbolExchange(response.S) != models.ExchangeBinance || len(response.X) > 0 {
+		return nil, fmt.Errorf("%w: funding and open interest are only collected for Binance futures", ErrCandleOverlayUnsupported)
+	}
+	if openInterest && binance.IsCoinMargined(response.S) {
+		return nil, fmt.Errorf("%w: open interest history is only available for USD-margined futures", ErrCandleOverlayUnsupported)
+	}
+	duration, ok := models.IntervalDuration(response.I)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown interval %q", ErrCandleOverlayUnsupported, response.I)
+	}
+
+	overlaid := *response
+	overlaid.D = append([]models.OptimizedCandle(nil), response.D...)
+	if len(overlaid.D) == 0 {
+		return &overlaid, nil
+	}
+
+	// Each bar takes the last observation at or before its close
+	closes := make([]int64, len(overlaid.D))
+	for i, candle := range overlaid.D {
+		openTime := time.UnixMilli(candle.T)
+		closeTime, aligned := models.CandleCloseTime(response.I, openTime)
+		if !aligned {
+			closeTime = openTime.Add(duration)
+		}
+		closes[i] = closeTime.UnixMilli()
+	}
+	firstOpen := time.UnixMilli(overlaid.D[0].T)
+	lastClose := time.UnixMilli(closes[len(closes)-1])
+
+	if funding {
+		points, err := s.fundingPoints(ctx, response.S, firstOpen.Add(-fundingLookback), lastClose)
+		if err != nil {
+			return nil, err
+		}
+		for i := range overlaid.D {
+			if point, ok := pointAt(points, closes[i]); ok {
+				rate := point.value
+				overlaid.D[i].FR = &rate
+			}
+		}
+	}
+
+	if openInterest {
+		period := openInterestPeriod(duration)
+		start := firstOpen.Add(-period.duration)
+		if oldest := time.Now().Add(-openInterestHistoryWindow); start.Before(oldest) {
+			start = oldest
+		}
+		if start.Before(lastClose) {
+			points, err := s.openInterestPoints(ctx, response.S, period.name, start, lastClose)
+			if err != nil {
+				return nil, err
+			}
+			for i := range overlaid.D {
+				if point, ok := pointAt(points, closes[i]); ok {
+					oi, value := point.value, point.value2
+					overlaid.D[i].OI, overlaid.D[i].OIV = &oi, &value
+				}
+			}
+		}
+	}
+
+	return &overlaid, nil
+}
+
+// fundingPoints returns the funding rates settled between start and end, oldest first
+func (s *DerivativesService) fundingPoints(ctx context.Context, symbol string, start, end time.Time) ([]overlayPoint, error) {
+	key := fmt.Sprintf("funding:%s:%d:%d", symbol, start.UnixMilli(), end.UnixMilli())
+	if points, ok := s.getOverlay(key); ok {
+		return points, nil
+	}
+
+	var points []overlayPoint
+	for page := 0; page < maxOverlayPages && start.Before(end); page++ {
+		rates, err := s.binanceClient.GetFundingRateHistory(ctx, symbol, start, end, fundingPageLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch funding rate history: %w", err)
+		}
+		for _, rate := range rates {
+			points = append(points, overlayPoint{at: rate.FundingTime, value: models.ParseFloat(rate.FundingRate)})
+		}
+		if len(rates) < fundingPageLimit {
+			break
+		}
+		start = time.UnixMilli(rates[len(rates)-1].FundingTime + 1)
+	}
+
+	s.setOverlay(key, points)
+	return points, nil
+}
+
+// openInterestPoints returns the open interest statistics between start and end, oldest first
+func (s *DerivativesService) openInterestPoints(ctx context.Context, symbol, period string, start, end time.Time) ([]overlayPoint, error) {
+	key := fmt.Sprintf("oi:%s:%s:%d:%d", symbol, period, start.Truncate(time.Minute).UnixMilli(), end.UnixMilli())
+	if points, ok := s.getOverlay(key); ok {
+		return points, nil
+	}
+
+	var points []overlayPoint
+	for page := 0; page < maxOverlayPages && start.Before(end); page++ {
+		stats, err := s.binanceClient.GetOpenInterestHist(ctx, symbol, period, start, end, openInterestPageLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch open interest history: %w", err)
+		}
+		for _, stat := range stats {
+			points = append(points, overlayPoint{
+				at:     stat.Timestamp,
+				value:  models.ParseFloat(stat.SumOpenInterest),
+				value2: models.ParseFloat(stat.SumOpenInterestValue),
+			})
+		}
+		if len(stats) < openInterestPageLimit {
+			break
+		}
+		start = time.UnixMilli(stats[len(stats)-1].Timestamp + 1)
+	}
+
+	s.setOverlay(key, points)
+	return points, nil
+}
+
+// openInterestPeriod returns the longest statistics period not longer than a candle interval
+func openInterestPeriod(interval time.Duration) statisticsPeriod {
+	period := openInterestPeriods[0]
+	for _, candidate := range openInterestPeriods {
+		if candidate.duration > interval {
+			break
+		}
+		period = candidate
+	}
+	return period
+}
+
+// pointAt returns the last point observed at or before a time
+func pointAt(points []overlayPoint, at int64) (overlayPoint, bool) {
+	i := sort.Search(len(points), func(i int) bool { return points[i].at > at })
+	if i == 0 {
+		return overlayPoint{}, false
+	}
+	return points[i-1], true
+}
+
+// getOverlay returns an unexpired cached history fetch
+func (s *DerivativesService) getOverlay(key string) ([]overlayPoint, bool) {
+	s.overlayMutex.Lock()
+	defer s.overlayMutex.Unlock()
+	entry, ok := s.overlayCache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.points, true
+}
+
+// setOverlay caches a history fetch, dropping expired entries
+func (s *DerivativesService) setOverlay(key string, points []overlayPoint) {
+	now := time.Now()
+	s.overlayMutex.Lock()
+	defer s.overlayMutex.Unlock()
+	for cached, entry := range s.overlayCache {
+		if now.After(entry.expires) {
+			delete(s.overlayCache, cached)
+		}
+	}
+	s.overlayCache[key] = overlayEntry{points: points, expires: now.Add(candleOverlayCacheTTL)}
+}
+
+// CandleOverlay merges derivatives data into candle responses
+type CandleOverlay interface {
+	OverlayCandles(ctx context.Context, response *models.CandleResponse, include []string) (*models.CandleResponse, error)
+}
+
+// SetCandleOverlay enables the include parameter of aggregated candles
+func (s *AggregationService) SetCandleOverlay(overlay CandleOverlay) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.candleOverlay = overlay
+}
+
+// OverlayCandles returns a copy of an aggregated candle response with the included derivatives
+// overlays merged into each bar; an empty include list returns the response unchanged
+func (s *AggregationService) OverlayCandles(ctx context.Context, response *models.CandleResponse, include []string) (*models.CandleResponse, error) {
+	if len(include) == 0 {
+		return response, nil
+	}
+	s.mu.RLock()
+	overlay := s.candleOverlay
+	s.mu.RUnlock()
+	if overlay == nil {
+		return nil, fmt.Errorf("%w: derivatives overlays are not enabled", ErrCandleOverlayUnsupported)
+	}
+	return overlay.OverlayCandles(ctx, response, include)
+}
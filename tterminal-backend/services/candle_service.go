@@ -4,37 +4,113 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"strings"
 	"sync"
 	"time"
+	"tterminal-backend/internal/batchwriter"
 	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/internal/websocket"
 	"tterminal-backend/models"
 	"tterminal-backend/repositories"
 )
 
+// staleCacheDuration is how long a response served from stored data is reused before retrying upstream
+const staleCacheDuration = 30 * time.Second
+
 // CandleService handles business logic for candles with ultra-fast performance
 type CandleService struct {
-	candleRepo    *repositories.CandleRepository
-	binanceClient *binance.Client
-	cache         map[string]*models.CandleResponse // In-memory cache for ultra-fast access
-	cacheMutex    sync.RWMutex
-	cacheExpiry   map[string]time.Time
+	candleRepo      marketdata.CandleStore
+	priceCandleRepo marketdata.PriceCandleStore               // Mark/index price candles
+	tradeRepo       marketdata.TradeStore                     // Persisted trades for candle drill-down
+	binanceClient   *binance.Client                           // Mark/index price klines (Binance only)
+	providers       *marketdata.Registry                      // Last price klines of every enabled exchange
+	wsAPIKlines     marketdata.KlineSource                    // Binance klines over the WS-API, tried before REST
+	wsAPIEnabled    func() bool                               // Checked per request, so the WS-API can be toggled at runtime
+	collection      *DataCollectionService                    // Symbol pauses; candles of paused symbols are served but not stored
+	symbolRepo      *repositories.SymbolRepository            // Contract multipliers for volume unit conversion
+	discrepancyRepo *repositories.CandleDiscrepancyRepository // Stream/REST divergences annotated on responses
+	stream          *websocket.BinanceStream                  // Stream disconnects annotated on Binance responses
+	cache           map[string]*models.CandleResponse         // In-memory cache for ultra-fast access
+	cacheMutex      sync.RWMutex
+	cacheExpiry     map[string]time.Time
+
+	multipliers     map[string]contractMultiplier // Symbol -> contract multiplier, reloaded after volumeMultiplierTTL
+	multiplierMutex sync.Mutex
+
+	// Candles fetched upstream are stored off the request path through batch writers
+	candleWriter      *batchwriter.Writer[models.Candle]
+	priceCandleWriter *batchwriter.Writer[models.Candle] // nil without a price candle repository
 }
 
-// NewCandleService creates a new ultra-fast candle service
-func NewCandleService(candleRepo *repositories.CandleRepository, binanceClient *binance.Client) *CandleService {
-	if candleRepo == nil {
+// NewCandleService creates a new ultra-fast candle service; its batch writers are registered with
+// writers for metrics and shutdown flushing when it is not nil
+func NewCandleService(candleRepo marketdata.CandleStore, priceCandleRepo marketdata.PriceCandleStore, binanceClient *binance.Client, providers *marketdata.Registry, writers *batchwriter.Registry) *CandleService {
+	if isNil(candleRepo) {
 		log.Fatalf("[CandleService] CRITICAL: repo cannot be nil")
 	}
+	if providers == nil {
+		log.Fatalf("[CandleService] CRITICAL: providers cannot be nil")
+	}
+	if isNil(priceCandleRepo) {
+		log.Printf("[CandleService] WARNING: priceCandleRepo is nil - mark/index candles will not be stored")
+		priceCandleRepo = nil
+	}
 	if binanceClient == nil {
 		log.Printf("[CandleService] WARNING: binanceClient is nil - only database operations will work")
 	}
+	s := &CandleService{
+		candleRepo:      candleRepo,
+		priceCandleRepo: priceCandleRepo,
+		binanceClient:   binanceClient,
+		providers:       providers,
+		cache:           make(map[string]*models.CandleResponse),
+		cacheExpiry:     make(map[string]time.Time),
+		multipliers:     make(map[string]contractMultiplier),
+		candleWriter:    batchwriter.New(writers, batchwriter.Config{Name: "candles"}, candleRepo.BulkCreate),
+	}
+	if priceCandleRepo != nil {
+		s.priceCandleWriter = batchwriter.New(writers, batchwriter.Config{Name: "price_candles"}, priceCandleRepo.BulkCreate)
+	}
 	log.Printf("[CandleService] Successfully initialized")
-	return &CandleService{
-		candleRepo:    candleRepo,
-		binanceClient: binanceClient,
-		cache:         make(map[string]*models.CandleResponse),
-		cacheExpiry:   make(map[string]time.Time),
+	return s
+}
+
+// SetTradeRepository enables drill-down from a candle to the persisted trades composing it
+func (s *CandleService) SetTradeRepository(tradeRepo marketdata.TradeStore) {
+	if isNil(tradeRepo) {
+		tradeRepo = nil
 	}
+	s.tradeRepo = tradeRepo
+}
+
+// SetBinanceWSAPI fetches Binance klines over the WebSocket API before falling back to REST,
+// saving a REST round trip on cache misses, whenever enabled reports true
+func (s *CandleService) SetBinanceWSAPI(klines marketdata.KlineSource, enabled func() bool) {
+	s.wsAPIKlines = klines
+	s.wsAPIEnabled = enabled
+}
+
+// SetCollectionPauses stops storing fetched candles of symbols paused in the data collection
+// service, and lists their pauses in coverage reports
+func (s *CandleService) SetCollectionPauses(collection *DataCollectionService) {
+	s.collection = collection
+}
+
+// SetSymbolRepository enables converting candle volume to and from futures contracts
+func (s *CandleService) SetSymbolRepository(symbolRepo *repositories.SymbolRepository) {
+	s.symbolRepo = symbolRepo
+}
+
+// symbolPaused reports whether a symbol's collection is paused
+func (s *CandleService) symbolPaused(symbol string) bool {
+	return s.collection != nil && s.collection.IsPaused(symbol)
+}
+
+// provider returns the market data provider of a symbol's exchange, or nil when none is registered
+func (s *CandleService) provider(symbol string) marketdata.MarketDataProvider {
+	return s.providers.ForSymbol(symbol)
 }
 
 // GetOptimizedCandles retrieves candles optimized for ultra-fast frontend rendering
@@ -58,7 +134,10 @@ func (s *CandleService) GetOptimizedCandles(ctx context.Context, symbol, interva
 			// If Binance fails but we have some data, return what we have
 			if len(candles) > 0 {
 				response := models.NewOptimizedResponse(symbol, interval, candles)
-				s.setCachedResponse(cacheKey, response, 30*time.Second) // Short cache for stale data
+				response.MarkStale(candles)
+				response.SetNextCursor(limit)
+				response.A = s.AnnotateCandles(ctx, response)
+				s.setCachedResponse(cacheKey, response, staleCacheDuration)
 				return response, nil
 			}
 			return nil, fmt.Errorf("failed to fetch from Binance: %w", err)
@@ -68,6 +147,8 @@ func (s *CandleService) GetOptimizedCandles(ctx context.Context, symbol, interva
 
 	// Create optimized response for ultra-fast transmission
 	response := models.NewOptimizedResponse(symbol, interval, candles)
+	response.SetNextCursor(limit)
+	response.A = s.AnnotateCandles(ctx, response)
 
 	// Cache for ultra-fast subsequent requests
 	cacheDuration := s.getCacheDuration(interval)
@@ -76,26 +157,248 @@ func (s *CandleService) GetOptimizedCandles(ctx context.Context, symbol, interva
 	return response, nil
 }
 
-// fetchFromBinanceAndStore fetches fresh data from Binance and stores it
+// GetOptimizedCandlesByPriceType retrieves optimized candles built from last, mark or index prices
+func (s *CandleService) GetOptimizedCandlesByPriceType(ctx context.Context, symbol, interval, priceType string, limit int) (*models.CandleResponse, error) {
+	if priceType == "" || priceType == models.PriceTypeLast {
+		return s.GetOptimizedCandles(ctx, symbol, interval, limit)
+	}
+	if !models.IsValidPriceType(priceType) {
+		return nil, fmt.Errorf("invalid price type: %s", priceType)
+	}
+
+	cacheKey := fmt.Sprintf("%s:%s:%s:%d", symbol, interval, priceType, limit)
+	if cached := s.getCachedResponse(cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	var candles []models.Candle
+	if s.priceCandleRepo != nil {
+		var err error
+		candles, err = s.priceCandleRepo.GetBySymbolAndInterval(ctx, symbol, interval, priceType, limit)
+		if err != nil {
+			log.Printf("[CandleService] WARNING: failed to get %s candles from database: %v", priceType, err)
+		}
+	}
+
+	// Price candles are returned oldest first, so staleness is judged on the newest one
+	if len(candles) == 0 || len(candles) < limit || s.isDataStale(candles[len(candles)-1:], interval) {
+		freshCandles, err := s.fetchPriceCandlesAndStore(ctx, symbol, interval, priceType, limit)
+		if err != nil {
+			if len(candles) == 0 {
+				return nil, fmt.Errorf("failed to fetch %s candles from Binance: %w", priceType, err)
+			}
+
+			// Serve stored candles, flagged as stale
+			response := models.NewOptimizedResponse(symbol, interval, candles)
+			response.P = priceType
+			response.MarkStale(candles)
+			response.SetNextCursor(limit)
+			response.A = s.AnnotateCandles(ctx, response)
+			s.setCachedResponse(cacheKey, response, staleCacheDuration)
+			return response, nil
+		}
+		candles = freshCandles
+	}
+
+	response := models.NewOptimizedResponse(symbol, interval, candles)
+	response.P = priceType
+	response.SetNextCursor(limit)
+
+	response.A = s.AnnotateCandles(ctx, response)
+	s.setCachedResponse(cacheKey, response, s.getCacheDuration(interval))
+	return response, nil
+}
+
+// GetCandlesBeforeByPriceType retrieves the limit candles opening before a time, for charts panning backwards
+// Stored candles are served when the page is complete and gap-free; otherwise the page is fetched from the
+// exchange ending just before the anchor and stored. Anchors in the future fall back to the most recent candles
+func (s *CandleService) GetCandlesBeforeByPriceType(ctx context.Context, symbol, interval, priceType string, before time.Time, limit int) (*models.CandleResponse, error) {
+	if !before.Before(time.Now()) {
+		return s.GetOptimizedCandlesByPriceType(ctx, symbol, interval, priceType, limit)
+	}
+	if priceType == "" {
+		priceType = models.PriceTypeLast
+	}
+	if !models.IsValidPriceType(priceType) {
+		return nil, fmt.Errorf("invalid price type: %s", priceType)
+	}
+
+	cacheKey := fmt.Sprintf("%s:%s:%s:%d:before:%d", symbol, interval, priceType, limit, before.UnixMilli())
+	if cached := s.getCachedResponse(cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	var candles []models.Candle
+	var err error
+	if priceType == models.PriceTypeLast {
+		candles, err = s.candleRepo.GetBeforeTime(ctx, symbol, interval, before, limit)
+	} else if s.priceCandleRepo != nil {
+		candles, err = s.priceCandleRepo.GetBeforeTime(ctx, symbol, interval, priceType, before, limit)
+	}
+	if err != nil {
+		log.Printf("[CandleService] WARNING: failed to get %s candles before %s from database: %v", priceType, before.Format(time.RFC3339), err)
+	}
+
+	// A short or gapped page means history not yet stored: backfill it instead of serving the gap
+	if !models.IsCompletePage(interval, models.CandleOpenTimes(candles), before, limit) && s.canFetchPriceType(symbol, priceType) {
+		var freshCandles []models.Candle
+		if priceType == models.PriceTypeLast {
+			freshCandles, err = s.fetchKlinesEndingAt(ctx, s.provider(symbol), symbol, interval, before.Add(-time.Millisecond), limit)
+		} else {
+			freshCandles, err = s.binanceClient.GetPriceKlinesEndingAt(ctx, symbol, interval, priceType, before.Add(-time.Millisecond), limit)
+		}
+		if err != nil {
+			if len(candles) == 0 {
+				return nil, fmt.Errorf("failed to fetch %s candles before %s from %s: %w", priceType, before.Format(time.RFC3339), models.SymbolExchange(symbol), err)
+			}
+
+			// Serve the stored part of the page and retry upstream soon; the cursor keeps scrolling going
+			response := models.NewOptimizedResponse(symbol, interval, candles)
+			response.P = priceType
+			response.NextCursor = response.F
+			response.A = s.AnnotateCandles(ctx, response)
+			s.setCachedResponse(cacheKey, response, staleCacheDuration)
+			return response, nil
+		}
+		candles = freshCandles
+
+		if priceType == models.PriceTypeLast {
+			s.storeCandlesAsync(models.LabelCandles(candles, models.CandleSourceBackfill))
+		} else {
+			s.storePriceCandlesAsync(candles)
+		}
+	}
+
+	response := models.NewOptimizedResponse(symbol, interval, candles)
+	response.P = priceType
+	response.SetNextCursor(limit)
+
+	response.A = s.AnnotateCandles(ctx, response)
+	s.setCachedResponse(cacheKey, response, s.getCacheDuration(interval))
+	return response, nil
+}
+
+// GetCandleRangeByPriceType retrieves last, mark or index price candles within a time range
+func (s *CandleService) GetCandleRangeByPriceType(ctx context.Context, symbol, interval, priceType string, startTime, endTime time.Time) ([]models.Candle, error) {
+	if priceType == "" || priceType == models.PriceTypeLast {
+		return s.GetCandleRange(ctx, symbol, interval, startTime, endTime)
+	}
+	if !models.IsValidPriceType(priceType) {
+		return nil, fmt.Errorf("invalid price type: %s", priceType)
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if interval == "" {
+		return nil, fmt.Errorf("interval is required")
+	}
+	if err := validateTimeRange(interval, startTime, endTime); err != nil {
+		return nil, err
+	}
+
+	if s.priceCandleRepo != nil {
+		candles, err := s.priceCandleRepo.GetByTimeRange(ctx, symbol, interval, priceType, startTime, endTime)
+		if err == nil && len(candles) > 0 {
+			return candles, nil
+		}
+	}
+
+	if s.binanceClient == nil {
+		return nil, fmt.Errorf("no %s candles in database and Binance client is not available", priceType)
+	}
+
+	candles, err := s.binanceClient.GetPriceKlinesWithTimeRange(ctx, symbol, interval, priceType, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s candles from Binance: %w", priceType, err)
+	}
+	s.storePriceCandlesAsync(candles)
+
+	return candles, nil
+}
+
+// fetchPriceCandlesAndStore fetches mark/index candles from Binance and stores them
+func (s *CandleService) fetchPriceCandlesAndStore(ctx context.Context, symbol, interval, priceType string, limit int) ([]models.Candle, error) {
+	if s.binanceClient == nil {
+		return nil, fmt.Errorf("binance client is not available")
+	}
+
+	candles, err := s.binanceClient.GetPriceKlines(ctx, symbol, interval, priceType, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.storePriceCandlesAsync(candles)
+
+	return candles, nil
+}
+
+// storePriceCandlesAsync persists mark/index candles without blocking the request
+func (s *CandleService) storePriceCandlesAsync(candles []models.Candle) {
+	if s.priceCandleWriter == nil || len(candles) == 0 || s.symbolPaused(candles[0].Symbol) {
+		return
+	}
+	s.priceCandleWriter.Add(candles...)
+}
+
+// storeCandlesAsync persists last price candles without blocking the request
+func (s *CandleService) storeCandlesAsync(candles []models.Candle) {
+	if len(candles) == 0 || s.symbolPaused(candles[0].Symbol) {
+		return
+	}
+	s.candleWriter.Add(candles...)
+}
+
+// canFetchPriceType reports whether candles of a price type can be fetched upstream for a symbol
+// Mark and index price klines are only available from Binance
+func (s *CandleService) canFetchPriceType(symbol, priceType string) bool {
+	if priceType == models.PriceTypeLast {
+		return s.provider(symbol) != nil
+	}
+	return s.binanceClient != nil && models.SymbolExchange(symbol) == models.ExchangeBinance
+}
+
+// fetchFromBinanceAndStore fetches fresh data from the symbol's exchange and stores it
 func (s *CandleService) fetchFromBinanceAndStore(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
-	// Fetch from Binance with optimized parameters
-	candles, err := s.binanceClient.GetKlines(symbol, interval, limit, nil, nil)
+	source := s.provider(symbol)
+	if source == nil {
+		return nil, fmt.Errorf("no %s client is available", models.SymbolExchange(symbol))
+	}
+
+	// Fetch from the exchange with optimized parameters
+	candles, err := s.fetchKlines(ctx, source, symbol, interval, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from Binance: %w", err)
 	}
 
 	// Store in database asynchronously for performance
-	go func() {
-		storeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	s.candleWriter.Add(candles...)
+
+	return candles, nil
+}
+
+// fetchKlines fetches the most recent klines from a symbol's exchange, over the Binance WS-API
+// when it is enabled; any WS-API failure falls back to the provider's REST client
+func (s *CandleService) fetchKlines(ctx context.Context, source marketdata.MarketDataProvider, symbol, interval string, limit int) ([]models.Candle, error) {
+	if s.useWSAPI(symbol) {
+		if candles, err := s.wsAPIKlines.GetKlinesOptimized(ctx, symbol, interval, limit); err == nil {
+			return candles, nil
+		}
+	}
+	return source.GetKlines(ctx, symbol, interval, limit)
+}
 
-		if err := s.candleRepo.BulkCreate(storeCtx, candles); err != nil {
-			// Log error but don't fail the main request
-			fmt.Printf("Warning: failed to store candles in database: %v\n", err)
+// fetchKlinesEndingAt fetches the klines opening at or before endTime, like fetchKlines
+func (s *CandleService) fetchKlinesEndingAt(ctx context.Context, source marketdata.MarketDataProvider, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	if s.useWSAPI(symbol) {
+		if candles, err := s.wsAPIKlines.GetKlinesEndingAt(ctx, symbol, interval, endTime, limit); err == nil {
+			return candles, nil
 		}
-	}()
+	}
+	return source.GetKlinesEndingAt(ctx, symbol, interval, endTime, limit)
+}
 
-	return candles, nil
+// useWSAPI reports whether a symbol's klines are tried over the Binance WS-API first
+func (s *CandleService) useWSAPI(symbol string) bool {
+	return s.wsAPIKlines != nil && s.wsAPIEnabled() && models.SymbolExchange(symbol) == models.ExchangeBinance
 }
 
 // getCachedResponse gets response from in-memory cache with expiry check
@@ -190,8 +493,12 @@ func (s *CandleService) GetOptimizedCandlesJSON(ctx context.Context, symbol, int
 
 // EXISTING METHODS (keeping for backward compatibility)
 
-// CreateCandle creates a new candle
+// CreateCandle creates a new candle, labeled as a manual fix unless it has a source
 func (s *CandleService) CreateCandle(ctx context.Context, candle *models.Candle) error {
+	if candle.Source == "" {
+		candle.Source = models.CandleSourceManualFix
+	}
+
 	// Validate candle data
 	if err := s.validateCandle(candle); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -236,18 +543,178 @@ func (s *CandleService) GetCandleRange(ctx context.Context, symbol, interval str
 	if interval == "" {
 		return nil, fmt.Errorf("interval is required")
 	}
+	if err := validateTimeRange(interval, startTime, endTime); err != nil {
+		return nil, err
+	}
+
+	return s.candleRepo.GetByTimeRange(ctx, symbol, interval, startTime, endTime)
+}
+
+// GetCandleTrades returns the persisted trades inside the candle opening at openTime, oldest first
+// At most limit trades are returned; the totals cover the returned trades only
+func (s *CandleService) GetCandleTrades(ctx context.Context, symbol, interval string, openTime time.Time, limit int) (*models.CandleTradesResponse, error) {
+	if s.tradeRepo == nil {
+		return nil, fmt.Errorf("trade storage is not configured")
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	closeTime, ok := models.CandleCloseTime(interval, openTime)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a %s candle open time", openTime.UTC().Format(time.RFC3339), interval)
+	}
+	lastTime := closeTime.Add(-time.Millisecond) // Trades are stored at millisecond precision
+
+	// Fetch one extra trade to detect truncation
+	trades, err := s.tradeRepo.GetByTimeRange(ctx, symbol, openTime, lastTime, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.CandleTradesResponse{
+		Symbol:    symbol,
+		Interval:  interval,
+		OpenTime:  openTime.UnixMilli(),
+		CloseTime: lastTime.UnixMilli(),
+		Trades:    trades,
+	}
+	if len(trades) > limit {
+		response.Trades = trades[:limit]
+		response.Truncated = true
+	}
+	if response.Trades == nil {
+		response.Trades = []models.TradeRecord{}
+	}
+	response.Count = len(response.Trades)
+
+	var notional float64
+	for _, trade := range response.Trades {
+		response.Volume += trade.Quantity
+		if trade.IsBuyerMaker {
+			response.SellVolume += trade.Quantity
+		} else {
+			response.BuyVolume += trade.Quantity
+		}
+		notional += trade.Price * trade.Quantity
+	}
+	response.Delta = response.BuyVolume - response.SellVolume
+	if response.Volume > 0 {
+		response.VWAP = notional / response.Volume
+	}
+
+	// Include the stored candle so clients can compare it against the rebuilt totals
+	candles, err := s.candleRepo.GetByTimeRange(ctx, symbol, interval, openTime, openTime)
+	if err != nil {
+		log.Printf("[CandleService] Failed to load %s %s candle at %s: %v", symbol, interval, openTime.UTC().Format(time.RFC3339), err)
+	} else if len(candles) > 0 {
+		response.Candle = &candles[0]
+	}
+
+	return response, nil
+}
+
+// GetCandleCoverage reports how many candles of a range are stored and which sources they came from,
+// and the collection pauses overlapping it with how many expected candles opened while paused
+// Expected counts assume fixed-length bars, so 1w and 1M ranges are approximate
+func (s *CandleService) GetCandleCoverage(ctx context.Context, symbol, interval string, startTime, endTime time.Time) (*models.CandleCoverage, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("invalid interval: %s", interval)
+	}
+	if startTime.IsZero() || endTime.IsZero() {
+		return nil, fmt.Errorf("start time and end time are required")
+	}
 	if startTime.After(endTime) {
 		return nil, fmt.Errorf("start time must be before end time")
 	}
 
-	return s.candleRepo.GetByTimeRange(ctx, symbol, interval, startTime, endTime)
+	stats, err := s.candleRepo.GetSourceStats(ctx, symbol, interval, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &models.CandleCoverage{
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Sources:   []models.CandleSourceStats{},
+		Pauses:    []models.SymbolPause{},
+	}
+
+	// Bars opening within [startTime, endTime]
+	step := duration.Milliseconds()
+	firstOpen := (startTime.UnixMilli() + step - 1) / step * step
+	if lastOpen := endTime.UnixMilli(); firstOpen <= lastOpen {
+		coverage.ExpectedCandles = (lastOpen-firstOpen)/step + 1
+	}
+
+	for _, stat := range stats {
+		coverage.StoredCandles += stat.Count
+	}
+	for _, stat := range stats {
+		stat.SharePercent = float64(stat.Count) / float64(coverage.StoredCandles) * 100
+		coverage.Sources = append(coverage.Sources, stat)
+	}
+
+	if missing := coverage.ExpectedCandles - coverage.StoredCandles; missing > 0 {
+		coverage.MissingCandles = missing
+	}
+	if coverage.ExpectedCandles > 0 {
+		coverage.CoveragePercent = math.Min(float64(coverage.StoredCandles)/float64(coverage.ExpectedCandles)*100, 100)
+	}
+
+	if s.collection != nil {
+		pauses, err := s.collection.PausesBetween(ctx, symbol, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		coverage.Paused = s.collection.IsPaused(symbol)
+		coverage.Pauses = pauses
+
+		// Bars opening within each pause (pauses of a symbol never overlap), up to now for pauses in effect
+		rangeEnd := endTime.UnixMilli() + 1
+		for _, pause := range pauses {
+			from := max(pause.PausedAt.UnixMilli(), startTime.UnixMilli())
+			to := rangeEnd
+			if end := pause.End(); !end.IsZero() && end.UnixMilli() < to {
+				to = end.UnixMilli()
+			}
+			if now := time.Now().UnixMilli(); now < to {
+				to = now
+			}
+			if to > from {
+				coverage.PausedCandles += (to+step-1)/step - (from+step-1)/step
+			}
+		}
+	}
+
+	return coverage, nil
+}
+
+// validateTimeRange rejects open-ended, inverted or oversized range queries so they never scan the whole table
+func validateTimeRange(interval string, startTime, endTime time.Time) error {
+	if startTime.IsZero() || endTime.IsZero() {
+		return fmt.Errorf("start time and end time are required")
+	}
+	if startTime.After(endTime) {
+		return fmt.Errorf("start time must be before end time")
+	}
+	if maxRange := models.MaxRangeDuration(interval); maxRange > 0 && endTime.Sub(startTime) > maxRange {
+		return fmt.Errorf("time range too large: at most %d %s candles (%s) per request", models.MaxRangeCandles, interval, maxRange)
+	}
+	return nil
 }
 
-// BulkCreateCandles creates multiple candles efficiently
+// BulkCreateCandles creates multiple candles efficiently, labeled as imported unless they have a source
 func (s *CandleService) BulkCreateCandles(ctx context.Context, candles []models.Candle) error {
 	if len(candles) == 0 {
 		return nil
 	}
+	models.LabelCandles(candles, models.CandleSourceImport)
 
 	// Validate all candles
 	for i, candle := range candles {
@@ -256,7 +723,7 @@ func (s *CandleService) BulkCreateCandles(ctx context.Context, candles []models.
 		}
 	}
 
-	return s.candleRepo.BulkCreate(ctx, candles)
+	return s.candleWriter.Write(ctx, candles)
 }
 
 // GetCandleStats returns statistics for candles
@@ -286,6 +753,20 @@ func (s *CandleService) GetCandleStats(ctx context.Context, symbol, interval str
 	return stats, nil
 }
 
+// InvalidateSymbol drops every cached response of a symbol
+func (s *CandleService) InvalidateSymbol(symbol string) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	prefix := symbol + ":"
+	for key := range s.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.cache, key)
+			delete(s.cacheExpiry, key)
+		}
+	}
+}
+
 // CleanupCache removes expired cache entries (call periodically)
 func (s *CandleService) CleanupCache() {
 	s.cacheMutex.Lock()
@@ -317,6 +798,9 @@ func (s *CandleService) validateCandle(candle *models.Candle) error {
 	if candle.OpenTime.After(candle.CloseTime) {
 		return fmt.Errorf("open time must be before close time")
 	}
+	if candle.Source != "" && !models.IsValidCandleSource(candle.Source) {
+		return fmt.Errorf("invalid source: %s", candle.Source)
+	}
 
 	return nil
 }
@@ -362,17 +846,18 @@ func (s *CandleService) GetBySymbolAndInterval(ctx context.Context, symbol, inte
 		log.Printf("[CandleService] No candles found in database, trying Binance API...")
 	}
 
-	// Fallback to Binance API if database is empty or fails
-	if s.binanceClient == nil {
-		err := fmt.Errorf("no data in database and Binance client is not available")
+	// Fallback to the exchange API if database is empty or fails
+	source := s.provider(symbol)
+	if source == nil {
+		err := fmt.Errorf("no data in database and %s client is not available", models.SymbolExchange(symbol))
 		log.Printf("[CandleService] ERROR: %v", err)
 		return nil, err
 	}
 
-	log.Printf("[CandleService] Fetching data from Binance API...")
+	log.Printf("[CandleService] Fetching data from %s API...", models.SymbolExchange(symbol))
 
-	// Get data from Binance using the optimized method
-	candles, err = s.binanceClient.GetKlinesOptimized(ctx, symbol, interval, limit)
+	// Get data from the exchange using the optimized method
+	candles, err = s.fetchKlines(ctx, source, symbol, interval, limit)
 	if err != nil {
 		err = fmt.Errorf("failed to get data from Binance API: %w", err)
 		log.Printf("[CandleService] Binance API error: %v", err)
@@ -382,14 +867,7 @@ func (s *CandleService) GetBySymbolAndInterval(ctx context.Context, symbol, inte
 	log.Printf("[CandleService] Retrieved %d candles from Binance API", len(candles))
 
 	// Store in database for future use (non-blocking)
-	go func() {
-		ctx := context.Background()
-		if err := s.candleRepo.BulkCreate(ctx, candles); err != nil {
-			log.Printf("[CandleService] WARNING: Failed to store candles in database: %v", err)
-		} else {
-			log.Printf("[CandleService] Successfully stored %d candles in database", len(candles))
-		}
-	}()
+	s.candleWriter.Add(candles...)
 
 	log.Printf("[CandleService] Returning %d candles to caller", len(candles))
 	return candles, nil
@@ -443,15 +921,16 @@ func (s *CandleService) GetOptimizedCandleData(ctx context.Context, symbol, inte
 
 	log.Printf("[CandleService] No optimized candles found in repository, fetching from Binance...")
 
-	// Fallback: fetch from Binance and store, then get optimized data
-	if s.binanceClient == nil {
-		err := fmt.Errorf("no data in repository and Binance client is not available")
+	// Fallback: fetch from the exchange and store, then get optimized data
+	source := s.provider(symbol)
+	if source == nil {
+		err := fmt.Errorf("no data in repository and %s client is not available", models.SymbolExchange(symbol))
 		log.Printf("[CandleService] ERROR: %v", err)
 		return nil, err
 	}
 
-	// Fetch from Binance
-	candles, err := s.binanceClient.GetKlinesOptimized(ctx, symbol, interval, limit)
+	// Fetch from the symbol's exchange
+	candles, err := s.fetchKlines(ctx, source, symbol, interval, limit)
 	if err != nil {
 		err = fmt.Errorf("failed to get data from Binance API: %w", err)
 		log.Printf("[CandleService] Binance API error: %v", err)
@@ -461,7 +940,7 @@ func (s *CandleService) GetOptimizedCandleData(ctx context.Context, symbol, inte
 	log.Printf("[CandleService] Retrieved %d candles from Binance API", len(candles))
 
 	// Store in database
-	if err := s.candleRepo.BulkCreate(ctx, candles); err != nil {
+	if err := s.candleWriter.Write(ctx, candles); err != nil {
 		log.Printf("[CandleService] WARNING: Failed to store candles in database: %v", err)
 		// Continue anyway, convert the fetched candles to optimized format
 	} else {
@@ -477,3 +956,46 @@ func (s *CandleService) GetOptimizedCandleData(ctx context.Context, symbol, inte
 	log.Printf("[CandleService] Returning %d optimized candles", len(optimizedCandles))
 	return optimizedCandles, nil
 }
+
+// GetOptimizedCandleDataBefore retrieves optimized candle data for the limit candles opening before a time
+// A short or gapped page in the repository is refetched from the symbol's exchange, ending just before the anchor
+func (s *CandleService) GetOptimizedCandleDataBefore(ctx context.Context, symbol, interval string, before time.Time, limit int) ([]models.OptimizedCandle, error) {
+	log.Printf("[CandleService] GetOptimizedCandleDataBefore called: symbol=%s, interval=%s, before=%s, limit=%d", symbol, interval, before.Format(time.RFC3339), limit)
+
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	if interval == "" {
+		return nil, fmt.Errorf("interval cannot be empty")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+
+	optimizedCandles, err := s.candleRepo.GetOptimizedCandleDataBefore(ctx, symbol, interval, before, limit)
+	if err != nil {
+		log.Printf("[CandleService] Repository error: %v", err)
+		return nil, fmt.Errorf("failed to get optimized candles from repository: %w", err)
+	}
+	// A short or gapped page is backfilled instead of serving the gap
+	source := s.provider(symbol)
+	if models.IsCompletePage(interval, models.OptimizedOpenTimes(optimizedCandles), before, limit) || source == nil {
+		return optimizedCandles, nil
+	}
+
+	candles, err := s.fetchKlinesEndingAt(ctx, source, symbol, interval, before.Add(-time.Millisecond), limit)
+	if err != nil {
+		if len(optimizedCandles) > 0 {
+			log.Printf("[CandleService] WARNING: serving %d stored candles before %s, Binance error: %v", len(optimizedCandles), before.Format(time.RFC3339), err)
+			return optimizedCandles, nil
+		}
+		return nil, fmt.Errorf("failed to get data from Binance API: %w", err)
+	}
+	s.storeCandlesAsync(models.LabelCandles(candles, models.CandleSourceBackfill))
+
+	optimizedCandles = make([]models.OptimizedCandle, len(candles))
+	for i, candle := range candles {
+		optimizedCandles[i] = candle.ToOptimized()
+	}
+	return optimizedCandles, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/models"
+)
+
+// volumeMultiplierTTL bounds how long a contract multiplier is reused before it is reloaded
+const volumeMultiplierTTL = 10 * time.Minute
+
+// ErrVolumeUnitUnsupported is returned when a candle series cannot be expressed in the requested volume unit
+var ErrVolumeUnitUnsupported = errors.New("volume unit is not supported for this series")
+
+// contractMultiplier is a cached contract size
+type contractMultiplier struct {
+	value   float64
+	expires time.Time
+}
+
+// ConvertVolume returns a copy of a last price candle response with V, BV and SV in another unit,
+// leaving the response itself (which may be cached) untouched. Stored volume is in the base asset,
+// except for Binance COIN-margined contracts whose klines count contracts; conversions between
+// base and quote use each candle's stored quote asset volume, estimated from the typical price
+// for candles not stored yet. An empty unit returns the response unchanged
+func (s *CandleService) ConvertVolume(ctx context.Context, response *models.CandleResponse, unit string) (*models.CandleResponse, error) {
+	if unit == "" || response == nil {
+		return response, nil
+	}
+	if !models.IsValidVolumeUnit(unit) {
+		return nil, fmt.Errorf("%w: unknown unit %q, use base, quote or contracts", ErrVolumeUnitUnsupported, unit)
+	}
+
+	exchange := models.SymbolExchange(response.S)
+	if exchange == models.ExchangeComposite || len(response.X) > 0 {
+		return nil, fmt.Errorf("%w: composite series combine several volumes", ErrVolumeUnitUnsupported)
+	}
+	if response.P != "" && response.P != models.PriceTypeLast {
+		return nil, fmt.Errorf("%w: %s price candles carry no volume", ErrVolumeUnitUnsupported, response.P)
+	}
+
+	coinMargined := exchange == models.ExchangeBinance && binance.IsCoinMargined(response.S)
+	storedUnit := models.VolumeUnitBase
+	if coinMargined {
+		storedUnit = models.VolumeUnitContracts
+	}
+
+	converted := *response
+	converted.D = append([]models.OptimizedCandle(nil), response.D...)
+	converted.U = unit
+	if unit == storedUnit || len(converted.D) == 0 {
+		return &converted, nil
+	}
+
+	multiplier := 1.0
+	if unit == models.VolumeUnitContracts || coinMargined {
+		if exchange != models.ExchangeBinance {
+			return nil, fmt.Errorf("%w: contract sizes are only known for Binance symbols", ErrVolumeUnitUnsupported)
+		}
+		var err error
+		if multiplier, err = s.contractMultiplier(ctx, response.S); err != nil {
+			return nil, err
+		}
+	}
+
+	// Linear quote and COIN-margined base volume are stored in the quote asset volume column
+	var stored map[int64]models.Candle
+	if unit != models.VolumeUnitContracts && !(coinMargined && unit == models.VolumeUnitQuote) {
+		candles, err := s.candleRepo.GetByTimeRange(ctx, response.S, response.I, time.UnixMilli(response.F), time.UnixMilli(response.L))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stored volume: %w", err)
+		}
+		stored = make(map[int64]models.Candle, len(candles))
+		for _, candle := range candles {
+			stored[candle.OpenTime.UnixMilli()] = candle
+		}
+	}
+
+	for i := range converted.D {
+		candle := &converted.D[i]
+		volume, buyVolume, exact := candle.V, candle.BV, false
+
+		switch {
+		case unit == models.VolumeUnitContracts:
+			volume = candle.V / multiplier
+		case coinMargined && unit == models.VolumeUnitQuote:
+			volume = candle.V * multiplier
+		default:
+			if row, ok := stored[candle.T]; ok && models.ParseFloat(row.QuoteAssetVolume) > 0 {
+				volume = models.ParseFloat(row.QuoteAssetVolume)
+				buyVolume = models.ParseFloat(row.TakerBuyQuoteAssetVolume)
+				exact = !candle.E
+			} else if typical := (candle.H + candle.L + candle.C) / 3; typical > 0 {
+				if coinMargined {
+					volume = candle.V * multiplier / typical
+				} else {
+					volume = candle.V * typical
+				}
+			}
+		}
+
+		// Taker volume without a stored counterpart keeps its share of the total
+		if !exact {
+			buyVolume = 0
+			if candle.V > 0 {
+				buyVolume = candle.BV * volume / candle.V
+			}
+		}
+		candle.V = volume
+		candle.BV = buyVolume
+		candle.SV = volume - buyVolume
+	}
+
+	return &converted, nil
+}
+
+// contractMultiplier returns the contract size of a Binance futures symbol: 1 for linear
+// contracts, the USD value of one contract for COIN-margined ones
+func (s *CandleService) contractMultiplier(ctx context.Context, symbol string) (float64, error) {
+	if s.symbolRepo == nil {
+		return 0, fmt.Errorf("%w: contract sizes are not available", ErrVolumeUnitUnsupported)
+	}
+
+	s.multiplierMutex.Lock()
+	cached, ok := s.multipliers[symbol]
+	s.multiplierMutex.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	stored, err := s.symbolRepo.GetBySymbol(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	if stored == nil || !stored.ContractMultiplier.Valid {
+		return 0, fmt.Errorf("%w: no contract size is known for %s", ErrVolumeUnitUnsupported, symbol)
+	}
+	stored.FillDisplay()
+
+	s.multiplierMutex.Lock()
+	s.multipliers[symbol] = contractMultiplier{value: stored.Display.ContractMultiplier, expires: time.Now().Add(volumeMultiplierTTL)}
+	s.multiplierMutex.Unlock()
+	return stored.Display.ContractMultiplier, nil
+}
+
+// VolumeConverter expresses candle volume in another unit
+type VolumeConverter interface {
+	ConvertVolume(ctx context.Context, response *models.CandleResponse, unit string) (*models.CandleResponse, error)
+}
+
+// SetVolumeConverter enables the volume unit parameter of aggregated candles
+func (s *AggregationService) SetVolumeConverter(converter VolumeConverter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.volumeConverter = converter
+}
+
+// ConvertVolume returns a copy of an aggregated candle response with its volume in another unit;
+// an empty unit returns the response unchanged
+func (s *AggregationService) ConvertVolume(ctx context.Context, response *models.CandleResponse, unit string) (*models.CandleResponse, error) {
+	if unit == "" {
+		return response, nil
+	}
+	s.mu.RLock()
+	converter := s.volumeConverter
+	s.mu.RUnlock()
+	if converter == nil {
+		return nil, fmt.Errorf("%w: volume conversion is not enabled", ErrVolumeUnitUnsupported)
+	}
+	return converter.ConvertVolume(ctx, response, unit)
+}
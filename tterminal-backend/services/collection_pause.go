@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// maxSymbolPauses caps the pauses returned per history request
+const maxSymbolPauses = 500
+
+var (
+	// ErrSymbolAlreadyPaused is returned when pausing a symbol that is paused
+	ErrSymbolAlreadyPaused = errors.New("symbol is already paused")
+	// ErrSymbolNotPaused is returned when resuming a symbol that is not paused
+	ErrSymbolNotPaused = errors.New("symbol is not paused")
+)
+
+// SetPauseStore enables pausing symbols: pauses are stored in pauseRepo and applied to the
+// stream updates hub publishes. Pauses in effect are loaded, and those whose until time passed
+// while the service was down are resumed as of that time
+func (s *DataCollectionService) SetPauseStore(pauseRepo *repositories.SymbolPauseRepository, hub transport.Publisher) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	active, err := pauseRepo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	if isNil(hub) {
+		hub = nil
+	}
+
+	s.mu.Lock()
+	s.pauseRepo = pauseRepo
+	s.hub = hub
+	s.mu.Unlock()
+
+	now := time.Now()
+	for i := range active {
+		pause := active[i]
+		if pause.Until != nil && !pause.Until.After(now) {
+			if _, err := pauseRepo.Expire(ctx, pause.ID); err != nil {
+				return err
+			}
+			log.Printf("[DataCollectionService] Pause of %s expired at %s while stopped", pause.Symbol, pause.Until.UTC().Format(time.RFC3339))
+			continue
+		}
+		s.applyPause(&pause)
+	}
+	return nil
+}
+
+// SetAnomalyPause pauses a symbol for duration when one of its closed 1m bars ranges (high - low)
+// more than movePct percent of its open, which is treated as a data anomaly such as a flash crash
+// The triggering bar is not stored. A movePct of 0 disables automatic pausing
+func (s *DataCollectionService) SetAnomalyPause(movePct float64, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.anomalyMovePct = movePct
+	s.anomalyPause = duration
+}
+
+// PauseSymbol stops collecting and storing a symbol's candles and delivering its stream updates
+// until it is resumed or, when set, until pause.Until. The pause is stored for coverage reports
+func (s *DataCollectionService) PauseSymbol(ctx context.Context, pause *models.SymbolPause) (*models.SymbolPause, error) {
+	s.mu.RLock()
+	pauseRepo := s.pauseRepo
+	s.mu.RUnlock()
+	if pauseRepo == nil {
+		return nil, fmt.Errorf("symbol pausing is not available")
+	}
+
+	pause.Symbol = strings.TrimSpace(pause.Symbol)
+	if pause.Symbol == "" {
+		return nil, fmt.Errorf("validation failed: symbol is required")
+	}
+	if len(pause.Reason) > 500 {
+		return nil, fmt.Errorf("validation failed: reason must be at most 500 characters")
+	}
+	pause.PausedAt = time.Now().UTC().Truncate(time.Microsecond)
+	if pause.Until != nil && !pause.Until.After(pause.PausedAt) {
+		return nil, fmt.Errorf("validation failed: until must be in the future")
+	}
+	if pause.Source == "" {
+		pause.Source = models.SymbolPauseManual
+	}
+
+	created, err := pauseRepo.Create(ctx, pause)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrSymbolAlreadyPaused
+	}
+
+	s.applyPause(pause)
+	log.Printf("[DataCollectionService] Paused %s (%s): %s", pause.Symbol, pause.Source, pause.Reason)
+	return pause, nil
+}
+
+// ResumeSymbol ends a symbol's pause, resuming its collection and stream updates
+func (s *DataCollectionService) ResumeSymbol(ctx context.Context, symbol, resumedBy string) (*models.SymbolPause, error) {
+	s.mu.RLock()
+	pauseRepo := s.pauseRepo
+	s.mu.RUnlock()
+	if pauseRepo == nil {
+		return nil, fmt.Errorf("symbol pausing is not available")
+	}
+
+	pause, err := pauseRepo.Resume(ctx, symbol, time.Now().UTC().Truncate(time.Microsecond), resumedBy)
+	if err != nil {
+		return nil, err
+	}
+	if pause == nil {
+		return nil, ErrSymbolNotPaused
+	}
+
+	s.releasePause(symbol, pause.ID)
+	log.Printf("[DataCollectionService] Resumed %s", symbol)
+	return pause, nil
+}
+
+// IsPaused reports whether a symbol's collection is paused
+func (s *DataCollectionService) IsPaused(symbol string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, paused := s.pauses[symbol]
+	return paused
+}
+
+// PausedSymbols returns the pauses in effect, by symbol
+func (s *DataCollectionService) PausedSymbols() []models.SymbolPause {
+	s.mu.RLock()
+	pauses := make([]models.SymbolPause, 0, len(s.pauses))
+	for _, pause := range s.pauses {
+		pauses = append(pauses, *pause)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i].Symbol < pauses[j].Symbol })
+	return pauses
+}
+
+// PauseHistory returns the most recent pauses of a symbol, or of every symbol when symbol is empty
+func (s *DataCollectionService) PauseHistory(ctx context.Context, symbol string, limit int) ([]models.SymbolPause, error) {
+	s.mu.RLock()
+	pauseRepo := s.pauseRepo
+	s.mu.RUnlock()
+	if pauseRepo == nil {
+		return []models.SymbolPause{}, nil
+	}
+
+	if limit <= 0 || limit > maxSymbolPauses {
+		limit = maxSymbolPauses
+	}
+	return pauseRepo.List(ctx, symbol, limit)
+}
+
+// PausesBetween returns a symbol's pauses overlapping a time range, oldest first
+func (s *DataCollectionService) PausesBetween(ctx context.Context, symbol string, startTime, endTime time.Time) ([]models.SymbolPause, error) {
+	s.mu.RLock()
+	pauseRepo := s.pauseRepo
+	s.mu.RUnlock()
+	if pauseRepo == nil {
+		return []models.SymbolPause{}, nil
+	}
+
+	return pauseRepo.GetOverlapping(ctx, symbol, startTime, endTime)
+}
+
+// checkAnomaly pauses a bar's symbol when the bar is a closed 1m bar whose range exceeds the
+// anomaly threshold. Reports whether the symbol was paused
+func (s *DataCollectionService) checkAnomaly(bar models.BarClose) bool {
+	s.mu.RLock()
+	movePct, duration := s.anomalyMovePct, s.anomalyPause
+	s.mu.RUnlock()
+	if movePct <= 0 || bar.Interval != "1m" || bar.Open <= 0 {
+		return false
+	}
+
+	move := (bar.High - bar.Low) / bar.Open * 100
+	if move < movePct {
+		return false
+	}
+
+	until := time.Now().UTC().Add(duration)
+	pause := &models.SymbolPause{
+		Symbol: bar.Symbol,
+		Source: models.SymbolPauseAnomaly,
+		Reason: fmt.Sprintf("1m bar at %s ranged %.2f%% (high %g, low %g)",
+			time.UnixMilli(bar.OpenTime).UTC().Format("2006-01-02 15:04"), move, bar.High, bar.Low),
+		Until: &until,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := s.PauseSymbol(ctx, pause); err != nil && !errors.Is(err, ErrSymbolAlreadyPaused) {
+		log.Printf("[DataCollectionService] ERROR pausing %s after anomaly: %v", bar.Symbol, err)
+		return false
+	}
+	return true
+}
+
+// applyPause records a pause in effect, pauses the symbol's stream updates and schedules its end
+func (s *DataCollectionService) applyPause(pause *models.SymbolPause) {
+	var until time.Time
+	if pause.Until != nil {
+		until = *pause.Until
+	}
+
+	s.mu.Lock()
+	if timer := s.pauseTimers[pause.Symbol]; timer != nil {
+		timer.Stop()
+		delete(s.pauseTimers, pause.Symbol)
+	}
+	s.pauses[pause.Symbol] = pause
+	if !until.IsZero() {
+		symbol, id := pause.Symbol, pause.ID
+		s.pauseTimers[symbol] = time.AfterFunc(time.Until(until), func() { s.expirePause(symbol, id) })
+	}
+	hub := s.hub
+	s.mu.Unlock()
+
+	if hub != nil {
+		hub.SetSymbolPaused(pause.Symbol, true, pause.Reason, until)
+	}
+}
+
+// releasePause forgets a symbol's pause and resumes its stream updates, unless the pause in
+// effect is another one
+func (s *DataCollectionService) releasePause(symbol string, id int64) {
+	s.mu.Lock()
+	if pause, paused := s.pauses[symbol]; paused && pause.ID != id {
+		s.mu.Unlock()
+		return
+	}
+	if timer := s.pauseTimers[symbol]; timer != nil {
+		timer.Stop()
+		delete(s.pauseTimers, symbol)
+	}
+	delete(s.pauses, symbol)
+	hub := s.hub
+	s.mu.Unlock()
+
+	if hub != nil {
+		hub.SetSymbolPaused(symbol, false, "", time.Time{})
+	}
+}
+
+// expirePause resumes a symbol whose pause reached its until time, unless that pause already
+// ended. Storing the end is retried every minute until it succeeds
+func (s *DataCollectionService) expirePause(symbol string, id int64) {
+	s.mu.RLock()
+	pauseRepo := s.pauseRepo
+	s.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := pauseRepo.Expire(ctx, id); err != nil {
+		log.Printf("[DataCollectionService] ERROR ending expired pause of %s, retrying in a minute: %v", symbol, err)
+		s.mu.Lock()
+		if pause, paused := s.pauses[symbol]; paused && pause.ID == id {
+			s.pauseTimers[symbol] = time.AfterFunc(time.Minute, func() { s.expirePause(symbol, id) })
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	s.releasePause(symbol, id)
+	log.Printf("[DataCollectionService] Pause of %s expired, resumed", symbol)
+}
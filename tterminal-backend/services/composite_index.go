@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+const (
+	// compositeIndexMinuteSpan is the longest span built from 1m bars; longer spans weight each
+	// exchange's bars of the interval itself
+	compositeIndexMinuteSpan = 7 * 24 * time.Hour
+	// compositeIndexPage is how many bars are read per constituent request
+	compositeIndexPage = 1000
+)
+
+// ErrNoIndexConstituents is returned when a symbol has no cross-exchange mappings to build an index from
+var ErrNoIndexConstituents = errors.New("symbol has no exchange mappings for a composite index")
+
+// IndexConstituents resolves a symbol to the exchange symbol keys of its composite index
+type IndexConstituents interface {
+	IndexConstituents(symbol string) (canonical string, symbols []string, err error)
+}
+
+// SetIndexConstituents enables composite index candles (source=composite)
+func (s *AggregationService) SetIndexConstituents(constituents IndexConstituents) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.constituents = constituents
+}
+
+// GetCompositeCandlesBefore returns the limit composite index candles opening before a time (the
+// latest candles for a zero time). Each bar is the volume-weighted average of the exchanges' bars
+// opening at the same minute, rolled up to the interval; exchanges missing a minute are left out of
+// it, and an exchange that cannot be read is left out of the index
+func (s *AggregationService) GetCompositeCandlesBefore(ctx context.Context, symbol, interval string, before time.Time, limit int) (*models.CandleResponse, error) {
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval %q", interval)
+	}
+	if limit <= 0 || limit > 5000 {
+		return nil, fmt.Errorf("limit must be between 1 and 5000, got %d", limit)
+	}
+
+	s.mu.RLock()
+	constituents := s.constituents
+	s.mu.RUnlock()
+	if constituents == nil {
+		return nil, fmt.Errorf("composite index candles are not configured")
+	}
+	canonical, symbols, err := constituents.IndexConstituents(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	anchored := !before.IsZero() && before.Before(time.Now())
+	end := before
+	if !anchored {
+		end = time.Now()
+	}
+	end = end.Truncate(duration)
+	if !anchored {
+		end = end.Add(duration) // Include the open bar
+	}
+	start := end.Add(-time.Duration(limit) * duration)
+
+	cacheKey := fmt.Sprintf("agg:candles:composite:%s:%s:%d", canonical, interval, limit)
+	if anchored {
+		cacheKey += fmt.Sprintf(":before:%d", before.UnixMilli())
+	}
+	if cached := s.getFromMemCache(cacheKey); cached != nil {
+		if response, ok := cached.Data.(*models.CandleResponse); ok {
+			return response, nil
+		}
+	}
+
+	sourceInterval := "1m"
+	if time.Duration(limit)*duration > compositeIndexMinuteSpan {
+		sourceInterval = interval
+	}
+	sourceDuration, _ := models.IntervalDuration(sourceInterval)
+	count := int(end.Sub(start) / sourceDuration)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	bars := make(map[string][]models.OptimizedCandle, len(symbols))
+	for _, constituent := range symbols {
+		wg.Add(1)
+		go func(constituent string) {
+			defer wg.Done()
+			candles, err := s.indexConstituentBars(ctx, constituent, sourceInterval, end, count)
+			if err != nil {
+				log.Printf("[AggregationService] Leaving %s out of the %s composite index: %v", constituent, canonical, err)
+				return
+			}
+			mu.Lock()
+			bars[constituent] = candles
+			mu.Unlock()
+		}(constituent)
+	}
+	wg.Wait()
+
+	if len(bars) == 0 {
+		err := fmt.Errorf("no exchange data for the %s composite index", canonical)
+		s.trackError(err)
+		return nil, err
+	}
+
+	candles := rollUpOptimized(volumeWeightedBars(bars, start, end), duration)
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+
+	response := &models.CandleResponse{
+		S: canonical,
+		I: interval,
+		D: candles,
+		N: len(candles),
+		X: make([]string, 0, len(bars)),
+	}
+	for constituent := range bars {
+		response.X = append(response.X, constituent)
+	}
+	sort.Strings(response.X)
+	if response.N > 0 {
+		response.F = candles[0].T
+		response.L = candles[response.N-1].T
+	}
+	if anchored {
+		response.SetNextCursor(limit)
+	} else if response.N > 0 {
+		response.NextCursor = response.F
+	}
+
+	s.setMemCache(cacheKey, response, 30*time.Second)
+	return response, nil
+}
+
+// indexConstituentBars reads count bars of a constituent opening before end, paging backwards
+func (s *AggregationService) indexConstituentBars(ctx context.Context, symbol, interval string, end time.Time, count int) ([]models.OptimizedCandle, error) {
+	var candles []models.OptimizedCandle
+	cursor := end
+	for len(candles) < count {
+		page, err := s.candleService.GetOptimizedCandleDataBefore(ctx, symbol, interval, cursor, min(count-len(candles), compositeIndexPage))
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		candles = append(page, candles...)
+		cursor = time.UnixMilli(page[0].T)
+	}
+	return candles, nil
+}
+
+// volumeWeightedBars combines the exchanges' bars opening at the same time within [start, end),
+// weighting prices by volume (equally when no exchange traded), oldest first
+func volumeWeightedBars(bars map[string][]models.OptimizedCandle, start, end time.Time) []models.OptimizedCandle {
+	byTime := make(map[int64][]models.OptimizedCandle)
+	for _, candles := range bars {
+		for _, candle := range candles {
+			if candle.T >= start.UnixMilli() && candle.T < end.UnixMilli() {
+				byTime[candle.T] = append(byTime[candle.T], candle)
+			}
+		}
+	}
+
+	result := make([]models.OptimizedCandle, 0, len(byTime))
+	for openTime, group := range byTime {
+		var volume float64
+		for _, candle := range group {
+			volume += candle.V
+		}
+
+		bar := models.OptimizedCandle{T: openTime}
+		for _, candle := range group {
+			weight := 1 / float64(len(group))
+			if volume > 0 {
+				weight = candle.V / volume
+			}
+			bar.O += candle.O * weight
+			bar.H += candle.H * weight
+			bar.L += candle.L * weight
+			bar.C += candle.C * weight
+			bar.V += candle.V
+			bar.BV += candle.BV
+			bar.SV += candle.SV
+			bar.E = bar.E || candle.E
+		}
+		result = append(result, bar)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].T < result[j].T })
+	return result
+}
+
+// rollUpOptimized combines bars, oldest first, into bars of a longer interval
+func rollUpOptimized(candles []models.OptimizedCandle, duration time.Duration) []models.OptimizedCandle {
+	var result []models.OptimizedCandle
+	for _, candle := range candles {
+		bucket := time.UnixMilli(candle.T).Truncate(duration).UnixMilli()
+		n := len(result)
+		if n == 0 || result[n-1].T != bucket {
+			candle.T = bucket
+			result = append(result, candle)
+			continue
+		}
+
+		bar := &result[n-1]
+		bar.H = max(bar.H, candle.H)
+		bar.L = min(bar.L, candle.L)
+		bar.C = candle.C
+		bar.V += candle.V
+		bar.BV += candle.BV
+		bar.SV += candle.SV
+		bar.E = bar.E || candle.E
+	}
+	return result
+}
@@ -0,0 +1,534 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxCompositesPerUser caps the composites a user can define
+	maxCompositesPerUser = 20
+	// maxCompositeKlines caps the klines computed per request
+	maxCompositeKlines = 1000
+	// maxCompositeMinutes is the longest span computed from constituent 1m bars; longer spans
+	// are computed from constituent bars of the requested interval
+	maxCompositeMinutes = 7 * 24 * 60
+	// compositeBackfillBars is the history computed and stored per interval when a composite is created
+	compositeBackfillBars = 500
+	// compositePendingWindow is how long constituent bar closes wait for the rest of a minute
+	compositePendingWindow = 10 * time.Minute
+)
+
+// compositeIntervals are the intervals composite bars are closed and stored for live
+var compositeIntervals = []string{"1m", "5m", "15m"}
+
+// compiledComposite is a composite with its parsed expression
+type compiledComposite struct {
+	composite  models.CompositeSymbol
+	expression *models.CompositeExpression
+	// Constituent 1m bars by open time (Unix milliseconds) until every constituent has closed
+	pending map[int64]map[string]models.Candle
+	// Most recent closed composite 1m bars, oldest first, for closing 5m and 15m bars
+	recent []models.Candle
+}
+
+// CompositeService manages user-defined composite symbols (spreads and ratios) and computes
+// their candles from constituent 1m bars. It is the kline source of "COMPOSITE:" symbols, so
+// composite candles are served, cached and stored like any exchange's. Live composite bars are
+// closed once every constituent's 1m bar has closed, then stored and published on the stream
+type CompositeService struct {
+	compositeRepo *repositories.CompositeRepository
+	candleService *CandleService
+	stream        *websocket.CompositeStream // Optional live streaming
+
+	mu         sync.Mutex
+	composites map[string]*compiledComposite // Keyed by composite symbol
+}
+
+// NewCompositeService creates a new composite service
+func NewCompositeService(compositeRepo *repositories.CompositeRepository, candleService *CandleService) *CompositeService {
+	if compositeRepo == nil {
+		log.Fatalf("[CompositeService] CRITICAL: compositeRepo cannot be nil")
+	}
+	if candleService == nil {
+		log.Fatalf("[CompositeService] CRITICAL: candleService cannot be nil")
+	}
+	log.Printf("[CompositeService] Successfully initialized")
+	return &CompositeService{
+		compositeRepo: compositeRepo,
+		candleService: candleService,
+		composites:    make(map[string]*compiledComposite),
+	}
+}
+
+// SetStream enables live composite prices and klines
+func (s *CompositeService) SetStream(stream *websocket.CompositeStream) {
+	s.stream = stream
+}
+
+// Start loads every stored composite for live computation
+func (s *CompositeService) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	composites, err := s.compositeRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, composite := range composites {
+		if _, err := s.register(composite); err != nil {
+			log.Printf("[CompositeService] WARNING: skipping composite %s: %v", composite.Name, err)
+		}
+	}
+
+	log.Printf("[CompositeService] Loaded %d composites", len(composites))
+	return nil
+}
+
+// CreateComposite defines a new composite for a user and stores its recent history
+func (s *CompositeService) CreateComposite(ctx context.Context, userID string, req *models.CreateCompositeRequest) (*models.CompositeSymbol, error) {
+	name := strings.ToUpper(strings.TrimSpace(req.Name))
+	if !models.IsValidCompositeName(name) {
+		return nil, fmt.Errorf("validation failed: name must be 1-32 letters, digits or underscores")
+	}
+	expressionText := strings.TrimSpace(req.Expression)
+	expression, err := models.ParseCompositeExpression(expressionText)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	for _, symbol := range expression.Constituents() {
+		if !models.IsValidExchange(models.SymbolExchange(symbol)) {
+			return nil, fmt.Errorf("validation failed: %s is not on a supported exchange", symbol)
+		}
+		if s.candleService.provider(symbol) == nil {
+			return nil, fmt.Errorf("validation failed: %s data is not available", models.SymbolExchange(symbol))
+		}
+	}
+
+	owned, err := s.compositeRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) >= maxCompositesPerUser {
+		return nil, fmt.Errorf("validation failed: at most %d composites per user", maxCompositesPerUser)
+	}
+	existing, err := s.compositeRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("validation failed: composite name %s is taken", name)
+	}
+
+	composite := models.CompositeSymbol{
+		UserID:     userID,
+		Name:       name,
+		Expression: expressionText,
+	}
+	if err := s.compositeRepo.Create(ctx, &composite); err != nil {
+		return nil, err
+	}
+	compiled, err := s.register(composite)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.backfill(compiled.composite.Symbol)
+
+	return &compiled.composite, nil
+}
+
+// GetComposites returns all composites of a user
+func (s *CompositeService) GetComposites(ctx context.Context, userID string) ([]models.CompositeSymbol, error) {
+	composites, err := s.compositeRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range composites {
+		describeComposite(&composites[i])
+	}
+	return composites, nil
+}
+
+// GetComposite returns a composite owned by the user
+func (s *CompositeService) GetComposite(ctx context.Context, userID string, id int64) (*models.CompositeSymbol, error) {
+	composite, err := s.compositeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Composites of other users are reported as missing
+	if composite == nil || composite.UserID != userID {
+		return nil, fmt.Errorf("composite not found")
+	}
+
+	describeComposite(composite)
+	return composite, nil
+}
+
+// DeleteComposite deletes a composite and its stored candles
+func (s *CompositeService) DeleteComposite(ctx context.Context, userID string, id int64) error {
+	composite, err := s.GetComposite(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.compositeRepo.Delete(ctx, composite); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.composites, composite.Symbol)
+	s.mu.Unlock()
+	if s.stream != nil {
+		s.stream.RemoveComposite(composite.Symbol)
+	}
+	s.candleService.InvalidateSymbol(composite.Symbol)
+	return nil
+}
+
+// GetKlinesOptimized computes the most recent klines of a composite
+func (s *CompositeService) GetKlinesOptimized(ctx context.Context, symbol, interval string, limit int) ([]models.Candle, error) {
+	return s.GetKlinesEndingAt(ctx, symbol, interval, time.Now(), limit)
+}
+
+// GetKlinesEndingAt computes the limit klines of a composite opening at or before endTime
+// Spans of up to a week are computed from constituent 1m bars and aggregated; longer spans use
+// constituent bars of the interval itself, so their highs and lows are looser bounds. Bars
+// missing for any constituent are skipped
+func (s *CompositeService) GetKlinesEndingAt(ctx context.Context, symbol, interval string, endTime time.Time, limit int) ([]models.Candle, error) {
+	s.mu.Lock()
+	compiled, exists := s.composites[strings.ToUpper(symbol)]
+	s.mu.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("composite %s not found", symbol)
+	}
+
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval %s", interval)
+	}
+	if limit <= 0 || limit > maxCompositeKlines {
+		limit = maxCompositeKlines
+	}
+
+	end := endTime.Truncate(duration)
+	start := end.Add(-time.Duration(limit-1) * duration)
+	base := interval
+	if duration >= time.Minute && end.Add(duration).Sub(start) <= maxCompositeMinutes*time.Minute {
+		base = "1m"
+	}
+	baseDuration, _ := models.IntervalDuration(base)
+	baseEnd := end.Add(duration - baseDuration)
+
+	// Constituent bars by open time, keeping only times every constituent has
+	constituents := compiled.expression.Constituents()
+	bars := make(map[int64]map[string]models.Candle)
+	for i, constituent := range constituents {
+		candles, err := s.constituentBars(ctx, constituent, base, start, baseEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s candles: %w", constituent, err)
+		}
+		present := make(map[int64]bool, len(candles))
+		for _, candle := range candles {
+			openTime := candle.OpenTime.UnixMilli()
+			if i == 0 {
+				bars[openTime] = make(map[string]models.Candle, len(constituents))
+			} else if bars[openTime] == nil {
+				continue
+			}
+			bars[openTime][constituent] = candle
+			present[openTime] = true
+		}
+		for openTime := range bars {
+			if !present[openTime] {
+				delete(bars, openTime)
+			}
+		}
+	}
+
+	openTimes := make([]int64, 0, len(bars))
+	for openTime := range bars {
+		openTimes = append(openTimes, openTime)
+	}
+	sort.Slice(openTimes, func(i, j int) bool { return openTimes[i] < openTimes[j] })
+
+	computed := make([]models.Candle, 0, len(openTimes))
+	for _, openTime := range openTimes {
+		candle, err := compiled.candle(bars[openTime], base, time.UnixMilli(openTime))
+		if err != nil {
+			continue
+		}
+		computed = append(computed, candle)
+	}
+
+	if base == interval {
+		return computed, nil
+	}
+	return aggregateCompositeBars(computed, interval), nil
+}
+
+// HandleBarClose collects closed constituent 1m bars and closes the composite minute once every
+// constituent has closed it, then closes 5m and 15m bars on their boundaries
+func (s *CompositeService) HandleBarClose(bar models.BarClose) {
+	if bar.Interval != "1m" {
+		return
+	}
+	candle := bar.Candle
+
+	var closed []models.Candle
+	s.mu.Lock()
+	for _, compiled := range s.composites {
+		if !compiled.hasConstituent(bar.Symbol) {
+			continue
+		}
+		closed = append(closed, compiled.addBar(bar.Symbol, bar.OpenTime, candle)...)
+	}
+	s.mu.Unlock()
+
+	if len(closed) == 0 {
+		return
+	}
+
+	models.LabelCandles(closed, models.CandleSourceStream)
+	s.candleService.storeCandlesAsync(closed)
+	for _, candle := range closed {
+		s.candleService.InvalidateSymbol(candle.Symbol)
+		if s.stream != nil {
+			s.stream.PublishClosedBar(candle)
+		}
+	}
+}
+
+// register compiles a composite and starts computing it live
+func (s *CompositeService) register(composite models.CompositeSymbol) (*compiledComposite, error) {
+	expression, err := models.ParseCompositeExpression(composite.Expression)
+	if err != nil {
+		return nil, err
+	}
+	describeComposite(&composite)
+
+	compiled := &compiledComposite{
+		composite:  composite,
+		expression: expression,
+		pending:    make(map[int64]map[string]models.Candle),
+	}
+
+	s.mu.Lock()
+	s.composites[composite.Symbol] = compiled
+	s.mu.Unlock()
+	if s.stream != nil {
+		s.stream.SetComposite(composite.Symbol, expression)
+	}
+	return compiled, nil
+}
+
+// backfill computes and stores recent history of a new composite for its live intervals, so
+// charts open with history rather than only the bars closed since creation
+func (s *CompositeService) backfill(symbol string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	for _, interval := range compositeIntervals {
+		candles, err := s.GetKlinesOptimized(ctx, symbol, interval, compositeBackfillBars)
+		if err != nil {
+			log.Printf("[CompositeService] WARNING: failed to backfill %s/%s: %v", symbol, interval, err)
+			continue
+		}
+		// The last bar is still forming
+		if len(candles) > 0 {
+			candles = candles[:len(candles)-1]
+		}
+		models.LabelCandles(candles, models.CandleSourceBackfill)
+		s.candleService.storeCandlesAsync(candles)
+	}
+	s.candleService.InvalidateSymbol(symbol)
+}
+
+// constituentBars returns a constituent's bars opening within [start, end], from stored candles
+// when they cover the range and from its exchange otherwise
+func (s *CompositeService) constituentBars(ctx context.Context, symbol, interval string, start, end time.Time) ([]models.Candle, error) {
+	duration, _ := models.IntervalDuration(interval)
+	expected := int(end.Sub(start)/duration) + 1
+
+	stored, err := s.candleService.GetByTimeRange(ctx, symbol, interval, start, end)
+	if err == nil && len(stored) >= expected {
+		return stored, nil
+	}
+
+	source := s.candleService.provider(symbol)
+	if source == nil {
+		return nil, fmt.Errorf("no %s client is available", models.SymbolExchange(symbol))
+	}
+
+	// Page backwards from end; exchanges cap the klines per request
+	var candles []models.Candle
+	cursor := end
+	for len(candles) < expected {
+		page, err := source.GetKlinesEndingAt(ctx, symbol, interval, cursor, expected-len(candles))
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		candles = append(page, candles...)
+		oldest := page[0].OpenTime
+		if !oldest.After(start) {
+			break
+		}
+		cursor = oldest.Add(-duration)
+	}
+
+	inRange := candles[:0]
+	for _, candle := range candles {
+		if !candle.OpenTime.Before(start) && !candle.OpenTime.After(end) {
+			inRange = append(inRange, candle)
+		}
+	}
+	return inRange, nil
+}
+
+// hasConstituent reports whether symbol is a constituent of the composite
+func (c *compiledComposite) hasConstituent(symbol string) bool {
+	for _, constituent := range c.expression.Constituents() {
+		if constituent == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// addBar records a closed constituent 1m bar and returns the composite bars it closes
+func (c *compiledComposite) addBar(symbol string, openTime int64, candle models.Candle) []models.Candle {
+	// Bars too old to complete are dropped
+	for pendingTime := range c.pending {
+		if pendingTime < openTime-compositePendingWindow.Milliseconds() {
+			delete(c.pending, pendingTime)
+		}
+	}
+
+	bars, exists := c.pending[openTime]
+	if !exists {
+		bars = make(map[string]models.Candle, len(c.expression.Constituents()))
+		c.pending[openTime] = bars
+	}
+	bars[symbol] = candle
+	if len(bars) < len(c.expression.Constituents()) {
+		return nil
+	}
+	delete(c.pending, openTime)
+
+	minute, err := c.candle(bars, "1m", time.UnixMilli(openTime))
+	if err != nil {
+		return nil
+	}
+	closed := []models.Candle{minute}
+
+	c.recent = append(c.recent, minute)
+	if len(c.recent) > 15 {
+		c.recent = c.recent[len(c.recent)-15:]
+	}
+
+	// A minute ending on an interval boundary closes that interval's bar when all its minutes closed
+	closeTime := minute.OpenTime.Add(time.Minute)
+	for _, interval := range compositeIntervals[1:] {
+		duration, _ := models.IntervalDuration(interval)
+		if !closeTime.Truncate(duration).Equal(closeTime) {
+			continue
+		}
+		barStart := closeTime.Add(-duration)
+		var minutes []models.Candle
+		for _, recent := range c.recent {
+			if !recent.OpenTime.Before(barStart) {
+				minutes = append(minutes, recent)
+			}
+		}
+		if len(minutes) != int(duration/time.Minute) {
+			continue
+		}
+		closed = append(closed, aggregateCompositeBars(minutes, interval)...)
+	}
+
+	return closed
+}
+
+// candle computes the composite bar of an interval from one bar per constituent
+func (c *compiledComposite) candle(bars map[string]models.Candle, interval string, openTime time.Time) (models.Candle, error) {
+	open, high, low, close, err := c.expression.EvaluateCandle(bars)
+	if err != nil {
+		return models.Candle{}, err
+	}
+	duration, _ := models.IntervalDuration(interval)
+	return compositeCandle(c.composite.Symbol, interval, openTime, duration, open, high, low, close), nil
+}
+
+// aggregateCompositeBars combines composite bars, oldest first, into bars of a longer interval
+func aggregateCompositeBars(candles []models.Candle, interval string) []models.Candle {
+	duration, _ := models.IntervalDuration(interval)
+
+	var result []models.Candle
+	var openTime time.Time
+	var open, high, low, close float64
+	flush := func() {
+		if !openTime.IsZero() {
+			result = append(result, compositeCandle(candles[0].Symbol, interval, openTime, duration, open, high, low, close))
+		}
+	}
+
+	for _, candle := range candles {
+		bucket := candle.OpenTime.Truncate(duration)
+		barHigh, barLow := models.ParseFloat(candle.High), models.ParseFloat(candle.Low)
+		if !bucket.Equal(openTime) {
+			flush()
+			openTime = bucket
+			open, high, low = models.ParseFloat(candle.Open), barHigh, barLow
+		}
+		if barHigh > high {
+			high = barHigh
+		}
+		if barLow < low {
+			low = barLow
+		}
+		close = models.ParseFloat(candle.Close)
+	}
+	flush()
+
+	return result
+}
+
+// compositeCandle builds a stored composite candle; composites have no volume of their own
+func compositeCandle(symbol, interval string, openTime time.Time, duration time.Duration, open, high, low, close float64) models.Candle {
+	return models.Candle{
+		Symbol:                   symbol,
+		OpenTime:                 openTime,
+		Open:                     strconv.FormatFloat(open, 'f', -1, 64),
+		High:                     strconv.FormatFloat(high, 'f', -1, 64),
+		Low:                      strconv.FormatFloat(low, 'f', -1, 64),
+		Close:                    strconv.FormatFloat(close, 'f', -1, 64),
+		Volume:                   "0",
+		CloseTime:                openTime.Add(duration - time.Millisecond),
+		QuoteAssetVolume:         "0",
+		TakerBuyBaseAssetVolume:  "0",
+		TakerBuyQuoteAssetVolume: "0",
+		Interval:                 interval,
+		PriceType:                models.PriceTypeLast,
+	}
+}
+
+// describeComposite fills a stored composite's symbol and constituents
+func describeComposite(composite *models.CompositeSymbol) {
+	composite.Symbol = models.QualifySymbol(models.ExchangeComposite, composite.Name)
+	composite.Constituents = []string{}
+	if expression, err := models.ParseCompositeExpression(composite.Expression); err == nil {
+		composite.Constituents = expression.Constituents()
+	}
+}
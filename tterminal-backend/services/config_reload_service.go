@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	// configEnvFile is re-read on every reload; its values override the process environment
+	configEnvFile = ".env"
+	// maxConfigAuditEntries caps the audit entries returned per request
+	maxConfigAuditEntries = 500
+	// configAuditTimeout bounds recording a SIGHUP reload
+	configAuditTimeout = 5 * time.Second
+)
+
+// ConfigReloadService reloads the runtime settings (log level, request throttle, feature toggles)
+// on SIGHUP or an admin request. The configuration is loaded and validated exactly as at startup;
+// a valid one has its runtime settings applied in place, so streams and WebSocket clients stay
+// connected, while an invalid one changes nothing. Every reload is recorded in the audit log
+type ConfigReloadService struct {
+	cfg       *config.Config
+	auditRepo *repositories.ConfigAuditRepository
+
+	reloadMu  sync.Mutex // One reload at a time
+	mu        sync.Mutex
+	signals   chan os.Signal
+	isRunning bool
+	stopChan  chan struct{}
+}
+
+// NewConfigReloadService creates a new config reload service
+func NewConfigReloadService(cfg *config.Config, auditRepo *repositories.ConfigAuditRepository) *ConfigReloadService {
+	if cfg == nil {
+		log.Fatalf("[ConfigReloadService] CRITICAL: cfg cannot be nil")
+	}
+	if auditRepo == nil {
+		log.Fatalf("[ConfigReloadService] CRITICAL: auditRepo cannot be nil")
+	}
+
+	log.Printf("[ConfigReloadService] Successfully initialized")
+	return &ConfigReloadService{
+		cfg:       cfg,
+		auditRepo: auditRepo,
+		signals:   make(chan os.Signal, 1),
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start reloads the configuration whenever the process receives SIGHUP
+func (s *ConfigReloadService) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return fmt.Errorf("config reload service is already running")
+	}
+	s.isRunning = true
+
+	signal.Notify(s.signals, syscall.SIGHUP)
+	go s.signalLoop()
+	return nil
+}
+
+// Stop stops listening for SIGHUP
+func (s *ConfigReloadService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+	s.isRunning = false
+	signal.Stop(s.signals)
+	close(s.stopChan)
+}
+
+// signalLoop reloads on every SIGHUP until stopped
+func (s *ConfigReloadService) signalLoop() {
+	for {
+		select {
+		case <-s.signals:
+			ctx, cancel := context.WithTimeout(context.Background(), configAuditTimeout)
+			s.Reload(ctx, models.ConfigReloadSignal, "")
+			cancel()
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// Reload loads and validates the configuration and applies its runtime settings
+// The audit entry is returned with the changes made; a rejected configuration also returns an error
+func (s *ConfigReloadService) Reload(ctx context.Context, source, actor string) (*models.ConfigAuditEntry, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	entry := &models.ConfigAuditEntry{
+		Source:  source,
+		Actor:   actor,
+		Changes: []models.ConfigChange{},
+	}
+
+	if err := godotenv.Overload(configEnvFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		entry.Error = fmt.Sprintf("failed to read %s: %v", configEnvFile, err)
+	} else if loaded, err := config.Load(); err != nil {
+		entry.Error = err.Error()
+	} else {
+		settings := loaded.RuntimeSettings()
+		entry.Changes = diffRuntimeSettings(s.cfg.Runtime().Settings(), settings)
+		if len(entry.Changes) > 0 {
+			s.cfg.Runtime().Apply(settings)
+		}
+	}
+
+	if entry.Error != "" {
+		log.Printf("[ConfigReloadService] Reload (%s) rejected, settings unchanged: %s", source, entry.Error)
+	} else {
+		log.Printf("[ConfigReloadService] Reload (%s) applied %d change(s): %v", source, len(entry.Changes), entry.Changes)
+	}
+
+	// A reload is not undone when it cannot be recorded
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[ConfigReloadService] Failed to record reload: %v", err)
+	}
+
+	if entry.Error != "" {
+		return entry, fmt.Errorf("validation failed: %s", entry.Error)
+	}
+	return entry, nil
+}
+
+// GetAudit returns the most recent reloads, newest first
+func (s *ConfigReloadService) GetAudit(ctx context.Context, limit int) ([]models.ConfigAuditEntry, error) {
+	if limit <= 0 || limit > maxConfigAuditEntries {
+		limit = maxConfigAuditEntries
+	}
+	return s.auditRepo.GetRecent(ctx, limit)
+}
+
+// diffRuntimeSettings lists the settings that differ, sorted by name
+func diffRuntimeSettings(previous, current config.RuntimeSettings) []models.ConfigChange {
+	oldValues, newValues := previous.Values(), current.Values()
+	changes := []models.ConfigChange{}
+	for setting, value := range newValues {
+		if oldValues[setting] != value {
+			changes = append(changes, models.ConfigChange{Setting: setting, Old: oldValues[setting], New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Setting < changes[j].Setting })
+	return changes
+}
@@ -5,25 +5,52 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
+	"tterminal-backend/internal/batchwriter"
 	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/internal/transport"
 	"tterminal-backend/models"
 	"tterminal-backend/repositories"
 )
 
 // DataCollectionService continuously collects fresh data from Binance
 type DataCollectionService struct {
-	candleRepo    *repositories.CandleRepository
-	binanceClient *binance.Client
-	isRunning     bool
-	stopChan      chan bool
-	symbols       []string
-	intervals     []string
-	mu            sync.RWMutex
-	lastUpdate    map[string]time.Time
-	errorCount    int64
-	successCount  int64
-	stats         *CollectionStats
+	candleRepo      marketdata.CandleStore
+	priceCandleRepo marketdata.PriceCandleStore
+	binanceClient   *binance.Client      // Mark/index price klines (Binance only)
+	providers       *marketdata.Registry // Last price klines of every enabled exchange
+	isRunning       bool
+	stopChan        chan bool
+	symbols         []string
+	intervals       []string
+	priceTypes      []string // Additional price types (mark, index) collected alongside last price
+	mu              sync.RWMutex
+	lastUpdate      map[string]time.Time
+	errorCount      int64
+	successCount    int64
+	stats           *CollectionStats
+	activeRuns      atomic.Int32 // Collection runs in progress
+
+	// Candle writes go through batch writers for their retries and metrics. They are synchronous:
+	// collection runs report the outcome, and a closed bar must be stored before the listeners
+	// after this one invalidate the caches it affects
+	candleWriter      *batchwriter.Writer[models.Candle]
+	priceCandleWriter *batchwriter.Writer[models.Candle]
+	barWriter         *batchwriter.Writer[models.Candle]
+
+	// Compares closed bars between the stream and REST polling; nil stores whichever came last
+	consistency *CandleConsistencyService
+
+	// Paused symbols are neither collected nor stored, and their stream updates are not delivered
+	// (see collection_pause.go). Pausing is unavailable until SetPauseStore
+	pauseRepo      *repositories.SymbolPauseRepository
+	hub            transport.Publisher
+	pauses         map[string]*models.SymbolPause // Pauses in effect by symbol
+	pauseTimers    map[string]*time.Timer         // Ends of pauses with an until time
+	anomalyMovePct float64                        // 1m bar range pausing its symbol, percent of open (0 disables)
+	anomalyPause   time.Duration                  // How long an anomaly pauses a symbol
 }
 
 // CollectionStats tracks data collection statistics
@@ -38,6 +65,8 @@ type CollectionStats struct {
 	CandlesCollected int64     `json:"candles_collected"`
 	ActiveSymbols    []string  `json:"active_symbols"`
 	ActiveIntervals  []string  `json:"active_intervals"`
+	ActivePriceTypes []string  `json:"active_price_types"`
+	PausedSymbols    []string  `json:"paused_symbols"` // Active symbols not collected while paused
 	CollectionPeriod int       `json:"collection_period_seconds"`
 	IsRunning        bool      `json:"is_running"`
 	// New fields for dual-frequency collection
@@ -45,31 +74,75 @@ type CollectionStats struct {
 	IntervalCollectionPeriod int `json:"interval_collection_period_seconds"` // 300 seconds for 5m+ data
 }
 
-// NewDataCollectionService creates a new data collection service
-func NewDataCollectionService(candleRepo *repositories.CandleRepository, binanceClient *binance.Client) *DataCollectionService {
-	if candleRepo == nil {
+// NewDataCollectionService creates a new data collection service; its batch writers are
+// registered with writers for metrics and shutdown flushing when it is not nil
+func NewDataCollectionService(candleRepo marketdata.CandleStore, priceCandleRepo marketdata.PriceCandleStore, binanceClient *binance.Client, providers *marketdata.Registry, writers *batchwriter.Registry) *DataCollectionService {
+	if isNil(candleRepo) {
 		log.Fatalf("[DataCollectionService] CRITICAL: candleRepo cannot be nil")
 	}
+	if isNil(priceCandleRepo) {
+		priceCandleRepo = nil
+	}
 	if binanceClient == nil {
 		log.Fatalf("[DataCollectionService] CRITICAL: binanceClient cannot be nil")
 	}
+	if providers == nil {
+		log.Fatalf("[DataCollectionService] CRITICAL: providers cannot be nil")
+	}
 
-	return &DataCollectionService{
-		candleRepo:    candleRepo,
-		binanceClient: binanceClient,
-		isRunning:     false,
-		stopChan:      make(chan bool),
-		symbols:       []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "ADAUSDT", "XRPUSDT"}, // Popular symbols
-		intervals:     []string{"1m", "5m", "15m", "30m", "1h", "4h", "1d"},            // Popular intervals
-		lastUpdate:    make(map[string]time.Time),
+	s := &DataCollectionService{
+		candleRepo:      candleRepo,
+		priceCandleRepo: priceCandleRepo,
+		binanceClient:   binanceClient,
+		providers:       providers,
+		isRunning:       false,
+		stopChan:        make(chan bool),
+		symbols:         []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "ADAUSDT", "XRPUSDT"}, // Popular symbols
+		intervals:       []string{"1m", "5m", "15m", "30m", "1h", "4h", "1d"},            // Popular intervals
+		lastUpdate:      make(map[string]time.Time),
+		pauses:          make(map[string]*models.SymbolPause),
+		pauseTimers:     make(map[string]*time.Timer),
 		stats: &CollectionStats{
 			ActiveSymbols:            []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "ADAUSDT", "XRPUSDT"},
 			ActiveIntervals:          []string{"1m", "5m", "15m", "30m", "1h", "4h", "1d"},
+			ActivePriceTypes:         []string{models.PriceTypeLast},
 			CollectionPeriod:         300, // 5 minutes (legacy field)
 			MinuteCollectionPeriod:   60,  // 1 minute for 1m data
 			IntervalCollectionPeriod: 300, // 5 minutes for 5m+ data
 		},
 	}
+
+	s.candleWriter = batchwriter.New(writers, batchwriter.Config{Name: "candles:collection"}, candleRepo.BulkCreate)
+	if priceCandleRepo != nil {
+		s.priceCandleWriter = batchwriter.New(writers, batchwriter.Config{Name: "price_candles:collection"}, priceCandleRepo.BulkCreate)
+	}
+	s.barWriter = batchwriter.New(writers, batchwriter.Config{Name: "candles:bar_close"}, s.storeClosedBars)
+
+	return s
+}
+
+// AddExchangeSymbols collects symbols of a non-Binance exchange (bare, e.g. "BTCUSDT") under
+// their qualified keys (e.g. "BYBIT:BTCUSDT") through the exchange's registered provider
+func (s *DataCollectionService) AddExchangeSymbols(exchange string, symbols []string) {
+	for _, symbol := range symbols {
+		s.AddSymbol(models.QualifySymbol(exchange, symbol))
+	}
+}
+
+// SetConsistencyChecker compares closed bars stored from the stream with their REST-polled
+// version, keeping the preferred source's when both exist
+func (s *DataCollectionService) SetConsistencyChecker(consistency *CandleConsistencyService) {
+	s.consistency = consistency
+}
+
+// provider returns the market data provider of a symbol's exchange
+func (s *DataCollectionService) provider(symbol string) (marketdata.MarketDataProvider, error) {
+	exchange := models.SymbolExchange(symbol)
+	provider := s.providers.Get(exchange)
+	if provider == nil {
+		return nil, fmt.Errorf("no %s provider is registered", exchange)
+	}
+	return provider, nil
 }
 
 // Start begins the continuous data collection process
@@ -157,6 +230,9 @@ func (s *DataCollectionService) runImmediateCollection() {
 // fetchRecentHistoricalData fetches a declared period of recent historical data for all symbols/intervals
 // This is much more efficient than complex gap detection - we simply ensure we have recent complete data
 func (s *DataCollectionService) fetchRecentHistoricalData() {
+	s.activeRuns.Add(1)
+	defer s.activeRuns.Add(-1)
+
 	log.Printf("[DataCollectionService] Fetching recent historical data for all symbols/intervals...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Minute)
@@ -168,7 +244,7 @@ func (s *DataCollectionService) fetchRecentHistoricalData() {
 
 	totalCandles := 0
 
-	for _, symbol := range s.symbols {
+	for _, symbol := range s.collectedSymbols() {
 		for _, interval := range s.intervals {
 			wg.Add(1)
 
@@ -204,9 +280,15 @@ func (s *DataCollectionService) fetchHistoricalDataForSymbolInterval(ctx context
 	log.Printf("[DataCollectionService] Fetching %d recent candles for %s/%s (most recent data)",
 		limit, symbol, interval)
 
+	source, err := s.provider(symbol)
+	if err != nil {
+		log.Printf("[DataCollectionService] ERROR fetching historical data for %s/%s: %v", symbol, interval, err)
+		return 0
+	}
+
 	// Use the regular optimized method to get the MOST RECENT data (not time range)
 	// This ensures we get the latest candles up to the current time
-	candles, err := s.binanceClient.GetKlinesOptimized(ctx, symbol, interval, limit)
+	candles, err := source.GetKlines(ctx, symbol, interval, limit)
 	if err != nil {
 		log.Printf("[DataCollectionService] ERROR fetching historical data for %s/%s: %v", symbol, interval, err)
 		return 0
@@ -223,7 +305,7 @@ func (s *DataCollectionService) fetchHistoricalDataForSymbolInterval(ctx context
 		candles[len(candles)-1].OpenTime.Format("2006-01-02 15:04"))
 
 	// Store in database (this will upsert, so existing data won't be duplicated)
-	if err := s.candleRepo.BulkCreate(ctx, candles); err != nil {
+	if err := s.candleWriter.Write(ctx, models.LabelCandles(candles, models.CandleSourceBackfill)); err != nil {
 		log.Printf("[DataCollectionService] ERROR storing historical data for %s/%s: %v", symbol, interval, err)
 		return 0
 	}
@@ -257,6 +339,9 @@ func (s *DataCollectionService) getHistoricalLimit(interval string) int {
 
 // collectAllData collects data for all symbols and intervals
 func (s *DataCollectionService) collectAllData() {
+	s.activeRuns.Add(1)
+	defer s.activeRuns.Add(-1)
+
 	startTime := time.Now()
 
 	s.mu.Lock()
@@ -279,7 +364,7 @@ func (s *DataCollectionService) collectAllData() {
 	var resultMu sync.Mutex
 
 	// Collect data for each symbol/interval combination
-	for _, symbol := range s.symbols {
+	for _, symbol := range s.collectedSymbols() {
 		for _, interval := range s.intervals {
 			wg.Add(1)
 
@@ -335,21 +420,52 @@ func (s *DataCollectionService) collectDataForSymbolInterval(ctx context.Context
 
 	log.Printf("[DataCollectionService] Fetching %d candles for %s/%s", limit, symbol, interval)
 
-	// Fetch fresh data from Binance
-	candles, err := s.binanceClient.GetKlinesOptimized(ctx, symbol, interval, limit)
+	source, err := s.provider(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch fresh data from the symbol's exchange
+	fetchedAt := time.Now()
+	candles, err := source.GetKlines(ctx, symbol, interval, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from Binance: %w", err)
+		return nil, fmt.Errorf("failed to fetch from %s: %w", models.SymbolExchange(symbol), err)
 	}
 
 	if len(candles) == 0 {
-		return nil, fmt.Errorf("no candles returned from Binance")
+		return nil, fmt.Errorf("no candles returned from %s", models.SymbolExchange(symbol))
+	}
+
+	// The symbol may have been paused while fetching
+	if s.IsPaused(symbol) {
+		return nil, nil
 	}
 
-	// Store in database
-	if err := s.candleRepo.BulkCreate(ctx, candles); err != nil {
+	// Store in database, keeping stream versions of closed bars where those are preferred
+	toStore := models.LabelCandles(candles, models.CandleSourceRESTPoll)
+	if s.consistency != nil {
+		toStore = s.consistency.ReconcileREST(ctx, symbol, interval, toStore, fetchedAt)
+	}
+	if err := s.candleWriter.Write(ctx, toStore); err != nil {
 		return nil, fmt.Errorf("failed to store candles in database: %w", err)
 	}
 
+	// Collect mark/index price candles when enabled (only Binance serves them)
+	s.mu.RLock()
+	priceTypes := append([]string(nil), s.priceTypes...)
+	s.mu.RUnlock()
+	if models.SymbolExchange(symbol) != models.ExchangeBinance {
+		priceTypes = nil
+	}
+
+	for _, priceType := range priceTypes {
+		priceCandles, err := s.collectPriceCandles(ctx, symbol, interval, priceType, limit)
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, priceCandles...)
+	}
+
 	// Update last update time
 	key := fmt.Sprintf("%s:%s", symbol, interval)
 	s.mu.Lock()
@@ -359,6 +475,58 @@ func (s *DataCollectionService) collectDataForSymbolInterval(ctx context.Context
 	return candles, nil
 }
 
+// collectPriceCandles fetches and stores mark or index price candles for a symbol/interval
+func (s *DataCollectionService) collectPriceCandles(ctx context.Context, symbol, interval, priceType string, limit int) ([]models.Candle, error) {
+	candles, err := s.binanceClient.GetPriceKlines(ctx, symbol, interval, priceType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s candles from Binance: %w", priceType, err)
+	}
+
+	if err := s.priceCandleWriter.Write(ctx, candles); err != nil {
+		return nil, fmt.Errorf("failed to store %s candles in database: %w", priceType, err)
+	}
+
+	return candles, nil
+}
+
+// HandleBarClose stores a confirmed final bar as soon as it closes, so stored history does not
+// wait for the next collection run. Bars of paused symbols, and anomalous bars pausing their
+// symbol, are not stored
+func (s *DataCollectionService) HandleBarClose(bar models.BarClose) {
+	if s.IsPaused(bar.Symbol) || s.checkAnomaly(bar) {
+		return
+	}
+
+	// Long enough for the writer's retries
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// A REST candle already stored for the closed bar stays when REST is the preferred source
+	if bar.Source == "stream" && s.consistency != nil && !s.consistency.ReconcileStream(ctx, bar.Candle) {
+		return
+	}
+
+	if err := s.barWriter.Write(ctx, []models.Candle{bar.Candle}); err != nil {
+		log.Printf("[DataCollectionService] ERROR storing closed bar %s/%s: %v", bar.Symbol, bar.Interval, err)
+	}
+}
+
+// storeClosedBars writes a batch of closed bars and marks their symbol/intervals as updated
+func (s *DataCollectionService) storeClosedBars(ctx context.Context, bars []models.Candle) error {
+	if err := s.candleRepo.BulkCreate(ctx, bars); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	for _, bar := range bars {
+		s.lastUpdate[fmt.Sprintf("%s:%s", bar.Symbol, bar.Interval)] = now
+	}
+	s.stats.CandlesCollected += int64(len(bars))
+	s.mu.Unlock()
+	return nil
+}
+
 // getLimitForInterval returns the appropriate limit for each interval
 func (s *DataCollectionService) getLimitForInterval(interval string) int {
 	switch interval {
@@ -386,8 +554,17 @@ func (s *DataCollectionService) GetStats() *CollectionStats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Create a copy to avoid race conditions
+	// Create a copy to avoid race conditions; symbols are removed from the slices in place
 	stats := *s.stats
+	stats.ActiveSymbols = append([]string(nil), s.stats.ActiveSymbols...)
+	stats.ActiveIntervals = append([]string(nil), s.stats.ActiveIntervals...)
+	stats.ActivePriceTypes = append([]string(nil), s.stats.ActivePriceTypes...)
+	stats.PausedSymbols = make([]string, 0, len(s.pauses))
+	for _, symbol := range s.stats.ActiveSymbols {
+		if _, paused := s.pauses[symbol]; paused {
+			stats.PausedSymbols = append(stats.PausedSymbols, symbol)
+		}
+	}
 	stats.IsRunning = s.isRunning
 	return &stats
 }
@@ -434,6 +611,56 @@ func (s *DataCollectionService) RemoveSymbol(symbol string) {
 	log.Printf("[DataCollectionService] Removed symbol: %s", symbol)
 }
 
+// SetPriceTypes sets which price types are collected (last is always collected)
+func (s *DataCollectionService) SetPriceTypes(priceTypes []string) error {
+	extra := make([]string, 0, len(priceTypes))
+	for _, priceType := range priceTypes {
+		if !models.IsValidPriceType(priceType) {
+			return fmt.Errorf("invalid price type: %s", priceType)
+		}
+		if priceType == models.PriceTypeLast {
+			continue
+		}
+		duplicate := false
+		for _, existing := range extra {
+			if existing == priceType {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			extra = append(extra, priceType)
+		}
+	}
+
+	if len(extra) > 0 && s.priceCandleRepo == nil {
+		return fmt.Errorf("price candle storage is not available")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.priceTypes = extra
+	s.stats.ActivePriceTypes = append([]string{models.PriceTypeLast}, extra...)
+
+	log.Printf("[DataCollectionService] Collecting price types: %v", s.stats.ActivePriceTypes)
+	return nil
+}
+
+// collectedSymbols returns the symbols to collect: every symbol that is not paused
+func (s *DataCollectionService) collectedSymbols() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	symbols := make([]string, 0, len(s.symbols))
+	for _, symbol := range s.symbols {
+		if _, paused := s.pauses[symbol]; !paused {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
 // GetLastUpdateTime returns the last update time for a symbol/interval
 func (s *DataCollectionService) GetLastUpdateTime(symbol, interval string) *time.Time {
 	s.mu.RLock()
@@ -453,6 +680,11 @@ func (s *DataCollectionService) IsRunning() bool {
 	return s.isRunning
 }
 
+// ActiveRuns returns the number of collection runs in progress
+func (s *DataCollectionService) ActiveRuns() int {
+	return int(s.activeRuns.Load())
+}
+
 // CollectNow triggers an immediate data collection (useful for manual refresh)
 func (s *DataCollectionService) CollectNow() {
 	if !s.isRunning {
@@ -466,6 +698,9 @@ func (s *DataCollectionService) CollectNow() {
 
 // collectIntervalData collects data for a specific interval only
 func (s *DataCollectionService) collectIntervalData(targetInterval string) {
+	s.activeRuns.Add(1)
+	defer s.activeRuns.Add(-1)
+
 	startTime := time.Now()
 
 	s.mu.Lock()
@@ -487,7 +722,7 @@ func (s *DataCollectionService) collectIntervalData(targetInterval string) {
 	var resultMu sync.Mutex
 
 	// Collect data for all symbols with the target interval
-	for _, symbol := range s.symbols {
+	for _, symbol := range s.collectedSymbols() {
 		wg.Add(1)
 
 		go func(sym string) {
@@ -0,0 +1,18 @@
+package services
+
+import "reflect"
+
+// isNil reports whether a dependency is missing. Dependencies taken as interfaces (stores,
+// publishers) compare unequal to nil when they hold a nil pointer, such as an unset repository,
+// so == nil alone does not catch them
+func isNil(dependency interface{}) bool {
+	if dependency == nil {
+		return true
+	}
+	value := reflect.ValueOf(dependency)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+)
+
+// derivativesCacheTTL controls how long a dashboard snapshot is reused
+const derivativesCacheTTL = 15 * time.Second
+
+// DerivativesService assembles funding, open interest, positioning, liquidation and basis data
+type DerivativesService struct {
+	binanceClient *binance.Client
+	stream        *websocket.BinanceStream
+	cache         map[string]*models.DerivativesSnapshot
+	cacheExpiry   map[string]time.Time
+	cacheMutex    sync.RWMutex
+
+	// Funding and open interest history fetched for candle overlays
+	overlayCache map[string]overlayEntry
+	overlayMutex sync.Mutex
+}
+
+// NewDerivativesService creates a new derivatives dashboard service
+func NewDerivativesService(binanceClient *binance.Client, stream *websocket.BinanceStream) *DerivativesService {
+	if binanceClient == nil {
+		log.Fatalf("[DerivativesService] CRITICAL: binanceClient cannot be nil")
+	}
+	if stream == nil {
+		log.Printf("[DerivativesService] WARNING: stream is nil - funding, liquidation and basis data will be empty")
+	}
+
+	return &DerivativesService{
+		binanceClient: binanceClient,
+		stream:        stream,
+		cache:         make(map[string]*models.DerivativesSnapshot),
+		cacheExpiry:   make(map[string]time.Time),
+		overlayCache:  make(map[string]overlayEntry),
+	}
+}
+
+// GetSnapshot returns the derivatives dashboard snapshot for a symbol
+func (s *DerivativesService) GetSnapshot(ctx context.Context, symbol string, liqHours int) (*models.DerivativesSnapshot, error) {
+	symbol = strings.ToUpper(symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if liqHours <= 0 {
+		liqHours = 24
+	}
+
+	cacheKey := fmt.Sprintf("%s:%d", symbol, liqHours)
+	if cached := s.getCached(cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	snapshot := &models.DerivativesSnapshot{
+		Symbol:    symbol,
+		Timestamp: time.Now().UnixMilli(),
+		Errors:    make(map[string]string),
+	}
+
+	// Stream-backed sections are local and cheap
+	s.fillMarkPrice(snapshot)
+	snapshot.Liquidations = s.liquidationTotals(symbol, liqHours)
+
+	// REST-backed sections run concurrently
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		oi, err := s.binanceClient.GetOpenInterest(ctx, symbol)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			snapshot.Errors["open_interest"] = err.Error()
+			return
+		}
+		snapshot.OpenInterest = models.ParseFloat(oi.OpenInterest)
+	}()
+	go func() {
+		defer wg.Done()
+		hist, err := s.binanceClient.GetOpenInterestHist(ctx, symbol, "1h", time.Time{}, time.Time{}, 25)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			snapshot.Errors["open_interest_change"] = err.Error()
+			return
+		}
+		if len(hist) > 0 {
+			first := models.ParseFloat(hist[0].SumOpenInterest)
+			last := hist[len(hist)-1]
+			snapshot.OpenInterestValue = models.ParseFloat(last.SumOpenInterestValue)
+			if first > 0 {
+				snapshot.OpenInterestChangePct = (models.ParseFloat(last.SumOpenInterest) - first) / first * 100
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		ratios, err := s.binanceClient.GetLongShortRatio(ctx, symbol, "5m", 1)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			snapshot.Errors["long_short_ratio"] = err.Error()
+			return
+		}
+		if len(ratios) > 0 {
+			latest := ratios[len(ratios)-1]
+			snapshot.LongShortRatio = models.ParseFloat(latest.LongShortRatio)
+			snapshot.LongAccountPct = models.ParseFloat(latest.LongAccount) * 100
+			snapshot.ShortAccountPct = models.ParseFloat(latest.ShortAccount) * 100
+		}
+	}()
+	wg.Wait()
+
+	// Fall back to the previous snapshot for sections Binance could not serve
+	if len(snapshot.Errors) > 0 {
+		if previous := s.getLatest(cacheKey); previous != nil {
+			carryOverSections(snapshot, previous)
+		}
+	}
+
+	if len(snapshot.Errors) == 0 {
+		snapshot.Errors = nil
+	}
+
+	s.setCached(cacheKey, snapshot)
+	return snapshot, nil
+}
+
+// GetAllSnapshots returns dashboard snapshots for every streamed symbol
+func (s *DerivativesService) GetAllSnapshots(ctx context.Context, liqHours int) ([]*models.DerivativesSnapshot, error) {
+	if s.stream == nil {
+		return nil, fmt.Errorf("stream is not available")
+	}
+
+	symbols := s.stream.GetConnectedSymbols()
+	snapshots := make([]*models.DerivativesSnapshot, len(symbols))
+
+	// Bound concurrency to stay well inside Binance rate limits
+	semaphore := make(chan struct{}, 5)
+	var wg sync.WaitGroup
+
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(idx int, sym string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			snapshot, err := s.GetSnapshot(ctx, sym, liqHours)
+			if err != nil {
+				log.Printf("[DerivativesService] ERROR building snapshot for %s: %v", sym, err)
+				return
+			}
+			snapshots[idx] = snapshot
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	// Drop symbols that failed
+	result := make([]*models.DerivativesSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot != nil {
+			result = append(result, snapshot)
+		}
+	}
+
+	return result, nil
+}
+
+// GetFundingPrediction returns the live premium index and predicted next funding rate
+func (s *DerivativesService) GetFundingPrediction(symbol string) (*websocket.FundingPrediction, error) {
+	if s.stream == nil {
+		return nil, fmt.Errorf("stream is not available")
+	}
+
+	prediction, exists := s.stream.GetFundingPrediction(strings.ToUpper(symbol))
+	if !exists {
+		return nil, fmt.Errorf("no funding data for symbol %s", strings.ToUpper(symbol))
+	}
+	return &prediction, nil
+}
+
+// GetFundingHistory merges past funding predictions with settled rates from Binance
+func (s *DerivativesService) GetFundingHistory(ctx context.Context, symbol string, limit int) (*models.FundingHistoryResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	actual, err := s.binanceClient.GetFundingRateHistory(ctx, symbol, time.Time{}, time.Time{}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch funding rate history: %w", err)
+	}
+
+	// Settled funding times can drift a few milliseconds, so match on the minute
+	points := make(map[int64]*models.FundingHistoryPoint)
+	for _, rate := range actual {
+		fundingMinute := rate.FundingTime - rate.FundingTime%60000
+		actualRate := models.ParseFloat(rate.FundingRate)
+		points[fundingMinute] = &models.FundingHistoryPoint{
+			FundingTime:       fundingMinute,
+			ActualFundingRate: &actualRate,
+		}
+	}
+
+	if s.stream != nil {
+		for _, record := range s.stream.GetFundingPredictionHistory(symbol) {
+			fundingMinute := record.FundingTime - record.FundingTime%60000
+			predictedRate := record.PredictedFundingRate
+
+			point, exists := points[fundingMinute]
+			if !exists {
+				point = &models.FundingHistoryPoint{FundingTime: fundingMinute}
+				points[fundingMinute] = point
+			}
+			point.PredictedFundingRate = &predictedRate
+			if point.ActualFundingRate != nil {
+				predictionError := predictedRate - *point.ActualFundingRate
+				point.PredictionError = &predictionError
+			}
+		}
+	}
+
+	series := make([]models.FundingHistoryPoint, 0, len(points))
+	for _, point := range points {
+		series = append(series, *point)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].FundingTime < series[j].FundingTime
+	})
+	if len(series) > limit {
+		series = series[len(series)-limit:]
+	}
+
+	return &models.FundingHistoryResponse{
+		Symbol:    symbol,
+		Points:    series,
+		Count:     len(series),
+		Timestamp: time.Now().UnixMilli(),
+	}, nil
+}
+
+// fillMarkPrice copies mark, index, funding and basis data from the stream cache
+func (s *DerivativesService) fillMarkPrice(snapshot *models.DerivativesSnapshot) {
+	if s.stream == nil {
+		snapshot.Errors["mark_price"] = "stream is not available"
+		return
+	}
+
+	markPrice, exists := s.stream.GetMarkPriceData(snapshot.Symbol)
+	if !exists {
+		snapshot.Errors["mark_price"] = "no mark price data for symbol"
+		return
+	}
+
+	snapshot.MarkPrice = markPrice.MarkPrice
+	snapshot.IndexPrice = markPrice.IndexPrice
+	snapshot.FundingRate = markPrice.FundingRate
+	snapshot.NextFundingTime = markPrice.NextFundingTime.UnixMilli()
+
+	if snapshot.IndexPrice > 0 {
+		snapshot.Basis = snapshot.MarkPrice - snapshot.IndexPrice
+		snapshot.BasisPct = snapshot.Basis / snapshot.IndexPrice * 100
+	}
+}
+
+// liquidationTotals sums streamed liquidations within the window
+func (s *DerivativesService) liquidationTotals(symbol string, hours int) models.LiquidationTotals {
+	totals := models.LiquidationTotals{WindowHours: hours}
+	if s.stream == nil {
+		return totals
+	}
+
+	threshold := time.Now().Add(-time.Duration(hours) * time.Hour)
+	liquidations := s.stream.GetRecentLiquidations(symbol, 0)
+	totals.CoveredFrom = liquidationCoverage(liquidations, threshold)
+	for _, liq := range liquidations {
+		if liq.TradeTime.Before(threshold) {
+			continue
+		}
+		notional := liq.Price * liq.Quantity
+
+		// A SELL liquidation order closes a long position
+		if liq.Side == "SELL" {
+			totals.LongCount++
+			totals.LongNotional += notional
+		} else {
+			totals.ShortCount++
+			totals.ShortNotional += notional
+		}
+	}
+
+	return totals
+}
+
+// liquidationCoverage returns when the span covered by buffered liquidations (oldest first) starts
+// within a window starting at start, or 0 when none are buffered: older liquidations may have been
+// evicted from the buffer, or occurred before the stream was connected
+func liquidationCoverage(liquidations []models.LiquidationEvent, start time.Time) int64 {
+	if len(liquidations) == 0 {
+		return 0
+	}
+	if oldest := liquidations[0].TradeTime; oldest.After(start) {
+		return oldest.UnixMilli()
+	}
+	return start.UnixMilli()
+}
+
+// getCached returns a cached snapshot if it has not expired
+func (s *DerivativesService) getCached(key string) *models.DerivativesSnapshot {
+	s.cacheMutex.RLock()
+	defer s.cacheMutex.RUnlock()
+
+	snapshot, exists := s.cache[key]
+	if !exists || time.Now().After(s.cacheExpiry[key]) {
+		return nil
+	}
+	return snapshot
+}
+
+// getLatest returns the most recent snapshot for a key, even if it has expired
+func (s *DerivativesService) getLatest(key string) *models.DerivativesSnapshot {
+	s.cacheMutex.RLock()
+	defer s.cacheMutex.RUnlock()
+	return s.cache[key]
+}
+
+// carryOverSections copies failed REST-backed sections from an earlier snapshot and marks the snapshot stale
+func carryOverSections(snapshot, previous *models.DerivativesSnapshot) {
+	carried := false
+	carry := func(section string, copySection func()) {
+		if _, failed := snapshot.Errors[section]; !failed || previous.Errors[section] != "" {
+			return
+		}
+		copySection()
+		delete(snapshot.Errors, section)
+		carried = true
+	}
+
+	carry("open_interest", func() {
+		snapshot.OpenInterest = previous.OpenInterest
+	})
+	carry("open_interest_change", func() {
+		snapshot.OpenInterestValue = previous.OpenInterestValue
+		snapshot.OpenInterestChangePct = previous.OpenInterestChangePct
+	})
+	carry("long_short_ratio", func() {
+		snapshot.LongShortRatio = previous.LongShortRatio
+		snapshot.LongAccountPct = previous.LongAccountPct
+		snapshot.ShortAccountPct = previous.ShortAccountPct
+	})
+	if !carried {
+		return
+	}
+
+	snapshot.Stale = true
+	snapshot.DataAge = models.DataAgeSeconds(time.UnixMilli(previous.Timestamp))
+	if previous.Stale {
+		snapshot.DataAge += previous.DataAge
+	}
+}
+
+// setCached stores a snapshot in the cache
+func (s *DerivativesService) setCached(key string, snapshot *models.DerivativesSnapshot) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	s.cache[key] = snapshot
+	s.cacheExpiry[key] = time.Now().Add(derivativesCacheTTL)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/models"
+)
+
+// peerHealthTimeout bounds the health check of each peer before it is offered in reconnect hints
+const peerHealthTimeout = 2 * time.Second
+
+// maxPeerHealthBody caps the health response read for a peer's routing hint
+const maxPeerHealthBody = 64 << 10
+
+// DrainService drains the instance for a zero-drop rolling restart: new connections are refused,
+// connected clients are told to reconnect to a healthy peer, background jobs finish, and the
+// status reports when the process can be terminated
+type DrainService struct {
+	hub            transport.Drainer
+	purgeService   *PurgeService
+	dataCollection *DataCollectionService
+	peers          []string
+	grace          time.Duration
+	httpClient     *http.Client
+
+	mu           sync.Mutex
+	startedAt    *time.Time
+	closeAt      *time.Time
+	healthyPeers []string
+}
+
+// NewDrainService creates a new drain service
+func NewDrainService(hub transport.Drainer, purgeService *PurgeService, dataCollection *DataCollectionService, peers []string, grace time.Duration) *DrainService {
+	if isNil(hub) {
+		log.Fatalf("[DrainService] CRITICAL: hub cannot be nil")
+	}
+	if purgeService == nil {
+		log.Fatalf("[DrainService] CRITICAL: purgeService cannot be nil")
+	}
+	if dataCollection == nil {
+		log.Fatalf("[DrainService] CRITICAL: dataCollection cannot be nil")
+	}
+	log.Printf("[DrainService] Successfully initialized with %d peers", len(peers))
+	return &DrainService{
+		hub:            hub,
+		purgeService:   purgeService,
+		dataCollection: dataCollection,
+		peers:          peers,
+		grace:          grace,
+		httpClient:     &http.Client{Timeout: peerHealthTimeout},
+	}
+}
+
+// Start begins draining; calling it again while draining only returns the status
+func (s *DrainService) Start(ctx context.Context) *models.DrainStatus {
+	s.mu.Lock()
+	if s.startedAt != nil {
+		s.mu.Unlock()
+		return s.Status()
+	}
+	now := time.Now()
+	closeAt := now.Add(s.grace)
+	s.startedAt = &now
+	s.closeAt = &closeAt
+	s.mu.Unlock()
+
+	healthy := s.Peers(ctx)
+	urls := make([]string, 0, len(healthy))
+	for _, peer := range healthy {
+		urls = append(urls, peer.URL)
+	}
+	s.mu.Lock()
+	s.healthyPeers = urls
+	s.mu.Unlock()
+
+	log.Printf("[DrainService] Draining: %d of %d peers healthy, closing connections in %v", len(healthy), len(s.peers), s.grace)
+	s.hub.StartDrain(healthy, s.grace)
+	s.dataCollection.Stop()
+	s.purgeService.Drain()
+	time.AfterFunc(s.grace, s.hub.CloseAllClients)
+
+	return s.Status()
+}
+
+// IsDraining reports whether draining has started
+func (s *DrainService) IsDraining() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.startedAt != nil
+}
+
+// Status returns the drain progress
+func (s *DrainService) Status() *models.DrainStatus {
+	s.mu.Lock()
+	status := &models.DrainStatus{
+		Draining:  s.startedAt != nil,
+		StartedAt: s.startedAt,
+		Peers:     append([]string{}, s.healthyPeers...),
+		CloseAt:   s.closeAt,
+	}
+	s.mu.Unlock()
+
+	status.Connections = s.hub.GetConnectedClients()
+	status.InFlightJobs = models.DrainJobCounts{
+		Purge:          s.purgeService.RunningJobs(),
+		DataCollection: s.dataCollection.ActiveRuns(),
+	}
+	status.ReadyToTerminate = status.Draining && status.Connections == 0 &&
+		status.InFlightJobs.Purge == 0 && status.InFlightJobs.DataCollection == 0
+	return status
+}
+
+// Peers returns the configured peers whose health endpoint answers 200, in configured order,
+// with the routing hint each reports (instance, region and load)
+func (s *DrainService) Peers(ctx context.Context) []transport.ReconnectPeer {
+	checked := make([]*transport.ReconnectPeer, len(s.peers))
+	var wg sync.WaitGroup
+	for i, peer := range s.peers {
+		wg.Add(1)
+		go func(i int, peer string) {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, peer+"/api/v1/health", nil)
+			if err != nil {
+				return
+			}
+			resp, err := s.httpClient.Do(req)
+			if err != nil {
+				log.Printf("[DrainService] Peer %s unreachable: %v", peer, err)
+				return
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			// Peers running an older version report no routing hint and are still offered
+			var health struct {
+				Instance *transport.RoutingHint `json:"instance"`
+			}
+			if err := json.NewDecoder(io.LimitReader(resp.Body, maxPeerHealthBody)).Decode(&health); err != nil {
+				health.Instance = nil
+			}
+			checked[i] = &transport.ReconnectPeer{URL: peer, Instance: health.Instance}
+		}(i, peer)
+	}
+	wg.Wait()
+
+	peers := []transport.ReconnectPeer{}
+	for _, peer := range checked {
+		if peer != nil {
+			peers = append(peers, *peer)
+		}
+	}
+	return peers
+}
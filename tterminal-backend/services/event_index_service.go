@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// Event detection settings
+const (
+	// eventRangeKeep is how many of the largest range bars are indexed per symbol and interval
+	eventRangeKeep = 20
+	// volumeSpikeWindow is the number of preceding bars in the rolling mean volume
+	volumeSpikeWindow = 20
+	// volumeSpikeRatio flags bars whose volume is at least this multiple of the rolling mean
+	volumeSpikeRatio = 3.0
+	// gapThresholdPercent flags bars opening at least this far from the previous close
+	gapThresholdPercent = 0.5
+	// eventJumpLimit is the candle page size suggested for jumping to an event
+	eventJumpLimit = 200
+)
+
+// eventSeries is the rolling state of one symbol and interval used to detect events
+type eventSeries struct {
+	volumes    []float64 // Volumes of the latest bars, oldest first (up to volumeSpikeWindow)
+	prevOpen   int64     // Open time of the previous bar (Unix milliseconds)
+	prevClose  float64
+	rangeFloor float64 // Smallest range among the indexed largest ranges
+	rangeCount int     // Indexed range events (up to eventRangeKeep)
+}
+
+// EventIndexService indexes notable closed bars (largest ranges, volume spikes and gaps) for the
+// chart's significant events navigator. Bars are checked as they close; the rolling state of a
+// symbol and interval is seeded from stored candles on its first bar close
+type EventIndexService struct {
+	eventRepo  *repositories.MarketEventRepository
+	candleRepo *repositories.CandleRepository
+	onEvent    func(models.MarketEvent) // Optional, called for every stored event
+
+	mu     sync.Mutex
+	series map[string]*eventSeries // Keyed by "SYMBOL:interval"
+}
+
+// NewEventIndexService creates a new event index service
+func NewEventIndexService(eventRepo *repositories.MarketEventRepository, candleRepo *repositories.CandleRepository) *EventIndexService {
+	if eventRepo == nil {
+		log.Fatalf("[EventIndexService] CRITICAL: eventRepo cannot be nil")
+	}
+	if candleRepo == nil {
+		log.Fatalf("[EventIndexService] CRITICAL: candleRepo cannot be nil")
+	}
+	log.Printf("[EventIndexService] Successfully initialized")
+	return &EventIndexService{
+		eventRepo:  eventRepo,
+		candleRepo: candleRepo,
+		series:     make(map[string]*eventSeries),
+	}
+}
+
+// SetEventHandler registers a handler called with every event stored, such as webhook alerts
+func (s *EventIndexService) SetEventHandler(handler func(models.MarketEvent)) {
+	s.onEvent = handler
+}
+
+// HandleBarClose checks a confirmed final bar for events and stores any it finds
+func (s *EventIndexService) HandleBarClose(bar models.BarClose) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	series, err := s.seriesFor(ctx, bar)
+	if err != nil {
+		log.Printf("[EventIndexService] WARNING: Failed to seed %s %s: %v", bar.Symbol, bar.Interval, err)
+		return
+	}
+
+	s.mu.Lock()
+	if bar.OpenTime <= series.prevOpen {
+		s.mu.Unlock()
+		return
+	}
+	events := detectEvents(series, bar)
+	checkRange := series.rangeCount < eventRangeKeep || rangePercent(bar) > series.rangeFloor
+	series.prevOpen = bar.OpenTime
+	series.prevClose = bar.Close
+	series.volumes = append(series.volumes, bar.Volume)
+	if len(series.volumes) > volumeSpikeWindow {
+		series.volumes = series.volumes[len(series.volumes)-volumeSpikeWindow:]
+	}
+	s.mu.Unlock()
+
+	if checkRange && bar.Open > 0 {
+		events = append(events, newMarketEvent(bar, models.MarketEventRange, rangePercent(bar), bar.Open))
+	}
+
+	for i := range events {
+		if err := s.eventRepo.Create(ctx, &events[i]); err != nil {
+			log.Printf("[EventIndexService] WARNING: Failed to store %s event for %s %s: %v", events[i].Type, bar.Symbol, bar.Interval, err)
+			continue
+		}
+		if s.onEvent != nil {
+			s.onEvent(events[i])
+		}
+	}
+
+	if checkRange && bar.Open > 0 {
+		s.refreshRanges(ctx, bar.Symbol, bar.Interval, series)
+	}
+}
+
+// seriesFor returns the rolling state of a bar's symbol and interval, seeding it on first use
+func (s *EventIndexService) seriesFor(ctx context.Context, bar models.BarClose) (*eventSeries, error) {
+	key := bar.Symbol + ":" + bar.Interval
+
+	s.mu.Lock()
+	series, exists := s.series[key]
+	s.mu.Unlock()
+	if exists {
+		return series, nil
+	}
+
+	series = &eventSeries{}
+	candles, err := s.candleRepo.GetBeforeTime(ctx, bar.Symbol, bar.Interval, time.UnixMilli(bar.OpenTime), volumeSpikeWindow)
+	if err != nil {
+		return nil, err
+	}
+	for _, candle := range candles {
+		series.volumes = append(series.volumes, models.ParseFloat(candle.Volume))
+	}
+	// Only a stored bar directly before this one is a valid reference for a gap
+	if duration, ok := models.IntervalDuration(bar.Interval); ok && len(candles) > 0 {
+		last := candles[len(candles)-1]
+		if last.OpenTime.UnixMilli() == bar.OpenTime-duration.Milliseconds() {
+			series.prevOpen = last.OpenTime.UnixMilli()
+			series.prevClose = models.ParseFloat(last.Close)
+		}
+	}
+	series.rangeFloor, series.rangeCount, err = s.eventRepo.RangeFloor(ctx, bar.Symbol, bar.Interval, eventRangeKeep)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, exists := s.series[key]; exists {
+		return existing, nil
+	}
+	s.series[key] = series
+	return series, nil
+}
+
+// refreshRanges drops range events pushed out of the largest ranges and re-reads the floor
+func (s *EventIndexService) refreshRanges(ctx context.Context, symbol, interval string, series *eventSeries) {
+	if _, err := s.eventRepo.PruneRanges(ctx, symbol, interval, eventRangeKeep); err != nil {
+		log.Printf("[EventIndexService] WARNING: Failed to prune range events for %s %s: %v", symbol, interval, err)
+		return
+	}
+	floor, count, err := s.eventRepo.RangeFloor(ctx, symbol, interval, eventRangeKeep)
+	if err != nil {
+		log.Printf("[EventIndexService] WARNING: Failed to refresh range floor for %s %s: %v", symbol, interval, err)
+		return
+	}
+
+	s.mu.Lock()
+	series.rangeFloor, series.rangeCount = floor, count
+	s.mu.Unlock()
+}
+
+// detectEvents returns the volume spike and gap events of a bar; the caller holds s.mu
+func detectEvents(series *eventSeries, bar models.BarClose) []models.MarketEvent {
+	var events []models.MarketEvent
+
+	if len(series.volumes) == volumeSpikeWindow {
+		var sum float64
+		for _, volume := range series.volumes {
+			sum += volume
+		}
+		if mean := sum / float64(len(series.volumes)); mean > 0 && bar.Volume/mean >= volumeSpikeRatio {
+			events = append(events, newMarketEvent(bar, models.MarketEventVolumeSpike, bar.Volume/mean, mean))
+		}
+	}
+
+	if duration, ok := models.IntervalDuration(bar.Interval); ok && series.prevClose > 0 && series.prevOpen == bar.OpenTime-duration.Milliseconds() {
+		gap := (bar.Open - series.prevClose) / series.prevClose * 100
+		if math.Abs(gap) >= gapThresholdPercent {
+			events = append(events, newMarketEvent(bar, models.MarketEventGap, gap, series.prevClose))
+		}
+	}
+
+	return events
+}
+
+// rangePercent returns a bar's high-low range as a percent of its open
+func rangePercent(bar models.BarClose) float64 {
+	if bar.Open <= 0 {
+		return 0
+	}
+	return (bar.High - bar.Low) / bar.Open * 100
+}
+
+// newMarketEvent builds an event of a closed bar
+func newMarketEvent(bar models.BarClose, eventType string, magnitude, reference float64) models.MarketEvent {
+	return models.MarketEvent{
+		Symbol:    bar.Symbol,
+		Interval:  bar.Interval,
+		Type:      eventType,
+		OpenTime:  time.UnixMilli(bar.OpenTime).UTC(),
+		Magnitude: magnitude,
+		Reference: reference,
+		Open:      bar.Open,
+		High:      bar.High,
+		Low:       bar.Low,
+		Close:     bar.Close,
+		Volume:    bar.Volume,
+	}
+}
+
+// EventListParams filters a market event listing
+type EventListParams struct {
+	Interval    string    // Bar interval (empty = every interval)
+	Types       []string  // Event types (empty = every type)
+	Before      time.Time // Only events opening before this time (zero = no bound)
+	ByMagnitude bool      // Largest first instead of newest first
+	Limit       int
+}
+
+// ListEvents returns indexed events of a symbol with jump-to metadata for each
+func (s *EventIndexService) ListEvents(ctx context.Context, symbol string, params EventListParams) (*models.MarketEventsResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	if params.Interval != "" && !models.IsValidInterval(params.Interval) {
+		return nil, fmt.Errorf("invalid interval: %s", params.Interval)
+	}
+	for _, eventType := range params.Types {
+		if !models.IsValidMarketEventType(eventType) {
+			return nil, fmt.Errorf("invalid event type %q, use %s", eventType, strings.Join(models.MarketEventTypes, ", "))
+		}
+	}
+
+	events, err := s.eventRepo.List(ctx, symbol, params.Interval, params.Types, params.Before, params.ByMagnitude, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].Jump = models.NewEventJump(events[i], eventJumpLimit)
+	}
+
+	types := params.Types
+	if len(types) == 0 {
+		types = models.MarketEventTypes
+	}
+	return &models.MarketEventsResponse{
+		Symbol:   symbol,
+		Interval: params.Interval,
+		Types:    types,
+		Count:    len(events),
+		Events:   events,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/models"
+)
+
+const (
+	// footprintSettleDelay lets the trade recorder flush a bar's last trades before its footprint is built
+	footprintSettleDelay = 3 * time.Second
+	// maxFootprintRangeBars caps the bars of a footprint range request
+	maxFootprintRangeBars = 1000
+)
+
+// ErrFootprintStorageDisabled is returned for footprint range requests without a footprint store
+var ErrFootprintStorageDisabled = errors.New("footprint history is not stored")
+
+// SetFootprintStore enables stored footprint history: every closed bar's footprint is built from
+// persisted trades and served instead of the candle-based estimate
+func (s *AggregationService) SetFootprintStore(store marketdata.FootprintStore) {
+	if isNil(store) {
+		store = nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.footprints = store
+}
+
+// footprintStore returns the footprint store, or nil when footprint history is not stored
+func (s *AggregationService) footprintStore() marketdata.FootprintStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.footprints
+}
+
+// GetFootprintRange returns the stored footprint bars opening in [start, end), oldest first
+func (s *AggregationService) GetFootprintRange(ctx context.Context, symbol, interval string, start, end time.Time) ([]models.FootprintCandle, error) {
+	store := s.footprintStore()
+	if store == nil {
+		return nil, ErrFootprintStorageDisabled
+	}
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("validation failed: unsupported interval %q", interval)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("validation failed: end must be after start")
+	}
+	if bars := end.Sub(start) / duration; bars > maxFootprintRangeBars {
+		return nil, fmt.Errorf("validation failed: range spans %d bars, at most %d are allowed", bars, maxFootprintRangeBars)
+	}
+
+	return store.GetRange(ctx, symbol, interval, start, end)
+}
+
+// storeFootprint builds and stores a closed bar's footprint, then drops the bar's cached footprints
+// Runs on its own goroutine, so waiting for the trade recorder holds back no other handler
+func (s *AggregationService) storeFootprint(store marketdata.FootprintStore, bar models.BarClose) {
+	duration, ok := models.IntervalDuration(bar.Interval)
+	if !ok {
+		return
+	}
+	time.Sleep(footprintSettleDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	openTime := time.UnixMilli(bar.OpenTime)
+	if _, err := store.UpsertFromTrades(ctx, bar.Symbol, bar.Interval, openTime, openTime.Add(duration), models.FootprintBucketSize(bar.Close)); err != nil {
+		log.Printf("[AggregationService] WARNING: Failed to store footprint of %s %s at %s: %v", bar.Symbol, bar.Interval, openTime.UTC().Format(time.RFC3339), err)
+		return
+	}
+
+	prefix := fmt.Sprintf("footprint:%s:%s:", bar.Symbol, bar.Interval)
+	s.mu.Lock()
+	for key := range s.memCache {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.memCache, key)
+		}
+	}
+	s.mu.Unlock()
+}
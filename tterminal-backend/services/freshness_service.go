@@ -0,0 +1,212 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+)
+
+const (
+	// freshnessSampleInterval is how often every series' lag is checked against its target
+	freshnessSampleInterval = 30 * time.Second
+
+	// defaultFreshnessTarget applies to intervals without a configured target: two runs of the
+	// 5 minute collection of 5m+ data
+	defaultFreshnessTarget = 10 * time.Minute
+
+	// freshnessBuckets is how many buckets a series' samples are counted in across the window
+	freshnessBuckets = 24
+)
+
+// freshnessBucket counts the samples of one slice of the window
+type freshnessBucket struct {
+	start     time.Time
+	samples   int
+	compliant int
+}
+
+// FreshnessService samples how far the stored candles of every collected symbol/interval lag
+// behind, from the collection monitor's last store times, and reports each series' rolling
+// compliance with its interval's freshness target
+type FreshnessService struct {
+	collection *DataCollectionService
+	targets    map[string]time.Duration
+	window     time.Duration
+	bucketSize time.Duration
+	stopChan   chan struct{}
+
+	mu      sync.Mutex
+	buckets map[string][]freshnessBucket // "symbol:interval" -> buckets, oldest first
+}
+
+// NewFreshnessService creates a freshness monitor holding each interval to its target over window
+func NewFreshnessService(collection *DataCollectionService, targets map[string]time.Duration, window time.Duration) *FreshnessService {
+	if collection == nil {
+		log.Fatalf("[FreshnessService] CRITICAL: collection cannot be nil")
+	}
+
+	return &FreshnessService{
+		collection: collection,
+		targets:    targets,
+		window:     window,
+		bucketSize: window / freshnessBuckets,
+		stopChan:   make(chan struct{}),
+		buckets:    make(map[string][]freshnessBucket),
+	}
+}
+
+// Start samples every series' lag until Stop
+func (s *FreshnessService) Start() error {
+	go func() {
+		ticker := time.NewTicker(freshnessSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				s.sample(now)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sampling
+func (s *FreshnessService) Stop() {
+	close(s.stopChan)
+}
+
+// target returns the freshness target of an interval
+func (s *FreshnessService) target(interval string) time.Duration {
+	if target, ok := s.targets[interval]; ok {
+		return target
+	}
+	return defaultFreshnessTarget
+}
+
+// sample records whether each collected series is within its target; paused symbols are skipped,
+// and series no longer collected are forgotten
+func (s *FreshnessService) sample(now time.Time) {
+	stats := s.collection.GetStats()
+	paused := make(map[string]bool, len(stats.PausedSymbols))
+	for _, symbol := range stats.PausedSymbols {
+		paused[symbol] = true
+	}
+
+	bucketStart := now.Truncate(s.bucketSize)
+	horizon := now.Add(-s.window)
+	collected := make(map[string]bool)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, symbol := range stats.ActiveSymbols {
+		for _, interval := range stats.ActiveIntervals {
+			key := symbol + ":" + interval
+			collected[key] = true
+			if paused[symbol] {
+				continue
+			}
+
+			lastUpdate := s.collection.GetLastUpdateTime(symbol, interval)
+			compliant := lastUpdate != nil && now.Sub(*lastUpdate) <= s.target(interval)
+
+			buckets := s.buckets[key]
+			for len(buckets) > 0 && !buckets[0].start.Add(s.bucketSize).After(horizon) {
+				buckets = buckets[1:]
+			}
+			if len(buckets) == 0 || !buckets[len(buckets)-1].start.Equal(bucketStart) {
+				buckets = append(buckets, freshnessBucket{start: bucketStart})
+			}
+			last := &buckets[len(buckets)-1]
+			last.samples++
+			if compliant {
+				last.compliant++
+			}
+			s.buckets[key] = buckets
+		}
+	}
+
+	for key := range s.buckets {
+		if !collected[key] {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Report returns the freshness of the collected series, optionally only of one symbol or interval
+func (s *FreshnessService) Report(symbol, interval string) *models.FreshnessReport {
+	now := time.Now()
+	stats := s.collection.GetStats()
+	paused := make(map[string]bool, len(stats.PausedSymbols))
+	for _, symbol := range stats.PausedSymbols {
+		paused[symbol] = true
+	}
+	horizon := now.Add(-s.window)
+
+	report := &models.FreshnessReport{
+		WindowSeconds:         int64(s.window / time.Second),
+		SampleIntervalSeconds: int64(freshnessSampleInterval / time.Second),
+		Series:                []models.FreshnessStats{},
+		GeneratedAt:           now.UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var totalSamples, totalCompliant int
+	for _, sym := range stats.ActiveSymbols {
+		if symbol != "" && sym != symbol {
+			continue
+		}
+		for _, intv := range stats.ActiveIntervals {
+			if interval != "" && intv != interval {
+				continue
+			}
+
+			target := s.target(intv)
+			series := models.FreshnessStats{
+				Symbol:        sym,
+				Interval:      intv,
+				TargetSeconds: target.Seconds(),
+				Paused:        paused[sym],
+			}
+			if lastUpdate := s.collection.GetLastUpdateTime(sym, intv); lastUpdate != nil {
+				lag := now.Sub(*lastUpdate)
+				lagSeconds := lag.Seconds()
+				series.LagSeconds = &lagSeconds
+				series.WithinTarget = lag <= target
+			}
+
+			// Buckets wholly before the window are pruned by the next sample
+			var compliant int
+			for _, bucket := range s.buckets[sym+":"+intv] {
+				if bucket.start.Add(s.bucketSize).After(horizon) {
+					series.Samples += bucket.samples
+					compliant += bucket.compliant
+				}
+			}
+			if series.Samples > 0 {
+				pct := float64(compliant) / float64(series.Samples) * 100
+				series.CompliancePct = &pct
+			}
+			totalSamples += series.Samples
+			totalCompliant += compliant
+
+			report.Series = append(report.Series, series)
+		}
+	}
+
+	if totalSamples > 0 {
+		pct := float64(totalCompliant) / float64(totalSamples) * 100
+		report.CompliancePct = &pct
+	}
+	sort.SliceStable(report.Series, func(i, j int) bool {
+		return report.Series[i].Symbol < report.Series[j].Symbol
+	})
+	return report
+}
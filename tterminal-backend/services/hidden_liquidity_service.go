@@ -0,0 +1,425 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/models"
+)
+
+const (
+	// hiddenResolveAfter is how long trades at a level wait for a book update touching it; a level
+	// still showing its displayed size afterwards absorbed the trades entirely
+	hiddenResolveAfter = time.Second
+
+	// hiddenPushInterval is how often changed estimates are pushed on the "hidden_liquidity" channel
+	hiddenPushInterval = 2 * time.Second
+
+	// hiddenBucket is the granularity at which level totals expire from the window
+	hiddenBucket = time.Minute
+
+	// hiddenPushLevels caps the levels per symbol pushed on the channel
+	hiddenPushLevels = 20
+
+	// hiddenMaxLevels caps the levels returned by GetEstimate
+	hiddenMaxLevels = 200
+)
+
+// ErrHiddenLiquiditySymbol is returned for estimates of a symbol that is not tracked
+var ErrHiddenLiquiditySymbol = errors.New("symbol is not tracked for hidden liquidity")
+
+// HiddenLiquidityService infers hidden and iceberg liquidity per price level of configured symbols
+// by comparing the volume executed at a level with the change of its displayed size in the book
+// stream. Estimates are experimental and pushed on the "hidden_liquidity" WebSocket channel
+type HiddenLiquidityService struct {
+	providers *marketdata.Registry
+	hub       transport.Publisher
+	symbols   []string
+	window    time.Duration
+
+	mu       sync.Mutex
+	books    map[string]*hiddenBook // Tracked symbols only
+	stopChan chan struct{}
+}
+
+// hiddenLevelKey identifies a price level on one side of a book
+type hiddenLevelKey struct {
+	side  string
+	price float64
+}
+
+// hiddenBook is a symbol's displayed book with the executions and inferences per level
+type hiddenBook struct {
+	displayed map[hiddenLevelKey]float64
+	pending   map[hiddenLevelKey]*hiddenExecution // Trades awaiting a book update at their level
+	decreases map[hiddenLevelKey]*hiddenDecrease  // Displayed decreases seen before their trades
+	levels    map[hiddenLevelKey]*hiddenLevel
+	dirty     bool // Estimates changed since the last push
+}
+
+// hiddenExecution is the volume traded at a level since its last book update
+type hiddenExecution struct {
+	quantity float64
+	first    time.Time
+	last     time.Time
+}
+
+// hiddenDecrease is displayed size a level lost with no trades pending, which trades arriving
+// shortly after (the book and trade streams are not ordered) may account for
+type hiddenDecrease struct {
+	quantity float64
+	at       time.Time
+}
+
+// hiddenLevel accumulates a level's inferences in expiring buckets
+type hiddenLevel struct {
+	buckets   map[int64]*hiddenTotals // Keyed by bucket start (Unix seconds)
+	lastTrade time.Time
+}
+
+// hiddenTotals are the inferences of one bucket
+type hiddenTotals struct {
+	executed float64
+	decrease float64
+	hidden   float64
+	refills  int
+}
+
+// NewHiddenLiquidityService creates a hidden liquidity service for qualified symbol keys, keeping
+// inferences for window
+func NewHiddenLiquidityService(providers *marketdata.Registry, hub transport.Publisher, symbols []string, window time.Duration) *HiddenLiquidityService {
+	if providers == nil {
+		log.Fatalf("[HiddenLiquidityService] CRITICAL: providers cannot be nil")
+	}
+	if isNil(hub) {
+		log.Fatalf("[HiddenLiquidityService] CRITICAL: hub cannot be nil")
+	}
+
+	books := make(map[string]*hiddenBook, len(symbols))
+	tracked := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || books[symbol] != nil {
+			continue
+		}
+		books[symbol] = newHiddenBook()
+		tracked = append(tracked, symbol)
+	}
+
+	log.Printf("[HiddenLiquidityService] Successfully initialized (%d symbols, %v window)", len(tracked), window)
+	return &HiddenLiquidityService{
+		providers: providers,
+		hub:       hub,
+		symbols:   tracked,
+		window:    window,
+		books:     books,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// newHiddenBook creates an empty book
+func newHiddenBook() *hiddenBook {
+	return &hiddenBook{
+		displayed: make(map[hiddenLevelKey]float64),
+		pending:   make(map[hiddenLevelKey]*hiddenExecution),
+		decreases: make(map[hiddenLevelKey]*hiddenDecrease),
+		levels:    make(map[hiddenLevelKey]*hiddenLevel),
+	}
+}
+
+// Start follows the trade and book streams of the tracked symbols' exchanges and pushes
+// changed estimates on every push interval
+func (s *HiddenLiquidityService) Start() error {
+	exchanges := make(map[string]bool)
+	for _, symbol := range s.symbols {
+		provider := s.providers.ForSymbol(symbol)
+		if provider == nil {
+			log.Printf("[HiddenLiquidityService] Exchange of %s is not enabled, it will have no estimates", symbol)
+			continue
+		}
+		if exchanges[provider.Exchange()] {
+			continue
+		}
+		exchanges[provider.Exchange()] = true
+		provider.StreamTrades(s.onTrade)
+		provider.StreamDepth(s.onDepth)
+	}
+
+	go func() {
+		ticker := time.NewTicker(hiddenPushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				s.push(now)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops pushing estimates; stream handlers stay registered but are cheap
+func (s *HiddenLiquidityService) Stop() {
+	close(s.stopChan)
+}
+
+// Symbols returns the tracked symbols
+func (s *HiddenLiquidityService) Symbols() []string {
+	return append([]string{}, s.symbols...)
+}
+
+// onTrade records a trade against the level it executed at: taker buys lift asks, taker sells hit bids
+func (s *HiddenLiquidityService) onTrade(trade models.TradeRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book := s.books[trade.Symbol]
+	if book == nil || trade.Quantity <= 0 {
+		return
+	}
+	side := models.BookSideAsk
+	if trade.IsBuyerMaker {
+		side = models.BookSideBid
+	}
+	key := hiddenLevelKey{side: side, price: trade.Price}
+
+	execution := book.pending[key]
+	if execution == nil {
+		execution = &hiddenExecution{first: trade.TradeTime}
+		book.pending[key] = execution
+	}
+	execution.quantity += trade.Quantity
+	execution.last = trade.TradeTime
+}
+
+// onDepth applies a book update, resolving the pending trades of every level it touches
+func (s *HiddenLiquidityService) onDepth(update models.DepthUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book := s.books[update.Symbol]
+	if book == nil {
+		return
+	}
+
+	next := make(map[hiddenLevelKey]float64, len(update.Bids)+len(update.Asks))
+	for _, side := range []struct {
+		name   string
+		levels [][]string
+	}{{models.BookSideBid, update.Bids}, {models.BookSideAsk, update.Asks}} {
+		for _, level := range side.levels {
+			if len(level) < 2 {
+				continue
+			}
+			price, err := strconv.ParseFloat(level[0], 64)
+			if err != nil {
+				continue
+			}
+			quantity, err := strconv.ParseFloat(level[1], 64)
+			if err != nil {
+				continue
+			}
+			next[hiddenLevelKey{side: side.name, price: price}] = quantity
+		}
+	}
+
+	// A snapshot removes every level it does not list
+	if update.Snapshot {
+		for key := range book.displayed {
+			if _, listed := next[key]; !listed {
+				next[key] = 0
+			}
+		}
+	}
+
+	for key, quantity := range next {
+		old, known := book.displayed[key]
+		if execution := book.pending[key]; execution != nil {
+			delete(book.pending, key)
+			if known {
+				decrease := math.Max(old-quantity, 0) + book.takeDecrease(key, execution)
+				book.record(key, execution, decrease, quantity >= old)
+			}
+		} else if known && quantity < old {
+			recent := book.decreases[key]
+			if recent == nil {
+				recent = &hiddenDecrease{}
+				book.decreases[key] = recent
+			}
+			recent.quantity += old - quantity
+			recent.at = update.Time
+		}
+		if quantity > 0 {
+			book.displayed[key] = quantity
+		} else {
+			delete(book.displayed, key)
+		}
+	}
+}
+
+// record adds an inference to a level: volume executed beyond the displayed decrease is hidden
+func (b *hiddenBook) record(key hiddenLevelKey, execution *hiddenExecution, decrease float64, refilled bool) {
+	level := b.levels[key]
+	if level == nil {
+		level = &hiddenLevel{buckets: make(map[int64]*hiddenTotals)}
+		b.levels[key] = level
+	}
+	bucket := execution.last.Truncate(hiddenBucket).Unix()
+	totals := level.buckets[bucket]
+	if totals == nil {
+		totals = &hiddenTotals{}
+		level.buckets[bucket] = totals
+	}
+
+	totals.executed += execution.quantity
+	totals.decrease += math.Min(decrease, execution.quantity)
+	totals.hidden += math.Max(execution.quantity-decrease, 0)
+	if refilled {
+		totals.refills++
+	}
+	if execution.last.After(level.lastTrade) {
+		level.lastTrade = execution.last
+	}
+	b.dirty = true
+}
+
+// takeDecrease returns and clears the displayed decrease a level showed shortly before trades
+func (b *hiddenBook) takeDecrease(key hiddenLevelKey, execution *hiddenExecution) float64 {
+	recent := b.decreases[key]
+	if recent == nil {
+		return 0
+	}
+	delete(b.decreases, key)
+	if recent.at.Before(execution.first.Add(-hiddenResolveAfter)) {
+		return 0
+	}
+	return recent.quantity
+}
+
+// sweep resolves trades whose level saw no book update in time: the level kept its displayed
+// size, so all of the traded volume was hidden. Trades at levels never displayed are dropped, and
+// levels without inferences in the window are pruned
+func (b *hiddenBook) sweep(now time.Time, window time.Duration) {
+	for key, execution := range b.pending {
+		if now.Sub(execution.first) < hiddenResolveAfter {
+			continue
+		}
+		delete(b.pending, key)
+		if _, known := b.displayed[key]; known {
+			decrease := b.takeDecrease(key, execution)
+			b.record(key, execution, decrease, decrease == 0)
+		}
+	}
+	for key, recent := range b.decreases {
+		if now.Sub(recent.at) > 2*hiddenResolveAfter {
+			delete(b.decreases, key) // Cancelled rather than traded
+		}
+	}
+
+	cutoff := now.Add(-window).Truncate(hiddenBucket).Unix()
+	for key, level := range b.levels {
+		for bucket := range level.buckets {
+			if bucket < cutoff {
+				delete(level.buckets, bucket)
+				b.dirty = true
+			}
+		}
+		if len(level.buckets) == 0 {
+			delete(b.levels, key)
+		}
+	}
+}
+
+// estimate sums each level's inferences over the window, largest hidden estimates first
+func (b *hiddenBook) estimate(symbol string, window time.Duration, minHidden float64, limit int) *models.HiddenLiquidityEstimate {
+	estimate := &models.HiddenLiquidityEstimate{
+		Symbol:        symbol,
+		WindowSeconds: int(window / time.Second),
+		Levels:        []models.HiddenLiquidityLevel{},
+		Experimental:  true,
+		Timestamp:     time.Now().UnixMilli(),
+	}
+
+	for key, level := range b.levels {
+		result := models.HiddenLiquidityLevel{
+			Side:      key.side,
+			Price:     key.price,
+			Displayed: b.displayed[key],
+			LastTrade: level.lastTrade,
+		}
+		for _, totals := range level.buckets {
+			result.Executed += totals.executed
+			result.DisplayedDecrease += totals.decrease
+			result.HiddenEstimate += totals.hidden
+			result.Refills += totals.refills
+		}
+		if result.HiddenEstimate <= 0 || result.HiddenEstimate < minHidden {
+			continue
+		}
+		result.HiddenRatio = result.HiddenEstimate / result.Executed
+		if key.side == models.BookSideBid {
+			estimate.HiddenBid += result.HiddenEstimate
+		} else {
+			estimate.HiddenAsk += result.HiddenEstimate
+		}
+		estimate.Levels = append(estimate.Levels, result)
+	}
+
+	sort.Slice(estimate.Levels, func(i, j int) bool {
+		if estimate.Levels[i].HiddenEstimate != estimate.Levels[j].HiddenEstimate {
+			return estimate.Levels[i].HiddenEstimate > estimate.Levels[j].HiddenEstimate
+		}
+		return estimate.Levels[i].Price < estimate.Levels[j].Price
+	})
+	if len(estimate.Levels) > limit {
+		estimate.Levels = estimate.Levels[:limit]
+	}
+	return estimate
+}
+
+// push resolves expired trades and publishes the estimates of symbols that changed
+func (s *HiddenLiquidityService) push(now time.Time) {
+	s.mu.Lock()
+	estimates := make([]models.HiddenLiquidityEstimate, 0)
+	for _, symbol := range s.symbols {
+		book := s.books[symbol]
+		book.sweep(now, s.window)
+		if !book.dirty {
+			continue
+		}
+		book.dirty = false
+		estimates = append(estimates, *book.estimate(symbol, s.window, 0, hiddenPushLevels))
+	}
+	s.mu.Unlock()
+
+	if len(estimates) > 0 {
+		s.hub.BroadcastHiddenLiquidityUpdate(estimates)
+	}
+}
+
+// GetEstimate returns a tracked symbol's inferred hidden liquidity, keeping levels with at least
+// minHidden hidden volume
+func (s *HiddenLiquidityService) GetEstimate(symbol string, minHidden float64, limit int) (*models.HiddenLiquidityEstimate, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if limit <= 0 || limit > hiddenMaxLevels {
+		limit = hiddenMaxLevels
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	book := s.books[symbol]
+	if book == nil {
+		return nil, ErrHiddenLiquiditySymbol
+	}
+	return book.estimate(symbol, s.window, minHidden, limit), nil
+}
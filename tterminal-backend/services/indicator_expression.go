@@ -0,0 +1,579 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"tterminal-backend/models"
+)
+
+// indicatorFields are the candle fields an expression can read, named like query fields
+var indicatorFields = map[string]bool{
+	models.QueryFieldOpen:       true,
+	models.QueryFieldHigh:       true,
+	models.QueryFieldLow:        true,
+	models.QueryFieldClose:      true,
+	models.QueryFieldVolume:     true,
+	models.QueryFieldBuyVolume:  true,
+	models.QueryFieldSellVolume: true,
+	models.QueryFieldDelta:      true,
+	models.QueryFieldTrades:     true,
+}
+
+// indicatorExpression is a parsed custom indicator expression evaluated over whole series
+// Supports numbers, candle fields, + - * / with the usual precedence, parentheses and the
+// functions of indicatorFunctions. Undefined values are NaN and propagate
+type indicatorExpression struct {
+	root     indicatorNode
+	fields   []string
+	lookback int
+}
+
+// indicatorNode is a node of the expression tree
+type indicatorNode interface {
+	eval(columns map[string][]float64, n int) []float64
+	lookback() int
+}
+
+type indicatorNumber float64
+
+type indicatorField string
+
+type indicatorUnary struct {
+	operand indicatorNode
+}
+
+type indicatorBinary struct {
+	op          byte
+	left, right indicatorNode
+}
+
+type indicatorCall struct {
+	fn     indicatorFunction
+	args   []indicatorNode
+	period int // 0 for functions without a period
+}
+
+// indicatorFunction is a function an expression can call: its series arguments, whether a
+// constant period follows them, the bars it needs before its first value and its evaluation
+type indicatorFunction struct {
+	series int
+	period bool
+	warmup func(period int) int
+	apply  func(args [][]float64, period int) []float64
+}
+
+// indicatorFunctions are the callable functions by name. ema and rsi are seeded with the simple
+// average of their first period values, so they read 4 periods of warm-up to settle
+var indicatorFunctions = map[string]indicatorFunction{
+	"sma":     {series: 1, period: true, warmup: windowWarmup, apply: indicatorSMA},
+	"ema":     {series: 1, period: true, warmup: smoothedWarmup, apply: indicatorEMA},
+	"rsi":     {series: 1, period: true, warmup: smoothedWarmup, apply: indicatorRSI},
+	"sum":     {series: 1, period: true, warmup: windowWarmup, apply: indicatorSum},
+	"stdev":   {series: 1, period: true, warmup: windowWarmup, apply: indicatorStdev},
+	"highest": {series: 1, period: true, warmup: windowWarmup, apply: indicatorHighest},
+	"lowest":  {series: 1, period: true, warmup: windowWarmup, apply: indicatorLowest},
+	"lag":     {series: 1, period: true, warmup: func(period int) int { return period }, apply: indicatorLag},
+	"abs":     {series: 1, apply: elementwise(math.Abs)},
+	"sqrt":    {series: 1, apply: elementwise(math.Sqrt)},
+	"log":     {series: 1, apply: elementwise(math.Log)},
+	"min":     {series: 2, apply: pairwise(math.Min)},
+	"max":     {series: 2, apply: pairwise(math.Max)},
+}
+
+// indicatorFunctionNames returns the callable function names, sorted
+func indicatorFunctionNames() []string {
+	names := make([]string, 0, len(indicatorFunctions))
+	for name := range indicatorFunctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// indicatorFieldNames returns the candle fields an expression can read, sorted
+func indicatorFieldNames() []string {
+	names := make([]string, 0, len(indicatorFields))
+	for name := range indicatorFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseIndicatorExpression parses an expression such as "ema(close,21) - ema(close,55)" or
+// "rsi(close, 14)". Names are case-insensitive
+func parseIndicatorExpression(expression string) (*indicatorExpression, error) {
+	if len(expression) > models.MaxIndicatorExpression {
+		return nil, fmt.Errorf("expression is longer than %d characters", models.MaxIndicatorExpression)
+	}
+
+	p := &indicatorParser{input: strings.ToLower(expression), seen: make(map[string]bool)}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	if len(p.fields) == 0 {
+		return nil, fmt.Errorf("expression must read at least one candle field")
+	}
+	if p.nodes > models.MaxIndicatorNodes {
+		return nil, fmt.Errorf("expression has more than %d terms", models.MaxIndicatorNodes)
+	}
+	lookback := root.lookback()
+	if lookback > models.MaxIndicatorLookback {
+		return nil, fmt.Errorf("expression needs %d warm-up bars, at most %d", lookback, models.MaxIndicatorLookback)
+	}
+
+	sort.Strings(p.fields)
+	return &indicatorExpression{root: root, fields: p.fields, lookback: lookback}, nil
+}
+
+// Fields returns the candle fields the expression reads, sorted
+func (e *indicatorExpression) Fields() []string {
+	return e.fields
+}
+
+// Lookback returns how many bars before the first value wanted must be read to compute it
+func (e *indicatorExpression) Lookback() int {
+	return e.lookback
+}
+
+// Evaluate computes the expression at each of n bars from columns of the fields it reads, oldest
+// first; bars without a value are NaN
+func (e *indicatorExpression) Evaluate(columns map[string][]float64, n int) []float64 {
+	return e.root.eval(columns, n)
+}
+
+func (v indicatorNumber) eval(_ map[string][]float64, n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(v)
+	}
+	return values
+}
+
+func (v indicatorField) eval(columns map[string][]float64, n int) []float64 {
+	values := make([]float64, n)
+	copy(values, columns[string(v)])
+	return values
+}
+
+func (v indicatorUnary) eval(columns map[string][]float64, n int) []float64 {
+	values := v.operand.eval(columns, n)
+	for i := range values {
+		values[i] = -values[i]
+	}
+	return values
+}
+
+func (v indicatorBinary) eval(columns map[string][]float64, n int) []float64 {
+	left, right := v.left.eval(columns, n), v.right.eval(columns, n)
+	for i := range left {
+		switch v.op {
+		case '+':
+			left[i] += right[i]
+		case '-':
+			left[i] -= right[i]
+		case '*':
+			left[i] *= right[i]
+		default:
+			left[i] /= right[i]
+		}
+		left[i] = finite(left[i])
+	}
+	return left
+}
+
+func (v indicatorCall) eval(columns map[string][]float64, n int) []float64 {
+	args := make([][]float64, len(v.args))
+	for i, arg := range v.args {
+		args[i] = arg.eval(columns, n)
+	}
+	values := v.fn.apply(args, v.period)
+	for i := range values {
+		values[i] = finite(values[i])
+	}
+	return values
+}
+
+func (indicatorNumber) lookback() int { return 0 }
+
+func (indicatorField) lookback() int { return 0 }
+
+func (v indicatorUnary) lookback() int { return v.operand.lookback() }
+
+func (v indicatorBinary) lookback() int { return max(v.left.lookback(), v.right.lookback()) }
+
+func (v indicatorCall) lookback() int {
+	lookback := 0
+	for _, arg := range v.args {
+		lookback = max(lookback, arg.lookback())
+	}
+	if v.fn.warmup != nil {
+		lookback += v.fn.warmup(v.period)
+	}
+	return lookback
+}
+
+// finite turns infinities (division by zero, log of zero) into undefined values
+func finite(v float64) float64 {
+	if math.IsInf(v, 0) {
+		return math.NaN()
+	}
+	return v
+}
+
+// windowWarmup is the warm-up of a function over the last period values
+func windowWarmup(period int) int {
+	return period - 1
+}
+
+// smoothedWarmup is the warm-up of a recursively smoothed function
+func smoothedWarmup(period int) int {
+	return 4 * period
+}
+
+// elementwise applies a function to each value of a series
+func elementwise(fn func(float64) float64) func(args [][]float64, period int) []float64 {
+	return func(args [][]float64, _ int) []float64 {
+		for i, v := range args[0] {
+			args[0][i] = fn(v)
+		}
+		return args[0]
+	}
+}
+
+// pairwise applies a function to the values of two series at each bar
+func pairwise(fn func(a, b float64) float64) func(args [][]float64, period int) []float64 {
+	return func(args [][]float64, _ int) []float64 {
+		for i, v := range args[0] {
+			args[0][i] = fn(v, args[1][i])
+		}
+		return args[0]
+	}
+}
+
+// indicatorWindow applies fn to each run of the last period values, which is undefined while the
+// run is incomplete or holds an undefined value
+func indicatorWindow(values []float64, period int, fn func(run []float64) float64) []float64 {
+	out := make([]float64, len(values))
+	undefined := 0 // Undefined values in the run
+	for i, v := range values {
+		if math.IsNaN(v) {
+			undefined++
+		}
+		if i >= period && math.IsNaN(values[i-period]) {
+			undefined--
+		}
+		if i < period-1 || undefined > 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = fn(values[i-period+1 : i+1])
+	}
+	return out
+}
+
+func indicatorSum(args [][]float64, period int) []float64 {
+	return indicatorWindow(args[0], period, func(run []float64) float64 {
+		var sum float64
+		for _, v := range run {
+			sum += v
+		}
+		return sum
+	})
+}
+
+func indicatorSMA(args [][]float64, period int) []float64 {
+	return smaSeries(args[0], period)
+}
+
+func indicatorStdev(args [][]float64, period int) []float64 {
+	return indicatorWindow(args[0], period, func(run []float64) float64 {
+		var sum float64
+		for _, v := range run {
+			sum += v
+		}
+		mean := sum / float64(len(run))
+		var squares float64
+		for _, v := range run {
+			squares += (v - mean) * (v - mean)
+		}
+		return math.Sqrt(squares / float64(len(run)))
+	})
+}
+
+func indicatorHighest(args [][]float64, period int) []float64 {
+	return indicatorWindow(args[0], period, func(run []float64) float64 {
+		highest := run[0]
+		for _, v := range run[1:] {
+			highest = math.Max(highest, v)
+		}
+		return highest
+	})
+}
+
+func indicatorLowest(args [][]float64, period int) []float64 {
+	return indicatorWindow(args[0], period, func(run []float64) float64 {
+		lowest := run[0]
+		for _, v := range run[1:] {
+			lowest = math.Min(lowest, v)
+		}
+		return lowest
+	})
+}
+
+func indicatorLag(args [][]float64, period int) []float64 {
+	out := make([]float64, len(args[0]))
+	for i := range out {
+		if i < period {
+			out[i] = math.NaN()
+		} else {
+			out[i] = args[0][i-period]
+		}
+	}
+	return out
+}
+
+func indicatorEMA(args [][]float64, period int) []float64 {
+	return emaSeries(args[0], period)
+}
+
+// indicatorRSI is Wilder's relative strength index (0 to 100; 50 while flat), restarted after
+// an undefined value
+func indicatorRSI(args [][]float64, period int) []float64 {
+	out := make([]float64, len(args[0]))
+	previous := math.NaN()
+	var gain, loss float64 // Sums over the seed changes, then Wilder averages
+	count := 0
+	for i, v := range args[0] {
+		out[i] = math.NaN()
+		if math.IsNaN(v) || math.IsNaN(previous) {
+			previous, gain, loss, count = v, 0, 0, 0
+			continue
+		}
+		change := v - previous
+		previous = v
+
+		if count < period {
+			gain += math.Max(change, 0)
+			loss += math.Max(-change, 0)
+			count++
+			if count < period {
+				continue
+			}
+			gain, loss = gain/float64(period), loss/float64(period)
+		} else {
+			gain = (gain*float64(period-1) + math.Max(change, 0)) / float64(period)
+			loss = (loss*float64(period-1) + math.Max(-change, 0)) / float64(period)
+		}
+
+		if gain+loss == 0 {
+			out[i] = 50
+		} else {
+			out[i] = 100 * gain / (gain + loss)
+		}
+	}
+	return out
+}
+
+// indicatorParser is a recursive descent parser for indicator expressions
+type indicatorParser struct {
+	input  string
+	pos    int
+	nodes  int
+	fields []string
+	seen   map[string]bool
+}
+
+func (p *indicatorParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// parseSum parses terms joined by + and -
+func (p *indicatorParser) parseSum() (indicatorNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return left, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		p.nodes++
+		left = indicatorBinary{op: op, left: left, right: right}
+	}
+}
+
+// parseProduct parses factors joined by * and /
+func (p *indicatorParser) parseProduct() (indicatorNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			return left, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		p.nodes++
+		left = indicatorBinary{op: op, left: left, right: right}
+	}
+}
+
+// parseFactor parses a number, a field, a function call, a negation or a parenthesized expression
+func (p *indicatorParser) parseFactor() (indicatorNode, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.nodes++
+	if p.nodes > models.MaxIndicatorNodes {
+		return nil, fmt.Errorf("expression has more than %d terms", models.MaxIndicatorNodes)
+	}
+
+	switch c := p.input[p.pos]; {
+	case c == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return indicatorUnary{operand: operand}, nil
+
+	case c == '(':
+		p.pos++
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return inner, nil
+
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", p.input[start:p.pos], start)
+		}
+		return indicatorNumber(number), nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && isIndicatorNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[start], start)
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		return p.parseCall(name)
+	}
+	if !indicatorFields[name] {
+		return nil, fmt.Errorf("unknown field %q, use one of %s", name, strings.Join(indicatorFieldNames(), ", "))
+	}
+	if !p.seen[name] {
+		p.seen[name] = true
+		p.fields = append(p.fields, name)
+	}
+	return indicatorField(name), nil
+}
+
+// parseCall parses the arguments of a function call after its opening parenthesis
+func (p *indicatorParser) parseCall(name string) (indicatorNode, error) {
+	fn, ok := indicatorFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q, use one of %s", name, strings.Join(indicatorFunctionNames(), ", "))
+	}
+	usage := name + "(series"
+	if fn.series == 2 {
+		usage += ", series"
+	}
+	if fn.period {
+		usage += ", period"
+	}
+	usage += ")"
+
+	call := indicatorCall{fn: fn}
+	for i := 0; i < fn.series; i++ {
+		if i > 0 {
+			if err := p.expect(','); err != nil {
+				return nil, fmt.Errorf("%s takes %s", name, usage)
+			}
+		}
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+
+	if fn.period {
+		if err := p.expect(','); err != nil {
+			return nil, fmt.Errorf("%s takes %s", name, usage)
+		}
+		p.skipSpaces()
+		start := p.pos
+		for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+			p.pos++
+		}
+		period, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil || period < 1 || period > models.MaxIndicatorPeriod {
+			return nil, fmt.Errorf("%s period must be a whole number between 1 and %d", name, models.MaxIndicatorPeriod)
+		}
+		call.period = period
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, fmt.Errorf("%s takes %s", name, usage)
+	}
+	return call, nil
+}
+
+// expect consumes a character after optional spaces
+func (p *indicatorParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.pos >= len(p.input) || p.input[p.pos] != c {
+		if c == ')' {
+			return fmt.Errorf("missing closing parenthesis")
+		}
+		return fmt.Errorf("expected %q at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// isIndicatorNameChar reports whether c can appear in a field or function name
+func isIndicatorNameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_'
+}
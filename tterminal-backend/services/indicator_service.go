@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxIndicatorsPerUser caps the custom indicators a user can register
+	maxIndicatorsPerUser = 50
+	// maxIndicatorNameLength matches the name column
+	maxIndicatorNameLength = 64
+	// maxIndicatorBars caps the bars of a custom indicator request without a time range
+	maxIndicatorBars = 1000
+)
+
+// IndicatorService manages custom indicators: user-registered expressions over candle fields,
+// evaluated server-side against stored candles like the built-in series. Each request reads the
+// expression's warm-up bars before the range so values do not depend on where the range starts
+type IndicatorService struct {
+	indicatorRepo *repositories.IndicatorRepository
+	candleService *CandleService
+}
+
+// NewIndicatorService creates a new custom indicator service
+func NewIndicatorService(indicatorRepo *repositories.IndicatorRepository, candleService *CandleService) *IndicatorService {
+	if indicatorRepo == nil {
+		log.Fatalf("[IndicatorService] CRITICAL: indicatorRepo cannot be nil")
+	}
+	if candleService == nil {
+		log.Fatalf("[IndicatorService] CRITICAL: candleService cannot be nil")
+	}
+	log.Printf("[IndicatorService] Successfully initialized")
+	return &IndicatorService{
+		indicatorRepo: indicatorRepo,
+		candleService: candleService,
+	}
+}
+
+// CreateIndicator registers a custom indicator for a user
+func (s *IndicatorService) CreateIndicator(ctx context.Context, userID string, req *models.CreateIndicatorRequest) (*models.CustomIndicator, error) {
+	name, err := normalizeIndicatorName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	expressionText := strings.TrimSpace(req.Expression)
+	expression, err := parseIndicatorExpression(expressionText)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	owned, err := s.indicatorRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) >= maxIndicatorsPerUser {
+		return nil, fmt.Errorf("validation failed: at most %d indicators per user", maxIndicatorsPerUser)
+	}
+	if indicatorNameTaken(owned, name, 0) {
+		return nil, fmt.Errorf("validation failed: indicator name %q is taken", name)
+	}
+
+	indicator := &models.CustomIndicator{
+		UserID:     userID,
+		Name:       name,
+		Expression: expressionText,
+		Lookback:   expression.Lookback(),
+	}
+	if err := s.indicatorRepo.Create(ctx, indicator); err != nil {
+		return nil, err
+	}
+
+	return indicator, nil
+}
+
+// GetIndicators returns all custom indicators of a user
+func (s *IndicatorService) GetIndicators(ctx context.Context, userID string) ([]models.CustomIndicator, error) {
+	indicators, err := s.indicatorRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range indicators {
+		describeIndicator(&indicators[i])
+	}
+	return indicators, nil
+}
+
+// GetIndicator returns a custom indicator owned by the user
+func (s *IndicatorService) GetIndicator(ctx context.Context, userID string, id int64) (*models.CustomIndicator, error) {
+	indicator, err := s.indicatorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Indicators of other users are reported as missing
+	if indicator == nil || indicator.UserID != userID {
+		return nil, fmt.Errorf("indicator not found")
+	}
+
+	describeIndicator(indicator)
+	return indicator, nil
+}
+
+// UpdateIndicator renames a custom indicator or replaces its expression
+func (s *IndicatorService) UpdateIndicator(ctx context.Context, userID string, id int64, req *models.UpdateIndicatorRequest) (*models.CustomIndicator, error) {
+	indicator, err := s.GetIndicator(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Name) != "" {
+		name, err := normalizeIndicatorName(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		owned, err := s.indicatorRepo.GetByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if indicatorNameTaken(owned, name, id) {
+			return nil, fmt.Errorf("validation failed: indicator name %q is taken", name)
+		}
+		indicator.Name = name
+	}
+	if expressionText := strings.TrimSpace(req.Expression); expressionText != "" {
+		expression, err := parseIndicatorExpression(expressionText)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		indicator.Expression = expressionText
+		indicator.Lookback = expression.Lookback()
+	}
+
+	if err := s.indicatorRepo.Update(ctx, indicator); err != nil {
+		return nil, err
+	}
+
+	return indicator, nil
+}
+
+// DeleteIndicator deletes a custom indicator
+func (s *IndicatorService) DeleteIndicator(ctx context.Context, userID string, id int64) error {
+	if _, err := s.GetIndicator(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.indicatorRepo.Delete(ctx, id)
+}
+
+// GetIndicatorSeries evaluates a custom indicator over a symbol's bars opening in [start, end],
+// or over its last limit bars when start is zero
+func (s *IndicatorService) GetIndicatorSeries(ctx context.Context, userID string, id int64, symbol, interval string, start, end time.Time, limit int) (*models.IndicatorSeries, error) {
+	duration, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("validation failed: unsupported interval %s", interval)
+	}
+	if start.IsZero() && (limit <= 0 || limit > maxIndicatorBars) {
+		return nil, fmt.Errorf("validation failed: limit must be between 1 and %d", maxIndicatorBars)
+	}
+
+	indicator, err := s.GetIndicator(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	expression, err := parseIndicatorExpression(indicator.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("stored expression of indicator %d no longer parses: %w", id, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	// Warm-up bars before the range, which are computed but not returned
+	var candles []models.Candle
+	if start.IsZero() {
+		candles, err = s.candleService.GetBySymbolAndInterval(ctx, symbol, interval, limit+expression.Lookback())
+	} else {
+		if err := validateTimeRange(interval, start, end); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		warmStart := start.Add(-time.Duration(expression.Lookback()) * duration)
+		candles, err = s.candleService.GetCandleRange(ctx, symbol, interval, warmStart, end)
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrQueryTimeout
+		}
+		if strings.HasPrefix(err.Error(), "time range too large") {
+			return nil, fmt.Errorf("validation failed: %w (including %d warm-up bars)", err, expression.Lookback())
+		}
+		return nil, err
+	}
+
+	columns := make(map[string][]float64, len(expression.Fields()))
+	for _, field := range expression.Fields() {
+		extract := queryFields[field]
+		column := make([]float64, len(candles))
+		for i, candle := range candles {
+			column[i] = extract(candle)
+		}
+		columns[field] = column
+	}
+	values := expression.Evaluate(columns, len(candles))
+
+	// Without a range, the series spans the last limit bars read
+	first := 0
+	if start.IsZero() {
+		first = max(len(candles)-limit, 0)
+		start, end = time.Now(), time.Now()
+		if len(candles) > 0 {
+			start, end = candles[first].OpenTime, candles[len(candles)-1].OpenTime
+		}
+	}
+	series := &models.IndicatorSeries{
+		ID: indicator.ID,
+		N:  indicator.Name,
+		E:  indicator.Expression,
+		S:  symbol,
+		I:  interval,
+		ST: start.UnixMilli(),
+		ET: end.UnixMilli(),
+		D:  make([]models.IndicatorBar, 0, len(candles)-first),
+	}
+	for i := first; i < len(candles); i++ {
+		if candles[i].OpenTime.Before(start) || math.IsNaN(values[i]) {
+			continue
+		}
+		series.D = append(series.D, models.IndicatorBar{T: candles[i].OpenTime.UnixMilli(), V: values[i]})
+	}
+
+	return series, nil
+}
+
+// normalizeIndicatorName trims and checks an indicator name
+func normalizeIndicatorName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("validation failed: name is required")
+	}
+	if len(name) > maxIndicatorNameLength {
+		return "", fmt.Errorf("validation failed: name is longer than %d characters", maxIndicatorNameLength)
+	}
+	return name, nil
+}
+
+// indicatorNameTaken reports whether another of the user's indicators than id has the name
+func indicatorNameTaken(owned []models.CustomIndicator, name string, id int64) bool {
+	for _, indicator := range owned {
+		if indicator.ID != id && strings.EqualFold(indicator.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeIndicator fills the warm-up a stored indicator's expression needs
+func describeIndicator(indicator *models.CustomIndicator) {
+	if expression, err := parseIndicatorExpression(indicator.Expression); err == nil {
+		indicator.Lookback = expression.Lookback()
+	}
+}
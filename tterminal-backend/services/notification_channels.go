@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+)
+
+// telegramAPIURL is the Bot API every Telegram webhook sends through
+const telegramAPIURL = "https://api.telegram.org"
+
+// telegramBotTokenPattern matches bot tokens issued by BotFather ("<bot id>:<secret>")
+var telegramBotTokenPattern = regexp.MustCompile(`^[0-9]{5,16}:[A-Za-z0-9_-]{30,64}$`)
+
+// telegramChatIDPattern matches numeric chat IDs and @channel usernames
+var telegramChatIDPattern = regexp.MustCompile(`^(-?[0-9]{1,20}|@[A-Za-z][A-Za-z0-9_]{4,31})$`)
+
+// discordWebhookHosts are the hosts Discord channel webhook URLs are issued on
+var discordWebhookHosts = []string{"discord.com", "discordapp.com", "ptb.discord.com", "canary.discord.com"}
+
+// validateDiscordURL checks a URL is a Discord channel webhook
+func validateDiscordURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "https" || !strings.HasPrefix(u.Path, "/api/webhooks/") {
+		return fmt.Errorf("url must be a Discord channel webhook URL (https://discord.com/api/webhooks/...)")
+	}
+	for _, host := range discordWebhookHosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("url must be a Discord channel webhook URL (https://discord.com/api/webhooks/...)")
+}
+
+// validateTelegramChat checks a Telegram bot token and the chat it sends to
+func validateTelegramChat(botToken, chatID string) error {
+	if !telegramBotTokenPattern.MatchString(botToken) {
+		return fmt.Errorf("bot_token must be a Telegram bot token (<bot id>:<secret>)")
+	}
+	if !telegramChatIDPattern.MatchString(chatID) {
+		return fmt.Errorf("chat_id must be a numeric Telegram chat ID or an @channel username")
+	}
+	return nil
+}
+
+// sendMessage delivers a delivery as a text message through a Telegram or Discord webhook,
+// returning the response status (0 when none)
+func (s *WebhookService) sendMessage(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	text, err := notificationText(delivery)
+	if err != nil {
+		return 0, err
+	}
+
+	var target string
+	var message interface{}
+	switch webhook.Channel {
+	case models.WebhookChannelTelegram:
+		target = webhook.URL + "/bot" + webhook.Secret + "/sendMessage"
+		message = map[string]interface{}{"chat_id": webhook.ChatID, "text": text, "disable_web_page_preview": true}
+	case models.WebhookChannelDiscord:
+		target = webhook.URL
+		message = map[string]interface{}{"content": text, "allowed_mentions": map[string][]string{"parse": {}}}
+	default:
+		return 0, fmt.Errorf("unknown channel %q", webhook.Channel)
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TTerminal-Webhooks/1.0")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		// The request URL carries the bot token (telegram) or webhook token (discord): keep it out of the delivery log
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("%s request failed: %w", webhook.Channel, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return resp.StatusCode, fmt.Errorf("%s answered %d: %s", webhook.Channel, resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
+
+// notificationText renders a delivery's event as a short plain text message
+func notificationText(delivery *models.WebhookDelivery) (string, error) {
+	switch delivery.Event {
+	case models.WebhookEventCandle:
+		var candle models.WebhookCandle
+		if err := json.Unmarshal(delivery.Payload, &candle); err != nil {
+			return "", fmt.Errorf("failed to decode payload: %w", err)
+		}
+		return fmt.Sprintf("%s %s candle of %s closed\nO %s  H %s  L %s  C %s\nVolume %s",
+			candle.Symbol, candle.Interval, formatMessageTime(candle.OpenTime),
+			formatMessageNumber(candle.Open), formatMessageNumber(candle.High), formatMessageNumber(candle.Low),
+			formatMessageNumber(candle.Close), formatMessageNumber(candle.Volume)), nil
+
+	case models.WebhookEventMarketEvent:
+		var event models.MarketEvent
+		if err := json.Unmarshal(delivery.Payload, &event); err != nil {
+			return "", fmt.Errorf("failed to decode payload: %w", err)
+		}
+		return fmt.Sprintf("%s %s %s at %s\nMagnitude %s (reference %s), close %s",
+			event.Symbol, event.Interval, strings.ReplaceAll(event.Type, "_", " "),
+			event.OpenTime.UTC().Format(time.RFC3339), formatMessageNumber(event.Magnitude),
+			formatMessageNumber(event.Reference), formatMessageNumber(event.Close)), nil
+
+	case models.WebhookEventAlert:
+		var alert models.WebhookAlert
+		if err := json.Unmarshal(delivery.Payload, &alert); err != nil {
+			return "", fmt.Errorf("failed to decode payload: %w", err)
+		}
+		direction := "up"
+		if alert.Trigger.Direction == models.AlertDirectionCrossDown {
+			direction = "down"
+		}
+		text := fmt.Sprintf("%s price alert: crossed %s through %s at %s (%s)",
+			alert.Alert.Symbol, direction, formatMessageNumber(alert.Trigger.TriggerPrice),
+			formatMessageNumber(alert.Trigger.Price), alert.Alert.Market)
+		if alert.Alert.Note != "" {
+			text += "\n" + alert.Alert.Note
+		}
+		return text, nil
+
+	case models.WebhookEventLiquidation:
+		var liquidation models.WebhookLiquidation
+		if err := json.Unmarshal(delivery.Payload, &liquidation); err != nil {
+			return "", fmt.Errorf("failed to decode payload: %w", err)
+		}
+		position := "short"
+		if liquidation.Side == "SELL" {
+			position = "long"
+		}
+		return fmt.Sprintf("%s %s liquidated: %s @ %s (notional %s)",
+			liquidation.Symbol, position, formatMessageNumber(liquidation.Quantity),
+			formatMessageNumber(liquidation.Price), formatMessageNumber(liquidation.Notional)), nil
+
+	case models.WebhookEventPing:
+		return "TTerminal test notification", nil
+
+	default:
+		return fmt.Sprintf("%s %s", delivery.Event, delivery.Payload), nil
+	}
+}
+
+// formatMessageNumber formats a number without trailing zeros
+func formatMessageNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatMessageTime formats Unix milliseconds as UTC RFC 3339
+func formatMessageTime(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/deribit"
+	"tterminal-backend/models"
+)
+
+const (
+	// optionsChainCacheTTL controls how long a chain snapshot is reused
+	optionsChainCacheTTL = 15 * time.Second
+	// dvolCacheTTL controls how long a DVOL candle series is reused
+	dvolCacheTTL = 30 * time.Second
+)
+
+// Errors returned when options data cannot be served
+var (
+	ErrOptionsDisabled       = errors.New("Deribit options data is disabled")
+	ErrOptionsCurrency       = errors.New("currency is not an enabled options currency")
+	ErrOptionsInterval       = errors.New("interval is not available for DVOL")
+	ErrOptionsExpiryNotFound = errors.New("expiry not found")
+)
+
+// OptionsService serves Deribit option chains, implied volatility term structures and DVOL candles
+type OptionsService struct {
+	deribitClient *deribit.Client
+	currencies    map[string]bool
+	chains        map[string]*models.OptionsChain
+	dvol          map[string]*models.DVOLResponse
+	cacheExpiry   map[string]time.Time
+	cacheMutex    sync.RWMutex
+}
+
+// NewOptionsService creates a new options service; a nil client disables options data
+func NewOptionsService(deribitClient *deribit.Client, currencies []string) *OptionsService {
+	if deribitClient == nil {
+		log.Printf("[OptionsService] DERIBIT_ENABLED is false - options endpoints will return 503")
+	}
+
+	enabled := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		enabled[strings.ToUpper(currency)] = true
+	}
+
+	log.Printf("[OptionsService] Successfully initialized")
+	return &OptionsService{
+		deribitClient: deribitClient,
+		currencies:    enabled,
+		chains:        make(map[string]*models.OptionsChain),
+		dvol:          make(map[string]*models.DVOLResponse),
+		cacheExpiry:   make(map[string]time.Time),
+	}
+}
+
+// GetChain returns the option chain of a currency, limited to one expiry when expiry is set
+func (s *OptionsService) GetChain(ctx context.Context, currency, expiry string) (*models.OptionsChain, error) {
+	chain, err := s.chain(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	if expiry == "" {
+		return chain, nil
+	}
+
+	expiry = strings.ToUpper(expiry)
+	for _, entry := range chain.Expiries {
+		if entry.Label == expiry {
+			filtered := *chain
+			filtered.Expiries = []models.OptionExpiry{entry}
+			return &filtered, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrOptionsExpiryNotFound, expiry)
+}
+
+// GetTermStructure returns the at-the-money implied volatility of every expiry with the latest DVOL
+func (s *OptionsService) GetTermStructure(ctx context.Context, currency string) (*models.IVTermStructure, error) {
+	chain, err := s.chain(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	term := &models.IVTermStructure{
+		Currency:   chain.Currency,
+		IndexPrice: chain.IndexPrice,
+		Timestamp:  chain.Timestamp,
+		Expiries:   make([]models.OptionExpiry, len(chain.Expiries)),
+	}
+	for i, entry := range chain.Expiries {
+		entry.Strikes = nil
+		term.Expiries[i] = entry
+	}
+
+	// DVOL is supplementary; the term structure is served without it
+	if dvol, err := s.GetDVOL(ctx, currency, "1m", 5); err != nil {
+		log.Printf("[OptionsService] Failed to fetch latest DVOL for %s: %v", chain.Currency, err)
+	} else if len(dvol.Candles) > 0 {
+		term.DVOL = dvol.Candles[len(dvol.Candles)-1].C
+	}
+
+	return term, nil
+}
+
+// GetDVOL returns the last limit DVOL candles of a currency, oldest first
+func (s *OptionsService) GetDVOL(ctx context.Context, currency, interval string, limit int) (*models.DVOLResponse, error) {
+	currency, err := s.checkCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := deribit.DVOLResolution(interval); !ok {
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrOptionsInterval, interval, strings.Join(deribit.DVOLIntervals(), ", "))
+	}
+
+	cacheKey := fmt.Sprintf("dvol:%s:%s:%d", currency, interval, limit)
+	s.cacheMutex.RLock()
+	cached, exists := s.dvol[cacheKey]
+	fresh := exists && time.Now().Before(s.cacheExpiry[cacheKey])
+	s.cacheMutex.RUnlock()
+	if fresh {
+		return cached, nil
+	}
+
+	candles, err := s.deribitClient.GetDVOLCandles(ctx, currency, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+	response := &models.DVOLResponse{
+		Currency: currency,
+		Interval: interval,
+		Count:    len(candles),
+		Candles:  candles,
+	}
+
+	s.cacheMutex.Lock()
+	s.dvol[cacheKey] = response
+	s.cacheExpiry[cacheKey] = time.Now().Add(dvolCacheTTL)
+	s.cacheMutex.Unlock()
+
+	return response, nil
+}
+
+// Currencies returns the enabled option currencies, sorted
+func (s *OptionsService) Currencies() []string {
+	currencies := make([]string, 0, len(s.currencies))
+	for currency := range s.currencies {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// chain returns the cached chain of a currency, fetching a new snapshot once it expires
+func (s *OptionsService) chain(ctx context.Context, currency string) (*models.OptionsChain, error) {
+	currency, err := s.checkCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := "chain:" + currency
+	s.cacheMutex.RLock()
+	cached, exists := s.chains[cacheKey]
+	fresh := exists && time.Now().Before(s.cacheExpiry[cacheKey])
+	s.cacheMutex.RUnlock()
+	if fresh {
+		return cached, nil
+	}
+
+	quotes, err := s.deribitClient.GetOptionQuotes(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	indexPrice, err := s.deribitClient.GetIndexPrice(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	chain := buildOptionsChain(currency, indexPrice, quotes, time.Now())
+
+	s.cacheMutex.Lock()
+	s.chains[cacheKey] = chain
+	s.cacheExpiry[cacheKey] = time.Now().Add(optionsChainCacheTTL)
+	s.cacheMutex.Unlock()
+
+	return chain, nil
+}
+
+// checkCurrency returns the upper-cased currency if options data is enabled for it
+func (s *OptionsService) checkCurrency(currency string) (string, error) {
+	if s.deribitClient == nil {
+		return "", ErrOptionsDisabled
+	}
+	currency = strings.ToUpper(currency)
+	if !s.currencies[currency] {
+		return "", fmt.Errorf("%w: %s (enabled: %s)", ErrOptionsCurrency, currency, strings.Join(s.Currencies(), ", "))
+	}
+	return currency, nil
+}
+
+// buildOptionsChain groups quotes by expiry and strike, dropping expired instruments, and derives
+// each expiry's forward, at-the-money strike and IV, open interest and put/call ratio
+func buildOptionsChain(currency string, indexPrice float64, quotes []models.OptionQuote, now time.Time) *models.OptionsChain {
+	type expiryGroup struct {
+		expiry  *models.OptionExpiry
+		strikes map[float64]*models.OptionStrike
+		callOI  float64
+		putOI   float64
+	}
+
+	groups := make(map[time.Time]*expiryGroup)
+	for i := range quotes {
+		quote := &quotes[i]
+		if !quote.Expiry.After(now) {
+			continue
+		}
+
+		group, exists := groups[quote.Expiry]
+		if !exists {
+			group = &expiryGroup{
+				expiry: &models.OptionExpiry{
+					Label:        deribit.ExpiryLabel(quote.Expiry),
+					Expiry:       quote.Expiry,
+					DaysToExpiry: quote.Expiry.Sub(now).Hours() / 24,
+				},
+				strikes: make(map[float64]*models.OptionStrike),
+			}
+			groups[quote.Expiry] = group
+		}
+		if group.expiry.Forward == 0 && quote.UnderlyingPrice > 0 {
+			group.expiry.Forward = quote.UnderlyingPrice
+		}
+
+		strike, exists := group.strikes[quote.Strike]
+		if !exists {
+			strike = &models.OptionStrike{Strike: quote.Strike}
+			group.strikes[quote.Strike] = strike
+		}
+		if quote.Type == models.OptionTypeCall {
+			strike.Call = quote
+			group.callOI += quote.OpenInterest
+		} else {
+			strike.Put = quote
+			group.putOI += quote.OpenInterest
+		}
+	}
+
+	chain := &models.OptionsChain{
+		Currency:   currency,
+		IndexPrice: indexPrice,
+		Timestamp:  now.UnixMilli(),
+		Expiries:   make([]models.OptionExpiry, 0, len(groups)),
+	}
+	for _, group := range groups {
+		expiry := group.expiry
+		expiry.OpenInterest = group.callOI + group.putOI
+		if group.callOI > 0 {
+			expiry.PutCallRatio = group.putOI / group.callOI
+		}
+
+		expiry.Strikes = make([]models.OptionStrike, 0, len(group.strikes))
+		for _, strike := range group.strikes {
+			expiry.Strikes = append(expiry.Strikes, *strike)
+		}
+		sort.Slice(expiry.Strikes, func(i, j int) bool {
+			return expiry.Strikes[i].Strike < expiry.Strikes[j].Strike
+		})
+
+		forward := expiry.Forward
+		if forward == 0 {
+			forward = indexPrice
+		}
+		var atm *models.OptionStrike
+		for i := range expiry.Strikes {
+			if atm == nil || math.Abs(expiry.Strikes[i].Strike-forward) < math.Abs(atm.Strike-forward) {
+				atm = &expiry.Strikes[i]
+			}
+		}
+		if atm != nil {
+			expiry.ATMStrike = atm.Strike
+			expiry.ATMIV = atmIV(atm)
+		}
+
+		chain.Expiries = append(chain.Expiries, *expiry)
+	}
+	sort.Slice(chain.Expiries, func(i, j int) bool {
+		return chain.Expiries[i].Expiry.Before(chain.Expiries[j].Expiry)
+	})
+
+	return chain
+}
+
+// atmIV averages the mark IVs of a strike's call and put, using whichever is quoted
+func atmIV(strike *models.OptionStrike) float64 {
+	var sum float64
+	var count int
+	for _, quote := range []*models.OptionQuote{strike.Call, strike.Put} {
+		if quote != nil && quote.MarkIV > 0 {
+			sum += quote.MarkIV
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// OrderFilterService validates orders against the exchange filters stored from exchangeInfo
+type OrderFilterService struct {
+	symbolRepo *repositories.SymbolRepository
+	stream     *websocket.BinanceStream
+}
+
+// NewOrderFilterService creates a new order filter validation service
+func NewOrderFilterService(symbolRepo *repositories.SymbolRepository, stream *websocket.BinanceStream) *OrderFilterService {
+	if symbolRepo == nil {
+		log.Fatalf("[OrderFilterService] CRITICAL: symbolRepo cannot be nil")
+	}
+	if stream == nil {
+		log.Printf("[OrderFilterService] WARNING: stream is nil - market orders and PERCENT_PRICE cannot be checked")
+	}
+
+	return &OrderFilterService{
+		symbolRepo: symbolRepo,
+		stream:     stream,
+	}
+}
+
+// ValidateOrder checks an order against the symbol's filters; a zero price validates a market order at the live price
+// Symbols without stored filters pass with FiltersChecked false
+func (s *OrderFilterService) ValidateOrder(ctx context.Context, req *models.ValidateOrderRequest) (*models.OrderValidationResult, error) {
+	req.Symbol = strings.ToUpper(req.Symbol)
+	if req.Symbol == "" {
+		return nil, fmt.Errorf("validation failed: symbol is required")
+	}
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("validation failed: quantity must be positive")
+	}
+	if req.Price < 0 {
+		return nil, fmt.Errorf("validation failed: price cannot be negative")
+	}
+
+	price := req.Price
+	market := price == 0
+	if market {
+		livePrice, ok := s.lastPrice(req.Symbol)
+		if !ok {
+			return nil, fmt.Errorf("no live price for symbol %s", req.Symbol)
+		}
+		price = livePrice
+	}
+
+	return s.Check(ctx, req.Symbol, req.Quantity, price, market)
+}
+
+// Check validates a quantity and price against the symbol's filters
+// For market orders price is the expected fill price and only sizes the notional check
+func (s *OrderFilterService) Check(ctx context.Context, symbol string, quantity, price float64, market bool) (*models.OrderValidationResult, error) {
+	result := &models.OrderValidationResult{
+		Symbol:    symbol,
+		Valid:     true,
+		OrderType: models.OrderTypeLimit,
+		Quantity:  quantity,
+		Price:     price,
+	}
+	if market {
+		result.OrderType = models.OrderTypeMarket
+	} else {
+		result.ReferencePrice = s.markPrice(symbol)
+	}
+
+	stored, err := s.symbolRepo.GetBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load filters for %s: %w", symbol, err)
+	}
+	if stored == nil || !stored.HasOrderFilters() {
+		return result, nil
+	}
+
+	result.FiltersChecked = true
+	result.Violations = stored.ValidateOrder(quantity, price, result.ReferencePrice, market)
+	result.Valid = len(result.Violations) == 0
+	return result, nil
+}
+
+// lastPrice returns the last traded price from the stream
+func (s *OrderFilterService) lastPrice(symbol string) (float64, bool) {
+	if s.stream == nil {
+		return 0, false
+	}
+	price, ok := s.stream.GetLastPrice(symbol)
+	return price, ok && price > 0
+}
+
+// markPrice returns the streamed mark price, or 0 when unknown
+func (s *OrderFilterService) markPrice(symbol string) float64 {
+	if s.stream == nil {
+		return 0
+	}
+	if markPrice, ok := s.stream.GetMarkPriceData(symbol); ok {
+		return markPrice.MarkPrice
+	}
+	return 0
+}
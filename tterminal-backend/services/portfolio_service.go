@@ -0,0 +1,615 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// fundingSettlementInterval controls how often open positions are checked for settled funding
+const fundingSettlementInterval = time.Minute
+
+// PortfolioService manages sub-account portfolios and executes paper orders against live prices
+type PortfolioService struct {
+	portfolioRepo *repositories.PortfolioRepository
+	binanceClient *binance.Client
+	stream        *websocket.BinanceStream
+	fees          models.FeeSchedule
+
+	isRunning bool
+	stopChan  chan struct{}
+	mu        sync.Mutex
+
+	// Orders are serialized per portfolio so risk checks see a consistent state
+	orderLocks     map[int64]*sync.Mutex
+	orderLocksLock sync.Mutex
+
+	// Optional exchange filter validation before fills (set after construction)
+	orderFilters *OrderFilterService
+}
+
+// NewPortfolioService creates a new portfolio service
+func NewPortfolioService(portfolioRepo *repositories.PortfolioRepository, binanceClient *binance.Client, stream *websocket.BinanceStream, fees models.FeeSchedule) *PortfolioService {
+	if portfolioRepo == nil {
+		log.Fatalf("[PortfolioService] CRITICAL: portfolioRepo cannot be nil")
+	}
+	if binanceClient == nil {
+		log.Fatalf("[PortfolioService] CRITICAL: binanceClient cannot be nil")
+	}
+	if stream == nil {
+		log.Printf("[PortfolioService] WARNING: stream is nil - orders cannot be filled and PnL uses entry prices")
+	}
+
+	return &PortfolioService{
+		portfolioRepo: portfolioRepo,
+		binanceClient: binanceClient,
+		stream:        stream,
+		fees:          fees,
+		stopChan:      make(chan struct{}),
+		orderLocks:    make(map[int64]*sync.Mutex),
+	}
+}
+
+// SetOrderFilters enables validation of orders against stored exchange filters
+func (s *PortfolioService) SetOrderFilters(orderFilters *OrderFilterService) {
+	s.orderFilters = orderFilters
+}
+
+// Start begins settling funding payments for open positions
+func (s *PortfolioService) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return fmt.Errorf("portfolio service is already running")
+	}
+	s.isRunning = true
+
+	log.Printf("[PortfolioService] Starting funding settlement (taker fee %.4f%%, maker fee %.4f%%)",
+		s.fees.TakerRate*100, s.fees.MakerRate*100)
+	go s.fundingLoop()
+
+	return nil
+}
+
+// Stop stops funding settlement
+func (s *PortfolioService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+}
+
+// CreatePortfolio creates a new portfolio for a user
+func (s *PortfolioService) CreatePortfolio(ctx context.Context, userID string, req *models.CreatePortfolioRequest) (*models.Portfolio, error) {
+	if req.Mode == "" {
+		req.Mode = models.PortfolioModePaper
+	}
+	if err := s.validateCreatePortfolioRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	portfolio := &models.Portfolio{
+		UserID:              userID,
+		Name:                strings.TrimSpace(req.Name),
+		Mode:                req.Mode,
+		InitialBalance:      req.InitialBalance,
+		Balance:             req.InitialBalance,
+		MaxLeverage:         req.MaxLeverage,
+		MaxPositionNotional: req.MaxPositionNotional,
+	}
+
+	if err := s.portfolioRepo.Create(ctx, portfolio); err != nil {
+		return nil, err
+	}
+
+	return portfolio, nil
+}
+
+// GetPortfolios returns all portfolios of a user
+func (s *PortfolioService) GetPortfolios(ctx context.Context, userID string) ([]models.Portfolio, error) {
+	return s.portfolioRepo.GetByUser(ctx, userID)
+}
+
+// GetPortfolio returns a portfolio owned by the user
+func (s *PortfolioService) GetPortfolio(ctx context.Context, userID string, id int64) (*models.Portfolio, error) {
+	portfolio, err := s.portfolioRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Portfolios of other users are reported as missing
+	if portfolio == nil || portfolio.UserID != userID {
+		return nil, fmt.Errorf("portfolio not found")
+	}
+
+	return portfolio, nil
+}
+
+// UpdatePortfolio updates a portfolio's name and risk limits
+func (s *PortfolioService) UpdatePortfolio(ctx context.Context, userID string, id int64, req *models.UpdatePortfolioRequest) (*models.Portfolio, error) {
+	portfolio, err := s.GetPortfolio(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		portfolio.Name = name
+	}
+	if req.MaxLeverage != nil {
+		if *req.MaxLeverage < 0 {
+			return nil, fmt.Errorf("validation failed: max_leverage cannot be negative")
+		}
+		portfolio.MaxLeverage = *req.MaxLeverage
+	}
+	if req.MaxPositionNotional != nil {
+		if *req.MaxPositionNotional < 0 {
+			return nil, fmt.Errorf("validation failed: max_position_notional cannot be negative")
+		}
+		portfolio.MaxPositionNotional = *req.MaxPositionNotional
+	}
+
+	if err := s.portfolioRepo.Update(ctx, portfolio); err != nil {
+		return nil, err
+	}
+
+	return portfolio, nil
+}
+
+// DeletePortfolio deletes a portfolio with its positions and orders
+func (s *PortfolioService) DeletePortfolio(ctx context.Context, userID string, id int64) error {
+	if _, err := s.GetPortfolio(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.portfolioRepo.Delete(ctx, id)
+}
+
+// GetOrders returns the most recent orders of a portfolio
+func (s *PortfolioService) GetOrders(ctx context.Context, userID string, id int64, limit int) ([]models.PortfolioOrder, error) {
+	if _, err := s.GetPortfolio(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.portfolioRepo.GetOrders(ctx, id, limit)
+}
+
+// PlaceOrder fills a market order in the selected portfolio at the live price
+func (s *PortfolioService) PlaceOrder(ctx context.Context, userID string, req *models.PlaceOrderRequest) (*models.PortfolioOrder, error) {
+	req.Symbol = strings.ToUpper(req.Symbol)
+	req.Side = strings.ToUpper(req.Side)
+	if req.Symbol == "" {
+		return nil, fmt.Errorf("validation failed: symbol is required")
+	}
+	if req.Side != models.OrderSideBuy && req.Side != models.OrderSideSell {
+		return nil, fmt.Errorf("validation failed: side must be BUY or SELL")
+	}
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("validation failed: quantity must be positive")
+	}
+
+	lock := s.orderLock(req.PortfolioID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	portfolio, err := s.GetPortfolio(ctx, userID, req.PortfolioID)
+	if err != nil {
+		return nil, err
+	}
+	if portfolio.Mode != models.PortfolioModePaper {
+		return nil, fmt.Errorf("live order routing is not available, use a paper portfolio")
+	}
+
+	price, ok := s.livePrice(req.Symbol)
+	if !ok {
+		return nil, fmt.Errorf("no live price for symbol %s", req.Symbol)
+	}
+
+	// Reject orders the exchange would refuse
+	if s.orderFilters != nil {
+		check, err := s.orderFilters.Check(ctx, req.Symbol, req.Quantity, price, true)
+		if err != nil {
+			return nil, err
+		}
+		if !check.Valid {
+			return nil, &models.OrderFilterError{Symbol: req.Symbol, Violations: check.Violations}
+		}
+	}
+
+	positions, err := s.portfolioRepo.GetPositions(ctx, portfolio.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := models.PortfolioPosition{PortfolioID: portfolio.ID, Symbol: req.Symbol}
+	for _, p := range positions {
+		if p.Symbol == req.Symbol {
+			position = p
+			break
+		}
+	}
+
+	delta := req.Quantity
+	if req.Side == models.OrderSideSell {
+		delta = -delta
+	}
+	updated, realized := applyFillToPosition(position, delta, price)
+
+	// Market orders take liquidity and pay the taker fee
+	fee := req.Quantity * price * s.fees.TakerRate
+	updated.FeesPaid += fee
+
+	if math.Abs(updated.Quantity) > math.Abs(position.Quantity) {
+		if err := s.checkRiskLimits(portfolio, positions, updated, price, realized-fee); err != nil {
+			return nil, err
+		}
+	}
+
+	order := &models.PortfolioOrder{
+		PortfolioID: portfolio.ID,
+		Symbol:      req.Symbol,
+		Side:        req.Side,
+		Quantity:    req.Quantity,
+		Price:       price,
+		RealizedPnL: realized,
+		Fee:         fee,
+	}
+	if err := s.portfolioRepo.ApplyFill(ctx, order, &updated); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetPortfolioPnL returns the PnL report of a single portfolio
+func (s *PortfolioService) GetPortfolioPnL(ctx context.Context, userID string, id int64) (*models.PortfolioPnL, error) {
+	portfolio, err := s.GetPortfolio(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildPnL(ctx, portfolio)
+}
+
+// GetAggregatePnL returns per-portfolio PnL reports and their totals for a user
+func (s *PortfolioService) GetAggregatePnL(ctx context.Context, userID string) (*models.AggregatePnL, error) {
+	portfolios, err := s.portfolioRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate := &models.AggregatePnL{
+		UserID:     userID,
+		Portfolios: make([]models.PortfolioPnL, 0, len(portfolios)),
+		Timestamp:  time.Now().UnixMilli(),
+	}
+
+	for i := range portfolios {
+		pnl, err := s.buildPnL(ctx, &portfolios[i])
+		if err != nil {
+			return nil, err
+		}
+
+		aggregate.InitialBalance += pnl.InitialBalance
+		aggregate.Balance += pnl.Balance
+		aggregate.RealizedPnL += pnl.RealizedPnL
+		aggregate.UnrealizedPnL += pnl.UnrealizedPnL
+		aggregate.FeesPaid += pnl.FeesPaid
+		aggregate.FundingAccrued += pnl.FundingAccrued
+		aggregate.Equity += pnl.Equity
+		aggregate.GrossNotional += pnl.GrossNotional
+		aggregate.Portfolios = append(aggregate.Portfolios, *pnl)
+	}
+
+	if aggregate.InitialBalance > 0 {
+		aggregate.ReturnPct = (aggregate.Equity - aggregate.InitialBalance) / aggregate.InitialBalance * 100
+	}
+	aggregate.Count = len(aggregate.Portfolios)
+
+	return aggregate, nil
+}
+
+// buildPnL values a portfolio's positions at live prices
+func (s *PortfolioService) buildPnL(ctx context.Context, portfolio *models.Portfolio) (*models.PortfolioPnL, error) {
+	positions, err := s.portfolioRepo.GetPositions(ctx, portfolio.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pnl := &models.PortfolioPnL{
+		PortfolioID:    portfolio.ID,
+		Name:           portfolio.Name,
+		Mode:           portfolio.Mode,
+		InitialBalance: portfolio.InitialBalance,
+		Balance:        portfolio.Balance,
+		RealizedPnL:    portfolio.RealizedPnL,
+		FeesPaid:       portfolio.FeesPaid,
+		FundingAccrued: portfolio.FundingAccrued,
+		FeeSchedule:    s.fees,
+		Positions:      make([]models.PositionPnL, 0, len(positions)),
+		Timestamp:      time.Now().UnixMilli(),
+	}
+
+	for _, position := range positions {
+		if position.Quantity == 0 {
+			continue
+		}
+
+		markPrice, ok := s.livePrice(position.Symbol)
+		if !ok {
+			markPrice = position.EntryPrice
+		}
+
+		unrealized := (markPrice - position.EntryPrice) * position.Quantity
+		notional := math.Abs(position.Quantity) * markPrice
+		closeFee := notional * s.fees.TakerRate
+
+		pnl.UnrealizedPnL += unrealized
+		pnl.GrossNotional += notional
+		pnl.Positions = append(pnl.Positions, models.PositionPnL{
+			Symbol:           position.Symbol,
+			Quantity:         position.Quantity,
+			EntryPrice:       position.EntryPrice,
+			MarkPrice:        markPrice,
+			Notional:         notional,
+			UnrealizedPnL:    unrealized,
+			RealizedPnL:      position.RealizedPnL,
+			FeesPaid:         position.FeesPaid,
+			ExpectedCloseFee: closeFee,
+			FundingAccrued:   position.FundingAccrued,
+			NetPnL:           position.RealizedPnL + unrealized + position.FundingAccrued - position.FeesPaid - closeFee,
+			BreakEvenPrice:   breakEvenPrice(position, s.fees.TakerRate),
+		})
+	}
+
+	pnl.Equity = pnl.Balance + pnl.UnrealizedPnL
+	if pnl.InitialBalance > 0 {
+		pnl.ReturnPct = (pnl.Equity - pnl.InitialBalance) / pnl.InitialBalance * 100
+	}
+	if pnl.Equity > 0 {
+		pnl.Leverage = pnl.GrossNotional / pnl.Equity
+	}
+
+	return pnl, nil
+}
+
+// checkRiskLimits rejects orders that would breach the portfolio's position or leverage limits
+func (s *PortfolioService) checkRiskLimits(portfolio *models.Portfolio, positions []models.PortfolioPosition, updated models.PortfolioPosition, price, realized float64) error {
+	notional := math.Abs(updated.Quantity) * price
+	if portfolio.MaxPositionNotional > 0 && notional > portfolio.MaxPositionNotional {
+		return fmt.Errorf("risk limit exceeded: %s notional %.2f above max position notional %.2f",
+			updated.Symbol, notional, portfolio.MaxPositionNotional)
+	}
+
+	grossNotional := notional
+	equity := portfolio.Balance + realized + (price-updated.EntryPrice)*updated.Quantity
+	for _, position := range positions {
+		if position.Symbol == updated.Symbol || position.Quantity == 0 {
+			continue
+		}
+		markPrice, ok := s.livePrice(position.Symbol)
+		if !ok {
+			markPrice = position.EntryPrice
+		}
+		grossNotional += math.Abs(position.Quantity) * markPrice
+		equity += (markPrice - position.EntryPrice) * position.Quantity
+	}
+
+	if equity <= 0 {
+		return fmt.Errorf("risk limit exceeded: portfolio equity is not positive")
+	}
+	if portfolio.MaxLeverage > 0 && grossNotional/equity > portfolio.MaxLeverage {
+		return fmt.Errorf("risk limit exceeded: leverage %.2fx above max leverage %.2fx",
+			grossNotional/equity, portfolio.MaxLeverage)
+	}
+
+	return nil
+}
+
+// livePrice returns the last traded price, falling back to the mark price
+func (s *PortfolioService) livePrice(symbol string) (float64, bool) {
+	if s.stream == nil {
+		return 0, false
+	}
+	if price, ok := s.stream.GetLastPrice(symbol); ok && price > 0 {
+		return price, true
+	}
+	if markPrice, ok := s.stream.GetMarkPriceData(symbol); ok && markPrice.MarkPrice > 0 {
+		return markPrice.MarkPrice, true
+	}
+	return 0, false
+}
+
+// orderLock returns the order mutex of a portfolio
+func (s *PortfolioService) orderLock(portfolioID int64) *sync.Mutex {
+	s.orderLocksLock.Lock()
+	defer s.orderLocksLock.Unlock()
+
+	lock, exists := s.orderLocks[portfolioID]
+	if !exists {
+		lock = &sync.Mutex{}
+		s.orderLocks[portfolioID] = lock
+	}
+	return lock
+}
+
+// validateCreatePortfolioRequest validates the create portfolio request
+func (s *PortfolioService) validateCreatePortfolioRequest(req *models.CreatePortfolioRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if req.Mode != models.PortfolioModePaper && req.Mode != models.PortfolioModeReal {
+		return fmt.Errorf("mode must be paper or real")
+	}
+	if req.InitialBalance <= 0 {
+		return fmt.Errorf("initial_balance must be positive")
+	}
+	if req.MaxLeverage < 0 || req.MaxPositionNotional < 0 {
+		return fmt.Errorf("risk limits cannot be negative")
+	}
+	return nil
+}
+
+// applyFillToPosition applies a signed fill quantity to a position using average entry pricing
+// and returns the updated position with the PnL realized by the fill
+func applyFillToPosition(position models.PortfolioPosition, delta, price float64) (models.PortfolioPosition, float64) {
+	var realized float64
+
+	if position.Quantity == 0 {
+		// A position reopened from flat starts a new fee and funding history
+		position.RealizedPnL = 0
+		position.FeesPaid = 0
+		position.FundingAccrued = 0
+		position.FundingSettledAt = time.Now()
+	}
+
+	switch {
+	case position.Quantity == 0 || (position.Quantity > 0) == (delta > 0):
+		// Opening or adding to a position
+		size := math.Abs(position.Quantity)
+		position.EntryPrice = (size*position.EntryPrice + math.Abs(delta)*price) / (size + math.Abs(delta))
+		position.Quantity = roundQuantity(position.Quantity + delta)
+
+	default:
+		// Reducing, closing or flipping a position
+		closed := math.Min(math.Abs(position.Quantity), math.Abs(delta))
+		if position.Quantity > 0 {
+			realized = closed * (price - position.EntryPrice)
+		} else {
+			realized = closed * (position.EntryPrice - price)
+		}
+
+		previous := position.Quantity
+		position.Quantity = roundQuantity(position.Quantity + delta)
+		if position.Quantity == 0 {
+			position.EntryPrice = 0
+		} else if (previous > 0) != (position.Quantity > 0) {
+			position.EntryPrice = price
+		}
+	}
+
+	position.RealizedPnL += realized
+	return position, realized
+}
+
+// roundQuantity rounds to the stored quantity precision so closed positions net to exactly zero
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*1e8) / 1e8
+}
+
+// breakEvenPrice returns the close price at which the position's realized PnL, funding and fees
+// (including the taker fee to close) net to zero
+func breakEvenPrice(position models.PortfolioPosition, takerRate float64) float64 {
+	quantity := position.Quantity
+	denominator := quantity - math.Abs(quantity)*takerRate
+	if denominator == 0 {
+		return 0
+	}
+	costs := quantity*position.EntryPrice + position.FeesPaid - position.FundingAccrued - position.RealizedPnL
+	return math.Max(costs/denominator, 0)
+}
+
+// fundingLoop periodically settles funding for open positions
+func (s *PortfolioService) fundingLoop() {
+	ticker := time.NewTicker(fundingSettlementInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.settleFunding()
+		}
+	}
+}
+
+// settleFunding applies funding settled since each open position's last settlement
+// Longs pay and shorts receive when the funding rate is positive
+func (s *PortfolioService) settleFunding() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	positions, err := s.portfolioRepo.GetOpenPositions(ctx)
+	if err != nil {
+		log.Printf("[PortfolioService] ERROR loading open positions: %v", err)
+		return
+	}
+
+	// Fetch funding history once per symbol from the oldest unsettled position
+	oldest := make(map[string]time.Time)
+	for _, position := range positions {
+		if since, exists := oldest[position.Symbol]; !exists || position.FundingSettledAt.Before(since) {
+			oldest[position.Symbol] = position.FundingSettledAt
+		}
+	}
+
+	rates := make(map[string][]binance.FundingRate, len(oldest))
+	for symbol, since := range oldest {
+		if time.Since(since) < fundingSettlementInterval {
+			continue
+		}
+		history, err := s.binanceClient.GetFundingRateHistory(ctx, symbol, since.Add(time.Millisecond), time.Time{}, 100)
+		if err != nil {
+			log.Printf("[PortfolioService] ERROR fetching funding history for %s: %v", symbol, err)
+			continue
+		}
+		if len(history) > 0 {
+			rates[symbol] = history
+		}
+	}
+
+	for _, position := range positions {
+		if history, exists := rates[position.Symbol]; exists {
+			s.settlePositionFunding(ctx, position.PortfolioID, position.Symbol, history)
+		}
+	}
+}
+
+// settlePositionFunding applies funding rates newer than the position's last settlement
+func (s *PortfolioService) settlePositionFunding(ctx context.Context, portfolioID int64, symbol string, history []binance.FundingRate) {
+	lock := s.orderLock(portfolioID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Re-read under the order lock so fills since loading are respected
+	position, err := s.portfolioRepo.GetPosition(ctx, portfolioID, symbol)
+	if err != nil || position == nil || position.Quantity == 0 {
+		return
+	}
+
+	var payment float64
+	settledAt := position.FundingSettledAt
+	for _, rate := range history {
+		fundingTime := time.UnixMilli(rate.FundingTime)
+		if !fundingTime.After(position.FundingSettledAt) {
+			continue
+		}
+
+		markPrice := models.ParseFloat(rate.MarkPrice)
+		if markPrice == 0 {
+			markPrice = position.EntryPrice
+		}
+		payment -= position.Quantity * markPrice * models.ParseFloat(rate.FundingRate)
+		if fundingTime.After(settledAt) {
+			settledAt = fundingTime
+		}
+	}
+
+	if settledAt.Equal(position.FundingSettledAt) {
+		return
+	}
+
+	if err := s.portfolioRepo.ApplyFunding(ctx, portfolioID, symbol, payment, settledAt); err != nil {
+		log.Printf("[PortfolioService] ERROR applying funding for portfolio %d %s: %v", portfolioID, symbol, err)
+	}
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxAccountFills caps the fills returned per request
+	maxAccountFills = 1000
+	// positionWriteTimeout bounds persisting a position or fill from the user data stream
+	positionWriteTimeout = 5 * time.Second
+	// positionSyncTimeout bounds the position snapshot taken on every user data (re)connect
+	positionSyncTimeout = 10 * time.Second
+)
+
+// PositionService tracks the open futures positions of the deployment's Binance account from
+// the user data stream: ACCOUNT_UPDATE events set quantities and entry prices, and fills add
+// realized PnL and fees. Positions are re-synced from the position risk endpoint on every
+// stream (re)connect, since updates may have been missed while disconnected, and valued at the
+// streamed mark price on read
+type PositionService struct {
+	positionRepo  *repositories.AccountPositionRepository
+	binanceClient *binance.Client
+	stream        *websocket.BinanceStream
+	wsURL         string
+
+	mu        sync.RWMutex
+	positions map[string]*models.AccountPosition // Keyed by symbol and position side
+	isRunning bool
+}
+
+// NewPositionService creates a new position service streaming user data from wsURL
+func NewPositionService(positionRepo *repositories.AccountPositionRepository, binanceClient *binance.Client, stream *websocket.BinanceStream, wsURL string) *PositionService {
+	if positionRepo == nil {
+		log.Fatalf("[PositionService] CRITICAL: positionRepo cannot be nil")
+	}
+	if binanceClient == nil {
+		log.Fatalf("[PositionService] CRITICAL: binanceClient cannot be nil")
+	}
+	if stream == nil {
+		log.Fatalf("[PositionService] CRITICAL: stream cannot be nil")
+	}
+
+	log.Printf("[PositionService] Successfully initialized")
+	return &PositionService{
+		positionRepo:  positionRepo,
+		binanceClient: binanceClient,
+		stream:        stream,
+		wsURL:         wsURL,
+		positions:     make(map[string]*models.AccountPosition),
+	}
+}
+
+// Start loads the stored positions and subscribes to the account's user data stream
+func (s *PositionService) Start() error {
+	s.mu.Lock()
+	if s.isRunning {
+		s.mu.Unlock()
+		return fmt.Errorf("position service is already running")
+	}
+	s.isRunning = true
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), positionSyncTimeout)
+	defer cancel()
+
+	positions, err := s.positionRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for i := range positions {
+		position := positions[i]
+		s.positions[positionKey(position.Symbol, position.PositionSide)] = &position
+	}
+	s.mu.Unlock()
+
+	log.Printf("[PositionService] Loaded %d stored positions", len(positions))
+	return s.stream.EnableUserData(s.wsURL, s.binanceClient, s)
+}
+
+// Stop stops receiving position updates and closes the user data stream's listen key
+func (s *PositionService) Stop() {
+	s.mu.Lock()
+	if !s.isRunning {
+		s.mu.Unlock()
+		return
+	}
+	s.isRunning = false
+	s.mu.Unlock()
+
+	s.stream.DisableUserData()
+	log.Printf("[PositionService] Stopped")
+}
+
+// StreamConnected reports whether position updates are being received
+func (s *PositionService) StreamConnected() bool {
+	return s.stream.UserDataConnected()
+}
+
+// GetPositions returns the open positions valued at the mark price, with their combined PnL
+func (s *PositionService) GetPositions() *models.AccountPositionsResponse {
+	return s.positionsResponse("")
+}
+
+// GetPosition returns the open positions in a symbol (both sides in hedge mode)
+func (s *PositionService) GetPosition(symbol string) (*models.AccountPositionsResponse, error) {
+	response := s.positionsResponse(strings.ToUpper(symbol))
+	if response.Count == 0 {
+		return nil, fmt.Errorf("position not found")
+	}
+	return response, nil
+}
+
+// GetFills returns the most recent fills, newest first, optionally for one symbol and after since
+func (s *PositionService) GetFills(ctx context.Context, symbol string, since time.Time, limit int) ([]models.AccountFill, error) {
+	if limit <= 0 || limit > maxAccountFills {
+		limit = maxAccountFills
+	}
+	return s.positionRepo.GetFills(ctx, strings.ToUpper(symbol), since, limit)
+}
+
+// HandleUserDataConnected re-syncs every position from the position risk endpoint
+func (s *PositionService) HandleUserDataConnected() {
+	ctx, cancel := context.WithTimeout(context.Background(), positionSyncTimeout)
+	defer cancel()
+
+	risks, err := s.binanceClient.GetPositionRisk(ctx)
+	if err != nil {
+		log.Printf("[PositionService] Failed to sync positions, keeping stored ones: %v", err)
+		return
+	}
+
+	now := time.Now()
+	reported := make(map[string]bool, len(risks))
+	for _, risk := range risks {
+		key := positionKey(risk.Symbol, risk.PositionSide)
+		quantity := models.ParseFloat(risk.PositionAmt)
+		if quantity == 0 && !s.isOpen(key) {
+			continue
+		}
+		reported[key] = true
+		s.applyPosition(risk.Symbol, risk.PositionSide, quantity, models.ParseFloat(risk.EntryPrice), 0, risk.MarginType, now)
+	}
+
+	// Positions missing from the snapshot were closed and are no longer reported
+	for _, position := range s.openPositions("") {
+		if !reported[positionKey(position.Symbol, position.PositionSide)] {
+			s.applyPosition(position.Symbol, position.PositionSide, 0, 0, 0, "", now)
+		}
+	}
+
+	log.Printf("[PositionService] Synced %d open positions", len(s.openPositions("")))
+}
+
+// HandleAccountUpdate applies the positions changed in an ACCOUNT_UPDATE event
+func (s *PositionService) HandleAccountUpdate(update websocket.BinanceAccountUpdate) {
+	at := time.UnixMilli(update.TransactionTime)
+	for _, position := range update.Account.Positions {
+		s.applyPosition(
+			position.Symbol, position.PositionSide,
+			models.ParseFloat(position.PositionAmt), models.ParseFloat(position.EntryPrice),
+			models.ParseFloat(position.BreakEvenPrice), position.MarginType, at,
+		)
+	}
+}
+
+// HandleOrderTradeUpdate records a fill and adds its realized PnL and fee to the position
+// Binance sends the fill's ACCOUNT_UPDATE first, so the position already holds the new quantity
+func (s *PositionService) HandleOrderTradeUpdate(update websocket.BinanceOrderTradeUpdate) {
+	order := update.Order
+	if order.ExecutionType != "TRADE" {
+		return
+	}
+
+	fill := &models.AccountFill{
+		Symbol:          order.Symbol,
+		PositionSide:    positionSide(order.PositionSide),
+		TradeID:         order.TradeID,
+		OrderID:         order.OrderID,
+		ClientOrderID:   order.ClientOrderID,
+		Side:            order.Side,
+		Price:           models.ParseFloat(order.LastFilledPrice),
+		Quantity:        models.ParseFloat(order.LastFilledQty),
+		RealizedPnL:     models.ParseFloat(order.RealizedProfit),
+		Commission:      models.ParseFloat(order.Commission),
+		CommissionAsset: order.CommissionAsset,
+		IsMaker:         order.IsMaker,
+		TradedAt:        time.UnixMilli(order.TradeTime),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), positionWriteTimeout)
+	defer cancel()
+
+	created, err := s.positionRepo.CreateFill(ctx, fill)
+	if err != nil {
+		log.Printf("[PositionService] Failed to record fill %s #%d: %v", fill.Symbol, fill.TradeID, err)
+		return
+	}
+	if !created {
+		return
+	}
+
+	// Fees in another asset (BNB) are not in the position's quote asset and are left out
+	fee := 0.0
+	if strings.HasSuffix(fill.Symbol, fill.CommissionAsset) {
+		fee = fill.Commission
+	}
+
+	s.mu.Lock()
+	key := positionKey(fill.Symbol, fill.PositionSide)
+	position, exists := s.positions[key]
+	if !exists {
+		position = &models.AccountPosition{Symbol: fill.Symbol, PositionSide: fill.PositionSide, OpenedAt: fill.TradedAt}
+		s.positions[key] = position
+	}
+	position.RealizedPnL += fill.RealizedPnL
+	position.FeesPaid += fee
+	position.UpdatedAt = fill.TradedAt
+	saved := *position
+	s.mu.Unlock()
+
+	if err := s.positionRepo.Save(ctx, &saved); err != nil {
+		log.Printf("[PositionService] Failed to save position %s: %v", saved.Symbol, err)
+	}
+}
+
+// applyPosition sets a position's quantity and prices; a position opened from flat or flipped
+// starts over with no realized PnL or fees. Zero prices and an empty margin type keep the current values
+func (s *PositionService) applyPosition(symbol, side string, quantity, entryPrice, breakEvenPrice float64, marginType string, at time.Time) {
+	side = positionSide(side)
+
+	s.mu.Lock()
+	key := positionKey(symbol, side)
+	position, exists := s.positions[key]
+	if !exists {
+		position = &models.AccountPosition{Symbol: symbol, PositionSide: side, OpenedAt: at}
+		s.positions[key] = position
+	}
+
+	if quantity != 0 && (position.Quantity == 0 || (quantity > 0) != (position.Quantity > 0)) {
+		position.RealizedPnL = 0
+		position.FeesPaid = 0
+		position.OpenedAt = at
+	}
+
+	position.Quantity = quantity
+	if quantity == 0 {
+		position.EntryPrice = 0
+		position.BreakEvenPrice = 0
+	} else {
+		if entryPrice > 0 {
+			position.EntryPrice = entryPrice
+		}
+		if breakEvenPrice > 0 {
+			position.BreakEvenPrice = breakEvenPrice
+		}
+	}
+	if marginType != "" {
+		position.MarginType = strings.ToLower(marginType)
+	}
+	position.UpdatedAt = at
+	saved := *position
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), positionWriteTimeout)
+	defer cancel()
+	if err := s.positionRepo.Save(ctx, &saved); err != nil {
+		log.Printf("[PositionService] Failed to save position %s: %v", symbol, err)
+	}
+}
+
+// positionsResponse values the open positions, optionally of one symbol, at the mark price
+func (s *PositionService) positionsResponse(symbol string) *models.AccountPositionsResponse {
+	response := &models.AccountPositionsResponse{
+		Positions:       []models.AccountPositionPnL{},
+		StreamConnected: s.StreamConnected(),
+		Timestamp:       time.Now().UnixMilli(),
+	}
+
+	for _, position := range s.openPositions(symbol) {
+		markPrice := position.EntryPrice
+		if markData, ok := s.stream.GetMarkPriceData(position.Symbol); ok && markData.MarkPrice > 0 {
+			markPrice = markData.MarkPrice
+		}
+
+		unrealized := (markPrice - position.EntryPrice) * position.Quantity
+		notional := math.Abs(position.Quantity) * markPrice
+		pnl := models.AccountPositionPnL{
+			AccountPosition: position,
+			Side:            models.PositionSideLong,
+			MarkPrice:       markPrice,
+			Notional:        notional,
+			UnrealizedPnL:   unrealized,
+			NetPnL:          position.RealizedPnL + unrealized - position.FeesPaid,
+		}
+		if position.Quantity < 0 {
+			pnl.Side = models.PositionSideShort
+		}
+		if entryNotional := math.Abs(position.Quantity) * position.EntryPrice; entryNotional > 0 {
+			pnl.UnrealizedPnLPct = unrealized / entryNotional * 100
+		}
+
+		response.Positions = append(response.Positions, pnl)
+		response.GrossNotional += notional
+		response.UnrealizedPnL += unrealized
+		response.RealizedPnL += position.RealizedPnL
+		response.FeesPaid += position.FeesPaid
+	}
+
+	response.Count = len(response.Positions)
+	return response
+}
+
+// openPositions returns copies of the open positions, optionally of one symbol, sorted by symbol and side
+func (s *PositionService) openPositions(symbol string) []models.AccountPosition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var positions []models.AccountPosition
+	for _, position := range s.positions {
+		if position.IsOpen() && (symbol == "" || position.Symbol == symbol) {
+			positions = append(positions, *position)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Symbol != positions[j].Symbol {
+			return positions[i].Symbol < positions[j].Symbol
+		}
+		return positions[i].PositionSide < positions[j].PositionSide
+	})
+	return positions
+}
+
+// isOpen reports whether a tracked position holds a quantity
+func (s *PositionService) isOpen(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	position, exists := s.positions[key]
+	return exists && position.IsOpen()
+}
+
+// positionSide defaults an empty position side to one-way mode's BOTH
+func positionSide(side string) string {
+	if side == "" {
+		return models.PositionSideBoth
+	}
+	return side
+}
+
+// positionKey identifies a position by symbol and position side
+func positionKey(symbol, side string) string {
+	return symbol + "|" + positionSide(side)
+}
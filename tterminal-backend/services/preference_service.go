@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// PreferenceService stores user preferences validated against the preference schema. Only the
+// values a user set are stored, so a changed default reaches every user who never overrode it
+type PreferenceService struct {
+	preferencesRepo *repositories.UserPreferencesRepository
+}
+
+// NewPreferenceService creates a new preference service
+func NewPreferenceService(preferencesRepo *repositories.UserPreferencesRepository) *PreferenceService {
+	if preferencesRepo == nil {
+		log.Fatalf("[PreferenceService] CRITICAL: preferencesRepo cannot be nil")
+	}
+
+	log.Printf("[PreferenceService] Successfully initialized")
+	return &PreferenceService{preferencesRepo: preferencesRepo}
+}
+
+// GetPreferences returns every preference of a user, with defaults for those not set
+func (s *PreferenceService) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	stored, err := s.preferencesRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		stored = &models.UserPreferences{UserID: userID}
+	}
+
+	return &models.UserPreferences{
+		UserID:      userID,
+		Preferences: resolvePreferences(models.PreferenceSchema(), stored.Preferences),
+		UpdatedAt:   stored.UpdatedAt,
+	}, nil
+}
+
+// UpdatePreferences sets the given preferences, leaving the others as they are. Object
+// preferences are merged field by field; a null value resets a preference to its default
+func (s *PreferenceService) UpdatePreferences(ctx context.Context, userID string, updates map[string]interface{}) (*models.UserPreferences, error) {
+	stored, err := s.preferencesRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		stored = &models.UserPreferences{UserID: userID}
+	}
+
+	merged, err := mergePreferences(models.PreferenceSchema(), stored.Preferences, updates, "")
+	if err != nil {
+		return nil, err
+	}
+	stored.Preferences = merged
+	if err := s.preferencesRepo.Save(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &models.UserPreferences{
+		UserID:      userID,
+		Preferences: resolvePreferences(models.PreferenceSchema(), merged),
+		UpdatedAt:   stored.UpdatedAt,
+	}, nil
+}
+
+// ResetPreferences removes every preference a user set, restoring the defaults
+func (s *PreferenceService) ResetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	if err := s.preferencesRepo.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// mergePreferences validates updates against the schema fields and applies them to the stored
+// values, returning the values to store. Stored values outside the schema are dropped
+func mergePreferences(fields map[string]models.PreferenceField, stored, updates map[string]interface{}, path string) (map[string]interface{}, error) {
+	merged := make(map[string]interface{}, len(stored)+len(updates))
+	for key, value := range stored {
+		if _, known := fields[key]; known {
+			merged[key] = value
+		}
+	}
+
+	for key, value := range updates {
+		name := path + key
+		field, known := fields[key]
+		if !known {
+			return nil, fmt.Errorf("validation failed: unknown preference %q", name)
+		}
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+
+		if field.Type == models.PreferenceObject {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("validation failed: %s must be an object", name)
+			}
+			current, _ := merged[key].(map[string]interface{})
+			nested, err := mergePreferences(field.Fields, current, object, name+".")
+			if err != nil {
+				return nil, err
+			}
+			if len(nested) == 0 {
+				delete(merged, key)
+			} else {
+				merged[key] = nested
+			}
+			continue
+		}
+
+		if err := checkPreference(field, name, value); err != nil {
+			return nil, err
+		}
+		merged[key] = value
+	}
+
+	return merged, nil
+}
+
+// checkPreference validates a value against a non-object preference field
+func checkPreference(field models.PreferenceField, name string, value interface{}) error {
+	switch field.Type {
+	case models.PreferenceString:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("validation failed: %s must be a string", name)
+		}
+		if len(field.Enum) == 0 {
+			return nil
+		}
+		for _, allowed := range field.Enum {
+			if text == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("validation failed: %s must be one of %s", name, strings.Join(field.Enum, ", "))
+	case models.PreferenceBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("validation failed: %s must be a boolean", name)
+		}
+	case models.PreferenceNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("validation failed: %s must be a number", name)
+		}
+		if field.Minimum != nil && number < *field.Minimum {
+			return fmt.Errorf("validation failed: %s must be at least %g", name, *field.Minimum)
+		}
+		if field.Maximum != nil && number > *field.Maximum {
+			return fmt.Errorf("validation failed: %s must be at most %g", name, *field.Maximum)
+		}
+	default:
+		return fmt.Errorf("validation failed: %s has unsupported type %s", name, field.Type)
+	}
+	return nil
+}
+
+// resolvePreferences returns a value for every schema field: the stored value while it still
+// passes validation, the default otherwise (values left invalid by a schema change fall back)
+func resolvePreferences(fields map[string]models.PreferenceField, stored map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(fields))
+	for key, field := range fields {
+		value, exists := stored[key]
+		if field.Type == models.PreferenceObject {
+			nested, _ := value.(map[string]interface{})
+			resolved[key] = resolvePreferences(field.Fields, nested)
+			continue
+		}
+		if exists && checkPreference(field, key, value) == nil {
+			resolved[key] = value
+		} else {
+			resolved[key] = field.Default
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// purgeTokenTTL is how long a preview's confirmation token can start the purge
+	purgeTokenTTL = 5 * time.Minute
+	// purgeChunk is the time slice deleted per statement, so progress advances and locks stay short
+	purgeChunk = 24 * time.Hour
+	// purgeChunkTimeout bounds each delete statement
+	purgeChunkTimeout = 2 * time.Minute
+	// maxPurgeJobs caps how many jobs are kept for progress queries
+	maxPurgeJobs = 50
+)
+
+// Errors returned when a purge cannot start
+var (
+	ErrPurgeInvalid      = errors.New("invalid purge request")
+	ErrPurgeTokenInvalid = errors.New("confirmation token is missing, expired or does not match the request")
+	ErrPurgeRunning      = errors.New("a purge is already running for this symbol")
+	ErrPurgeJobNotFound  = errors.New("purge job not found")
+	ErrPurgeDraining     = errors.New("instance is draining; start the purge on another instance")
+)
+
+// PurgeService deletes stored data of a symbol in the background after a confirmed preview
+// A purge is two requests: a preview counting the rows to delete and returning a single-use
+// confirmation token, then the purge itself with that token and the same parameters
+type PurgeService struct {
+	repo               *repositories.PurgeRepository
+	candleService      *CandleService
+	aggregationService *AggregationService
+
+	mu     sync.Mutex
+	tokens map[string]pendingPurge
+	jobs   map[string]*models.PurgeJob
+	order  []string // Job IDs, oldest first
+
+	draining bool // No new jobs start once the instance is draining
+}
+
+// pendingPurge is a previewed purge awaiting confirmation
+type pendingPurge struct {
+	fingerprint string
+	rows        int64
+	expiresAt   time.Time
+}
+
+// purgeRange is the part of one dataset a job deletes
+type purgeRange struct {
+	dataset string
+	chunks  [][2]time.Time
+}
+
+// NewPurgeService creates a new purge service
+func NewPurgeService(repo *repositories.PurgeRepository, candleService *CandleService, aggregationService *AggregationService) *PurgeService {
+	return &PurgeService{
+		repo:               repo,
+		candleService:      candleService,
+		aggregationService: aggregationService,
+		tokens:             make(map[string]pendingPurge),
+		jobs:               make(map[string]*models.PurgeJob),
+	}
+}
+
+// Preview counts the rows a purge would delete and issues its confirmation token
+func (s *PurgeService) Preview(ctx context.Context, req models.PurgeRequest) (*models.PurgePreview, error) {
+	if err := normalizePurgeRequest(&req); err != nil {
+		return nil, err
+	}
+
+	startTime, endTime := purgeBounds(req)
+	preview := &models.PurgePreview{
+		Symbol:    req.Symbol,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Datasets:  req.Datasets,
+		Rows:      make(map[string]int64, len(req.Datasets)),
+	}
+	for _, dataset := range req.Datasets {
+		count, err := s.repo.Count(ctx, dataset, req.Symbol, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		preview.Rows[dataset] = count
+		preview.TotalRows += count
+	}
+
+	token, err := newConfirmationToken()
+	if err != nil {
+		return nil, err
+	}
+	preview.ConfirmationToken = token
+	preview.ExpiresAt = time.Now().UTC().Add(purgeTokenTTL)
+
+	s.mu.Lock()
+	s.pruneTokensLocked()
+	s.tokens[token] = pendingPurge{fingerprint: purgeFingerprint(req), rows: preview.TotalRows, expiresAt: preview.ExpiresAt}
+	s.mu.Unlock()
+
+	return preview, nil
+}
+
+// Start begins a previewed purge in the background; the token is consumed even if the purge fails
+func (s *PurgeService) Start(req models.PurgeRequest) (*models.PurgeJob, error) {
+	if err := normalizePurgeRequest(&req); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining {
+		return nil, ErrPurgeDraining
+	}
+	s.pruneTokensLocked()
+	pending, exists := s.tokens[req.ConfirmationToken]
+	if !exists || pending.fingerprint != purgeFingerprint(req) {
+		return nil, ErrPurgeTokenInvalid
+	}
+	for _, job := range s.jobs {
+		if job.Symbol == req.Symbol && job.Status == models.PurgeStatusRunning {
+			return nil, ErrPurgeRunning
+		}
+	}
+	delete(s.tokens, req.ConfirmationToken)
+
+	job := &models.PurgeJob{
+		ID:           uuid.New().String(),
+		Symbol:       req.Symbol,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Datasets:     req.Datasets,
+		Status:       models.PurgeStatusRunning,
+		ExpectedRows: pending.rows,
+		DeletedRows:  make(map[string]int64, len(req.Datasets)),
+		StartedAt:    time.Now().UTC(),
+	}
+	s.jobs[job.ID] = job
+	s.order = append(s.order, job.ID)
+	s.pruneJobsLocked()
+
+	log.Printf("[PurgeService] Purging %s %v (%d rows previewed) as job %s", job.Symbol, job.Datasets, job.ExpectedRows, job.ID)
+	go s.run(job.ID, req)
+
+	return copyPurgeJob(job), nil
+}
+
+// GetJob returns a purge job's progress
+func (s *PurgeService) GetJob(id string) (*models.PurgeJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, ErrPurgeJobNotFound
+	}
+	return copyPurgeJob(job), nil
+}
+
+// ListJobs returns the kept purge jobs, newest first
+func (s *PurgeService) ListJobs() []*models.PurgeJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]*models.PurgeJob, 0, len(s.order))
+	for i := len(s.order) - 1; i >= 0; i-- {
+		jobs = append(jobs, copyPurgeJob(s.jobs[s.order[i]]))
+	}
+	return jobs
+}
+
+// Drain stops new purges from starting; running purges continue to completion
+func (s *PurgeService) Drain() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.draining = true
+}
+
+// RunningJobs returns the number of purges in progress
+func (s *PurgeService) RunningJobs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	running := 0
+	for _, job := range s.jobs {
+		if job.Status == models.PurgeStatusRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// run deletes the selected data chunk by chunk, then invalidates dependent caches
+func (s *PurgeService) run(jobID string, req models.PurgeRequest) {
+	startTime, endTime := purgeBounds(req)
+
+	// Plan every chunk first so progress covers the whole job
+	var plan []purgeRange
+	totalChunks := 0
+	for _, dataset := range req.Datasets {
+		ctx, cancel := context.WithTimeout(context.Background(), purgeChunkTimeout)
+		oldest, newest, ok, err := s.repo.TimeBounds(ctx, dataset, req.Symbol, startTime, endTime)
+		cancel()
+		if err != nil {
+			s.fail(jobID, err)
+			return
+		}
+		if !ok {
+			continue
+		}
+
+		// Delete up to just past the newest row when the range is open-ended
+		last := newest.Add(time.Microsecond)
+		if !endTime.IsZero() && endTime.Before(last) {
+			last = endTime
+		}
+		r := purgeRange{dataset: dataset}
+		for from := oldest; from.Before(last); from = from.Add(purgeChunk) {
+			to := from.Add(purgeChunk)
+			if to.After(last) {
+				to = last
+			}
+			r.chunks = append(r.chunks, [2]time.Time{from, to})
+		}
+		plan = append(plan, r)
+		totalChunks += len(r.chunks)
+	}
+
+	done := 0
+	for _, r := range plan {
+		for _, chunk := range r.chunks {
+			ctx, cancel := context.WithTimeout(context.Background(), purgeChunkTimeout)
+			deleted, err := s.repo.Delete(ctx, r.dataset, req.Symbol, chunk[0], chunk[1])
+			cancel()
+			if err != nil {
+				s.fail(jobID, err)
+				return
+			}
+
+			done++
+			s.mu.Lock()
+			job := s.jobs[jobID]
+			job.CurrentDataset = r.dataset
+			job.DeletedRows[r.dataset] += deleted
+			job.Progress = float64(done) / float64(totalChunks) * 100
+			s.mu.Unlock()
+		}
+	}
+
+	// Cached responses may still hold the purged data
+	s.candleService.InvalidateSymbol(req.Symbol)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	err := s.aggregationService.InvalidateSymbol(ctx, req.Symbol)
+	cancel()
+	if err != nil {
+		s.fail(jobID, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	job := s.jobs[jobID]
+	job.Status = models.PurgeStatusCompleted
+	job.Progress = 100
+	job.CurrentDataset = ""
+	job.FinishedAt = &now
+	deleted := copyPurgeJob(job).DeletedRows
+	s.mu.Unlock()
+
+	log.Printf("[PurgeService] Purge job %s completed: %v", jobID, deleted)
+}
+
+// fail marks a job failed; rows already deleted stay deleted
+func (s *PurgeService) fail(jobID string, err error) {
+	now := time.Now().UTC()
+	s.mu.Lock()
+	job := s.jobs[jobID]
+	job.Status = models.PurgeStatusFailed
+	job.Error = err.Error()
+	job.FinishedAt = &now
+	s.mu.Unlock()
+
+	log.Printf("[PurgeService] ERROR: Purge job %s failed: %v", jobID, err)
+}
+
+// pruneTokensLocked drops expired confirmation tokens; the caller holds s.mu
+func (s *PurgeService) pruneTokensLocked() {
+	now := time.Now()
+	for token, pending := range s.tokens {
+		if now.After(pending.expiresAt) {
+			delete(s.tokens, token)
+		}
+	}
+}
+
+// pruneJobsLocked drops the oldest finished jobs beyond maxPurgeJobs; the caller holds s.mu
+func (s *PurgeService) pruneJobsLocked() {
+	for i := 0; len(s.order) > maxPurgeJobs && i < len(s.order); {
+		if s.jobs[s.order[i]].Status == models.PurgeStatusRunning {
+			i++
+			continue
+		}
+		delete(s.jobs, s.order[i])
+		s.order = append(s.order[:i], s.order[i+1:]...)
+	}
+}
+
+// normalizePurgeRequest validates a purge request, upper-casing the symbol and defaulting datasets
+func normalizePurgeRequest(req *models.PurgeRequest) error {
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if req.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", ErrPurgeInvalid)
+	}
+	if req.StartTime != nil && req.EndTime != nil && !req.StartTime.Before(*req.EndTime) {
+		return fmt.Errorf("%w: start_time must be before end_time", ErrPurgeInvalid)
+	}
+
+	if len(req.Datasets) == 0 {
+		req.Datasets = append([]string(nil), models.PurgeDatasets...)
+		return nil
+	}
+	selected := make(map[string]bool, len(req.Datasets))
+	for _, dataset := range req.Datasets {
+		known := false
+		for _, candidate := range models.PurgeDatasets {
+			if dataset == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown dataset %q, use %s", ErrPurgeInvalid, dataset, strings.Join(models.PurgeDatasets, ", "))
+		}
+		selected[dataset] = true
+	}
+
+	// Keep purge order stable regardless of request order
+	req.Datasets = req.Datasets[:0]
+	for _, dataset := range models.PurgeDatasets {
+		if selected[dataset] {
+			req.Datasets = append(req.Datasets, dataset)
+		}
+	}
+	return nil
+}
+
+// purgeBounds returns the request's time range, with zero times for open sides
+func purgeBounds(req models.PurgeRequest) (time.Time, time.Time) {
+	var startTime, endTime time.Time
+	if req.StartTime != nil {
+		startTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		endTime = *req.EndTime
+	}
+	return startTime, endTime
+}
+
+// purgeFingerprint identifies a normalized request's parameters so a token only confirms what was previewed
+func purgeFingerprint(req models.PurgeRequest) string {
+	startTime, endTime := purgeBounds(req)
+	datasets := append([]string(nil), req.Datasets...)
+	sort.Strings(datasets)
+	return strings.Join([]string{req.Symbol, startTime.Format(time.RFC3339Nano), endTime.Format(time.RFC3339Nano), strings.Join(datasets, ",")}, "|")
+}
+
+// newConfirmationToken returns a random confirmation token
+func newConfirmationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// copyPurgeJob returns a snapshot of a job safe to hand out while it runs
+func copyPurgeJob(job *models.PurgeJob) *models.PurgeJob {
+	snapshot := *job
+	snapshot.DeletedRows = make(map[string]int64, len(job.DeletedRows))
+	for dataset, rows := range job.DeletedRows {
+		snapshot.DeletedRows[dataset] = rows
+	}
+	return &snapshot
+}
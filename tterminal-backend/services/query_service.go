@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+)
+
+// Resource limits of a query; the source range is also capped at models.MaxRangeCandles candles
+const (
+	queryTimeout       = 5 * time.Second
+	queryMaxTransforms = 10
+	queryMaxPeriod     = 1000 // ema and sma period, delta and pct_change lag
+)
+
+// ErrQueryTimeout is returned when a query exceeds its time limit
+var ErrQueryTimeout = errors.New("query exceeded its time limit")
+
+// queryFields extract a field from a stored candle
+var queryFields = map[string]func(c models.Candle) float64{
+	models.QueryFieldOpen:   func(c models.Candle) float64 { return models.ParseFloat(c.Open) },
+	models.QueryFieldHigh:   func(c models.Candle) float64 { return models.ParseFloat(c.High) },
+	models.QueryFieldLow:    func(c models.Candle) float64 { return models.ParseFloat(c.Low) },
+	models.QueryFieldClose:  func(c models.Candle) float64 { return models.ParseFloat(c.Close) },
+	models.QueryFieldVolume: func(c models.Candle) float64 { return models.ParseFloat(c.Volume) },
+	models.QueryFieldBuyVolume: func(c models.Candle) float64 {
+		return models.ParseFloat(c.TakerBuyBaseAssetVolume)
+	},
+	models.QueryFieldSellVolume: func(c models.Candle) float64 {
+		return models.ParseFloat(c.Volume) - models.ParseFloat(c.TakerBuyBaseAssetVolume)
+	},
+	models.QueryFieldDelta: func(c models.Candle) float64 {
+		return 2*models.ParseFloat(c.TakerBuyBaseAssetVolume) - models.ParseFloat(c.Volume)
+	},
+	models.QueryFieldTrades: func(c models.Candle) float64 { return float64(c.TradeCount) },
+}
+
+// QueryService evaluates declarative time-series queries against stored data, so power users can
+// combine series and transforms without an endpoint per combination
+type QueryService struct {
+	candleService *CandleService
+}
+
+// NewQueryService creates a new query service
+func NewQueryService(candleService *CandleService) *QueryService {
+	return &QueryService{
+		candleService: candleService,
+	}
+}
+
+// querySeries is a series being transformed
+type querySeries struct {
+	times    []int64
+	values   []float64
+	interval string
+}
+
+// Evaluate validates a query, reads its source series and applies its transforms in order
+func (s *QueryService) Evaluate(ctx context.Context, query *models.SeriesQuery) (*models.QueryResult, error) {
+	if err := normalizeQuery(query); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	source := query.Source
+	candles, err := s.candleService.GetCandleRangeByPriceType(ctx, source.Symbol, source.Interval, source.PriceType, query.Range.Start.Time, query.Range.End.Time)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrQueryTimeout
+		}
+		if strings.HasPrefix(err.Error(), "time range too large") {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		return nil, err
+	}
+
+	field := queryFields[source.Field]
+	series := &querySeries{
+		times:    make([]int64, len(candles)),
+		values:   make([]float64, len(candles)),
+		interval: source.Interval,
+	}
+	for i, candle := range candles {
+		series.times[i] = candle.OpenTime.UnixMilli()
+		series.values[i] = field(candle)
+	}
+
+	for _, transform := range query.Transforms {
+		if ctx.Err() != nil {
+			return nil, ErrQueryTimeout
+		}
+		series = applyTransform(series, transform)
+	}
+
+	return &models.QueryResult{
+		Symbol:       source.Symbol,
+		Field:        source.Field,
+		Interval:     series.interval,
+		Times:        series.times,
+		Values:       series.values,
+		Count:        len(series.values),
+		SourcePoints: len(candles),
+		Timestamp:    time.Now().UnixMilli(),
+	}, nil
+}
+
+// normalizeQuery validates a query and fills its defaults
+func normalizeQuery(query *models.SeriesQuery) error {
+	source := &query.Source
+	if source.Type == "" {
+		source.Type = models.QuerySourceCandles
+	}
+	if source.Type != models.QuerySourceCandles {
+		return fmt.Errorf("validation failed: source type must be %s", models.QuerySourceCandles)
+	}
+	source.Symbol = strings.ToUpper(strings.TrimSpace(source.Symbol))
+	if source.Symbol == "" {
+		return fmt.Errorf("validation failed: source symbol is required")
+	}
+	if source.Exchange != "" {
+		source.Exchange = strings.ToLower(source.Exchange)
+		if !models.IsValidExchange(source.Exchange) {
+			return fmt.Errorf("validation failed: source exchange must be one of %s", strings.Join(models.Exchanges, ", "))
+		}
+		source.Symbol = models.QualifySymbol(source.Exchange, source.Symbol)
+	}
+	if !models.IsValidInterval(source.Interval) {
+		return fmt.Errorf("validation failed: source interval must be one of %s", strings.Join(models.SupportedIntervalNames(), ", "))
+	}
+	if source.Field == "" {
+		source.Field = models.QueryFieldClose
+	}
+	if _, ok := queryFields[source.Field]; !ok {
+		return fmt.Errorf("validation failed: unknown source field %q", source.Field)
+	}
+	if source.PriceType != "" && !models.IsValidPriceType(source.PriceType) {
+		return fmt.Errorf("validation failed: invalid price type %q", source.PriceType)
+	}
+
+	if query.Range.Start.IsZero() {
+		return fmt.Errorf("validation failed: range start is required")
+	}
+	if query.Range.End.IsZero() {
+		query.Range.End.Time = time.Now().UTC()
+	}
+	if !query.Range.Start.Before(query.Range.End.Time) {
+		return fmt.Errorf("validation failed: range start must be before end")
+	}
+
+	if len(query.Transforms) > queryMaxTransforms {
+		return fmt.Errorf("validation failed: at most %d transforms", queryMaxTransforms)
+	}
+	interval := source.Interval
+	for i := range query.Transforms {
+		transform := &query.Transforms[i]
+		switch transform.Op {
+		case models.QueryOpResample:
+			target, ok := models.IntervalDuration(transform.Interval)
+			current, _ := models.IntervalDuration(interval)
+			if !ok || target < current {
+				return fmt.Errorf("validation failed: transform %d: resample interval must be a supported interval no shorter than %s", i, interval)
+			}
+			switch transform.Agg {
+			case "":
+				transform.Agg = models.QueryAggLast
+			case models.QueryAggLast, models.QueryAggFirst, models.QueryAggSum, models.QueryAggMean, models.QueryAggMax, models.QueryAggMin:
+			default:
+				return fmt.Errorf("validation failed: transform %d: unknown resample aggregation %q", i, transform.Agg)
+			}
+			interval = transform.Interval
+		case models.QueryOpEMA, models.QueryOpSMA:
+			if transform.Period < 1 || transform.Period > queryMaxPeriod {
+				return fmt.Errorf("validation failed: transform %d: period must be between 1 and %d", i, queryMaxPeriod)
+			}
+		case models.QueryOpDelta, models.QueryOpPctChange:
+			if transform.Lag == 0 {
+				transform.Lag = 1
+			}
+			if transform.Lag < 1 || transform.Lag > queryMaxPeriod {
+				return fmt.Errorf("validation failed: transform %d: lag must be between 1 and %d", i, queryMaxPeriod)
+			}
+		case models.QueryOpCumSum:
+		default:
+			return fmt.Errorf("validation failed: transform %d: unknown op %q", i, transform.Op)
+		}
+	}
+	return nil
+}
+
+// applyTransform applies one validated transform; warm-up points without a value are dropped
+func applyTransform(series *querySeries, transform models.QueryTransform) *querySeries {
+	out := &querySeries{interval: series.interval}
+	emit := func(t int64, v float64) {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			out.times = append(out.times, t)
+			out.values = append(out.values, v)
+		}
+	}
+
+	switch transform.Op {
+	case models.QueryOpResample:
+		out.interval = transform.Interval
+		duration, _ := models.IntervalDuration(transform.Interval)
+		var bucket []float64
+		var bucketTime int64
+		flush := func() {
+			if len(bucket) > 0 {
+				emit(bucketTime, aggregateBucket(bucket, transform.Agg))
+			}
+		}
+		for i, t := range series.times {
+			start := resampleBucket(time.UnixMilli(t).UTC(), transform.Interval, duration).UnixMilli()
+			if len(bucket) == 0 || start != bucketTime {
+				flush()
+				bucket, bucketTime = bucket[:0], start
+			}
+			bucket = append(bucket, series.values[i])
+		}
+		flush()
+
+	case models.QueryOpEMA:
+		for i, v := range emaSeries(series.values, transform.Period) {
+			emit(series.times[i], v)
+		}
+
+	case models.QueryOpSMA:
+		for i, v := range smaSeries(series.values, transform.Period) {
+			emit(series.times[i], v)
+		}
+
+	case models.QueryOpDelta:
+		for i := transform.Lag; i < len(series.values); i++ {
+			emit(series.times[i], series.values[i]-series.values[i-transform.Lag])
+		}
+
+	case models.QueryOpPctChange:
+		for i := transform.Lag; i < len(series.values); i++ {
+			if previous := series.values[i-transform.Lag]; previous != 0 {
+				emit(series.times[i], (series.values[i]-previous)/previous*100)
+			}
+		}
+
+	case models.QueryOpCumSum:
+		var total float64
+		for i, v := range series.values {
+			total += v
+			emit(series.times[i], total)
+		}
+	}
+
+	if out.times == nil {
+		out.times, out.values = []int64{}, []float64{}
+	}
+	return out
+}
+
+// resampleBucket returns the open time of the interval bucket containing t; months are calendar months
+func resampleBucket(t time.Time, interval string, duration time.Duration) time.Time {
+	if interval == "1M" {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(duration)
+}
+
+// aggregateBucket combines the values of one resample bucket
+func aggregateBucket(values []float64, agg string) float64 {
+	switch agg {
+	case models.QueryAggFirst:
+		return values[0]
+	case models.QueryAggSum, models.QueryAggMean:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		if agg == models.QueryAggMean {
+			return sum / float64(len(values))
+		}
+		return sum
+	case models.QueryAggMax:
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+		return result
+	case models.QueryAggMin:
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+		return result
+	default:
+		return values[len(values)-1]
+	}
+}
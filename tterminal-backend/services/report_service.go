@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// reportRunOffset is the time after UTC midnight when the previous day's reports are generated
+	reportRunOffset = 5 * time.Minute
+	// reportNotableLiquidations is the number of largest liquidations listed per symbol
+	reportNotableLiquidations = 5
+	// reportDateLayout is the layout of report dates
+	reportDateLayout = "2006-01-02"
+)
+
+// ReportService generates, stores and emails daily market recaps for user watchlists
+type ReportService struct {
+	reportRepo       *repositories.ReportRepository
+	subscriptionRepo *repositories.UserSubscriptionRepository
+	binanceClient    *binance.Client
+	stream           *websocket.BinanceStream
+	cfg              *config.Config
+
+	isRunning bool
+	stopChan  chan struct{}
+	mu        sync.Mutex
+}
+
+// NewReportService creates a new report service
+func NewReportService(reportRepo *repositories.ReportRepository, subscriptionRepo *repositories.UserSubscriptionRepository,
+	binanceClient *binance.Client, stream *websocket.BinanceStream, cfg *config.Config) *ReportService {
+	if reportRepo == nil || subscriptionRepo == nil {
+		log.Fatalf("[ReportService] CRITICAL: repositories cannot be nil")
+	}
+	if binanceClient == nil {
+		log.Fatalf("[ReportService] CRITICAL: binanceClient cannot be nil")
+	}
+	if stream == nil {
+		log.Printf("[ReportService] WARNING: stream is nil - reports will not include liquidations")
+	}
+	if cfg.SMTPHost == "" {
+		log.Printf("[ReportService] SMTP_HOST not set - report emails are disabled")
+	}
+
+	return &ReportService{
+		reportRepo:       reportRepo,
+		subscriptionRepo: subscriptionRepo,
+		binanceClient:    binanceClient,
+		stream:           stream,
+		cfg:              cfg,
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// Start begins generating reports daily shortly after UTC midnight
+func (s *ReportService) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return fmt.Errorf("report service is already running")
+	}
+	s.isRunning = true
+
+	go s.scheduleLoop()
+	return nil
+}
+
+// Stop stops the daily report schedule
+func (s *ReportService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+}
+
+// GetSettings returns a user's report settings, with defaults when none are stored
+func (s *ReportService) GetSettings(ctx context.Context, userID string) (*models.ReportSettings, error) {
+	settings, err := s.reportRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.ReportSettings{UserID: userID, Watchlist: []string{}}
+	}
+	return settings, nil
+}
+
+// UpdateSettings updates a user's watchlist and email preferences
+func (s *ReportService) UpdateSettings(ctx context.Context, userID string, req *models.UpdateReportSettingsRequest) (*models.ReportSettings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Watchlist != nil {
+		settings.Watchlist = normalizeSymbols(req.Watchlist)
+	}
+	if req.Email != nil {
+		settings.Email = strings.TrimSpace(*req.Email)
+	}
+	if req.EmailEnabled != nil {
+		settings.EmailEnabled = *req.EmailEnabled
+	}
+	if settings.EmailEnabled && !strings.Contains(settings.Email, "@") {
+		return nil, fmt.Errorf("validation failed: a valid email is required to enable report emails")
+	}
+
+	if err := s.reportRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetHistory returns a user's report history
+func (s *ReportService) GetHistory(ctx context.Context, userID string, limit int) ([]models.ReportSummary, error) {
+	return s.reportRepo.GetHistory(ctx, userID, limit)
+}
+
+// GetReport returns a stored report
+func (s *ReportService) GetReport(ctx context.Context, userID string, id int64) (*models.Report, error) {
+	report, err := s.reportRepo.GetReport(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("report not found")
+	}
+	return report, nil
+}
+
+// GetLatestReport returns a user's most recent report
+func (s *ReportService) GetLatestReport(ctx context.Context, userID string) (*models.Report, error) {
+	report, err := s.reportRepo.GetLatestReport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("report not found")
+	}
+	return report, nil
+}
+
+// GenerateReport builds, stores and optionally emails a user's recap for a UTC day
+func (s *ReportService) GenerateReport(ctx context.Context, userID string, day time.Time) (*models.Report, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist := settings.Watchlist
+	if len(watchlist) == 0 {
+		subs, err := s.subscriptionRepo.Get(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if subs != nil {
+			watchlist = normalizeSymbols(subs.Symbols)
+		}
+	}
+	if len(watchlist) == 0 {
+		return nil, fmt.Errorf("validation failed: watchlist is empty")
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	report := &models.Report{
+		UserID:     userID,
+		ReportDate: start.Format(reportDateLayout),
+		Recap: models.DailyRecap{
+			Date:    start.Format(reportDateLayout),
+			Symbols: make([]models.SymbolRecap, 0, len(watchlist)),
+		},
+	}
+
+	for _, symbol := range watchlist {
+		report.Recap.Symbols = append(report.Recap.Symbols, s.buildSymbolRecap(ctx, symbol, start))
+	}
+	report.Recap.GeneratedAt = time.Now().UnixMilli()
+
+	if err := s.reportRepo.SaveReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if settings.EmailEnabled && settings.Email != "" && s.cfg.SMTPHost != "" {
+		if err := s.sendReportEmail(settings.Email, report); err != nil {
+			log.Printf("[ReportService] ERROR emailing report %d to user %s: %v", report.ID, userID, err)
+		} else {
+			emailedAt := time.Now()
+			if err := s.reportRepo.MarkEmailed(ctx, report.ID, emailedAt); err == nil {
+				report.EmailedAt = &emailedAt
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// buildSymbolRecap collects range, volume, funding and liquidation data for a symbol's UTC day
+func (s *ReportService) buildSymbolRecap(ctx context.Context, symbol string, start time.Time) models.SymbolRecap {
+	end := start.Add(24 * time.Hour)
+	recap := models.SymbolRecap{
+		Symbol:              symbol,
+		NotableLiquidations: []models.NotableLiquidation{},
+		Errors:              make(map[string]string),
+	}
+
+	candles, err := s.binanceClient.GetKlinesWithTimeRange(ctx, symbol, "1d", start, end.Add(-time.Millisecond))
+	if err != nil {
+		recap.Errors["range"] = err.Error()
+	} else if len(candles) > 0 {
+		candle := candles[0]
+		recap.Open = models.ParseFloat(candle.Open)
+		recap.High = models.ParseFloat(candle.High)
+		recap.Low = models.ParseFloat(candle.Low)
+		recap.Close = models.ParseFloat(candle.Close)
+		recap.Volume = models.ParseFloat(candle.Volume)
+		recap.QuoteVolume = models.ParseFloat(candle.QuoteAssetVolume)
+		recap.TradeCount = candle.TradeCount
+		if recap.Open > 0 {
+			recap.ChangePct = (recap.Close - recap.Open) / recap.Open * 100
+			recap.RangePct = (recap.High - recap.Low) / recap.Open * 100
+		}
+	}
+
+	rates, err := s.binanceClient.GetFundingRateHistory(ctx, symbol, start, end.Add(-time.Millisecond), 100)
+	if err != nil {
+		recap.Errors["funding"] = err.Error()
+	} else if len(rates) > 0 {
+		for _, rate := range rates {
+			recap.FundingRateSum += models.ParseFloat(rate.FundingRate)
+		}
+		recap.FundingSettlements = len(rates)
+		recap.FundingRateAvg = recap.FundingRateSum / float64(len(rates))
+	}
+
+	s.fillLiquidations(&recap, start, end)
+
+	if len(recap.Errors) == 0 {
+		recap.Errors = nil
+	}
+	return recap
+}
+
+// fillLiquidations summarizes liquidations held in the stream's buffer for the day
+func (s *ReportService) fillLiquidations(recap *models.SymbolRecap, start, end time.Time) {
+	recap.Liquidations.WindowHours = 24
+	if s.stream == nil {
+		return
+	}
+
+	liquidations := s.stream.GetRecentLiquidations(recap.Symbol, 0)
+	recap.Liquidations.CoveredFrom = liquidationCoverage(liquidations, start)
+	for _, liq := range liquidations {
+		if liq.TradeTime.Before(start) || !liq.TradeTime.Before(end) {
+			continue
+		}
+		notional := liq.Price * liq.Quantity
+
+		// A SELL liquidation order closes a long position
+		if liq.Side == "SELL" {
+			recap.Liquidations.LongCount++
+			recap.Liquidations.LongNotional += notional
+		} else {
+			recap.Liquidations.ShortCount++
+			recap.Liquidations.ShortNotional += notional
+		}
+
+		recap.NotableLiquidations = append(recap.NotableLiquidations, models.NotableLiquidation{
+			Time:     liq.TradeTime.UnixMilli(),
+			Side:     liq.Side,
+			Price:    liq.Price,
+			Quantity: liq.Quantity,
+			Notional: notional,
+		})
+	}
+
+	sort.Slice(recap.NotableLiquidations, func(i, j int) bool {
+		return recap.NotableLiquidations[i].Notional > recap.NotableLiquidations[j].Notional
+	})
+	if len(recap.NotableLiquidations) > reportNotableLiquidations {
+		recap.NotableLiquidations = recap.NotableLiquidations[:reportNotableLiquidations]
+	}
+}
+
+// scheduleLoop generates the previous day's reports for all users after each UTC midnight
+func (s *ReportService) scheduleLoop() {
+	for {
+		now := time.Now().UTC()
+		next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(reportRunOffset)
+		if !next.After(now) {
+			next = next.Add(24 * time.Hour)
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stopChan:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.generateAll(next.Add(-24 * time.Hour))
+		}
+	}
+}
+
+// generateAll generates reports for every user with a watchlist
+func (s *ReportService) generateAll(day time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	userIDs, err := s.reportRepo.GetReportUserIDs(ctx)
+	if err != nil {
+		log.Printf("[ReportService] ERROR loading report users: %v", err)
+		return
+	}
+
+	generated := 0
+	for _, userID := range userIDs {
+		if _, err := s.GenerateReport(ctx, userID, day); err != nil {
+			log.Printf("[ReportService] ERROR generating report for user %s: %v", userID, err)
+			continue
+		}
+		generated++
+	}
+
+	log.Printf("[ReportService] Generated %d/%d daily reports for %s", generated, len(userIDs), day.Format(reportDateLayout))
+}
+
+// sendReportEmail emails a plain-text version of a report
+func (s *ReportService) sendReportEmail(to string, report *models.Report) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", s.cfg.ReportEmailFrom)
+	fmt.Fprintf(&body, "To: %s\r\n", to)
+	fmt.Fprintf(&body, "Subject: TTerminal daily recap %s\r\n", report.ReportDate)
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+
+	for _, recap := range report.Recap.Symbols {
+		fmt.Fprintf(&body, "%s  close %.8g (%+.2f%%)  range %.8g-%.8g (%.2f%%)  volume %.0f\r\n",
+			recap.Symbol, recap.Close, recap.ChangePct, recap.Low, recap.High, recap.RangePct, recap.QuoteVolume)
+		fmt.Fprintf(&body, "    funding avg %.4f%% over %d settlements  liquidations long %.0f / short %.0f\r\n",
+			recap.FundingRateAvg*100, recap.FundingSettlements,
+			recap.Liquidations.LongNotional, recap.Liquidations.ShortNotional)
+	}
+
+	var auth smtp.Auth
+	if s.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", s.cfg.SMTPUsername, s.cfg.SMTPPassword, s.cfg.SMTPHost)
+	}
+
+	addr := fmt.Sprintf("%s:%d", s.cfg.SMTPHost, s.cfg.SMTPPort)
+	return smtp.SendMail(addr, auth, s.cfg.ReportEmailFrom, []string{to}, []byte(body.String()))
+}
+
+// normalizeSymbols upper-cases, trims and de-duplicates symbols
+func normalizeSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		result = append(result, symbol)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package services
+
+// rollingWindow keeps the sum, maximum and minimum of the last size values in O(1) amortized per value
+// Maximum and minimum use monotonic queues of value indexes so evicted values never need a rescan
+type rollingWindow struct {
+	size   int
+	values []float64 // Ring buffer of the window
+	count  int       // Values added so far
+	sum    float64
+	maxIdx []int // Indexes of decreasing values; the front is the window maximum
+	minIdx []int // Indexes of increasing values; the front is the window minimum
+}
+
+// newRollingWindow creates a rolling window over the last size values
+func newRollingWindow(size int) *rollingWindow {
+	if size < 1 {
+		size = 1
+	}
+	return &rollingWindow{size: size, values: make([]float64, size)}
+}
+
+// add pushes a value, evicting the oldest once the window is full
+func (w *rollingWindow) add(value float64) {
+	slot := w.count % w.size
+	if w.count >= w.size {
+		w.sum -= w.values[slot]
+	}
+	w.values[slot] = value
+	w.sum += value
+
+	oldest := w.count - w.size + 1
+	for len(w.maxIdx) > 0 && w.value(w.maxIdx[len(w.maxIdx)-1]) <= value {
+		w.maxIdx = w.maxIdx[:len(w.maxIdx)-1]
+	}
+	w.maxIdx = append(w.maxIdx, w.count)
+	if w.maxIdx[0] < oldest {
+		w.maxIdx = w.maxIdx[1:]
+	}
+	for len(w.minIdx) > 0 && w.value(w.minIdx[len(w.minIdx)-1]) >= value {
+		w.minIdx = w.minIdx[:len(w.minIdx)-1]
+	}
+	w.minIdx = append(w.minIdx, w.count)
+	if w.minIdx[0] < oldest {
+		w.minIdx = w.minIdx[1:]
+	}
+
+	w.count++
+}
+
+// value returns the value added at index i, which must still be inside the window
+func (w *rollingWindow) value(i int) float64 {
+	return w.values[i%w.size]
+}
+
+// full reports whether the window holds size values
+func (w *rollingWindow) full() bool {
+	return w.count >= w.size
+}
+
+// len returns the number of values in the window
+func (w *rollingWindow) len() int {
+	if w.count < w.size {
+		return w.count
+	}
+	return w.size
+}
+
+// total returns the sum of the window
+func (w *rollingWindow) total() float64 {
+	return w.sum
+}
+
+// avg returns the mean of the window (0 when empty)
+func (w *rollingWindow) avg() float64 {
+	if n := w.len(); n > 0 {
+		return w.sum / float64(n)
+	}
+	return 0
+}
+
+// max returns the largest value of the window (0 when empty)
+func (w *rollingWindow) max() float64 {
+	if len(w.maxIdx) == 0 {
+		return 0
+	}
+	return w.value(w.maxIdx[0])
+}
+
+// min returns the smallest value of the window (0 when empty)
+func (w *rollingWindow) min() float64 {
+	if len(w.minIdx) == 0 {
+		return 0
+	}
+	return w.value(w.minIdx[0])
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+)
+
+const (
+	// screenerCacheTTL controls how long computed metrics are reused; sorting and filtering
+	// run on the cached rows per request
+	screenerCacheTTL = 30 * time.Second
+
+	// screenerSpikeHours is how many closed hours the last hour's volume is compared against
+	screenerSpikeHours = 24
+
+	// screenerConcurrency bounds the symbols screened at once (derivatives snapshots call Binance)
+	screenerConcurrency = 5
+
+	// screenerComputeTimeout bounds one pass over every symbol
+	screenerComputeTimeout = 20 * time.Second
+
+	// Default and maximum number of rows returned
+	defaultScreenerLimit = 50
+	maxScreenerLimit     = 500
+)
+
+// ScreenerService ranks the tracked symbols by price change, volume, volume spike, open
+// interest change and funding, combining the live stream, stored candles and derivatives snapshots
+type ScreenerService struct {
+	stream             *websocket.BinanceStream
+	candleService      *CandleService
+	derivativesService *DerivativesService
+
+	// refreshMu serializes recomputation so concurrent requests share one pass
+	refreshMu  sync.Mutex
+	mu         sync.RWMutex
+	rows       []models.ScreenerRow
+	errors     map[string]string
+	computedAt time.Time
+}
+
+// NewScreenerService creates a new market screener service
+func NewScreenerService(stream *websocket.BinanceStream, candleService *CandleService, derivativesService *DerivativesService) *ScreenerService {
+	if candleService == nil {
+		log.Fatalf("[ScreenerService] CRITICAL: candleService cannot be nil")
+	}
+	if derivativesService == nil {
+		log.Fatalf("[ScreenerService] CRITICAL: derivativesService cannot be nil")
+	}
+	if stream == nil {
+		log.Printf("[ScreenerService] WARNING: stream is nil - the screener has no symbols")
+	}
+
+	return &ScreenerService{
+		stream:             stream,
+		candleService:      candleService,
+		derivativesService: derivativesService,
+	}
+}
+
+// Screen returns the tracked symbols passing the filters, ranked by the sort metric
+// Rows missing the sort metric are listed last
+func (s *ScreenerService) Screen(ctx context.Context, params models.ScreenerParams) (*models.ScreenerResponse, error) {
+	if params.Sort == "" {
+		params.Sort = models.ScreenerMetricChange
+	}
+	if !models.IsValidScreenerMetric(params.Sort) {
+		return nil, fmt.Errorf("validation failed: sort must be one of %s", strings.Join(models.ScreenerMetrics, ", "))
+	}
+	for _, bounds := range []map[string]float64{params.Min, params.Max} {
+		for metric := range bounds {
+			if !models.IsValidScreenerMetric(metric) {
+				return nil, fmt.Errorf("validation failed: unknown filter metric %q", metric)
+			}
+		}
+	}
+	if params.Limit <= 0 || params.Limit > maxScreenerLimit {
+		params.Limit = defaultScreenerLimit
+	}
+
+	rows, errs, computedAt, err := s.metrics()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]models.ScreenerRow, 0, len(rows))
+	for i := range rows {
+		if screenerPasses(&rows[i], params) {
+			matched = append(matched, rows[i])
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, okA := matched[i].Metric(params.Sort)
+		b, okB := matched[j].Metric(params.Sort)
+		if okA != okB {
+			return okA
+		}
+		if params.Ascending {
+			return a < b
+		}
+		return a > b
+	})
+
+	response := &models.ScreenerResponse{
+		Sort:       params.Sort,
+		Order:      "desc",
+		Matched:    len(matched),
+		Tracked:    len(rows),
+		ComputedAt: computedAt.UnixMilli(),
+		Errors:     errs,
+	}
+	if params.Ascending {
+		response.Order = "asc"
+	}
+	if len(matched) > params.Limit {
+		matched = matched[:params.Limit]
+	}
+	response.Symbols = matched
+	response.Count = len(matched)
+	return response, nil
+}
+
+// screenerPasses reports whether a row is within every filter bound
+func screenerPasses(row *models.ScreenerRow, params models.ScreenerParams) bool {
+	for metric, min := range params.Min {
+		if value, ok := row.Metric(metric); !ok || value < min {
+			return false
+		}
+	}
+	for metric, max := range params.Max {
+		if value, ok := row.Metric(metric); !ok || value > max {
+			return false
+		}
+	}
+	return true
+}
+
+// metrics returns the cached rows, recomputing them once they expire
+func (s *ScreenerService) metrics() ([]models.ScreenerRow, map[string]string, time.Time, error) {
+	if rows, errs, computedAt, ok := s.cached(); ok {
+		return rows, errs, computedAt, nil
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// Another request may have refreshed while this one waited
+	if rows, errs, computedAt, ok := s.cached(); ok {
+		return rows, errs, computedAt, nil
+	}
+
+	// The pass is shared by every waiting request, so it does not end with this one
+	computeCtx, cancel := context.WithTimeout(context.Background(), screenerComputeTimeout)
+	defer cancel()
+
+	rows, errs, err := s.compute(computeCtx)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	computedAt := time.Now()
+
+	s.mu.Lock()
+	s.rows, s.errors, s.computedAt = rows, errs, computedAt
+	s.mu.Unlock()
+	return rows, errs, computedAt, nil
+}
+
+// cached returns the rows computed within the cache TTL
+func (s *ScreenerService) cached() ([]models.ScreenerRow, map[string]string, time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.rows == nil || time.Since(s.computedAt) > screenerCacheTTL {
+		return nil, nil, time.Time{}, false
+	}
+	return s.rows, s.errors, s.computedAt, true
+}
+
+// compute screens every streamed symbol
+func (s *ScreenerService) compute(ctx context.Context) ([]models.ScreenerRow, map[string]string, error) {
+	if s.stream == nil {
+		return nil, nil, fmt.Errorf("stream is not available")
+	}
+
+	symbols := s.stream.GetConnectedSymbols()
+	rows := make([]models.ScreenerRow, len(symbols))
+	failures := make([][]string, len(symbols))
+
+	semaphore := make(chan struct{}, screenerConcurrency)
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(idx int, sym string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			rows[idx], failures[idx] = s.screenSymbol(ctx, sym)
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	var errs map[string]string
+	for i, failed := range failures {
+		if len(failed) == 0 {
+			continue
+		}
+		if errs == nil {
+			errs = make(map[string]string)
+		}
+		errs[symbols[i]] = strings.Join(failed, "; ")
+	}
+	return rows, errs, nil
+}
+
+// screenSymbol gathers the metrics of one symbol, reporting the sources that failed
+func (s *ScreenerService) screenSymbol(ctx context.Context, symbol string) (models.ScreenerRow, []string) {
+	row := models.ScreenerRow{Symbol: symbol}
+	var failed []string
+
+	// 24h change and quote volume from the live futures ticker
+	if price, ok := s.stream.GetLastPrice(symbol); ok {
+		row.LastPrice = price
+	}
+	if ticker, ok := s.stream.GetFuturesTicker(symbol); ok {
+		change := models.ParseFloat(ticker.PriceChangePercent)
+		volume := models.ParseFloat(ticker.TotalTradedValue)
+		row.ChangePct, row.QuoteVolume = &change, &volume
+		if row.LastPrice == 0 {
+			row.LastPrice = models.ParseFloat(ticker.LastPrice)
+		}
+	} else {
+		failed = append(failed, "ticker: no 24h ticker for symbol")
+	}
+
+	// Volume spike from stored hourly candles
+	spike, err := s.volumeSpike(ctx, symbol)
+	if err != nil {
+		failed = append(failed, "volume_spike: "+err.Error())
+	} else if spike != nil {
+		row.VolumeSpike = spike
+	}
+
+	// Open interest and funding from the (cached) derivatives snapshot
+	snapshot, err := s.derivativesService.GetSnapshot(ctx, symbol, 24)
+	if err != nil {
+		failed = append(failed, "derivatives: "+err.Error())
+		return row, failed
+	}
+	if reason, failedOI := snapshot.Errors["open_interest_change"]; failedOI {
+		failed = append(failed, "open_interest_change: "+reason)
+	} else if snapshot.OpenInterestValue > 0 {
+		value, change := snapshot.OpenInterestValue, snapshot.OpenInterestChangePct
+		row.OpenInterestValue, row.OpenInterestChangePct = &value, &change
+	}
+	if reason, failedMark := snapshot.Errors["mark_price"]; failedMark {
+		failed = append(failed, "funding: "+reason)
+	} else {
+		funding := snapshot.FundingRate
+		row.FundingRate = &funding
+		row.NextFundingTime = snapshot.NextFundingTime
+	}
+
+	return row, failed
+}
+
+// volumeSpike compares the last closed hour's volume with the average of the screenerSpikeHours
+// closed hours before it; nil when either is missing
+func (s *ScreenerService) volumeSpike(ctx context.Context, symbol string) (*float64, error) {
+	end := time.Now().UTC().Truncate(time.Hour)
+	lastOpen := end.Add(-time.Hour)
+	start := lastOpen.Add(-screenerSpikeHours * time.Hour)
+
+	candles, err := s.candleService.GetCandleRange(ctx, symbol, "1h", start, lastOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	var last float64
+	var total float64
+	var hours int
+	for _, candle := range candles {
+		volume := models.ParseFloat(candle.Volume)
+		if candle.OpenTime.Equal(lastOpen) {
+			last = volume
+			continue
+		}
+		total += volume
+		hours++
+	}
+	if hours == 0 || total <= 0 || last == 0 {
+		return nil, nil
+	}
+
+	spike := last / (total / float64(hours))
+	return &spike, nil
+}
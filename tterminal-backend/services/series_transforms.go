@@ -0,0 +1,63 @@
+package services
+
+import "math"
+
+// The moving averages below are shared by the query DSL's transforms and custom indicator
+// expressions. Both return one value per input value; undefined values are NaN
+
+// smaSeries is the simple moving average of the last period values, undefined while the run is
+// incomplete or holds an undefined value
+func smaSeries(values []float64, period int) []float64 {
+	out := make([]float64, len(values))
+	var sum float64
+	undefined := 0 // Undefined values in the run
+	for i, v := range values {
+		if math.IsNaN(v) {
+			undefined++
+		} else {
+			sum += v
+		}
+		if i >= period {
+			if old := values[i-period]; math.IsNaN(old) {
+				undefined--
+			} else {
+				sum -= old
+			}
+		}
+		if i < period-1 || undefined > 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = sum / float64(period)
+	}
+	return out
+}
+
+// emaSeries is the exponential moving average, seeded with the simple average of the first
+// period values and restarted after an undefined value
+func emaSeries(values []float64, period int) []float64 {
+	alpha := 2 / float64(period+1)
+	out := make([]float64, len(values))
+	var ema, sum float64
+	count := 0
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			ema, sum, count = 0, 0, 0
+			out[i] = math.NaN()
+			continue
+		case count < period:
+			sum += v
+			count++
+			if count < period {
+				out[i] = math.NaN()
+				continue
+			}
+			ema = sum / float64(period)
+		default:
+			ema = alpha*v + (1-alpha)*ema
+		}
+		out[i] = ema
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+)
+
+// SetTradingSessions sets the named UTC sessions of session volume profiles, given as
+// "<name>=HH:MM-HH:MM" entries
+func (s *AggregationService) SetTradingSessions(values []string) {
+	sessions := make([]models.TradingSession, 0, len(values))
+	for _, value := range values {
+		session, err := models.ParseTradingSession(value)
+		if err != nil {
+			log.Fatalf("[AggregationService] CRITICAL: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = sessions
+}
+
+// TradingSessions returns the configured trading sessions
+func (s *AggregationService) TradingSessions() []models.TradingSession {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessions
+}
+
+// GetSessionVolumeProfiles returns a volume profile per occurrence of the named sessions (all
+// configured sessions when names is empty) overlapping [startTime, endTime), each built from the
+// 1m candles within both the session and the range. Occurrences without candles are omitted
+func (s *AggregationService) GetSessionVolumeProfiles(ctx context.Context, symbol string, startTime, endTime time.Time, names []string) (*models.SessionVolumeProfiles, error) {
+	if err := validateVersionedRange(startTime, endTime, maxVersionedVolumeProfileRange); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.selectSessions(names)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("vp:sessions:%s:%d:%d:%s", symbol, startTime.Unix(), endTime.Unix(), strings.Join(names, ","))
+	if cached := s.getFromMemCache(cacheKey); cached != nil {
+		if profiles, ok := cached.Data.(*models.SessionVolumeProfiles); ok {
+			return profiles, nil
+		}
+	}
+
+	candles, err := s.candleService.GetByTimeRange(ctx, symbol, "1m", startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profiles := &models.SessionVolumeProfiles{
+		S:  symbol,
+		ST: startTime.UnixMilli(),
+		ET: endTime.UnixMilli(),
+		P:  []models.SessionVolumeProfile{},
+	}
+	for _, session := range sessions {
+		for _, window := range session.Windows(startTime, endTime) {
+			from, to := window[0], window[1]
+			if from.Before(startTime) {
+				from = startTime
+			}
+			if to.After(endTime) {
+				to = endTime
+			}
+
+			// Candles are sorted by open time, so the session's are one contiguous run
+			first := sort.Search(len(candles), func(i int) bool { return !candles[i].OpenTime.Before(from) })
+			last := sort.Search(len(candles), func(i int) bool { return !candles[i].OpenTime.Before(to) })
+			if first == last {
+				continue
+			}
+
+			profiles.P = append(profiles.P, models.SessionVolumeProfile{
+				N:             session.Name,
+				Open:          window[0].UnixMilli(),
+				Close:         window[1].UnixMilli(),
+				Partial:       !from.Equal(window[0]) || !to.Equal(window[1]) || window[1].After(now),
+				VolumeProfile: buildVolumeProfile(symbol, from, to, candles[first:last]),
+			})
+		}
+	}
+
+	// Ordered by open; sessions opening together keep their configured order
+	sort.SliceStable(profiles.P, func(i, j int) bool {
+		return profiles.P[i].Open < profiles.P[j].Open
+	})
+
+	s.setMemCache(cacheKey, profiles, 2*time.Minute)
+	return profiles, nil
+}
+
+// selectSessions returns the configured sessions named in names, or all when names is empty
+func (s *AggregationService) selectSessions(names []string) ([]models.TradingSession, error) {
+	configured := s.TradingSessions()
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("validation failed: no trading sessions are configured")
+	}
+	if len(names) == 0 {
+		return configured, nil
+	}
+
+	selected := make([]models.TradingSession, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, session := range configured {
+			if session.Name == name {
+				selected = append(selected, session)
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(configured))
+			for i, session := range configured {
+				available[i] = session.Name
+			}
+			return nil, fmt.Errorf("validation failed: unknown session %q, use %s", name, strings.Join(available, ", "))
+		}
+	}
+	return selected, nil
+}
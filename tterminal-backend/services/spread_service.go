@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+	"tterminal-backend/internal/batchwriter"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// ErrUnknownSpreadPair is returned for spread history of a pair that is not monitored
+var ErrUnknownSpreadPair = errors.New("spread pair is not monitored")
+
+// SpreadService samples spot-perp and exchange-vs-exchange spreads of configured pairs from live
+// stream prices, persists them and pushes each tick on the "spreads" WebSocket channel
+type SpreadService struct {
+	spreadRepo   *repositories.SpreadRepository
+	spreadWriter *batchwriter.Writer[models.SpreadSample] // Stores samples off the ticker
+	hub          transport.Publisher
+	lastPrice    func(symbol string) (float64, bool)
+	pairs        []models.SpreadPair
+	interval     time.Duration
+	arbitrageBps float64
+
+	mu       sync.RWMutex
+	latest   map[string]models.SpreadSample // Most recent sample per pair name
+	stopChan chan struct{}
+}
+
+// NewSpreadService creates a new spread service for pairs given as "<leg_a>/<leg_b>" symbol keys
+// Its batch writer is registered with writers for metrics and shutdown flushing when it is not nil
+func NewSpreadService(spreadRepo *repositories.SpreadRepository, hub transport.Publisher, lastPrice func(symbol string) (float64, bool), pairs []string, interval time.Duration, arbitrageBps float64, writers *batchwriter.Registry) *SpreadService {
+	if spreadRepo == nil {
+		log.Fatalf("[SpreadService] CRITICAL: spreadRepo cannot be nil")
+	}
+	if isNil(hub) {
+		log.Fatalf("[SpreadService] CRITICAL: hub cannot be nil")
+	}
+	if lastPrice == nil {
+		log.Fatalf("[SpreadService] CRITICAL: lastPrice cannot be nil")
+	}
+
+	parsed := make([]models.SpreadPair, 0, len(pairs))
+	for _, value := range pairs {
+		pair, err := models.ParseSpreadPair(value)
+		if err != nil {
+			log.Fatalf("[SpreadService] CRITICAL: %v", err)
+		}
+		parsed = append(parsed, pair)
+	}
+
+	log.Printf("[SpreadService] Successfully initialized (%d pairs every %v, arbitrage at %.1f bps)", len(parsed), interval, arbitrageBps)
+	return &SpreadService{
+		spreadRepo:   spreadRepo,
+		spreadWriter: batchwriter.New(writers, batchwriter.Config{Name: "spreads"}, spreadRepo.BulkCreate),
+		hub:          hub,
+		lastPrice:    lastPrice,
+		pairs:        parsed,
+		interval:     interval,
+		arbitrageBps: arbitrageBps,
+		latest:       make(map[string]models.SpreadSample, len(parsed)),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start samples spreads on every interval
+func (s *SpreadService) Start() error {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				s.sample(now)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sampling
+func (s *SpreadService) Stop() {
+	close(s.stopChan)
+}
+
+// sample computes the spread of every pair whose legs both have a live price,
+// then broadcasts and queues them to be stored; pairs missing a price are skipped this tick
+func (s *SpreadService) sample(now time.Time) {
+	now = now.UTC().Truncate(time.Second)
+
+	samples := make([]models.SpreadSample, 0, len(s.pairs))
+	for _, pair := range s.pairs {
+		priceA, okA := s.lastPrice(pair.LegA)
+		priceB, okB := s.lastPrice(pair.LegB)
+		if !okA || !okB || priceA <= 0 || priceB <= 0 {
+			continue
+		}
+
+		spread := priceA - priceB
+		spreadBps := spread / priceB * 10000
+		samples = append(samples, models.SpreadSample{
+			SpreadPair: pair,
+			PriceA:     priceA,
+			PriceB:     priceB,
+			Spread:     spread,
+			SpreadBps:  spreadBps,
+			Arbitrage:  math.Abs(spreadBps) >= s.arbitrageBps,
+			Time:       now,
+		})
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	for _, sample := range samples {
+		s.latest[sample.Name] = sample
+	}
+	s.mu.Unlock()
+
+	s.hub.BroadcastSpreadUpdate(samples)
+	s.spreadWriter.Add(samples...)
+}
+
+// ArbitrageBps returns the threshold at which spreads are flagged as arbitrage
+func (s *SpreadService) ArbitrageBps() float64 {
+	return s.arbitrageBps
+}
+
+// GetLatest returns the most recent sample of every monitored pair, in configured order
+// Pairs that have not been sampled yet are omitted
+func (s *SpreadService) GetLatest() []models.SpreadSample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := make([]models.SpreadSample, 0, len(s.pairs))
+	for _, pair := range s.pairs {
+		if sample, ok := s.latest[pair.Name]; ok {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// GetHistory returns a monitored pair's stored spreads over the last hours, bucketed by interval
+func (s *SpreadService) GetHistory(ctx context.Context, pair, interval string, hours int) (*models.SpreadHistory, error) {
+	parsed, err := models.ParseSpreadPair(pair)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	monitored := false
+	for _, p := range s.pairs {
+		if p.Name == parsed.Name {
+			monitored = true
+			break
+		}
+	}
+	if !monitored {
+		return nil, ErrUnknownSpreadPair
+	}
+
+	bucket, ok := models.IntervalDuration(interval)
+	if !ok {
+		return nil, fmt.Errorf("validation failed: invalid interval %q", interval)
+	}
+
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(hours) * time.Hour)
+	buckets, err := s.spreadRepo.GetBuckets(ctx, parsed.Name, bucket, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SpreadHistory{
+		Pair:      parsed.Name,
+		Interval:  interval,
+		Buckets:   buckets,
+		Count:     len(buckets),
+		Timestamp: time.Now().UnixMilli(),
+	}, nil
+}
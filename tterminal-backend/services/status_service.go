@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/database"
+	"tterminal-backend/internal/websocket"
+	"tterminal-backend/models"
+)
+
+const (
+	// statusCacheTTL is how long an assembled status page is reused, so a public endpoint cannot
+	// turn banner polling into database pings
+	statusCacheTTL = 5 * time.Second
+	// streamStaleAfter marks a connected stream degraded when it delivers nothing for this long
+	streamStaleAfter = time.Minute
+	// reconnectWindow is how far back the status page lists stream reconnects
+	reconnectWindow = 24 * time.Hour
+	// collectionStaleRuns marks collection degraded after this many missed 1m collection periods
+	collectionStaleRuns = 3
+)
+
+// StatusService assembles the public status page from the health monitors
+// Messages are written for end users and never include raw upstream or database errors
+type StatusService struct {
+	db             *database.DB
+	binanceClient  *binance.Client
+	stream         *websocket.BinanceStream
+	dataCollection *DataCollectionService
+
+	mu       sync.Mutex
+	cached   *models.StatusPage
+	cachedAt time.Time
+}
+
+// NewStatusService creates a new status service
+func NewStatusService(db *database.DB, binanceClient *binance.Client, stream *websocket.BinanceStream, dataCollection *DataCollectionService) *StatusService {
+	return &StatusService{
+		db:             db,
+		binanceClient:  binanceClient,
+		stream:         stream,
+		dataCollection: dataCollection,
+	}
+}
+
+// GetStatus returns the current status page
+func (s *StatusService) GetStatus(ctx context.Context) *models.StatusPage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < statusCacheTTL {
+		return s.cached
+	}
+
+	s.cached = s.buildStatus(ctx)
+	s.cachedAt = time.Now()
+	return s.cached
+}
+
+// buildStatus checks every component and derives incidents and the overall status
+func (s *StatusService) buildStatus(ctx context.Context) *models.StatusPage {
+	now := time.Now().UTC()
+	page := &models.StatusPage{
+		Status:           models.ComponentOperational,
+		Incidents:        []models.Incident{},
+		RecentReconnects: s.stream.RecentReconnects(now.Add(-reconnectWindow)),
+		Freshness: models.DataFreshness{
+			StreamAgeSeconds: make(map[string]float64),
+			CandleAgeSeconds: make(map[string]float64),
+		},
+		PausedSymbols: []models.PausedSymbol{},
+		UpdatedAt:     now,
+	}
+
+	page.Components = append(page.Components, models.ComponentStatus{
+		ID: "api", Name: "API", Status: models.ComponentOperational,
+	})
+	page.Components = append(page.Components, s.databaseStatus(ctx))
+	page.Components = append(page.Components, s.upstreamStatus())
+	page.Components = append(page.Components, s.streamStatuses(now, &page.Freshness)...)
+	page.Components = append(page.Components, s.collectionStatus(now, &page.Freshness))
+	for _, pause := range s.dataCollection.PausedSymbols() {
+		page.PausedSymbols = append(page.PausedSymbols, models.PausedSymbol{
+			Symbol: pause.Symbol, Reason: pause.Reason, Since: pause.PausedAt, Until: pause.Until,
+		})
+	}
+
+	for _, component := range page.Components {
+		if models.ComponentSeverity(component.Status) > models.ComponentSeverity(page.Status) {
+			page.Status = component.Status
+		}
+		if component.Status == models.ComponentOperational {
+			continue
+		}
+
+		startedAt := now
+		if component.Since != nil {
+			startedAt = *component.Since
+		}
+		severity := "minor"
+		if component.Status == models.ComponentMajorOutage {
+			severity = "major"
+		}
+		page.Incidents = append(page.Incidents, models.Incident{
+			ID:        fmt.Sprintf("%s-%d", component.ID, startedAt.Unix()),
+			Component: component.ID,
+			Severity:  severity,
+			Title:     component.Name + " " + incidentVerb(component.Status),
+			Message:   component.Message,
+			StartedAt: startedAt,
+		})
+	}
+
+	return page
+}
+
+// databaseStatus pings the database
+func (s *StatusService) databaseStatus(ctx context.Context) models.ComponentStatus {
+	component := models.ComponentStatus{ID: "database", Name: "Historical data storage", Status: models.ComponentOperational}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := s.db.Health(pingCtx); err != nil {
+		component.Status = models.ComponentMajorOutage
+		component.Message = "Stored candles and analytics are unavailable"
+	}
+	return component
+}
+
+// upstreamStatus reports Binance REST reachability
+func (s *StatusService) upstreamStatus() models.ComponentStatus {
+	component := models.ComponentStatus{ID: "binance_rest", Name: "Exchange API", Status: models.ComponentOperational}
+	if s.stream.IsSynthetic() {
+		component.Message = "Synthetic market data"
+		return component
+	}
+
+	upstream := s.binanceClient.UpstreamStatus()
+	since := upstream.Since.UTC()
+	component.Since = &since
+	if upstream.Degraded {
+		component.Status = models.ComponentPartialOutage
+		component.Message = "Exchange API unreachable, serving stored data"
+	}
+	return component
+}
+
+// streamStatuses reports each market data stream and records its freshness
+func (s *StatusService) streamStatuses(now time.Time, freshness *models.DataFreshness) []models.ComponentStatus {
+	names := map[string]string{
+		"spot":           "Spot market stream",
+		"futures":        "Futures market stream",
+		"coinm":          "COIN-margined futures stream",
+		"spot_backup":    "Spot market backup stream",
+		"futures_backup": "Futures market backup stream",
+		"coinm_backup":   "COIN-margined futures backup stream",
+	}
+
+	connections := s.stream.StreamConnections()
+	connected := make(map[string]bool, len(connections))
+	for _, connection := range connections {
+		connected[connection.Stream] = connection.Connected
+	}
+
+	var components []models.ComponentStatus
+	for _, connection := range connections {
+		component := models.ComponentStatus{
+			ID:     "stream_" + connection.Stream,
+			Name:   names[connection.Stream],
+			Status: models.ComponentOperational,
+			Since:  connection.Since,
+		}
+		if connection.LastMessageAt != nil {
+			freshness.StreamAgeSeconds[connection.Stream] = now.Sub(*connection.LastMessageAt).Seconds()
+		}
+
+		switch {
+		case s.stream.IsSynthetic():
+			// Synthetic data only feeds the futures stream
+			component.Message = "Synthetic market data"
+		case !connection.Connected && connected[connection.Stream+"_backup"]:
+			// Redundant connections: clients keep receiving the market from the backup
+			component.Status = models.ComponentDegraded
+			component.Message = "Disconnected, served by the backup connection"
+		case !connection.Connected:
+			component.Status = models.ComponentPartialOutage
+			component.Message = "Disconnected, reconnecting"
+		case connection.LastMessageAt == nil || now.Sub(*connection.LastMessageAt) > streamStaleAfter:
+			component.Status = models.ComponentDegraded
+			component.Message = "Connected but not receiving updates"
+			component.Since = nil
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// collectionStatus reports the candle collection runs and records candle freshness
+// Paused symbols are named in the message but do not degrade collection, which pauses them on purpose
+func (s *StatusService) collectionStatus(now time.Time, freshness *models.DataFreshness) models.ComponentStatus {
+	component := models.ComponentStatus{ID: "data_collection", Name: "Candle collection", Status: models.ComponentOperational}
+
+	stats := s.dataCollection.GetStats()
+	if !stats.LastSuccessTime.IsZero() {
+		age := now.Sub(stats.LastSuccessTime).Seconds()
+		freshness.CollectionAgeSeconds = &age
+	}
+	for _, symbol := range stats.ActiveSymbols {
+		if lastUpdate := s.dataCollection.GetLastUpdateTime(symbol, "1m"); lastUpdate != nil {
+			freshness.CandleAgeSeconds[symbol] = now.Sub(*lastUpdate).Seconds()
+		}
+	}
+
+	staleAfter := time.Duration(collectionStaleRuns*stats.MinuteCollectionPeriod) * time.Second
+	switch {
+	case !stats.IsRunning:
+		component.Status = models.ComponentDegraded
+		component.Message = "Collection is paused, stored candles may be behind"
+	case !stats.LastSuccessTime.IsZero() && now.Sub(stats.LastSuccessTime) > staleAfter:
+		component.Status = models.ComponentDegraded
+		component.Message = "Stored candles are behind"
+		since := stats.LastSuccessTime.Add(staleAfter).UTC()
+		component.Since = &since
+	case len(stats.PausedSymbols) > 0:
+		component.Message = "Paused for " + strings.Join(stats.PausedSymbols, ", ")
+	}
+	return component
+}
+
+// incidentVerb describes a component state in an incident title
+func incidentVerb(state string) string {
+	switch state {
+	case models.ComponentMajorOutage:
+		return "outage"
+	case models.ComponentPartialOutage:
+		return "partially unavailable"
+	default:
+		return "degraded"
+	}
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"time"
+	"tterminal-backend/internal/marketdata"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/models"
+)
+
+const (
+	// maxCaptureRange caps how much time a single stream capture may cover
+	maxCaptureRange = 24 * time.Hour
+
+	// captureTradePage is how many stored trades are read per query while writing a capture
+	captureTradePage = 10000
+)
+
+// StreamCaptureService packages stored trades, klines and liquidations into the messages the
+// live stream sends, so the frontend replays archived data exactly like live or recorded data
+type StreamCaptureService struct {
+	tradeRepo    marketdata.TradeStore
+	candleRepo   marketdata.CandleStore
+	liquidations marketdata.LiquidationSource
+	messages     transport.MessageEncoder
+}
+
+// NewStreamCaptureService creates a new stream capture service
+func NewStreamCaptureService(tradeRepo marketdata.TradeStore, candleRepo marketdata.CandleStore, liquidations marketdata.LiquidationSource, messages transport.MessageEncoder) *StreamCaptureService {
+	if isNil(tradeRepo) {
+		log.Fatalf("[StreamCaptureService] CRITICAL: tradeRepo cannot be nil")
+	}
+	if isNil(candleRepo) {
+		log.Fatalf("[StreamCaptureService] CRITICAL: candleRepo cannot be nil")
+	}
+	if isNil(messages) {
+		log.Fatalf("[StreamCaptureService] CRITICAL: messages cannot be nil")
+	}
+	if isNil(liquidations) {
+		log.Printf("[StreamCaptureService] WARNING: liquidations is nil - captures will not include liquidations")
+		liquidations = nil
+	}
+
+	log.Printf("[StreamCaptureService] Successfully initialized")
+	return &StreamCaptureService{
+		tradeRepo:    tradeRepo,
+		candleRepo:   candleRepo,
+		liquidations: liquidations,
+		messages:     messages,
+	}
+}
+
+// StreamCapture is a validated capture whose klines and liquidations are loaded; trades are
+// read page by page while it is written
+type StreamCapture struct {
+	service *StreamCaptureService
+	params  models.StreamCaptureParams
+	pending []models.RecordedMessage // Kline and liquidation messages, oldest first
+}
+
+// Capture validates params and loads the capture's klines and liquidations
+func (s *StreamCaptureService) Capture(ctx context.Context, params models.StreamCaptureParams) (*StreamCapture, error) {
+	if params.Symbol == "" {
+		return nil, fmt.Errorf("validation failed: symbol is required")
+	}
+	if !params.End.After(params.Start) {
+		return nil, fmt.Errorf("validation failed: end must be after start")
+	}
+	if params.End.Sub(params.Start) > maxCaptureRange {
+		return nil, fmt.Errorf("validation failed: range exceeds %v", maxCaptureRange)
+	}
+	if len(params.Events) == 0 {
+		params.Events = models.CaptureEventTypes
+	}
+	for _, event := range params.Events {
+		if !models.IsValidCaptureEvent(event) {
+			return nil, fmt.Errorf("validation failed: invalid event %q, use trades, klines or liquidations", event)
+		}
+	}
+	if !models.IsValidInterval(params.Interval) {
+		return nil, fmt.Errorf("validation failed: invalid interval %q", params.Interval)
+	}
+
+	capture := &StreamCapture{service: s, params: params}
+	if capture.includes(models.CaptureEventKlines) {
+		if err := capture.loadKlines(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if capture.includes(models.CaptureEventLiquidations) {
+		if err := capture.loadLiquidations(); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(capture.pending, func(i, j int) bool {
+		return capture.pending[i].Time < capture.pending[j].Time
+	})
+	return capture, nil
+}
+
+// Write streams the capture to w as gzipped newline-delimited messages in time order,
+// returning the number of messages written
+func (c *StreamCapture) Write(ctx context.Context, w io.Writer) (int, error) {
+	gz := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gz)
+	written := 0
+
+	// writeUntil writes pending kline and liquidation messages up to and including t
+	writeUntil := func(t int64) error {
+		for len(c.pending) > 0 && c.pending[0].Time <= t {
+			if err := encoder.Encode(c.pending[0]); err != nil {
+				return err
+			}
+			c.pending = c.pending[1:]
+			written++
+		}
+		return nil
+	}
+
+	if c.includes(models.CaptureEventTrades) {
+		start := c.params.Start
+		var lastTime time.Time
+		var lastID int64
+		for {
+			trades, err := c.service.tradeRepo.GetByTimeRange(ctx, c.params.Symbol, start, c.params.End, captureTradePage)
+			if err != nil {
+				return written, err
+			}
+
+			progressed := false
+			for _, trade := range trades {
+				// Pages resume at the last trade time, so skip trades already written at it
+				if trade.TradeTime.Equal(lastTime) && trade.TradeID <= lastID {
+					continue
+				}
+				progressed = true
+				lastTime, lastID = trade.TradeTime, trade.TradeID
+
+				tradeTime := trade.TradeTime.UnixMilli()
+				if err := writeUntil(tradeTime); err != nil {
+					return written, err
+				}
+				message, err := c.service.messages.TradeMessage(trade, tradeTime)
+				if err != nil {
+					return written, err
+				}
+				if err := encoder.Encode(models.RecordedMessage{Time: tradeTime, Message: message}); err != nil {
+					return written, err
+				}
+				written++
+			}
+
+			if len(trades) < captureTradePage || !progressed {
+				break
+			}
+			start = lastTime
+		}
+	}
+
+	if err := writeUntil(c.params.End.UnixMilli()); err != nil {
+		return written, err
+	}
+	return written, gz.Close()
+}
+
+// includes reports whether the capture contains an event type
+func (c *StreamCapture) includes(event string) bool {
+	for _, e := range c.params.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// loadKlines queues a closed kline message, stamped at its close, for every stored candle that
+// closes within the range
+func (c *StreamCapture) loadKlines(ctx context.Context) error {
+	candles, err := c.service.candleRepo.GetByTimeRange(ctx, c.params.Symbol, c.params.Interval, c.params.Start, c.params.End)
+	if err != nil {
+		return err
+	}
+
+	for _, candle := range candles {
+		if candle.CloseTime.After(c.params.End) {
+			continue
+		}
+		closeTime := candle.CloseTime.UnixMilli()
+		message, err := c.service.messages.KlineMessage(candle, true, closeTime)
+		if err != nil {
+			return err
+		}
+		c.pending = append(c.pending, models.RecordedMessage{Time: closeTime, Message: message})
+	}
+	return nil
+}
+
+// loadLiquidations queues the liquidations the live stream still holds for the range
+// Liquidations are not persisted; the stream keeps the last 1000 per symbol
+func (c *StreamCapture) loadLiquidations() error {
+	if c.service.liquidations == nil {
+		return nil
+	}
+
+	start, end := c.params.Start.UnixMilli(), c.params.End.UnixMilli()
+	for _, liquidation := range c.service.liquidations.GetRecentLiquidations(c.params.Symbol, 0) {
+		tradeTime := liquidation.TradeTime.UnixMilli()
+		if tradeTime < start || tradeTime > end {
+			continue
+		}
+		message, err := c.service.messages.LiquidationMessage(liquidation, tradeTime)
+		if err != nil {
+			return err
+		}
+		c.pending = append(c.pending, models.RecordedMessage{Time: tradeTime, Message: message})
+	}
+	return nil
+}
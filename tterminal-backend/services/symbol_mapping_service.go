@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// Errors returned when a canonical symbol cannot be resolved
+var (
+	ErrCanonicalSymbolNotFound = errors.New("canonical symbol not found")
+	ErrSymbolMappingNotFound   = errors.New("canonical symbol is not mapped on exchange")
+)
+
+// SymbolMappingService resolves canonical symbols ("BTC-PERP") to each exchange's symbol key
+// Mappings are kept in memory so requests and stream subscriptions resolve without a query
+type SymbolMappingService struct {
+	mappingRepo *repositories.SymbolMappingRepository
+	mu          sync.RWMutex
+	mappings    map[string]map[string]models.SymbolMapping // Canonical symbol -> exchange -> mapping
+}
+
+// NewSymbolMappingService creates a new symbol mapping service
+func NewSymbolMappingService(mappingRepo *repositories.SymbolMappingRepository) *SymbolMappingService {
+	if mappingRepo == nil {
+		log.Fatalf("[SymbolMappingService] CRITICAL: mappingRepo cannot be nil")
+	}
+	log.Printf("[SymbolMappingService] Successfully initialized")
+	return &SymbolMappingService{
+		mappingRepo: mappingRepo,
+		mappings:    make(map[string]map[string]models.SymbolMapping),
+	}
+}
+
+// Start loads the stored mappings
+func (s *SymbolMappingService) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.reload(ctx); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	log.Printf("[SymbolMappingService] Loaded %d canonical symbols", len(s.mappings))
+	s.mu.RUnlock()
+	return nil
+}
+
+// ResolveSymbol returns the symbol key of a canonical symbol on an exchange, defaulting to Binance
+// (or the first mapped exchange) when exchange is empty. Symbols that are not canonical are
+// returned unchanged with resolved false
+func (s *SymbolMappingService) ResolveSymbol(symbol, exchange string) (key string, resolved bool, err error) {
+	canonical := strings.ToUpper(symbol)
+	if !models.IsCanonicalSymbol(canonical) {
+		return symbol, false, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	byExchange, exists := s.mappings[canonical]
+	if !exists {
+		// Not a known canonical symbol; leave it to the exchange
+		return symbol, false, nil
+	}
+
+	exchange = strings.ToLower(exchange)
+	if exchange == "" {
+		for _, candidate := range models.Exchanges {
+			if _, mapped := byExchange[candidate]; mapped {
+				exchange = candidate
+				break
+			}
+		}
+	}
+	mapping, mapped := byExchange[exchange]
+	if !mapped {
+		return "", false, fmt.Errorf("%w: %s on %s", ErrSymbolMappingNotFound, canonical, exchange)
+	}
+	return mapping.Symbol, true, nil
+}
+
+// IndexConstituents returns the canonical symbol of a canonical or mapped exchange symbol with the
+// symbol key of every exchange it is mapped on, for composite index candles
+func (s *SymbolMappingService) IndexConstituents(symbol string) (canonical string, symbols []string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	canonical = strings.ToUpper(symbol)
+	byExchange, exists := s.mappings[canonical]
+	if !exists {
+		// An exchange symbol: find the canonical symbol it is mapped from
+		canonical = ""
+		for candidate, mappings := range s.mappings {
+			for _, mapping := range mappings {
+				if mapping.Symbol == symbol && (canonical == "" || candidate < canonical) {
+					canonical, byExchange = candidate, mappings
+				}
+			}
+		}
+	}
+	if canonical == "" {
+		return "", nil, fmt.Errorf("%w: %s", ErrNoIndexConstituents, symbol)
+	}
+
+	for _, exchange := range models.Exchanges {
+		if mapping, mapped := byExchange[exchange]; mapped {
+			symbols = append(symbols, mapping.Symbol)
+		}
+	}
+	return canonical, symbols, nil
+}
+
+// GetCanonicalSymbols returns every canonical symbol with its mappings, sorted by canonical symbol
+func (s *SymbolMappingService) GetCanonicalSymbols(ctx context.Context) ([]models.CanonicalSymbol, error) {
+	mappings, err := s.mappingRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []models.CanonicalSymbol{}
+	for _, mapping := range mappings {
+		mapping.Symbol = models.QualifySymbol(mapping.Exchange, mapping.ExchangeSymbol)
+		if n := len(symbols); n > 0 && symbols[n-1].Canonical == mapping.Canonical {
+			symbols[n-1].Mappings = append(symbols[n-1].Mappings, mapping)
+			continue
+		}
+		symbols = append(symbols, models.CanonicalSymbol{
+			Canonical: mapping.Canonical,
+			Mappings:  []models.SymbolMapping{mapping},
+		})
+	}
+	return symbols, nil
+}
+
+// GetCanonicalSymbol returns a canonical symbol with its mappings
+func (s *SymbolMappingService) GetCanonicalSymbol(ctx context.Context, canonical string) (*models.CanonicalSymbol, error) {
+	canonical = strings.ToUpper(canonical)
+	symbols, err := s.GetCanonicalSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range symbols {
+		if symbols[i].Canonical == canonical {
+			return &symbols[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrCanonicalSymbolNotFound, canonical)
+}
+
+// SetMapping maps a canonical symbol on an exchange, replacing any earlier mapping there
+func (s *SymbolMappingService) SetMapping(ctx context.Context, canonical, exchange string, req *models.SetSymbolMappingRequest) (*models.SymbolMapping, error) {
+	canonical = strings.ToUpper(strings.TrimSpace(canonical))
+	if !models.IsCanonicalSymbol(canonical) {
+		return nil, fmt.Errorf("validation failed: canonical symbol must be up to 32 letters and digits joined by hyphens (e.g. BTC-PERP)")
+	}
+	exchange = strings.ToLower(exchange)
+	if !models.IsValidExchange(exchange) {
+		return nil, fmt.Errorf("validation failed: exchange must be one of %s", strings.Join(models.Exchanges, ", "))
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if symbol == "" || len(symbol) > 32 || strings.ContainsAny(symbol, ":-") {
+		return nil, fmt.Errorf("validation failed: symbol must be the bare exchange symbol (e.g. BTCUSDT)")
+	}
+
+	mapping := &models.SymbolMapping{
+		Canonical:      canonical,
+		Exchange:       exchange,
+		ExchangeSymbol: symbol,
+	}
+	if err := s.mappingRepo.Upsert(ctx, mapping); err != nil {
+		return nil, err
+	}
+	mapping.Symbol = models.QualifySymbol(exchange, symbol)
+
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// DeleteMapping removes a canonical symbol's mapping on an exchange
+func (s *SymbolMappingService) DeleteMapping(ctx context.Context, canonical, exchange string) error {
+	canonical = strings.ToUpper(canonical)
+	exchange = strings.ToLower(exchange)
+	deleted, err := s.mappingRepo.Delete(ctx, canonical, exchange)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s on %s", ErrSymbolMappingNotFound, canonical, exchange)
+	}
+	return s.reload(ctx)
+}
+
+// reload replaces the in-memory mappings with the stored ones
+func (s *SymbolMappingService) reload(ctx context.Context) error {
+	stored, err := s.mappingRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	mappings := make(map[string]map[string]models.SymbolMapping)
+	for _, mapping := range stored {
+		mapping.Symbol = models.QualifySymbol(mapping.Exchange, mapping.ExchangeSymbol)
+		if mappings[mapping.Canonical] == nil {
+			mappings[mapping.Canonical] = make(map[string]models.SymbolMapping)
+		}
+		mappings[mapping.Canonical][mapping.Exchange] = mapping
+	}
+
+	s.mu.Lock()
+	s.mappings = mappings
+	s.mu.Unlock()
+	return nil
+}
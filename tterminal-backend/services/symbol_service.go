@@ -48,6 +48,7 @@ func (s *SymbolService) CreateSymbol(ctx context.Context, req *models.CreateSymb
 		return nil, fmt.Errorf("failed to create symbol: %w", err)
 	}
 
+	symbol.FillDisplay()
 	return symbol, nil
 }
 
@@ -66,6 +67,7 @@ func (s *SymbolService) GetSymbol(ctx context.Context, symbolName string) (*mode
 		return nil, fmt.Errorf("symbol not found")
 	}
 
+	symbol.FillDisplay()
 	return symbol, nil
 }
 
@@ -76,6 +78,7 @@ func (s *SymbolService) GetAllSymbols(ctx context.Context) ([]models.Symbol, err
 		return nil, fmt.Errorf("failed to get symbols: %w", err)
 	}
 
+	fillSymbolDisplay(symbols)
 	return symbols, nil
 }
 
@@ -86,6 +89,7 @@ func (s *SymbolService) GetActiveSymbols(ctx context.Context) ([]models.Symbol,
 		return nil, fmt.Errorf("failed to get active symbols: %w", err)
 	}
 
+	fillSymbolDisplay(symbols)
 	return symbols, nil
 }
 
@@ -166,3 +170,10 @@ func (s *SymbolService) validateCreateSymbolRequest(req *models.CreateSymbolRequ
 
 	return nil
 }
+
+// fillSymbolDisplay derives display metadata for every symbol in a list
+func fillSymbolDisplay(symbols []models.Symbol) {
+	for i := range symbols {
+		symbols[i].FillDisplay()
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/internal/transport"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+// SymbolSyncService periodically syncs USDT futures symbols from exchangeInfo into the symbols table
+// and broadcasts a "symbol_update" event for every symbol whose precision, filters, status or
+// leverage brackets changed, so terminals update order-entry validation live
+type SymbolSyncService struct {
+	binanceService *BinanceService
+	binanceClient  *binance.Client
+	symbolRepo     *repositories.SymbolRepository
+	hub            transport.Publisher
+	interval       time.Duration
+
+	mu       sync.Mutex
+	brackets map[string][]models.LeverageBracket // Last synced leverage brackets; nil until first synced
+	stopChan chan struct{}
+}
+
+// NewSymbolSyncService creates a new symbol sync service
+func NewSymbolSyncService(binanceService *BinanceService, binanceClient *binance.Client, symbolRepo *repositories.SymbolRepository, hub transport.Publisher, interval time.Duration) *SymbolSyncService {
+	if binanceService == nil {
+		log.Fatalf("[SymbolSyncService] CRITICAL: binanceService cannot be nil")
+	}
+	if symbolRepo == nil {
+		log.Fatalf("[SymbolSyncService] CRITICAL: symbolRepo cannot be nil")
+	}
+	if isNil(hub) {
+		log.Fatalf("[SymbolSyncService] CRITICAL: hub cannot be nil")
+	}
+	log.Printf("[SymbolSyncService] Successfully initialized (every %v)", interval)
+	return &SymbolSyncService{
+		binanceService: binanceService,
+		binanceClient:  binanceClient,
+		symbolRepo:     symbolRepo,
+		hub:            hub,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start syncs immediately, then on every interval
+func (s *SymbolSyncService) Start() error {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.runSync()
+		for {
+			select {
+			case <-ticker.C:
+				s.runSync()
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic sync
+func (s *SymbolSyncService) Stop() {
+	close(s.stopChan)
+}
+
+// runSync performs one sync with a timeout, logging failures
+func (s *SymbolSyncService) runSync() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	updated, err := s.Sync(ctx)
+	if err != nil {
+		log.Printf("[SymbolSyncService] Sync failed: %v", err)
+		return
+	}
+	if updated > 0 {
+		log.Printf("[SymbolSyncService] %d symbols changed", updated)
+	}
+}
+
+// Sync stores exchangeInfo changes and broadcasts them, returning the number of symbols changed
+// Leverage brackets are synced only when Binance API credentials are configured; their first sync
+// sets the baseline without broadcasting
+func (s *SymbolSyncService) Sync(ctx context.Context) (int, error) {
+	exchangeInfo, err := s.binanceService.FetchExchangeInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	stored, err := s.symbolRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	storedBySymbol := make(map[string]*models.Symbol, len(stored))
+	for i := range stored {
+		storedBySymbol[stored[i].Symbol] = &stored[i]
+	}
+
+	brackets, previousBrackets := s.syncBrackets(ctx)
+
+	updated := 0
+	for _, info := range exchangeInfo.Symbols {
+		// Only USDT futures are stored; non-trading statuses are kept so status changes are seen
+		if info.QuoteAsset != "USDT" {
+			continue
+		}
+		synced := s.binanceService.ConvertBinanceSymbolToModel(info)
+
+		var changes []string
+		if existing, exists := storedBySymbol[synced.Symbol]; !exists {
+			if synced.Status != "TRADING" {
+				continue
+			}
+			if err := s.symbolRepo.Create(ctx, synced); err != nil {
+				log.Printf("[SymbolSyncService] Failed to store %s: %v", synced.Symbol, err)
+				continue
+			}
+			changes = append(changes, models.SymbolChangeListed)
+		} else {
+			changes = models.MetadataChanges(existing, synced)
+			if len(changes) > 0 {
+				if err := s.symbolRepo.UpdateMetadata(ctx, synced); err != nil {
+					log.Printf("[SymbolSyncService] Failed to update %s: %v", synced.Symbol, err)
+					continue
+				}
+				synced.ID, synced.CreatedAt = existing.ID, existing.CreatedAt
+			}
+		}
+		if previousBrackets != nil && !reflect.DeepEqual(previousBrackets[synced.Symbol], brackets[synced.Symbol]) {
+			changes = append(changes, models.SymbolChangeLeverageBrackets)
+		}
+
+		if len(changes) > 0 {
+			updated++
+			s.hub.BroadcastSymbolUpdate(synced, changes, brackets[synced.Symbol])
+		}
+	}
+	return updated, nil
+}
+
+// syncBrackets fetches the leverage brackets and returns them with the previously synced ones
+// When they cannot be fetched, the previous brackets are returned for both so nothing is reported
+func (s *SymbolSyncService) syncBrackets(ctx context.Context) (current, previous map[string][]models.LeverageBracket) {
+	s.mu.Lock()
+	previous = s.brackets
+	s.mu.Unlock()
+
+	if s.binanceClient == nil {
+		return previous, nil
+	}
+	current, err := s.binanceClient.GetLeverageBrackets(ctx)
+	if err != nil {
+		if !errors.Is(err, binance.ErrNoCredentials) {
+			log.Printf("[SymbolSyncService] Failed to fetch leverage brackets: %v", err)
+		}
+		return previous, nil
+	}
+
+	s.mu.Lock()
+	s.brackets = current
+	s.mu.Unlock()
+	return current, previous
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"tterminal-backend/config"
+	"tterminal-backend/internal/binance"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxTradingOrders caps the orders returned per list request
+	maxTradingOrders = 500
+	// clientOrderIDPrefix marks client order IDs generated by the terminal
+	clientOrderIDPrefix = "tt-"
+	// pendingOrderGrace is how long an unconfirmed order may still reach Binance; a pending order
+	// Binance does not know after it is considered never placed
+	pendingOrderGrace = time.Minute
+)
+
+// clientOrderIDPattern is Binance's format for client order IDs
+var clientOrderIDPattern = regexp.MustCompile(`^[.A-Z:/a-z0-9_-]{1,36}$`)
+
+// decimalPattern matches the plain decimals sent to the exchange: no sign, exponent, hex or Inf
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ErrTradingDisabled is returned for new orders and amendments while TRADING_ENABLED is off
+var ErrTradingDisabled = errors.New("live trading is disabled; set TRADING_ENABLED to place or amend orders")
+
+// TradingService places, amends and cancels live futures orders through the signed Binance
+// endpoints. Every order is stored before it is sent, under a client order ID Binance also
+// knows it by, so an order whose placement outcome is unknown (network failure, 5xx) can be
+// reconciled later instead of being placed twice
+//
+// TRADING_ENABLED is checked per request: switched off by a reload, it stops new orders and
+// amendments while open orders can still be read and cancelled
+type TradingService struct {
+	orderRepo     *repositories.TradingOrderRepository
+	binanceClient *binance.Client
+	runtime       *config.Runtime
+	orderFilters  *OrderFilterService // Optional; rejects orders breaking exchange filters before sending them
+}
+
+// NewTradingService creates a new trading service
+func NewTradingService(orderRepo *repositories.TradingOrderRepository, binanceClient *binance.Client, runtime *config.Runtime) *TradingService {
+	if orderRepo == nil {
+		log.Fatalf("[TradingService] CRITICAL: orderRepo cannot be nil")
+	}
+	if binanceClient == nil {
+		log.Fatalf("[TradingService] CRITICAL: binanceClient cannot be nil")
+	}
+	if runtime == nil {
+		log.Fatalf("[TradingService] CRITICAL: runtime cannot be nil")
+	}
+	if !binanceClient.HasCredentials() {
+		log.Printf("[TradingService] WARNING: Binance API credentials are not configured - orders will be rejected")
+	}
+
+	log.Printf("[TradingService] Successfully initialized")
+	return &TradingService{
+		orderRepo:     orderRepo,
+		binanceClient: binanceClient,
+		runtime:       runtime,
+	}
+}
+
+// SetOrderFilters enables exchange filter checks before orders are sent
+func (s *TradingService) SetOrderFilters(orderFilters *OrderFilterService) {
+	s.orderFilters = orderFilters
+}
+
+// GetOrders returns the most recent orders, newest first, as last stored
+func (s *TradingService) GetOrders(ctx context.Context, symbol string, openOnly bool, limit int) ([]models.TradingOrder, error) {
+	if limit <= 0 || limit > maxTradingOrders {
+		limit = maxTradingOrders
+	}
+	return s.orderRepo.GetRecent(ctx, strings.ToUpper(symbol), openOnly, limit)
+}
+
+// GetOrder returns an order, refreshing its state from Binance while it is open
+// When Binance cannot be reached the stored state is returned
+func (s *TradingService) GetOrder(ctx context.Context, id int64) (*models.TradingOrder, error) {
+	order, err := s.getOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsOpen() {
+		return order, nil
+	}
+
+	exchangeOrder, err := s.binanceClient.GetOrder(ctx, order.Symbol, order.ClientOrderID)
+	switch {
+	case err == nil:
+		applyExchangeOrder(order, exchangeOrder)
+	case binance.IsOrderNotFound(err) && order.Status == models.TradingOrderPending &&
+		time.Since(order.CreatedAt) > pendingOrderGrace:
+		// The placement request never reached Binance
+		order.Status = models.TradingOrderRejected
+		order.LastError = "order was never received by binance: " + order.LastError
+	default:
+		log.Printf("[TradingService] Failed to refresh order %s: %v", order.ClientOrderID, err)
+		return order, nil
+	}
+
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// StoredOrder returns an order as last stored, without refreshing it from Binance
+func (s *TradingService) StoredOrder(ctx context.Context, id int64) (*models.TradingOrder, error) {
+	return s.getOrder(ctx, id)
+}
+
+// PlaceOrder validates and places a futures order
+// Repeating a client order ID returns the order already placed under it
+func (s *TradingService) PlaceOrder(ctx context.Context, createdBy string, req *models.PlaceTradingOrderRequest) (*models.TradingOrder, error) {
+	if !s.runtime.Settings().TradingEnabled {
+		return nil, ErrTradingDisabled
+	}
+	if err := s.validatePlaceOrderRequest(req); err != nil {
+		return nil, err
+	}
+
+	if req.ClientOrderID != "" {
+		existing, err := s.orderRepo.GetByClientOrderID(ctx, req.ClientOrderID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	} else {
+		clientOrderID, err := newClientOrderID()
+		if err != nil {
+			return nil, err
+		}
+		req.ClientOrderID = clientOrderID
+	}
+
+	if hasLimitPrice(req.Type) {
+		if err := s.checkFilters(ctx, req.Symbol, req.Quantity, req.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	order := &models.TradingOrder{
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		TimeInForce:   req.TimeInForce,
+		Quantity:      req.Quantity,
+		Price:         decimalOrZero(req.Price),
+		StopPrice:     decimalOrZero(req.StopPrice),
+		ReduceOnly:    req.ReduceOnly,
+		Status:        models.TradingOrderPending,
+		ExecutedQty:   "0",
+		AvgPrice:      "0",
+		CreatedBy:     createdBy,
+	}
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	exchangeOrder, err := s.binanceClient.PlaceOrder(ctx, binance.OrderParams{
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		TimeInForce:   req.TimeInForce,
+		Quantity:      req.Quantity,
+		Price:         req.Price,
+		StopPrice:     req.StopPrice,
+		ReduceOnly:    req.ReduceOnly,
+		ClientOrderID: req.ClientOrderID,
+	})
+	if err != nil {
+		order.LastError = err.Error()
+		if binance.IsRejection(err) {
+			order.Status = models.TradingOrderRejected
+		} else {
+			err = fmt.Errorf("order %s may have been placed, its state is reconciled when it is read: %w", order.ClientOrderID, err)
+		}
+		if updateErr := s.orderRepo.Update(ctx, order); updateErr != nil {
+			log.Printf("[TradingService] Failed to record failed placement of %s: %v", order.ClientOrderID, updateErr)
+		}
+		return nil, err
+	}
+
+	applyExchangeOrder(order, exchangeOrder)
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[TradingService] Placed %s %s %s %s (%s): %s", order.Side, order.Quantity, order.Symbol, order.Type, order.ClientOrderID, order.Status)
+	return order, nil
+}
+
+// AmendOrder changes the quantity and/or price of an open limit order
+func (s *TradingService) AmendOrder(ctx context.Context, id int64, req *models.AmendTradingOrderRequest) (*models.TradingOrder, error) {
+	if !s.runtime.Settings().TradingEnabled {
+		return nil, ErrTradingDisabled
+	}
+	order, err := s.getOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Type != models.OrderTypeLimit {
+		return nil, fmt.Errorf("validation failed: only LIMIT orders can be amended")
+	}
+	if err := checkOrderOpen(order); err != nil {
+		return nil, err
+	}
+
+	quantity, price := strings.TrimSpace(req.Quantity), strings.TrimSpace(req.Price)
+	if quantity == "" && price == "" {
+		return nil, fmt.Errorf("validation failed: quantity or price is required")
+	}
+	if quantity == "" {
+		quantity = order.Quantity
+	} else if !isPositiveDecimal(quantity) {
+		return nil, fmt.Errorf("validation failed: quantity must be a positive number")
+	}
+	if price == "" {
+		price = order.Price
+	} else if !isPositiveDecimal(price) {
+		return nil, fmt.Errorf("validation failed: price must be a positive number")
+	}
+
+	if err := s.checkFilters(ctx, order.Symbol, quantity, price); err != nil {
+		return nil, err
+	}
+
+	exchangeOrder, err := s.binanceClient.AmendOrder(ctx, order.Symbol, order.ClientOrderID, order.Side, quantity, price)
+	if err != nil {
+		return nil, s.recordFailure(ctx, order, err)
+	}
+
+	applyExchangeOrder(order, exchangeOrder)
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[TradingService] Amended %s to %s @ %s", order.ClientOrderID, order.Quantity, order.Price)
+	return order, nil
+}
+
+// CancelOrder cancels an open order
+func (s *TradingService) CancelOrder(ctx context.Context, id int64) (*models.TradingOrder, error) {
+	order, err := s.getOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrderOpen(order); err != nil {
+		return nil, err
+	}
+
+	exchangeOrder, err := s.binanceClient.CancelOrder(ctx, order.Symbol, order.ClientOrderID)
+	if err != nil {
+		return nil, s.recordFailure(ctx, order, err)
+	}
+
+	applyExchangeOrder(order, exchangeOrder)
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[TradingService] Cancelled %s: %s", order.ClientOrderID, order.Status)
+	return order, nil
+}
+
+// getOrder loads an order by ID
+func (s *TradingService) getOrder(ctx context.Context, id int64) (*models.TradingOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("trading order not found")
+	}
+	return order, nil
+}
+
+// recordFailure stores the error of a failed amend or cancel on the order and returns it
+func (s *TradingService) recordFailure(ctx context.Context, order *models.TradingOrder, err error) error {
+	order.LastError = err.Error()
+	if updateErr := s.orderRepo.Update(ctx, order); updateErr != nil {
+		log.Printf("[TradingService] Failed to record error of %s: %v", order.ClientOrderID, updateErr)
+	}
+	return err
+}
+
+// checkFilters rejects a limit price and quantity breaking the symbol's exchange filters
+func (s *TradingService) checkFilters(ctx context.Context, symbol, quantity, price string) error {
+	if s.orderFilters == nil {
+		return nil
+	}
+	check, err := s.orderFilters.Check(ctx, symbol, models.ParseFloat(quantity), models.ParseFloat(price), false)
+	if err != nil {
+		return err
+	}
+	if !check.Valid {
+		return &models.OrderFilterError{Symbol: symbol, Violations: check.Violations}
+	}
+	return nil
+}
+
+// validatePlaceOrderRequest normalizes and validates a new order
+func (s *TradingService) validatePlaceOrderRequest(req *models.PlaceTradingOrderRequest) error {
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	req.Side = strings.ToUpper(req.Side)
+	req.Type = strings.ToUpper(req.Type)
+	req.TimeInForce = strings.ToUpper(req.TimeInForce)
+	req.Quantity = strings.TrimSpace(req.Quantity)
+	req.Price = strings.TrimSpace(req.Price)
+	req.StopPrice = strings.TrimSpace(req.StopPrice)
+
+	if req.Symbol == "" {
+		return fmt.Errorf("validation failed: symbol is required")
+	}
+	if strings.Contains(req.Symbol, ":") || binance.IsCoinMargined(req.Symbol) {
+		return fmt.Errorf("validation failed: only Binance USDⓈ-M futures symbols can be traded")
+	}
+	if req.Side != models.OrderSideBuy && req.Side != models.OrderSideSell {
+		return fmt.Errorf("validation failed: side must be BUY or SELL")
+	}
+	if !models.IsValidTradingOrderType(req.Type) {
+		return fmt.Errorf("validation failed: type must be one of %s", strings.Join(models.TradingOrderTypes, ", "))
+	}
+	if !isPositiveDecimal(req.Quantity) {
+		return fmt.Errorf("validation failed: quantity must be a positive number")
+	}
+
+	if hasLimitPrice(req.Type) {
+		if !isPositiveDecimal(req.Price) {
+			return fmt.Errorf("validation failed: price is required for %s orders", req.Type)
+		}
+		if req.TimeInForce == "" {
+			req.TimeInForce = "GTC"
+		}
+		if !models.IsValidTimeInForce(req.TimeInForce) {
+			return fmt.Errorf("validation failed: time_in_force must be one of %s", strings.Join(models.TimeInForceValues, ", "))
+		}
+	} else {
+		if req.Price != "" {
+			return fmt.Errorf("validation failed: price is not accepted for %s orders", req.Type)
+		}
+		if req.TimeInForce != "" {
+			return fmt.Errorf("validation failed: time_in_force is not accepted for %s orders", req.Type)
+		}
+	}
+
+	hasStopPrice := req.Type != models.OrderTypeLimit && req.Type != models.OrderTypeMarket
+	if hasStopPrice && !isPositiveDecimal(req.StopPrice) {
+		return fmt.Errorf("validation failed: stop_price is required for %s orders", req.Type)
+	}
+	if !hasStopPrice && req.StopPrice != "" {
+		return fmt.Errorf("validation failed: stop_price is not accepted for %s orders", req.Type)
+	}
+
+	if req.ClientOrderID != "" && !clientOrderIDPattern.MatchString(req.ClientOrderID) {
+		return fmt.Errorf("validation failed: client_order_id must be 1-36 letters, digits or .:/_-")
+	}
+	return nil
+}
+
+// checkOrderOpen rejects changes to orders that are settled or not confirmed yet
+func checkOrderOpen(order *models.TradingOrder) error {
+	if order.Status == models.TradingOrderPending {
+		return fmt.Errorf("validation failed: order %s is not confirmed by binance yet", order.ClientOrderID)
+	}
+	if !order.IsOpen() {
+		return fmt.Errorf("validation failed: order %s is already %s", order.ClientOrderID, order.Status)
+	}
+	return nil
+}
+
+// applyExchangeOrder copies Binance's view of an order onto the stored order
+func applyExchangeOrder(order *models.TradingOrder, exchangeOrder *binance.FuturesOrder) {
+	exchangeOrderID := exchangeOrder.OrderID
+	order.ExchangeOrderID = &exchangeOrderID
+	order.Status = exchangeOrder.Status
+	order.Quantity = exchangeOrder.OrigQty
+	order.Price = decimalOrZero(exchangeOrder.Price)
+	order.StopPrice = decimalOrZero(exchangeOrder.StopPrice)
+	order.ExecutedQty = decimalOrZero(exchangeOrder.ExecutedQty)
+	order.AvgPrice = decimalOrZero(exchangeOrder.AvgPrice)
+	order.LastError = ""
+}
+
+// hasLimitPrice reports whether an order type rests at a limit price
+func hasLimitPrice(orderType string) bool {
+	return orderType == models.OrderTypeLimit || orderType == models.OrderTypeStop || orderType == models.OrderTypeTakeProfit
+}
+
+// isPositiveDecimal reports whether value is a plain positive decimal number
+func isPositiveDecimal(value string) bool {
+	if !decimalPattern.MatchString(value) {
+		return false
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	return err == nil && parsed > 0
+}
+
+// decimalOrZero returns "0" for an absent decimal
+func decimalOrZero(value string) string {
+	if value == "" {
+		return "0"
+	}
+	return value
+}
+
+// newClientOrderID generates a random client order ID
+func newClientOrderID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate client order ID: %w", err)
+	}
+	return clientOrderIDPrefix + hex.EncodeToString(buf), nil
+}
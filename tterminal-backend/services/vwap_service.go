@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// vwapSession is the length of a session VWAP, which restarts at every UTC midnight
+	vwapSession = 24 * time.Hour
+	// maxVWAPBands caps the band multipliers of one request
+	maxVWAPBands = 5
+	// maxVWAPBandMultiplier caps a band's distance from the VWAP, in standard deviations
+	maxVWAPBandMultiplier = 10.0
+)
+
+// VWAPParams selects a VWAP series; a zero Anchor selects session VWAP
+type VWAPParams struct {
+	Symbol   string
+	Interval string
+	Source   string    // models.VWAPSourceCandles or models.VWAPSourceTrades
+	Anchor   time.Time // Start of an anchored VWAP
+	Start    time.Time // First bar returned; the current session's start when zero
+	End      time.Time // Last bar returned; now when zero
+	Bands    []float64 // Standard deviation multipliers
+}
+
+// VWAPService computes session and anchored VWAP with standard deviation bands from stored
+// candles (volume-weighted typical price) or stored trades (exact trade prices)
+type VWAPService struct {
+	candleService *CandleService
+	tradeRepo     *repositories.TradeRepository
+}
+
+// NewVWAPService creates a new VWAP service
+func NewVWAPService(candleService *CandleService, tradeRepo *repositories.TradeRepository) *VWAPService {
+	if candleService == nil {
+		log.Fatalf("[VWAPService] CRITICAL: candleService cannot be nil")
+	}
+	if tradeRepo == nil {
+		log.Fatalf("[VWAPService] CRITICAL: tradeRepo cannot be nil")
+	}
+	log.Printf("[VWAPService] Successfully initialized")
+	return &VWAPService{candleService: candleService, tradeRepo: tradeRepo}
+}
+
+// GetVWAP returns the VWAP series of the bars opening in [Start, End]. Session VWAP accumulates
+// from the UTC midnight opening each bar's session, reading the part of the first session before
+// Start without returning it; anchored VWAP accumulates from Anchor
+func (s *VWAPService) GetVWAP(ctx context.Context, params VWAPParams) (*models.VWAPSeries, error) {
+	duration, ok := models.IntervalDuration(params.Interval)
+	if !ok {
+		return nil, fmt.Errorf("validation failed: unsupported interval %s", params.Interval)
+	}
+	if err := validateVWAPBands(params.Bands); err != nil {
+		return nil, err
+	}
+	if params.Source != models.VWAPSourceCandles && params.Source != models.VWAPSourceTrades {
+		return nil, fmt.Errorf("validation failed: source must be %s or %s", models.VWAPSourceCandles, models.VWAPSourceTrades)
+	}
+
+	mode := models.VWAPModeSession
+	if !params.Anchor.IsZero() {
+		mode = models.VWAPModeAnchored
+	} else if duration >= vwapSession {
+		return nil, fmt.Errorf("validation failed: session VWAP needs an intraday interval, use an anchor for %s bars", params.Interval)
+	}
+
+	end := params.End
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	start := params.Start
+	var readFrom time.Time
+	if mode == models.VWAPModeAnchored {
+		if params.Anchor.After(end) {
+			return nil, fmt.Errorf("validation failed: anchor must be before end")
+		}
+		if start.IsZero() || start.Before(params.Anchor) {
+			start = params.Anchor
+		}
+		readFrom = params.Anchor
+	} else {
+		if start.IsZero() {
+			start = end.Truncate(vwapSession)
+		}
+		readFrom = start.Truncate(vwapSession)
+	}
+	if err := validateTimeRange(params.Interval, readFrom, end); err != nil {
+		if strings.HasPrefix(err.Error(), "time range too large") {
+			return nil, fmt.Errorf("validation failed: %w (counted from %s)", err, readFrom.UTC().Format(time.RFC3339))
+		}
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	var buckets []models.VWAPBucket
+	var err error
+	if params.Source == models.VWAPSourceTrades {
+		buckets, err = s.tradeRepo.GetVWAPBuckets(ctx, params.Symbol, duration, readFrom, end)
+	} else {
+		// Candles anchor at the bar containing the anchor
+		var candles []models.Candle
+		candles, err = s.candleService.GetByTimeRange(ctx, params.Symbol, params.Interval, readFrom.Truncate(duration), end)
+		buckets = candleVWAPBuckets(candles)
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrQueryTimeout
+		}
+		return nil, err
+	}
+
+	series := &models.VWAPSeries{
+		S:   params.Symbol,
+		I:   params.Interval,
+		M:   mode,
+		Src: params.Source,
+		B:   params.Bands,
+		ST:  start.UnixMilli(),
+		ET:  end.UnixMilli(),
+		D:   make([]models.VWAPBar, 0, len(buckets)),
+	}
+	if mode == models.VWAPModeAnchored {
+		series.A = params.Anchor.UnixMilli()
+	}
+
+	// Bars of the anchored VWAP's first bar or of a new session restart the sums
+	firstBar := start.Truncate(duration)
+	var session time.Time
+	var notional, volume, squareNotional float64
+	for _, bucket := range buckets {
+		if mode == models.VWAPModeSession {
+			if bucketSession := bucket.Time.Truncate(vwapSession); !bucketSession.Equal(session) {
+				session = bucketSession
+				notional, volume, squareNotional = 0, 0, 0
+			}
+		}
+		notional += bucket.Notional
+		volume += bucket.Volume
+		squareNotional += bucket.SquareNotional
+
+		if bucket.Time.Before(firstBar) || volume <= 0 {
+			continue
+		}
+		vwap := notional / volume
+		sd := math.Sqrt(math.Max(squareNotional/volume-vwap*vwap, 0))
+		bar := models.VWAPBar{
+			T:  bucket.Time.UnixMilli(),
+			V:  vwap,
+			SD: sd,
+			U:  make([]float64, len(params.Bands)),
+			L:  make([]float64, len(params.Bands)),
+		}
+		for i, multiplier := range params.Bands {
+			bar.U[i] = vwap + multiplier*sd
+			bar.L[i] = vwap - multiplier*sd
+		}
+		series.D = append(series.D, bar)
+	}
+
+	return series, nil
+}
+
+// candleVWAPBuckets weights each candle's typical price, (high + low + close) / 3, by its volume
+func candleVWAPBuckets(candles []models.Candle) []models.VWAPBucket {
+	buckets := make([]models.VWAPBucket, 0, len(candles))
+	for _, candle := range candles {
+		typical := (models.ParseFloat(candle.High) + models.ParseFloat(candle.Low) + models.ParseFloat(candle.Close)) / 3
+		volume := models.ParseFloat(candle.Volume)
+		buckets = append(buckets, models.VWAPBucket{
+			Time:           candle.OpenTime,
+			Notional:       typical * volume,
+			Volume:         volume,
+			SquareNotional: typical * typical * volume,
+		})
+	}
+	return buckets
+}
+
+// validateVWAPBands checks the band multipliers of a request
+func validateVWAPBands(bands []float64) error {
+	if len(bands) > maxVWAPBands {
+		return fmt.Errorf("validation failed: at most %d bands", maxVWAPBands)
+	}
+	for _, multiplier := range bands {
+		if multiplier <= 0 || multiplier > maxVWAPBandMultiplier || math.IsNaN(multiplier) {
+			return fmt.Errorf("validation failed: band multipliers must be above 0 and at most %g", maxVWAPBandMultiplier)
+		}
+	}
+	return nil
+}
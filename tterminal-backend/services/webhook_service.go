@@ -0,0 +1,888 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"tterminal-backend/models"
+	"tterminal-backend/repositories"
+)
+
+const (
+	// maxWebhooksPerUser caps the webhooks a user can register
+	maxWebhooksPerUser = 10
+	// maxWebhookSymbols caps the symbols one webhook subscribes to
+	maxWebhookSymbols = 50
+	// maxWebhookDeliveries caps the delivery log entries returned per request
+	maxWebhookDeliveries = 500
+	// webhookMaxAttempts is the attempts made before a delivery is marked failed
+	webhookMaxAttempts = 6
+	// webhookAttemptTimeout bounds one POST to a user endpoint
+	webhookAttemptTimeout = 10 * time.Second
+	// webhookLease is how long a claimed delivery is reserved for its attempt; a delivery whose
+	// attempt never recorded a result (crash, restart) is retried once its lease expires
+	webhookLease = 2 * time.Minute
+	// webhookRetryPoll is how often due retries are claimed
+	webhookRetryPoll = 15 * time.Second
+	// webhookClaimBatch is the due deliveries claimed per poll
+	webhookClaimBatch = 100
+	// webhookWorkers is the number of concurrent deliveries
+	webhookWorkers = 4
+	// webhookQueueSize bounds the deliveries waiting for a worker
+	webhookQueueSize = 1024
+	// webhookRefreshInterval reloads active webhooks, picking up changes made on other instances
+	webhookRefreshInterval = time.Minute
+	// webhookDeliveryRetention is how long finished deliveries stay in the log
+	webhookDeliveryRetention = 7 * 24 * time.Hour
+	// webhookDeadLetterRetention keeps failed deliveries longer, so they can be replayed after an outage
+	webhookDeadLetterRetention = 30 * 24 * time.Hour
+	// webhookSecretPrefix marks signing secrets
+	webhookSecretPrefix = "whsec_"
+)
+
+// webhookRetryBackoff is the wait before each retry, by attempts made so far
+var webhookRetryBackoff = []time.Duration{30 * time.Second, 2 * time.Minute, 10 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+// errPrivateAddress is returned when a webhook resolves to a private, loopback or link-local address
+var errPrivateAddress = errors.New("webhook endpoints must be public addresses")
+
+// webhookEvent is one event to deliver to every webhook matching it
+type webhookEvent struct {
+	event      string
+	symbol     string
+	interval   string
+	userID     string  // Only this user's webhooks receive the event; empty for market data
+	notional   float64 // Liquidation notional, checked against each webhook's minimum
+	webhookIDs []int64 // The user's webhooks receiving the event whatever their subscriptions; empty matches subscriptions
+	data       interface{}
+}
+
+// WebhookService manages user webhooks and delivers closed candles, market event alerts, price
+// alerts and liquidations to them as signed JSON POSTs, or as Telegram and Discord messages.
+// Every payload is stored as a delivery before its first attempt and retried with backoff until the endpoint answers 2xx, so deliveries
+// survive restarts and users can inspect the delivery log. Deliveries out of attempts are kept as
+// dead letters that users can replay
+type WebhookService struct {
+	webhookRepo  *repositories.WebhookRepository
+	httpClient   *http.Client
+	allowPrivate bool   // Allow endpoints on private networks (development)
+	deploymentID string // Watermarks deliveries in compliance mode; empty sends none
+
+	mu     sync.RWMutex
+	active []models.Webhook // Active webhooks, for matching events
+
+	queue     chan models.WebhookDelivery
+	events    chan webhookEvent // Stream events waiting for their deliveries to be stored
+	isRunning bool
+	stopChan  chan struct{}
+}
+
+// NewWebhookService creates a new webhook service
+// Unless allowPrivate is set, endpoints resolving to private, loopback or link-local addresses
+// are refused when registered and when dialed
+func NewWebhookService(webhookRepo *repositories.WebhookRepository, allowPrivate bool) *WebhookService {
+	if webhookRepo == nil {
+		log.Fatalf("[WebhookService] CRITICAL: webhookRepo cannot be nil")
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = refusePrivateAddress
+	}
+	httpClient := &http.Client{
+		Timeout:   webhookAttemptTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, MaxIdleConnsPerHost: 2},
+		// Redirects are answered as failures: the registered URL is the only endpoint
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	log.Printf("[WebhookService] Successfully initialized")
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		httpClient:   httpClient,
+		allowPrivate: allowPrivate,
+		queue:        make(chan models.WebhookDelivery, webhookQueueSize),
+		events:       make(chan webhookEvent, webhookQueueSize),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// SetWatermark sends X-Deployment-ID and X-Data-Watermark headers with every delivery, like
+// raw-data exports in compliance mode
+func (s *WebhookService) SetWatermark(deploymentID string) {
+	s.deploymentID = deploymentID
+}
+
+// Start loads active webhooks, then starts the delivery workers and the retry loop
+func (s *WebhookService) Start() error {
+	s.mu.Lock()
+	if s.isRunning {
+		s.mu.Unlock()
+		return fmt.Errorf("webhook service is already running")
+	}
+	s.isRunning = true
+	s.mu.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	for i := 0; i < webhookWorkers; i++ {
+		go s.worker()
+	}
+	go s.dispatcher()
+	go s.retryLoop()
+	return nil
+}
+
+// Stop stops the delivery workers and the retry loop; unfinished deliveries are retried after restart
+func (s *WebhookService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+	s.isRunning = false
+	close(s.stopChan)
+}
+
+// GetWebhooks returns all webhooks of a user, without their secrets
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook owned by the user, without its secret
+func (s *WebhookService) GetWebhook(ctx context.Context, userID string, id int64) (*models.Webhook, error) {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook registers a webhook for a user; the response is the only one carrying its secret
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID string, req *models.CreateWebhookRequest) (*models.Webhook, error) {
+	webhook := &models.Webhook{
+		UserID:  userID,
+		Channel: strings.ToLower(strings.TrimSpace(req.Channel)),
+		Active:  true,
+	}
+	if webhook.Channel == "" {
+		webhook.Channel = models.WebhookChannelHTTP
+	}
+	if !models.IsValidWebhookChannel(webhook.Channel) {
+		return nil, fmt.Errorf("validation failed: invalid channel %q, use %s", webhook.Channel, strings.Join(models.WebhookChannels, ", "))
+	}
+	if err := s.applyWebhookFields(webhook, req.URL, req.BotToken, req.ChatID, req.Events, req.Symbols, req.Intervals, req.MinNotional); err != nil {
+		return nil, err
+	}
+
+	owned, err := s.webhookRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(owned) >= maxWebhooksPerUser {
+		return nil, fmt.Errorf("validation failed: at most %d webhooks per user", maxWebhooksPerUser)
+	}
+
+	if webhook.Channel == models.WebhookChannelHTTP {
+		if webhook.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.refreshAsync()
+	return webhook, nil
+}
+
+// UpdateWebhook changes a webhook's endpoint, subscriptions or active flag
+func (s *WebhookService) UpdateWebhook(ctx context.Context, userID string, id int64, req *models.UpdateWebhookRequest) (*models.Webhook, error) {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	target, events, symbols, intervals, minNotional := webhook.URL, webhook.Events, webhook.Symbols, webhook.Intervals, webhook.MinNotional
+	botToken, chatID := webhook.Secret, webhook.ChatID
+	if req.URL != nil {
+		target = *req.URL
+	}
+	if req.BotToken != nil {
+		botToken = *req.BotToken
+	}
+	if req.ChatID != nil {
+		chatID = *req.ChatID
+	}
+	if req.Events != nil {
+		events = req.Events
+	}
+	if req.Symbols != nil {
+		symbols = req.Symbols
+	}
+	if req.Intervals != nil {
+		intervals = req.Intervals
+	}
+	if req.MinNotional != nil {
+		minNotional = *req.MinNotional
+	}
+	if err := s.applyWebhookFields(webhook, target, botToken, chatID, events, symbols, intervals, minNotional); err != nil {
+		return nil, err
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	s.refreshAsync()
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// RotateSecret replaces an http webhook's signing secret, returning the webhook with the new secret
+func (s *WebhookService) RotateSecret(ctx context.Context, userID string, id int64) (*models.Webhook, error) {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Channel != models.WebhookChannelHTTP {
+		return nil, fmt.Errorf("validation failed: only http webhooks have a signing secret")
+	}
+	if webhook.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID string, id int64) error {
+	if _, err := s.ownedWebhook(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.refreshAsync()
+	return nil
+}
+
+// GetDeliveries returns the most recent deliveries of a webhook, newest first, optionally only
+// those with a status (failed lists the dead letters)
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID string, id int64, status string, limit int) ([]models.WebhookDelivery, error) {
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		return nil, fmt.Errorf("validation failed: invalid status %q, use pending, delivered or failed", status)
+	}
+	if _, err := s.ownedWebhook(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = maxWebhookDeliveries
+	}
+	return s.webhookRepo.GetDeliveries(ctx, id, status, limit)
+}
+
+// ReplayDeliveries sends dead letters of an active webhook again with a fresh set of attempts:
+// one delivery, or every failed one when deliveryID is 0. Payloads keep their delivery ID, so
+// endpoints deduplicating on it ignore replays of deliveries they already processed
+func (s *WebhookService) ReplayDeliveries(ctx context.Context, userID string, id, deliveryID int64) ([]models.WebhookDelivery, error) {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Active {
+		return nil, fmt.Errorf("validation failed: webhook is inactive")
+	}
+	if deliveryID != 0 {
+		delivery, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+		if err != nil {
+			return nil, err
+		}
+		if delivery == nil || delivery.WebhookID != id {
+			return nil, fmt.Errorf("delivery not found")
+		}
+		if delivery.Status != models.WebhookDeliveryFailed {
+			return nil, fmt.Errorf("validation failed: only failed deliveries can be replayed, delivery %d is %s", deliveryID, delivery.Status)
+		}
+	}
+
+	replayed, err := s.webhookRepo.ReplayDeliveries(ctx, id, deliveryID, webhookLease)
+	if err != nil {
+		return nil, err
+	}
+	if deliveryID != 0 && len(replayed) == 0 {
+		return nil, fmt.Errorf("validation failed: delivery %d was already replayed", deliveryID)
+	}
+	for _, delivery := range replayed {
+		select {
+		case s.queue <- delivery:
+		default:
+			// Workers are behind; the retry loop attempts the rest once their lease expires
+		}
+	}
+	return replayed, nil
+}
+
+// SendTest queues a ping delivery to an active webhook, so users can check their endpoint and signature verification
+func (s *WebhookService) SendTest(ctx context.Context, userID string, id int64) (*models.WebhookDelivery, error) {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Active {
+		return nil, fmt.Errorf("validation failed: webhook is inactive")
+	}
+
+	data := map[string]string{"message": "Webhook test delivery"}
+	return s.createDelivery(ctx, webhook.ID, models.WebhookEventPing, "", "", data)
+}
+
+// HandleBarClose delivers a closed bar to every webhook subscribed to its symbol and interval
+func (s *WebhookService) HandleBarClose(bar models.BarClose) {
+	s.dispatch(webhookEvent{event: models.WebhookEventCandle, symbol: bar.Symbol, interval: bar.Interval, data: models.WebhookCandle{
+		Symbol:      bar.Symbol,
+		Interval:    bar.Interval,
+		OpenTime:    bar.OpenTime,
+		CloseTime:   bar.CloseTime,
+		Open:        bar.Open,
+		High:        bar.High,
+		Low:         bar.Low,
+		Close:       bar.Close,
+		Volume:      bar.Volume,
+		BuyVolume:   bar.BuyVolume,
+		QuoteVolume: bar.QuoteVolume,
+		TradeCount:  bar.TradeCount,
+	}})
+}
+
+// HandleMarketEvent delivers a significant event alert to every webhook subscribed to its symbol and interval
+func (s *WebhookService) HandleMarketEvent(event models.MarketEvent) {
+	s.dispatch(webhookEvent{event: models.WebhookEventMarketEvent, symbol: event.Symbol, interval: event.Interval, data: event})
+}
+
+// HandleAlertTriggered delivers a fired price alert to the webhooks it names, or to its owner's
+// webhooks subscribed to alerts on its symbol when it names none
+// Runs on the price stream's read loop, so deliveries are stored by the dispatcher
+func (s *WebhookService) HandleAlertTriggered(alert models.PriceAlert, trigger models.AlertTrigger) {
+	s.enqueue(webhookEvent{
+		event:      models.WebhookEventAlert,
+		symbol:     alert.Symbol,
+		userID:     alert.UserID,
+		webhookIDs: alert.WebhookIDs,
+		data:       models.WebhookAlert{Alert: alert, Trigger: trigger},
+	})
+}
+
+// CheckWebhooks validates webhooks a price alert names: each must be one of the user's webhooks
+func (s *WebhookService) CheckWebhooks(ctx context.Context, userID string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	owned, err := s.webhookRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		found := false
+		for _, webhook := range owned {
+			if webhook.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("validation failed: webhook %d not found", id)
+		}
+	}
+	return nil
+}
+
+// HandleLiquidation delivers a streamed liquidation to the webhooks subscribed to its symbol whose
+// minimum notional it reaches. Runs on the exchange stream's read loop, so deliveries are stored
+// by the dispatcher
+func (s *WebhookService) HandleLiquidation(liquidation models.LiquidationEvent) {
+	notional := liquidation.Price * liquidation.Quantity
+	s.enqueue(webhookEvent{
+		event:    models.WebhookEventLiquidation,
+		symbol:   liquidation.Symbol,
+		notional: notional,
+		data: models.WebhookLiquidation{
+			Symbol:    liquidation.Symbol,
+			Side:      liquidation.Side,
+			Price:     liquidation.Price,
+			Quantity:  liquidation.Quantity,
+			Notional:  notional,
+			TradeTime: liquidation.TradeTime.UnixMilli(),
+		},
+	})
+}
+
+// enqueue hands an event some webhook receives to the dispatcher, dropping it when the
+// dispatcher is behind rather than blocking the caller
+func (s *WebhookService) enqueue(event webhookEvent) {
+	if len(s.matching(event)) == 0 {
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+		log.Printf("[WebhookService] WARNING: Event queue full, %s of %s not delivered", event.event, event.symbol)
+	}
+}
+
+// dispatcher stores the deliveries of enqueued events until the service stops
+func (s *WebhookService) dispatcher() {
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case event := <-s.events:
+			s.dispatch(event)
+		}
+	}
+}
+
+// dispatch stores and queues one delivery per matching webhook
+func (s *WebhookService) dispatch(event webhookEvent) {
+	matches := s.matching(event)
+	if len(matches) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, webhookID := range matches {
+		if _, err := s.createDelivery(ctx, webhookID, event.event, event.symbol, event.interval, event.data); err != nil {
+			log.Printf("[WebhookService] WARNING: Failed to queue %s delivery of %s %s to webhook %d: %v", event.event, event.symbol, event.interval, webhookID, err)
+		}
+	}
+}
+
+// matching returns the IDs of the active webhooks receiving an event
+func (s *WebhookService) matching(event webhookEvent) []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []int64
+	for i := range s.active {
+		webhook := &s.active[i]
+		if event.userID != "" && webhook.UserID != event.userID {
+			continue
+		}
+		if len(event.webhookIDs) > 0 {
+			if containsID(event.webhookIDs, webhook.ID) {
+				matches = append(matches, webhook.ID)
+			}
+			continue
+		}
+		if !webhook.Matches(event.event, event.symbol, event.interval) {
+			continue
+		}
+		if event.event == models.WebhookEventLiquidation && event.notional < webhook.MinNotional {
+			continue
+		}
+		matches = append(matches, webhook.ID)
+	}
+	return matches
+}
+
+// createDelivery stores a pending delivery, leased for its immediate attempt, and queues it
+func (s *WebhookService) createDelivery(ctx context.Context, webhookID int64, event, symbol, interval string, data interface{}) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	leaseUntil := time.Now().Add(webhookLease)
+	delivery := &models.WebhookDelivery{
+		WebhookID:     webhookID,
+		Event:         event,
+		Symbol:        symbol,
+		Interval:      interval,
+		Payload:       payload,
+		NextAttemptAt: &leaseUntil,
+	}
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.queue <- *delivery:
+	default:
+		// Workers are behind; the retry loop attempts it once the lease expires
+	}
+	return delivery, nil
+}
+
+// worker attempts queued deliveries until the service stops
+func (s *WebhookService) worker() {
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case delivery := <-s.queue:
+			s.attempt(delivery)
+		}
+	}
+}
+
+// retryLoop claims due retries, reloads active webhooks and prunes the delivery log
+func (s *WebhookService) retryLoop() {
+	retryTicker := time.NewTicker(webhookRetryPoll)
+	defer retryTicker.Stop()
+	refreshTicker := time.NewTicker(webhookRefreshInterval)
+	defer refreshTicker.Stop()
+	pruneTicker := time.NewTicker(time.Hour)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-retryTicker.C:
+			s.claimDue()
+		case <-refreshTicker.C:
+			if err := s.refresh(); err != nil {
+				log.Printf("[WebhookService] WARNING: Failed to reload webhooks: %v", err)
+			}
+		case <-pruneTicker.C:
+			s.prune()
+		}
+	}
+}
+
+// claimDue leases due deliveries and hands them to the workers
+func (s *WebhookService) claimDue() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deliveries, err := s.webhookRepo.ClaimDue(ctx, webhookLease, webhookClaimBatch)
+	if err != nil {
+		log.Printf("[WebhookService] WARNING: Failed to claim due deliveries: %v", err)
+		return
+	}
+	for _, delivery := range deliveries {
+		select {
+		case <-s.stopChan:
+			return
+		case s.queue <- delivery:
+		}
+	}
+}
+
+// attempt POSTs or messages a delivery and records the outcome: delivered, retried after a backoff, or failed
+func (s *WebhookService) attempt(delivery models.WebhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookAttemptTimeout+5*time.Second)
+	defer cancel()
+
+	webhook, err := s.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if err != nil {
+		log.Printf("[WebhookService] WARNING: Failed to load webhook %d: %v", delivery.WebhookID, err)
+		return // Retried once the lease expires
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.NextAttemptAt = nil
+	switch {
+	case webhook == nil || !webhook.Active:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "webhook is inactive"
+	default:
+		if webhook.Channel == models.WebhookChannelHTTP {
+			delivery.ResponseStatus, err = s.post(ctx, webhook, &delivery)
+		} else {
+			delivery.ResponseStatus, err = s.sendMessage(ctx, webhook, &delivery)
+		}
+		switch {
+		case err == nil:
+			delivery.Status = models.WebhookDeliveryDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+		case delivery.Attempts >= webhookMaxAttempts:
+			delivery.Status = models.WebhookDeliveryFailed
+			delivery.LastError = err.Error()
+		default:
+			next := now.Add(webhookRetryBackoff[delivery.Attempts-1])
+			delivery.Status = models.WebhookDeliveryPending
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	if err := s.webhookRepo.RecordAttempt(ctx, &delivery); err != nil {
+		log.Printf("[WebhookService] WARNING: Failed to record delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// post sends a delivery to its webhook, returning the response status (0 when none)
+// The body is signed with the webhook secret over "<timestamp>.<body>"
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(models.WebhookPayload{
+		ID:        delivery.ID,
+		Event:     delivery.Event,
+		WebhookID: webhook.ID,
+		Attempt:   delivery.Attempts,
+		CreatedAt: delivery.CreatedAt.UnixMilli(),
+		Data:      json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TTerminal-Webhooks/1.0")
+	req.Header.Set("X-TTerminal-Event", delivery.Event)
+	req.Header.Set("X-TTerminal-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set("X-TTerminal-Signature", "t="+timestamp+",v1="+signWebhookPayload(webhook.Secret, timestamp, body))
+	if s.deploymentID != "" {
+		req.Header.Set("X-Deployment-ID", s.deploymentID)
+		req.Header.Set("X-Data-Watermark", fmt.Sprintf("deployment=%s; user=%s; issued=%s",
+			s.deploymentID, webhook.UserID, time.Now().UTC().Format(time.RFC3339)))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return resp.StatusCode, fmt.Errorf("endpoint answered %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
+
+// prune removes delivered deliveries and dead letters past their retention
+func (s *WebhookService) prune() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	removed, err := s.webhookRepo.DeleteDeliveriesBefore(ctx, now.Add(-webhookDeliveryRetention), now.Add(-webhookDeadLetterRetention))
+	if err != nil {
+		log.Printf("[WebhookService] WARNING: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("[WebhookService] Pruned %d webhook deliveries", removed)
+	}
+}
+
+// refresh reloads the active webhooks events are matched against
+func (s *WebhookService) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	webhooks, err := s.webhookRepo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.active = webhooks
+	s.mu.Unlock()
+	return nil
+}
+
+// refreshAsync reloads active webhooks after a change without delaying the response
+func (s *WebhookService) refreshAsync() {
+	go func() {
+		if err := s.refresh(); err != nil {
+			log.Printf("[WebhookService] WARNING: Failed to reload webhooks: %v", err)
+		}
+	}()
+}
+
+// ownedWebhook loads a webhook, reporting webhooks of other users as not found
+func (s *WebhookService) ownedWebhook(ctx context.Context, userID string, id int64) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || webhook.UserID != userID {
+		return nil, fmt.Errorf("webhook not found")
+	}
+	return webhook, nil
+}
+
+// applyWebhookFields validates and normalizes a webhook's endpoint and subscriptions onto it
+// The endpoint depends on the webhook's channel: a URL (http, discord) or a bot and chat (telegram)
+func (s *WebhookService) applyWebhookFields(webhook *models.Webhook, target, botToken, chatID string, events, symbols, intervals []string, minNotional float64) error {
+	target = strings.TrimSpace(target)
+	switch webhook.Channel {
+	case models.WebhookChannelTelegram:
+		botToken, chatID = strings.TrimSpace(botToken), strings.TrimSpace(chatID)
+		if target != "" && target != telegramAPIURL {
+			return fmt.Errorf("validation failed: telegram webhooks take bot_token and chat_id instead of a url")
+		}
+		if err := validateTelegramChat(botToken, chatID); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		target = telegramAPIURL
+	case models.WebhookChannelDiscord:
+		if err := validateDiscordURL(target); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	default:
+		if err := s.validateWebhookURL(target); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	if len(events) == 0 {
+		return fmt.Errorf("validation failed: events must list at least one of %s", strings.Join(models.WebhookEvents, ", "))
+	}
+	for _, event := range events {
+		if !models.IsValidWebhookEvent(event) {
+			return fmt.Errorf("validation failed: invalid event %q, use %s", event, strings.Join(models.WebhookEvents, ", "))
+		}
+	}
+
+	normalized := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		normalized = append(normalized, symbol)
+	}
+	if len(normalized) == 0 || len(normalized) > maxWebhookSymbols {
+		return fmt.Errorf("validation failed: a webhook needs between 1 and %d symbols", maxWebhookSymbols)
+	}
+
+	for _, interval := range intervals {
+		if !models.IsValidInterval(interval) {
+			return fmt.Errorf("validation failed: invalid interval %q", interval)
+		}
+	}
+	if intervals == nil {
+		intervals = []string{}
+	}
+	if minNotional < 0 {
+		return fmt.Errorf("validation failed: min_notional cannot be negative")
+	}
+
+	webhook.URL = target
+	if webhook.Channel == models.WebhookChannelTelegram {
+		webhook.Secret = botToken
+		webhook.ChatID = chatID
+	}
+	webhook.Events = events
+	webhook.Symbols = normalized
+	webhook.Intervals = intervals
+	webhook.MinNotional = minNotional
+	return nil
+}
+
+// validateWebhookURL checks a webhook endpoint is an http(s) URL, on a public host unless private endpoints are allowed
+// Hostnames are checked again when dialed, since they may resolve to private addresses
+func (s *WebhookService) validateWebhookURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if s.allowPrivate {
+		return nil
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return errPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// containsID reports whether ids holds id
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// refusePrivateAddress is the dialer control refusing connections to non-public addresses
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// isPrivateIP reports whether an address is loopback, private, link-local, multicast or unspecified
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// newWebhookSecret generates a random signing secret
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + hex.EncodeToString(buf), nil
+}
+
+// signWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" under the webhook secret
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}